@@ -0,0 +1,400 @@
+// Package anomaly blocks mutations that would amplify a runaway account. When
+// today's spend has spiked against yesterday's, resuming a paused object or
+// raising its budget is held back until the operator overrides it with
+// --override-anomaly, so automation cannot pour more budget into an account
+// that is already overspending.
+package anomaly
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	PolicySchemaVersion = 1
+
+	ChangeResume         = "resume"
+	ChangeBudgetIncrease = "budget_increase"
+
+	errorTypeAnomaly = "spend_anomaly_gate"
+	errorCodeAnomaly = 409300
+)
+
+var budgetParams = []string{"daily_budget", "lifetime_budget"}
+
+// Policy is the spend anomaly policy file.
+type Policy struct {
+	SchemaVersion int    `yaml:"schema_version"`
+	Rules         []Rule `yaml:"rules"`
+}
+
+// Rule flags an account as anomalous when today's spend is more than
+// MaxSpendRatio times yesterday's. Spend is in the account currency, as
+// insights report it. A rule without accounts or labels covers every account.
+type Rule struct {
+	Name string `yaml:"name"`
+	// Accounts lists ad account ids, with or without the act_ prefix.
+	Accounts []string `yaml:"accounts,omitempty"`
+	// Labels is a profile selector such as "env=prod".
+	Labels        string  `yaml:"labels,omitempty"`
+	MaxSpendRatio float64 `yaml:"max_spend_ratio"`
+	// MinSpend ignores spikes while today's spend is below it, so small
+	// accounts do not trip on noise. When yesterday had no spend, any spend
+	// from MinSpend up counts as a spike.
+	MinSpend float64 `yaml:"min_spend,omitempty"`
+
+	selector *config.Selector
+}
+
+// Spend is an account's spend for yesterday and so far today.
+type Spend struct {
+	Yesterday float64
+	Today     float64
+}
+
+// Finding is one rule an account's spend trips.
+type Finding struct {
+	Rule           string  `json:"rule"`
+	Account        string  `json:"account"`
+	YesterdaySpend float64 `json:"yesterday_spend"`
+	TodaySpend     float64 `json:"today_spend"`
+	// Ratio is today over yesterday; it is omitted when yesterday had no
+	// spend.
+	Ratio         float64 `json:"ratio,omitempty"`
+	MaxSpendRatio float64 `json:"max_spend_ratio"`
+}
+
+func DefaultPolicyPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "anomaly.yaml"), nil
+}
+
+// LoadPolicy returns nil without error when no policy file exists, which
+// leaves the anomaly guard off.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read spend anomaly policy %s: %w", path, err)
+	}
+	policy := &Policy{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(policy); err != nil {
+		return nil, fmt.Errorf("decode spend anomaly policy %s: %w", path, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid spend anomaly policy %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+func (p *Policy) Validate() error {
+	if p.SchemaVersion != PolicySchemaVersion {
+		return fmt.Errorf("unsupported schema_version=%d (expected %d)", p.SchemaVersion, PolicySchemaVersion)
+	}
+	names := map[string]struct{}{}
+	for index := range p.Rules {
+		rule := &p.Rules[index]
+		rule.Name = strings.TrimSpace(rule.Name)
+		if rule.Name == "" {
+			return fmt.Errorf("rules[%d]: name is required", index)
+		}
+		if _, exists := names[rule.Name]; exists {
+			return fmt.Errorf("rules[%d]: duplicate rule name %q", index, rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+		if rule.MaxSpendRatio <= 1 {
+			return fmt.Errorf("rule %q: max_spend_ratio must be > 1", rule.Name)
+		}
+		if rule.MinSpend < 0 {
+			return fmt.Errorf("rule %q: min_spend must be >= 0", rule.Name)
+		}
+		for accountIndex, account := range rule.Accounts {
+			rule.Accounts[accountIndex] = strings.TrimPrefix(strings.TrimSpace(account), "act_")
+		}
+		if labels := strings.TrimSpace(rule.Labels); labels != "" {
+			selector, err := config.ParseSelector(labels)
+			if err != nil {
+				return fmt.Errorf("rule %q: %w", rule.Name, err)
+			}
+			rule.selector = &selector
+		}
+	}
+	return nil
+}
+
+func (r *Rule) covers(account string, labels map[string]string) bool {
+	if r.selector != nil && !r.selector.Matches(labels) {
+		return false
+	}
+	if len(r.Accounts) == 0 {
+		return true
+	}
+	for _, candidate := range r.Accounts {
+		if candidate == account {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns the rules account's spend trips.
+func (p *Policy) Check(account string, labels map[string]string, spend Spend) []Finding {
+	if p == nil {
+		return nil
+	}
+	findings := make([]Finding, 0)
+	for index := range p.Rules {
+		rule := &p.Rules[index]
+		if !rule.covers(account, labels) || spend.Today <= 0 || spend.Today < rule.MinSpend {
+			continue
+		}
+		finding := Finding{
+			Rule:           rule.Name,
+			Account:        account,
+			YesterdaySpend: spend.Yesterday,
+			TodaySpend:     spend.Today,
+			MaxSpendRatio:  rule.MaxSpendRatio,
+		}
+		if spend.Yesterday > 0 {
+			finding.Ratio = math.Round(spend.Today/spend.Yesterday*100) / 100
+			if spend.Today <= spend.Yesterday*rule.MaxSpendRatio {
+				continue
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// Classify reports whether mutation resumes an object or raises its budget,
+// given the object's current fields. Other mutations return "".
+func Classify(mutation graph.Mutation, current map[string]any) string {
+	if strings.EqualFold(strings.TrimSpace(mutation.Form["status"]), "ACTIVE") &&
+		!strings.EqualFold(fmt.Sprint(current["status"]), "ACTIVE") {
+		return ChangeResume
+	}
+	for _, param := range budgetParams {
+		raw := strings.TrimSpace(mutation.Form[param])
+		if raw == "" {
+			continue
+		}
+		proposed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			continue
+		}
+		existing, _ := number(current[param])
+		if proposed > existing {
+			return ChangeBudgetIncrease
+		}
+	}
+	return ""
+}
+
+// objectID returns the id a mutation updates, or "" for creates, edges and
+// account-level requests, which cannot resume or raise an existing object.
+func objectID(mutation graph.Mutation) string {
+	if mutation.Method != "POST" {
+		return ""
+	}
+	path := strings.Trim(mutation.Path, "/")
+	if path == "" || strings.Contains(path, "/") || strings.HasPrefix(path, "act_") {
+		return ""
+	}
+	return path
+}
+
+func touchesStatusOrBudget(mutation graph.Mutation) bool {
+	if strings.TrimSpace(mutation.Form["status"]) != "" {
+		return true
+	}
+	for _, param := range budgetParams {
+		if strings.TrimSpace(mutation.Form[param]) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Guard returns a graph.MutationGuard enforcing p. It reads the object being
+// changed and its account's spend through the mutation's credentials; spend is
+// read once per account per process. An --override-anomaly reason on the
+// invocation lets mutations through. labels resolves the labels of the
+// invoking profile and may be nil.
+func (p *Policy) Guard(labels func(profile string) map[string]string) graph.MutationGuard {
+	var (
+		mu     sync.Mutex
+		spends = map[string]Spend{}
+	)
+	accountSpend := func(ctx context.Context, read readFunc, account string) (Spend, error) {
+		mu.Lock()
+		cached, ok := spends[account]
+		mu.Unlock()
+		if ok {
+			return cached, nil
+		}
+		spend, err := readSpend(ctx, read, account)
+		if err != nil {
+			return Spend{}, err
+		}
+		mu.Lock()
+		spends[account] = spend
+		mu.Unlock()
+		return spend, nil
+	}
+
+	return func(ctx context.Context, mutation graph.Mutation) error {
+		id := objectID(mutation)
+		if id == "" || !touchesStatusOrBudget(mutation) || mutation.Read == nil {
+			return nil
+		}
+		invocation := audit.InvocationFromContext(ctx)
+		if strings.TrimSpace(invocation.AnomalyOverride) != "" {
+			return nil
+		}
+
+		fields := []string{"account_id", "status"}
+		for _, param := range budgetParams {
+			if strings.TrimSpace(mutation.Form[param]) != "" {
+				fields = append(fields, param)
+			}
+		}
+		current, err := mutation.Read(ctx, id, map[string]string{"fields": strings.Join(fields, ",")})
+		if err != nil {
+			return unverified(mutation, fmt.Errorf("read %s: %w", id, err))
+		}
+		change := Classify(mutation, current.Body)
+		if change == "" {
+			return nil
+		}
+		accountID, _ := current.Body["account_id"].(string)
+		account := strings.TrimPrefix(strings.TrimSpace(accountID), "act_")
+		if account == "" {
+			return unverified(mutation, fmt.Errorf("%s did not report an account_id", id))
+		}
+		var profileLabels map[string]string
+		if labels != nil {
+			profileLabels = labels(invocation.Profile)
+		}
+		if !p.coversAny(account, profileLabels) {
+			return nil
+		}
+		spend, err := accountSpend(ctx, mutation.Read, account)
+		if err != nil {
+			return unverified(mutation, err)
+		}
+		findings := p.Check(account, profileLabels, spend)
+		if len(findings) == 0 {
+			return nil
+		}
+		return &graph.APIError{
+			Type:      errorTypeAnomaly,
+			Code:      errorCodeAnomaly,
+			Message:   fmt.Sprintf("%s %s blocked: %s while account act_%s spend spiked (today %.2f, yesterday %.2f; rule %q)", mutation.Method, mutation.Path, strings.ReplaceAll(change, "_", " "), account, spend.Today, spend.Yesterday, findings[0].Rule),
+			Retryable: false,
+			Diagnostics: map[string]any{
+				"change":   change,
+				"findings": findings,
+			},
+			Remediation: remediation("Account spend is anomalous; resumes and budget increases are held back."),
+		}
+	}
+}
+
+func (p *Policy) coversAny(account string, labels map[string]string) bool {
+	for index := range p.Rules {
+		if p.Rules[index].covers(account, labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// unverified blocks a mutation whose spend could not be checked; the guard
+// errs on the side of blocking.
+func unverified(mutation graph.Mutation, err error) error {
+	return &graph.APIError{
+		Type:      errorTypeAnomaly,
+		Code:      errorCodeAnomaly,
+		Message:   fmt.Sprintf("%s %s blocked: spend anomaly check failed: %v", mutation.Method, mutation.Path, err),
+		Retryable: false,
+		Diagnostics: map[string]any{
+			"check_error": err.Error(),
+		},
+		Remediation: remediation("The spend anomaly check could not read the object or its account's spend."),
+	}
+}
+
+func remediation(summary string) *graph.Remediation {
+	return &graph.Remediation{
+		Category: graph.RemediationCategoryPermission,
+		Summary:  summary,
+		Actions: []string{
+			"Review the account's spend with `meta insights run` before resuming delivery or raising budgets.",
+			"If the change is intended, rerun with --override-anomaly \"<reason>\"; the reason is recorded in the audit log.",
+		},
+	}
+}
+
+type readFunc func(ctx context.Context, path string, query map[string]string) (*graph.Response, error)
+
+func readSpend(ctx context.Context, read readFunc, account string) (Spend, error) {
+	var spend Spend
+	for _, preset := range []string{"yesterday", "today"} {
+		response, err := read(ctx, "act_"+account+"/insights", map[string]string{
+			"fields":      "spend",
+			"date_preset": preset,
+			"level":       "account",
+		})
+		if err != nil {
+			return Spend{}, fmt.Errorf("read %s spend of act_%s: %w", preset, account, err)
+		}
+		amount := 0.0
+		if rows, ok := response.Body["data"].([]any); ok {
+			for _, row := range rows {
+				if fields, ok := row.(map[string]any); ok {
+					value, _ := number(fields["spend"])
+					amount += value
+				}
+			}
+		}
+		if preset == "today" {
+			spend.Today = amount
+		} else {
+			spend.Yesterday = amount
+		}
+	}
+	return spend, nil
+}
+
+// number reads Graph numbers, which arrive as JSON numbers or numeric strings.
+func number(value any) (float64, bool) {
+	switch typed := value.(type) {
+	case float64:
+		return typed, true
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(typed), 64)
+		return parsed, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,155 @@
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const testPolicy = `schema_version: 1
+rules:
+  - name: acme
+    accounts: [act_111]
+    max_spend_ratio: 2
+    min_spend: 50
+`
+
+func loadTestPolicy(t *testing.T) *Policy {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "anomaly.yaml")
+	if err := os.WriteFile(path, []byte(testPolicy), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	return policy
+}
+
+func TestCheckFlagsSpikesAboveRatioAndMinimum(t *testing.T) {
+	t.Parallel()
+
+	policy := loadTestPolicy(t)
+	cases := []struct {
+		name    string
+		account string
+		spend   Spend
+		want    bool
+	}{
+		{"spike", "111", Spend{Yesterday: 100, Today: 250}, true},
+		{"within ratio", "111", Spend{Yesterday: 100, Today: 180}, false},
+		{"below minimum", "111", Spend{Yesterday: 10, Today: 40}, false},
+		{"no spend yesterday", "111", Spend{Yesterday: 0, Today: 60}, true},
+		{"other account", "222", Spend{Yesterday: 100, Today: 900}, false},
+	}
+	for _, tc := range cases {
+		findings := policy.Check(tc.account, nil, tc.spend)
+		if got := len(findings) > 0; got != tc.want {
+			t.Fatalf("%s: expected anomaly=%t, got %#v", tc.name, tc.want, findings)
+		}
+	}
+}
+
+func TestClassifyResumesAndBudgetIncreases(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		form    map[string]string
+		current map[string]any
+		want    string
+	}{
+		{"resume", map[string]string{"status": "ACTIVE"}, map[string]any{"status": "PAUSED"}, ChangeResume},
+		{"already active", map[string]string{"status": "ACTIVE"}, map[string]any{"status": "ACTIVE"}, ""},
+		{"pause", map[string]string{"status": "PAUSED"}, map[string]any{"status": "ACTIVE"}, ""},
+		{"budget increase", map[string]string{"daily_budget": "6000"}, map[string]any{"daily_budget": "5000"}, ChangeBudgetIncrease},
+		{"budget decrease", map[string]string{"daily_budget": "4000"}, map[string]any{"daily_budget": "5000"}, ""},
+	}
+	for _, tc := range cases {
+		got := Classify(graph.Mutation{Method: "POST", Path: "123", Form: tc.form}, tc.current)
+		if got != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestLoadPolicyRejectsRatioNotAboveOne(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "anomaly.yaml")
+	if err := os.WriteFile(path, []byte("schema_version: 1\nrules:\n  - name: flat\n    max_spend_ratio: 1\n"), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	if _, err := LoadPolicy(path); err == nil || !strings.Contains(err.Error(), "max_spend_ratio must be > 1") {
+		t.Fatalf("expected ratio validation error, got %v", err)
+	}
+}
+
+func TestGuardBlocksResumeDuringSpikeUnlessOverridden(t *testing.T) {
+	t.Parallel()
+
+	policy := loadTestPolicy(t)
+	var reads []string
+	read := func(_ context.Context, path string, query map[string]string) (*graph.Response, error) {
+		reads = append(reads, path+"?"+query["date_preset"])
+		switch {
+		case path == "123":
+			return &graph.Response{Body: map[string]any{"account_id": "111", "status": "PAUSED"}}, nil
+		case query["date_preset"] == "yesterday":
+			return &graph.Response{Body: map[string]any{"data": []any{map[string]any{"spend": "100.00"}}}}, nil
+		default:
+			return &graph.Response{Body: map[string]any{"data": []any{map[string]any{"spend": "420.50"}}}}, nil
+		}
+	}
+	guard := policy.Guard(nil)
+	mutation := graph.Mutation{Method: "POST", Path: "123", Form: map[string]string{"status": "ACTIVE"}, Read: read}
+
+	err := guard(context.Background(), mutation)
+	apiErr := &graph.APIError{}
+	if !errors.As(err, &apiErr) || apiErr.Type != "spend_anomaly_gate" || apiErr.Diagnostics["change"] != ChangeResume {
+		t.Fatalf("expected the resume to be blocked, got %v", err)
+	}
+	if strings.Join(reads, ",") != "123?,act_111/insights?yesterday,act_111/insights?today" {
+		t.Fatalf("unexpected reads %v", reads)
+	}
+
+	reads = nil
+	if err := guard(context.Background(), mutation); err == nil || strings.Join(reads, ",") != "123?" {
+		t.Fatalf("expected spend to be read once per account, got %v (reads %v)", err, reads)
+	}
+
+	overridden := audit.WithInvocation(context.Background(), audit.Invocation{AnomalyOverride: "launch day"})
+	if err := guard(overridden, mutation); err != nil {
+		t.Fatalf("expected --override-anomaly to let the resume through, got %v", err)
+	}
+
+	pause := graph.Mutation{Method: "POST", Path: "123", Form: map[string]string{"status": "PAUSED"}, Read: read}
+	if err := guard(context.Background(), pause); err != nil {
+		t.Fatalf("expected pauses to pass, got %v", err)
+	}
+}
+
+func TestGuardBlocksWhenCheckCannotRead(t *testing.T) {
+	t.Parallel()
+
+	guard := loadTestPolicy(t).Guard(nil)
+	err := guard(context.Background(), graph.Mutation{
+		Method: "POST",
+		Path:   "123",
+		Form:   map[string]string{"daily_budget": "9000"},
+		Read: func(context.Context, string, map[string]string) (*graph.Response, error) {
+			return nil, errors.New("network down")
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "spend anomaly check failed") {
+		t.Fatalf("expected an unverifiable change to be blocked, got %v", err)
+	}
+}
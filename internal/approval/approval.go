@@ -0,0 +1,549 @@
+// Package approval enforces a two-person rule on high-risk Graph mutations.
+// A policy file names the operators (by ed25519 public key) and the operations
+// that need approval: budgets above a threshold, deletes and permission grants.
+// Such a mutation fails closed: the CLI writes an approval request signed by the
+// requesting operator, a second operator signs it with `meta approve`, and only
+// a rerun carrying that approval token sends the mutation.
+package approval
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	PolicySchemaVersion   = 1
+	RequestSchemaVersion  = 1
+	ApprovalSchemaVersion = 1
+
+	DefaultTTL = 24 * time.Hour
+
+	ReasonDelete          = "delete"
+	ReasonBudget          = "budget_above_threshold"
+	ReasonPermissionGrant = "permission_grant"
+)
+
+var (
+	budgetParams = []string{"daily_budget", "lifetime_budget", "spend_cap"}
+	// permissionEdges grant people, partners or other accounts access to an
+	// asset.
+	permissionEdges = map[string]struct{}{
+		"assigned_users":  {},
+		"business_users":  {},
+		"system_users":    {},
+		"agencies":        {},
+		"userpermissions": {},
+		"adaccounts":      {},
+		"partners":        {},
+	}
+	// unsignedParams never reach the fingerprint: they are credentials, not
+	// part of the change being approved.
+	unsignedParams = map[string]struct{}{
+		"access_token":    {},
+		"appsecret_proof": {},
+	}
+)
+
+// Policy is the approval policy file.
+type Policy struct {
+	SchemaVersion int `yaml:"schema_version"`
+	// Operators maps operator names to base64 ed25519 public keys.
+	Operators map[string]string `yaml:"operators"`
+	Rules     Rules             `yaml:"rules"`
+	// ApprovalTTL bounds how long requests and approvals stay valid, e.g. 4h.
+	ApprovalTTL string `yaml:"approval_ttl,omitempty"`
+}
+
+type Rules struct {
+	// BudgetAbove requires approval for daily_budget, lifetime_budget or
+	// spend_cap above this amount in minor currency units; zero disables it.
+	BudgetAbove      int64 `yaml:"budget_above,omitempty"`
+	Delete           bool  `yaml:"delete,omitempty"`
+	PermissionGrants bool  `yaml:"permission_grants,omitempty"`
+}
+
+func DefaultPolicyPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "approval", "policy.yaml"), nil
+}
+
+func DefaultKeyPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "approval", "operator.key"), nil
+}
+
+func DefaultRequestDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "approval", "requests"), nil
+}
+
+// LoadPolicy returns nil without error when no policy file exists, which
+// leaves approvals off.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read approval policy %s: %w", path, err)
+	}
+	policy := &Policy{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(policy); err != nil {
+		return nil, fmt.Errorf("decode approval policy %s: %w", path, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid approval policy %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+func (p *Policy) Validate() error {
+	if p.SchemaVersion != PolicySchemaVersion {
+		return fmt.Errorf("unsupported schema_version=%d (expected %d)", p.SchemaVersion, PolicySchemaVersion)
+	}
+	if len(p.Operators) < 2 {
+		return errors.New("at least two operators are required for a two-person rule")
+	}
+	for name, key := range p.Operators {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("operator name is required")
+		}
+		if _, err := decodePublicKey(key); err != nil {
+			return fmt.Errorf("operator %q: %w", name, err)
+		}
+	}
+	if p.Rules.BudgetAbove < 0 {
+		return errors.New("rules.budget_above must be >= 0")
+	}
+	if _, err := p.TTL(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *Policy) TTL() (time.Duration, error) {
+	raw := strings.TrimSpace(p.ApprovalTTL)
+	if raw == "" {
+		return DefaultTTL, nil
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("invalid approval_ttl %q: expected a positive duration such as 4h", p.ApprovalTTL)
+	}
+	return ttl, nil
+}
+
+// Reasons lists why a mutation needs approval under the policy; none means it
+// can be sent as is.
+func (p *Policy) Reasons(mutation graph.Mutation) []string {
+	if p == nil {
+		return nil
+	}
+	reasons := make([]string, 0)
+	if p.Rules.Delete && (mutation.Method == "DELETE" || strings.EqualFold(mutation.Form["status"], "DELETED")) {
+		reasons = append(reasons, ReasonDelete)
+	}
+	if p.Rules.BudgetAbove > 0 {
+		for _, param := range budgetParams {
+			amount, err := strconv.ParseInt(strings.TrimSpace(mutation.Form[param]), 10, 64)
+			if err == nil && amount > p.Rules.BudgetAbove {
+				reasons = append(reasons, fmt.Sprintf("%s: %s=%d is above %d", ReasonBudget, param, amount, p.Rules.BudgetAbove))
+			}
+		}
+	}
+	if p.Rules.PermissionGrants && mutation.Method == "POST" {
+		segments := strings.Split(strings.Trim(mutation.Path, "/"), "/")
+		if _, ok := permissionEdges[segments[len(segments)-1]]; ok && len(segments) > 1 {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", ReasonPermissionGrant, segments[len(segments)-1]))
+		}
+	}
+	return reasons
+}
+
+// Identity is an operator's signing key.
+type Identity struct {
+	Operator   string `json:"operator"`
+	PrivateKey string `json:"private_key"`
+}
+
+// GenerateIdentity creates a key for operator and writes it to path, readable
+// only by the current user. The returned public key goes into the policy.
+func GenerateIdentity(path string, operator string) (string, error) {
+	operator = strings.TrimSpace(operator)
+	if operator == "" {
+		return "", errors.New("operator name is required")
+	}
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("approval key %s already exists; remove it first to replace it", path)
+	}
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("generate approval key: %w", err)
+	}
+	payload, err := json.MarshalIndent(Identity{Operator: operator, PrivateKey: base64.StdEncoding.EncodeToString(private.Seed())}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode approval key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("create approval key directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, append(payload, '\n'), 0o600); err != nil {
+		return "", fmt.Errorf("write approval key %s: %w", path, err)
+	}
+	return base64.StdEncoding.EncodeToString(public), nil
+}
+
+func LoadIdentity(path string) (*Identity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read approval key %s: %w", path, err)
+	}
+	identity := &Identity{}
+	if err := json.Unmarshal(data, identity); err != nil {
+		return nil, fmt.Errorf("decode approval key %s: %w", path, err)
+	}
+	identity.Operator = strings.TrimSpace(identity.Operator)
+	if identity.Operator == "" {
+		return nil, fmt.Errorf("approval key %s has no operator", path)
+	}
+	if _, err := identity.privateKey(); err != nil {
+		return nil, fmt.Errorf("approval key %s: %w", path, err)
+	}
+	return identity, nil
+}
+
+func (i *Identity) privateKey() (ed25519.PrivateKey, error) {
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(i.PrivateKey))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, errors.New("invalid private key")
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+func (i *Identity) sign(message []byte) (string, error) {
+	key, err := i.privateKey()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(key, message)), nil
+}
+
+// Operation is the mutation an approval covers.
+type Operation struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Version string            `json:"version,omitempty"`
+	Params  map[string]string `json:"params,omitempty"`
+}
+
+// Request is the signed approval request file handed to the second operator.
+type Request struct {
+	SchemaVersion int       `json:"schema_version"`
+	ID            string    `json:"id"`
+	Requester     string    `json:"requester"`
+	Command       string    `json:"command,omitempty"`
+	Profile       string    `json:"profile,omitempty"`
+	Operation     Operation `json:"operation"`
+	Reasons       []string  `json:"reasons"`
+	Fingerprint   string    `json:"fingerprint"`
+	RequestedAt   string    `json:"requested_at"`
+	ExpiresAt     string    `json:"expires_at"`
+	Signature     string    `json:"signature,omitempty"`
+}
+
+// Approval is the second operator's signed decision; its base64 encoding is
+// the approval token.
+type Approval struct {
+	SchemaVersion int    `json:"schema_version"`
+	RequestID     string `json:"request_id"`
+	Fingerprint   string `json:"fingerprint"`
+	Requester     string `json:"requester"`
+	Approver      string `json:"approver"`
+	ApprovedAt    string `json:"approved_at"`
+	ExpiresAt     string `json:"expires_at"`
+	Signature     string `json:"signature,omitempty"`
+}
+
+// NewRequest builds and signs the approval request for mutation.
+func NewRequest(policy *Policy, identity *Identity, command string, profile string, mutation graph.Mutation, reasons []string, now time.Time) (*Request, error) {
+	ttl, err := policy.TTL()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := policy.Operators[identity.Operator]; !ok {
+		return nil, fmt.Errorf("operator %q is not listed in the approval policy", identity.Operator)
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("generate approval request id: %w", err)
+	}
+	request := &Request{
+		SchemaVersion: RequestSchemaVersion,
+		ID:            hex.EncodeToString(id),
+		Requester:     identity.Operator,
+		Command:       strings.TrimSpace(command),
+		Profile:       strings.TrimSpace(profile),
+		Operation:     operationOf(mutation),
+		Reasons:       reasons,
+		Fingerprint:   Fingerprint(mutation),
+		RequestedAt:   now.UTC().Format(time.RFC3339),
+		ExpiresAt:     now.Add(ttl).UTC().Format(time.RFC3339),
+	}
+	message, err := request.message()
+	if err != nil {
+		return nil, err
+	}
+	if request.Signature, err = identity.sign(message); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// Fingerprint identifies a mutation by method, path and params, so an approval
+// covers exactly the change that was reviewed.
+func Fingerprint(mutation graph.Mutation) string {
+	operation := operationOf(mutation)
+	keys := make([]string, 0, len(operation.Params))
+	for key := range operation.Params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sum := sha256.New()
+	fmt.Fprintf(sum, "%s\n%s\n", operation.Method, operation.Path)
+	for _, key := range keys {
+		fmt.Fprintf(sum, "%s=%s\n", key, operation.Params[key])
+	}
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+func operationOf(mutation graph.Mutation) Operation {
+	params := make(map[string]string, len(mutation.Form))
+	for key, value := range mutation.Form {
+		if _, skip := unsignedParams[key]; skip {
+			continue
+		}
+		params[key] = value
+	}
+	if len(params) == 0 {
+		params = nil
+	}
+	return Operation{
+		Method:  strings.ToUpper(strings.TrimSpace(mutation.Method)),
+		Path:    strings.Trim(strings.TrimSpace(mutation.Path), "/"),
+		Version: mutation.Version,
+		Params:  params,
+	}
+}
+
+func (r *Request) message() ([]byte, error) {
+	unsigned := *r
+	unsigned.Signature = ""
+	message, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("encode approval request: %w", err)
+	}
+	return message, nil
+}
+
+func (a *Approval) message() ([]byte, error) {
+	unsigned := *a
+	unsigned.Signature = ""
+	message, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("encode approval: %w", err)
+	}
+	return message, nil
+}
+
+// Verify checks the requester's signature, the fingerprint and expiry.
+func (r *Request) Verify(policy *Policy, now time.Time) error {
+	if r.SchemaVersion != RequestSchemaVersion {
+		return fmt.Errorf("unsupported approval request schema_version=%d (expected %d)", r.SchemaVersion, RequestSchemaVersion)
+	}
+	message, err := r.message()
+	if err != nil {
+		return err
+	}
+	if err := policy.verify(r.Requester, message, r.Signature); err != nil {
+		return fmt.Errorf("approval request %s: %w", r.ID, err)
+	}
+	if Fingerprint(graph.Mutation{Method: r.Operation.Method, Path: r.Operation.Path, Form: r.Operation.Params}) != r.Fingerprint {
+		return fmt.Errorf("approval request %s: fingerprint does not match its operation", r.ID)
+	}
+	return checkExpiry("approval request "+r.ID, r.ExpiresAt, now)
+}
+
+// Approve signs request as approver. The approver must be a different
+// operator than the requester.
+func Approve(policy *Policy, approver *Identity, request *Request, now time.Time) (*Approval, string, error) {
+	if err := request.Verify(policy, now); err != nil {
+		return nil, "", err
+	}
+	if approver.Operator == request.Requester {
+		return nil, "", fmt.Errorf("two-person rule: %s requested this operation and cannot approve it", request.Requester)
+	}
+	if _, ok := policy.Operators[approver.Operator]; !ok {
+		return nil, "", fmt.Errorf("operator %q is not listed in the approval policy", approver.Operator)
+	}
+	ttl, err := policy.TTL()
+	if err != nil {
+		return nil, "", err
+	}
+	approval := &Approval{
+		SchemaVersion: ApprovalSchemaVersion,
+		RequestID:     request.ID,
+		Fingerprint:   request.Fingerprint,
+		Requester:     request.Requester,
+		Approver:      approver.Operator,
+		ApprovedAt:    now.UTC().Format(time.RFC3339),
+		ExpiresAt:     now.Add(ttl).UTC().Format(time.RFC3339),
+	}
+	message, err := approval.message()
+	if err != nil {
+		return nil, "", err
+	}
+	if approval.Signature, err = approver.sign(message); err != nil {
+		return nil, "", err
+	}
+	token, err := json.Marshal(approval)
+	if err != nil {
+		return nil, "", fmt.Errorf("encode approval token: %w", err)
+	}
+	return approval, base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// ParseToken decodes an approval token without verifying it.
+func ParseToken(token string) (*Approval, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(token))
+	if err != nil {
+		return nil, fmt.Errorf("decode approval token: %w", err)
+	}
+	approval := &Approval{}
+	if err := json.Unmarshal(payload, approval); err != nil {
+		return nil, fmt.Errorf("decode approval token: %w", err)
+	}
+	if approval.SchemaVersion != ApprovalSchemaVersion {
+		return nil, fmt.Errorf("unsupported approval token schema_version=%d (expected %d)", approval.SchemaVersion, ApprovalSchemaVersion)
+	}
+	return approval, nil
+}
+
+// Verify checks that the approval was signed by a policy operator other than
+// requester, covers fingerprint and has not expired.
+func (a *Approval) Verify(policy *Policy, requester string, fingerprint string, now time.Time) error {
+	message, err := a.message()
+	if err != nil {
+		return err
+	}
+	if err := policy.verify(a.Approver, message, a.Signature); err != nil {
+		return fmt.Errorf("approval of request %s: %w", a.RequestID, err)
+	}
+	if a.Approver == a.Requester {
+		return fmt.Errorf("approval of request %s: two-person rule requires an approver other than %s", a.RequestID, a.Requester)
+	}
+	if a.Requester != requester {
+		return fmt.Errorf("approval of request %s was granted to %s, not %s", a.RequestID, a.Requester, requester)
+	}
+	if a.Fingerprint != fingerprint {
+		return fmt.Errorf("approval of request %s covers a different operation", a.RequestID)
+	}
+	return checkExpiry("approval of request "+a.RequestID, a.ExpiresAt, now)
+}
+
+func (p *Policy) verify(operator string, message []byte, signature string) error {
+	encoded, ok := p.Operators[operator]
+	if !ok {
+		return fmt.Errorf("operator %q is not listed in the approval policy", operator)
+	}
+	public, err := decodePublicKey(encoded)
+	if err != nil {
+		return err
+	}
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || !ed25519.Verify(public, message, raw) {
+		return fmt.Errorf("signature of operator %q is invalid", operator)
+	}
+	return nil
+}
+
+func decodePublicKey(encoded string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid ed25519 public key")
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+func checkExpiry(subject string, expiresAt string, now time.Time) error {
+	expires, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return fmt.Errorf("%s: invalid expires_at %q", subject, expiresAt)
+	}
+	if !now.Before(expires) {
+		return fmt.Errorf("%s expired at %s", subject, expiresAt)
+	}
+	return nil
+}
+
+func LoadRequest(path string) (*Request, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read approval request %s: %w", path, err)
+	}
+	request := &Request{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(request); err != nil {
+		return nil, fmt.Errorf("decode approval request %s: %w", path, err)
+	}
+	return request, nil
+}
+
+// WriteRequest writes request into dir as <id>.json and returns its path.
+func WriteRequest(dir string, request *Request) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create approval request directory %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, request.ID+".json")
+	lock, err := filelock.Acquire(path)
+	if err != nil {
+		return "", err
+	}
+	defer lock.Release()
+	payload, err := json.MarshalIndent(request, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode approval request: %w", err)
+	}
+	if err := os.WriteFile(path, append(payload, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("write approval request %s: %w", path, err)
+	}
+	return path, nil
+}
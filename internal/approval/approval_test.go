@@ -0,0 +1,153 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func testOperators(t *testing.T) (*Policy, *Identity, *Identity) {
+	t.Helper()
+
+	dir := t.TempDir()
+	policy := &Policy{
+		SchemaVersion: PolicySchemaVersion,
+		Operators:     map[string]string{},
+		Rules:         Rules{BudgetAbove: 100000, Delete: true, PermissionGrants: true},
+		ApprovalTTL:   "1h",
+	}
+	identities := make([]*Identity, 0, 2)
+	for _, name := range []string{"alice", "bob"} {
+		path := filepath.Join(dir, name+".key")
+		publicKey, err := GenerateIdentity(path, name)
+		if err != nil {
+			t.Fatalf("generate %s: %v", name, err)
+		}
+		policy.Operators[name] = publicKey
+		identity, err := LoadIdentity(path)
+		if err != nil {
+			t.Fatalf("load %s: %v", name, err)
+		}
+		identities = append(identities, identity)
+	}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("validate policy: %v", err)
+	}
+	return policy, identities[0], identities[1]
+}
+
+func TestPolicyReasons(t *testing.T) {
+	t.Parallel()
+
+	policy := &Policy{Rules: Rules{BudgetAbove: 100000, Delete: true, PermissionGrants: true}}
+	cases := []struct {
+		mutation graph.Mutation
+		want     string
+	}{
+		{graph.Mutation{Method: "DELETE", Path: "120"}, ReasonDelete},
+		{graph.Mutation{Method: "POST", Path: "120", Form: map[string]string{"status": "DELETED"}}, ReasonDelete},
+		{graph.Mutation{Method: "POST", Path: "120", Form: map[string]string{"daily_budget": "250000"}}, ReasonBudget},
+		{graph.Mutation{Method: "POST", Path: "act_1/assigned_users", Form: map[string]string{"user": "9"}}, ReasonPermissionGrant},
+		{graph.Mutation{Method: "POST", Path: "120", Form: map[string]string{"daily_budget": "5000"}}, ""},
+		{graph.Mutation{Method: "POST", Path: "act_1/campaigns", Form: map[string]string{"name": "x"}}, ""},
+	}
+	for _, tc := range cases {
+		reasons := policy.Reasons(tc.mutation)
+		if tc.want == "" {
+			if len(reasons) != 0 {
+				t.Fatalf("expected no reasons for %#v, got %v", tc.mutation, reasons)
+			}
+			continue
+		}
+		if len(reasons) != 1 || !strings.HasPrefix(reasons[0], tc.want) {
+			t.Fatalf("expected %s for %#v, got %v", tc.want, tc.mutation, reasons)
+		}
+	}
+}
+
+func TestApproveEnforcesTwoPersonRule(t *testing.T) {
+	t.Parallel()
+
+	policy, alice, bob := testOperators(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mutation := graph.Mutation{Method: "DELETE", Path: "120", Form: map[string]string{"access_token": "secret"}}
+	request, err := NewRequest(policy, alice, "meta campaign delete", "prod", mutation, policy.Reasons(mutation), now)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+
+	if _, _, err := Approve(policy, alice, request, now); err == nil || !strings.Contains(err.Error(), "two-person rule") {
+		t.Fatalf("expected self-approval to fail, got %v", err)
+	}
+
+	tampered := *request
+	tampered.Operation.Path = "121"
+	if _, _, err := Approve(policy, bob, &tampered, now); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Fatalf("expected tampered request to fail, got %v", err)
+	}
+
+	_, token, err := Approve(policy, bob, request, now)
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	granted, err := ParseToken(token)
+	if err != nil {
+		t.Fatalf("parse token: %v", err)
+	}
+	if err := granted.Verify(policy, "alice", Fingerprint(mutation), now.Add(time.Minute)); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := granted.Verify(policy, "alice", Fingerprint(graph.Mutation{Method: "DELETE", Path: "121"}), now); err == nil {
+		t.Fatal("expected approval for another operation to fail")
+	}
+	if err := granted.Verify(policy, "alice", Fingerprint(mutation), now.Add(2*time.Hour)); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("expected expired approval to fail, got %v", err)
+	}
+}
+
+func TestGateFailsClosedUntilApproved(t *testing.T) {
+	t.Parallel()
+
+	policy, alice, bob := testOperators(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	gate := &Gate{Policy: policy, Identity: alice, RequestDir: t.TempDir(), Now: func() time.Time { return now }}
+	mutation := graph.Mutation{Method: "POST", Path: "120", Form: map[string]string{"daily_budget": "500000"}}
+
+	if err := gate.Guard(context.Background(), graph.Mutation{Method: "POST", Path: "120", Form: map[string]string{"name": "x"}}); err != nil {
+		t.Fatalf("expected uncovered mutation to pass, got %v", err)
+	}
+
+	err := gate.Guard(context.Background(), mutation)
+	apiErr := &graph.APIError{}
+	if !errors.As(err, &apiErr) || apiErr.Type != "approval_required_gate" {
+		t.Fatalf("expected approval_required_gate, got %v", err)
+	}
+	requestFile, _ := apiErr.Diagnostics["request_file"].(string)
+	if _, statErr := os.Stat(requestFile); statErr != nil {
+		t.Fatalf("expected request file, got %v", statErr)
+	}
+
+	request, err := LoadRequest(requestFile)
+	if err != nil {
+		t.Fatalf("load request: %v", err)
+	}
+	_, token, err := Approve(policy, bob, request, now)
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	gate.Tokens = []string{token}
+	if err := gate.Guard(context.Background(), mutation); err != nil {
+		t.Fatalf("expected approved mutation to pass, got %v", err)
+	}
+
+	gate.Identity = bob
+	if err := gate.Guard(context.Background(), mutation); err == nil {
+		t.Fatal("expected an approval granted to alice not to cover bob")
+	}
+}
@@ -0,0 +1,111 @@
+package approval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	errorTypeRequired = "approval_required_gate"
+	errorCodeRequired = 428100
+)
+
+// Gate is the mutation guard that enforces a policy. It fails closed: a
+// mutation the policy covers is only sent with a verified approval.
+type Gate struct {
+	Policy *Policy
+	// Identity signs approval requests and is the requester approvals must be
+	// granted to. Without one, covered mutations are blocked outright.
+	Identity *Identity
+	// IdentityErr explains why Identity could not be loaded.
+	IdentityErr error
+	Tokens      []string
+	RequestDir  string
+	Now         func() time.Time
+}
+
+// Guard satisfies graph.MutationGuard.
+func (g *Gate) Guard(ctx context.Context, mutation graph.Mutation) error {
+	reasons := g.Policy.Reasons(mutation)
+	if len(reasons) == 0 {
+		return nil
+	}
+	now := time.Now()
+	if g.Now != nil {
+		now = g.Now()
+	}
+	if g.Identity == nil {
+		reason := "no operator key is configured"
+		if g.IdentityErr != nil {
+			reason = g.IdentityErr.Error()
+		}
+		return requiredError(fmt.Sprintf("%s %s needs a second operator's approval (%s), but %s", mutation.Method, mutation.Path, strings.Join(reasons, "; "), reason), map[string]any{
+			"reasons": reasons,
+		}, "Create an operator key with `meta approve keygen --operator <name>` and add its public key to the approval policy.")
+	}
+
+	fingerprint := Fingerprint(mutation)
+	rejected := make([]string, 0)
+	for _, token := range g.Tokens {
+		approval, err := ParseToken(token)
+		if err == nil {
+			if approval.Fingerprint != fingerprint {
+				continue
+			}
+			err = approval.Verify(g.Policy, g.Identity.Operator, fingerprint, now)
+		}
+		if err == nil {
+			return nil
+		}
+		rejected = append(rejected, err.Error())
+	}
+
+	invocation := audit.InvocationFromContext(ctx)
+	request, err := NewRequest(g.Policy, g.Identity, invocation.Command, invocation.Profile, mutation, reasons, now)
+	if err != nil {
+		return requiredError(fmt.Sprintf("%s %s needs approval, but the approval request could not be signed: %v", mutation.Method, mutation.Path, err), map[string]any{
+			"reasons": reasons,
+		}, "Check that your operator key matches a public key in the approval policy.")
+	}
+	path, err := WriteRequest(g.RequestDir, request)
+	if err != nil {
+		return requiredError(fmt.Sprintf("%s %s needs approval, but the approval request could not be written: %v", mutation.Method, mutation.Path, err), map[string]any{
+			"reasons": reasons,
+		}, "Check that the approval request directory is writable.")
+	}
+	diagnostics := map[string]any{
+		"reasons":      reasons,
+		"request_file": path,
+		"request_id":   request.ID,
+		"fingerprint":  fingerprint,
+	}
+	if len(rejected) > 0 {
+		diagnostics["rejected_tokens"] = rejected
+	}
+	return requiredError(
+		fmt.Sprintf("%s %s needs a second operator's approval (%s); approval request written to %s", mutation.Method, mutation.Path, strings.Join(reasons, "; "), path),
+		diagnostics,
+		fmt.Sprintf("Send %s to another operator and have them run `meta approve %s`.", path, path),
+		"Rerun this command unchanged with --approval-token <token> (the token or the file `meta approve` wrote).",
+	)
+}
+
+func requiredError(message string, diagnostics map[string]any, actions ...string) error {
+	return &graph.APIError{
+		Type:        errorTypeRequired,
+		Code:        errorCodeRequired,
+		Message:     message,
+		Retryable:   false,
+		Diagnostics: diagnostics,
+		Remediation: &graph.Remediation{
+			Category: graph.RemediationCategoryPermission,
+			Summary:  "The approval policy requires a second operator to approve this mutation.",
+			Actions:  actions,
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	ExportFormatJSONL = "jsonl"
+	ExportFormatCSV   = "csv"
+)
+
+var csvColumns = []string{
+	"sequence",
+	"timestamp",
+	"command",
+	"profile",
+	"method",
+	"path",
+	"graph_version",
+	"target_ids",
+	"payload_hash",
+	"result",
+	"status_code",
+	"error_type",
+	"error_code",
+	"fbtrace_id",
+	"break_glass",
+	"anomaly_override",
+	"prev_hash",
+	"hash",
+}
+
+// Export writes entries in format. JSONL output keeps every field, so an
+// exported full log still verifies; CSV joins target ids with semicolons.
+func Export(w io.Writer, entries []Entry, format string) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case ExportFormatJSONL:
+		encoder := json.NewEncoder(w)
+		for _, entry := range entries {
+			if err := encoder.Encode(entry); err != nil {
+				return fmt.Errorf("encode audit entry: %w", err)
+			}
+		}
+		return nil
+	case ExportFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(csvColumns); err != nil {
+			return fmt.Errorf("write audit csv: %w", err)
+		}
+		for _, entry := range entries {
+			record := []string{
+				strconv.FormatInt(entry.Sequence, 10),
+				entry.Timestamp,
+				entry.Command,
+				entry.Profile,
+				entry.Method,
+				entry.Path,
+				entry.GraphVersion,
+				strings.Join(entry.TargetIDs, ";"),
+				entry.PayloadHash,
+				entry.Result,
+				formatOptionalInt(entry.StatusCode),
+				entry.ErrorType,
+				formatOptionalInt(entry.ErrorCode),
+				entry.FBTraceID,
+				entry.BreakGlass,
+				entry.AnomalyOverride,
+				entry.PrevHash,
+				entry.Hash,
+			}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("write audit csv: %w", err)
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return fmt.Errorf("write audit csv: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported audit export format %q; expected %s|%s", format, ExportFormatJSONL, ExportFormatCSV)
+	}
+}
+
+func formatOptionalInt(value int) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
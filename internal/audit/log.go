@@ -0,0 +1,248 @@
+// Package audit keeps an append-only, hash-chained record of every mutation the
+// CLI sends to the Graph API. Each entry embeds the hash of the entry before
+// it, so editing or deleting a line breaks the chain and `meta audit verify`
+// reports where.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+)
+
+const (
+	SchemaVersion = 1
+
+	ResultSuccess = "success"
+	ResultError   = "error"
+
+	// tailReadSize bounds how much of the log is read to find the chain head.
+	tailReadSize = 64 * 1024
+
+	// lockWait is how long Append waits for another process appending, even
+	// without --wait-lock: a mutation has already been sent by then.
+	lockWait = 5 * time.Second
+)
+
+var ErrPathRequired = errors.New("audit log path is required")
+
+// Entry is one executed mutation. PayloadHash covers the final request payload
+// without credentials; Hash covers every other field, including PrevHash.
+type Entry struct {
+	SchemaVersion int      `json:"schema_version"`
+	Sequence      int64    `json:"sequence"`
+	Timestamp     string   `json:"timestamp"`
+	Command       string   `json:"command,omitempty"`
+	Profile       string   `json:"profile,omitempty"`
+	Method        string   `json:"method"`
+	Path          string   `json:"path"`
+	GraphVersion  string   `json:"graph_version,omitempty"`
+	TargetIDs     []string `json:"target_ids,omitempty"`
+	PayloadHash   string   `json:"payload_hash"`
+	Result        string   `json:"result"`
+	StatusCode    int      `json:"status_code,omitempty"`
+	ErrorType     string   `json:"error_type,omitempty"`
+	ErrorCode     int      `json:"error_code,omitempty"`
+	FBTraceID     string   `json:"fbtrace_id,omitempty"`
+	// BreakGlass is the reason given with --break-glass to override a freeze
+	// window.
+	BreakGlass string `json:"break_glass,omitempty"`
+	// AnomalyOverride is the reason given with --override-anomaly to resume
+	// or raise a budget despite a spend spike.
+	AnomalyOverride string `json:"anomaly_override,omitempty"`
+	PrevHash        string `json:"prev_hash"`
+	Hash            string `json:"hash"`
+}
+
+// ComputeHash returns the chain hash of e: sha256 over its JSON encoding with
+// Hash cleared.
+func (e Entry) ComputeHash() (string, error) {
+	e.Hash = ""
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("encode audit entry: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "audit", "mutations.jsonl"), nil
+}
+
+// Append chains entry onto the log at path and writes it as one line. The
+// sequence, prev_hash and hash fields are assigned here. Concurrent appends,
+// from other processes or goroutines, are serialized through filelock.
+func Append(path string, entry Entry) (Entry, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return Entry{}, ErrPathRequired
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return Entry{}, fmt.Errorf("create audit log directory for %s: %w", path, err)
+	}
+
+	lock, err := filelock.AcquireWait(path, lockWait)
+	if err != nil {
+		return Entry{}, fmt.Errorf("lock audit log: %w", err)
+	}
+	defer lock.Release()
+
+	head, err := lastEntry(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	entry.SchemaVersion = SchemaVersion
+	entry.Sequence = 1
+	entry.PrevHash = ""
+	if head != nil {
+		entry.Sequence = head.Sequence + 1
+		entry.PrevHash = head.Hash
+	}
+	entry.Hash, err = entry.ComputeHash()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return Entry{}, fmt.Errorf("encode audit entry: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return Entry{}, fmt.Errorf("open audit log %s: %w", path, err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return Entry{}, fmt.Errorf("write audit log %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return Entry{}, fmt.Errorf("close audit log %s: %w", path, err)
+	}
+	return entry, nil
+}
+
+// Read returns every entry in the log in the order written. A missing log reads
+// as empty.
+func Read(path string) ([]Entry, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, ErrPathRequired
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Entry{}, nil
+		}
+		return nil, fmt.Errorf("open audit log %s: %w", path, err)
+	}
+	defer file.Close()
+
+	entries := []Entry{}
+	err = scanLines(file, func(lineNumber int, line []byte) error {
+		entry, err := decodeEntry(line)
+		if err != nil {
+			return fmt.Errorf("decode audit log %s line %d: %w", path, lineNumber, err)
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func decodeEntry(line []byte) (Entry, error) {
+	var entry Entry
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&entry); err != nil {
+		return Entry{}, err
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return Entry{}, errors.New("multiple JSON values")
+		}
+		return Entry{}, err
+	}
+	if entry.SchemaVersion != SchemaVersion {
+		return Entry{}, fmt.Errorf("unsupported schema_version=%d (expected %d)", entry.SchemaVersion, SchemaVersion)
+	}
+	return entry, nil
+}
+
+func scanLines(reader io.Reader, visit func(lineNumber int, line []byte) error) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), tailReadSize)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := visit(lineNumber, line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read audit log: %w", err)
+	}
+	return nil
+}
+
+// lastEntry decodes the final line of the log without reading the whole file.
+func lastEntry(path string) (*Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open audit log %s: %w", path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat audit log %s: %w", path, err)
+	}
+	offset := info.Size() - tailReadSize
+	if offset < 0 {
+		offset = 0
+	}
+	tail := make([]byte, info.Size()-offset)
+	if _, err := file.ReadAt(tail, offset); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("read audit log %s: %w", path, err)
+	}
+	tail = bytes.TrimSpace(tail)
+	if len(tail) == 0 {
+		return nil, nil
+	}
+	if index := bytes.LastIndexByte(tail, '\n'); index >= 0 {
+		tail = tail[index+1:]
+	} else if offset > 0 {
+		return nil, fmt.Errorf("audit log %s: last entry exceeds %d bytes", path, tailReadSize)
+	}
+	entry, err := decodeEntry(tail)
+	if err != nil {
+		return nil, fmt.Errorf("decode last audit entry in %s: %w", path, err)
+	}
+	return &entry, nil
+}
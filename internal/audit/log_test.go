@@ -0,0 +1,284 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func appendTestEntries(t *testing.T, path string, count int) []Entry {
+	t.Helper()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := make([]Entry, 0, count)
+	for i := 0; i < count; i++ {
+		entry := NewEntry(Invocation{Command: "meta campaign pause", Profile: "prod"}, graph.Mutation{
+			Method:   http.MethodPost,
+			Path:     "/12345",
+			Version:  "v25.0",
+			Form:     map[string]string{"status": "PAUSED"},
+			Response: &graph.Response{StatusCode: http.StatusOK, Body: map[string]any{"success": true}},
+		}, base.Add(time.Duration(i)*time.Hour))
+		appended, err := Append(path, entry)
+		if err != nil {
+			t.Fatalf("append entry %d: %v", i, err)
+		}
+		entries = append(entries, appended)
+	}
+	return entries
+}
+
+func TestAppendChainsEntries(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit", "mutations.jsonl")
+	appended := appendTestEntries(t, path, 3)
+
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Sequence != int64(i+1) {
+			t.Fatalf("entry %d: unexpected sequence %d", i, entry.Sequence)
+		}
+		if entry.Hash != appended[i].Hash || entry.Hash == "" {
+			t.Fatalf("entry %d: unexpected hash %q", i, entry.Hash)
+		}
+	}
+	if entries[0].PrevHash != "" || entries[1].PrevHash != entries[0].Hash || entries[2].PrevHash != entries[1].Hash {
+		t.Fatalf("entries are not chained: %+v", entries)
+	}
+	if _, err := os.Stat(path + ".lock"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected lock file to be released, got %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 audit log, got %o", info.Mode().Perm())
+	}
+
+	result, err := Verify(path)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if !result.Valid || result.Entries != 3 || result.HeadHash != entries[2].Hash {
+		t.Fatalf("unexpected verify result %+v", result)
+	}
+}
+
+func TestAppendFromConcurrentGoroutinesKeepsTheChain(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit", "mutations.jsonl")
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entry := NewEntry(Invocation{Command: "meta bulk apply"}, graph.Mutation{
+				Method:   http.MethodPost,
+				Path:     "/12345",
+				Response: &graph.Response{StatusCode: http.StatusOK},
+			}, time.Date(2026, 3, 1, 12, 0, worker, 0, time.UTC))
+			if _, err := Append(path, entry); err != nil {
+				t.Errorf("worker %d: append: %v", worker, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	result, err := Verify(path)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if !result.Valid || result.Entries != 8 {
+		t.Fatalf("expected 8 chained entries, got %+v", result)
+	}
+}
+
+func TestVerifyReportsFirstBrokenEntry(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		tamper func(lines []string) []string
+		line   int
+		reason string
+	}{
+		{
+			name: "edited field",
+			tamper: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], `"profile":"prod"`, `"profile":"staging"`, 1)
+				return lines
+			},
+			line:   2,
+			reason: "hash does not match",
+		},
+		{
+			name: "deleted entry",
+			tamper: func(lines []string) []string {
+				return append(lines[:1], lines[2:]...)
+			},
+			line:   2,
+			reason: "sequence 3, expected 2",
+		},
+		{
+			name: "garbage line",
+			tamper: func(lines []string) []string {
+				lines[2] = "not json"
+				return lines
+			},
+			line:   3,
+			reason: "does not decode",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "mutations.jsonl")
+			appendTestEntries(t, path, 3)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			lines := tc.tamper(strings.Split(strings.TrimSpace(string(data)), "\n"))
+			if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+
+			result, err := Verify(path)
+			if err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+			if result.Valid || result.BrokenLine != tc.line || !strings.Contains(result.Reason, tc.reason) {
+				t.Fatalf("unexpected verify result %+v", result)
+			}
+		})
+	}
+}
+
+func TestVerifyMissingLogIsEmptyAndValid(t *testing.T) {
+	t.Parallel()
+
+	result, err := Verify(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if !result.Valid || result.Entries != 0 {
+		t.Fatalf("unexpected verify result %+v", result)
+	}
+}
+
+func TestNewEntryDescribesMutation(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mutation := graph.Mutation{
+		Method:  http.MethodPost,
+		Path:    "/act_123/campaigns",
+		Version: "v25.0",
+		Form:    map[string]string{"name": "Spring", "access_token": "secret-a"},
+		Response: &graph.Response{
+			StatusCode: http.StatusOK,
+			Body:       map[string]any{"id": "987"},
+			Headers:    http.Header{"X-Fb-Trace-Id": []string{"trace-1"}},
+		},
+	}
+	entry := NewEntry(Invocation{Command: "meta campaign create", Profile: "prod"}, mutation, now)
+
+	if entry.Result != ResultSuccess || entry.StatusCode != http.StatusOK || entry.FBTraceID != "trace-1" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+	if strings.Join(entry.TargetIDs, ",") != "act_123,987" {
+		t.Fatalf("unexpected target ids %v", entry.TargetIDs)
+	}
+	if entry.Path != "act_123/campaigns" || entry.Timestamp != "2026-03-01T12:00:00Z" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+
+	otherToken := mutation
+	otherToken.Form = map[string]string{"name": "Spring", "access_token": "secret-b"}
+	if PayloadHash(otherToken) != entry.PayloadHash {
+		t.Fatal("expected payload hash to ignore the access token")
+	}
+	otherName := mutation
+	otherName.Form = map[string]string{"name": "Summer"}
+	if PayloadHash(otherName) == entry.PayloadHash {
+		t.Fatal("expected payload hash to change with the payload")
+	}
+
+	failed := NewEntry(Invocation{}, graph.Mutation{
+		Method: http.MethodDelete,
+		Path:   "/555",
+		Err:    &graph.APIError{Type: "OAuthException", Code: 100, FBTraceID: "trace-2", StatusCode: http.StatusBadRequest},
+	}, now)
+	if failed.Result != ResultError || failed.ErrorType != "OAuthException" || failed.ErrorCode != 100 || failed.FBTraceID != "trace-2" || failed.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected failed entry %+v", failed)
+	}
+
+	canceled := NewEntry(Invocation{}, graph.Mutation{Method: http.MethodPost, Path: "/555", Err: context.Canceled}, now)
+	if canceled.ErrorType != "canceled" {
+		t.Fatalf("unexpected canceled entry %+v", canceled)
+	}
+}
+
+func TestFilterAndExport(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "mutations.jsonl")
+	appendTestEntries(t, path, 3)
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	filtered := Filter{Since: time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC), Target: "12345", Limit: 1}.Apply(entries)
+	if len(filtered) != 1 || filtered[0].Sequence != 3 {
+		t.Fatalf("unexpected filtered entries %+v", filtered)
+	}
+	if len(Filter{Profile: "staging"}.Apply(entries)) != 0 {
+		t.Fatal("expected profile filter to exclude every entry")
+	}
+
+	csvOut := &bytes.Buffer{}
+	if err := Export(csvOut, filtered, ExportFormatCSV); err != nil {
+		t.Fatalf("export csv: %v", err)
+	}
+	rows := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(rows) != 2 || !strings.HasPrefix(rows[0], "sequence,timestamp,command") || !strings.HasPrefix(rows[1], "3,2026-03-01T14:00:00Z,meta campaign pause,prod,POST,12345") {
+		t.Fatalf("unexpected csv export %q", csvOut.String())
+	}
+
+	exportPath := filepath.Join(t.TempDir(), "export.jsonl")
+	jsonlOut := &bytes.Buffer{}
+	if err := Export(jsonlOut, entries, ExportFormatJSONL); err != nil {
+		t.Fatalf("export jsonl: %v", err)
+	}
+	if err := os.WriteFile(exportPath, jsonlOut.Bytes(), 0o600); err != nil {
+		t.Fatalf("write export: %v", err)
+	}
+	result, err := Verify(exportPath)
+	if err != nil || !result.Valid || result.Entries != 3 {
+		t.Fatalf("expected full jsonl export to verify, got %+v err=%v", result, err)
+	}
+
+	if err := Export(&bytes.Buffer{}, entries, "xml"); err == nil || !strings.Contains(err.Error(), "unsupported audit export format") {
+		t.Fatalf("expected unsupported format error, got %v", err)
+	}
+}
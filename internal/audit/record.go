@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/redact"
+)
+
+// credentialFields never contribute to the payload hash, so the same change
+// hashes identically whichever token sent it.
+var credentialFields = map[string]struct{}{
+	"access_token":    {},
+	"appsecret_proof": {},
+}
+
+var graphIDPattern = regexp.MustCompile(`^(act_)?[0-9]+(_[0-9]+)?$`)
+
+// Invocation identifies the CLI command that issued a mutation.
+type Invocation struct {
+	Command string
+	Profile string
+	// BreakGlass is the --break-glass reason, recorded on every mutation of
+	// the invocation.
+	BreakGlass string
+	// AnomalyOverride is the --override-anomaly reason, recorded on every
+	// mutation of the invocation.
+	AnomalyOverride string
+}
+
+type invocationKey struct{}
+
+func WithInvocation(ctx context.Context, invocation Invocation) context.Context {
+	return context.WithValue(ctx, invocationKey{}, invocation)
+}
+
+func InvocationFromContext(ctx context.Context) Invocation {
+	if ctx == nil {
+		return Invocation{}
+	}
+	invocation, _ := ctx.Value(invocationKey{}).(Invocation)
+	return invocation
+}
+
+// NewEntry describes mutation as an unchained entry; Append assigns the chain
+// fields.
+func NewEntry(invocation Invocation, mutation graph.Mutation, now time.Time) Entry {
+	entry := Entry{
+		Timestamp:       now.UTC().Format(time.RFC3339Nano),
+		Command:         invocation.Command,
+		Profile:         invocation.Profile,
+		BreakGlass:      redact.String(invocation.BreakGlass),
+		AnomalyOverride: redact.String(invocation.AnomalyOverride),
+		Method:          mutation.Method,
+		Path:            redact.String(strings.TrimPrefix(mutation.Path, "/")),
+		GraphVersion:    mutation.Version,
+		PayloadHash:     PayloadHash(mutation),
+		Result:          ResultSuccess,
+	}
+
+	targets := pathTargetIDs(entry.Path)
+	if mutation.Response != nil {
+		entry.StatusCode = mutation.Response.StatusCode
+		entry.FBTraceID = mutation.Response.Headers.Get("X-Fb-Trace-Id")
+		if id, ok := mutation.Response.Body["id"].(string); ok && id != "" && !containsTarget(targets, id) {
+			targets = append(targets, id)
+		}
+	}
+	entry.TargetIDs = targets
+
+	if mutation.Err != nil {
+		entry.Result = ResultError
+		entry.ErrorType = "request_error"
+		var apiErr *graph.APIError
+		var transient *graph.TransientError
+		switch {
+		case errors.As(mutation.Err, &apiErr):
+			entry.ErrorType = apiErr.Type
+			entry.ErrorCode = apiErr.Code
+			entry.StatusCode = apiErr.StatusCode
+			entry.FBTraceID = apiErr.FBTraceID
+		case errors.As(mutation.Err, &transient):
+			entry.ErrorType = "transient_error"
+			entry.StatusCode = transient.StatusCode
+		case errors.Is(mutation.Err, context.Canceled), errors.Is(mutation.Err, context.DeadlineExceeded):
+			entry.ErrorType = "canceled"
+		}
+	}
+	return entry
+}
+
+// PayloadHash is the sha256 of the final request payload: method, path,
+// version, form fields without credentials and the digest of any uploaded file.
+func PayloadHash(mutation graph.Mutation) string {
+	form := make(map[string]string, len(mutation.Form))
+	for key, value := range mutation.Form {
+		if _, ok := credentialFields[key]; ok {
+			continue
+		}
+		form[key] = value
+	}
+	payload := map[string]any{
+		"method":  mutation.Method,
+		"path":    strings.TrimPrefix(mutation.Path, "/"),
+		"version": mutation.Version,
+		"form":    form,
+	}
+	if mutation.Multipart != nil {
+		fileSum := sha256.Sum256(mutation.Multipart.FileBytes)
+		payload["multipart"] = map[string]string{
+			"field_name": mutation.Multipart.FieldName,
+			"file_name":  mutation.Multipart.FileName,
+			"sha256":     hex.EncodeToString(fileSum[:]),
+		}
+	}
+	// encoding/json sorts map keys, which makes this encoding canonical.
+	encoded, _ := json.Marshal(payload)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+func pathTargetIDs(path string) []string {
+	var targets []string
+	for _, segment := range strings.Split(path, "/") {
+		if graphIDPattern.MatchString(segment) {
+			targets = append(targets, segment)
+		}
+	}
+	return targets
+}
@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// VerifyResult reports whether the chain is intact. When it is not, BrokenLine
+// and Reason describe the first entry that does not chain onto its predecessor.
+type VerifyResult struct {
+	Path       string `json:"path"`
+	Entries    int    `json:"entries"`
+	Valid      bool   `json:"valid"`
+	HeadHash   string `json:"head_hash,omitempty"`
+	BrokenLine int    `json:"broken_line,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// Verify walks the log from the first line and checks that sequences are
+// contiguous, each prev_hash matches the preceding hash and each hash matches
+// its entry. Lines that do not decode are reported as breaks, not errors.
+func Verify(path string) (VerifyResult, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return VerifyResult{}, ErrPathRequired
+	}
+	result := VerifyResult{Path: path, Valid: true}
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return VerifyResult{}, fmt.Errorf("open audit log %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var previous *Entry
+	err = scanLines(file, func(lineNumber int, line []byte) error {
+		if !result.Valid {
+			return nil
+		}
+		fail := func(reason string) {
+			result.Valid = false
+			result.BrokenLine = lineNumber
+			result.Reason = reason
+		}
+
+		entry, err := decodeEntry(line)
+		if err != nil {
+			fail(fmt.Sprintf("entry does not decode: %v", err))
+			return nil
+		}
+		expectedSequence, expectedPrev := int64(1), ""
+		if previous != nil {
+			expectedSequence, expectedPrev = previous.Sequence+1, previous.Hash
+		}
+		if entry.Sequence != expectedSequence {
+			fail(fmt.Sprintf("sequence %d, expected %d", entry.Sequence, expectedSequence))
+			return nil
+		}
+		if entry.PrevHash != expectedPrev {
+			fail("prev_hash does not match the preceding entry")
+			return nil
+		}
+		hash, err := entry.ComputeHash()
+		if err != nil {
+			return err
+		}
+		if hash != entry.Hash {
+			fail("hash does not match entry contents")
+			return nil
+		}
+		result.Entries++
+		result.HeadHash = entry.Hash
+		previous = &entry
+		return nil
+	})
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	return result, nil
+}
+
+type Filter struct {
+	Since   time.Time
+	Command string
+	Profile string
+	Target  string
+	Result  string
+	// Limit keeps the most recent matches; zero keeps all.
+	Limit int
+}
+
+// Apply returns the entries matching every set field, oldest first.
+func (f Filter) Apply(entries []Entry) []Entry {
+	matched := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if !f.Since.IsZero() {
+			timestamp, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+			if err != nil || timestamp.Before(f.Since) {
+				continue
+			}
+		}
+		if f.Command != "" && !strings.Contains(entry.Command, f.Command) {
+			continue
+		}
+		if f.Profile != "" && entry.Profile != f.Profile {
+			continue
+		}
+		if f.Result != "" && entry.Result != f.Result {
+			continue
+		}
+		if f.Target != "" && !containsTarget(entry.TargetIDs, f.Target) {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[len(matched)-f.Limit:]
+	}
+	return matched
+}
+
+func containsTarget(targets []string, target string) bool {
+	target = strings.TrimPrefix(target, "act_")
+	for _, candidate := range targets {
+		if strings.TrimPrefix(candidate, "act_") == target {
+			return true
+		}
+	}
+	return false
+}
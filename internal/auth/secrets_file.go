@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
 )
 
 // FileStore implements SecretStore using a JSON file on disk.
@@ -44,6 +46,11 @@ func (f *FileStore) Set(ref string, value string) error {
 
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	lock, err := filelock.Acquire(f.path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
 
 	data, err := f.load()
 	if err != nil {
@@ -81,6 +88,11 @@ func (f *FileStore) Delete(ref string) error {
 
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	lock, err := filelock.Acquire(f.path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
 
 	data, err := f.load()
 	if err != nil {
@@ -106,14 +118,33 @@ func (f *FileStore) load() (map[string]string, error) {
 }
 
 func (f *FileStore) save(data map[string]string) error {
-	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+	dir := filepath.Dir(f.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
 		return fmt.Errorf("create secrets directory: %w", err)
 	}
 	raw, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal secrets: %w", err)
 	}
-	if err := os.WriteFile(f.path, raw, 0600); err != nil {
+
+	tmpFile, err := os.CreateTemp(dir, ".secrets-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp secrets file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(raw); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp secrets file: %w", err)
+	}
+	if err := tmpFile.Chmod(0600); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("chmod temp secrets file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp secrets file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), f.path); err != nil {
 		return fmt.Errorf("write secrets file: %w", err)
 	}
 	return nil
@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/filelock"
+	"github.com/bilalbayram/metacli/internal/transport"
 )
 
 const (
@@ -33,6 +35,9 @@ const (
 	DefaultGraphBaseURL = "https://graph.facebook.com"
 )
 
+// ErrTokenInvalid is returned when debug_token reports a token as invalid.
+var ErrTokenInvalid = errors.New("token is invalid according to debug_token")
+
 type HTTPClient interface {
 	Do(*http.Request) (*http.Response, error)
 }
@@ -52,6 +57,7 @@ type AddSystemUserInput struct {
 	AppSecret  string
 	AuthMode   string
 	Scopes     []string
+	WABAID     string
 }
 
 type AddUserInput struct {
@@ -67,6 +73,9 @@ type AddUserInput struct {
 	LastValidatedAt string
 	IGUserID        string
 	PageID          string
+	// ScopesVerifiedAt is set when Scopes were read from the token with
+	// debug_token rather than assumed.
+	ScopesVerifiedAt string
 }
 
 type PageTokenInput struct {
@@ -87,6 +96,7 @@ type UpdateProfileBindingsInput struct {
 	Profile  string
 	PageID   string
 	IGUserID string
+	WABAID   string
 }
 
 type ExchangeCodeInput struct {
@@ -103,7 +113,7 @@ type DebugTokenResponse struct {
 
 func NewService(configPath string, secrets SecretStore, httpClient HTTPClient, graphBaseURL string) *Service {
 	if httpClient == nil {
-		httpClient = &http.Client{Timeout: 30 * time.Second}
+		httpClient = transport.NewClient(transport.DefaultTimeout)
 	}
 	if graphBaseURL == "" {
 		graphBaseURL = DefaultGraphBaseURL
@@ -133,7 +143,31 @@ func (s *Service) AddSystemUser(ctx context.Context, input AddSystemUserInput) e
 		return errors.New("app secret is required")
 	}
 
-	cfg, err := config.LoadOrCreate(s.configPath)
+	// Without declared scopes the profile records what the token was actually
+	// granted. When Graph cannot be asked, the defaults stay unverified and the
+	// scope preflight only warns about them.
+	now := time.Now().UTC()
+	scopes := normalizedScopesOrDefault(input.Scopes, []string{"ads_management", "business_management"})
+	scopesVerifiedAt := ""
+	if len(input.Scopes) == 0 {
+		granted, err := s.tokenScopes(ctx, config.DefaultGraphVersion, input.AppID, input.AppSecret, input.Token)
+		switch {
+		case errors.Is(err, ErrTokenInvalid):
+			return err
+		case err == nil && len(granted) > 0:
+			scopes = granted
+			scopesVerifiedAt = now.Format(time.RFC3339)
+		}
+	}
+	authMode := normalizedAuthModeOrDefault(input.AuthMode, AuthModeBoth)
+
+	lock, err := filelock.Acquire(s.configPath)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	cfg, err := config.LoadOrCreateHeld(lock, s.configPath)
 	if err != nil {
 		return err
 	}
@@ -154,29 +188,27 @@ func (s *Service) AddSystemUser(ctx context.Context, input AddSystemUserInput) e
 		return err
 	}
 
-	now := time.Now().UTC()
-	scopes := normalizedScopesOrDefault(input.Scopes, []string{"ads_management", "business_management"})
-	authMode := normalizedAuthModeOrDefault(input.AuthMode, AuthModeBoth)
-
 	if err := cfg.UpsertProfile(input.Profile, config.Profile{
-		Domain:          config.DefaultDomain,
-		GraphVersion:    config.DefaultGraphVersion,
-		TokenType:       TokenTypeSystemUser,
-		BusinessID:      input.BusinessID,
-		AppID:           input.AppID,
-		TokenRef:        tokenRef,
-		AppSecretRef:    appSecretRef,
-		AuthProvider:    AuthProviderSystemUser,
-		AuthMode:        authMode,
-		Scopes:          scopes,
-		IssuedAt:        now.Format(time.RFC3339),
-		ExpiresAt:       now.AddDate(10, 0, 0).Format(time.RFC3339),
-		LastValidatedAt: now.Format(time.RFC3339),
+		Domain:           config.DefaultDomain,
+		GraphVersion:     config.DefaultGraphVersion,
+		TokenType:        TokenTypeSystemUser,
+		BusinessID:       input.BusinessID,
+		AppID:            input.AppID,
+		TokenRef:         tokenRef,
+		AppSecretRef:     appSecretRef,
+		AuthProvider:     AuthProviderSystemUser,
+		AuthMode:         authMode,
+		Scopes:           scopes,
+		IssuedAt:         now.Format(time.RFC3339),
+		ExpiresAt:        now.AddDate(10, 0, 0).Format(time.RFC3339),
+		LastValidatedAt:  now.Format(time.RFC3339),
+		WABAID:           strings.TrimSpace(input.WABAID),
+		ScopesVerifiedAt: scopesVerifiedAt,
 	}); err != nil {
 		return err
 	}
 
-	return config.Save(s.configPath, cfg)
+	return config.SaveHeld(lock, s.configPath, cfg)
 }
 
 func (s *Service) AddUser(ctx context.Context, input AddUserInput) error {
@@ -193,7 +225,13 @@ func (s *Service) AddUser(ctx context.Context, input AddUserInput) error {
 		return errors.New("app secret is required")
 	}
 
-	cfg, err := config.LoadOrCreate(s.configPath)
+	lock, err := filelock.Acquire(s.configPath)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	cfg, err := config.LoadOrCreateHeld(lock, s.configPath)
 	if err != nil {
 		return err
 	}
@@ -228,25 +266,26 @@ func (s *Service) AddUser(ctx context.Context, input AddUserInput) error {
 	}
 
 	if err := cfg.UpsertProfile(input.Profile, config.Profile{
-		Domain:          config.DefaultDomain,
-		GraphVersion:    config.DefaultGraphVersion,
-		TokenType:       TokenTypeUser,
-		AppID:           input.AppID,
-		PageID:          strings.TrimSpace(input.PageID),
-		TokenRef:        tokenRef,
-		AppSecretRef:    appSecretRef,
-		AuthProvider:    normalizedAuthProviderOrDefault(input.AuthProvider, AuthProviderFacebookLogin),
-		AuthMode:        normalizedAuthModeOrDefault(input.AuthMode, AuthModeBoth),
-		Scopes:          normalizedScopesOrDefault(input.Scopes, []string{"ads_read"}),
-		IssuedAt:        issuedAt,
-		ExpiresAt:       expiresAt,
-		LastValidatedAt: lastValidatedAt,
-		IGUserID:        strings.TrimSpace(input.IGUserID),
+		Domain:           config.DefaultDomain,
+		GraphVersion:     config.DefaultGraphVersion,
+		TokenType:        TokenTypeUser,
+		AppID:            input.AppID,
+		PageID:           strings.TrimSpace(input.PageID),
+		TokenRef:         tokenRef,
+		AppSecretRef:     appSecretRef,
+		AuthProvider:     normalizedAuthProviderOrDefault(input.AuthProvider, AuthProviderFacebookLogin),
+		AuthMode:         normalizedAuthModeOrDefault(input.AuthMode, AuthModeBoth),
+		Scopes:           normalizedScopesOrDefault(input.Scopes, []string{"ads_read"}),
+		IssuedAt:         issuedAt,
+		ExpiresAt:        expiresAt,
+		LastValidatedAt:  lastValidatedAt,
+		IGUserID:         strings.TrimSpace(input.IGUserID),
+		ScopesVerifiedAt: strings.TrimSpace(input.ScopesVerifiedAt),
 	}); err != nil {
 		return err
 	}
 
-	return config.Save(s.configPath, cfg)
+	return config.SaveHeld(lock, s.configPath, cfg)
 }
 
 func (s *Service) SetAppToken(ctx context.Context, input SetAppTokenInput) error {
@@ -265,7 +304,13 @@ func (s *Service) SetAppToken(ctx context.Context, input SetAppTokenInput) error
 		return err
 	}
 
-	cfg, err := config.LoadOrCreate(s.configPath)
+	lock, err := filelock.Acquire(s.configPath)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	cfg, err := config.LoadOrCreateHeld(lock, s.configPath)
 	if err != nil {
 		return err
 	}
@@ -305,7 +350,7 @@ func (s *Service) SetAppToken(ctx context.Context, input SetAppTokenInput) error
 		return err
 	}
 
-	return config.Save(s.configPath, cfg)
+	return config.SaveHeld(lock, s.configPath, cfg)
 }
 
 func (s *Service) DerivePageToken(ctx context.Context, input PageTokenInput) error {
@@ -319,6 +364,12 @@ func (s *Service) DerivePageToken(ctx context.Context, input PageTokenInput) err
 		return errors.New("source profile is required")
 	}
 
+	lock, err := filelock.Acquire(s.configPath)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
 	cfg, err := config.Load(s.configPath)
 	if err != nil {
 		return err
@@ -399,7 +450,7 @@ func (s *Service) DerivePageToken(ctx context.Context, input PageTokenInput) err
 		return err
 	}
 
-	return config.Save(s.configPath, cfg)
+	return config.SaveHeld(lock, s.configPath, cfg)
 }
 
 func (s *Service) ExchangeOAuthCode(ctx context.Context, input ExchangeCodeInput) (string, error) {
@@ -475,6 +526,53 @@ func (s *Service) ValidateProfile(ctx context.Context, profileName string) (*Deb
 	return resp, nil
 }
 
+// RecordVerifiedScopes replaces the scopes on record for a profile with the
+// ones debug_token reported for its token.
+func (s *Service) RecordVerifiedScopes(profileName string, scopes []string, verifiedAt time.Time) error {
+	if len(scopes) == 0 {
+		return errors.New("verified scopes cannot be empty")
+	}
+	lock, err := filelock.Acquire(s.configPath)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		return err
+	}
+	name, profile, err := cfg.ResolveProfile(profileName)
+	if err != nil {
+		return err
+	}
+	profile.Scopes = append([]string(nil), scopes...)
+	profile.ScopesVerifiedAt = verifiedAt.UTC().Format(time.RFC3339)
+	cfg.Profiles[name] = profile
+	return config.SaveHeld(lock, s.configPath, cfg)
+}
+
+// tokenScopes asks debug_token, with the app's own token, which scopes token
+// was granted. An invalid token is ErrTokenInvalid.
+func (s *Service) tokenScopes(ctx context.Context, version string, appID string, appSecret string, token string) ([]string, error) {
+	appToken, err := s.fetchAppToken(ctx, version, appID, appSecret)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.DebugToken(ctx, version, token, appToken)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := NormalizeDebugTokenMetadata(resp)
+	if err != nil {
+		return nil, err
+	}
+	if !metadata.IsValid {
+		return nil, ErrTokenInvalid
+	}
+	return metadata.Scopes, nil
+}
+
 func (s *Service) RotateProfile(ctx context.Context, profileName string) error {
 	cfg, err := config.Load(s.configPath)
 	if err != nil {
@@ -550,6 +648,12 @@ func (s *Service) UpdateProfileBindings(ctx context.Context, input UpdateProfile
 		return errors.New("profile is required")
 	}
 
+	lock, err := filelock.Acquire(s.configPath)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
 	cfg, err := config.Load(s.configPath)
 	if err != nil {
 		return err
@@ -567,12 +671,16 @@ func (s *Service) UpdateProfileBindings(ctx context.Context, input UpdateProfile
 	if igUserID != "" {
 		profile.IGUserID = igUserID
 	}
+	wabaID := strings.TrimSpace(input.WABAID)
+	if wabaID != "" {
+		profile.WABAID = wabaID
+	}
 	profile.LastValidatedAt = time.Now().UTC().Format(time.RFC3339)
 
 	if err := cfg.UpsertProfile(name, profile); err != nil {
 		return err
 	}
-	return config.Save(s.configPath, cfg)
+	return config.SaveHeld(lock, s.configPath, cfg)
 }
 
 func normalizedScopesOrDefault(scopes []string, fallback []string) []string {
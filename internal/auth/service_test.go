@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+)
+
+func TestAddSystemUserRecordsScopesFromDebugToken(t *testing.T) {
+	t.Parallel()
+
+	svc, configPath := newSystemUserService(t, `{"data":{"is_valid":true,"scopes":["ads_management","instagram_basic","leads_retrieval"]}}`)
+	if err := svc.AddSystemUser(context.Background(), systemUserInput()); err != nil {
+		t.Fatalf("add system user: %v", err)
+	}
+
+	profile := loadProfile(t, configPath, "prod")
+	if want := []string{"ads_management", "instagram_basic", "leads_retrieval"}; !reflect.DeepEqual(profile.Scopes, want) {
+		t.Fatalf("unexpected scopes: got=%v want=%v", profile.Scopes, want)
+	}
+	if profile.ScopesVerifiedAt == "" {
+		t.Fatal("expected scopes to be marked verified")
+	}
+}
+
+func TestAddSystemUserRejectsInvalidToken(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newSystemUserService(t, `{"data":{"is_valid":false,"scopes":[]}}`)
+	err := svc.AddSystemUser(context.Background(), systemUserInput())
+	if !errors.Is(err, ErrTokenInvalid) {
+		t.Fatalf("expected ErrTokenInvalid, got %v", err)
+	}
+}
+
+func TestAddSystemUserKeepsDefaultScopesUnverifiedWhenDebugTokenFails(t *testing.T) {
+	t.Parallel()
+
+	svc, configPath := newSystemUserService(t, "")
+	if err := svc.AddSystemUser(context.Background(), systemUserInput()); err != nil {
+		t.Fatalf("add system user: %v", err)
+	}
+
+	profile := loadProfile(t, configPath, "prod")
+	if want := []string{"ads_management", "business_management"}; !reflect.DeepEqual(profile.Scopes, want) {
+		t.Fatalf("unexpected scopes: got=%v want=%v", profile.Scopes, want)
+	}
+	if profile.ScopesVerifiedAt != "" {
+		t.Fatalf("expected unverified scopes, got scopes_verified_at=%q", profile.ScopesVerifiedAt)
+	}
+}
+
+func TestRecordVerifiedScopesReplacesProfileScopes(t *testing.T) {
+	t.Parallel()
+
+	configPath := mustWriteConfigWithProfile(t, "prod", config.Profile{})
+	svc := NewService(configPath, newInMemorySecretStore(), nil, "")
+	if err := svc.RecordVerifiedScopes("prod", []string{"ads_read", "pages_show_list"}, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("record verified scopes: %v", err)
+	}
+
+	profile := loadProfile(t, configPath, "prod")
+	if want := []string{"ads_read", "pages_show_list"}; !reflect.DeepEqual(profile.Scopes, want) {
+		t.Fatalf("unexpected scopes: got=%v want=%v", profile.Scopes, want)
+	}
+	if profile.ScopesVerifiedAt != "2026-03-01T12:00:00Z" {
+		t.Fatalf("unexpected scopes_verified_at %q", profile.ScopesVerifiedAt)
+	}
+}
+
+func systemUserInput() AddSystemUserInput {
+	return AddSystemUserInput{
+		Profile:    "prod",
+		BusinessID: "biz-1",
+		AppID:      "app-123",
+		Token:      "system-token",
+		AppSecret:  "app-secret",
+	}
+}
+
+// newSystemUserService serves debugTokenPayload from debug_token, or fails
+// the app-token request when it is empty.
+func newSystemUserService(t *testing.T, debugTokenPayload string) (*Service, string) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/" + config.DefaultGraphVersion + "/oauth/access_token":
+			if debugTokenPayload == "" {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"error":{"message":"unavailable","type":"OAuthException","code":2}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"app-token"}`))
+		case "/" + config.DefaultGraphVersion + "/debug_token":
+			if got := r.URL.Query().Get("input_token"); got != "system-token" {
+				t.Errorf("unexpected input token: %s", got)
+			}
+			_, _ = w.Write([]byte(debugTokenPayload))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	return NewService(configPath, newInMemorySecretStore(), server.Client(), server.URL), configPath
+}
+
+func loadProfile(t *testing.T, configPath string, name string) config.Profile {
+	t.Helper()
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	profile, ok := cfg.Profiles[name]
+	if !ok {
+		t.Fatalf("profile %q not saved", name)
+	}
+	return profile
+}
@@ -0,0 +1,412 @@
+// Package bench measures the CLI's own hot paths (schema pack loading, lint,
+// envelope serialization and the Graph request pipeline against a local stub
+// server) so releases can be compared and regressions in the CLI itself caught
+// before users notice them. Nothing here talks to Meta.
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/lint"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/bilalbayram/metacli/internal/schema"
+)
+
+const (
+	ReportSchemaVersion = 1
+
+	CaseSchemaDecode = "schema_decode"
+	CaseSchemaLoad   = "schema_load"
+	CaseLint         = "lint"
+	CaseEnvelope     = "envelope_json"
+	CaseGraphRequest = "graph_request"
+	CaseGraphPaging  = "graph_pagination"
+
+	DefaultIterations = 200
+	// DefaultMaxRegression is how much slower per op a case may get against a
+	// baseline before it counts as a regression.
+	DefaultMaxRegression = 0.25
+
+	benchDomain  = "marketing"
+	benchVersion = "v25.0"
+	stubPageSize = 25
+	stubPages    = 4
+)
+
+// Cases lists every case in run order.
+var Cases = []string{CaseSchemaDecode, CaseSchemaLoad, CaseLint, CaseEnvelope, CaseGraphRequest, CaseGraphPaging}
+
+type Options struct {
+	SchemaDir  string
+	Iterations int
+	// Cases selects cases by name; empty runs all of them.
+	Cases []string
+}
+
+type Result struct {
+	Name        string  `json:"name"`
+	Iterations  int     `json:"iterations"`
+	TotalMS     float64 `json:"total_ms"`
+	NsPerOp     int64   `json:"ns_per_op"`
+	OpsPerSec   float64 `json:"ops_per_sec"`
+	AllocsPerOp uint64  `json:"allocs_per_op"`
+	BytesPerOp  uint64  `json:"bytes_per_op"`
+}
+
+type Report struct {
+	SchemaVersion int      `json:"schema_version"`
+	CLIVersion    string   `json:"cli_version,omitempty"`
+	GoVersion     string   `json:"go_version"`
+	OS            string   `json:"os"`
+	Arch          string   `json:"arch"`
+	CPUs          int      `json:"cpus"`
+	StartedAt     string   `json:"started_at"`
+	Results       []Result `json:"results"`
+}
+
+// Regression is a case that got slower per op than a baseline allows.
+type Regression struct {
+	Name          string  `json:"name"`
+	BaselineNsOp  int64   `json:"baseline_ns_per_op"`
+	CurrentNsOp   int64   `json:"current_ns_per_op"`
+	Change        float64 `json:"change"`
+	MaxRegression float64 `json:"max_regression"`
+}
+
+// Run measures the selected cases one after the other. Each case gets one
+// untimed warm-up iteration so one-time setup does not skew ns_per_op.
+func Run(ctx context.Context, options Options) (*Report, error) {
+	iterations := options.Iterations
+	if iterations == 0 {
+		iterations = DefaultIterations
+	}
+	if iterations < 1 {
+		return nil, fmt.Errorf("bench iterations must be > 0, got %d", iterations)
+	}
+	selected, err := selectCases(options.Cases)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture, err := newFixture(options.SchemaDir)
+	if err != nil {
+		return nil, err
+	}
+	defer fixture.close()
+
+	report := &Report{
+		SchemaVersion: ReportSchemaVersion,
+		GoVersion:     runtime.Version(),
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		CPUs:          runtime.NumCPU(),
+		StartedAt:     time.Now().UTC().Format(time.RFC3339),
+		Results:       make([]Result, 0, len(selected)),
+	}
+	for _, name := range selected {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		op := fixture.op(ctx, name)
+		if err := op(); err != nil {
+			return nil, fmt.Errorf("bench %s: %w", name, err)
+		}
+		result, err := measure(name, iterations, op)
+		if err != nil {
+			return nil, fmt.Errorf("bench %s: %w", name, err)
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+func measure(name string, iterations int, op func() error) (Result, error) {
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	started := time.Now()
+	for i := 0; i < iterations; i++ {
+		if err := op(); err != nil {
+			return Result{}, err
+		}
+	}
+	elapsed := time.Since(started)
+	runtime.ReadMemStats(&after)
+
+	result := Result{
+		Name:        name,
+		Iterations:  iterations,
+		TotalMS:     float64(elapsed.Microseconds()) / 1000,
+		NsPerOp:     elapsed.Nanoseconds() / int64(iterations),
+		AllocsPerOp: (after.Mallocs - before.Mallocs) / uint64(iterations),
+		BytesPerOp:  (after.TotalAlloc - before.TotalAlloc) / uint64(iterations),
+	}
+	if elapsed > 0 {
+		result.OpsPerSec = float64(iterations) / elapsed.Seconds()
+	}
+	return result, nil
+}
+
+func selectCases(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return append([]string(nil), Cases...), nil
+	}
+	known := map[string]struct{}{}
+	for _, name := range Cases {
+		known[name] = struct{}{}
+	}
+	wanted := map[string]struct{}{}
+	for _, raw := range names {
+		name := strings.TrimSpace(raw)
+		if name == "" {
+			continue
+		}
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("unknown bench case %q; expected one of %s", name, strings.Join(Cases, ","))
+		}
+		wanted[name] = struct{}{}
+	}
+	selected := make([]string, 0, len(wanted))
+	for _, name := range Cases {
+		if _, ok := wanted[name]; ok {
+			selected = append(selected, name)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("at least one bench case is required")
+	}
+	return selected, nil
+}
+
+// fixture holds what the cases share: the pack bytes, a linter and the stub
+// Graph server.
+type fixture struct {
+	packPath string
+	packData []byte
+	provider *schema.Provider
+	linter   *lint.Linter
+	server   *httptest.Server
+	client   *graph.Client
+}
+
+func newFixture(schemaDir string) (*fixture, error) {
+	if strings.TrimSpace(schemaDir) == "" {
+		schemaDir = schema.DefaultSchemaDir()
+	}
+	provider := schema.NewProvider(schemaDir, "", "")
+	packPath := filepath.Join(provider.BaseDir, benchDomain, benchVersion+".json")
+	data, err := os.ReadFile(packPath)
+	if err != nil {
+		return nil, fmt.Errorf("read bench schema pack: %w", err)
+	}
+	pack, err := provider.GetPack(benchDomain, benchVersion)
+	if err != nil {
+		return nil, err
+	}
+	linter, err := lint.New(pack)
+	if err != nil {
+		return nil, err
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(serveStub))
+	client := graph.NewClient(nil, server.URL)
+	client.MaxRetries = 0
+	return &fixture{
+		packPath: packPath,
+		packData: data,
+		provider: provider,
+		linter:   linter,
+		server:   server,
+		client:   client,
+	}, nil
+}
+
+func (f *fixture) close() {
+	f.server.Close()
+}
+
+func (f *fixture) op(ctx context.Context, name string) func() error {
+	switch name {
+	case CaseSchemaDecode:
+		// A cold load: read and decode the pack without the process cache.
+		return func() error {
+			data, err := os.ReadFile(f.packPath)
+			if err != nil {
+				return err
+			}
+			var pack schema.Pack
+			return json.Unmarshal(data, &pack)
+		}
+	case CaseSchemaLoad:
+		return func() error {
+			_, err := f.provider.GetPack(benchDomain, benchVersion)
+			return err
+		}
+	case CaseLint:
+		spec := &lint.RequestSpec{
+			Method: "POST",
+			Path:   "act_1/campaigns",
+			Params: map[string]string{"name": "Launch", "objective": "OUTCOME_SALES", "status": "PAUSED", "special_ad_categories": "[]"},
+			Fields: []string{"id", "name", "status"},
+		}
+		return func() error {
+			f.linter.Lint(spec, false)
+			return nil
+		}
+	case CaseEnvelope:
+		data := stubRows(0, 100)
+		return func() error {
+			envelope, err := output.NewEnvelope("meta bench", true, data, nil, nil, nil)
+			if err != nil {
+				return err
+			}
+			return output.Write(io.Discard, "json", envelope)
+		}
+	case CaseGraphRequest:
+		return func() error {
+			_, err := f.client.Do(ctx, graph.Request{Method: "GET", Path: "act_1", Version: benchVersion, Query: map[string]string{"fields": "id,name"}, AccessToken: "bench"})
+			return err
+		}
+	default:
+		return func() error {
+			count := 0
+			_, err := f.client.FetchWithPagination(ctx, graph.Request{Method: "GET", Path: "act_1/campaigns", Version: benchVersion, AccessToken: "bench"}, graph.PaginationOptions{FollowNext: true}, func(map[string]any) error {
+				count++
+				return nil
+			})
+			if err == nil && count != stubPageSize*stubPages {
+				err = fmt.Errorf("stub pagination returned %d items, expected %d", count, stubPageSize*stubPages)
+			}
+			return err
+		}
+	}
+}
+
+// serveStub answers like Graph: a single object, or pages of campaigns linked
+// by offset next URLs.
+func serveStub(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Business-Use-Case-Usage", `{"1":[{"type":"ads_management","call_count":1,"total_cputime":1,"total_time":1}]}`)
+	if !strings.HasSuffix(r.URL.Path, "/campaigns") {
+		_, _ = w.Write([]byte(`{"id":"act_1","name":"Bench Account"}`))
+		return
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	body := map[string]any{"data": stubRows(offset, stubPageSize)}
+	if next := offset + stubPageSize; next < stubPageSize*stubPages {
+		query := r.URL.Query()
+		query.Set("offset", strconv.Itoa(next))
+		body["paging"] = map[string]any{"next": "http://" + r.Host + r.URL.Path + "?" + query.Encode()}
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func stubRows(offset int, count int) []map[string]any {
+	rows := make([]map[string]any, 0, count)
+	for i := offset; i < offset+count; i++ {
+		rows = append(rows, map[string]any{
+			"id":           strconv.Itoa(1000 + i),
+			"name":         "Campaign " + strconv.Itoa(i),
+			"status":       "PAUSED",
+			"objective":    "OUTCOME_SALES",
+			"daily_budget": "1000",
+		})
+	}
+	return rows
+}
+
+// Compare lists the cases of current that are slower per op than baseline by
+// more than maxRegression (0.25 = 25%). Cases missing from either side are
+// ignored.
+func Compare(baseline *Report, current *Report, maxRegression float64) []Regression {
+	if baseline == nil || current == nil {
+		return nil
+	}
+	previous := map[string]Result{}
+	for _, result := range baseline.Results {
+		previous[result.Name] = result
+	}
+	regressions := make([]Regression, 0)
+	for _, result := range current.Results {
+		before, ok := previous[result.Name]
+		if !ok || before.NsPerOp <= 0 {
+			continue
+		}
+		change := float64(result.NsPerOp-before.NsPerOp) / float64(before.NsPerOp)
+		if change > maxRegression {
+			regressions = append(regressions, Regression{
+				Name:          result.Name,
+				BaselineNsOp:  before.NsPerOp,
+				CurrentNsOp:   result.NsPerOp,
+				Change:        change,
+				MaxRegression: maxRegression,
+			})
+		}
+	}
+	sort.Slice(regressions, func(i, j int) bool { return regressions[i].Change > regressions[j].Change })
+	return regressions
+}
+
+// LoadReport reads a report written by WriteReport, or the envelope `meta debug
+// bench` printed around one.
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read bench baseline %s: %w", path, err)
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err == nil && len(envelope.Data) > 0 {
+		var wrapped struct {
+			Report *Report `json:"report"`
+		}
+		if err := json.Unmarshal(envelope.Data, &wrapped); err == nil && wrapped.Report != nil {
+			data, _ = json.Marshal(wrapped.Report)
+		}
+	}
+	report := &Report{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(report); err != nil {
+		return nil, fmt.Errorf("decode bench baseline %s: %w", path, err)
+	}
+	if report.SchemaVersion != ReportSchemaVersion {
+		return nil, fmt.Errorf("unsupported bench report schema_version=%d in %s (expected %d)", report.SchemaVersion, path, ReportSchemaVersion)
+	}
+	return report, nil
+}
+
+func WriteReport(path string, report *Report) error {
+	if report == nil {
+		return errors.New("bench report is required")
+	}
+	payload, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode bench report: %w", err)
+	}
+	payload = append(payload, '\n')
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create bench report directory for %s: %w", path, err)
+		}
+	}
+	if err := os.WriteFile(path, payload, 0o644); err != nil {
+		return fmt.Errorf("write bench report %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package bench
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunMeasuresEveryCase(t *testing.T) {
+	t.Parallel()
+
+	report, err := Run(context.Background(), Options{SchemaDir: filepath.Join("..", "..", "schema-packs"), Iterations: 3})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(report.Results) != len(Cases) {
+		t.Fatalf("expected %d results, got %#v", len(Cases), report.Results)
+	}
+	for index, result := range report.Results {
+		if result.Name != Cases[index] || result.Iterations != 3 || result.NsPerOp <= 0 {
+			t.Fatalf("unexpected result %#v", result)
+		}
+	}
+
+	if _, err := Run(context.Background(), Options{SchemaDir: filepath.Join("..", "..", "schema-packs"), Cases: []string{"nope"}}); err == nil || !strings.Contains(err.Error(), "unknown bench case") {
+		t.Fatalf("expected unknown case error, got %v", err)
+	}
+}
+
+func TestCompareFlagsSlowerCases(t *testing.T) {
+	t.Parallel()
+
+	baseline := &Report{SchemaVersion: ReportSchemaVersion, Results: []Result{{Name: CaseLint, NsPerOp: 100}, {Name: CaseEnvelope, NsPerOp: 100}}}
+	current := &Report{SchemaVersion: ReportSchemaVersion, Results: []Result{{Name: CaseLint, NsPerOp: 150}, {Name: CaseEnvelope, NsPerOp: 110}, {Name: CaseGraphRequest, NsPerOp: 900}}}
+	regressions := Compare(baseline, current, 0.25)
+	if len(regressions) != 1 || regressions[0].Name != CaseLint || regressions[0].Change != 0.5 {
+		t.Fatalf("unexpected regressions %#v", regressions)
+	}
+
+	path := filepath.Join(t.TempDir(), "bench.json")
+	if err := WriteReport(path, baseline); err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+	loaded, err := LoadReport(path)
+	if err != nil || len(loaded.Results) != 2 {
+		t.Fatalf("unexpected loaded report %#v %v", loaded, err)
+	}
+}
@@ -0,0 +1,503 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bilalbayram/metacli/internal/checkpoint"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/workqueue"
+)
+
+const (
+	DefaultBatchSize     = 50
+	ResultsSchemaVersion = 1
+
+	errorTypeValidation     = "bulk_import_validation_error"
+	errorCodeValidation     = 422500
+	errorTypePartialFailure = "bulk_import_partial_failure"
+	errorCodePartialFailure = 424500
+)
+
+// BatchFunc sends one Graph batch; graph.Client.ExecuteBatch satisfies it once
+// bound to a version and token.
+type BatchFunc func(ctx context.Context, requests []graph.BatchRequest) ([]graph.BatchResult, error)
+
+type ExecuteOptions struct {
+	BatchSize int
+	// Concurrency is how many batches of one level are sent at once. Levels
+	// still run in order, since children need their parents' ids.
+	Concurrency int
+	// OnProgress is called after every batch with objects settled so far.
+	OnProgress func(workqueue.Progress)
+	// Previous is the results file of an earlier run of the same sheet. Objects whose
+	// idempotency key it maps to an id are reused instead of created again.
+	Previous *Results
+	// Checkpoint records every created object's id by idempotency key as its
+	// batch returns, so a run killed before the results file is written can
+	// resume without creating the object again.
+	Checkpoint *checkpoint.Checkpoint
+}
+
+// Execute creates the planned objects level by level so every parent id is known
+// before its children are sent. Each level is sent in Graph batches of BatchSize;
+// a failed object marks its descendants skipped without stopping unrelated rows.
+func Execute(ctx context.Context, report *Report, batch BatchFunc, options ExecuteOptions) error {
+	if report == nil {
+		return errors.New("bulk import report is required")
+	}
+	if report.Invalid > 0 {
+		return NewValidationError(report)
+	}
+	if batch == nil {
+		return errors.New("bulk import batch executor is required")
+	}
+	accountID := strings.TrimPrefix(strings.TrimSpace(report.AccountID), "act_")
+	if accountID == "" {
+		return errors.New("bulk import account id is required")
+	}
+	batchSize := options.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultBatchSize
+	}
+	if batchSize < 0 || batchSize > DefaultBatchSize {
+		return fmt.Errorf("batch size must be between 1 and %d", DefaultBatchSize)
+	}
+	previous, err := options.Previous.createdIDs(report.AccountID)
+	if err != nil {
+		return err
+	}
+
+	var checkpointErr error
+	byKey := make(map[string]*Object, len(report.Objects))
+	for _, object := range report.Objects {
+		byKey[object.Key] = object
+	}
+
+	for _, kind := range kinds {
+		pending := make([]*Object, 0)
+		for _, object := range report.Objects {
+			if object.Kind != kind {
+				continue
+			}
+			if object.Action == ActionReference {
+				object.Status = StatusReferenced
+				continue
+			}
+			if parent := byKey[object.ParentKey]; parent != nil && parent.ID == "" {
+				object.Status = StatusSkipped
+				object.Error = fmt.Sprintf("parent %s was not created", parent.Key)
+				report.Summary.Skipped++
+				continue
+			}
+			if id := previous[object.IdempotencyKey]; id != "" {
+				object.ID = id
+				object.Status = StatusReused
+				report.Summary.Reused++
+				continue
+			}
+			var checkpointed string
+			if _, err := options.Checkpoint.Lookup(checkpointUnit(object), &checkpointed); err != nil {
+				return err
+			}
+			if checkpointed != "" {
+				object.ID = checkpointed
+				object.Status = StatusReused
+				report.Summary.Reused++
+				continue
+			}
+			pending = append(pending, object)
+		}
+
+		chunks := make([][]*Object, 0, (len(pending)+batchSize-1)/batchSize)
+		for start := 0; start < len(pending); start += batchSize {
+			chunks = append(chunks, pending[start:min(start+batchSize, len(pending))])
+		}
+		sent := make([]bool, len(chunks))
+		var mu sync.Mutex
+		_, runErr := workqueue.Run(ctx, len(chunks), workqueue.Options{Concurrency: options.Concurrency}, func(ctx context.Context, index int) error {
+			chunk := chunks[index]
+			requests := make([]graph.BatchRequest, 0, len(chunk))
+			for _, object := range chunk {
+				requests = append(requests, createRequest(accountID, object, byKey[object.ParentKey]))
+			}
+
+			results, err := batch(ctx, requests)
+			if err == nil && len(results) != len(chunk) {
+				err = fmt.Errorf("batch returned %d result(s) for %d request(s)", len(results), len(chunk))
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			sent[index] = true
+			for position, object := range chunk {
+				if err != nil {
+					markFailed(report, object, err)
+					continue
+				}
+				result := results[position]
+				if result.Error != nil {
+					markFailed(report, object, result.Error)
+					continue
+				}
+				id, _ := result.Body["id"].(string)
+				if strings.TrimSpace(id) == "" {
+					markFailed(report, object, errors.New("create response did not include an id"))
+					continue
+				}
+				object.ID = id
+				object.Status = StatusCreated
+				report.Summary.Created++
+				if err := options.Checkpoint.Complete(checkpointUnit(object), id); err != nil && checkpointErr == nil {
+					checkpointErr = err
+				}
+			}
+			if options.OnProgress != nil {
+				options.OnProgress(executeProgress(report))
+			}
+			return err
+		})
+		if runErr != nil {
+			// Canceled imports leave unsent objects skipped; a rerun with the
+			// same results file picks them up.
+			for index, chunk := range chunks {
+				if sent[index] {
+					continue
+				}
+				for _, object := range chunk {
+					object.Status = StatusSkipped
+					object.Error = fmt.Sprintf("not sent: %v", runErr)
+					report.Summary.Skipped++
+				}
+			}
+		}
+	}
+
+	resolveRows(report, byKey)
+	if checkpointErr != nil {
+		return fmt.Errorf("bulk import checkpoint: %w", checkpointErr)
+	}
+	if report.Summary.Failed == 0 && report.Summary.Skipped == 0 {
+		return nil
+	}
+	return &graph.APIError{
+		Type:      errorTypePartialFailure,
+		Code:      errorCodePartialFailure,
+		Message:   fmt.Sprintf("bulk import finished with %d failed and %d skipped object(s) of %d", report.Summary.Failed, report.Summary.Skipped, report.Summary.Create),
+		Retryable: false,
+		Diagnostics: map[string]any{
+			"report": report,
+		},
+		Remediation: &graph.Remediation{
+			Category: graph.RemediationCategoryValidation,
+			Summary:  "Some objects failed after other rows were created.",
+			Actions: []string{
+				"Inspect diagnostics.report.objects for failed objects and fix their rows.",
+				"Rerun the import with the same results file; objects created by earlier runs are reused through their idempotency keys.",
+			},
+		},
+	}
+}
+
+func NewValidationError(report *Report) error {
+	return &graph.APIError{
+		Type:      errorTypeValidation,
+		Code:      errorCodeValidation,
+		Message:   fmt.Sprintf("bulk sheet has %d invalid row(s) of %d; nothing was created", report.Invalid, report.Total),
+		Retryable: false,
+		Diagnostics: map[string]any{
+			"report": report,
+		},
+		Remediation: &graph.Remediation{
+			Category: graph.RemediationCategoryValidation,
+			Summary:  "Bulk sheet rows failed validation.",
+			Actions: []string{
+				"Fix the rows listed in diagnostics.report.rows and rerun; use --dry-run to validate without creating anything.",
+			},
+		},
+	}
+}
+
+func createRequest(accountID string, object *Object, parent *Object) graph.BatchRequest {
+	params := make(map[string]string, len(object.Params)+2)
+	for key, value := range object.Params {
+		params[key] = value
+	}
+	params["name"] = object.Name
+
+	edge := "campaigns"
+	switch object.Kind {
+	case KindAdSet:
+		edge = "adsets"
+		params["campaign_id"] = parent.ID
+	case KindAd:
+		edge = "ads"
+		params["adset_id"] = parent.ID
+	}
+	return graph.BatchRequest{
+		Method: "POST",
+		Path:   "act_" + accountID + "/" + edge,
+		Params: params,
+	}
+}
+
+// executeProgress counts objects rather than batches so the total stays fixed
+// across levels.
+func executeProgress(report *Report) workqueue.Progress {
+	summary := report.Summary
+	return workqueue.Progress{
+		Total:  summary.Create,
+		Done:   summary.Created + summary.Reused + summary.Failed + summary.Skipped,
+		Failed: summary.Failed,
+	}
+}
+
+// checkpointUnit keys created objects by idempotency key, which stays stable
+// when rows are reordered.
+func checkpointUnit(object *Object) string {
+	if object.IdempotencyKey != "" {
+		return object.IdempotencyKey
+	}
+	return object.Key
+}
+
+func markFailed(report *Report, object *Object, err error) {
+	object.Status = StatusFailed
+	object.Error = err.Error()
+	report.Summary.Failed++
+}
+
+// resolveRows copies object ids and failures back onto the rows that defined them.
+func resolveRows(report *Report, byKey map[string]*Object) {
+	rank := map[string]int{StatusReferenced: 0, StatusReused: 1, StatusCreated: 2, StatusRolledBack: 3, StatusSkipped: 4, StatusFailed: 5}
+	for index := range report.Rows {
+		entry := &report.Rows[index]
+		status := ""
+		for _, key := range []string{entry.CampaignKey, entry.AdSetKey, entry.AdKey} {
+			object := byKey[key]
+			if object == nil {
+				continue
+			}
+			switch object.Kind {
+			case KindCampaign:
+				entry.CampaignID = object.ID
+			case KindAdSet:
+				entry.AdSetID = object.ID
+			case KindAd:
+				entry.AdID = object.ID
+			}
+			if object.Error != "" {
+				entry.Errors = append(entry.Errors, object.Key+": "+object.Error)
+			}
+			if status == "" || rank[object.Status] > rank[status] {
+				status = object.Status
+			}
+		}
+		if status != "" {
+			entry.Status = status
+		}
+	}
+}
+
+// MarkRolledBack records that created objects with the given ids were paused or
+// deleted after a failed import. Rolled back objects are not reused by reruns.
+func MarkRolledBack(report *Report, ids map[string]struct{}) int {
+	rolledBack := map[string]struct{}{}
+	for _, object := range report.Objects {
+		if object.Status != StatusCreated {
+			continue
+		}
+		if _, ok := ids[object.ID]; !ok {
+			continue
+		}
+		object.Status = StatusRolledBack
+		report.Summary.Created--
+		report.Summary.RolledBack++
+		rolledBack[object.Key] = struct{}{}
+	}
+	for index := range report.Rows {
+		entry := &report.Rows[index]
+		if entry.Status == StatusFailed || entry.Status == StatusSkipped {
+			continue
+		}
+		for _, key := range []string{entry.CampaignKey, entry.AdSetKey, entry.AdKey} {
+			if _, ok := rolledBack[key]; ok {
+				entry.Status = StatusRolledBack
+				break
+			}
+		}
+	}
+	return len(rolledBack)
+}
+
+// Results is the file written after an import. It maps every sheet row to the ids
+// it produced and keeps object idempotency keys for reruns.
+type Results struct {
+	SchemaVersion int            `json:"schema_version"`
+	File          string         `json:"file"`
+	AccountID     string         `json:"account_id"`
+	Summary       Summary        `json:"summary"`
+	Rows          []RowResult    `json:"rows"`
+	Objects       []ObjectResult `json:"objects"`
+}
+
+type RowResult struct {
+	Row        int      `json:"row"`
+	Status     string   `json:"status"`
+	CampaignID string   `json:"campaign_id,omitempty"`
+	AdSetID    string   `json:"adset_id,omitempty"`
+	AdID       string   `json:"ad_id,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+type ObjectResult struct {
+	Key            string `json:"key"`
+	Kind           string `json:"kind"`
+	Name           string `json:"name,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	ID             string `json:"id,omitempty"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+}
+
+func NewResults(report *Report) *Results {
+	results := &Results{
+		SchemaVersion: ResultsSchemaVersion,
+		File:          report.File,
+		AccountID:     report.AccountID,
+		Summary:       report.Summary,
+		Rows:          make([]RowResult, 0, len(report.Rows)),
+		Objects:       make([]ObjectResult, 0, len(report.Objects)),
+	}
+	for _, entry := range report.Rows {
+		results.Rows = append(results.Rows, RowResult{
+			Row:        entry.Row,
+			Status:     entry.Status,
+			CampaignID: entry.CampaignID,
+			AdSetID:    entry.AdSetID,
+			AdID:       entry.AdID,
+			Errors:     entry.Errors,
+		})
+	}
+	for _, object := range report.Objects {
+		results.Objects = append(results.Objects, ObjectResult{
+			Key:            object.Key,
+			Kind:           object.Kind,
+			Name:           object.Name,
+			IdempotencyKey: object.IdempotencyKey,
+			ID:             object.ID,
+			Status:         object.Status,
+			Error:          object.Error,
+		})
+	}
+	return results
+}
+
+// DefaultResultsPath places the results next to the sheet: structures.csv ->
+// structures.results.json.
+func DefaultResultsPath(file string) string {
+	file = strings.TrimSpace(file)
+	return strings.TrimSuffix(file, filepath.Ext(file)) + ".results.json"
+}
+
+// LoadResults returns nil without error when no results file exists yet.
+func LoadResults(path string) (*Results, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("bulk results path is required")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read bulk results %s: %w", path, err)
+	}
+
+	results := &Results{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(results); err != nil {
+		return nil, fmt.Errorf("decode bulk results %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return nil, fmt.Errorf("decode bulk results %s: multiple JSON values", path)
+		}
+		return nil, fmt.Errorf("decode bulk results %s: %w", path, err)
+	}
+	if results.SchemaVersion != ResultsSchemaVersion {
+		return nil, fmt.Errorf("unsupported bulk results schema_version=%d in %s (expected %d)", results.SchemaVersion, path, ResultsSchemaVersion)
+	}
+	return results, nil
+}
+
+func WriteResults(path string, results *Results) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return errors.New("bulk results path is required")
+	}
+	if results == nil {
+		return errors.New("bulk results are required")
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create bulk results directory for %s: %w", path, err)
+	}
+	payload, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode bulk results: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".bulk-results-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp bulk results file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp bulk results file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp bulk results file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("replace bulk results %s: %w", path, err)
+	}
+	return nil
+}
+
+func (r *Results) createdIDs(accountID string) (map[string]string, error) {
+	ids := map[string]string{}
+	if r == nil {
+		return ids, nil
+	}
+	if normalizeAccount(r.AccountID) != normalizeAccount(accountID) {
+		return nil, fmt.Errorf("bulk results belong to act_%s, not act_%s", normalizeAccount(r.AccountID), normalizeAccount(accountID))
+	}
+	for _, object := range r.Objects {
+		if object.IdempotencyKey == "" || object.ID == "" {
+			continue
+		}
+		if object.Status == StatusCreated || object.Status == StatusReused {
+			ids[object.IdempotencyKey] = object.ID
+		}
+	}
+	return ids, nil
+}
+
+func normalizeAccount(accountID string) string {
+	return strings.TrimPrefix(strings.TrimSpace(accountID), "act_")
+}
@@ -0,0 +1,248 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/checkpoint"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/workqueue"
+)
+
+// fakeBatch answers create requests with sequential ids and records every batch.
+type fakeBatch struct {
+	mu      sync.Mutex
+	calls   [][]graph.BatchRequest
+	fail    map[string]bool
+	created int
+}
+
+func (f *fakeBatch) execute(_ context.Context, requests []graph.BatchRequest) ([]graph.BatchResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, requests)
+	results := make([]graph.BatchResult, 0, len(requests))
+	for _, request := range requests {
+		if f.fail[request.Params["name"]] {
+			results = append(results, graph.BatchResult{Code: 400, Error: &graph.APIError{Type: "OAuthException", Code: 100, Message: "invalid parameter"}})
+			continue
+		}
+		f.created++
+		results = append(results, graph.BatchResult{Code: 200, Body: map[string]any{"id": request.Params["name"] + "_id"}})
+	}
+	return results, nil
+}
+
+func planSheet(t *testing.T, content string) *Report {
+	t.Helper()
+	rows, err := decodeCSV([]byte(content))
+	if err != nil {
+		t.Fatalf("decode sheet: %v", err)
+	}
+	report := Plan(rows, PlanOptions{File: "structures.csv", AccountID: "act_1"})
+	if report.Invalid != 0 {
+		t.Fatalf("unexpected invalid rows %#v", report.Rows)
+	}
+	return report
+}
+
+func TestExecuteCreatesLevelsInBatches(t *testing.T) {
+	t.Parallel()
+
+	report := planSheet(t, `campaign_name,campaign.objective,adset_name,ad_name
+C1,OUTCOME_SALES,S1,A1
+C1,OUTCOME_SALES,S1,A2
+C2,OUTCOME_SALES,S2,A3
+`)
+	batch := &fakeBatch{}
+	if err := Execute(context.Background(), report, batch.execute, ExecuteOptions{BatchSize: 2}); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	// campaigns (2) -> ad sets (2) -> ads (2 + 1)
+	if len(batch.calls) != 4 || len(batch.calls[3]) != 1 {
+		t.Fatalf("unexpected batches %#v", batch.calls)
+	}
+	adSetRequest := batch.calls[1][0]
+	if adSetRequest.Method != "POST" || adSetRequest.Path != "act_1/adsets" || adSetRequest.Params["campaign_id"] != "C1_id" {
+		t.Fatalf("unexpected ad set request %#v", adSetRequest)
+	}
+	if report.Summary.Created != 7 {
+		t.Fatalf("unexpected summary %#v", report.Summary)
+	}
+	row := report.Rows[1]
+	if row.Status != StatusCreated || row.CampaignID != "C1_id" || row.AdSetID != "S1_id" || row.AdID != "A2_id" {
+		t.Fatalf("unexpected row mapping %#v", row)
+	}
+}
+
+func TestExecuteSendsBatchesOfALevelConcurrently(t *testing.T) {
+	t.Parallel()
+
+	report := planSheet(t, "campaign_name,adset_name\nC1,S1\nC2,S2\nC3,S3\n")
+	batch := &fakeBatch{fail: map[string]bool{"S2": true}}
+	var last workqueue.Progress
+	err := Execute(context.Background(), report, batch.execute, ExecuteOptions{
+		BatchSize:   1,
+		Concurrency: 3,
+		OnProgress:  func(progress workqueue.Progress) { last = progress },
+	})
+
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != errorTypePartialFailure {
+		t.Fatalf("expected partial failure, got %v", err)
+	}
+	if len(batch.calls) != 6 || report.Summary.Created != 5 || report.Summary.Failed != 1 {
+		t.Fatalf("unexpected calls=%d summary=%#v", len(batch.calls), report.Summary)
+	}
+	for _, call := range batch.calls[3:] {
+		if call[0].Path != "act_1/adsets" {
+			t.Fatalf("expected ad sets to follow every campaign, got %#v", batch.calls)
+		}
+	}
+	if last.String() != "6/6 done, 1 failed" {
+		t.Fatalf("unexpected final progress %#v", last)
+	}
+}
+
+func TestExecuteSkipsChildrenOfFailedObjects(t *testing.T) {
+	t.Parallel()
+
+	report := planSheet(t, "campaign_name,adset_name,ad_name\nC1,S1,A1\nC2,S2,A2\n")
+	batch := &fakeBatch{fail: map[string]bool{"S1": true}}
+	err := Execute(context.Background(), report, batch.execute, ExecuteOptions{})
+
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != errorTypePartialFailure {
+		t.Fatalf("expected partial failure, got %v", err)
+	}
+	if report.Summary.Failed != 1 || report.Summary.Skipped != 1 || report.Summary.Created != 4 {
+		t.Fatalf("unexpected summary %#v", report.Summary)
+	}
+	if report.Rows[0].Status != StatusFailed || report.Rows[0].CampaignID != "C1_id" || report.Rows[1].Status != StatusCreated {
+		t.Fatalf("unexpected rows %#v", report.Rows)
+	}
+}
+
+func TestExecuteStopsSendingWhenCanceled(t *testing.T) {
+	t.Parallel()
+
+	report := planSheet(t, "campaign_name,adset_name\nC1,S1\nC2,S2\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	batch := &fakeBatch{}
+	cancelAfterFirst := func(ctx context.Context, requests []graph.BatchRequest) ([]graph.BatchResult, error) {
+		defer cancel()
+		return batch.execute(ctx, requests)
+	}
+	err := Execute(ctx, report, cancelAfterFirst, ExecuteOptions{})
+
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != errorTypePartialFailure {
+		t.Fatalf("expected partial failure, got %v", err)
+	}
+	if len(batch.calls) != 1 || report.Summary.Created != 2 || report.Summary.Skipped != 2 {
+		t.Fatalf("expected only the campaign batch to be sent, got calls=%d summary=%#v", len(batch.calls), report.Summary)
+	}
+	if object := report.Objects[len(report.Objects)-1]; object.Status != StatusSkipped || !strings.Contains(object.Error, "context canceled") {
+		t.Fatalf("unexpected unsent object %#v", object)
+	}
+}
+
+func TestExecuteReusesObjectsFromResultsFile(t *testing.T) {
+	t.Parallel()
+
+	sheet := "campaign_name,adset_name\nC1,S1\nC1,S2\n"
+	first := planSheet(t, sheet)
+	batch := &fakeBatch{fail: map[string]bool{"S2": true}}
+	if err := Execute(context.Background(), first, batch.execute, ExecuteOptions{}); err == nil {
+		t.Fatal("expected first run to fail")
+	}
+	path := filepath.Join(t.TempDir(), "structures.results.json")
+	if err := WriteResults(path, NewResults(first)); err != nil {
+		t.Fatalf("write results: %v", err)
+	}
+	previous, err := LoadResults(path)
+	if err != nil {
+		t.Fatalf("load results: %v", err)
+	}
+
+	second := planSheet(t, sheet)
+	retry := &fakeBatch{}
+	if err := Execute(context.Background(), second, retry.execute, ExecuteOptions{Previous: previous}); err != nil {
+		t.Fatalf("rerun: %v", err)
+	}
+	if retry.created != 1 || second.Summary.Reused != 2 || second.Rows[1].AdSetID != "S2_id" {
+		t.Fatalf("expected only S2 to be created on rerun, got %#v", second.Summary)
+	}
+
+	if _, err := LoadResults(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Fatalf("expected missing results to load as nil, got %v", err)
+	}
+	other := planSheet(t, sheet)
+	other.AccountID = "act_2"
+	if err := Execute(context.Background(), other, retry.execute, ExecuteOptions{Previous: previous}); err == nil || !strings.Contains(err.Error(), "belong to act_1") {
+		t.Fatalf("expected account mismatch error, got %v", err)
+	}
+}
+
+func TestExecuteResumesFromCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	sheet := "campaign_name,adset_name\nC1,S1\nC1,S2\n"
+	path := filepath.Join(t.TempDir(), "structures.checkpoint.json")
+	first, err := checkpoint.Open(path, "bulk_import", "sheet", false)
+	if err != nil {
+		t.Fatalf("open checkpoint: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupted := planSheet(t, sheet)
+	batch := &fakeBatch{}
+	// Cancel once the campaign level is sent, as an interrupt would.
+	err = Execute(ctx, interrupted, func(ctx context.Context, requests []graph.BatchRequest) ([]graph.BatchResult, error) {
+		defer cancel()
+		return batch.execute(ctx, requests)
+	}, ExecuteOptions{Checkpoint: first})
+	if err == nil || interrupted.Summary.Created != 1 {
+		t.Fatalf("expected interrupted run after the campaign, got %v %#v", err, interrupted.Summary)
+	}
+
+	resumed, err := checkpoint.Open(path, "bulk_import", "sheet", true)
+	if err != nil {
+		t.Fatalf("resume checkpoint: %v", err)
+	}
+	second := planSheet(t, sheet)
+	retry := &fakeBatch{}
+	if err := Execute(context.Background(), second, retry.execute, ExecuteOptions{Checkpoint: resumed}); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if retry.created != 2 || second.Summary.Reused != 1 || second.Rows[0].CampaignID != "C1_id" {
+		t.Fatalf("expected only ad sets to be created on resume, got %d %#v", retry.created, second.Summary)
+	}
+	if resumed.Len() != 3 {
+		t.Fatalf("expected every created object checkpointed, got %v", resumed.Units())
+	}
+}
+
+func TestExecuteRejectsInvalidReport(t *testing.T) {
+	t.Parallel()
+
+	report := &Report{Total: 1, Invalid: 1, AccountID: "act_1"}
+	err := Execute(context.Background(), report, (&fakeBatch{}).execute, ExecuteOptions{})
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != errorTypeValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestDefaultResultsPath(t *testing.T) {
+	t.Parallel()
+
+	if got := DefaultResultsPath("sheets/structures.csv"); got != "sheets/structures.results.json" {
+		t.Fatalf("unexpected results path %q", got)
+	}
+}
@@ -0,0 +1,436 @@
+package bulk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	KindCampaign = "campaign"
+	KindAdSet    = "adset"
+	KindAd       = "ad"
+
+	ActionCreate    = "create"
+	ActionReference = "reference"
+
+	StatusPlanned    = "planned"
+	StatusCreated    = "created"
+	StatusReused     = "reused"
+	StatusReferenced = "referenced"
+	StatusFailed     = "failed"
+	StatusSkipped    = "skipped"
+	StatusRolledBack = "rolled_back"
+
+	RowStatusValid   = "valid"
+	RowStatusInvalid = "invalid"
+
+	idempotencyKeyPrefix = "bulk:"
+)
+
+// kinds lists object levels parent first; it is also the execution order.
+var kinds = []string{KindCampaign, KindAdSet, KindAd}
+
+// hierarchyColumns name or reference the object a row belongs to. Every other
+// column is a create param prefixed by its level, e.g. adset.daily_budget or the
+// Ads Manager style "Ad Set Daily Budget".
+var hierarchyColumns = map[string]struct{}{
+	"campaign_name": {},
+	"campaign_id":   {},
+	"adset_name":    {},
+	"adset_id":      {},
+	"ad_name":       {},
+}
+
+// reservedParams are derived from the hierarchy columns and may not be set as params.
+var reservedParams = map[string]struct{}{
+	"name":        {},
+	"campaign_id": {},
+	"adset_id":    {},
+	"account_id":  {},
+}
+
+// Row is one decoded line of a bulk sheet.
+type Row struct {
+	Number       int
+	CampaignName string
+	CampaignID   string
+	AdSetName    string
+	AdSetID      string
+	AdName       string
+	Params       map[string]map[string]string
+}
+
+// Object is a campaign, ad set, or ad the plan creates or references. Sources maps
+// each param to the sheet row that supplied it.
+type Object struct {
+	Key            string            `json:"key"`
+	ParentKey      string            `json:"parent_key,omitempty"`
+	Kind           string            `json:"kind"`
+	Name           string            `json:"name,omitempty"`
+	Action         string            `json:"action"`
+	ID             string            `json:"id,omitempty"`
+	Params         map[string]string `json:"params,omitempty"`
+	Sources        map[string]int    `json:"sources,omitempty"`
+	Rows           []int             `json:"rows"`
+	IdempotencyKey string            `json:"idempotency_key,omitempty"`
+	Status         string            `json:"status"`
+	Error          string            `json:"error,omitempty"`
+}
+
+// RowEntry reports what a sheet row maps to and, after execution, the ids it produced.
+type RowEntry struct {
+	Row         int      `json:"row"`
+	Level       string   `json:"level"`
+	CampaignKey string   `json:"campaign_key,omitempty"`
+	AdSetKey    string   `json:"adset_key,omitempty"`
+	AdKey       string   `json:"ad_key,omitempty"`
+	CampaignID  string   `json:"campaign_id,omitempty"`
+	AdSetID     string   `json:"adset_id,omitempty"`
+	AdID        string   `json:"ad_id,omitempty"`
+	Status      string   `json:"status"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+type Summary struct {
+	Create     int `json:"create"`
+	Reference  int `json:"reference"`
+	Created    int `json:"created"`
+	Reused     int `json:"reused"`
+	Failed     int `json:"failed"`
+	Skipped    int `json:"skipped"`
+	RolledBack int `json:"rolled_back,omitempty"`
+}
+
+type Report struct {
+	File      string     `json:"file"`
+	AccountID string     `json:"account_id"`
+	DryRun    bool       `json:"dry_run"`
+	Total     int        `json:"total"`
+	Invalid   int        `json:"invalid"`
+	Summary   Summary    `json:"summary"`
+	Objects   []*Object  `json:"objects"`
+	Rows      []RowEntry `json:"rows"`
+}
+
+type PlanOptions struct {
+	File      string
+	AccountID string
+	// Lint validates the params of an object to be created, e.g. against the schema pack.
+	Lint func(kind string, params map[string]string) error
+}
+
+func LoadFile(path string) ([]Row, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("bulk file path is required")
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".csv") {
+		return nil, fmt.Errorf("unsupported bulk file %s: expected .csv", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read bulk file %s: %w", path, err)
+	}
+	rows, err := decodeCSV(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode bulk file %s: %w", path, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("bulk file %s has no rows", path)
+	}
+	return rows, nil
+}
+
+// Plan validates every row and folds rows into the objects they create. Rows that
+// repeat a campaign or ad set share it; a param may be repeated across those rows
+// only with the same value.
+func Plan(rows []Row, options PlanOptions) *Report {
+	report := &Report{
+		File:      options.File,
+		AccountID: options.AccountID,
+		Total:     len(rows),
+		Objects:   make([]*Object, 0),
+		Rows:      make([]RowEntry, 0, len(rows)),
+	}
+	objects := map[string]*Object{}
+	rowErrors := map[int][]string{}
+	addError := func(row int, message string) {
+		rowErrors[row] = append(rowErrors[row], message)
+	}
+
+	for _, row := range rows {
+		entry := RowEntry{Row: row.Number}
+		keys := map[string]string{}
+		parentKey := ""
+		for _, kind := range kinds {
+			name, id := row.identity(kind)
+			params := row.Params[kind]
+			if name == "" && id == "" {
+				if len(params) > 0 {
+					addError(row.Number, fmt.Sprintf("%s params are set but the row names no %s", kind, kind))
+				}
+				continue
+			}
+			if kind != KindCampaign && parentKey == "" && id == "" {
+				addError(row.Number, fmt.Sprintf("%s %q needs a parent %s", kind, name, parentKind(kind)))
+				break
+			}
+
+			object, err := foldObject(objects, report, row, kind, parentKey, name, id, params)
+			if err != nil {
+				addError(row.Number, err.Error())
+			}
+			if object == nil {
+				break
+			}
+			keys[kind] = object.Key
+			entry.Level = kind
+			parentKey = object.Key
+		}
+		entry.CampaignKey = keys[KindCampaign]
+		entry.AdSetKey = keys[KindAdSet]
+		entry.AdKey = keys[KindAd]
+		report.Rows = append(report.Rows, entry)
+	}
+
+	for _, object := range report.Objects {
+		if object.Action != ActionCreate {
+			report.Summary.Reference++
+			continue
+		}
+		report.Summary.Create++
+		object.IdempotencyKey = idempotencyKey(object)
+		if options.Lint != nil {
+			if err := options.Lint(object.Kind, object.Params); err != nil {
+				addError(object.Rows[0], fmt.Sprintf("%s %q: %v", object.Kind, object.Name, err))
+			}
+		}
+	}
+
+	for index := range report.Rows {
+		entry := &report.Rows[index]
+		entry.Errors = rowErrors[entry.Row]
+		entry.Status = RowStatusValid
+		if len(entry.Errors) > 0 {
+			entry.Status = RowStatusInvalid
+			report.Invalid++
+		}
+	}
+	return report
+}
+
+func foldObject(objects map[string]*Object, report *Report, row Row, kind string, parentKey string, name string, id string, params map[string]string) (*Object, error) {
+	if id != "" && name != "" {
+		return nil, fmt.Errorf("set either %s_name or %s_id, not both", kind, kind)
+	}
+	if id != "" && strings.Contains(id, "/") {
+		return nil, fmt.Errorf("invalid %s_id %q: expected single graph id token", kind, id)
+	}
+	for param := range params {
+		if _, reserved := reservedParams[param]; reserved {
+			return nil, fmt.Errorf("%s.%s is derived from the hierarchy columns and cannot be set", kind, param)
+		}
+	}
+
+	key := kind + ":" + name
+	action := ActionCreate
+	if id != "" {
+		key = kind + "#" + id
+		action = ActionReference
+		if len(params) > 0 {
+			return nil, fmt.Errorf("%s %s is referenced by id; bulk import only sets params on objects it creates", kind, id)
+		}
+		// Referenced objects are global; only created objects are scoped to their parent.
+		parentKey = ""
+	}
+	if parentKey != "" {
+		key = parentKey + "/" + key
+	}
+
+	object, exists := objects[key]
+	if !exists {
+		object = &Object{
+			Key:       key,
+			ParentKey: parentKey,
+			Kind:      kind,
+			Name:      name,
+			Action:    action,
+			ID:        id,
+			Params:    map[string]string{},
+			Sources:   map[string]int{},
+			Status:    StatusPlanned,
+		}
+		objects[key] = object
+		report.Objects = append(report.Objects, object)
+	}
+	if object.Rows == nil || object.Rows[len(object.Rows)-1] != row.Number {
+		object.Rows = append(object.Rows, row.Number)
+	}
+
+	for _, param := range sortedKeys(params) {
+		value := params[param]
+		if previous, set := object.Params[param]; set {
+			if previous != value {
+				return object, fmt.Errorf("%s.%s=%q conflicts with %q from row %d", kind, param, value, previous, object.Sources[param])
+			}
+			continue
+		}
+		object.Params[param] = value
+		object.Sources[param] = row.Number
+	}
+	return object, nil
+}
+
+func (r Row) identity(kind string) (string, string) {
+	switch kind {
+	case KindCampaign:
+		return r.CampaignName, r.CampaignID
+	case KindAdSet:
+		return r.AdSetName, r.AdSetID
+	default:
+		return r.AdName, ""
+	}
+}
+
+func parentKind(kind string) string {
+	if kind == KindAd {
+		return KindAdSet
+	}
+	return KindCampaign
+}
+
+// idempotencyKey is stable for the same object definition, so rerunning a sheet
+// against its results file reuses objects that were already created.
+func idempotencyKey(object *Object) string {
+	hash := sha256.New()
+	_, _ = io.WriteString(hash, object.Kind+"\n"+object.Key+"\n")
+	for _, param := range sortedKeys(object.Params) {
+		_, _ = io.WriteString(hash, param+"="+object.Params[param]+"\n")
+	}
+	return idempotencyKeyPrefix + hex.EncodeToString(hash.Sum(nil))[:32]
+}
+
+func decodeCSV(data []byte) ([]Row, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("missing header row")
+		}
+		return nil, err
+	}
+
+	type column struct {
+		kind  string
+		param string
+	}
+	columns := make([]column, len(header))
+	seen := map[string]struct{}{}
+	for index, raw := range header {
+		name := normalizeColumn(raw)
+		if name == "" {
+			return nil, fmt.Errorf("column %d has an empty header", index+1)
+		}
+		if _, exists := seen[name]; exists {
+			return nil, fmt.Errorf("duplicate column %q", raw)
+		}
+		seen[name] = struct{}{}
+		if _, ok := hierarchyColumns[name]; ok {
+			columns[index] = column{param: name}
+			continue
+		}
+		kind, param, ok := splitParamColumn(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q: expected campaign_name, campaign_id, adset_name, adset_id, ad_name, or a campaign./adset./ad. param column", raw)
+		}
+		columns[index] = column{kind: kind, param: param}
+	}
+
+	rows := make([]Row, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := Row{Number: line, Params: map[string]map[string]string{}}
+		empty := true
+		for index, value := range record {
+			value = strings.TrimSpace(value)
+			if value == "" || index >= len(columns) {
+				continue
+			}
+			empty = false
+			target := columns[index]
+			if target.kind == "" {
+				switch target.param {
+				case "campaign_name":
+					row.CampaignName = value
+				case "campaign_id":
+					row.CampaignID = value
+				case "adset_name":
+					row.AdSetName = value
+				case "adset_id":
+					row.AdSetID = value
+				case "ad_name":
+					row.AdName = value
+				}
+				continue
+			}
+			if row.Params[target.kind] == nil {
+				row.Params[target.kind] = map[string]string{}
+			}
+			row.Params[target.kind][target.param] = value
+		}
+		if empty {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// normalizeColumn maps both campaign.objective and "Campaign Objective" style
+// headers onto one lower_snake form; "ad set" is folded into "adset".
+func normalizeColumn(raw string) string {
+	name := strings.ToLower(strings.TrimSpace(raw))
+	name = strings.Join(strings.Fields(name), "_")
+	name = strings.ReplaceAll(name, "-", "_")
+	if strings.HasPrefix(name, "ad_set") {
+		name = "adset" + strings.TrimPrefix(name, "ad_set")
+	}
+	return name
+}
+
+func splitParamColumn(name string) (string, string, bool) {
+	for _, kind := range []string{KindCampaign, KindAdSet, KindAd} {
+		for _, separator := range []string{".", "_"} {
+			prefix := kind + separator
+			if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+				return kind, strings.TrimPrefix(name, prefix), true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
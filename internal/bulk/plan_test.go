@@ -0,0 +1,136 @@
+package bulk
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const planTestSheet = `Campaign Name,Campaign Objective,Ad Set Name,adset.daily_budget,ad_name,ad.creative
+Launch,OUTCOME_SALES,Prospecting,1000,Ad A,"{""creative_id"":""cr1""}"
+Launch,,Prospecting,1000,Ad B,"{""creative_id"":""cr2""}"
+Launch,OUTCOME_SALES,Retargeting,500,,
+`
+
+func writeSheet(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "structures.csv")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write sheet: %v", err)
+	}
+	return path
+}
+
+func TestPlanFoldsRowsIntoHierarchy(t *testing.T) {
+	t.Parallel()
+
+	rows, err := LoadFile(writeSheet(t, planTestSheet))
+	if err != nil {
+		t.Fatalf("load sheet: %v", err)
+	}
+	report := Plan(rows, PlanOptions{AccountID: "act_1"})
+	if report.Invalid != 0 {
+		t.Fatalf("unexpected invalid rows %#v", report.Rows)
+	}
+	if report.Summary.Create != 5 || len(report.Objects) != 5 {
+		t.Fatalf("expected 1 campaign, 2 ad sets, 2 ads; got %#v", report.Objects)
+	}
+
+	campaign := report.Objects[0]
+	if campaign.Key != "campaign:Launch" || campaign.Params["objective"] != "OUTCOME_SALES" || campaign.Sources["objective"] != 2 {
+		t.Fatalf("unexpected campaign %#v", campaign)
+	}
+	if want := []int{2, 3, 4}; len(campaign.Rows) != len(want) {
+		t.Fatalf("expected campaign rows %v, got %v", want, campaign.Rows)
+	}
+	adSet := report.Objects[1]
+	if adSet.Key != "campaign:Launch/adset:Prospecting" || adSet.ParentKey != campaign.Key || adSet.Params["daily_budget"] != "1000" {
+		t.Fatalf("unexpected ad set %#v", adSet)
+	}
+	if report.Rows[0].Level != KindAd || report.Rows[2].Level != KindAdSet {
+		t.Fatalf("unexpected row levels %#v", report.Rows)
+	}
+	if !strings.HasPrefix(campaign.IdempotencyKey, "bulk:") || campaign.IdempotencyKey == adSet.IdempotencyKey {
+		t.Fatalf("unexpected idempotency keys %q %q", campaign.IdempotencyKey, adSet.IdempotencyKey)
+	}
+}
+
+func TestPlanReportsRowErrors(t *testing.T) {
+	t.Parallel()
+
+	rows, err := decodeCSV([]byte(`campaign_name,campaign.objective,adset_name,adset_id,ad_name,adset.name
+Launch,OUTCOME_SALES,,,,
+Launch,OUTCOME_TRAFFIC,,,,
+,,,,Orphan,
+Launch,,Prospecting,123,,
+Launch,,,,,Named
+`))
+	if err != nil {
+		t.Fatalf("decode sheet: %v", err)
+	}
+	report := Plan(rows, PlanOptions{AccountID: "act_1"})
+
+	want := map[int]string{
+		3: `campaign.objective="OUTCOME_TRAFFIC" conflicts with "OUTCOME_SALES" from row 2`,
+		4: "ad \"Orphan\" needs a parent adset",
+		5: "set either adset_name or adset_id, not both",
+		6: "adset params are set but the row names no adset",
+	}
+	if report.Invalid != len(want) {
+		t.Fatalf("expected %d invalid rows, got %#v", len(want), report.Rows)
+	}
+	for _, entry := range report.Rows {
+		message, invalid := want[entry.Row]
+		if !invalid {
+			if entry.Status != RowStatusValid {
+				t.Fatalf("expected row %d to be valid, got %v", entry.Row, entry.Errors)
+			}
+			continue
+		}
+		if entry.Status != RowStatusInvalid || !strings.Contains(strings.Join(entry.Errors, "; "), message) {
+			t.Fatalf("row %d: expected error %q, got %v", entry.Row, message, entry.Errors)
+		}
+	}
+}
+
+func TestPlanLintsCreatedObjects(t *testing.T) {
+	t.Parallel()
+
+	rows, err := decodeCSV([]byte("campaign_id,adset_name,adset.bogus\n42,Prospecting,1\n"))
+	if err != nil {
+		t.Fatalf("decode sheet: %v", err)
+	}
+	linted := make([]string, 0)
+	report := Plan(rows, PlanOptions{
+		AccountID: "act_1",
+		Lint: func(kind string, params map[string]string) error {
+			linted = append(linted, kind)
+			if _, ok := params["bogus"]; ok {
+				return errors.New(`unknown param "bogus"`)
+			}
+			return nil
+		},
+	})
+	if len(linted) != 1 || linted[0] != KindAdSet {
+		t.Fatalf("expected only the created ad set to be linted, got %v", linted)
+	}
+	if report.Summary.Reference != 1 || report.Invalid != 1 || !strings.Contains(report.Rows[0].Errors[0], `unknown param "bogus"`) {
+		t.Fatalf("unexpected report %#v", report)
+	}
+}
+
+func TestDecodeCSVRejectsUnknownColumns(t *testing.T) {
+	t.Parallel()
+
+	if _, err := decodeCSV([]byte("campaign_name,budget\nLaunch,1\n")); err == nil || !strings.Contains(err.Error(), `unknown column "budget"`) {
+		t.Fatalf("expected unknown column error, got %v", err)
+	}
+	if _, err := decodeCSV([]byte("Campaign Name,campaign_name\nLaunch,Launch\n")); err == nil || !strings.Contains(err.Error(), "duplicate column") {
+		t.Fatalf("expected duplicate column error, got %v", err)
+	}
+	if _, err := LoadFile(writeSheet(t, "campaign_name\n")); err == nil || !strings.Contains(err.Error(), "has no rows") {
+		t.Fatalf("expected empty sheet error, got %v", err)
+	}
+}
@@ -0,0 +1,443 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	AdAccountScopeOwned  = "owned"
+	AdAccountScopeClient = "client"
+
+	AssetTypeAdAccount = "ad-account"
+	AssetTypePage      = "page"
+	AssetTypePixel     = "pixel"
+
+	RoleAdmin      = "admin"
+	RoleAdvertiser = "advertiser"
+	RoleAnalyst    = "analyst"
+
+	BusinessRoleAdmin    = "ADMIN"
+	BusinessRoleEmployee = "EMPLOYEE"
+)
+
+var (
+	DefaultAdAccountFields = []string{"id", "account_id", "name", "account_status", "currency", "timezone_name"}
+
+	assignedUserFields = []string{"id", "name", "user_type", "tasks"}
+
+	// assetTasks lists the tasks each asset type accepts on its assigned_users edge.
+	assetTasks = map[string][]string{
+		AssetTypeAdAccount: {"MANAGE", "ADVERTISE", "ANALYZE", "DRAFT"},
+		AssetTypePage:      {"MANAGE", "CREATE_CONTENT", "MODERATE", "MESSAGING", "ADVERTISE", "ANALYZE"},
+		AssetTypePixel:     {"EDIT", "UPLOAD", "ANALYZE"},
+	}
+	// roleTasks maps CLI roles to the task set granted for each asset type.
+	roleTasks = map[string]map[string][]string{
+		RoleAdmin: {
+			AssetTypeAdAccount: {"MANAGE", "ADVERTISE", "ANALYZE"},
+			AssetTypePage:      {"MANAGE", "CREATE_CONTENT", "MODERATE", "MESSAGING", "ADVERTISE", "ANALYZE"},
+			AssetTypePixel:     {"EDIT", "UPLOAD", "ANALYZE"},
+		},
+		RoleAdvertiser: {
+			AssetTypeAdAccount: {"ADVERTISE", "ANALYZE"},
+			AssetTypePage:      {"CREATE_CONTENT", "MODERATE", "ADVERTISE", "ANALYZE"},
+			AssetTypePixel:     {"UPLOAD", "ANALYZE"},
+		},
+		RoleAnalyst: {
+			AssetTypeAdAccount: {"ANALYZE"},
+			AssetTypePage:      {"ANALYZE"},
+			AssetTypePixel:     {"ANALYZE"},
+		},
+	}
+	// privilegedTasks are flagged by the permission audit.
+	privilegedTasks = map[string]struct{}{"MANAGE": {}, "EDIT": {}}
+)
+
+type ListAdAccountsOptions struct {
+	BusinessID string
+	Scope      string
+	Fields     []string
+	Limit      int
+	FollowNext bool
+}
+
+type ListAdAccountsResult struct {
+	BusinessID  string                  `json:"business_id"`
+	Scope       string                  `json:"scope"`
+	RequestPath string                  `json:"request_path"`
+	AdAccounts  []map[string]any        `json:"ad_accounts"`
+	Paging      *graph.PaginationResult `json:"paging,omitempty"`
+}
+
+type InviteUserOptions struct {
+	BusinessID string
+	Email      string
+	Role       string
+}
+
+type AssignAssetOptions struct {
+	BusinessID string
+	AssetType  string
+	AssetID    string
+	UserID     string
+	Role       string
+	Tasks      []string
+}
+
+type MutationResult struct {
+	Operation   string         `json:"operation"`
+	BusinessID  string         `json:"business_id"`
+	RequestPath string         `json:"request_path"`
+	Grant       Grant          `json:"grant"`
+	Response    map[string]any `json:"response"`
+}
+
+// Grant describes the permission a mutation gives away. It is shown before
+// confirmation and echoed in the result.
+type Grant struct {
+	AssetType string   `json:"asset_type,omitempty"`
+	AssetID   string   `json:"asset_id,omitempty"`
+	UserID    string   `json:"user_id,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	Role      string   `json:"role"`
+	Tasks     []string `json:"tasks,omitempty"`
+}
+
+type AuditOptions struct {
+	BusinessID string
+}
+
+type Assignment struct {
+	AssetType  string   `json:"asset_type"`
+	AssetID    string   `json:"asset_id"`
+	AssetName  string   `json:"asset_name,omitempty"`
+	UserID     string   `json:"user_id"`
+	UserName   string   `json:"user_name,omitempty"`
+	UserType   string   `json:"user_type,omitempty"`
+	Tasks      []string `json:"tasks"`
+	Privileged bool     `json:"privileged"`
+}
+
+type AuditResult struct {
+	BusinessID      string           `json:"business_id"`
+	AssetCounts     map[string]int   `json:"asset_counts"`
+	Users           []map[string]any `json:"users"`
+	SystemUsers     []map[string]any `json:"system_users"`
+	Assignments     []Assignment     `json:"assignments"`
+	PrivilegedCount int              `json:"privileged_count"`
+}
+
+type Service struct {
+	Client *graph.Client
+}
+
+func New(client *graph.Client) *Service {
+	if client == nil {
+		client = graph.NewClient(nil, "")
+	}
+	return &Service{Client: client}
+}
+
+func (s *Service) ListAdAccounts(ctx context.Context, version string, token string, appSecret string, options ListAdAccountsOptions) (*ListAdAccountsResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("business service client is required")
+	}
+	businessID, err := normalizeGraphID("business id", options.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	scope := strings.ToLower(strings.TrimSpace(options.Scope))
+	if scope == "" {
+		scope = AdAccountScopeOwned
+	}
+	if scope != AdAccountScopeOwned && scope != AdAccountScopeClient {
+		return nil, fmt.Errorf("unsupported ad account scope %q: expected owned|client", options.Scope)
+	}
+	fields := options.Fields
+	if len(fields) == 0 {
+		fields = DefaultAdAccountFields
+	}
+
+	path := businessID + "/" + scope + "_ad_accounts"
+	accounts, pagination, err := s.list(ctx, version, token, appSecret, path, map[string]string{"fields": strings.Join(fields, ",")}, options.Limit, options.FollowNext)
+	if err != nil {
+		return nil, err
+	}
+	return &ListAdAccountsResult{
+		BusinessID:  businessID,
+		Scope:       scope,
+		RequestPath: path,
+		AdAccounts:  accounts,
+		Paging:      pagination,
+	}, nil
+}
+
+// PlanInvite validates an invite and returns the grant it would create without
+// calling the Graph API.
+func PlanInvite(options InviteUserOptions) (Grant, error) {
+	if _, err := normalizeGraphID("business id", options.BusinessID); err != nil {
+		return Grant{}, err
+	}
+	email := strings.ToLower(strings.TrimSpace(options.Email))
+	if strings.Count(email, "@") != 1 || strings.HasPrefix(email, "@") || strings.HasSuffix(email, "@") {
+		return Grant{}, fmt.Errorf("invalid invite email %q", options.Email)
+	}
+	role := strings.ToUpper(strings.TrimSpace(options.Role))
+	if role == "" {
+		role = BusinessRoleEmployee
+	}
+	if role != BusinessRoleEmployee && role != BusinessRoleAdmin {
+		return Grant{}, fmt.Errorf("unsupported business role %q: expected EMPLOYEE|ADMIN", options.Role)
+	}
+	return Grant{Email: email, Role: role}, nil
+}
+
+func (s *Service) InviteUser(ctx context.Context, version string, token string, appSecret string, options InviteUserOptions) (*MutationResult, error) {
+	grant, err := PlanInvite(options)
+	if err != nil {
+		return nil, err
+	}
+	businessID := strings.TrimSpace(options.BusinessID)
+	return s.mutate(ctx, version, token, appSecret, "invite_user", businessID, businessID+"/business_users", map[string]string{
+		"email": grant.Email,
+		"role":  grant.Role,
+	}, grant)
+}
+
+// PlanAssignment resolves the task set for an asset assignment. Explicit tasks win
+// over the role preset; both are checked against the tasks the asset type accepts.
+func PlanAssignment(options AssignAssetOptions) (Grant, error) {
+	if _, err := normalizeGraphID("business id", options.BusinessID); err != nil {
+		return Grant{}, err
+	}
+	assetType := strings.ToLower(strings.TrimSpace(options.AssetType))
+	allowed, ok := assetTasks[assetType]
+	if !ok {
+		return Grant{}, fmt.Errorf("unsupported asset type %q: expected ad-account|page|pixel", options.AssetType)
+	}
+	assetID, err := normalizeGraphID("asset id", options.AssetID)
+	if err != nil {
+		return Grant{}, err
+	}
+	if assetType == AssetTypeAdAccount && !strings.HasPrefix(assetID, "act_") {
+		assetID = "act_" + assetID
+	}
+	userID, err := normalizeGraphID("user id", options.UserID)
+	if err != nil {
+		return Grant{}, err
+	}
+
+	role := strings.ToLower(strings.TrimSpace(options.Role))
+	tasks := make([]string, 0, len(options.Tasks))
+	for _, task := range options.Tasks {
+		normalized := strings.ToUpper(strings.TrimSpace(task))
+		if normalized == "" {
+			continue
+		}
+		if !contains(allowed, normalized) {
+			return Grant{}, fmt.Errorf("task %q is not valid for %s assets (allowed: %s)", task, assetType, strings.Join(allowed, ", "))
+		}
+		if !contains(tasks, normalized) {
+			tasks = append(tasks, normalized)
+		}
+	}
+	switch {
+	case len(tasks) > 0 && role != "":
+		return Grant{}, errors.New("use either a role or explicit tasks, not both")
+	case len(tasks) > 0:
+		role = "custom"
+	case role == "":
+		return Grant{}, errors.New("a role (admin|advertiser|analyst) or explicit tasks are required")
+	default:
+		presets, ok := roleTasks[role]
+		if !ok {
+			return Grant{}, fmt.Errorf("unsupported role %q: expected admin|advertiser|analyst", options.Role)
+		}
+		tasks = append(tasks, presets[assetType]...)
+	}
+	return Grant{
+		AssetType: assetType,
+		AssetID:   assetID,
+		UserID:    userID,
+		Role:      role,
+		Tasks:     tasks,
+	}, nil
+}
+
+func (s *Service) AssignAsset(ctx context.Context, version string, token string, appSecret string, options AssignAssetOptions) (*MutationResult, error) {
+	grant, err := PlanAssignment(options)
+	if err != nil {
+		return nil, err
+	}
+	encodedTasks, err := json.Marshal(grant.Tasks)
+	if err != nil {
+		return nil, fmt.Errorf("encode assignment tasks: %w", err)
+	}
+	businessID := strings.TrimSpace(options.BusinessID)
+	return s.mutate(ctx, version, token, appSecret, "assign_asset", businessID, grant.AssetID+"/assigned_users", map[string]string{
+		"user":     grant.UserID,
+		"tasks":    string(encodedTasks),
+		"business": businessID,
+	}, grant)
+}
+
+// Audit lists business users and system users, then reads assigned_users for every
+// owned ad account, page, and pixel. Assignments holding MANAGE or EDIT are flagged
+// as privileged.
+func (s *Service) Audit(ctx context.Context, version string, token string, appSecret string, options AuditOptions) (*AuditResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("business service client is required")
+	}
+	businessID, err := normalizeGraphID("business id", options.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AuditResult{
+		BusinessID:  businessID,
+		AssetCounts: map[string]int{},
+		Assignments: []Assignment{},
+	}
+	userQuery := map[string]string{"fields": "id,name,role"}
+	if result.Users, _, err = s.list(ctx, version, token, appSecret, businessID+"/business_users", userQuery, 0, true); err != nil {
+		return nil, err
+	}
+	if result.SystemUsers, _, err = s.list(ctx, version, token, appSecret, businessID+"/system_users", userQuery, 0, true); err != nil {
+		return nil, err
+	}
+
+	assetEdges := []struct {
+		assetType string
+		edge      string
+	}{
+		{AssetTypeAdAccount, "owned_ad_accounts"},
+		{AssetTypePage, "owned_pages"},
+		{AssetTypePixel, "adspixels"},
+	}
+	for _, assetEdge := range assetEdges {
+		assets, _, err := s.list(ctx, version, token, appSecret, businessID+"/"+assetEdge.edge, map[string]string{"fields": "id,name"}, 0, true)
+		if err != nil {
+			return nil, err
+		}
+		result.AssetCounts[assetEdge.assetType] = len(assets)
+		for _, asset := range assets {
+			assetID := strings.TrimSpace(stringValue(asset["id"]))
+			if assetID == "" {
+				return nil, fmt.Errorf("%s listing returned an asset without id", assetEdge.edge)
+			}
+			users, _, err := s.list(ctx, version, token, appSecret, assetID+"/assigned_users", map[string]string{
+				"business": businessID,
+				"fields":   strings.Join(assignedUserFields, ","),
+			}, 0, true)
+			if err != nil {
+				return nil, err
+			}
+			for _, user := range users {
+				assignment := Assignment{
+					AssetType: assetEdge.assetType,
+					AssetID:   assetID,
+					AssetName: stringValue(asset["name"]),
+					UserID:    stringValue(user["id"]),
+					UserName:  stringValue(user["name"]),
+					UserType:  stringValue(user["user_type"]),
+					Tasks:     []string{},
+				}
+				rawTasks, _ := user["tasks"].([]any)
+				for _, rawTask := range rawTasks {
+					task := strings.ToUpper(fmt.Sprint(rawTask))
+					assignment.Tasks = append(assignment.Tasks, task)
+					if _, ok := privilegedTasks[task]; ok {
+						assignment.Privileged = true
+					}
+				}
+				sort.Strings(assignment.Tasks)
+				if assignment.Privileged {
+					result.PrivilegedCount++
+				}
+				result.Assignments = append(result.Assignments, assignment)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) list(ctx context.Context, version string, token string, appSecret string, path string, query map[string]string, limit int, followNext bool) ([]map[string]any, *graph.PaginationResult, error) {
+	items := make([]map[string]any, 0)
+	pagination, err := s.Client.FetchWithPagination(ctx, graph.Request{
+		Method:      "GET",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Query:       query,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, graph.PaginationOptions{
+		FollowNext: followNext,
+		Limit:      limit,
+	}, func(item map[string]any) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, pagination, nil
+}
+
+func (s *Service) mutate(ctx context.Context, version string, token string, appSecret string, operation string, businessID string, path string, form map[string]string, grant Grant) (*MutationResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("business service client is required")
+	}
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:      "POST",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Form:        form,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if success, ok := response.Body["success"].(bool); ok && !success {
+		return nil, fmt.Errorf("business %s response was not successful", strings.ReplaceAll(operation, "_", " "))
+	}
+	return &MutationResult{
+		Operation:   operation,
+		BusinessID:  businessID,
+		RequestPath: path,
+		Grant:       grant,
+		Response:    response.Body,
+	}, nil
+}
+
+func normalizeGraphID(label string, value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "", fmt.Errorf("%s is required", label)
+	}
+	if strings.Contains(trimmed, "/") {
+		return "", fmt.Errorf("invalid %s %q: expected single graph id token", label, value)
+	}
+	return trimmed, nil
+}
+
+func stringValue(value any) string {
+	typed, _ := value.(string)
+	return typed
+}
+
+func contains(values []string, candidate string) bool {
+	for _, value := range values {
+		if value == candidate {
+			return true
+		}
+	}
+	return false
+}
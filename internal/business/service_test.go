@@ -0,0 +1,123 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestPlanAssignmentResolvesRolePresets(t *testing.T) {
+	t.Parallel()
+
+	grant, err := PlanAssignment(AssignAssetOptions{
+		BusinessID: "biz_1",
+		AssetType:  "page",
+		AssetID:    "page_1",
+		UserID:     "user_1",
+		Role:       "analyst",
+	})
+	if err != nil {
+		t.Fatalf("plan assignment: %v", err)
+	}
+	if strings.Join(grant.Tasks, ",") != "ANALYZE" || grant.AssetID != "page_1" {
+		t.Fatalf("unexpected grant %#v", grant)
+	}
+}
+
+func TestPlanAssignmentRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		options AssignAssetOptions
+		want    string
+	}{
+		{
+			name:    "unknown asset type",
+			options: AssignAssetOptions{BusinessID: "biz_1", AssetType: "catalog", AssetID: "1", UserID: "u", Role: RoleAdmin},
+			want:    "unsupported asset type",
+		},
+		{
+			name:    "task not valid for asset",
+			options: AssignAssetOptions{BusinessID: "biz_1", AssetType: AssetTypePixel, AssetID: "1", UserID: "u", Tasks: []string{"ADVERTISE"}},
+			want:    `task "ADVERTISE" is not valid for pixel assets`,
+		},
+		{
+			name:    "role and tasks",
+			options: AssignAssetOptions{BusinessID: "biz_1", AssetType: AssetTypePage, AssetID: "1", UserID: "u", Role: RoleAdmin, Tasks: []string{"ANALYZE"}},
+			want:    "either a role or explicit tasks",
+		},
+		{
+			name:    "missing role",
+			options: AssignAssetOptions{BusinessID: "biz_1", AssetType: AssetTypePage, AssetID: "1", UserID: "u"},
+			want:    "a role (admin|advertiser|analyst) or explicit tasks are required",
+		},
+		{
+			name:    "missing business",
+			options: AssignAssetOptions{AssetType: AssetTypePage, AssetID: "1", UserID: "u", Role: RoleAdmin},
+			want:    "business id is required",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := PlanAssignment(tc.options); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestAuditCollectsAssignmentsAndFlagsPrivilegedTasks(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data []map[string]any
+		switch r.URL.Path {
+		case "/v25.0/biz_1/business_users":
+			data = []map[string]any{{"id": "user_1", "name": "Jane", "role": "ADMIN"}}
+		case "/v25.0/biz_1/system_users":
+			data = []map[string]any{{"id": "sys_1", "name": "Deployer", "role": "EMPLOYEE"}}
+		case "/v25.0/biz_1/owned_ad_accounts":
+			data = []map[string]any{{"id": "act_1", "name": "Main"}}
+		case "/v25.0/biz_1/owned_pages", "/v25.0/biz_1/adspixels":
+			data = []map[string]any{}
+		case "/v25.0/act_1/assigned_users":
+			if r.URL.Query().Get("business") != "biz_1" {
+				t.Fatalf("expected business filter, got %q", r.URL.RawQuery)
+			}
+			data = []map[string]any{
+				{"id": "user_1", "name": "Jane", "user_type": "BUSINESS_USER", "tasks": []string{"MANAGE", "ANALYZE"}},
+				{"id": "sys_1", "name": "Deployer", "user_type": "SYSTEM_USER", "tasks": []string{"ANALYZE"}},
+			}
+		default:
+			t.Fatalf("unexpected request path %q", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	defer server.Close()
+
+	service := New(graph.NewClient(server.Client(), server.URL))
+	result, err := service.Audit(context.Background(), "v25.0", "token", "", AuditOptions{BusinessID: "biz_1"})
+	if err != nil {
+		t.Fatalf("audit: %v", err)
+	}
+	if len(result.Users) != 1 || len(result.SystemUsers) != 1 {
+		t.Fatalf("unexpected users %#v %#v", result.Users, result.SystemUsers)
+	}
+	if result.AssetCounts[AssetTypeAdAccount] != 1 || result.AssetCounts[AssetTypePage] != 0 {
+		t.Fatalf("unexpected asset counts %#v", result.AssetCounts)
+	}
+	if len(result.Assignments) != 2 || result.PrivilegedCount != 1 {
+		t.Fatalf("unexpected assignments %#v", result.Assignments)
+	}
+	if !result.Assignments[0].Privileged || result.Assignments[1].Privileged {
+		t.Fatalf("unexpected privileged flags %#v", result.Assignments)
+	}
+}
@@ -0,0 +1,571 @@
+package capi
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/bilalbayram/metacli/internal/schema"
+)
+
+const (
+	SchemaDomain = "capi"
+
+	serverEventEntity = "server_event"
+	userDataEntity    = "user_data"
+
+	// Meta rejects events whose event_time is more than seven days in the past.
+	MaxEventAge = 7 * 24 * time.Hour
+	// maxEventClockSkew tolerates small clock differences for events stamped "now".
+	maxEventClockSkew = time.Minute
+)
+
+var (
+	actionSources = []string{"app", "business_messaging", "chat", "email", "other", "phone_call", "physical_store", "system_generated", "website"}
+
+	// hashedUserDataFields are normalized and SHA-256 hashed locally before sending.
+	hashedUserDataFields = map[string]struct{}{
+		"em": {}, "ph": {}, "fn": {}, "ln": {}, "ge": {}, "db": {},
+		"ct": {}, "st": {}, "zp": {}, "country": {}, "external_id": {},
+	}
+	customerInfoFields = []string{"em", "ph", "external_id", "fbc", "fbp", "client_ip_address", "madid", "lead_id", "subscription_id", "fb_login_id", "anon_id", "page_scoped_user_id", "ctwa_clid", "ig_sid"}
+
+	csvIntegerColumns = map[string]struct{}{"event_time": {}, "custom_data.num_items": {}}
+	csvNumberColumns  = map[string]struct{}{"custom_data.value": {}, "custom_data.predicted_ltv": {}}
+	csvListColumns    = map[string]struct{}{"custom_data.content_ids": {}}
+
+	sha256Hex      = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	birthDateValue = regexp.MustCompile(`^[0-9]{8}$`)
+)
+
+type EventViolation struct {
+	Index     int    `json:"index"`
+	EventName string `json:"event_name,omitempty"`
+	EventID   string `json:"event_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+type PrepareOptions struct {
+	// DedupFields derive a deterministic event_id for events that do not carry one.
+	DedupFields []string
+	Now         time.Time
+}
+
+type PrepareStats struct {
+	EventsRead        int `json:"events_read"`
+	EventsPrepared    int `json:"events_prepared"`
+	DuplicatesDropped int `json:"duplicates_dropped"`
+	DerivedEventIDs   int `json:"derived_event_ids"`
+	MissingEventIDs   int `json:"missing_event_ids"`
+	HashedValues      int `json:"hashed_values"`
+}
+
+type PreparedEvents struct {
+	Events     []map[string]any `json:"-"`
+	Stats      PrepareStats     `json:"stats"`
+	Violations []EventViolation `json:"violations,omitempty"`
+}
+
+// LoadEventsFile reads server events from a JSON array, a {"data": [...]} object,
+// or a CSV file whose header uses dotted paths (for example user_data.em).
+func LoadEventsFile(path string) ([]map[string]any, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, errors.New("events file path is required")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read events file %s: %w", path, err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return decodeJSONEvents(data, path)
+	case ".csv":
+		return decodeCSVEvents(data, path)
+	default:
+		return nil, fmt.Errorf("unsupported events file format for %s: expected .json or .csv", path)
+	}
+}
+
+// PrepareEvents validates events against the capi schema pack, hashes customer
+// information, and drops in-file duplicates sharing event_name and event_id.
+// Violations are collected for every event instead of stopping at the first one.
+func PrepareEvents(pack *schema.Pack, events []map[string]any, options PrepareOptions) (*PreparedEvents, error) {
+	if pack == nil {
+		return nil, errors.New("capi schema pack is required")
+	}
+	if len(pack.Entities[serverEventEntity]) == 0 || len(pack.Entities[userDataEntity]) == 0 {
+		return nil, fmt.Errorf("schema pack %s/%s does not define %s and %s entities", pack.Domain, pack.Version, serverEventEntity, userDataEntity)
+	}
+	if len(events) == 0 {
+		return nil, errors.New("events cannot be empty")
+	}
+	now := options.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	dedupFields := make([]string, 0, len(options.DedupFields))
+	for _, field := range options.DedupFields {
+		trimmed := strings.TrimSpace(field)
+		if trimmed == "" {
+			return nil, errors.New("dedup fields contain blank entries")
+		}
+		dedupFields = append(dedupFields, trimmed)
+	}
+
+	eventFields := toSet(pack.Entities[serverEventEntity])
+	userDataFields := toSet(pack.Entities[userDataEntity])
+	required := pack.EndpointRequiredParams[serverEventEntity]
+
+	prepared := &PreparedEvents{
+		Events: make([]map[string]any, 0, len(events)),
+		Stats:  PrepareStats{EventsRead: len(events)},
+	}
+	seen := map[string]struct{}{}
+	for index, raw := range events {
+		event := cloneMap(raw)
+		report := func(format string, args ...any) {
+			prepared.Violations = append(prepared.Violations, EventViolation{
+				Index:     index,
+				EventName: stringValue(event["event_name"]),
+				EventID:   stringValue(event["event_id"]),
+				Message:   fmt.Sprintf(format, args...),
+			})
+		}
+
+		for _, field := range sortedKeys(event) {
+			if _, ok := eventFields[field]; !ok {
+				report("unknown event field %q", field)
+			}
+		}
+		for _, field := range required {
+			if isBlank(event[field]) {
+				report("missing required field %q", field)
+			}
+		}
+
+		if !isBlank(event["event_time"]) {
+			eventTime, ok := integerValue(event["event_time"])
+			switch {
+			case !ok:
+				report("event_time must be a unix timestamp in seconds")
+			case time.Unix(eventTime, 0).Before(now.Add(-MaxEventAge)):
+				report("event_time %d is older than 7 days", eventTime)
+			case time.Unix(eventTime, 0).After(now.Add(maxEventClockSkew)):
+				report("event_time %d is in the future", eventTime)
+			default:
+				event["event_time"] = eventTime
+			}
+		}
+		actionSource := strings.ToLower(strings.TrimSpace(stringValue(event["action_source"])))
+		if actionSource != "" {
+			if !contains(actionSources, actionSource) {
+				report("action_source must be one of [%s], got %q", strings.Join(actionSources, ", "), actionSource)
+			}
+			event["action_source"] = actionSource
+		}
+
+		userData, hasUserData := event["user_data"].(map[string]any)
+		if !isBlank(event["user_data"]) && !hasUserData {
+			report("user_data must be an object")
+		}
+		if hasUserData {
+			for _, field := range sortedKeys(userData) {
+				if _, ok := userDataFields[field]; !ok {
+					report("unknown user_data field %q", field)
+				}
+			}
+			hashed, count, problems := hashUserData(userData)
+			for _, problem := range problems {
+				report("%s", problem)
+			}
+			prepared.Stats.HashedValues += count
+			event["user_data"] = hashed
+			if !hasAnyField(hashed, customerInfoFields) {
+				report("user_data must include at least one customer information parameter (%s)", strings.Join(customerInfoFields, ", "))
+			}
+			if actionSource == "website" {
+				if isBlank(event["event_source_url"]) {
+					report("website events require event_source_url")
+				}
+				if isBlank(hashed["client_user_agent"]) {
+					report("website events require user_data.client_user_agent")
+				}
+			}
+		}
+		if sourceURL := stringValue(event["event_source_url"]); sourceURL != "" {
+			parsed, err := url.Parse(sourceURL)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				report("event_source_url must be an absolute http(s) url")
+			}
+		}
+		if customData, ok := event["custom_data"].(map[string]any); ok {
+			if currency := stringValue(customData["currency"]); currency != "" && len(currency) != 3 {
+				report("custom_data.currency must be a 3-letter ISO code, got %q", currency)
+			}
+			if stringValue(event["event_name"]) == "Purchase" && (isBlank(customData["value"]) || isBlank(customData["currency"])) {
+				report("Purchase events require custom_data.value and custom_data.currency")
+			}
+		} else if !isBlank(event["custom_data"]) {
+			report("custom_data must be an object")
+		} else if stringValue(event["event_name"]) == "Purchase" {
+			report("Purchase events require custom_data.value and custom_data.currency")
+		}
+
+		if isBlank(event["event_id"]) && len(dedupFields) > 0 {
+			eventID, err := deriveEventID(event, dedupFields)
+			if err != nil {
+				report("%s", err.Error())
+			} else {
+				event["event_id"] = eventID
+				prepared.Stats.DerivedEventIDs++
+			}
+		}
+		eventID := stringValue(event["event_id"])
+		if eventID == "" {
+			prepared.Stats.MissingEventIDs++
+		} else {
+			key := stringValue(event["event_name"]) + "\x00" + eventID
+			if _, duplicate := seen[key]; duplicate {
+				prepared.Stats.DuplicatesDropped++
+				continue
+			}
+			seen[key] = struct{}{}
+		}
+		prepared.Events = append(prepared.Events, event)
+	}
+	prepared.Stats.EventsPrepared = len(prepared.Events)
+	return prepared, nil
+}
+
+func hashUserData(userData map[string]any) (map[string]any, int, []string) {
+	hashed := make(map[string]any, len(userData))
+	count := 0
+	problems := make([]string, 0)
+	for _, field := range sortedKeys(userData) {
+		value := userData[field]
+		if _, ok := hashedUserDataFields[field]; !ok {
+			hashed[field] = value
+			continue
+		}
+		values, isList := value.([]any)
+		if !isList {
+			values = []any{value}
+		}
+		out := make([]any, 0, len(values))
+		for _, entry := range values {
+			text, ok := entry.(string)
+			if !ok {
+				text = fmt.Sprint(entry)
+			}
+			trimmed := strings.TrimSpace(text)
+			if trimmed == "" {
+				continue
+			}
+			if sha256Hex.MatchString(strings.ToLower(trimmed)) {
+				out = append(out, strings.ToLower(trimmed))
+				continue
+			}
+			normalized, valid := normalizeUserDataValue(field, trimmed)
+			if !valid {
+				problems = append(problems, fmt.Sprintf("user_data.%s value is not valid after normalization", field))
+				continue
+			}
+			sum := sha256.Sum256([]byte(normalized))
+			out = append(out, hex.EncodeToString(sum[:]))
+			count++
+		}
+		switch {
+		case len(out) == 0:
+		case isList:
+			hashed[field] = out
+		default:
+			hashed[field] = out[0]
+		}
+	}
+	return hashed, count, problems
+}
+
+func normalizeUserDataValue(field string, raw string) (string, bool) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	switch field {
+	case "em":
+		return value, strings.Count(value, "@") == 1 && !strings.HasPrefix(value, "@") && !strings.HasSuffix(value, "@") && !strings.ContainsAny(value, " \t")
+	case "ph":
+		digits := strings.TrimLeft(keepRunes(value, unicode.IsDigit), "0")
+		return digits, len(digits) >= 7 && len(digits) <= 15
+	case "fn", "ln", "ct":
+		letters := keepRunes(value, unicode.IsLetter)
+		return letters, letters != ""
+	case "st", "country":
+		letters := keepRunes(value, unicode.IsLetter)
+		return letters, len(letters) == 2
+	case "zp":
+		compact := strings.ReplaceAll(value, " ", "")
+		if len(compact) == 10 && compact[5] == '-' {
+			compact = compact[:5]
+		}
+		return compact, compact != ""
+	case "ge":
+		switch value {
+		case "m", "male":
+			return "m", true
+		case "f", "female":
+			return "f", true
+		}
+		return "", false
+	case "db":
+		digits := keepRunes(value, unicode.IsDigit)
+		return digits, birthDateValue.MatchString(digits)
+	case "external_id":
+		return value, true
+	default:
+		return "", false
+	}
+}
+
+func deriveEventID(event map[string]any, fields []string) (string, error) {
+	parts := []string{stringValue(event["event_name"])}
+	for _, field := range fields {
+		value, ok := lookupPath(event, field)
+		if !ok || isBlank(value) {
+			return "", fmt.Errorf("dedup field %q is missing", field)
+		}
+		parts = append(parts, fmt.Sprint(value))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])[:32], nil
+}
+
+func decodeJSONEvents(data []byte, path string) ([]map[string]any, error) {
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("decode events file %s: %w", path, err)
+	}
+	if object, ok := decoded.(map[string]any); ok {
+		decoded, ok = object["data"]
+		if !ok {
+			return nil, fmt.Errorf("events file %s object must include a \"data\" array", path)
+		}
+	}
+	entries, ok := decoded.([]any)
+	if !ok {
+		return nil, fmt.Errorf("events file %s must contain a JSON array of events", path)
+	}
+	events := make([]map[string]any, 0, len(entries))
+	for index, entry := range entries {
+		event, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("events file %s entry %d must be an object", path, index)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func decodeCSVEvents(data []byte, path string) ([]map[string]any, error) {
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read events file %s header: %w", path, err)
+	}
+	for index, column := range header {
+		header[index] = strings.TrimSpace(column)
+		if header[index] == "" {
+			return nil, fmt.Errorf("events file %s has a blank header column at position %d", path, index+1)
+		}
+	}
+
+	events := make([]map[string]any, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read events file %s: %w", path, err)
+		}
+		event := map[string]any{}
+		for index, column := range header {
+			cell := strings.TrimSpace(record[index])
+			if cell == "" {
+				continue
+			}
+			value, err := csvCellValue(column, cell)
+			if err != nil {
+				return nil, fmt.Errorf("events file %s line %d: %w", path, line, err)
+			}
+			setPath(event, column, value)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func csvCellValue(column string, cell string) (any, error) {
+	if _, ok := csvIntegerColumns[column]; ok {
+		parsed, err := strconv.ParseInt(cell, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("column %s must be an integer, got %q", column, cell)
+		}
+		return parsed, nil
+	}
+	if _, ok := csvNumberColumns[column]; ok {
+		parsed, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return nil, fmt.Errorf("column %s must be a number, got %q", column, cell)
+		}
+		return parsed, nil
+	}
+	if _, ok := csvListColumns[column]; ok {
+		parts := strings.Split(cell, "|")
+		values := make([]any, 0, len(parts))
+		for _, part := range parts {
+			if trimmed := strings.TrimSpace(part); trimmed != "" {
+				values = append(values, trimmed)
+			}
+		}
+		return values, nil
+	}
+	return cell, nil
+}
+
+func setPath(target map[string]any, path string, value any) {
+	parts := strings.Split(path, ".")
+	current := target
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
+
+func lookupPath(source map[string]any, path string) (any, bool) {
+	parts := strings.Split(path, ".")
+	var current any = source
+	for _, part := range parts {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = object[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func integerValue(value any) (int64, bool) {
+	switch typed := value.(type) {
+	case int64:
+		return typed, true
+	case int:
+		return int64(typed), true
+	case float64:
+		if typed != float64(int64(typed)) {
+			return 0, false
+		}
+		return int64(typed), true
+	case json.Number:
+		parsed, err := typed.Int64()
+		return parsed, err == nil
+	case string:
+		parsed, err := strconv.ParseInt(strings.TrimSpace(typed), 10, 64)
+		return parsed, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func stringValue(value any) string {
+	typed, _ := value.(string)
+	return strings.TrimSpace(typed)
+}
+
+func isBlank(value any) bool {
+	switch typed := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(typed) == ""
+	case map[string]any:
+		return len(typed) == 0
+	case []any:
+		return len(typed) == 0
+	default:
+		return false
+	}
+}
+
+func hasAnyField(values map[string]any, fields []string) bool {
+	for _, field := range fields {
+		if !isBlank(values[field]) {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneMap(source map[string]any) map[string]any {
+	out := make(map[string]any, len(source))
+	for key, value := range source {
+		if nested, ok := value.(map[string]any); ok {
+			value = cloneMap(nested)
+		}
+		out[key] = value
+	}
+	return out
+}
+
+func sortedKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func toSet(values []string) map[string]struct{} {
+	out := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		out[value] = struct{}{}
+	}
+	return out
+}
+
+func contains(values []string, candidate string) bool {
+	for _, value := range values {
+		if value == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+func keepRunes(value string, keep func(rune) bool) string {
+	var builder strings.Builder
+	for _, r := range value {
+		if keep(r) {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
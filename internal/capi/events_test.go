@@ -0,0 +1,176 @@
+package capi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/schema"
+)
+
+func TestLoadEventsFileDecodesCSVDottedColumns(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "events.csv")
+	content := "event_name,event_time,action_source,user_data.em,custom_data.value,custom_data.currency,custom_data.content_ids\n" +
+		"Purchase,1760000000,website,Jane@Example.com,19.5,USD,sku_1|sku_2\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write events file: %v", err)
+	}
+
+	events, err := LoadEventsFile(path)
+	if err != nil {
+		t.Fatalf("load events: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	event := events[0]
+	if event["event_time"] != int64(1760000000) {
+		t.Fatalf("unexpected event_time %#v", event["event_time"])
+	}
+	userData := event["user_data"].(map[string]any)
+	if userData["em"] != "Jane@Example.com" {
+		t.Fatalf("unexpected user_data %#v", userData)
+	}
+	customData := event["custom_data"].(map[string]any)
+	if customData["value"] != 19.5 {
+		t.Fatalf("unexpected value %#v", customData["value"])
+	}
+	if ids, ok := customData["content_ids"].([]any); !ok || len(ids) != 2 || ids[1] != "sku_2" {
+		t.Fatalf("unexpected content_ids %#v", customData["content_ids"])
+	}
+}
+
+func TestLoadEventsFileRejectsUnsupportedExtension(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "events.txt")
+	if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+		t.Fatalf("write events file: %v", err)
+	}
+	_, err := LoadEventsFile(path)
+	if err == nil || !strings.Contains(err.Error(), "expected .json or .csv") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPrepareEventsHashesUserDataAndDropsDuplicates(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1760000000, 0)
+	events := []map[string]any{
+		{
+			"event_name":       "Purchase",
+			"event_time":       float64(1759990000),
+			"action_source":    "Website",
+			"event_source_url": "https://shop.example.com/checkout",
+			"user_data": map[string]any{
+				"em":                " Jane@Example.com ",
+				"ph":                "+1 (555) 010-0200",
+				"client_user_agent": "Mozilla/5.0",
+			},
+			"custom_data": map[string]any{"value": 19.5, "currency": "USD", "order_id": "o-1"},
+		},
+		{
+			"event_name":    "Purchase",
+			"event_time":    float64(1759990000),
+			"action_source": "system_generated",
+			"user_data":     map[string]any{"external_id": "crm-1"},
+			"custom_data":   map[string]any{"value": 19.5, "currency": "USD", "order_id": "o-1"},
+		},
+	}
+
+	prepared, err := PrepareEvents(testPack(t), events, PrepareOptions{
+		DedupFields: []string{"custom_data.order_id"},
+		Now:         now,
+	})
+	if err != nil {
+		t.Fatalf("prepare events: %v", err)
+	}
+	if len(prepared.Violations) != 0 {
+		t.Fatalf("unexpected violations %#v", prepared.Violations)
+	}
+	if prepared.Stats.EventsPrepared != 1 || prepared.Stats.DuplicatesDropped != 1 || prepared.Stats.DerivedEventIDs != 2 {
+		t.Fatalf("unexpected stats %+v", prepared.Stats)
+	}
+
+	event := prepared.Events[0]
+	if event["action_source"] != "website" || event["event_time"] != int64(1759990000) {
+		t.Fatalf("unexpected normalized event %#v", event)
+	}
+	userData := event["user_data"].(map[string]any)
+	if userData["em"] != sha256HexOf("jane@example.com") {
+		t.Fatalf("unexpected em hash %#v", userData["em"])
+	}
+	if userData["ph"] != sha256HexOf("15550100200") {
+		t.Fatalf("unexpected ph hash %#v", userData["ph"])
+	}
+	if userData["client_user_agent"] != "Mozilla/5.0" {
+		t.Fatalf("client_user_agent must not be hashed: %#v", userData["client_user_agent"])
+	}
+	if events[0]["user_data"].(map[string]any)["em"] != " Jane@Example.com " {
+		t.Fatal("input events must not be mutated")
+	}
+}
+
+func TestPrepareEventsReportsSchemaAndSemanticViolations(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1760000000, 0)
+	prepared, err := PrepareEvents(testPack(t), []map[string]any{
+		{
+			"event_name":    "Purchase",
+			"event_time":    float64(now.Add(-8 * 24 * time.Hour).Unix()),
+			"action_source": "website",
+			"user_data":     map[string]any{"em": "not-an-email", "favourite_colour": "red"},
+			"colour":        "red",
+		},
+		{
+			"event_name": "Lead",
+			"user_data":  map[string]any{"em": "lead@example.com"},
+		},
+	}, PrepareOptions{Now: now})
+	if err != nil {
+		t.Fatalf("prepare events: %v", err)
+	}
+
+	messages := make([]string, 0, len(prepared.Violations))
+	for _, violation := range prepared.Violations {
+		messages = append(messages, violation.Message)
+	}
+	joined := strings.Join(messages, "\n")
+	for _, want := range []string{
+		`unknown event field "colour"`,
+		`unknown user_data field "favourite_colour"`,
+		"older than 7 days",
+		"user_data.em value is not valid",
+		"website events require event_source_url",
+		"website events require user_data.client_user_agent",
+		"Purchase events require custom_data.value and custom_data.currency",
+		`missing required field "event_time"`,
+		`missing required field "action_source"`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected violation %q in:\n%s", want, joined)
+		}
+	}
+}
+
+func testPack(t *testing.T) *schema.Pack {
+	t.Helper()
+	pack, err := schema.NewProvider(filepath.Join("..", "..", "schema-packs"), "", "").GetPack(SchemaDomain, "v25.0")
+	if err != nil {
+		t.Fatalf("load capi schema pack: %v", err)
+	}
+	return pack
+}
+
+func sha256HexOf(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,226 @@
+package capi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	// MaxEventsPerRequest is the Conversions API limit for events in one request.
+	MaxEventsPerRequest = 1000
+
+	StatsAggregationEvent = "event"
+)
+
+var statsAggregations = []string{
+	"browser_type", "custom_data_field", "device_os", "device_type", "event", "event_detection_method",
+	"event_processing_results", "event_source", "event_total_counts", "event_value_count", "had_pii",
+	"host", "match_keys", "pixel_fire", "url", "url_by_rule",
+}
+
+type SendInput struct {
+	PixelID       string
+	Events        []map[string]any
+	TestEventCode string
+	BatchSize     int
+}
+
+type SendBatch struct {
+	BatchSeq       int      `json:"batch_seq"`
+	Events         int      `json:"events"`
+	EventsReceived int      `json:"events_received"`
+	Messages       []string `json:"messages,omitempty"`
+	FBTraceID      string   `json:"fbtrace_id,omitempty"`
+}
+
+type SendResult struct {
+	Operation      string      `json:"operation"`
+	PixelID        string      `json:"pixel_id"`
+	RequestPath    string      `json:"request_path"`
+	TestEventCode  string      `json:"test_event_code,omitempty"`
+	EventsSent     int         `json:"events_sent"`
+	EventsReceived int         `json:"events_received"`
+	Batches        []SendBatch `json:"batches"`
+}
+
+type StatsInput struct {
+	PixelID     string
+	Aggregation string
+	Start       time.Time
+	End         time.Time
+}
+
+type StatsResult struct {
+	Operation   string           `json:"operation"`
+	PixelID     string           `json:"pixel_id"`
+	Aggregation string           `json:"aggregation"`
+	RequestPath string           `json:"request_path"`
+	Buckets     []map[string]any `json:"buckets"`
+}
+
+type Service struct {
+	Client *graph.Client
+}
+
+func New(client *graph.Client) *Service {
+	if client == nil {
+		client = graph.NewClient(nil, "")
+	}
+	return &Service{Client: client}
+}
+
+// Send posts prepared events to the pixel's /events edge in batches of at most
+// MaxEventsPerRequest. A test event code routes events to Events Manager's Test
+// Events tool instead of production reporting.
+func (s *Service) Send(ctx context.Context, version string, token string, appSecret string, input SendInput) (*SendResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("capi service client is required")
+	}
+	pixelID, err := normalizePixelID(input.PixelID)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.Events) == 0 {
+		return nil, errors.New("events cannot be empty")
+	}
+	batchSize := input.BatchSize
+	if batchSize == 0 {
+		batchSize = MaxEventsPerRequest
+	}
+	if batchSize < 0 || batchSize > MaxEventsPerRequest {
+		return nil, fmt.Errorf("batch size must be between 1 and %d, got %d", MaxEventsPerRequest, input.BatchSize)
+	}
+
+	path := pixelID + "/events"
+	result := &SendResult{
+		Operation:     "send_events",
+		PixelID:       pixelID,
+		RequestPath:   path,
+		TestEventCode: strings.TrimSpace(input.TestEventCode),
+		Batches:       make([]SendBatch, 0, (len(input.Events)+batchSize-1)/batchSize),
+	}
+	for start := 0; start < len(input.Events); start += batchSize {
+		end := start + batchSize
+		if end > len(input.Events) {
+			end = len(input.Events)
+		}
+		encoded, err := json.Marshal(input.Events[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("encode events batch %d: %w", len(result.Batches)+1, err)
+		}
+		form := map[string]string{"data": string(encoded)}
+		if result.TestEventCode != "" {
+			form["test_event_code"] = result.TestEventCode
+		}
+
+		response, err := s.Client.Do(ctx, graph.Request{
+			Method:      "POST",
+			Path:        path,
+			Version:     strings.TrimSpace(version),
+			Form:        form,
+			AccessToken: token,
+			AppSecret:   appSecret,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("send events batch %d: %w", len(result.Batches)+1, err)
+		}
+
+		batch := SendBatch{
+			BatchSeq:       len(result.Batches) + 1,
+			Events:         end - start,
+			EventsReceived: responseInt(response.Body["events_received"]),
+		}
+		batch.FBTraceID, _ = response.Body["fbtrace_id"].(string)
+		if messages, ok := response.Body["messages"].([]any); ok {
+			for _, message := range messages {
+				batch.Messages = append(batch.Messages, fmt.Sprint(message))
+			}
+		}
+		result.Batches = append(result.Batches, batch)
+		result.EventsSent += batch.Events
+		result.EventsReceived += batch.EventsReceived
+	}
+	return result, nil
+}
+
+func (s *Service) Stats(ctx context.Context, version string, token string, appSecret string, input StatsInput) (*StatsResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("capi service client is required")
+	}
+	pixelID, err := normalizePixelID(input.PixelID)
+	if err != nil {
+		return nil, err
+	}
+	aggregation := strings.ToLower(strings.TrimSpace(input.Aggregation))
+	if aggregation == "" {
+		aggregation = StatsAggregationEvent
+	}
+	if !contains(statsAggregations, aggregation) {
+		return nil, fmt.Errorf("unsupported stats aggregation %q (supported: %s)", input.Aggregation, strings.Join(statsAggregations, ", "))
+	}
+	query := map[string]string{"aggregation": aggregation}
+	if !input.Start.IsZero() {
+		query["start_time"] = strconv.FormatInt(input.Start.Unix(), 10)
+	}
+	if !input.End.IsZero() {
+		if !input.Start.IsZero() && !input.End.After(input.Start) {
+			return nil, errors.New("stats end time must be after start time")
+		}
+		query["end_time"] = strconv.FormatInt(input.End.Unix(), 10)
+	}
+
+	path := pixelID + "/stats"
+	buckets := make([]map[string]any, 0)
+	if _, err := s.Client.FetchWithPagination(ctx, graph.Request{
+		Method:      "GET",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Query:       query,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, graph.PaginationOptions{FollowNext: true}, func(item map[string]any) error {
+		buckets = append(buckets, item)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &StatsResult{
+		Operation:   "pixel_stats",
+		PixelID:     pixelID,
+		Aggregation: aggregation,
+		RequestPath: path,
+		Buckets:     buckets,
+	}, nil
+}
+
+func normalizePixelID(value string) (string, error) {
+	pixelID := strings.TrimSpace(value)
+	if pixelID == "" {
+		return "", errors.New("pixel id is required")
+	}
+	if strings.Contains(pixelID, "/") {
+		return "", fmt.Errorf("invalid pixel id %q: expected a single Graph node id", value)
+	}
+	return pixelID, nil
+}
+
+func responseInt(value any) int {
+	switch typed := value.(type) {
+	case float64:
+		return int(typed)
+	case int:
+		return typed
+	case json.Number:
+		parsed, _ := typed.Int64()
+		return int(parsed)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,133 @@
+package capi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestSendBatchesEventsWithTestEventCode(t *testing.T) {
+	t.Parallel()
+
+	batchSizes := make([]int, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+		if r.URL.Path != "/v25.0/px_1/events" {
+			t.Fatalf("unexpected request path %q", r.URL.Path)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("parse form body: %v", err)
+		}
+		if got := form.Get("test_event_code"); got != "TEST123" {
+			t.Fatalf("unexpected test_event_code %q", got)
+		}
+		var events []map[string]any
+		if err := json.Unmarshal([]byte(form.Get("data")), &events); err != nil {
+			t.Fatalf("decode data: %v", err)
+		}
+		batchSizes = append(batchSizes, len(events))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"events_received": len(events),
+			"messages":        []string{},
+			"fbtrace_id":      "trace-1",
+		})
+	}))
+	defer server.Close()
+
+	events := make([]map[string]any, 0, 3)
+	for _, name := range []string{"Lead", "Lead", "Purchase"} {
+		events = append(events, map[string]any{"event_name": name})
+	}
+	service := New(graph.NewClient(server.Client(), server.URL))
+	result, err := service.Send(context.Background(), "v25.0", "token-1", "", SendInput{
+		PixelID:       "px_1",
+		Events:        events,
+		TestEventCode: "TEST123",
+		BatchSize:     2,
+	})
+	if err != nil {
+		t.Fatalf("send events: %v", err)
+	}
+	if len(batchSizes) != 2 || batchSizes[0] != 2 || batchSizes[1] != 1 {
+		t.Fatalf("unexpected batch sizes %v", batchSizes)
+	}
+	if result.EventsSent != 3 || result.EventsReceived != 3 || len(result.Batches) != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if result.Batches[1].FBTraceID != "trace-1" {
+		t.Fatalf("unexpected fbtrace id %q", result.Batches[1].FBTraceID)
+	}
+}
+
+func TestSendRejectsOversizedBatch(t *testing.T) {
+	t.Parallel()
+
+	service := New(graph.NewClient(nil, ""))
+	_, err := service.Send(context.Background(), "v25.0", "token-1", "", SendInput{
+		PixelID:   "px_1",
+		Events:    []map[string]any{{"event_name": "Lead"}},
+		BatchSize: MaxEventsPerRequest + 1,
+	})
+	if err == nil || !strings.Contains(err.Error(), "batch size must be between 1 and 1000") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStatsReadsAggregatedBuckets(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v25.0/px_1/stats" {
+			t.Fatalf("unexpected request path %q", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("aggregation") != "event_source" || query.Get("start_time") != "1760000000" || query.Get("end_time") != "1760086400" {
+			t.Fatalf("unexpected query %v", query)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{
+				{"start_time": "2025-10-09T08:00:00+0000", "data": []map[string]any{{"value": "SERVER", "count": 42}}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	service := New(graph.NewClient(server.Client(), server.URL))
+	result, err := service.Stats(context.Background(), "v25.0", "token-1", "", StatsInput{
+		PixelID:     "px_1",
+		Aggregation: "event_source",
+		Start:       time.Unix(1760000000, 0),
+		End:         time.Unix(1760086400, 0),
+	})
+	if err != nil {
+		t.Fatalf("pixel stats: %v", err)
+	}
+	if len(result.Buckets) != 1 || result.Aggregation != "event_source" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestStatsRejectsUnknownAggregation(t *testing.T) {
+	t.Parallel()
+
+	service := New(graph.NewClient(nil, ""))
+	_, err := service.Stats(context.Background(), "v25.0", "token-1", "", StatsInput{PixelID: "px_1", Aggregation: "weekly"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported stats aggregation") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,345 @@
+package changelog
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+	"github.com/bilalbayram/metacli/internal/transport"
+)
+
+const (
+	DefaultFeedURL      = "https://developers.facebook.com/docs/graph-api/changelog/versions/"
+	DefaultFeedCacheTTL = 6 * time.Hour
+	FeedSchemaVersion   = 1
+	feedDigestPrefix    = "sha256:"
+	maxFeedBodyBytes    = 8 << 20
+)
+
+var (
+	ErrFeedURLRequired       = errors.New("changelog feed url is required")
+	ErrFeedCachePathRequired = errors.New("changelog feed cache path is required")
+	ErrFeedEmpty             = errors.New("changelog feed contains no graph api versions")
+)
+
+var feedVersionPattern = regexp.MustCompile(`\bv([0-9]{1,3})\.([0-9]{1,2})\b`)
+
+type Feed struct {
+	SchemaVersion     int       `json:"schema_version"`
+	SourceURL         string    `json:"source_url"`
+	FetchedAt         time.Time `json:"fetched_at"`
+	LatestVersion     string    `json:"latest_version"`
+	Versions          []string  `json:"versions"`
+	OutOfCycleChanges []string  `json:"out_of_cycle_changes"`
+	Digest            string    `json:"digest"`
+}
+
+type FeedFetcher struct {
+	Client *http.Client
+	URL    string
+}
+
+type FeedResolveOptions struct {
+	CachePath string
+	TTL       time.Duration
+	Now       time.Time
+}
+
+type FeedResolution struct {
+	Feed      Feed   `json:"feed"`
+	CachePath string `json:"cache_path"`
+	FromCache bool   `json:"from_cache"`
+}
+
+type jsonFeedDocument struct {
+	Versions []struct {
+		Version string `json:"version"`
+	} `json:"versions"`
+	OutOfCycleChanges []struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	} `json:"out_of_cycle_changes"`
+}
+
+func NewFeedFetcher(client *http.Client, url string) *FeedFetcher {
+	if client == nil {
+		client = &http.Client{Transport: transport.RefuseOffline(nil)}
+	}
+	url = strings.TrimSpace(url)
+	if url == "" {
+		url = DefaultFeedURL
+	}
+	return &FeedFetcher{Client: client, URL: url}
+}
+
+func (f *FeedFetcher) Fetch(ctx context.Context, now time.Time) (Feed, error) {
+	if f == nil || f.Client == nil {
+		return Feed{}, errors.New("changelog feed fetcher is not initialized")
+	}
+	url := strings.TrimSpace(f.URL)
+	if url == "" {
+		return Feed{}, ErrFeedURLRequired
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Feed{}, fmt.Errorf("build changelog feed request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json, text/html;q=0.9, */*;q=0.1")
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return Feed{}, fmt.Errorf("fetch changelog feed %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBodyBytes))
+	if err != nil {
+		return Feed{}, fmt.Errorf("read changelog feed %s: %w", url, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Feed{}, fmt.Errorf("fetch changelog feed %s: unexpected status %d", url, resp.StatusCode)
+	}
+
+	feed, err := ParseFeed(body, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return Feed{}, fmt.Errorf("parse changelog feed %s: %w", url, err)
+	}
+	feed.SourceURL = url
+	feed.FetchedAt = now.UTC()
+	return feed, nil
+}
+
+func (f *FeedFetcher) Resolve(ctx context.Context, options FeedResolveOptions) (FeedResolution, error) {
+	if f == nil {
+		return FeedResolution{}, errors.New("changelog feed fetcher is not initialized")
+	}
+	cachePath := strings.TrimSpace(options.CachePath)
+	if cachePath == "" {
+		return FeedResolution{}, ErrFeedCachePathRequired
+	}
+	if options.TTL < 0 {
+		return FeedResolution{}, fmt.Errorf("changelog feed cache ttl must be >= 0, got %s", options.TTL)
+	}
+	now := options.Now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	if options.TTL > 0 {
+		cached, err := LoadFeedCache(cachePath)
+		switch {
+		case err == nil:
+			if cached.SourceURL == strings.TrimSpace(f.URL) && now.Sub(cached.FetchedAt) < options.TTL {
+				return FeedResolution{Feed: cached, CachePath: cachePath, FromCache: true}, nil
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			return FeedResolution{}, err
+		}
+	}
+
+	feed, err := f.Fetch(ctx, now)
+	if err != nil {
+		return FeedResolution{}, err
+	}
+	if err := SaveFeedCache(cachePath, feed); err != nil {
+		return FeedResolution{}, err
+	}
+	return FeedResolution{Feed: feed, CachePath: cachePath}, nil
+}
+
+func ParseFeed(body []byte, contentType string) (Feed, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	trimmed := bytes.TrimSpace(body)
+	if mediaType == "application/json" || (mediaType == "" && bytes.HasPrefix(trimmed, []byte("{"))) {
+		return parseJSONFeed(trimmed)
+	}
+	return parseDocumentFeed(body)
+}
+
+func DefaultFeedCachePath(stateDir string) string {
+	return filepath.Join(strings.TrimSpace(stateDir), "changelog-feed.json")
+}
+
+func LoadFeedCache(path string) (Feed, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return Feed{}, ErrFeedCachePathRequired
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Feed{}, fmt.Errorf("read changelog feed cache %s: %w", path, err)
+	}
+
+	var feed Feed
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&feed); err != nil {
+		return Feed{}, fmt.Errorf("decode changelog feed cache %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return Feed{}, fmt.Errorf("decode changelog feed cache %s: multiple JSON values", path)
+		}
+		return Feed{}, fmt.Errorf("decode changelog feed cache %s: %w", path, err)
+	}
+	if feed.SchemaVersion != FeedSchemaVersion {
+		return Feed{}, fmt.Errorf(
+			"unsupported changelog feed cache schema_version=%d in %s (expected %d)",
+			feed.SchemaVersion,
+			path,
+			FeedSchemaVersion,
+		)
+	}
+	return feed, nil
+}
+
+func SaveFeedCache(path string, feed Feed) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return ErrFeedCachePathRequired
+	}
+	feed.SchemaVersion = FeedSchemaVersion
+
+	lock, err := filelock.Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create changelog feed cache directory %s: %w", dir, err)
+	}
+	payload, err := json.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal changelog feed cache: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".changelog-feed-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp changelog feed cache file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp changelog feed cache file: %w", err)
+	}
+	if err := tmpFile.Chmod(0o600); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("chmod temp changelog feed cache file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp changelog feed cache file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("write changelog feed cache %s: %w", path, err)
+	}
+	return nil
+}
+
+func parseJSONFeed(body []byte) (Feed, error) {
+	var document jsonFeedDocument
+	if err := json.Unmarshal(body, &document); err != nil {
+		return Feed{}, fmt.Errorf("decode json feed: %w", err)
+	}
+	versions := make([]string, 0, len(document.Versions))
+	for _, entry := range document.Versions {
+		version := normalizeFeedVersion(entry.Version)
+		if version == "" {
+			return Feed{}, fmt.Errorf("invalid graph api version %q in feed", entry.Version)
+		}
+		versions = append(versions, version)
+	}
+	changes := make([]string, 0, len(document.OutOfCycleChanges))
+	for _, change := range document.OutOfCycleChanges {
+		id := strings.TrimSpace(change.ID)
+		if id == "" {
+			id = strings.TrimSpace(change.Title)
+		}
+		if id == "" {
+			return Feed{}, errors.New("out_of_cycle_changes entries require id or title")
+		}
+		changes = append(changes, id)
+	}
+	return newFeed(versions, changes)
+}
+
+func parseDocumentFeed(body []byte) (Feed, error) {
+	matches := feedVersionPattern.FindAllString(string(body), -1)
+	return newFeed(matches, nil)
+}
+
+func newFeed(versions []string, changes []string) (Feed, error) {
+	versions = uniqueSorted(versions, func(left string, right string) bool {
+		return compareVersion(left, right) > 0
+	})
+	if len(versions) == 0 {
+		return Feed{}, ErrFeedEmpty
+	}
+	changes = uniqueSorted(changes, func(left string, right string) bool {
+		return left < right
+	})
+
+	hash := sha256.New()
+	for _, version := range versions {
+		fmt.Fprintf(hash, "version:%s\n", version)
+	}
+	for _, change := range changes {
+		fmt.Fprintf(hash, "occ:%s\n", change)
+	}
+	return Feed{
+		SchemaVersion:     FeedSchemaVersion,
+		LatestVersion:     versions[0],
+		Versions:          versions,
+		OutOfCycleChanges: changes,
+		Digest:            feedDigestPrefix + hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+func normalizeFeedVersion(value string) string {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "v") {
+		value = "v" + value
+	}
+	if !feedVersionPattern.MatchString(value) || feedVersionPattern.FindString(value) != value {
+		return ""
+	}
+	return value
+}
+
+func uniqueSorted(values []string, less func(string, string) bool) []string {
+	seen := make(map[string]struct{}, len(values))
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		out = append(out, value)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return less(out[i], out[j])
+	})
+	return out
+}
@@ -0,0 +1,113 @@
+package changelog
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseFeedNormalizesJSONFeed(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"versions":[{"version":"v24.0"},{"version":"25.0"},{"version":"v24.0"}],"out_of_cycle_changes":[{"id":"occ-b"},{"title":"occ-a"}]}`)
+	feed, err := ParseFeed(body, "application/json; charset=utf-8")
+	if err != nil {
+		t.Fatalf("parse feed: %v", err)
+	}
+	if feed.LatestVersion != "v25.0" {
+		t.Fatalf("unexpected latest version: %s", feed.LatestVersion)
+	}
+	if len(feed.Versions) != 2 || feed.Versions[1] != "v24.0" {
+		t.Fatalf("unexpected versions: %v", feed.Versions)
+	}
+	if len(feed.OutOfCycleChanges) != 2 || feed.OutOfCycleChanges[0] != "occ-a" {
+		t.Fatalf("unexpected out-of-cycle changes: %v", feed.OutOfCycleChanges)
+	}
+
+	reordered, err := ParseFeed([]byte(`{"versions":[{"version":"v25.0"},{"version":"v24.0"}],"out_of_cycle_changes":[{"id":"occ-a"},{"id":"occ-b"}]}`), "")
+	if err != nil {
+		t.Fatalf("parse reordered feed: %v", err)
+	}
+	if reordered.Digest != feed.Digest {
+		t.Fatalf("expected order-independent digest: %s != %s", reordered.Digest, feed.Digest)
+	}
+}
+
+func TestParseFeedExtractsVersionsFromDocument(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`<table><tr><td><a href="/docs/graph-api/changelog/version25.0">v25.0</a></td></tr><tr><td>v26.0</td></tr><tr><td>v9.0</td></tr></table>`)
+	feed, err := ParseFeed(body, "text/html; charset=utf-8")
+	if err != nil {
+		t.Fatalf("parse feed: %v", err)
+	}
+	if feed.LatestVersion != "v26.0" || len(feed.Versions) != 3 {
+		t.Fatalf("unexpected feed: %+v", feed)
+	}
+
+	if _, err := ParseFeed([]byte("<html>no versions</html>"), "text/html"); !errors.Is(err, ErrFeedEmpty) {
+		t.Fatalf("expected empty feed error, got %v", err)
+	}
+}
+
+func TestFeedFetcherResolveUsesFreshCache(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"versions":[{"version":"v25.0"}]}`))
+	}))
+	defer server.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "changelog-feed.json")
+	fetcher := NewFeedFetcher(server.Client(), server.URL)
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	first, err := fetcher.Resolve(context.Background(), FeedResolveOptions{CachePath: cachePath, TTL: time.Hour, Now: now})
+	if err != nil {
+		t.Fatalf("resolve feed: %v", err)
+	}
+	if first.FromCache || first.Feed.SourceURL != server.URL || !first.Feed.FetchedAt.Equal(now) {
+		t.Fatalf("unexpected first resolution: %+v", first)
+	}
+
+	second, err := fetcher.Resolve(context.Background(), FeedResolveOptions{CachePath: cachePath, TTL: time.Hour, Now: now.Add(30 * time.Minute)})
+	if err != nil {
+		t.Fatalf("resolve cached feed: %v", err)
+	}
+	if !second.FromCache || second.Feed.Digest != first.Feed.Digest {
+		t.Fatalf("expected cached resolution, got %+v", second)
+	}
+
+	third, err := fetcher.Resolve(context.Background(), FeedResolveOptions{CachePath: cachePath, TTL: time.Hour, Now: now.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("resolve expired feed: %v", err)
+	}
+	if third.FromCache {
+		t.Fatal("expected expired cache to be refetched")
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("unexpected upstream requests: got=%d want=%d", got, 2)
+	}
+}
+
+func TestFeedFetcherFailsOnUpstreamError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	fetcher := NewFeedFetcher(server.Client(), server.URL)
+	if _, err := fetcher.Fetch(context.Background(), time.Now()); err == nil {
+		t.Fatal("expected upstream status error")
+	}
+}
@@ -0,0 +1,310 @@
+// Package checkpoint records the completed units of a long-running operation
+// (bulk import objects, audience upload batches, exported campaigns) in a file
+// written after every unit, so an interrupted run can resume from the last
+// completed unit instead of redoing, and possibly duplicating, its work.
+package checkpoint
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+)
+
+const SchemaVersion = 1
+
+// ErrExists matches the error returned when a fresh run finds the checkpoint
+// of an interrupted one.
+var ErrExists = errors.New("checkpoint exists")
+
+type state struct {
+	SchemaVersion int                        `json:"schema_version"`
+	Operation     string                     `json:"operation"`
+	Fingerprint   string                     `json:"fingerprint"`
+	StartedAt     string                     `json:"started_at"`
+	UpdatedAt     string                     `json:"updated_at"`
+	Attributes    map[string]string          `json:"attributes,omitempty"`
+	Units         map[string]json.RawMessage `json:"units"`
+}
+
+// Checkpoint is safe for concurrent use; workers of one operation record their
+// units as they finish.
+type Checkpoint struct {
+	path    string
+	mu      sync.Mutex
+	state   state
+	resumed bool
+}
+
+// Open starts a checkpoint at path for operation. The fingerprint identifies the
+// input (file digest, account, options); a checkpoint written for another input
+// is never resumed. Without resume an existing checkpoint is an error wrapping
+// ErrExists, so a rerun cannot silently discard the record of created objects.
+// With resume a missing checkpoint starts a fresh run.
+func Open(path string, operation string, fingerprint string, resume bool) (*Checkpoint, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("checkpoint path is required")
+	}
+	operation = strings.TrimSpace(operation)
+	if operation == "" {
+		return nil, errors.New("checkpoint operation is required")
+	}
+
+	loaded, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	if loaded != nil && !resume {
+		return nil, fmt.Errorf("%w at %s from an interrupted %s run (updated %s); rerun with --resume to continue it or delete the file to start over", ErrExists, path, loaded.Operation, loaded.UpdatedAt)
+	}
+	if loaded != nil {
+		if loaded.Operation != operation {
+			return nil, fmt.Errorf("checkpoint %s belongs to %s, not %s", path, loaded.Operation, operation)
+		}
+		if loaded.Fingerprint != fingerprint {
+			return nil, fmt.Errorf("checkpoint %s was written for different input; rerun with the original file and flags or delete it to start over", path)
+		}
+		if loaded.Units == nil {
+			loaded.Units = map[string]json.RawMessage{}
+		}
+		return &Checkpoint{path: path, state: *loaded, resumed: true}, nil
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	checkpoint := &Checkpoint{path: path, state: state{
+		SchemaVersion: SchemaVersion,
+		Operation:     operation,
+		Fingerprint:   fingerprint,
+		StartedAt:     now,
+		UpdatedAt:     now,
+		Units:         map[string]json.RawMessage{},
+	}}
+	if err := checkpoint.save(); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// Fingerprint digests the parts that identify an operation's input.
+func Fingerprint(parts ...string) string {
+	sum := sha256.New()
+	for _, part := range parts {
+		sum.Write([]byte(part))
+		sum.Write([]byte{0})
+	}
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// FileFingerprint digests a file's contents together with extra parts.
+func FileFingerprint(path string, parts ...string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read %s for checkpoint: %w", path, err)
+	}
+	digest := sha256.Sum256(data)
+	return Fingerprint(append([]string{hex.EncodeToString(digest[:])}, parts...)...), nil
+}
+
+// DefaultPath places the checkpoint next to a file: structures.csv ->
+// structures.checkpoint.json.
+func DefaultPath(file string) string {
+	file = strings.TrimSpace(file)
+	return strings.TrimSuffix(file, filepath.Ext(file)) + ".checkpoint.json"
+}
+
+func (c *Checkpoint) Path() string {
+	if c == nil {
+		return ""
+	}
+	return c.path
+}
+
+// Resumed reports whether the checkpoint was loaded from an interrupted run.
+func (c *Checkpoint) Resumed() bool {
+	return c != nil && c.resumed
+}
+
+// Len is the number of completed units.
+func (c *Checkpoint) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.state.Units)
+}
+
+// Units lists the completed unit keys in sorted order.
+func (c *Checkpoint) Units() []string {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, len(c.state.Units))
+	for key := range c.state.Units {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Lookup decodes the value recorded for a completed unit into out. It reports
+// false for units that have not completed; a nil checkpoint has none.
+func (c *Checkpoint) Lookup(unit string, out any) (bool, error) {
+	if c == nil {
+		return false, nil
+	}
+	c.mu.Lock()
+	raw, ok := c.state.Units[unit]
+	c.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	if out == nil {
+		return true, nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return false, fmt.Errorf("decode checkpoint unit %q in %s: %w", unit, c.path, err)
+	}
+	return true, nil
+}
+
+// Complete records a unit with its result and writes the checkpoint. Recording
+// on a nil checkpoint is a no-op, so callers need not check for --resume
+// support.
+func (c *Checkpoint) Complete(unit string, value any) error {
+	if c == nil {
+		return nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode checkpoint unit %q: %w", unit, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state.Units[unit] = raw
+	return c.saveLocked()
+}
+
+// Attribute returns a value recorded for the whole run, such as the upload
+// session id an interrupted run must continue.
+func (c *Checkpoint) Attribute(key string) string {
+	if c == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state.Attributes[key]
+}
+
+func (c *Checkpoint) SetAttribute(key string, value string) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state.Attributes == nil {
+		c.state.Attributes = map[string]string{}
+	}
+	c.state.Attributes[key] = value
+	return c.saveLocked()
+}
+
+// Remove deletes the checkpoint once its operation finished; a finished run has
+// nothing left to resume.
+func (c *Checkpoint) Remove() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove checkpoint %s: %w", c.path, err)
+	}
+	return nil
+}
+
+func (c *Checkpoint) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.saveLocked()
+}
+
+func (c *Checkpoint) saveLocked() error {
+	lock, err := filelock.Acquire(c.path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	c.state.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create checkpoint directory for %s: %w", c.path, err)
+	}
+	payload, err := json.MarshalIndent(c.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode checkpoint: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".checkpoint-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp checkpoint file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp checkpoint file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp checkpoint file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), c.path); err != nil {
+		return fmt.Errorf("replace checkpoint %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// load returns nil without error when no checkpoint exists.
+func load(path string) (*state, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read checkpoint %s: %w", path, err)
+	}
+
+	loaded := &state{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(loaded); err != nil {
+		return nil, fmt.Errorf("decode checkpoint %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return nil, fmt.Errorf("decode checkpoint %s: multiple JSON values", path)
+		}
+		return nil, fmt.Errorf("decode checkpoint %s: %w", path, err)
+	}
+	if loaded.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("unsupported checkpoint schema_version=%d in %s (expected %d)", loaded.SchemaVersion, path, SchemaVersion)
+	}
+	return loaded, nil
+}
@@ -0,0 +1,76 @@
+package checkpoint
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenResumesRecordedUnits(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "run.checkpoint.json")
+	first, err := Open(path, "bulk_import", Fingerprint("sheet", "act_1"), false)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if err := first.Complete("c1", "111"); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if err := first.SetAttribute("session_id", "42"); err != nil {
+		t.Fatalf("set attribute: %v", err)
+	}
+
+	if _, err := Open(path, "bulk_import", Fingerprint("sheet", "act_1"), false); !errors.Is(err, ErrExists) {
+		t.Fatalf("expected a fresh run to refuse the checkpoint, got %v", err)
+	}
+	if _, err := Open(path, "bulk_import", Fingerprint("other", "act_1"), true); err == nil || !strings.Contains(err.Error(), "different input") {
+		t.Fatalf("expected fingerprint mismatch, got %v", err)
+	}
+	if _, err := Open(path, "export", Fingerprint("sheet", "act_1"), true); err == nil || !strings.Contains(err.Error(), "belongs to bulk_import") {
+		t.Fatalf("expected operation mismatch, got %v", err)
+	}
+
+	resumed, err := Open(path, "bulk_import", Fingerprint("sheet", "act_1"), true)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	var id string
+	if ok, err := resumed.Lookup("c1", &id); err != nil || !ok || id != "111" {
+		t.Fatalf("unexpected lookup %v %v %q", ok, err, id)
+	}
+	if ok, _ := resumed.Lookup("c2", &id); ok {
+		t.Fatal("expected c2 to be pending")
+	}
+	if !resumed.Resumed() || resumed.Attribute("session_id") != "42" {
+		t.Fatalf("unexpected resumed state %#v", resumed.state)
+	}
+
+	if err := resumed.Remove(); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected checkpoint removed, got %v", err)
+	}
+	fresh, err := Open(path, "bulk_import", "x", true)
+	if err != nil || fresh.Resumed() {
+		t.Fatalf("expected --resume without a checkpoint to start fresh, got %v", err)
+	}
+}
+
+func TestNilCheckpointIsNoop(t *testing.T) {
+	t.Parallel()
+
+	var checkpoint *Checkpoint
+	if err := checkpoint.Complete("c1", "1"); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if ok, err := checkpoint.Lookup("c1", nil); ok || err != nil {
+		t.Fatalf("unexpected lookup %v %v", ok, err)
+	}
+	if DefaultPath("data/structures.csv") != "data/structures.checkpoint.json" {
+		t.Fatalf("unexpected default path %q", DefaultPath("data/structures.csv"))
+	}
+}
@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/spf13/cobra"
+)
+
+var (
+	accountLoadProfileCredentials = loadProfileCredentials
+	accountNewGraphClient         = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+	accountNewService = func(client *graph.Client) *marketing.AccountService {
+		return marketing.NewAccountService(client)
+	}
+)
+
+func NewAccountCommand(runtime Runtime) *cobra.Command {
+	accountCmd := &cobra.Command{
+		Use:   "account",
+		Short: "Ad account listing, settings, funding, and spend cap commands",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "account")
+		},
+	}
+	accountCmd.AddCommand(newAccountListCommand(runtime))
+	accountCmd.AddCommand(newAccountGetCommand(runtime))
+	accountCmd.AddCommand(newAccountFundingCommand(runtime))
+	accountCmd.AddCommand(newAccountSpendCapCommand(runtime))
+	return accountCmd
+}
+
+func newAccountListCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile    string
+		version    string
+		fieldsRaw  string
+		name       string
+		activeOnly bool
+		limit      int
+		followNext bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List ad accounts accessible to the profile token",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveAccountProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta account list", err)
+			}
+
+			result, err := accountNewService(accountNewGraphClient()).List(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.AccountListInput{
+				Fields:     csvToSlice(fieldsRaw),
+				Name:       name,
+				ActiveOnly: activeOnly,
+				Limit:      limit,
+				FollowNext: followNext,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta account list", err)
+			}
+			return writeSuccess(cmd, runtime, "meta account list", result.Accounts, result.Paging, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated account fields (defaults to "+strings.Join(marketing.DefaultAccountReadFields, ",")+")")
+	cmd.Flags().StringVar(&name, "name", "", "Case-insensitive account name substring filter")
+	cmd.Flags().BoolVar(&activeOnly, "active-only", false, "Show only active ad accounts (account_status=1)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of accounts to return (0 = unlimited)")
+	cmd.Flags().BoolVar(&followNext, "follow-next", true, "Follow paging.next links")
+	return cmd
+}
+
+func newAccountGetCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile     string
+		version     string
+		accountID   string
+		accountName string
+		fieldsRaw   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Show ad account settings (name, status, currency, timezone, spend cap, funding)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveAccountProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta account get", err)
+			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta account get", err)
+			}
+
+			account, err := accountNewService(accountNewGraphClient()).Get(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.AccountGetInput{
+				AccountID: accountID,
+				Fields:    csvToSlice(fieldsRaw),
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta account get", err)
+			}
+			return writeSuccess(cmd, runtime, "meta account get", account, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated account fields (defaults to "+strings.Join(marketing.DefaultAccountGetFields, ",")+")")
+	cmd.MarkFlagsOneRequired("account-id", "account")
+	return cmd
+}
+
+func newAccountFundingCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile     string
+		version     string
+		accountID   string
+		accountName string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "funding",
+		Short: "Inspect the funding source attached to an ad account",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveAccountProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta account funding", err)
+			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta account funding", err)
+			}
+
+			result, err := accountNewService(accountNewGraphClient()).FundingSource(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, accountID)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta account funding", err)
+			}
+			return writeSuccess(cmd, runtime, "meta account funding", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
+	cmd.MarkFlagsOneRequired("account-id", "account")
+	return cmd
+}
+
+func newAccountSpendCapCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile             string
+		version             string
+		accountID           string
+		accountName         string
+		amount              string
+		remove              bool
+		reset               bool
+		confirmBudgetChange bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "spend-cap",
+		Short: "Set, remove, or reset an ad account spend cap",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			input := marketing.AccountSpendCapInput{
+				Amount: amount,
+				Remove: remove,
+				Reset:  reset,
+			}
+			payload, err := marketing.BuildSpendCapPayload(input)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta account spend-cap", err)
+			}
+			accountRef := accountID
+			if strings.TrimSpace(accountName) != "" {
+				accountRef = accountName
+			}
+			if err := enforceAccountSpendCapGuardrail(accountRef, payload, confirmBudgetChange); err != nil {
+				return writeCommandError(cmd, runtime, "meta account spend-cap", err)
+			}
+
+			creds, resolvedVersion, err := resolveAccountProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta account spend-cap", err)
+			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta account spend-cap", err)
+			}
+			input.AccountID = accountID
+			result, err := accountNewService(accountNewGraphClient()).SetSpendCap(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, input)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta account spend-cap", err)
+			}
+			return writeSuccess(cmd, runtime, "meta account spend-cap", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
+	cmd.Flags().StringVar(&amount, "amount", "", "Spend cap in the account currency (for example 5000 or 2500.50)")
+	cmd.Flags().BoolVar(&remove, "remove", false, "Remove the spend cap")
+	cmd.Flags().BoolVar(&reset, "reset", false, "Reset the amount spent against the current cap")
+	cmd.Flags().BoolVar(&confirmBudgetChange, "confirm-budget-change", false, "Acknowledge the spend cap change")
+	cmd.MarkFlagsOneRequired("account-id", "account")
+	return cmd
+}
+
+func enforceAccountSpendCapGuardrail(accountID string, payload map[string]string, confirmed bool) error {
+	if confirmed || strings.TrimSpace(accountID) == "" {
+		return nil
+	}
+	if action, ok := payload["spend_cap_action"]; ok {
+		return fmt.Errorf("spend cap %s on account %s changes spend limits; rerun with --confirm-budget-change", action, strings.TrimSpace(accountID))
+	}
+	return fmt.Errorf("setting spend_cap=%s on account %s changes spend limits; rerun with --confirm-budget-change", payload["spend_cap"], strings.TrimSpace(accountID))
+}
+
+func resolveAccountProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
+	resolvedProfile := strings.TrimSpace(profile)
+	if resolvedProfile == "" {
+		resolvedProfile = runtime.ProfileName()
+	}
+	if resolvedProfile == "" {
+		return nil, "", errors.New("profile is required (--profile or global --profile)")
+	}
+
+	creds, err := accountLoadProfileCredentials(resolvedProfile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resolvedVersion := strings.TrimSpace(version)
+	if resolvedVersion == "" {
+		resolvedVersion = creds.Profile.GraphVersion
+	}
+	if resolvedVersion == "" {
+		resolvedVersion = config.DefaultGraphVersion
+	}
+	return creds, resolvedVersion, nil
+}
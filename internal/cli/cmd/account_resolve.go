@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/spf13/cobra"
+)
+
+const (
+	accountCachePathEnv = "META_ACCOUNT_CACHE_PATH"
+	accountCacheTTLEnv  = "META_ACCOUNT_CACHE_TTL"
+)
+
+var (
+	accountResolverNewGraphClient = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+	accountResolverNow = time.Now
+)
+
+// addAccountNameFlag registers --account next to an existing --account-id flag.
+func addAccountNameFlag(cmd *cobra.Command, accountName *string) {
+	cmd.Flags().StringVar(accountName, "account", "", "Ad account name, resolved to an id via accessible ad accounts (alternative to --account-id)")
+	cmd.MarkFlagsMutuallyExclusive("account-id", "account")
+}
+
+// resolveAccountNameFlag replaces accountID with the id behind --account. It is a
+// no-op when --account is not set.
+func resolveAccountNameFlag(ctx context.Context, creds *ProfileCredentials, version string, accountID *string, accountName string) error {
+	name := strings.TrimSpace(accountName)
+	if name == "" {
+		return nil
+	}
+	if creds == nil {
+		return errors.New("profile credentials are required to resolve --account")
+	}
+
+	cachePath, err := resolveAccountCachePath()
+	if err != nil {
+		return err
+	}
+	ttl, err := resolveAccountCacheTTL()
+	if err != nil {
+		return err
+	}
+	resolver := &marketing.AccountResolver{
+		Service:   marketing.NewAccountService(accountResolverNewGraphClient()),
+		CachePath: cachePath,
+		TTL:       ttl,
+		Now:       accountResolverNow,
+	}
+	resolution, err := resolver.Resolve(ctx, version, creds.Token, creds.AppSecret, creds.Name, name)
+	if err != nil {
+		return fmt.Errorf("resolve --account: %w", err)
+	}
+	*accountID = resolution.AccountID
+	return nil
+}
+
+func resolveAccountCachePath() (string, error) {
+	if envPath := strings.TrimSpace(os.Getenv(accountCachePathEnv)); envPath != "" {
+		return envPath, nil
+	}
+	return marketing.DefaultAccountCachePath()
+}
+
+func resolveAccountCacheTTL() (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(accountCacheTTLEnv))
+	if raw == "" {
+		return marketing.DefaultAccountCacheTTL, nil
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid %s %q: expected a non-negative duration such as 30m", accountCacheTTLEnv, raw)
+	}
+	return ttl, nil
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestAccountGetReadsSettingsFields(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"id":"act_42","name":"Acme Inc","account_status":1,"currency":"USD","timezone_name":"America/New_York","spend_cap":"500000"}`,
+	}
+	useAccountDependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewAccountCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"get", "--account-id", "42"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute account get: %v", err)
+	}
+	if !strings.Contains(stub.lastURL, "/v25.0/act_42?") || !strings.Contains(stub.lastURL, "funding_source_details") {
+		t.Fatalf("unexpected request url %q", stub.lastURL)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta account get")
+	data := envelope["data"].(map[string]any)
+	if data["display"] != "Acme Inc (act_42) · USD · America/New_York" {
+		t.Fatalf("unexpected display %v", data["display"])
+	}
+}
+
+func TestAccountSpendCapRequiresConfirmation(t *testing.T) {
+	useAccountDependencies(t, nil)
+
+	cmd := NewAccountCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"spend-cap", "--account-id", "42", "--amount", "5000"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "rerun with --confirm-budget-change") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAccountSpendCapRemovePostsZero(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"success":true}`,
+	}
+	useAccountDependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewAccountCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"spend-cap", "--account-id", "act_42", "--remove", "--confirm-budget-change"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute account spend-cap: %v", err)
+	}
+	if stub.lastMethod != http.MethodPost || !strings.HasSuffix(stub.lastURL, "/v25.0/act_42") {
+		t.Fatalf("unexpected request %s %s", stub.lastMethod, stub.lastURL)
+	}
+	if !strings.Contains(stub.lastBody, "spend_cap=0") {
+		t.Fatalf("unexpected body %q", stub.lastBody)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta account spend-cap")
+}
+
+func TestAccountGetResolvesAccountName(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"id":"act_42","name":"Acme Inc","currency":"USD"}`,
+	}
+	useAccountDependencies(t, stub)
+	useAccountResolver(t, `{"data":[{"account_id":"42","name":"Acme Inc"},{"account_id":"43","name":"Other"}]}`)
+
+	output := &bytes.Buffer{}
+	cmd := NewAccountCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"get", "--account", "acme inc"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute account get: %v", err)
+	}
+	if !strings.Contains(stub.lastURL, "/v25.0/act_42?") {
+		t.Fatalf("unexpected request url %q", stub.lastURL)
+	}
+}
+
+func TestAccountGetFailsOnAmbiguousAccountName(t *testing.T) {
+	useAccountDependencies(t, nil)
+	useAccountResolver(t, `{"data":[{"account_id":"42","name":"Acme"},{"account_id":"43","name":"ACME"}]}`)
+
+	cmd := NewAccountCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"get", "--account", "acme"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "act_42 (Acme), act_43 (ACME)") || !strings.Contains(err.Error(), "pass --account-id explicitly") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func useAccountResolver(t *testing.T, response string) {
+	t.Helper()
+	t.Setenv(accountCachePathEnv, filepath.Join(t.TempDir(), "ad-accounts.json"))
+	original := accountResolverNewGraphClient
+	t.Cleanup(func() {
+		accountResolverNewGraphClient = original
+	})
+	accountResolverNewGraphClient = func() *graph.Client {
+		client := graph.NewClient(&stubHTTPClient{t: t, statusCode: http.StatusOK, response: response}, "https://graph.example.com")
+		client.MaxRetries = 0
+		return client
+	}
+}
+
+func useAccountDependencies(t *testing.T, stub *stubHTTPClient) {
+	t.Helper()
+	originalLoad := accountLoadProfileCredentials
+	originalClient := accountNewGraphClient
+	t.Cleanup(func() {
+		accountLoadProfileCredentials = originalLoad
+		accountNewGraphClient = originalClient
+	})
+
+	accountLoadProfileCredentials = func(string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{
+			Name:    "prod",
+			Profile: config.Profile{GraphVersion: "v25.0"},
+			Token:   "token",
+		}, nil
+	}
+	accountNewGraphClient = func() *graph.Client {
+		if stub == nil {
+			t.Fatal("graph client should not be created")
+		}
+		client := graph.NewClient(stub, "https://graph.example.com")
+		client.MaxRetries = 0
+		return client
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/bilalbayram/metacli/internal/marketing"
 	"github.com/bilalbayram/metacli/internal/ops"
 	"github.com/bilalbayram/metacli/internal/schema"
+	"github.com/bilalbayram/metacli/internal/templates"
 	"github.com/spf13/cobra"
 )
 
@@ -45,6 +47,8 @@ func NewAdCommand(runtime Runtime) *cobra.Command {
 	adCmd.AddCommand(newAdPauseCommand(runtime))
 	adCmd.AddCommand(newAdResumeCommand(runtime))
 	adCmd.AddCommand(newAdCloneCommand(runtime))
+	adCmd.AddCommand(newAdPreviewCommand(runtime))
+	adCmd.AddCommand(newWatchCommand(runtime, adWatchTarget))
 	return adCmd
 }
 
@@ -53,6 +57,7 @@ func newAdListCommand(runtime Runtime) *cobra.Command {
 		profile            string
 		version            string
 		accountID          string
+		accountName        string
 		campaignID         string
 		adSetID            string
 		fieldsRaw          string
@@ -75,6 +80,9 @@ func newAdListCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta ad list", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta ad list", err)
+			}
 
 			linter, err := newAdMutationLinter(creds, resolvedVersion, schemaDir)
 			if err != nil {
@@ -113,6 +121,7 @@ func newAdListCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&campaignID, "campaign-id", "", "Campaign id filter")
 	cmd.Flags().StringVar(&adSetID, "adset-id", "", "Ad set id filter")
 	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated Graph fields (defaults to ad read fields)")
@@ -129,12 +138,16 @@ func newAdListCommand(runtime Runtime) *cobra.Command {
 
 func newAdCreateCommand(runtime Runtime) *cobra.Command {
 	var (
-		profile   string
-		version   string
-		accountID string
-		paramsRaw string
-		jsonRaw   string
-		schemaDir string
+		profile      string
+		version      string
+		accountID    string
+		accountName  string
+		paramsRaw    string
+		jsonRaw      string
+		schemaDir    string
+		templateName string
+		templateVars []string
+		utm          utmFlags
 	)
 
 	cmd := &cobra.Command{
@@ -146,6 +159,9 @@ func newAdCreateCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta ad create", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta ad create", err)
+			}
 
 			form, err := parseKeyValueList(paramsRaw)
 			if err != nil {
@@ -158,6 +174,9 @@ func newAdCreateCommand(runtime Runtime) *cobra.Command {
 			if err := mergeParams(form, jsonForm, "--json"); err != nil {
 				return writeCommandError(cmd, runtime, "meta ad create", err)
 			}
+			if err := mergeTemplateParams(form, templates.KindAd, templateName, templateVars); err != nil {
+				return writeCommandError(cmd, runtime, "meta ad create", err)
+			}
 
 			linter, err := newAdMutationLinter(creds, resolvedVersion, schemaDir)
 			if err != nil {
@@ -167,6 +186,33 @@ func newAdCreateCommand(runtime Runtime) *cobra.Command {
 				return writeCommandError(cmd, runtime, "meta ad create", err)
 			}
 
+			tags, err := utm.render(cmd.Context(), map[string]string{
+				"account.id": accountID,
+				"adset.id":   strings.TrimSpace(form["adset_id"]),
+				"ad.name":    form["name"],
+			}, form["adset_id"], func(ctx context.Context, adSetID string) (map[string]string, error) {
+				return adsetNewService(adNewGraphClient()).UTMVars(ctx, resolvedVersion, creds.Token, creds.AppSecret, adSetID)
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ad create", err)
+			}
+			var taggedLinks []marketing.TaggedLink
+			if len(tags) > 0 {
+				creativeID, links, err := adNewService(adNewGraphClient()).CreativeLinks(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, form["creative"])
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta ad create", err)
+				}
+				if err := requireTaggedCreativeLinks(creativeID, links, tags); err != nil {
+					return writeCommandError(cmd, runtime, "meta ad create", err)
+				}
+				if utm.checkURLs {
+					if err := marketing.CheckLinks(cmd.Context(), utmCheckHTTPClient(), links); err != nil {
+						return writeCommandError(cmd, runtime, "meta ad create", err)
+					}
+				}
+				taggedLinks = links
+			}
+
 			result, err := adNewService(adNewGraphClient()).Create(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.AdCreateInput{
 				AccountID: accountID,
 				Params:    form,
@@ -174,6 +220,7 @@ func newAdCreateCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta ad create", err)
 			}
+			result.TaggedLinks = taggedLinks
 			if err := persistTrackedResource(trackedResourceInput{
 				Command:       "meta ad create",
 				ResourceKind:  ops.ResourceKindAd,
@@ -196,9 +243,12 @@ func newAdCreateCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&paramsRaw, "params", "", "Comma-separated mutation params (k=v,k2=v2)")
 	cmd.Flags().StringVar(&jsonRaw, "json", "", "Inline JSON object payload")
 	cmd.Flags().StringVar(&schemaDir, "schema-dir", schema.DefaultSchemaDir(), "Schema pack root directory")
+	addTemplateFlags(cmd, &templateName, &templateVars)
+	addUTMFlags(cmd, &utm)
 	return cmd
 }
 
@@ -243,8 +293,9 @@ func newAdUpdateCommand(runtime Runtime) *cobra.Command {
 			}
 
 			result, err := adNewService(adNewGraphClient()).Update(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.AdUpdateInput{
-				AdID:   adID,
-				Params: form,
+				AdID:        adID,
+				Params:      form,
+				DiffPreview: marketing.DiffPreview{PreviewDiff: true},
 			})
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta ad update", err)
@@ -319,14 +370,15 @@ func newAdStatusCommand(runtime Runtime, operation string, status string) *cobra
 
 func newAdCloneCommand(runtime Runtime) *cobra.Command {
 	var (
-		profile    string
-		version    string
-		sourceAdID string
-		accountID  string
-		fieldsRaw  string
-		paramsRaw  string
-		jsonRaw    string
-		schemaDir  string
+		profile     string
+		version     string
+		sourceAdID  string
+		accountID   string
+		accountName string
+		fieldsRaw   string
+		paramsRaw   string
+		jsonRaw     string
+		schemaDir   string
 	)
 
 	cmd := &cobra.Command{
@@ -338,6 +390,9 @@ func newAdCloneCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta ad clone", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta ad clone", err)
+			}
 
 			overrides, err := parseKeyValueList(paramsRaw)
 			if err != nil {
@@ -400,6 +455,7 @@ func newAdCloneCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&sourceAdID, "source-ad-id", "", "Source ad id")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Target ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&fieldsRaw, "fields", strings.Join(marketing.DefaultAdCloneFields, ","), "Comma-separated fields to read from source ad")
 	cmd.Flags().StringVar(&paramsRaw, "params", "", "Comma-separated override params (k=v,k2=v2)")
 	cmd.Flags().StringVar(&jsonRaw, "json", "", "Inline JSON object overrides")
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/spf13/cobra"
+)
+
+func newAdPreviewCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile          string
+		version          string
+		adID             string
+		accountID        string
+		accountName      string
+		creativeSpecPath string
+		formatsRaw       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "preview",
+		Short: "Render ad previews per placement for review before activation",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveAdProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ad preview", err)
+			}
+
+			input := marketing.AdPreviewInput{
+				AdID:    adID,
+				Formats: csvToSlice(formatsRaw),
+			}
+			if strings.TrimSpace(creativeSpecPath) != "" {
+				if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+					return writeCommandError(cmd, runtime, "meta ad preview", err)
+				}
+				if strings.TrimSpace(accountID) == "" {
+					return writeCommandError(cmd, runtime, "meta ad preview", errors.New("--creative-spec requires --account-id or --account"))
+				}
+				spec, err := readAdCreativeSpec(creativeSpecPath)
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta ad preview", err)
+				}
+				input.AccountID = accountID
+				input.CreativeSpec = spec
+			}
+
+			result, err := adNewService(adNewGraphClient()).Preview(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, input)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ad preview", err)
+			}
+			return writeSuccess(cmd, runtime, "meta ad preview", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&adID, "ad-id", "", "Existing ad id to preview")
+	cmd.Flags().StringVar(&creativeSpecPath, "creative-spec", "", "Path to a JSON creative spec to preview without creating an ad")
+	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id used to render --creative-spec (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
+	cmd.Flags().StringVar(&formatsRaw, "formats", "MOBILE_FEED_STANDARD", "Comma-separated ad formats (for example MOBILE_FEED_STANDARD,INSTAGRAM_STORY)")
+	cmd.MarkFlagsOneRequired("ad-id", "creative-spec")
+	cmd.MarkFlagsMutuallyExclusive("ad-id", "creative-spec")
+	return cmd
+}
+
+func readAdCreativeSpec(path string) (map[string]any, error) {
+	data, err := os.ReadFile(strings.TrimSpace(path))
+	if err != nil {
+		return nil, fmt.Errorf("read --creative-spec: %w", err)
+	}
+	var spec map[string]any
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("decode --creative-spec: expected a JSON object: %w", err)
+	}
+	if len(spec) == 0 {
+		return nil, errors.New("--creative-spec must not be empty")
+	}
+	return spec, nil
+}
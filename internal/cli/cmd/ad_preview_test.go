@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestAdPreviewCreativeSpecFromFile(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "creative.json")
+	if err := os.WriteFile(specPath, []byte(`{"object_story_spec":{"page_id":"p1"}}`), 0o644); err != nil {
+		t.Fatalf("write creative spec: %v", err)
+	}
+
+	formats := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v25.0/act_222/generatepreviews" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		formats = append(formats, r.URL.Query().Get("ad_format"))
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{{"body": `<iframe src="https://preview.example.com/x"></iframe>`}}})
+	}))
+	defer server.Close()
+	useAdPreviewDependencies(t, func() *graph.Client {
+		client := graph.NewClient(server.Client(), server.URL)
+		client.MaxRetries = 0
+		return client
+	})
+
+	output := &bytes.Buffer{}
+	cmd := NewAdCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"preview",
+		"--creative-spec", specPath,
+		"--account-id", "222",
+		"--formats", "MOBILE_FEED_STANDARD,INSTAGRAM_STORY",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute ad preview: %v", err)
+	}
+	if strings.Join(formats, ",") != "MOBILE_FEED_STANDARD,INSTAGRAM_STORY" {
+		t.Fatalf("unexpected formats %v", formats)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta ad preview")
+	data, ok := envelope["data"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected object payload, got %T", envelope["data"])
+	}
+	previews, _ := data["previews"].([]any)
+	if len(previews) != 2 {
+		t.Fatalf("unexpected previews %v", data["previews"])
+	}
+}
+
+func TestAdPreviewCreativeSpecRequiresAccount(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "creative.json")
+	if err := os.WriteFile(specPath, []byte(`{"name":"x"}`), 0o644); err != nil {
+		t.Fatalf("write creative spec: %v", err)
+	}
+	useAdPreviewDependencies(t, func() *graph.Client {
+		t.Fatal("graph client should not be created")
+		return nil
+	})
+
+	cmd := NewAdCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"preview", "--creative-spec", specPath})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--creative-spec requires --account-id or --account") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestAdPreviewRejectsAdIDWithCreativeSpec(t *testing.T) {
+	useAdPreviewDependencies(t, func() *graph.Client {
+		t.Fatal("graph client should not be created")
+		return nil
+	})
+
+	cmd := NewAdCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"preview", "--ad-id", "ad_1", "--creative-spec", "spec.json"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected mutually exclusive flag error")
+	}
+}
+
+func useAdPreviewDependencies(t *testing.T, clientFn func() *graph.Client) {
+	t.Helper()
+	useAdDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name: "prod",
+				Profile: config.Profile{
+					Domain:       config.DefaultDomain,
+					GraphVersion: config.DefaultGraphVersion,
+				},
+				Token: "test-token",
+			}, nil
+		},
+		clientFn,
+	)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/bilalbayram/metacli/internal/marketing"
 	"github.com/bilalbayram/metacli/internal/ops"
 	"github.com/bilalbayram/metacli/internal/schema"
+	"github.com/bilalbayram/metacli/internal/templates"
 	"github.com/spf13/cobra"
 )
 
@@ -81,6 +82,7 @@ func NewAdsetCommand(runtime Runtime) *cobra.Command {
 	adsetCmd.AddCommand(newAdsetUpdateCommand(runtime))
 	adsetCmd.AddCommand(newAdsetPauseCommand(runtime))
 	adsetCmd.AddCommand(newAdsetResumeCommand(runtime))
+	adsetCmd.AddCommand(newWatchCommand(runtime, adsetWatchTarget))
 	return adsetCmd
 }
 
@@ -93,6 +95,7 @@ func newAdsetListCommand(runtime Runtime) *cobra.Command {
 		profile            string
 		version            string
 		accountID          string
+		accountName        string
 		campaignID         string
 		fieldsRaw          string
 		nameRaw            string
@@ -114,6 +117,9 @@ func newAdsetListCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta adset list", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta adset list", err)
+			}
 
 			linter, err := newAdsetMutationLinter(creds, resolvedVersion, schemaDir)
 			if err != nil {
@@ -128,6 +134,7 @@ func newAdsetListCommand(runtime Runtime) *cobra.Command {
 				return writeCommandError(cmd, runtime, "meta adset list", err)
 			}
 
+			prefetchOutputCurrency(cmd, runtime, adsetNewGraphClient(), resolvedVersion, creds.Token, creds.AppSecret, accountID)
 			result, err := adsetNewService(adsetNewGraphClient()).List(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.AdSetListInput{
 				AccountID:         accountID,
 				CampaignID:        campaignID,
@@ -144,6 +151,8 @@ func newAdsetListCommand(runtime Runtime) *cobra.Command {
 				return writeCommandError(cmd, runtime, "meta adset list", err)
 			}
 
+			recordListedEntities(creds.Name, marketing.EntityKindAdSet, accountID, result.AdSets)
+			resolveOutputCurrency(cmd, runtime, adsetNewGraphClient(), resolvedVersion, creds.Token, creds.AppSecret, listedAccountID(accountID, result.AdSets))
 			return writeSuccess(cmd, runtime, "meta adset list", result.AdSets, result.Paging, nil)
 		},
 	}
@@ -151,6 +160,7 @@ func newAdsetListCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&campaignID, "campaign-id", "", "Campaign id filter")
 	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated Graph fields (defaults to ad set read fields)")
 	cmd.Flags().StringVar(&nameRaw, "name", "", "Case-insensitive ad set name contains filter")
@@ -169,10 +179,13 @@ func newAdsetCreateCommand(runtime Runtime) *cobra.Command {
 		profile             string
 		version             string
 		accountID           string
+		accountName         string
 		paramsRaw           string
 		jsonRaw             string
 		schemaDir           string
 		confirmBudgetChange bool
+		templateName        string
+		templateVars        []string
 	)
 
 	cmd := &cobra.Command{
@@ -184,6 +197,9 @@ func newAdsetCreateCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta adset create", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta adset create", err)
+			}
 
 			form, err := parseKeyValueList(paramsRaw)
 			if err != nil {
@@ -196,9 +212,17 @@ func newAdsetCreateCommand(runtime Runtime) *cobra.Command {
 			if err := mergeParams(form, jsonForm, "--json"); err != nil {
 				return writeCommandError(cmd, runtime, "meta adset create", err)
 			}
+			if err := mergeTemplateParams(form, templates.KindAdSet, templateName, templateVars); err != nil {
+				return writeCommandError(cmd, runtime, "meta adset create", err)
+			}
 			if err := enforceAdsetBudgetGuardrail(form, confirmBudgetChange); err != nil {
 				return writeCommandError(cmd, runtime, "meta adset create", err)
 			}
+			if len(adsetBudgetFieldValues(form)) > 0 {
+				// The budget floor check needs the account currency; fetch it while
+				// the schema pack loads.
+				marketing.AccountContextCacheFrom(cmd.Context()).Prefetch(cmd.Context(), adsetNewGraphClient(), resolvedVersion, creds.Token, creds.AppSecret, accountID)
+			}
 			if err := resolveAdsetIntentRequirements(form); err != nil {
 				return writeCommandError(cmd, runtime, "meta adset create", err)
 			}
@@ -245,10 +269,12 @@ func newAdsetCreateCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&paramsRaw, "params", "", "Comma-separated mutation params (k=v,k2=v2)")
 	cmd.Flags().StringVar(&jsonRaw, "json", "", "Inline JSON object payload")
 	cmd.Flags().StringVar(&schemaDir, "schema-dir", schema.DefaultSchemaDir(), "Schema pack root directory")
 	cmd.Flags().BoolVar(&confirmBudgetChange, "confirm-budget-change", false, "Acknowledge budget mutation fields (daily_budget/lifetime_budget)")
+	addTemplateFlags(cmd, &templateName, &templateVars)
 	return cmd
 }
 
@@ -305,8 +331,9 @@ func newAdsetUpdateCommand(runtime Runtime) *cobra.Command {
 			}
 
 			result, err := service.Update(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.AdSetUpdateInput{
-				AdSetID: adSetID,
-				Params:  form,
+				AdSetID:     adSetID,
+				Params:      form,
+				DiffPreview: marketing.DiffPreview{PreviewDiff: true},
 			})
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta adset update", err)
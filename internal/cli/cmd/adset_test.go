@@ -663,6 +663,18 @@ func TestAdsetUpdateBudgetMutationAllowsConfirmation(t *testing.T) {
 					}
 				},
 			},
+			{
+				body: `{"daily_budget":"1500","id":"8100"}`,
+				assert: func(t *testing.T, req *http.Request, _ string) {
+					t.Helper()
+					if req.Method != http.MethodGet {
+						t.Fatalf("unexpected method %q", req.Method)
+					}
+					if got := req.URL.Query().Get("fields"); got != "daily_budget" {
+						t.Fatalf("unexpected current-values fields query %q", got)
+					}
+				},
+			},
 			{
 				body: `{"success":true}`,
 				assert: func(t *testing.T, req *http.Request, body string) {
@@ -727,6 +739,22 @@ func TestAdsetUpdateBudgetMutationAllowsConfirmation(t *testing.T) {
 
 	envelope := decodeEnvelope(t, output.Bytes())
 	assertEnvelopeBasics(t, envelope, "meta adset update")
+	data, ok := envelope["data"].(map[string]any)
+	if !ok {
+		t.Fatalf("unexpected data payload type %T", envelope["data"])
+	}
+	diff, ok := data["diff"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected update diff, got %#v", data["diff"])
+	}
+	changes, ok := diff["changes"].([]any)
+	if !ok || len(changes) != 1 {
+		t.Fatalf("unexpected diff changes %#v", diff["changes"])
+	}
+	change := changes[0].(map[string]any)
+	if change["field"] != "daily_budget" || change["before"] != "1500" || change["after"] != "2000" || change["changed"] != true {
+		t.Fatalf("unexpected diff change %#v", change)
+	}
 	if errOutput.Len() != 0 {
 		t.Fatalf("expected empty stderr, got %q", errOutput.String())
 	}
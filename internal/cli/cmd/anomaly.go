@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"github.com/bilalbayram/metacli/pkg/metacli"
+)
+
+const anomalyPolicyPathEnv = metacli.AnomalyPolicyPathEnv
+
+// ConfigureAnomalyGuard installs the spend anomaly guard when an anomaly
+// policy exists. Only --override-anomaly lets a blocked resume or budget
+// increase through.
+func ConfigureAnomalyGuard() error {
+	return metacli.InstallAnomalyGuard()
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestAnomalyGuardBlocksResumeOnSpendSpike(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "anomaly.yaml")
+	t.Setenv(anomalyPolicyPathEnv, policyPath)
+	t.Cleanup(func() { graph.SetMutationGuard("spend_anomaly", nil) })
+	policy := "schema_version: 1\nrules:\n  - name: runaway\n    max_spend_ratio: 3\n"
+	if err := os.WriteFile(policyPath, []byte(policy), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	if err := ConfigureAnomalyGuard(); err != nil {
+		t.Fatalf("configure anomaly guard: %v", err)
+	}
+
+	var posts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("access_token"); got != "test-token" {
+			t.Fatalf("expected preflight reads to use the mutation's token, got %q", got)
+		}
+		switch {
+		case r.Method == http.MethodPost:
+			posts++
+			_, _ = w.Write([]byte(`{"success":true}`))
+		case r.URL.Path == "/v25.0/777":
+			_ = json.NewEncoder(w).Encode(map[string]any{"account_id": "111", "status": "PAUSED"})
+		case r.URL.Query().Get("date_preset") == "yesterday":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []any{map[string]any{"spend": "10.00"}}})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []any{map[string]any{"spend": "95.00"}}})
+		}
+	}))
+	defer server.Close()
+
+	client := graph.NewClient(server.Client(), server.URL)
+	client.MaxRetries = 0
+	_, err := client.Do(context.Background(), graph.Request{
+		Method:      http.MethodPost,
+		Path:        "777",
+		Version:     "v25.0",
+		Form:        map[string]string{"status": "ACTIVE"},
+		AccessToken: "test-token",
+	})
+	apiErr := &graph.APIError{}
+	if !errors.As(err, &apiErr) || apiErr.Type != "spend_anomaly_gate" || posts != 0 {
+		t.Fatalf("expected the resume to be blocked before sending, got %v (posts=%d)", err, posts)
+	}
+}
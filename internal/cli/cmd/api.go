@@ -12,6 +12,7 @@ import (
 
 	"github.com/bilalbayram/metacli/internal/config"
 	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -36,14 +37,16 @@ func NewAPICommand(runtime Runtime) *cobra.Command {
 
 func newAPIGetCommand(runtime Runtime) *cobra.Command {
 	var (
-		profile    string
-		version    string
-		paramsRaw  string
-		fields     string
-		followNext bool
-		limit      int
-		pageSize   int
-		stream     bool
+		profile     string
+		version     string
+		paramsRaw   string
+		fields      string
+		followNext  bool
+		limit       int
+		pageSize    int
+		stream      bool
+		concurrency int
+		outPath     string
 	)
 
 	cmd := &cobra.Command{
@@ -51,6 +54,9 @@ func newAPIGetCommand(runtime Runtime) *cobra.Command {
 		Short: "Run a Graph GET request",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if concurrency < 1 {
+				return errors.New("--concurrency must be > 0")
+			}
 			creds, resolvedVersion, err := resolveAPIProfileAndVersion(runtime, profile, version)
 			if err != nil {
 				return err
@@ -77,14 +83,47 @@ func newAPIGetCommand(runtime Runtime) *cobra.Command {
 				AppSecret:   creds.AppSecret,
 			}
 
+			options := graph.PaginationOptions{
+				FollowNext:  followNext || stream,
+				Limit:       limit,
+				PageSize:    pageSize,
+				Stream:      stream,
+				Concurrency: concurrency,
+			}
+			// Paginated reads report rows fetched on stderr. Streams written to a
+			// terminal skip it, since the bar would interleave with the rows.
+			ctx := cmd.Context()
+			fetchProgress := &commandProgress{}
+			if options.FollowNext && !(stream && output.IsTerminal(cmd.OutOrStdout())) {
+				fetchProgress = startProgress(cmd, runtime, "api get", int64(limit))
+				fetchProgress.Bar().SetUnit("items")
+				ctx = fetchProgress.Context(ctx)
+			}
+			defer fetchProgress.Finish()
+
+			if strings.TrimSpace(outPath) != "" {
+				result, pagination, err := exportAPIRows(ctx, client, request, options, outPath, selectedOutputColumns(runtime))
+				fetchProgress.Finish()
+				if err != nil {
+					return err
+				}
+				return writeSuccess(cmd, runtime, "meta api get", result, pagination, nil)
+			}
+			if stream && selectedOutputFormat(runtime) == "csv" {
+				writer := output.NewCSVWriter(cmd.OutOrStdout(), selectedOutputColumns(runtime), true)
+				if _, err := client.FetchWithPagination(ctx, request, options, func(item map[string]any) error {
+					if err := writer.WriteRow(item); err != nil {
+						return err
+					}
+					return writer.Flush()
+				}); err != nil {
+					return err
+				}
+				return writer.Close()
+			}
 			if followNext || stream {
 				items := make([]map[string]any, 0)
-				pagination, err := client.FetchWithPagination(cmd.Context(), request, graph.PaginationOptions{
-					FollowNext: followNext || stream,
-					Limit:      limit,
-					PageSize:   pageSize,
-					Stream:     stream,
-				}, func(item map[string]any) error {
+				pagination, err := client.FetchWithPagination(ctx, request, options, func(item map[string]any) error {
 					if stream {
 						line, err := json.Marshal(item)
 						if err != nil {
@@ -96,6 +135,7 @@ func newAPIGetCommand(runtime Runtime) *cobra.Command {
 					items = append(items, item)
 					return nil
 				})
+				fetchProgress.Finish()
 				if err != nil {
 					return err
 				}
@@ -121,6 +161,8 @@ func newAPIGetCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of records to return")
 	cmd.Flags().IntVar(&pageSize, "page-size", 0, "Page size for paginated queries")
 	cmd.Flags().BoolVar(&stream, "stream", false, "Stream records as newline-delimited JSON")
+	cmd.Flags().StringVar(&outPath, "out", "", "Write the records to this .csv or .jsonl file as pages arrive and print a summary instead")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "Pages fetched in parallel when paging.next is offset-based (throttled by rate-limit usage)")
 	return cmd
 }
 
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/output"
+)
+
+type apiExportResult struct {
+	Path           string   `json:"path"`
+	Format         string   `json:"format"`
+	Rows           int      `json:"rows"`
+	Columns        []string `json:"columns,omitempty"`
+	DroppedColumns []string `json:"dropped_columns,omitempty"`
+}
+
+// apiRowSink is where exportAPIRows puts records as they arrive.
+type apiRowSink interface {
+	WriteRow(map[string]any) error
+	Close() error
+}
+
+type jsonlRowSink struct {
+	buffer *bufio.Writer
+	rows   int
+}
+
+func (s *jsonlRowSink) WriteRow(row map[string]any) error {
+	line, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	if _, err := s.buffer.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	s.rows++
+	return nil
+}
+
+func (s *jsonlRowSink) Close() error {
+	return s.buffer.Flush()
+}
+
+// exportAPIRows streams every record of a (paginated) GET into path. Rows go
+// to a temp file beside path through a bounded buffer and the file is renamed
+// into place only once the last page is written, so an interrupted export
+// never leaves a truncated file behind.
+func exportAPIRows(ctx context.Context, client *graph.Client, request graph.Request, options graph.PaginationOptions, path string, columns []string) (apiExportResult, *graph.PaginationResult, error) {
+	path = strings.TrimSpace(path)
+	result := apiExportResult{Path: path}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		result.Format = "csv"
+	case ".jsonl", ".ndjson":
+		result.Format = "jsonl"
+	default:
+		return result, nil, fmt.Errorf("--out %s: expected a .csv or .jsonl file", path)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return result, nil, fmt.Errorf("create export directory for %s: %w", path, err)
+	}
+	tmpFile, err := os.CreateTemp(dir, ".export-*"+filepath.Ext(path))
+	if err != nil {
+		return result, nil, fmt.Errorf("create temp export file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	var (
+		sink   apiRowSink
+		csvOut *output.CSVWriter
+		jsonl  *jsonlRowSink
+	)
+	if result.Format == "csv" {
+		csvOut = output.NewCSVWriter(tmpFile, columns, true)
+		sink = csvOut
+	} else {
+		jsonl = &jsonlRowSink{buffer: bufio.NewWriterSize(tmpFile, 64*1024)}
+		sink = jsonl
+	}
+
+	pagination, err := client.FetchWithPagination(ctx, request, options, sink.WriteRow)
+	if err == nil {
+		err = sink.Close()
+	}
+	if err != nil {
+		tmpFile.Close()
+		return result, nil, fmt.Errorf("write export file %s: %w", path, err)
+	}
+	if err := tmpFile.Chmod(0o644); err != nil {
+		tmpFile.Close()
+		return result, nil, fmt.Errorf("chmod temp export file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return result, nil, fmt.Errorf("close temp export file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return result, nil, fmt.Errorf("replace export file %s: %w", path, err)
+	}
+
+	if csvOut != nil {
+		result.Rows = csvOut.Rows()
+		result.Columns = csvOut.Columns()
+		result.DroppedColumns = csvOut.DroppedColumns()
+	} else {
+		result.Rows = jsonl.rows
+	}
+	return result, pagination, nil
+}
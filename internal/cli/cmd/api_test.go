@@ -5,12 +5,16 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/bilalbayram/metacli/internal/config"
 	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/testutil"
 )
 
 type stubHTTPClient struct {
@@ -313,6 +317,7 @@ func decodeEnvelope(t *testing.T, raw []byte) map[string]any {
 	if err := json.Unmarshal(raw, &decoded); err != nil {
 		t.Fatalf("decode envelope: %v", err)
 	}
+	testutil.AssertContract(t, "envelope", raw)
 	return decoded
 }
 
@@ -330,3 +335,55 @@ func assertEnvelopeBasics(t *testing.T, envelope map[string]any, command string)
 		t.Fatalf("expected successful envelope, got %+v", envelope)
 	}
 }
+
+func TestAPIGetOutStreamsPagesToFile(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("after") == "" {
+			_, _ = io.WriteString(w, `{"data":[{"id":"1","name":"One"},{"id":"2","name":"Two"}],"paging":{"next":"`+server.URL+`/v25.0/act_1/campaigns?after=c1"}}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"data":[{"id":"3","name":"Three, Inc","status":"PAUSED"}]}`)
+	}))
+	defer server.Close()
+	useDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{Name: "prod", Profile: config.Profile{GraphVersion: "v25.0"}, Token: "test-token"}, nil
+		},
+		func() *graph.Client { return graph.NewClient(server.Client(), server.URL) },
+	)
+
+	outPath := filepath.Join(t.TempDir(), "exports", "campaigns.csv")
+	stdout := &bytes.Buffer{}
+	cmd := newAPIGetCommand(testRuntime("prod"))
+	cmd.SetOut(stdout)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"act_1/campaigns", "--follow-next", "--out", outPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute api get --out: %v", err)
+	}
+
+	written, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if want := "id,name\n1,One\n2,Two\n3,\"Three, Inc\"\n"; string(written) != want {
+		t.Fatalf("unexpected csv:\n%s", written)
+	}
+	envelope := decodeEnvelope(t, stdout.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta api get")
+	data := envelope["data"].(map[string]any)
+	if data["rows"] != float64(3) || data["format"] != "csv" || data["dropped_columns"].([]any)[0] != "status" {
+		t.Fatalf("unexpected summary: %v", data)
+	}
+
+	cmd = newAPIGetCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"act_1/campaigns", "--out", filepath.Join(t.TempDir(), "campaigns.xlsx")})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "expected a .csv or .jsonl file") {
+		t.Fatalf("expected unsupported extension error, got %v", err)
+	}
+}
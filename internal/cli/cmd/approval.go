@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/approval"
+	"github.com/bilalbayram/metacli/pkg/metacli"
+	"github.com/spf13/cobra"
+)
+
+const (
+	approvalPolicyPathEnv = metacli.ApprovalPolicyPathEnv
+	approvalKeyPathEnv    = metacli.ApprovalKeyPathEnv
+	approvalRequestDirEnv = metacli.ApprovalRequestDirEnv
+)
+
+var approvalNow = time.Now
+
+type approveResult struct {
+	RequestID   string   `json:"request_id"`
+	Requester   string   `json:"requester"`
+	Approver    string   `json:"approver"`
+	Command     string   `json:"command,omitempty"`
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Reasons     []string `json:"reasons"`
+	Fingerprint string   `json:"fingerprint"`
+	ExpiresAt   string   `json:"expires_at"`
+	Token       string   `json:"token"`
+	TokenFile   string   `json:"token_file,omitempty"`
+}
+
+type approvalKeygenResult struct {
+	Operator  string `json:"operator"`
+	KeyPath   string `json:"key_path"`
+	PublicKey string `json:"public_key"`
+}
+
+// ConfigureApprovalGate installs the two-person approval guard when an
+// approval policy exists. tokens are the --approval-token values: a token or
+// the path of a file holding one.
+func ConfigureApprovalGate(tokens []string) error {
+	return metacli.InstallApprovalGate(tokens, approvalNow)
+}
+
+func resolveApprovalPath(env string, fallback func() (string, error)) (string, error) {
+	if envPath := strings.TrimSpace(os.Getenv(env)); envPath != "" {
+		return envPath, nil
+	}
+	return fallback()
+}
+
+func NewApproveCommand(runtime Runtime) *cobra.Command {
+	var tokenFile string
+
+	cmd := &cobra.Command{
+		Use:   "approve <request-file>",
+		Short: "Approve another operator's high-risk mutation request",
+		Long: "Mutations covered by the approval policy (~/.meta/approval/policy.yaml, or $" + approvalPolicyPathEnv + ") fail closed and\n" +
+			"write a signed approval request. A second operator reviews it and signs it with `meta approve`; the requester\n" +
+			"then reruns the original command with --approval-token.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy, identity, err := loadApprovalOperator()
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta approve", err)
+			}
+			request, err := approval.LoadRequest(args[0])
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta approve", err)
+			}
+			granted, token, err := approval.Approve(policy, identity, request, approvalNow())
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta approve", err)
+			}
+			result := approveResult{
+				RequestID:   granted.RequestID,
+				Requester:   granted.Requester,
+				Approver:    granted.Approver,
+				Command:     request.Command,
+				Method:      request.Operation.Method,
+				Path:        request.Operation.Path,
+				Reasons:     request.Reasons,
+				Fingerprint: granted.Fingerprint,
+				ExpiresAt:   granted.ExpiresAt,
+				Token:       token,
+			}
+			if path := strings.TrimSpace(tokenFile); path != "" {
+				if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+					return writeCommandError(cmd, runtime, "meta approve", fmt.Errorf("write approval token %s: %w", path, err))
+				}
+				result.TokenFile = path
+			}
+			return writeSuccess(cmd, runtime, "meta approve", result, nil, nil)
+		},
+	}
+	cmd.Flags().StringVar(&tokenFile, "token-file", "", "Also write the approval token to this file")
+	cmd.AddCommand(newApproveKeygenCommand(runtime))
+	return cmd
+}
+
+func newApproveKeygenCommand(runtime Runtime) *cobra.Command {
+	var operator string
+
+	cmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Create this operator's approval signing key",
+		Long:  "Create an ed25519 signing key (~/.meta/approval/operator.key, or $" + approvalKeyPathEnv + ") and print its public key for the operators section of the approval policy.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			keyPath, err := resolveApprovalPath(approvalKeyPathEnv, approval.DefaultKeyPath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta approve keygen", err)
+			}
+			publicKey, err := approval.GenerateIdentity(keyPath, operator)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta approve keygen", err)
+			}
+			return writeSuccess(cmd, runtime, "meta approve keygen", approvalKeygenResult{
+				Operator:  strings.TrimSpace(operator),
+				KeyPath:   keyPath,
+				PublicKey: publicKey,
+			}, nil, nil)
+		},
+	}
+	cmd.Flags().StringVar(&operator, "operator", "", "Operator name as listed in the approval policy")
+	mustMarkFlagRequired(cmd, "operator")
+	return cmd
+}
+
+func loadApprovalOperator() (*approval.Policy, *approval.Identity, error) {
+	policyPath, err := resolveApprovalPath(approvalPolicyPathEnv, approval.DefaultPolicyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	policy, err := approval.LoadPolicy(policyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if policy == nil {
+		return nil, nil, errors.New("no approval policy at " + policyPath)
+	}
+	keyPath, err := resolveApprovalPath(approvalKeyPathEnv, approval.DefaultKeyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	identity, err := approval.LoadIdentity(keyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return policy, identity, nil
+}
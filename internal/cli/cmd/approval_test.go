@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestApprovalGateRequiresSecondOperator(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(approvalPolicyPathEnv, filepath.Join(dir, "policy.yaml"))
+	t.Setenv(approvalRequestDirEnv, filepath.Join(dir, "requests"))
+	t.Cleanup(func() { graph.SetMutationGuard("approval", nil) })
+
+	run := func(args ...string) (map[string]any, error) {
+		t.Helper()
+		output := &bytes.Buffer{}
+		cmd := NewApproveCommand(testRuntime(""))
+		cmd.SetOut(output)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			return nil, err
+		}
+		return decodeEnvelope(t, output.Bytes())["data"].(map[string]any), nil
+	}
+
+	publicKeys := map[string]string{}
+	for _, name := range []string{"alice", "bob"} {
+		t.Setenv(approvalKeyPathEnv, filepath.Join(dir, name+".key"))
+		data, err := run("keygen", "--operator", name)
+		if err != nil {
+			t.Fatalf("keygen %s: %v", name, err)
+		}
+		publicKeys[name] = data["public_key"].(string)
+	}
+	policy := "schema_version: 1\noperators:\n  alice: " + publicKeys["alice"] + "\n  bob: " + publicKeys["bob"] + "\nrules:\n  delete: true\n"
+	if err := os.WriteFile(filepath.Join(dir, "policy.yaml"), []byte(policy), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+
+	stub := &stubHTTPClient{t: t, statusCode: http.StatusOK, response: `{"success":true}`}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+	deleteCampaign := func() error {
+		_, err := client.Do(context.Background(), graph.Request{Method: http.MethodDelete, Path: "777", AccessToken: "test-token"})
+		return err
+	}
+
+	t.Setenv(approvalKeyPathEnv, filepath.Join(dir, "alice.key"))
+	if err := ConfigureApprovalGate(nil); err != nil {
+		t.Fatalf("configure gate: %v", err)
+	}
+	err := deleteCampaign()
+	apiErr := &graph.APIError{}
+	if !errors.As(err, &apiErr) || apiErr.Type != "approval_required_gate" || stub.calls != 0 {
+		t.Fatalf("expected delete to be blocked before sending, got %v (calls=%d)", err, stub.calls)
+	}
+	requestFile := apiErr.Diagnostics["request_file"].(string)
+
+	if _, err := run(requestFile); err == nil {
+		t.Fatal("expected the requester to be unable to approve their own request")
+	}
+
+	t.Setenv(approvalKeyPathEnv, filepath.Join(dir, "bob.key"))
+	tokenFile := filepath.Join(dir, "approval.token")
+	approved, err := run(requestFile, "--token-file", tokenFile)
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if approved["approver"] != "bob" || approved["requester"] != "alice" || approved["method"] != http.MethodDelete {
+		t.Fatalf("unexpected approval %#v", approved)
+	}
+
+	t.Setenv(approvalKeyPathEnv, filepath.Join(dir, "alice.key"))
+	if err := ConfigureApprovalGate([]string{tokenFile}); err != nil {
+		t.Fatalf("configure gate: %v", err)
+	}
+	if err := deleteCampaign(); err != nil || stub.calls != 1 {
+		t.Fatalf("expected approved delete to be sent, got %v (calls=%d)", err, stub.calls)
+	}
+}
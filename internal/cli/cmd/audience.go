@@ -67,6 +67,8 @@ func NewAudienceCommand(runtime Runtime) *cobra.Command {
 	audienceCmd.AddCommand(newAudienceDeleteCommand(runtime))
 	audienceCmd.AddCommand(newAudienceListCommand(runtime))
 	audienceCmd.AddCommand(newAudienceGetCommand(runtime))
+	audienceCmd.AddCommand(newAudienceShareCommand(runtime))
+	audienceCmd.AddCommand(newAudienceUploadUsersCommand(runtime))
 	return audienceCmd
 }
 
@@ -75,11 +77,15 @@ func newAudienceCreateCommand(runtime Runtime) *cobra.Command {
 		profile      string
 		version      string
 		accountID    string
+		accountName  string
 		kind         string
 		paramsRaw    string
 		jsonRaw      string
 		schemaDir    string
 		domainPolicy string
+		lookalikeOf  string
+		ratio        float64
+		country      string
 	)
 
 	cmd := &cobra.Command{
@@ -95,6 +101,9 @@ func newAudienceCreateCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta audience create", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta audience create", err)
+			}
 			proceed, err := enforceMarketingDomainGate(cmd, runtime, "meta audience create", domainPolicy, creds.Profile.Domain)
 			if err != nil {
 				return err
@@ -128,6 +137,9 @@ func newAudienceCreateCommand(runtime Runtime) *cobra.Command {
 					return writeCommandError(cmd, runtime, "meta audience create", err)
 				}
 			}
+			if err := applyAudienceLookalikeParams(cmd, form, normalizedKind, lookalikeOf, ratio, country); err != nil {
+				return writeCommandError(cmd, runtime, "meta audience create", err)
+			}
 
 			result, err := audienceNewService(audienceNewGraphClient()).Create(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.AudienceCreateInput{
 				AccountID: accountID,
@@ -159,11 +171,15 @@ func newAudienceCreateCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&kind, "kind", marketing.AudienceListKindCustom, "Audience kind for create: custom|saved")
 	cmd.Flags().StringVar(&paramsRaw, "params", "", "Comma-separated mutation params (k=v,k2=v2)")
 	cmd.Flags().StringVar(&jsonRaw, "json", "", "Inline JSON object payload")
 	cmd.Flags().StringVar(&schemaDir, "schema-dir", schema.DefaultSchemaDir(), "Schema pack root directory")
 	cmd.Flags().StringVar(&domainPolicy, "domain-policy", domainGatePolicyStrict, "Domain gating policy for non-marketing profiles: strict|skip")
+	cmd.Flags().StringVar(&lookalikeOf, "lookalike-of", "", "Create a lookalike of this custom audience id")
+	cmd.Flags().Float64Var(&ratio, "ratio", marketing.AudienceLookalikeMinRatio, "Lookalike population ratio (0.01-0.20)")
+	cmd.Flags().StringVar(&country, "country", "", "Lookalike target country code (for example US)")
 	return cmd
 }
 
@@ -220,8 +236,9 @@ func newAudienceUpdateCommand(runtime Runtime) *cobra.Command {
 			}
 
 			result, err := audienceNewService(audienceNewGraphClient()).Update(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.AudienceUpdateInput{
-				AudienceID: audienceID,
-				Params:     form,
+				AudienceID:  audienceID,
+				Params:      form,
+				DiffPreview: marketing.DiffPreview{PreviewDiff: true},
 			})
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta audience update", err)
@@ -243,10 +260,11 @@ func newAudienceUpdateCommand(runtime Runtime) *cobra.Command {
 
 func newAudienceDeleteCommand(runtime Runtime) *cobra.Command {
 	var (
-		profile      string
-		version      string
-		audienceID   string
-		domainPolicy string
+		profile       string
+		version       string
+		audienceID    string
+		domainPolicy  string
+		confirmDelete bool
 	)
 
 	cmd := &cobra.Command{
@@ -257,6 +275,9 @@ func newAudienceDeleteCommand(runtime Runtime) *cobra.Command {
 			if err := validateDomainGatePolicy(domainPolicy); err != nil {
 				return writeCommandError(cmd, runtime, "meta audience delete", err)
 			}
+			if err := enforceAudienceDeleteGuardrail(audienceID, confirmDelete); err != nil {
+				return writeCommandError(cmd, runtime, "meta audience delete", err)
+			}
 
 			creds, resolvedVersion, err := resolveAudienceProfileAndVersion(runtime, profile, version)
 			if err != nil {
@@ -285,6 +306,57 @@ func newAudienceDeleteCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&audienceID, "audience-id", "", "Audience id")
 	cmd.Flags().StringVar(&domainPolicy, "domain-policy", domainGatePolicyStrict, "Domain gating policy for non-marketing profiles: strict|skip")
+	cmd.Flags().BoolVar(&confirmDelete, "confirm-delete", false, "Acknowledge that deleting the audience is irreversible")
+	return cmd
+}
+
+func newAudienceShareCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile       string
+		version       string
+		audienceID    string
+		accountIDsRaw string
+		domainPolicy  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "share",
+		Short: "Share a custom audience with other ad accounts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := validateDomainGatePolicy(domainPolicy); err != nil {
+				return writeCommandError(cmd, runtime, "meta audience share", err)
+			}
+
+			creds, resolvedVersion, err := resolveAudienceProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta audience share", err)
+			}
+			proceed, err := enforceMarketingDomainGate(cmd, runtime, "meta audience share", domainPolicy, creds.Profile.Domain)
+			if err != nil {
+				return err
+			}
+			if !proceed {
+				return nil
+			}
+
+			result, err := audienceNewService(audienceNewGraphClient()).Share(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.AudienceShareInput{
+				AudienceID: audienceID,
+				AccountIDs: csvToSlice(accountIDsRaw),
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta audience share", err)
+			}
+
+			return writeSuccess(cmd, runtime, "meta audience share", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&audienceID, "audience-id", "", "Audience id")
+	cmd.Flags().StringVar(&accountIDsRaw, "account-ids", "", "Comma-separated ad account ids to share with (with or without act_ prefix)")
+	cmd.Flags().StringVar(&domainPolicy, "domain-policy", domainGatePolicyStrict, "Domain gating policy for non-marketing profiles: strict|skip")
 	return cmd
 }
 
@@ -293,6 +365,7 @@ func newAudienceListCommand(runtime Runtime) *cobra.Command {
 		profile      string
 		version      string
 		accountID    string
+		accountName  string
 		kind         string
 		fieldsRaw    string
 		limit        int
@@ -313,6 +386,9 @@ func newAudienceListCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta audience list", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta audience list", err)
+			}
 			proceed, err := enforceMarketingDomainGate(cmd, runtime, "meta audience list", domainPolicy, creds.Profile.Domain)
 			if err != nil {
 				return err
@@ -352,6 +428,7 @@ func newAudienceListCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&kind, "kind", marketing.AudienceListKindAll, "Audience kind: all|custom|saved")
 	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated Graph fields (defaults to audience read fields)")
 	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of audiences to return")
@@ -469,6 +546,34 @@ func lintAudienceMutation(linter *lint.Linter, params map[string]string) error {
 	return nil
 }
 
+func applyAudienceLookalikeParams(cmd *cobra.Command, form map[string]string, kind string, lookalikeOf string, ratio float64, country string) error {
+	if strings.TrimSpace(lookalikeOf) == "" {
+		if cmd.Flags().Changed("ratio") || cmd.Flags().Changed("country") {
+			return errors.New("--ratio and --country require --lookalike-of")
+		}
+		return nil
+	}
+	if kind != marketing.AudienceListKindCustom {
+		return errors.New("--lookalike-of requires --kind custom")
+	}
+	params, err := marketing.AudienceLookalikeSpec{
+		OriginAudienceID: lookalikeOf,
+		Ratio:            ratio,
+		Country:          country,
+	}.Params()
+	if err != nil {
+		return err
+	}
+	return mergeParams(form, params, "--lookalike-of")
+}
+
+func enforceAudienceDeleteGuardrail(audienceID string, confirmed bool) error {
+	if confirmed || strings.TrimSpace(audienceID) == "" {
+		return nil
+	}
+	return fmt.Errorf("deleting audience %s is irreversible; rerun with --confirm-delete", strings.TrimSpace(audienceID))
+}
+
 func normalizeAudienceCreateCommandKind(kind string) (string, error) {
 	switch strings.ToLower(strings.TrimSpace(kind)) {
 	case "", marketing.AudienceListKindCustom:
@@ -558,7 +663,7 @@ func writeDomainGateBlockedError(cmd *cobra.Command, runtime Runtime, commandNam
 	if envErr != nil {
 		return fmt.Errorf("%w (secondary output error: %v)", err, envErr)
 	}
-	if writeErr := output.Write(cmd.ErrOrStderr(), selectedOutputFormat(runtime), envelope); writeErr != nil {
+	if writeErr := writeEnvelope(cmd.ErrOrStderr(), runtime, envelope); writeErr != nil {
 		return fmt.Errorf("%w (secondary output error: %v)", err, writeErr)
 	}
 	return err
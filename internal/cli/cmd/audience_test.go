@@ -21,7 +21,7 @@ func TestNewAudienceCommandIncludesLifecycleSubcommands(t *testing.T) {
 
 	cmd := NewAudienceCommand(Runtime{})
 
-	for _, name := range []string{"create", "update", "delete", "list", "get"} {
+	for _, name := range []string{"create", "update", "delete", "list", "get", "share", "upload-users"} {
 		sub, _, err := cmd.Find([]string{name})
 		if err != nil {
 			t.Fatalf("find %s subcommand: %v", name, err)
@@ -508,6 +508,7 @@ func TestAudienceDeleteExecutesMutation(t *testing.T) {
 	cmd.SetArgs([]string{
 		"delete",
 		"--audience-id", "aud_777",
+		"--confirm-delete",
 	})
 
 	if err := cmd.Execute(); err != nil {
@@ -1140,3 +1141,166 @@ func writeAudienceSchemaPack(t *testing.T) string {
 	}
 	return schemaDir
 }
+
+func TestAudienceCreateBuildsLookalikeParams(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"id":"aud_lal_1"}`,
+	}
+	schemaDir := writeAudienceSchemaPack(t)
+	useAudienceDependencies(t, audienceTestCredentials, func() *graph.Client {
+		client := graph.NewClient(stub, "https://graph.example.com")
+		client.MaxRetries = 0
+		return client
+	})
+
+	output := &bytes.Buffer{}
+	cmd := NewAudienceCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"create",
+		"--account-id", "act_1234",
+		"--params", "name=VIP Lookalike 2%",
+		"--lookalike-of", "aud_991",
+		"--ratio", "0.02",
+		"--country", "us",
+		"--schema-dir", schemaDir,
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute audience create: %v", err)
+	}
+
+	form, err := url.ParseQuery(stub.lastBody)
+	if err != nil {
+		t.Fatalf("parse request body: %v", err)
+	}
+	if form.Get("subtype") != "LOOKALIKE" || form.Get("origin_audience_id") != "aud_991" {
+		t.Fatalf("unexpected lookalike form %v", form)
+	}
+	if got := form.Get("lookalike_spec"); got != `{"country":"US","ratio":0.02}` {
+		t.Fatalf("unexpected lookalike_spec %q", got)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta audience create")
+}
+
+func TestAudienceCreateRejectsLookalikeRatioOutOfRange(t *testing.T) {
+	schemaDir := writeAudienceSchemaPack(t)
+	useAudienceDependencies(t, audienceTestCredentials, func() *graph.Client {
+		t.Fatal("graph client should not be constructed for invalid lookalike input")
+		return nil
+	})
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewAudienceCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{
+		"create",
+		"--account-id", "act_1234",
+		"--params", "name=Too broad",
+		"--lookalike-of", "aud_991",
+		"--ratio", "0.5",
+		"--country", "US",
+		"--schema-dir", schemaDir,
+	})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "lookalike ratio must be between") {
+		t.Fatalf("expected ratio error, got %v", err)
+	}
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	if got := envelope["command"]; got != "meta audience create" {
+		t.Fatalf("unexpected command field %v", got)
+	}
+}
+
+func TestAudienceDeleteRequiresConfirmation(t *testing.T) {
+	useAudienceDependencies(t, audienceTestCredentials, func() *graph.Client {
+		t.Fatal("graph client should not be constructed without --confirm-delete")
+		return nil
+	})
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewAudienceCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"delete", "--audience-id", "aud_777"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--confirm-delete") {
+		t.Fatalf("expected confirmation error, got %v", err)
+	}
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	if got := envelope["command"]; got != "meta audience delete" {
+		t.Fatalf("unexpected command field %v", got)
+	}
+}
+
+func TestAudienceShareExecutesMutation(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"success":true}`,
+	}
+	useAudienceDependencies(t, audienceTestCredentials, func() *graph.Client {
+		client := graph.NewClient(stub, "https://graph.example.com")
+		client.MaxRetries = 0
+		return client
+	})
+
+	output := &bytes.Buffer{}
+	cmd := NewAudienceCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"share",
+		"--audience-id", "aud_991",
+		"--account-ids", "act_111,222,act_111",
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute audience share: %v", err)
+	}
+
+	requestURL, err := url.Parse(stub.lastURL)
+	if err != nil {
+		t.Fatalf("parse request url: %v", err)
+	}
+	if requestURL.Path != "/v25.0/aud_991/adaccounts" || stub.lastMethod != http.MethodPost {
+		t.Fatalf("unexpected share request %s %s", stub.lastMethod, requestURL.Path)
+	}
+	form, err := url.ParseQuery(stub.lastBody)
+	if err != nil {
+		t.Fatalf("parse request body: %v", err)
+	}
+	if got := form.Get("adaccounts"); got != `["111","222"]` {
+		t.Fatalf("unexpected adaccounts %q", got)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta audience share")
+	data, _ := envelope["data"].(map[string]any)
+	if data["operation"] != "share" || data["audience_id"] != "aud_991" {
+		t.Fatalf("unexpected share payload %#v", data)
+	}
+}
+
+func audienceTestCredentials(string) (*ProfileCredentials, error) {
+	return &ProfileCredentials{
+		Name: "prod",
+		Profile: config.Profile{
+			Domain:       config.DefaultDomain,
+			GraphVersion: config.DefaultGraphVersion,
+		},
+		Token: "test-token",
+	}, nil
+}
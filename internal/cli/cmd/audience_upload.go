@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/checkpoint"
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/spf13/cobra"
+)
+
+var audienceNewUploadSessionID = func() (int64, error) {
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return 0, fmt.Errorf("generate audience upload session id: %w", err)
+	}
+	return int64(binary.BigEndian.Uint64(raw)>>1) + 1, nil
+}
+
+var audienceUploadStatsFields = []string{
+	"id",
+	"name",
+	"approximate_count_lower_bound",
+	"approximate_count_upper_bound",
+	"operation_status",
+}
+
+type audienceUploadUsersCommandResult struct {
+	*marketing.AudienceUserUploadResult
+	Audience map[string]any `json:"audience,omitempty"`
+}
+
+func newAudienceUploadUsersCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile        string
+		version        string
+		audienceID     string
+		filePath       string
+		schemaRaw      string
+		noHeader       bool
+		batchSize      int
+		concurrency    int
+		sessionID      int64
+		skipStats      bool
+		domainPolicy   string
+		checkpointOpts checkpointFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "upload-users",
+		Short: "Upload a customer file to a custom audience (normalized and SHA-256 hashed locally)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := validateDomainGatePolicy(domainPolicy); err != nil {
+				return writeCommandError(cmd, runtime, "meta audience upload-users", err)
+			}
+			if concurrency < 1 {
+				return writeCommandError(cmd, runtime, "meta audience upload-users", errors.New("--concurrency must be > 0"))
+			}
+
+			payload, err := readAudienceCustomerFile(filePath, csvToSlice(schemaRaw), !noHeader)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta audience upload-users", err)
+			}
+			creds, resolvedVersion, err := resolveAudienceProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta audience upload-users", err)
+			}
+			proceed, err := enforceMarketingDomainGate(cmd, runtime, "meta audience upload-users", domainPolicy, creds.Profile.Domain)
+			if err != nil {
+				return err
+			}
+			if !proceed {
+				return nil
+			}
+
+			fingerprint, err := checkpoint.FileFingerprint(filePath, audienceID, schemaRaw, strconv.FormatBool(noHeader), strconv.Itoa(batchSize))
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta audience upload-users", err)
+			}
+			runCheckpoint, err := checkpointOpts.open(checkpoint.DefaultPath(filePath), "audience_upload_users", fingerprint)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta audience upload-users", err)
+			}
+			if sessionID, err = resolveAudienceUploadSession(runCheckpoint, sessionID); err != nil {
+				return writeCommandError(cmd, runtime, "meta audience upload-users", err)
+			}
+
+			service := audienceNewService(governedClient(audienceNewGraphClient(), concurrency))
+			uploadProgress := startProgress(cmd, runtime, "audience upload-users", 0)
+			uploadProgress.Bar().SetUnit("batches")
+			result, err := service.UploadUsers(uploadProgress.Context(cmd.Context()), resolvedVersion, creds.Token, creds.AppSecret, marketing.AudienceUserUploadInput{
+				AudienceID:  audienceID,
+				Payload:     payload,
+				SessionID:   sessionID,
+				BatchSize:   batchSize,
+				Concurrency: concurrency,
+				OnProgress:  uploadProgress.Work,
+				Checkpoint:  runCheckpoint,
+			})
+			uploadProgress.Finish()
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta audience upload-users", err)
+			}
+			if err := runCheckpoint.Remove(); err != nil {
+				return writeCommandError(cmd, runtime, "meta audience upload-users", err)
+			}
+
+			payloadResult := audienceUploadUsersCommandResult{AudienceUserUploadResult: result}
+			if !skipStats {
+				audience, err := service.Get(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.AudienceGetInput{
+					AudienceID: result.AudienceID,
+					Fields:     audienceUploadStatsFields,
+				})
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta audience upload-users", err)
+				}
+				payloadResult.Audience = audience.Audience
+			}
+			return writeSuccess(cmd, runtime, "meta audience upload-users", payloadResult, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&audienceID, "audience-id", "", "Custom audience id")
+	cmd.Flags().StringVar(&filePath, "file", "", "Customer CSV file path")
+	cmd.Flags().StringVar(&schemaRaw, "schema", "", "Comma-separated column keys in file order (email,phone,fn,ln,ct,st,zip,country,gen,doby,dobm,dobd,madid,extern_id)")
+	cmd.Flags().BoolVar(&noHeader, "no-header", false, "Treat the first CSV row as data instead of a header")
+	cmd.Flags().IntVar(&batchSize, "batch-size", marketing.AudienceUserMaxBatchSize, "Rows per upload request (max 10000)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "Upload requests sent in parallel (the closing batch is always sent last); throttled by rate-limit usage")
+	cmd.Flags().Int64Var(&sessionID, "session-id", 0, "Upload session id (defaults to a random id)")
+	cmd.Flags().BoolVar(&skipStats, "skip-audience-stats", false, "Skip reading audience size estimates after upload")
+	cmd.Flags().StringVar(&domainPolicy, "domain-policy", domainGatePolicyStrict, "Domain gating policy for non-marketing profiles: strict|skip")
+	addCheckpointFlags(cmd, &checkpointOpts, "<file>.checkpoint.json")
+	return cmd
+}
+
+// resolveAudienceUploadSession keeps a resumed upload in the session of the
+// interrupted run; Meta only appends batches to the session that opened them.
+func resolveAudienceUploadSession(runCheckpoint *checkpoint.Checkpoint, sessionID int64) (int64, error) {
+	if recorded := runCheckpoint.Attribute("session_id"); recorded != "" {
+		resumed, err := strconv.ParseInt(recorded, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid session_id %q in checkpoint %s", recorded, runCheckpoint.Path())
+		}
+		if sessionID > 0 && sessionID != resumed {
+			return 0, fmt.Errorf("--session-id %d does not match session %d of the checkpointed upload", sessionID, resumed)
+		}
+		return resumed, nil
+	}
+	if sessionID <= 0 {
+		var err error
+		sessionID, err = audienceNewUploadSessionID()
+		if err != nil {
+			return 0, err
+		}
+	}
+	if err := runCheckpoint.SetAttribute("session_id", strconv.FormatInt(sessionID, 10)); err != nil {
+		return 0, err
+	}
+	return sessionID, nil
+}
+
+func readAudienceCustomerFile(path string, schema []string, hasHeader bool) (*marketing.AudienceUserPayload, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, errors.New("--file is required")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open customer file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records := make([][]string, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read customer file %s: %w", path, err)
+		}
+		records = append(records, record)
+	}
+	if hasHeader && len(records) > 0 {
+		records = records[1:]
+	}
+	return marketing.PrepareAudienceUsers(schema, records)
+}
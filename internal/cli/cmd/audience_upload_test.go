@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestAudienceUploadUsersHashesLocallyAndReportsStats(t *testing.T) {
+	stub := &igPublishSequenceHTTPClient{
+		t: t,
+		responses: []igPublishSequenceResponse{
+			{statusCode: http.StatusOK, response: `{"audience_id":"aud_9","session_id":77,"num_received":1,"num_invalid_entries":0}`},
+			{statusCode: http.StatusOK, response: `{"audience_id":"aud_9","session_id":77,"num_received":1,"num_invalid_entries":1,"invalid_entry_samples":{"row":"PHONE"}}`},
+			{statusCode: http.StatusOK, response: `{"id":"aud_9","approximate_count_lower_bound":1000,"approximate_count_upper_bound":1200}`},
+		},
+	}
+	useAudienceDependencies(t, audienceTestCredentials, func() *graph.Client {
+		client := graph.NewClient(stub, "https://graph.example.com")
+		client.MaxRetries = 0
+		return client
+	})
+	original := audienceNewUploadSessionID
+	t.Cleanup(func() { audienceNewUploadSessionID = original })
+	audienceNewUploadSessionID = func() (int64, error) { return 77, nil }
+
+	filePath := filepath.Join(t.TempDir(), "customers.csv")
+	if err := os.WriteFile(filePath, []byte("email,phone\nJane.Doe@Example.com,+1 555 123 4567\njohn@example.com,\n,\n"), 0o600); err != nil {
+		t.Fatalf("write customer file: %v", err)
+	}
+
+	output := &bytes.Buffer{}
+	cmd := NewAudienceCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"upload-users",
+		"--audience-id", "aud_9",
+		"--file", filePath,
+		"--schema", "email,phone",
+		"--batch-size", "1",
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute audience upload-users: %v", err)
+	}
+
+	if len(stub.calls) != 3 {
+		t.Fatalf("expected 2 upload batches and 1 stats read, got %d calls", len(stub.calls))
+	}
+	for _, call := range stub.calls[:2] {
+		if call.method != http.MethodPost || !strings.Contains(call.url, "/v25.0/aud_9/users") {
+			t.Fatalf("unexpected upload request %s %s", call.method, call.url)
+		}
+		lower := strings.ToLower(call.body)
+		if strings.Contains(lower, "example.com") || strings.Contains(lower, "5551234567") {
+			t.Fatalf("upload body contains raw PII: %s", call.body)
+		}
+	}
+	if !strings.Contains(stub.calls[0].body, "86e0b9e56c17cc4d12387e1949b85053fbe73bc3ce5a1188713a9d300cc6133d") {
+		t.Fatalf("expected hashed email in first batch: %s", stub.calls[0].body)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta audience upload-users")
+	data, _ := envelope["data"].(map[string]any)
+	prepared, _ := data["prepared"].(map[string]any)
+	audience, _ := data["audience"].(map[string]any)
+	if data["session_id"] != float64(77) || data["num_received"] != float64(2) || data["num_invalid_entries"] != float64(1) {
+		t.Fatalf("unexpected upload summary %#v", data)
+	}
+	if prepared["rows_read"] != float64(3) || prepared["rows_prepared"] != float64(2) || prepared["rows_skipped"] != float64(1) {
+		t.Fatalf("unexpected prepare stats %#v", prepared)
+	}
+	if audience["approximate_count_lower_bound"] != float64(1000) {
+		t.Fatalf("unexpected audience stats %#v", audience)
+	}
+}
+
+func TestAudienceUploadUsersRejectsUnknownSchemaBeforeNetwork(t *testing.T) {
+	useAudienceDependencies(t, audienceTestCredentials, func() *graph.Client {
+		t.Fatal("graph client should not be constructed for an invalid schema")
+		return nil
+	})
+
+	filePath := filepath.Join(t.TempDir(), "customers.csv")
+	if err := os.WriteFile(filePath, []byte("email,ssn\na@example.com,123\n"), 0o600); err != nil {
+		t.Fatalf("write customer file: %v", err)
+	}
+
+	cmd := NewAudienceCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"upload-users", "--audience-id", "aud_9", "--file", filePath, "--schema", "email,ssn"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), `unsupported audience user schema key "ssn"`) {
+		t.Fatalf("expected schema error, got %v", err)
+	}
+}
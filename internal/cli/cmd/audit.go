@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/bilalbayram/metacli/pkg/metacli"
+	"github.com/spf13/cobra"
+)
+
+const auditLogPathEnv = metacli.AuditLogPathEnv
+
+var auditNow = time.Now
+
+// ConfigureAuditLog tags the command context with the invocation and installs
+// the recorder that appends every executed Graph mutation to the audit log.
+// A failed append is reported on stderr; the mutation has already happened, so
+// it never fails the command.
+func ConfigureAuditLog(cmd *cobra.Command) {
+	profile := ""
+	if flag := cmd.Flags().Lookup("profile"); flag != nil {
+		profile = strings.TrimSpace(flag.Value.String())
+	}
+	if profile == "" {
+		profile = defaultProfileName()
+	}
+	breakGlass := ""
+	if flag := cmd.Flags().Lookup("break-glass"); flag != nil {
+		breakGlass = strings.TrimSpace(flag.Value.String())
+	}
+	anomalyOverride := ""
+	if flag := cmd.Flags().Lookup("override-anomaly"); flag != nil {
+		anomalyOverride = strings.TrimSpace(flag.Value.String())
+	}
+	cmd.SetContext(audit.WithInvocation(cmd.Context(), audit.Invocation{
+		Command:         cmd.CommandPath(),
+		Profile:         profile,
+		BreakGlass:      breakGlass,
+		AnomalyOverride: anomalyOverride,
+	}))
+
+	metacli.InstallAuditLog(cmd.ErrOrStderr(), auditNow)
+}
+
+func resolveAuditLogPath() (string, error) {
+	return metacli.AuditLogPath()
+}
+
+func NewAuditCommand(runtime Runtime) *cobra.Command {
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the local log of executed mutations",
+		Long: "Every POST and DELETE sent to the Graph API is appended to a hash-chained JSONL log\n" +
+			"(~/.meta/audit/mutations.jsonl, or $" + auditLogPathEnv + ").",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "audit")
+		},
+	}
+	auditCmd.AddCommand(newAuditListCommand(runtime))
+	auditCmd.AddCommand(newAuditVerifyCommand(runtime))
+	auditCmd.AddCommand(newAuditExportCommand(runtime))
+	return auditCmd
+}
+
+type auditFilterFlags struct {
+	path    string
+	since   string
+	command string
+	profile string
+	target  string
+	result  string
+}
+
+func (f *auditFilterFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.path, "file", "", "Audit log path (defaults to $"+auditLogPathEnv+", then ~/.meta/audit/mutations.jsonl)")
+	cmd.Flags().StringVar(&f.since, "since", "", "Only entries at or after this time (RFC3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&f.command, "command", "", "Only entries whose command contains this text")
+	cmd.Flags().StringVar(&f.profile, "profile", "", "Only entries recorded for this profile")
+	cmd.Flags().StringVar(&f.target, "target", "", "Only entries that touched this object id")
+	cmd.Flags().StringVar(&f.result, "result", "", "Only entries with this result: success|error")
+}
+
+func (f *auditFilterFlags) load(limit int) ([]audit.Entry, error) {
+	path, err := f.resolvePath()
+	if err != nil {
+		return nil, err
+	}
+	since, err := parseCAPIStatsTime("--since", f.since)
+	if err != nil {
+		return nil, err
+	}
+	result := strings.TrimSpace(f.result)
+	if result != "" && result != audit.ResultSuccess && result != audit.ResultError {
+		return nil, fmt.Errorf("invalid --result %q: expected %s|%s", f.result, audit.ResultSuccess, audit.ResultError)
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("--limit must be >= 0")
+	}
+	entries, err := audit.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	return audit.Filter{
+		Since:   since,
+		Command: strings.TrimSpace(f.command),
+		Profile: strings.TrimSpace(f.profile),
+		Target:  strings.TrimSpace(f.target),
+		Result:  result,
+		Limit:   limit,
+	}.Apply(entries), nil
+}
+
+func (f *auditFilterFlags) resolvePath() (string, error) {
+	if path := strings.TrimSpace(f.path); path != "" {
+		return path, nil
+	}
+	return resolveAuditLogPath()
+}
+
+func newAuditListCommand(runtime Runtime) *cobra.Command {
+	var (
+		filters auditFilterFlags
+		limit   int
+	)
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recorded mutations, oldest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			entries, err := filters.load(limit)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta audit list", err)
+			}
+			return writeSuccess(cmd, runtime, "meta audit list", entries, nil, nil)
+		},
+	}
+	filters.register(cmd)
+	cmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of most recent entries to show (0 for all)")
+	return cmd
+}
+
+func newAuditVerifyCommand(runtime Runtime) *cobra.Command {
+	var path string
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check that the audit log hash chain is intact",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			filters := auditFilterFlags{path: path}
+			resolvedPath, err := filters.resolvePath()
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta audit verify", err)
+			}
+			result, err := audit.Verify(resolvedPath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta audit verify", err)
+			}
+			if !result.Valid {
+				return writeAuditChainBrokenError(cmd, runtime, result)
+			}
+			return writeSuccess(cmd, runtime, "meta audit verify", result, nil, nil)
+		},
+	}
+	cmd.Flags().StringVar(&path, "file", "", "Audit log path (defaults to $"+auditLogPathEnv+", then ~/.meta/audit/mutations.jsonl)")
+	return cmd
+}
+
+func newAuditExportCommand(runtime Runtime) *cobra.Command {
+	var (
+		filters auditFilterFlags
+		format  string
+		outPath string
+	)
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export recorded mutations as JSONL or CSV",
+		Long:  "Export recorded mutations as JSONL or CSV. Without --out the export is written to stdout as-is, without an envelope.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			entries, err := filters.load(0)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta audit export", err)
+			}
+			outPath = strings.TrimSpace(outPath)
+			if outPath == "" {
+				if err := audit.Export(cmd.OutOrStdout(), entries, format); err != nil {
+					return writeCommandError(cmd, runtime, "meta audit export", err)
+				}
+				return nil
+			}
+			if err := writeAuditExportFile(outPath, entries, format); err != nil {
+				return writeCommandError(cmd, runtime, "meta audit export", err)
+			}
+			return writeSuccess(cmd, runtime, "meta audit export", map[string]any{
+				"file":    outPath,
+				"format":  strings.ToLower(strings.TrimSpace(format)),
+				"entries": len(entries),
+			}, nil, nil)
+		},
+	}
+	filters.register(cmd)
+	cmd.Flags().StringVar(&format, "format", audit.ExportFormatJSONL, "Export format: jsonl|csv")
+	cmd.Flags().StringVar(&outPath, "out", "", "Write the export to this file instead of stdout")
+	return cmd
+}
+
+func writeAuditExportFile(path string, entries []audit.Entry, format string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("create audit export %s: %w", path, err)
+	}
+	if err := audit.Export(file, entries, format); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close audit export %s: %w", path, err)
+	}
+	return nil
+}
+
+func writeAuditChainBrokenError(cmd *cobra.Command, runtime Runtime, result audit.VerifyResult) error {
+	err := fmt.Errorf("audit log %s is broken at line %d: %s", result.Path, result.BrokenLine, result.Reason)
+	errorInfo := &output.ErrorInfo{
+		Type:      "audit_chain_broken",
+		Message:   err.Error(),
+		Retryable: false,
+	}
+
+	envelope, envErr := output.NewEnvelope("meta audit verify", false, result, nil, nil, errorInfo)
+	if envErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, envErr)
+	}
+	if writeErr := writeEnvelope(cmd.ErrOrStderr(), runtime, envelope); writeErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, writeErr)
+	}
+	return err
+}
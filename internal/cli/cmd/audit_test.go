@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+func newAuditTestRoot(runtime Runtime) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "meta",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+			ConfigureAuditLog(cmd)
+		},
+	}
+	root.PersistentFlags().String("profile", "", "Auth profile name")
+	root.AddCommand(NewCampaignCommand(runtime))
+	root.AddCommand(NewAuditCommand(runtime))
+	return root
+}
+
+func TestAuditLogRecordsMutationsAndVerifies(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "mutations.jsonl")
+	t.Setenv(auditLogPathEnv, logPath)
+	t.Cleanup(func() { graph.SetMutationRecorder(nil) })
+
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"success":true}`,
+	}
+	schemaDir := writeCampaignSchemaPack(t)
+	useCampaignDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{Domain: config.DefaultDomain, GraphVersion: config.DefaultGraphVersion},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	run := func(args ...string) (string, error) {
+		t.Helper()
+		output := &bytes.Buffer{}
+		root := newAuditTestRoot(testRuntime("prod"))
+		root.SetOut(output)
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs(args)
+		err := root.Execute()
+		return output.String(), err
+	}
+
+	for _, id := range []string{"777", "888"} {
+		if _, err := run("--profile", "prod", "campaign", "pause", "--campaign-id", id, "--schema-dir", schemaDir); err != nil {
+			t.Fatalf("campaign pause %s: %v", id, err)
+		}
+	}
+
+	listed, err := run("audit", "list", "--target", "888")
+	if err != nil {
+		t.Fatalf("audit list: %v", err)
+	}
+	envelope := decodeEnvelope(t, []byte(listed))
+	assertEnvelopeBasics(t, envelope, "meta audit list")
+	entries, ok := envelope["data"].([]any)
+	if !ok || len(entries) != 1 {
+		t.Fatalf("expected one listed entry, got %#v", envelope["data"])
+	}
+	entry := entries[0].(map[string]any)
+	if entry["command"] != "meta campaign pause" || entry["profile"] != "prod" || entry["method"] != http.MethodPost || entry["result"] != "success" {
+		t.Fatalf("unexpected audit entry %#v", entry)
+	}
+	if entry["sequence"] != float64(2) || entry["prev_hash"] == "" {
+		t.Fatalf("expected second chained entry, got %#v", entry)
+	}
+	if strings.Contains(listed, "test-token") {
+		t.Fatalf("audit entry leaked the access token: %s", listed)
+	}
+
+	verified, err := run("audit", "verify")
+	if err != nil {
+		t.Fatalf("audit verify: %v", err)
+	}
+	result := decodeEnvelope(t, []byte(verified))["data"].(map[string]any)
+	if result["valid"] != true || result["entries"] != float64(2) {
+		t.Fatalf("unexpected verify result %#v", result)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	if err := os.WriteFile(logPath, bytes.Replace(data, []byte(`"777"`), []byte(`"778"`), 1), 0o600); err != nil {
+		t.Fatalf("tamper audit log: %v", err)
+	}
+	if _, err := run("audit", "verify"); err == nil || !strings.Contains(err.Error(), "broken at line 1") {
+		t.Fatalf("expected broken chain error, got %v", err)
+	}
+
+	exported, err := run("audit", "export", "--format", "csv")
+	if err != nil {
+		t.Fatalf("audit export: %v", err)
+	}
+	if rows := strings.Split(strings.TrimSpace(exported), "\n"); len(rows) != 3 || !strings.HasPrefix(rows[0], "sequence,") {
+		t.Fatalf("unexpected csv export %q", exported)
+	}
+}
@@ -30,6 +30,7 @@ type authCLIService interface {
 	ExchangeLongLivedUserToken(context.Context, auth.ExchangeLongLivedUserTokenInput) (auth.LongLivedToken, error)
 	EnsureValid(context.Context, string, time.Duration, []string) (*auth.DebugTokenMetadata, error)
 	ValidateProfile(context.Context, string) (*auth.DebugTokenResponse, error)
+	RecordVerifiedScopes(string, []string, time.Time) error
 	RotateProfile(context.Context, string) error
 	DebugToken(context.Context, string, string, string) (*auth.DebugTokenResponse, error)
 	ListProfiles() (map[string]config.Profile, error)
@@ -112,6 +113,7 @@ func newAuthAddCommand(runtime Runtime) *cobra.Command {
 		appID      string
 		token      string
 		appSecret  string
+		wabaID     string
 	)
 
 	systemUserCmd := &cobra.Command{
@@ -134,13 +136,20 @@ func newAuthAddCommand(runtime Runtime) *cobra.Command {
 				Token:      token,
 				AppSecret:  appSecret,
 				AuthMode:   auth.AuthModeBoth,
-				Scopes:     []string{"ads_management", "business_management"},
+				WABAID:     wabaID,
 			}); err != nil {
 				return err
 			}
+			profiles, err := svc.ListProfiles()
+			if err != nil {
+				return err
+			}
+			added := profiles[resolvedProfile]
 			return writeSuccess(cmd, runtime, "meta auth add system-user", map[string]any{
-				"status":  "ok",
-				"profile": resolvedProfile,
+				"status":          "ok",
+				"profile":         resolvedProfile,
+				"scopes":          added.Scopes,
+				"scopes_verified": added.ScopesVerifiedAt != "",
 			}, nil, nil)
 		},
 	}
@@ -149,6 +158,7 @@ func newAuthAddCommand(runtime Runtime) *cobra.Command {
 	systemUserCmd.Flags().StringVar(&appID, "app-id", "", "Meta App ID")
 	systemUserCmd.Flags().StringVar(&token, "token", "", "System-user access token")
 	systemUserCmd.Flags().StringVar(&appSecret, "app-secret", "", "Meta App Secret")
+	systemUserCmd.Flags().StringVar(&wabaID, "waba-id", "", "Optional WhatsApp Business Account id binding")
 	mustMarkFlagRequired(systemUserCmd, "business-id")
 	mustMarkFlagRequired(systemUserCmd, "app-id")
 	mustMarkFlagRequired(systemUserCmd, "token")
@@ -171,6 +181,7 @@ func newAuthSetupCommand(runtime Runtime) *cobra.Command {
 		openBrowser    bool
 		pageID         string
 		igUserID       string
+		wabaID         string
 		nonInteractive bool
 	)
 
@@ -236,11 +247,13 @@ func newAuthSetupCommand(runtime Runtime) *cobra.Command {
 				}
 			}
 
-			if selectedPageID != "" || selectedIGUserID != "" {
+			selectedWABAID := strings.TrimSpace(wabaID)
+			if selectedPageID != "" || selectedIGUserID != "" || selectedWABAID != "" {
 				if err := svc.UpdateProfileBindings(cmd.Context(), auth.UpdateProfileBindingsInput{
 					Profile:  resolvedProfile,
 					PageID:   selectedPageID,
 					IGUserID: selectedIGUserID,
+					WABAID:   selectedWABAID,
 				}); err != nil {
 					return err
 				}
@@ -258,6 +271,7 @@ func newAuthSetupCommand(runtime Runtime) *cobra.Command {
 				"pages":            pages,
 				"selected_page_id": selectedPageID,
 				"selected_ig_user": selectedIGUserID,
+				"selected_waba_id": selectedWABAID,
 				"non_interactive":  nonInteractive,
 			}, nil, nil)
 		},
@@ -271,10 +285,11 @@ func newAuthSetupCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&scopesRaw, "scopes", "", "Comma-separated OAuth scopes (overrides --scope-pack when set)")
 	cmd.Flags().StringVar(&listenAddr, "listen", defaultAuthListenAddr, "OAuth callback listener host:port")
 	cmd.Flags().StringVar(&redirectURI, "redirect-uri", "", "OAuth redirect URI override (recommended for https tunnel domains)")
-	cmd.Flags().DurationVar(&timeout, "timeout", defaultAuthTimeout, "OAuth callback timeout")
+	cmd.Flags().DurationVar(&timeout, "callback-timeout", defaultAuthTimeout, "How long to wait for the OAuth callback")
 	cmd.Flags().BoolVar(&openBrowser, "open-browser", true, "Open browser automatically")
 	cmd.Flags().StringVar(&pageID, "page-id", "", "Optional page id binding")
 	cmd.Flags().StringVar(&igUserID, "ig-user-id", "", "Optional Instagram user id binding")
+	cmd.Flags().StringVar(&wabaID, "waba-id", "", "Optional WhatsApp Business Account id binding")
 	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Run without interactive prompts")
 	mustMarkFlagRequired(cmd, "app-id")
 	mustMarkFlagRequired(cmd, "app-secret")
@@ -343,7 +358,7 @@ func newAuthLoginCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&scopesRaw, "scopes", "", "Comma-separated OAuth scopes")
 	cmd.Flags().StringVar(&listenAddr, "listen", defaultAuthListenAddr, "OAuth callback listener host:port")
 	cmd.Flags().StringVar(&redirectURI, "redirect-uri", "", "OAuth redirect URI override (recommended for https tunnel domains)")
-	cmd.Flags().DurationVar(&timeout, "timeout", defaultAuthTimeout, "OAuth callback timeout")
+	cmd.Flags().DurationVar(&timeout, "callback-timeout", defaultAuthTimeout, "How long to wait for the OAuth callback")
 	cmd.Flags().BoolVar(&openBrowser, "open-browser", true, "Open browser automatically")
 	mustMarkFlagRequired(cmd, "app-id")
 	mustMarkFlagRequired(cmd, "app-secret")
@@ -418,16 +433,17 @@ func newAuthLoginManualCommand(runtime Runtime) *cobra.Command {
 			}
 
 			if err := svc.AddUser(cmd.Context(), auth.AddUserInput{
-				Profile:         resolvedProfile,
-				AppID:           appID,
-				Token:           longLived.AccessToken,
-				AppSecret:       appSecret,
-				AuthProvider:    auth.AuthProviderFacebookLogin,
-				AuthMode:        auth.AuthModeBoth,
-				Scopes:          metadata.Scopes,
-				IssuedAt:        now.Format(time.RFC3339),
-				ExpiresAt:       expiresAt.Format(time.RFC3339),
-				LastValidatedAt: now.Format(time.RFC3339),
+				Profile:          resolvedProfile,
+				AppID:            appID,
+				Token:            longLived.AccessToken,
+				AppSecret:        appSecret,
+				AuthProvider:     auth.AuthProviderFacebookLogin,
+				AuthMode:         auth.AuthModeBoth,
+				Scopes:           metadata.Scopes,
+				IssuedAt:         now.Format(time.RFC3339),
+				ExpiresAt:        expiresAt.Format(time.RFC3339),
+				LastValidatedAt:  now.Format(time.RFC3339),
+				ScopesVerifiedAt: scopesVerifiedAt(metadata.Scopes, now),
 			}); err != nil {
 				return err
 			}
@@ -601,11 +617,15 @@ func newAuthValidateCommand(runtime Runtime) *cobra.Command {
 		profile       string
 		minTTL        time.Duration
 		requireScopes string
+		selector      string
 	)
 	cmd := &cobra.Command{
 		Use:   "validate",
 		Short: "Validate token configured for a profile",
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(selector) != "" {
+				return runProfileFleet(cmd, runtime, "meta auth validate", selector)
+			}
 			resolvedProfile, err := resolveAuthProfile(runtime, profile)
 			if err != nil {
 				return err
@@ -625,6 +645,11 @@ func newAuthValidateCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if len(metadata.Scopes) > 0 {
+				if err := svc.RecordVerifiedScopes(resolvedProfile, metadata.Scopes, time.Now()); err != nil {
+					return err
+				}
+			}
 
 			return writeSuccess(cmd, runtime, "meta auth validate", map[string]any{
 				"status":         "ok",
@@ -643,15 +668,22 @@ func newAuthValidateCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().DurationVar(&minTTL, "min-ttl", defaultAuthPreflightTTL, "Minimum remaining token TTL (for example 30m, 12h)")
 	cmd.Flags().StringVar(&requireScopes, "require-scopes", "", "Comma-separated scopes that must be present")
+	addProfilesSelectorFlag(cmd, &selector)
 	return cmd
 }
 
 func newAuthRotateCommand(runtime Runtime) *cobra.Command {
-	var profile string
+	var (
+		profile  string
+		selector string
+	)
 	cmd := &cobra.Command{
 		Use:   "rotate",
 		Short: "Rotate token for a profile",
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(selector) != "" {
+				return runProfileFleet(cmd, runtime, "meta auth rotate", selector)
+			}
 			resolvedProfile, err := resolveAuthProfile(runtime, profile)
 			if err != nil {
 				return err
@@ -670,6 +702,7 @@ func newAuthRotateCommand(runtime Runtime) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	addProfilesSelectorFlag(cmd, &selector)
 	return cmd
 }
 
@@ -779,7 +812,7 @@ func runOAuthAutoLogin(cmd *cobra.Command, svc authCLIService, input oauthLoginI
 		return oauthLoginResult{}, errors.New("scopes are required")
 	}
 	if input.Timeout <= 0 {
-		return oauthLoginResult{}, errors.New("timeout must be greater than zero")
+		return oauthLoginResult{}, errors.New("callback timeout must be greater than zero")
 	}
 	listenerURI, err := localCallbackRedirectURI(input.ListenAddr)
 	if err != nil {
@@ -868,18 +901,19 @@ func runOAuthAutoLogin(cmd *cobra.Command, svc authCLIService, input oauthLoginI
 	}
 
 	if err := svc.AddUser(cmd.Context(), auth.AddUserInput{
-		Profile:         input.Profile,
-		AppID:           input.AppID,
-		Token:           longLived.AccessToken,
-		AppSecret:       input.AppSecret,
-		AuthProvider:    input.AuthProvider,
-		AuthMode:        input.AuthMode,
-		Scopes:          metadata.Scopes,
-		IssuedAt:        now.Format(time.RFC3339),
-		ExpiresAt:       expiresAt.Format(time.RFC3339),
-		LastValidatedAt: now.Format(time.RFC3339),
-		PageID:          input.PageID,
-		IGUserID:        input.IGUserID,
+		Profile:          input.Profile,
+		AppID:            input.AppID,
+		Token:            longLived.AccessToken,
+		AppSecret:        input.AppSecret,
+		AuthProvider:     input.AuthProvider,
+		AuthMode:         input.AuthMode,
+		Scopes:           metadata.Scopes,
+		IssuedAt:         now.Format(time.RFC3339),
+		ExpiresAt:        expiresAt.Format(time.RFC3339),
+		LastValidatedAt:  now.Format(time.RFC3339),
+		PageID:           input.PageID,
+		IGUserID:         input.IGUserID,
+		ScopesVerifiedAt: scopesVerifiedAt(metadata.Scopes, now),
 	}); err != nil {
 		return oauthLoginResult{}, err
 	}
@@ -897,6 +931,15 @@ func runOAuthAutoLogin(cmd *cobra.Command, svc authCLIService, input oauthLoginI
 	}, nil
 }
 
+// scopesVerifiedAt stamps scopes that debug_token reported. A token that
+// reported none keeps the default scopes, which stay unverified.
+func scopesVerifiedAt(scopes []string, now time.Time) string {
+	if len(scopes) == 0 {
+		return ""
+	}
+	return now.Format(time.RFC3339)
+}
+
 func resolveAuthProfile(runtime Runtime, profile string) (string, error) {
 	resolved := strings.TrimSpace(profile)
 	if resolved == "" {
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -44,6 +45,10 @@ type stubAuthService struct {
 	validateProfileResp  *auth.DebugTokenResponse
 	validateProfileErr   error
 
+	recordScopesProfile string
+	recordScopes        []string
+	recordScopesErr     error
+
 	rotateProfileInput string
 	rotateProfileErr   error
 
@@ -126,6 +131,12 @@ func (s *stubAuthService) ValidateProfile(_ context.Context, profile string) (*a
 	return s.validateProfileResp, nil
 }
 
+func (s *stubAuthService) RecordVerifiedScopes(profile string, scopes []string, _ time.Time) error {
+	s.recordScopesProfile = profile
+	s.recordScopes = scopes
+	return s.recordScopesErr
+}
+
 func (s *stubAuthService) RotateProfile(_ context.Context, profile string) error {
 	s.rotateProfileInput = profile
 	return s.rotateProfileErr
@@ -521,6 +532,9 @@ func TestAuthValidateUsesEnsureValidWithDefaults(t *testing.T) {
 	if service.ensureValidMinTTL != defaultAuthPreflightTTL {
 		t.Fatalf("unexpected min ttl: got=%s want=%s", service.ensureValidMinTTL, defaultAuthPreflightTTL)
 	}
+	if service.recordScopesProfile != "prod" || !reflect.DeepEqual(service.recordScopes, []string{"ads_read"}) {
+		t.Fatalf("expected validated scopes recorded on prod, got profile=%q scopes=%v", service.recordScopesProfile, service.recordScopes)
+	}
 	envelope := decodeEnvelope(t, stdout.Bytes())
 	assertEnvelopeBasics(t, envelope, "meta auth validate")
 }
@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/bulk"
+	"github.com/bilalbayram/metacli/internal/checkpoint"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/lint"
+	"github.com/bilalbayram/metacli/internal/ops"
+	"github.com/bilalbayram/metacli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bulkLoadProfileCredentials = loadProfileCredentials
+	bulkNewGraphClient         = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+)
+
+type bulkImportResult struct {
+	ResultsFile string       `json:"results_file,omitempty"`
+	Report      *bulk.Report `json:"report"`
+}
+
+func NewBulkCommand(runtime Runtime) *cobra.Command {
+	bulkCmd := &cobra.Command{
+		Use:   "bulk",
+		Short: "Bulk campaign/adset/ad creation from spreadsheets",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "bulk")
+		},
+	}
+	bulkCmd.AddCommand(newBulkImportCommand(runtime))
+	return bulkCmd
+}
+
+func newBulkImportCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile             string
+		version             string
+		accountID           string
+		accountName         string
+		filePath            string
+		resultsPath         string
+		schemaDir           string
+		batchSize           int
+		concurrency         int
+		confirmBudgetChange bool
+		rollbackOnFailure   bool
+		dryRun              bool
+		checkpointOpts      checkpointFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Create campaigns, ad sets, and ads from a CSV bulk sheet",
+		Long:  "Create campaigns, ad sets, and ads from a CSV bulk sheet. Hierarchy columns: campaign_name, campaign_id, adset_name, adset_id, ad_name. Every other column is a create param prefixed by its level (campaign.objective, adset.daily_budget, ad.creative; Ads Manager headers such as \"Ad Set Daily Budget\" also work). Rows that repeat a campaign or ad set name share that object. Every row is validated before anything is created.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if concurrency < 1 {
+				return writeCommandError(cmd, runtime, "meta bulk import", errors.New("--concurrency must be > 0"))
+			}
+			rows, err := bulk.LoadFile(filePath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta bulk import", err)
+			}
+			creds, resolvedVersion, err := resolveBulkProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta bulk import", err)
+			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta bulk import", err)
+			}
+			normalizedAccountID := strings.TrimPrefix(strings.TrimSpace(accountID), "act_")
+			if normalizedAccountID == "" {
+				return writeCommandError(cmd, runtime, "meta bulk import", errors.New("account id is required"))
+			}
+
+			linters := bulkLinters{creds: creds, version: resolvedVersion, schemaDir: schemaDir}
+			report := bulk.Plan(rows, bulk.PlanOptions{
+				File:      filePath,
+				AccountID: "act_" + normalizedAccountID,
+				Lint:      linters.lint,
+			})
+			report.DryRun = dryRun
+			if report.Invalid > 0 {
+				return writeCommandError(cmd, runtime, "meta bulk import", bulk.NewValidationError(report))
+			}
+			if dryRun {
+				return writeSuccess(cmd, runtime, "meta bulk import", bulkImportResult{Report: report}, nil, nil)
+			}
+			if err := enforceBulkBudgetGuardrail(report, confirmBudgetChange); err != nil {
+				return writeCommandError(cmd, runtime, "meta bulk import", err)
+			}
+
+			if strings.TrimSpace(resultsPath) == "" {
+				resultsPath = bulk.DefaultResultsPath(filePath)
+			}
+			previous, err := bulk.LoadResults(resultsPath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta bulk import", err)
+			}
+
+			fingerprint, err := checkpoint.FileFingerprint(filePath, report.AccountID)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta bulk import", err)
+			}
+			runCheckpoint, err := checkpointOpts.open(checkpoint.DefaultPath(filePath), "bulk_import", fingerprint)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta bulk import", err)
+			}
+
+			runID, err := newTrackedRunID("bulk")
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta bulk import", err)
+			}
+			client := governedClient(bulkNewGraphClient(), concurrency)
+			importProgress := startProgress(cmd, runtime, "bulk import", int64(report.Summary.Create))
+			importProgress.Bar().SetUnit("objects")
+			execErr := bulk.Execute(importProgress.Context(cmd.Context()), report, func(ctx context.Context, requests []graph.BatchRequest) ([]graph.BatchResult, error) {
+				return client.ExecuteBatch(ctx, resolvedVersion, creds.Token, creds.AppSecret, requests)
+			}, bulk.ExecuteOptions{
+				BatchSize:   batchSize,
+				Concurrency: concurrency,
+				OnProgress:  importProgress.Work,
+				Previous:    previous,
+				Checkpoint:  runCheckpoint,
+			})
+			importProgress.Finish()
+			endTrackedRun := beginTrackedRun(runID)
+			trackErr := trackBulkCreatedResources(report, creds.Name, resolvedVersion)
+			endTrackedRun()
+			if execErr != nil && rollbackOnFailure && trackErr == nil {
+				rollback, rollbackErr := rollbackTrackedRun(cmd.Context(), runID, resolvedVersion)
+				if rollbackErr == nil {
+					bulk.MarkRolledBack(report, rolledBackResourceIDs(rollback))
+				}
+				execErr = withRollbackDiagnostics(execErr, rollback, rollbackErr)
+			}
+			if err := bulk.WriteResults(resultsPath, bulk.NewResults(report)); err != nil {
+				return writeCommandError(cmd, runtime, "meta bulk import", err)
+			}
+			// The results file now holds every created id, so reruns reuse them
+			// through it and the checkpoint has nothing left to add.
+			if err := runCheckpoint.Remove(); err != nil {
+				return writeCommandError(cmd, runtime, "meta bulk import", err)
+			}
+			if trackErr != nil {
+				return writeCommandError(cmd, runtime, "meta bulk import", trackErr)
+			}
+			if execErr != nil {
+				return writeCommandError(cmd, runtime, "meta bulk import", execErr)
+			}
+			return writeSuccess(cmd, runtime, "meta bulk import", bulkImportResult{
+				ResultsFile: resultsPath,
+				Report:      report,
+			}, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
+	cmd.Flags().StringVar(&filePath, "file", "", "Bulk sheet (.csv with header row)")
+	cmd.Flags().StringVar(&resultsPath, "results-file", "", "Results file mapping rows to created ids (defaults to <file>.results.json); an existing file makes reruns reuse created objects")
+	cmd.Flags().StringVar(&schemaDir, "schema-dir", schema.DefaultSchemaDir(), "Schema pack root directory")
+	cmd.Flags().IntVar(&batchSize, "batch-size", bulk.DefaultBatchSize, fmt.Sprintf("Create requests per Graph batch call (1-%d)", bulk.DefaultBatchSize))
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "Graph batch calls sent in parallel within each level (campaigns, then ad sets, then ads); throttled by rate-limit usage")
+	cmd.Flags().BoolVar(&confirmBudgetChange, "confirm-budget-change", false, "Acknowledge budget fields (daily_budget/lifetime_budget) in the sheet")
+	cmd.Flags().BoolVar(&rollbackOnFailure, "rollback-on-failure", false, "When any object fails, pause or delete every object this run created (per the resource ledger) instead of keeping the rows that succeeded")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate every row and print the plan without creating anything")
+	addCheckpointFlags(cmd, &checkpointOpts, "<file>.checkpoint.json")
+	mustMarkFlagRequired(cmd, "file")
+	cmd.MarkFlagsOneRequired("account-id", "account")
+	return cmd
+}
+
+// bulkLinters builds each create linter once and lints objects with the same
+// rules as the campaign|adset|ad create commands.
+type bulkLinters struct {
+	creds     *ProfileCredentials
+	version   string
+	schemaDir string
+	linters   map[string]*lint.Linter
+}
+
+func (l *bulkLinters) lint(kind string, params map[string]string) error {
+	if l.linters == nil {
+		l.linters = map[string]*lint.Linter{}
+	}
+	linter, ok := l.linters[kind]
+	if !ok {
+		var err error
+		switch kind {
+		case bulk.KindCampaign:
+			linter, err = newCampaignMutationLinter(l.creds, l.version, l.schemaDir)
+		case bulk.KindAdSet:
+			linter, err = newAdsetMutationLinter(l.creds, l.version, l.schemaDir)
+		case bulk.KindAd:
+			linter, err = newAdMutationLinter(l.creds, l.version, l.schemaDir)
+		default:
+			err = fmt.Errorf("unsupported bulk object kind %q", kind)
+		}
+		if err != nil {
+			return err
+		}
+		l.linters[kind] = linter
+	}
+
+	switch kind {
+	case bulk.KindCampaign:
+		return lintCampaignMutation(linter, params)
+	case bulk.KindAdSet:
+		return lintAdsetMutation(linter, params)
+	default:
+		return lintAdMutation(linter, params)
+	}
+}
+
+func enforceBulkBudgetGuardrail(report *bulk.Report, confirmed bool) error {
+	for _, object := range report.Objects {
+		if object.Action != bulk.ActionCreate {
+			continue
+		}
+		var err error
+		switch object.Kind {
+		case bulk.KindCampaign:
+			err = enforceCampaignBudgetGuardrail(object.Params, confirmed)
+		case bulk.KindAdSet:
+			err = enforceAdsetBudgetGuardrail(object.Params, confirmed)
+		}
+		if err != nil {
+			return fmt.Errorf("row %d: %w", object.Rows[0], err)
+		}
+	}
+	return nil
+}
+
+func trackBulkCreatedResources(report *bulk.Report, profile string, version string) error {
+	for _, object := range report.Objects {
+		if object.Status != bulk.StatusCreated {
+			continue
+		}
+		if err := persistTrackedResource(trackedResourceInput{
+			Command:       "meta bulk import",
+			ResourceKind:  object.Kind,
+			ResourceID:    object.ID,
+			CleanupAction: ops.CleanupActionPause,
+			Profile:       profile,
+			GraphVersion:  version,
+			AccountID:     report.AccountID,
+			Metadata: map[string]string{
+				"operation": "create",
+				"row":       strconv.Itoa(object.Rows[0]),
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resolveBulkProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
+	resolvedProfile := strings.TrimSpace(profile)
+	if resolvedProfile == "" {
+		resolvedProfile = runtime.ProfileName()
+	}
+	if resolvedProfile == "" {
+		return nil, "", errors.New("profile is required (--profile or global --profile)")
+	}
+
+	creds, err := bulkLoadProfileCredentials(resolvedProfile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resolvedVersion := strings.TrimSpace(version)
+	if resolvedVersion == "" {
+		resolvedVersion = creds.Profile.GraphVersion
+	}
+	if resolvedVersion == "" {
+		resolvedVersion = config.DefaultGraphVersion
+	}
+	return creds, resolvedVersion, nil
+}
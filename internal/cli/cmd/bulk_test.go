@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/bulk"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const bulkTestSheet = `Campaign Name,Campaign Objective,Ad Set Name,Ad Set Daily Budget,Ad Name,ad.creative
+Launch,OUTCOME_SALES,Prospecting,1000,Ad A,"{""creative_id"":""cr1""}"
+Launch,OUTCOME_SALES,Prospecting,1000,Ad B,"{""creative_id"":""cr2""}"
+`
+
+func TestBulkImportDryRunShowsPlan(t *testing.T) {
+	sheetPath := writeBulkSheet(t, bulkTestSheet)
+	useBulkDependencies(t, nil)
+
+	output, err := runBulkImport(t, "--file", sheetPath, "--account-id", "123", "--schema-dir", writeBulkSchemaPack(t), "--dry-run")
+	if err != nil {
+		t.Fatalf("execute bulk import: %v", err)
+	}
+	envelope := decodeEnvelope(t, output)
+	assertEnvelopeBasics(t, envelope, "meta bulk import")
+	report, _ := envelope["data"].(map[string]any)["report"].(map[string]any)
+	summary, _ := report["summary"].(map[string]any)
+	if report["dry_run"] != true || summary["create"] != float64(4) {
+		t.Fatalf("unexpected report %v", report)
+	}
+	if _, err := os.Stat(bulk.DefaultResultsPath(sheetPath)); !os.IsNotExist(err) {
+		t.Fatalf("dry run must not write a results file, stat err=%v", err)
+	}
+}
+
+func TestBulkImportRejectsInvalidRowsBeforeCreating(t *testing.T) {
+	sheetPath := writeBulkSheet(t, "campaign_name,campaign.bogus\nLaunch,1\n")
+	useBulkDependencies(t, nil)
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewBulkCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"import", "--file", sheetPath, "--account-id", "123", "--schema-dir", writeBulkSchemaPack(t)})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected validation failure")
+	}
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	errorBody, _ := envelope["error"].(map[string]any)
+	if errorBody["type"] != "bulk_import_validation_error" || !strings.Contains(errorBody["message"].(string), "1 invalid row(s)") {
+		t.Fatalf("unexpected error envelope %v", envelope["error"])
+	}
+}
+
+func TestBulkImportExecutesBatchesAndWritesResults(t *testing.T) {
+	sheetPath := writeBulkSheet(t, bulkTestSheet)
+	batches := make([][]map[string]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v25.0" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		var entries []map[string]string
+		if err := json.Unmarshal([]byte(r.PostForm.Get("batch")), &entries); err != nil {
+			t.Fatalf("decode batch: %v", err)
+		}
+		batches = append(batches, entries)
+		items := make([]map[string]any, 0, len(entries))
+		for index := range entries {
+			items = append(items, map[string]any{"code": 200, "body": fmt.Sprintf(`{"id":"b%d_%d"}`, len(batches), index)})
+		}
+		_ = json.NewEncoder(w).Encode(items)
+	}))
+	defer server.Close()
+	useBulkDependencies(t, server)
+
+	if _, err := runBulkImport(t, "--file", sheetPath, "--account-id", "123", "--schema-dir", writeBulkSchemaPack(t)); err == nil || !strings.Contains(err.Error(), "--confirm-budget-change") {
+		t.Fatalf("expected budget guardrail, got %v", err)
+	}
+
+	output, err := runBulkImport(t, "--file", sheetPath, "--account-id", "123", "--schema-dir", writeBulkSchemaPack(t), "--confirm-budget-change")
+	if err != nil {
+		t.Fatalf("execute bulk import: %v", err)
+	}
+	assertEnvelopeBasics(t, decodeEnvelope(t, output), "meta bulk import")
+	if len(batches) != 3 || len(batches[2]) != 2 || !strings.Contains(batches[1][0]["body"], "campaign_id=b1_0") {
+		t.Fatalf("unexpected batches %v", batches)
+	}
+
+	results, err := bulk.LoadResults(bulk.DefaultResultsPath(sheetPath))
+	if err != nil || results == nil {
+		t.Fatalf("load results: %v", err)
+	}
+	if row := results.Rows[1]; row.CampaignID != "b1_0" || row.AdSetID != "b2_0" || row.AdID != "b3_1" {
+		t.Fatalf("unexpected row mapping %#v", row)
+	}
+
+	// A rerun with the same results file reuses every object.
+	if _, err := runBulkImport(t, "--file", sheetPath, "--account-id", "123", "--schema-dir", writeBulkSchemaPack(t), "--confirm-budget-change"); err != nil {
+		t.Fatalf("rerun bulk import: %v", err)
+	}
+	if len(batches) != 3 {
+		t.Fatalf("expected rerun to send no batches, got %d", len(batches))
+	}
+}
+
+func TestBulkImportRollsBackCreatedObjectsOnFailure(t *testing.T) {
+	sheetPath := writeBulkSheet(t, "campaign_name,adset_name\nLaunch,Prospecting\n")
+	paused := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		if r.URL.Path != "/v25.0" {
+			paused = append(paused, r.URL.Path+" "+r.PostForm.Get("status"))
+			_, _ = w.Write([]byte(`{"success":true}`))
+			return
+		}
+		var entries []map[string]string
+		if err := json.Unmarshal([]byte(r.PostForm.Get("batch")), &entries); err != nil {
+			t.Fatalf("decode batch: %v", err)
+		}
+		if strings.HasSuffix(entries[0]["relative_url"], "/adsets") {
+			_ = json.NewEncoder(w).Encode([]map[string]any{{"code": 400, "body": `{"error":{"message":"Invalid parameter","type":"OAuthException","code":100}}`}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]map[string]any{{"code": 200, "body": `{"id":"c1"}`}})
+	}))
+	defer server.Close()
+	useBulkDependencies(t, server)
+
+	if _, err := runBulkImport(t, "--file", sheetPath, "--account-id", "123", "--schema-dir", writeBulkSchemaPack(t), "--rollback-on-failure"); err == nil || !strings.Contains(err.Error(), "rolled back 1 resource(s), 0 rollback failure(s)") {
+		t.Fatalf("expected rolled back partial failure, got %v", err)
+	}
+	if len(paused) != 1 || paused[0] != "/v25.0/c1 PAUSED" {
+		t.Fatalf("expected the created campaign to be paused, got %v", paused)
+	}
+
+	results, err := bulk.LoadResults(bulk.DefaultResultsPath(sheetPath))
+	if err != nil || results == nil {
+		t.Fatalf("load results: %v", err)
+	}
+	if results.Objects[0].Status != bulk.StatusRolledBack || results.Summary.RolledBack != 1 || results.Rows[0].Status != bulk.StatusFailed {
+		t.Fatalf("unexpected results %#v", results)
+	}
+}
+
+func runBulkImport(t *testing.T, args ...string) ([]byte, error) {
+	t.Helper()
+	output := &bytes.Buffer{}
+	cmd := NewBulkCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(append([]string{"import"}, args...))
+	err := cmd.Execute()
+	return output.Bytes(), err
+}
+
+func writeBulkSheet(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "structures.csv")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write sheet: %v", err)
+	}
+	return path
+}
+
+func writeBulkSchemaPack(t *testing.T) string {
+	t.Helper()
+	schemaDir := t.TempDir()
+	marketingDir := filepath.Join(schemaDir, config.DefaultDomain)
+	if err := os.MkdirAll(marketingDir, 0o755); err != nil {
+		t.Fatalf("create schema dir: %v", err)
+	}
+
+	pack := `{
+  "domain":"marketing",
+  "version":"v25.0",
+  "entities":{},
+  "endpoint_params":{
+    "campaigns.post":["name","status","objective","daily_budget","lifetime_budget"],
+    "adsets.post":["name","campaign_id","status","daily_budget","lifetime_budget"],
+    "ads.post":["name","adset_id","status","creative"]
+  }
+}`
+	if err := os.WriteFile(filepath.Join(marketingDir, config.DefaultGraphVersion+".json"), []byte(pack), 0o644); err != nil {
+		t.Fatalf("write schema pack: %v", err)
+	}
+	return schemaDir
+}
+
+func useBulkDependencies(t *testing.T, server *httptest.Server) {
+	t.Helper()
+	configureTestResourceLedgerPath(t)
+	originalLoad := bulkLoadProfileCredentials
+	originalClient := bulkNewGraphClient
+	t.Cleanup(func() {
+		bulkLoadProfileCredentials = originalLoad
+		bulkNewGraphClient = originalClient
+	})
+
+	bulkLoadProfileCredentials = func(string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{
+			Name: "prod",
+			Profile: config.Profile{
+				Domain:       config.DefaultDomain,
+				GraphVersion: config.DefaultGraphVersion,
+			},
+			Token: "token",
+		}, nil
+	}
+	bulkNewGraphClient = func() *graph.Client {
+		if server == nil {
+			t.Fatal("graph client should not be constructed")
+		}
+		client := graph.NewClient(server.Client(), server.URL)
+		client.MaxRetries = 0
+		return client
+	}
+	useRollbackDependencies(t, bulkLoadProfileCredentials, bulkNewGraphClient)
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/business"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+var (
+	businessLoadProfileCredentials = loadProfileCredentials
+	businessNewGraphClient         = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+)
+
+func NewBusinessCommand(runtime Runtime) *cobra.Command {
+	businessCmd := &cobra.Command{
+		Use:   "business",
+		Short: "Business Manager ad accounts, user invites, asset assignments, and permission audits",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "business")
+		},
+	}
+	businessCmd.AddCommand(newBusinessAdAccountsCommand(runtime))
+	businessCmd.AddCommand(newBusinessInviteCommand(runtime))
+	businessCmd.AddCommand(newBusinessAssignCommand(runtime))
+	businessCmd.AddCommand(newBusinessAuditCommand(runtime))
+	return businessCmd
+}
+
+func newBusinessAdAccountsCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile    string
+		version    string
+		businessID string
+		scope      string
+		fieldsRaw  string
+		limit      int
+		followNext bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ad-accounts",
+		Short: "List owned or client ad accounts of a business",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveBusinessProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta business ad-accounts", err)
+			}
+
+			result, err := business.New(businessNewGraphClient()).ListAdAccounts(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, business.ListAdAccountsOptions{
+				BusinessID: resolveBusinessID(creds, businessID),
+				Scope:      scope,
+				Fields:     csvToSlice(fieldsRaw),
+				Limit:      limit,
+				FollowNext: followNext,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta business ad-accounts", err)
+			}
+			return writeSuccess(cmd, runtime, "meta business ad-accounts", result.AdAccounts, result.Paging, nil)
+		},
+	}
+
+	addBusinessCommonFlags(cmd, &profile, &version, &businessID)
+	cmd.Flags().StringVar(&scope, "scope", business.AdAccountScopeOwned, "Ad account scope: owned|client")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated ad account fields (defaults to "+strings.Join(business.DefaultAdAccountFields, ",")+")")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of ad accounts to return (0 = unlimited)")
+	cmd.Flags().BoolVar(&followNext, "follow-next", false, "Follow paging.next links")
+	return cmd
+}
+
+func newBusinessInviteCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile      string
+		version      string
+		businessID   string
+		email        string
+		role         string
+		confirmGrant bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "invite",
+		Short: "Invite a user to a business by email",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveBusinessProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta business invite", err)
+			}
+			options := business.InviteUserOptions{
+				BusinessID: resolveBusinessID(creds, businessID),
+				Email:      email,
+				Role:       role,
+			}
+			grant, err := business.PlanInvite(options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta business invite", err)
+			}
+			if err := enforceBusinessGrantGuardrail(grant, confirmGrant); err != nil {
+				return writeCommandError(cmd, runtime, "meta business invite", err)
+			}
+
+			result, err := business.New(businessNewGraphClient()).InviteUser(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta business invite", err)
+			}
+			return writeSuccess(cmd, runtime, "meta business invite", result, nil, nil)
+		},
+	}
+
+	addBusinessCommonFlags(cmd, &profile, &version, &businessID)
+	cmd.Flags().StringVar(&email, "email", "", "Email address to invite")
+	cmd.Flags().StringVar(&role, "role", business.BusinessRoleEmployee, "Business role: EMPLOYEE|ADMIN")
+	cmd.Flags().BoolVar(&confirmGrant, "confirm-grant", false, "Acknowledge the business permission grant")
+	mustMarkFlagRequired(cmd, "email")
+	return cmd
+}
+
+func newBusinessAssignCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile      string
+		version      string
+		businessID   string
+		assetType    string
+		assetID      string
+		userID       string
+		role         string
+		tasksRaw     string
+		confirmGrant bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "assign",
+		Short: "Assign an ad account, page, or pixel to a user or system user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveBusinessProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta business assign", err)
+			}
+			options := business.AssignAssetOptions{
+				BusinessID: resolveBusinessID(creds, businessID),
+				AssetType:  assetType,
+				AssetID:    assetID,
+				UserID:     userID,
+				Role:       role,
+				Tasks:      csvToSlice(tasksRaw),
+			}
+			grant, err := business.PlanAssignment(options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta business assign", err)
+			}
+			if err := enforceBusinessGrantGuardrail(grant, confirmGrant); err != nil {
+				return writeCommandError(cmd, runtime, "meta business assign", err)
+			}
+
+			result, err := business.New(businessNewGraphClient()).AssignAsset(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta business assign", err)
+			}
+			return writeSuccess(cmd, runtime, "meta business assign", result, nil, nil)
+		},
+	}
+
+	addBusinessCommonFlags(cmd, &profile, &version, &businessID)
+	cmd.Flags().StringVar(&assetType, "asset-type", "", "Asset type: ad-account|page|pixel")
+	cmd.Flags().StringVar(&assetID, "asset-id", "", "Asset id (ad account ids may omit act_)")
+	cmd.Flags().StringVar(&userID, "user-id", "", "Business user or system user id")
+	cmd.Flags().StringVar(&role, "role", "", "Task preset: admin|advertiser|analyst")
+	cmd.Flags().StringVar(&tasksRaw, "tasks", "", "Comma-separated explicit tasks (for example ANALYZE,ADVERTISE)")
+	cmd.Flags().BoolVar(&confirmGrant, "confirm-grant", false, "Acknowledge the asset permission grant")
+	return cmd
+}
+
+func newBusinessAuditCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile    string
+		version    string
+		businessID string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit user and system user permissions across owned ad accounts, pages, and pixels",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveBusinessProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta business audit", err)
+			}
+
+			result, err := business.New(businessNewGraphClient()).Audit(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, business.AuditOptions{
+				BusinessID: resolveBusinessID(creds, businessID),
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta business audit", err)
+			}
+			return writeSuccess(cmd, runtime, "meta business audit", result, nil, nil)
+		},
+	}
+
+	addBusinessCommonFlags(cmd, &profile, &version, &businessID)
+	return cmd
+}
+
+func addBusinessCommonFlags(cmd *cobra.Command, profile *string, version *string, businessID *string) {
+	cmd.Flags().StringVar(profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(businessID, "business-id", "", "Business id (optional when profile has business_id)")
+}
+
+func enforceBusinessGrantGuardrail(grant business.Grant, confirmed bool) error {
+	if confirmed {
+		return nil
+	}
+	if grant.Email != "" {
+		return fmt.Errorf("inviting %s as %s grants business access; rerun with --confirm-grant", grant.Email, grant.Role)
+	}
+	return fmt.Errorf("assigning %s %s to user %s grants tasks [%s]; rerun with --confirm-grant", grant.AssetType, grant.AssetID, grant.UserID, strings.Join(grant.Tasks, ","))
+}
+
+func resolveBusinessID(creds *ProfileCredentials, businessID string) string {
+	resolved := strings.TrimSpace(businessID)
+	if resolved == "" && creds != nil {
+		resolved = strings.TrimSpace(creds.Profile.BusinessID)
+	}
+	return resolved
+}
+
+func resolveBusinessProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
+	resolvedProfile := strings.TrimSpace(profile)
+	if resolvedProfile == "" {
+		resolvedProfile = runtime.ProfileName()
+	}
+	if resolvedProfile == "" {
+		return nil, "", errors.New("profile is required (--profile or global --profile)")
+	}
+
+	creds, err := businessLoadProfileCredentials(resolvedProfile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resolvedVersion := strings.TrimSpace(version)
+	if resolvedVersion == "" {
+		resolvedVersion = creds.Profile.GraphVersion
+	}
+	if resolvedVersion == "" {
+		resolvedVersion = config.DefaultGraphVersion
+	}
+	return creds, resolvedVersion, nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestBusinessAdAccountsListsClientAccounts(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"id":"act_1","name":"Client One","currency":"USD"}]}`,
+	}
+	useBusinessDependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewBusinessCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"ad-accounts", "--scope", "client"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute business ad-accounts: %v", err)
+	}
+	if !strings.Contains(stub.lastURL, "/v25.0/biz_1/client_ad_accounts") {
+		t.Fatalf("unexpected request url %q", stub.lastURL)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta business ad-accounts")
+	data := envelope["data"].([]any)
+	if len(data) != 1 {
+		t.Fatalf("expected one ad account, got %d", len(data))
+	}
+}
+
+func TestBusinessAssignRequiresConfirmGrant(t *testing.T) {
+	useBusinessDependencies(t, nil)
+
+	cmd := NewBusinessCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"assign", "--asset-type", "ad-account", "--asset-id", "123", "--user-id", "user_1", "--role", "advertiser"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "rerun with --confirm-grant") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(err.Error(), "act_123") || !strings.Contains(err.Error(), "ADVERTISE,ANALYZE") {
+		t.Fatalf("expected grant details in error, got %v", err)
+	}
+}
+
+func TestBusinessAssignPostsTasksWhenConfirmed(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"success":true}`,
+	}
+	useBusinessDependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewBusinessCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"assign", "--asset-type", "pixel", "--asset-id", "px_1", "--user-id", "sys_1", "--tasks", "analyze,upload", "--confirm-grant"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute business assign: %v", err)
+	}
+	if stub.lastMethod != http.MethodPost || !strings.HasSuffix(stub.lastURL, "/v25.0/px_1/assigned_users") {
+		t.Fatalf("unexpected request %s %s", stub.lastMethod, stub.lastURL)
+	}
+	form, err := url.ParseQuery(stub.lastBody)
+	if err != nil {
+		t.Fatalf("parse body: %v", err)
+	}
+	if form.Get("user") != "sys_1" || form.Get("tasks") != `["ANALYZE","UPLOAD"]` || form.Get("business") != "biz_1" {
+		t.Fatalf("unexpected form %v", form)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta business assign")
+}
+
+func TestBusinessInviteRequiresConfirmGrant(t *testing.T) {
+	useBusinessDependencies(t, nil)
+
+	cmd := NewBusinessCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"invite", "--email", "ops@example.com", "--role", "admin"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "inviting ops@example.com as ADMIN") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func useBusinessDependencies(t *testing.T, stub *stubHTTPClient) {
+	t.Helper()
+	originalLoad := businessLoadProfileCredentials
+	originalClient := businessNewGraphClient
+	t.Cleanup(func() {
+		businessLoadProfileCredentials = originalLoad
+		businessNewGraphClient = originalClient
+	})
+
+	businessLoadProfileCredentials = func(string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{
+			Name:    "prod",
+			Profile: config.Profile{GraphVersion: "v25.0", BusinessID: "biz_1"},
+			Token:   "token",
+		}, nil
+	}
+	businessNewGraphClient = func() *graph.Client {
+		if stub == nil {
+			t.Fatal("graph client should not be created")
+		}
+		client := graph.NewClient(stub, "https://graph.example.com")
+		client.MaxRetries = 0
+		return client
+	}
+}
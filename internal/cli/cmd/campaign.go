@@ -12,6 +12,7 @@ import (
 	"github.com/bilalbayram/metacli/internal/ops"
 	"github.com/bilalbayram/metacli/internal/requirements"
 	"github.com/bilalbayram/metacli/internal/schema"
+	"github.com/bilalbayram/metacli/internal/templates"
 	"github.com/spf13/cobra"
 )
 
@@ -58,6 +59,7 @@ func NewCampaignCommand(runtime Runtime) *cobra.Command {
 	campaignCmd.AddCommand(newCampaignPauseCommand(runtime))
 	campaignCmd.AddCommand(newCampaignResumeCommand(runtime))
 	campaignCmd.AddCommand(newCampaignCloneCommand(runtime))
+	campaignCmd.AddCommand(newWatchCommand(runtime, campaignWatchTarget))
 	return campaignCmd
 }
 
@@ -66,12 +68,15 @@ func newCampaignCreateCommand(runtime Runtime) *cobra.Command {
 		profile             string
 		version             string
 		accountID           string
+		accountName         string
 		paramsRaw           string
 		jsonRaw             string
 		schemaDir           string
 		rulesDir            string
 		confirmBudgetChange bool
 		dryRun              bool
+		templateName        string
+		templateVars        []string
 	)
 
 	cmd := &cobra.Command{
@@ -83,6 +88,9 @@ func newCampaignCreateCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta campaign create", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta campaign create", err)
+			}
 
 			form, err := parseKeyValueList(paramsRaw)
 			if err != nil {
@@ -95,6 +103,9 @@ func newCampaignCreateCommand(runtime Runtime) *cobra.Command {
 			if err := mergeParams(form, jsonForm, "--json"); err != nil {
 				return writeCommandError(cmd, runtime, "meta campaign create", err)
 			}
+			if err := mergeTemplateParams(form, templates.KindCampaign, templateName, templateVars); err != nil {
+				return writeCommandError(cmd, runtime, "meta campaign create", err)
+			}
 			if err := enforceCampaignBudgetGuardrail(form, confirmBudgetChange); err != nil {
 				return writeCommandError(cmd, runtime, "meta campaign create", err)
 			}
@@ -176,6 +187,7 @@ func newCampaignCreateCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&paramsRaw, "params", "", "Comma-separated mutation params (k=v,k2=v2)")
 	cmd.Flags().StringVar(&jsonRaw, "json", "", "Inline JSON object payload")
 	cmd.Flags().StringVar(&schemaDir, "schema-dir", schema.DefaultSchemaDir(), "Schema pack root directory")
@@ -183,6 +195,7 @@ func newCampaignCreateCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().BoolVar(&confirmBudgetChange, "confirm-budget-change", false, "Acknowledge budget mutation fields (daily_budget/lifetime_budget)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Resolve requirements and output plan without executing mutation")
 	cmd.Flags().BoolVar(&dryRun, "plan", false, "Alias of --dry-run")
+	addTemplateFlags(cmd, &templateName, &templateVars)
 	return cmd
 }
 
@@ -212,13 +225,14 @@ type campaignDryRunResult struct {
 
 func newCampaignResolveRequirementsCommand(runtime Runtime) *cobra.Command {
 	var (
-		profile   string
-		version   string
-		accountID string
-		paramsRaw string
-		jsonRaw   string
-		schemaDir string
-		rulesDir  string
+		profile     string
+		version     string
+		accountID   string
+		accountName string
+		paramsRaw   string
+		jsonRaw     string
+		schemaDir   string
+		rulesDir    string
 	)
 
 	cmd := &cobra.Command{
@@ -230,6 +244,9 @@ func newCampaignResolveRequirementsCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta campaign resolve-requirements", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta campaign resolve-requirements", err)
+			}
 
 			form, err := parseKeyValueList(paramsRaw)
 			if err != nil {
@@ -272,6 +289,7 @@ func newCampaignResolveRequirementsCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&paramsRaw, "params", "", "Comma-separated mutation params (k=v,k2=v2)")
 	cmd.Flags().StringVar(&jsonRaw, "json", "", "Inline JSON object payload")
 	cmd.Flags().StringVar(&schemaDir, "schema-dir", schema.DefaultSchemaDir(), "Schema pack root directory")
@@ -284,6 +302,7 @@ func newCampaignListCommand(runtime Runtime) *cobra.Command {
 		profile            string
 		version            string
 		accountID          string
+		accountName        string
 		fieldsRaw          string
 		nameRaw            string
 		statusRaw          string
@@ -304,6 +323,9 @@ func newCampaignListCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta campaign list", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta campaign list", err)
+			}
 
 			linter, err := newCampaignMutationLinter(creds, resolvedVersion, schemaDir)
 			if err != nil {
@@ -318,6 +340,7 @@ func newCampaignListCommand(runtime Runtime) *cobra.Command {
 				return writeCommandError(cmd, runtime, "meta campaign list", err)
 			}
 
+			prefetchOutputCurrency(cmd, runtime, campaignNewGraphClient(), resolvedVersion, creds.Token, creds.AppSecret, accountID)
 			result, err := campaignNewService(campaignNewGraphClient()).List(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.CampaignListInput{
 				AccountID:         accountID,
 				Fields:            fields,
@@ -333,6 +356,8 @@ func newCampaignListCommand(runtime Runtime) *cobra.Command {
 				return writeCommandError(cmd, runtime, "meta campaign list", err)
 			}
 
+			recordListedEntities(creds.Name, marketing.EntityKindCampaign, accountID, result.Campaigns)
+			resolveOutputCurrency(cmd, runtime, campaignNewGraphClient(), resolvedVersion, creds.Token, creds.AppSecret, listedAccountID(accountID, result.Campaigns))
 			return writeSuccess(cmd, runtime, "meta campaign list", result.Campaigns, result.Paging, nil)
 		},
 	}
@@ -340,6 +365,7 @@ func newCampaignListCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated Graph fields (defaults to campaign read fields)")
 	cmd.Flags().StringVar(&nameRaw, "name", "", "Case-insensitive campaign name contains filter")
 	cmd.Flags().StringVar(&statusRaw, "status", "", "Comma-separated campaign status filter values")
@@ -418,8 +444,9 @@ func newCampaignUpdateCommand(runtime Runtime) *cobra.Command {
 			}
 
 			result, err := campaignNewService(campaignNewGraphClient()).Update(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.CampaignUpdateInput{
-				CampaignID: campaignID,
-				Params:     form,
+				CampaignID:  campaignID,
+				Params:      form,
+				DiffPreview: marketing.DiffPreview{PreviewDiff: true},
 			})
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta campaign update", err)
@@ -500,6 +527,7 @@ func newCampaignCloneCommand(runtime Runtime) *cobra.Command {
 		version          string
 		sourceCampaignID string
 		accountID        string
+		accountName      string
 		fieldsRaw        string
 		paramsRaw        string
 		jsonRaw          string
@@ -517,6 +545,9 @@ func newCampaignCloneCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta campaign clone", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta campaign clone", err)
+			}
 
 			overrides, err := parseKeyValueList(paramsRaw)
 			if err != nil {
@@ -640,6 +671,7 @@ func newCampaignCloneCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&sourceCampaignID, "source-campaign-id", "", "Source campaign id")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Target ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&fieldsRaw, "fields", strings.Join(marketing.DefaultCampaignCloneFields, ","), "Comma-separated fields to read from source campaign")
 	cmd.Flags().StringVar(&paramsRaw, "params", "", "Comma-separated override params (k=v,k2=v2)")
 	cmd.Flags().StringVar(&jsonRaw, "json", "", "Inline JSON object overrides")
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/testutil"
+)
+
+// TestCampaignPauseFixtures runs every fixture in
+// testdata/fixtures/campaign_pause through `meta campaign pause` and
+// compares the printed envelope with the fixture's golden file. Rerun with
+// META_UPDATE_GOLDEN=1 to record goldens.
+func TestCampaignPauseFixtures(t *testing.T) {
+	for _, fixture := range testutil.LoadFixtures(t, filepath.Join("testdata", "fixtures", "campaign_pause")) {
+		t.Run(fixture.Name, func(t *testing.T) {
+			httpClient := fixture.HTTPClient(t)
+			useCampaignDependencies(t, fixtureCredentials(fixture), fixtureGraphClient(httpClient))
+			vars := map[string]string{"SCHEMA_DIR": writeCampaignSchemaPack(t)}
+
+			args := append([]string{"pause"}, fixture.ExpandArgs(vars)...)
+			stdout, stderr, err := runFixtureCommand(NewCampaignCommand(testRuntime(fixture.Profile)), args)
+			fixture.AssertEnvelope(t, stdout, stderr, err)
+			httpClient.AssertDone(t)
+		})
+	}
+}
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -11,9 +13,11 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bilalbayram/metacli/internal/config"
 	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/output"
 )
 
 func TestNewCampaignCommandIncludesLifecycleSubcommands(t *testing.T) {
@@ -1099,8 +1103,9 @@ func TestCampaignUpdateDoesNotForceCreateRequiredOrInjectDefaults(t *testing.T)
 		t.Fatalf("execute campaign update: %v", err)
 	}
 
-	if stub.calls != 1 {
-		t.Fatalf("expected one graph call, got %d", stub.calls)
+	// The update reads the current values for its diff, then posts.
+	if stub.calls != 2 {
+		t.Fatalf("expected two graph calls, got %d", stub.calls)
 	}
 	form, err := url.ParseQuery(stub.lastBody)
 	if err != nil {
@@ -1965,3 +1970,300 @@ func testRuntimeWithOutputFormat(profile string, outputFormat string) Runtime {
 		Debug:   &debug,
 	}
 }
+
+func TestCampaignListAppliesQueryBeforeRendering(t *testing.T) {
+	schemaDir := writeCampaignSchemaPack(t)
+	for _, tc := range []struct {
+		query   string
+		wantErr string
+	}{
+		{query: "[?status=='ACTIVE'].id"},
+		{query: "length(@[0].id) | sum(@)", wantErr: "sum() expects array of numbers"},
+	} {
+		stub := &stubHTTPClient{
+			t:          t,
+			statusCode: http.StatusOK,
+			response:   `{"data":[{"id":"cmp_1","status":"ACTIVE"},{"id":"cmp_2","status":"PAUSED"}]}`,
+		}
+		useCampaignDependencies(t,
+			func(string) (*ProfileCredentials, error) {
+				return &ProfileCredentials{
+					Name:    "prod",
+					Profile: config.Profile{Domain: config.DefaultDomain, GraphVersion: config.DefaultGraphVersion},
+					Token:   "test-token",
+				}, nil
+			},
+			func() *graph.Client {
+				client := graph.NewClient(stub, "https://graph.example.com")
+				client.MaxRetries = 0
+				return client
+			},
+		)
+
+		runtime := testRuntime("prod")
+		query := tc.query
+		runtime.Query = &query
+		output := &bytes.Buffer{}
+		errOutput := &bytes.Buffer{}
+		cmd := NewCampaignCommand(runtime)
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		cmd.SetOut(output)
+		cmd.SetErr(errOutput)
+		cmd.SetArgs([]string{"list", "--account-id", "1234", "--fields", "id,status", "--schema-dir", schemaDir})
+
+		err := cmd.Execute()
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) || output.Len() != 0 {
+				t.Fatalf("expected query failure %q, got err=%v stdout=%q", tc.wantErr, err, output.String())
+			}
+			envelope := decodeEnvelope(t, errOutput.Bytes())
+			if envelope["success"] != false {
+				t.Fatalf("expected failure envelope, got %+v", envelope)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("execute campaign list: %v", err)
+		}
+		envelope := decodeEnvelope(t, output.Bytes())
+		data, ok := envelope["data"].([]any)
+		if !ok || len(data) != 1 || data[0] != "cmp_1" {
+			t.Fatalf("expected queried ids, got %#v", envelope["data"])
+		}
+	}
+}
+
+func TestCampaignCreateIDsOutputPrintsOnlyTheCampaignID(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"id":"992","name":"Launch"}`,
+	}
+	schemaDir := writeCampaignSchemaPack(t)
+	useCampaignDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{Domain: config.DefaultDomain, GraphVersion: config.DefaultGraphVersion},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	for _, runtime := range []Runtime{testRuntimeWithOutputFormat("prod", "ids"), testQuietRuntime("prod")} {
+		output := &bytes.Buffer{}
+		errOutput := &bytes.Buffer{}
+		cmd := NewCampaignCommand(runtime)
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		cmd.SetOut(output)
+		cmd.SetErr(errOutput)
+		cmd.SetArgs([]string{
+			"create",
+			"--account-id", "1234",
+			"--params", "name=Launch,objective=OUTCOME_SALES,status=PAUSED",
+			"--schema-dir", schemaDir,
+		})
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("execute campaign create: %v (stderr %q)", err, errOutput.String())
+		}
+		if output.String() != "992\n" {
+			t.Fatalf("expected bare campaign id, got %q", output.String())
+		}
+	}
+}
+
+func testQuietRuntime(profile string) Runtime {
+	runtime := testRuntime(profile)
+	quiet := true
+	runtime.Quiet = &quiet
+	return runtime
+}
+
+func TestCampaignListReportsTimeoutAsCanceled(t *testing.T) {
+	schemaDir := writeCampaignSchemaPack(t)
+	stub := &stubHTTPClient{t: t, err: errors.New("dial tcp: i/o timeout")}
+	useCampaignDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{Domain: config.DefaultDomain, GraphVersion: config.DefaultGraphVersion},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.Sleep = func(time.Duration) {}
+			return client
+		},
+	)
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	output := &bytes.Buffer{}
+	errOutput := &bytes.Buffer{}
+	cmd := NewCampaignCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"list", "--account-id", "1234", "--schema-dir", schemaDir})
+
+	if err := cmd.ExecuteContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if stub.calls != 1 {
+		t.Fatalf("expected canceled request not to be retried, got %d calls", stub.calls)
+	}
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	errorBody, _ := envelope["error"].(map[string]any)
+	diagnostics, _ := errorBody["diagnostics"].(map[string]any)
+	remediation, _ := errorBody["remediation"].(map[string]any)
+	if errorBody["class"] != "canceled" || errorBody["type"] != "canceled" || diagnostics["cancel_reason"] != CancelReasonTimeout || remediation["category"] != graph.RemediationCategoryCanceled {
+		t.Fatalf("unexpected canceled envelope %#v", errorBody)
+	}
+}
+
+func TestCampaignErrorsCarryStableErrorClass(t *testing.T) {
+	schemaDir := writeCampaignSchemaPack(t)
+
+	cases := []struct {
+		name      string
+		load      func(string) (*ProfileCredentials, error)
+		args      []string
+		response  string
+		status    int
+		wantClass string
+	}{
+		{
+			name:      "missing profile config",
+			load:      loadProfileCredentials,
+			wantClass: output.ErrorClassAuth,
+		},
+		{
+			name:      "graph rate limit",
+			response:  `{"error":{"message":"User request limit reached","type":"OAuthException","code":17}}`,
+			status:    400,
+			wantClass: output.ErrorClassRateLimited,
+		},
+		{
+			name:      "missing flag",
+			args:      []string{"list", "--schema-dir", schemaDir},
+			wantClass: output.ErrorClassInput,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			load := tc.load
+			if load == nil {
+				load = func(string) (*ProfileCredentials, error) {
+					return &ProfileCredentials{
+						Name:    "prod",
+						Profile: config.Profile{Domain: config.DefaultDomain, GraphVersion: config.DefaultGraphVersion},
+						Token:   "test-token",
+					}, nil
+				}
+			}
+			stub := &stubHTTPClient{t: t, statusCode: tc.status, response: tc.response}
+			useCampaignDependencies(t, load, func() *graph.Client {
+				client := graph.NewClient(stub, "https://graph.example.com")
+				client.MaxRetries = 0
+				return client
+			})
+
+			errOutput := &bytes.Buffer{}
+			cmd := NewCampaignCommand(testRuntime("prod"))
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(errOutput)
+			args := tc.args
+			if args == nil {
+				args = []string{"list", "--account-id", "1234", "--schema-dir", schemaDir}
+			}
+			cmd.SetArgs(args)
+			if err := cmd.Execute(); err == nil {
+				t.Fatal("expected error")
+			}
+
+			envelope := decodeEnvelope(t, errOutput.Bytes())
+			errorBody, _ := envelope["error"].(map[string]any)
+			if errorBody["class"] != tc.wantClass {
+				t.Fatalf("expected class %q, got %#v", tc.wantClass, errorBody)
+			}
+		})
+	}
+}
+
+func TestCampaignListFormatsBudgetsInAccountCurrencyForTables(t *testing.T) {
+	schemaDir := writeCampaignSchemaPack(t)
+	t.Setenv(entityCachePathEnv, filepath.Join(t.TempDir(), "entities.json"))
+	for _, tc := range []struct {
+		format    string
+		responses []adsetQueuedResponse
+		want      string
+	}{
+		{
+			format: "table",
+			responses: []adsetQueuedResponse{
+				{body: `{"data":[{"id":"cmp_1","name":"Spring","daily_budget":"150000"}]}`},
+				{
+					body: `{"id":"act_1234","currency":"TRY"}`,
+					assert: func(t *testing.T, req *http.Request, _ string) {
+						if !strings.HasSuffix(req.URL.Path, "/act_1234") || req.URL.Query().Get("fields") != "currency" {
+							t.Fatalf("expected account currency lookup, got %s", req.URL.String())
+						}
+					},
+				},
+			},
+			want: "₺1.500,00 (150000 minor units, TRY)",
+		},
+		{
+			format: "json",
+			responses: []adsetQueuedResponse{
+				{body: `{"data":[{"id":"cmp_1","name":"Spring","daily_budget":"150000"}]}`},
+			},
+			want: `"daily_budget": "150000"`,
+		},
+	} {
+		client := &adsetQueuedHTTPClient{t: t, responses: tc.responses}
+		useCampaignDependencies(t,
+			func(string) (*ProfileCredentials, error) {
+				return &ProfileCredentials{
+					Name:    "prod",
+					Profile: config.Profile{Domain: config.DefaultDomain, GraphVersion: config.DefaultGraphVersion},
+					Token:   "test-token",
+				}, nil
+			},
+			func() *graph.Client {
+				graphClient := graph.NewClient(client, "https://graph.example.com")
+				graphClient.MaxRetries = 0
+				return graphClient
+			},
+		)
+
+		output := &bytes.Buffer{}
+		cmd := NewCampaignCommand(testRuntimeWithOutputFormat("prod", tc.format))
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		cmd.SetOut(output)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"list", "--account-id", "1234", "--fields", "id,name,daily_budget", "--schema-dir", schemaDir})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("execute campaign list (%s): %v", tc.format, err)
+		}
+		if !strings.Contains(output.String(), tc.want) {
+			t.Fatalf("expected %q in %s output:\n%s", tc.want, tc.format, output.String())
+		}
+		if client.calls != len(tc.responses) {
+			t.Fatalf("expected %d graph calls for %s output, got %d", len(tc.responses), tc.format, client.calls)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/output"
+)
+
+const (
+	errorTypeCanceled = "canceled"
+
+	CancelReasonTimeout     = "timeout"
+	CancelReasonInterrupted = "interrupted"
+)
+
+// CancellationReason reports why the invocation context ended: the --timeout
+// deadline, an interrupt (Ctrl-C or SIGTERM), or "" while it is still live.
+func CancellationReason(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	switch err := ctx.Err(); {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CancelReasonTimeout
+	case errors.Is(err, context.Canceled):
+		return CancelReasonInterrupted
+	default:
+		return ""
+	}
+}
+
+// markCanceled turns the error envelope of an interrupted command into a
+// canceled error. Partial results already attached as diagnostics (bulk
+// reports, workflow state) are kept, and the original type is preserved.
+func markCanceled(errorInfo *output.ErrorInfo, reason string) {
+	summary := "The command was interrupted before it finished."
+	actions := []string{"Inspect diagnostics for work completed before the interrupt, then rerun the command."}
+	if reason == CancelReasonTimeout {
+		summary = "The command did not finish within --timeout."
+		actions = []string{"Inspect diagnostics for work completed before the deadline, then rerun with a larger --timeout."}
+	}
+	if errorInfo.Remediation != nil {
+		actions = append(actions, errorInfo.Remediation.Actions...)
+	}
+
+	diagnostics := errorInfo.Diagnostics
+	if diagnostics == nil {
+		diagnostics = map[string]any{}
+	}
+	diagnostics["cancel_reason"] = reason
+	if errorInfo.Type != "" && errorInfo.Type != "error" {
+		diagnostics["original_type"] = errorInfo.Type
+	}
+
+	errorInfo.Class = output.ErrorClassCanceled
+	errorInfo.Type = errorTypeCanceled
+	errorInfo.Message = fmt.Sprintf("command %s: %s", reason, errorInfo.Message)
+	errorInfo.Retryable = true
+	errorInfo.Diagnostics = diagnostics
+	errorInfo.Remediation = &output.Remediation{
+		Category: graph.RemediationCategoryCanceled,
+		Summary:  summary,
+		Actions:  actions,
+	}
+}
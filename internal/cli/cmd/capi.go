@@ -1,7 +1,268 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/capi"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/bilalbayram/metacli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	capiLoadProfileCredentials = loadProfileCredentials
+	capiNewGraphClient         = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+	capiNewService = func(client *graph.Client) *capi.Service {
+		return capi.New(client)
+	}
+	capiNewSchemaProvider = func(schemaDir string) schema.SchemaProvider {
+		return schema.NewProvider(schemaDir, "", "")
+	}
+	capiNow = time.Now
+)
+
+type capiSendOptions struct {
+	profile       string
+	version       string
+	pixelID       string
+	filePath      string
+	dedupFields   string
+	testEventCode string
+	batchSize     int
+	schemaDir     string
+	validateOnly  bool
+}
+
+type capiSendCommandResult struct {
+	Status     string                `json:"status"`
+	Prepared   capi.PrepareStats     `json:"prepared"`
+	Send       *capi.SendResult      `json:"send,omitempty"`
+	Events     []map[string]any      `json:"events,omitempty"`
+	Violations []capi.EventViolation `json:"violations,omitempty"`
+}
 
 func NewCAPICommand(runtime Runtime) *cobra.Command {
-	return newNamespaceBootstrapCommandForNamespace(runtime, "capi")
+	capiCmd := newNamespaceBootstrapCommandForNamespace(runtime, "capi")
+	capiCmd.AddCommand(newCAPISendCommand(runtime))
+	capiCmd.AddCommand(newCAPITestCommand(runtime))
+	capiCmd.AddCommand(newCAPIStatsCommand(runtime))
+	return capiCmd
+}
+
+func newCAPISendCommand(runtime Runtime) *cobra.Command {
+	options := capiSendOptions{}
+	cmd := &cobra.Command{
+		Use:   "send",
+		Short: "Validate, hash, and send server events from a JSON or CSV file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runCAPISend(cmd, runtime, "meta capi send", options)
+		},
+	}
+	addCAPISendFlags(cmd, &options)
+	cmd.Flags().StringVar(&options.testEventCode, "test-event-code", "", "Route events to the Events Manager Test Events tool")
+	cmd.Flags().BoolVar(&options.validateOnly, "validate-only", false, "Validate and hash events without sending them")
+	return cmd
+}
+
+func newCAPITestCommand(runtime Runtime) *cobra.Command {
+	options := capiSendOptions{}
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Send server events with a required test_event_code",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(options.testEventCode) == "" {
+				return writeCommandError(cmd, runtime, "meta capi test", errors.New("--test-event-code is required"))
+			}
+			return runCAPISend(cmd, runtime, "meta capi test", options)
+		},
+	}
+	addCAPISendFlags(cmd, &options)
+	cmd.Flags().StringVar(&options.testEventCode, "test-event-code", "", "Test event code from Events Manager")
+	return cmd
+}
+
+func newCAPIStatsCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile     string
+		version     string
+		pixelID     string
+		aggregation string
+		since       string
+		until       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Read pixel event stats",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			start, err := parseCAPIStatsTime("--since", since)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta capi stats", err)
+			}
+			end, err := parseCAPIStatsTime("--until", until)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta capi stats", err)
+			}
+			creds, resolvedVersion, err := resolveCAPIProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta capi stats", err)
+			}
+
+			result, err := capiNewService(capiNewGraphClient()).Stats(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, capi.StatsInput{
+				PixelID:     pixelID,
+				Aggregation: aggregation,
+				Start:       start,
+				End:         end,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta capi stats", err)
+			}
+			return writeSuccess(cmd, runtime, "meta capi stats", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&pixelID, "pixel-id", "", "Pixel (dataset) id")
+	cmd.Flags().StringVar(&aggregation, "aggregation", capi.StatsAggregationEvent, "Stats aggregation (for example event, event_source, match_keys)")
+	cmd.Flags().StringVar(&since, "since", "", "Start time (RFC3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&until, "until", "", "End time (RFC3339 or YYYY-MM-DD)")
+	return cmd
+}
+
+func addCAPISendFlags(cmd *cobra.Command, options *capiSendOptions) {
+	cmd.Flags().StringVar(&options.profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&options.version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&options.pixelID, "pixel-id", "", "Pixel (dataset) id")
+	cmd.Flags().StringVar(&options.filePath, "file", "", "Events file (.json or .csv with dotted headers such as user_data.em)")
+	cmd.Flags().StringVar(&options.dedupFields, "dedup-fields", "", "Comma-separated fields used to derive event_id when missing (for example custom_data.order_id)")
+	cmd.Flags().IntVar(&options.batchSize, "batch-size", capi.MaxEventsPerRequest, "Events per request (max 1000)")
+	cmd.Flags().StringVar(&options.schemaDir, "schema-dir", schema.DefaultSchemaDir(), "Schema pack root directory")
+}
+
+func runCAPISend(cmd *cobra.Command, runtime Runtime, commandName string, options capiSendOptions) error {
+	events, err := capi.LoadEventsFile(options.filePath)
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+
+	var (
+		creds           *ProfileCredentials
+		resolvedVersion = strings.TrimSpace(options.version)
+	)
+	if !options.validateOnly {
+		creds, resolvedVersion, err = resolveCAPIProfileAndVersion(runtime, options.profile, options.version)
+		if err != nil {
+			return writeCommandError(cmd, runtime, commandName, err)
+		}
+	}
+	if resolvedVersion == "" {
+		resolvedVersion = config.DefaultGraphVersion
+	}
+
+	pack, err := capiNewSchemaProvider(options.schemaDir).GetPack(capi.SchemaDomain, resolvedVersion)
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	prepared, err := capi.PrepareEvents(pack, events, capi.PrepareOptions{
+		DedupFields: csvToSlice(options.dedupFields),
+		Now:         capiNow(),
+	})
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+
+	result := capiSendCommandResult{
+		Status:   "valid",
+		Prepared: prepared.Stats,
+	}
+	if len(prepared.Violations) > 0 {
+		result.Status = "invalid"
+		result.Violations = prepared.Violations
+		return writeCAPIValidationError(cmd, runtime, commandName, result)
+	}
+	if options.validateOnly {
+		result.Events = prepared.Events
+		return writeSuccess(cmd, runtime, commandName, result, nil, nil)
+	}
+
+	sent, err := capiNewService(capiNewGraphClient()).Send(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, capi.SendInput{
+		PixelID:       options.pixelID,
+		Events:        prepared.Events,
+		TestEventCode: options.testEventCode,
+		BatchSize:     options.batchSize,
+	})
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	result.Status = "sent"
+	result.Send = sent
+	return writeSuccess(cmd, runtime, commandName, result, nil, nil)
+}
+
+func resolveCAPIProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
+	resolvedProfile := strings.TrimSpace(profile)
+	if resolvedProfile == "" {
+		resolvedProfile = runtime.ProfileName()
+	}
+	if resolvedProfile == "" {
+		return nil, "", errors.New("profile is required (--profile or global --profile)")
+	}
+
+	creds, err := capiLoadProfileCredentials(resolvedProfile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resolvedVersion := strings.TrimSpace(version)
+	if resolvedVersion == "" {
+		resolvedVersion = creds.Profile.GraphVersion
+	}
+	if resolvedVersion == "" {
+		resolvedVersion = config.DefaultGraphVersion
+	}
+	return creds, resolvedVersion, nil
+}
+
+func parseCAPIStatsTime(flag string, value string) (time.Time, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, trimmed); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.Parse("2006-01-02", trimmed)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: expected RFC3339 or YYYY-MM-DD", flag, value)
+	}
+	return parsed, nil
+}
+
+func writeCAPIValidationError(cmd *cobra.Command, runtime Runtime, commandName string, result capiSendCommandResult) error {
+	err := fmt.Errorf("capi event validation failed with %d violation(s)", len(result.Violations))
+	errorInfo := &output.ErrorInfo{
+		Type:      "capi_validation_failed",
+		Message:   err.Error(),
+		Retryable: false,
+	}
+
+	envelope, envErr := output.NewEnvelope(commandName, false, result, nil, nil, errorInfo)
+	if envErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, envErr)
+	}
+	if writeErr := writeEnvelope(cmd.ErrOrStderr(), runtime, envelope); writeErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, writeErr)
+	}
+	return err
 }
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const capiTestSchemaDir = "../../../schema-packs"
+
+func TestCAPISendValidatesHashesAndSendsEvents(t *testing.T) {
+	eventsPath := filepath.Join(t.TempDir(), "events.json")
+	payload := `[{"event_name":"Lead","event_time":1760000000,"event_id":"lead-1","action_source":"system_generated","user_data":{"em":"Jane@Example.com"}}]`
+	if err := os.WriteFile(eventsPath, []byte(payload), 0o644); err != nil {
+		t.Fatalf("write events file: %v", err)
+	}
+
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"events_received":1,"messages":[],"fbtrace_id":"trace-1"}`,
+	}
+	useCAPIDependencies(t,
+		func(profile string) (*ProfileCredentials, error) {
+			if profile != "prod" {
+				t.Fatalf("unexpected profile %q", profile)
+			}
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{GraphVersion: config.DefaultGraphVersion},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	output := &bytes.Buffer{}
+	cmd := NewCAPICommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"send",
+		"--pixel-id", "px_1",
+		"--file", eventsPath,
+		"--test-event-code", "TEST123",
+		"--schema-dir", capiTestSchemaDir,
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute capi send: %v", err)
+	}
+	requestURL, err := url.Parse(stub.lastURL)
+	if err != nil {
+		t.Fatalf("parse request url: %v", err)
+	}
+	if requestURL.Path != "/v25.0/px_1/events" {
+		t.Fatalf("unexpected path %q", requestURL.Path)
+	}
+	form, err := url.ParseQuery(stub.lastBody)
+	if err != nil {
+		t.Fatalf("parse form body: %v", err)
+	}
+	if got := form.Get("test_event_code"); got != "TEST123" {
+		t.Fatalf("unexpected test_event_code %q", got)
+	}
+	if strings.Contains(form.Get("data"), "Example.com") {
+		t.Fatalf("raw email must not be sent: %s", form.Get("data"))
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta capi send")
+	data := envelope["data"].(map[string]any)
+	if data["status"] != "sent" {
+		t.Fatalf("unexpected status %v", data["status"])
+	}
+	send := data["send"].(map[string]any)
+	if send["events_received"] != float64(1) {
+		t.Fatalf("unexpected events_received %v", send["events_received"])
+	}
+}
+
+func TestCAPISendRejectsInvalidEventsBeforeNetwork(t *testing.T) {
+	eventsPath := filepath.Join(t.TempDir(), "events.json")
+	payload := `[{"event_name":"Purchase","event_time":1760000000,"action_source":"website","user_data":{"em":"jane@example.com"}}]`
+	if err := os.WriteFile(eventsPath, []byte(payload), 0o644); err != nil {
+		t.Fatalf("write events file: %v", err)
+	}
+	useCAPIDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{Name: "prod", Token: "test-token"}, nil
+		},
+		func() *graph.Client {
+			t.Fatal("graph client should not be created when validation fails")
+			return nil
+		},
+	)
+
+	output := &bytes.Buffer{}
+	errOutput := &bytes.Buffer{}
+	cmd := NewCAPICommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"send", "--pixel-id", "px_1", "--file", eventsPath, "--schema-dir", capiTestSchemaDir})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if output.Len() != 0 {
+		t.Fatalf("expected empty stdout, got %q", output.String())
+	}
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	errorBody := envelope["error"].(map[string]any)
+	if errorBody["type"] != "capi_validation_failed" {
+		t.Fatalf("unexpected error type %v", errorBody["type"])
+	}
+	data := envelope["data"].(map[string]any)
+	violations, ok := data["violations"].([]any)
+	if !ok || len(violations) == 0 {
+		t.Fatalf("expected violations, got %#v", data["violations"])
+	}
+}
+
+func TestCAPITestRequiresTestEventCode(t *testing.T) {
+	useCAPIDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			t.Fatal("profile should not be loaded without --test-event-code")
+			return nil, nil
+		},
+		func() *graph.Client {
+			t.Fatal("graph client should not be created without --test-event-code")
+			return nil
+		},
+	)
+
+	cmd := NewCAPICommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"test", "--pixel-id", "px_1", "--file", "events.json"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--test-event-code is required") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCAPIStatsReadsPixelStats(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"start_time":"2025-10-09T08:00:00+0000","data":[{"value":"Purchase","count":12}]}]}`,
+	}
+	useCAPIDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{Name: "prod", Token: "test-token"}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	output := &bytes.Buffer{}
+	cmd := NewCAPICommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"stats", "--pixel-id", "px_1", "--since", "2025-10-09"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute capi stats: %v", err)
+	}
+	requestURL, err := url.Parse(stub.lastURL)
+	if err != nil {
+		t.Fatalf("parse request url: %v", err)
+	}
+	if requestURL.Path != "/v25.0/px_1/stats" {
+		t.Fatalf("unexpected path %q", requestURL.Path)
+	}
+	if got := requestURL.Query().Get("start_time"); got != "1759968000" {
+		t.Fatalf("unexpected start_time %q", got)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta capi stats")
+	data := envelope["data"].(map[string]any)
+	if buckets, ok := data["buckets"].([]any); !ok || len(buckets) != 1 {
+		t.Fatalf("unexpected buckets %#v", data["buckets"])
+	}
+}
+
+func useCAPIDependencies(t *testing.T, loadFn func(string) (*ProfileCredentials, error), clientFn func() *graph.Client) {
+	t.Helper()
+	originalLoad := capiLoadProfileCredentials
+	originalClient := capiNewGraphClient
+	originalNow := capiNow
+	t.Cleanup(func() {
+		capiLoadProfileCredentials = originalLoad
+		capiNewGraphClient = originalClient
+		capiNow = originalNow
+	})
+
+	capiLoadProfileCredentials = loadFn
+	capiNewGraphClient = clientFn
+	capiNow = func() time.Time {
+		return time.Unix(1760003600, 0)
+	}
+}
@@ -27,13 +27,19 @@ var (
 func NewCatalogCommand(runtime Runtime) *cobra.Command {
 	catalogCmd := &cobra.Command{
 		Use:   "catalog",
-		Short: "Catalog item upload and batch workflows",
+		Short: "Catalog, product set, feed, and item batch workflows",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return requireSubcommand(cmd, "catalog")
 		},
 	}
 	catalogCmd.AddCommand(newCatalogUploadItemsCommand(runtime))
 	catalogCmd.AddCommand(newCatalogBatchItemsCommand(runtime))
+	catalogCmd.AddCommand(newCatalogItemsBatchCommand(runtime))
+	catalogCmd.AddCommand(newCatalogListCommand(runtime))
+	catalogCmd.AddCommand(newCatalogCreateCommand(runtime))
+	catalogCmd.AddCommand(newCatalogProductSetCommand(runtime))
+	catalogCmd.AddCommand(newCatalogFeedCommand(runtime))
+	catalogCmd.AddCommand(newCatalogDiagnoseCommand(runtime))
 	return catalogCmd
 }
 
@@ -322,7 +328,7 @@ func writeCatalogBatchItemError(cmd *cobra.Command, runtime Runtime, commandName
 	if envErr != nil {
 		return fmt.Errorf("%w (secondary output error: %v)", err, envErr)
 	}
-	if writeErr := output.Write(cmd.ErrOrStderr(), selectedOutputFormat(runtime), envelope); writeErr != nil {
+	if writeErr := writeEnvelope(cmd.ErrOrStderr(), runtime, envelope); writeErr != nil {
 		return fmt.Errorf("%w (secondary output error: %v)", err, writeErr)
 	}
 	return err
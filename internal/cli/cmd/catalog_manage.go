@@ -0,0 +1,477 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+type catalogCommandFunc func(cmd *cobra.Command, service *marketing.CatalogService, creds *ProfileCredentials, version string) (any, error)
+
+func newCatalogListCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile      string
+		version      string
+		businessID   string
+		fieldsRaw    string
+		limit        int
+		followNext   bool
+		domainPolicy string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List catalogs owned by a business",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return executeCatalogCommand(cmd, runtime, "meta catalog list", profile, version, domainPolicy, func(cmd *cobra.Command, service *marketing.CatalogService, creds *ProfileCredentials, version string) (any, error) {
+				return service.ListCatalogs(cmd.Context(), version, creds.Token, creds.AppSecret, marketing.CatalogListInput{
+					BusinessID: businessID,
+					Fields:     csvToSlice(fieldsRaw),
+					Limit:      limit,
+					FollowNext: followNext,
+				})
+			})
+		},
+	}
+
+	addCatalogCommonFlags(cmd, &profile, &version, &domainPolicy)
+	cmd.Flags().StringVar(&businessID, "business-id", "", "Business id that owns the catalogs")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated Graph fields")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of catalogs to return")
+	cmd.Flags().BoolVar(&followNext, "follow-next", false, "Follow paging.next links")
+	return cmd
+}
+
+func newCatalogCreateCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile      string
+		version      string
+		businessID   string
+		name         string
+		vertical     string
+		domainPolicy string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a catalog owned by a business",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return executeCatalogCommand(cmd, runtime, "meta catalog create", profile, version, domainPolicy, func(cmd *cobra.Command, service *marketing.CatalogService, creds *ProfileCredentials, version string) (any, error) {
+				return service.CreateCatalog(cmd.Context(), version, creds.Token, creds.AppSecret, marketing.CatalogCreateInput{
+					BusinessID: businessID,
+					Name:       name,
+					Vertical:   vertical,
+				})
+			})
+		},
+	}
+
+	addCatalogCommonFlags(cmd, &profile, &version, &domainPolicy)
+	cmd.Flags().StringVar(&businessID, "business-id", "", "Business id that will own the catalog")
+	cmd.Flags().StringVar(&name, "name", "", "Catalog name")
+	cmd.Flags().StringVar(&vertical, "vertical", "", "Catalog vertical (for example commerce)")
+	return cmd
+}
+
+func newCatalogProductSetCommand(runtime Runtime) *cobra.Command {
+	productSetCmd := &cobra.Command{
+		Use:   "product-set",
+		Short: "Manage catalog product sets",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "catalog product-set")
+		},
+	}
+	productSetCmd.AddCommand(newCatalogProductSetListCommand(runtime))
+	productSetCmd.AddCommand(newCatalogProductSetCreateCommand(runtime))
+	productSetCmd.AddCommand(newCatalogProductSetDeleteCommand(runtime))
+	return productSetCmd
+}
+
+func newCatalogProductSetListCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile      string
+		version      string
+		catalogID    string
+		fieldsRaw    string
+		limit        int
+		followNext   bool
+		domainPolicy string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List product sets in a catalog",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return executeCatalogCommand(cmd, runtime, "meta catalog product-set list", profile, version, domainPolicy, func(cmd *cobra.Command, service *marketing.CatalogService, creds *ProfileCredentials, version string) (any, error) {
+				return service.ListProductSets(cmd.Context(), version, creds.Token, creds.AppSecret, marketing.CatalogEdgeListInput{
+					CatalogID:  catalogID,
+					Fields:     csvToSlice(fieldsRaw),
+					Limit:      limit,
+					FollowNext: followNext,
+				})
+			})
+		},
+	}
+
+	addCatalogCommonFlags(cmd, &profile, &version, &domainPolicy)
+	cmd.Flags().StringVar(&catalogID, "catalog-id", "", "Catalog id")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated Graph fields")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of product sets to return")
+	cmd.Flags().BoolVar(&followNext, "follow-next", false, "Follow paging.next links")
+	return cmd
+}
+
+func newCatalogProductSetCreateCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile      string
+		version      string
+		catalogID    string
+		name         string
+		filterRaw    string
+		domainPolicy string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a product set from a catalog filter",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return executeCatalogCommand(cmd, runtime, "meta catalog product-set create", profile, version, domainPolicy, func(cmd *cobra.Command, service *marketing.CatalogService, creds *ProfileCredentials, version string) (any, error) {
+				var filter map[string]any
+				if strings.TrimSpace(filterRaw) != "" {
+					if err := json.Unmarshal([]byte(filterRaw), &filter); err != nil {
+						return nil, fmt.Errorf("decode --filter payload: %w", err)
+					}
+				}
+				return service.CreateProductSet(cmd.Context(), version, creds.Token, creds.AppSecret, marketing.ProductSetCreateInput{
+					CatalogID: catalogID,
+					Name:      name,
+					Filter:    filter,
+				})
+			})
+		},
+	}
+
+	addCatalogCommonFlags(cmd, &profile, &version, &domainPolicy)
+	cmd.Flags().StringVar(&catalogID, "catalog-id", "", "Catalog id")
+	cmd.Flags().StringVar(&name, "name", "", "Product set name")
+	cmd.Flags().StringVar(&filterRaw, "filter", "", `Product set filter JSON (for example {"brand":{"eq":"Acme"}})`)
+	return cmd
+}
+
+func newCatalogProductSetDeleteCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile       string
+		version       string
+		productSetID  string
+		confirmDelete bool
+		domainPolicy  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a product set",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(productSetID) != "" && !confirmDelete {
+				return writeCommandError(cmd, runtime, "meta catalog product-set delete", fmt.Errorf("deleting product set %s is irreversible; rerun with --confirm-delete", strings.TrimSpace(productSetID)))
+			}
+			return executeCatalogCommand(cmd, runtime, "meta catalog product-set delete", profile, version, domainPolicy, func(cmd *cobra.Command, service *marketing.CatalogService, creds *ProfileCredentials, version string) (any, error) {
+				return service.DeleteProductSet(cmd.Context(), version, creds.Token, creds.AppSecret, marketing.ProductSetDeleteInput{
+					ProductSetID: productSetID,
+				})
+			})
+		},
+	}
+
+	addCatalogCommonFlags(cmd, &profile, &version, &domainPolicy)
+	cmd.Flags().StringVar(&productSetID, "product-set-id", "", "Product set id")
+	cmd.Flags().BoolVar(&confirmDelete, "confirm-delete", false, "Acknowledge that deleting the product set is irreversible")
+	return cmd
+}
+
+func newCatalogFeedCommand(runtime Runtime) *cobra.Command {
+	feedCmd := &cobra.Command{
+		Use:   "feed",
+		Short: "Manage scheduled catalog feeds",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "catalog feed")
+		},
+	}
+	feedCmd.AddCommand(newCatalogFeedListCommand(runtime))
+	feedCmd.AddCommand(newCatalogFeedCreateCommand(runtime))
+	feedCmd.AddCommand(newCatalogFeedUploadCommand(runtime))
+	return feedCmd
+}
+
+func newCatalogFeedListCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile      string
+		version      string
+		catalogID    string
+		fieldsRaw    string
+		limit        int
+		followNext   bool
+		domainPolicy string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List feeds in a catalog",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return executeCatalogCommand(cmd, runtime, "meta catalog feed list", profile, version, domainPolicy, func(cmd *cobra.Command, service *marketing.CatalogService, creds *ProfileCredentials, version string) (any, error) {
+				return service.ListFeeds(cmd.Context(), version, creds.Token, creds.AppSecret, marketing.CatalogEdgeListInput{
+					CatalogID:  catalogID,
+					Fields:     csvToSlice(fieldsRaw),
+					Limit:      limit,
+					FollowNext: followNext,
+				})
+			})
+		},
+	}
+
+	addCatalogCommonFlags(cmd, &profile, &version, &domainPolicy)
+	cmd.Flags().StringVar(&catalogID, "catalog-id", "", "Catalog id")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated Graph fields")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of feeds to return")
+	cmd.Flags().BoolVar(&followNext, "follow-next", false, "Follow paging.next links")
+	return cmd
+}
+
+func newCatalogFeedCreateCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile      string
+		version      string
+		catalogID    string
+		name         string
+		feedURL      string
+		interval     string
+		hour         int
+		weekday      string
+		domainPolicy string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a feed that Meta fetches on a schedule",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return executeCatalogCommand(cmd, runtime, "meta catalog feed create", profile, version, domainPolicy, func(cmd *cobra.Command, service *marketing.CatalogService, creds *ProfileCredentials, version string) (any, error) {
+				return service.CreateFeed(cmd.Context(), version, creds.Token, creds.AppSecret, marketing.CatalogFeedCreateInput{
+					CatalogID: catalogID,
+					Name:      name,
+					Schedule: marketing.CatalogFeedSchedule{
+						Interval: interval,
+						URL:      feedURL,
+						Hour:     hour,
+						Weekday:  weekday,
+					},
+				})
+			})
+		},
+	}
+
+	addCatalogCommonFlags(cmd, &profile, &version, &domainPolicy)
+	cmd.Flags().StringVar(&catalogID, "catalog-id", "", "Catalog id")
+	cmd.Flags().StringVar(&name, "name", "", "Feed name")
+	cmd.Flags().StringVar(&feedURL, "url", "", "Feed file URL Meta fetches on schedule")
+	cmd.Flags().StringVar(&interval, "interval", marketing.CatalogFeedIntervalDaily, "Fetch interval: hourly|daily|weekly")
+	cmd.Flags().IntVar(&hour, "hour", 0, "Hour of day (0-23) for daily and weekly fetches")
+	cmd.Flags().StringVar(&weekday, "weekday", "", "Day of week for weekly fetches (for example monday)")
+	return cmd
+}
+
+func newCatalogFeedUploadCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile      string
+		version      string
+		feedID       string
+		feedURL      string
+		domainPolicy string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "upload",
+		Short: "Trigger a one-off feed fetch from a URL",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return executeCatalogCommand(cmd, runtime, "meta catalog feed upload", profile, version, domainPolicy, func(cmd *cobra.Command, service *marketing.CatalogService, creds *ProfileCredentials, version string) (any, error) {
+				return service.UploadFeed(cmd.Context(), version, creds.Token, creds.AppSecret, marketing.CatalogFeedUploadInput{
+					FeedID: feedID,
+					URL:    feedURL,
+				})
+			})
+		},
+	}
+
+	addCatalogCommonFlags(cmd, &profile, &version, &domainPolicy)
+	cmd.Flags().StringVar(&feedID, "feed-id", "", "Feed id")
+	cmd.Flags().StringVar(&feedURL, "url", "", "Feed file URL")
+	return cmd
+}
+
+type catalogItemsBatchValidation struct {
+	Status     string                       `json:"status"`
+	ItemType   string                       `json:"item_type"`
+	TotalItems int                          `json:"total_items"`
+	Violations []marketing.CatalogItemError `json:"violations"`
+}
+
+func newCatalogItemsBatchCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile      string
+		version      string
+		catalogID    string
+		itemType     string
+		filePath     string
+		validateOnly bool
+		domainPolicy string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "items-batch",
+		Short: "Validate a catalog items_batch request file against the item schema, then send it",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(filePath) == "" {
+				return writeCommandError(cmd, runtime, "meta catalog items-batch", errors.New("--file is required"))
+			}
+			requests, err := parseCatalogBatchRequestsInput(filePath, "")
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta catalog items-batch", err)
+			}
+			violations, err := marketing.ValidateCatalogBatchRequests(itemType, requests)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta catalog items-batch", err)
+			}
+			validation := catalogItemsBatchValidation{
+				Status:     "valid",
+				ItemType:   strings.ToUpper(strings.TrimSpace(itemType)),
+				TotalItems: len(requests),
+				Violations: violations,
+			}
+			if validation.ItemType == "" {
+				validation.ItemType = "PRODUCT_ITEM"
+			}
+			if len(violations) > 0 {
+				validation.Status = "invalid"
+				return writeCatalogValidationError(cmd, runtime, validation, &marketing.CatalogBatchItemErrors{
+					Operation:  "catalog_items_batch_validation",
+					ItemErrors: violations,
+				})
+			}
+			if validateOnly {
+				return writeSuccess(cmd, runtime, "meta catalog items-batch", validation, nil, nil)
+			}
+
+			return executeCatalogCommand(cmd, runtime, "meta catalog items-batch", profile, version, domainPolicy, func(cmd *cobra.Command, service *marketing.CatalogService, creds *ProfileCredentials, version string) (any, error) {
+				return service.BatchItems(cmd.Context(), version, creds.Token, creds.AppSecret, marketing.CatalogBatchItemsInput{
+					CatalogID: catalogID,
+					ItemType:  itemType,
+					Requests:  requests,
+				})
+			})
+		},
+	}
+
+	addCatalogCommonFlags(cmd, &profile, &version, &domainPolicy)
+	cmd.Flags().StringVar(&catalogID, "catalog-id", "", "Catalog id")
+	cmd.Flags().StringVar(&itemType, "item-type", "", "Catalog item type (default PRODUCT_ITEM)")
+	cmd.Flags().StringVar(&filePath, "file", "", "Path to JSON request file")
+	cmd.Flags().BoolVar(&validateOnly, "validate-only", false, "Validate the request file without sending it")
+	return cmd
+}
+
+func addCatalogCommonFlags(cmd *cobra.Command, profile *string, version *string, domainPolicy *string) {
+	cmd.Flags().StringVar(profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(domainPolicy, "domain-policy", domainGatePolicyStrict, "Domain gating policy for non-marketing profiles: strict|skip")
+}
+
+func executeCatalogCommand(cmd *cobra.Command, runtime Runtime, commandName string, profile string, version string, domainPolicy string, run catalogCommandFunc) error {
+	if err := validateDomainGatePolicy(domainPolicy); err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	creds, resolvedVersion, err := resolveCatalogProfileAndVersion(runtime, profile, version)
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	proceed, err := enforceMarketingDomainGate(cmd, runtime, commandName, domainPolicy, creds.Profile.Domain)
+	if err != nil {
+		return err
+	}
+	if !proceed {
+		return nil
+	}
+
+	result, err := run(cmd, catalogNewService(catalogNewGraphClient()), creds, resolvedVersion)
+	if err != nil {
+		var itemErr *marketing.CatalogBatchItemErrors
+		if batchResult, ok := result.(*marketing.CatalogBatchResult); ok && errors.As(err, &itemErr) {
+			return writeCatalogBatchItemError(cmd, runtime, commandName, batchResult, itemErr)
+		}
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	return writeSuccess(cmd, runtime, commandName, result, nil, nil)
+}
+
+func writeCatalogValidationError(cmd *cobra.Command, runtime Runtime, validation catalogItemsBatchValidation, err error) error {
+	errorInfo := &output.ErrorInfo{
+		Type:      "catalog_validation_failed",
+		Message:   err.Error(),
+		Retryable: false,
+	}
+
+	envelope, envErr := output.NewEnvelope("meta catalog items-batch", false, validation, nil, nil, errorInfo)
+	if envErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, envErr)
+	}
+	if writeErr := writeEnvelope(cmd.ErrOrStderr(), runtime, envelope); writeErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, writeErr)
+	}
+	return err
+}
+
+func newCatalogDiagnoseCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile      string
+		version      string
+		catalogID    string
+		feedID       string
+		uploadLimit  int
+		sampleLimit  int
+		domainPolicy string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diagnose",
+		Short: "Summarize feed upload sessions and group item rejections by error code",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return executeCatalogCommand(cmd, runtime, "meta catalog diagnose", profile, version, domainPolicy, func(cmd *cobra.Command, service *marketing.CatalogService, creds *ProfileCredentials, version string) (any, error) {
+				return service.Diagnose(cmd.Context(), version, creds.Token, creds.AppSecret, marketing.CatalogDiagnoseInput{
+					CatalogID:   catalogID,
+					FeedID:      feedID,
+					UploadLimit: uploadLimit,
+					SampleLimit: sampleLimit,
+				})
+			})
+		},
+	}
+
+	addCatalogCommonFlags(cmd, &profile, &version, &domainPolicy)
+	cmd.Flags().StringVar(&catalogID, "catalog-id", "", "Catalog id (diagnoses every feed in the catalog)")
+	cmd.Flags().StringVar(&feedID, "feed-id", "", "Feed id (diagnoses a single feed)")
+	cmd.Flags().IntVar(&uploadLimit, "uploads", 1, "Recent upload sessions to include per feed")
+	cmd.Flags().IntVar(&sampleLimit, "samples", 3, "Error sample rows to include per error code")
+	return cmd
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestNewCatalogCommandIncludesManagementSubcommands(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewCatalogCommand(Runtime{})
+	for _, path := range [][]string{
+		{"list"},
+		{"create"},
+		{"items-batch"},
+		{"product-set", "list"},
+		{"product-set", "create"},
+		{"product-set", "delete"},
+		{"feed", "list"},
+		{"feed", "create"},
+		{"feed", "upload"},
+		{"diagnose"},
+	} {
+		sub, _, err := cmd.Find(path)
+		if err != nil {
+			t.Fatalf("find %v subcommand: %v", path, err)
+		}
+		if sub == nil || sub.Name() != path[len(path)-1] {
+			t.Fatalf("expected %v subcommand, got %#v", path, sub)
+		}
+	}
+}
+
+func TestCatalogItemsBatchRejectsSchemaViolationsBeforeNetwork(t *testing.T) {
+	payload := `[{"method":"CREATE","retailer_id":"sku_1","data":{"title":"Shirt","price":"bad"}}]`
+	payloadPath := filepath.Join(t.TempDir(), "requests.json")
+	if err := os.WriteFile(payloadPath, []byte(payload), 0o644); err != nil {
+		t.Fatalf("write payload file: %v", err)
+	}
+	useCatalogDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			t.Fatal("profile should not be loaded when validation fails")
+			return nil, nil
+		},
+		func() *graph.Client {
+			t.Fatal("graph client should not be created when validation fails")
+			return nil
+		},
+	)
+
+	output := &bytes.Buffer{}
+	errOutput := &bytes.Buffer{}
+	cmd := NewCatalogCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"items-batch", "--catalog-id", "cat_123", "--file", payloadPath})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if output.Len() != 0 {
+		t.Fatalf("expected empty stdout, got %q", output.String())
+	}
+
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	if envelope["success"] != false {
+		t.Fatalf("expected success=false, got %v", envelope["success"])
+	}
+	errorBody, ok := envelope["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected error object, got %T", envelope["error"])
+	}
+	if errorBody["type"] != "catalog_validation_failed" {
+		t.Fatalf("unexpected error type %v", errorBody["type"])
+	}
+	data, ok := envelope["data"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected data object, got %T", envelope["data"])
+	}
+	if data["status"] != "invalid" {
+		t.Fatalf("unexpected status %v", data["status"])
+	}
+	violations, ok := data["violations"].([]any)
+	if !ok || len(violations) == 0 {
+		t.Fatalf("expected violations, got %#v", data["violations"])
+	}
+}
+
+func TestCatalogItemsBatchValidateOnlySkipsNetwork(t *testing.T) {
+	payload := `[{"method":"UPDATE","retailer_id":"sku_1","data":{"price":"11.00 USD","availability":"in stock"}}]`
+	payloadPath := filepath.Join(t.TempDir(), "requests.json")
+	if err := os.WriteFile(payloadPath, []byte(payload), 0o644); err != nil {
+		t.Fatalf("write payload file: %v", err)
+	}
+	useCatalogDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			t.Fatal("profile should not be loaded for --validate-only")
+			return nil, nil
+		},
+		func() *graph.Client {
+			t.Fatal("graph client should not be created for --validate-only")
+			return nil
+		},
+	)
+
+	output := &bytes.Buffer{}
+	cmd := NewCatalogCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"items-batch", "--file", payloadPath, "--validate-only"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute catalog items-batch: %v", err)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta catalog items-batch")
+	data := envelope["data"].(map[string]any)
+	if data["status"] != "valid" || data["total_items"] != float64(1) {
+		t.Fatalf("unexpected validation payload %#v", data)
+	}
+}
+
+func TestCatalogProductSetDeleteRequiresConfirmation(t *testing.T) {
+	useCatalogDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			t.Fatal("profile should not be loaded without confirmation")
+			return nil, nil
+		},
+		func() *graph.Client {
+			t.Fatal("graph client should not be created without confirmation")
+			return nil
+		},
+	)
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewCatalogCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"product-set", "delete", "--product-set-id", "ps_1"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected confirmation error")
+	}
+	if !strings.Contains(err.Error(), "--confirm-delete") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCatalogFeedCreateSendsSchedule(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"id":"feed_1"}`,
+	}
+	useCatalogDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name: "prod",
+				Profile: config.Profile{
+					Domain:       config.DefaultDomain,
+					GraphVersion: config.DefaultGraphVersion,
+				},
+				Token: "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	output := &bytes.Buffer{}
+	cmd := NewCatalogCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"feed", "create",
+		"--catalog-id", "cat_123",
+		"--name", "Daily feed",
+		"--url", "https://example.com/feed.csv",
+		"--interval", "daily",
+		"--hour", "4",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute catalog feed create: %v", err)
+	}
+	if stub.lastMethod != http.MethodPost {
+		t.Fatalf("unexpected method %q", stub.lastMethod)
+	}
+	requestURL, err := url.Parse(stub.lastURL)
+	if err != nil {
+		t.Fatalf("parse request url: %v", err)
+	}
+	if requestURL.Path != "/v25.0/cat_123/product_feeds" {
+		t.Fatalf("unexpected path %q", requestURL.Path)
+	}
+	form, err := url.ParseQuery(stub.lastBody)
+	if err != nil {
+		t.Fatalf("parse form body: %v", err)
+	}
+	var schedule map[string]any
+	if err := json.Unmarshal([]byte(form.Get("schedule")), &schedule); err != nil {
+		t.Fatalf("decode schedule: %v", err)
+	}
+	if schedule["interval"] != "DAILY" || schedule["hour"] != float64(4) {
+		t.Fatalf("unexpected schedule %#v", schedule)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta catalog feed create")
+	data := envelope["data"].(map[string]any)
+	if data["id"] != "feed_1" {
+		t.Fatalf("unexpected feed id %v", data["id"])
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/checkpoint"
+	"github.com/spf13/cobra"
+)
+
+// checkpointFlags are the --checkpoint-file and --resume flags of commands whose
+// runs can be interrupted part way through.
+type checkpointFlags struct {
+	path   string
+	resume bool
+}
+
+func addCheckpointFlags(cmd *cobra.Command, flags *checkpointFlags, defaultPath string) {
+	cmd.Flags().StringVar(&flags.path, "checkpoint-file", "", "Checkpoint recording completed units while the run is in progress (defaults to "+defaultPath+"); removed once the run completes")
+	cmd.Flags().BoolVar(&flags.resume, "resume", false, "Continue an interrupted run from its checkpoint instead of refusing to start over it")
+}
+
+// open starts the run's checkpoint. Without a checkpoint path or default it
+// returns nil, which records nothing.
+func (f checkpointFlags) open(defaultPath string, operation string, fingerprint string) (*checkpoint.Checkpoint, error) {
+	path := strings.TrimSpace(f.path)
+	if path == "" {
+		path = strings.TrimSpace(defaultPath)
+	}
+	if path == "" {
+		if f.resume {
+			return nil, errors.New("--resume requires --checkpoint-file")
+		}
+		return nil, nil
+	}
+	return checkpoint.Open(path, operation, fingerprint, f.resume)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/cmdpolicy"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+const commandPolicyPathEnv = "META_COMMAND_POLICY_PATH"
+
+// commandPolicySystemPath is the installation-wide policy; a var so tests can
+// point it elsewhere.
+var commandPolicySystemPath = cmdpolicy.SystemPath
+
+// EnforceCommandPolicy blocks cmd before it runs when the installation or user
+// command policy denies it for the selected profile, writing a policy_blocked
+// error envelope. The installation policy cannot be overridden by the user's.
+func EnforceCommandPolicy(cmd *cobra.Command, runtime Runtime) error {
+	userPath := strings.TrimSpace(os.Getenv(commandPolicyPathEnv))
+	if userPath == "" {
+		defaultPath, err := cmdpolicy.DefaultUserPath()
+		if err != nil {
+			return err
+		}
+		userPath = defaultPath
+	}
+
+	profile := ""
+	if flag := cmd.Flags().Lookup("profile"); flag != nil {
+		profile = strings.TrimSpace(flag.Value.String())
+	}
+	if profile == "" {
+		profile = defaultProfileName()
+	}
+	subject := cmdpolicy.Subject{
+		Command: strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()),
+		Profile: profile,
+	}
+	labelsResolved := false
+
+	for _, path := range []string{commandPolicySystemPath, userPath} {
+		policy, err := cmdpolicy.Load(path)
+		if err != nil {
+			return err
+		}
+		if policy == nil {
+			continue
+		}
+		if !labelsResolved {
+			subject.Labels = profileLabels()(profile)
+			labelsResolved = true
+		}
+		decision := policy.Check(subject)
+		if decision == nil {
+			continue
+		}
+		return writeCommandError(cmd, runtime, cmd.CommandPath(), commandPolicyError(decision))
+	}
+	return nil
+}
+
+func commandPolicyError(decision *cmdpolicy.Decision) error {
+	message := fmt.Sprintf("command %q is not on the allow list", decision.Command)
+	if decision.Reason == "denied" {
+		message = fmt.Sprintf("command %q is denied by %q", decision.Command, decision.Entry)
+	}
+	if decision.Profile != "" {
+		message += fmt.Sprintf(" for profile %s", decision.Profile)
+	}
+	return &graph.APIError{
+		Type:      "command_policy_gate",
+		Code:      403200,
+		Message:   fmt.Sprintf("%s (rule %d of %s)", message, decision.Rule, decision.Source),
+		Retryable: false,
+		Diagnostics: map[string]any{
+			"decision": decision,
+		},
+		Remediation: &graph.Remediation{
+			Category: graph.RemediationCategoryPermission,
+			Summary:  "An administrator's command policy blocks this command.",
+			Actions: []string{
+				"Run the command with a profile the policy allows, or ask the administrator to change the policy.",
+			},
+		},
+	}
+}
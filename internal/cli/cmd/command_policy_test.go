@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestEnforceCommandPolicyWritesPolicyBlockedEnvelope(t *testing.T) {
+	dir := t.TempDir()
+	systemPath := filepath.Join(dir, "system.yaml")
+	userPath := filepath.Join(dir, "user.yaml")
+	t.Setenv(commandPolicyPathEnv, userPath)
+	originalSystemPath := commandPolicySystemPath
+	commandPolicySystemPath = systemPath
+	t.Cleanup(func() { commandPolicySystemPath = originalSystemPath })
+	if err := os.WriteFile(systemPath, []byte("schema_version: 1\nrules:\n  - deny: [business]\n"), 0o600); err != nil {
+		t.Fatalf("write system policy: %v", err)
+	}
+
+	run := func(args ...string) (*bytes.Buffer, error) {
+		t.Helper()
+		stderr := &bytes.Buffer{}
+		root := &cobra.Command{
+			Use:           "meta",
+			SilenceErrors: true,
+			SilenceUsage:  true,
+			PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+				return EnforceCommandPolicy(cmd, testRuntime("prod"))
+			},
+		}
+		root.PersistentFlags().String("profile", "", "Auth profile name")
+		root.AddCommand(NewBusinessCommand(testRuntime("prod")))
+		root.AddCommand(NewDebugCommand(testRuntime("prod")))
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(stderr)
+		root.SetArgs(args)
+		return stderr, root.Execute()
+	}
+
+	stderr, err := run("--profile", "prod", "business", "audit")
+	if err == nil {
+		t.Fatal("expected business audit to be blocked")
+	}
+	envelope := decodeEnvelope(t, stderr.Bytes())
+	errorBody := envelope["error"].(map[string]any)
+	if errorBody["type"] != "command_policy_gate" || errorBody["class"] != "policy_blocked" {
+		t.Fatalf("unexpected error %#v", errorBody)
+	}
+
+	if err := os.WriteFile(userPath, []byte("schema_version: 1\nrules:\n  - profiles: [prod]\n    allow: [business]\n"), 0o600); err != nil {
+		t.Fatalf("write user policy: %v", err)
+	}
+	if _, err := run("--profile", "prod", "business", "audit"); err == nil {
+		t.Fatal("expected the user policy not to override the installation policy")
+	}
+	if _, err := run("--profile", "prod", "debug", "bench", "--help"); err != nil {
+		t.Fatalf("expected help to stay available, got %v", err)
+	}
+	stderr, err = run("--profile", "prod", "debug", "bench")
+	if err == nil {
+		t.Fatal("expected debug bench to be outside the user allow list")
+	}
+	decision := decodeEnvelope(t, stderr.Bytes())["error"].(map[string]any)["diagnostics"].(map[string]any)["decision"].(map[string]any)
+	if decision["reason"] != "not_allowed" || decision["source"] != userPath {
+		t.Fatalf("unexpected decision %#v", decision)
+	}
+}
@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/commerce"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+var (
+	commerceLoadProfileCredentials = loadProfileCredentials
+	commerceNewGraphClient         = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+)
+
+func NewCommerceCommand(runtime Runtime) *cobra.Command {
+	commerceCmd := &cobra.Command{
+		Use:   "commerce",
+		Short: "Commerce order management for shops",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "commerce")
+		},
+	}
+	commerceCmd.AddCommand(newCommerceOrdersCommand(runtime))
+	return commerceCmd
+}
+
+func newCommerceOrdersCommand(runtime Runtime) *cobra.Command {
+	ordersCmd := &cobra.Command{
+		Use:   "orders",
+		Short: "List, acknowledge, ship, cancel, and refund commerce orders",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "commerce orders")
+		},
+	}
+	ordersCmd.AddCommand(newCommerceOrdersListCommand(runtime))
+	ordersCmd.AddCommand(newCommerceOrdersAcknowledgeCommand(runtime))
+	ordersCmd.AddCommand(newCommerceOrdersShipCommand(runtime))
+	ordersCmd.AddCommand(newCommerceOrdersCancelCommand(runtime))
+	ordersCmd.AddCommand(newCommerceOrdersRefundCommand(runtime))
+	return ordersCmd
+}
+
+func newCommerceOrdersListCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile      string
+		version      string
+		cmsID        string
+		statesRaw    string
+		updatedAfter int64
+		fieldsRaw    string
+		limit        int
+		followNext   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List orders of a commerce account by state",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveCommerceProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta commerce orders list", err)
+			}
+
+			result, err := commerce.New(commerceNewGraphClient()).ListOrders(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, commerce.ListOrdersOptions{
+				CMSID:        cmsID,
+				States:       csvToSlice(statesRaw),
+				UpdatedAfter: updatedAfter,
+				Fields:       csvToSlice(fieldsRaw),
+				Limit:        limit,
+				FollowNext:   followNext,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta commerce orders list", err)
+			}
+			return writeSuccess(cmd, runtime, "meta commerce orders list", result.Orders, result.Paging, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&cmsID, "cms-id", "", "Commerce account (commerce merchant settings) id")
+	cmd.Flags().StringVar(&statesRaw, "state", "CREATED", "Comma-separated order states: FB_PROCESSING|CREATED|IN_PROGRESS|COMPLETED")
+	cmd.Flags().Int64Var(&updatedAfter, "updated-after", 0, "Only orders updated after this unix timestamp")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated order fields (defaults to "+strings.Join(commerce.DefaultOrderFields, ",")+")")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of orders to return (0 = unlimited)")
+	cmd.Flags().BoolVar(&followNext, "follow-next", true, "Follow paging.next links")
+	mustMarkFlagRequired(cmd, "cms-id")
+	return cmd
+}
+
+func newCommerceOrdersAcknowledgeCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile        string
+		version        string
+		orderID        string
+		reference      string
+		idempotencyKey string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "acknowledge",
+		Short: "Acknowledge a CREATED order so it moves to IN_PROGRESS",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			mutation, err := commerce.PlanAcknowledge(commerce.AcknowledgeOptions{
+				OrderID:                orderID,
+				MerchantOrderReference: reference,
+				IdempotencyKey:         idempotencyKey,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta commerce orders acknowledge", err)
+			}
+			return runCommerceMutation(cmd, runtime, "meta commerce orders acknowledge", profile, version, mutation)
+		},
+	}
+
+	addCommerceMutationFlags(cmd, &profile, &version, &orderID, &idempotencyKey)
+	cmd.Flags().StringVar(&reference, "merchant-order-reference", "", "Merchant-side order id to attach")
+	return cmd
+}
+
+func newCommerceOrdersShipCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile        string
+		version        string
+		orderID        string
+		itemsRaw       string
+		trackingNumber string
+		carrier        string
+		shippingMethod string
+		idempotencyKey string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ship",
+		Short: "Record a shipment with tracking for order items",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			items, err := commerce.ParseLineItems(csvToSlice(itemsRaw))
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta commerce orders ship", err)
+			}
+			mutation, err := commerce.PlanShip(commerce.ShipOptions{
+				OrderID:        orderID,
+				Items:          items,
+				TrackingNumber: trackingNumber,
+				Carrier:        carrier,
+				ShippingMethod: shippingMethod,
+				IdempotencyKey: idempotencyKey,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta commerce orders ship", err)
+			}
+			return runCommerceMutation(cmd, runtime, "meta commerce orders ship", profile, version, mutation)
+		},
+	}
+
+	addCommerceMutationFlags(cmd, &profile, &version, &orderID, &idempotencyKey)
+	cmd.Flags().StringVar(&itemsRaw, "items", "", "Comma-separated RETAILER_ID:QUANTITY entries")
+	cmd.Flags().StringVar(&trackingNumber, "tracking-number", "", "Carrier tracking number")
+	cmd.Flags().StringVar(&carrier, "carrier", "", "Carrier code (for example UPS, FEDEX, USPS, DHL)")
+	cmd.Flags().StringVar(&shippingMethod, "shipping-method", "", "Optional shipping method name")
+	mustMarkFlagRequired(cmd, "items")
+	mustMarkFlagRequired(cmd, "tracking-number")
+	mustMarkFlagRequired(cmd, "carrier")
+	return cmd
+}
+
+func newCommerceOrdersCancelCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile        string
+		version        string
+		orderID        string
+		reasonCode     string
+		description    string
+		restock        bool
+		idempotencyKey string
+		confirmCancel  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel an order",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			mutation, err := commerce.PlanCancel(commerce.CancelOptions{
+				OrderID:           orderID,
+				ReasonCode:        reasonCode,
+				ReasonDescription: description,
+				RestockItems:      restock,
+				IdempotencyKey:    idempotencyKey,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta commerce orders cancel", err)
+			}
+			if !confirmCancel {
+				return writeCommandError(cmd, runtime, "meta commerce orders cancel", fmt.Errorf("%s cannot be undone; rerun with --confirm-cancel", mutation.Summary))
+			}
+			return runCommerceMutation(cmd, runtime, "meta commerce orders cancel", profile, version, mutation)
+		},
+	}
+
+	addCommerceMutationFlags(cmd, &profile, &version, &orderID, &idempotencyKey)
+	cmd.Flags().StringVar(&reasonCode, "reason", "CANCEL_REASON_OTHER", "Cancel reason: CUSTOMER_REQUESTED|OUT_OF_STOCK|INVALID_ADDRESS|SUSPICIOUS_ORDER|CANCEL_REASON_OTHER")
+	cmd.Flags().StringVar(&description, "reason-description", "", "Optional reason shown to the buyer")
+	cmd.Flags().BoolVar(&restock, "restock", true, "Return cancelled items to inventory")
+	cmd.Flags().BoolVar(&confirmCancel, "confirm-cancel", false, "Acknowledge the order cancellation")
+	return cmd
+}
+
+func newCommerceOrdersRefundCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile        string
+		version        string
+		orderID        string
+		reasonCode     string
+		reasonText     string
+		itemsRaw       string
+		shippingRefund string
+		currency       string
+		idempotencyKey string
+		confirmRefund  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "refund",
+		Short: "Refund a full order or selected items",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			items, err := commerce.ParseLineItems(csvToSlice(itemsRaw))
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta commerce orders refund", err)
+			}
+			mutation, err := commerce.PlanRefund(commerce.RefundOptions{
+				OrderID:        orderID,
+				ReasonCode:     reasonCode,
+				ReasonText:     reasonText,
+				Items:          items,
+				ShippingRefund: shippingRefund,
+				Currency:       currency,
+				IdempotencyKey: idempotencyKey,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta commerce orders refund", err)
+			}
+			if !confirmRefund {
+				return writeCommandError(cmd, runtime, "meta commerce orders refund", fmt.Errorf("%s returns buyer funds; rerun with --confirm-refund", mutation.Summary))
+			}
+			return runCommerceMutation(cmd, runtime, "meta commerce orders refund", profile, version, mutation)
+		},
+	}
+
+	addCommerceMutationFlags(cmd, &profile, &version, &orderID, &idempotencyKey)
+	cmd.Flags().StringVar(&reasonCode, "reason", "", "Refund reason: BUYERS_REMORSE|DAMAGED_GOODS|NOT_AS_DESCRIBED|QUALITY_ISSUE|WRONG_ITEM|REFUND_REASON_OTHER")
+	cmd.Flags().StringVar(&reasonText, "reason-text", "", "Optional reason shown to the buyer")
+	cmd.Flags().StringVar(&itemsRaw, "items", "", "Comma-separated RETAILER_ID:QUANTITY entries (omit to refund the full order)")
+	cmd.Flags().StringVar(&shippingRefund, "shipping-refund", "", "Additional shipping amount to refund (for example 4.99)")
+	cmd.Flags().StringVar(&currency, "currency", "", "Currency of --shipping-refund (ISO 4217)")
+	cmd.Flags().BoolVar(&confirmRefund, "confirm-refund", false, "Acknowledge the refund")
+	mustMarkFlagRequired(cmd, "reason")
+	return cmd
+}
+
+func runCommerceMutation(cmd *cobra.Command, runtime Runtime, commandName string, profile string, version string, mutation *commerce.Mutation) error {
+	creds, resolvedVersion, err := resolveCommerceProfileAndVersion(runtime, profile, version)
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	result, err := commerce.New(commerceNewGraphClient()).Apply(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, mutation)
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	return writeSuccess(cmd, runtime, commandName, result, nil, nil)
+}
+
+func addCommerceMutationFlags(cmd *cobra.Command, profile *string, version *string, orderID *string, idempotencyKey *string) {
+	cmd.Flags().StringVar(profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(orderID, "order-id", "", "Commerce order id")
+	cmd.Flags().StringVar(idempotencyKey, "idempotency-key", "", "Idempotency key (derived from the order and payload when omitted)")
+	mustMarkFlagRequired(cmd, "order-id")
+}
+
+func resolveCommerceProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
+	resolvedProfile := strings.TrimSpace(profile)
+	if resolvedProfile == "" {
+		resolvedProfile = runtime.ProfileName()
+	}
+	if resolvedProfile == "" {
+		return nil, "", errors.New("profile is required (--profile or global --profile)")
+	}
+
+	creds, err := commerceLoadProfileCredentials(resolvedProfile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resolvedVersion := strings.TrimSpace(version)
+	if resolvedVersion == "" {
+		resolvedVersion = creds.Profile.GraphVersion
+	}
+	if resolvedVersion == "" {
+		resolvedVersion = config.DefaultGraphVersion
+	}
+	return creds, resolvedVersion, nil
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestCommerceOrdersListSendsStateFilter(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"id":"order_1"}]}`,
+	}
+	useCommerceDependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewCommerceCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"orders", "list", "--cms-id", "cms_1", "--state", "created,in_progress"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute commerce orders list: %v", err)
+	}
+	parsed, err := url.Parse(stub.lastURL)
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+	if parsed.Path != "/v25.0/cms_1/commerce_orders" || parsed.Query().Get("state") != "CREATED,IN_PROGRESS" {
+		t.Fatalf("unexpected request %s", stub.lastURL)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta commerce orders list")
+}
+
+func TestCommerceOrdersShipPostsShipment(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"success":true}`,
+	}
+	useCommerceDependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewCommerceCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"orders", "ship",
+		"--order-id", "order_1",
+		"--items", "sku-1:2,sku-2",
+		"--tracking-number", "1Z999",
+		"--carrier", "ups",
+		"--idempotency-key", "ship-1",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute commerce orders ship: %v", err)
+	}
+	if stub.lastMethod != http.MethodPost || !strings.HasSuffix(stub.lastURL, "/v25.0/order_1/shipments") {
+		t.Fatalf("unexpected request %s %s", stub.lastMethod, stub.lastURL)
+	}
+	form, err := url.ParseQuery(stub.lastBody)
+	if err != nil {
+		t.Fatalf("parse form body: %v", err)
+	}
+	if form.Get("idempotency_key") != "ship-1" || form.Get("items") != `[{"retailer_id":"sku-1","quantity":2},{"retailer_id":"sku-2","quantity":1}]` {
+		t.Fatalf("unexpected form %v", form)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta commerce orders ship")
+}
+
+func TestCommerceOrdersRefundRequiresConfirmation(t *testing.T) {
+	useCommerceDependencies(t, nil)
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewCommerceCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"orders", "refund", "--order-id", "order_1", "--reason", "damaged_goods", "--items", "sku-1"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected refund without confirmation to fail")
+	}
+	if !strings.Contains(err.Error(), "refund 1x sku-1 on order order_1 (DAMAGED_GOODS)") || !strings.Contains(err.Error(), "rerun with --confirm-refund") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestCommerceOrdersCancelRequiresConfirmation(t *testing.T) {
+	useCommerceDependencies(t, nil)
+
+	cmd := NewCommerceCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"orders", "cancel", "--order-id", "order_1"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "rerun with --confirm-cancel") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestCommerceOrdersCancelWithConfirmation(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"success":true}`,
+	}
+	useCommerceDependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewCommerceCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"orders", "cancel", "--order-id", "order_1", "--reason", "out_of_stock", "--confirm-cancel"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute commerce orders cancel: %v", err)
+	}
+	if !strings.HasSuffix(stub.lastURL, "/v25.0/order_1/cancellations") {
+		t.Fatalf("unexpected url %s", stub.lastURL)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta commerce orders cancel")
+}
+
+func useCommerceDependencies(t *testing.T, stub *stubHTTPClient) {
+	t.Helper()
+	originalLoad := commerceLoadProfileCredentials
+	originalClient := commerceNewGraphClient
+	t.Cleanup(func() {
+		commerceLoadProfileCredentials = originalLoad
+		commerceNewGraphClient = originalClient
+	})
+
+	commerceLoadProfileCredentials = func(string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{
+			Name:      "prod",
+			Profile:   config.Profile{GraphVersion: "v25.0"},
+			Token:     "token",
+			AppSecret: "secret",
+		}, nil
+	}
+	commerceNewGraphClient = func() *graph.Client {
+		if stub == nil {
+			t.Fatal("graph client should not be created")
+		}
+		client := graph.NewClient(stub, "https://graph.example.com")
+		client.MaxRetries = 0
+		return client
+	}
+}
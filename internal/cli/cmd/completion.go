@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/spf13/cobra"
+)
+
+const (
+	entityCachePathEnv = "META_ENTITY_CACHE_PATH"
+	// completionLiveEnv opts completion into a Graph lookup when the local cache
+	// has nothing for the requested scope.
+	completionLiveEnv     = "META_COMPLETION_LIVE"
+	completionLiveTimeout = 3 * time.Second
+	completionLiveLimit   = 50
+)
+
+type flagCompletionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+var (
+	completionLoadProfileCredentials = loadProfileCredentials
+	completionNewGraphClient         = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+	completionNow = time.Now
+)
+
+// RegisterDynamicCompletions completes --profile, --account-id, --campaign-id and
+// --adset-id on every command under root that defines them.
+func RegisterDynamicCompletions(root *cobra.Command) {
+	completions := map[string]flagCompletionFunc{
+		"profile":     completeProfiles,
+		"account-id":  completeAccountIDs,
+		"campaign-id": completeEntityIDs(marketing.EntityKindCampaign),
+		"adset-id":    completeEntityIDs(marketing.EntityKindAdSet),
+	}
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for name, complete := range completions {
+			// Flags() holds only this command's own flags until it is parsed, so
+			// inherited flags are registered once, where they are declared.
+			if cmd.Flags().Lookup(name) == nil && cmd.PersistentFlags().Lookup(name) == nil {
+				continue
+			}
+			_ = cmd.RegisterFlagCompletionFunc(name, complete)
+		}
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+	}
+	walk(root)
+}
+
+// recordListedEntities remembers list results for completion. It is best
+// effort: a cache write failure never fails the list command.
+func recordListedEntities(profile string, kind string, accountID string, items []map[string]any) {
+	path, err := resolveEntityCachePath()
+	if err != nil {
+		return
+	}
+	entities := marketing.CachedEntitiesFromItems(accountID, items, completionNow())
+	_ = marketing.RecordEntities(path, profile, kind, entities)
+}
+
+func resolveEntityCachePath() (string, error) {
+	if envPath := strings.TrimSpace(os.Getenv(entityCachePathEnv)); envPath != "" {
+		return envPath, nil
+	}
+	return marketing.DefaultEntityCachePath()
+}
+
+func completeProfiles(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := loadCompletionConfig()
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		if strings.HasPrefix(name, toComplete) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	candidates := make([]string, 0, len(names))
+	for _, name := range names {
+		profile := cfg.Profiles[name]
+		description := strings.TrimSpace(profile.Domain + " " + profile.GraphVersion)
+		if name == cfg.DefaultProfile {
+			description = strings.TrimSpace(description + " (default)")
+		}
+		candidates = append(candidates, completionCandidate(name, description))
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeAccountIDs(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	profile := completionProfileName(cmd)
+	if profile == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cachePath, err := resolveAccountCachePath()
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var accounts []marketing.CachedAccount
+	if cache, err := marketing.LoadAccountCache(cachePath); err == nil {
+		accounts = cache.Profiles[profile].Accounts
+	}
+	if len(accounts) == 0 && completionLiveEnabled() {
+		accounts = liveCompletionAccounts(cmd, profile, cachePath)
+	}
+
+	prefix := strings.TrimPrefix(toComplete, "act_")
+	candidates := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		if strings.HasPrefix(account.AccountID, prefix) {
+			candidates = append(candidates, completionCandidate(account.AccountID, account.Name))
+		}
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeEntityIDs(kind string) flagCompletionFunc {
+	return func(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		profile := completionProfileName(cmd)
+		if profile == "" {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		cachePath, err := resolveEntityCachePath()
+		if err != nil {
+			cobra.CompDebugln(err.Error(), true)
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		accountID := strings.TrimPrefix(completionFlagValue(cmd, "account-id"), "act_")
+		campaignID := ""
+		if kind == marketing.EntityKindAdSet {
+			campaignID = completionFlagValue(cmd, "campaign-id")
+		}
+
+		var entities []marketing.CachedEntity
+		if cache, err := marketing.LoadEntityCache(cachePath); err == nil {
+			entities = filterCachedEntities(cache.Entities(profile, kind), accountID, campaignID)
+		}
+		if len(entities) == 0 && completionLiveEnabled() {
+			entities = filterCachedEntities(liveCompletionEntities(cmd, profile, kind, accountID, campaignID, cachePath), accountID, campaignID)
+		}
+
+		candidates := make([]string, 0, len(entities))
+		for _, entity := range entities {
+			if !strings.HasPrefix(entity.ID, toComplete) {
+				continue
+			}
+			description := entity.Name
+			if entity.Status != "" {
+				description = strings.TrimSpace(fmt.Sprintf("%s (%s)", entity.Name, entity.Status))
+			}
+			candidates = append(candidates, completionCandidate(entity.ID, description))
+		}
+		return candidates, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func filterCachedEntities(entities []marketing.CachedEntity, accountID string, campaignID string) []marketing.CachedEntity {
+	filtered := make([]marketing.CachedEntity, 0, len(entities))
+	for _, entity := range entities {
+		if accountID != "" && entity.AccountID != "" && entity.AccountID != accountID {
+			continue
+		}
+		if campaignID != "" && entity.CampaignID != campaignID {
+			continue
+		}
+		filtered = append(filtered, entity)
+	}
+	return filtered
+}
+
+func liveCompletionAccounts(cmd *cobra.Command, profile string, cachePath string) []marketing.CachedAccount {
+	creds, err := completionLoadProfileCredentials(profile)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil
+	}
+	ctx, cancel := completionContext(cmd)
+	defer cancel()
+
+	resolver := &marketing.AccountResolver{
+		Service:   marketing.NewAccountService(completionNewGraphClient()),
+		CachePath: cachePath,
+		TTL:       marketing.DefaultAccountCacheTTL,
+		Now:       completionNow,
+	}
+	accounts, err := resolver.Accounts(ctx, creds.Profile.GraphVersion, creds.Token, creds.AppSecret, creds.Name)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil
+	}
+	return accounts
+}
+
+// liveCompletionEntities fetches one page of campaigns or ad sets and caches it.
+// Listing needs an ad account, so nothing is fetched without --account-id.
+func liveCompletionEntities(cmd *cobra.Command, profile string, kind string, accountID string, campaignID string, cachePath string) []marketing.CachedEntity {
+	if accountID == "" {
+		return nil
+	}
+	creds, err := completionLoadProfileCredentials(profile)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil
+	}
+	ctx, cancel := completionContext(cmd)
+	defer cancel()
+
+	var items []map[string]any
+	switch kind {
+	case marketing.EntityKindCampaign:
+		result, err := marketing.NewCampaignService(completionNewGraphClient()).List(ctx, creds.Profile.GraphVersion, creds.Token, creds.AppSecret, marketing.CampaignListInput{
+			AccountID: accountID,
+			Fields:    []string{"id", "name", "status", "effective_status"},
+			Limit:     completionLiveLimit,
+		})
+		if err != nil {
+			cobra.CompDebugln(err.Error(), true)
+			return nil
+		}
+		items = result.Campaigns
+	case marketing.EntityKindAdSet:
+		result, err := marketing.NewAdSetService(completionNewGraphClient()).List(ctx, creds.Profile.GraphVersion, creds.Token, creds.AppSecret, marketing.AdSetListInput{
+			AccountID:  accountID,
+			CampaignID: campaignID,
+			Fields:     []string{"id", "name", "status", "effective_status", "campaign_id"},
+			Limit:      completionLiveLimit,
+		})
+		if err != nil {
+			cobra.CompDebugln(err.Error(), true)
+			return nil
+		}
+		items = result.AdSets
+	}
+
+	entities := marketing.CachedEntitiesFromItems(accountID, items, completionNow())
+	if err := marketing.RecordEntities(cachePath, creds.Name, kind, entities); err != nil {
+		cobra.CompDebugln(err.Error(), true)
+	}
+	return entities
+}
+
+func completionContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, completionLiveTimeout)
+}
+
+// completionProfileName resolves the profile the completed command would use:
+// its own --profile, then the global one, then the configured default.
+func completionProfileName(cmd *cobra.Command) string {
+	name := completionFlagValue(cmd, "profile")
+	if name == "" {
+		if flag := cmd.Root().PersistentFlags().Lookup("profile"); flag != nil {
+			name = strings.TrimSpace(flag.Value.String())
+		}
+	}
+	cfg, err := loadCompletionConfig()
+	if err != nil {
+		return name
+	}
+	resolved, _, err := cfg.ResolveProfile(name)
+	if err != nil {
+		return name
+	}
+	return resolved
+}
+
+func completionFlagValue(cmd *cobra.Command, name string) string {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil {
+		return ""
+	}
+	return strings.TrimSpace(flag.Value.String())
+}
+
+func loadCompletionConfig() (*config.Config, error) {
+	configPath, err := config.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return config.Load(configPath)
+}
+
+func completionLiveEnabled() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(completionLiveEnv))) {
+	case "1", "true", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+func completionCandidate(value string, description string) string {
+	description = strings.Join(strings.Fields(description), " ")
+	if description == "" {
+		return value
+	}
+	return value + "\t" + description
+}
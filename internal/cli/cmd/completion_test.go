@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/spf13/cobra"
+)
+
+func runCompletion(t *testing.T, runtime Runtime, args ...string) string {
+	t.Helper()
+	root := &cobra.Command{Use: "meta"}
+	root.PersistentFlags().String("profile", "", "")
+	root.AddCommand(NewCampaignCommand(runtime))
+	root.AddCommand(NewAdCommand(runtime))
+	RegisterDynamicCompletions(root)
+
+	output := &bytes.Buffer{}
+	root.SetOut(output)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{cobra.ShellCompRequestCmd}, args...))
+	if err := root.Execute(); err != nil {
+		t.Fatalf("complete %v: %v", args, err)
+	}
+	return output.String()
+}
+
+func TestCampaignListFeedsCampaignIDCompletion(t *testing.T) {
+	t.Setenv(entityCachePathEnv, filepath.Join(t.TempDir(), "entities.json"))
+	schemaDir := writeCampaignSchemaPack(t)
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"id":"cmp_1","name":"Spring Sale","effective_status":"ACTIVE"},{"id":"cmp_2","name":"Winter","status":"PAUSED"}]}`,
+	}
+	useCampaignDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{Domain: config.DefaultDomain, GraphVersion: config.DefaultGraphVersion},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	cmd := NewCampaignCommand(testRuntime("prod"))
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"list", "--account-id", "1234", "--schema-dir", schemaDir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("campaign list: %v", err)
+	}
+
+	completed := runCompletion(t, testRuntime("prod"), "campaign", "update", "--profile", "prod", "--campaign-id", "cmp_")
+	if !strings.Contains(completed, "cmp_1\tSpring Sale (ACTIVE)\n") || !strings.Contains(completed, "cmp_2\tWinter (PAUSED)\n") {
+		t.Fatalf("expected cached campaigns with names and statuses, got %q", completed)
+	}
+
+	completed = runCompletion(t, testRuntime("prod"), "campaign", "update", "--profile", "prod", "--campaign-id", "cmp_2")
+	if strings.Contains(completed, "cmp_1") {
+		t.Fatalf("expected completion to filter by prefix, got %q", completed)
+	}
+}
+
+func TestAdsetIDCompletionFiltersByCampaign(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "entities.json")
+	t.Setenv(entityCachePathEnv, cachePath)
+	err := marketing.RecordEntities(cachePath, "prod", marketing.EntityKindAdSet, []marketing.CachedEntity{
+		{ID: "as_1", Name: "Lookalikes", Status: "ACTIVE", AccountID: "1234", CampaignID: "cmp_1"},
+		{ID: "as_2", Name: "Retargeting", Status: "PAUSED", AccountID: "1234", CampaignID: "cmp_2"},
+	})
+	if err != nil {
+		t.Fatalf("record entities: %v", err)
+	}
+
+	completed := runCompletion(t, testRuntime("prod"), "ad", "list", "--profile", "prod", "--campaign-id", "cmp_2", "--adset-id", "")
+	if !strings.Contains(completed, "as_2\tRetargeting (PAUSED)\n") || strings.Contains(completed, "as_1") {
+		t.Fatalf("expected only ad sets of cmp_2, got %q", completed)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(completed), ":4") {
+		t.Fatalf("expected no-file-completion directive, got %q", completed)
+	}
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/auth"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configDoctorSecretStore = auth.NewSecretStore
+	configDoctorNow         = time.Now
+)
+
+type configDoctorResult struct {
+	config.Diagnosis
+	Migration *config.MigrationResult `json:"migration,omitempty"`
+	Errors    int                     `json:"errors"`
+	Warnings  int                     `json:"warnings"`
+}
+
+func NewConfigCommand(runtime Runtime) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect, repair and encrypt the local config file",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "config")
+		},
+	}
+	configCmd.AddCommand(newConfigDoctorCommand(runtime))
+	configCmd.AddCommand(newConfigEncryptCommand(runtime))
+	configCmd.AddCommand(newConfigDecryptCommand(runtime))
+	return configCmd
+}
+
+func newConfigDoctorCommand(runtime Runtime) *cobra.Command {
+	var (
+		path string
+		fix  bool
+	)
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Validate config.yaml and print fixes for every problem found",
+		Long: "Checks config.yaml against the current schema_version, every profile's required fields,\n" +
+			"and that each token_ref and app_secret_ref resolves in the secret store.\n" +
+			"With --fix, an older schema_version is migrated after backing up the original file.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			commandName := "meta config doctor"
+			configPath := strings.TrimSpace(path)
+			if configPath == "" {
+				defaultPath, err := config.DefaultPath()
+				if err != nil {
+					return writeCommandError(cmd, runtime, commandName, err)
+				}
+				configPath = defaultPath
+			}
+
+			diagnosis, err := config.Diagnose(configPath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, commandName, err)
+			}
+			result := configDoctorResult{}
+			if fix && hasConfigIssue(diagnosis, "schema_version_outdated") {
+				migration, err := config.MigrateFile(configPath, configDoctorNow())
+				if err != nil {
+					return writeCommandError(cmd, runtime, commandName, err)
+				}
+				result.Migration = &migration
+				diagnosis, err = config.Diagnose(configPath)
+				if err != nil {
+					return writeCommandError(cmd, runtime, commandName, err)
+				}
+			}
+			if diagnosis.Config != nil && !hasConfigIssue(diagnosis, "schema_version_newer") {
+				checkConfigSecretRefs(&diagnosis, configDoctorSecretStore())
+			}
+
+			result.Diagnosis = diagnosis
+			for _, issue := range diagnosis.Issues {
+				if issue.Severity == config.IssueError {
+					result.Errors++
+				} else {
+					result.Warnings++
+				}
+			}
+			if diagnosis.HasErrors() {
+				return writeConfigInvalidError(cmd, runtime, commandName, result)
+			}
+			return writeSuccess(cmd, runtime, commandName, result, nil, nil)
+		},
+	}
+	cmd.Flags().StringVar(&path, "config", "", "Config file path (default ~/.meta/config.yaml)")
+	cmd.Flags().BoolVar(&fix, "fix", false, "Migrate an older schema_version in place, keeping a backup")
+	return cmd
+}
+
+// checkConfigSecretRefs reports secret refs that are malformed or no longer
+// resolve, which otherwise only surface as a failed lookup mid-command.
+func checkConfigSecretRefs(diagnosis *config.Diagnosis, store auth.SecretStore) {
+	names := make([]string, 0, len(diagnosis.Config.Profiles))
+	for name := range diagnosis.Config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		profile := diagnosis.Config.Profiles[name]
+		for _, ref := range []struct {
+			field string
+			value string
+			fix   string
+		}{
+			{"token_ref", profile.TokenRef, tokenRestoreFix(name, profile)},
+			{"app_secret_ref", profile.AppSecretRef, fmt.Sprintf("Store the app secret again with `meta auth setup --profile %s`.", name)},
+		} {
+			if strings.TrimSpace(ref.value) == "" {
+				continue
+			}
+			if _, _, err := auth.ParseSecretRef(ref.value); err != nil {
+				diagnosis.AddIssue(config.Issue{
+					Code:     "secret_ref_invalid",
+					Severity: config.IssueError,
+					Profile:  name,
+					Message:  fmt.Sprintf("%s: %v", ref.field, err),
+					Fix:      ref.fix,
+				})
+				continue
+			}
+			if err := checkSecretAccess(store, ref.value); err != nil {
+				diagnosis.AddIssue(config.Issue{
+					Code:     "secret_ref_dangling",
+					Severity: config.IssueError,
+					Profile:  name,
+					Message:  fmt.Sprintf("%s %s does not resolve in the secret store: %v", ref.field, ref.value, err),
+					Fix:      ref.fix,
+				})
+			}
+		}
+	}
+}
+
+func tokenRestoreFix(name string, profile config.Profile) string {
+	switch {
+	case profile.TokenType == "page":
+		return fmt.Sprintf("Derive the page token again with `meta auth page-token --profile %s`.", name)
+	case profile.AuthProvider == "system_user":
+		return fmt.Sprintf("Store the system-user token again with `meta auth add system-user --profile %s`.", name)
+	case profile.AuthProvider == "app":
+		return fmt.Sprintf("Create the app token again with `meta auth app-token set --profile %s`.", name)
+	default:
+		return fmt.Sprintf("Log in again with `meta auth login --profile %s`.", name)
+	}
+}
+
+func hasConfigIssue(diagnosis config.Diagnosis, code string) bool {
+	for _, issue := range diagnosis.Issues {
+		if issue.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func writeConfigInvalidError(cmd *cobra.Command, runtime Runtime, commandName string, result configDoctorResult) error {
+	err := fmt.Errorf("config %s has %d error(s); see the fix listed for each issue", result.Path, result.Errors)
+	errorInfo := &output.ErrorInfo{
+		Type:      "config_invalid",
+		Message:   err.Error(),
+		Retryable: false,
+	}
+
+	envelope, envErr := output.NewEnvelope(commandName, false, result, nil, nil, errorInfo)
+	if envErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, envErr)
+	}
+	if writeErr := writeEnvelope(cmd.ErrOrStderr(), runtime, envelope); writeErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, writeErr)
+	}
+	return err
+}
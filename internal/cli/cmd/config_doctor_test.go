@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/auth"
+	"github.com/bilalbayram/metacli/internal/config"
+)
+
+func useConfigDoctorSecretStore(t *testing.T, store auth.SecretStore) {
+	t.Helper()
+	original := configDoctorSecretStore
+	configDoctorSecretStore = func() auth.SecretStore { return store }
+	t.Cleanup(func() {
+		configDoctorSecretStore = original
+	})
+}
+
+func configIssueCodes(t *testing.T, data map[string]any) []string {
+	t.Helper()
+	issues, ok := data["issues"].([]any)
+	if !ok {
+		t.Fatalf("expected issues array, got %T", data["issues"])
+	}
+	codes := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		codes = append(codes, issue.(map[string]any)["code"].(string))
+	}
+	return codes
+}
+
+func TestConfigDoctorPassesHealthyConfig(t *testing.T) {
+	configPath, _ := mustWriteDoctorConfig(t, "prod", "")
+	store := newMockSecretStore()
+	tokenRef, _ := auth.SecretRef("prod", auth.SecretToken)
+	appSecretRef, _ := auth.SecretRef("prod", auth.SecretAppSecret)
+	store.values[tokenRef] = "tok-123"
+	store.values[appSecretRef] = "secret-123"
+	useConfigDoctorSecretStore(t, store)
+
+	cmd := NewConfigCommand(testRuntime(""))
+	output := &bytes.Buffer{}
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"doctor", "--config", configPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute config doctor: %v", err)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta config doctor")
+	data := envelope["data"].(map[string]any)
+	if codes := configIssueCodes(t, data); len(codes) != 0 {
+		t.Fatalf("expected no issues, got %v", codes)
+	}
+	if data["errors"] != float64(0) {
+		t.Fatalf("unexpected error count: %v", data["errors"])
+	}
+}
+
+func TestConfigDoctorReportsDanglingSecretRefs(t *testing.T) {
+	configPath, _ := mustWriteDoctorConfig(t, "prod", "")
+	store := newMockSecretStore()
+	tokenRef, _ := auth.SecretRef("prod", auth.SecretToken)
+	store.values[tokenRef] = "tok-123"
+	useConfigDoctorSecretStore(t, store)
+
+	cmd := NewConfigCommand(testRuntime(""))
+	errOutput := &bytes.Buffer{}
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"doctor", "--config", configPath})
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected dangling app secret to fail")
+	}
+
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	errorBody := envelope["error"].(map[string]any)
+	if errorBody["type"] != "config_invalid" {
+		t.Fatalf("unexpected error type: %v", errorBody["type"])
+	}
+	data := envelope["data"].(map[string]any)
+	if got := strings.Join(configIssueCodes(t, data), ","); got != "secret_ref_dangling" {
+		t.Fatalf("unexpected issues: %s", got)
+	}
+	issue := data["issues"].([]any)[0].(map[string]any)
+	if issue["profile"] != "prod" || !strings.Contains(issue["fix"].(string), "meta auth setup --profile prod") {
+		t.Fatalf("unexpected issue: %v", issue)
+	}
+}
+
+func TestConfigDoctorFixMigratesPreviousSchemaVersion(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	raw := `schema_version: 1
+default_profile: prod
+profiles:
+  prod:
+    token_type: user
+    app_id: "1234567890"
+    token_ref: keychain://meta-marketing-cli/prod/token
+    app_secret_ref: keychain://meta-marketing-cli/prod/app_secret
+    scopes:
+      - ads_read
+    issued_at: 2026-01-01T00:00:00Z
+    expires_at: 2026-12-31T00:00:00Z
+`
+	if err := os.WriteFile(configPath, []byte(raw), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	store := newMockSecretStore()
+	store.values["keychain://meta-marketing-cli/prod/token"] = "tok-123"
+	store.values["keychain://meta-marketing-cli/prod/app_secret"] = "secret-123"
+	useConfigDoctorSecretStore(t, store)
+
+	originalNow := configDoctorNow
+	configDoctorNow = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { configDoctorNow = originalNow })
+
+	cmd := NewConfigCommand(testRuntime(""))
+	output := &bytes.Buffer{}
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"doctor", "--config", configPath, "--fix"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute config doctor --fix: %v", err)
+	}
+
+	data := decodeEnvelope(t, output.Bytes())["data"].(map[string]any)
+	migration := data["migration"].(map[string]any)
+	if migration["from"] != float64(1) || migration["to"] != float64(config.SchemaVersion) {
+		t.Fatalf("unexpected migration: %v", migration)
+	}
+	if _, err := os.Stat(configPath + ".v1-20261016T120000Z.bak"); err != nil {
+		t.Fatalf("expected backup: %v", err)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("load migrated config: %v", err)
+	}
+	if cfg.Profiles["prod"].AuthProvider != "facebook_login" {
+		t.Fatalf("unexpected auth provider: %q", cfg.Profiles["prod"].AuthProvider)
+	}
+}
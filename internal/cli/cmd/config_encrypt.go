@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newConfigEncryptCommand(runtime Runtime) *cobra.Command {
+	var (
+		path    string
+		keyFile string
+	)
+	cmd := &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt config.yaml at rest with a passphrase or key file",
+		Long: "Encrypts config.yaml with AES-256-GCM. The key comes from --key-file, $" + config.KeyFileEnv + " or $" + config.PassphraseEnv + ".\n" +
+			"Every later command needs the same secret in the environment to read or update the config.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runConfigEncryption(cmd, runtime, "meta config encrypt", path, keyFile, config.Encrypt)
+		},
+	}
+	cmd.Flags().StringVar(&path, "config", "", "Config file path (default ~/.meta/config.yaml)")
+	cmd.Flags().StringVar(&keyFile, "key-file", "", "File holding at least 32 bytes of key material (default $"+config.KeyFileEnv+")")
+	return cmd
+}
+
+func newConfigDecryptCommand(runtime Runtime) *cobra.Command {
+	var (
+		path    string
+		keyFile string
+	)
+	cmd := &cobra.Command{
+		Use:   "decrypt",
+		Short: "Rewrite an encrypted config.yaml as plaintext",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runConfigEncryption(cmd, runtime, "meta config decrypt", path, keyFile, config.Decrypt)
+		},
+	}
+	cmd.Flags().StringVar(&path, "config", "", "Config file path (default ~/.meta/config.yaml)")
+	cmd.Flags().StringVar(&keyFile, "key-file", "", "Key file the config was encrypted with (default $"+config.KeyFileEnv+")")
+	return cmd
+}
+
+func runConfigEncryption(cmd *cobra.Command, runtime Runtime, commandName string, path string, keyFile string, apply func(string, config.EncryptionSecret) error) error {
+	configPath := strings.TrimSpace(path)
+	if configPath == "" {
+		defaultPath, err := config.DefaultPath()
+		if err != nil {
+			return writeCommandError(cmd, runtime, commandName, err)
+		}
+		configPath = defaultPath
+	}
+
+	var (
+		secret config.EncryptionSecret
+		err    error
+	)
+	if strings.TrimSpace(keyFile) != "" {
+		secret, err = config.LoadKeyFile(keyFile)
+	} else {
+		secret, err = config.EncryptionSecretFromEnv()
+	}
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	if err := apply(configPath, secret); err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+
+	encrypted, err := config.IsEncrypted(configPath)
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	keySource := "passphrase"
+	if secret.KeyFile != nil {
+		keySource = "key_file"
+	}
+	return writeSuccess(cmd, runtime, commandName, map[string]any{
+		"status":     "ok",
+		"path":       configPath,
+		"encrypted":  encrypted,
+		"key_source": keySource,
+	}, nil, nil)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -35,7 +36,7 @@ var (
 func NewCreativeCommand(runtime Runtime) *cobra.Command {
 	creativeCmd := &cobra.Command{
 		Use:   "creative",
-		Short: "Creative upload and create workflows",
+		Short: "Creative upload, create and copy lint workflows",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return requireSubcommand(cmd, "creative")
 		},
@@ -43,16 +44,18 @@ func NewCreativeCommand(runtime Runtime) *cobra.Command {
 	creativeCmd.AddCommand(newCreativeUploadCommand(runtime))
 	creativeCmd.AddCommand(newCreativeUploadVideoCommand(runtime))
 	creativeCmd.AddCommand(newCreativeCreateCommand(runtime))
+	creativeCmd.AddCommand(newCreativeLintCommand(runtime))
 	return creativeCmd
 }
 
 func newCreativeUploadCommand(runtime Runtime) *cobra.Command {
 	var (
-		profile   string
-		version   string
-		accountID string
-		filePath  string
-		fileName  string
+		profile     string
+		version     string
+		accountID   string
+		accountName string
+		filePath    string
+		fileName    string
 	)
 
 	cmd := &cobra.Command{
@@ -64,6 +67,9 @@ func newCreativeUploadCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta creative upload", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta creative upload", err)
+			}
 
 			result, err := creativeNewService(creativeNewGraphClient()).Upload(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.CreativeUploadInput{
 				AccountID: accountID,
@@ -81,6 +87,7 @@ func newCreativeUploadCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&filePath, "file", "", "Path to creative image file")
 	cmd.Flags().StringVar(&fileName, "name", "", "Uploaded file name override")
 	return cmd
@@ -88,12 +95,15 @@ func newCreativeUploadCommand(runtime Runtime) *cobra.Command {
 
 func newCreativeCreateCommand(runtime Runtime) *cobra.Command {
 	var (
-		profile   string
-		version   string
-		accountID string
-		paramsRaw string
-		jsonRaw   string
-		schemaDir string
+		profile     string
+		version     string
+		accountID   string
+		accountName string
+		paramsRaw   string
+		jsonRaw     string
+		schemaDir   string
+		utm         utmFlags
+		utmAdSetID  string
 	)
 
 	cmd := &cobra.Command{
@@ -105,6 +115,9 @@ func newCreativeCreateCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta creative create", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta creative create", err)
+			}
 
 			form, err := parseKeyValueList(paramsRaw)
 			if err != nil {
@@ -118,6 +131,29 @@ func newCreativeCreateCommand(runtime Runtime) *cobra.Command {
 				return writeCommandError(cmd, runtime, "meta creative create", err)
 			}
 
+			tags, err := utm.render(cmd.Context(), map[string]string{
+				"account.id":    accountID,
+				"adset.id":      strings.TrimSpace(utmAdSetID),
+				"creative.name": form["name"],
+			}, utmAdSetID, func(ctx context.Context, adSetID string) (map[string]string, error) {
+				return adsetNewService(creativeNewGraphClient()).UTMVars(ctx, resolvedVersion, creds.Token, creds.AppSecret, adSetID)
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta creative create", err)
+			}
+			var taggedLinks []marketing.TaggedLink
+			if len(tags) > 0 {
+				taggedLinks, err = tagCreativeLinks(form, tags)
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta creative create", err)
+				}
+				if utm.checkURLs {
+					if err := marketing.CheckLinks(cmd.Context(), utmCheckHTTPClient(), taggedLinks); err != nil {
+						return writeCommandError(cmd, runtime, "meta creative create", err)
+					}
+				}
+			}
+
 			linter, err := newCreativeMutationLinter(creds, resolvedVersion, schemaDir)
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta creative create", err)
@@ -133,6 +169,7 @@ func newCreativeCreateCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta creative create", err)
 			}
+			result.TaggedLinks = taggedLinks
 			if err := persistTrackedResource(trackedResourceInput{
 				Command:       "meta creative create",
 				ResourceKind:  ops.ResourceKindCreative,
@@ -155,9 +192,12 @@ func newCreativeCreateCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&paramsRaw, "params", "", "Comma-separated mutation params (k=v,k2=v2)")
 	cmd.Flags().StringVar(&jsonRaw, "json", "", "Inline JSON object payload")
 	cmd.Flags().StringVar(&schemaDir, "schema-dir", schema.DefaultSchemaDir(), "Schema pack root directory")
+	addUTMFlags(cmd, &utm)
+	cmd.Flags().StringVar(&utmAdSetID, "utm-adset-id", "", "Ad set id whose ad set and campaign fill the --utm-template placeholders")
 	return cmd
 }
 
@@ -166,6 +206,7 @@ func newCreativeUploadVideoCommand(runtime Runtime) *cobra.Command {
 		profile      string
 		version      string
 		accountID    string
+		accountName  string
 		filePath     string
 		fileName     string
 		waitReady    bool
@@ -182,6 +223,9 @@ func newCreativeUploadVideoCommand(runtime Runtime) *cobra.Command {
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta creative upload-video", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta creative upload-video", err)
+			}
 
 			result, err := creativeNewService(creativeNewGraphClient()).UploadVideo(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, marketing.CreativeVideoUploadInput{
 				AccountID:    accountID,
@@ -202,10 +246,11 @@ func newCreativeUploadVideoCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&filePath, "file", "", "Path to creative video file")
 	cmd.Flags().StringVar(&fileName, "name", "", "Uploaded file name override")
 	cmd.Flags().BoolVar(&waitReady, "wait-ready", false, "Poll until video readiness is reached")
-	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Minute, "Maximum wait duration when --wait-ready is set")
+	cmd.Flags().DurationVar(&timeout, "wait-timeout", 10*time.Minute, "Maximum wait duration when --wait-ready is set")
 	cmd.Flags().DurationVar(&pollInterval, "poll-interval", 5*time.Second, "Polling interval when --wait-ready is set")
 	return cmd
 }
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/spf13/cobra"
+)
+
+func newCreativeLintCommand(runtime Runtime) *cobra.Command {
+	var (
+		title         string
+		body          string
+		description   string
+		placementsRaw string
+		strict        bool
+		packPath      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check ad copy against placement limits and policy heuristics",
+		Long: "Check a creative's title (headline), body (primary text) and description locally, without calling Graph.\n" +
+			"Copy over the hard limits is an error. Placements that truncate it, mostly-capital text, repeated ! or ?\n" +
+			"and words or phrases from the prohibited-content pack are warnings, which --strict turns into errors.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			placements, err := marketing.NormalizeCreativePlacements(csvToSlice(placementsRaw))
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta creative lint", err)
+			}
+			pack, err := resolveCreativeCopyPack(packPath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta creative lint", err)
+			}
+
+			result := marketing.LintCreativeCopy(marketing.CreativeCopyInput{
+				Title:       title,
+				Body:        body,
+				Description: description,
+			}, placements, strict, pack)
+			if len(result.Errors) > 0 {
+				return writeCommandError(cmd, runtime, "meta creative lint", errors.New(strings.Join(result.Errors, "; ")))
+			}
+
+			return writeSuccess(cmd, runtime, "meta creative lint", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&title, "title", "", "Headline text")
+	cmd.Flags().StringVar(&body, "body", "", "Primary text")
+	cmd.Flags().StringVar(&description, "description", "", "Link description text")
+	cmd.Flags().StringVar(&placementsRaw, "placements", "", "Comma-separated placements to check truncation for (default all: "+strings.Join(marketing.CreativePlacements(), ",")+")")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Treat warnings as errors")
+	cmd.Flags().StringVar(&packPath, "pack", "", "Prohibited-content pack JSON with word and phrase lists by category (defaults to ~/.meta/creative/copy_lint.json when present, else the built-in pack)")
+	return cmd
+}
+
+func resolveCreativeCopyPack(path string) (*marketing.CreativeCopyPack, error) {
+	resolvedPath := strings.TrimSpace(path)
+	if resolvedPath != "" {
+		return marketing.LoadCreativeCopyPack(resolvedPath)
+	}
+	defaultPath, err := marketing.DefaultCreativeCopyPackPath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(defaultPath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("stat creative copy pack %s: %w", defaultPath, err)
+	}
+	return marketing.LoadCreativeCopyPack(defaultPath)
+}
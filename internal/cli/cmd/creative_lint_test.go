@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func executeCreativeLint(t *testing.T, args ...string) (*bytes.Buffer, *bytes.Buffer, error) {
+	t.Helper()
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCreativeCommand(testRuntime(""))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(append([]string{"lint"}, args...))
+	err := cmd.Execute()
+	return stdout, stderr, err
+}
+
+func TestCreativeLintWritesFindingsAsWarnings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	stdout, _, err := executeCreativeLint(t,
+		"--title", "Summer sale!!",
+		"--body", "Fresh picks for the season.",
+		"--placements", "facebook_feed,audience_network",
+	)
+	if err != nil {
+		t.Fatalf("execute creative lint: %v", err)
+	}
+
+	envelope := decodeEnvelope(t, stdout.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta creative lint")
+	data := envelope["data"].(map[string]any)
+	if data["valid"] != true || data["pack"] != "builtin" {
+		t.Fatalf("unexpected result %v", data)
+	}
+	if placements := data["placements"].([]any); len(placements) != 2 || placements[1] != "audience_network" {
+		t.Fatalf("unexpected placements %v", placements)
+	}
+	warnings := data["warnings"].([]any)
+	if len(warnings) != 1 || warnings[0] != `title repeats punctuation "!!"` {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+}
+
+func TestCreativeLintStrictModeAndPackFailTheCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	packPath := filepath.Join(t.TempDir(), "copy_lint.json")
+	if err := os.WriteFile(packPath, []byte(`{"schema_version":1,"categories":[{"name":"brand","words":["cheap"]}]}`), 0o600); err != nil {
+		t.Fatalf("write pack: %v", err)
+	}
+
+	_, stderr, err := executeCreativeLint(t, "--body", "Cheap flights", "--pack", packPath, "--strict")
+	if err == nil || !strings.Contains(err.Error(), `strict mode: body contains prohibited word "cheap" (brand)`) {
+		t.Fatalf("expected strict pack error, got %v", err)
+	}
+	envelope := decodeEnvelope(t, stderr.Bytes())
+	if envelope["command"] != "meta creative lint" || envelope["success"] != false {
+		t.Fatalf("unexpected error envelope %v", envelope)
+	}
+
+	if _, _, err := executeCreativeLint(t, "--body", "x", "--placements", "tiktok"); err == nil || !strings.Contains(err.Error(), `unsupported placement "tiktok"`) {
+		t.Fatalf("expected placement error, got %v", err)
+	}
+	if _, _, err := executeCreativeLint(t); err == nil || !strings.Contains(err.Error(), "at least one of title, body or description is required") {
+		t.Fatalf("expected missing copy error, got %v", err)
+	}
+}
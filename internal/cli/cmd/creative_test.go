@@ -208,7 +208,7 @@ func TestCreativeUploadVideoWaitReadyExecutesUploadAndStatusPoll(t *testing.T) {
 		"--file", filePath,
 		"--name", "launch.mp4",
 		"--wait-ready",
-		"--timeout", "100ms",
+		"--wait-timeout", "100ms",
 		"--poll-interval", "1ms",
 	})
 
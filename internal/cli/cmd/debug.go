@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/bench"
+	"github.com/bilalbayram/metacli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+type debugBenchResult struct {
+	Report      *bench.Report      `json:"report"`
+	Baseline    string             `json:"baseline,omitempty"`
+	Regressions []bench.Regression `json:"regressions,omitempty"`
+	SavedTo     string             `json:"saved_to,omitempty"`
+	CPUProfile  string             `json:"cpu_profile,omitempty"`
+	MemProfile  string             `json:"mem_profile,omitempty"`
+}
+
+func NewDebugCommand(runtime Runtime) *cobra.Command {
+	debugCmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Profile and benchmark the CLI itself",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "debug")
+		},
+	}
+	debugCmd.AddCommand(newDebugBenchCommand(runtime))
+	return debugCmd
+}
+
+func newDebugBenchCommand(runtime Runtime) *cobra.Command {
+	var (
+		schemaDir     string
+		iterations    int
+		casesRaw      string
+		baselinePath  string
+		maxRegression float64
+		savePath      string
+		cpuProfile    string
+		memProfile    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Benchmark schema loading, lint, envelope output and the request pipeline against a local stub server",
+		Long:  "Benchmark the CLI's own hot paths: schema pack decoding and loading, lint throughput, envelope JSON serialization, and Graph requests and pagination against a local stub server. Nothing is sent to Meta. Save a report per release with --save and compare later builds with --baseline; the command fails when a case is slower per op than --max-regression allows.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if maxRegression < 0 {
+				return writeCommandError(cmd, runtime, "meta debug bench", errors.New("--max-regression must be >= 0"))
+			}
+			var baseline *bench.Report
+			if strings.TrimSpace(baselinePath) != "" {
+				loaded, err := bench.LoadReport(baselinePath)
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta debug bench", err)
+				}
+				baseline = loaded
+			}
+
+			stopCPUProfile, err := startCPUProfile(cpuProfile)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta debug bench", err)
+			}
+			report, err := bench.Run(cmd.Context(), bench.Options{
+				SchemaDir:  schemaDir,
+				Iterations: iterations,
+				Cases:      csvToSlice(casesRaw),
+			})
+			if stopErr := stopCPUProfile(); err == nil {
+				err = stopErr
+			}
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta debug bench", err)
+			}
+			if err := writeMemProfile(memProfile); err != nil {
+				return writeCommandError(cmd, runtime, "meta debug bench", err)
+			}
+			report.CLIVersion = cmd.Root().Version
+
+			result := debugBenchResult{
+				Report:     report,
+				Baseline:   strings.TrimSpace(baselinePath),
+				CPUProfile: strings.TrimSpace(cpuProfile),
+				MemProfile: strings.TrimSpace(memProfile),
+			}
+			if strings.TrimSpace(savePath) != "" {
+				if err := bench.WriteReport(savePath, report); err != nil {
+					return writeCommandError(cmd, runtime, "meta debug bench", err)
+				}
+				result.SavedTo = strings.TrimSpace(savePath)
+			}
+			result.Regressions = bench.Compare(baseline, report, maxRegression)
+			if len(result.Regressions) > 0 {
+				slowest := result.Regressions[0]
+				return writeCommandError(cmd, runtime, "meta debug bench", fmt.Errorf("%d bench case(s) regressed more than %.0f%% against %s; slowest %s went from %dns to %dns per op", len(result.Regressions), maxRegression*100, baselinePath, slowest.Name, slowest.BaselineNsOp, slowest.CurrentNsOp))
+			}
+			return writeSuccess(cmd, runtime, "meta debug bench", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&schemaDir, "schema-dir", schema.DefaultSchemaDir(), "Schema pack root directory (must hold the marketing v25.0 pack)")
+	cmd.Flags().IntVar(&iterations, "iterations", bench.DefaultIterations, "Timed iterations per case")
+	cmd.Flags().StringVar(&casesRaw, "cases", "", "Comma-separated cases to run (defaults to all: "+strings.Join(bench.Cases, ",")+")")
+	cmd.Flags().StringVar(&baselinePath, "baseline", "", "Report saved by an earlier --save (or its JSON envelope) to compare against")
+	cmd.Flags().Float64Var(&maxRegression, "max-regression", bench.DefaultMaxRegression, "Allowed slowdown per op against --baseline, as a fraction (0.25 = 25%)")
+	cmd.Flags().StringVar(&savePath, "save", "", "Write the report to this path for later --baseline comparisons")
+	cmd.Flags().StringVar(&cpuProfile, "cpu-profile", "", "Write a pprof CPU profile of the run to this path")
+	cmd.Flags().StringVar(&memProfile, "mem-profile", "", "Write a pprof heap profile to this path after the run")
+	return cmd
+}
+
+// startCPUProfile returns a no-op stop func when path is empty.
+func startCPUProfile(path string) (func() error, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return func() error { return nil }, nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create cpu profile %s: %w", path, err)
+	}
+	if err := pprof.StartCPUProfile(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("start cpu profile: %w", err)
+	}
+	return func() error {
+		pprof.StopCPUProfile()
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("close cpu profile %s: %w", path, err)
+		}
+		return nil
+	}, nil
+}
+
+func writeMemProfile(path string) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create memory profile %s: %w", path, err)
+	}
+	defer file.Close()
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		return fmt.Errorf("write memory profile %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/redact"
+)
+
+// ConfigureDebugLog prints every Graph request attempt to w when enabled.
+// Params, URLs and errors pass through the redact package first, so tokens,
+// appsecret proofs and hashed customer data never reach the log.
+func ConfigureDebugLog(w io.Writer, enabled bool) {
+	if !enabled {
+		graph.SetRequestLogger(nil)
+		return
+	}
+	var mu sync.Mutex
+	graph.SetRequestLogger(func(_ context.Context, entry graph.RequestLog) {
+		line := formatDebugRequest(entry)
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintln(w, line)
+	})
+}
+
+func formatDebugRequest(entry graph.RequestLog) string {
+	var line strings.Builder
+	fmt.Fprintf(&line, "debug: graph %s %s attempt=%d", entry.Method, redact.URL(entry.URL), entry.Attempt)
+	if entry.StatusCode > 0 {
+		fmt.Fprintf(&line, " status=%d", entry.StatusCode)
+	}
+	fmt.Fprintf(&line, " duration=%s", entry.Duration.Round(1e6))
+	if len(entry.Query) > 0 {
+		encoded, _ := json.Marshal(redact.Map(entry.Query))
+		fmt.Fprintf(&line, " query=%s", encoded)
+	}
+	if len(entry.Form) > 0 {
+		encoded, _ := json.Marshal(redact.Map(entry.Form))
+		fmt.Fprintf(&line, " form=%s", encoded)
+	}
+	if entry.Err != nil {
+		fmt.Fprintf(&line, " error=%q", redact.String(entry.Err.Error()))
+	}
+	return line.String()
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestDebugLogRedactsRequests(t *testing.T) {
+	t.Cleanup(func() { graph.SetRequestLogger(nil) })
+
+	stderr := &bytes.Buffer{}
+	ConfigureDebugLog(stderr, true)
+	stub := &stubHTTPClient{t: t, statusCode: http.StatusOK, response: `{"events_received":1}`}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+
+	const token = "EAABsbCS1iHgBAKZCZCdebugTokenValue1234567"
+	const digest = "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"
+	_, err := client.Do(context.Background(), graph.Request{
+		Method: http.MethodPost,
+		Path:   "123/events",
+		Query:  map[string]string{"access_token": token},
+		Form: map[string]string{
+			"data":            `[{"event_name":"Purchase","user_data":{"em":["` + digest + `"]}}]`,
+			"appsecret_proof": "proof-value",
+		},
+		AccessToken: token,
+		AppSecret:   "app-secret",
+	})
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+
+	logged := stderr.String()
+	if !strings.Contains(logged, "debug: graph POST https://graph.example.com/") || !strings.Contains(logged, "status=200") {
+		t.Fatalf("expected the request to be logged, got %q", logged)
+	}
+	for _, secret := range []string{token, digest, "proof-value", "app-secret"} {
+		if strings.Contains(logged, secret) {
+			t.Fatalf("debug log leaked %q: %s", secret, logged)
+		}
+	}
+
+	ConfigureDebugLog(stderr, false)
+	stderr.Reset()
+	if _, err := client.Do(context.Background(), graph.Request{Method: http.MethodGet, Path: "me"}); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected no debug output when disabled, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDebugBenchSavesReportAndProfiles(t *testing.T) {
+	dir := t.TempDir()
+	stdout := &bytes.Buffer{}
+	cmd := NewDebugCommand(testRuntime(""))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(stdout)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"bench",
+		"--schema-dir", filepath.Join("..", "..", "..", "schema-packs"),
+		"--iterations", "2",
+		"--cases", "lint,envelope_json",
+		"--save", filepath.Join(dir, "bench.json"),
+		"--cpu-profile", filepath.Join(dir, "cpu.pprof"),
+		"--mem-profile", filepath.Join(dir, "mem.pprof"),
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute debug bench: %v", err)
+	}
+
+	envelope := decodeEnvelope(t, stdout.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta debug bench")
+	results := envelope["data"].(map[string]any)["report"].(map[string]any)["results"].([]any)
+	if len(results) != 2 {
+		t.Fatalf("expected two cases, got %v", results)
+	}
+	for _, name := range []string{"bench.json", "cpu.pprof", "mem.pprof"} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || info.Size() == 0 {
+			t.Fatalf("expected %s to be written: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/checkpoint"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/declarative"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+var (
+	declarativeLoadProfileCredentials = loadProfileCredentials
+	declarativeNewGraphClient         = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+)
+
+func NewPlanCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile  string
+		version  string
+		specPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Diff a campaign spec against live state (create/update/noop per object)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveDeclarativeProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta plan", err)
+			}
+			spec, err := declarative.LoadSpec(specPath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta plan", err)
+			}
+			plan, err := declarative.New(declarativeNewGraphClient()).Plan(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, spec)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta plan", err)
+			}
+			return writeSuccess(cmd, runtime, "meta plan", plan, nil, nil)
+		},
+	}
+
+	addDeclarativeFlags(cmd, &profile, &version, &specPath)
+	return cmd
+}
+
+func NewApplyCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile  string
+		version  string
+		specPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Plan a campaign spec and apply it, rolling back on failure",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveDeclarativeProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta apply", err)
+			}
+			spec, err := declarative.LoadSpec(specPath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta apply", err)
+			}
+
+			service := declarative.New(declarativeNewGraphClient())
+			plan, err := service.Plan(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, spec)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta apply", err)
+			}
+			result, err := service.Apply(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, plan)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta apply", err)
+			}
+			return writeSuccess(cmd, runtime, "meta apply", result, nil, nil)
+		},
+	}
+
+	addDeclarativeFlags(cmd, &profile, &version, &specPath)
+	return cmd
+}
+
+type declarativeExportResult struct {
+	File    string                    `json:"file,omitempty"`
+	Summary declarative.ExportSummary `json:"summary"`
+	Spec    *declarative.Spec         `json:"spec"`
+}
+
+func NewExportCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile           string
+		version           string
+		accountID         string
+		accountName       string
+		campaignIDsRaw    string
+		outPath           string
+		campaignFieldsRaw string
+		adSetFieldsRaw    string
+		adFieldsRaw       string
+		checkpointOpts    checkpointFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Snapshot live campaigns into a declarative spec for plan/apply",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveDeclarativeProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta export", err)
+			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta export", err)
+			}
+
+			exportOptions := declarative.ExportOptions{
+				AccountID:      accountID,
+				CampaignIDs:    csvToSlice(campaignIDsRaw),
+				CampaignFields: csvToSlice(campaignFieldsRaw),
+				AdSetFields:    csvToSlice(adSetFieldsRaw),
+				AdFields:       csvToSlice(adFieldsRaw),
+			}
+			defaultCheckpoint := ""
+			if strings.TrimSpace(outPath) != "" {
+				defaultCheckpoint = checkpoint.DefaultPath(outPath)
+			}
+			exportOptions.Checkpoint, err = checkpointOpts.open(defaultCheckpoint, "export", checkpoint.Fingerprint(
+				strings.TrimPrefix(strings.TrimSpace(accountID), "act_"),
+				strings.Join(exportOptions.CampaignIDs, ","),
+				strings.Join(exportOptions.CampaignFields, ","),
+				strings.Join(exportOptions.AdSetFields, ","),
+				strings.Join(exportOptions.AdFields, ","),
+			))
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta export", err)
+			}
+
+			spec, err := declarative.New(declarativeNewGraphClient()).Export(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, exportOptions)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta export", err)
+			}
+			if strings.TrimSpace(outPath) != "" {
+				if err := declarative.WriteSpec(outPath, spec); err != nil {
+					return writeCommandError(cmd, runtime, "meta export", err)
+				}
+			}
+			if err := exportOptions.Checkpoint.Remove(); err != nil {
+				return writeCommandError(cmd, runtime, "meta export", err)
+			}
+			return writeSuccess(cmd, runtime, "meta export", declarativeExportResult{
+				File:    strings.TrimSpace(outPath),
+				Summary: spec.Summary(),
+				Spec:    spec,
+			}, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
+	cmd.Flags().StringVar(&campaignIDsRaw, "campaign-id", "", "Comma-separated campaign ids to export (defaults to every campaign in the account)")
+	cmd.Flags().StringVarP(&outPath, "file", "f", "", "Write the spec to this path (.json for JSON, YAML otherwise)")
+	cmd.Flags().StringVar(&campaignFieldsRaw, "campaign-fields", "", "Comma-separated campaign params to export (defaults to "+strings.Join(declarative.DefaultExportCampaignFields, ",")+")")
+	cmd.Flags().StringVar(&adSetFieldsRaw, "adset-fields", "", "Comma-separated ad set params to export (defaults to "+strings.Join(declarative.DefaultExportAdSetFields, ",")+")")
+	cmd.Flags().StringVar(&adFieldsRaw, "ad-fields", "", "Comma-separated ad params to export (defaults to "+strings.Join(declarative.DefaultExportAdFields, ",")+")")
+	addCheckpointFlags(cmd, &checkpointOpts, "<file>.checkpoint.json when --file is set")
+	cmd.MarkFlagsOneRequired("account-id", "account")
+	return cmd
+}
+
+func addDeclarativeFlags(cmd *cobra.Command, profile *string, version *string, specPath *string) {
+	cmd.Flags().StringVar(profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(version, "version", "", "Graph API version")
+	cmd.Flags().StringVarP(specPath, "file", "f", "", "Path to a YAML or JSON campaign spec")
+	mustMarkFlagRequired(cmd, "file")
+}
+
+func resolveDeclarativeProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
+	resolvedProfile := strings.TrimSpace(profile)
+	if resolvedProfile == "" {
+		resolvedProfile = runtime.ProfileName()
+	}
+	if resolvedProfile == "" {
+		return nil, "", errors.New("profile is required (--profile or global --profile)")
+	}
+
+	creds, err := declarativeLoadProfileCredentials(resolvedProfile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resolvedVersion := strings.TrimSpace(version)
+	if resolvedVersion == "" {
+		resolvedVersion = creds.Profile.GraphVersion
+	}
+	if resolvedVersion == "" {
+		resolvedVersion = config.DefaultGraphVersion
+	}
+	return creds, resolvedVersion, nil
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const declarativeTestSpec = `schema_version: 1
+account_id: act_123
+campaigns:
+  - name: Launch
+    params:
+      objective: OUTCOME_SALES
+      status: PAUSED
+    adsets:
+      - name: Prospecting
+        params:
+          daily_budget: 1000
+`
+
+func TestPlanCommandReportsActions(t *testing.T) {
+	specPath := writeDeclarativeSpec(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v25.0/act_123/campaigns":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []any{
+				map[string]any{"id": "c1", "name": "Launch", "objective": "OUTCOME_SALES", "status": "ACTIVE"},
+			}})
+		case "GET /v25.0/c1/adsets":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []any{}})
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	useDeclarativeDependencies(t, server)
+
+	output := &bytes.Buffer{}
+	cmd := NewPlanCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"-f", specPath})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute plan: %v", err)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta plan")
+	data, ok := envelope["data"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected object payload, got %T", envelope["data"])
+	}
+	summary, _ := data["summary"].(map[string]any)
+	if summary["create"] != float64(1) || summary["update"] != float64(1) || summary["noop"] != float64(0) {
+		t.Fatalf("unexpected summary %v", data["summary"])
+	}
+}
+
+func TestApplyCommandReportsRollbackInDiagnostics(t *testing.T) {
+	specPath := writeDeclarativeSpec(t)
+	routes := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.Method + " " + r.URL.Path
+		routes = append(routes, route)
+		switch route {
+		case "GET /v25.0/act_123/campaigns":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []any{}})
+		case "POST /v25.0/act_123/campaigns":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "c_new"})
+		case "POST /v25.0/act_123/adsets":
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"message": "invalid targeting", "type": "OAuthException", "code": 100}})
+		case "DELETE /v25.0/c_new":
+			_ = json.NewEncoder(w).Encode(map[string]any{"success": true})
+		default:
+			t.Fatalf("unexpected request %s", route)
+		}
+	}))
+	defer server.Close()
+	useDeclarativeDependencies(t, server)
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewApplyCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"--file", specPath})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected apply to fail")
+	}
+	if routes[len(routes)-1] != "DELETE /v25.0/c_new" {
+		t.Fatalf("expected created campaign to be rolled back, got %v", routes)
+	}
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	errorBody, _ := envelope["error"].(map[string]any)
+	if errorBody["type"] != "declarative_apply_failed" || !strings.Contains(errorBody["message"].(string), "rolled back 1 step(s)") {
+		t.Fatalf("unexpected error envelope %v", envelope["error"])
+	}
+}
+
+func TestExportCommandWritesSpecFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v25.0/c1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "c1", "name": "Launch", "account_id": "123", "objective": "OUTCOME_SALES"})
+		case "GET /v25.0/c1/adsets":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []any{}})
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	useDeclarativeDependencies(t, server)
+
+	outPath := filepath.Join(t.TempDir(), "launch.yaml")
+	output := &bytes.Buffer{}
+	cmd := NewExportCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--account-id", "act_123", "--campaign-id", "c1", "-f", outPath})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute export: %v", err)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta export")
+	written, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read exported spec: %v", err)
+	}
+	if !strings.Contains(string(written), "account_id: act_123") || !strings.Contains(string(written), "objective: OUTCOME_SALES") {
+		t.Fatalf("unexpected exported spec:\n%s", written)
+	}
+}
+
+func writeDeclarativeSpec(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "campaign.yaml")
+	if err := os.WriteFile(path, []byte(declarativeTestSpec), 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	return path
+}
+
+func useDeclarativeDependencies(t *testing.T, server *httptest.Server) {
+	t.Helper()
+	originalLoad := declarativeLoadProfileCredentials
+	originalClient := declarativeNewGraphClient
+	t.Cleanup(func() {
+		declarativeLoadProfileCredentials = originalLoad
+		declarativeNewGraphClient = originalClient
+	})
+
+	declarativeLoadProfileCredentials = func(string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{
+			Name:    "prod",
+			Profile: config.Profile{GraphVersion: "v25.0"},
+			Token:   "token",
+		}, nil
+	}
+	declarativeNewGraphClient = func() *graph.Client {
+		client := graph.NewClient(server.Client(), server.URL)
+		client.MaxRetries = 0
+		return client
+	}
+}
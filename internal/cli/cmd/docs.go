@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var docsNow = time.Now
+
+type manPagesResult struct {
+	OutDir  string   `json:"out_dir"`
+	Section int      `json:"section"`
+	Pages   []string `json:"pages"`
+}
+
+func NewDocsCommand(runtime Runtime) *cobra.Command {
+	docsCmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate reference documentation for the CLI",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "docs")
+		},
+	}
+	docsCmd.AddCommand(newDocsManCommand(runtime))
+	return docsCmd
+}
+
+func newDocsManCommand(runtime Runtime) *cobra.Command {
+	var (
+		outDir  string
+		section int
+	)
+	cmd := &cobra.Command{
+		Use:   "man",
+		Short: "Write a man page for every command, examples included",
+		Long: "Writes meta.1, meta-campaign.1, meta-campaign-create.1 and so on into --out-dir. The date\n" +
+			"in each page honors SOURCE_DATE_EPOCH, so packaged pages are reproducible.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if section < 1 || section > 9 {
+				return writeCommandError(cmd, runtime, "meta docs man", fmt.Errorf("--section must be between 1 and 9, got %d", section))
+			}
+			pages, err := writeManPages(cmd.Root(), outDir, section, manDate())
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta docs man", err)
+			}
+			return writeSuccess(cmd, runtime, "meta docs man", manPagesResult{OutDir: outDir, Section: section, Pages: pages}, nil, nil)
+		},
+	}
+	cmd.Flags().StringVar(&outDir, "out-dir", "", "Directory the man pages are written to (created if missing)")
+	cmd.Flags().IntVar(&section, "section", 1, "Manual section of the pages")
+	_ = cmd.MarkFlagRequired("out-dir")
+	return cmd
+}
+
+// manDate is today, or SOURCE_DATE_EPOCH when set.
+func manDate() time.Time {
+	if raw := strings.TrimSpace(os.Getenv("SOURCE_DATE_EPOCH")); raw != "" {
+		if seconds, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC()
+		}
+	}
+	return docsNow().UTC()
+}
+
+// writeManPages writes one page per available command under root and returns
+// the file names, parents before children.
+func writeManPages(root *cobra.Command, outDir string, section int, date time.Time) ([]string, error) {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create man page directory: %w", err)
+	}
+	pages := []string{}
+	var walk func(cmd *cobra.Command) error
+	walk = func(cmd *cobra.Command) error {
+		var buf bytes.Buffer
+		writeManPage(&buf, cmd, section, date)
+		name := fmt.Sprintf("%s.%d", manPageName(cmd), section)
+		if err := os.WriteFile(filepath.Join(outDir, name), buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("write man page %s: %w", name, err)
+		}
+		pages = append(pages, name)
+		for _, child := range cmd.Commands() {
+			if !child.IsAvailableCommand() || child.IsAdditionalHelpTopicCommand() {
+				continue
+			}
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(root); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+func manPageName(cmd *cobra.Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "-")
+}
+
+func writeManPage(w io.Writer, cmd *cobra.Command, section int, date time.Time) {
+	root := cmd.Root()
+	fmt.Fprintf(w, ".TH \"%s\" \"%d\" \"%s\" \"%s %s\" \"%s\"\n", strings.ToUpper(manPageName(cmd)), section, date.Format("2006-01-02"), root.Name(), root.Version, root.Short)
+
+	fmt.Fprintln(w, ".SH NAME")
+	fmt.Fprintf(w, "%s \\- %s\n", roffEscape(manPageName(cmd)), roffEscape(cmd.Short))
+
+	fmt.Fprintln(w, ".SH SYNOPSIS")
+	fmt.Fprintf(w, "\\fB%s\\fP\n", roffEscape(cmd.UseLine()))
+
+	fmt.Fprintln(w, ".SH DESCRIPTION")
+	description := cmd.Long
+	if strings.TrimSpace(description) == "" {
+		description = cmd.Short
+	}
+	writeRoffParagraphs(w, description)
+
+	if cmd.HasAvailableLocalFlags() {
+		fmt.Fprintln(w, ".SH OPTIONS")
+		writeManFlags(w, cmd.LocalFlags())
+	}
+	if cmd.HasAvailableInheritedFlags() {
+		fmt.Fprintln(w, ".SH GLOBAL OPTIONS")
+		writeManFlags(w, cmd.InheritedFlags())
+	}
+	if strings.TrimSpace(cmd.Example) != "" {
+		fmt.Fprintln(w, ".SH EXAMPLES")
+		fmt.Fprintln(w, ".PP")
+		fmt.Fprintln(w, ".RS")
+		fmt.Fprintln(w, ".nf")
+		for _, line := range strings.Split(cmd.Example, "\n") {
+			fmt.Fprintln(w, roffLine(strings.TrimPrefix(line, "  ")))
+		}
+		fmt.Fprintln(w, ".fi")
+		fmt.Fprintln(w, ".RE")
+	}
+
+	related := []string{}
+	if cmd.HasParent() {
+		related = append(related, manPageName(cmd.Parent()))
+	}
+	for _, child := range cmd.Commands() {
+		if child.IsAvailableCommand() && !child.IsAdditionalHelpTopicCommand() {
+			related = append(related, manPageName(child))
+		}
+	}
+	if len(related) > 0 {
+		fmt.Fprintln(w, ".SH SEE ALSO")
+		refs := make([]string, 0, len(related))
+		for _, name := range related {
+			refs = append(refs, fmt.Sprintf("\\fB%s\\fP(%d)", roffEscape(name), section))
+		}
+		fmt.Fprintln(w, strings.Join(refs, ", "))
+	}
+}
+
+func writeManFlags(w io.Writer, flags *pflag.FlagSet) {
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden {
+			return
+		}
+		name := "--" + flag.Name
+		if flag.Shorthand != "" {
+			name = "-" + flag.Shorthand + ", " + name
+		}
+		if kind := flag.Value.Type(); kind != "bool" {
+			name += " <" + kind + ">"
+		}
+		fmt.Fprintln(w, ".TP")
+		fmt.Fprintf(w, "\\fB%s\\fP\n", roffEscape(name))
+		usage := flag.Usage
+		if defValue := manDefault(flag.DefValue); defValue != "" {
+			usage += fmt.Sprintf(" (default %q)", defValue)
+		}
+		fmt.Fprintln(w, roffLine(usage))
+	})
+}
+
+// manDefault hides zero defaults and shortens paths under the home directory
+// of whoever generated the pages to ~.
+func manDefault(value string) string {
+	switch value {
+	case "", "false", "0", "0s", "[]":
+		return ""
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" && strings.HasPrefix(value, home+string(filepath.Separator)) {
+		return "~" + strings.TrimPrefix(value, home)
+	}
+	return value
+}
+
+func writeRoffParagraphs(w io.Writer, text string) {
+	for _, paragraph := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		fmt.Fprintln(w, ".PP")
+		for _, line := range strings.Split(paragraph, "\n") {
+			fmt.Fprintln(w, roffLine(line))
+		}
+	}
+}
+
+// roffLine escapes text and keeps a leading dot or quote from being read as
+// a request.
+func roffLine(text string) string {
+	escaped := roffEscape(text)
+	if strings.HasPrefix(escaped, ".") || strings.HasPrefix(escaped, "'") {
+		return "\\&" + escaped
+	}
+	return escaped
+}
+
+func roffEscape(text string) string {
+	return strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(text)
+}
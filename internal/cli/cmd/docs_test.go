@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestDocsManWritesAPagePerCommand(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1792108800")
+
+	root := &cobra.Command{Use: "meta", Short: "Meta Marketing CLI", Version: "v1.2.3"}
+	root.PersistentFlags().String("output", "json", "Output format")
+	campaign := &cobra.Command{Use: "campaign", Short: "Campaign commands"}
+	create := &cobra.Command{
+		Use:     "create",
+		Short:   "Create a campaign",
+		Long:    "Creates a campaign.\n\n.dotted lines stay text.",
+		Example: "  # Create a paused campaign.\n  meta campaign create --params status=PAUSED",
+		RunE:    func(*cobra.Command, []string) error { return nil },
+	}
+	create.Flags().String("params", "", "Comma-separated mutation params")
+	campaign.AddCommand(create)
+	root.AddCommand(campaign, &cobra.Command{Use: "internal", Hidden: true, Run: func(*cobra.Command, []string) {}})
+	root.AddCommand(NewDocsCommand(testRuntime("")))
+
+	outDir := filepath.Join(t.TempDir(), "man")
+	stdout := &bytes.Buffer{}
+	root.SetOut(stdout)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"docs", "man", "--out-dir", outDir})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	envelope := decodeEnvelope(t, stdout.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta docs man")
+	pages := envelope["data"].(map[string]any)["pages"].([]any)
+	for _, want := range []string{"meta.1", "meta-campaign.1", "meta-campaign-create.1", "meta-docs-man.1"} {
+		if !containsAny(pages, want) {
+			t.Fatalf("expected page %s in %v", want, pages)
+		}
+	}
+	if containsAny(pages, "meta-internal.1") {
+		t.Fatalf("hidden command got a page: %v", pages)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(outDir, "meta-campaign-create.1"))
+	if err != nil {
+		t.Fatalf("read page: %v", err)
+	}
+	page := string(raw)
+	for _, want := range []string{
+		`.TH "META-CAMPAIGN-CREATE" "1" "2026-10-16" "meta v1.2.3" "Meta Marketing CLI"`,
+		"meta\\-campaign\\-create \\- Create a campaign",
+		"\\&.dotted lines stay text.",
+		".SH OPTIONS\n.TP\n\\fB\\-\\-params <string>\\fP\nComma\\-separated mutation params\n",
+		".SH GLOBAL OPTIONS\n.TP\n\\fB\\-\\-output <string>\\fP\nOutput format (default \"json\")\n",
+		".SH EXAMPLES\n.PP\n.RS\n.nf\n# Create a paused campaign.\nmeta campaign create \\-\\-params status=PAUSED\n.fi\n",
+		".SH SEE ALSO\n\\fBmeta\\-campaign\\fP(1)\n",
+	} {
+		if !strings.Contains(page, want) {
+			t.Fatalf("page missing %q:\n%s", want, page)
+		}
+	}
+}
+
+func containsAny(values []any, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+// FlagProvenance is a flag value and where it came from: flag, env, project,
+// profile or default.
+type FlagProvenance struct {
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// dryRunPlan replaces the data of every envelope written during a global
+// --dry-run.
+type dryRunPlan struct {
+	DryRun    bool                      `json:"dry_run"`
+	Command   string                    `json:"command"`
+	Profile   string                    `json:"profile,omitempty"`
+	Mutations []graph.PlannedMutation   `json:"mutations"`
+	Flags     map[string]FlagProvenance `json:"flags,omitempty"`
+	// Result is what the command would have returned, with simulated ids in
+	// place of the ones Meta would assign.
+	Result any `json:"result"`
+}
+
+type dryRunProvenanceKey struct{}
+
+// EnableDryRun puts the command in dry-run mode: mutations are planned instead
+// of sent, and the success envelope carries the plan. provenance records where
+// each flag value came from.
+func EnableDryRun(cmd *cobra.Command, provenance map[string]FlagProvenance) {
+	ctx := graph.WithDryRun(cmd.Context(), &graph.DryRun{})
+	cmd.SetContext(context.WithValue(ctx, dryRunProvenanceKey{}, provenance))
+}
+
+// dryRunResult wraps data in the plan when cmd runs under --dry-run.
+func dryRunResult(cmd *cobra.Command, data any) any {
+	ctx := cmd.Context()
+	dryRun := graph.DryRunFromContext(ctx)
+	if dryRun == nil {
+		return data
+	}
+	invocation := audit.InvocationFromContext(ctx)
+	provenance, _ := ctx.Value(dryRunProvenanceKey{}).(map[string]FlagProvenance)
+	mutations := dryRun.Mutations()
+	if mutations == nil {
+		mutations = []graph.PlannedMutation{}
+	}
+	return dryRunPlan{
+		DryRun:    true,
+		Command:   invocation.Command,
+		Profile:   invocation.Profile,
+		Mutations: mutations,
+		Flags:     provenance,
+		Result:    data,
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+func TestDryRunReturnsPlanInsteadOfMutating(t *testing.T) {
+	stub := &stubHTTPClient{t: t, statusCode: http.StatusOK, response: `{"success":true}`}
+	schemaDir := writeCampaignSchemaPack(t)
+	useCampaignDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{Domain: config.DefaultDomain, GraphVersion: config.DefaultGraphVersion},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	output := &bytes.Buffer{}
+	root := &cobra.Command{
+		Use:           "meta",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+			ConfigureAuditLog(cmd)
+			EnableDryRun(cmd, map[string]FlagProvenance{"campaign-id": {Value: "777", Source: "flag"}})
+		},
+	}
+	root.PersistentFlags().String("profile", "", "Auth profile name")
+	root.AddCommand(NewCampaignCommand(testRuntime("prod")))
+	root.SetOut(output)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"--profile", "prod", "campaign", "pause", "--campaign-id", "777", "--schema-dir", schemaDir})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("campaign pause: %v", err)
+	}
+	if stub.calls != 0 {
+		t.Fatalf("expected no request to be sent, got %d", stub.calls)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta campaign pause")
+	plan := envelope["data"].(map[string]any)
+	mutations, ok := plan["mutations"].([]any)
+	if plan["dry_run"] != true || plan["profile"] != "prod" || !ok || len(mutations) != 1 {
+		t.Fatalf("unexpected plan %#v", plan)
+	}
+	mutation := mutations[0].(map[string]any)
+	params := mutation["params"].(map[string]any)
+	if mutation["method"] != http.MethodPost || mutation["path"] != "777" || params["status"] != "PAUSED" {
+		t.Fatalf("unexpected planned mutation %#v", mutation)
+	}
+	if plan["flags"].(map[string]any)["campaign-id"].(map[string]any)["source"] != "flag" {
+		t.Fatalf("expected flag provenance, got %#v", plan["flags"])
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/output"
+)
+
+// classifiedError tags a plain error with the error.class it surfaces as.
+type classifiedError struct {
+	class string
+	err   error
+}
+
+func (e *classifiedError) Error() string {
+	if e == nil || e.err == nil {
+		return ""
+	}
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.err
+}
+
+func withErrorClass(class string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{class: class, err: err}
+}
+
+// commandErrorClass picks error.class for errors that did not come back from
+// the Graph API. Unmarked plain errors are flag, argument or local
+// configuration failures, so they default to input_error.
+func commandErrorClass(err error) string {
+	var classified *classifiedError
+	if errors.As(err, &classified) {
+		return classified.class
+	}
+	var transientErr *graph.TransientError
+	if errors.As(err, &transientErr) {
+		return output.ErrorClassTransient
+	}
+	return output.ErrorClassInput
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// Helpers shared by the fixture tests scaffolded with
+// `go run ./internal/testutil/testgen`.
+
+// fixtureCredentials loads the fixture's profile with a test token on the
+// default domain and Graph version.
+func fixtureCredentials(fixture *testutil.Fixture) func(string) (*ProfileCredentials, error) {
+	return func(string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{
+			Name: fixture.Profile,
+			Profile: config.Profile{
+				Domain:       config.DefaultDomain,
+				GraphVersion: config.DefaultGraphVersion,
+			},
+			Token: "test-token",
+		}, nil
+	}
+}
+
+// fixtureGraphClient sends through httpClient without retries, so every
+// exchange of a fixture is exactly one request.
+func fixtureGraphClient(httpClient graph.HTTPClient) func() *graph.Client {
+	return func() *graph.Client {
+		client := graph.NewClient(httpClient, "https://graph.example.com")
+		client.MaxRetries = 0
+		return client
+	}
+}
+
+// runFixtureCommand executes cmd with args and returns what it printed.
+func runFixtureCommand(cmd *cobra.Command, args []string) ([]byte, []byte, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/bilalbayram/metacli/pkg/metacli"
+)
+
+const freezeWindowsPathEnv = metacli.FreezePathEnv
+
+var freezeNow = time.Now
+
+// ConfigureFreezeWindows installs the guard that blocks mutations inside a
+// configured freeze window unless the invocation breaks glass.
+func ConfigureFreezeWindows() error {
+	return metacli.InstallFreezeWindows(freezeNow)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+func TestFreezeWindowBlocksUntilBreakGlass(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "mutations.jsonl")
+	freezePath := filepath.Join(dir, "freeze.yaml")
+	t.Setenv(auditLogPathEnv, logPath)
+	t.Setenv(freezeWindowsPathEnv, freezePath)
+	t.Cleanup(func() {
+		graph.SetMutationRecorder(nil)
+		graph.SetMutationGuard("freeze", nil)
+	})
+	originalNow := freezeNow
+	freezeNow = func() time.Time { return time.Date(2026, 11, 28, 9, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { freezeNow = originalNow })
+	if err := os.WriteFile(freezePath, []byte("schema_version: 1\nwindows:\n  - name: black-friday\n    start: 2026-11-27\n    end: 2026-12-01\n"), 0o600); err != nil {
+		t.Fatalf("write freeze windows: %v", err)
+	}
+
+	stub := &stubHTTPClient{t: t, statusCode: http.StatusOK, response: `{"success":true}`}
+	schemaDir := writeCampaignSchemaPack(t)
+	useCampaignDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{Domain: config.DefaultDomain, GraphVersion: config.DefaultGraphVersion},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	run := func(args ...string) error {
+		t.Helper()
+		root := &cobra.Command{
+			Use:           "meta",
+			SilenceErrors: true,
+			SilenceUsage:  true,
+			PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+				ConfigureAuditLog(cmd)
+				return ConfigureFreezeWindows()
+			},
+		}
+		root.PersistentFlags().String("profile", "", "Auth profile name")
+		root.PersistentFlags().String("break-glass", "", "Break-glass reason")
+		root.AddCommand(NewCampaignCommand(testRuntime("prod")))
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs(args)
+		return root.Execute()
+	}
+
+	err := run("--profile", "prod", "campaign", "pause", "--campaign-id", "777", "--schema-dir", schemaDir)
+	if err == nil || !strings.Contains(err.Error(), `freeze window "black-friday"`) || stub.calls != 0 {
+		t.Fatalf("expected freeze to block the pause, got %v (calls=%d)", err, stub.calls)
+	}
+
+	if err := run("--profile", "prod", "--break-glass", "checkout outage", "campaign", "pause", "--campaign-id", "777", "--schema-dir", schemaDir); err != nil {
+		t.Fatalf("break-glass pause: %v", err)
+	}
+	entries, err := audit.Read(logPath)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	if len(entries) != 1 || entries[0].BreakGlass != "checkout outage" {
+		t.Fatalf("expected break-glass reason in the audit log, got %#v", entries)
+	}
+}
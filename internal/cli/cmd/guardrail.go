@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/bilalbayram/metacli/pkg/metacli"
+)
+
+const guardrailPolicyPathEnv = metacli.GuardrailPolicyPathEnv
+
+// ConfigureSpendGuardrails installs the spend ceiling guard when a guardrail
+// policy exists. It runs at the Graph client, so no confirmation flag skips it.
+func ConfigureSpendGuardrails() error {
+	return metacli.InstallSpendGuardrails()
+}
+
+// profileLabels resolves profile labels from the config, read once on first
+// use. A missing config leaves every profile unlabeled.
+func profileLabels() func(profile string) map[string]string {
+	return metacli.ProfileLabels()
+}
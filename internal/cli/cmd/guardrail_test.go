@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestSpendGuardrailsBlockBudgetAboveCeiling(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "guardrails.yaml")
+	t.Setenv(guardrailPolicyPathEnv, policyPath)
+	t.Cleanup(func() { graph.SetMutationGuard("spend_guardrail", nil) })
+	policy := "schema_version: 1\nrules:\n  - name: acme\n    accounts: [act_111]\n    max_daily_budget: 50000\n"
+	if err := os.WriteFile(policyPath, []byte(policy), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	if err := ConfigureSpendGuardrails(); err != nil {
+		t.Fatalf("configure guardrails: %v", err)
+	}
+
+	stub := &stubHTTPClient{t: t, statusCode: http.StatusOK, response: `{"id":"1"}`}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+	create := func(budget string) error {
+		_, err := client.Do(context.Background(), graph.Request{
+			Method:      http.MethodPost,
+			Path:        "act_111/campaigns",
+			Form:        map[string]string{"name": "launch", "daily_budget": budget},
+			AccessToken: "test-token",
+		})
+		return err
+	}
+
+	err := create("90000")
+	apiErr := &graph.APIError{}
+	if !errors.As(err, &apiErr) || apiErr.Type != "spend_policy_violation" || stub.calls != 0 {
+		t.Fatalf("expected budget above ceiling to be blocked before sending, got %v (calls=%d)", err, stub.calls)
+	}
+	if err := create("40000"); err != nil || stub.calls != 1 {
+		t.Fatalf("expected budget within ceiling to be sent, got %v (calls=%d)", err, stub.calls)
+	}
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"io"
+	"time"
+
+	"github.com/bilalbayram/metacli/pkg/metacli"
+)
+
+const hooksPathEnv = metacli.HooksPathEnv
+
+var hooksNow = time.Now
+
+// ConfigureHooks installs the mutation hooks of the hooks file. Failures of
+// post_mutation and on_error hooks are reported to warnings.
+func ConfigureHooks(warnings io.Writer) error {
+	return metacli.InstallHooks(warnings, hooksNow)
+}
@@ -3,7 +3,10 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/bilalbayram/metacli/internal/config"
 	"github.com/bilalbayram/metacli/internal/graph"
@@ -56,6 +59,8 @@ func newIGPluginManifest(runtime Runtime) plugin.Manifest {
 			igCmd.AddCommand(newIGCaptionCommand(runtime, pluginRuntime))
 			igCmd.AddCommand(newIGPublishCommand(runtime, pluginRuntime))
 			igCmd.AddCommand(newIGConversationsCommand(runtime, pluginRuntime))
+			igCmd.AddCommand(newIGCommentsCommand(runtime, pluginRuntime))
+			igCmd.AddCommand(newIGHashtagCommand(runtime, pluginRuntime))
 			return igCmd, nil
 		},
 	}
@@ -119,14 +124,18 @@ func newIGPublishCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.C
 	publishCmd.AddCommand(newIGPublishFeedCommand(runtime, pluginRuntime))
 	publishCmd.AddCommand(newIGPublishReelCommand(runtime, pluginRuntime))
 	publishCmd.AddCommand(newIGPublishStoryCommand(runtime, pluginRuntime))
+	publishCmd.AddCommand(newIGPublishCarouselCommand(runtime, pluginRuntime))
 	publishCmd.AddCommand(newIGPublishScheduleCommand(runtime, pluginRuntime))
+	publishCmd.AddCommand(newIGPublishQuotaCommand(runtime, pluginRuntime))
+	publishCmd.AddCommand(newIGPublishBatchCommand(runtime, pluginRuntime))
 	return publishCmd
 }
 
 func newIGCaptionValidateCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
 	var (
-		caption string
-		strict  bool
+		caption    string
+		strict     bool
+		lintConfig string
 	)
 
 	cmd := &cobra.Command{
@@ -142,7 +151,12 @@ func newIGCaptionValidateCommand(runtime Runtime, pluginRuntime plugin.Runtime)
 				return writeCommandError(cmd, runtime, "meta ig caption validate", err)
 			}
 
-			result := ig.ValidateCaption(caption, strict)
+			lint, err := resolveIGCaptionLintConfig(lintConfig)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig caption validate", err)
+			}
+
+			result := ig.ValidateCaptionWithLint(caption, strict, lint)
 			if len(result.Errors) > 0 {
 				return writeCommandError(cmd, runtime, "meta ig caption validate", errors.New(strings.Join(result.Errors, "; ")))
 			}
@@ -153,6 +167,7 @@ func newIGCaptionValidateCommand(runtime Runtime, pluginRuntime plugin.Runtime)
 
 	cmd.Flags().StringVar(&caption, "caption", "", "Caption text to validate")
 	cmd.Flags().BoolVar(&strict, "strict", false, "Treat warnings as errors")
+	addIGCaptionLintConfigFlag(cmd, &lintConfig)
 	return cmd
 }
 
@@ -162,14 +177,18 @@ func newIGMediaUploadCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cob
 		version        string
 		igUserID       string
 		mediaURL       string
+		filePath       string
 		caption        string
 		mediaType      string
 		isCarouselItem bool
+		chunkSize      int64
+		chunkRetries   int
 	)
 
 	cmd := &cobra.Command{
 		Use:   "upload",
 		Short: "Upload an Instagram media container",
+		Long:  "Upload an Instagram media container from a public URL (--media-url) or, for VIDEO|REELS|STORIES, from a local file (--file) using the resumable upload protocol.",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			if err := pluginRuntime.Trace(plugin.TraceEvent{
@@ -186,6 +205,42 @@ func newIGMediaUploadCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cob
 			}
 			resolvedIGUserID := resolveIGUserID(igUserID, creds.Profile)
 
+			if strings.TrimSpace(filePath) != "" {
+				if strings.TrimSpace(mediaURL) != "" {
+					return writeCommandError(cmd, runtime, "meta ig media upload", errors.New("use either --media-url or --file, not both"))
+				}
+				uploadProgress := startProgress(cmd, runtime, "ig media upload", 0)
+				uploadProgress.Bar().SetUnit("bytes")
+				fileOptions := ig.ResumableUploadOptions{
+					IGUserID:        resolvedIGUserID,
+					FilePath:        filePath,
+					Caption:         caption,
+					MediaType:       mediaType,
+					IsCarouselItem:  isCarouselItem,
+					ChunkSize:       chunkSize,
+					MaxChunkRetries: chunkRetries,
+					Progress: func(progress ig.ResumableUploadProgress) {
+						if bar := uploadProgress.Bar(); bar != nil {
+							bar.Set(progress.BytesUploaded, progress.TotalBytes)
+							bar.Detail(fmt.Sprintf("chunk %d/%d", progress.Chunk, progress.Chunks))
+							return
+						}
+						uploadProgress.Line("uploaded chunk %d/%d (%d/%d bytes, %d%%)", progress.Chunk, progress.Chunks, progress.BytesUploaded, progress.TotalBytes, progress.BytesUploaded*100/progress.TotalBytes)
+					},
+				}
+				if _, _, err := ig.BuildResumableUploadRequest(resolvedVersion, creds.Token, creds.AppSecret, fileOptions); err != nil {
+					return writeCommandError(cmd, runtime, "meta ig media upload", err)
+				}
+
+				service := ig.New(igNewGraphClient())
+				result, err := service.UploadFile(uploadProgress.Context(cmd.Context()), resolvedVersion, creds.Token, creds.AppSecret, fileOptions)
+				uploadProgress.Finish()
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta ig media upload", err)
+				}
+				return writeSuccess(cmd, runtime, "meta ig media upload", result, nil, nil)
+			}
+
 			options := ig.MediaUploadOptions{
 				IGUserID:       resolvedIGUserID,
 				MediaURL:       mediaURL,
@@ -211,9 +266,12 @@ func newIGMediaUploadCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cob
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&igUserID, "ig-user-id", "", "Instagram user id (optional when profile has ig_user_id)")
 	cmd.Flags().StringVar(&mediaURL, "media-url", "", "Public media URL")
+	cmd.Flags().StringVar(&filePath, "file", "", "Local video file uploaded via resumable upload (VIDEO|REELS|STORIES)")
 	cmd.Flags().StringVar(&caption, "caption", "", "Instagram caption")
 	cmd.Flags().StringVar(&mediaType, "media-type", ig.MediaTypeImage, "Media type: IMAGE|VIDEO|REELS|STORIES")
 	cmd.Flags().BoolVar(&isCarouselItem, "is-carousel-item", false, "Mark media container as a carousel child")
+	cmd.Flags().Int64Var(&chunkSize, "chunk-size", ig.DefaultResumableChunkSize, "Chunk size in bytes for --file uploads")
+	cmd.Flags().IntVar(&chunkRetries, "chunk-retries", ig.DefaultResumableChunkRetries, "Retries per chunk on transient failures for --file uploads")
 	return cmd
 }
 
@@ -323,6 +381,10 @@ func newIGPublishImmediateCommand(runtime Runtime, pluginRuntime plugin.Runtime,
 		publishAt         string
 		scheduleStatePath string
 		strict            bool
+		quotaPolicy       string
+		quotaReserve      int
+		storyElements     ig.StoryElementOptions
+		lintConfig        string
 	)
 
 	cmd := &cobra.Command{
@@ -371,11 +433,23 @@ func newIGPublishImmediateCommand(runtime Runtime, pluginRuntime plugin.Runtime,
 			}
 			options.MediaType = normalizedMediaType
 
-			captionValidation := ig.ValidateCaption(options.Caption, options.StrictMode)
+			if err := ig.ValidateStoryElements(spec.surface, options.MediaType, storyElements); err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, spec.commandName, err)
+			}
+
+			lint, err := resolveIGCaptionLintConfig(lintConfig)
+			if err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, spec.commandName, err)
+			}
+			captionValidation := ig.ValidateCaptionWithLint(options.Caption, options.StrictMode, lint)
 			if !captionValidation.Valid {
 				return writeIGPublishScheduleCommandError(cmd, runtime, spec.commandName, errors.New(strings.Join(captionValidation.Errors, "; ")))
 			}
 
+			if _, err := ig.NormalizePublishQuotaPolicy(quotaPolicy); err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, spec.commandName, err)
+			}
+
 			if _, _, err := ig.BuildUploadRequest(resolvedVersion, creds.Token, creds.AppSecret, ig.MediaUploadOptions{
 				IGUserID:       options.IGUserID,
 				MediaURL:       options.MediaURL,
@@ -412,6 +486,16 @@ func newIGPublishImmediateCommand(runtime Runtime, pluginRuntime plugin.Runtime,
 			}
 
 			service := ig.New(igNewGraphClient())
+			quotaPreflight, err := service.PublishQuotaPreflight(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, ig.PublishQuotaPreflightOptions{
+				IGUserID: options.IGUserID,
+				Policy:   quotaPolicy,
+				Reserve:  quotaReserve,
+				Required: 1,
+			})
+			if err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, spec.commandName, err)
+			}
+
 			var result *ig.FeedPublishResult
 			switch spec.surface {
 			case ig.PublishSurfaceFeed:
@@ -426,6 +510,7 @@ func newIGPublishImmediateCommand(runtime Runtime, pluginRuntime plugin.Runtime,
 			if err != nil {
 				return writeIGPublishScheduleCommandError(cmd, runtime, spec.commandName, err)
 			}
+			result.QuotaPreflight = quotaPreflight
 
 			return writeSuccess(cmd, runtime, spec.commandName, result, nil, nil)
 		},
@@ -441,6 +526,124 @@ func newIGPublishImmediateCommand(runtime Runtime, pluginRuntime plugin.Runtime,
 	cmd.Flags().StringVar(&publishAt, "publish-at", "", "Schedule publish time (RFC3339); when set, publish is scheduled instead of immediate execution")
 	cmd.Flags().StringVar(&scheduleStatePath, "schedule-state-path", "", "Schedule state file path (defaults to ~/.meta/ig/schedules.json)")
 	cmd.Flags().BoolVar(&strict, "strict", true, "Treat caption warnings as errors")
+	cmd.Flags().StringVar(&storyElements.LinkURL, "link-sticker", "", "Story link sticker URL (validated; rejected where the publishing API does not support it)")
+	cmd.Flags().StringVar(&storyElements.Poll, "poll", "", "Story poll sticker as \"Question|Option 1|Option 2\" (validated; rejected where unsupported)")
+	cmd.Flags().StringVar(&storyElements.QuestionPrompt, "question-sticker", "", "Story question sticker prompt (validated; rejected where unsupported)")
+	addIGCaptionLintConfigFlag(cmd, &lintConfig)
+	addIGPublishQuotaFlags(cmd, &quotaPolicy, &quotaReserve)
+	return cmd
+}
+
+func newIGPublishCarouselCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile        string
+		version        string
+		igUserID       string
+		mediaURLs      []string
+		caption        string
+		idempotencyKey string
+		strict         bool
+		dryRun         bool
+		timeout        time.Duration
+		pollInterval   time.Duration
+		quotaPolicy    string
+		quotaReserve   int
+		lintConfig     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "carousel",
+		Short: "Publish an Instagram carousel of up to 10 images or videos",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  igPluginID,
+				Namespace: igNamespace,
+				Command:   "publish-carousel",
+			}); err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish carousel", err)
+			}
+
+			creds, resolvedVersion, err := resolveIGProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish carousel", ig.NormalizePublishPreflightError(err))
+			}
+
+			if err := ig.ValidatePublishCapability(creds.Name, creds.Profile); err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish carousel", err)
+			}
+
+			binding, err := ig.ResolvePublishBinding(ig.PublishBindingOptions{
+				ProfileName:       creds.Name,
+				Profile:           creds.Profile,
+				RequestedIGUserID: igUserID,
+			})
+			if err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish carousel", err)
+			}
+
+			lint, err := resolveIGCaptionLintConfig(lintConfig)
+			if err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish carousel", err)
+			}
+			if captionValidation := ig.ValidateCaptionWithLint(caption, strict, lint); !captionValidation.Valid {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish carousel", errors.New(strings.Join(captionValidation.Errors, "; ")))
+			}
+
+			service := ig.New(igNewGraphClient())
+			var quotaPreflight *ig.PublishQuotaPreflight
+			if !dryRun {
+				if _, _, _, err := ig.PlanCarousel(ig.CarouselPublishOptions{
+					IGUserID:       binding.IGUserID,
+					MediaURLs:      mediaURLs,
+					Caption:        caption,
+					StrictMode:     strict,
+					IdempotencyKey: idempotencyKey,
+				}); err != nil {
+					return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish carousel", err)
+				}
+				quotaPreflight, err = service.PublishQuotaPreflight(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, ig.PublishQuotaPreflightOptions{
+					IGUserID: binding.IGUserID,
+					Policy:   quotaPolicy,
+					Reserve:  quotaReserve,
+					Required: 1,
+				})
+				if err != nil {
+					return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish carousel", err)
+				}
+			}
+
+			result, err := service.PublishCarousel(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, ig.CarouselPublishOptions{
+				IGUserID:       binding.IGUserID,
+				MediaURLs:      mediaURLs,
+				Caption:        caption,
+				StrictMode:     strict,
+				IdempotencyKey: idempotencyKey,
+				DryRun:         dryRun,
+				PollInterval:   pollInterval,
+				Timeout:        timeout,
+			})
+			if err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish carousel", err)
+			}
+			result.QuotaPreflight = quotaPreflight
+
+			return writeSuccess(cmd, runtime, "meta ig publish carousel", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&igUserID, "ig-user-id", "", "Instagram user id (optional when profile has ig_user_id)")
+	cmd.Flags().StringArrayVar(&mediaURLs, "media-url", nil, "Public media URL for a carousel child; repeat 2-10 times in display order (.mp4/.mov/.m4v are uploaded as VIDEO)")
+	cmd.Flags().StringVar(&caption, "caption", "", "Instagram caption (required)")
+	cmd.Flags().StringVar(&idempotencyKey, "idempotency-key", "", "Idempotency key used to suppress duplicate publish requests")
+	cmd.Flags().BoolVar(&strict, "strict", true, "Treat caption warnings as errors")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the planned container graph without calling the Graph API")
+	cmd.Flags().DurationVar(&timeout, "wait-timeout", 5*time.Minute, "Maximum wait per container for status_code FINISHED")
+	cmd.Flags().DurationVar(&pollInterval, "poll-interval", 5*time.Second, "Container status polling interval")
+	addIGCaptionLintConfigFlag(cmd, &lintConfig)
+	addIGPublishQuotaFlags(cmd, &quotaPolicy, &quotaReserve)
 	return cmd
 }
 
@@ -587,6 +790,8 @@ func newIGPublishScheduleRunCommand(runtime Runtime, pluginRuntime plugin.Runtim
 		scheduleStatePath string
 		dryRun            bool
 		limit             int
+		quotaPolicy       string
+		quotaReserve      int
 	)
 
 	cmd := &cobra.Command{
@@ -607,6 +812,10 @@ func newIGPublishScheduleRunCommand(runtime Runtime, pluginRuntime plugin.Runtim
 				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish schedule run", err)
 			}
 
+			if _, err := ig.NormalizePublishQuotaPolicy(quotaPolicy); err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish schedule run", err)
+			}
+
 			scheduleService := ig.NewScheduleService(resolvedSchedulePath)
 
 			publishFn := func(ctx context.Context, record ig.ScheduledPublishRecord) (string, error) {
@@ -633,6 +842,15 @@ func newIGPublishScheduleRunCommand(runtime Runtime, pluginRuntime plugin.Runtim
 				}
 
 				service := ig.New(igNewGraphClient())
+				if _, err := service.PublishQuotaPreflight(ctx, resolvedVersion, creds.Token, creds.AppSecret, ig.PublishQuotaPreflightOptions{
+					IGUserID: record.IGUserID,
+					Policy:   quotaPolicy,
+					Reserve:  quotaReserve,
+					Required: 1,
+				}); err != nil {
+					return "", err
+				}
+
 				var result *ig.FeedPublishResult
 				switch record.Surface {
 				case ig.PublishSurfaceFeed:
@@ -664,6 +882,7 @@ func newIGPublishScheduleRunCommand(runtime Runtime, pluginRuntime plugin.Runtim
 	cmd.Flags().StringVar(&scheduleStatePath, "schedule-state-path", "", "Schedule state file path (defaults to ~/.meta/ig/schedules.json)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview due publishes without executing")
 	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of records to process (0 = unlimited)")
+	addIGPublishQuotaFlags(cmd, &quotaPolicy, &quotaReserve)
 	return cmd
 }
 
@@ -703,6 +922,92 @@ func readProfileIGUserID(profile config.Profile) string {
 	return strings.TrimSpace(profile.IGUserID)
 }
 
+func newIGPublishQuotaCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile   string
+		version   string
+		igUserIDs []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "quota",
+		Short: "Show remaining Instagram content publishing quota per IG user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  igPluginID,
+				Namespace: igNamespace,
+				Command:   "publish-quota",
+			}); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig publish quota", err)
+			}
+
+			creds, resolvedVersion, err := resolveIGProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig publish quota", err)
+			}
+
+			resolvedIGUserIDs := igUserIDs
+			if len(resolvedIGUserIDs) == 0 {
+				resolvedIGUserID, err := requireResolvedIGUserID("", creds.Profile)
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta ig publish quota", err)
+				}
+				resolvedIGUserIDs = []string{resolvedIGUserID}
+			}
+			for _, resolvedIGUserID := range resolvedIGUserIDs {
+				if _, _, err := ig.BuildPublishQuotaRequest(resolvedVersion, creds.Token, creds.AppSecret, ig.PublishQuotaOptions{IGUserID: resolvedIGUserID}); err != nil {
+					return writeCommandError(cmd, runtime, "meta ig publish quota", err)
+				}
+			}
+
+			service := ig.New(igNewGraphClient())
+			results := make([]*ig.PublishQuotaResult, 0, len(resolvedIGUserIDs))
+			for _, resolvedIGUserID := range resolvedIGUserIDs {
+				result, err := service.PublishQuota(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, ig.PublishQuotaOptions{IGUserID: resolvedIGUserID})
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta ig publish quota", err)
+				}
+				results = append(results, result)
+			}
+
+			return writeSuccess(cmd, runtime, "meta ig publish quota", results, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringSliceVar(&igUserIDs, "ig-user-id", nil, "Instagram user id(s); repeat or comma-separate (defaults to profile ig_user_id)")
+	return cmd
+}
+
+func addIGPublishQuotaFlags(cmd *cobra.Command, policy *string, reserve *int) {
+	cmd.Flags().StringVar(policy, "quota-policy", ig.PublishQuotaPolicyFail, "Content publishing limit preflight: fail|warn|skip")
+	cmd.Flags().IntVar(reserve, "quota-reserve", 0, "Posts to keep unused in the rolling 24h publishing quota")
+}
+
+func addIGCaptionLintConfigFlag(cmd *cobra.Command, path *string) {
+	cmd.Flags().StringVar(path, "caption-lint-config", "", "Caption lint config JSON with banned words/phrases, allowed mentions, and max hashtags (defaults to ~/.meta/ig/caption_lint.json when present)")
+}
+
+func resolveIGCaptionLintConfig(path string) (*ig.CaptionLintConfig, error) {
+	resolvedPath := strings.TrimSpace(path)
+	if resolvedPath != "" {
+		return ig.LoadCaptionLintConfig(resolvedPath)
+	}
+	defaultPath, err := ig.DefaultCaptionLintConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(defaultPath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("stat caption lint config %s: %w", defaultPath, err)
+	}
+	return ig.LoadCaptionLintConfig(defaultPath)
+}
+
 func resolveIGScheduleStatePath(path string) (string, error) {
 	resolvedPath := strings.TrimSpace(path)
 	if resolvedPath != "" {
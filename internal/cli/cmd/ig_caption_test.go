@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -129,3 +131,51 @@ func TestIGCaptionCommandFailsWithoutSubcommand(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestIGCaptionValidateAppliesLintConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	lintPath := filepath.Join(t.TempDir(), "caption_lint.json")
+	if err := os.WriteFile(lintPath, []byte(`{"schema_version":1,"banned_words":["cheap"]}`), 0o600); err != nil {
+		t.Fatalf("write lint config: %v", err)
+	}
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewIGCommand(Runtime{})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{
+		"caption", "validate",
+		"--caption", "Cheap deals today #meta",
+		"--caption-lint-config", lintPath,
+		"--strict",
+	})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected command error")
+	}
+	if !strings.Contains(err.Error(), `strict mode: caption contains banned word "cheap"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := &bytes.Buffer{}
+	cmd = NewIGCommand(Runtime{})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"caption", "validate", "--caption", "Cheap deals today #meta", "--caption-lint-config", lintPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute non-strict caption validate: %v", err)
+	}
+	data, _ := decodeEnvelope(t, output.Bytes())["data"].(map[string]any)
+	if data["lint_config"] != lintPath {
+		t.Fatalf("expected lint_config %q, got %v", lintPath, data["lint_config"])
+	}
+	warnings, _ := data["warnings"].([]any)
+	if len(warnings) != 1 {
+		t.Fatalf("expected banned word warning, got %#v", data["warnings"])
+	}
+}
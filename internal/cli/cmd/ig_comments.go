@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"github.com/bilalbayram/metacli/internal/ig"
+	"github.com/bilalbayram/metacli/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+func newIGCommentsCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	commentsCmd := &cobra.Command{
+		Use:   "comments",
+		Short: "Instagram comment moderation commands",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "ig comments")
+		},
+	}
+	commentsCmd.AddCommand(newIGCommentsListCommand(runtime, pluginRuntime))
+	commentsCmd.AddCommand(newIGCommentsReplyCommand(runtime, pluginRuntime))
+	commentsCmd.AddCommand(newIGCommentsHideCommand(runtime, pluginRuntime))
+	commentsCmd.AddCommand(newIGCommentsDeleteCommand(runtime, pluginRuntime))
+	return commentsCmd
+}
+
+func newIGCommentsListCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile    string
+		version    string
+		mediaID    string
+		fieldsRaw  string
+		limit      int
+		pageSize   int
+		followNext bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List comments on Instagram media",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  igPluginID,
+				Namespace: igNamespace,
+				Command:   "comments-list",
+			}); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments list", err)
+			}
+
+			creds, resolvedVersion, err := resolveIGProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments list", err)
+			}
+
+			options := ig.CommentListOptions{
+				MediaID:    mediaID,
+				Fields:     csvToSlice(fieldsRaw),
+				Limit:      limit,
+				PageSize:   pageSize,
+				FollowNext: followNext,
+			}
+			if _, _, err := ig.BuildCommentListRequest(resolvedVersion, creds.Token, creds.AppSecret, options); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments list", err)
+			}
+
+			service := ig.New(igNewGraphClient())
+			result, err := service.ListComments(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments list", err)
+			}
+
+			return writeSuccess(cmd, runtime, "meta ig comments list", result.Comments, result.Paging, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&mediaID, "media-id", "", "Instagram media id")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated comment fields (defaults to id,text,username,timestamp,like_count,hidden,parent_id)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of comments to return (0 = unlimited)")
+	cmd.Flags().IntVar(&pageSize, "page-size", 0, "Graph page size for comment reads")
+	cmd.Flags().BoolVar(&followNext, "follow-next", false, "Follow paging.next links")
+	return cmd
+}
+
+func newIGCommentsReplyCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile   string
+		version   string
+		commentID string
+		message   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "reply",
+		Short: "Reply to an Instagram comment",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  igPluginID,
+				Namespace: igNamespace,
+				Command:   "comments-reply",
+			}); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments reply", err)
+			}
+
+			creds, resolvedVersion, err := resolveIGProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments reply", err)
+			}
+
+			options := ig.CommentReplyOptions{
+				CommentID: commentID,
+				Message:   message,
+			}
+			if _, _, err := ig.BuildCommentReplyRequest(resolvedVersion, creds.Token, creds.AppSecret, options); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments reply", err)
+			}
+
+			service := ig.New(igNewGraphClient())
+			result, err := service.ReplyToComment(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments reply", err)
+			}
+
+			return writeSuccess(cmd, runtime, "meta ig comments reply", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&commentID, "comment-id", "", "Instagram comment id")
+	cmd.Flags().StringVar(&message, "message", "", "Reply text")
+	return cmd
+}
+
+func newIGCommentsHideCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile   string
+		version   string
+		commentID string
+		unhide    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "hide",
+		Short: "Hide or unhide an Instagram comment",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  igPluginID,
+				Namespace: igNamespace,
+				Command:   "comments-hide",
+			}); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments hide", err)
+			}
+
+			creds, resolvedVersion, err := resolveIGProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments hide", err)
+			}
+
+			options := ig.CommentHideOptions{
+				CommentID: commentID,
+				Hide:      !unhide,
+			}
+			if _, _, err := ig.BuildCommentHideRequest(resolvedVersion, creds.Token, creds.AppSecret, options); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments hide", err)
+			}
+
+			service := ig.New(igNewGraphClient())
+			result, err := service.HideComment(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments hide", err)
+			}
+
+			return writeSuccess(cmd, runtime, "meta ig comments hide", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&commentID, "comment-id", "", "Instagram comment id")
+	cmd.Flags().BoolVar(&unhide, "unhide", false, "Make a hidden comment visible again")
+	return cmd
+}
+
+func newIGCommentsDeleteCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile   string
+		version   string
+		commentID string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete an Instagram comment",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  igPluginID,
+				Namespace: igNamespace,
+				Command:   "comments-delete",
+			}); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments delete", err)
+			}
+
+			creds, resolvedVersion, err := resolveIGProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments delete", err)
+			}
+
+			options := ig.CommentDeleteOptions{
+				CommentID: commentID,
+			}
+			if _, _, err := ig.BuildCommentDeleteRequest(resolvedVersion, creds.Token, creds.AppSecret, options); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments delete", err)
+			}
+
+			service := ig.New(igNewGraphClient())
+			result, err := service.DeleteComment(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig comments delete", err)
+			}
+
+			return writeSuccess(cmd, runtime, "meta ig comments delete", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&commentID, "comment-id", "", "Instagram comment id")
+	return cmd
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestIGCommentsListWritesOneJSONLLinePerComment(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"id":"c_1","text":"love it"},{"id":"c_2","text":"spam"}]}`,
+	}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:      "prod",
+				Profile:   config.Profile{GraphVersion: "v25.0"},
+				Token:     "test-token",
+				AppSecret: "test-secret",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	output := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntimeWithOutputFormat("prod", "jsonl"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"comments", "list", "--media-id", "media_1", "--page-size", "50"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute ig comments list: %v", err)
+	}
+
+	parsedURL, err := url.Parse(stub.lastURL)
+	if err != nil {
+		t.Fatalf("parse request url: %v", err)
+	}
+	if parsedURL.Path != "/v25.0/media_1/comments" || parsedURL.Query().Get("limit") != "50" {
+		t.Fatalf("unexpected request url %q", stub.lastURL)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two jsonl lines, got %d: %q", len(lines), output.String())
+	}
+	var line map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &line); err != nil {
+		t.Fatalf("decode jsonl line: %v", err)
+	}
+	data, _ := line["data"].(map[string]any)
+	if line["command"] != "meta ig comments list" || data["id"] != "c_2" {
+		t.Fatalf("unexpected jsonl line: %v", line)
+	}
+}
+
+func TestIGCommentsHideSendsHideFlag(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"success":true}`,
+	}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:      "prod",
+				Profile:   config.Profile{GraphVersion: "v25.0"},
+				Token:     "test-token",
+				AppSecret: "test-secret",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	output := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"comments", "hide", "--comment-id", "c_2"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute ig comments hide: %v", err)
+	}
+	if stub.lastMethod != http.MethodPost {
+		t.Fatalf("unexpected method %q", stub.lastMethod)
+	}
+	form, err := url.ParseQuery(stub.lastBody)
+	if err != nil {
+		t.Fatalf("parse form: %v", err)
+	}
+	if got := form.Get("hide"); got != "true" {
+		t.Fatalf("unexpected hide value %q", got)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta ig comments hide")
+	data, _ := envelope["data"].(map[string]any)
+	if data["operation"] != "hide" || data["hidden"] != true {
+		t.Fatalf("unexpected hide payload: %v", data)
+	}
+}
+
+func TestIGCommentsDeleteRequiresCommentID(t *testing.T) {
+	stub := &stubHTTPClient{t: t}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{Name: "prod", Profile: config.Profile{GraphVersion: "v25.0"}, Token: "test-token"}, nil
+		},
+		func() *graph.Client {
+			return graph.NewClient(stub, "https://graph.example.com")
+		},
+	)
+
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"comments", "delete"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "comment id is required") {
+		t.Fatalf("expected missing comment id error, got %v", err)
+	}
+	if stub.calls != 0 {
+		t.Fatalf("expected no graph calls, got %d", stub.calls)
+	}
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/ig"
+	"github.com/bilalbayram/metacli/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+func newIGHashtagCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	hashtagCmd := &cobra.Command{
+		Use:   "hashtag",
+		Short: "Instagram hashtag research commands",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "ig hashtag")
+		},
+	}
+	hashtagCmd.AddCommand(newIGHashtagSearchCommand(runtime, pluginRuntime))
+	hashtagCmd.AddCommand(newIGHashtagMediaCommand(runtime, pluginRuntime))
+	return hashtagCmd
+}
+
+func newIGHashtagSearchCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile        string
+		version        string
+		igUserID       string
+		query          string
+		quotaStatePath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search",
+		Short: "Resolve a hashtag name to its Instagram hashtag id",
+		Long:  "Resolve a hashtag name to its Instagram hashtag id. Each IG user may search 30 unique hashtags per rolling 7 days; usage is tracked locally and new searches fail closed once the quota is exhausted.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  igPluginID,
+				Namespace: igNamespace,
+				Command:   "hashtag-search",
+			}); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig hashtag search", err)
+			}
+
+			creds, resolvedVersion, err := resolveIGProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig hashtag search", err)
+			}
+			resolvedIGUserID, err := requireResolvedIGUserID(igUserID, creds.Profile)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig hashtag search", err)
+			}
+
+			options := ig.HashtagSearchOptions{
+				IGUserID: resolvedIGUserID,
+				Query:    query,
+			}
+			if _, _, _, err := ig.BuildHashtagSearchRequest(resolvedVersion, creds.Token, creds.AppSecret, options); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig hashtag search", err)
+			}
+
+			resolvedQuotaPath, err := resolveIGHashtagQuotaStatePath(quotaStatePath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig hashtag search", err)
+			}
+			tracker := ig.NewHashtagQuotaTracker(resolvedQuotaPath)
+			if _, err := tracker.Check(resolvedIGUserID, query); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig hashtag search", err)
+			}
+
+			service := ig.New(igNewGraphClient())
+			result, err := service.SearchHashtag(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig hashtag search", err)
+			}
+
+			quota, err := tracker.Record(result.IGUserID, result.Query, result.HashtagID)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig hashtag search", err)
+			}
+			result.Quota = &quota
+
+			return writeSuccess(cmd, runtime, "meta ig hashtag search", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&igUserID, "ig-user-id", "", "Instagram user id (optional when profile has ig_user_id)")
+	cmd.Flags().StringVar(&query, "q", "", "Hashtag name to search (leading # is optional)")
+	cmd.Flags().StringVar(&quotaStatePath, "quota-state-path", "", "Hashtag quota state file path (defaults to ~/.meta/ig/hashtag_quota.json)")
+	return cmd
+}
+
+func newIGHashtagMediaCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile    string
+		version    string
+		igUserID   string
+		hashtagID  string
+		mediaType  string
+		fieldsRaw  string
+		limit      int
+		pageSize   int
+		followNext bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "media",
+		Short: "List top or recent media for an Instagram hashtag id",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  igPluginID,
+				Namespace: igNamespace,
+				Command:   "hashtag-media",
+			}); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig hashtag media", err)
+			}
+
+			creds, resolvedVersion, err := resolveIGProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig hashtag media", err)
+			}
+			resolvedIGUserID, err := requireResolvedIGUserID(igUserID, creds.Profile)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig hashtag media", err)
+			}
+
+			options := ig.HashtagMediaOptions{
+				IGUserID:   resolvedIGUserID,
+				HashtagID:  hashtagID,
+				Type:       mediaType,
+				Fields:     csvToSlice(fieldsRaw),
+				Limit:      limit,
+				PageSize:   pageSize,
+				FollowNext: followNext,
+			}
+			if _, _, err := ig.BuildHashtagMediaRequest(resolvedVersion, creds.Token, creds.AppSecret, options); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig hashtag media", err)
+			}
+
+			service := ig.New(igNewGraphClient())
+			result, err := service.HashtagMedia(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig hashtag media", err)
+			}
+
+			return writeSuccess(cmd, runtime, "meta ig hashtag media", result.Media, result.Paging, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&igUserID, "ig-user-id", "", "Instagram user id (optional when profile has ig_user_id)")
+	cmd.Flags().StringVar(&hashtagID, "hashtag-id", "", "Instagram hashtag id returned by ig hashtag search")
+	cmd.Flags().StringVar(&mediaType, "type", ig.HashtagMediaTypeTop, "Hashtag media edge: top|recent")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated media fields (defaults to "+strings.Join(ig.DefaultHashtagMediaFields, ",")+")")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of media objects to return (0 = unlimited)")
+	cmd.Flags().IntVar(&pageSize, "page-size", 0, "Graph page size for hashtag media reads")
+	cmd.Flags().BoolVar(&followNext, "follow-next", false, "Follow paging.next links")
+	return cmd
+}
+
+func resolveIGHashtagQuotaStatePath(path string) (string, error) {
+	resolvedPath := strings.TrimSpace(path)
+	if resolvedPath != "" {
+		return resolvedPath, nil
+	}
+	return ig.DefaultHashtagQuotaStatePath()
+}
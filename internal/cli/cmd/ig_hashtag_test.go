@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestIGHashtagSearchRecordsQuotaUsage(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"id":"17843857450040591"}]}`,
+	}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:      "prod",
+				Profile:   config.Profile{GraphVersion: "v25.0", IGUserID: "178"},
+				Token:     "test-token",
+				AppSecret: "test-secret",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	quotaPath := filepath.Join(t.TempDir(), "hashtag_quota.json")
+	output := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SetOut(output)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"hashtag", "search", "--q", "fitness", "--quota-state-path", quotaPath})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute ig hashtag search: %v", err)
+	}
+
+	parsedURL, err := url.Parse(stub.lastURL)
+	if err != nil {
+		t.Fatalf("parse request url: %v", err)
+	}
+	if parsedURL.Path != "/v25.0/ig_hashtag_search" || parsedURL.Query().Get("user_id") != "178" {
+		t.Fatalf("unexpected request url %q", stub.lastURL)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta ig hashtag search")
+	data, _ := envelope["data"].(map[string]any)
+	if data["hashtag_id"] != "17843857450040591" {
+		t.Fatalf("unexpected data %#v", data)
+	}
+	quota, _ := data["quota"].(map[string]any)
+	if quota["used"] != float64(1) || quota["remaining"] != float64(29) {
+		t.Fatalf("unexpected quota %#v", quota)
+	}
+}
+
+func TestIGHashtagSearchFailsClosedWhenQuotaExhausted(t *testing.T) {
+	stub := &stubHTTPClient{t: t, statusCode: http.StatusOK, response: `{"data":[{"id":"1"}]}`}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{GraphVersion: "v25.0", IGUserID: "178"},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	quotaPath := filepath.Join(t.TempDir(), "hashtag_quota.json")
+	for index := 0; index < 30; index++ {
+		cmd := NewIGCommand(testRuntime("prod"))
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs([]string{"hashtag", "search", "--q", fmt.Sprintf("tag%02d", index), "--quota-state-path", quotaPath})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("execute search %d: %v", index, err)
+		}
+	}
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"hashtag", "search", "--q", "overflow", "--quota-state-path", quotaPath})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected quota exhausted error")
+	}
+	if stub.calls != 30 {
+		t.Fatalf("expected no graph call once quota is exhausted, got %d calls", stub.calls)
+	}
+	if !strings.Contains(errOutput.String(), "ig_hashtag_quota_exhausted") {
+		t.Fatalf("unexpected error output %q", errOutput.String())
+	}
+}
+
+func TestIGHashtagMediaListsRecentMedia(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"id":"m_1"},{"id":"m_2"}]}`,
+	}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{GraphVersion: "v25.0"},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	output := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SetOut(output)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"hashtag", "media", "--ig-user-id", "178", "--hashtag-id", "1784", "--type", "recent"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute ig hashtag media: %v", err)
+	}
+	parsedURL, err := url.Parse(stub.lastURL)
+	if err != nil {
+		t.Fatalf("parse request url: %v", err)
+	}
+	if parsedURL.Path != "/v25.0/1784/recent_media" || parsedURL.Query().Get("user_id") != "178" {
+		t.Fatalf("unexpected request url %q", stub.lastURL)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta ig hashtag media")
+	data, ok := envelope["data"].([]any)
+	if !ok || len(data) != 2 {
+		t.Fatalf("unexpected data %#v", envelope["data"])
+	}
+}
@@ -19,13 +19,110 @@ import (
 var igInsightsNow = time.Now
 
 func newIGInsightsCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile    string
+		version    string
+		igUserID   string
+		mediaIDs   []string
+		metrics    []string
+		period     string
+		metricType string
+		breakdown  string
+		datePreset string
+		since      string
+		until      string
+		timeframe  string
+		format     string
+	)
+
 	insightsCmd := &cobra.Command{
 		Use:   "insights",
 		Short: "Instagram account and media insights commands",
+		Long:  "Fetch Instagram account metrics (--ig-user-id) or media metrics (--media-id) as flat metric/value rows, or use a subcommand for raw metric objects.",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return requireSubcommand(cmd, "ig insights")
+			if len(metrics) == 0 && len(mediaIDs) == 0 && strings.TrimSpace(igUserID) == "" {
+				return requireSubcommand(cmd, "ig insights")
+			}
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  igPluginID,
+				Namespace: igNamespace,
+				Command:   "insights",
+			}); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig insights", err)
+			}
+			if len(mediaIDs) > 0 && strings.TrimSpace(igUserID) != "" {
+				return writeCommandError(cmd, runtime, "meta ig insights", errors.New("use either --ig-user-id or --media-id, not both"))
+			}
+
+			creds, resolvedVersion, err := resolveIGProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig insights", err)
+			}
+			format, err = normalizeInsightsFormat(format)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig insights", err)
+			}
+			resolvedSince, resolvedUntil, err := resolveIGInsightsRange(since, until, datePreset, "")
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig insights", err)
+			}
+
+			service := ig.New(igNewGraphClient())
+			if len(mediaIDs) > 0 {
+				rows, err := service.MediaInsights(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, ig.MediaInsightsOptions{
+					MediaIDs:   mediaIDs,
+					Metrics:    metrics,
+					Period:     period,
+					MetricType: metricType,
+					Breakdown:  breakdown,
+					Since:      resolvedSince,
+					Until:      resolvedUntil,
+					Timeframe:  timeframe,
+				})
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta ig insights", err)
+				}
+				flattened := make([]map[string]any, 0)
+				for _, row := range rows {
+					flattened = append(flattened, ig.FlattenInsightsMetrics("media_id", row.MediaID, row.RawMetrics)...)
+				}
+				return writeInsightsOutput(cmd, runtime, "meta ig insights", format, flattened, nil)
+			}
+
+			resolvedIGUserID, err := requireResolvedIGUserID(igUserID, creds.Profile)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig insights", err)
+			}
+			result, err := service.AccountInsights(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, ig.AccountInsightsOptions{
+				IGUserID:   resolvedIGUserID,
+				Metrics:    metrics,
+				Period:     period,
+				MetricType: metricType,
+				Breakdown:  breakdown,
+				Since:      resolvedSince,
+				Until:      resolvedUntil,
+				Timeframe:  timeframe,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta ig insights", err)
+			}
+			return writeInsightsOutput(cmd, runtime, "meta ig insights", format, ig.FlattenInsightsMetrics("ig_user_id", result.IGUserID, result.RawMetrics), result.Pagination)
 		},
 	}
+
+	insightsCmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	insightsCmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	insightsCmd.Flags().StringVar(&igUserID, "ig-user-id", "", "Instagram user id for account metrics (optional when profile has ig_user_id)")
+	insightsCmd.Flags().StringSliceVar(&mediaIDs, "media-id", nil, "Instagram media id(s) for media metrics; repeat the flag or pass a comma-separated list")
+	insightsCmd.Flags().StringSliceVar(&metrics, "metrics", nil, "Comma-separated metric names, e.g. reach,impressions,profile_views")
+	insightsCmd.Flags().StringVar(&period, "period", "", "Metric period, e.g. day|week|days_28|lifetime")
+	insightsCmd.Flags().StringVar(&metricType, "metric-type", "", "Metric type")
+	insightsCmd.Flags().StringVar(&breakdown, "breakdown", "", "Metric breakdown")
+	insightsCmd.Flags().StringVar(&datePreset, "date-preset", "", "Resolve a common date preset into --since/--until")
+	insightsCmd.Flags().StringVar(&since, "since", "", "Start date (YYYY-MM-DD)")
+	insightsCmd.Flags().StringVar(&until, "until", "", "End date (YYYY-MM-DD)")
+	insightsCmd.Flags().StringVar(&timeframe, "timeframe", "", "Timeframe value")
+	insightsCmd.Flags().StringVar(&format, "format", "json", "Export format: json|jsonl|csv")
 	insightsCmd.AddCommand(newIGInsightsAccountCommand(runtime, pluginRuntime))
 	insightsCmd.AddCommand(newIGInsightsMediaCommand(runtime, pluginRuntime))
 	insightsCmd.AddCommand(newIGInsightsCombinedLocalIntentCommand(runtime, pluginRuntime))
@@ -113,7 +210,7 @@ func newIGInsightsAccountRunCommand(runtime Runtime, pluginRuntime plugin.Runtim
 				return writeCommandError(cmd, runtime, "meta ig insights account run", err)
 			}
 
-			return writeInsightsOutput(cmd, "meta ig insights account run", format, result.RawMetrics, result.Pagination)
+			return writeInsightsOutput(cmd, runtime, "meta ig insights account run", format, result.RawMetrics, result.Pagination)
 		},
 	}
 
@@ -184,7 +281,7 @@ func newIGInsightsAccountLocalIntentCommand(runtime Runtime, pluginRuntime plugi
 				"summary":     report.Summary,
 				"raw_metrics": report.RawMetrics,
 			}
-			return writeInsightsOutput(cmd, "meta ig insights account local-intent", format, data, nil)
+			return writeInsightsOutput(cmd, runtime, "meta ig insights account local-intent", format, data, nil)
 		},
 	}
 
@@ -242,7 +339,7 @@ func newIGInsightsMediaListCommand(runtime Runtime, pluginRuntime plugin.Runtime
 				return writeCommandError(cmd, runtime, "meta ig insights media list", err)
 			}
 
-			return writeInsightsOutput(cmd, "meta ig insights media list", format, result.Media, result.Pagination)
+			return writeInsightsOutput(cmd, runtime, "meta ig insights media list", format, result.Media, result.Pagination)
 		},
 	}
 
@@ -305,7 +402,7 @@ func newIGInsightsMediaRunCommand(runtime Runtime, pluginRuntime plugin.Runtime)
 				return writeCommandError(cmd, runtime, "meta ig insights media run", err)
 			}
 
-			return writeInsightsOutput(cmd, "meta ig insights media run", format, mediaInsightsRowsToMaps(rows), nil)
+			return writeInsightsOutput(cmd, runtime, "meta ig insights media run", format, mediaInsightsRowsToMaps(rows), nil)
 		},
 	}
 
@@ -325,14 +422,15 @@ func newIGInsightsMediaRunCommand(runtime Runtime, pluginRuntime plugin.Runtime)
 
 func newIGInsightsCombinedLocalIntentCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
 	var (
-		profile    string
-		version    string
-		accountID  string
-		igUserID   string
-		datePreset string
-		since      string
-		until      string
-		format     string
+		profile     string
+		version     string
+		accountID   string
+		accountName string
+		igUserID    string
+		datePreset  string
+		since       string
+		until       string
+		format      string
 	)
 
 	cmd := &cobra.Command{
@@ -346,14 +444,17 @@ func newIGInsightsCombinedLocalIntentCommand(runtime Runtime, pluginRuntime plug
 			}); err != nil {
 				return writeCommandError(cmd, runtime, "meta ig insights local-intent", err)
 			}
-			if strings.TrimSpace(accountID) == "" {
-				return writeCommandError(cmd, runtime, "meta ig insights local-intent", errors.New("account id is required (--account-id)"))
+			if strings.TrimSpace(accountID) == "" && strings.TrimSpace(accountName) == "" {
+				return writeCommandError(cmd, runtime, "meta ig insights local-intent", errors.New("account id is required (--account-id or --account)"))
 			}
 
 			creds, resolvedVersion, err := resolveIGProfileAndVersion(runtime, profile, version)
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta ig insights local-intent", err)
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta ig insights local-intent", err)
+			}
 			resolvedIGUserID, err := requireResolvedIGUserID(igUserID, creds.Profile)
 			if err != nil {
 				return writeCommandError(cmd, runtime, "meta ig insights local-intent", err)
@@ -405,13 +506,14 @@ func newIGInsightsCombinedLocalIntentCommand(runtime Runtime, pluginRuntime plug
 					"raw_metrics": accountReport.RawMetrics,
 				},
 			}
-			return writeInsightsOutput(cmd, "meta ig insights local-intent", format, data, nil)
+			return writeInsightsOutput(cmd, runtime, "meta ig insights local-intent", format, data, nil)
 		},
 	}
 
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id without act_ prefix")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&igUserID, "ig-user-id", "", "Instagram user id (optional when profile has ig_user_id)")
 	cmd.Flags().StringVar(&datePreset, "date-preset", "last_7d", "Resolve a common date preset into --since/--until")
 	cmd.Flags().StringVar(&since, "since", "", "Start date (YYYY-MM-DD)")
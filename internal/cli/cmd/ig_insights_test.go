@@ -363,3 +363,141 @@ func TestIGInsightsCombinedLocalIntentReturnsLayeredReport(t *testing.T) {
 		t.Fatalf("unexpected paid row publisher_platform %#v", got)
 	}
 }
+
+func TestIGInsightsShortcutExportsAccountMetricsAsCSV(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"name":"reach","period":"day","values":[{"value":10,"end_time":"2026-03-14T07:00:00+0000"},{"value":12,"end_time":"2026-03-15T07:00:00+0000"}]}]}`,
+	}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:      "prod",
+				Profile:   config.Profile{GraphVersion: "v25.0"},
+				Token:     "test-token",
+				AppSecret: "test-secret",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	output := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SetOut(output)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{
+		"insights",
+		"--ig-user-id", "17841401876639191",
+		"--metrics", "reach,impressions",
+		"--period", "day",
+		"--since", "2026-03-14",
+		"--until", "2026-03-15",
+		"--format", "csv",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute ig insights: %v", err)
+	}
+
+	parsedURL, err := url.Parse(stub.lastURL)
+	if err != nil {
+		t.Fatalf("parse request url: %v", err)
+	}
+	if parsedURL.Path != "/v25.0/17841401876639191/insights" {
+		t.Fatalf("unexpected request path %q", parsedURL.Path)
+	}
+	if got := parsedURL.Query().Get("metric"); got != "reach,impressions" {
+		t.Fatalf("unexpected metric query %q", got)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header plus two csv rows, got %q", output.String())
+	}
+	for _, column := range []string{"end_time", "ig_user_id", "metric", "period", "value"} {
+		if !strings.Contains(lines[0], column) {
+			t.Fatalf("expected csv header to contain %q, got %q", column, lines[0])
+		}
+	}
+	if !strings.Contains(lines[2], "2026-03-15T07:00:00+0000") || !strings.Contains(lines[2], "12") {
+		t.Fatalf("unexpected second csv row %q", lines[2])
+	}
+}
+
+func TestIGInsightsShortcutFetchesMediaMetrics(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"name":"reach","period":"lifetime","values":[{"value":42}]}]}`,
+	}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:      "prod",
+				Profile:   config.Profile{GraphVersion: "v25.0"},
+				Token:     "test-token",
+				AppSecret: "test-secret",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	output := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SetOut(output)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"insights", "--media-id", "1789", "--metrics", "reach", "--period", "lifetime"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute ig insights: %v", err)
+	}
+	if !strings.Contains(stub.lastURL, "/v25.0/1789/insights") {
+		t.Fatalf("unexpected request url %q", stub.lastURL)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta ig insights")
+	data, ok := envelope["data"].([]any)
+	if !ok || len(data) != 1 {
+		t.Fatalf("expected one flattened row, got %#v", envelope["data"])
+	}
+	row, _ := data[0].(map[string]any)
+	if row["media_id"] != "1789" || row["metric"] != "reach" || row["value"] != float64(42) {
+		t.Fatalf("unexpected flattened row %#v", row)
+	}
+}
+
+func TestIGInsightsShortcutRejectsAccountAndMediaTogether(t *testing.T) {
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			t.Fatal("profile should not be loaded")
+			return nil, nil
+		},
+		func() *graph.Client {
+			t.Fatal("graph client should not be created")
+			return nil
+		},
+	)
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"insights", "--ig-user-id", "178", "--media-id", "1789", "--metrics", "reach"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(errOutput.String(), "use either --ig-user-id or --media-id") {
+		t.Fatalf("unexpected error output %q", errOutput.String())
+	}
+}
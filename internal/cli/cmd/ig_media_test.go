@@ -5,6 +5,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -186,6 +188,91 @@ func TestIGMediaUploadCommandUsesProfileIGUserIDWhenFlagOmitted(t *testing.T) {
 	}
 }
 
+func TestIGMediaUploadFileUsesResumableUploadWithProgress(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"id":"container_1","uri":"https://rupload.example.com/ig-api-upload/v25.0/container_1","success":true}`,
+	}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{GraphVersion: "v25.0"},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	filePath := filepath.Join(t.TempDir(), "reel.mp4")
+	if err := os.WriteFile(filePath, []byte("0123456789"), 0o600); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	output := &bytes.Buffer{}
+	progress := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SetOut(output)
+	cmd.SetErr(progress)
+	cmd.SetArgs([]string{
+		"media", "upload",
+		"--ig-user-id", "178",
+		"--file", filePath,
+		"--media-type", "REELS",
+		"--chunk-size", "4",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute ig media upload --file: %v", err)
+	}
+	if stub.calls != 4 {
+		t.Fatalf("expected container create plus three chunks, got %d calls", stub.calls)
+	}
+	if !strings.HasPrefix(stub.lastURL, "https://rupload.example.com/ig-api-upload/v25.0/container_1") || stub.lastBody != "89" {
+		t.Fatalf("unexpected last chunk url=%q body=%q", stub.lastURL, stub.lastBody)
+	}
+	if !strings.Contains(progress.String(), "uploaded chunk 3/3 (10/10 bytes, 100%)") {
+		t.Fatalf("unexpected progress output %q", progress.String())
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta ig media upload")
+	data, _ := envelope["data"].(map[string]any)
+	if data["creation_id"] != "container_1" || data["chunks"] != float64(3) {
+		t.Fatalf("unexpected data %#v", data)
+	}
+}
+
+func TestIGMediaUploadRejectsFileAndMediaURLTogether(t *testing.T) {
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{Name: "prod", Profile: config.Profile{GraphVersion: "v25.0"}, Token: "test-token"}, nil
+		},
+		func() *graph.Client {
+			t.Fatal("graph client should not be created")
+			return nil
+		},
+	)
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"media", "upload", "--ig-user-id", "178", "--file", "clip.mp4", "--media-url", "https://cdn.example.com/clip.mp4", "--media-type", "REELS"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(errOutput.String(), "use either --media-url or --file") {
+		t.Fatalf("unexpected error output %q", errOutput.String())
+	}
+}
+
 func TestIGMediaStatusCommandExecutesShapedRequest(t *testing.T) {
 
 	stub := &stubHTTPClient{
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/ig"
+	"github.com/bilalbayram/metacli/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+func newIGPublishBatchCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile           string
+		version           string
+		igUserID          string
+		filePath          string
+		scheduleStatePath string
+		strict            bool
+		dryRun            bool
+		quotaPolicy       string
+		quotaReserve      int
+		lintConfig        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "batch",
+		Short: "Publish or schedule Instagram posts from a CSV/JSON content calendar",
+		Long:  "Publish or schedule Instagram posts from a CSV/JSON content calendar. Columns: id, surface, ig_user_id, media_url, caption, media_type, publish_at. Every row is validated before anything is published; rows with publish_at are scheduled, others are published immediately. Idempotency keys are derived from row ids as batch:<id>.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  igPluginID,
+				Namespace: igNamespace,
+				Command:   "publish-batch",
+			}); err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish batch", err)
+			}
+
+			rows, err := ig.LoadPublishBatchFile(filePath)
+			if err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish batch", err)
+			}
+
+			creds, resolvedVersion, err := resolveIGProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish batch", ig.NormalizePublishPreflightError(err))
+			}
+			if err := ig.ValidatePublishCapability(creds.Name, creds.Profile); err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish batch", err)
+			}
+			if _, err := ig.NormalizePublishQuotaPolicy(quotaPolicy); err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish batch", err)
+			}
+
+			lint, err := resolveIGCaptionLintConfig(lintConfig)
+			if err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish batch", err)
+			}
+
+			report := ig.PlanPublishBatch(rows, ig.PublishBatchPlanOptions{
+				File:        filePath,
+				StrictMode:  strict,
+				CaptionLint: lint,
+				Now:         time.Now().UTC(),
+				ResolveIGUserID: func(requested string) (string, error) {
+					if requested == "" {
+						requested = igUserID
+					}
+					binding, err := ig.ResolvePublishBinding(ig.PublishBindingOptions{
+						ProfileName:       creds.Name,
+						Profile:           creds.Profile,
+						RequestedIGUserID: requested,
+					})
+					if err != nil {
+						return "", err
+					}
+					return binding.IGUserID, nil
+				},
+			})
+			report.DryRun = dryRun
+			if report.Invalid > 0 {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish batch", ig.NewPublishBatchValidationError(report))
+			}
+			if dryRun {
+				return writeSuccess(cmd, runtime, "meta ig publish batch", report, nil, nil)
+			}
+
+			resolvedSchedulePath, err := resolveIGScheduleStatePath(scheduleStatePath)
+			if err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish batch", err)
+			}
+			scheduleService := ig.NewScheduleService(resolvedSchedulePath)
+			service := ig.New(igNewGraphClient())
+
+			err = ig.ExecutePublishBatch(cmd.Context(), report, ig.PublishBatchExecutor{
+				Schedule: func(entry ig.PublishBatchEntry) (*ig.SchedulePublishResult, error) {
+					return scheduleService.Schedule(ig.SchedulePublishOptions{
+						Profile:        creds.Name,
+						Version:        resolvedVersion,
+						Surface:        entry.Surface,
+						IdempotencyKey: entry.IdempotencyKey,
+						IGUserID:       entry.IGUserID,
+						MediaURL:       entry.MediaURL,
+						Caption:        entry.Caption,
+						MediaType:      entry.MediaType,
+						StrictMode:     strict,
+						PublishAt:      entry.PublishAt,
+					})
+				},
+				Publish: func(ctx context.Context, entry ig.PublishBatchEntry) (*ig.FeedPublishResult, error) {
+					if _, err := service.PublishQuotaPreflight(ctx, resolvedVersion, creds.Token, creds.AppSecret, ig.PublishQuotaPreflightOptions{
+						IGUserID: entry.IGUserID,
+						Policy:   quotaPolicy,
+						Reserve:  quotaReserve,
+						Required: 1,
+					}); err != nil {
+						return nil, err
+					}
+
+					options := ig.FeedPublishOptions{
+						IGUserID:       entry.IGUserID,
+						MediaURL:       entry.MediaURL,
+						Caption:        entry.Caption,
+						MediaType:      entry.MediaType,
+						StrictMode:     strict,
+						IdempotencyKey: entry.IdempotencyKey,
+					}
+					switch entry.Surface {
+					case ig.PublishSurfaceFeed:
+						return service.PublishFeedImmediate(ctx, resolvedVersion, creds.Token, creds.AppSecret, options)
+					case ig.PublishSurfaceReel:
+						return service.PublishReelImmediate(ctx, resolvedVersion, creds.Token, creds.AppSecret, options)
+					case ig.PublishSurfaceStory:
+						return service.PublishStoryImmediate(ctx, resolvedVersion, creds.Token, creds.AppSecret, options)
+					default:
+						return nil, errors.New("unsupported batch surface")
+					}
+				},
+			})
+			if err != nil {
+				return writeIGPublishScheduleCommandError(cmd, runtime, "meta ig publish batch", err)
+			}
+			return writeSuccess(cmd, runtime, "meta ig publish batch", report, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&igUserID, "ig-user-id", "", "Default Instagram user id for rows without ig_user_id (optional when profile has ig_user_id)")
+	cmd.Flags().StringVar(&filePath, "file", "", "Content calendar file (.csv with header row or .json array)")
+	cmd.Flags().StringVar(&scheduleStatePath, "schedule-state-path", "", "Schedule state file path (defaults to ~/.meta/ig/schedules.json)")
+	cmd.Flags().BoolVar(&strict, "strict", true, "Treat caption warnings as errors")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate every row and print the plan without publishing or scheduling")
+	addIGCaptionLintConfigFlag(cmd, &lintConfig)
+	addIGPublishQuotaFlags(cmd, &quotaPolicy, &quotaReserve)
+	return cmd
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func writeIGPublishBatchCalendar(t *testing.T, name string, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write calendar: %v", err)
+	}
+	return path
+}
+
+func TestIGPublishBatchPublishesAndSchedulesRows(t *testing.T) {
+	stub := &igPublishSequenceHTTPClient{
+		t: t,
+		responses: []igPublishSequenceResponse{
+			{statusCode: http.StatusOK, response: `{"id":"creation_1","status_code":"IN_PROGRESS"}`},
+			{statusCode: http.StatusOK, response: `{"id":"creation_1","status":"FINISHED","status_code":"FINISHED"}`},
+			{statusCode: http.StatusOK, response: `{"id":"media_1"}`},
+		},
+	}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:      "prod",
+				Profile:   config.Profile{GraphVersion: "v25.0", IGUserID: "17841400008460056"},
+				Token:     "test-token",
+				AppSecret: "test-secret",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	publishAt := time.Now().UTC().Add(3 * time.Hour).Format(time.RFC3339)
+	calendar := writeIGPublishBatchCalendar(t, "calendar.csv", "id,surface,media_url,caption,media_type,publish_at\n"+
+		"launch,feed,https://cdn.example.com/launch.jpg,launch day #meta,IMAGE,\n"+
+		"teaser,reel,https://cdn.example.com/teaser.mp4,teaser,REELS,"+publishAt+"\n")
+	statePath := filepath.Join(t.TempDir(), "ig-schedules.json")
+
+	output := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"publish", "batch", "--file", calendar, "--schedule-state-path", statePath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute batch: %v", err)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta ig publish batch")
+	data, ok := envelope["data"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected object payload, got %T", envelope["data"])
+	}
+	if data["published"] != float64(1) || data["scheduled"] != float64(1) || data["failed"] != float64(0) {
+		t.Fatalf("unexpected counters %#v", data)
+	}
+	rows, _ := data["rows"].([]any)
+	if len(rows) != 2 {
+		t.Fatalf("expected two rows, got %#v", data["rows"])
+	}
+	first, _ := rows[0].(map[string]any)
+	if first["status"] != "published" || first["media_id"] != "media_1" || first["idempotency_key"] != "batch:launch" {
+		t.Fatalf("unexpected first row %#v", first)
+	}
+	second, _ := rows[1].(map[string]any)
+	if second["status"] != "scheduled" || second["schedule_id"] == "" || second["idempotency_key"] != "batch:teaser" {
+		t.Fatalf("unexpected second row %#v", second)
+	}
+	if stub.quotaCalls != 1 {
+		t.Fatalf("expected one quota preflight for the immediate row, got %d", stub.quotaCalls)
+	}
+	if !strings.Contains(stub.calls[0].body, "idempotency_key=batch%3Alaunch") {
+		t.Fatalf("expected row-derived idempotency key in upload body, got %q", stub.calls[0].body)
+	}
+}
+
+func TestIGPublishBatchRejectsInvalidRowsBeforePublishing(t *testing.T) {
+	wasCalled := false
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:      "prod",
+				Profile:   config.Profile{GraphVersion: "v25.0", IGUserID: "17841400008460056"},
+				Token:     "test-token",
+				AppSecret: "test-secret",
+			}, nil
+		},
+		func() *graph.Client {
+			wasCalled = true
+			return graph.NewClient(nil, "")
+		},
+	)
+
+	publishAt := time.Now().UTC().Add(3 * time.Hour).Format(time.RFC3339)
+	calendar := writeIGPublishBatchCalendar(t, "calendar.json", `[
+		{"id":"a","media_url":"https://cdn.example.com/a.jpg","caption":"a","media_type":"IMAGE","publish_at":"`+publishAt+`"},
+		{"id":"b","media_url":"https://cdn.example.com/b.jpg","caption":"b","media_type":"IMAGE","publish_at":"`+publishAt+`"}
+	]`)
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"publish", "batch", "--file", calendar, "--schedule-state-path", filepath.Join(t.TempDir(), "s.json")})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected validation error")
+	}
+	if wasCalled {
+		t.Fatal("graph client should not be created when rows fail validation")
+	}
+
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	errorBody, ok := envelope["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected error payload, got %T", envelope["error"])
+	}
+	if got := errorBody["type"]; got != "ig_batch_validation_error" {
+		t.Fatalf("unexpected error type %v", got)
+	}
+	if !strings.Contains(errorBody["message"].(string), "1 invalid row(s) of 2") {
+		t.Fatalf("unexpected message %v", errorBody["message"])
+	}
+}
+
+func TestIGPublishBatchDryRunReturnsPlan(t *testing.T) {
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:      "prod",
+				Profile:   config.Profile{GraphVersion: "v25.0"},
+				Token:     "test-token",
+				AppSecret: "test-secret",
+			}, nil
+		},
+		func() *graph.Client {
+			t.Fatal("graph client should not be created during dry-run")
+			return nil
+		},
+	)
+
+	calendar := writeIGPublishBatchCalendar(t, "calendar.csv", "id,media_url,caption\npost-1,https://cdn.example.com/a.jpg,hello\n")
+	output := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"publish", "batch", "--file", calendar, "--ig-user-id", "17841400008460056", "--dry-run"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute dry-run: %v", err)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	data, _ := envelope["data"].(map[string]any)
+	if data["dry_run"] != true {
+		t.Fatalf("expected dry_run=true, got %#v", data)
+	}
+	rows, _ := data["rows"].([]any)
+	row, _ := rows[0].(map[string]any)
+	if row["status"] != "valid" || row["ig_user_id"] != "17841400008460056" || row["media_type"] != "IMAGE" {
+		t.Fatalf("unexpected planned row %#v", row)
+	}
+}
@@ -25,14 +25,34 @@ type igPublishCapturedCall struct {
 	body   string
 }
 
+const igPublishDefaultQuotaResponse = `{"data":[{"config":{"quota_total":100,"quota_duration":86400},"quota_usage":0}]}`
+
+// igPublishSequenceHTTPClient answers content_publishing_limit preflight reads out of
+// band (quotaResponse, defaulting to an unused quota) so responses/calls only cover
+// the publish flow itself.
 type igPublishSequenceHTTPClient struct {
 	t *testing.T
 
-	responses []igPublishSequenceResponse
-	calls     []igPublishCapturedCall
+	responses     []igPublishSequenceResponse
+	calls         []igPublishCapturedCall
+	quotaResponse string
+	quotaCalls    int
 }
 
 func (c *igPublishSequenceHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/content_publishing_limit") {
+		c.quotaCalls++
+		quotaResponse := c.quotaResponse
+		if quotaResponse == "" {
+			quotaResponse = igPublishDefaultQuotaResponse
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(quotaResponse)),
+		}, nil
+	}
+
 	body := ""
 	if req.Body != nil {
 		rawBody, readErr := io.ReadAll(req.Body)
@@ -76,7 +96,7 @@ func TestNewIGCommandIncludesPublishSubcommands(t *testing.T) {
 		t.Fatalf("expected publish command, got %#v", publishCmd)
 	}
 
-	for _, name := range []string{"feed", "reel", "story", "schedule"} {
+	for _, name := range []string{"feed", "reel", "story", "carousel", "schedule", "quota", "batch"} {
 		subcommand, _, err := cmd.Find([]string{"publish", name})
 		if err != nil {
 			t.Fatalf("find publish %s command: %v", name, err)
@@ -210,6 +230,13 @@ func TestIGPublishFeedCommandExecutesImmediateFlow(t *testing.T) {
 	if got := data["idempotency_key"]; got != "feed_01" {
 		t.Fatalf("unexpected idempotency_key %v", got)
 	}
+	if stub.quotaCalls != 1 {
+		t.Fatalf("expected one publish quota preflight read, got %d", stub.quotaCalls)
+	}
+	quotaPreflight, ok := data["quota_preflight"].(map[string]any)
+	if !ok || quotaPreflight["policy"] != "fail" {
+		t.Fatalf("unexpected quota_preflight %#v", data["quota_preflight"])
+	}
 	if errOutput.Len() != 0 {
 		t.Fatalf("expected empty stderr, got %q", errOutput.String())
 	}
@@ -985,3 +1012,274 @@ func TestIGPublishCommandFailsWithoutSubcommand(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestIGPublishCarouselCommandDryRunShowsContainerGraph(t *testing.T) {
+	stub := &igPublishSequenceHTTPClient{t: t}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:      "prod",
+				Profile:   config.Profile{GraphVersion: "v25.0"},
+				Token:     "test-token",
+				AppSecret: "test-secret",
+			}, nil
+		},
+		func() *graph.Client {
+			return graph.NewClient(stub, "https://graph.example.com")
+		},
+	)
+
+	output := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{
+		"publish", "carousel",
+		"--ig-user-id", "17841400008460056",
+		"--media-url", "https://cdn.example.com/one.jpg",
+		"--media-url", "https://cdn.example.com/two.jpg",
+		"--caption", "hello #meta",
+		"--dry-run",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute ig publish carousel dry-run: %v", err)
+	}
+	if len(stub.calls) != 0 {
+		t.Fatalf("expected no graph calls, got %d", len(stub.calls))
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta ig publish carousel")
+	data, ok := envelope["data"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected object payload, got %T", envelope["data"])
+	}
+	if got := data["mode"]; got != "dry_run" {
+		t.Fatalf("unexpected mode %v", got)
+	}
+	plan, ok := data["plan"].([]any)
+	if !ok || len(plan) != 4 {
+		t.Fatalf("unexpected plan %v", data["plan"])
+	}
+	container, _ := plan[2].(map[string]any)
+	form, _ := container["form"].(map[string]any)
+	if got := form["children"]; got != "{{child_1.id}},{{child_2.id}}" {
+		t.Fatalf("unexpected carousel children %v", got)
+	}
+}
+
+func TestIGPublishCarouselCommandWritesPartialFailureDiagnostics(t *testing.T) {
+	stub := &igPublishSequenceHTTPClient{
+		t: t,
+		responses: []igPublishSequenceResponse{
+			{statusCode: http.StatusOK, response: `{"id":"child_1"}`},
+			{statusCode: http.StatusOK, response: `{"id":"child_1","status_code":"FINISHED"}`},
+			{statusCode: http.StatusBadRequest, response: `{"error":{"message":"Invalid image url","type":"OAuthException","code":100}}`},
+		},
+	}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:      "prod",
+				Profile:   config.Profile{GraphVersion: "v25.0"},
+				Token:     "test-token",
+				AppSecret: "test-secret",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{
+		"publish", "carousel",
+		"--ig-user-id", "17841400008460056",
+		"--media-url", "https://cdn.example.com/one.jpg",
+		"--media-url", "https://cdn.example.com/two.jpg",
+		"--caption", "hello #meta",
+		"--poll-interval", "1ms",
+	})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected command error")
+	}
+	if len(stub.calls) != 3 {
+		t.Fatalf("expected two child uploads and one status call, got %d", len(stub.calls))
+	}
+
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	errorBody, ok := envelope["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected error payload, got %T", envelope["error"])
+	}
+	if got := errorBody["type"]; got != "ig_carousel_partial_failure" {
+		t.Fatalf("unexpected error type %v", got)
+	}
+	diagnostics, ok := errorBody["diagnostics"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected diagnostics, got %T", errorBody["diagnostics"])
+	}
+	if got := diagnostics["failed_child_index"]; got != float64(2) {
+		t.Fatalf("unexpected failed_child_index %v", got)
+	}
+	created, ok := diagnostics["created_containers"].([]any)
+	if !ok || len(created) != 1 {
+		t.Fatalf("unexpected created containers %v", diagnostics["created_containers"])
+	}
+}
+
+func TestIGPublishFeedFailsClosedWhenPublishQuotaExhausted(t *testing.T) {
+	stub := &igPublishSequenceHTTPClient{
+		t:             t,
+		quotaResponse: `{"data":[{"config":{"quota_total":100,"quota_duration":86400},"quota_usage":100}]}`,
+	}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{GraphVersion: "v25.0"},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{
+		"publish", "feed",
+		"--ig-user-id", "17841400008460056",
+		"--media-url", "https://cdn.example.com/image.jpg",
+		"--caption", "hello #meta",
+	})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected quota gate error")
+	}
+	if len(stub.calls) != 0 {
+		t.Fatalf("expected no publish calls after quota gate, got %d", len(stub.calls))
+	}
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	errorBody, _ := envelope["error"].(map[string]any)
+	if errorBody["type"] != "ig_publish_quota_gate" {
+		t.Fatalf("unexpected error %#v", envelope["error"])
+	}
+	diagnostics, _ := errorBody["diagnostics"].(map[string]any)
+	if diagnostics["remaining"] != float64(0) || diagnostics["quota_total"] != float64(100) {
+		t.Fatalf("unexpected diagnostics %#v", diagnostics)
+	}
+}
+
+func TestIGPublishQuotaCommandReportsRemainingPerIGUser(t *testing.T) {
+	stub := &igPublishSequenceHTTPClient{
+		t:             t,
+		quotaResponse: `{"data":[{"config":{"quota_total":100,"quota_duration":86400},"quota_usage":42}]}`,
+	}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{GraphVersion: "v25.0"},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	output := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SetOut(output)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"publish", "quota", "--ig-user-id", "178,179"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute ig publish quota: %v", err)
+	}
+	if stub.quotaCalls != 2 {
+		t.Fatalf("expected two quota reads, got %d", stub.quotaCalls)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta ig publish quota")
+	data, ok := envelope["data"].([]any)
+	if !ok || len(data) != 2 {
+		t.Fatalf("unexpected data %#v", envelope["data"])
+	}
+	second, _ := data[1].(map[string]any)
+	if second["ig_user_id"] != "179" || second["remaining"] != float64(58) || second["quota_usage"] != float64(42) {
+		t.Fatalf("unexpected quota row %#v", second)
+	}
+}
+
+func TestIGPublishStoryRejectsUnsupportedStickersBeforeGraphCalls(t *testing.T) {
+	stub := &igPublishSequenceHTTPClient{t: t}
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{GraphVersion: "v25.0"},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{
+		"publish", "story",
+		"--ig-user-id", "17841400008460056",
+		"--media-url", "https://cdn.example.com/story.mp4",
+		"--caption", "Coming soon",
+		"--link-sticker", "https://shop.example.com/drop",
+	})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected story element violation")
+	}
+	if len(stub.calls) != 0 || stub.quotaCalls != 0 {
+		t.Fatalf("expected no graph calls, got calls=%d quota=%d", len(stub.calls), stub.quotaCalls)
+	}
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	errorBody, _ := envelope["error"].(map[string]any)
+	if errorBody["type"] != "ig_story_element_violation" {
+		t.Fatalf("unexpected error %#v", envelope["error"])
+	}
+	diagnostics, _ := errorBody["diagnostics"].(map[string]any)
+	violations, _ := diagnostics["violations"].([]any)
+	if len(violations) != 1 {
+		t.Fatalf("unexpected violations %#v", diagnostics)
+	}
+}
@@ -0,0 +1,518 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/auth"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/filelock"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/i18n"
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/bilalbayram/metacli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+const (
+	initStepOK      = "ok"
+	initStepSkipped = "skipped"
+	initStepFailed  = "failed"
+)
+
+var (
+	initConfigPath             = config.DefaultPath
+	initLoadProfileCredentials = loadProfileCredentials
+	initListAdAccounts         = func(ctx context.Context, creds *ProfileCredentials, version string) ([]map[string]any, error) {
+		result, err := marketing.NewAccountService(graph.NewClient(nil, "")).List(ctx, version, creds.Token, creds.AppSecret, marketing.AccountListInput{
+			FollowNext: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.Accounts, nil
+	}
+	initSyncSchema = func(ctx context.Context, schemaDir string) (schema.SyncResult, error) {
+		provider := schema.NewProvider(schemaDir, schema.DefaultManifestURL, schema.DefaultManifestPubKey)
+		return provider.SyncWithRequest(ctx, schema.SyncRequest{
+			Channel:             "stable",
+			RemoteFailurePolicy: schema.SyncRemoteFailurePolicyHardFail,
+		})
+	}
+	initSmokeRead = smokeReadProfile
+)
+
+type initStep struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type initResult struct {
+	Profile        string     `json:"profile"`
+	ConfigPath     string     `json:"config_path"`
+	DefaultProfile bool       `json:"default_profile"`
+	Scopes         []string   `json:"scopes"`
+	TokenExpiresAt string     `json:"token_expires_at"`
+	PageID         string     `json:"page_id,omitempty"`
+	IGUserID       string     `json:"ig_user_id,omitempty"`
+	AccountID      string     `json:"account_id,omitempty"`
+	Steps          []initStep `json:"steps"`
+}
+
+func (r *initResult) step(name string, status string, detail string, err error) {
+	step := initStep{Name: name, Status: status, Detail: detail}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	r.Steps = append(r.Steps, step)
+}
+
+// initPrompter asks the wizard's questions on stderr so stdout keeps only the
+// envelope. Questions answered by a flag are never asked, and a blank answer,
+// end of input or --non-interactive keeps the default. Questions are shown in
+// lang; answers are read the same in every language.
+type initPrompter struct {
+	in          *bufio.Scanner
+	out         io.Writer
+	interactive bool
+	lang        string
+}
+
+func (p *initPrompter) heading(title string) {
+	fmt.Fprintf(p.out, "\n== %s\n", i18n.Translate(p.lang, title))
+}
+
+func (p *initPrompter) ask(question string, current string) (string, error) {
+	return p.prompt(i18n.Translate(p.lang, question), current)
+}
+
+func (p *initPrompter) prompt(question string, current string) (string, error) {
+	if !p.interactive {
+		return current, nil
+	}
+	if current != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, current)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	if !p.in.Scan() {
+		fmt.Fprintln(p.out)
+		return current, p.in.Err()
+	}
+	if answer := strings.TrimSpace(p.in.Text()); answer != "" {
+		return answer, nil
+	}
+	return current, nil
+}
+
+func (p *initPrompter) require(question string, current string, flag string) (string, error) {
+	answer, err := p.ask(question, current)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(answer) == "" {
+		return "", fmt.Errorf("%s is required (--%s)", strings.ToLower(question), flag)
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+func (p *initPrompter) confirm(question string, fallback bool) (bool, error) {
+	hint := "Y/n"
+	if !fallback {
+		hint = "y/N"
+	}
+	answer, err := p.prompt(i18n.Translate(p.lang, question)+" ("+i18n.Translate(p.lang, hint)+")", "")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "":
+		return fallback, nil
+	case "y", "yes", "e", "evet":
+		return true, nil
+	case "n", "no", "h", "hayır":
+		return false, nil
+	default:
+		return false, fmt.Errorf("answer %q is not yes or no", answer)
+	}
+}
+
+// choose lists options and returns the picked index, defaulting to the first.
+// Answering 0 skips the choice and returns -1.
+func (p *initPrompter) choose(question string, options []string) (int, error) {
+	if len(options) == 0 {
+		return -1, nil
+	}
+	if p.interactive {
+		for i, option := range options {
+			fmt.Fprintf(p.out, "  %d) %s\n", i+1, option)
+		}
+	}
+	answer, err := p.prompt(i18n.Translate(p.lang, question)+" ("+i18n.Translate(p.lang, "0 to skip")+")", "1")
+	if err != nil {
+		return -1, err
+	}
+	index, err := strconv.Atoi(answer)
+	if err != nil || index < 0 || index > len(options) {
+		return -1, fmt.Errorf("choose a number between 0 and %d, got %q", len(options), answer)
+	}
+	return index - 1, nil
+}
+
+func NewInitCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile        string
+		appID          string
+		appSecret      string
+		mode           string
+		scopePack      string
+		listenAddr     string
+		redirectURI    string
+		timeout        time.Duration
+		openBrowser    bool
+		pageID         string
+		accountID      string
+		setDefault     bool
+		schemaDir      string
+		skipSchemaSync bool
+		skipSmoke      bool
+		nonInteractive bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "First-run setup: app credentials, login, asset discovery, default profile, schema sync and a smoke read",
+		Long: "Walks a new user from nothing to a working config in one command. Flags answer the\n" +
+			"matching questions up front; with --non-interactive every other question takes its default.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			commandName := "meta init"
+			prompter := &initPrompter{
+				in:          bufio.NewScanner(cmd.InOrStdin()),
+				out:         cmd.ErrOrStderr(),
+				interactive: !nonInteractive,
+				lang:        runtime.Language(),
+			}
+			result := initResult{Steps: []initStep{}}
+			fail := func(err error) error {
+				return writeCommandError(cmd, runtime, commandName, err)
+			}
+
+			configPath, err := initConfigPath()
+			if err != nil {
+				return fail(err)
+			}
+			result.ConfigPath = configPath
+
+			prompter.heading("App credentials")
+			if profile == "" {
+				profile = runtime.ProfileName()
+			}
+			if profile == "" {
+				profile = "default"
+			}
+			if !cmd.Flags().Changed("profile") {
+				if profile, err = prompter.require("Profile name", profile, "profile"); err != nil {
+					return fail(err)
+				}
+			}
+			result.Profile = profile
+			if strings.TrimSpace(appID) == "" {
+				if appID, err = prompter.require("Meta App ID", "", "app-id"); err != nil {
+					return fail(err)
+				}
+			}
+			if strings.TrimSpace(appSecret) == "" {
+				if appSecret, err = prompter.require("Meta App Secret", "", "app-secret"); err != nil {
+					return fail(err)
+				}
+			}
+			if !cmd.Flags().Changed("scope-pack") {
+				if scopePack, err = prompter.ask("Scope pack (solo_smb|ads_only|ig_publish)", scopePack); err != nil {
+					return fail(err)
+				}
+			}
+			resolvedMode, err := normalizeAuthMode(mode)
+			if err != nil {
+				return fail(err)
+			}
+			scopes, err := scopePackScopes(scopePack, resolvedMode)
+			if err != nil {
+				return fail(err)
+			}
+			result.step("app_credentials", initStepOK, fmt.Sprintf("app %s, scope pack %s", appID, scopePack), nil)
+
+			prompter.heading("Login")
+			svc, err := newAuthCLIService()
+			if err != nil {
+				return fail(err)
+			}
+			login, err := runOAuthAutoLogin(cmd, svc, oauthLoginInput{
+				Profile:      profile,
+				AppID:        appID,
+				AppSecret:    appSecret,
+				Scopes:       scopes,
+				ListenAddr:   listenAddr,
+				RedirectURI:  redirectURI,
+				Timeout:      timeout,
+				OpenBrowser:  openBrowser,
+				Version:      config.DefaultGraphVersion,
+				AuthProvider: authProviderFromMode(resolvedMode),
+				AuthMode:     resolvedMode,
+			})
+			if err != nil {
+				return fail(err)
+			}
+			result.Scopes = login.Scopes
+			result.TokenExpiresAt = login.ExpiresAt.Format(time.RFC3339)
+			result.step("login", initStepOK, "token expires "+result.TokenExpiresAt, nil)
+
+			prompter.heading("Asset discovery")
+			if err := discoverInitAssets(cmd.Context(), prompter, svc, &result, strings.TrimSpace(pageID), strings.TrimSpace(accountID)); err != nil {
+				return fail(err)
+			}
+
+			prompter.heading("Default profile")
+			isDefault, err := saveInitDefaults(prompter, configPath, &result, setDefault, cmd.Flags().Changed("set-default"))
+			if err != nil {
+				return fail(err)
+			}
+			result.DefaultProfile = isDefault
+
+			prompter.heading("Schema packs")
+			if skipSchemaSync {
+				result.step("schema_sync", initStepSkipped, "--skip-schema-sync", nil)
+			} else if synced, err := initSyncSchema(cmd.Context(), schemaDir); err != nil {
+				result.step("schema_sync", initStepFailed, "", err)
+			} else {
+				result.step("schema_sync", initStepOK, fmt.Sprintf("%d pack(s) on channel %s", len(synced.Packs), synced.Channel), nil)
+			}
+
+			prompter.heading("Smoke read")
+			if skipSmoke {
+				result.step("smoke_read", initStepSkipped, "--skip-smoke", nil)
+			} else if detail, err := runInitSmokeRead(cmd.Context(), profile, result.AccountID); err != nil {
+				result.step("smoke_read", initStepFailed, "", err)
+			} else {
+				result.step("smoke_read", initStepOK, detail, nil)
+			}
+
+			for _, step := range result.Steps {
+				if step.Status == initStepFailed {
+					return writeInitIncompleteError(cmd, runtime, commandName, result, step)
+				}
+			}
+			return writeSuccess(cmd, runtime, commandName, result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile to create (default: global --profile, then \"default\")")
+	cmd.Flags().StringVar(&appID, "app-id", "", "Meta App ID")
+	cmd.Flags().StringVar(&appSecret, "app-secret", "", "Meta App Secret")
+	cmd.Flags().StringVar(&mode, "mode", auth.AuthModeBoth, "Auth mode: both|facebook|instagram")
+	cmd.Flags().StringVar(&scopePack, "scope-pack", "solo_smb", "Scope pack: solo_smb|ads_only|ig_publish")
+	cmd.Flags().StringVar(&listenAddr, "listen", defaultAuthListenAddr, "OAuth callback listener host:port")
+	cmd.Flags().StringVar(&redirectURI, "redirect-uri", "", "OAuth redirect URI override (recommended for https tunnel domains)")
+	cmd.Flags().DurationVar(&timeout, "callback-timeout", defaultAuthTimeout, "How long to wait for the OAuth callback")
+	cmd.Flags().BoolVar(&openBrowser, "open-browser", true, "Open browser automatically")
+	cmd.Flags().StringVar(&pageID, "page-id", "", "Page to bind instead of choosing from the discovered pages")
+	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account to store as the profile's default instead of choosing one")
+	cmd.Flags().BoolVar(&setDefault, "set-default", true, "Make the new profile the default profile")
+	cmd.Flags().StringVar(&schemaDir, "schema-dir", schema.DefaultSchemaDir(), "Schema pack root directory")
+	cmd.Flags().BoolVar(&skipSchemaSync, "skip-schema-sync", false, "Do not sync schema packs")
+	cmd.Flags().BoolVar(&skipSmoke, "skip-smoke", false, "Do not run the smoke read")
+	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Never prompt; unanswered questions take their defaults")
+	return cmd
+}
+
+// discoverInitAssets binds a page (and its Instagram account) and picks the ad
+// account stored as the profile's default --account-id.
+func discoverInitAssets(ctx context.Context, prompter *initPrompter, svc authCLIService, result *initResult, pageID string, accountID string) error {
+	pages, err := svc.DiscoverPagesAndIGBusinessAccounts(ctx, result.Profile)
+	if err != nil {
+		return err
+	}
+	if pageID == "" {
+		options := make([]string, 0, len(pages))
+		for _, page := range pages {
+			option := fmt.Sprintf("%s (%s)", page.Name, page.PageID)
+			if page.IGBusinessAccountID != "" {
+				option += " · instagram " + page.IGBusinessAccountID
+			}
+			options = append(options, option)
+		}
+		index, err := prompter.choose("Page to bind", options)
+		if err != nil {
+			return err
+		}
+		if index >= 0 {
+			pageID = pages[index].PageID
+		}
+	}
+	for _, page := range pages {
+		if page.PageID == pageID {
+			result.IGUserID = page.IGBusinessAccountID
+		}
+	}
+	result.PageID = pageID
+	if pageID != "" {
+		if err := svc.UpdateProfileBindings(ctx, auth.UpdateProfileBindingsInput{
+			Profile:  result.Profile,
+			PageID:   pageID,
+			IGUserID: result.IGUserID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if accountID == "" && grantsAdsRead(result.Scopes) {
+		creds, err := initLoadProfileCredentials(result.Profile)
+		if err != nil {
+			return err
+		}
+		accounts, err := initListAdAccounts(ctx, creds, config.DefaultGraphVersion)
+		if err != nil {
+			return err
+		}
+		options := make([]string, 0, len(accounts))
+		for _, account := range accounts {
+			options = append(options, fmt.Sprint(account["display"]))
+		}
+		index, err := prompter.choose("Default ad account", options)
+		if err != nil {
+			return err
+		}
+		if index >= 0 {
+			accountID = fmt.Sprint(accounts[index]["account_id"])
+		}
+	}
+	result.AccountID = strings.TrimPrefix(accountID, "act_")
+
+	detail := fmt.Sprintf("%d page(s)", len(pages))
+	if result.PageID != "" {
+		detail += ", bound page " + result.PageID
+	}
+	if result.AccountID != "" {
+		detail += ", ad account act_" + result.AccountID
+	}
+	result.step("asset_discovery", initStepOK, detail, nil)
+	return nil
+}
+
+func grantsAdsRead(scopes []string) bool {
+	for _, scope := range scopes {
+		if scope == "ads_read" || scope == "ads_management" {
+			return true
+		}
+	}
+	return false
+}
+
+// saveInitDefaults stores the chosen ad account as the profile's default
+// --account-id and, when confirmed, makes the profile the default profile.
+func saveInitDefaults(prompter *initPrompter, configPath string, result *initResult, setDefault bool, answered bool) (bool, error) {
+	lock, err := filelock.Acquire(configPath)
+	if err != nil {
+		return false, err
+	}
+	defer lock.Release()
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return false, err
+	}
+	profile, ok := cfg.Profiles[result.Profile]
+	if !ok {
+		return false, fmt.Errorf("profile %q was not saved by login", result.Profile)
+	}
+	if cfg.DefaultProfile != result.Profile && cfg.DefaultProfile != "" && !answered {
+		if setDefault, err = prompter.confirm(i18n.Sprintf(prompter.lang, "Replace default profile %q with %q?", cfg.DefaultProfile, result.Profile), setDefault); err != nil {
+			return false, err
+		}
+	}
+	if setDefault || cfg.DefaultProfile == "" {
+		cfg.DefaultProfile = result.Profile
+	}
+	if result.AccountID != "" {
+		profile.Defaults.AccountID = result.AccountID
+		cfg.Profiles[result.Profile] = profile
+	}
+	if err := config.SaveHeld(lock, configPath, cfg); err != nil {
+		return false, err
+	}
+
+	isDefault := cfg.DefaultProfile == result.Profile
+	detail := fmt.Sprintf("default profile is %s", cfg.DefaultProfile)
+	if result.AccountID != "" {
+		detail += fmt.Sprintf("; --account-id defaults to %s for %s", result.AccountID, result.Profile)
+	}
+	result.step("default_profile", initStepOK, detail, nil)
+	return isDefault, nil
+}
+
+func runInitSmokeRead(ctx context.Context, profile string, accountID string) (string, error) {
+	creds, err := initLoadProfileCredentials(profile)
+	if err != nil {
+		return "", err
+	}
+	return initSmokeRead(ctx, creds, config.DefaultGraphVersion, accountID)
+}
+
+// smokeReadProfile proves the stored token works with one read of the token's
+// user and, when one was chosen, the default ad account.
+func smokeReadProfile(ctx context.Context, creds *ProfileCredentials, version string, accountID string) (string, error) {
+	client := graph.NewClient(nil, "")
+	me, err := client.Do(ctx, graph.Request{
+		Method:      "GET",
+		Path:        "me",
+		Version:     version,
+		Query:       map[string]string{"fields": "id,name"},
+		AccessToken: creds.Token,
+		AppSecret:   creds.AppSecret,
+	})
+	if err != nil {
+		return "", err
+	}
+	detail := fmt.Sprintf("read me as %v (%v)", me.Body["name"], me.Body["id"])
+	if accountID == "" {
+		return detail, nil
+	}
+	account, err := client.Do(ctx, graph.Request{
+		Method:      "GET",
+		Path:        "act_" + accountID,
+		Version:     version,
+		Query:       map[string]string{"fields": "id,name,account_status,currency"},
+		AccessToken: creds.Token,
+		AppSecret:   creds.AppSecret,
+	})
+	if err != nil {
+		return "", err
+	}
+	return detail + fmt.Sprintf(", read ad account %v (%v)", account.Body["name"], account.Body["id"]), nil
+}
+
+func writeInitIncompleteError(cmd *cobra.Command, runtime Runtime, commandName string, result initResult, failed initStep) error {
+	err := fmt.Errorf("profile %s is set up but %s failed: %s", result.Profile, failed.Name, failed.Error)
+	errorInfo := &output.ErrorInfo{
+		Type:      "init_incomplete",
+		Message:   err.Error(),
+		Retryable: false,
+	}
+
+	envelope, envErr := output.NewEnvelope(commandName, false, result, nil, nil, errorInfo)
+	if envErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, envErr)
+	}
+	if writeErr := writeEnvelope(cmd.ErrOrStderr(), runtime, envelope); writeErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, writeErr)
+	}
+	return err
+}
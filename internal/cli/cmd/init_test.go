@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/auth"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/schema"
+)
+
+type initStubs struct {
+	configPath   string
+	smokeAccount string
+	smokeErr     error
+	synced       bool
+}
+
+// useInitStubs writes a config holding the profiles login would have saved and
+// replaces every network step of meta init.
+func useInitStubs(t *testing.T, profiles ...string) *initStubs {
+	t.Helper()
+	stubs := &initStubs{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+	cfg := config.New()
+	for _, name := range profiles {
+		if err := cfg.UpsertProfile(name, config.Profile{
+			TokenType:       "user",
+			AppID:           "app_1",
+			TokenRef:        "keychain://meta-marketing-cli/" + name + "/token",
+			AppSecretRef:    "keychain://meta-marketing-cli/" + name + "/app_secret",
+			AuthProvider:    "facebook_login",
+			AuthMode:        "both",
+			Scopes:          []string{"ads_read"},
+			IssuedAt:        "2026-01-01T00:00:00Z",
+			ExpiresAt:       "2027-01-01T00:00:00Z",
+			LastValidatedAt: "2026-01-01T00:00:00Z",
+		}); err != nil {
+			t.Fatalf("upsert profile %s: %v", name, err)
+		}
+	}
+	if err := config.Save(stubs.configPath, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	originalPath := initConfigPath
+	originalCreds := initLoadProfileCredentials
+	originalAccounts := initListAdAccounts
+	originalSync := initSyncSchema
+	originalSmoke := initSmokeRead
+	t.Cleanup(func() {
+		initConfigPath = originalPath
+		initLoadProfileCredentials = originalCreds
+		initListAdAccounts = originalAccounts
+		initSyncSchema = originalSync
+		initSmokeRead = originalSmoke
+	})
+	initConfigPath = func() (string, error) { return stubs.configPath, nil }
+	initLoadProfileCredentials = func(profile string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{Name: profile, Token: "token_1", AppSecret: "secret_1"}, nil
+	}
+	initListAdAccounts = func(context.Context, *ProfileCredentials, string) ([]map[string]any, error) {
+		return []map[string]any{
+			{"account_id": "111", "display": "Acme (act_111) · USD"},
+			{"account_id": "222", "display": "Globex (act_222) · EUR"},
+		}, nil
+	}
+	initSyncSchema = func(context.Context, string) (schema.SyncResult, error) {
+		stubs.synced = true
+		return schema.SyncResult{Channel: "stable", Packs: []schema.PackRef{{}}}, nil
+	}
+	initSmokeRead = func(_ context.Context, _ *ProfileCredentials, _ string, accountID string) (string, error) {
+		stubs.smokeAccount = accountID
+		if stubs.smokeErr != nil {
+			return "", stubs.smokeErr
+		}
+		return "read me as Jane (1)", nil
+	}
+	return stubs
+}
+
+func TestInitWalksThroughEveryStep(t *testing.T) {
+	stubs := useInitStubs(t, "existing", "acme")
+	service := &stubAuthService{
+		discoveredPages: []auth.DiscoveredPage{
+			{PageID: "p_1", Name: "Page One"},
+			{PageID: "p_2", Name: "Page Two", IGBusinessAccountID: "ig_2"},
+		},
+	}
+	useAuthServiceFactory(t, func() (authCLIService, error) { return service, nil })
+	useOAuthAutomationStubs(t)
+
+	stdout, prompts := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := NewInitCommand(testRuntime(""))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(stdout)
+	cmd.SetErr(prompts)
+	// profile, app id, app secret, scope pack, page, ad account, replace default
+	cmd.SetIn(strings.NewReader("acme\napp_1\nsecret_1\n\n2\n\ny\n"))
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute init: %v\n%s", err, prompts.String())
+	}
+
+	if service.addUserInput == nil || service.addUserInput.Profile != "acme" || service.addUserInput.AppSecret != "secret_1" {
+		t.Fatalf("unexpected login input: %+v", service.addUserInput)
+	}
+	if service.updateProfileBindingsIn == nil || service.updateProfileBindingsIn.PageID != "p_2" || service.updateProfileBindingsIn.IGUserID != "ig_2" {
+		t.Fatalf("unexpected bindings: %+v", service.updateProfileBindingsIn)
+	}
+	for _, prompt := range []string{"Profile name [default]:", "2) Page Two (p_2) · instagram ig_2", "1) Acme (act_111) · USD", `Replace default profile "existing" with "acme"?`} {
+		if !strings.Contains(prompts.String(), prompt) {
+			t.Fatalf("expected prompt %q in:\n%s", prompt, prompts.String())
+		}
+	}
+
+	cfg, err := config.Load(stubs.configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.DefaultProfile != "acme" || cfg.Profiles["acme"].Defaults.AccountID != "111" {
+		t.Fatalf("unexpected config: default=%s defaults=%+v", cfg.DefaultProfile, cfg.Profiles["acme"].Defaults)
+	}
+	if !stubs.synced || stubs.smokeAccount != "111" {
+		t.Fatalf("expected schema sync and a smoke read of act_111, got synced=%v account=%q", stubs.synced, stubs.smokeAccount)
+	}
+
+	envelope := decodeEnvelope(t, stdout.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta init")
+	data := envelope["data"].(map[string]any)
+	if data["default_profile"] != true || data["account_id"] != "111" {
+		t.Fatalf("unexpected data: %v", data)
+	}
+	var names []string
+	for _, raw := range data["steps"].([]any) {
+		step := raw.(map[string]any)
+		if step["status"] != "ok" {
+			t.Fatalf("unexpected step: %v", step)
+		}
+		names = append(names, step["name"].(string))
+	}
+	if strings.Join(names, ",") != "app_credentials,login,asset_discovery,default_profile,schema_sync,smoke_read" {
+		t.Fatalf("unexpected steps: %v", names)
+	}
+}
+
+func TestInitNonInteractiveReportsIncompleteSetup(t *testing.T) {
+	stubs := useInitStubs(t, "existing", "acme")
+	stubs.smokeErr = errors.New("(#190) Invalid OAuth access token")
+	service := &stubAuthService{discoveredPages: []auth.DiscoveredPage{{PageID: "p_1", Name: "Page One"}}}
+	useAuthServiceFactory(t, func() (authCLIService, error) { return service, nil })
+	useOAuthAutomationStubs(t)
+
+	cmd := NewInitCommand(testRuntime(""))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--non-interactive", "--app-id", "app_1"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "meta app secret is required (--app-secret)") {
+		t.Fatalf("expected missing app secret to fail, got %v", err)
+	}
+
+	stderr := &bytes.Buffer{}
+	cmd = NewInitCommand(testRuntime(""))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"--non-interactive", "--profile", "acme", "--app-id", "app_1", "--app-secret", "secret_1", "--account-id", "act_222", "--skip-schema-sync"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "profile acme is set up but smoke_read failed") {
+		t.Fatalf("expected incomplete setup error, got %v", err)
+	}
+
+	cfg, loadErr := config.Load(stubs.configPath)
+	if loadErr != nil {
+		t.Fatalf("load config: %v", loadErr)
+	}
+	if cfg.DefaultProfile != "acme" || cfg.Profiles["acme"].Defaults.AccountID != "222" {
+		t.Fatalf("unexpected config: default=%s defaults=%+v", cfg.DefaultProfile, cfg.Profiles["acme"].Defaults)
+	}
+	if stubs.synced || stubs.smokeAccount != "222" {
+		t.Fatalf("unexpected sync/smoke: synced=%v account=%q", stubs.synced, stubs.smokeAccount)
+	}
+
+	// Progress lines precede the envelope on stderr.
+	envelope := decodeEnvelope(t, stderr.Bytes()[strings.Index(stderr.String(), "{"):])
+	if envelope["error"].(map[string]any)["type"] != "init_incomplete" {
+		t.Fatalf("unexpected error: %v", envelope["error"])
+	}
+	steps := envelope["data"].(map[string]any)["steps"].([]any)
+	if steps[4].(map[string]any)["status"] != "skipped" || steps[5].(map[string]any)["status"] != "failed" {
+		t.Fatalf("unexpected steps: %v", steps)
+	}
+}
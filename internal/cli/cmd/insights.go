@@ -74,6 +74,9 @@ func NewInsightsCommand(runtime Runtime) *cobra.Command {
 	}
 	insightsCmd.AddCommand(newInsightsAccountsCommand(runtime))
 	insightsCmd.AddCommand(newInsightsRunCommand(runtime))
+	insightsCmd.AddCommand(newInsightsGetCommand(runtime))
+	insightsCmd.AddCommand(newInsightsJobsCommand(runtime))
+	insightsCmd.AddCommand(newInsightsExportCommand(runtime))
 	insightsCmd.AddCommand(newInsightsActionTypesCommand(runtime))
 	return insightsCmd
 }
@@ -82,6 +85,7 @@ func newInsightsRunCommand(runtime Runtime) *cobra.Command {
 	var (
 		profile           string
 		accountID         string
+		accountName       string
 		level             string
 		datePreset        string
 		breakdowns        string
@@ -103,7 +107,7 @@ func newInsightsRunCommand(runtime Runtime) *cobra.Command {
 			if profile == "" {
 				return errors.New("profile is required (--profile or global --profile)")
 			}
-			if accountID == "" {
+			if accountID == "" && strings.TrimSpace(accountName) == "" {
 				return missingInsightsAccountIDError(profile)
 			}
 			level, err := normalizeInsightsLevel(level)
@@ -131,6 +135,9 @@ func newInsightsRunCommand(runtime Runtime) *cobra.Command {
 			if version == "" {
 				version = config.DefaultGraphVersion
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, version, &accountID, accountName); err != nil {
+				return err
+			}
 
 			client := insightsNewGraphClient()
 			service := insightsNewService(client)
@@ -152,11 +159,12 @@ func newInsightsRunCommand(runtime Runtime) *cobra.Command {
 				result.Rows = insights.NormalizeLocalIntentRows(result.Rows)
 			}
 
-			return writeInsightsOutput(cmd, "meta insights run", format, result.Rows, result.Pagination)
+			return writeInsightsOutput(cmd, runtime, "meta insights run", format, result.Rows, result.Pagination)
 		},
 	}
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id without act_ prefix")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&level, "level", "campaign", "Insights level: account|campaign|adset|ad")
 	cmd.Flags().StringVar(&datePreset, "date-preset", "last_7d", "Date preset (for example last_7d)")
 	cmd.Flags().StringVar(&breakdowns, "breakdowns", "", "Comma-separated breakdowns")
@@ -174,6 +182,7 @@ func newInsightsActionTypesCommand(runtime Runtime) *cobra.Command {
 	var (
 		profile           string
 		accountID         string
+		accountName       string
 		level             string
 		datePreset        string
 		attribution       string
@@ -194,7 +203,7 @@ func newInsightsActionTypesCommand(runtime Runtime) *cobra.Command {
 			if profile == "" {
 				return errors.New("profile is required (--profile or global --profile)")
 			}
-			if accountID == "" {
+			if accountID == "" && strings.TrimSpace(accountName) == "" {
 				return missingInsightsAccountIDError(profile)
 			}
 
@@ -218,6 +227,9 @@ func newInsightsActionTypesCommand(runtime Runtime) *cobra.Command {
 			if version == "" {
 				version = config.DefaultGraphVersion
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, version, &accountID, accountName); err != nil {
+				return err
+			}
 
 			client := insightsNewGraphClient()
 			service := insightsNewService(client)
@@ -235,12 +247,13 @@ func newInsightsActionTypesCommand(runtime Runtime) *cobra.Command {
 				return err
 			}
 
-			return writeInsightsOutput(cmd, "meta insights action-types", format, insights.DiscoverActionTypes(result.Rows), result.Pagination)
+			return writeInsightsOutput(cmd, runtime, "meta insights action-types", format, insights.DiscoverActionTypes(result.Rows), result.Pagination)
 		},
 	}
 
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id without act_ prefix")
+	addAccountNameFlag(cmd, &accountName)
 	cmd.Flags().StringVar(&level, "level", "ad", "Insights level: account|campaign|adset|ad")
 	cmd.Flags().StringVar(&datePreset, "date-preset", "last_30d", "Date preset (for example last_30d)")
 	cmd.Flags().StringVar(&attribution, "attribution", "", "Comma-separated action attribution windows")
@@ -330,7 +343,7 @@ func missingInsightsAccountIDError(profile string) error {
 	if strings.TrimSpace(profile) != "" {
 		suggestion = fmt.Sprintf("%s --profile %s", suggestion, profile)
 	}
-	return fmt.Errorf("account id is required (--account-id). discover active accounts with: %s", suggestion)
+	return fmt.Errorf("account id is required (--account-id or --account). discover active accounts with: %s", suggestion)
 }
 
 func insightsFieldsForMetricPack(metricPack string) []string {
@@ -376,7 +389,11 @@ func normalizeInsightsFormat(format string) (string, error) {
 	}
 }
 
-func writeInsightsOutput(cmd *cobra.Command, commandName string, format string, data any, paging any) error {
+func writeInsightsOutput(cmd *cobra.Command, runtime Runtime, commandName string, format string, data any, paging any) error {
+	data, err := applyOutputQuery(runtime, data)
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
 	env, err := output.NewEnvelope(commandName, true, data, paging, nil, nil)
 	if err != nil {
 		return err
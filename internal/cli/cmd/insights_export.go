@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/insights"
+	"github.com/bilalbayram/metacli/internal/s3"
+	"github.com/spf13/cobra"
+)
+
+const (
+	insightsExportModeAppend    = "append"
+	insightsExportModeOverwrite = "overwrite"
+)
+
+var (
+	insightsNow         = time.Now
+	insightsNewS3Client = s3.NewClientFromEnv
+)
+
+type insightsExportResult struct {
+	Template    string                     `json:"template"`
+	AccountID   string                     `json:"account_id"`
+	DatePreset  string                     `json:"date_preset,omitempty"`
+	Since       string                     `json:"since,omitempty"`
+	Until       string                     `json:"until,omitempty"`
+	Async       bool                       `json:"async"`
+	ReportRunID string                     `json:"report_run_id,omitempty"`
+	Output      *insights.ExportFileResult `json:"output"`
+}
+
+func newInsightsExportCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile      string
+		templatePath string
+		accountID    string
+		accountName  string
+		outPath      string
+		format       string
+		mode         string
+		asOf         string
+		pollInterval time.Duration
+		maxPolls     int
+		version      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Run an insights export template and write rows to a file or S3 object",
+		Long:  "Run a local YAML export template (level, fields, breakdowns, filters, date window) and append deterministic csv/jsonl rows to a file or S3 object. Designed to be run from cron.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if profile == "" {
+				profile = runtime.ProfileName()
+			}
+			if profile == "" {
+				return errors.New("profile is required (--profile or global --profile)")
+			}
+			template, err := insights.LoadExportTemplate(templatePath)
+			if err != nil {
+				return err
+			}
+			destination, err := resolveInsightsExportDestination(outPath)
+			if err != nil {
+				return err
+			}
+			if accountID == "" {
+				accountID = template.AccountID
+			}
+			accountID = strings.TrimPrefix(strings.TrimSpace(accountID), "act_")
+			if accountID == "" && strings.TrimSpace(accountName) == "" {
+				return missingInsightsAccountIDError(profile)
+			}
+			if format == "" {
+				format = template.Format
+			}
+			if format == "" {
+				format = insights.ExportFormatCSV
+			}
+			mode = strings.ToLower(strings.TrimSpace(mode))
+			if mode != insightsExportModeAppend && mode != insightsExportModeOverwrite {
+				return errors.New("invalid --mode value: expected append|overwrite")
+			}
+			if pollInterval <= 0 {
+				return errors.New("--poll-interval must be > 0")
+			}
+			if maxPolls <= 0 {
+				return errors.New("--max-polls must be > 0")
+			}
+			runDate := insightsNow().UTC()
+			if strings.TrimSpace(asOf) != "" {
+				runDate, err = time.Parse(time.DateOnly, strings.TrimSpace(asOf))
+				if err != nil {
+					return errors.New("invalid --as-of value: expected YYYY-MM-DD")
+				}
+			}
+			options := template.RunOptions(accountID, runDate)
+
+			creds, err := insightsLoadProfileCredentials(profile)
+			if err != nil {
+				return err
+			}
+			if version == "" {
+				version = creds.Profile.GraphVersion
+			}
+			if version == "" {
+				version = config.DefaultGraphVersion
+			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, version, &accountID, accountName); err != nil {
+				return err
+			}
+			options.AccountID = accountID
+
+			service := insightsNewService(insightsNewGraphClient())
+			service.PollInterval = pollInterval
+			service.MaxPollAttempts = maxPolls
+			result, err := service.Run(cmd.Context(), version, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return err
+			}
+
+			columns := insights.ExportColumns(result.Rows, template.Columns())
+			if err := insights.SortRows(result.Rows, template.SortBy, columns); err != nil {
+				return err
+			}
+			written, err := destination.write(cmd.Context(), format, result.Rows, columns, mode == insightsExportModeAppend)
+			if err != nil {
+				return err
+			}
+			return writeSuccess(cmd, runtime, "meta insights export", insightsExportResult{
+				Template:    template.Name,
+				AccountID:   accountID,
+				DatePreset:  options.DatePreset,
+				Since:       options.Since,
+				Until:       options.Until,
+				Async:       options.Async,
+				ReportRunID: result.ReportRunID,
+				Output:      written,
+			}, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&templatePath, "template", "", "Export template YAML path")
+	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (overrides the template account_id)")
+	addAccountNameFlag(cmd, &accountName)
+	cmd.Flags().StringVar(&outPath, "out", "", "Output file path (plain path or file:// URL) or S3 object (s3://bucket/key, credentials from AWS_* variables)")
+	cmd.Flags().StringVar(&format, "format", "", "Output format: csv|jsonl (defaults to the template format, then csv)")
+	cmd.Flags().StringVar(&mode, "mode", insightsExportModeAppend, "Write mode: append|overwrite")
+	cmd.Flags().StringVar(&asOf, "as-of", "", "Run date for relative last_days windows (YYYY-MM-DD, defaults to today UTC)")
+	cmd.Flags().DurationVar(&pollInterval, "poll-interval", 2*time.Second, "Async report run poll interval")
+	cmd.Flags().IntVar(&maxPolls, "max-polls", 60, "Maximum async report run status polls")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	mustMarkFlagRequired(cmd, "template")
+	mustMarkFlagRequired(cmd, "out")
+	return cmd
+}
+
+// insightsExportDestination is where --out writes: a local file, or an S3
+// object staged through a temp file.
+type insightsExportDestination struct {
+	path     string
+	location *s3.Location
+	client   *s3.Client
+}
+
+func resolveInsightsExportDestination(out string) (insightsExportDestination, error) {
+	out = strings.TrimSpace(out)
+	switch {
+	case out == "":
+		return insightsExportDestination{}, errors.New("--out is required")
+	case strings.HasPrefix(out, "file://"):
+		path := strings.TrimPrefix(out, "file://")
+		if path == "" {
+			return insightsExportDestination{}, fmt.Errorf("invalid --out value %q: file path is empty", out)
+		}
+		return insightsExportDestination{path: path}, nil
+	case strings.HasPrefix(out, "s3://"):
+		location, err := s3.ParseURL(out)
+		if err != nil {
+			return insightsExportDestination{}, fmt.Errorf("invalid --out value: %w", err)
+		}
+		client, err := insightsNewS3Client()
+		if err != nil {
+			return insightsExportDestination{}, err
+		}
+		return insightsExportDestination{location: &location, client: client}, nil
+	case strings.Contains(out, "://"):
+		return insightsExportDestination{}, fmt.Errorf("unsupported --out destination %q: expected a file path, file:// or s3:// URL", out)
+	default:
+		return insightsExportDestination{path: out}, nil
+	}
+}
+
+// write writes rows as insights.WriteExportFile does. An S3 object is
+// downloaded to a temp file when appending, written there and uploaded again
+// on the condition that nobody changed it in between.
+func (d insightsExportDestination) write(ctx context.Context, format string, rows []map[string]any, columns []string, appendRows bool) (*insights.ExportFileResult, error) {
+	if d.location == nil {
+		return insights.WriteExportFile(d.path, format, rows, columns, appendRows)
+	}
+
+	dir, err := os.MkdirTemp("", "meta-export-*")
+	if err != nil {
+		return nil, fmt.Errorf("create export staging directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	staging := filepath.Join(dir, path.Base(d.location.Key))
+
+	options := s3.PutOptions{ContentType: "text/csv"}
+	if strings.EqualFold(strings.TrimSpace(format), insights.ExportFormatJSONL) {
+		options.ContentType = "application/x-ndjson"
+	}
+	if appendRows {
+		object, err := d.client.Get(ctx, *d.location)
+		switch {
+		case errors.Is(err, s3.ErrNotFound):
+			options.IfAbsent = true
+		case err != nil:
+			return nil, err
+		default:
+			if err := os.WriteFile(staging, object.Body, 0o600); err != nil {
+				return nil, fmt.Errorf("stage %s: %w", d.location, err)
+			}
+			options.IfMatch = object.ETag
+		}
+	}
+
+	written, err := insights.WriteExportFile(staging, format, rows, columns, appendRows)
+	if err != nil {
+		return nil, err
+	}
+	body, err := os.ReadFile(staging)
+	if err != nil {
+		return nil, fmt.Errorf("read staged export %s: %w", staging, err)
+	}
+	if err := d.client.Put(ctx, *d.location, body, options); err != nil {
+		return nil, err
+	}
+	written.Path = d.location.String()
+	return written, nil
+}
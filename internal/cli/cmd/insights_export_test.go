@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInsightsExportAppendsSortedRowsFromTemplate(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"campaign_id":"c2","spend":"3.00","date_start":"2026-04-08","date_stop":"2026-04-14"},{"campaign_id":"c1","spend":"5.00","date_start":"2026-04-08","date_stop":"2026-04-14"}]}`,
+	}
+	useInsightsStubDependencies(t, stub)
+
+	dir := t.TempDir()
+	templatePath := filepath.Join(dir, "weekly-performance.yaml")
+	if err := os.WriteFile(templatePath, []byte(`schema_version: 1
+name: weekly-performance
+account_id: act_123
+level: campaign
+fields: [campaign_id, spend]
+filters:
+  - field: spend
+    operator: GREATER_THAN
+    value: 0
+date:
+  last_days: 7
+`), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	outPath := filepath.Join(dir, "exports", "weekly.csv")
+
+	for run := 0; run < 2; run++ {
+		envelope, err := executeInsightsCommand(t, "export", "--template", templatePath, "--out", "file://"+outPath, "--as-of", "2026-04-15")
+		if err != nil {
+			t.Fatalf("run %d: insights export: %v", run, err)
+		}
+		assertEnvelopeBasics(t, envelope, "meta insights export")
+		data, _ := envelope["data"].(map[string]any)
+		output, _ := data["output"].(map[string]any)
+		if data["since"] != "2026-04-08" || data["until"] != "2026-04-14" || output["appended"] != (run == 1) {
+			t.Fatalf("run %d: unexpected export summary %#v", run, data)
+		}
+	}
+
+	requestURL, err := url.Parse(stub.lastURL)
+	if err != nil {
+		t.Fatalf("parse request url: %v", err)
+	}
+	query := requestURL.Query()
+	if requestURL.Path != "/v25.0/act_123/insights" || query.Get("time_range") != `{"since":"2026-04-08","until":"2026-04-14"}` || query.Get("filtering") == "" {
+		t.Fatalf("unexpected export request %s", stub.lastURL)
+	}
+
+	content, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	row1 := "c1,5.00,2026-04-08,2026-04-14\n"
+	row2 := "c2,3.00,2026-04-08,2026-04-14\n"
+	if string(content) != "campaign_id,spend,date_start,date_stop\n"+row1+row2+row1+row2 {
+		t.Fatalf("unexpected export content %q", string(content))
+	}
+}
+
+func TestInsightsExportAppendsToS3ObjectConditionally(t *testing.T) {
+	useInsightsStubDependencies(t, &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"spend":"5.00","date_start":"2026-04-08","date_stop":"2026-04-14"}]}`,
+	})
+
+	objects := map[string]string{}
+	var puts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+			t.Errorf("expected a signed request, got %q", r.Header.Get("Authorization"))
+		}
+		current, exists := objects[r.URL.Path]
+		switch r.Method {
+		case http.MethodGet:
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", fmt.Sprintf(`"%d"`, len(current)))
+			_, _ = io.WriteString(w, current)
+		case http.MethodPut:
+			puts = append(puts, r.Header.Get("If-None-Match")+"|"+r.Header.Get("If-Match")+"|"+r.Header.Get("Content-Type"))
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = string(body)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("AWS_ACCESS_KEY_ID", "key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_ENDPOINT_URL_S3", server.URL)
+
+	templatePath := filepath.Join(t.TempDir(), "template.yaml")
+	if err := os.WriteFile(templatePath, []byte("schema_version: 1\nname: x\nlevel: account\nfields: [spend]\ndate: {last_days: 7}\n"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	for run := 0; run < 2; run++ {
+		envelope, err := executeInsightsCommand(t, "export", "--template", templatePath, "--account-id", "123", "--out", "s3://reports/meta/weekly.csv", "--as-of", "2026-04-15")
+		if err != nil {
+			t.Fatalf("run %d: insights export: %v", run, err)
+		}
+		data, _ := envelope["data"].(map[string]any)
+		output, _ := data["output"].(map[string]any)
+		if output["path"] != "s3://reports/meta/weekly.csv" || output["appended"] != (run == 1) {
+			t.Fatalf("run %d: unexpected export output %#v", run, output)
+		}
+	}
+
+	header := "spend,date_start,date_stop\n"
+	row := "5.00,2026-04-08,2026-04-14\n"
+	if content := objects["/reports/meta/weekly.csv"]; content != header+row+row {
+		t.Fatalf("unexpected object content %q", content)
+	}
+	if len(puts) != 2 || puts[0] != "*||text/csv" || puts[1] != fmt.Sprintf(`|"%d"|text/csv`, len(header+row)) {
+		t.Fatalf("expected an if-absent put then an if-match put, got %q", puts)
+	}
+}
+
+func TestInsightsExportRejectsUnsupportedDestinations(t *testing.T) {
+	useInsightsStubDependencies(t, &stubHTTPClient{t: t, statusCode: http.StatusOK, response: `{"data":[]}`})
+
+	templatePath := filepath.Join(t.TempDir(), "template.yaml")
+	if err := os.WriteFile(templatePath, []byte("schema_version: 1\nname: x\nlevel: account\nfields: [spend]\ndate: {preset: last_7d}\n"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	_, err := executeInsightsCommand(t, "export", "--template", templatePath, "--account-id", "123", "--out", "gs://bucket/weekly.csv")
+	if err == nil || !strings.Contains(err.Error(), "unsupported --out destination") {
+		t.Fatalf("expected unsupported destination error, got %v", err)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	_, err = executeInsightsCommand(t, "export", "--template", templatePath, "--account-id", "123", "--out", "s3://bucket/weekly.csv")
+	if err == nil || !strings.Contains(err.Error(), "AWS_ACCESS_KEY_ID") {
+		t.Fatalf("expected missing s3 credentials error, got %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/insights"
+	"github.com/spf13/cobra"
+)
+
+const (
+	insightsAsyncAuto   = "auto"
+	insightsAsyncAlways = "always"
+	insightsAsyncNever  = "never"
+)
+
+type insightsGetExport struct {
+	Path        string `json:"path"`
+	Format      string `json:"format"`
+	Rows        int    `json:"rows"`
+	Async       bool   `json:"async"`
+	ReportRunID string `json:"report_run_id,omitempty"`
+}
+
+func newInsightsGetCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile           string
+		accountID         string
+		accountName       string
+		level             string
+		fields            string
+		datePreset        string
+		since             string
+		until             string
+		timeIncrement     string
+		breakdowns        string
+		attribution       string
+		publisherPlatform string
+		limit             int
+		asyncMode         string
+		pollInterval      time.Duration
+		maxPolls          int
+		format            string
+		outPath           string
+		noWait            bool
+		jobStatePath      string
+		concurrency       int
+		version           string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Fetch insights for accounts, campaigns, ad sets, or ads",
+		Long:  "Fetch insights with explicit fields, breakdowns, and date windows. Large queries are submitted as async report runs and polled until ready; --out writes raw rows to a csv/jsonl/json file.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if profile == "" {
+				profile = runtime.ProfileName()
+			}
+			if profile == "" {
+				return errors.New("profile is required (--profile or global --profile)")
+			}
+			accountID = strings.TrimPrefix(strings.TrimSpace(accountID), "act_")
+			if accountID == "" && strings.TrimSpace(accountName) == "" {
+				return missingInsightsAccountIDError(profile)
+			}
+
+			var err error
+			level, err = normalizeInsightsLevel(level)
+			if err != nil {
+				return err
+			}
+			format, err = normalizeInsightsFormat(format)
+			if err != nil {
+				return err
+			}
+			asyncMode = strings.ToLower(strings.TrimSpace(asyncMode))
+			switch asyncMode {
+			case insightsAsyncAuto, insightsAsyncAlways, insightsAsyncNever:
+			default:
+				return errors.New("invalid --async value: expected auto|always|never")
+			}
+			if pollInterval <= 0 {
+				return errors.New("--poll-interval must be > 0")
+			}
+			if maxPolls <= 0 {
+				return errors.New("--max-polls must be > 0")
+			}
+			if concurrency <= 0 {
+				return errors.New("--concurrency must be > 0")
+			}
+			if strings.TrimSpace(since) != "" || strings.TrimSpace(until) != "" {
+				datePreset = ""
+			}
+
+			options := insights.RunOptions{
+				AccountID:         accountID,
+				Level:             level,
+				DatePreset:        datePreset,
+				Since:             since,
+				Until:             until,
+				TimeIncrement:     timeIncrement,
+				Breakdowns:        csvToSlice(breakdowns),
+				Attribution:       csvToSlice(attribution),
+				Fields:            csvToSlice(fields),
+				Limit:             limit,
+				PublisherPlatform: strings.ToLower(strings.TrimSpace(publisherPlatform)),
+				Concurrency:       concurrency,
+			}
+			switch asyncMode {
+			case insightsAsyncAlways:
+				options.Async = true
+			case insightsAsyncAuto:
+				options.Async = insights.ShouldRunAsync(options)
+			}
+
+			creds, err := insightsLoadProfileCredentials(profile)
+			if err != nil {
+				return err
+			}
+			if version == "" {
+				version = creds.Profile.GraphVersion
+			}
+			if version == "" {
+				version = config.DefaultGraphVersion
+			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, version, &accountID, accountName); err != nil {
+				return err
+			}
+			options.AccountID = accountID
+
+			service := insightsNewService(insightsNewGraphClient())
+			if noWait {
+				return submitInsightsJob(cmd, runtime, service, creds, version, jobStatePath, options)
+			}
+			service.PollInterval = pollInterval
+			service.MaxPollAttempts = maxPolls
+			runProgress := startProgress(cmd, runtime, "insights get", int64(options.Limit))
+			runProgress.Bar().SetUnit("rows")
+			result, err := service.Run(runProgress.Context(cmd.Context()), version, creds.Token, creds.AppSecret, options)
+			runProgress.Finish()
+			if err != nil {
+				return err
+			}
+
+			if strings.TrimSpace(outPath) == "" {
+				return writeInsightsOutput(cmd, runtime, "meta insights get", format, result.Rows, result.Pagination)
+			}
+			if err := writeInsightsExportFile(outPath, format, result.Rows, options.Fields); err != nil {
+				return err
+			}
+			return writeSuccess(cmd, runtime, "meta insights get", insightsGetExport{
+				Path:        outPath,
+				Format:      format,
+				Rows:        len(result.Rows),
+				Async:       options.Async,
+				ReportRunID: result.ReportRunID,
+			}, result.Pagination, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (act_ prefix optional)")
+	addAccountNameFlag(cmd, &accountName)
+	cmd.Flags().StringVar(&level, "level", "campaign", "Insights level: account|campaign|adset|ad")
+	cmd.Flags().StringVar(&fields, "fields", "", "Comma-separated insights fields (for example impressions,spend,ctr)")
+	cmd.Flags().StringVar(&datePreset, "date-preset", "last_7d", "Date preset (ignored when --since/--until are set)")
+	cmd.Flags().StringVar(&since, "since", "", "Time range start (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&until, "until", "", "Time range end (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&timeIncrement, "time-increment", "", "Time increment: 1-90 days, monthly, or all_days")
+	cmd.Flags().StringVar(&breakdowns, "breakdowns", "", "Comma-separated breakdowns (for example age,gender)")
+	cmd.Flags().StringVar(&attribution, "attribution", "", "Comma-separated action attribution windows")
+	cmd.Flags().StringVar(&publisherPlatform, "publisher-platform", "", "Filter insight rows to a publisher platform (for example instagram)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Limit total rows returned")
+	cmd.Flags().StringVar(&asyncMode, "async", insightsAsyncAuto, "Async report run mode: auto|always|never")
+	cmd.Flags().DurationVar(&pollInterval, "poll-interval", 2*time.Second, "Async report run poll interval")
+	cmd.Flags().IntVar(&maxPolls, "max-polls", 60, "Maximum async report run status polls")
+	cmd.Flags().StringVar(&format, "format", "jsonl", "Export format: json|jsonl|csv")
+	cmd.Flags().StringVar(&outPath, "out", "", "Write raw rows to this file instead of stdout")
+	cmd.Flags().BoolVar(&noWait, "no-wait", false, "Submit an async report run, record it locally, and return without polling")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "Split a --since/--until window with a day or monthly --time-increment into this many date slices fetched in parallel")
+	cmd.Flags().StringVar(&jobStatePath, "job-state-path", "", "Insights job state path for --no-wait (defaults to ~/.meta/insights/jobs.json)")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	return cmd
+}
+
+func submitInsightsJob(cmd *cobra.Command, runtime Runtime, service *insights.Service, creds *ProfileCredentials, version string, jobStatePath string, options insights.RunOptions) error {
+	store, err := resolveInsightsJobStore(jobStatePath)
+	if err != nil {
+		return err
+	}
+	reportRunID, params, err := service.Submit(cmd.Context(), version, creds.Token, creds.AppSecret, options)
+	if err != nil {
+		return err
+	}
+	job, err := store.Put(insights.Job{
+		ReportRunID:       reportRunID,
+		Profile:           creds.Name,
+		Version:           version,
+		AccountID:         options.AccountID,
+		Params:            params,
+		PublisherPlatform: options.PublisherPlatform,
+		Status:            insights.JobStatusRunning,
+	})
+	if err != nil {
+		return err
+	}
+	return writeSuccess(cmd, runtime, "meta insights get", job, nil, nil)
+}
+
+func writeInsightsExportFile(path string, format string, rows []map[string]any, fields []string) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create export directory for %s: %w", path, err)
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create export file %s: %w", path, err)
+	}
+	if err := insights.WriteRows(file, format, rows, insights.ExportColumns(rows, fields), true); err != nil {
+		file.Close()
+		return fmt.Errorf("write export file %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close export file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInsightsGetWritesCSVExportInFieldOrder(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"spend":"12.50","impressions":"1000","ctr":"1.2","date_start":"2026-03-01"}]}`,
+	}
+	useInsightsStubDependencies(t, stub)
+
+	outPath := filepath.Join(t.TempDir(), "exports", "campaigns.csv")
+	output := &bytes.Buffer{}
+	cmd := newInsightsGetCommand(testRuntime("prod"))
+	cmd.SetOut(output)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{
+		"--account-id", "act_123",
+		"--fields", "impressions,spend,ctr",
+		"--since", "2026-03-01",
+		"--until", "2026-03-07",
+		"--format", "csv",
+		"--out", outPath,
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute insights get: %v", err)
+	}
+
+	requestURL, err := url.Parse(stub.lastURL)
+	if err != nil {
+		t.Fatalf("parse request url: %v", err)
+	}
+	if requestURL.Path != "/v25.0/act_123/insights" || stub.lastMethod != http.MethodGet {
+		t.Fatalf("expected sync insights request, got %s %s", stub.lastMethod, requestURL.Path)
+	}
+	if got := requestURL.Query().Get("date_preset"); got != "" {
+		t.Fatalf("expected date_preset to be dropped with since/until, got %q", got)
+	}
+
+	content, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if string(content) != "impressions,spend,ctr,date_start\n1000,12.50,1.2,2026-03-01\n" {
+		t.Fatalf("unexpected export content %q", string(content))
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta insights get")
+	data, _ := envelope["data"].(map[string]any)
+	if data["rows"] != float64(1) || data["async"] != false || data["path"] != outPath {
+		t.Fatalf("unexpected export summary %#v", data)
+	}
+}
+
+func TestInsightsGetRunsBreakdownQueriesAsync(t *testing.T) {
+	stub := &igPublishSequenceHTTPClient{
+		t: t,
+		responses: []igPublishSequenceResponse{
+			{statusCode: http.StatusOK, response: `{"report_run_id":"run_9"}`},
+			{statusCode: http.StatusOK, response: `{"async_status":"Job Running","async_percent_completion":40}`},
+			{statusCode: http.StatusOK, response: `{"async_status":"Job Completed","async_percent_completion":100}`},
+			{statusCode: http.StatusOK, response: `{"data":[{"age":"18-24","gender":"female","impressions":"10"}]}`},
+		},
+	}
+	useInsightsStubDependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := newInsightsGetCommand(testRuntime("prod"))
+	cmd.SetOut(output)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{
+		"--account-id", "123",
+		"--fields", "impressions",
+		"--breakdowns", "age,gender",
+		"--poll-interval", "1ms",
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute insights get: %v", err)
+	}
+
+	if len(stub.calls) != 4 {
+		t.Fatalf("expected submit, two polls, and fetch, got %d calls", len(stub.calls))
+	}
+	submitForm, err := url.ParseQuery(stub.calls[0].body)
+	if err != nil {
+		t.Fatalf("parse submit form: %v", err)
+	}
+	if stub.calls[0].method != http.MethodPost || submitForm.Get("async") != "true" || submitForm.Get("breakdowns") != "age,gender" {
+		t.Fatalf("unexpected async submit %s %#v", stub.calls[0].method, submitForm)
+	}
+	if !strings.Contains(stub.calls[3].url, "/v25.0/run_9/insights") {
+		t.Fatalf("unexpected fetch url %s", stub.calls[3].url)
+	}
+	if !strings.Contains(output.String(), `"gender":"female"`) {
+		t.Fatalf("expected jsonl rows, got %q", output.String())
+	}
+}
+
+func TestInsightsGetRejectsInvalidAsyncMode(t *testing.T) {
+	useInsightsStubDependencies(t, &stubHTTPClient{t: t})
+
+	cmd := newInsightsGetCommand(testRuntime("prod"))
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--account-id", "123", "--async", "sometimes"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid --async value") {
+		t.Fatalf("expected async mode error, got %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/insights"
+	"github.com/spf13/cobra"
+)
+
+var insightsJobStatePath = insights.DefaultJobStatePath
+
+func newInsightsJobsCommand(runtime Runtime) *cobra.Command {
+	jobsCmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "Manage async insights report runs",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "insights jobs")
+		},
+	}
+	jobsCmd.AddCommand(newInsightsJobsListCommand(runtime))
+	jobsCmd.AddCommand(newInsightsJobsStatusCommand(runtime))
+	jobsCmd.AddCommand(newInsightsJobsCancelCommand(runtime))
+	jobsCmd.AddCommand(newInsightsJobsDownloadCommand(runtime))
+	return jobsCmd
+}
+
+func newInsightsJobsListCommand(runtime Runtime) *cobra.Command {
+	var (
+		status    string
+		statePath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List locally tracked insights report runs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			store, err := resolveInsightsJobStore(statePath)
+			if err != nil {
+				return err
+			}
+			jobs, err := store.List(status)
+			if err != nil {
+				return err
+			}
+			return writeSuccess(cmd, runtime, "meta insights jobs list", jobs, nil, nil)
+		},
+	}
+	cmd.Flags().StringVar(&status, "status", "", "Filter by status: running|completed|failed|cancelled")
+	cmd.Flags().StringVar(&statePath, "state-path", "", "Insights job state path (defaults to ~/.meta/insights/jobs.json)")
+	return cmd
+}
+
+func newInsightsJobsStatusCommand(runtime Runtime) *cobra.Command {
+	var (
+		reportRunID string
+		profile     string
+		version     string
+		statePath   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Refresh a report run's status from the Graph API",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			store, job, creds, resolvedVersion, err := loadInsightsJob(runtime, statePath, reportRunID, profile, version)
+			if err != nil {
+				return err
+			}
+			job, err = refreshInsightsJobStatus(cmd.Context(), store, *job, creds, resolvedVersion)
+			if err != nil {
+				return err
+			}
+			return writeSuccess(cmd, runtime, "meta insights jobs status", job, nil, nil)
+		},
+	}
+	addInsightsJobFlags(cmd, &reportRunID, &profile, &version, &statePath)
+	return cmd
+}
+
+func newInsightsJobsCancelCommand(runtime Runtime) *cobra.Command {
+	var (
+		reportRunID string
+		profile     string
+		version     string
+		statePath   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel a running report run",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			store, job, creds, resolvedVersion, err := loadInsightsJob(runtime, statePath, reportRunID, profile, version)
+			if err != nil {
+				return err
+			}
+			if job.Status == insights.JobStatusCompleted || job.Status == insights.JobStatusCancelled {
+				return errors.New("insights job " + job.ReportRunID + " is already " + job.Status)
+			}
+
+			service := insightsNewService(insightsNewGraphClient())
+			if err := service.CancelReportRun(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, job.ReportRunID); err != nil {
+				return err
+			}
+			job.Status = insights.JobStatusCancelled
+			job, err = store.Put(*job)
+			if err != nil {
+				return err
+			}
+			return writeSuccess(cmd, runtime, "meta insights jobs cancel", job, nil, nil)
+		},
+	}
+	addInsightsJobFlags(cmd, &reportRunID, &profile, &version, &statePath)
+	return cmd
+}
+
+func newInsightsJobsDownloadCommand(runtime Runtime) *cobra.Command {
+	var (
+		reportRunID string
+		profile     string
+		version     string
+		statePath   string
+		outPath     string
+		format      string
+		pageSize    int
+		restart     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "download",
+		Short: "Download a completed report run to a local file (resumable)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(outPath) == "" {
+				return errors.New("--out is required")
+			}
+			store, job, creds, resolvedVersion, err := loadInsightsJob(runtime, statePath, reportRunID, profile, version)
+			if err != nil {
+				return err
+			}
+			if job.Status != insights.JobStatusCompleted {
+				job, err = refreshInsightsJobStatus(cmd.Context(), store, *job, creds, resolvedVersion)
+				if err != nil {
+					return err
+				}
+			}
+			if job.Status != insights.JobStatusCompleted {
+				return errors.New("insights job " + job.ReportRunID + " is " + job.Status + "; download requires a completed report run")
+			}
+
+			service := insightsNewService(insightsNewGraphClient())
+			job, err = service.DownloadJob(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, store, *job, insights.JobDownloadOptions{
+				Path:     outPath,
+				Format:   format,
+				PageSize: pageSize,
+				Restart:  restart,
+			})
+			if err != nil {
+				return err
+			}
+			return writeSuccess(cmd, runtime, "meta insights jobs download", job, nil, nil)
+		},
+	}
+	addInsightsJobFlags(cmd, &reportRunID, &profile, &version, &statePath)
+	cmd.Flags().StringVar(&outPath, "out", "", "Output file path")
+	cmd.Flags().StringVar(&format, "format", insights.ExportFormatCSV, "Download format: csv|jsonl")
+	cmd.Flags().IntVar(&pageSize, "page-size", 500, "Rows requested per page")
+	cmd.Flags().BoolVar(&restart, "restart", false, "Discard download progress and start from the first page")
+	return cmd
+}
+
+func addInsightsJobFlags(cmd *cobra.Command, reportRunID *string, profile *string, version *string, statePath *string) {
+	cmd.Flags().StringVar(reportRunID, "report-run-id", "", "Async report run id")
+	cmd.Flags().StringVar(profile, "profile", "", "Profile name (defaults to the profile that submitted the job)")
+	cmd.Flags().StringVar(version, "version", "", "Graph API version (defaults to the version used at submit)")
+	cmd.Flags().StringVar(statePath, "state-path", "", "Insights job state path (defaults to ~/.meta/insights/jobs.json)")
+}
+
+func resolveInsightsJobStore(statePath string) (*insights.JobStore, error) {
+	if strings.TrimSpace(statePath) == "" {
+		defaultPath, err := insightsJobStatePath()
+		if err != nil {
+			return nil, err
+		}
+		statePath = defaultPath
+	}
+	return insights.NewJobStore(statePath), nil
+}
+
+func loadInsightsJob(runtime Runtime, statePath string, reportRunID string, profile string, version string) (*insights.JobStore, *insights.Job, *ProfileCredentials, string, error) {
+	if strings.TrimSpace(reportRunID) == "" {
+		return nil, nil, nil, "", errors.New("--report-run-id is required")
+	}
+	store, err := resolveInsightsJobStore(statePath)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+	job, err := store.Get(reportRunID)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+
+	if profile == "" {
+		profile = job.Profile
+	}
+	if profile == "" {
+		profile = runtime.ProfileName()
+	}
+	if profile == "" {
+		return nil, nil, nil, "", errors.New("profile is required (--profile or global --profile)")
+	}
+	creds, err := insightsLoadProfileCredentials(profile)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+	if version == "" {
+		version = job.Version
+	}
+	if version == "" {
+		version = creds.Profile.GraphVersion
+	}
+	if version == "" {
+		version = config.DefaultGraphVersion
+	}
+	return store, job, creds, version, nil
+}
+
+func refreshInsightsJobStatus(ctx context.Context, store *insights.JobStore, job insights.Job, creds *ProfileCredentials, version string) (*insights.Job, error) {
+	service := insightsNewService(insightsNewGraphClient())
+	status, err := service.ReportRunStatus(ctx, version, creds.Token, creds.AppSecret, job.ReportRunID)
+	if err != nil {
+		return nil, err
+	}
+	job.AsyncStatus = status.AsyncStatus
+	job.PercentCompletion = status.PercentCompletion
+	if job.Status != insights.JobStatusCancelled {
+		job.Status = status.JobStatus()
+	}
+	return store.Put(job)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func useInsightsJobStatePath(t *testing.T) string {
+	t.Helper()
+	original := insightsJobStatePath
+	t.Cleanup(func() {
+		insightsJobStatePath = original
+	})
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	insightsJobStatePath = func() (string, error) { return path, nil }
+	return path
+}
+
+func executeInsightsCommand(t *testing.T, args ...string) (map[string]any, error) {
+	t.Helper()
+	output := &bytes.Buffer{}
+	cmd := NewInsightsCommand(testRuntime("prod"))
+	cmd.SetOut(output)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs(args)
+	if err := cmd.Execute(); err != nil {
+		return nil, err
+	}
+	return decodeEnvelope(t, output.Bytes()), nil
+}
+
+func TestInsightsJobsSubmitStatusAndDownload(t *testing.T) {
+	useInsightsJobStatePath(t)
+	stub := &igPublishSequenceHTTPClient{
+		t: t,
+		responses: []igPublishSequenceResponse{
+			{statusCode: http.StatusOK, response: `{"report_run_id":"run_5"}`},
+			{statusCode: http.StatusOK, response: `{"async_status":"Job Running","async_percent_completion":35}`},
+			{statusCode: http.StatusOK, response: `{"async_status":"Job Completed","async_percent_completion":100}`},
+			{statusCode: http.StatusOK, response: `{"data":[{"campaign_id":"c1","spend":"4.20"}],"paging":{"cursors":{"after":"a1"}}}`},
+		},
+	}
+	useInsightsStubDependencies(t, stub)
+
+	envelope, err := executeInsightsCommand(t, "get", "--account-id", "123", "--fields", "campaign_id,spend", "--no-wait")
+	if err != nil {
+		t.Fatalf("submit insights job: %v", err)
+	}
+	job, _ := envelope["data"].(map[string]any)
+	if job["report_run_id"] != "run_5" || job["status"] != "running" || job["profile"] != "prod" {
+		t.Fatalf("unexpected submitted job %#v", job)
+	}
+
+	envelope, err = executeInsightsCommand(t, "jobs", "status", "--report-run-id", "run_5")
+	if err != nil {
+		t.Fatalf("insights jobs status: %v", err)
+	}
+	job, _ = envelope["data"].(map[string]any)
+	if job["status"] != "running" || job["percent_completion"] != float64(35) {
+		t.Fatalf("unexpected job status %#v", job)
+	}
+
+	outPath := filepath.Join(t.TempDir(), "run_5.csv")
+	envelope, err = executeInsightsCommand(t, "jobs", "download", "--report-run-id", "run_5", "--out", outPath)
+	if err != nil {
+		t.Fatalf("insights jobs download: %v", err)
+	}
+	job, _ = envelope["data"].(map[string]any)
+	download, _ := job["download"].(map[string]any)
+	if job["status"] != "completed" || download["completed"] != true || download["rows"] != float64(1) {
+		t.Fatalf("unexpected downloaded job %#v", job)
+	}
+	if !strings.Contains(stub.calls[3].url, "/v25.0/run_5/insights") || !strings.Contains(stub.calls[3].url, "fields=campaign_id%2Cspend") {
+		t.Fatalf("unexpected download url %s", stub.calls[3].url)
+	}
+	content, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read download: %v", err)
+	}
+	if string(content) != "campaign_id,spend\nc1,4.20\n" {
+		t.Fatalf("unexpected download content %q", string(content))
+	}
+
+	envelope, err = executeInsightsCommand(t, "jobs", "list", "--status", "completed")
+	if err != nil {
+		t.Fatalf("insights jobs list: %v", err)
+	}
+	if jobs, _ := envelope["data"].([]any); len(jobs) != 1 {
+		t.Fatalf("expected one completed job, got %#v", envelope["data"])
+	}
+}
+
+func TestInsightsJobsCancelMarksJobCancelled(t *testing.T) {
+	useInsightsJobStatePath(t)
+	stub := &igPublishSequenceHTTPClient{
+		t: t,
+		responses: []igPublishSequenceResponse{
+			{statusCode: http.StatusOK, response: `{"report_run_id":"run_6"}`},
+			{statusCode: http.StatusOK, response: `{"success":true}`},
+		},
+	}
+	useInsightsStubDependencies(t, stub)
+
+	if _, err := executeInsightsCommand(t, "get", "--account-id", "123", "--no-wait"); err != nil {
+		t.Fatalf("submit insights job: %v", err)
+	}
+	envelope, err := executeInsightsCommand(t, "jobs", "cancel", "--report-run-id", "run_6")
+	if err != nil {
+		t.Fatalf("cancel insights job: %v", err)
+	}
+	if stub.calls[1].method != http.MethodDelete || !strings.Contains(stub.calls[1].url, "/v25.0/run_6") {
+		t.Fatalf("unexpected cancel request %s %s", stub.calls[1].method, stub.calls[1].url)
+	}
+	job, _ := envelope["data"].(map[string]any)
+	if job["status"] != "cancelled" {
+		t.Fatalf("unexpected cancelled job %#v", job)
+	}
+
+	if _, err := executeInsightsCommand(t, "jobs", "cancel", "--report-run-id", "run_6"); err == nil || !strings.Contains(err.Error(), "already cancelled") {
+		t.Fatalf("expected already cancelled error, got %v", err)
+	}
+}
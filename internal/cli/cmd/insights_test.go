@@ -49,7 +49,7 @@ func TestInsightsRunMissingAccountIDIncludesGuidance(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected missing account id error")
 	}
-	if !strings.Contains(err.Error(), "account id is required (--account-id)") {
+	if !strings.Contains(err.Error(), "account id is required (--account-id or --account)") {
 		t.Fatalf("unexpected error text: %v", err)
 	}
 	if !strings.Contains(err.Error(), "meta insights accounts list --active-only --profile prod") {
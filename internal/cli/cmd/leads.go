@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/insights"
+	"github.com/bilalbayram/metacli/internal/leads"
+	"github.com/bilalbayram/metacli/internal/page"
+	"github.com/spf13/cobra"
+)
+
+var (
+	leadsLoadProfileCredentials = loadProfileCredentials
+	leadsNewGraphClient         = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+)
+
+type leadsExportResult struct {
+	FormID string                     `json:"form_id"`
+	Since  string                     `json:"since,omitempty"`
+	Until  string                     `json:"until,omitempty"`
+	Leads  int                        `json:"leads"`
+	Output *insights.ExportFileResult `json:"output"`
+}
+
+func NewLeadsCommand(runtime Runtime) *cobra.Command {
+	leadsCmd := &cobra.Command{
+		Use:   "leads",
+		Short: "Lead ads forms, exports, and webhook subscriptions",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "leads")
+		},
+	}
+	leadsCmd.AddCommand(newLeadsListCommand(runtime))
+	leadsCmd.AddCommand(newLeadsExportCommand(runtime))
+	leadsCmd.AddCommand(newLeadsSubscribeCommand(runtime))
+	return leadsCmd
+}
+
+func newLeadsListCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile    string
+		version    string
+		pageID     string
+		fieldsRaw  string
+		limit      int
+		followNext bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List lead forms on a Facebook Page",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, resolvedPageID, err := resolveLeadsPageProfile(runtime, profile, version, pageID)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta leads list", err)
+			}
+
+			result, err := leads.New(leadsNewGraphClient()).ListForms(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, leads.ListFormsOptions{
+				PageID:     resolvedPageID,
+				Fields:     csvToSlice(fieldsRaw),
+				Limit:      limit,
+				FollowNext: followNext,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta leads list", err)
+			}
+			return writeSuccess(cmd, runtime, "meta leads list", result.Forms, result.Paging, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name (must hold a page token)")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&pageID, "page-id", "", "Facebook Page ID (optional when profile has page_id)")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated form fields (defaults to "+strings.Join(leads.DefaultFormFields, ",")+")")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of forms to return (0 = unlimited)")
+	cmd.Flags().BoolVar(&followNext, "follow-next", false, "Follow paging.next links")
+	return cmd
+}
+
+func newLeadsExportCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile string
+		version string
+		formID  string
+		since   string
+		until   string
+		format  string
+		outPath string
+		mode    string
+		limit   int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export leads for a form to csv or jsonl with flattened answers",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			destination, err := resolveInsightsExportDestination(outPath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta leads export", err)
+			}
+			switch mode {
+			case insightsExportModeAppend, insightsExportModeOverwrite:
+			default:
+				return writeCommandError(cmd, runtime, "meta leads export", errors.New("--mode must be append or overwrite"))
+			}
+			sinceTime, err := leads.ParseTime(since)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta leads export", err)
+			}
+			untilTime, err := leads.ParseTime(until)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta leads export", err)
+			}
+
+			creds, resolvedVersion, err := resolveLeadsProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta leads export", err)
+			}
+			if err := page.ValidatePageToken(creds.Name, creds.Profile); err != nil {
+				return writeCommandError(cmd, runtime, "meta leads export", err)
+			}
+
+			result, err := leads.New(leadsNewGraphClient()).ListLeads(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, leads.ListLeadsOptions{
+				FormID: formID,
+				Since:  sinceTime,
+				Until:  untilTime,
+				Limit:  limit,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta leads export", err)
+			}
+
+			rows, columns := leads.FlattenLeads(result.Leads)
+			written, err := destination.write(cmd.Context(), format, rows, columns, mode == insightsExportModeAppend)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta leads export", err)
+			}
+			return writeSuccess(cmd, runtime, "meta leads export", leadsExportResult{
+				FormID: result.FormID,
+				Since:  strings.TrimSpace(since),
+				Until:  strings.TrimSpace(until),
+				Leads:  len(result.Leads),
+				Output: written,
+			}, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name (must hold a page token)")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&formID, "form-id", "", "Lead form id")
+	cmd.Flags().StringVar(&since, "since", "", "Include leads created at or after (RFC3339, YYYY-MM-DD, or unix seconds)")
+	cmd.Flags().StringVar(&until, "until", "", "Include leads created before (RFC3339, YYYY-MM-DD, or unix seconds)")
+	cmd.Flags().StringVar(&format, "format", insights.ExportFormatCSV, "Output format: csv|jsonl")
+	cmd.Flags().StringVar(&outPath, "out", "", "Output file path (plain path or file:// URL) or S3 object (s3://bucket/key, credentials from AWS_* variables)")
+	cmd.Flags().StringVar(&mode, "mode", insightsExportModeOverwrite, "Write mode: append|overwrite")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of leads to export (0 = unlimited)")
+	mustMarkFlagRequired(cmd, "out")
+	return cmd
+}
+
+func newLeadsSubscribeCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile   string
+		version   string
+		pageID    string
+		fieldsRaw string
+		remove    bool
+		status    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "subscribe",
+		Short: "Subscribe a Facebook Page to leadgen webhooks for real-time lead delivery",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if remove && status {
+				return writeCommandError(cmd, runtime, "meta leads subscribe", errors.New("--remove and --status cannot be combined"))
+			}
+			if remove && strings.TrimSpace(fieldsRaw) != "" {
+				return writeCommandError(cmd, runtime, "meta leads subscribe", errors.New("--fields cannot be combined with --remove"))
+			}
+			creds, resolvedVersion, resolvedPageID, err := resolveLeadsPageProfile(runtime, profile, version, pageID)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta leads subscribe", err)
+			}
+
+			service := leads.New(leadsNewGraphClient())
+			options := leads.SubscriptionOptions{
+				PageID: resolvedPageID,
+				Fields: csvToSlice(fieldsRaw),
+			}
+			var result *leads.SubscriptionResult
+			switch {
+			case status:
+				result, err = service.Subscriptions(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			case remove:
+				result, err = service.Unsubscribe(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			default:
+				result, err = service.Subscribe(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			}
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta leads subscribe", err)
+			}
+			return writeSuccess(cmd, runtime, "meta leads subscribe", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name (must hold a page token)")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&pageID, "page-id", "", "Facebook Page ID (optional when profile has page_id)")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated webhook fields (defaults to leadgen)")
+	cmd.Flags().BoolVar(&remove, "remove", false, "Remove the app's page subscription")
+	cmd.Flags().BoolVar(&status, "status", false, "Show apps currently subscribed to the page")
+	return cmd
+}
+
+func resolveLeadsPageProfile(runtime Runtime, profile string, version string, pageID string) (*ProfileCredentials, string, string, error) {
+	creds, resolvedVersion, err := resolveLeadsProfileAndVersion(runtime, profile, version)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if err := page.ValidatePageToken(creds.Name, creds.Profile); err != nil {
+		return nil, "", "", err
+	}
+	resolvedPageID := strings.TrimSpace(pageID)
+	if resolvedPageID == "" {
+		resolvedPageID = strings.TrimSpace(creds.Profile.PageID)
+	}
+	if resolvedPageID == "" {
+		return nil, "", "", errors.New("page id is required (--page-id or profile page_id)")
+	}
+	return creds, resolvedVersion, resolvedPageID, nil
+}
+
+func resolveLeadsProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
+	resolvedProfile := strings.TrimSpace(profile)
+	if resolvedProfile == "" {
+		resolvedProfile = runtime.ProfileName()
+	}
+	if resolvedProfile == "" {
+		return nil, "", errors.New("profile is required (--profile or global --profile)")
+	}
+
+	creds, err := leadsLoadProfileCredentials(resolvedProfile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resolvedVersion := strings.TrimSpace(version)
+	if resolvedVersion == "" {
+		resolvedVersion = creds.Profile.GraphVersion
+	}
+	if resolvedVersion == "" {
+		resolvedVersion = config.DefaultGraphVersion
+	}
+	return creds, resolvedVersion, nil
+}
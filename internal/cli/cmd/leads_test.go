@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestLeadsExportWritesFlattenedCSV(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response: `{"data":[{"id":"lead_1","created_time":"2025-10-09T10:00:00+0000","form_id":"form_1","field_data":[
+  {"name":"email","values":["jane@example.com"]},
+  {"name":"full_name","values":["Jane Doe"]}
+]}]}`,
+	}
+	useLeadsDependencies(t, leadsTestCredentials("page"), stub)
+
+	outPath := filepath.Join(t.TempDir(), "leads.csv")
+	output := &bytes.Buffer{}
+	cmd := NewLeadsCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"export", "--form-id", "form_1", "--since", "2025-10-01", "--out", outPath})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute leads export: %v", err)
+	}
+	if !strings.Contains(stub.lastURL, "/v25.0/form_1/leads") || !strings.Contains(stub.lastURL, "filtering=") {
+		t.Fatalf("unexpected request url %q", stub.lastURL)
+	}
+
+	content, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read export file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header and one row, got %q", string(content))
+	}
+	if !strings.HasPrefix(lines[0], "id,created_time,form_id,") || !strings.HasSuffix(lines[0], ",email,full_name") {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], ",jane@example.com,Jane Doe") {
+		t.Fatalf("unexpected row %q", lines[1])
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta leads export")
+	data := envelope["data"].(map[string]any)
+	if data["leads"] != float64(1) {
+		t.Fatalf("unexpected leads count %v", data["leads"])
+	}
+}
+
+func TestLeadsSubscribeRequiresPageToken(t *testing.T) {
+	useLeadsDependencies(t, leadsTestCredentials("user"), nil)
+
+	cmd := NewLeadsCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"subscribe"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "require a page token") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLeadsSubscribePostsLeadgenSubscription(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"success":true}`,
+	}
+	useLeadsDependencies(t, leadsTestCredentials("page"), stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewLeadsCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"subscribe"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute leads subscribe: %v", err)
+	}
+	if stub.lastMethod != http.MethodPost || !strings.HasSuffix(stub.lastURL, "/v25.0/page_1/subscribed_apps") {
+		t.Fatalf("unexpected request %s %s", stub.lastMethod, stub.lastURL)
+	}
+	if !strings.Contains(stub.lastBody, "subscribed_fields=leadgen") {
+		t.Fatalf("unexpected body %q", stub.lastBody)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta leads subscribe")
+}
+
+func leadsTestCredentials(tokenType string) func(string) (*ProfileCredentials, error) {
+	return func(string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{
+			Name:    "prod",
+			Profile: config.Profile{GraphVersion: "v25.0", TokenType: tokenType, PageID: "page_1"},
+			Token:   "page-token",
+		}, nil
+	}
+}
+
+func useLeadsDependencies(t *testing.T, loadFn func(string) (*ProfileCredentials, error), stub *stubHTTPClient) {
+	t.Helper()
+	originalLoad := leadsLoadProfileCredentials
+	originalClient := leadsNewGraphClient
+	t.Cleanup(func() {
+		leadsLoadProfileCredentials = originalLoad
+		leadsNewGraphClient = originalClient
+	})
+
+	leadsLoadProfileCredentials = loadFn
+	leadsNewGraphClient = func() *graph.Client {
+		if stub == nil {
+			t.Fatal("graph client should not be created")
+		}
+		client := graph.NewClient(stub, "https://graph.example.com")
+		client.MaxRetries = 0
+		return client
+	}
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+	"github.com/bilalbayram/metacli/internal/metrics"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const metricsPathEnv = "META_METRICS_PATH"
+
+var (
+	metricsNow                           = time.Now
+	metricsHTTPClient metrics.HTTPClient = nil
+)
+
+type metricsCommandRow struct {
+	Command       string           `json:"command"`
+	Invocations   int64            `json:"invocations"`
+	Errors        int64            `json:"errors"`
+	AvgDurationMS int64            `json:"avg_duration_ms"`
+	MaxDurationMS int64            `json:"max_duration_ms"`
+	ErrorClasses  map[string]int64 `json:"error_classes,omitempty"`
+	LastRunAt     string           `json:"last_run_at"`
+}
+
+type metricsShowResult struct {
+	Path         string              `json:"path"`
+	Enabled      bool                `json:"enabled"`
+	Endpoint     string              `json:"endpoint,omitempty"`
+	Since        string              `json:"since,omitempty"`
+	LastPushedAt string              `json:"last_pushed_at,omitempty"`
+	Commands     []metricsCommandRow `json:"commands"`
+}
+
+// RecordUsage adds the finished command to the local usage metrics when the
+// user has enabled them. It never fails the command: a sample that cannot be
+// written is reported on stderr, or dropped if another process holds the file.
+func RecordUsage(executed *cobra.Command, duration time.Duration, err error) {
+	if executed == nil || isMetricsCommand(executed) {
+		return
+	}
+	path, pathErr := resolveMetricsPath()
+	if pathErr != nil {
+		return
+	}
+	invocation := metrics.Invocation{
+		Command:  executed.CommandPath(),
+		Duration: duration,
+		At:       metricsNow(),
+	}
+	if err != nil {
+		invocation.ErrorClass = commandErrorInfo(err).Class
+		if CancellationReason(executed.Context()) != "" {
+			invocation.ErrorClass = output.ErrorClassCanceled
+		}
+	}
+	if recordErr := metrics.Record(path, invocation); recordErr != nil && !errors.Is(recordErr, filelock.ErrLocked) {
+		fmt.Fprintf(executed.ErrOrStderr(), "warning: usage metrics not recorded: %v\n", recordErr)
+	}
+}
+
+// isMetricsCommand keeps `meta metrics reset` and friends out of the numbers
+// they manage.
+func isMetricsCommand(cmd *cobra.Command) bool {
+	for current := cmd; current != nil; current = current.Parent() {
+		if current.Name() == "metrics" && current.Parent() != nil && !current.Parent().HasParent() {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveMetricsPath() (string, error) {
+	if envPath := strings.TrimSpace(os.Getenv(metricsPathEnv)); envPath != "" {
+		return envPath, nil
+	}
+	return metrics.DefaultPath()
+}
+
+func NewMetricsCommand(runtime Runtime) *cobra.Command {
+	metricsCmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Opt-in local usage metrics",
+		Long: "When enabled, each command's run count, duration and error class are aggregated in\n" +
+			"~/.meta/metrics.json (or $" + metricsPathEnv + "). Arguments, profiles and ids are never recorded,\n" +
+			"and nothing is sent anywhere unless an endpoint is configured and `meta metrics push` runs.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "metrics")
+		},
+	}
+	metricsCmd.AddCommand(newMetricsShowCommand(runtime))
+	metricsCmd.AddCommand(newMetricsEnableCommand(runtime))
+	metricsCmd.AddCommand(newMetricsDisableCommand(runtime))
+	metricsCmd.AddCommand(newMetricsResetCommand(runtime))
+	metricsCmd.AddCommand(newMetricsPushCommand(runtime))
+	return metricsCmd
+}
+
+func newMetricsShowCommand(runtime Runtime) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show the aggregated usage metrics",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			path, err := resolveMetricsPath()
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta metrics show", err)
+			}
+			state, err := metrics.Load(path)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta metrics show", err)
+			}
+			return writeSuccess(cmd, runtime, "meta metrics show", metricsShow(path, state), nil, nil)
+		},
+	}
+}
+
+func newMetricsEnableCommand(runtime Runtime) *cobra.Command {
+	var endpoint string
+	cmd := &cobra.Command{
+		Use:   "enable",
+		Short: "Start recording usage metrics locally",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			endpoint = strings.TrimSpace(endpoint)
+			if endpoint != "" {
+				if err := metrics.ValidateEndpoint(endpoint); err != nil {
+					return writeCommandError(cmd, runtime, "meta metrics enable", err)
+				}
+			}
+			return updateMetrics(cmd, runtime, "meta metrics enable", func(state *metrics.State) error {
+				state.Enabled = true
+				if cmd.Flags().Changed("endpoint") {
+					state.Endpoint = endpoint
+				}
+				return nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&endpoint, "endpoint", "", "URL `meta metrics push` posts aggregates to (empty keeps metrics local)")
+	return cmd
+}
+
+func newMetricsDisableCommand(runtime Runtime) *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable",
+		Short: "Stop recording usage metrics; collected numbers are kept until reset",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return updateMetrics(cmd, runtime, "meta metrics disable", func(state *metrics.State) error {
+				state.Enabled = false
+				return nil
+			})
+		},
+	}
+}
+
+func newMetricsResetCommand(runtime Runtime) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reset",
+		Short: "Clear the collected usage metrics and keep the settings",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return updateMetrics(cmd, runtime, "meta metrics reset", func(state *metrics.State) error {
+				state.Reset()
+				return nil
+			})
+		},
+	}
+}
+
+func newMetricsPushCommand(runtime Runtime) *cobra.Command {
+	return &cobra.Command{
+		Use:   "push",
+		Short: "Send the aggregates to the configured endpoint, then reset them",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			path, err := resolveMetricsPath()
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta metrics push", err)
+			}
+			report, err := metrics.Push(cmd.Context(), metricsHTTPClient, path, metricsNow())
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta metrics push", err)
+			}
+			return writeSuccess(cmd, runtime, "meta metrics push", map[string]any{
+				"status":   "pushed",
+				"since":    report.Since,
+				"until":    report.Until,
+				"commands": len(report.Commands),
+			}, nil, nil)
+		},
+	}
+}
+
+func updateMetrics(cmd *cobra.Command, runtime Runtime, commandName string, fn func(*metrics.State) error) error {
+	path, err := resolveMetricsPath()
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	state, err := metrics.Update(path, fn)
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	return writeSuccess(cmd, runtime, commandName, metricsShow(path, state), nil, nil)
+}
+
+func metricsShow(path string, state metrics.State) metricsShowResult {
+	result := metricsShowResult{
+		Path:         path,
+		Enabled:      state.Enabled,
+		Endpoint:     state.Endpoint,
+		Since:        state.Since,
+		LastPushedAt: state.LastPushedAt,
+		Commands:     []metricsCommandRow{},
+	}
+	for _, name := range state.CommandNames() {
+		stats := state.Commands[name]
+		row := metricsCommandRow{
+			Command:       name,
+			Invocations:   stats.Invocations,
+			Errors:        stats.Errors,
+			MaxDurationMS: stats.MaxDurationMS,
+			ErrorClasses:  stats.ErrorClasses,
+			LastRunAt:     stats.LastRunAt,
+		}
+		if stats.Invocations > 0 {
+			row.AvgDurationMS = stats.TotalDurationMS / stats.Invocations
+		}
+		result.Commands = append(result.Commands, row)
+	}
+	return result
+}
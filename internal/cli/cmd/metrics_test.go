@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func runMetricsCommand(t *testing.T, args ...string) map[string]any {
+	t.Helper()
+	stdout := &bytes.Buffer{}
+	cmd := NewMetricsCommand(testRuntime(""))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(stdout)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs(args)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute metrics %v: %v", args, err)
+	}
+	return decodeEnvelope(t, stdout.Bytes())
+}
+
+func TestRecordUsageIsOptInAndShownByMetricsShow(t *testing.T) {
+	t.Setenv(metricsPathEnv, filepath.Join(t.TempDir(), "metrics.json"))
+
+	root := &cobra.Command{Use: "meta"}
+	api := &cobra.Command{Use: "api"}
+	get := &cobra.Command{Use: "get"}
+	root.AddCommand(api)
+	api.AddCommand(get)
+	metricsCmd := NewMetricsCommand(testRuntime(""))
+	root.AddCommand(metricsCmd)
+
+	RecordUsage(get, 40*time.Millisecond, nil)
+	if commands := runMetricsCommand(t, "show")["data"].(map[string]any)["commands"].([]any); len(commands) != 0 {
+		t.Fatalf("expected nothing recorded before opt-in, got %v", commands)
+	}
+
+	runMetricsCommand(t, "enable")
+	RecordUsage(get, 40*time.Millisecond, nil)
+	RecordUsage(get, 80*time.Millisecond, errors.New("boom"))
+	show, _, _ := metricsCmd.Find([]string{"show"})
+	RecordUsage(show, time.Millisecond, nil)
+
+	envelope := runMetricsCommand(t, "show")
+	assertEnvelopeBasics(t, envelope, "meta metrics show")
+	data := envelope["data"].(map[string]any)
+	commands := data["commands"].([]any)
+	if data["enabled"] != true || len(commands) != 1 {
+		t.Fatalf("unexpected metrics: %v", data)
+	}
+	row := commands[0].(map[string]any)
+	if row["command"] != "meta api get" || row["invocations"] != float64(2) || row["errors"] != float64(1) || row["avg_duration_ms"] != float64(60) {
+		t.Fatalf("unexpected row: %v", row)
+	}
+
+	runMetricsCommand(t, "reset")
+	data = runMetricsCommand(t, "disable")["data"].(map[string]any)
+	if data["enabled"] != false || len(data["commands"].([]any)) != 0 {
+		t.Fatalf("unexpected metrics after reset/disable: %v", data)
+	}
+}
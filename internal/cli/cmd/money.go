@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/spf13/cobra"
+)
+
+type outputCurrencyKey struct{}
+
+// resolveOutputCurrency looks up the ad account currency used to format money
+// columns in table output, at most once per command. Machine formats keep raw
+// minor units, so nothing is fetched for them, and a failed lookup only leaves
+// the amounts unformatted.
+func resolveOutputCurrency(cmd *cobra.Command, runtime Runtime, client *graph.Client, version string, token string, appSecret string, accountID string) string {
+	if currency := outputCurrency(cmd); currency != "" {
+		return currency
+	}
+	if !humanOutputSelected(runtime) || strings.TrimSpace(accountID) == "" || client == nil {
+		return ""
+	}
+	currency, err := marketing.AccountContextCacheFrom(cmd.Context()).Currency(cmd.Context(), client, version, token, appSecret, accountID)
+	if err != nil {
+		return ""
+	}
+	if currency != "" {
+		cmd.SetContext(context.WithValue(cmd.Context(), outputCurrencyKey{}, currency))
+	}
+	return currency
+}
+
+// prefetchOutputCurrency starts the account lookup behind resolveOutputCurrency
+// while the command fetches its rows. It only does anything when the invocation
+// carries an account context cache to hold the result.
+func prefetchOutputCurrency(cmd *cobra.Command, runtime Runtime, client *graph.Client, version string, token string, appSecret string, accountID string) {
+	if outputCurrency(cmd) != "" || !humanOutputSelected(runtime) || strings.TrimSpace(accountID) == "" {
+		return
+	}
+	marketing.AccountContextCacheFrom(cmd.Context()).Prefetch(cmd.Context(), client, version, token, appSecret, accountID)
+}
+
+func outputCurrency(cmd *cobra.Command) string {
+	if cmd == nil || cmd.Context() == nil {
+		return ""
+	}
+	currency, _ := cmd.Context().Value(outputCurrencyKey{}).(string)
+	return currency
+}
+
+func humanOutputSelected(runtime Runtime) bool {
+	if runtime.Quiet != nil && *runtime.Quiet {
+		return false
+	}
+	return selectedOutputFormat(runtime) == "table"
+}
+
+// listedAccountID returns the requested account, or the account of the first
+// listed item when the list was scoped some other way, such as by campaign.
+func listedAccountID(accountID string, items []map[string]any) string {
+	if strings.TrimSpace(accountID) != "" || len(items) == 0 {
+		return accountID
+	}
+	itemAccountID, _ := items[0]["account_id"].(string)
+	return itemAccountID
+}
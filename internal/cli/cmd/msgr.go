@@ -43,14 +43,17 @@ func newMSGRPluginManifest(runtime Runtime) plugin.Manifest {
 		Short:   "Messenger Platform commands",
 		Build: func(pluginRuntime plugin.Runtime) (*cobra.Command, error) {
 			msgrCmd := &cobra.Command{
-				Use:   msgrNamespace,
-				Short: "Messenger Platform commands",
+				Use:     msgrNamespace,
+				Aliases: []string{"messenger"},
+				Short:   "Messenger Platform commands",
 				RunE: func(cmd *cobra.Command, _ []string) error {
 					return requireSubcommand(cmd, msgrNamespace)
 				},
 			}
 			msgrCmd.AddCommand(newMSGRHealthCommand(runtime, pluginRuntime))
+			msgrCmd.AddCommand(newMSGRSendCommand(runtime, pluginRuntime))
 			msgrCmd.AddCommand(newMSGRConversationsCommand(runtime, pluginRuntime))
+			msgrCmd.AddCommand(newMSGRHandoverCommand(runtime, pluginRuntime))
 			msgrCmd.AddCommand(newMSGRAutoReplyCommand(runtime, pluginRuntime))
 			return msgrCmd, nil
 		},
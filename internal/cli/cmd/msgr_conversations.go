@@ -24,6 +24,7 @@ func newMSGRConversationsListCommand(runtime Runtime, pluginRuntime plugin.Runti
 		profile string
 		version string
 		pageID  string
+		psid    string
 		limit   int
 	)
 
@@ -52,6 +53,7 @@ func newMSGRConversationsListCommand(runtime Runtime, pluginRuntime plugin.Runti
 
 			options := msgr.ListConversationsOptions{
 				PageID: resolvedPageID,
+				UserID: psid,
 				Limit:  limit,
 			}
 			if _, _, err := msgr.BuildListConversationsRequest(resolvedVersion, creds.Token, creds.AppSecret, options); err != nil {
@@ -71,6 +73,7 @@ func newMSGRConversationsListCommand(runtime Runtime, pluginRuntime plugin.Runti
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&pageID, "page-id", "", "Facebook Page ID (optional when profile has page_id)")
+	cmd.Flags().StringVar(&psid, "psid", "", "Only list the conversation with this page-scoped user ID")
 	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of conversations to return")
 	return cmd
 }
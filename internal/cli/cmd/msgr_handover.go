@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bilalbayram/metacli/internal/msgr"
+	"github.com/bilalbayram/metacli/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+func newMSGRHandoverCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	handoverCmd := &cobra.Command{
+		Use:   "handover",
+		Short: "Messenger handover protocol thread control commands",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "msgr handover")
+		},
+	}
+	handoverCmd.AddCommand(newMSGRHandoverActionCommand(runtime, pluginRuntime, msgr.HandoverActionPass, "Pass thread control to another app (for example a human agent inbox)"))
+	handoverCmd.AddCommand(newMSGRHandoverActionCommand(runtime, pluginRuntime, msgr.HandoverActionTake, "Take thread control back as the primary receiver"))
+	handoverCmd.AddCommand(newMSGRHandoverActionCommand(runtime, pluginRuntime, msgr.HandoverActionRequest, "Request thread control from the current owner"))
+	handoverCmd.AddCommand(newMSGRHandoverOwnerCommand(runtime, pluginRuntime))
+	return handoverCmd
+}
+
+func newMSGRHandoverActionCommand(runtime Runtime, pluginRuntime plugin.Runtime, action string, short string) *cobra.Command {
+	var (
+		profile     string
+		version     string
+		psid        string
+		targetAppID string
+		metadata    string
+	)
+	commandName := fmt.Sprintf("meta msgr handover %s", action)
+
+	cmd := &cobra.Command{
+		Use:   action,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  msgrPluginID,
+				Namespace: msgrNamespace,
+				Command:   "handover-" + action,
+			}); err != nil {
+				return writeCommandError(cmd, runtime, commandName, err)
+			}
+
+			options := msgr.HandoverOptions{
+				Action:      action,
+				RecipientID: psid,
+				TargetAppID: targetAppID,
+				Metadata:    metadata,
+			}
+			if _, err := msgr.BuildHandoverRequest("", "", "", options); err != nil {
+				return writeCommandError(cmd, runtime, commandName, err)
+			}
+
+			creds, resolvedVersion, err := resolveMSGRProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, commandName, err)
+			}
+
+			service := msgr.New(msgrNewGraphClient())
+			result, err := service.Handover(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, commandName, err)
+			}
+
+			return writeSuccess(cmd, runtime, commandName, result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&psid, "psid", "", "Page-scoped user ID (PSID)")
+	if action == msgr.HandoverActionPass {
+		cmd.Flags().StringVar(&targetAppID, "target-app-id", "", "App id receiving thread control")
+		mustMarkFlagRequired(cmd, "target-app-id")
+	}
+	cmd.Flags().StringVar(&metadata, "metadata", "", "Optional metadata string delivered with the handover event")
+	mustMarkFlagRequired(cmd, "psid")
+	return cmd
+}
+
+func newMSGRHandoverOwnerCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile string
+		version string
+		psid    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "owner",
+		Short: "Show which app currently owns a conversation thread",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  msgrPluginID,
+				Namespace: msgrNamespace,
+				Command:   "handover-owner",
+			}); err != nil {
+				return writeCommandError(cmd, runtime, "meta msgr handover owner", err)
+			}
+
+			creds, resolvedVersion, err := resolveMSGRProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta msgr handover owner", err)
+			}
+
+			service := msgr.New(msgrNewGraphClient())
+			result, err := service.ThreadOwner(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, psid)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta msgr handover owner", err)
+			}
+
+			return writeSuccess(cmd, runtime, "meta msgr handover owner", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&psid, "psid", "", "Page-scoped user ID (PSID)")
+	mustMarkFlagRequired(cmd, "psid")
+	return cmd
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestMSGRSendPostsTaggedMessage(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"recipient_id":"psid_123","message_id":"m_1"}`,
+	}
+	useMSGRStubDependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewMSGRCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"send", "--psid", "psid_123", "--text", "An agent will follow up", "--messaging-type", "MESSAGE_TAG", "--tag", "HUMAN_AGENT"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute msgr send: %v", err)
+	}
+	if stub.lastMethod != http.MethodPost || !strings.HasSuffix(stub.lastURL, "/v25.0/me/messages") {
+		t.Fatalf("unexpected request %s %s", stub.lastMethod, stub.lastURL)
+	}
+	form, err := url.ParseQuery(stub.lastBody)
+	if err != nil {
+		t.Fatalf("parse form body: %v", err)
+	}
+	if form.Get("messaging_type") != "MESSAGE_TAG" || form.Get("tag") != "HUMAN_AGENT" {
+		t.Fatalf("unexpected form %v", form)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta msgr send")
+	if data := envelope["data"].(map[string]any); data["message_id"] != "m_1" {
+		t.Fatalf("unexpected data %#v", data)
+	}
+}
+
+func TestMSGRSendRejectsTagWithoutMessageTagType(t *testing.T) {
+	useMSGRStubDependencies(t, nil)
+
+	cmd := NewMSGRCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"send", "--psid", "psid_123", "--text", "hi", "--tag", "HUMAN_AGENT"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "requires messaging type MESSAGE_TAG") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMSGRHandoverPassPostsThreadControl(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"success":true}`,
+	}
+	useMSGRStubDependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewMSGRCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"handover", "pass", "--psid", "psid_123", "--target-app-id", "263902037430900"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute msgr handover pass: %v", err)
+	}
+	if !strings.HasSuffix(stub.lastURL, "/v25.0/me/pass_thread_control") {
+		t.Fatalf("unexpected request url %q", stub.lastURL)
+	}
+	form, err := url.ParseQuery(stub.lastBody)
+	if err != nil {
+		t.Fatalf("parse form body: %v", err)
+	}
+	if form.Get("target_app_id") != "263902037430900" || form.Get("recipient") != `{"id":"psid_123"}` {
+		t.Fatalf("unexpected form %v", form)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta msgr handover pass")
+}
+
+func useMSGRStubDependencies(t *testing.T, stub *stubHTTPClient) {
+	t.Helper()
+	useMSGRDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{GraphVersion: "v25.0", PageID: "page_123"},
+				Token:   "page-token",
+			}, nil
+		},
+		func() *graph.Client {
+			if stub == nil {
+				t.Fatal("graph client should not be created")
+			}
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"github.com/bilalbayram/metacli/internal/msgr"
+	"github.com/bilalbayram/metacli/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+func newMSGRSendCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile       string
+		version       string
+		psid          string
+		text          string
+		messagingType string
+		tag           string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "send",
+		Short: "Send a Messenger text message to a page-scoped user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  msgrPluginID,
+				Namespace: msgrNamespace,
+				Command:   "send",
+			}); err != nil {
+				return writeCommandError(cmd, runtime, "meta msgr send", err)
+			}
+
+			options := msgr.SendOptions{
+				RecipientID:   psid,
+				Text:          text,
+				MessagingType: messagingType,
+				Tag:           tag,
+			}
+			if _, err := msgr.BuildSendRequest("", "", "", options); err != nil {
+				return writeCommandError(cmd, runtime, "meta msgr send", err)
+			}
+
+			creds, resolvedVersion, err := resolveMSGRProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta msgr send", err)
+			}
+
+			service := msgr.New(msgrNewGraphClient())
+			result, err := service.Send(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta msgr send", err)
+			}
+
+			return writeSuccess(cmd, runtime, "meta msgr send", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&psid, "psid", "", "Page-scoped user ID (PSID)")
+	cmd.Flags().StringVar(&text, "text", "", "Message text to send")
+	cmd.Flags().StringVar(&messagingType, "messaging-type", msgr.MessagingTypeResponse, "Messaging type: RESPONSE|UPDATE|MESSAGE_TAG")
+	cmd.Flags().StringVar(&tag, "tag", "", "Message tag for MESSAGE_TAG sends: CONFIRMED_EVENT_UPDATE|POST_PURCHASE_UPDATE|ACCOUNT_UPDATE|HUMAN_AGENT")
+	mustMarkFlagRequired(cmd, "psid")
+	mustMarkFlagRequired(cmd, "text")
+	return cmd
+}
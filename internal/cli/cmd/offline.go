@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/bilalbayram/metacli/internal/readcache"
+	"github.com/bilalbayram/metacli/internal/transport"
+	"github.com/spf13/cobra"
+)
+
+var offlineNow = time.Now
+
+// ConfigureReadCache installs the cache every successful Graph read is written
+// through to, so a later --offline invocation can serve it. The cache stays
+// off when META_READ_CACHE_DIR is "off" or there is no home directory to keep
+// it in; commands run as usual without it.
+func ConfigureReadCache() {
+	dir, err := readcache.DefaultDir()
+	if err != nil || dir == "" {
+		graph.SetReadCache(nil)
+		return
+	}
+	graph.SetReadCache(readcache.New(dir))
+}
+
+// EnableOffline puts the command in offline mode: Graph reads are served from
+// the read cache and anything else that needs the network fails with
+// offline_unavailable. Callers enable dry-run as well, so mutations are
+// planned before they would reach the network.
+func EnableOffline(cmd *cobra.Command) {
+	cmd.SetContext(graph.WithOffline(cmd.Context(), &graph.Offline{Now: offlineNow}))
+}
+
+// offlineInfo lists the cached reads behind an envelope of an offline
+// invocation; it is nil online.
+func offlineInfo(cmd *cobra.Command) *output.OfflineInfo {
+	offline := graph.OfflineFromContext(cmd.Context())
+	if offline == nil {
+		return nil
+	}
+	info := &output.OfflineInfo{Reads: []output.OfflineRead{}}
+	for _, read := range offline.Reads() {
+		info.Reads = append(info.Reads, output.OfflineRead{
+			Path:       read.Path,
+			Version:    read.Version,
+			CachedAt:   read.CachedAt,
+			AgeSeconds: read.AgeSeconds,
+		})
+		if read.AgeSeconds > info.MaxAgeSeconds {
+			info.MaxAgeSeconds = read.AgeSeconds
+		}
+	}
+	return info
+}
+
+// markOffline reports a request outside the Graph client that --offline
+// refused, such as a schema or update download, like an uncached Graph read.
+func markOffline(errorInfo *output.ErrorInfo, err error) {
+	if !errors.Is(err, transport.ErrOffline) {
+		return
+	}
+	errorInfo.Type = graph.ErrorTypeOffline
+	errorInfo.Class = output.ErrorClassOffline
+	errorInfo.Retryable = false
+	errorInfo.Remediation = &output.Remediation{
+		Category: graph.RemediationCategoryOffline,
+		Summary:  "This request needs the network, which --offline disables.",
+		Actions: []string{
+			"Rerun the command without --offline.",
+		},
+	}
+}
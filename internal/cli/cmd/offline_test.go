@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/readcache"
+	"github.com/bilalbayram/metacli/internal/transport"
+	"github.com/spf13/cobra"
+)
+
+func TestOfflineServesCachedReadsWithStaleness(t *testing.T) {
+	graph.SetReadCache(readcache.New(filepath.Join(t.TempDir(), "graph")))
+	t.Cleanup(func() { graph.SetReadCache(nil) })
+	stub := &stubHTTPClient{t: t, statusCode: http.StatusOK, response: `{"id":"120","name":"Launch"}`}
+	client := graph.NewClient(stub, "https://graph.example.test")
+	read := graph.Request{Method: http.MethodGet, Path: "120", Version: "v25.0", Query: map[string]string{"fields": "name"}, AccessToken: "token"}
+	if _, err := client.Do(context.Background(), read); err != nil {
+		t.Fatalf("online read: %v", err)
+	}
+
+	originalNow := offlineNow
+	offlineNow = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	t.Cleanup(func() { offlineNow = originalNow })
+
+	stdout := &bytes.Buffer{}
+	cmd := &cobra.Command{
+		Use: "get",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			response, err := client.Do(cmd.Context(), read)
+			if err != nil {
+				return writeCommandError(cmd, testRuntime(""), "meta test get", err)
+			}
+			return writeSuccess(cmd, testRuntime(""), "meta test get", response.Body, nil, nil)
+		},
+	}
+	cmd.SetContext(context.Background())
+	EnableOffline(cmd)
+	cmd.SetOut(stdout)
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("offline read: %v", err)
+	}
+	if stub.calls != 1 {
+		t.Fatalf("offline read reached the network: %d call(s)", stub.calls)
+	}
+
+	envelope := decodeEnvelope(t, stdout.Bytes())
+	if envelope["data"].(map[string]any)["name"] != "Launch" {
+		t.Fatalf("unexpected data %v", envelope["data"])
+	}
+	offline := envelope["offline"].(map[string]any)
+	reads := offline["reads"].([]any)
+	if len(reads) != 1 || reads[0].(map[string]any)["path"] != "120" {
+		t.Fatalf("unexpected offline reads %v", reads)
+	}
+	if age := offline["max_age_seconds"].(float64); age < 7200 || age > 7260 {
+		t.Fatalf("expected a read about two hours old, got %v", age)
+	}
+}
+
+func TestOfflineFailuresUseTheOfflineClass(t *testing.T) {
+	for _, err := range []error{
+		fmt.Errorf("download schema manifest: %w", transport.ErrOffline),
+		fmt.Errorf("campaign read failed: %w", &graph.APIError{Type: graph.ErrorTypeOffline, Message: "GET 120 has no cached response"}),
+	} {
+		stderr := &bytes.Buffer{}
+		cmd := &cobra.Command{Use: "sync"}
+		cmd.SetContext(context.Background())
+		EnableOffline(cmd)
+		cmd.SetErr(stderr)
+		_ = writeCommandError(cmd, testRuntime(""), "meta schema sync", err)
+
+		envelope := decodeEnvelope(t, stderr.Bytes())
+		errorBody := envelope["error"].(map[string]any)
+		if errorBody["type"] != graph.ErrorTypeOffline || errorBody["class"] != "offline" || errorBody["retryable"] != false {
+			t.Fatalf("unexpected offline error %v", errorBody)
+		}
+		if _, ok := envelope["offline"]; !ok {
+			t.Fatalf("offline error envelope should carry the offline block: %s", stderr.String())
+		}
+	}
+}
+
+func TestConfigureReadCacheStaysOffWithoutADirectory(t *testing.T) {
+	for name, env := range map[string]map[string]string{
+		"switched off": {"HOME": t.TempDir(), readcache.DirEnv: "off"},
+		"no home":      {"HOME": "", readcache.DirEnv: ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for key, value := range env {
+				t.Setenv(key, value)
+			}
+			graph.SetReadCache(readcache.New(t.TempDir()))
+			t.Cleanup(func() { graph.SetReadCache(nil) })
+
+			ConfigureReadCache()
+
+			client := graph.NewClient(&stubHTTPClient{t: t}, "https://graph.example.test")
+			_, err := client.Do(graph.WithOffline(context.Background(), &graph.Offline{}), graph.Request{Method: http.MethodGet, Path: "120", Version: "v25.0"})
+			if err == nil || !strings.Contains(err.Error(), "the read cache is disabled") {
+				t.Fatalf("expected the read cache to be off, got %v", err)
+			}
+		})
+	}
+}
@@ -2,17 +2,24 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/bilalbayram/metacli/internal/changelog"
 	"github.com/bilalbayram/metacli/internal/config"
 	"github.com/bilalbayram/metacli/internal/graph"
 	"github.com/bilalbayram/metacli/internal/lint"
 	"github.com/bilalbayram/metacli/internal/ops"
+	"github.com/bilalbayram/metacli/internal/transport"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +28,13 @@ var (
 	opsNewGraphClient         = func() *graph.Client {
 		return graph.NewClient(nil, "")
 	}
+	opsNewCustomCheckExecutor = func() ops.CustomCheckExecutor {
+		return ops.NewCustomCheckExecutor(nil)
+	}
+	opsMetricsListen       = net.Listen
+	opsNewChangelogFetcher = func(url string) *changelog.FeedFetcher {
+		return changelog.NewFeedFetcher(&http.Client{Timeout: 30 * time.Second, Transport: transport.RefuseOffline(nil)}, url)
+	}
 )
 
 func NewOpsCommand(runtime Runtime) *cobra.Command {
@@ -33,11 +47,14 @@ func NewOpsCommand(runtime Runtime) *cobra.Command {
 	opsCmd.AddCommand(newOpsInitCommand(runtime))
 	opsCmd.AddCommand(newOpsRunCommand(runtime))
 	opsCmd.AddCommand(newOpsCleanupCommand(runtime))
+	opsCmd.AddCommand(newOpsReportCommand(runtime))
+	opsCmd.AddCommand(newOpsMetricsCommand(runtime))
 	return opsCmd
 }
 
 func newOpsInitCommand(runtime Runtime) *cobra.Command {
 	var statePath string
+	var changelogFlags opsChangelogFeedFlags
 
 	cmd := &cobra.Command{
 		Use:   "init",
@@ -52,7 +69,12 @@ func newOpsInitCommand(runtime Runtime) *cobra.Command {
 				return writeOpsError(cmd, runtime, ops.CommandInit, ops.WrapExit(ops.ExitCodeState, err))
 			}
 
-			result, err := ops.Initialize(resolvedPath)
+			feed, err := changelogFlags.resolve(cmd.Context(), resolvedPath)
+			if err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandInit, err)
+			}
+
+			result, err := ops.InitializeWithOptions(resolvedPath, ops.InitOptions{ChangelogFeed: feed})
 			if err != nil {
 				return writeOpsError(cmd, runtime, ops.CommandInit, err)
 			}
@@ -65,6 +87,7 @@ func newOpsInitCommand(runtime Runtime) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&statePath, "state-path", "", "Path to baseline state JSON file")
+	changelogFlags.register(cmd)
 	return cmd
 }
 
@@ -75,6 +98,15 @@ func newOpsRunCommand(runtime Runtime) *cobra.Command {
 	var preflightOptionalPolicy string
 	var runtimeResponsePath string
 	var lintRequestPath string
+	var checksPath string
+	var historyDir string
+	var exitPolicyPath string
+	var failOn string
+	var checkPolicies []string
+	var reportFormat string
+	var reportFile string
+	var changelogFlags opsChangelogFeedFlags
+	var selector string
 
 	cmd := &cobra.Command{
 		Use:   "run",
@@ -83,6 +115,9 @@ func newOpsRunCommand(runtime Runtime) *cobra.Command {
 			if err := ensureOpsOutput(runtime, ops.CommandRun); err != nil {
 				return writeOpsError(cmd, runtime, ops.CommandRun, ops.WrapExit(ops.ExitCodeInput, err))
 			}
+			if strings.TrimSpace(selector) != "" {
+				return runProfileFleet(cmd, runtime, ops.CommandRun, selector)
+			}
 
 			resolvedPath, err := resolveStatePath(statePath)
 			if err != nil {
@@ -94,8 +129,22 @@ func newOpsRunCommand(runtime Runtime) *cobra.Command {
 			}
 			normalizedPreflightOptionalPolicy := ops.NormalizeOptionalModulePolicy(preflightOptionalPolicy)
 
+			resolvedHistoryDir, err := resolveReportHistoryDir(historyDir, resolvedPath)
+			if err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandRun, ops.WrapExit(ops.ExitCodeState, err))
+			}
+			exitPolicy, err := resolveExitPolicy(exitPolicyPath, failOn, checkPolicies)
+			if err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandRun, ops.WrapExit(ops.ExitCodeInput, err))
+			}
+			if err := ops.ValidateReportFormat(reportFormat, reportFile); err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandRun, ops.WrapExit(ops.ExitCodeInput, err))
+			}
+
 			runOptions := ops.RunOptions{
 				OptionalModulePolicy: normalizedPreflightOptionalPolicy,
+				HistoryDir:           resolvedHistoryDir,
+				ExitPolicy:           &exitPolicy,
 			}
 			preflightSnapshot := buildPermissionPreflightSnapshot(runtime.ProfileName(), preflightConfigPath, normalizedPreflightOptionalPolicy)
 			runOptions.PermissionPreflight = &preflightSnapshot
@@ -122,26 +171,48 @@ func newOpsRunCommand(runtime Runtime) *cobra.Command {
 				runOptions.LintRequestSpec = spec
 				runOptions.LintRequestSpecFile = lintRequestPath
 			}
+			if strings.TrimSpace(checksPath) != "" {
+				checksConfig, err := ops.LoadCustomChecksConfig(checksPath)
+				if err != nil {
+					return writeOpsError(cmd, runtime, ops.CommandRun, ops.WrapExit(ops.ExitCodeInput, err))
+				}
+				runOptions.CustomChecks = checksConfig.Checks
+				runOptions.CustomCheckExecutor = opsNewCustomCheckExecutor()
+			}
+			feed, err := changelogFlags.resolve(cmd.Context(), resolvedPath)
+			if err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandRun, err)
+			}
+			runOptions.ChangelogFeed = feed
 
 			result, err := ops.RunWithOptions(resolvedPath, runOptions)
 			if err != nil {
 				return writeOpsError(cmd, runtime, ops.CommandRun, err)
 			}
+			if strings.EqualFold(strings.TrimSpace(reportFormat), ops.ReportFormatJUnit) {
+				if err := ops.WriteJUnitFile(reportFile, ops.JUnitFromReport(result.Report)); err != nil {
+					return writeOpsError(cmd, runtime, ops.CommandRun, ops.WrapExit(ops.ExitCodeRuntime, err))
+				}
+			}
 
 			envelope := ops.NewSuccessEnvelope(ops.CommandRun, result)
 			if code := ops.RunExitCode(result.Report); code != ops.ExitCodeSuccess {
 				envelope.Success = false
 				envelope.ExitCode = code
-				switch ops.RunOutcomeForReport(result.Report) {
-				case ops.RunOutcomeWarning:
+				warnings, blocking := result.Report.Summary.Warnings, result.Report.Summary.Blocking
+				if result.Report.ExitPolicy != nil {
+					warnings, blocking = result.Report.ExitPolicy.Warnings, result.Report.ExitPolicy.Blocking
+				}
+				switch code {
+				case ops.ExitCodeWarning:
 					envelope.Error = &ops.ErrorInfo{
 						Type:    "warning_findings",
-						Message: fmt.Sprintf("ops run reported %d warning finding(s)", result.Report.Summary.Warnings),
+						Message: fmt.Sprintf("ops run reported %d warning finding(s)", warnings),
 					}
-				case ops.RunOutcomeBlocking:
+				case ops.ExitCodePolicy:
 					envelope.Error = &ops.ErrorInfo{
 						Type:    "blocking_findings",
-						Message: fmt.Sprintf("ops run reported %d blocking finding(s)", result.Report.Summary.Blocking),
+						Message: fmt.Sprintf("ops run reported %d blocking finding(s)", blocking),
 					}
 				default:
 					envelope.Error = &ops.ErrorInfo{
@@ -165,6 +236,81 @@ func newOpsRunCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&preflightOptionalPolicy, "preflight-optional-policy", ops.OptionalModulePolicyStrict, "Policy for optional preflight modules: strict|skip")
 	cmd.Flags().StringVar(&runtimeResponsePath, "runtime-response-file", "", "Path to runtime response shape snapshot JSON file")
 	cmd.Flags().StringVar(&lintRequestPath, "lint-request-file", "", "Path to lint request spec JSON file linked to runtime drift check")
+	cmd.Flags().StringVar(&checksPath, "checks-file", "", "Path to custom checks config JSON file merged into the report")
+	cmd.Flags().StringVar(&historyDir, "history-dir", "", "Directory where each run report is persisted (default: reports/ next to the state file)")
+	addExitPolicyFlags(cmd, &exitPolicyPath, &failOn, &checkPolicies)
+	addReportFormatFlags(cmd, &reportFormat, &reportFile)
+	changelogFlags.register(cmd)
+	addProfilesSelectorFlag(cmd, &selector)
+	return cmd
+}
+
+func newOpsReportCommand(runtime Runtime) *cobra.Command {
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Operations report history commands",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "ops report")
+		},
+	}
+	reportCmd.AddCommand(newOpsReportDiffCommand(runtime))
+	return reportCmd
+}
+
+func newOpsReportDiffCommand(runtime Runtime) *cobra.Command {
+	var statePath string
+	var historyDir string
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the latest two recorded ops run reports",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := ensureOpsOutput(runtime, ops.CommandReportDiff); err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandReportDiff, ops.WrapExit(ops.ExitCodeInput, err))
+			}
+
+			resolvedPath, err := resolveStatePath(statePath)
+			if err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandReportDiff, ops.WrapExit(ops.ExitCodeState, err))
+			}
+			resolvedHistoryDir, err := resolveReportHistoryDir(historyDir, resolvedPath)
+			if err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandReportDiff, ops.WrapExit(ops.ExitCodeState, err))
+			}
+
+			result, err := ops.DiffLatestReports(resolvedHistoryDir)
+			if err != nil {
+				code := ops.ExitCodeState
+				if errors.Is(err, ops.ErrReportHistoryInsufficient) {
+					code = ops.ExitCodeInput
+				}
+				return writeOpsError(cmd, runtime, ops.CommandReportDiff, ops.WrapExit(code, err))
+			}
+
+			envelope := ops.NewSuccessEnvelope(ops.CommandReportDiff, result)
+			if code := ops.ReportDiffExitCode(result); code != ops.ExitCodeSuccess {
+				envelope.Success = false
+				envelope.ExitCode = code
+				envelope.Error = &ops.ErrorInfo{
+					Type: "new_findings",
+					Message: fmt.Sprintf(
+						"ops report diff found %d new blocking and %d new warning finding(s)",
+						result.Regression.NewBlocking,
+						result.Regression.NewWarnings,
+					),
+				}
+			}
+			if err := ops.WriteEnvelope(cmd.OutOrStdout(), opsEnvelopeOutputFormat(runtime), envelope); err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandReportDiff, ops.WrapExit(ops.ExitCodeUnknown, fmt.Errorf("write success envelope: %w", err)))
+			}
+			if !envelope.Success {
+				return ops.WrapExit(envelope.ExitCode, errors.New(envelope.Error.Message))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&statePath, "state-path", "", "Path to baseline state JSON file used to locate the default history directory")
+	cmd.Flags().StringVar(&historyDir, "history-dir", "", "Directory containing persisted run reports")
 	return cmd
 }
 
@@ -262,6 +408,213 @@ func resolveStatePath(path string) (string, error) {
 	return ops.DefaultStatePath()
 }
 
+func newOpsMetricsCommand(runtime Runtime) *cobra.Command {
+	metricsCmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Operations metrics exposition commands",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "ops metrics")
+		},
+	}
+	metricsCmd.AddCommand(newOpsMetricsServeCommand(runtime))
+	return metricsCmd
+}
+
+func newOpsMetricsServeCommand(runtime Runtime) *cobra.Command {
+	var listenAddr string
+	var statePath string
+	var historyDir string
+	var rateTelemetryPath string
+	var configPath string
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Expose ops check, rate-limit, and token TTL metrics in Prometheus text format",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := ensureOpsOutput(runtime, ops.CommandMetricsServe); err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandMetricsServe, ops.WrapExit(ops.ExitCodeInput, err))
+			}
+			if strings.TrimSpace(listenAddr) == "" {
+				return writeOpsError(cmd, runtime, ops.CommandMetricsServe, ops.WrapExit(ops.ExitCodeInput, errors.New("--listen is required")))
+			}
+
+			resolvedPath, err := resolveStatePath(statePath)
+			if err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandMetricsServe, ops.WrapExit(ops.ExitCodeState, err))
+			}
+			resolvedHistoryDir, err := resolveReportHistoryDir(historyDir, resolvedPath)
+			if err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandMetricsServe, ops.WrapExit(ops.ExitCodeState, err))
+			}
+			resolvedConfigPath := strings.TrimSpace(configPath)
+			explicitConfigPath := resolvedConfigPath != ""
+			if !explicitConfigPath {
+				resolvedConfigPath, err = config.DefaultPath()
+				if err != nil {
+					return writeOpsError(cmd, runtime, ops.CommandMetricsServe, ops.WrapExit(ops.ExitCodeState, err))
+				}
+			}
+
+			collect := func() ([]ops.MetricFamily, error) {
+				sources := ops.MetricsSources{
+					StatePath:  resolvedPath,
+					HistoryDir: resolvedHistoryDir,
+				}
+				if strings.TrimSpace(rateTelemetryPath) != "" {
+					snapshot, err := loadRateLimitTelemetrySnapshot(rateTelemetryPath)
+					if err != nil {
+						return nil, err
+					}
+					sources.RateLimitTelemetry = &snapshot
+				}
+				cfg, err := config.Load(resolvedConfigPath)
+				switch {
+				case err == nil:
+					sources.Profiles = cfg.Profiles
+				case !explicitConfigPath && errors.Is(err, os.ErrNotExist):
+				default:
+					return nil, err
+				}
+				return ops.CollectMetrics(sources)
+			}
+			if _, err := collect(); err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandMetricsServe, ops.WrapExit(ops.ExitCodeInput, err))
+			}
+
+			listener, err := opsMetricsListen("tcp", strings.TrimSpace(listenAddr))
+			if err != nil {
+				return writeOpsError(cmd, runtime, ops.CommandMetricsServe, ops.WrapExit(ops.ExitCodeRuntime, fmt.Errorf("listen on %s: %w", listenAddr, err)))
+			}
+
+			mux := http.NewServeMux()
+			mux.Handle(ops.MetricsPath, ops.NewMetricsHandler(collect))
+			server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+			envelope := ops.NewSuccessEnvelope(ops.CommandMetricsServe, map[string]any{
+				"listen_addr":  listener.Addr().String(),
+				"metrics_path": ops.MetricsPath,
+				"state_path":   resolvedPath,
+				"history_dir":  resolvedHistoryDir,
+			})
+			if err := ops.WriteEnvelope(cmd.OutOrStdout(), opsEnvelopeOutputFormat(runtime), envelope); err != nil {
+				_ = listener.Close()
+				return writeOpsError(cmd, runtime, ops.CommandMetricsServe, ops.WrapExit(ops.ExitCodeUnknown, fmt.Errorf("write success envelope: %w", err)))
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			serveErr := make(chan error, 1)
+			go func() {
+				serveErr <- server.Serve(listener)
+			}()
+			select {
+			case <-ctx.Done():
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := server.Shutdown(shutdownCtx); err != nil {
+					return ops.WrapExit(ops.ExitCodeRuntime, fmt.Errorf("shutdown metrics server: %w", err))
+				}
+				return nil
+			case err := <-serveErr:
+				if errors.Is(err, http.ErrServerClosed) {
+					return nil
+				}
+				return ops.WrapExit(ops.ExitCodeRuntime, fmt.Errorf("serve metrics: %w", err))
+			}
+		},
+	}
+	cmd.Flags().StringVar(&listenAddr, "listen", ops.DefaultMetricsListenAddr, "Address the metrics HTTP server listens on")
+	cmd.Flags().StringVar(&statePath, "state-path", "", "Path to baseline state JSON file")
+	cmd.Flags().StringVar(&historyDir, "history-dir", "", "Directory containing persisted run reports")
+	cmd.Flags().StringVar(&rateTelemetryPath, "rate-telemetry-file", "", "Path to rate-limit telemetry JSON snapshot file re-read on each scrape")
+	cmd.Flags().StringVar(&configPath, "config-path", "", "Path to auth config file used for token TTL metrics")
+	return cmd
+}
+
+func resolveReportHistoryDir(historyDir string, statePath string) (string, error) {
+	historyDir = strings.TrimSpace(historyDir)
+	if historyDir != "" {
+		return historyDir, nil
+	}
+	return ops.DefaultReportHistoryDir(statePath)
+}
+
+type opsChangelogFeedFlags struct {
+	refresh   bool
+	url       string
+	cachePath string
+	cacheTTL  time.Duration
+}
+
+func (f *opsChangelogFeedFlags) register(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.refresh, "refresh-changelog", false, "Evaluate changelog OCC state against the live upstream changelog feed")
+	cmd.Flags().StringVar(&f.url, "changelog-url", changelog.DefaultFeedURL, "Changelog feed URL used with --refresh-changelog (JSON feed or versions page)")
+	cmd.Flags().StringVar(&f.cachePath, "changelog-cache-path", "", "Path to cached changelog feed JSON (default: changelog-feed.json next to the state file)")
+	cmd.Flags().DurationVar(&f.cacheTTL, "changelog-cache-ttl", changelog.DefaultFeedCacheTTL, "Reuse a cached changelog feed younger than this duration (0 always fetches)")
+}
+
+func (f *opsChangelogFeedFlags) resolve(ctx context.Context, statePath string) (*changelog.FeedResolution, error) {
+	if !f.refresh {
+		return nil, nil
+	}
+	if strings.TrimSpace(f.url) == "" {
+		return nil, ops.WrapExit(ops.ExitCodeInput, changelog.ErrFeedURLRequired)
+	}
+	if f.cacheTTL < 0 {
+		return nil, ops.WrapExit(ops.ExitCodeInput, fmt.Errorf("--changelog-cache-ttl must be >= 0, got %s", f.cacheTTL))
+	}
+	cachePath := strings.TrimSpace(f.cachePath)
+	if cachePath == "" {
+		cachePath = changelog.DefaultFeedCachePath(filepath.Dir(statePath))
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	resolution, err := opsNewChangelogFetcher(f.url).Resolve(ctx, changelog.FeedResolveOptions{
+		CachePath: cachePath,
+		TTL:       f.cacheTTL,
+		Now:       time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, ops.WrapExit(ops.ExitCodeRuntime, err)
+	}
+	return &resolution, nil
+}
+
+func addExitPolicyFlags(cmd *cobra.Command, policyPath *string, failOn *string, checkPolicies *[]string) {
+	cmd.Flags().StringVar(policyPath, "exit-policy-file", "", "Path to exit policy JSON file mapping findings to exit codes")
+	cmd.Flags().StringVar(failOn, "fail-on", "", "Lowest finding severity that fails the run: warning|blocking (default: warning)")
+	cmd.Flags().StringArrayVar(checkPolicies, "check-policy", nil, "Per-check severity override name=blocking|warning|ignore (repeatable)")
+}
+
+func addReportFormatFlags(cmd *cobra.Command, format *string, path *string) {
+	cmd.Flags().StringVar(format, "report-format", "", "Additional report file written alongside the JSON envelope: junit")
+	cmd.Flags().StringVar(path, "report-file", "", "Path of the report file written with --report-format")
+}
+
+func resolveExitPolicy(policyPath string, failOn string, checkPolicies []string) (ops.ExitPolicy, error) {
+	policy := ops.DefaultExitPolicy()
+	if strings.TrimSpace(policyPath) != "" {
+		loaded, err := ops.LoadExitPolicy(policyPath)
+		if err != nil {
+			return ops.ExitPolicy{}, err
+		}
+		policy = loaded
+	}
+	overrides, err := ops.ParseExitPolicyOverrides(checkPolicies)
+	if err != nil {
+		return ops.ExitPolicy{}, err
+	}
+	policy = policy.WithFlags(failOn, overrides)
+	if err := policy.Validate(); err != nil {
+		return ops.ExitPolicy{}, err
+	}
+	return policy, nil
+}
+
 func resolveOpsCleanupProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
 	resolvedProfile := strings.TrimSpace(profile)
 	if resolvedProfile == "" {
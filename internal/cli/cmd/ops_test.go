@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -15,6 +17,8 @@ import (
 	"github.com/bilalbayram/metacli/internal/config"
 	"github.com/bilalbayram/metacli/internal/graph"
 	"github.com/bilalbayram/metacli/internal/ops"
+	"github.com/bilalbayram/metacli/internal/smoke"
+	"github.com/bilalbayram/metacli/internal/testutil"
 )
 
 type envelopeFixture struct {
@@ -585,6 +589,213 @@ func TestOpsRunCommandReturnsWarningExitOnRateLimitWarningThreshold(t *testing.T
 	}
 }
 
+func TestOpsRunCommandFailOnBlockingDowngradesWarningExit(t *testing.T) {
+	t.Parallel()
+
+	statePath := filepath.Join(t.TempDir(), "baseline-state.json")
+	if _, err := ops.Initialize(statePath); err != nil {
+		t.Fatalf("initialize baseline state: %v", err)
+	}
+
+	telemetryPath := filepath.Join(t.TempDir(), "telemetry-warning.json")
+	telemetry := "{\n  \"app_call_count\": 65,\n  \"app_total_cputime\": 20,\n  \"app_total_time\": 10,\n  \"page_call_count\": 10,\n  \"page_total_cputime\": 5,\n  \"page_total_time\": 3,\n  \"ad_account_util_pct\": 2\n}\n"
+	if err := os.WriteFile(telemetryPath, []byte(telemetry), 0o600); err != nil {
+		t.Fatalf("write telemetry fixture: %v", err)
+	}
+
+	stdout, stderr, err := executeOpsCommand(Runtime{}, "run", "--preflight-optional-policy", "skip", "--state-path", statePath, "--rate-telemetry-file", telemetryPath, "--fail-on", "blocking")
+	if err != nil {
+		t.Fatalf("expected fail-on blocking run to succeed, got %v", err)
+	}
+	if stderr != "" {
+		t.Fatalf("expected empty stderr, got %q", stderr)
+	}
+
+	envelope := decodeOpsEnvelope(t, []byte(stdout))
+	if !envelope.Success || envelope.ExitCode != ops.ExitCodeSuccess {
+		t.Fatalf("unexpected envelope status: success=%v exit_code=%d", envelope.Success, envelope.ExitCode)
+	}
+	var data ops.RunResult
+	if err := json.Unmarshal(envelope.Data, &data); err != nil {
+		t.Fatalf("decode run data: %v", err)
+	}
+	if data.Report.Outcome != ops.RunOutcomeWarning {
+		t.Fatalf("expected raw outcome to stay warning, got %s", data.Report.Outcome)
+	}
+	if data.Report.ExitPolicy == nil {
+		t.Fatal("expected effective exit policy in report")
+	}
+	if data.Report.ExitPolicy.FailOn != ops.FailOnBlocking || data.Report.ExitPolicy.Warnings != 1 || data.Report.ExitPolicy.ExitCode != ops.ExitCodeSuccess {
+		t.Fatalf("unexpected exit policy echo: %+v", data.Report.ExitPolicy)
+	}
+}
+
+func TestOpsRunCommandWritesJUnitReportFile(t *testing.T) {
+	t.Parallel()
+
+	statePath := filepath.Join(t.TempDir(), "baseline-state.json")
+	if _, err := ops.Initialize(statePath); err != nil {
+		t.Fatalf("initialize baseline state: %v", err)
+	}
+
+	telemetryPath := filepath.Join(t.TempDir(), "telemetry-warning.json")
+	telemetry := "{\n  \"app_call_count\": 65,\n  \"app_total_cputime\": 20,\n  \"app_total_time\": 10,\n  \"page_call_count\": 10,\n  \"page_total_cputime\": 5,\n  \"page_total_time\": 3,\n  \"ad_account_util_pct\": 2\n}\n"
+	if err := os.WriteFile(telemetryPath, []byte(telemetry), 0o600); err != nil {
+		t.Fatalf("write telemetry fixture: %v", err)
+	}
+	reportPath := filepath.Join(t.TempDir(), "junit", "ops.xml")
+
+	_, _, err := executeOpsCommand(Runtime{}, "run", "--preflight-optional-policy", "skip", "--state-path", statePath, "--rate-telemetry-file", telemetryPath, "--report-format", "junit", "--report-file", reportPath)
+	var exitErr *ops.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ops.ExitCodeWarning {
+		t.Fatalf("expected warning exit, got %v", err)
+	}
+
+	payload, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("read junit report: %v", err)
+	}
+	var suites ops.JUnitTestSuites
+	if err := xml.Unmarshal(payload, &suites); err != nil {
+		t.Fatalf("decode junit report: %v", err)
+	}
+	if suites.Name != ops.CommandRun || suites.Tests == 0 || suites.Failures != 1 {
+		t.Fatalf("unexpected junit totals: %+v", suites)
+	}
+	var failed *ops.JUnitTestCase
+	for _, suite := range suites.Suites {
+		for index := range suite.Cases {
+			if suite.Cases[index].Failure != nil {
+				failed = &suite.Cases[index]
+			}
+		}
+	}
+	if failed == nil || failed.Name != "rate_limit_threshold" || failed.Failure.Type != ops.FindingSeverityWarning {
+		t.Fatalf("unexpected failed case: %+v", failed)
+	}
+
+	_, _, err = executeOpsCommand(Runtime{}, "run", "--preflight-optional-policy", "skip", "--state-path", statePath, "--report-format", "junit")
+	if !errors.As(err, &exitErr) || exitErr.Code != ops.ExitCodeInput {
+		t.Fatalf("expected input exit for missing report file, got %v", err)
+	}
+}
+
+func TestOpsRunCommandCheckPolicyEscalatesWarningToPolicyExit(t *testing.T) {
+	t.Parallel()
+
+	statePath := filepath.Join(t.TempDir(), "baseline-state.json")
+	if _, err := ops.Initialize(statePath); err != nil {
+		t.Fatalf("initialize baseline state: %v", err)
+	}
+
+	telemetryPath := filepath.Join(t.TempDir(), "telemetry-warning.json")
+	telemetry := "{\n  \"app_call_count\": 65,\n  \"app_total_cputime\": 20,\n  \"app_total_time\": 10,\n  \"page_call_count\": 10,\n  \"page_total_cputime\": 5,\n  \"page_total_time\": 3,\n  \"ad_account_util_pct\": 2\n}\n"
+	if err := os.WriteFile(telemetryPath, []byte(telemetry), 0o600); err != nil {
+		t.Fatalf("write telemetry fixture: %v", err)
+	}
+
+	stdout, _, err := executeOpsCommand(Runtime{}, "run", "--preflight-optional-policy", "skip", "--state-path", statePath, "--rate-telemetry-file", telemetryPath, "--check-policy", "rate_limit_threshold=blocking")
+	var exitErr *ops.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected ExitError, got %T", err)
+	}
+	if exitErr.Code != ops.ExitCodePolicy {
+		t.Fatalf("unexpected exit code: got=%d want=%d", exitErr.Code, ops.ExitCodePolicy)
+	}
+	envelope := decodeOpsEnvelope(t, []byte(stdout))
+	if envelope.Error == nil || envelope.Error.Type != "blocking_findings" {
+		t.Fatalf("unexpected envelope error payload: %+v", envelope.Error)
+	}
+
+	_, stderr, err := executeOpsCommand(Runtime{}, "run", "--preflight-optional-policy", "skip", "--state-path", statePath, "--check-policy", "rate_limit_threshold=fatal")
+	if !errors.As(err, &exitErr) || exitErr.Code != ops.ExitCodeInput {
+		t.Fatalf("expected input exit for invalid check policy, got %v", err)
+	}
+	if envelope := decodeOpsEnvelope(t, []byte(stderr)); envelope.Error == nil || envelope.Error.Type != "input_error" {
+		t.Fatalf("unexpected envelope error payload: %+v", envelope.Error)
+	}
+}
+
+func TestOpsRunCommandRefreshChangelogDetectsLiveFeedDrift(t *testing.T) {
+	t.Parallel()
+
+	feedBody := `{"versions":[{"version":"v25.0"},{"version":"v24.0"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(feedBody))
+	}))
+	defer server.Close()
+
+	statePath := filepath.Join(t.TempDir(), "baseline-state.json")
+	if _, _, err := executeOpsCommand(Runtime{}, "init", "--state-path", statePath, "--refresh-changelog", "--changelog-url", server.URL); err != nil {
+		t.Fatalf("execute ops init with live changelog: %v", err)
+	}
+	state, err := ops.LoadBaseline(statePath)
+	if err != nil {
+		t.Fatalf("load baseline: %v", err)
+	}
+	if state.Snapshots.ChangelogOCC.LatestVersion != "v25.0" || !strings.HasPrefix(state.Snapshots.ChangelogOCC.OCCDigest, "sha256:") {
+		t.Fatalf("unexpected live changelog baseline: %+v", state.Snapshots.ChangelogOCC)
+	}
+
+	stdout, _, err := executeOpsCommand(Runtime{}, "run", "--preflight-optional-policy", "skip", "--state-path", statePath, "--refresh-changelog", "--changelog-url", server.URL)
+	if err != nil {
+		t.Fatalf("execute ops run with cached live changelog: %v", err)
+	}
+	var data ops.RunResult
+	if err := json.Unmarshal(decodeOpsEnvelope(t, []byte(stdout)).Data, &data); err != nil {
+		t.Fatalf("decode run data: %v", err)
+	}
+	if data.ChangelogFeed == nil || !data.ChangelogFeed.FromCache || data.ChangelogFeed.Feed.SourceURL != server.URL {
+		t.Fatalf("expected cached changelog feed echo, got %+v", data.ChangelogFeed)
+	}
+	if data.Report.Checks[0].Status != ops.CheckStatusPass {
+		t.Fatalf("expected unchanged changelog check, got %+v", data.Report.Checks[0])
+	}
+
+	feedBody = `{"versions":[{"version":"v26.0"},{"version":"v25.0"}]}`
+	stdout, _, err = executeOpsCommand(Runtime{}, "run", "--preflight-optional-policy", "skip", "--state-path", statePath, "--refresh-changelog", "--changelog-url", server.URL, "--changelog-cache-ttl", "0s")
+	var exitErr *ops.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ops.ExitCodePolicy {
+		t.Fatalf("expected policy exit on live changelog drift, got %v", err)
+	}
+	if err := json.Unmarshal(decodeOpsEnvelope(t, []byte(stdout)).Data, &data); err != nil {
+		t.Fatalf("decode run data: %v", err)
+	}
+	if data.ChangelogFeed == nil || data.ChangelogFeed.FromCache || data.ChangelogFeed.Feed.LatestVersion != "v26.0" {
+		t.Fatalf("expected fresh changelog feed echo, got %+v", data.ChangelogFeed)
+	}
+	if check := data.Report.Checks[0]; check.Status != ops.CheckStatusFail || !check.Blocking || !strings.Contains(check.Message, "latest_version=v26.0") {
+		t.Fatalf("unexpected changelog check: %+v", check)
+	}
+}
+
+func TestOpsRunCommandRefreshChangelogReturnsRuntimeExitOnFetchFailure(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	statePath := filepath.Join(t.TempDir(), "baseline-state.json")
+	if _, err := ops.Initialize(statePath); err != nil {
+		t.Fatalf("initialize baseline state: %v", err)
+	}
+
+	stdout, stderr, err := executeOpsCommand(Runtime{}, "run", "--preflight-optional-policy", "skip", "--state-path", statePath, "--refresh-changelog", "--changelog-url", server.URL)
+	var exitErr *ops.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ops.ExitCodeRuntime {
+		t.Fatalf("expected runtime exit on changelog fetch failure, got %v", err)
+	}
+	if stdout != "" {
+		t.Fatalf("expected empty stdout, got %q", stdout)
+	}
+	if envelope := decodeOpsEnvelope(t, []byte(stderr)); envelope.Error == nil || !strings.Contains(envelope.Error.Message, "unexpected status 503") {
+		t.Fatalf("unexpected envelope error payload: %+v", envelope.Error)
+	}
+}
+
 func TestOpsRunCommandWritesDeterministicJSONLSections(t *testing.T) {
 	t.Parallel()
 
@@ -1118,6 +1329,219 @@ func TestOpsRunCommandRejectsInvalidPreflightOptionalPolicy(t *testing.T) {
 	}
 }
 
+func TestOpsRunCommandMergesCustomChecksFromChecksFile(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	statePath := filepath.Join(tempDir, "baseline-state.json")
+	if _, err := ops.Initialize(statePath); err != nil {
+		t.Fatalf("initialize baseline state: %v", err)
+	}
+	checksPath := filepath.Join(tempDir, "checks.json")
+	checksBody := `{"schema_version":1,"checks":[{"name":"org_schema_source","type":"builtin","builtin":"schema_pack_source","policy":"blocking"}]}`
+	if err := os.WriteFile(checksPath, []byte(checksBody), 0o600); err != nil {
+		t.Fatalf("write checks file: %v", err)
+	}
+
+	stdout, stderr, err := executeOpsCommand(Runtime{}, "run", "--preflight-optional-policy", "skip", "--state-path", statePath, "--checks-file", checksPath)
+	if err != nil {
+		t.Fatalf("execute ops run: %v", err)
+	}
+	if stderr != "" {
+		t.Fatalf("expected empty stderr, got %q", stderr)
+	}
+
+	envelope := decodeOpsEnvelope(t, []byte(stdout))
+	var data ops.RunResult
+	if err := json.Unmarshal(envelope.Data, &data); err != nil {
+		t.Fatalf("decode run data: %v", err)
+	}
+	if len(data.Report.Checks) != 6 {
+		t.Fatalf("expected six checks, got %d", len(data.Report.Checks))
+	}
+	custom := data.Report.Checks[5]
+	if custom.Name != "org_schema_source" || custom.Status != ops.CheckStatusPass {
+		t.Fatalf("unexpected custom check: %+v", custom)
+	}
+	lastSection := data.Report.Sections[len(data.Report.Sections)-1]
+	if lastSection.Name != "other" || len(lastSection.Checks) != 1 {
+		t.Fatalf("expected custom check in other section, got %+v", lastSection)
+	}
+}
+
+func TestOpsRunCommandReturnsInputExitOnInvalidChecksFile(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	statePath := filepath.Join(tempDir, "baseline-state.json")
+	if _, err := ops.Initialize(statePath); err != nil {
+		t.Fatalf("initialize baseline state: %v", err)
+	}
+	checksPath := filepath.Join(tempDir, "checks.json")
+	if err := os.WriteFile(checksPath, []byte(`{"schema_version":1,"checks":[{"name":"gate","type":"shell"}]}`), 0o600); err != nil {
+		t.Fatalf("write checks file: %v", err)
+	}
+
+	stdout, stderr, err := executeOpsCommand(Runtime{}, "run", "--preflight-optional-policy", "skip", "--state-path", statePath, "--checks-file", checksPath)
+	if err == nil {
+		t.Fatal("expected invalid checks file to fail")
+	}
+	if code := ops.ExitCode(err); code != ops.ExitCodeInput {
+		t.Fatalf("unexpected exit code: got=%d want=%d", code, ops.ExitCodeInput)
+	}
+	if stdout != "" {
+		t.Fatalf("expected empty stdout, got %q", stdout)
+	}
+	envelope := decodeOpsEnvelope(t, []byte(stderr))
+	if envelope.Error == nil || envelope.Error.Type != "input_error" {
+		t.Fatalf("unexpected error payload: %+v", envelope.Error)
+	}
+}
+
+func TestOpsReportDiffCommandReportsNewFailuresBetweenRuns(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	statePath := filepath.Join(tempDir, "baseline-state.json")
+	if _, err := ops.Initialize(statePath); err != nil {
+		t.Fatalf("initialize baseline state: %v", err)
+	}
+
+	if _, _, err := executeOpsCommand(Runtime{}, "run", "--preflight-optional-policy", "skip", "--state-path", statePath); err != nil {
+		t.Fatalf("execute first ops run: %v", err)
+	}
+
+	state, err := ops.LoadBaseline(statePath)
+	if err != nil {
+		t.Fatalf("load baseline state: %v", err)
+	}
+	state.Snapshots.ChangelogOCC.LatestVersion = "v24.0"
+	if err := ops.SaveBaseline(statePath, state); err != nil {
+		t.Fatalf("save baseline state: %v", err)
+	}
+	if _, _, err := executeOpsCommand(Runtime{}, "run", "--preflight-optional-policy", "skip", "--state-path", statePath); err == nil {
+		t.Fatal("expected second ops run to report blocking drift")
+	}
+
+	stdout, stderr, err := executeOpsCommand(Runtime{}, "report", "diff", "--state-path", statePath)
+	if err == nil {
+		t.Fatal("expected report diff to fail on new blocking findings")
+	}
+	if code := ops.ExitCode(err); code != ops.ExitCodePolicy {
+		t.Fatalf("unexpected exit code: got=%d want=%d", code, ops.ExitCodePolicy)
+	}
+	if stderr != "" {
+		t.Fatalf("expected empty stderr, got %q", stderr)
+	}
+
+	envelope := decodeOpsEnvelope(t, []byte(stdout))
+	if envelope.Command != ops.CommandReportDiff {
+		t.Fatalf("unexpected command: %s", envelope.Command)
+	}
+	if envelope.Error == nil || envelope.Error.Type != "new_findings" {
+		t.Fatalf("unexpected error payload: %+v", envelope.Error)
+	}
+	var data ops.ReportDiffResult
+	if err := json.Unmarshal(envelope.Data, &data); err != nil {
+		t.Fatalf("decode diff data: %v", err)
+	}
+	if data.HistoryDir != filepath.Join(tempDir, "reports") {
+		t.Fatalf("unexpected history dir: %s", data.HistoryDir)
+	}
+	if len(data.NewFailures) != 1 || data.NewFailures[0].Name != "changelog_occ_delta" {
+		t.Fatalf("unexpected new failures: %+v", data.NewFailures)
+	}
+	if !data.OutcomeChanged || data.Previous.Outcome != ops.RunOutcomeClean || data.Latest.Outcome != ops.RunOutcomeBlocking {
+		t.Fatalf("unexpected outcome transition: previous=%s latest=%s", data.Previous.Outcome, data.Latest.Outcome)
+	}
+	if data.SummaryDelta.Blocking != 1 {
+		t.Fatalf("unexpected summary delta: %+v", data.SummaryDelta)
+	}
+}
+
+func TestOpsReportDiffCommandReturnsInputExitWithoutEnoughHistory(t *testing.T) {
+	t.Parallel()
+
+	historyDir := filepath.Join(t.TempDir(), "reports")
+	_, stderr, err := executeOpsCommand(Runtime{}, "report", "diff", "--history-dir", historyDir)
+	if err == nil {
+		t.Fatal("expected report diff without history to fail")
+	}
+	if code := ops.ExitCode(err); code != ops.ExitCodeInput {
+		t.Fatalf("unexpected exit code: got=%d want=%d", code, ops.ExitCodeInput)
+	}
+	envelope := decodeOpsEnvelope(t, []byte(stderr))
+	if envelope.Error == nil || !strings.Contains(envelope.Error.Message, "at least two recorded runs") {
+		t.Fatalf("unexpected error payload: %+v", envelope.Error)
+	}
+}
+
+func TestOpsMetricsServeCommandWritesListenEnvelopeAndStopsOnCancel(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	statePath := filepath.Join(tempDir, "baseline-state.json")
+	configPath := filepath.Join(tempDir, "config.yaml")
+	if err := config.Save(configPath, config.New()); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cmd := NewOpsCommand(Runtime{})
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"metrics", "serve", "--listen", "127.0.0.1:0", "--state-path", statePath, "--config-path", configPath})
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		t.Fatalf("execute ops metrics serve: %v", err)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected empty stderr, got %q", stderr.String())
+	}
+
+	envelope := decodeOpsEnvelope(t, stdout.Bytes())
+	if envelope.Command != ops.CommandMetricsServe || !envelope.Success {
+		t.Fatalf("unexpected envelope: %+v", envelope)
+	}
+	var data map[string]any
+	if err := json.Unmarshal(envelope.Data, &data); err != nil {
+		t.Fatalf("decode serve data: %v", err)
+	}
+	if listenAddr, _ := data["listen_addr"].(string); !strings.HasPrefix(listenAddr, "127.0.0.1:") {
+		t.Fatalf("unexpected listen addr: %v", data["listen_addr"])
+	}
+	if data["metrics_path"] != "/metrics" {
+		t.Fatalf("unexpected metrics path: %v", data["metrics_path"])
+	}
+	if data["history_dir"] != filepath.Join(tempDir, "reports") {
+		t.Fatalf("unexpected history dir: %v", data["history_dir"])
+	}
+}
+
+func TestOpsMetricsServeCommandReturnsInputExitOnMissingExplicitConfig(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	_, stderr, err := executeOpsCommand(Runtime{}, "metrics", "serve",
+		"--listen", "127.0.0.1:0",
+		"--state-path", filepath.Join(tempDir, "baseline-state.json"),
+		"--config-path", filepath.Join(tempDir, "missing.yaml"),
+	)
+	if err == nil {
+		t.Fatal("expected missing explicit config to fail")
+	}
+	if code := ops.ExitCode(err); code != ops.ExitCodeInput {
+		t.Fatalf("unexpected exit code: got=%d want=%d", code, ops.ExitCodeInput)
+	}
+	envelope := decodeOpsEnvelope(t, []byte(stderr))
+	if envelope.Command != ops.CommandMetricsServe || envelope.Error == nil {
+		t.Fatalf("unexpected error envelope: %+v", envelope)
+	}
+}
+
 func TestOpsRunCommandReturnsStateExitOnMissingBaseline(t *testing.T) {
 	t.Parallel()
 
@@ -1174,9 +1598,35 @@ func decodeOpsEnvelope(t *testing.T, raw []byte) envelopeFixture {
 	if err := json.Unmarshal(raw, &envelope); err != nil {
 		t.Fatalf("decode envelope: %v\nraw=%s", err, string(raw))
 	}
+	assertReportContract(t, envelope.Data)
 	return envelope
 }
 
+// assertReportContract checks data.report of an ops or smoke run envelope
+// against the report contract its kind names.
+func assertReportContract(t *testing.T, data json.RawMessage) {
+	t.Helper()
+
+	var run struct {
+		Report json.RawMessage `json:"report"`
+	}
+	if json.Unmarshal(data, &run) != nil || len(run.Report) == 0 {
+		return
+	}
+	var report struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(run.Report, &report); err != nil {
+		return
+	}
+	switch report.Kind {
+	case "ops_report":
+		testutil.AssertContract(t, "ops-report", run.Report)
+	case smoke.ReportKind:
+		testutil.AssertContract(t, "smoke-report", run.Report)
+	}
+}
+
 func runtimeWithProfile(profile string) Runtime {
 	output := "json"
 	debug := false
@@ -3,24 +3,57 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 
+	"github.com/bilalbayram/metacli/internal/filelock"
 	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/i18n"
 	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/bilalbayram/metacli/internal/query"
 	"github.com/spf13/cobra"
 )
 
 func writeSuccess(cmd *cobra.Command, runtime Runtime, commandName string, data any, paging any, rateLimit any) error {
+	data, err := applyOutputQuery(runtime, dryRunResult(cmd, data))
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
 	envelope, err := output.NewEnvelope(commandName, true, data, paging, rateLimit, nil)
 	if err != nil {
 		return err
 	}
-	return output.Write(cmd.OutOrStdout(), selectedOutputFormat(runtime), envelope)
+	envelope.Offline = offlineInfo(cmd)
+	return writeEnvelopeWithCurrency(cmd.OutOrStdout(), runtime, envelope, outputCurrency(cmd))
 }
 
 func writeCommandError(cmd *cobra.Command, runtime Runtime, commandName string, err error) error {
 	if err == nil {
 		return nil
 	}
+	errorInfo := commandErrorInfo(err)
+	if reason := CancellationReason(cmd.Context()); reason != "" {
+		markCanceled(errorInfo, reason)
+	}
+	if errorInfo.Retryable && recordReplayPlan(cmd, runtime, commandName, errorInfo) {
+		hintReplay(errorInfo)
+	}
+	localizeRemediation(errorInfo, runtime.Language())
+
+	envelope, envErr := output.NewEnvelope(commandName, false, nil, nil, nil, errorInfo)
+	if envErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, envErr)
+	}
+	envelope.Offline = offlineInfo(cmd)
+	if writeErr := writeEnvelope(cmd.ErrOrStderr(), runtime, envelope); writeErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, writeErr)
+	}
+	return err
+}
+
+// commandErrorInfo builds the envelope error for err before any cancellation
+// or replay hints are added.
+func commandErrorInfo(err error) *output.ErrorInfo {
 	errorInfo := &output.ErrorInfo{
 		Type:      "error",
 		Message:   err.Error(),
@@ -48,16 +81,89 @@ func writeCommandError(cmd *cobra.Command, runtime Runtime, commandName string,
 			remediation := graph.ClassifyRemediation(apiErr.StatusCode, apiErr.Code, apiErr.ErrorSubcode, apiErr.Message, apiErr.Diagnostics)
 			errorInfo.Remediation = mapRemediation(&remediation)
 		}
+		errorInfo.Class = output.ClassifyError(errorInfo)
+	} else {
+		errorInfo.Class = commandErrorClass(err)
+		markStateLocked(errorInfo, err)
+		markOffline(errorInfo, err)
 	}
+	return errorInfo
+}
 
-	envelope, envErr := output.NewEnvelope(commandName, false, nil, nil, nil, errorInfo)
-	if envErr != nil {
-		return fmt.Errorf("%w (secondary output error: %v)", err, envErr)
+// localizeRemediation translates the human-facing remediation text; the
+// category, reference, fields and docs stay as they are for automation.
+func localizeRemediation(errorInfo *output.ErrorInfo, lang string) {
+	if errorInfo.Remediation == nil {
+		return
 	}
-	if writeErr := output.Write(cmd.ErrOrStderr(), selectedOutputFormat(runtime), envelope); writeErr != nil {
-		return fmt.Errorf("%w (secondary output error: %v)", err, writeErr)
+	errorInfo.Remediation.Summary = i18n.Translate(lang, errorInfo.Remediation.Summary)
+	errorInfo.Remediation.Actions = i18n.TranslateAll(lang, errorInfo.Remediation.Actions)
+}
+
+// markStateLocked reports a config or state file held by another meta process
+// so scripts can tell it apart from a bad input.
+func markStateLocked(errorInfo *output.ErrorInfo, err error) {
+	var locked *filelock.LockedError
+	if !errors.As(err, &locked) {
+		return
 	}
-	return err
+	errorInfo.Type = "state_locked"
+	errorInfo.Class = output.ErrorClassConflict
+	errorInfo.Diagnostics = map[string]any{"path": locked.Path}
+	if locked.PID > 0 {
+		errorInfo.Diagnostics["lock_pid"] = locked.PID
+	}
+	errorInfo.Remediation = &output.Remediation{
+		Category: graph.RemediationCategoryConflict,
+		Summary:  "Another meta process is writing the same local state file.",
+		Actions: []string{
+			"Rerun with --wait-lock 30s (or META_WAIT_LOCK) to wait for the other process to finish.",
+			"If no meta process is running, remove " + locked.Path + ".lock.",
+		},
+	}
+}
+
+// writeEnvelope renders through the selected output format; --columns and
+// terminal sizing only affect table output.
+func writeEnvelope(w io.Writer, runtime Runtime, envelope output.Envelope) error {
+	return writeEnvelopeWithCurrency(w, runtime, envelope, "")
+}
+
+// writeEnvelopeWithCurrency is writeEnvelope with the account currency used to
+// format money columns in table output.
+func writeEnvelopeWithCurrency(w io.Writer, runtime Runtime, envelope output.Envelope, currency string) error {
+	format := selectedOutputFormat(runtime)
+	if runtime.Quiet != nil && *runtime.Quiet {
+		format = "quiet"
+	}
+	table := output.TerminalTableOptions(w, selectedOutputColumns(runtime))
+	table.Currency = currency
+	table.Lang = runtime.Language()
+	return output.WriteWithOptions(w, format, envelope, table)
+}
+
+// applyOutputQuery filters success data through --query. Errors are reported
+// as failures so a broken expression never looks like an empty result.
+func applyOutputQuery(runtime Runtime, data any) (any, error) {
+	if runtime.Query == nil || strings.TrimSpace(*runtime.Query) == "" {
+		return data, nil
+	}
+	expression, err := query.Compile(*runtime.Query)
+	if err != nil {
+		return nil, err
+	}
+	result, err := expression.Search(data)
+	if err != nil {
+		return nil, fmt.Errorf("apply --query %q: %w", expression, err)
+	}
+	return result, nil
+}
+
+func selectedOutputColumns(runtime Runtime) []string {
+	if runtime.Columns == nil {
+		return nil
+	}
+	return csvToSlice(*runtime.Columns)
 }
 
 func selectedOutputFormat(runtime Runtime) string {
@@ -86,10 +192,12 @@ func mapRemediation(remediation *graph.Remediation) *output.Remediation {
 	}
 
 	return &output.Remediation{
-		Category: remediation.Category,
-		Summary:  remediation.Summary,
-		Actions:  actions,
-		Fields:   fields,
+		Category:  remediation.Category,
+		Summary:   remediation.Summary,
+		Actions:   actions,
+		Fields:    fields,
+		Reference: remediation.Reference,
+		Docs:      append([]string(nil), remediation.Docs...),
 	}
 }
 
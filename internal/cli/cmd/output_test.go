@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/bilalbayram/metacli/internal/filelock"
 	"github.com/bilalbayram/metacli/internal/graph"
 	"github.com/spf13/cobra"
 )
@@ -70,6 +73,28 @@ func TestWriteCommandErrorIncludesStructuredGraphRemediation(t *testing.T) {
 	}
 }
 
+func TestWriteCommandErrorCarriesKnowledgeBaseReference(t *testing.T) {
+	t.Parallel()
+
+	errOutput := &bytes.Buffer{}
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+
+	apiErr := &graph.APIError{Type: "OAuthException", Code: 190, ErrorSubcode: 463, StatusCode: 401, Message: "Session has expired"}
+	_ = writeCommandError(cmd, runtimeWithJSONOutput(), "meta campaign list", apiErr)
+
+	errorBody := decodeCommandOutputEnvelope(t, errOutput.Bytes())["error"].(map[string]any)
+	remediation := errorBody["remediation"].(map[string]any)
+	if got := remediation["reference"]; got != "190/463" {
+		t.Fatalf("unexpected remediation reference %v", got)
+	}
+	docs, ok := remediation["docs"].([]any)
+	if !ok || len(docs) == 0 {
+		t.Fatalf("expected remediation docs, got %#v", remediation["docs"])
+	}
+}
+
 func TestWriteCommandErrorAddsDefaultRemediationForGenericErrors(t *testing.T) {
 	t.Parallel()
 
@@ -99,6 +124,30 @@ func TestWriteCommandErrorAddsDefaultRemediationForGenericErrors(t *testing.T) {
 	}
 }
 
+func TestWriteCommandErrorReportsStateLockHolder(t *testing.T) {
+	t.Parallel()
+
+	errOutput := &bytes.Buffer{}
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+
+	lockErr := fmt.Errorf("save template: %w", &filelock.LockedError{Path: "/home/me/.meta/templates.json", PID: 4242})
+	_ = writeCommandError(cmd, runtimeWithJSONOutput(), "meta template save", lockErr)
+
+	envelope := decodeCommandOutputEnvelope(t, errOutput.Bytes())
+	errorBody := envelope["error"].(map[string]any)
+	if errorBody["type"] != "state_locked" || errorBody["class"] != "conflict" {
+		t.Fatalf("unexpected error type/class: %v", errorBody)
+	}
+	if !strings.Contains(errorBody["message"].(string), "state locked by PID 4242") {
+		t.Fatalf("expected holder PID in message, got %v", errorBody["message"])
+	}
+	if got := errorBody["diagnostics"].(map[string]any)["lock_pid"]; got != float64(4242) {
+		t.Fatalf("unexpected lock_pid %v", got)
+	}
+}
+
 func runtimeWithJSONOutput() Runtime {
 	output := "json"
 	return Runtime{Output: &output}
@@ -113,3 +162,39 @@ func decodeCommandOutputEnvelope(t *testing.T, raw []byte) map[string]any {
 	}
 	return decoded
 }
+
+func TestWriteCommandErrorLocalizesRemediationOnly(t *testing.T) {
+	t.Parallel()
+
+	errOutput := &bytes.Buffer{}
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+
+	runtime := runtimeWithJSONOutput()
+	lang := "tr"
+	runtime.Lang = &lang
+	apiErr := &graph.APIError{
+		Type:       "OAuthException",
+		Code:       190,
+		StatusCode: 401,
+		Message:    "Error validating access token",
+	}
+	_ = writeCommandError(cmd, runtime, "meta campaign list", apiErr)
+
+	errorBody := decodeCommandOutputEnvelope(t, errOutput.Bytes())["error"].(map[string]any)
+	if errorBody["type"] != "OAuthException" || errorBody["code"] != float64(190) || errorBody["message"] != "Error validating access token" {
+		t.Fatalf("machine-readable fields changed: %#v", errorBody)
+	}
+	remediation := errorBody["remediation"].(map[string]any)
+	if remediation["category"] != graph.RemediationCategoryAuth || remediation["reference"] != "190" {
+		t.Fatalf("remediation category or reference changed: %#v", remediation)
+	}
+	if got := remediation["summary"]; got != "Erişim token'ı geçersiz veya süresi dolmuş." {
+		t.Fatalf("unexpected summary %v", got)
+	}
+	actions := remediation["actions"].([]any)
+	if got := actions[1]; got != "Kimlik bilgilerini `meta auth setup` veya `meta auth login` ile yenileyin." {
+		t.Fatalf("unexpected action %v", got)
+	}
+}
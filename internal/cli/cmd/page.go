@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/page"
+	"github.com/bilalbayram/metacli/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+const (
+	pagePluginID  = "facebook-page"
+	pageNamespace = "page"
+)
+
+var (
+	pageLoadProfileCredentials = loadProfileCredentials
+	pageNewGraphClient         = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+	pageNow = time.Now
+)
+
+func NewPageCommand(runtime Runtime) *cobra.Command {
+	tracer, err := plugin.NewNamespaceTracer(pageNamespace)
+	if err != nil {
+		return newPluginErrorCommand(pageNamespace, err)
+	}
+
+	registry, err := newPluginRegistry(tracer, newPagePluginManifest(runtime))
+	if err != nil {
+		return newPluginErrorCommand(pageNamespace, err)
+	}
+	return buildCommandFromRegistry(registry, pageNamespace)
+}
+
+func newPagePluginManifest(runtime Runtime) plugin.Manifest {
+	return plugin.Manifest{
+		ID:      pagePluginID,
+		Command: pageNamespace,
+		Short:   "Facebook Page publishing commands",
+		Build: func(pluginRuntime plugin.Runtime) (*cobra.Command, error) {
+			pageCmd := &cobra.Command{
+				Use:   pageNamespace,
+				Short: "Facebook Page publishing commands",
+				RunE: func(cmd *cobra.Command, _ []string) error {
+					return requireSubcommand(cmd, pageNamespace)
+				},
+			}
+			pageCmd.AddCommand(newPageHealthCommand(runtime, pluginRuntime))
+			pageCmd.AddCommand(newPagePostCommand(runtime, pluginRuntime, pagePostSpec{
+				use:          "post",
+				short:        "Publish a Facebook Page post (text, link, photo, or video)",
+				traceCommand: "post",
+				commandName:  "meta page post",
+			}))
+			pageCmd.AddCommand(newPagePostCommand(runtime, pluginRuntime, pagePostSpec{
+				use:          "schedule",
+				short:        "Schedule a Facebook Page post via scheduled_publish_time",
+				traceCommand: "schedule",
+				commandName:  "meta page schedule",
+				schedule:     true,
+			}))
+			pageCmd.AddCommand(newPageListCommand(runtime, pluginRuntime))
+			pageCmd.AddCommand(newPageDeleteCommand(runtime, pluginRuntime))
+			return pageCmd, nil
+		},
+	}
+}
+
+func newPageHealthCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	return &cobra.Command{
+		Use:   "health",
+		Short: "Verify Facebook Page plugin runtime scaffold",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  pagePluginID,
+				Namespace: pageNamespace,
+				Command:   "health",
+			}); err != nil {
+				return err
+			}
+			return writeSuccess(cmd, runtime, "meta page health", map[string]string{
+				"namespace": pageNamespace,
+				"plugin":    pagePluginID,
+				"status":    "ok",
+			}, nil, nil)
+		},
+	}
+}
+
+type pagePostSpec struct {
+	use          string
+	short        string
+	traceCommand string
+	commandName  string
+	schedule     bool
+}
+
+func newPagePostCommand(runtime Runtime, pluginRuntime plugin.Runtime, spec pagePostSpec) *cobra.Command {
+	var (
+		profile    string
+		version    string
+		pageID     string
+		message    string
+		link       string
+		photoURL   string
+		videoURL   string
+		videoTitle string
+		publishAt  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   spec.use,
+		Short: spec.short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  pagePluginID,
+				Namespace: pageNamespace,
+				Command:   spec.traceCommand,
+			}); err != nil {
+				return writeCommandError(cmd, runtime, spec.commandName, err)
+			}
+
+			creds, resolvedVersion, resolvedPageID, err := resolvePageProfile(runtime, profile, version, pageID)
+			if err != nil {
+				return writeCommandError(cmd, runtime, spec.commandName, err)
+			}
+
+			options := page.PostOptions{
+				PageID:      resolvedPageID,
+				Message:     message,
+				Link:        link,
+				PhotoURL:    photoURL,
+				VideoURL:    videoURL,
+				VideoTitle:  videoTitle,
+				PublishAt:   publishAt,
+				RequireTime: spec.schedule,
+				Now:         pageNow(),
+			}
+			if _, _, err := page.BuildPostRequest(resolvedVersion, creds.Token, creds.AppSecret, options); err != nil {
+				return writeCommandError(cmd, runtime, spec.commandName, err)
+			}
+
+			service := page.New(pageNewGraphClient())
+			result, err := service.Post(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, spec.commandName, err)
+			}
+			return writeSuccess(cmd, runtime, spec.commandName, result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name (must hold a page token)")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&pageID, "page-id", "", "Facebook Page ID (optional when profile has page_id)")
+	cmd.Flags().StringVar(&message, "message", "", "Post text (caption/description for photo and video posts)")
+	cmd.Flags().StringVar(&link, "link", "", "Link to attach to a feed post")
+	cmd.Flags().StringVar(&photoURL, "photo-url", "", "Public photo URL to publish as a photo post")
+	cmd.Flags().StringVar(&videoURL, "video-url", "", "Public video URL to publish as a video post")
+	cmd.Flags().StringVar(&videoTitle, "video-title", "", "Video title (video posts only)")
+	if spec.schedule {
+		cmd.Flags().StringVar(&publishAt, "publish-at", "", "Publish time (RFC3339), 10 minutes to 30 days from now (required)")
+	} else {
+		cmd.Flags().StringVar(&publishAt, "publish-at", "", "Optional publish time (RFC3339); schedules the post instead of publishing now")
+	}
+	return cmd
+}
+
+func newPageListCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile    string
+		version    string
+		pageID     string
+		scheduled  bool
+		fieldsRaw  string
+		limit      int
+		pageSize   int
+		followNext bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List published or scheduled Facebook Page posts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  pagePluginID,
+				Namespace: pageNamespace,
+				Command:   "list",
+			}); err != nil {
+				return writeCommandError(cmd, runtime, "meta page list", err)
+			}
+
+			creds, resolvedVersion, resolvedPageID, err := resolvePageProfile(runtime, profile, version, pageID)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta page list", err)
+			}
+
+			kind := page.ListKindPublished
+			if scheduled {
+				kind = page.ListKindScheduled
+			}
+			options := page.ListPostsOptions{
+				PageID:     resolvedPageID,
+				Kind:       kind,
+				Fields:     csvToSlice(fieldsRaw),
+				Limit:      limit,
+				PageSize:   pageSize,
+				FollowNext: followNext,
+			}
+			if _, _, err := page.BuildListPostsRequest(resolvedVersion, creds.Token, creds.AppSecret, options); err != nil {
+				return writeCommandError(cmd, runtime, "meta page list", err)
+			}
+
+			service := page.New(pageNewGraphClient())
+			result, err := service.ListPosts(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta page list", err)
+			}
+			return writeSuccess(cmd, runtime, "meta page list", result.Posts, result.Paging, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name (must hold a page token)")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&pageID, "page-id", "", "Facebook Page ID (optional when profile has page_id)")
+	cmd.Flags().BoolVar(&scheduled, "scheduled", false, "List scheduled (unpublished) posts instead of published posts")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated post fields (defaults to "+strings.Join(page.DefaultListFields, ",")+")")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of posts to return (0 = unlimited)")
+	cmd.Flags().IntVar(&pageSize, "page-size", 0, "Graph page size for post reads")
+	cmd.Flags().BoolVar(&followNext, "follow-next", false, "Follow paging.next links")
+	return cmd
+}
+
+func newPageDeleteCommand(runtime Runtime, pluginRuntime plugin.Runtime) *cobra.Command {
+	var (
+		profile string
+		version string
+		postID  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a published or scheduled Facebook Page post",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := pluginRuntime.Trace(plugin.TraceEvent{
+				PluginID:  pagePluginID,
+				Namespace: pageNamespace,
+				Command:   "delete",
+			}); err != nil {
+				return writeCommandError(cmd, runtime, "meta page delete", err)
+			}
+
+			creds, resolvedVersion, err := resolvePageProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta page delete", err)
+			}
+			if err := page.ValidatePageToken(creds.Name, creds.Profile); err != nil {
+				return writeCommandError(cmd, runtime, "meta page delete", err)
+			}
+
+			options := page.DeletePostOptions{PostID: postID}
+			if _, _, err := page.BuildDeletePostRequest(resolvedVersion, creds.Token, creds.AppSecret, options); err != nil {
+				return writeCommandError(cmd, runtime, "meta page delete", err)
+			}
+
+			service := page.New(pageNewGraphClient())
+			result, err := service.DeletePost(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta page delete", err)
+			}
+			return writeSuccess(cmd, runtime, "meta page delete", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name (must hold a page token)")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&postID, "post-id", "", "Page post id (<PAGE_ID>_<POST_ID>) or photo/video id")
+	return cmd
+}
+
+func resolvePageProfile(runtime Runtime, profile string, version string, pageID string) (*ProfileCredentials, string, string, error) {
+	creds, resolvedVersion, err := resolvePageProfileAndVersion(runtime, profile, version)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if err := page.ValidatePageToken(creds.Name, creds.Profile); err != nil {
+		return nil, "", "", err
+	}
+	resolvedPageID := strings.TrimSpace(pageID)
+	if resolvedPageID == "" {
+		resolvedPageID = strings.TrimSpace(creds.Profile.PageID)
+	}
+	if resolvedPageID == "" {
+		return nil, "", "", errors.New("page id is required (--page-id or profile page_id)")
+	}
+	return creds, resolvedVersion, resolvedPageID, nil
+}
+
+func resolvePageProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
+	resolvedProfile := strings.TrimSpace(profile)
+	if resolvedProfile == "" {
+		resolvedProfile = runtime.ProfileName()
+	}
+	if resolvedProfile == "" {
+		return nil, "", errors.New("profile is required (--profile or global --profile)")
+	}
+
+	creds, err := pageLoadProfileCredentials(resolvedProfile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resolvedVersion := strings.TrimSpace(version)
+	if resolvedVersion == "" {
+		resolvedVersion = creds.Profile.GraphVersion
+	}
+	if resolvedVersion == "" {
+		resolvedVersion = config.DefaultGraphVersion
+	}
+
+	return creds, resolvedVersion, nil
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func usePageDependencies(t *testing.T, loadFn func(string) (*ProfileCredentials, error), clientFn func() *graph.Client) {
+	t.Helper()
+	originalLoad := pageLoadProfileCredentials
+	originalClient := pageNewGraphClient
+	originalNow := pageNow
+	t.Cleanup(func() {
+		pageLoadProfileCredentials = originalLoad
+		pageNewGraphClient = originalClient
+		pageNow = originalNow
+	})
+
+	pageLoadProfileCredentials = loadFn
+	pageNewGraphClient = clientFn
+	pageNow = func() time.Time {
+		return time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	}
+}
+
+func pageTestCredentials(tokenType string) func(string) (*ProfileCredentials, error) {
+	return func(string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{
+			Name:    "brand-page",
+			Profile: config.Profile{GraphVersion: "v25.0", TokenType: tokenType, PageID: "page_1"},
+			Token:   "page-token",
+		}, nil
+	}
+}
+
+func TestNewPageCommandIncludesSubcommands(t *testing.T) {
+	cmd := NewPageCommand(Runtime{})
+	if cmd.Name() != "page" {
+		t.Fatalf("expected page command name, got %q", cmd.Name())
+	}
+	for _, name := range []string{"health", "post", "schedule", "list", "delete"} {
+		sub, _, err := cmd.Find([]string{name})
+		if err != nil || sub == nil || sub.Name() != name {
+			t.Fatalf("expected %s subcommand, got %#v (%v)", name, sub, err)
+		}
+	}
+}
+
+func TestPageScheduleCommandPostsUnpublishedFeedPost(t *testing.T) {
+	stub := &stubHTTPClient{t: t, statusCode: http.StatusOK, response: `{"id":"page_1_post_9"}`}
+	usePageDependencies(t, pageTestCredentials("page"), func() *graph.Client {
+		return graph.NewClient(stub, "https://graph.example.com")
+	})
+
+	output := &bytes.Buffer{}
+	cmd := NewPageCommand(testRuntime("brand-page"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"schedule",
+		"--message", "Launch tomorrow",
+		"--link", "https://example.com/launch",
+		"--publish-at", "2026-04-02T09:00:00Z",
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute page schedule: %v", err)
+	}
+
+	if stub.lastMethod != http.MethodPost || !strings.Contains(stub.lastURL, "/v25.0/page_1/feed") {
+		t.Fatalf("unexpected request %s %s", stub.lastMethod, stub.lastURL)
+	}
+	for _, want := range []string{"published=false", "scheduled_publish_time=1775120400", "link=https%3A%2F%2Fexample.com%2Flaunch"} {
+		if !strings.Contains(stub.lastBody, want) {
+			t.Fatalf("expected %q in body %q", want, stub.lastBody)
+		}
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta page schedule")
+	data, _ := envelope["data"].(map[string]any)
+	if data["mode"] != "scheduled" || data["post_type"] != "link" || data["id"] != "page_1_post_9" {
+		t.Fatalf("unexpected data %#v", data)
+	}
+}
+
+func TestPageScheduleCommandRequiresPublishAt(t *testing.T) {
+	usePageDependencies(t, pageTestCredentials("page"), func() *graph.Client {
+		t.Fatal("graph client should not be created without publish-at")
+		return nil
+	})
+
+	cmd := NewPageCommand(testRuntime("brand-page"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"schedule", "--message", "hi"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "publish-at is required") {
+		t.Fatalf("expected publish-at error, got %v", err)
+	}
+}
+
+func TestPagePostCommandRejectsNonPageToken(t *testing.T) {
+	usePageDependencies(t, pageTestCredentials("system_user"), func() *graph.Client {
+		t.Fatal("graph client should not be created for non-page tokens")
+		return nil
+	})
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewPageCommand(testRuntime("brand-page"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"post", "--message", "hi"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected page token error")
+	}
+
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	errorBody, _ := envelope["error"].(map[string]any)
+	if errorBody["type"] != "page_token_required" {
+		t.Fatalf("unexpected error %#v", errorBody)
+	}
+}
+
+func TestPageListCommandReadsScheduledPosts(t *testing.T) {
+	stub := &stubHTTPClient{t: t, statusCode: http.StatusOK, response: `{"data":[{"id":"page_1_post_1"},{"id":"page_1_post_2"}]}`}
+	usePageDependencies(t, pageTestCredentials("page"), func() *graph.Client {
+		return graph.NewClient(stub, "https://graph.example.com")
+	})
+
+	output := &bytes.Buffer{}
+	cmd := NewPageCommand(testRuntime("brand-page"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"list", "--scheduled", "--fields", "id,message"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute page list: %v", err)
+	}
+
+	if !strings.Contains(stub.lastURL, "/v25.0/page_1/scheduled_posts") || !strings.Contains(stub.lastURL, "fields=id%2Cmessage") {
+		t.Fatalf("unexpected url %s", stub.lastURL)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta page list")
+	posts, _ := envelope["data"].([]any)
+	if len(posts) != 2 {
+		t.Fatalf("expected two posts, got %#v", envelope["data"])
+	}
+}
+
+func TestPageDeleteCommandDeletesPost(t *testing.T) {
+	stub := &stubHTTPClient{t: t, statusCode: http.StatusOK, response: `{"success":true}`}
+	usePageDependencies(t, pageTestCredentials("page"), func() *graph.Client {
+		return graph.NewClient(stub, "https://graph.example.com")
+	})
+
+	output := &bytes.Buffer{}
+	cmd := NewPageCommand(testRuntime("brand-page"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"delete", "--post-id", "page_1_post_1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute page delete: %v", err)
+	}
+
+	if stub.lastMethod != http.MethodDelete || !strings.Contains(stub.lastURL, "/v25.0/page_1_post_1") {
+		t.Fatalf("unexpected request %s %s", stub.lastMethod, stub.lastURL)
+	}
+	data, _ := decodeEnvelope(t, output.Bytes())["data"].(map[string]any)
+	if data["deleted"] != true {
+		t.Fatalf("expected deleted=true, got %#v", data)
+	}
+}
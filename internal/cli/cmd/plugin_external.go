@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+const externalPluginNamespace = "external"
+
+var (
+	pluginPathEnv = func() string {
+		return os.Getenv("PATH")
+	}
+	pluginExternalConfigPath     = plugin.DefaultExternalConfigPath
+	pluginLoadProfileCredentials = loadProfileCredentials
+	pluginRunExternal            = plugin.RunExternal
+)
+
+func NewPluginCommand(runtime Runtime) *cobra.Command {
+	pluginCmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Inspect CLI plugins",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "plugin")
+		},
+	}
+	pluginCmd.AddCommand(newPluginListCommand(runtime))
+	pluginCmd.AddCommand(newPluginTraceCommand(runtime))
+	return pluginCmd
+}
+
+func newPluginListCommand(runtime Runtime) *cobra.Command {
+	var configPath string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List external plugins discovered on PATH and in plugins.yaml",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			plugins, err := discoverExternalPlugins(cmd.Root(), configPath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta plugin list", err)
+			}
+			return writeSuccess(cmd, runtime, "meta plugin list", plugins, nil, nil)
+		},
+	}
+	cmd.Flags().StringVar(&configPath, "config", "", "Plugin config path (defaults to ~/.meta/plugins.yaml)")
+	return cmd
+}
+
+// AddExternalPluginCommands mounts discovered meta-<name> executables as `meta <name>`.
+// Discovery problems are not fatal here; `meta plugin list` reports them.
+func AddExternalPluginCommands(root *cobra.Command, runtime Runtime) error {
+	plugins, err := discoverExternalPlugins(root, "")
+	if err != nil {
+		return err
+	}
+	for _, external := range plugins {
+		root.AddCommand(newExternalPluginCommand(runtime, external))
+	}
+	return nil
+}
+
+func discoverExternalPlugins(root *cobra.Command, configPath string) ([]plugin.ExternalPlugin, error) {
+	if root == nil {
+		return nil, errors.New("root command is required for plugin discovery")
+	}
+	if strings.TrimSpace(configPath) == "" {
+		defaultPath, err := pluginExternalConfigPath()
+		if err != nil {
+			return nil, err
+		}
+		configPath = defaultPath
+	}
+
+	reserved := []string{"help", "completion"}
+	for _, builtIn := range root.Commands() {
+		reserved = append(reserved, builtIn.Name())
+		reserved = append(reserved, builtIn.Aliases...)
+	}
+	return plugin.DiscoverExternal(plugin.ExternalDiscoveryOptions{
+		PathEnv:    pluginPathEnv(),
+		ConfigPath: configPath,
+		Reserved:   reserved,
+	})
+}
+
+func newExternalPluginCommand(runtime Runtime, external plugin.ExternalPlugin) *cobra.Command {
+	commandName := "meta " + external.Name
+	return &cobra.Command{
+		Use:                external.Name,
+		Short:              external.Short,
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			forwarded, globals, err := splitExternalPluginArgs(args)
+			if err != nil {
+				return writeCommandError(cmd, runtime, commandName, err)
+			}
+
+			profile := runtime.ProfileName()
+			if globals.profile != nil {
+				profile = *globals.profile
+			}
+			handshake := plugin.ExternalHandshake{
+				Args: forwarded,
+				Runtime: plugin.ExternalHandshakeRuntime{
+					Output: selectedOutputFormat(runtime),
+					Debug:  runtime.Debug != nil && *runtime.Debug,
+				},
+			}
+			if globals.output != nil {
+				handshake.Runtime.Output = *globals.output
+			}
+			if globals.debug != nil {
+				handshake.Runtime.Debug = *globals.debug
+			}
+
+			if strings.TrimSpace(profile) != "" {
+				creds, err := pluginLoadProfileCredentials(profile)
+				if err != nil {
+					return writeCommandError(cmd, runtime, commandName, err)
+				}
+				handshake.Profile = &plugin.ExternalHandshakeProfile{
+					Name:         creds.Name,
+					Domain:       creds.Profile.Domain,
+					GraphVersion: creds.Profile.GraphVersion,
+					TokenType:    creds.Profile.TokenType,
+					Token:        creds.Token,
+					AppSecret:    creds.AppSecret,
+					AppID:        creds.Profile.AppID,
+					BusinessID:   creds.Profile.BusinessID,
+					PageID:       creds.Profile.PageID,
+					IGUserID:     creds.Profile.IGUserID,
+					Scopes:       creds.Profile.Scopes,
+				}
+			}
+
+			plugin.EmitTrace(plugin.TraceEvent{
+				PluginID:  external.Name,
+				Namespace: externalPluginNamespace,
+				Command:   external.Name,
+			})
+			if err := pluginRunExternal(cmd.Context(), external, handshake, cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+				return writeCommandError(cmd, runtime, commandName, err)
+			}
+			return nil
+		},
+	}
+}
+
+type externalPluginGlobals struct {
+	profile *string
+	output  *string
+	debug   *bool
+}
+
+// splitExternalPluginArgs consumes meta's global flags (--profile, --output, --debug),
+// which cobra leaves in place when flag parsing is disabled. Everything after "--"
+// is forwarded untouched.
+func splitExternalPluginArgs(args []string) ([]string, externalPluginGlobals, error) {
+	globals := externalPluginGlobals{}
+	forwarded := make([]string, 0, len(args))
+	for index := 0; index < len(args); index++ {
+		arg := args[index]
+		if arg == "--" {
+			forwarded = append(forwarded, args[index+1:]...)
+			break
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--profile", "--output":
+			if !hasValue {
+				if index+1 >= len(args) {
+					return nil, globals, errors.New("flag needs an argument: " + name)
+				}
+				index++
+				value = args[index]
+			}
+			if name == "--profile" {
+				globals.profile = &value
+			} else {
+				globals.output = &value
+			}
+		case "--debug":
+			enabled := true
+			if hasValue {
+				parsed, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, globals, errors.New("invalid --debug value " + strconv.Quote(value))
+				}
+				enabled = parsed
+			}
+			globals.debug = &enabled
+		default:
+			forwarded = append(forwarded, arg)
+		}
+	}
+	return forwarded, globals, nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+func TestSplitExternalPluginArgsConsumesGlobalFlags(t *testing.T) {
+	t.Parallel()
+
+	forwarded, globals, err := splitExternalPluginArgs([]string{"--profile", "prod", "sync", "--output=table", "--debug", "--limit", "5", "--", "--profile", "raw"})
+	if err != nil {
+		t.Fatalf("split args: %v", err)
+	}
+	if strings.Join(forwarded, " ") != "sync --limit 5 --profile raw" {
+		t.Fatalf("unexpected forwarded args %#v", forwarded)
+	}
+	if globals.profile == nil || *globals.profile != "prod" || globals.output == nil || *globals.output != "table" || globals.debug == nil || !*globals.debug {
+		t.Fatalf("unexpected globals %#v", globals)
+	}
+
+	if _, _, err := splitExternalPluginArgs([]string{"--profile"}); err == nil {
+		t.Fatal("expected missing --profile value error")
+	}
+}
+
+func TestExternalPluginCommandSendsProfileHandshake(t *testing.T) {
+	originalLoad := pluginLoadProfileCredentials
+	originalRun := pluginRunExternal
+	t.Cleanup(func() {
+		pluginLoadProfileCredentials = originalLoad
+		pluginRunExternal = originalRun
+	})
+
+	pluginLoadProfileCredentials = func(profile string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{
+			Name:      profile,
+			Profile:   config.Profile{GraphVersion: "v25.0", TokenType: "system_user", BusinessID: "biz_1"},
+			Token:     "test-token",
+			AppSecret: "test-secret",
+		}, nil
+	}
+	var captured plugin.ExternalHandshake
+	pluginRunExternal = func(_ context.Context, external plugin.ExternalPlugin, handshake plugin.ExternalHandshake, stdout io.Writer, _ io.Writer) error {
+		captured = handshake
+		_, err := io.WriteString(stdout, "ran "+external.Name)
+		return err
+	}
+
+	output := &bytes.Buffer{}
+	root := &cobra.Command{Use: "meta"}
+	root.AddCommand(newExternalPluginCommand(testRuntime("prod"), plugin.ExternalPlugin{Name: "foo", Path: "/usr/local/bin/meta-foo", Short: "Foo tools"}))
+	root.SetOut(output)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"foo", "sync", "--since", "7d"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute external plugin: %v", err)
+	}
+
+	if output.String() != "ran foo" {
+		t.Fatalf("unexpected plugin output %q", output.String())
+	}
+	if strings.Join(captured.Args, " ") != "sync --since 7d" || captured.Runtime.Output != "json" {
+		t.Fatalf("unexpected handshake %#v", captured)
+	}
+	if captured.Profile == nil || captured.Profile.Name != "prod" || captured.Profile.Token != "test-token" || captured.Profile.BusinessID != "biz_1" {
+		t.Fatalf("unexpected handshake profile %#v", captured.Profile)
+	}
+}
+
+func TestPluginListCommandReportsDiscoveredPlugins(t *testing.T) {
+	originalPath := pluginPathEnv
+	originalConfig := pluginExternalConfigPath
+	t.Cleanup(func() {
+		pluginPathEnv = originalPath
+		pluginExternalConfigPath = originalConfig
+	})
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "meta-foo"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("write plugin: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "meta-plugin"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("write plugin: %v", err)
+	}
+	pluginPathEnv = func() string { return dir }
+	pluginExternalConfigPath = func() (string, error) { return filepath.Join(dir, "plugins.yaml"), nil }
+
+	output := &bytes.Buffer{}
+	root := &cobra.Command{Use: "meta"}
+	root.AddCommand(NewPluginCommand(testRuntime("")))
+	root.SetOut(output)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"plugin", "list"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute plugin list: %v", err)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta plugin list")
+	plugins, _ := envelope["data"].([]any)
+	if len(plugins) != 1 {
+		t.Fatalf("expected only meta-foo, got %#v", envelope["data"])
+	}
+	first, _ := plugins[0].(map[string]any)
+	if first["name"] != "foo" || first["source"] != plugin.ExternalSourcePath {
+		t.Fatalf("unexpected plugin entry %#v", first)
+	}
+}
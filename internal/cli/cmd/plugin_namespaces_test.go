@@ -343,7 +343,7 @@ func namespaceCommandCases() []namespaceCommandCase {
 			namespace:              "capi",
 			pluginID:               "capi",
 			supportedCapability:    "send-event",
-			discoveredCapabilities: []string{"send-event", "test-event"},
+			discoveredCapabilities: []string{"pixel-stats", "send-event", "test-event"},
 			newCommand:             NewCAPICommand,
 		},
 	}
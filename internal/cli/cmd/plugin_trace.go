@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+var pluginTraceHTTPClient plugin.HTTPClient
+
+// ConfigureTraceSinks installs the trace sinks declared under `trace` in plugins.yaml.
+// A missing plugins.yaml disables trace export.
+func ConfigureTraceSinks(stderr io.Writer) error {
+	cfg, err := loadPluginTraceConfig()
+	if err != nil {
+		return err
+	}
+	if cfg == nil || len(cfg.Sinks) == 0 {
+		plugin.SetTraceSink(nil)
+		return nil
+	}
+	sink, err := plugin.NewTraceSink(*cfg, stderr, pluginTraceHTTPClient)
+	if err != nil {
+		return err
+	}
+	plugin.SetTraceSink(sink)
+	return nil
+}
+
+func loadPluginTraceConfig() (*plugin.TraceConfig, error) {
+	path, err := pluginExternalConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := plugin.LoadExternalConfig(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cfg.Trace, nil
+}
+
+func newPluginTraceCommand(runtime Runtime) *cobra.Command {
+	traceCmd := &cobra.Command{
+		Use:   "trace",
+		Short: "Inspect plugin trace events",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "plugin trace")
+		},
+	}
+	traceCmd.AddCommand(newPluginTraceTailCommand(runtime))
+	return traceCmd
+}
+
+func newPluginTraceTailCommand(runtime Runtime) *cobra.Command {
+	options := plugin.TraceTailOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "tail",
+		Short: "Show the most recent plugin trace events from the file sink",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(options.Path) == "" {
+				path, err := resolvePluginTraceLogPath()
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta plugin trace tail", err)
+				}
+				options.Path = path
+			}
+			events, err := plugin.TailTraceLog(options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta plugin trace tail", err)
+			}
+			return writeSuccess(cmd, runtime, "meta plugin trace tail", events, nil, nil)
+		},
+	}
+	cmd.Flags().StringVar(&options.Path, "file", "", "Trace log path (defaults to the configured file sink, then ~/.meta/plugin_trace.jsonl)")
+	cmd.Flags().IntVar(&options.Limit, "limit", 20, "Maximum number of events to show")
+	cmd.Flags().StringVar(&options.Namespace, "namespace", "", "Only show events for this namespace")
+	cmd.Flags().StringVar(&options.PluginID, "plugin-id", "", "Only show events for this plugin id")
+	return cmd
+}
+
+func resolvePluginTraceLogPath() (string, error) {
+	cfg, err := loadPluginTraceConfig()
+	if err != nil {
+		return "", err
+	}
+	if cfg != nil {
+		for _, sinkConfig := range cfg.Sinks {
+			if sinkConfig.Type == plugin.TraceSinkFile && strings.TrimSpace(sinkConfig.Path) != "" {
+				return sinkConfig.Path, nil
+			}
+		}
+	}
+	return plugin.DefaultTraceLogPath()
+}
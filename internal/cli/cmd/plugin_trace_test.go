@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+func usePluginTraceConfig(t *testing.T, content string) string {
+	t.Helper()
+	originalConfig := pluginExternalConfigPath
+	t.Cleanup(func() {
+		pluginExternalConfigPath = originalConfig
+		plugin.SetTraceSink(nil)
+	})
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "plugins.yaml")
+	if err := os.WriteFile(configPath, []byte(strings.ReplaceAll(content, "{dir}", dir)), 0o600); err != nil {
+		t.Fatalf("write plugin config: %v", err)
+	}
+	pluginExternalConfigPath = func() (string, error) { return configPath, nil }
+	return dir
+}
+
+func TestConfigureTraceSinksExportsNamespaceEvents(t *testing.T) {
+	dir := usePluginTraceConfig(t, "schema_version: 1\ntrace:\n  sinks:\n    - type: file\n      path: {dir}/trace.jsonl\n    - type: stderr\n")
+
+	stderr := &bytes.Buffer{}
+	if err := ConfigureTraceSinks(stderr); err != nil {
+		t.Fatalf("configure trace sinks: %v", err)
+	}
+
+	cmd := NewIGCommand(testRuntime("prod"))
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"health"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute ig health: %v", err)
+	}
+	if !strings.Contains(stderr.String(), `"namespace":"ig"`) {
+		t.Fatalf("expected ig trace on stderr, got %q", stderr.String())
+	}
+
+	output := &bytes.Buffer{}
+	root := &cobra.Command{Use: "meta"}
+	root.AddCommand(NewPluginCommand(testRuntime("")))
+	root.SetOut(output)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"plugin", "trace", "tail", "--namespace", "ig"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute plugin trace tail: %v", err)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta plugin trace tail")
+	events, _ := envelope["data"].([]any)
+	if len(events) != 1 {
+		t.Fatalf("expected one ig event, got %#v", envelope["data"])
+	}
+	first, _ := events[0].(map[string]any)
+	if first["plugin_id"] != "instagram" || first["command"] != "health" {
+		t.Fatalf("unexpected trace event %#v", first)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "trace.jsonl")); err != nil {
+		t.Fatalf("expected trace log file: %v", err)
+	}
+}
+
+func TestConfigureTraceSinksRejectsInvalidSink(t *testing.T) {
+	usePluginTraceConfig(t, "schema_version: 1\ntrace:\n  sinks:\n    - type: otlp\n")
+
+	if err := ConfigureTraceSinks(&bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "otlp sink endpoint is required") {
+		t.Fatalf("expected otlp endpoint error, got %v", err)
+	}
+}
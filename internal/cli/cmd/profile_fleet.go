@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const profilesSelectorFlag = "profiles-selector"
+
+// FlagSourceAnnotation marks flags filled from the environment or config
+// rather than the command line. Per-profile runs resolve those again, so only
+// explicit flags are forwarded to them.
+const FlagSourceAnnotation = "meta_flag_source"
+
+// fleetFlagsDropped are not forwarded to the per-profile runs: the selector and
+// profile are replaced, and output flags only shape the combined envelope.
+var fleetFlagsDropped = map[string]struct{}{
+	profilesSelectorFlag: {},
+	"profile":            {},
+	"output":             {},
+	"quiet":              {},
+	"query":              {},
+	"columns":            {},
+}
+
+var (
+	fleetMu         sync.Mutex
+	fleetReplayer   Replayer
+	fleetConfigPath = config.DefaultPath
+)
+
+// SetFleetReplayer installs the runner used to execute a command once per
+// profile matched by --profiles-selector.
+func SetFleetReplayer(replayer Replayer) {
+	fleetMu.Lock()
+	defer fleetMu.Unlock()
+	fleetReplayer = replayer
+}
+
+func currentFleetReplayer() Replayer {
+	fleetMu.Lock()
+	defer fleetMu.Unlock()
+	return fleetReplayer
+}
+
+type profileFleetRun struct {
+	Profile string         `json:"profile"`
+	Success bool           `json:"success"`
+	Data    any            `json:"data,omitempty"`
+	Error   map[string]any `json:"error,omitempty"`
+}
+
+type profileFleetResult struct {
+	Selector  string            `json:"selector"`
+	Profiles  []string          `json:"profiles"`
+	Runs      []profileFleetRun `json:"runs"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+func addProfilesSelectorFlag(cmd *cobra.Command, selector *string) {
+	cmd.Flags().StringVar(selector, profilesSelectorFlag, "", "Run once for every profile whose labels match, e.g. env=staging,client!=acme (replaces --profile)")
+}
+
+// runProfileFleet executes cmd once per profile matching selector, in name
+// order, and reports every run in a single envelope. Runs continue past
+// failures; the command fails with the first failure once all have run.
+func runProfileFleet(cmd *cobra.Command, runtime Runtime, commandName string, rawSelector string) error {
+	selector, err := config.ParseSelector(rawSelector)
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	replayer := currentFleetReplayer()
+	if replayer == nil {
+		return writeCommandError(cmd, runtime, commandName, errors.New("--profiles-selector is not available in this build"))
+	}
+	configPath, err := fleetConfigPath()
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return writeCommandError(cmd, runtime, commandName, err)
+	}
+	profiles := cfg.SelectProfiles(selector)
+	if len(profiles) == 0 {
+		return writeCommandError(cmd, runtime, commandName, fmt.Errorf("no profiles match selector %q", rawSelector))
+	}
+
+	result := profileFleetResult{Selector: rawSelector, Profiles: profiles, Runs: []profileFleetRun{}}
+	var firstErr error
+	for _, profile := range profiles {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		runErr := replayer(cmd.Context(), fleetArgs(cmd, profile), stdout, stderr)
+		run := decodeFleetRun(profile, stdout.Bytes(), stderr.Bytes(), runErr)
+		if run.Success {
+			result.Succeeded++
+		} else {
+			result.Failed++
+			if firstErr == nil {
+				firstErr = runErr
+				if firstErr == nil {
+					firstErr = errors.New("run reported failure")
+				}
+				firstErr = fmt.Errorf("profile %s: %w", profile, firstErr)
+			}
+		}
+		result.Runs = append(result.Runs, run)
+	}
+
+	if firstErr == nil {
+		return writeSuccess(cmd, runtime, commandName, result, nil, nil)
+	}
+	err = fmt.Errorf("%d of %d profiles failed; first failure: %w", result.Failed, len(profiles), firstErr)
+	errorInfo := &output.ErrorInfo{
+		Type:      "profile_fleet_failed",
+		Message:   err.Error(),
+		Retryable: false,
+	}
+	envelope, envErr := output.NewEnvelope(commandName, false, result, nil, nil, errorInfo)
+	if envErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, envErr)
+	}
+	if writeErr := writeEnvelope(cmd.ErrOrStderr(), runtime, envelope); writeErr != nil {
+		return fmt.Errorf("%w (secondary output error: %v)", err, writeErr)
+	}
+	return err
+}
+
+// fleetArgs rebuilds the invocation for one profile from the explicit flags,
+// forcing JSON so each run's envelope can be collected.
+func fleetArgs(cmd *cobra.Command, profile string) []string {
+	args := append([]string{}, strings.Fields(cmd.CommandPath())[1:]...)
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if !flag.Changed || len(flag.Annotations[FlagSourceAnnotation]) > 0 {
+			return
+		}
+		if _, skip := fleetFlagsDropped[flag.Name]; skip {
+			return
+		}
+		if values, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, value := range values.GetSlice() {
+				args = append(args, "--"+flag.Name+"="+value)
+			}
+			return
+		}
+		args = append(args, "--"+flag.Name+"="+flag.Value.String())
+	})
+	return append(args, "--profile="+profile, "--output=json")
+}
+
+func decodeFleetRun(profile string, stdout []byte, stderr []byte, runErr error) profileFleetRun {
+	run := profileFleetRun{Profile: profile, Success: runErr == nil}
+	var envelope map[string]any
+	for _, raw := range [][]byte{stdout, stderr} {
+		if json.Unmarshal(bytes.TrimSpace(raw), &envelope) == nil {
+			break
+		}
+		envelope = nil
+	}
+	if envelope == nil {
+		if runErr != nil {
+			run.Error = map[string]any{"type": "error", "message": runErr.Error()}
+		}
+		return run
+	}
+	if success, ok := envelope["success"].(bool); ok && !success {
+		run.Success = false
+	}
+	run.Data = envelope["data"]
+	if errorInfo, ok := envelope["error"].(map[string]any); ok {
+		run.Error = errorInfo
+	}
+	return run
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func useFleetProfiles(t *testing.T, labels map[string]map[string]string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := config.New()
+	for name, profileLabels := range labels {
+		if err := cfg.UpsertProfile(name, config.Profile{
+			TokenType:       "system_user",
+			AppID:           "app-1",
+			TokenRef:        "keychain://meta-marketing-cli/" + name + "/token",
+			AppSecretRef:    "keychain://meta-marketing-cli/" + name + "/app_secret",
+			AuthProvider:    "system_user",
+			AuthMode:        "both",
+			Scopes:          []string{"ads_read"},
+			IssuedAt:        "2026-01-01T00:00:00Z",
+			ExpiresAt:       "2027-01-01T00:00:00Z",
+			LastValidatedAt: "2026-01-01T00:00:00Z",
+			Labels:          profileLabels,
+		}); err != nil {
+			t.Fatalf("upsert profile %s: %v", name, err)
+		}
+	}
+	if err := config.Save(path, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	originalPath := fleetConfigPath
+	fleetConfigPath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { fleetConfigPath = originalPath })
+}
+
+func useFleetReplayer(t *testing.T, replayer Replayer) {
+	t.Helper()
+	original := currentFleetReplayer()
+	SetFleetReplayer(replayer)
+	t.Cleanup(func() { SetFleetReplayer(original) })
+}
+
+func TestAuthValidateRunsOncePerSelectedProfile(t *testing.T) {
+	useFleetProfiles(t, map[string]map[string]string{
+		"acme-staging":   {"env": "staging", "client": "acme"},
+		"globex-staging": {"env": "staging", "client": "globex"},
+		"acme-prod":      {"env": "prod", "client": "acme"},
+	})
+	var calls [][]string
+	useFleetReplayer(t, func(_ context.Context, args []string, stdout io.Writer, stderr io.Writer) error {
+		calls = append(calls, args)
+		profile := strings.TrimPrefix(args[len(args)-2], "--profile=")
+		if profile == "globex-staging" {
+			fmt.Fprint(stderr, `{"command":"meta auth validate","success":false,"error":{"type":"OAuthException","message":"token expired"}}`)
+			return errors.New("token expired")
+		}
+		fmt.Fprintf(stdout, `{"command":"meta auth validate","success":true,"data":{"profile":%q,"status":"ok"}}`, profile)
+		return nil
+	})
+
+	cmd := NewAuthCommand(testRuntime(""))
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"validate", "--profiles-selector", "env=staging", "--min-ttl", "1h"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 profiles failed") || !strings.Contains(err.Error(), "globex-staging") {
+		t.Fatalf("expected fleet failure naming the profile, got %v", err)
+	}
+
+	wantCalls := [][]string{
+		{"validate", "--min-ttl=1h0m0s", "--profile=acme-staging", "--output=json"},
+		{"validate", "--min-ttl=1h0m0s", "--profile=globex-staging", "--output=json"},
+	}
+	if fmt.Sprint(calls) != fmt.Sprint(wantCalls) {
+		t.Fatalf("unexpected runs:\n got %v\nwant %v", calls, wantCalls)
+	}
+
+	envelope := decodeEnvelope(t, stderr.Bytes())
+	if envelope["error"].(map[string]any)["type"] != "profile_fleet_failed" {
+		t.Fatalf("unexpected error: %v", envelope["error"])
+	}
+	data := envelope["data"].(map[string]any)
+	if data["succeeded"] != float64(1) || data["failed"] != float64(1) {
+		t.Fatalf("unexpected counts: %v", data)
+	}
+	runs := data["runs"].([]any)
+	first, second := runs[0].(map[string]any), runs[1].(map[string]any)
+	if first["profile"] != "acme-staging" || first["success"] != true || first["data"].(map[string]any)["status"] != "ok" {
+		t.Fatalf("unexpected first run: %v", first)
+	}
+	if second["success"] != false || second["error"].(map[string]any)["message"] != "token expired" {
+		t.Fatalf("unexpected second run: %v", second)
+	}
+}
+
+func TestProfilesSelectorSkipsFlagsBoundFromConfig(t *testing.T) {
+	useFleetProfiles(t, map[string]map[string]string{"acme-prod": {"env": "prod"}})
+	var calls [][]string
+	useFleetReplayer(t, func(_ context.Context, args []string, stdout io.Writer, _ io.Writer) error {
+		calls = append(calls, args)
+		fmt.Fprint(stdout, `{"command":"meta auth validate","success":true,"data":{"status":"ok"}}`)
+		return nil
+	})
+
+	var validate *cobra.Command
+	cmd := NewAuthCommand(testRuntime(""))
+	for _, child := range cmd.Commands() {
+		if child.Name() == "validate" {
+			validate = child
+		}
+	}
+	if validate == nil {
+		t.Fatal("validate command not found")
+	}
+	// A flag filled from META_REQUIRE_SCOPES is resolved again by each run.
+	if err := validate.Flags().Set("require-scopes", "ads_management"); err != nil {
+		t.Fatalf("set require-scopes: %v", err)
+	}
+	validate.Flags().Lookup("require-scopes").Annotations = map[string][]string{FlagSourceAnnotation: {"env META_REQUIRE_SCOPES"}}
+
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"validate", "--profiles-selector", "env=prod"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if fmt.Sprint(calls) != fmt.Sprint([][]string{{"validate", "--profile=acme-prod", "--output=json"}}) {
+		t.Fatalf("unexpected runs: %v", calls)
+	}
+
+	cmd.SetArgs([]string{"rotate", "--profiles-selector", "env=qa"})
+	cmd.SilenceErrors = true
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), `no profiles match selector "env=qa"`) {
+		t.Fatalf("expected no-match error, got %v", err)
+	}
+}
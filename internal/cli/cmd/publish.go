@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/crosspost"
+	"github.com/bilalbayram/metacli/internal/ig"
+	"github.com/bilalbayram/metacli/internal/page"
+	"github.com/spf13/cobra"
+)
+
+func NewPublishCommand(runtime Runtime) *cobra.Command {
+	publishCmd := &cobra.Command{
+		Use:   "publish",
+		Short: "Cross-surface publishing commands",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "publish")
+		},
+	}
+	publishCmd.AddCommand(newPublishCrosspostCommand(runtime))
+	return publishCmd
+}
+
+type publishCrosspostInput struct {
+	profile        string
+	igProfile      string
+	pageProfile    string
+	version        string
+	igUserID       string
+	pageID         string
+	mediaURL       string
+	caption        string
+	link           string
+	idempotencyKey string
+	strict         bool
+	lintConfig     string
+	quotaPolicy    string
+	quotaReserve   int
+}
+
+func newPublishCrosspostCommand(runtime Runtime) *cobra.Command {
+	var (
+		destinationsRaw []string
+		input           publishCrosspostInput
+	)
+
+	cmd := &cobra.Command{
+		Use:   "crosspost",
+		Short: "Publish one piece of content to several Instagram and Facebook Page surfaces",
+		Long:  "Publish one piece of content to several surfaces. Every destination is validated before anything is published; publishing then continues past individual failures and the result lists each destination's outcome.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			destinations, err := crosspost.ParseDestinations(destinationsRaw)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta publish crosspost", err)
+			}
+
+			lint, err := resolveIGCaptionLintConfig(input.lintConfig)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta publish crosspost", err)
+			}
+
+			targets := make([]crosspost.Target, 0, len(destinations))
+			for _, destination := range destinations {
+				switch destination.Platform {
+				case crosspost.PlatformIG:
+					targets = append(targets, newIGCrosspostTarget(runtime, destination, input, lint))
+				case crosspost.PlatformPage:
+					targets = append(targets, newPageCrosspostTarget(runtime, destination, input))
+				}
+			}
+
+			result, err := crosspost.Run(cmd.Context(), targets)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta publish crosspost", err)
+			}
+			return writeSuccess(cmd, runtime, "meta publish crosspost", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&destinationsRaw, "destinations", nil, "Comma-separated destinations: ig:feed|ig:reel|ig:story|page:feed")
+	cmd.Flags().StringVar(&input.profile, "profile", "", "Profile name used for every destination unless overridden")
+	cmd.Flags().StringVar(&input.igProfile, "ig-profile", "", "Profile for ig destinations (defaults to --profile)")
+	cmd.Flags().StringVar(&input.pageProfile, "page-profile", "", "Page token profile for page destinations (defaults to --profile)")
+	cmd.Flags().StringVar(&input.version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&input.igUserID, "ig-user-id", "", "Instagram user id (optional when profile has ig_user_id)")
+	cmd.Flags().StringVar(&input.pageID, "page-id", "", "Facebook Page ID (optional when profile has page_id)")
+	cmd.Flags().StringVar(&input.mediaURL, "media-url", "", "Public media URL (.mp4/.mov/.m4v are treated as video)")
+	cmd.Flags().StringVar(&input.caption, "caption", "", "Caption, used as the Page post message")
+	cmd.Flags().StringVar(&input.link, "link", "", "Link to attach to page:feed posts without media")
+	cmd.Flags().StringVar(&input.idempotencyKey, "idempotency-key", "", "Idempotency key for ig destinations (suffixed per surface)")
+	cmd.Flags().BoolVar(&input.strict, "strict", true, "Treat caption warnings as errors")
+	addIGCaptionLintConfigFlag(cmd, &input.lintConfig)
+	addIGPublishQuotaFlags(cmd, &input.quotaPolicy, &input.quotaReserve)
+	return cmd
+}
+
+func newIGCrosspostTarget(runtime Runtime, destination crosspost.Destination, input publishCrosspostInput, lint *ig.CaptionLintConfig) crosspost.Target {
+	var (
+		creds           *ProfileCredentials
+		resolvedVersion string
+		options         ig.FeedPublishOptions
+	)
+
+	return crosspost.Target{
+		Destination: destination,
+		Validate: func() error {
+			var err error
+			creds, resolvedVersion, err = resolveIGProfileAndVersion(runtime, firstNonEmpty(input.igProfile, input.profile), input.version)
+			if err != nil {
+				return ig.NormalizePublishPreflightError(err)
+			}
+			if err := ig.ValidatePublishCapability(creds.Name, creds.Profile); err != nil {
+				return err
+			}
+			binding, err := ig.ResolvePublishBinding(ig.PublishBindingOptions{
+				ProfileName:       creds.Name,
+				Profile:           creds.Profile,
+				RequestedIGUserID: input.igUserID,
+			})
+			if err != nil {
+				return err
+			}
+
+			mediaType := ig.InferCarouselChildMediaType(input.mediaURL)
+			switch destination.Surface {
+			case ig.PublishSurfaceReel:
+				mediaType = ig.MediaTypeReels
+			case ig.PublishSurfaceStory:
+				mediaType = ig.MediaTypeStories
+			}
+			mediaType, err = ig.ValidatePublishMediaTypeForSurface(destination.Surface, mediaType)
+			if err != nil {
+				return err
+			}
+
+			idempotencyKey := strings.TrimSpace(input.idempotencyKey)
+			if idempotencyKey != "" {
+				idempotencyKey += ":" + destination.Surface
+			}
+			options = ig.FeedPublishOptions{
+				IGUserID:       binding.IGUserID,
+				MediaURL:       input.mediaURL,
+				Caption:        input.caption,
+				MediaType:      mediaType,
+				StrictMode:     input.strict,
+				IdempotencyKey: idempotencyKey,
+			}
+			if captionValidation := ig.ValidateCaptionWithLint(options.Caption, options.StrictMode, lint); !captionValidation.Valid {
+				return errors.New(strings.Join(captionValidation.Errors, "; "))
+			}
+			if _, err := ig.NormalizePublishQuotaPolicy(input.quotaPolicy); err != nil {
+				return err
+			}
+			_, _, err = ig.BuildUploadRequest(resolvedVersion, creds.Token, creds.AppSecret, ig.MediaUploadOptions{
+				IGUserID:       options.IGUserID,
+				MediaURL:       options.MediaURL,
+				Caption:        options.Caption,
+				MediaType:      options.MediaType,
+				IdempotencyKey: options.IdempotencyKey,
+			})
+			return err
+		},
+		Publish: func(ctx context.Context) (any, error) {
+			service := ig.New(igNewGraphClient())
+			quotaPreflight, err := service.PublishQuotaPreflight(ctx, resolvedVersion, creds.Token, creds.AppSecret, ig.PublishQuotaPreflightOptions{
+				IGUserID: options.IGUserID,
+				Policy:   input.quotaPolicy,
+				Reserve:  input.quotaReserve,
+				Required: 1,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			var result *ig.FeedPublishResult
+			switch destination.Surface {
+			case ig.PublishSurfaceFeed:
+				result, err = service.PublishFeedImmediate(ctx, resolvedVersion, creds.Token, creds.AppSecret, options)
+			case ig.PublishSurfaceReel:
+				result, err = service.PublishReelImmediate(ctx, resolvedVersion, creds.Token, creds.AppSecret, options)
+			case ig.PublishSurfaceStory:
+				result, err = service.PublishStoryImmediate(ctx, resolvedVersion, creds.Token, creds.AppSecret, options)
+			default:
+				err = errors.New("unsupported ig crosspost surface")
+			}
+			if err != nil {
+				return nil, err
+			}
+			result.QuotaPreflight = quotaPreflight
+			return result, nil
+		},
+	}
+}
+
+func newPageCrosspostTarget(runtime Runtime, destination crosspost.Destination, input publishCrosspostInput) crosspost.Target {
+	var (
+		creds           *ProfileCredentials
+		resolvedVersion string
+		options         page.PostOptions
+	)
+
+	return crosspost.Target{
+		Destination: destination,
+		Validate: func() error {
+			var err error
+			var resolvedPageID string
+			creds, resolvedVersion, resolvedPageID, err = resolvePageProfile(runtime, firstNonEmpty(input.pageProfile, input.profile), input.version, input.pageID)
+			if err != nil {
+				return err
+			}
+
+			options = page.PostOptions{
+				PageID:  resolvedPageID,
+				Message: input.caption,
+				Link:    input.link,
+			}
+			if mediaURL := strings.TrimSpace(input.mediaURL); mediaURL != "" {
+				if ig.InferCarouselChildMediaType(mediaURL) == ig.MediaTypeVideo {
+					options.VideoURL = mediaURL
+				} else {
+					options.PhotoURL = mediaURL
+				}
+			}
+			_, _, err = page.BuildPostRequest(resolvedVersion, creds.Token, creds.AppSecret, options)
+			return err
+		},
+		Publish: func(ctx context.Context) (any, error) {
+			service := page.New(pageNewGraphClient())
+			return service.Post(ctx, resolvedVersion, creds.Token, creds.AppSecret, options)
+		},
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
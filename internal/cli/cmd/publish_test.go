@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func usePublishCrosspostIGDependencies(t *testing.T, stub *igPublishSequenceHTTPClient) {
+	t.Helper()
+	useIGDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{GraphVersion: "v25.0"},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+}
+
+func TestPublishCrosspostCommandPublishesToIGAndPage(t *testing.T) {
+	igStub := &igPublishSequenceHTTPClient{
+		t: t,
+		responses: []igPublishSequenceResponse{
+			{statusCode: http.StatusOK, response: `{"id":"creation_1"}`},
+			{statusCode: http.StatusOK, response: `{"id":"creation_1","status":"FINISHED","status_code":"FINISHED"}`},
+			{statusCode: http.StatusOK, response: `{"id":"media_1"}`},
+		},
+	}
+	usePublishCrosspostIGDependencies(t, igStub)
+	pageStub := &stubHTTPClient{t: t, statusCode: http.StatusOK, response: `{"id":"photo_1","post_id":"page_1_post_1"}`}
+	usePageDependencies(t, pageTestCredentials("page"), func() *graph.Client {
+		return graph.NewClient(pageStub, "https://graph.example.com")
+	})
+
+	output := &bytes.Buffer{}
+	cmd := NewPublishCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"crosspost",
+		"--destinations", "ig:feed,page:feed",
+		"--page-profile", "brand-page",
+		"--ig-user-id", "17841400008460056",
+		"--media-url", "https://cdn.example.com/image.jpg",
+		"--caption", "hello #meta",
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute publish crosspost: %v", err)
+	}
+
+	if len(igStub.calls) != 3 || igStub.quotaCalls != 1 {
+		t.Fatalf("expected three ig publish calls and one quota call, got %d/%d", len(igStub.calls), igStub.quotaCalls)
+	}
+	if !strings.Contains(pageStub.lastURL, "/v25.0/page_1/photos") || !strings.Contains(pageStub.lastBody, "caption=hello+%23meta") {
+		t.Fatalf("unexpected page request %s %s", pageStub.lastURL, pageStub.lastBody)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta publish crosspost")
+	data, _ := envelope["data"].(map[string]any)
+	if data["published"] != float64(2) || data["failed"] != float64(0) {
+		t.Fatalf("unexpected crosspost data %#v", data)
+	}
+}
+
+func TestPublishCrosspostCommandValidatesEveryDestinationBeforePublishing(t *testing.T) {
+	igStub := &igPublishSequenceHTTPClient{t: t}
+	usePublishCrosspostIGDependencies(t, igStub)
+	usePageDependencies(t, pageTestCredentials("system_user"), func() *graph.Client {
+		t.Fatal("page graph client should not be created when validation fails")
+		return nil
+	})
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewPublishCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{
+		"crosspost",
+		"--destinations", "ig:feed,page:feed",
+		"--ig-user-id", "17841400008460056",
+		"--media-url", "https://cdn.example.com/image.jpg",
+		"--caption", "hello",
+	})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected crosspost validation error")
+	}
+
+	if len(igStub.calls) != 0 || igStub.quotaCalls != 0 {
+		t.Fatalf("expected no ig graph calls, got %d/%d", len(igStub.calls), igStub.quotaCalls)
+	}
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	errorBody, _ := envelope["error"].(map[string]any)
+	if errorBody["type"] != "crosspost_validation_error" {
+		t.Fatalf("unexpected error %#v", errorBody)
+	}
+}
@@ -1,16 +1,31 @@
 package cmd
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/bilalbayram/metacli/internal/graph"
 	"github.com/bilalbayram/metacli/internal/ops"
 )
 
 const resourceLedgerPathEnv = "META_RESOURCE_LEDGER_PATH"
 
+var (
+	rollbackLoadProfileCredentials = loadProfileCredentials
+	rollbackNewGraphClient         = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+
+	// activeTrackedRunID tags resources tracked while a composed operation runs so
+	// --rollback-on-failure can undo exactly what that run created.
+	activeTrackedRunID string
+)
+
 type trackedResourceInput struct {
 	Command       string
 	ResourceKind  string
@@ -43,6 +58,12 @@ func persistTrackedResource(input trackedResourceInput) error {
 		SourceID:      strings.TrimSpace(input.SourceID),
 		Metadata:      normalizeTrackedResourceMetadata(input.Metadata),
 	}
+	if activeTrackedRunID != "" {
+		if entry.Metadata == nil {
+			entry.Metadata = map[string]string{}
+		}
+		entry.Metadata[ops.TrackedRunMetadataKey] = activeTrackedRunID
+	}
 	if _, err := ops.AppendResourceLedgerEntry(ledgerPath, entry); err != nil {
 		if !explicitPath && isResourceLedgerPathOrWriteError(err) {
 			return nil
@@ -52,6 +73,89 @@ func persistTrackedResource(input trackedResourceInput) error {
 	return nil
 }
 
+func newTrackedRunID(kind string) (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("generate %s run id: %w", kind, err)
+	}
+	return kind + "-" + hex.EncodeToString(suffix), nil
+}
+
+// beginTrackedRun tags every resource tracked until the returned func is called
+// with runID.
+func beginTrackedRun(runID string) func() {
+	previous := activeTrackedRunID
+	activeTrackedRunID = runID
+	return func() {
+		activeTrackedRunID = previous
+	}
+}
+
+// rollbackTrackedRun pauses or deletes, per its cleanup action, every resource the
+// ledger records for runID. It ignores the cancellation of ctx: a run that was
+// interrupted or timed out still needs its cleanup.
+func rollbackTrackedRun(ctx context.Context, runID string, version string) (ops.CleanupResult, error) {
+	ctx = context.WithoutCancel(ctx)
+	ledgerPath, err := resolveResourceLedgerPath("")
+	if err != nil {
+		return ops.CleanupResult{}, fmt.Errorf("resolve resource ledger path: %w", err)
+	}
+	return ops.RollbackTrackedRun(ctx, ledgerPath, ops.RollbackOptions{
+		RunID:   runID,
+		Version: version,
+		Credentials: func(profile string) (string, string, error) {
+			if strings.TrimSpace(profile) == "" {
+				return "", "", errors.New("tracked resource has no profile to roll back with")
+			}
+			creds, err := rollbackLoadProfileCredentials(profile)
+			if err != nil {
+				return "", "", err
+			}
+			return creds.Token, creds.AppSecret, nil
+		},
+		Executor: ops.NewGraphCleanupExecutor(rollbackNewGraphClient()),
+	})
+}
+
+func rolledBackResourceIDs(result ops.CleanupResult) map[string]struct{} {
+	ids := make(map[string]struct{}, len(result.Resources))
+	for _, resource := range result.Resources {
+		if resource.Classification == ops.CleanupClassificationApplied {
+			ids[resource.ResourceID] = struct{}{}
+		}
+	}
+	return ids
+}
+
+// withRollbackDiagnostics records the rollback outcome on a composed operation's
+// failure so the error envelope shows what was undone and what was left behind.
+func withRollbackDiagnostics(err error, result ops.CleanupResult, rollbackErr error) error {
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) {
+		if rollbackErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+		}
+		return fmt.Errorf("%w (rolled back %d resource(s), %d rollback failure(s))", err, result.Summary.Applied, result.Summary.Failed)
+	}
+	if apiErr.Diagnostics == nil {
+		apiErr.Diagnostics = map[string]any{}
+	}
+	if apiErr.Remediation == nil {
+		apiErr.Remediation = &graph.Remediation{Category: graph.RemediationCategoryUnknown}
+	}
+	if rollbackErr != nil {
+		apiErr.Diagnostics["rollback_error"] = rollbackErr.Error()
+		apiErr.Remediation.Actions = append(apiErr.Remediation.Actions, "Rollback did not run; inspect the resource ledger and run `meta ops cleanup` for resources created by this run.")
+		return apiErr
+	}
+	apiErr.Diagnostics["rollback"] = result
+	apiErr.Message = fmt.Sprintf("%s; rolled back %d resource(s), %d rollback failure(s)", apiErr.Message, result.Summary.Applied, result.Summary.Failed)
+	if result.Summary.Failed > 0 {
+		apiErr.Remediation.Actions = append(apiErr.Remediation.Actions, "Resources in diagnostics.rollback.resources with classification failed were left in place and stay in the resource ledger for `meta ops cleanup`.")
+	}
+	return apiErr
+}
+
 func resolveResourceLedgerPath(path string) (string, error) {
 	resolvedPath, _, err := resolveResourceLedgerPathForTracking(path)
 	return resolvedPath, err
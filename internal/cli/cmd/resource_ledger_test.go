@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/bilalbayram/metacli/internal/graph"
 	"github.com/bilalbayram/metacli/internal/ops"
 )
 
@@ -221,3 +222,16 @@ func configureTestResourceLedgerPath(t *testing.T) string {
 	t.Setenv(resourceLedgerPathEnv, ledgerPath)
 	return ledgerPath
 }
+
+func useRollbackDependencies(t *testing.T, loadFn func(string) (*ProfileCredentials, error), clientFn func() *graph.Client) {
+	t.Helper()
+	originalLoad := rollbackLoadProfileCredentials
+	originalClient := rollbackNewGraphClient
+	t.Cleanup(func() {
+		rollbackLoadProfileCredentials = originalLoad
+		rollbackNewGraphClient = originalClient
+	})
+
+	rollbackLoadProfileCredentials = loadFn
+	rollbackNewGraphClient = clientFn
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/bilalbayram/metacli/internal/replay"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Replayer runs args through a fresh root command, as if they had been typed
+// on the command line.
+type Replayer func(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) error
+
+var (
+	replayPlanPath = replay.DefaultPath
+	replayNow      = time.Now
+)
+
+func NewRetryCommand(runtime Runtime, replayer Replayer) *cobra.Command {
+	var (
+		last   bool
+		from   string
+		dryRun bool
+	)
+	cmd := &cobra.Command{
+		Use:   "retry",
+		Short: "Replay a command that failed with a retryable error",
+		Long: "Replay a command that failed with a retryable error (throttling, transient API failures, timeouts).\n" +
+			"Failed invocations are recorded without secret flag values; idempotency keys are replayed unchanged.\n" +
+			"Global flags passed to retry, such as --output or --timeout, override the recorded ones.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			path := strings.TrimSpace(from)
+			if last == (path != "") {
+				return writeCommandError(cmd, runtime, "meta retry", errors.New("exactly one of --last or --from is required"))
+			}
+			if last {
+				defaultPath, err := replayPlanPath()
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta retry", err)
+				}
+				path = defaultPath
+			}
+			plan, err := replay.Load(path)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta retry", err)
+			}
+			if dryRun {
+				return writeSuccess(cmd, runtime, "meta retry", plan, nil, nil)
+			}
+			if replayer == nil {
+				return writeCommandError(cmd, runtime, "meta retry", errors.New("retry is not available in this build"))
+			}
+
+			args := append(append([]string{}, plan.Args...), changedFlagArgs(cmd.InheritedFlags(), nil)...)
+			return replayer(cmd.Context(), args, cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+	cmd.Flags().BoolVar(&last, "last", false, "Replay the most recent failed command")
+	cmd.Flags().StringVar(&from, "from", "", "Replay the plan stored in this replay file")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the recorded plan without executing it")
+	return cmd
+}
+
+// recordReplayPlan stores the resolved invocation of a command that failed
+// with a retryable error. It reports whether the plan was written; recording
+// is best effort and never masks the original failure.
+func recordReplayPlan(cmd *cobra.Command, runtime Runtime, commandName string, errorInfo *output.ErrorInfo) bool {
+	if cmd == nil || cmd.Name() == "retry" {
+		return false
+	}
+	path := strings.Fields(cmd.CommandPath())
+	if len(path) < 2 {
+		return false
+	}
+
+	var omitted []string
+	args := append(append([]string{}, path[1:]...), cmd.Flags().Args()...)
+	args = append(args, changedFlagArgs(cmd.Flags(), func(name string) {
+		omitted = append(omitted, name)
+	})...)
+
+	plan := replay.NewPlan(commandName, args, replay.Failure{
+		Class:   errorInfo.Class,
+		Type:    errorInfo.Type,
+		Message: errorInfo.Message,
+	}, replayNow())
+	plan.Profile = runtime.ProfileName()
+	if plan.Profile == "" {
+		// Pin the default profile so a later default change does not redirect the replay.
+		plan.Profile = defaultProfileName()
+	}
+	if flag := cmd.Flags().Lookup("profile"); plan.Profile != "" && (flag == nil || !flag.Changed) {
+		plan.Args = append(plan.Args, "--profile="+plan.Profile)
+	}
+	if flag := cmd.Flags().Lookup("idempotency-key"); flag != nil && flag.Changed {
+		plan.IdempotencyKey = flag.Value.String()
+	}
+	plan.OmittedFlags = omitted
+
+	planPath, err := replayPlanPath()
+	if err != nil {
+		return false
+	}
+	return replay.Save(planPath, plan) == nil
+}
+
+func hintReplay(errorInfo *output.ErrorInfo) {
+	if errorInfo.Remediation == nil {
+		errorInfo.Remediation = &output.Remediation{Category: graph.RemediationCategoryUnknown}
+	}
+	errorInfo.Remediation.Actions = append(errorInfo.Remediation.Actions, "Once the cause has cleared, rerun this command with `meta retry --last`.")
+}
+
+// changedFlagArgs renders explicitly set flags back into --name=value form.
+// Secret flags are skipped and reported through omit.
+func changedFlagArgs(flags *pflag.FlagSet, omit func(name string)) []string {
+	args := []string{}
+	// VisitAll rather than Visit: InheritedFlags() copies flags without their
+	// set state, so Changed is the only reliable marker.
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if !flag.Changed {
+			return
+		}
+		if replay.IsSecretFlag(flag.Name) {
+			if omit != nil {
+				omit(flag.Name)
+			}
+			return
+		}
+		if values, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, value := range values.GetSlice() {
+				args = append(args, "--"+flag.Name+"="+value)
+			}
+			return
+		}
+		args = append(args, "--"+flag.Name+"="+flag.Value.String())
+	})
+	return args
+}
+
+func defaultProfileName() string {
+	configPath, err := config.DefaultPath()
+	if err != nil {
+		return ""
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return ""
+	}
+	return cfg.DefaultProfile
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/bilalbayram/metacli/internal/replay"
+	"github.com/spf13/cobra"
+)
+
+func useReplayPlanPath(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "last-failed.json")
+	original := replayPlanPath
+	t.Cleanup(func() {
+		replayPlanPath = original
+	})
+	replayPlanPath = func() (string, error) {
+		return path, nil
+	}
+	return path
+}
+
+func TestRetryableFailureRecordsReplayPlanAndRetryReplaysIt(t *testing.T) {
+	planPath := useReplayPlanPath(t)
+	schemaDir := writeCampaignSchemaPack(t)
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: 400,
+		response:   `{"error":{"message":"User request limit reached","type":"OAuthException","code":17}}`,
+	}
+	useCampaignDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{Domain: config.DefaultDomain, GraphVersion: config.DefaultGraphVersion},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewCampaignCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"list", "--account-id", "1234", "--schema-dir", schemaDir})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected rate limit error")
+	}
+	if !strings.Contains(errOutput.String(), "meta retry --last") {
+		t.Fatalf("expected retry hint in error envelope, got %s", errOutput.String())
+	}
+
+	plan, err := replay.Load(planPath)
+	if err != nil {
+		t.Fatalf("load replay plan: %v", err)
+	}
+	wantArgs := []string{"list", "--account-id=1234", "--schema-dir=" + schemaDir, "--profile=prod"}
+	if !reflect.DeepEqual(plan.Args, wantArgs) {
+		t.Fatalf("unexpected replay args %#v", plan.Args)
+	}
+	if plan.Profile != "prod" || plan.Failure.Class != "rate_limited" {
+		t.Fatalf("unexpected replay plan %#v", plan)
+	}
+
+	var replayed []string
+	retry := NewRetryCommand(testRuntime("prod"), func(_ context.Context, args []string, _ io.Writer, _ io.Writer) error {
+		replayed = args
+		return nil
+	})
+	parent := &cobra.Command{Use: "meta"}
+	var outputFormat string
+	parent.PersistentFlags().StringVar(&outputFormat, "output", "json", "")
+	parent.AddCommand(retry)
+	parent.SetOut(&bytes.Buffer{})
+	parent.SetErr(&bytes.Buffer{})
+	parent.SetArgs([]string{"retry", "--last", "--output", "table"})
+	if err := parent.Execute(); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if !reflect.DeepEqual(replayed, append(wantArgs, "--output=table")) {
+		t.Fatalf("unexpected replayed args %#v", replayed)
+	}
+}
+
+func TestRetryableFailureOmitsSecretFlags(t *testing.T) {
+	planPath := useReplayPlanPath(t)
+
+	cmd := &cobra.Command{Use: "verify"}
+	var appSecret, idempotencyKey string
+	cmd.Flags().StringVar(&appSecret, "app-secret", "", "")
+	cmd.Flags().StringVar(&idempotencyKey, "idempotency-key", "", "")
+	parent := &cobra.Command{Use: "meta"}
+	parent.AddCommand(cmd)
+	if err := cmd.ParseFlags([]string{"--app-secret", "shh", "--idempotency-key", "launch-1"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	info := &output.ErrorInfo{Class: output.ErrorClassTransient, Type: "error", Message: "boom", Retryable: true}
+	if !recordReplayPlan(cmd, testRuntime("prod"), "meta verify", info) {
+		t.Fatal("expected replay plan to be recorded")
+	}
+	plan, err := replay.Load(planPath)
+	if err != nil {
+		t.Fatalf("load replay plan: %v", err)
+	}
+	if strings.Contains(strings.Join(plan.Args, " "), "shh") {
+		t.Fatalf("secret leaked into replay args %#v", plan.Args)
+	}
+	if !reflect.DeepEqual(plan.OmittedFlags, []string{"app-secret"}) || plan.IdempotencyKey != "launch-1" {
+		t.Fatalf("unexpected replay plan %#v", plan)
+	}
+}
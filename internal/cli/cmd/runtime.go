@@ -1,9 +1,18 @@
 package cmd
 
+import "github.com/bilalbayram/metacli/internal/i18n"
+
 type Runtime struct {
 	Profile *string
 	Output  *string
+	Columns *string
+	Query   *string
+	Quiet   *bool
 	Debug   *bool
+	// NoProgress turns off progress bars and progress lines on stderr.
+	NoProgress *bool
+	// Lang is the language of prompts and remediation text.
+	Lang *string
 }
 
 func (r Runtime) ProfileName() string {
@@ -12,3 +21,16 @@ func (r Runtime) ProfileName() string {
 	}
 	return *r.Profile
 }
+
+// Language returns the selected message language, English when unset or
+// unsupported.
+func (r Runtime) Language() string {
+	if r.Lang == nil {
+		return i18n.English
+	}
+	lang, err := i18n.Parse(*r.Lang)
+	if err != nil {
+		return i18n.English
+	}
+	return lang
+}
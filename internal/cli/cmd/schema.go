@@ -12,6 +12,7 @@ func NewSchemaCommand(runtime Runtime) *cobra.Command {
 	}
 	schemaCmd.AddCommand(newSchemaListCommand(runtime))
 	schemaCmd.AddCommand(newSchemaSyncCommand(runtime))
+	schemaCmd.AddCommand(newSchemaContractsCommand(runtime))
 	return schemaCmd
 }
 
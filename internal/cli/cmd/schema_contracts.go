@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/contracts"
+	"github.com/bilalbayram/metacli/internal/ops"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/bilalbayram/metacli/internal/smoke"
+	"github.com/spf13/cobra"
+)
+
+type contractsExportResult struct {
+	OutDir          string   `json:"out_dir"`
+	ContractVersion string   `json:"contract_version"`
+	Files           []string `json:"files"`
+}
+
+// contractDefinitions are the types behind the published schemas. A test
+// regenerates every schema from them and compares it with the embedded copy.
+func contractDefinitions() []contracts.Contract {
+	return []contracts.Contract{
+		{
+			Name:        "envelope",
+			Title:       "meta command envelope",
+			Description: "The JSON object every meta command writes with --output json. data depends on the command; error is set when success is false.",
+			Value:       output.Envelope{},
+			Const:       map[string]any{"contract_version": output.ContractVersion},
+		},
+		{
+			Name:        "dry-run-plan",
+			Title:       "meta --dry-run plan",
+			Description: "The envelope data of a command run with the global --dry-run flag.",
+			Value:       dryRunPlan{},
+			Const:       map[string]any{"dry_run": true},
+		},
+		{
+			Name:        "ops-report",
+			Title:       "meta ops run report",
+			Description: "The report written by meta ops run, found at data.report of its envelope.",
+			Value:       ops.Report{},
+			Const:       map[string]any{"schema_version": ops.ReportSchemaVersion, "kind": "ops_report"},
+		},
+		{
+			Name:        "smoke-report",
+			Title:       "meta smoke run report",
+			Description: "The report written by meta smoke run, found at data.report of its envelope.",
+			Value:       smoke.Report{},
+			Const:       map[string]any{"schema_version": smoke.ReportSchemaVersion, "kind": smoke.ReportKind},
+		},
+	}
+}
+
+func newSchemaContractsCommand(runtime Runtime) *cobra.Command {
+	contractsCmd := &cobra.Command{
+		Use:   "contracts",
+		Short: "JSON Schemas of the CLI's output envelope and reports",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "schema contracts")
+		},
+	}
+	contractsCmd.AddCommand(newSchemaContractsExportCommand(runtime))
+	return contractsCmd
+}
+
+func newSchemaContractsExportCommand(runtime Runtime) *cobra.Command {
+	var (
+		outDir string
+		names  []string
+	)
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the JSON Schemas of the CLI's outputs",
+		Long: "Writes <name>.schema.json (JSON Schema 2020-12) for " + strings.Join(contracts.Names(), ", ") + " into --out-dir.\n" +
+			"Without --out-dir, the single schema named by --name is printed as is, for piping into a validator\n" +
+			"or code generator.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if len(names) == 0 {
+				names = contracts.Names()
+			}
+			schemas := make([][]byte, 0, len(names))
+			for _, name := range names {
+				data, err := contracts.Schema(strings.TrimSpace(name))
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta schema contracts export", err)
+				}
+				schemas = append(schemas, data)
+			}
+			if strings.TrimSpace(outDir) == "" {
+				if len(schemas) != 1 {
+					return writeCommandError(cmd, runtime, "meta schema contracts export", fmt.Errorf("--out-dir is required unless --name selects a single schema"))
+				}
+				_, err := cmd.OutOrStdout().Write(schemas[0])
+				return err
+			}
+			if err := os.MkdirAll(outDir, 0o755); err != nil {
+				return writeCommandError(cmd, runtime, "meta schema contracts export", fmt.Errorf("create contracts directory: %w", err))
+			}
+			result := contractsExportResult{OutDir: outDir, ContractVersion: output.ContractVersion, Files: []string{}}
+			for index, name := range names {
+				file := contracts.FileName(strings.TrimSpace(name))
+				if err := os.WriteFile(filepath.Join(outDir, file), schemas[index], 0o644); err != nil {
+					return writeCommandError(cmd, runtime, "meta schema contracts export", fmt.Errorf("write %s: %w", file, err))
+				}
+				result.Files = append(result.Files, file)
+			}
+			return writeSuccess(cmd, runtime, "meta schema contracts export", result, nil, nil)
+		},
+	}
+	cmd.Flags().StringVar(&outDir, "out-dir", "", "Directory the schemas are written to (created if missing)")
+	cmd.Flags().StringSliceVar(&names, "name", nil, "Schema to export (repeatable): "+strings.Join(contracts.Names(), "|")+"; default all")
+	return cmd
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/contracts"
+	"github.com/bilalbayram/metacli/internal/output"
+)
+
+// TestContractSchemasMatchTheirTypes regenerates every published schema from
+// its Go type. Run with META_UPDATE_CONTRACTS=1 to rewrite
+// internal/contracts/schemas after changing an output type.
+func TestContractSchemasMatchTheirTypes(t *testing.T) {
+	definitions := contractDefinitions()
+	names := make([]string, 0, len(definitions))
+	for _, definition := range definitions {
+		names = append(names, definition.Name)
+		generated, err := contracts.Generate(definition)
+		if err != nil {
+			t.Fatalf("generate %s: %v", definition.Name, err)
+		}
+		path := filepath.Join("..", "..", "contracts", "schemas", contracts.FileName(definition.Name))
+		if os.Getenv("META_UPDATE_CONTRACTS") == "1" {
+			if err := os.WriteFile(path, generated, 0o644); err != nil {
+				t.Fatalf("write %s: %v", path, err)
+			}
+			continue
+		}
+		published, err := contracts.Schema(definition.Name)
+		if err != nil {
+			t.Fatalf("published schema: %v", err)
+		}
+		if !bytes.Equal(generated, published) {
+			t.Fatalf("%s is stale; rerun with META_UPDATE_CONTRACTS=1 and commit the result", path)
+		}
+	}
+	sort.Strings(names)
+	if published := contracts.Names(); strings.Join(published, ",") != strings.Join(names, ",") {
+		t.Fatalf("published schemas %v do not match definitions %v", published, names)
+	}
+}
+
+func TestSchemaContractsExportWritesEverySchema(t *testing.T) {
+	t.Parallel()
+
+	outDir := filepath.Join(t.TempDir(), "contracts")
+	cmd := NewSchemaCommand(testRuntime(""))
+	stdout := &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"contracts", "export", "--out-dir", outDir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	envelope := decodeEnvelope(t, stdout.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta schema contracts export")
+	data := envelope["data"].(map[string]any)
+	if data["contract_version"] != output.ContractVersion {
+		t.Fatalf("unexpected contract version: %#v", data)
+	}
+	for _, want := range []string{"envelope.schema.json", "dry-run-plan.schema.json", "ops-report.schema.json", "smoke-report.schema.json"} {
+		if !containsAny(data["files"].([]any), want) {
+			t.Fatalf("expected %s in %v", want, data["files"])
+		}
+		raw, err := os.ReadFile(filepath.Join(outDir, want))
+		if err != nil {
+			t.Fatalf("read %s: %v", want, err)
+		}
+		schema := map[string]any{}
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			t.Fatalf("decode %s: %v", want, err)
+		}
+		if schema["$schema"] != "https://json-schema.org/draft/2020-12/schema" {
+			t.Fatalf("%s has no dialect: %v", want, schema["$schema"])
+		}
+	}
+}
+
+func TestSchemaContractsExportPrintsSingleSchema(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewSchemaCommand(testRuntime(""))
+	stdout := &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"contracts", "export", "--name", "envelope"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	schema := map[string]any{}
+	if err := json.Unmarshal(stdout.Bytes(), &schema); err != nil {
+		t.Fatalf("decode schema: %v", err)
+	}
+	version := schema["properties"].(map[string]any)["contract_version"].(map[string]any)
+	if version["const"] != output.ContractVersion {
+		t.Fatalf("contract_version is not pinned: %#v", version)
+	}
+
+	cmd = NewSchemaCommand(testRuntime(""))
+	stderr := &bytes.Buffer{}
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"contracts", "export"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected stdout export of every schema to fail")
+	}
+	if message := decodeEnvelope(t, stderr.Bytes())["error"].(map[string]any)["message"].(string); !strings.Contains(message, "--out-dir is required") {
+		t.Fatalf("unexpected error: %s", message)
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/scopes"
+	"github.com/spf13/cobra"
+)
+
+// EnforceScopePreflight blocks cmd before it runs when the selected profile's
+// recorded scopes do not cover the scopes the command needs, writing a
+// permission_denied error envelope that names them. Scopes that were never
+// checked against the token with debug_token only produce a warning, since
+// the token may hold more than the profile assumed. Commands without declared
+// scopes, and profiles that cannot be resolved, are left to fail on their own.
+func EnforceScopePreflight(cmd *cobra.Command, runtime Runtime) error {
+	command := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+	if len(scopes.Required(command)) == 0 {
+		return nil
+	}
+	configPath, err := config.DefaultPath()
+	if err != nil {
+		return nil
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil
+	}
+	profileName := ""
+	if flag := cmd.Flags().Lookup("profile"); flag != nil {
+		profileName = strings.TrimSpace(flag.Value.String())
+	}
+	profileName, profile, err := cfg.ResolveProfile(profileName)
+	if err != nil {
+		return nil
+	}
+	missing := scopes.Missing(command, profile.Scopes)
+	if len(missing) == 0 {
+		return nil
+	}
+	if strings.TrimSpace(profile.ScopesVerifiedAt) == "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: profile %s may be missing %s, required by %q; its scopes were never checked against the token (run `meta auth validate --profile %s` to record them)\n", profileName, requirementNames(missing), command, profileName)
+		return nil
+	}
+	return writeCommandError(cmd, runtime, cmd.CommandPath(), missingScopesError(command, profileName, profile.Scopes, missing))
+}
+
+func missingScopesError(command string, profile string, granted []string, missing []scopes.Requirement) error {
+	return &graph.APIError{
+		Type:      "missing_permission_scope",
+		Code:      403300,
+		Message:   fmt.Sprintf("profile %s is missing %s, required by %q", profile, requirementNames(missing), command),
+		Retryable: false,
+		Diagnostics: map[string]any{
+			"command":        command,
+			"profile":        profile,
+			"missing_scopes": missing,
+			"profile_scopes": granted,
+		},
+		Remediation: &graph.Remediation{
+			Category: graph.RemediationCategoryPermission,
+			Summary:  "The profile's token was not granted a scope this command needs.",
+			Actions: []string{
+				"Grant the missing scope to the app or system user and issue a new token.",
+				fmt.Sprintf("Record the new scopes on the profile with `meta auth validate --profile %s`.", profile),
+			},
+		},
+	}
+}
+
+func requirementNames(missing []scopes.Requirement) string {
+	names := make([]string, 0, len(missing))
+	for _, requirement := range missing {
+		names = append(names, requirement.String())
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// writeReaderProfile saves an ads_read-only "reader" profile under a fresh
+// HOME, its scopes verified at scopesVerifiedAt (empty for never).
+func writeReaderProfile(t *testing.T, scopesVerifiedAt string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cfg := config.New()
+	if err := cfg.UpsertProfile("reader", config.Profile{
+		TokenType:        "system_user",
+		AppID:            "app-1",
+		TokenRef:         "keychain://meta-marketing-cli/reader/token",
+		AppSecretRef:     "keychain://meta-marketing-cli/reader/app_secret",
+		AuthProvider:     "system_user",
+		AuthMode:         "both",
+		Scopes:           []string{"ads_read"},
+		IssuedAt:         "2026-01-01T00:00:00Z",
+		ExpiresAt:        "2027-01-01T00:00:00Z",
+		LastValidatedAt:  "2026-01-01T00:00:00Z",
+		ScopesVerifiedAt: scopesVerifiedAt,
+	}); err != nil {
+		t.Fatalf("upsert profile: %v", err)
+	}
+	if err := config.Save(filepath.Join(home, ".meta", "config.yaml"), cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+}
+
+// runPreflighted executes args under a root that runs the scope preflight for
+// the "reader" profile.
+func runPreflighted(t *testing.T, command *cobra.Command, args ...string) (*bytes.Buffer, error) {
+	t.Helper()
+	stderr := &bytes.Buffer{}
+	root := &cobra.Command{
+		Use:           "meta",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			return EnforceScopePreflight(cmd, testRuntime("reader"))
+		},
+	}
+	root.PersistentFlags().String("profile", "", "Auth profile name")
+	root.AddCommand(command)
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(stderr)
+	root.SetArgs(args)
+	return stderr, root.Execute()
+}
+
+func TestEnforceScopePreflightNamesMissingScope(t *testing.T) {
+	writeReaderProfile(t, "2026-01-01T00:00:00Z")
+
+	stderr, err := runPreflighted(t, NewCampaignCommand(testRuntime("reader")), "--profile", "reader", "campaign", "pause", "--campaign-id", "777")
+	if err == nil {
+		t.Fatal("expected campaign pause to be blocked")
+	}
+	errorBody := decodeEnvelope(t, stderr.Bytes())["error"].(map[string]any)
+	if errorBody["type"] != "missing_permission_scope" || errorBody["class"] != "permission_denied" {
+		t.Fatalf("unexpected error %#v", errorBody)
+	}
+	if errorBody["message"] != `profile reader is missing ads_management, required by "campaign pause"` {
+		t.Fatalf("unexpected message %q", errorBody["message"])
+	}
+}
+
+func TestEnforceScopePreflightOnlyWarnsForUnverifiedScopes(t *testing.T) {
+	writeReaderProfile(t, "")
+
+	ran := false
+	campaign := &cobra.Command{Use: "campaign"}
+	campaign.AddCommand(&cobra.Command{
+		Use: "pause",
+		RunE: func(*cobra.Command, []string) error {
+			ran = true
+			return nil
+		},
+	})
+
+	stderr, err := runPreflighted(t, campaign, "--profile", "reader", "campaign", "pause")
+	if err != nil {
+		t.Fatalf("expected unverified scopes not to block, got %v", err)
+	}
+	if !ran {
+		t.Fatal("expected campaign pause to run")
+	}
+	if !strings.Contains(stderr.String(), "warning: profile reader may be missing ads_management") ||
+		!strings.Contains(stderr.String(), "meta auth validate --profile reader") {
+		t.Fatalf("unexpected warning %q", stderr.String())
+	}
+}
@@ -0,0 +1,526 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	mcpProtocolVersion = "2024-11-05"
+
+	serveReadOnlyGuard     = "serve_read_only"
+	errorTypeServeReadOnly = "serve_read_only"
+	errorCodeServeReadOnly = 403400
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+// serveExcludedCommands are never exposed as tools: they prompt, run until
+// interrupted, replace the binary or would serve recursively. A tool call has
+// to end with exactly one envelope.
+var serveExcludedCommands = map[string]struct{}{
+	"serve":             {},
+	"tui":               {},
+	"init":              {},
+	"retry":             {},
+	"self-update":       {},
+	"completion":        {},
+	"help":              {},
+	"auth login":        {},
+	"auth setup":        {},
+	"config encrypt":    {},
+	"config decrypt":    {},
+	"debug bench":       {},
+	"ops metrics serve": {},
+	"webhook listen":    {},
+	"campaign watch":    {},
+	"adset watch":       {},
+	"ad watch":          {},
+}
+
+// serveHiddenFlags only shape how an envelope is rendered or where flags come
+// from; the server always reads JSON envelopes, so tools do not take them.
+var serveHiddenFlags = map[string]struct{}{
+	"output":      {},
+	"columns":     {},
+	"query":       {},
+	"quiet":       {},
+	"debug":       {},
+	"no-progress": {},
+	"wait-lock":   {},
+	"env-prefix":  {},
+	"help":        {},
+	"version":     {},
+}
+
+func NewServeCommand(runtime Runtime, runner Replayer, version string) *cobra.Command {
+	var (
+		readOnly       bool
+		allow          []string
+		allowOverrides bool
+	)
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve commands as MCP tools over JSON-RPC on stdin and stdout",
+		Long: "Serve the CLI's commands as Model Context Protocol tools, one newline-delimited JSON-RPC 2.0 message per line on stdin and stdout.\n" +
+			"Every tool call runs the command in-process with --output json under the same command policy, scope preflight,\n" +
+			"freeze windows, spend guardrails, anomaly guard, approval gate and audit log as the command line, and returns its envelope.\n" +
+			"Global flags given to serve, such as --profile or --dry-run, apply to every call and win over the call's arguments.\n" +
+			"Interactive and long-running commands (login, tui, init, listeners) are not exposed.\n" +
+			"Safety overrides (--break-glass, --override-anomaly, --approval-token, --force, --confirm-*, --public-key and --manifest-url)\n" +
+			"are not tool arguments unless serve runs with --allow-safety-overrides.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if runner == nil {
+				return writeCommandError(cmd, runtime, "meta serve", errors.New("serve is not available in this build"))
+			}
+			tools := serveTools(cmd.Root(), allow, allowOverrides)
+			if len(tools) == 0 {
+				return writeCommandError(cmd, runtime, "meta serve", fmt.Errorf("no commands match --allow %s", strings.Join(allow, ",")))
+			}
+			if readOnly {
+				graph.SetMutationGuard(serveReadOnlyGuard, readOnlyMutationGuard)
+				defer graph.SetMutationGuard(serveReadOnlyGuard, nil)
+			}
+			server := &mcpServer{
+				runner:   runner,
+				version:  version,
+				tools:    tools,
+				readOnly: readOnly,
+				pinned:   serveForwardedFlags(cmd.InheritedFlags()),
+			}
+			return server.serve(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+	cmd.Flags().BoolVar(&readOnly, "read-only", false, "Block every mutation that is not a --dry-run plan")
+	cmd.Flags().StringArrayVar(&allow, "allow", nil, "Only expose commands under this path, e.g. \"campaign\" or \"insights get\" (repeatable)")
+	cmd.Flags().BoolVar(&allowOverrides, "allow-safety-overrides", false, "Let tool calls pass --break-glass, --override-anomaly, --approval-token, --force and --confirm-* flags")
+	return cmd
+}
+
+// serveForwardedFlags returns the global flags set on serve that every call
+// inherits.
+func serveForwardedFlags(flags *pflag.FlagSet) []string {
+	args := []string{}
+	for _, arg := range changedFlagArgs(flags, nil) {
+		name, _, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if _, hidden := serveHiddenFlags[name]; hidden {
+			continue
+		}
+		args = append(args, arg)
+	}
+	return args
+}
+
+// readOnlyMutationGuard lets dry-run plans through: guards run before the
+// dry-run recorder, and a plan sends nothing.
+func readOnlyMutationGuard(ctx context.Context, mutation graph.Mutation) error {
+	if graph.DryRunFromContext(ctx) != nil {
+		return nil
+	}
+	return &graph.APIError{
+		Type:      errorTypeServeReadOnly,
+		Code:      errorCodeServeReadOnly,
+		Message:   fmt.Sprintf("%s %s blocked: meta serve runs with --read-only", mutation.Method, mutation.Path),
+		Retryable: false,
+		Remediation: &graph.Remediation{
+			Category: graph.RemediationCategoryPermission,
+			Summary:  "This server only allows reads and dry-run plans.",
+			Actions: []string{
+				"Call the tool with dry-run set to plan the mutation.",
+				"Restart meta serve without --read-only to allow mutations.",
+			},
+		},
+	}
+}
+
+type serveTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+
+	path  []string
+	flags map[string]*pflag.Flag
+	// overrides are the safety override flags the command takes but the
+	// server does not accept from callers.
+	overrides map[string]struct{}
+}
+
+// serveTools lists a tool for every runnable leaf command under root, named
+// after its path with underscores, e.g. campaign_list. Safety override flags
+// are only tool arguments when allowOverrides is set.
+func serveTools(root *cobra.Command, allow []string, allowOverrides bool) []*serveTool {
+	tools := []*serveTool{}
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, child := range cmd.Commands() {
+			if !child.IsAvailableCommand() {
+				continue
+			}
+			path := strings.Fields(child.CommandPath())[1:]
+			joined := strings.Join(path, " ")
+			if _, excluded := serveExcludedCommands[joined]; excluded {
+				continue
+			}
+			if child.HasAvailableSubCommands() {
+				walk(child)
+				continue
+			}
+			if child.Runnable() && serveAllowed(joined, allow) {
+				tools = append(tools, newServeTool(child, path, allowOverrides))
+			}
+		}
+	}
+	walk(root)
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+func serveAllowed(path string, allow []string) bool {
+	if len(allow) == 0 {
+		return true
+	}
+	for _, prefix := range allow {
+		prefix = strings.Join(strings.Fields(prefix), " ")
+		if path == prefix || strings.HasPrefix(path, prefix+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func newServeTool(cmd *cobra.Command, path []string, allowOverrides bool) *serveTool {
+	tool := &serveTool{
+		Name:        strings.Join(path, "_"),
+		Description: strings.TrimSpace(cmd.Short),
+		path:        path,
+		flags:       map[string]*pflag.Flag{},
+		overrides:   map[string]struct{}{},
+	}
+	properties := map[string]any{}
+	required := []string{}
+	addFlag := func(flag *pflag.Flag) {
+		if _, hidden := serveHiddenFlags[flag.Name]; hidden || flag.Hidden {
+			return
+		}
+		if _, seen := tool.flags[flag.Name]; seen {
+			return
+		}
+		if !allowOverrides && config.IsSafetyOverrideFlag(flag.Name) {
+			tool.overrides[flag.Name] = struct{}{}
+			return
+		}
+		tool.flags[flag.Name] = flag
+		property := flagSchema(flag)
+		property["description"] = flag.Usage
+		properties[flag.Name] = property
+		if len(flag.Annotations[cobra.BashCompOneRequiredFlag]) > 0 {
+			required = append(required, flag.Name)
+		}
+	}
+	cmd.LocalFlags().VisitAll(addFlag)
+	cmd.InheritedFlags().VisitAll(addFlag)
+	if fields := strings.Fields(cmd.Use); len(fields) > 1 {
+		properties["args"] = map[string]any{
+			"type":        "array",
+			"items":       map[string]any{"type": "string"},
+			"description": "Positional arguments: " + strings.Join(fields[1:], " "),
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	tool.InputSchema = schema
+	return tool
+}
+
+func flagSchema(flag *pflag.Flag) map[string]any {
+	kind := flag.Value.Type()
+	switch {
+	case kind == "bool":
+		return map[string]any{"type": "boolean"}
+	case kind == "count" || strings.HasPrefix(kind, "int") || strings.HasPrefix(kind, "uint"):
+		if strings.HasSuffix(kind, "Slice") {
+			return map[string]any{"type": "array", "items": map[string]any{"type": "integer"}}
+		}
+		return map[string]any{"type": "integer"}
+	case strings.HasPrefix(kind, "float"):
+		return map[string]any{"type": "number"}
+	case strings.HasSuffix(kind, "Slice") || strings.HasSuffix(kind, "Array"):
+		return map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+// argv builds the invocation for a call: the call's flags, then the flags
+// pinned by serve so they win, then JSON output and the positional arguments.
+func (t *serveTool) argv(arguments map[string]any, pinned []string) ([]string, error) {
+	args := append([]string{}, t.path...)
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var positional []string
+	for _, name := range names {
+		value := arguments[name]
+		if name == "args" {
+			values, err := stringValues(name, value)
+			if err != nil {
+				return nil, err
+			}
+			positional = values
+			continue
+		}
+		if _, ok := t.overrides[name]; ok {
+			return nil, fmt.Errorf("tool %s does not accept the safety override %q; restart meta serve with --allow-safety-overrides to allow it", t.Name, name)
+		}
+		if _, ok := t.flags[name]; !ok {
+			return nil, fmt.Errorf("tool %s has no argument %q", t.Name, name)
+		}
+		values, err := stringValues(name, value)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			args = append(args, "--"+name+"="+v)
+		}
+	}
+	args = append(args, pinned...)
+	args = append(args, "--output=json")
+	if len(positional) > 0 {
+		args = append(append(args, "--"), positional...)
+	}
+	return args, nil
+}
+
+func stringValues(name string, value any) ([]string, error) {
+	switch typed := value.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		values := make([]string, 0, len(typed))
+		for _, item := range typed {
+			scalar, err := scalarString(name, item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, scalar)
+		}
+		return values, nil
+	default:
+		scalar, err := scalarString(name, value)
+		if err != nil {
+			return nil, err
+		}
+		return []string{scalar}, nil
+	}
+}
+
+func scalarString(name string, value any) (string, error) {
+	switch typed := value.(type) {
+	case string:
+		return typed, nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), nil
+	case json.Number:
+		return typed.String(), nil
+	default:
+		return "", fmt.Errorf("argument %q must be a string, number, boolean or an array of them", name)
+	}
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type toolCallParams struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+type toolContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolCallResult struct {
+	Content           []toolContent `json:"content"`
+	StructuredContent any           `json:"structuredContent,omitempty"`
+	IsError           bool          `json:"isError"`
+}
+
+// mcpServer answers one request at a time: the guardrails a call installs are
+// process-wide, so calls never overlap.
+type mcpServer struct {
+	runner   Replayer
+	version  string
+	tools    []*serveTool
+	readOnly bool
+	pinned   []string
+}
+
+func (s *mcpServer) serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	encoder := json.NewEncoder(out)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if response := s.handle(ctx, line); response != nil {
+				if err := encoder.Encode(response); err != nil {
+					return err
+				}
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// handle answers one JSON-RPC message; notifications get no response.
+func (s *mcpServer) handle(ctx context.Context, line []byte) *rpcResponse {
+	var request rpcRequest
+	if err := json.Unmarshal(line, &request); err != nil {
+		return rpcFailure(json.RawMessage("null"), rpcParseError, "parse error: "+err.Error())
+	}
+	if request.JSONRPC != "2.0" || request.Method == "" {
+		return rpcFailure(request.ID, rpcInvalidRequest, "invalid request: jsonrpc must be \"2.0\" and method is required")
+	}
+	if len(request.ID) == 0 {
+		return nil
+	}
+
+	switch request.Method {
+	case "initialize":
+		return rpcSuccess(request.ID, map[string]any{
+			"protocolVersion": mcpProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{"listChanged": false}},
+			"serverInfo":      map[string]any{"name": "meta", "version": s.version},
+			"instructions":    s.instructions(),
+		})
+	case "ping":
+		return rpcSuccess(request.ID, map[string]any{})
+	case "tools/list":
+		return rpcSuccess(request.ID, map[string]any{"tools": s.tools})
+	case "tools/call":
+		var params toolCallParams
+		decoder := json.NewDecoder(bytes.NewReader(request.Params))
+		decoder.UseNumber()
+		if err := decoder.Decode(&params); err != nil {
+			return rpcFailure(request.ID, rpcInvalidParams, "invalid tools/call params: "+err.Error())
+		}
+		tool := s.tool(params.Name)
+		if tool == nil {
+			return rpcFailure(request.ID, rpcInvalidParams, fmt.Sprintf("unknown tool %q", params.Name))
+		}
+		args, err := tool.argv(params.Arguments, s.pinned)
+		if err != nil {
+			return rpcFailure(request.ID, rpcInvalidParams, err.Error())
+		}
+		return rpcSuccess(request.ID, s.call(ctx, args))
+	default:
+		return rpcFailure(request.ID, rpcMethodNotFound, fmt.Sprintf("method %q is not supported", request.Method))
+	}
+}
+
+func (s *mcpServer) instructions() string {
+	text := "Each tool runs one meta command and returns its JSON envelope (contract_version, success, data, error). " +
+		"Set dry-run to plan a mutation without sending it."
+	if s.readOnly {
+		text += " This server is read-only: mutations other than dry-run plans are blocked."
+	}
+	return text
+}
+
+func (s *mcpServer) tool(name string) *serveTool {
+	for _, tool := range s.tools {
+		if tool.Name == name {
+			return tool
+		}
+	}
+	return nil
+}
+
+// call runs args and returns the envelope the command wrote: success
+// envelopes go to stdout, error envelopes to stderr.
+func (s *mcpServer) call(ctx context.Context, args []string) toolCallResult {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	runErr := s.runner(ctx, args, stdout, stderr)
+	raw := bytes.TrimSpace(stdout.Bytes())
+	if runErr != nil || len(raw) == 0 {
+		if errRaw := bytes.TrimSpace(stderr.Bytes()); json.Valid(errRaw) {
+			raw = errRaw
+		}
+	}
+
+	result := toolCallResult{IsError: runErr != nil}
+	var envelope map[string]any
+	if json.Unmarshal(raw, &envelope) == nil {
+		result.StructuredContent = envelope
+		if success, ok := envelope["success"].(bool); ok && !success {
+			result.IsError = true
+		}
+	} else if runErr != nil {
+		raw = []byte(strings.TrimSpace(runErr.Error() + "\n" + stderr.String()))
+	}
+	result.Content = []toolContent{{Type: "text", Text: string(raw)}}
+	return result
+}
+
+func rpcSuccess(id json.RawMessage, result any) *rpcResponse {
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+func rpcFailure(id json.RawMessage, code int, message string) *rpcResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+type serveRun struct {
+	args []string
+}
+
+func newServeTestRoot(runner Replayer) *cobra.Command {
+	root := &cobra.Command{Use: "meta", SilenceErrors: true, SilenceUsage: true}
+	root.PersistentFlags().String("profile", "", "Auth profile name")
+	root.PersistentFlags().String("output", "json", "Output format")
+	root.PersistentFlags().Bool("dry-run", false, "Plan every mutation")
+	root.PersistentFlags().String("break-glass", "", "Override freeze windows")
+	root.PersistentFlags().StringArray("approval-token", nil, "Approval token")
+
+	campaign := &cobra.Command{Use: "campaign", Short: "Manage campaigns"}
+	list := &cobra.Command{Use: "list", Short: "List campaigns", RunE: func(*cobra.Command, []string) error { return nil }}
+	list.Flags().String("account-id", "", "Ad account id")
+	list.Flags().Int("limit", 0, "Page size")
+	list.Flags().StringSlice("fields", nil, "Fields to read")
+	_ = list.MarkFlagRequired("account-id")
+	pause := &cobra.Command{Use: "pause <campaign-id>", Short: "Pause a campaign", RunE: func(*cobra.Command, []string) error { return nil }}
+	pause.Flags().Bool("confirm-budget-change", false, "Acknowledge budget fields")
+	campaign.AddCommand(list, pause)
+	tui := &cobra.Command{Use: "tui", Short: "Interactive browser", RunE: func(*cobra.Command, []string) error { return nil }}
+
+	root.AddCommand(campaign, tui, NewServeCommand(testRuntime(""), runner, "1.2.3"))
+	return root
+}
+
+func runServe(t *testing.T, runner Replayer, args []string, requests ...string) []map[string]any {
+	t.Helper()
+	root := newServeTestRoot(runner)
+	stdout := &bytes.Buffer{}
+	root.SetArgs(append([]string{"serve"}, args...))
+	root.SetIn(strings.NewReader(strings.Join(requests, "\n") + "\n"))
+	root.SetOut(stdout)
+	root.SetErr(io.Discard)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	responses := []map[string]any{}
+	decoder := json.NewDecoder(stdout)
+	for decoder.More() {
+		response := map[string]any{}
+		if err := decoder.Decode(&response); err != nil {
+			t.Fatalf("decode response: %v\n%s", err, stdout.String())
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+func TestServeListsToolsAndRunsCallsWithPinnedFlags(t *testing.T) {
+	runs := []serveRun{}
+	runner := func(_ context.Context, args []string, stdout io.Writer, _ io.Writer) error {
+		runs = append(runs, serveRun{args: args})
+		fmt.Fprintf(stdout, `{"contract_version":"1.0","command":"meta campaign list","success":true,"data":[{"id":"1"}]}`+"\n")
+		return nil
+	}
+	responses := runServe(t, runner, []string{"--profile", "prod", "--output", "table"},
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"campaign_list","arguments":{"account-id":"act_1","limit":25,"fields":["id","name"],"profile":"staging"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"campaign_pause","arguments":{"args":["120"],"dry-run":true}}}`,
+	)
+	if len(responses) != 4 {
+		t.Fatalf("expected 4 responses (the notification gets none), got %d: %v", len(responses), responses)
+	}
+
+	initialize := responses[0]["result"].(map[string]any)
+	if initialize["protocolVersion"] != mcpProtocolVersion {
+		t.Fatalf("unexpected protocol version %v", initialize["protocolVersion"])
+	}
+	if info := initialize["serverInfo"].(map[string]any); info["name"] != "meta" || info["version"] != "1.2.3" {
+		t.Fatalf("unexpected server info %v", info)
+	}
+
+	tools := responses[1]["result"].(map[string]any)["tools"].([]any)
+	names := []string{}
+	for _, tool := range tools {
+		names = append(names, tool.(map[string]any)["name"].(string))
+	}
+	if !reflect.DeepEqual(names, []string{"campaign_list", "campaign_pause"}) {
+		t.Fatalf("unexpected tools %v", names)
+	}
+	schema := tools[0].(map[string]any)["inputSchema"].(map[string]any)
+	properties := schema["properties"].(map[string]any)
+	if _, ok := properties["output"]; ok {
+		t.Fatalf("output flag should not be a tool argument: %v", properties)
+	}
+	if properties["limit"].(map[string]any)["type"] != "integer" || properties["fields"].(map[string]any)["type"] != "array" || properties["dry-run"].(map[string]any)["type"] != "boolean" {
+		t.Fatalf("unexpected argument types %v", properties)
+	}
+	if !reflect.DeepEqual(schema["required"], []any{"account-id"}) {
+		t.Fatalf("unexpected required arguments %v", schema["required"])
+	}
+	if _, ok := tools[1].(map[string]any)["inputSchema"].(map[string]any)["properties"].(map[string]any)["args"]; !ok {
+		t.Fatal("expected positional args on campaign_pause")
+	}
+
+	call := responses[2]["result"].(map[string]any)
+	if call["isError"] != false {
+		t.Fatalf("unexpected call result %v", call)
+	}
+	if data := call["structuredContent"].(map[string]any)["data"].([]any); len(data) != 1 {
+		t.Fatalf("unexpected structured content %v", call["structuredContent"])
+	}
+	wantList := []string{"campaign", "list", "--account-id=act_1", "--fields=id", "--fields=name", "--limit=25", "--profile=staging", "--profile=prod", "--output=json"}
+	if !reflect.DeepEqual(runs[0].args, wantList) {
+		t.Fatalf("unexpected list argv %#v", runs[0].args)
+	}
+	wantPause := []string{"campaign", "pause", "--dry-run=true", "--profile=prod", "--output=json", "--", "120"}
+	if !reflect.DeepEqual(runs[1].args, wantPause) {
+		t.Fatalf("unexpected pause argv %#v", runs[1].args)
+	}
+}
+
+func TestServeReturnsErrorEnvelopesAsToolErrors(t *testing.T) {
+	runner := func(_ context.Context, _ []string, _ io.Writer, stderr io.Writer) error {
+		fmt.Fprintln(stderr, `{"contract_version":"1.0","command":"meta campaign pause","success":false,"error":{"type":"freeze_window_gate","message":"blocked"}}`)
+		return errors.New("blocked")
+	}
+	responses := runServe(t, runner, nil,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"campaign_pause","arguments":{"args":["120"]}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"tui"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"campaign_list","arguments":{"account":"x"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"resources/list"}`,
+		`not json`,
+	)
+	if len(responses) != 5 {
+		t.Fatalf("expected 5 responses, got %d", len(responses))
+	}
+
+	call := responses[0]["result"].(map[string]any)
+	if call["isError"] != true {
+		t.Fatalf("expected tool error, got %v", call)
+	}
+	envelope := call["structuredContent"].(map[string]any)
+	if envelope["error"].(map[string]any)["type"] != "freeze_window_gate" {
+		t.Fatalf("unexpected error envelope %v", envelope)
+	}
+
+	for index, wantCode := range []float64{rpcInvalidParams, rpcInvalidParams, rpcMethodNotFound, rpcParseError} {
+		rpcErr, ok := responses[index+1]["error"].(map[string]any)
+		if !ok || rpcErr["code"] != wantCode {
+			t.Fatalf("response %d: expected error code %v, got %v", index+1, wantCode, responses[index+1])
+		}
+	}
+}
+
+func TestServeAllowLimitsTools(t *testing.T) {
+	responses := runServe(t, func(context.Context, []string, io.Writer, io.Writer) error { return nil }, []string{"--allow", "campaign pause"},
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+	)
+	tools := responses[0]["result"].(map[string]any)["tools"].([]any)
+	if len(tools) != 1 || tools[0].(map[string]any)["name"] != "campaign_pause" {
+		t.Fatalf("unexpected tools %v", tools)
+	}
+
+	root := newServeTestRoot(func(context.Context, []string, io.Writer, io.Writer) error { return nil })
+	stderr := &bytes.Buffer{}
+	root.SetArgs([]string{"serve", "--allow", "adset"})
+	root.SetIn(strings.NewReader(""))
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(stderr)
+	if err := root.Execute(); err == nil {
+		t.Fatal("expected an error when --allow matches no command")
+	}
+	if !strings.Contains(stderr.String(), "no commands match --allow adset") {
+		t.Fatalf("unexpected error output %s", stderr.String())
+	}
+}
+
+func TestServeKeepsSafetyOverridesFromToolCallsUnlessAllowed(t *testing.T) {
+	runs := [][]string{}
+	runner := func(_ context.Context, args []string, stdout io.Writer, _ io.Writer) error {
+		runs = append(runs, args)
+		fmt.Fprintln(stdout, `{"contract_version":"1.0","command":"meta campaign pause","success":true,"data":{}}`)
+		return nil
+	}
+	call := `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"campaign_pause","arguments":{"args":["120"],"confirm-budget-change":true}}}`
+	overrides := []string{"approval-token", "break-glass", "confirm-budget-change"}
+
+	responses := runServe(t, runner, nil,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		call,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"campaign_pause","arguments":{"args":["120"],"break-glass":"incident"}}}`,
+	)
+	for _, tool := range responses[0]["result"].(map[string]any)["tools"].([]any) {
+		properties := tool.(map[string]any)["inputSchema"].(map[string]any)["properties"].(map[string]any)
+		for _, name := range overrides {
+			if _, ok := properties[name]; ok {
+				t.Fatalf("tool %v exposes safety override %q", tool.(map[string]any)["name"], name)
+			}
+		}
+	}
+	for _, response := range responses[1:] {
+		rpcErr, ok := response["error"].(map[string]any)
+		if !ok || rpcErr["code"] != float64(rpcInvalidParams) || !strings.Contains(rpcErr["message"].(string), "--allow-safety-overrides") {
+			t.Fatalf("expected the safety override to be rejected, got %v", response)
+		}
+	}
+	if len(runs) != 0 {
+		t.Fatalf("rejected calls should not run, got %v", runs)
+	}
+
+	responses = runServe(t, runner, []string{"--allow-safety-overrides"},
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		call,
+	)
+	tools := responses[0]["result"].(map[string]any)["tools"].([]any)
+	properties := tools[1].(map[string]any)["inputSchema"].(map[string]any)["properties"].(map[string]any)
+	for _, name := range overrides {
+		if _, ok := properties[name]; !ok {
+			t.Fatalf("expected %q with --allow-safety-overrides, got %v", name, properties)
+		}
+	}
+	if responses[1]["result"].(map[string]any)["isError"] != false {
+		t.Fatalf("unexpected call result %v", responses[1])
+	}
+	if len(runs) != 1 || !slices.Contains(runs[0], "--confirm-budget-change=true") {
+		t.Fatalf("unexpected runs %v", runs)
+	}
+}
+
+func TestServeReadOnlyGuardAllowsOnlyDryRunPlans(t *testing.T) {
+	mutation := graph.Mutation{Method: "POST", Path: "120"}
+	err := readOnlyMutationGuard(context.Background(), mutation)
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != errorTypeServeReadOnly {
+		t.Fatalf("expected read-only error, got %v", err)
+	}
+	if err := readOnlyMutationGuard(graph.WithDryRun(context.Background(), &graph.DryRun{}), mutation); err != nil {
+		t.Fatalf("dry-run plan was blocked: %v", err)
+	}
+}
@@ -3,15 +3,21 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/bilalbayram/metacli/internal/config"
 	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/ops"
 	"github.com/bilalbayram/metacli/internal/smoke"
 	"github.com/spf13/cobra"
 )
 
+const smokeHistoryDirEnv = "META_SMOKE_HISTORY_DIR"
+
 var (
+	smokeNow                    = time.Now
 	smokeLoadProfileCredentials = loadProfileCredentials
 	smokeNewGraphClient         = func() *graph.Client {
 		return graph.NewClient(nil, "")
@@ -29,6 +35,7 @@ func NewSmokeCommand(runtime Runtime) *cobra.Command {
 		SilenceUsage:  true,
 	}
 	smokeCmd.AddCommand(newSmokeRunCommand(runtime))
+	smokeCmd.AddCommand(newSmokeDiffCommand(runtime))
 	return smokeCmd
 }
 
@@ -37,8 +44,23 @@ func newSmokeRunCommand(runtime Runtime) *cobra.Command {
 		profile        string
 		version        string
 		accountID      string
+		accountName    string
+		accountIDs     []string
+		concurrency    int
 		catalogID      string
+		pageID         string
+		whatsAppID     string
+		requireSandbox bool
 		optionalPolicy string
+		cleanupPolicy  string
+		scenarioPath   string
+		exitPolicyPath string
+		failOn         string
+		checkPolicies  []string
+		historyDir     string
+		reportFormat   string
+		reportFile     string
+		selector       string
 	)
 
 	cmd := &cobra.Command{
@@ -46,28 +68,86 @@ func newSmokeRunCommand(runtime Runtime) *cobra.Command {
 		Short: "Run smoke runner v2 with account-aware reporting",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			if err := ensureSmokeOutput(runtime); err != nil {
+			if err := ensureSmokeOutput(runtime, smoke.CommandRun); err != nil {
 				return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeInput, err))
 			}
+			if strings.TrimSpace(selector) != "" {
+				return runProfileFleet(cmd, runtime, smoke.CommandRun, selector)
+			}
 			if err := smoke.ValidateOptionalPolicy(optionalPolicy); err != nil {
 				return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeInput, err))
 			}
+			if err := smoke.ValidateCleanupPolicy(cleanupPolicy); err != nil {
+				return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeInput, err))
+			}
+			if err := ops.ValidateReportFormat(reportFormat, reportFile); err != nil {
+				return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeInput, err))
+			}
+
+			var accounts []string
+			switch {
+			case (strings.TrimSpace(accountID) != "" || strings.TrimSpace(accountName) != "") && len(accountIDs) > 0:
+				return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeInput, errors.New("use either --account-id/--account or --accounts, not both")))
+			case len(accountIDs) > 0:
+				parsed, err := smoke.ParseAccountIDs(accountIDs)
+				if err != nil {
+					return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeInput, err))
+				}
+				if concurrency < 1 {
+					return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeInput, fmt.Errorf("%w: --concurrency must be >= 1, got %d", smoke.ErrInvalidConcurrency, concurrency)))
+				}
+				accounts = parsed
+			case strings.TrimSpace(accountID) == "" && strings.TrimSpace(accountName) == "":
+				return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeInput, fmt.Errorf("%w (--account-id, --account, or --accounts)", smoke.ErrAccountIDMissing)))
+			}
+
+			var scenario *smoke.Scenario
+			if strings.TrimSpace(scenarioPath) != "" {
+				loaded, err := smoke.LoadScenario(scenarioPath)
+				if err != nil {
+					return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeInput, err))
+				}
+				scenario = loaded
+			}
+
+			exitPolicy, err := resolveExitPolicy(exitPolicyPath, failOn, checkPolicies)
+			if err != nil {
+				return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeInput, err))
+			}
 
 			creds, resolvedVersion, err := resolveSmokeProfileAndVersion(runtime, profile, version)
 			if err != nil {
 				return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeInput, err))
 			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeInput, err))
+			}
 
-			runner := smokeNewRunner(smokeNewGraphClient())
-			result, err := runner.Run(cmd.Context(), smoke.RunInput{
-				ProfileName:    creds.Name,
-				Version:        resolvedVersion,
-				AccountID:      accountID,
-				Token:          creds.Token,
-				AppSecret:      creds.AppSecret,
-				OptionalPolicy: optionalPolicy,
-				CatalogID:      catalogID,
-			})
+			client := smokeNewGraphClient()
+			if len(accounts) > 0 {
+				client = governedClient(client, concurrency)
+			}
+			runner := smokeNewRunner(client)
+			runInput := smoke.RunInput{
+				ProfileName:           creds.Name,
+				Version:               resolvedVersion,
+				AccountID:             accountID,
+				Token:                 creds.Token,
+				AppSecret:             creds.AppSecret,
+				OptionalPolicy:        optionalPolicy,
+				CatalogID:             catalogID,
+				PageID:                pageID,
+				WhatsAppPhoneNumberID: whatsAppID,
+				RequireSandbox:        requireSandbox,
+				CleanupPolicy:         cleanupPolicy,
+				Scenario:              scenario,
+				ExitPolicy:            &exitPolicy,
+			}
+			if len(accounts) > 0 {
+				return runSmokeAccounts(cmd, runtime, runner, runInput, accounts, concurrency, historyDir, reportFormat, reportFile, resolvedVersion)
+			}
+
+			result, err := runner.Run(cmd.Context(), runInput)
 			if err != nil {
 				code := smoke.ExitCodeRuntime
 				switch {
@@ -79,41 +159,47 @@ func newSmokeRunCommand(runtime Runtime) *cobra.Command {
 					code = smoke.ExitCodeInput
 				case errors.Is(err, smoke.ErrInvalidOptionalPolicy):
 					code = smoke.ExitCodeInput
+				case errors.Is(err, smoke.ErrInvalidCleanupPolicy):
+					code = smoke.ExitCodeInput
+				case errors.Is(err, smoke.ErrInvalidScenario):
+					code = smoke.ExitCodeInput
+				case errors.Is(err, ops.ErrInvalidExitPolicy):
+					code = smoke.ExitCodeInput
 				}
 				return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(code, err))
 			}
 
-			for _, resource := range result.Report.CreatedResources {
-				if err := persistTrackedResource(trackedResourceInput{
-					Command:       resource.Command,
-					ResourceKind:  resource.ResourceKind,
-					ResourceID:    resource.ResourceID,
-					CleanupAction: resource.CleanupAction,
-					Profile:       creds.Name,
-					GraphVersion:  resolvedVersion,
-					AccountID:     resource.AccountID,
-					Metadata: map[string]string{
-						"step": resource.Step,
-					},
-				}); err != nil {
-					return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeRuntime, err))
-				}
+			if err := persistSmokeResources(result.Report, creds.Name, resolvedVersion); err != nil {
+				return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeRuntime, err))
+			}
+
+			historyPath, err := persistSmokeHistory(historyDir, creds.Name, result.Report)
+			if err != nil {
+				return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeRuntime, err))
+			}
+			result.HistoryPath = historyPath
+			if err := writeSmokeReportFile(reportFormat, reportFile, smoke.JUnitFromReport(result.Report)); err != nil {
+				return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeRuntime, err))
 			}
 
 			envelope := smoke.NewSuccessEnvelope(smoke.CommandRun, result)
 			if code := smoke.RunExitCode(result.Report); code != smoke.ExitCodeSuccess {
 				envelope.Success = false
 				envelope.ExitCode = code
-				switch smoke.RunOutcomeForReport(result.Report) {
-				case smoke.RunOutcomeWarning:
+				warnings, blocking := result.Report.Summary.Warnings, result.Report.Summary.Blocking
+				if result.Report.ExitPolicy != nil {
+					warnings, blocking = result.Report.ExitPolicy.Warnings, result.Report.ExitPolicy.Blocking
+				}
+				switch code {
+				case smoke.ExitCodeWarning:
 					envelope.Error = &smoke.ErrorInfo{
 						Type:    "warning_findings",
-						Message: fmt.Sprintf("smoke run reported %d warning finding(s)", result.Report.Summary.Warnings),
+						Message: fmt.Sprintf("smoke run reported %d warning finding(s)", warnings),
 					}
-				case smoke.RunOutcomeBlocking:
+				case smoke.ExitCodePolicy:
 					envelope.Error = &smoke.ErrorInfo{
 						Type:    "blocking_findings",
-						Message: fmt.Sprintf("smoke run reported %d blocking finding(s)", result.Report.Summary.Blocking),
+						Message: fmt.Sprintf("smoke run reported %d blocking finding(s)", blocking),
 					}
 				default:
 					envelope.Error = &smoke.ErrorInfo{
@@ -136,20 +222,214 @@ func newSmokeRunCommand(runtime Runtime) *cobra.Command {
 	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
 	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
 	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
+	cmd.Flags().StringSliceVar(&accountIDs, "accounts", nil, "Comma-separated ad account ids to smoke concurrently (replaces --account-id)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "Maximum accounts run in parallel with --accounts")
 	cmd.Flags().StringVar(&catalogID, "catalog-id", "", "Catalog id for optional catalog smoke step")
+	cmd.Flags().StringVar(&pageID, "page-id", "", "Facebook page id for optional creative and ad smoke steps")
+	cmd.Flags().StringVar(&whatsAppID, "whatsapp-phone-number-id", "", "WhatsApp Business phone number id for the optional messaging probe")
+	cmd.Flags().BoolVar(&requireSandbox, "require-sandbox", false, "Refuse mutation steps unless the account shows sandbox indicators")
 	cmd.Flags().StringVar(&optionalPolicy, "optional-policy", smoke.OptionalPolicyStrict, "Policy for optional smoke modules: strict|skip")
+	cmd.Flags().StringVar(&cleanupPolicy, "cleanup", smoke.CleanupPolicyNever, "Teardown of created resources at the end of the run: always|on-success|never")
+	cmd.Flags().StringVar(&scenarioPath, "scenario-file", "", "YAML scenario file replacing the built-in smoke steps")
+	cmd.Flags().StringVar(&historyDir, "history-dir", "", "Root directory for persisted smoke reports keyed by profile/account (default: ~/.meta/smoke/reports)")
+	addExitPolicyFlags(cmd, &exitPolicyPath, &failOn, &checkPolicies)
+	addReportFormatFlags(cmd, &reportFormat, &reportFile)
+	addProfilesSelectorFlag(cmd, &selector)
+	return cmd
+}
+
+func runSmokeAccounts(cmd *cobra.Command, runtime Runtime, runner *smoke.Runner, input smoke.RunInput, accounts []string, concurrency int, historyDir string, reportFormat string, reportFile string, resolvedVersion string) error {
+	runProgress := startProgress(cmd, runtime, "smoke run", int64(len(accounts)))
+	runProgress.Bar().SetUnit("accounts")
+	runner.OnProgress = runProgress.Work
+	result, err := runner.RunAccounts(runProgress.Context(cmd.Context()), input, accounts, concurrency)
+	runProgress.Finish()
+	if err != nil {
+		code := smoke.ExitCodeRuntime
+		switch {
+		case errors.Is(err, smoke.ErrAccountIDMissing):
+			code = smoke.ExitCodeInput
+		case errors.Is(err, smoke.ErrInvalidConcurrency):
+			code = smoke.ExitCodeInput
+		}
+		return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(code, err))
+	}
+
+	for index := range result.Accounts {
+		account := &result.Accounts[index]
+		if account.Report == nil {
+			continue
+		}
+		if err := persistSmokeResources(*account.Report, input.ProfileName, resolvedVersion); err != nil {
+			return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeRuntime, err))
+		}
+		historyPath, err := persistSmokeHistory(historyDir, input.ProfileName, *account.Report)
+		if err != nil {
+			return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeRuntime, err))
+		}
+		account.HistoryPath = historyPath
+	}
+	if err := writeSmokeReportFile(reportFormat, reportFile, smoke.JUnitFromMultiRun(result)); err != nil {
+		return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeRuntime, err))
+	}
+
+	envelope := smoke.NewSuccessEnvelope(smoke.CommandRun, result)
+	if code := smoke.MultiRunExitCode(result); code != smoke.ExitCodeSuccess {
+		envelope.Success = false
+		envelope.ExitCode = code
+		if code == smoke.ExitCodePolicy {
+			envelope.Error = &smoke.ErrorInfo{
+				Type: "blocking_findings",
+				Message: fmt.Sprintf(
+					"smoke run reported blocking findings or errors in %d of %d account(s)",
+					result.Summary.Blocking+result.Summary.Errored,
+					result.Summary.Accounts,
+				),
+			}
+		} else {
+			envelope.Error = &smoke.ErrorInfo{
+				Type:    "warning_findings",
+				Message: fmt.Sprintf("smoke run reported warning findings in %d of %d account(s)", result.Summary.Warning, result.Summary.Accounts),
+			}
+		}
+	}
+
+	if err := smoke.WriteEnvelope(cmd.OutOrStdout(), selectedOutputFormat(runtime), envelope); err != nil {
+		return writeSmokeError(cmd, runtime, smoke.CommandRun, smoke.WrapExit(smoke.ExitCodeUnknown, fmt.Errorf("write smoke envelope: %w", err)))
+	}
+	if !envelope.Success {
+		return smoke.WrapExit(envelope.ExitCode, errors.New(envelope.Error.Message))
+	}
+	return nil
+}
+
+func persistSmokeResources(report smoke.Report, profileName string, graphVersion string) error {
+	for _, resource := range report.RemainingResources() {
+		if err := persistTrackedResource(trackedResourceInput{
+			Command:       resource.Command,
+			ResourceKind:  resource.ResourceKind,
+			ResourceID:    resource.ResourceID,
+			CleanupAction: resource.CleanupAction,
+			Profile:       profileName,
+			GraphVersion:  graphVersion,
+			AccountID:     resource.AccountID,
+			Metadata: map[string]string{
+				"step": resource.Step,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newSmokeDiffCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile    string
+		accountID  string
+		historyDir string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the latest two persisted smoke reports for a profile/account",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := ensureSmokeOutput(runtime, smoke.CommandDiff); err != nil {
+				return writeSmokeError(cmd, runtime, smoke.CommandDiff, smoke.WrapExit(smoke.ExitCodeInput, err))
+			}
+
+			resolvedProfile := strings.TrimSpace(profile)
+			if resolvedProfile == "" {
+				resolvedProfile = runtime.ProfileName()
+			}
+			root, err := resolveSmokeHistoryRoot(historyDir)
+			if err != nil {
+				return writeSmokeError(cmd, runtime, smoke.CommandDiff, smoke.WrapExit(smoke.ExitCodeRuntime, err))
+			}
+			dir, err := smoke.HistoryDir(root, resolvedProfile, accountID)
+			if err != nil {
+				return writeSmokeError(cmd, runtime, smoke.CommandDiff, smoke.WrapExit(smoke.ExitCodeInput, err))
+			}
+
+			result, err := smoke.DiffLatestReports(dir)
+			if err != nil {
+				code := smoke.ExitCodeRuntime
+				if errors.Is(err, smoke.ErrHistoryInsufficient) {
+					code = smoke.ExitCodeInput
+				}
+				return writeSmokeError(cmd, runtime, smoke.CommandDiff, smoke.WrapExit(code, err))
+			}
+
+			envelope := smoke.NewSuccessEnvelope(smoke.CommandDiff, result)
+			if code := smoke.DiffExitCode(result); code != smoke.ExitCodeSuccess {
+				envelope.Success = false
+				envelope.ExitCode = code
+				envelope.Error = &smoke.ErrorInfo{
+					Type:    "smoke_regression",
+					Message: fmt.Sprintf("smoke diff found regressions: %s", result.Regression.Summary),
+				}
+			}
+			if err := smoke.WriteEnvelope(cmd.OutOrStdout(), selectedOutputFormat(runtime), envelope); err != nil {
+				return writeSmokeError(cmd, runtime, smoke.CommandDiff, smoke.WrapExit(smoke.ExitCodeUnknown, fmt.Errorf("write smoke envelope: %w", err)))
+			}
+			if !envelope.Success {
+				return smoke.WrapExit(envelope.ExitCode, errors.New(envelope.Error.Message))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name used to key persisted smoke reports")
+	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	cmd.Flags().StringVar(&historyDir, "history-dir", "", "Root directory for persisted smoke reports (default: ~/.meta/smoke/reports)")
 	mustMarkFlagRequired(cmd, "account-id")
 	return cmd
 }
 
-func ensureSmokeOutput(runtime Runtime) error {
+func ensureSmokeOutput(runtime Runtime, command string) error {
 	format := strings.ToLower(strings.TrimSpace(selectedOutputFormat(runtime)))
 	if format != "json" {
-		return fmt.Errorf("meta smoke run requires --output json, got %q", format)
+		return fmt.Errorf("%s requires --output json, got %q", command, format)
 	}
 	return nil
 }
 
+func writeSmokeReportFile(format string, path string, suites ops.JUnitTestSuites) error {
+	if !strings.EqualFold(strings.TrimSpace(format), ops.ReportFormatJUnit) {
+		return nil
+	}
+	return ops.WriteJUnitFile(path, suites)
+}
+
+func resolveSmokeHistoryRoot(historyDir string) (string, error) {
+	if trimmed := strings.TrimSpace(historyDir); trimmed != "" {
+		return trimmed, nil
+	}
+	if envDir := strings.TrimSpace(os.Getenv(smokeHistoryDirEnv)); envDir != "" {
+		return envDir, nil
+	}
+	return smoke.DefaultHistoryRoot()
+}
+
+func persistSmokeHistory(historyDir string, profileName string, report smoke.Report) (string, error) {
+	root, err := resolveSmokeHistoryRoot(historyDir)
+	if err != nil {
+		return "", err
+	}
+	dir, err := smoke.HistoryDir(root, profileName, report.Account.AccountID)
+	if err != nil {
+		return "", err
+	}
+	return smoke.SaveHistoryEntry(dir, smoke.HistoryEntry{
+		RecordedAt:  smokeNow(),
+		ProfileName: profileName,
+		AccountID:   report.Account.AccountID,
+		Report:      report,
+	})
+}
+
 func resolveSmokeProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
 	resolvedProfile := strings.TrimSpace(profile)
 	if resolvedProfile == "" {
@@ -3,12 +3,20 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/bilalbayram/metacli/internal/config"
 	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/ops"
 	"github.com/bilalbayram/metacli/internal/smoke"
 )
 
@@ -48,6 +56,20 @@ func (f *fakeSmokeGraphClient) Do(_ context.Context, req graph.Request) (*graph.
 	return expected.Response, nil
 }
 
+type routedSmokeGraphClient struct {
+	mu        sync.Mutex
+	responses map[string]*graph.Response
+}
+
+func (c *routedSmokeGraphClient) Do(_ context.Context, req graph.Request) (*graph.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if response, ok := c.responses[req.Method+" "+req.Path]; ok {
+		return response, nil
+	}
+	return nil, fmt.Errorf("unexpected smoke request %s %s", req.Method, req.Path)
+}
+
 func (f *fakeSmokeGraphClient) assertAllCallsConsumed() {
 	f.t.Helper()
 	if f.index != len(f.calls) {
@@ -177,6 +199,23 @@ func TestSmokeRunSkipPolicyReturnsWarningExit(t *testing.T) {
 					Message:    "Permissions error",
 				},
 			},
+			{
+				Method: http.MethodPost,
+				Path:   "act_1234/adsets",
+				Err: &graph.APIError{
+					Type:       "OAuthException",
+					Code:       200,
+					StatusCode: http.StatusBadRequest,
+					Message:    "Permissions error",
+				},
+			},
+			{
+				Method: http.MethodGet,
+				Path:   "cmp_1001/insights",
+				Response: &graph.Response{
+					Body: map[string]any{"data": []any{}},
+				},
+			},
 		},
 	}
 
@@ -227,6 +266,531 @@ func TestSmokeRunSkipPolicyReturnsWarningExit(t *testing.T) {
 	}
 }
 
+func TestSmokeRunFailOnBlockingReturnsSuccessForWarnings(t *testing.T) {
+	client := &fakeSmokeGraphClient{
+		t: t,
+		calls: []fakeSmokeCall{
+			{
+				Method: http.MethodGet,
+				Path:   "act_1234",
+				Response: &graph.Response{
+					Body: map[string]any{
+						"id":             "act_1234",
+						"name":           "Primary",
+						"currency":       "USD",
+						"account_status": float64(1),
+					},
+				},
+			},
+			{
+				Method: http.MethodPost,
+				Path:   "act_1234/campaigns",
+				Response: &graph.Response{
+					Body: map[string]any{
+						"id": "cmp_1001",
+					},
+				},
+			},
+			{
+				Method: http.MethodPost,
+				Path:   "act_1234/customaudiences",
+				Err: &graph.APIError{
+					Type:       "OAuthException",
+					Code:       200,
+					StatusCode: http.StatusBadRequest,
+					Message:    "Permissions error",
+				},
+			},
+			{
+				Method: http.MethodPost,
+				Path:   "act_1234/adsets",
+				Err: &graph.APIError{
+					Type:       "OAuthException",
+					Code:       200,
+					StatusCode: http.StatusBadRequest,
+					Message:    "Permissions error",
+				},
+			},
+			{
+				Method: http.MethodGet,
+				Path:   "cmp_1001/insights",
+				Response: &graph.Response{
+					Body: map[string]any{"data": []any{}},
+				},
+			},
+		},
+	}
+
+	useSmokeDependencies(
+		t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name: "prod",
+				Profile: config.Profile{
+					GraphVersion: config.DefaultGraphVersion,
+				},
+				Token: "test-token",
+			}, nil
+		},
+		func(smoke.GraphClient) *smoke.Runner {
+			return smoke.NewRunner(client)
+		},
+	)
+
+	stdout, stderr, err := executeSmokeCommand(runtimeWithProfile("prod"), "run", "--account-id", "1234", "--optional-policy", "skip", "--fail-on", "blocking")
+	if err != nil {
+		t.Fatalf("expected fail-on blocking smoke run to succeed, got %v", err)
+	}
+	if stderr != "" {
+		t.Fatalf("expected empty stderr, got %q", stderr)
+	}
+	client.assertAllCallsConsumed()
+
+	envelope := decodeOpsEnvelope(t, []byte(stdout))
+	if !envelope.Success || envelope.ExitCode != smoke.ExitCodeSuccess {
+		t.Fatalf("unexpected envelope status: success=%v exit_code=%d", envelope.Success, envelope.ExitCode)
+	}
+	var data smoke.RunResult
+	if err := json.Unmarshal(envelope.Data, &data); err != nil {
+		t.Fatalf("decode smoke data: %v", err)
+	}
+	if data.Report.ExitPolicy == nil {
+		t.Fatal("expected effective exit policy in report")
+	}
+	if data.Report.ExitPolicy.FailOn != "blocking" || data.Report.ExitPolicy.Warnings != 5 || data.Report.ExitPolicy.ExitCode != smoke.ExitCodeSuccess {
+		t.Fatalf("unexpected exit policy echo: %+v", data.Report.ExitPolicy)
+	}
+}
+
+func TestSmokeRunCleanupAlwaysSkipsLedgerForTornDownResources(t *testing.T) {
+	client := &fakeSmokeGraphClient{
+		t: t,
+		calls: []fakeSmokeCall{
+			{
+				Method: http.MethodGet,
+				Path:   "act_1234",
+				Response: &graph.Response{
+					Body: map[string]any{
+						"id":             "act_1234",
+						"account_status": float64(1),
+					},
+				},
+			},
+			{
+				Method: http.MethodPost,
+				Path:   "act_1234/campaigns",
+				Response: &graph.Response{
+					Body: map[string]any{"id": "cmp_1001"},
+				},
+			},
+			{
+				Method: http.MethodPost,
+				Path:   "act_1234/customaudiences",
+				Err: &graph.APIError{
+					Type:       "OAuthException",
+					Code:       200,
+					StatusCode: http.StatusBadRequest,
+					Message:    "Permissions error",
+				},
+			},
+			{
+				Method: http.MethodPost,
+				Path:   "act_1234/adsets",
+				Err: &graph.APIError{
+					Type:       "OAuthException",
+					Code:       200,
+					StatusCode: http.StatusBadRequest,
+					Message:    "Permissions error",
+				},
+			},
+			{
+				Method: http.MethodGet,
+				Path:   "cmp_1001/insights",
+				Response: &graph.Response{
+					Body: map[string]any{"data": []any{}},
+				},
+			},
+			{
+				Method: http.MethodPost,
+				Path:   "cmp_1001",
+				Response: &graph.Response{
+					Body: map[string]any{"success": true},
+				},
+			},
+		},
+	}
+
+	useSmokeDependencies(
+		t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name: "prod",
+				Profile: config.Profile{
+					GraphVersion: config.DefaultGraphVersion,
+				},
+				Token: "test-token",
+			}, nil
+		},
+		func(smoke.GraphClient) *smoke.Runner {
+			return smoke.NewRunner(client)
+		},
+	)
+	ledgerPath := configureTestResourceLedgerPath(t)
+
+	stdout, _, err := executeSmokeCommand(runtimeWithProfile("prod"), "run", "--account-id", "1234", "--optional-policy", "skip", "--fail-on", "blocking", "--cleanup", "always")
+	if err != nil {
+		t.Fatalf("execute smoke run with cleanup: %v", err)
+	}
+	client.assertAllCallsConsumed()
+
+	var data smoke.RunResult
+	if err := json.Unmarshal(decodeOpsEnvelope(t, []byte(stdout)).Data, &data); err != nil {
+		t.Fatalf("decode smoke data: %v", err)
+	}
+	if data.Report.Cleanup.Summary.Applied != 1 || data.Report.Cleanup.Resources[0].Status != smoke.CleanupStatusApplied {
+		t.Fatalf("unexpected cleanup report: %+v", data.Report.Cleanup)
+	}
+	if _, err := os.Stat(ledgerPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no ledger entries for torn-down resources, stat err=%v", err)
+	}
+
+	_, _, err = executeSmokeCommand(runtimeWithProfile("prod"), "run", "--account-id", "1234", "--cleanup", "sometimes")
+	var exitErr *smoke.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != smoke.ExitCodeInput {
+		t.Fatalf("expected input exit for invalid cleanup policy, got %v", err)
+	}
+}
+
+func TestSmokeRunScenarioFileReplacesBuiltInSteps(t *testing.T) {
+	client := &fakeSmokeGraphClient{
+		t: t,
+		calls: []fakeSmokeCall{
+			{
+				Method: http.MethodGet,
+				Path:   "act_1234",
+				Response: &graph.Response{
+					Body: map[string]any{
+						"id":             "act_1234",
+						"account_status": float64(1),
+					},
+				},
+			},
+			{
+				Method: http.MethodGet,
+				Path:   "act_1234/campaigns",
+				Response: &graph.Response{
+					Body: map[string]any{"data": []any{map[string]any{"id": "cmp_1001"}}},
+				},
+			},
+			{
+				Method: http.MethodGet,
+				Path:   "cmp_1001",
+				Response: &graph.Response{
+					Body: map[string]any{"id": "cmp_1001", "status": "PAUSED"},
+				},
+			},
+		},
+	}
+
+	useSmokeDependencies(
+		t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name: "prod",
+				Profile: config.Profile{
+					GraphVersion: config.DefaultGraphVersion,
+				},
+				Token: "test-token",
+			}, nil
+		},
+		func(smoke.GraphClient) *smoke.Runner {
+			return smoke.NewRunner(client)
+		},
+	)
+
+	scenarioPath := filepath.Join(t.TempDir(), "scenario.yaml")
+	scenario := `schema_version: 1
+name: read-only
+steps:
+  - name: campaign_list
+    method: GET
+    path: act_{{.account_id}}/campaigns
+    params:
+      limit: "1"
+    capture:
+      campaign_id: data.0.id
+  - name: campaign_read
+    method: GET
+    path: "{{.campaign_id}}"
+`
+	if err := os.WriteFile(scenarioPath, []byte(scenario), 0o600); err != nil {
+		t.Fatalf("write scenario: %v", err)
+	}
+
+	stdout, _, err := executeSmokeCommand(runtimeWithProfile("prod"), "run", "--account-id", "1234", "--scenario-file", scenarioPath)
+	if err != nil {
+		t.Fatalf("execute smoke run with scenario: %v", err)
+	}
+	client.assertAllCallsConsumed()
+
+	var data smoke.RunResult
+	if err := json.Unmarshal(decodeOpsEnvelope(t, []byte(stdout)).Data, &data); err != nil {
+		t.Fatalf("decode smoke data: %v", err)
+	}
+	if data.Report.Scenario != "read-only" || data.Report.Outcome != smoke.RunOutcomeClean || len(data.Report.Steps) != 3 {
+		t.Fatalf("unexpected scenario report: %+v", data.Report)
+	}
+
+	invalidPath := filepath.Join(t.TempDir(), "invalid.yaml")
+	if err := os.WriteFile(invalidPath, []byte("schema_version: 1\nname: broken\nsteps: []\n"), 0o600); err != nil {
+		t.Fatalf("write invalid scenario: %v", err)
+	}
+	_, _, err = executeSmokeCommand(runtimeWithProfile("prod"), "run", "--account-id", "1234", "--scenario-file", invalidPath)
+	var exitErr *smoke.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != smoke.ExitCodeInput {
+		t.Fatalf("expected input exit for invalid scenario, got %v", err)
+	}
+}
+
+func TestSmokeRunWritesJUnitReportFile(t *testing.T) {
+	client := &fakeSmokeGraphClient{
+		t: t,
+		calls: []fakeSmokeCall{
+			{
+				Method: http.MethodGet,
+				Path:   "act_1234",
+				Response: &graph.Response{
+					Body: map[string]any{"id": "act_1234", "account_status": float64(1)},
+				},
+			},
+			{
+				Method: http.MethodGet,
+				Path:   "act_1234/campaigns",
+				Err: &graph.APIError{
+					Type:       "OAuthException",
+					Code:       100,
+					StatusCode: http.StatusBadRequest,
+					Message:    "Invalid parameter",
+				},
+			},
+		},
+	}
+
+	useSmokeDependencies(
+		t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name: "prod",
+				Profile: config.Profile{
+					GraphVersion: config.DefaultGraphVersion,
+				},
+				Token: "test-token",
+			}, nil
+		},
+		func(smoke.GraphClient) *smoke.Runner {
+			return smoke.NewRunner(client)
+		},
+	)
+
+	scenarioPath := filepath.Join(t.TempDir(), "scenario.yaml")
+	scenario := `schema_version: 1
+name: read-only
+steps:
+  - name: campaign_list
+    method: GET
+    path: act_{{.account_id}}/campaigns
+`
+	if err := os.WriteFile(scenarioPath, []byte(scenario), 0o600); err != nil {
+		t.Fatalf("write scenario: %v", err)
+	}
+	reportPath := filepath.Join(t.TempDir(), "smoke.xml")
+
+	_, _, err := executeSmokeCommand(runtimeWithProfile("prod"), "run", "--account-id", "1234", "--scenario-file", scenarioPath, "--report-format", "junit", "--report-file", reportPath)
+	var exitErr *smoke.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != smoke.ExitCodePolicy {
+		t.Fatalf("expected policy exit, got %v", err)
+	}
+	client.assertAllCallsConsumed()
+
+	payload, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("read junit report: %v", err)
+	}
+	var suites ops.JUnitTestSuites
+	if err := xml.Unmarshal(payload, &suites); err != nil {
+		t.Fatalf("decode junit report: %v", err)
+	}
+	if suites.Tests != 2 || suites.Failures != 1 || len(suites.Suites) != 1 {
+		t.Fatalf("unexpected junit totals: %+v", suites)
+	}
+	failed := suites.Suites[0].Cases[1]
+	if failed.Name != "campaign_list" || failed.Failure == nil || failed.Failure.Type != ops.FindingSeverityBlocking {
+		t.Fatalf("unexpected failed case: %+v", failed)
+	}
+
+	_, _, err = executeSmokeCommand(runtimeWithProfile("prod"), "run", "--account-id", "1234", "--report-format", "tap", "--report-file", reportPath)
+	if !errors.As(err, &exitErr) || exitErr.Code != smoke.ExitCodeInput {
+		t.Fatalf("expected input exit for invalid report format, got %v", err)
+	}
+}
+
+func TestSmokeRunPersistsHistoryAndDiffReportsRegression(t *testing.T) {
+	accountCall := fakeSmokeCall{
+		Method: http.MethodGet,
+		Path:   "act_1234",
+		Response: &graph.Response{
+			Body: map[string]any{"id": "act_1234", "account_status": float64(1)},
+		},
+	}
+	client := &fakeSmokeGraphClient{
+		t: t,
+		calls: []fakeSmokeCall{
+			accountCall,
+			{Method: http.MethodGet, Path: "act_1234/campaigns", Response: &graph.Response{Body: map[string]any{"data": []any{}}}},
+			accountCall,
+			{
+				Method: http.MethodGet,
+				Path:   "act_1234/campaigns",
+				Err: &graph.APIError{
+					Type:       "OAuthException",
+					Code:       100,
+					StatusCode: http.StatusBadRequest,
+					Message:    "Invalid parameter",
+				},
+			},
+		},
+	}
+
+	useSmokeDependencies(
+		t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name: "prod",
+				Profile: config.Profile{
+					GraphVersion: config.DefaultGraphVersion,
+				},
+				Token: "test-token",
+			}, nil
+		},
+		func(smoke.GraphClient) *smoke.Runner {
+			return smoke.NewRunner(client)
+		},
+	)
+	historyDir := filepath.Join(t.TempDir(), "history")
+	recordedAt := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	originalNow := smokeNow
+	t.Cleanup(func() { smokeNow = originalNow })
+	smokeNow = func() time.Time {
+		recordedAt = recordedAt.Add(time.Minute)
+		return recordedAt
+	}
+
+	scenarioPath := filepath.Join(t.TempDir(), "scenario.yaml")
+	scenario := "schema_version: 1\nname: list\nsteps:\n  - name: campaign_list\n    method: GET\n    path: act_{{.account_id}}/campaigns\n"
+	if err := os.WriteFile(scenarioPath, []byte(scenario), 0o600); err != nil {
+		t.Fatalf("write scenario: %v", err)
+	}
+
+	stdout, _, err := executeSmokeCommand(runtimeWithProfile("prod"), "run", "--account-id", "1234", "--scenario-file", scenarioPath, "--history-dir", historyDir)
+	if err != nil {
+		t.Fatalf("execute first smoke run: %v", err)
+	}
+	var data smoke.RunResult
+	if err := json.Unmarshal(decodeOpsEnvelope(t, []byte(stdout)).Data, &data); err != nil {
+		t.Fatalf("decode smoke data: %v", err)
+	}
+	if data.HistoryPath == "" || filepath.Dir(data.HistoryPath) != filepath.Join(historyDir, "prod", "act_1234") {
+		t.Fatalf("unexpected history path: %q", data.HistoryPath)
+	}
+
+	if _, _, err := executeSmokeCommand(runtimeWithProfile("prod"), "run", "--account-id", "1234", "--scenario-file", scenarioPath, "--history-dir", historyDir); err == nil {
+		t.Fatal("expected second smoke run to report blocking findings")
+	}
+	client.assertAllCallsConsumed()
+
+	stdout, _, err = executeSmokeCommand(runtimeWithProfile("prod"), "diff", "--account-id", "act_1234", "--history-dir", historyDir)
+	var exitErr *smoke.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != smoke.ExitCodePolicy {
+		t.Fatalf("expected policy exit for regression, got %v", err)
+	}
+	envelope := decodeOpsEnvelope(t, []byte(stdout))
+	if envelope.Command != smoke.CommandDiff || envelope.Error == nil || envelope.Error.Type != "smoke_regression" {
+		t.Fatalf("unexpected diff envelope: %+v", envelope)
+	}
+	var diff smoke.DiffResult
+	if err := json.Unmarshal(envelope.Data, &diff); err != nil {
+		t.Fatalf("decode diff data: %v", err)
+	}
+	if len(diff.StepRegressions) != 1 || diff.StepRegressions[0].Name != "campaign_list" {
+		t.Fatalf("unexpected step regressions: %+v", diff.StepRegressions)
+	}
+
+	_, _, err = executeSmokeCommand(runtimeWithProfile("prod"), "diff", "--account-id", "5678", "--history-dir", historyDir)
+	if !errors.As(err, &exitErr) || exitErr.Code != smoke.ExitCodeInput {
+		t.Fatalf("expected input exit for missing history, got %v", err)
+	}
+}
+
+func TestSmokeRunAccountsRunsEachAccountWithCombinedSummary(t *testing.T) {
+	client := &routedSmokeGraphClient{
+		responses: map[string]*graph.Response{
+			"GET act_1":           {Body: map[string]any{"id": "act_1", "account_status": float64(1)}},
+			"GET act_1/campaigns": {Body: map[string]any{"data": []any{}}},
+			"GET act_2":           {Body: map[string]any{"id": "act_2", "account_status": float64(1)}},
+			"GET act_2/campaigns": {Body: map[string]any{"data": []any{}}},
+		},
+	}
+
+	useSmokeDependencies(
+		t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name: "prod",
+				Profile: config.Profile{
+					GraphVersion: config.DefaultGraphVersion,
+				},
+				Token: "test-token",
+			}, nil
+		},
+		func(smoke.GraphClient) *smoke.Runner {
+			return smoke.NewRunner(client)
+		},
+	)
+
+	scenarioPath := filepath.Join(t.TempDir(), "scenario.yaml")
+	scenario := "schema_version: 1\nname: list\nsteps:\n  - name: campaign_list\n    method: GET\n    path: act_{{.account_id}}/campaigns\n"
+	if err := os.WriteFile(scenarioPath, []byte(scenario), 0o600); err != nil {
+		t.Fatalf("write scenario: %v", err)
+	}
+
+	stdout, _, err := executeSmokeCommand(runtimeWithProfile("prod"), "run", "--accounts", "act_1,act_2", "--concurrency", "2", "--scenario-file", scenarioPath)
+	if err != nil {
+		t.Fatalf("execute multi-account smoke run: %v", err)
+	}
+	var data smoke.MultiRunResult
+	if err := json.Unmarshal(decodeOpsEnvelope(t, []byte(stdout)).Data, &data); err != nil {
+		t.Fatalf("decode smoke data: %v", err)
+	}
+	if data.Summary.Accounts != 2 || data.Summary.Clean != 2 || data.Summary.Outcome != smoke.RunOutcomeClean {
+		t.Fatalf("unexpected combined summary: %+v", data.Summary)
+	}
+	for _, account := range data.Accounts {
+		if account.Report == nil || account.HistoryPath == "" {
+			t.Fatalf("expected isolated report and history for %s, got %+v", account.AccountID, account)
+		}
+	}
+
+	_, _, err = executeSmokeCommand(runtimeWithProfile("prod"), "run", "--account-id", "1", "--accounts", "2")
+	var exitErr *smoke.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != smoke.ExitCodeInput {
+		t.Fatalf("expected input exit for conflicting account flags, got %v", err)
+	}
+	_, _, err = executeSmokeCommand(runtimeWithProfile("prod"), "run", "--accounts", "1", "--concurrency", "0")
+	if !errors.As(err, &exitErr) || exitErr.Code != smoke.ExitCodeInput {
+		t.Fatalf("expected input exit for invalid concurrency, got %v", err)
+	}
+}
+
 func TestSmokeRunRejectsInvalidOptionalPolicy(t *testing.T) {
 	useSmokeDependencies(
 		t,
@@ -292,6 +856,7 @@ func useSmokeDependencies(
 ) {
 	t.Helper()
 	configureTestResourceLedgerPath(t)
+	configureTestSmokeHistoryDir(t)
 	originalLoad := smokeLoadProfileCredentials
 	originalRunner := smokeNewRunner
 	originalClient := smokeNewGraphClient
@@ -309,3 +874,11 @@ func useSmokeDependencies(
 		return client
 	}
 }
+
+func configureTestSmokeHistoryDir(t *testing.T) string {
+	t.Helper()
+
+	historyDir := filepath.Join(t.TempDir(), "smoke-reports")
+	t.Setenv(smokeHistoryDirEnv, historyDir)
+	return historyDir
+}
@@ -0,0 +1,367 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/filelock"
+	"github.com/bilalbayram/metacli/internal/schema"
+	"github.com/bilalbayram/metacli/internal/templates"
+	"github.com/spf13/cobra"
+)
+
+const templateStorePathEnv = "META_TEMPLATE_STORE_PATH"
+
+var (
+	templateLoadProfileCredentials = loadProfileCredentials
+	templateNow                    = time.Now
+
+	// templateCreateFromFlags are the create flags `template create-from` forwards
+	// to the create command of the template kind.
+	templateCreateFromFlags = []string{
+		"profile", "version", "account-id", "account", "params", "json",
+		"schema-dir", "confirm-budget-change", "dry-run",
+	}
+)
+
+func NewTemplateCommand(runtime Runtime) *cobra.Command {
+	templateCmd := &cobra.Command{
+		Use:   "template",
+		Short: "Reusable named create payloads with {{variable}} substitution",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "template")
+		},
+	}
+	templateCmd.AddCommand(newTemplateSaveCommand(runtime))
+	templateCmd.AddCommand(newTemplateRenderCommand(runtime))
+	templateCmd.AddCommand(newTemplateListCommand(runtime))
+	templateCmd.AddCommand(newTemplateCreateFromCommand(runtime))
+	return templateCmd
+}
+
+func newTemplateSaveCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile     string
+		version     string
+		name        string
+		kind        string
+		paramsRaw   string
+		jsonRaw     string
+		defaultsRaw []string
+		schemaDir   string
+		force       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "save",
+		Short: "Save a parameterized create payload, linting its keys against the schema pack",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveTemplateProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template save", err)
+			}
+
+			params, err := parseKeyValueList(paramsRaw)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template save", err)
+			}
+			jsonParams, err := parseInlineJSONPayload(jsonRaw)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template save", err)
+			}
+			if err := mergeParams(params, jsonParams, "--json"); err != nil {
+				return writeCommandError(cmd, runtime, "meta template save", err)
+			}
+			defaults, err := templates.ParseVars(defaultsRaw)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template save", err)
+			}
+
+			template, err := templates.New(name, kind, params, defaults)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template save", err)
+			}
+			if err := lintTemplateParams(creds, resolvedVersion, schemaDir, template); err != nil {
+				return writeCommandError(cmd, runtime, "meta template save", err)
+			}
+			template.GraphVersion = resolvedVersion
+			template.SavedAt = templateNow().UTC()
+
+			storePath, err := resolveTemplateStorePath()
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template save", err)
+			}
+			lock, err := filelock.Acquire(storePath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template save", err)
+			}
+			defer lock.Release()
+			store, err := templates.LoadStore(storePath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template save", err)
+			}
+			if _, exists := store.Templates[template.Name]; exists && !force {
+				return writeCommandError(cmd, runtime, "meta template save", fmt.Errorf("template %q already exists; rerun with --force to overwrite", template.Name))
+			}
+			store.Templates[template.Name] = template
+			if err := templates.SaveStoreHeld(lock, storePath, store); err != nil {
+				return writeCommandError(cmd, runtime, "meta template save", err)
+			}
+			return writeSuccess(cmd, runtime, "meta template save", template, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&name, "name", "", "Template name")
+	cmd.Flags().StringVar(&kind, "kind", "", "Create command the template feeds: campaign|adset|ad")
+	cmd.Flags().StringVar(&paramsRaw, "params", "", "Comma-separated payload params with {{variable}} placeholders (k=v,k2={{var}})")
+	cmd.Flags().StringVar(&jsonRaw, "json", "", "Inline JSON object payload with {{variable}} placeholders")
+	cmd.Flags().StringArrayVar(&defaultsRaw, "default", nil, "Default variable value (name=value, repeatable)")
+	cmd.Flags().StringVar(&schemaDir, "schema-dir", schema.DefaultSchemaDir(), "Schema pack root directory")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing template with the same name")
+	mustMarkFlagRequired(cmd, "name")
+	mustMarkFlagRequired(cmd, "kind")
+	cmd.MarkFlagsOneRequired("params", "json")
+	return cmd
+}
+
+type templateRenderResult struct {
+	Name   string            `json:"name"`
+	Kind   string            `json:"kind"`
+	Params map[string]string `json:"params"`
+}
+
+func newTemplateRenderCommand(runtime Runtime) *cobra.Command {
+	var (
+		name    string
+		varsRaw []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Render a saved template with variables without creating anything",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			template, err := loadSavedTemplate(name)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template render", err)
+			}
+			vars, err := templates.ParseVars(varsRaw)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template render", err)
+			}
+			params, err := template.Render(vars)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template render", err)
+			}
+			return writeSuccess(cmd, runtime, "meta template render", templateRenderResult{
+				Name:   template.Name,
+				Kind:   template.Kind,
+				Params: params,
+			}, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Template name")
+	cmd.Flags().StringArrayVar(&varsRaw, "var", nil, "Template variable (name=value, repeatable)")
+	mustMarkFlagRequired(cmd, "name")
+	return cmd
+}
+
+func newTemplateListCommand(runtime Runtime) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List saved templates",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			storePath, err := resolveTemplateStorePath()
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template list", err)
+			}
+			store, err := templates.LoadStore(storePath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template list", err)
+			}
+			return writeSuccess(cmd, runtime, "meta template list", store.List(), nil, nil)
+		},
+	}
+}
+
+func newTemplateCreateFromCommand(runtime Runtime) *cobra.Command {
+	var (
+		name    string
+		varsRaw []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create-from",
+		Short: "Render a saved template and run the matching campaign|adset|ad create",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			template, err := loadSavedTemplate(name)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template create-from", err)
+			}
+			create, err := newTemplateTargetCommand(runtime, template.Kind)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta template create-from", err)
+			}
+
+			args := []string{"--template", template.Name}
+			for _, entry := range varsRaw {
+				args = append(args, "--var", entry)
+			}
+			for _, flagName := range templateCreateFromFlags {
+				if !cmd.Flags().Changed(flagName) {
+					continue
+				}
+				if create.Flags().Lookup(flagName) == nil {
+					return writeCommandError(cmd, runtime, "meta template create-from", fmt.Errorf("--%s is not supported by %s create", flagName, template.Kind))
+				}
+				args = append(args, "--"+flagName+"="+cmd.Flags().Lookup(flagName).Value.String())
+			}
+
+			create.SetArgs(args)
+			create.SetOut(cmd.OutOrStdout())
+			create.SetErr(cmd.ErrOrStderr())
+			create.SilenceErrors = true
+			create.SilenceUsage = true
+			return create.ExecuteContext(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Template name")
+	cmd.Flags().StringArrayVar(&varsRaw, "var", nil, "Template variable (name=value, repeatable)")
+	cmd.Flags().String("profile", "", "Profile name")
+	cmd.Flags().String("version", "", "Graph API version")
+	cmd.Flags().String("account-id", "", "Ad account id (with or without act_ prefix)")
+	cmd.Flags().String("account", "", "Ad account name, resolved to an id via accessible ad accounts (alternative to --account-id)")
+	cmd.Flags().String("params", "", "Additional comma-separated params merged with the rendered template")
+	cmd.Flags().String("json", "", "Additional inline JSON params merged with the rendered template")
+	cmd.Flags().String("schema-dir", schema.DefaultSchemaDir(), "Schema pack root directory")
+	cmd.Flags().Bool("confirm-budget-change", false, "Acknowledge budget mutation fields (campaign and adset templates)")
+	cmd.Flags().Bool("dry-run", false, "Resolve requirements without executing mutation (campaign templates only)")
+	mustMarkFlagRequired(cmd, "name")
+	return cmd
+}
+
+func newTemplateTargetCommand(runtime Runtime, kind string) (*cobra.Command, error) {
+	switch kind {
+	case templates.KindCampaign:
+		return newCampaignCreateCommand(runtime), nil
+	case templates.KindAdSet:
+		return newAdsetCreateCommand(runtime), nil
+	case templates.KindAd:
+		return newAdCreateCommand(runtime), nil
+	default:
+		return nil, fmt.Errorf("unsupported template kind %q", kind)
+	}
+}
+
+// addTemplateFlags registers --template/--var on a create command.
+func addTemplateFlags(cmd *cobra.Command, templateName *string, varsRaw *[]string) {
+	cmd.Flags().StringVar(templateName, "template", "", "Saved template to render into the payload (see meta template save)")
+	cmd.Flags().StringArrayVar(varsRaw, "var", nil, "Template variable (name=value, repeatable)")
+}
+
+// mergeTemplateParams renders the named template into form. It is a no-op when
+// --template is not set; keys already supplied by --params/--json are rejected as
+// duplicates rather than silently overridden.
+func mergeTemplateParams(form map[string]string, kind string, templateName string, varsRaw []string) error {
+	if strings.TrimSpace(templateName) == "" {
+		if len(varsRaw) > 0 {
+			return errors.New("--var requires --template")
+		}
+		return nil
+	}
+	template, err := loadSavedTemplate(templateName)
+	if err != nil {
+		return err
+	}
+	if template.Kind != kind {
+		return fmt.Errorf("template %q has kind %s and cannot be used with %s create", template.Name, template.Kind, kind)
+	}
+	vars, err := templates.ParseVars(varsRaw)
+	if err != nil {
+		return err
+	}
+	rendered, err := template.Render(vars)
+	if err != nil {
+		return err
+	}
+	return mergeParams(form, rendered, "--template")
+}
+
+func loadSavedTemplate(name string) (*templates.Template, error) {
+	storePath, err := resolveTemplateStorePath()
+	if err != nil {
+		return nil, err
+	}
+	store, err := templates.LoadStore(storePath)
+	if err != nil {
+		return nil, err
+	}
+	return store.Get(name)
+}
+
+func lintTemplateParams(creds *ProfileCredentials, version string, schemaDir string, template *templates.Template) error {
+	switch template.Kind {
+	case templates.KindCampaign:
+		linter, err := newCampaignMutationLinter(creds, version, schemaDir)
+		if err != nil {
+			return err
+		}
+		return lintCampaignMutation(linter, template.Params)
+	case templates.KindAdSet:
+		linter, err := newAdsetMutationLinter(creds, version, schemaDir)
+		if err != nil {
+			return err
+		}
+		return lintAdsetMutation(linter, template.Params)
+	case templates.KindAd:
+		linter, err := newAdMutationLinter(creds, version, schemaDir)
+		if err != nil {
+			return err
+		}
+		return lintAdMutation(linter, template.Params)
+	default:
+		return fmt.Errorf("unsupported template kind %q", template.Kind)
+	}
+}
+
+func resolveTemplateStorePath() (string, error) {
+	if envPath := strings.TrimSpace(os.Getenv(templateStorePathEnv)); envPath != "" {
+		return envPath, nil
+	}
+	return templates.DefaultStorePath()
+}
+
+func resolveTemplateProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
+	resolvedProfile := strings.TrimSpace(profile)
+	if resolvedProfile == "" {
+		resolvedProfile = runtime.ProfileName()
+	}
+	if resolvedProfile == "" {
+		return nil, "", errors.New("profile is required (--profile or global --profile)")
+	}
+
+	creds, err := templateLoadProfileCredentials(resolvedProfile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resolvedVersion := strings.TrimSpace(version)
+	if resolvedVersion == "" {
+		resolvedVersion = creds.Profile.GraphVersion
+	}
+	if resolvedVersion == "" {
+		resolvedVersion = config.DefaultGraphVersion
+	}
+	return creds, resolvedVersion, nil
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestTemplateSaveLintsKeysAndRenders(t *testing.T) {
+	schemaDir := writeAdSchemaPack(t)
+	useTemplateDependencies(t)
+
+	output, err := runTemplateCommand(t, "save",
+		"--name", "launch-ad",
+		"--kind", "ad",
+		"--params", "name=Launch {{variant}},adset_id={{adset_id}},status=PAUSED",
+		"--json", `{"creative":{"creative_id":"{{creative_id}}"}}`,
+		"--default", "variant=A",
+		"--schema-dir", schemaDir,
+	)
+	if err != nil {
+		t.Fatalf("execute template save: %v", err)
+	}
+	envelope := decodeEnvelope(t, output)
+	assertEnvelopeBasics(t, envelope, "meta template save")
+	data, _ := envelope["data"].(map[string]any)
+	if variables, _ := data["variables"].([]any); len(variables) != 3 {
+		t.Fatalf("unexpected variables %v", data["variables"])
+	}
+
+	output, err = runTemplateCommand(t, "render", "--name", "launch-ad", "--var", "adset_id=adset_1", "--var", "creative_id=creative_1")
+	if err != nil {
+		t.Fatalf("execute template render: %v", err)
+	}
+	envelope = decodeEnvelope(t, output)
+	params, _ := envelope["data"].(map[string]any)["params"].(map[string]any)
+	if params["name"] != "Launch A" || params["creative"] != `{"creative_id":"creative_1"}` {
+		t.Fatalf("unexpected rendered params %v", params)
+	}
+}
+
+func TestTemplateSaveRejectsUnknownParamAndDuplicates(t *testing.T) {
+	schemaDir := writeAdSchemaPack(t)
+	useTemplateDependencies(t)
+
+	if _, err := runTemplateCommand(t, "save", "--name", "launch-ad", "--kind", "ad", "--params", "bogus={{value}}", "--schema-dir", schemaDir); err == nil || !strings.Contains(err.Error(), `unknown param "bogus"`) {
+		t.Fatalf("expected lint failure, got %v", err)
+	}
+	if _, err := runTemplateCommand(t, "save", "--name", "launch-ad", "--kind", "ad", "--params", "status={{status}}", "--schema-dir", schemaDir); err != nil {
+		t.Fatalf("save template: %v", err)
+	}
+	if _, err := runTemplateCommand(t, "save", "--name", "launch-ad", "--kind", "ad", "--params", "status={{status}}", "--schema-dir", schemaDir); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected overwrite guard, got %v", err)
+	}
+}
+
+func TestTemplateCreateFromRunsAdCreate(t *testing.T) {
+	schemaDir := writeAdSchemaPack(t)
+	useTemplateDependencies(t)
+	if _, err := runTemplateCommand(t, "save",
+		"--name", "launch-ad",
+		"--kind", "ad",
+		"--params", "name=Launch,adset_id={{adset_id}},status=PAUSED",
+		"--json", `{"creative":{"creative_id":"{{creative_id}}"}}`,
+		"--schema-dir", schemaDir,
+	); err != nil {
+		t.Fatalf("save template: %v", err)
+	}
+
+	var createForm url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v25.0/adset_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "adset_1"})
+		case "GET /v25.0/creative_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "creative_1"})
+		case "POST /v25.0/act_1234/ads":
+			body, _ := io.ReadAll(r.Body)
+			createForm, _ = url.ParseQuery(string(body))
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "ad_501"})
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	useAdDependencies(t, templateTestCredentials, func() *graph.Client {
+		client := graph.NewClient(server.Client(), server.URL)
+		client.MaxRetries = 0
+		return client
+	})
+
+	output, err := runTemplateCommand(t, "create-from",
+		"--name", "launch-ad",
+		"--var", "adset_id=adset_1",
+		"--var", "creative_id=creative_1",
+		"--account-id", "1234",
+		"--schema-dir", schemaDir,
+	)
+	if err != nil {
+		t.Fatalf("execute template create-from: %v", err)
+	}
+	assertEnvelopeBasics(t, decodeEnvelope(t, output), "meta ad create")
+	if createForm.Get("adset_id") != "adset_1" || createForm.Get("creative") != `{"creative_id":"creative_1"}` {
+		t.Fatalf("unexpected create form %v", createForm)
+	}
+
+	if _, err := runTemplateCommand(t, "create-from", "--name", "launch-ad", "--dry-run"); err == nil || !strings.Contains(err.Error(), "--dry-run is not supported by ad create") {
+		t.Fatalf("expected unsupported flag error, got %v", err)
+	}
+}
+
+func TestCreateCommandRejectsTemplateOfOtherKind(t *testing.T) {
+	schemaDir := writeAdSchemaPack(t)
+	useTemplateDependencies(t)
+	if _, err := runTemplateCommand(t, "save", "--name", "launch-ad", "--kind", "ad", "--params", "status={{status}}", "--schema-dir", schemaDir); err != nil {
+		t.Fatalf("save template: %v", err)
+	}
+	useAdsetDependencies(t, templateTestCredentials, func() *graph.Client {
+		t.Fatal("graph client should not be constructed")
+		return nil
+	})
+
+	cmd := NewAdsetCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"create", "--account-id", "1234", "--template", "launch-ad", "--var", "status=PAUSED"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "has kind ad and cannot be used with adset create") {
+		t.Fatalf("expected kind mismatch error, got %v", err)
+	}
+}
+
+func runTemplateCommand(t *testing.T, args ...string) ([]byte, error) {
+	t.Helper()
+	output := &bytes.Buffer{}
+	cmd := NewTemplateCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return output.Bytes(), err
+}
+
+func templateTestCredentials(string) (*ProfileCredentials, error) {
+	return &ProfileCredentials{
+		Name: "prod",
+		Profile: config.Profile{
+			Domain:       config.DefaultDomain,
+			GraphVersion: config.DefaultGraphVersion,
+		},
+		Token: "test-token",
+	}, nil
+}
+
+func useTemplateDependencies(t *testing.T) {
+	t.Helper()
+	t.Setenv(templateStorePathEnv, filepath.Join(t.TempDir(), "templates.json"))
+	originalLoad := templateLoadProfileCredentials
+	t.Cleanup(func() {
+		templateLoadProfileCredentials = originalLoad
+	})
+	templateLoadProfileCredentials = templateTestCredentials
+}
@@ -55,6 +55,17 @@ func TestTrackBPublishWorkflowSmokeCLICommands(t *testing.T) {
 				}
 			},
 		},
+		{
+			method:   http.MethodGet,
+			path:     "/v25.0/17841400008460056/content_publishing_limit",
+			status:   http.StatusOK,
+			response: `{"data":[{"config":{"quota_total":100,"quota_duration":86400},"quota_usage":3}]}`,
+			assert: func(t *testing.T, req *http.Request) {
+				if got := req.URL.Query().Get("fields"); got != "config,quota_usage" {
+					t.Fatalf("unexpected quota fields query %q", got)
+				}
+			},
+		},
 		{
 			method:   http.MethodPost,
 			path:     "/v25.0/17841400008460056/media",
@@ -0,0 +1,482 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/i18n"
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const tuiHelp = "commands: p <n> pause | r <n> resume | d <n> diagnose | g refresh | ? help | q quit"
+
+var (
+	tuiLoadProfileCredentials = loadProfileCredentials
+	tuiNewGraphClient         = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+
+	tuiCampaignFields = []string{"id", "name", "status", "effective_status", "daily_budget", "lifetime_budget"}
+	tuiAdSetFields    = []string{"id", "name", "status", "effective_status", "campaign_id", "daily_budget", "lifetime_budget"}
+	tuiAdFields       = []string{"id", "name", "status", "effective_status", "adset_id"}
+
+	// tuiDiagnoseFields are read when a row is diagnosed; issues_info carries the
+	// delivery problems Ads Manager shows next to an entity.
+	tuiDiagnoseFields = map[string][]string{
+		marketing.EntityKindCampaign: {"id", "name", "status", "configured_status", "effective_status", "issues_info", "daily_budget", "lifetime_budget", "budget_remaining", "start_time", "stop_time"},
+		marketing.EntityKindAdSet:    {"id", "name", "status", "configured_status", "effective_status", "issues_info", "learning_stage_info", "daily_budget", "lifetime_budget", "budget_remaining", "start_time", "end_time"},
+		tuiKindAd:                    {"id", "name", "status", "configured_status", "effective_status", "issues_info", "ad_review_feedback"},
+	}
+)
+
+const tuiKindAd = "ad"
+
+type tuiNode struct {
+	Kind            string
+	ID              string
+	Name            string
+	Status          string
+	EffectiveStatus string
+	Budget          string
+	Depth           int
+}
+
+type tuiSession struct {
+	ctx       context.Context
+	creds     *ProfileCredentials
+	version   string
+	accountID string
+	cached    bool
+	// currency formats budgets; it is looked up once, on the first live load.
+	currency         string
+	currencyResolved bool
+	out              io.Writer
+	table            output.TableOptions
+	nodes            []tuiNode
+}
+
+func NewTUICommand(runtime Runtime) *cobra.Command {
+	var (
+		profile     string
+		version     string
+		accountID   string
+		accountName string
+		cached      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Interactive account dashboard with campaign, ad set and ad statuses",
+		Long: "Show the campaign -> ad set -> ad tree of an ad account with statuses and budgets, and act on rows by number.\n" +
+			tuiHelp,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveTUIProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta tui", err)
+			}
+			if err := resolveAccountNameFlag(cmd.Context(), creds, resolvedVersion, &accountID, accountName); err != nil {
+				return writeCommandError(cmd, runtime, "meta tui", err)
+			}
+			accountID = strings.TrimPrefix(strings.TrimSpace(accountID), "act_")
+			if accountID == "" {
+				return writeCommandError(cmd, runtime, "meta tui", errors.New("--account-id or --account is required"))
+			}
+
+			session := &tuiSession{
+				ctx:       cmd.Context(),
+				creds:     creds,
+				version:   resolvedVersion,
+				accountID: accountID,
+				cached:    cached,
+				out:       cmd.OutOrStdout(),
+				table:     output.TerminalTableOptions(cmd.OutOrStdout(), nil),
+			}
+			session.table.Lang = runtime.Language()
+			if err := session.load(); err != nil {
+				return writeCommandError(cmd, runtime, "meta tui", err)
+			}
+			return session.run(cmd.InOrStdin())
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&accountID, "account-id", "", "Ad account id (with or without act_ prefix)")
+	addAccountNameFlag(cmd, &accountName)
+	cmd.Flags().BoolVar(&cached, "cached", false, "Build the tree from the local campaign/ad set cache instead of reading the Graph API")
+	return cmd
+}
+
+func resolveTUIProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
+	resolvedProfile := strings.TrimSpace(profile)
+	if resolvedProfile == "" {
+		resolvedProfile = runtime.ProfileName()
+	}
+	if resolvedProfile == "" {
+		return nil, "", errors.New("profile is required (--profile or global --profile)")
+	}
+
+	creds, err := tuiLoadProfileCredentials(resolvedProfile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resolvedVersion := strings.TrimSpace(version)
+	if resolvedVersion == "" {
+		resolvedVersion = creds.Profile.GraphVersion
+	}
+	if resolvedVersion == "" {
+		resolvedVersion = config.DefaultGraphVersion
+	}
+	return creds, resolvedVersion, nil
+}
+
+func (s *tuiSession) run(in io.Reader) error {
+	if err := s.render(); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(in)
+	for {
+		if _, err := fmt.Fprint(s.out, "> "); err != nil {
+			return err
+		}
+		if !scanner.Scan() {
+			_, err := fmt.Fprintln(s.out)
+			if scanErr := scanner.Err(); scanErr != nil {
+				return scanErr
+			}
+			return err
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "q" || fields[0] == "quit" {
+			return nil
+		}
+		if err := s.dispatch(fields, scanner); err != nil {
+			if ctxErr := s.ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if _, writeErr := fmt.Fprintf(s.out, "error: %s\n", err.Error()); writeErr != nil {
+				return writeErr
+			}
+		}
+	}
+}
+
+func (s *tuiSession) dispatch(fields []string, scanner *bufio.Scanner) error {
+	switch fields[0] {
+	case "?", "h", "help":
+		_, err := fmt.Fprintln(s.out, tuiHelp)
+		return err
+	case "g", "refresh":
+		if err := s.load(); err != nil {
+			return err
+		}
+		return s.render()
+	case "p", "pause", "r", "resume", "d", "diagnose":
+	default:
+		return fmt.Errorf("unknown command %q; %s", fields[0], tuiHelp)
+	}
+
+	if len(fields) != 2 {
+		return fmt.Errorf("%s expects one row number", fields[0])
+	}
+	node, err := s.node(fields[1])
+	if err != nil {
+		return err
+	}
+	switch fields[0] {
+	case "d", "diagnose":
+		return s.diagnose(node)
+	case "p", "pause":
+		return s.setStatus(node, marketing.CampaignStatusPaused)
+	default:
+		// Resuming starts spend, so it is confirmed like other budget-affecting changes.
+		if _, err := fmt.Fprint(s.out, i18n.Sprintf(s.table.Lang, "resume %s %s (%s)? [y/N] ", node.Kind, node.ID, node.Name)); err != nil {
+			return err
+		}
+		if !scanner.Scan() || !confirmed(scanner.Text()) {
+			_, err := fmt.Fprintln(s.out, i18n.Translate(s.table.Lang, "resume canceled"))
+			return err
+		}
+		return s.setStatus(node, marketing.CampaignStatusActive)
+	}
+}
+
+// confirmed accepts yes in English or Turkish.
+func confirmed(answer string) bool {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "e", "evet":
+		return true
+	}
+	return false
+}
+
+func (s *tuiSession) node(raw string) (tuiNode, error) {
+	index, err := strconv.Atoi(raw)
+	if err != nil || index < 1 || index > len(s.nodes) {
+		return tuiNode{}, fmt.Errorf("row %q does not exist; pick 1-%d", raw, len(s.nodes))
+	}
+	return s.nodes[index-1], nil
+}
+
+func (s *tuiSession) load() error {
+	if s.cached {
+		return s.loadCached()
+	}
+	client := tuiNewGraphClient()
+	if !s.currencyResolved {
+		s.currency = s.lookupCurrency(client)
+		s.currencyResolved = true
+	}
+	campaigns, err := marketing.NewCampaignService(client).List(s.ctx, s.version, s.creds.Token, s.creds.AppSecret, marketing.CampaignListInput{
+		AccountID:  s.accountID,
+		Fields:     tuiCampaignFields,
+		FollowNext: true,
+	})
+	if err != nil {
+		return err
+	}
+	adsets, err := marketing.NewAdSetService(client).List(s.ctx, s.version, s.creds.Token, s.creds.AppSecret, marketing.AdSetListInput{
+		AccountID:  s.accountID,
+		Fields:     tuiAdSetFields,
+		FollowNext: true,
+	})
+	if err != nil {
+		return err
+	}
+	ads, err := marketing.NewAdService(client).List(s.ctx, s.version, s.creds.Token, s.creds.AppSecret, marketing.AdListInput{
+		AccountID:  s.accountID,
+		Fields:     tuiAdFields,
+		FollowNext: true,
+	})
+	if err != nil {
+		return err
+	}
+	recordListedEntities(s.creds.Name, marketing.EntityKindCampaign, s.accountID, campaigns.Campaigns)
+	recordListedEntities(s.creds.Name, marketing.EntityKindAdSet, s.accountID, adsets.AdSets)
+	s.nodes = buildTUITree(campaigns.Campaigns, adsets.AdSets, ads.Ads, s.currency)
+	return nil
+}
+
+// lookupCurrency is best effort: without a currency budgets show raw minor units.
+func (s *tuiSession) lookupCurrency(client *graph.Client) string {
+	currency, err := marketing.AccountContextCacheFrom(s.ctx).Currency(s.ctx, client, s.version, s.creds.Token, s.creds.AppSecret, s.accountID)
+	if err != nil {
+		return ""
+	}
+	return currency
+}
+
+// loadCached rebuilds the tree from the entity cache. Ads and budgets are not
+// cached, so only campaigns and ad sets with their last seen status appear.
+func (s *tuiSession) loadCached() error {
+	path, err := resolveEntityCachePath()
+	if err != nil {
+		return err
+	}
+	cache, err := marketing.LoadEntityCache(path)
+	if err != nil {
+		return fmt.Errorf("load cached tree: %w; run without --cached first", err)
+	}
+	toItems := func(kind string) []map[string]any {
+		items := []map[string]any{}
+		for _, entity := range cache.Entities(s.creds.Name, kind) {
+			if entity.AccountID != "" && entity.AccountID != s.accountID {
+				continue
+			}
+			items = append(items, map[string]any{
+				"id":               entity.ID,
+				"name":             entity.Name,
+				"effective_status": entity.Status,
+				"campaign_id":      entity.CampaignID,
+			})
+		}
+		return items
+	}
+	s.nodes = buildTUITree(toItems(marketing.EntityKindCampaign), toItems(marketing.EntityKindAdSet), nil, "")
+	if len(s.nodes) == 0 {
+		return fmt.Errorf("no cached campaigns for act_%s; run without --cached first", s.accountID)
+	}
+	return nil
+}
+
+// buildTUITree orders campaigns by name with their ad sets and ads nested below.
+// Children whose parent is not listed are dropped.
+func buildTUITree(campaigns []map[string]any, adsets []map[string]any, ads []map[string]any, currency string) []tuiNode {
+	adsetsByCampaign := groupTUIItems(adsets, "campaign_id")
+	adsByAdSet := groupTUIItems(ads, "adset_id")
+
+	nodes := []tuiNode{}
+	for _, campaign := range sortTUIItems(campaigns) {
+		nodes = append(nodes, newTUINode(marketing.EntityKindCampaign, campaign, 0, currency))
+		for _, adset := range sortTUIItems(adsetsByCampaign[tuiItemString(campaign, "id")]) {
+			nodes = append(nodes, newTUINode(marketing.EntityKindAdSet, adset, 1, currency))
+			for _, ad := range sortTUIItems(adsByAdSet[tuiItemString(adset, "id")]) {
+				nodes = append(nodes, newTUINode(tuiKindAd, ad, 2, currency))
+			}
+		}
+	}
+	return nodes
+}
+
+func groupTUIItems(items []map[string]any, parentKey string) map[string][]map[string]any {
+	grouped := map[string][]map[string]any{}
+	for _, item := range items {
+		parent := tuiItemString(item, parentKey)
+		grouped[parent] = append(grouped[parent], item)
+	}
+	return grouped
+}
+
+func sortTUIItems(items []map[string]any) []map[string]any {
+	sorted := append([]map[string]any(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		left, right := strings.ToLower(tuiItemString(sorted[i], "name")), strings.ToLower(tuiItemString(sorted[j], "name"))
+		if left != right {
+			return left < right
+		}
+		return tuiItemString(sorted[i], "id") < tuiItemString(sorted[j], "id")
+	})
+	return sorted
+}
+
+func newTUINode(kind string, item map[string]any, depth int, currency string) tuiNode {
+	budget := ""
+	if daily := tuiItemString(item, "daily_budget"); daily != "" && daily != "0" {
+		budget = tuiMoney(daily, currency) + "/day"
+	} else if lifetime := tuiItemString(item, "lifetime_budget"); lifetime != "" && lifetime != "0" {
+		budget = tuiMoney(lifetime, currency) + " lifetime"
+	}
+	return tuiNode{
+		Kind:            kind,
+		ID:              tuiItemString(item, "id"),
+		Name:            tuiItemString(item, "name"),
+		Status:          tuiItemString(item, "status"),
+		EffectiveStatus: tuiItemString(item, "effective_status"),
+		Budget:          budget,
+		Depth:           depth,
+	}
+}
+
+func tuiMoney(minorUnits string, currency string) string {
+	if formatted, ok := output.FormatMoneyValue(minorUnits, currency); ok {
+		return formatted
+	}
+	return minorUnits
+}
+
+func tuiItemString(item map[string]any, key string) string {
+	value, ok := item[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(value))
+}
+
+func (s *tuiSession) render() error {
+	if s.table.Width > 0 {
+		// Redraw in place on a terminal; redirected sessions keep a plain log.
+		if _, err := fmt.Fprint(s.out, "\x1b[H\x1b[2J"); err != nil {
+			return err
+		}
+	}
+	source := "live"
+	if s.cached {
+		source = "cached"
+	}
+	if _, err := fmt.Fprintf(s.out, "act_%s · profile %s · %s\n", s.accountID, s.creds.Name, source); err != nil {
+		return err
+	}
+
+	rows := make([]map[string]any, 0, len(s.nodes))
+	for index, node := range s.nodes {
+		rows = append(rows, map[string]any{
+			"#":                strconv.Itoa(index + 1),
+			"name":             strings.Repeat("  ", node.Depth) + node.Name,
+			"kind":             node.Kind,
+			"effective_status": node.EffectiveStatus,
+			"budget":           node.Budget,
+			"id":               node.ID,
+		})
+	}
+	options := s.table
+	options.Columns = []string{"#", "name", "kind", "effective_status", "budget", "id"}
+	if err := s.writeTable(rows, options); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(s.out, tuiHelp)
+	return err
+}
+
+func (s *tuiSession) writeTable(data any, options output.TableOptions) error {
+	envelope, err := output.NewEnvelope("meta tui", true, data, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return output.WriteWithOptions(s.out, "table", envelope, options)
+}
+
+func (s *tuiSession) setStatus(node tuiNode, status string) error {
+	client := tuiNewGraphClient()
+	var err error
+	switch node.Kind {
+	case marketing.EntityKindCampaign:
+		_, err = marketing.NewCampaignService(client).SetStatus(s.ctx, s.version, s.creds.Token, s.creds.AppSecret, marketing.CampaignStatusInput{CampaignID: node.ID, Status: status})
+	case marketing.EntityKindAdSet:
+		_, err = marketing.NewAdSetService(client).SetStatus(s.ctx, s.version, s.creds.Token, s.creds.AppSecret, marketing.AdSetStatusInput{AdSetID: node.ID, Status: status})
+	default:
+		_, err = marketing.NewAdService(client).SetStatus(s.ctx, s.version, s.creds.Token, s.creds.AppSecret, marketing.AdStatusInput{AdID: node.ID, Status: status})
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.out, "%s %s set to %s\n", node.Kind, node.ID, status); err != nil {
+		return err
+	}
+	if s.cached {
+		// Nothing to re-read; reflect the change locally until the next live load.
+		for index := range s.nodes {
+			if s.nodes[index].ID == node.ID {
+				s.nodes[index].Status = status
+				s.nodes[index].EffectiveStatus = status
+			}
+		}
+		return s.render()
+	}
+	if err := s.load(); err != nil {
+		return err
+	}
+	return s.render()
+}
+
+func (s *tuiSession) diagnose(node tuiNode) error {
+	response, err := tuiNewGraphClient().Do(s.ctx, graph.Request{
+		Method:      "GET",
+		Path:        node.ID,
+		Version:     s.version,
+		Query:       map[string]string{"fields": strings.Join(tuiDiagnoseFields[node.Kind], ",")},
+		AccessToken: s.creds.Token,
+		AppSecret:   s.creds.AppSecret,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.out, "%s %s\n", node.Kind, node.ID); err != nil {
+		return err
+	}
+	return s.writeTable(response.Body, s.table)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func useTUIDependencies(t *testing.T, client *adsetQueuedHTTPClient) {
+	t.Helper()
+	originalLoad := tuiLoadProfileCredentials
+	originalClient := tuiNewGraphClient
+	t.Cleanup(func() {
+		tuiLoadProfileCredentials = originalLoad
+		tuiNewGraphClient = originalClient
+	})
+	tuiLoadProfileCredentials = func(string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{
+			Name:    "prod",
+			Profile: config.Profile{Domain: config.DefaultDomain, GraphVersion: config.DefaultGraphVersion},
+			Token:   "test-token",
+		}, nil
+	}
+	tuiNewGraphClient = func() *graph.Client {
+		graphClient := graph.NewClient(client, "https://graph.example.com")
+		graphClient.MaxRetries = 0
+		return graphClient
+	}
+}
+
+func tuiTreeResponses() []adsetQueuedResponse {
+	return []adsetQueuedResponse{
+		{body: `{"data":[{"id":"c1","name":"Spring","status":"ACTIVE","effective_status":"ACTIVE","daily_budget":"5000"}]}`},
+		{body: `{"data":[{"id":"s1","name":"Lookalikes","status":"ACTIVE","effective_status":"ACTIVE","campaign_id":"c1"}]}`},
+		{body: `{"data":[{"id":"a1","name":"Video","status":"ACTIVE","effective_status":"ACTIVE","adset_id":"s1"}]}`},
+	}
+}
+
+// tuiCurrencyResponse answers the account currency lookup made once, before the
+// first tree load.
+func tuiCurrencyResponse() adsetQueuedResponse {
+	return adsetQueuedResponse{
+		body: `{"id":"act_1234","currency":"TRY"}`,
+		assert: func(t *testing.T, req *http.Request, _ string) {
+			if !strings.HasSuffix(req.URL.Path, "/act_1234") || req.URL.Query().Get("fields") != "currency" {
+				t.Fatalf("expected account currency lookup, got %s", req.URL.String())
+			}
+		},
+	}
+}
+
+func TestTUIRendersTreeAndPausesRow(t *testing.T) {
+	t.Setenv(entityCachePathEnv, filepath.Join(t.TempDir(), "entities.json"))
+	responses := append([]adsetQueuedResponse{tuiCurrencyResponse()}, tuiTreeResponses()...)
+	responses = append(responses, adsetQueuedResponse{
+		body: `{"success":true}`,
+		assert: func(t *testing.T, req *http.Request, body string) {
+			if req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, "/s1") || !strings.Contains(body, "status=PAUSED") {
+				t.Fatalf("expected ad set pause request, got %s %s %s", req.Method, req.URL.Path, body)
+			}
+		},
+	})
+	responses = append(responses, tuiTreeResponses()...)
+	client := &adsetQueuedHTTPClient{t: t, responses: responses}
+	useTUIDependencies(t, client)
+
+	output := &bytes.Buffer{}
+	cmd := NewTUICommand(testRuntime("prod"))
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetIn(strings.NewReader("p 2\nd 9\nq\n"))
+	cmd.SetArgs([]string{"--account-id", "act_1234"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("tui: %v", err)
+	}
+
+	got := output.String()
+	for _, want := range []string{"Spring", "  Lookalikes", "    Video", "₺50,00 (5000 minor units, TRY)/day", "adset s1 set to PAUSED", `row "9" does not exist`} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in tui output:\n%s", want, got)
+		}
+	}
+	if client.calls != len(responses) {
+		t.Fatalf("expected %d graph calls, got %d", len(responses), client.calls)
+	}
+}
+
+func TestTUIResumeRequiresConfirmation(t *testing.T) {
+	t.Setenv(entityCachePathEnv, filepath.Join(t.TempDir(), "entities.json"))
+	client := &adsetQueuedHTTPClient{t: t, responses: append([]adsetQueuedResponse{tuiCurrencyResponse()}, tuiTreeResponses()...)}
+	useTUIDependencies(t, client)
+
+	output := &bytes.Buffer{}
+	cmd := NewTUICommand(testRuntime("prod"))
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetIn(strings.NewReader("r 1\nn\nq\n"))
+	cmd.SetArgs([]string{"--account-id", "1234"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("tui: %v", err)
+	}
+	if !strings.Contains(output.String(), "resume canceled") || client.calls != 4 {
+		t.Fatalf("expected resume to be canceled without graph calls, got %d calls:\n%s", client.calls, output.String())
+	}
+}
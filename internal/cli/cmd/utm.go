@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/bilalbayram/metacli/internal/transport"
+	"github.com/spf13/cobra"
+)
+
+// utmCheckHTTPClient requests links for --check-urls. It is a plain HTTP
+// client, not the Graph one, and refuses to run under --offline.
+var utmCheckHTTPClient = func() *http.Client {
+	return &http.Client{Timeout: 15 * time.Second, Transport: transport.RefuseOffline(nil)}
+}
+
+// utmAdSetPlaceholders are filled from the ad set and its campaign.
+var utmAdSetPlaceholders = []string{"adset.name", "campaign.id", "campaign.name"}
+
+type utmFlags struct {
+	template  string
+	vars      []string
+	checkURLs bool
+}
+
+func addUTMFlags(cmd *cobra.Command, flags *utmFlags) {
+	cmd.Flags().StringVar(&flags.template, "utm-template", "", "UTM query template for link URLs, e.g. \"utm_source=meta&utm_campaign={{campaign.name}}\"; placeholders: {{"+strings.Join(marketing.UTMPlaceholders, "}}, {{")+"}}")
+	cmd.Flags().StringArrayVar(&flags.vars, "utm-var", nil, "UTM placeholder value (name=value, e.g. campaign.name=Summer, repeatable); overrides values read from Graph")
+	cmd.Flags().BoolVar(&flags.checkURLs, "check-urls", false, "Check that every tagged link resolves with a HEAD request (GET when HEAD is refused) before creating")
+}
+
+// utmAdSetLookup reads the ad set and campaign placeholders of adSetID.
+type utmAdSetLookup func(ctx context.Context, adSetID string) (map[string]string, error)
+
+// render parses the template and fills it from known, then from the ad set
+// when a placeholder needs it, then from --utm-var. It returns nil tags when
+// --utm-template is not set.
+func (f utmFlags) render(ctx context.Context, known map[string]string, adSetID string, lookup utmAdSetLookup) ([]marketing.UTMTag, error) {
+	if strings.TrimSpace(f.template) == "" {
+		if len(f.vars) > 0 || f.checkURLs {
+			return nil, errors.New("--utm-var and --check-urls require --utm-template")
+		}
+		return nil, nil
+	}
+	template, err := marketing.ParseUTMTemplate(f.template)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := parseUTMVars(f.vars)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]string{}
+	for name, value := range known {
+		vars[name] = value
+	}
+	if strings.TrimSpace(adSetID) != "" && needsUTMAdSet(template.Placeholders(), overrides) {
+		adSetVars, err := lookup(ctx, adSetID)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range adSetVars {
+			vars[name] = value
+		}
+	}
+	for name, value := range overrides {
+		vars[name] = value
+	}
+	return template.Render(vars)
+}
+
+func needsUTMAdSet(placeholders []string, overrides map[string]string) bool {
+	for _, name := range placeholders {
+		if _, ok := overrides[name]; !ok && slices.Contains(utmAdSetPlaceholders, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseUTMVars(entries []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, entry := range entries {
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --utm-var entry %q; expected name=value", entry)
+		}
+		if !slices.Contains(marketing.UTMPlaceholders, name) {
+			return nil, fmt.Errorf("invalid --utm-var name %q: expected one of %s", name, strings.Join(marketing.UTMPlaceholders, ", "))
+		}
+		if _, exists := vars[name]; exists {
+			return nil, fmt.Errorf("duplicate --utm-var %q", name)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// tagCreativeLinks tags the links of the creative spec params in form in
+// place.
+func tagCreativeLinks(form map[string]string, tags []marketing.UTMTag) ([]marketing.TaggedLink, error) {
+	links := make([]marketing.TaggedLink, 0)
+	for _, key := range []string{"object_story_spec", "asset_feed_spec"} {
+		spec, ok := form[key]
+		if !ok {
+			continue
+		}
+		tagged, specLinks, err := marketing.TagCreativeSpec(key, spec, tags)
+		if err != nil {
+			return nil, err
+		}
+		form[key] = tagged
+		links = append(links, specLinks...)
+	}
+	if len(links) == 0 {
+		return nil, errors.New("--utm-template found no link URLs to tag in object_story_spec or asset_feed_spec")
+	}
+	return links, nil
+}
+
+// requireTaggedCreativeLinks fails unless every link of the ad's creative
+// carries tags. An ad references an existing creative, whose links cannot be
+// changed, so ad create checks the tags instead of adding them.
+func requireTaggedCreativeLinks(creativeID string, links []marketing.TaggedLink, tags []marketing.UTMTag) error {
+	if len(links) == 0 {
+		return fmt.Errorf("creative %s has no link URLs to carry the --utm-template tags", creativeID)
+	}
+	for _, link := range links {
+		missing, err := marketing.MissingUTMTags(link.URL, tags)
+		if err != nil {
+			return fmt.Errorf("creative %s %s: %w", creativeID, link.Path, err)
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("creative %s link %s (%s) is missing utm tags %s; create the creative with --utm-template first", creativeID, link.Path, link.URL, strings.Join(missing, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func utmTestCredentials(string) (*ProfileCredentials, error) {
+	return &ProfileCredentials{
+		Name: "prod",
+		Profile: config.Profile{
+			Domain:       config.DefaultDomain,
+			GraphVersion: config.DefaultGraphVersion,
+		},
+		Token: "test-token",
+	}, nil
+}
+
+func TestCreativeCreateTagsSpecLinksFromUTMTemplate(t *testing.T) {
+	landing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Fatalf("unexpected check method %s", r.Method)
+		}
+		if got := r.URL.Query().Get("utm_campaign"); got != "Summer Sale" {
+			t.Fatalf("unexpected utm_campaign on checked link %q", got)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer landing.Close()
+
+	var postedSpec string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v25.0/adset_7":
+			if got := r.URL.Query().Get("fields"); got != "id,name,campaign{id,name}" {
+				t.Fatalf("unexpected ad set fields %q", got)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":       "adset_7",
+				"name":     "Prospecting",
+				"campaign": map[string]any{"id": "cmp_3", "name": "Summer Sale"},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/v25.0/act_1234/adcreatives":
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("read create request body: %v", err)
+			}
+			form, err := url.ParseQuery(string(body))
+			if err != nil {
+				t.Fatalf("parse create request body: %v", err)
+			}
+			postedSpec = form.Get("object_story_spec")
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "crt_12"})
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	useCreativeDependencies(t, utmTestCredentials, func() *graph.Client {
+		client := graph.NewClient(server.Client(), server.URL)
+		client.MaxRetries = 0
+		return client
+	})
+
+	output := &bytes.Buffer{}
+	errOutput := &bytes.Buffer{}
+	cmd := NewCreativeCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{
+		"create",
+		"--account-id", "1234",
+		"--json", `{"name":"Launch Creative","object_story_spec":{"page_id":"99","link_data":{"link":"` + landing.URL + `/shop?ref=home","message":"Hi"}}}`,
+		"--utm-template", "utm_source=meta&utm_campaign={{campaign.name}}&utm_content={{creative.name}}",
+		"--utm-adset-id", "adset_7",
+		"--check-urls",
+		"--schema-dir", writeCreativeSchemaPack(t),
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute creative create: %v\nstderr: %s", err, errOutput.String())
+	}
+
+	wantLink := landing.URL + "/shop?ref=home&utm_source=meta&utm_campaign=Summer%20Sale&utm_content=Launch%20Creative"
+	if !strings.Contains(postedSpec, `"link":"`+wantLink+`"`) {
+		t.Fatalf("posted spec does not carry the tagged link %q: %s", wantLink, postedSpec)
+	}
+
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta creative create")
+	data := envelope["data"].(map[string]any)
+	links, ok := data["tagged_links"].([]any)
+	if !ok || len(links) != 1 {
+		t.Fatalf("expected one tagged link, got %#v", data["tagged_links"])
+	}
+	link := links[0].(map[string]any)
+	if link["path"] != "object_story_spec.link_data.link" || link["url"] != wantLink || link["status"] != float64(200) {
+		t.Fatalf("unexpected tagged link %#v", link)
+	}
+}
+
+func TestCreativeCreateFailsWhenUTMPlaceholderHasNoValue(t *testing.T) {
+	wasCalled := false
+	useCreativeDependencies(t, utmTestCredentials, func() *graph.Client {
+		wasCalled = true
+		return graph.NewClient(nil, "")
+	})
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewCreativeCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{
+		"create",
+		"--account-id", "1234",
+		"--json", `{"name":"Launch Creative","object_story_spec":{"link_data":{"link":"https://example.com"}}}`,
+		"--utm-template", "utm_campaign={{campaign.name}}",
+		"--schema-dir", writeCreativeSchemaPack(t),
+	})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected command error")
+	}
+	if !strings.Contains(err.Error(), "pass --utm-var campaign.name=<value>") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wasCalled {
+		t.Fatal("graph client should not execute when a placeholder has no value")
+	}
+	decodeEnvelope(t, errOutput.Bytes())
+}
+
+func TestAdCreateRejectsCreativeLinksMissingUTMTags(t *testing.T) {
+	schemaDir := writeAdSchemaPack(t)
+	posted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v25.0/adset_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "adset_1"})
+		case r.Method == http.MethodGet && r.URL.Path == "/v25.0/creative_1":
+			if r.URL.Query().Get("fields") == "object_story_spec,asset_feed_spec" {
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"id": "creative_1",
+					"object_story_spec": map[string]any{
+						"link_data": map[string]any{"link": "https://example.com/shop?utm_source=meta"},
+					},
+				})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "creative_1"})
+		case r.Method == http.MethodPost:
+			posted = true
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "ad_1"})
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	useAdDependencies(t, utmTestCredentials, func() *graph.Client {
+		client := graph.NewClient(server.Client(), server.URL)
+		client.MaxRetries = 0
+		return client
+	})
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewAdCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{
+		"create",
+		"--account-id", "1234",
+		"--params", "name=Iteration A,adset_id=adset_1,status=PAUSED",
+		"--json", `{"creative":{"creative_id":"creative_1"}}`,
+		"--utm-template", "utm_source=meta&utm_content={{ad.name}}",
+		"--schema-dir", schemaDir,
+	})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected command error")
+	}
+	if !strings.Contains(err.Error(), "creative creative_1 link object_story_spec.link_data.link") || !strings.Contains(err.Error(), "utm_content=Iteration A") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if posted {
+		t.Fatal("ad should not be created when its creative links miss utm tags")
+	}
+	decodeEnvelope(t, errOutput.Bytes())
+}
+
+func TestParseUTMVarsValidatesNames(t *testing.T) {
+	vars, err := parseUTMVars([]string{"campaign.name=Summer=Sale", "ad.name="})
+	if err != nil {
+		t.Fatalf("parse utm vars: %v", err)
+	}
+	if vars["campaign.name"] != "Summer=Sale" || vars["ad.name"] != "" {
+		t.Fatalf("unexpected vars %#v", vars)
+	}
+
+	for _, entries := range [][]string{{"campaign"}, {"campaign.budget=1"}, {"ad.name=a", "ad.name=b"}} {
+		if _, err := parseUTMVars(entries); err == nil {
+			t.Fatalf("expected error for %q", entries)
+		}
+	}
+}
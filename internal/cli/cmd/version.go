@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"runtime/debug"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/selfupdate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	newSelfUpdater       = selfupdate.New
+	selfUpdateExecutable = os.Executable
+)
+
+type buildInfo struct {
+	Version    string `json:"version"`
+	GoVersion  string `json:"go_version"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+	Commit     string `json:"commit,omitempty"`
+	CommitTime string `json:"commit_time,omitempty"`
+	Modified   bool   `json:"modified,omitempty"`
+
+	Update *selfupdate.Check `json:"update,omitempty"`
+}
+
+type selfUpdateResult struct {
+	Previous   string `json:"previous"`
+	Installed  string `json:"installed"`
+	Executable string `json:"executable"`
+	Artifact   string `json:"artifact"`
+	// Updated is false when the binary is already current, and under
+	// --dry-run, where Installed names the release that would be installed.
+	Updated bool `json:"updated"`
+}
+
+// updateSourceFlags are the manifest settings shared by version --check-update
+// and self-update; they mirror schema sync's.
+type updateSourceFlags struct {
+	channel     string
+	manifestURL string
+	publicKey   string
+}
+
+func (f *updateSourceFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.channel, "channel", "stable", "Release channel to check")
+	cmd.Flags().StringVar(&f.manifestURL, "manifest-url", selfupdate.DefaultManifestURL, "Signed release manifest URL")
+	cmd.Flags().StringVar(&f.publicKey, "public-key", selfupdate.DefaultPublicKey, "Base64 Ed25519 public key for manifest verification")
+}
+
+func NewVersionCommand(runtime Runtime, version string) *cobra.Command {
+	var (
+		checkUpdate bool
+		source      updateSourceFlags
+	)
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show build information and optionally check for a newer release",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			info := currentBuildInfo(version)
+			if checkUpdate {
+				check, _, err := newSelfUpdater(source.manifestURL, source.publicKey).Check(cmd.Context(), source.channel, version)
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta version", err)
+				}
+				info.Update = &check
+			}
+			return writeSuccess(cmd, runtime, "meta version", info, nil, nil)
+		},
+	}
+	cmd.Flags().BoolVar(&checkUpdate, "check-update", false, "Check the signed release manifest for a newer version")
+	source.register(cmd)
+	return cmd
+}
+
+func NewSelfUpdateCommand(runtime Runtime, version string) *cobra.Command {
+	var (
+		force  bool
+		source updateSourceFlags
+	)
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Replace this binary with the latest signed release",
+		Long: "Downloads the release for this platform named in the signed release manifest, checks it\n" +
+			"against the SHA-256 in the manifest and swaps it in place of the running binary. The\n" +
+			"manifest is verified with the same Ed25519 trust model as schema sync.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			updater := newSelfUpdater(source.manifestURL, source.publicKey)
+			check, manifest, err := updater.Check(cmd.Context(), source.channel, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta self-update", err)
+			}
+			if !check.Comparable && !force {
+				return writeCommandError(cmd, runtime, "meta self-update", fmt.Errorf("version %q is not a release build; rerun with --force to install %s", version, check.Latest))
+			}
+			executable, err := resolveExecutable()
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta self-update", err)
+			}
+			result := selfUpdateResult{Previous: version, Installed: version, Executable: executable}
+			if !check.UpdateAvailable && !force {
+				return writeSuccess(cmd, runtime, "meta self-update", result, nil, nil)
+			}
+			if graph.DryRunFromContext(cmd.Context()) != nil {
+				artifact, ok := manifest.Artifact(goruntime.GOOS, goruntime.GOARCH)
+				if !ok {
+					return writeCommandError(cmd, runtime, "meta self-update", fmt.Errorf("release %s has no build for %s/%s", manifest.Version, goruntime.GOOS, goruntime.GOARCH))
+				}
+				result.Installed = manifest.Version
+				result.Artifact = artifact.URL
+				return writeSuccess(cmd, runtime, "meta self-update", result, nil, nil)
+			}
+			artifact, err := updater.Install(cmd.Context(), manifest, goruntime.GOOS, goruntime.GOARCH, executable)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta self-update", err)
+			}
+			result.Installed = manifest.Version
+			result.Artifact = artifact.URL
+			result.Updated = true
+			return writeSuccess(cmd, runtime, "meta self-update", result, nil, nil)
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, "Install the manifest's release even when it is not newer, or this is not a release build")
+	source.register(cmd)
+	return cmd
+}
+
+func resolveExecutable() (string, error) {
+	executable, err := selfUpdateExecutable()
+	if err != nil {
+		return "", fmt.Errorf("locate the running binary: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(executable)
+	if err != nil {
+		return "", fmt.Errorf("locate the running binary: %w", err)
+	}
+	return resolved, nil
+}
+
+func currentBuildInfo(version string) buildInfo {
+	info := buildInfo{
+		Version:   version,
+		GoVersion: goruntime.Version(),
+		OS:        goruntime.GOOS,
+		Arch:      goruntime.GOARCH,
+	}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Commit = setting.Value
+		case "vcs.time":
+			info.CommitTime = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
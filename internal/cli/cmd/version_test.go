@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/selfupdate"
+)
+
+func TestVersionReportsBuildInfo(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewVersionCommand(testRuntime(""), "v1.4.2")
+	stdout := &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	envelope := decodeEnvelope(t, stdout.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta version")
+	data := envelope["data"].(map[string]any)
+	if data["version"] != "v1.4.2" || data["os"] != goruntime.GOOS || data["arch"] != goruntime.GOARCH {
+		t.Fatalf("unexpected build info: %#v", data)
+	}
+	if _, ok := data["update"]; ok {
+		t.Fatalf("update check must be opt-in: %#v", data)
+	}
+}
+
+func TestVersionCheckUpdateReportsLatestRelease(t *testing.T) {
+	t.Parallel()
+
+	server, publicKey := newReleaseServer(t, "v1.5.0", []byte("new binary"))
+	cmd := NewVersionCommand(testRuntime(""), "v1.4.2")
+	stdout := &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--check-update", "--manifest-url", server.URL + "/release-manifest.json", "--public-key", publicKey})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	envelope := decodeEnvelope(t, stdout.Bytes())
+	update := envelope["data"].(map[string]any)["update"].(map[string]any)
+	if update["latest"] != "v1.5.0" || update["update_available"] != true || update["channel"] != "stable" {
+		t.Fatalf("unexpected update check: %#v", update)
+	}
+}
+
+func TestVersionCheckUpdateFailsOnUntrustedManifest(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newReleaseServer(t, "v1.5.0", []byte("new binary"))
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate keypair: %v", err)
+	}
+	cmd := NewVersionCommand(testRuntime(""), "v1.4.2")
+	stderr := &bytes.Buffer{}
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"--check-update", "--manifest-url", server.URL + "/release-manifest.json", "--public-key", base64.StdEncoding.EncodeToString(otherKey)})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected untrusted manifest to fail")
+	}
+
+	envelope := decodeEnvelope(t, stderr.Bytes())
+	errorBody := envelope["error"].(map[string]any)
+	if !strings.Contains(errorBody["message"].(string), "signature verification failed") {
+		t.Fatalf("unexpected error: %#v", errorBody)
+	}
+}
+
+func TestSelfUpdateReplacesExecutable(t *testing.T) {
+	executable := useSelfUpdateExecutable(t)
+	server, publicKey := newReleaseServer(t, "v1.5.0", []byte("new binary"))
+
+	cmd := NewSelfUpdateCommand(testRuntime(""), "v1.4.2")
+	stdout := &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--manifest-url", server.URL + "/release-manifest.json", "--public-key", publicKey})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	envelope := decodeEnvelope(t, stdout.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta self-update")
+	data := envelope["data"].(map[string]any)
+	if data["updated"] != true || data["previous"] != "v1.4.2" || data["installed"] != "v1.5.0" {
+		t.Fatalf("unexpected self-update result: %#v", data)
+	}
+	raw, err := os.ReadFile(executable)
+	if err != nil {
+		t.Fatalf("read executable: %v", err)
+	}
+	if string(raw) != "new binary" {
+		t.Fatalf("executable not replaced: %q", raw)
+	}
+}
+
+func TestSelfUpdateSkipsCurrentAndDevBuilds(t *testing.T) {
+	executable := useSelfUpdateExecutable(t)
+	server, publicKey := newReleaseServer(t, "v1.5.0", []byte("new binary"))
+	args := []string{"--manifest-url", server.URL + "/release-manifest.json", "--public-key", publicKey}
+
+	cmd := NewSelfUpdateCommand(testRuntime(""), "v1.5.0")
+	stdout := &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(args)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if data := decodeEnvelope(t, stdout.Bytes())["data"].(map[string]any); data["updated"] != false {
+		t.Fatalf("expected no update for current release: %#v", data)
+	}
+
+	cmd = NewSelfUpdateCommand(testRuntime(""), "dev")
+	stderr := &bytes.Buffer{}
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(stderr)
+	cmd.SetArgs(args)
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected dev build to require --force")
+	}
+	errorBody := decodeEnvelope(t, stderr.Bytes())["error"].(map[string]any)
+	if !strings.Contains(errorBody["message"].(string), "--force") {
+		t.Fatalf("unexpected error: %#v", errorBody)
+	}
+
+	raw, err := os.ReadFile(executable)
+	if err != nil {
+		t.Fatalf("read executable: %v", err)
+	}
+	if string(raw) != "old binary" {
+		t.Fatalf("executable changed: %q", raw)
+	}
+}
+
+func useSelfUpdateExecutable(t *testing.T) string {
+	t.Helper()
+
+	executable := filepath.Join(t.TempDir(), "meta")
+	if err := os.WriteFile(executable, []byte("old binary"), 0o755); err != nil {
+		t.Fatalf("write executable: %v", err)
+	}
+	original := selfUpdateExecutable
+	t.Cleanup(func() {
+		selfUpdateExecutable = original
+	})
+	selfUpdateExecutable = func() (string, error) {
+		return executable, nil
+	}
+	return executable
+}
+
+// newReleaseServer serves a signed manifest for version with binary as the
+// bare artifact for the platform running the tests.
+func newReleaseServer(t *testing.T, version string, binary []byte) (*httptest.Server, string) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate keypair: %v", err)
+	}
+	sum := sha256.Sum256(binary)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/meta" {
+			_, _ = w.Write(binary)
+			return
+		}
+		payload := selfupdate.ManifestPayload{
+			Channel:     "stable",
+			Version:     version,
+			PublishedAt: "2026-10-01T00:00:00Z",
+			Artifacts: []selfupdate.Artifact{
+				{OS: goruntime.GOOS, Arch: goruntime.GOARCH, URL: server.URL + "/meta", SHA256: hex.EncodeToString(sum[:])},
+			},
+		}
+		message, err := json.Marshal(payload)
+		if err != nil {
+			t.Errorf("marshal payload: %v", err)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(selfupdate.SignedManifest{
+			Payload:   payload,
+			Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, message)),
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, base64.StdEncoding.EncodeToString(pub)
+}
@@ -54,12 +54,17 @@ var builtinNamespaceBootstrapSpecs = []namespaceBootstrapSpec{
 		Capabilities: []namespaceCapability{
 			{Name: "send-event", Description: "Send conversion events to /events endpoint"},
 			{Name: "test-event", Description: "Validate conversion payloads using test_event_code"},
+			{Name: "pixel-stats", Description: "Read pixel event stats"},
 		},
 	},
 }
 
 func NewWACommand(runtime Runtime) *cobra.Command {
-	return newNamespaceBootstrapCommandForNamespace(runtime, "wa")
+	waCmd := newNamespaceBootstrapCommandForNamespace(runtime, "wa")
+	waCmd.AddCommand(newWASendCommand(runtime))
+	waCmd.AddCommand(newWATemplatesCommand(runtime))
+	waCmd.AddCommand(newWAPhoneNumbersCommand(runtime))
+	return waCmd
 }
 
 func newNamespaceBootstrapCommandForNamespace(runtime Runtime, namespace string) *cobra.Command {
@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/wa"
+	"github.com/spf13/cobra"
+)
+
+var (
+	waLoadProfileCredentials = loadProfileCredentials
+	waNewGraphClient         = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+)
+
+func newWASendCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile            string
+		version            string
+		phoneNumberID      string
+		to                 string
+		text               string
+		previewURL         bool
+		templateName       string
+		language           string
+		componentsRaw      string
+		componentsFilePath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "send",
+		Short: "Send a text or template message from a WhatsApp business phone number",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			components, err := readWATemplateComponents(componentsRaw, componentsFilePath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta wa send", err)
+			}
+			options := wa.SendMessageOptions{
+				PhoneNumberID:      phoneNumberID,
+				To:                 to,
+				Text:               text,
+				PreviewURL:         previewURL,
+				TemplateName:       templateName,
+				TemplateLanguage:   language,
+				TemplateComponents: components,
+			}
+			if _, err := wa.BuildMessagePayload(options); err != nil {
+				return writeCommandError(cmd, runtime, "meta wa send", err)
+			}
+
+			creds, resolvedVersion, err := resolveWAProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta wa send", err)
+			}
+			result, err := wa.New(waNewGraphClient()).SendMessage(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta wa send", err)
+			}
+			return writeSuccess(cmd, runtime, "meta wa send", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&phoneNumberID, "phone-number-id", "", "Sending business phone number id")
+	cmd.Flags().StringVar(&to, "to", "", "Recipient phone number with country code (for example 15551234567)")
+	cmd.Flags().StringVar(&text, "text", "", "Text message body (only inside the 24h customer service window)")
+	cmd.Flags().BoolVar(&previewURL, "preview-url", false, "Render a link preview for the first URL in --text")
+	cmd.Flags().StringVar(&templateName, "template", "", "Approved message template name")
+	cmd.Flags().StringVar(&language, "language", wa.DefaultTemplateLanguage, "Template language code")
+	cmd.Flags().StringVar(&componentsRaw, "components", "", "Inline JSON array of template components (header/body/button parameters)")
+	cmd.Flags().StringVar(&componentsFilePath, "components-file", "", "Path to a JSON array of template components")
+	cmd.MarkFlagsMutuallyExclusive("text", "template")
+	cmd.MarkFlagsMutuallyExclusive("components", "components-file")
+	mustMarkFlagRequired(cmd, "phone-number-id")
+	mustMarkFlagRequired(cmd, "to")
+	return cmd
+}
+
+func newWATemplatesCommand(runtime Runtime) *cobra.Command {
+	templatesCmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Manage WhatsApp message templates",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "wa templates")
+		},
+	}
+	templatesCmd.AddCommand(newWATemplatesListCommand(runtime))
+	templatesCmd.AddCommand(newWATemplatesCreateCommand(runtime))
+	templatesCmd.AddCommand(newWATemplatesStatusCommand(runtime))
+	return templatesCmd
+}
+
+func newWATemplatesListCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile    string
+		version    string
+		wabaID     string
+		name       string
+		status     string
+		fieldsRaw  string
+		limit      int
+		followNext bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List message templates on a WhatsApp Business Account",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveWAProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta wa templates list", err)
+			}
+
+			result, err := wa.New(waNewGraphClient()).ListTemplates(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, wa.ListTemplatesOptions{
+				WABAID:     resolveWABAID(creds, wabaID),
+				Name:       name,
+				Status:     status,
+				Fields:     csvToSlice(fieldsRaw),
+				Limit:      limit,
+				FollowNext: followNext,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta wa templates list", err)
+			}
+			return writeSuccess(cmd, runtime, "meta wa templates list", result.Templates, result.Paging, nil)
+		},
+	}
+
+	addWACommonFlags(cmd, &profile, &version, &wabaID)
+	cmd.Flags().StringVar(&name, "name", "", "Filter by template name")
+	cmd.Flags().StringVar(&status, "status", "", "Filter by review status: APPROVED|PENDING|REJECTED|PAUSED|DISABLED|IN_APPEAL")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated template fields (defaults to "+strings.Join(wa.DefaultTemplateFields, ",")+")")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of templates to return (0 = unlimited)")
+	cmd.Flags().BoolVar(&followNext, "follow-next", true, "Follow paging.next links")
+	return cmd
+}
+
+func newWATemplatesCreateCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile            string
+		version            string
+		wabaID             string
+		name               string
+		language           string
+		category           string
+		componentsRaw      string
+		componentsFilePath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Submit a message template for review",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			components, err := readWATemplateComponents(componentsRaw, componentsFilePath)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta wa templates create", err)
+			}
+			creds, resolvedVersion, err := resolveWAProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta wa templates create", err)
+			}
+
+			result, err := wa.New(waNewGraphClient()).CreateTemplate(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, wa.CreateTemplateOptions{
+				WABAID:     resolveWABAID(creds, wabaID),
+				Name:       name,
+				Language:   language,
+				Category:   category,
+				Components: components,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta wa templates create", err)
+			}
+			return writeSuccess(cmd, runtime, "meta wa templates create", result, nil, nil)
+		},
+	}
+
+	addWACommonFlags(cmd, &profile, &version, &wabaID)
+	cmd.Flags().StringVar(&name, "name", "", "Template name (lowercase letters, digits, underscores)")
+	cmd.Flags().StringVar(&language, "language", wa.DefaultTemplateLanguage, "Template language code")
+	cmd.Flags().StringVar(&category, "category", "", "Template category: MARKETING|UTILITY|AUTHENTICATION")
+	cmd.Flags().StringVar(&componentsRaw, "components", "", "Inline JSON array of template components")
+	cmd.Flags().StringVar(&componentsFilePath, "components-file", "", "Path to a JSON array of template components")
+	cmd.MarkFlagsMutuallyExclusive("components", "components-file")
+	cmd.MarkFlagsOneRequired("components", "components-file")
+	mustMarkFlagRequired(cmd, "name")
+	mustMarkFlagRequired(cmd, "category")
+	return cmd
+}
+
+func newWATemplatesStatusCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile    string
+		version    string
+		wabaID     string
+		templateID string
+		name       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show review status and quality of a message template",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveWAProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta wa templates status", err)
+			}
+
+			templates, err := wa.New(waNewGraphClient()).TemplateStatus(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, wa.TemplateStatusOptions{
+				TemplateID: templateID,
+				WABAID:     resolveWABAID(creds, wabaID),
+				Name:       name,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta wa templates status", err)
+			}
+			return writeSuccess(cmd, runtime, "meta wa templates status", templates, nil, nil)
+		},
+	}
+
+	addWACommonFlags(cmd, &profile, &version, &wabaID)
+	cmd.Flags().StringVar(&templateID, "template-id", "", "Message template id")
+	cmd.Flags().StringVar(&name, "name", "", "Template name (reports every language variant)")
+	cmd.MarkFlagsMutuallyExclusive("template-id", "name")
+	cmd.MarkFlagsOneRequired("template-id", "name")
+	return cmd
+}
+
+func newWAPhoneNumbersCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile    string
+		version    string
+		wabaID     string
+		fieldsRaw  string
+		limit      int
+		followNext bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "phone-numbers",
+		Short: "List business phone numbers with quality ratings and messaging limits",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveWAProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta wa phone-numbers", err)
+			}
+
+			result, err := wa.New(waNewGraphClient()).ListPhoneNumbers(cmd.Context(), resolvedVersion, creds.Token, creds.AppSecret, wa.ListPhoneNumbersOptions{
+				WABAID:     resolveWABAID(creds, wabaID),
+				Fields:     csvToSlice(fieldsRaw),
+				Limit:      limit,
+				FollowNext: followNext,
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta wa phone-numbers", err)
+			}
+			return writeSuccess(cmd, runtime, "meta wa phone-numbers", result.PhoneNumbers, result.Paging, nil)
+		},
+	}
+
+	addWACommonFlags(cmd, &profile, &version, &wabaID)
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated phone number fields (defaults to "+strings.Join(wa.DefaultPhoneNumberFields, ",")+")")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of phone numbers to return (0 = unlimited)")
+	cmd.Flags().BoolVar(&followNext, "follow-next", true, "Follow paging.next links")
+	return cmd
+}
+
+func addWACommonFlags(cmd *cobra.Command, profile *string, version *string, wabaID *string) {
+	cmd.Flags().StringVar(profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(wabaID, "waba-id", "", "WhatsApp Business Account id (optional when profile has waba_id)")
+}
+
+func readWATemplateComponents(raw string, filePath string) ([]map[string]any, error) {
+	source := "--components"
+	payload := []byte(strings.TrimSpace(raw))
+	if path := strings.TrimSpace(filePath); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read --components-file: %w", err)
+		}
+		source = "--components-file"
+		payload = data
+	}
+	if len(payload) == 0 {
+		return nil, nil
+	}
+
+	var components []map[string]any
+	if err := json.Unmarshal(payload, &components); err != nil {
+		return nil, fmt.Errorf("decode %s: expected a JSON array of component objects: %w", source, err)
+	}
+	return components, nil
+}
+
+func resolveWABAID(creds *ProfileCredentials, wabaID string) string {
+	resolved := strings.TrimSpace(wabaID)
+	if resolved == "" && creds != nil {
+		resolved = strings.TrimSpace(creds.Profile.WABAID)
+	}
+	return resolved
+}
+
+func resolveWAProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
+	resolvedProfile := strings.TrimSpace(profile)
+	if resolvedProfile == "" {
+		resolvedProfile = runtime.ProfileName()
+	}
+	if resolvedProfile == "" {
+		return nil, "", errors.New("profile is required (--profile or global --profile)")
+	}
+
+	creds, err := waLoadProfileCredentials(resolvedProfile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resolvedVersion := strings.TrimSpace(version)
+	if resolvedVersion == "" {
+		resolvedVersion = creds.Profile.GraphVersion
+	}
+	if resolvedVersion == "" {
+		resolvedVersion = config.DefaultGraphVersion
+	}
+	return creds, resolvedVersion, nil
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestWASendTemplateMessage(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"messaging_product":"whatsapp","messages":[{"id":"wamid.1"}]}`,
+	}
+	useWADependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewWACommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"send",
+		"--phone-number-id", "109",
+		"--to", "+15551234567",
+		"--template", "order_update",
+		"--components", `[{"type":"body","parameters":[{"type":"text","text":"A-1"}]}]`,
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute wa send: %v", err)
+	}
+	if stub.lastMethod != http.MethodPost || !strings.HasSuffix(stub.lastURL, "/v25.0/109/messages") {
+		t.Fatalf("unexpected request %s %s", stub.lastMethod, stub.lastURL)
+	}
+	form, err := url.ParseQuery(stub.lastBody)
+	if err != nil {
+		t.Fatalf("parse form body: %v", err)
+	}
+	if form.Get("type") != "template" || !strings.Contains(form.Get("template"), `"code":"en_US"`) {
+		t.Fatalf("unexpected form %v", form)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta wa send")
+}
+
+func TestWASendRejectsInvalidRecipientBeforeNetwork(t *testing.T) {
+	useWADependencies(t, nil)
+
+	cmd := NewWACommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"send", "--phone-number-id", "109", "--to", "call-me", "--text", "hi"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid recipient") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestWATemplatesListUsesProfileWABAID(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"id":"1","name":"order_update","status":"APPROVED"}]}`,
+	}
+	useWADependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewWACommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"templates", "list", "--status", "approved"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute wa templates list: %v", err)
+	}
+	if !strings.Contains(stub.lastURL, "/v25.0/waba_1/message_templates?") || !strings.Contains(stub.lastURL, "status=APPROVED") {
+		t.Fatalf("unexpected request url %q", stub.lastURL)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta wa templates list")
+}
+
+func TestWAPhoneNumbersListsQualityRatings(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"id":"109","display_phone_number":"+1 555-0100","quality_rating":"GREEN"}]}`,
+	}
+	useWADependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewWACommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"phone-numbers", "--waba-id", "waba_2"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute wa phone-numbers: %v", err)
+	}
+	if !strings.Contains(stub.lastURL, "/v25.0/waba_2/phone_numbers?") {
+		t.Fatalf("unexpected request url %q", stub.lastURL)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta wa phone-numbers")
+	data := envelope["data"].([]any)
+	if len(data) != 1 || data[0].(map[string]any)["quality_rating"] != "GREEN" {
+		t.Fatalf("unexpected data %#v", data)
+	}
+}
+
+func useWADependencies(t *testing.T, stub *stubHTTPClient) {
+	t.Helper()
+	originalLoad := waLoadProfileCredentials
+	originalClient := waNewGraphClient
+	t.Cleanup(func() {
+		waLoadProfileCredentials = originalLoad
+		waNewGraphClient = originalClient
+	})
+
+	waLoadProfileCredentials = func(string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{
+			Name:    "prod",
+			Profile: config.Profile{GraphVersion: "v25.0", WABAID: "waba_1"},
+			Token:   "token",
+		}, nil
+	}
+	waNewGraphClient = func() *graph.Client {
+		if stub == nil {
+			t.Fatal("graph client should not be created")
+		}
+		client := graph.NewClient(stub, "https://graph.example.com")
+		client.MaxRetries = 0
+		return client
+	}
+}
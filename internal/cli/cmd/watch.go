@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/lint"
+	"github.com/bilalbayram/metacli/internal/schema"
+	"github.com/bilalbayram/metacli/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchNow   = time.Now
+	watchSleep func(time.Duration)
+)
+
+// watchTarget is an object kind `watch` can poll, with the dependencies of
+// its command family so tests stub them the same way.
+type watchTarget struct {
+	object        string
+	idFlag        string
+	lintPath      string
+	defaultFields []string
+	resolve       func(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error)
+	newLinter     func(creds *ProfileCredentials, version string, schemaDir string) (*lint.Linter, error)
+	newClient     func() *graph.Client
+}
+
+// Status, budget and delivery fields. Fields that move on every poll while an
+// object delivers, such as budget_remaining, are left out so the feed only
+// carries real changes.
+var (
+	campaignWatchTarget = watchTarget{
+		object:        "campaign",
+		idFlag:        "campaign-id",
+		lintPath:      campaignMutationLintPath,
+		defaultFields: []string{"name", "status", "effective_status", "daily_budget", "lifetime_budget"},
+		resolve:       resolveCampaignProfileAndVersion,
+		newLinter:     newCampaignMutationLinter,
+		newClient:     func() *graph.Client { return campaignNewGraphClient() },
+	}
+	adsetWatchTarget = watchTarget{
+		object:        "adset",
+		idFlag:        "adset-id",
+		lintPath:      adsetMutationLintPath,
+		defaultFields: []string{"name", "status", "effective_status", "daily_budget", "lifetime_budget", "optimization_goal"},
+		resolve:       resolveAdsetProfileAndVersion,
+		newLinter:     newAdsetMutationLinter,
+		newClient:     func() *graph.Client { return adsetNewGraphClient() },
+	}
+	adWatchTarget = watchTarget{
+		object:        "ad",
+		idFlag:        "ad-id",
+		lintPath:      adMutationLintPath,
+		defaultFields: []string{"name", "status", "effective_status", "adset_id", "creative"},
+		resolve:       resolveAdProfileAndVersion,
+		newLinter:     newAdMutationLinter,
+		newClient:     func() *graph.Client { return adNewGraphClient() },
+	}
+)
+
+func newWatchCommand(runtime Runtime, target watchTarget) *cobra.Command {
+	var (
+		profile   string
+		version   string
+		objectID  string
+		fieldsRaw string
+		interval  time.Duration
+		maxPolls  int
+		maxErrors int
+		schemaDir string
+	)
+	commandName := "meta " + target.object + " watch"
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Poll a " + target.object + " and print status, budget and delivery changes as JSON lines",
+		Long: "Poll a " + target.object + " every --interval and print one JSON line per event on stdout:\n" +
+			"a snapshot of the watched fields first, then a change event with from/to values whenever one changes.\n" +
+			"Polls that fail with a retryable error print an error event; the watch stops after --max-errors of them in a row,\n" +
+			"on a non-retryable error, after --max-polls polls, or on Ctrl-C or --timeout.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			id := strings.TrimSpace(objectID)
+			if id == "" {
+				return writeCommandError(cmd, runtime, commandName, fmt.Errorf("--%s is required", target.idFlag))
+			}
+			if interval < watch.MinInterval {
+				return writeCommandError(cmd, runtime, commandName, fmt.Errorf("--interval must be at least %s, got %s", watch.MinInterval, interval))
+			}
+			if maxPolls < 0 {
+				return writeCommandError(cmd, runtime, commandName, errors.New("--max-polls must be >= 0"))
+			}
+			creds, resolvedVersion, err := target.resolve(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, commandName, err)
+			}
+			fields := csvToSlice(fieldsRaw)
+			if len(fields) == 0 {
+				fields = append([]string(nil), target.defaultFields...)
+			}
+			linter, err := target.newLinter(creds, resolvedVersion, schemaDir)
+			if err != nil {
+				return writeCommandError(cmd, runtime, commandName, err)
+			}
+			result := linter.Lint(&lint.RequestSpec{Method: http.MethodGet, Path: target.lintPath, Fields: fields}, true)
+			if len(result.Errors) > 0 {
+				return writeCommandError(cmd, runtime, commandName, fmt.Errorf("%s watch field lint failed with %d error(s): %s", target.object, len(result.Errors), strings.Join(result.Errors, "; ")))
+			}
+
+			client := target.newClient()
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			watcher := &watch.Watcher{
+				Object:    target.object,
+				ID:        id,
+				Fields:    fields,
+				Interval:  interval,
+				MaxPolls:  maxPolls,
+				MaxErrors: maxErrors,
+				Read: func(ctx context.Context) (map[string]any, error) {
+					response, err := client.Do(ctx, graph.Request{
+						Method:      http.MethodGet,
+						Path:        id,
+						Version:     resolvedVersion,
+						Query:       map[string]string{"fields": strings.Join(fields, ",")},
+						AccessToken: creds.Token,
+						AppSecret:   creds.AppSecret,
+					})
+					if err != nil {
+						return nil, err
+					}
+					return response.Body, nil
+				},
+				Emit:  func(event watch.Event) error { return encoder.Encode(event) },
+				Now:   watchNow,
+				Sleep: watchSleep,
+			}
+			// Events own stdout as JSON lines, so only a failure writes an
+			// envelope, to stderr.
+			if err := watcher.Run(cmd.Context()); err != nil {
+				return writeCommandError(cmd, runtime, commandName, err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(&version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(&objectID, target.idFlag, "", "Id of the "+target.object+" to watch")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated Graph fields to watch (default "+strings.Join(target.defaultFields, ",")+")")
+	cmd.Flags().DurationVar(&interval, "interval", time.Minute, "Time between polls, at least "+watch.MinInterval.String())
+	cmd.Flags().IntVar(&maxPolls, "max-polls", 0, "Stop after this many polls (0 watches until interrupted)")
+	cmd.Flags().IntVar(&maxErrors, "max-errors", watch.DefaultMaxErrors, "Stop after this many failed polls in a row")
+	cmd.Flags().StringVar(&schemaDir, "schema-dir", schema.DefaultSchemaDir(), "Schema pack root directory")
+	return cmd
+}
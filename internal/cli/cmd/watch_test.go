@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/watch"
+)
+
+func useWatchClock(t *testing.T) {
+	t.Helper()
+	originalNow, originalSleep := watchNow, watchSleep
+	t.Cleanup(func() {
+		watchNow, watchSleep = originalNow, originalSleep
+	})
+	watchNow = func() time.Time { return time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC) }
+	watchSleep = func(time.Duration) {}
+}
+
+func TestCampaignWatchEmitsSnapshotThenChanges(t *testing.T) {
+	useWatchClock(t)
+	assertFields := func(t *testing.T, req *http.Request, _ string) {
+		if req.Method != http.MethodGet || !strings.Contains(req.URL.Path, "/120000000000001") {
+			t.Fatalf("unexpected request %s %s", req.Method, req.URL)
+		}
+		if got := req.URL.Query().Get("fields"); got != "status,daily_budget" {
+			t.Fatalf("unexpected fields %q", got)
+		}
+	}
+	stub := &adsetQueuedHTTPClient{t: t, responses: []adsetQueuedResponse{
+		{body: `{"id":"120000000000001","status":"ACTIVE","daily_budget":"1000"}`, assert: assertFields},
+		{body: `{"id":"120000000000001","status":"ACTIVE","daily_budget":"1000"}`, assert: assertFields},
+		{statusCode: http.StatusInternalServerError, body: `{"error":{"message":"An unknown error occurred","type":"OAuthException","code":1,"is_transient":true}}`},
+		{body: `{"id":"120000000000001","status":"PAUSED","daily_budget":"2500"}`, assert: assertFields},
+	}}
+	useCampaignDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{Domain: config.DefaultDomain, GraphVersion: config.DefaultGraphVersion},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			client := graph.NewClient(stub, "https://graph.example.com")
+			client.MaxRetries = 0
+			return client
+		},
+	)
+
+	stdout := &bytes.Buffer{}
+	cmd := NewCampaignCommand(testRuntime("prod"))
+	cmd.SetOut(stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"watch", "--campaign-id", "120000000000001", "--fields", "status,daily_budget", "--interval", "30s", "--max-polls", "4", "--schema-dir", writeCampaignSchemaPack(t)})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("campaign watch: %v", err)
+	}
+
+	var events []watch.Event
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		var event watch.Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("decode event %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected snapshot, error and change events, got %d:\n%s", len(events), stdout.String())
+	}
+	if events[0].Event != watch.EventSnapshot || events[0].Object != "campaign" || events[0].Fields["status"] != "ACTIVE" {
+		t.Fatalf("unexpected snapshot %+v", events[0])
+	}
+	if events[1].Event != watch.EventError || events[1].Poll != 3 {
+		t.Fatalf("unexpected error event %+v", events[1])
+	}
+	change := events[2]
+	if change.Event != watch.EventChange || change.Poll != 4 || len(change.Changes) != 2 {
+		t.Fatalf("unexpected change event %+v", change)
+	}
+	if got := change.Changes["daily_budget"]; got.From != "1000" || got.To != "2500" {
+		t.Fatalf("unexpected budget change %+v", got)
+	}
+	if got := change.Changes["status"]; got.From != "ACTIVE" || got.To != "PAUSED" {
+		t.Fatalf("unexpected status change %+v", got)
+	}
+}
+
+func TestCampaignWatchRejectsShortIntervalsAndUnknownFields(t *testing.T) {
+	useCampaignDependencies(t,
+		func(string) (*ProfileCredentials, error) {
+			return &ProfileCredentials{
+				Name:    "prod",
+				Profile: config.Profile{Domain: config.DefaultDomain, GraphVersion: config.DefaultGraphVersion},
+				Token:   "test-token",
+			}, nil
+		},
+		func() *graph.Client {
+			t.Fatal("watch must not reach the Graph API")
+			return nil
+		},
+	)
+	schemaDir := writeCampaignSchemaPack(t)
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"--interval", "1s"}, want: "--interval must be at least 5s"},
+		{args: []string{"--fields", "status,spend_forecast"}, want: "campaign watch field lint failed"},
+	} {
+		stderr := &bytes.Buffer{}
+		cmd := NewCampaignCommand(testRuntime("prod"))
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(stderr)
+		cmd.SetArgs(append([]string{"watch", "--campaign-id", "120000000000001", "--schema-dir", schemaDir}, tc.args...))
+		if err := cmd.Execute(); err == nil {
+			t.Fatalf("%v: expected an error", tc.args)
+		}
+		envelope := decodeEnvelope(t, stderr.Bytes())
+		if message := envelope["error"].(map[string]any)["message"].(string); !strings.Contains(message, tc.want) {
+			t.Fatalf("%v: expected %q, got %q", tc.args, tc.want, message)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/auth"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/webhook"
+	"github.com/spf13/cobra"
+)
+
+var (
+	webhookLoadProfileCredentials = loadProfileCredentials
+	webhookNewGraphClient         = func() *graph.Client {
+		return graph.NewClient(nil, "")
+	}
+	webhookListen = net.Listen
+)
+
+func NewWebhookCommand(runtime Runtime) *cobra.Command {
+	webhookCmd := &cobra.Command{
+		Use:   "webhook",
+		Short: "App webhook subscriptions and a local signed-delivery receiver",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "webhook")
+		},
+	}
+	webhookCmd.AddCommand(newWebhookListCommand(runtime))
+	webhookCmd.AddCommand(newWebhookSubscribeCommand(runtime))
+	webhookCmd.AddCommand(newWebhookUnsubscribeCommand(runtime))
+	webhookCmd.AddCommand(newWebhookListenCommand(runtime))
+	return webhookCmd
+}
+
+func newWebhookListCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile string
+		version string
+		appID   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List webhook subscriptions of an app",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveWebhookProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta webhook list", err)
+			}
+			resolvedAppID, token, err := resolveWebhookAppToken(creds, appID)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta webhook list", err)
+			}
+
+			result, err := webhook.New(webhookNewGraphClient()).ListSubscriptions(cmd.Context(), resolvedVersion, token, creds.AppSecret, resolvedAppID)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta webhook list", err)
+			}
+			return writeSuccess(cmd, runtime, "meta webhook list", result, nil, nil)
+		},
+	}
+
+	addWebhookCommonFlags(cmd, &profile, &version, &appID)
+	return cmd
+}
+
+func newWebhookSubscribeCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile       string
+		version       string
+		appID         string
+		object        string
+		callbackURL   string
+		fieldsRaw     string
+		verifyToken   string
+		includeValues bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "subscribe",
+		Short: "Create or update an app webhook subscription",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			options := webhook.SubscribeOptions{
+				Object:        object,
+				CallbackURL:   callbackURL,
+				Fields:        csvToSlice(fieldsRaw),
+				VerifyToken:   verifyToken,
+				IncludeValues: includeValues,
+			}
+			if _, err := webhook.BuildSubscribeForm(options); err != nil {
+				return writeCommandError(cmd, runtime, "meta webhook subscribe", err)
+			}
+
+			creds, resolvedVersion, err := resolveWebhookProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta webhook subscribe", err)
+			}
+			resolvedAppID, token, err := resolveWebhookAppToken(creds, appID)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta webhook subscribe", err)
+			}
+			options.AppID = resolvedAppID
+
+			result, err := webhook.New(webhookNewGraphClient()).Subscribe(cmd.Context(), resolvedVersion, token, creds.AppSecret, options)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta webhook subscribe", err)
+			}
+			return writeSuccess(cmd, runtime, "meta webhook subscribe", result, nil, nil)
+		},
+	}
+
+	addWebhookCommonFlags(cmd, &profile, &version, &appID)
+	cmd.Flags().StringVar(&object, "object", "", "Webhook object: page|instagram|user|permissions|whatsapp_business_account|application|certificate_transparency")
+	cmd.Flags().StringVar(&callbackURL, "callback-url", "", "HTTPS callback URL that receives deliveries")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated topic fields (for example feed,messages,leadgen)")
+	cmd.Flags().StringVar(&verifyToken, "verify-token", "", "Token echoed back during the callback verification handshake")
+	cmd.Flags().BoolVar(&includeValues, "include-values", true, "Include changed values in deliveries")
+	mustMarkFlagRequired(cmd, "object")
+	mustMarkFlagRequired(cmd, "callback-url")
+	mustMarkFlagRequired(cmd, "fields")
+	mustMarkFlagRequired(cmd, "verify-token")
+	return cmd
+}
+
+func newWebhookUnsubscribeCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile   string
+		version   string
+		appID     string
+		object    string
+		fieldsRaw string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "unsubscribe",
+		Short: "Remove webhook fields, or a whole object subscription, from an app",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			creds, resolvedVersion, err := resolveWebhookProfileAndVersion(runtime, profile, version)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta webhook unsubscribe", err)
+			}
+			resolvedAppID, token, err := resolveWebhookAppToken(creds, appID)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta webhook unsubscribe", err)
+			}
+
+			result, err := webhook.New(webhookNewGraphClient()).Unsubscribe(cmd.Context(), resolvedVersion, token, creds.AppSecret, webhook.UnsubscribeOptions{
+				AppID:  resolvedAppID,
+				Object: object,
+				Fields: csvToSlice(fieldsRaw),
+			})
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta webhook unsubscribe", err)
+			}
+			return writeSuccess(cmd, runtime, "meta webhook unsubscribe", result, nil, nil)
+		},
+	}
+
+	addWebhookCommonFlags(cmd, &profile, &version, &appID)
+	cmd.Flags().StringVar(&object, "object", "", "Webhook object to unsubscribe")
+	cmd.Flags().StringVar(&fieldsRaw, "fields", "", "Comma-separated fields to remove (omit to remove the whole object)")
+	mustMarkFlagRequired(cmd, "object")
+	return cmd
+}
+
+func newWebhookListenCommand(runtime Runtime) *cobra.Command {
+	var (
+		profile     string
+		host        string
+		port        int
+		path        string
+		verifyToken string
+		appSecret   string
+		forward     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "listen",
+		Short: "Run a local webhook receiver that verifies signatures and prints events as JSON lines",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if port < 0 || port > 65535 {
+				return writeCommandError(cmd, runtime, "meta webhook listen", fmt.Errorf("invalid --port %d: expected 0-65535", port))
+			}
+			resolvedPath := "/" + strings.TrimPrefix(strings.TrimSpace(path), "/")
+
+			secret := strings.TrimSpace(appSecret)
+			if secret == "" {
+				creds, _, err := resolveWebhookProfileAndVersion(runtime, profile, "")
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta webhook listen", fmt.Errorf("app secret is required to verify %s (--app-secret or profile app secret): %w", webhook.SignatureHeader, err))
+				}
+				secret = strings.TrimSpace(creds.AppSecret)
+			}
+			if secret == "" {
+				return writeCommandError(cmd, runtime, "meta webhook listen", fmt.Errorf("app secret is required to verify %s (--app-secret or profile app secret)", webhook.SignatureHeader))
+			}
+
+			receiver := &webhook.Receiver{
+				AppSecret:   secret,
+				VerifyToken: strings.TrimSpace(verifyToken),
+				Events:      cmd.OutOrStdout(),
+				Errors:      cmd.ErrOrStderr(),
+			}
+			if handler := strings.TrimSpace(forward); handler != "" {
+				receiver.Forward = newWebhookForwarder(handler)
+			}
+
+			addr := net.JoinHostPort(strings.TrimSpace(host), strconv.Itoa(port))
+			listener, err := webhookListen("tcp", addr)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta webhook listen", fmt.Errorf("listen on %s: %w", addr, err))
+			}
+
+			mux := http.NewServeMux()
+			mux.Handle(resolvedPath, receiver)
+			server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+			// Events own stdout as JSON lines, so the startup notice goes to stderr.
+			notice, _ := json.Marshal(map[string]any{
+				"status":      "listening",
+				"listen_addr": listener.Addr().String(),
+				"path":        resolvedPath,
+				"forward":     strings.TrimSpace(forward),
+			})
+			fmt.Fprintln(cmd.ErrOrStderr(), string(notice))
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			serveErr := make(chan error, 1)
+			go func() {
+				serveErr <- server.Serve(listener)
+			}()
+			select {
+			case <-ctx.Done():
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := server.Shutdown(shutdownCtx); err != nil {
+					return fmt.Errorf("shutdown webhook receiver: %w", err)
+				}
+				return nil
+			case err := <-serveErr:
+				if errors.Is(err, http.ErrServerClosed) {
+					return nil
+				}
+				return fmt.Errorf("serve webhook receiver: %w", err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile whose app secret verifies deliveries")
+	cmd.Flags().StringVar(&host, "host", "127.0.0.1", "Interface to bind")
+	cmd.Flags().IntVar(&port, "port", 8080, "Port to listen on")
+	cmd.Flags().StringVar(&path, "path", "/", "Callback path")
+	cmd.Flags().StringVar(&verifyToken, "verify-token", "", "Verify token expected during the hub.challenge handshake")
+	cmd.Flags().StringVar(&appSecret, "app-secret", "", "App secret used to verify X-Hub-Signature-256 (defaults to the profile app secret)")
+	cmd.Flags().StringVar(&forward, "forward", "", "Executable run once per event with the event JSON on stdin")
+	return cmd
+}
+
+// newWebhookForwarder runs the handler once per event with the event JSON on stdin.
+func newWebhookForwarder(handler string) webhook.ForwardFunc {
+	return func(ctx context.Context, event []byte) error {
+		forwardCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		command := exec.CommandContext(forwardCtx, handler)
+		command.Stdin = bytes.NewReader(event)
+		output, err := command.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%s: %w: %s", handler, err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+}
+
+func addWebhookCommonFlags(cmd *cobra.Command, profile *string, version *string, appID *string) {
+	cmd.Flags().StringVar(profile, "profile", "", "Profile name")
+	cmd.Flags().StringVar(version, "version", "", "Graph API version")
+	cmd.Flags().StringVar(appID, "app-id", "", "App id (optional when profile has app_id)")
+}
+
+// resolveWebhookAppToken returns the app id and an app access token. App profiles
+// use their stored token; other profiles derive app_id|app_secret.
+func resolveWebhookAppToken(creds *ProfileCredentials, appID string) (string, string, error) {
+	if creds == nil {
+		return "", "", errors.New("profile credentials are required")
+	}
+	resolvedAppID := strings.TrimSpace(appID)
+	if resolvedAppID == "" {
+		resolvedAppID = strings.TrimSpace(creds.Profile.AppID)
+	}
+	if resolvedAppID == "" {
+		return "", "", errors.New("app id is required (--app-id or profile app_id)")
+	}
+	if creds.Profile.TokenType == auth.TokenTypeApp {
+		return resolvedAppID, creds.Token, nil
+	}
+	token, err := webhook.AppAccessToken(resolvedAppID, creds.AppSecret)
+	if err != nil {
+		return "", "", err
+	}
+	return resolvedAppID, token, nil
+}
+
+func resolveWebhookProfileAndVersion(runtime Runtime, profile string, version string) (*ProfileCredentials, string, error) {
+	resolvedProfile := strings.TrimSpace(profile)
+	if resolvedProfile == "" {
+		resolvedProfile = runtime.ProfileName()
+	}
+	if resolvedProfile == "" {
+		return nil, "", errors.New("profile is required (--profile or global --profile)")
+	}
+
+	creds, err := webhookLoadProfileCredentials(resolvedProfile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resolvedVersion := strings.TrimSpace(version)
+	if resolvedVersion == "" {
+		resolvedVersion = creds.Profile.GraphVersion
+	}
+	if resolvedVersion == "" {
+		resolvedVersion = config.DefaultGraphVersion
+	}
+	return creds, resolvedVersion, nil
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/webhook"
+)
+
+func TestWebhookSubscribeDerivesAppAccessToken(t *testing.T) {
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"success":true}`,
+	}
+	useWebhookDependencies(t, stub)
+
+	output := &bytes.Buffer{}
+	cmd := NewWebhookCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"subscribe",
+		"--object", "page",
+		"--callback-url", "https://hooks.example.com/meta",
+		"--fields", "feed,leadgen",
+		"--verify-token", "tok",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute webhook subscribe: %v", err)
+	}
+	if stub.lastMethod != http.MethodPost || !strings.HasSuffix(stub.lastURL, "/v25.0/app_1/subscriptions") {
+		t.Fatalf("unexpected request %s %s", stub.lastMethod, stub.lastURL)
+	}
+	form, err := url.ParseQuery(stub.lastBody)
+	if err != nil {
+		t.Fatalf("parse form body: %v", err)
+	}
+	if form.Get("access_token") != "app_1|app-secret" || form.Get("verify_token") != "tok" {
+		t.Fatalf("unexpected form %v", form)
+	}
+	envelope := decodeEnvelope(t, output.Bytes())
+	assertEnvelopeBasics(t, envelope, "meta webhook subscribe")
+}
+
+func TestWebhookSubscribeRejectsPlainHTTPCallback(t *testing.T) {
+	useWebhookDependencies(t, nil)
+
+	cmd := NewWebhookCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"subscribe", "--object", "page", "--callback-url", "http://localhost:8080", "--fields", "feed", "--verify-token", "tok"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "https endpoints") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestWebhookListenVerifiesSignatureAndPrintsEvents(t *testing.T) {
+	useWebhookDependencies(t, nil)
+	addrCh := make(chan string, 1)
+	originalListen := webhookListen
+	t.Cleanup(func() {
+		webhookListen = originalListen
+	})
+	webhookListen = func(network string, _ string) (net.Listener, error) {
+		listener, err := net.Listen(network, "127.0.0.1:0")
+		if err == nil {
+			addrCh <- listener.Addr().String()
+		}
+		return listener, err
+	}
+
+	events := &lockedBuffer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := NewWebhookCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(events)
+	cmd.SetErr(&lockedBuffer{})
+	cmd.SetArgs([]string{"listen", "--path", "/hooks"})
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.ExecuteContext(ctx)
+	}()
+
+	var addr string
+	select {
+	case addr = <-addrCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("receiver did not start listening")
+	}
+
+	payload := []byte(`{"object":"page","entry":[{"id":"p1","changes":[{"field":"feed","value":{"item":"post"}}]}]}`)
+	mac := hmac.New(sha256.New, []byte("app-secret"))
+	_, _ = mac.Write(payload)
+	req, err := http.NewRequest(http.MethodPost, "http://"+addr+"/hooks", bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set(webhook.SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("post delivery: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", res.StatusCode)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("listen returned error: %v", err)
+	}
+	if line := events.String(); !strings.Contains(line, `"field":"feed"`) || strings.Count(line, "\n") != 1 {
+		t.Fatalf("unexpected event output %q", line)
+	}
+}
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func useWebhookDependencies(t *testing.T, stub *stubHTTPClient) {
+	t.Helper()
+	originalLoad := webhookLoadProfileCredentials
+	originalClient := webhookNewGraphClient
+	t.Cleanup(func() {
+		webhookLoadProfileCredentials = originalLoad
+		webhookNewGraphClient = originalClient
+	})
+
+	webhookLoadProfileCredentials = func(string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{
+			Name:      "prod",
+			Profile:   config.Profile{GraphVersion: "v25.0", AppID: "app_1", TokenType: "user"},
+			Token:     "user-token",
+			AppSecret: "app-secret",
+		}, nil
+	}
+	webhookNewGraphClient = func() *graph.Client {
+		if stub == nil {
+			t.Fatal("graph client should not be created")
+		}
+		client := graph.NewClient(stub, "https://graph.example.com")
+		client.MaxRetries = 0
+		return client
+	}
+}
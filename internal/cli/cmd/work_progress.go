@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/bilalbayram/metacli/internal/progress"
+	"github.com/bilalbayram/metacli/internal/workqueue"
+	"github.com/spf13/cobra"
+)
+
+// commandProgress reports a long operation on stderr: a redrawn bar when stderr
+// is a terminal, otherwise one line per update for the operations that print
+// them, and nothing at all with --no-progress.
+type commandProgress struct {
+	label string
+	bar   *progress.Bar
+	lines io.Writer
+}
+
+func startProgress(cmd *cobra.Command, runtime Runtime, label string, total int64) *commandProgress {
+	reporter := &commandProgress{label: label}
+	if runtime.NoProgress != nil && *runtime.NoProgress {
+		return reporter
+	}
+	stderr := cmd.ErrOrStderr()
+	if output.IsTerminal(stderr) {
+		reporter.bar = progress.New(stderr, label, total)
+		return reporter
+	}
+	reporter.lines = stderr
+	return reporter
+}
+
+// Context returns ctx carrying the bar, so Graph retries and report polling
+// can show why the operation is waiting.
+func (p *commandProgress) Context(ctx context.Context) context.Context {
+	return progress.WithBar(ctx, p.bar)
+}
+
+// Bar is nil unless a bar is drawn.
+func (p *commandProgress) Bar() *progress.Bar {
+	return p.bar
+}
+
+// Line prints a progress line when no bar is drawn and progress is enabled.
+func (p *commandProgress) Line(format string, args ...any) {
+	if p.lines == nil {
+		return
+	}
+	fmt.Fprintf(p.lines, format+"\n", args...)
+}
+
+// Work reports a work queue update: "<label>: N/total done, M failed".
+func (p *commandProgress) Work(update workqueue.Progress) {
+	if p.bar != nil {
+		p.bar.Set(int64(update.Done), int64(update.Total))
+		if update.Failed > 0 {
+			p.bar.Detail(fmt.Sprintf("%d failed", update.Failed))
+		}
+		return
+	}
+	p.Line("%s: %s", p.label, update)
+}
+
+// Finish ends the bar's line; call it before writing the envelope.
+func (p *commandProgress) Finish() {
+	p.bar.Finish()
+}
+
+// governedClient bounds a bulk command's Graph requests by a governor when it
+// runs work in parallel, so workers back off together as usage headers climb.
+func governedClient(client *graph.Client, concurrency int) *graph.Client {
+	if client == nil || concurrency <= 1 {
+		return client
+	}
+	return client.WithGovernor(graph.NewGovernor(concurrency))
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/workqueue"
+	"github.com/spf13/cobra"
+)
+
+func TestCommandProgressPrintsLinesWithoutTerminal(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetErr(stderr)
+
+	reporter := startProgress(cmd, testRuntime("prod"), "bulk import", 4)
+	if reporter.Bar() != nil {
+		t.Fatal("expected no bar when stderr is not a terminal")
+	}
+	reporter.Work(workqueue.Progress{Total: 4, Done: 2, Failed: 1})
+	reporter.Finish()
+	if got := stderr.String(); got != "bulk import: 2/4 done, 1 failed\n" {
+		t.Fatalf("unexpected progress output %q", got)
+	}
+}
+
+func TestCommandProgressHonorsNoProgress(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetErr(stderr)
+	runtime := testRuntime("prod")
+	noProgress := true
+	runtime.NoProgress = &noProgress
+
+	reporter := startProgress(cmd, runtime, "bulk import", 4)
+	reporter.Work(workqueue.Progress{Total: 4, Done: 4})
+	reporter.Line("uploaded chunk %d/%d", 1, 1)
+	reporter.Finish()
+	if stderr.Len() != 0 {
+		t.Fatalf("expected no progress output, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/bilalbayram/metacli/internal/templates"
+	"github.com/bilalbayram/metacli/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+// workflowCommands lists the command families a workflow step may run. Workflow
+// itself is left out so plans cannot recurse.
+var workflowCommands = map[string]func(Runtime) *cobra.Command{
+	"account":  NewAccountCommand,
+	"ad":       NewAdCommand,
+	"adset":    NewAdsetCommand,
+	"api":      NewAPICommand,
+	"audience": NewAudienceCommand,
+	"bulk":     NewBulkCommand,
+	"campaign": NewCampaignCommand,
+	"catalog":  NewCatalogCommand,
+	"creative": NewCreativeCommand,
+	"insights": NewInsightsCommand,
+	"template": NewTemplateCommand,
+}
+
+type workflowRunResult struct {
+	StateFile string             `json:"state_file,omitempty"`
+	DryRun    bool               `json:"dry_run"`
+	State     *workflow.RunState `json:"state"`
+}
+
+func NewWorkflowCommand(runtime Runtime) *cobra.Command {
+	workflowCmd := &cobra.Command{
+		Use:   "workflow",
+		Short: "Multi-step workflows that chain CLI operations",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return requireSubcommand(cmd, "workflow")
+		},
+	}
+	workflowCmd.AddCommand(newWorkflowRunCommand(runtime))
+	return workflowCmd
+}
+
+func newWorkflowRunCommand(runtime Runtime) *cobra.Command {
+	var (
+		statePath         string
+		varsRaw           []string
+		restart           bool
+		rollbackOnFailure bool
+		dryRun            bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run <plan.yaml>",
+		Short: "Run a workflow plan, resuming from the last failed step",
+		Long:  "Run a workflow plan. Each step runs a CLI operation (command: campaign create) with args given as flags, may depend on other steps, and may capture values from its output data (capture: {campaign_id: campaign_id}) for later steps to use as ${steps.STEP.OUTPUT}. Plan vars are referenced as ${vars.NAME}. Progress is saved to a state file after every step; rerunning after a failure skips the steps that already succeeded.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			planFile := args[0]
+			plan, err := workflow.LoadPlan(planFile)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta workflow run", err)
+			}
+			vars, err := templates.ParseVars(varsRaw)
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta workflow run", err)
+			}
+
+			if strings.TrimSpace(statePath) == "" {
+				statePath = workflow.DefaultStatePath(planFile)
+			}
+			var previous *workflow.RunState
+			if !restart && !dryRun {
+				previous, err = workflow.LoadState(statePath)
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta workflow run", err)
+				}
+			}
+
+			runID := ""
+			if previous != nil {
+				runID = previous.RunID
+			}
+			if runID == "" {
+				runID, err = newTrackedRunID("workflow")
+				if err != nil {
+					return writeCommandError(cmd, runtime, "meta workflow run", err)
+				}
+			}
+			options := workflow.RunOptions{
+				RunID:    runID,
+				PlanFile: planFile,
+				Vars:     vars,
+				Previous: previous,
+				DryRun:   dryRun,
+				Save: func(state *workflow.RunState) error {
+					return workflow.SaveState(statePath, state)
+				},
+			}
+			if rollbackOnFailure {
+				options.Rollback = func(ctx context.Context, runID string) (any, error) {
+					return rollbackTrackedRun(ctx, runID, "")
+				}
+			}
+
+			runCtx := cmd.Context()
+			if marketing.AccountContextCacheFrom(runCtx) == nil {
+				runCtx = marketing.WithAccountContextCache(runCtx, marketing.NewAccountContextCache())
+			}
+			endTrackedRun := beginTrackedRun(runID)
+			state, err := workflow.Run(runCtx, plan, func(ctx context.Context, stepArgs []string) (any, error) {
+				return runWorkflowStep(ctx, runtime, stepArgs)
+			}, options)
+			endTrackedRun()
+			if err != nil {
+				return writeCommandError(cmd, runtime, "meta workflow run", err)
+			}
+			result := workflowRunResult{DryRun: dryRun, State: state}
+			if !dryRun {
+				result.StateFile = statePath
+			}
+			return writeSuccess(cmd, runtime, "meta workflow run", result, nil, nil)
+		},
+	}
+
+	cmd.Flags().StringVar(&statePath, "state-file", "", "Run state file (defaults to <plan>.state.json); an existing file resumes the run")
+	cmd.Flags().StringArrayVar(&varsRaw, "var", nil, "Plan variable override as name=value (repeatable)")
+	cmd.Flags().BoolVar(&restart, "restart", false, "Ignore the existing state file and run every step again")
+	cmd.Flags().BoolVar(&rollbackOnFailure, "rollback-on-failure", false, "When a step fails, pause or delete every resource this run created (per the resource ledger) so no half-built structure is left")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate the plan and print each step's rendered args without running anything")
+	return cmd
+}
+
+// runWorkflowStep runs one step through the same command tree as the CLI, with
+// JSON output captured so the envelope data can feed later steps.
+func runWorkflowStep(ctx context.Context, runtime Runtime, args []string) (any, error) {
+	if len(args) == 0 {
+		return nil, errors.New("workflow step command is required")
+	}
+	newCommand, ok := workflowCommands[args[0]]
+	if !ok {
+		supported := make([]string, 0, len(workflowCommands))
+		for name := range workflowCommands {
+			supported = append(supported, name)
+		}
+		sort.Strings(supported)
+		return nil, fmt.Errorf("command %q cannot run in a workflow; supported: %s", args[0], strings.Join(supported, ", "))
+	}
+
+	jsonOutput := "json"
+	stdout := &bytes.Buffer{}
+	step := newCommand(Runtime{Profile: runtime.Profile, Output: &jsonOutput, Debug: runtime.Debug, NoProgress: runtime.NoProgress})
+	step.SilenceErrors = true
+	step.SilenceUsage = true
+	step.SetOut(stdout)
+	step.SetErr(&bytes.Buffer{})
+	step.SetArgs(args[1:])
+	if err := step.ExecuteContext(ctx); err != nil {
+		return nil, err
+	}
+
+	var envelope struct {
+		Data any `json:"data"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &envelope); err != nil {
+		return nil, fmt.Errorf("decode %s output: %w", strings.Join(args[:min(2, len(args))], " "), err)
+	}
+	return envelope.Data, nil
+}
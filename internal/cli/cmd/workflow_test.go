@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/workflow"
+)
+
+const workflowTestPlan = `schema_version: 1
+steps:
+  - id: campaign
+    command: campaign create
+    args:
+      account-id: "123"
+      params: name=Launch,objective=OUTCOME_SALES
+      schema-dir: ${vars.campaign_schema}
+    capture:
+      id: campaign_id
+  - id: adset
+    command: adset create
+    depends_on: [campaign]
+    args:
+      account-id: "123"
+      params: name=Prospecting,campaign_id=${steps.campaign.id}
+      schema-dir: ${vars.adset_schema}
+`
+
+func TestWorkflowRunChainsStepsAndResumes(t *testing.T) {
+	failAdset := true
+	requests := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.PostForm.Get("campaign_id"))
+		switch r.URL.Path {
+		case "/v25.0/act_123/campaigns":
+			_, _ = w.Write([]byte(`{"id":"c1"}`))
+		case "/v25.0/act_123/adsets":
+			if failAdset {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":{"message":"Invalid parameter","type":"OAuthException","code":100}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":"s1"}`))
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	useWorkflowDependencies(t, server)
+
+	planPath := filepath.Join(t.TempDir(), "launch.yaml")
+	if err := os.WriteFile(planPath, []byte(workflowTestPlan), 0o644); err != nil {
+		t.Fatalf("write plan: %v", err)
+	}
+	schemaVars := []string{"--var", "campaign_schema=" + writeCampaignSchemaPack(t), "--var", "adset_schema=" + writeAdsetSchemaPack(t)}
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewWorkflowCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs(append([]string{"run", planPath}, schemaVars...))
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected adset step failure")
+	}
+	envelope := decodeEnvelope(t, errOutput.Bytes())
+	errorBody, _ := envelope["error"].(map[string]any)
+	if errorBody["type"] != "workflow_step_failed" || !strings.Contains(errorBody["message"].(string), "workflow step adset failed after 1 of 2") {
+		t.Fatalf("unexpected error envelope %v", envelope["error"])
+	}
+
+	failAdset = false
+	output, err := runWorkflowRun(t, append([]string{planPath}, schemaVars...)...)
+	if err != nil {
+		t.Fatalf("resume workflow: %v", err)
+	}
+	envelope = decodeEnvelope(t, output)
+	assertEnvelopeBasics(t, envelope, "meta workflow run")
+	data, _ := envelope["data"].(map[string]any)
+	if data["state_file"] != workflow.DefaultStatePath(planPath) {
+		t.Fatalf("unexpected data %v", data)
+	}
+	want := []string{
+		"POST /v25.0/act_123/campaigns ",
+		"POST /v25.0/act_123/adsets c1",
+		"POST /v25.0/act_123/adsets c1",
+	}
+	if strings.Join(requests, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected the campaign to be created once, got %v", requests)
+	}
+}
+
+func TestWorkflowRunRollsBackCreatedResourcesOnFailure(t *testing.T) {
+	requests := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.PostForm.Get("status"))
+		switch r.URL.Path {
+		case "/v25.0/act_123/campaigns":
+			_, _ = w.Write([]byte(`{"id":"c1"}`))
+		case "/v25.0/c1":
+			_, _ = w.Write([]byte(`{"success":true}`))
+		case "/v25.0/act_123/adsets":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"message":"Invalid parameter","type":"OAuthException","code":100}}`))
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	useWorkflowDependencies(t, server)
+
+	planPath := filepath.Join(t.TempDir(), "launch.yaml")
+	if err := os.WriteFile(planPath, []byte(workflowTestPlan), 0o644); err != nil {
+		t.Fatalf("write plan: %v", err)
+	}
+
+	errOutput := &bytes.Buffer{}
+	cmd := NewWorkflowCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(errOutput)
+	cmd.SetArgs([]string{"run", planPath, "--rollback-on-failure",
+		"--var", "campaign_schema=" + writeCampaignSchemaPack(t),
+		"--var", "adset_schema=" + writeAdsetSchemaPack(t)})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected adset step failure")
+	}
+	errorBody, _ := decodeEnvelope(t, errOutput.Bytes())["error"].(map[string]any)
+	diagnostics, _ := errorBody["diagnostics"].(map[string]any)
+	rollback, _ := diagnostics["rollback"].(map[string]any)
+	if summary, _ := rollback["summary"].(map[string]any); summary["applied"] != float64(1) || summary["failed"] != float64(0) {
+		t.Fatalf("unexpected rollback diagnostics %v", diagnostics["rollback"])
+	}
+	if requests[len(requests)-1] != "POST /v25.0/c1 PAUSED" {
+		t.Fatalf("expected the created campaign to be paused, got %v", requests)
+	}
+
+	state, err := workflow.LoadState(workflow.DefaultStatePath(planPath))
+	if err != nil || state == nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if !strings.HasPrefix(state.RunID, "workflow-") || state.Steps[0].Status != workflow.StepStatusRolledBack {
+		t.Fatalf("unexpected state %#v", state.Steps[0])
+	}
+}
+
+func TestWorkflowRunRejectsUnsupportedCommands(t *testing.T) {
+	useWorkflowDependencies(t, nil)
+	planPath := filepath.Join(t.TempDir(), "nested.yaml")
+	plan := "schema_version: 1\nsteps:\n  - {id: nested, command: workflow run}\n"
+	if err := os.WriteFile(planPath, []byte(plan), 0o644); err != nil {
+		t.Fatalf("write plan: %v", err)
+	}
+	if _, err := runWorkflowRun(t, planPath); err == nil || !strings.Contains(err.Error(), `command "workflow" cannot run in a workflow`) {
+		t.Fatalf("expected unsupported command error, got %v", err)
+	}
+}
+
+func runWorkflowRun(t *testing.T, args ...string) ([]byte, error) {
+	t.Helper()
+	output := &bytes.Buffer{}
+	cmd := NewWorkflowCommand(testRuntime("prod"))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOut(output)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(append([]string{"run"}, args...))
+	err := cmd.Execute()
+	return output.Bytes(), err
+}
+
+func useWorkflowDependencies(t *testing.T, server *httptest.Server) {
+	t.Helper()
+	loadFn := func(string) (*ProfileCredentials, error) {
+		return &ProfileCredentials{
+			Name: "prod",
+			Profile: config.Profile{
+				Domain:       config.DefaultDomain,
+				GraphVersion: config.DefaultGraphVersion,
+			},
+			Token: "token",
+		}, nil
+	}
+	clientFn := func() *graph.Client {
+		if server == nil {
+			t.Fatal("graph client should not be constructed")
+		}
+		client := graph.NewClient(server.Client(), server.URL)
+		client.MaxRetries = 0
+		return client
+	}
+	useCampaignDependencies(t, loadFn, clientFn)
+	useAdsetDependencies(t, loadFn, clientFn)
+	useRollbackDependencies(t, loadFn, clientFn)
+}
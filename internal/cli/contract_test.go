@@ -0,0 +1,489 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/auth"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/bilalbayram/metacli/internal/testutil"
+	"github.com/bilalbayram/metacli/internal/transport"
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+)
+
+// longRunningCommands keep going until stopped: serve and tui speak their own
+// protocol on stdout and ops metrics serve listens for scrapes.
+var longRunningCommands = map[string]bool{"meta serve": true, "meta tui": true, "meta ops metrics serve": true}
+
+// TestEveryCommandErrorEnvelopeMatchesContract runs every leaf command without
+// arguments and --offline, so it fails before reaching the network, and checks
+// each envelope it prints against the published envelope schema.
+func TestEveryCommandErrorEnvelopeMatchesContract(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	checked := 0
+	for _, path := range leafCommands(NewRootCommand()) {
+		if longRunningCommands["meta "+strings.Join(path, " ")] {
+			continue
+		}
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+		root := NewRootCommand()
+		root.SetOut(stdout)
+		root.SetErr(stderr)
+		root.SetIn(strings.NewReader(""))
+		root.SetArgs(append(path, "--offline", "--timeout", "5s"))
+		_ = root.Execute()
+
+		for _, raw := range append(envelopesIn(t, stdout.Bytes()), envelopesIn(t, stderr.Bytes())...) {
+			testutil.AssertContract(t, "envelope", raw)
+			checked++
+		}
+	}
+	if checked == 0 {
+		t.Fatal("expected commands to print envelopes")
+	}
+}
+
+// contractProfile is the config every success fixture runs with. prod, the
+// default, has scopes that cover what any command's auth preflight asks for;
+// page and app hold the page and app tokens some commands require.
+const contractProfile = `schema_version: 2
+default_profile: prod
+profiles:
+  prod:
+    domain: marketing
+    graph_version: v25.0
+    token_type: system_user
+    business_id: "900"
+    app_id: "100"
+    page_id: "200"
+    ig_user_id: "300"
+    waba_id: "400"
+    token_ref: keychain://meta-marketing-cli/prod/token
+    app_secret_ref: keychain://meta-marketing-cli/prod/app_secret
+    auth_provider: system_user
+    auth_mode: both
+    scopes: [ads_management, ads_read, business_management, catalog_management, instagram_basic, instagram_content_publish, instagram_manage_comments, instagram_manage_insights, instagram_manage_messages, leads_retrieval, pages_manage_metadata, pages_manage_posts, pages_messaging, pages_read_engagement, pages_show_list, whatsapp_business_management, whatsapp_business_messaging]
+    issued_at: "2026-01-01T00:00:00Z"
+    expires_at: "2099-01-01T00:00:00Z"
+    last_validated_at: "2026-01-01T00:00:00Z"
+    scopes_verified_at: "2026-01-01T00:00:00Z"
+  page:
+    domain: marketing
+    graph_version: v25.0
+    token_type: page
+    app_id: "100"
+    page_id: "200"
+    ig_user_id: "300"
+    source_profile: prod
+    token_ref: keychain://meta-marketing-cli/page/token
+    app_secret_ref: keychain://meta-marketing-cli/prod/app_secret
+    auth_provider: facebook_login
+    auth_mode: both
+    scopes: [leads_retrieval, pages_manage_metadata, pages_manage_posts, pages_messaging, pages_read_engagement, pages_show_list, instagram_basic, instagram_manage_messages]
+    issued_at: "2026-01-01T00:00:00Z"
+    expires_at: "2099-01-01T00:00:00Z"
+    last_validated_at: "2026-01-01T00:00:00Z"
+    scopes_verified_at: "2026-01-01T00:00:00Z"
+  app:
+    domain: marketing
+    graph_version: v25.0
+    token_type: app
+    app_id: "100"
+    token_ref: keychain://meta-marketing-cli/app/token
+    app_secret_ref: keychain://meta-marketing-cli/app/app_secret
+    auth_provider: app
+    auth_mode: both
+    scopes: [ads_read]
+    issued_at: "2026-01-01T00:00:00Z"
+    expires_at: "2099-01-01T00:00:00Z"
+    last_validated_at: "2026-01-01T00:00:00Z"
+`
+
+// eventStreamCommands print one JSON event per line instead of an envelope
+// when they succeed.
+var eventStreamCommands = map[string]bool{
+	"meta ad watch":       true,
+	"meta adset watch":    true,
+	"meta campaign watch": true,
+}
+
+// oauthCommands wait for the browser to return to their local callback with
+// an authorization code; the test plays the browser.
+var oauthCommands = map[string]bool{
+	"meta auth login": true,
+	"meta auth setup": true,
+	"meta init":       true,
+}
+
+// receiverCommands run until stopped and print what they receive instead of
+// an envelope, so only their error envelopes are checked.
+var receiverCommands = map[string]bool{"meta webhook listen": true}
+
+// TestEveryCommandSuccessEnvelopeMatchesContract drives every leaf command to
+// success from its fixture in testdata/contract, named after the command path
+// joined with underscores, and checks each envelope it prints against the
+// published envelope schema.
+func TestEveryCommandSuccessEnvelopeMatchesContract(t *testing.T) {
+	fixtures := map[string]*testutil.Fixture{}
+	for _, fixture := range testutil.LoadFixtures(t, filepath.Join("testdata", "contract")) {
+		fixtures[fixture.Name] = fixture
+	}
+	for _, path := range leafCommands(NewRootCommand()) {
+		command := "meta " + strings.Join(path, " ")
+		if longRunningCommands[command] || receiverCommands[command] {
+			continue
+		}
+		name := strings.Join(path, "_")
+		fixture, ok := fixtures[name]
+		if !ok {
+			t.Errorf("%s has no success fixture; add testdata/contract/%s.json", command, name)
+			continue
+		}
+		delete(fixtures, name)
+		t.Run(name, func(t *testing.T) {
+			runSuccessFixture(t, command, path, fixture)
+		})
+	}
+	for name := range fixtures {
+		t.Errorf("testdata/contract/%s.json matches no command", name)
+	}
+}
+
+// replayedCommands print the envelope of the command their fixture replays.
+var replayedCommands = map[string]string{
+	"meta retry":                "meta campaign list",
+	"meta template create-from": "meta campaign create",
+}
+
+// reportCommands version their envelopes separately (ops.v1, smoke.v2); only
+// the report of a run, named here by its schema, is a published contract.
+var reportCommands = map[string]string{
+	"meta ops cleanup":     "",
+	"meta ops init":        "",
+	"meta ops report diff": "",
+	"meta ops run":         "ops-report",
+	"meta smoke diff":      "",
+	"meta smoke run":       "smoke-report",
+}
+
+// runSuccessFixture runs one command in a fresh home as the fixture's profile
+// and returns what it printed. Every request, to Graph or any other https
+// host, is answered from the fixture's exchanges, except the token checks of
+// the auth preflight.
+func runSuccessFixture(t *testing.T, command string, path []string, fixture *testutil.Fixture) []byte {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	if err := os.MkdirAll(filepath.Join(home, ".meta"), 0o700); err != nil {
+		t.Fatalf("create config directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".meta", "config.yaml"), []byte(contractProfile), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	keyring.MockInit()
+	store := auth.NewSecretStore()
+	for ref, secret := range map[string]string{
+		"keychain://meta-marketing-cli/prod/token":      "test-token",
+		"keychain://meta-marketing-cli/prod/app_secret": "test-secret",
+		"keychain://meta-marketing-cli/page/token":      "test-page-token",
+		"keychain://meta-marketing-cli/app/token":       "100|test-secret",
+		"keychain://meta-marketing-cli/app/app_secret":  "test-secret",
+	} {
+		if err := store.Set(ref, secret); err != nil {
+			t.Fatalf("store secret: %v", err)
+		}
+	}
+
+	schemaDir, err := filepath.Abs(filepath.Join("..", "..", "schema-packs"))
+	if err != nil {
+		t.Fatalf("resolve schema packs: %v", err)
+	}
+	dir := t.TempDir()
+	now := time.Now()
+	vars := map[string]string{
+		"DIR":        dir,
+		"HOME":       home,
+		"SCHEMA_DIR": schemaDir,
+		"NOW":        strconv.FormatInt(now.Unix(), 10),
+		"TOMORROW":   now.Add(24 * time.Hour).UTC().Format(time.RFC3339),
+	}
+	fixture.WriteFiles(t, dir, vars)
+
+	httpClient := fixture.HTTPClient(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v25.0/oauth/access_token":
+			if r.URL.Query().Get("grant_type") == "client_credentials" && r.URL.Query().Get("client_secret") == "test-secret" {
+				_, _ = io.WriteString(w, `{"access_token":"100|test-secret","token_type":"bearer"}`)
+				return
+			}
+		case "/v25.0/debug_token":
+			if r.URL.Query().Get("input_token") == "test-token" {
+				_, _ = io.WriteString(w, `{"data":{"is_valid":true,"app_id":"100","type":"SYSTEM_USER","expires_at":0,"scopes":["ads_management","ads_read","business_management","catalog_management","instagram_basic","instagram_content_publish","instagram_manage_comments","instagram_manage_insights","instagram_manage_messages","leads_retrieval","pages_manage_metadata","pages_manage_posts","pages_messaging","pages_read_engagement","pages_show_list","whatsapp_business_management","whatsapp_business_messaging"]}}`)
+				return
+			}
+			if r.URL.Query().Get("input_token") == "test-page-token" {
+				_, _ = io.WriteString(w, `{"data":{"is_valid":true,"app_id":"100","type":"PAGE","profile_id":"200","expires_at":0,"scopes":["leads_retrieval","pages_manage_metadata","pages_manage_posts","pages_messaging","pages_read_engagement","pages_show_list","instagram_basic","instagram_manage_messages"]}}`)
+				return
+			}
+		}
+		if len(httpClient.Calls()) == len(fixture.Exchanges) {
+			body, _ := io.ReadAll(r.Body)
+			t.Errorf("unexpected request %d: %s %s %s", len(fixture.Exchanges)+1, r.Method, r.URL, body)
+			http.Error(w, `{"error":{"message":"unexpected request","type":"OAuthException","code":100}}`, http.StatusBadRequest)
+			return
+		}
+		response, err := httpClient.Do(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer response.Body.Close()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(response.StatusCode)
+		_, _ = io.Copy(w, response.Body)
+	}))
+	defer server.Close()
+	routeHTTPSTo(t, server, transport.Shared())
+	if base, ok := http.DefaultTransport.(*http.Transport); ok {
+		routeHTTPSTo(t, server, base)
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &lockedBuffer{}
+	root := NewRootCommand()
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	root.SetIn(strings.NewReader(fixture.Stdin))
+	root.SetArgs(append(append(append([]string{}, path...), fixture.ExpandArgs(vars)...), "--profile", fixture.Profile, "--timeout", "30s", "--no-progress"))
+	if oauthCommands[command] {
+		done := make(chan struct{})
+		defer close(done)
+		go completeOAuthLogin(t, stderr, done)
+	}
+	if err := root.Execute(); err != nil {
+		t.Fatalf("%s failed: %v\nstdout:\n%s\nstderr:\n%s", command, err, stdout, stderr)
+	}
+	httpClient.AssertDone(t)
+	if eventStreamCommands[command] {
+		return stdout.Bytes()
+	}
+	if schema, ok := reportCommands[command]; ok {
+		var envelope struct {
+			Success bool `json:"success"`
+			Data    struct {
+				Report json.RawMessage `json:"report"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &envelope); err != nil || !envelope.Success {
+			t.Fatalf("%s printed no successful envelope (%v):\n%s", command, err, stdout)
+		}
+		if schema != "" {
+			testutil.AssertContract(t, schema, envelope.Data.Report)
+		}
+		return stdout.Bytes()
+	}
+
+	envelopes := envelopesIn(t, stdout.Bytes())
+	if len(envelopes) == 0 {
+		t.Fatalf("%s printed no envelope:\n%s", command, stdout)
+	}
+	want := command
+	if replayed, ok := replayedCommands[command]; ok {
+		want = replayed
+	}
+	for _, raw := range envelopes {
+		envelope := testutil.DecodeEnvelope(t, raw)
+		testutil.AssertSuccess(t, envelope, want)
+	}
+	return stdout.Bytes()
+}
+
+// completeOAuthLogin follows the login URL a command prints to stderr back to
+// its callback, as the browser would once the user approved the app.
+func completeOAuthLogin(t *testing.T, stderr *lockedBuffer, done <-chan struct{}) {
+	const prompt = "Open this URL and complete login:\n"
+	for {
+		select {
+		case <-done:
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+		_, rest, ok := strings.Cut(stderr.String(), prompt)
+		if !ok {
+			continue
+		}
+		line, _, ok := strings.Cut(rest, "\n")
+		if !ok {
+			continue
+		}
+		authURL, err := url.Parse(line)
+		if err != nil {
+			t.Errorf("parse login url %q: %v", line, err)
+			return
+		}
+		query := authURL.Query()
+		callback := query.Get("redirect_uri") + "?" + url.Values{"code": {"oauth-code"}, "state": {query.Get("state")}}.Encode()
+		response, err := (&http.Client{Transport: &http.Transport{}}).Get(callback)
+		if err != nil {
+			t.Errorf("follow login callback: %v", err)
+			return
+		}
+		response.Body.Close()
+		return
+	}
+}
+
+// lockedBuffer is a bytes.Buffer that a command can write while the test
+// reads it.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// routeHTTPSTo dials server for every https request base makes, whatever the
+// host, until the test ends.
+func routeHTTPSTo(t *testing.T, server *httptest.Server, base *http.Transport) {
+	t.Helper()
+	proxy, dialTLS := base.Proxy, base.DialTLSContext
+	base.CloseIdleConnections()
+	base.Proxy = nil
+	base.DialTLSContext = func(ctx context.Context, network string, _ string) (net.Conn, error) {
+		conn, err := (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+		if err != nil {
+			return nil, err
+		}
+		return tls.Client(conn, &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"http/1.1"}}), nil
+	}
+	t.Cleanup(func() {
+		base.CloseIdleConnections()
+		base.Proxy, base.DialTLSContext = proxy, dialTLS
+	})
+}
+
+func TestDryRunPlanMatchesContract(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	stdout := &bytes.Buffer{}
+	root := NewRootCommand()
+	root.SetOut(stdout)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"template", "list", "--dry-run"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("template list: %v", err)
+	}
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode envelope: %v\n%s", err, stdout.String())
+	}
+	testutil.AssertContract(t, "envelope", stdout.Bytes())
+	testutil.AssertContract(t, "dry-run-plan", envelope.Data)
+}
+
+// TestIGPublishFeedDryRunPlansEveryStep runs the upload, readiness check and
+// publish of ig publish feed under --dry-run without a single Graph exchange:
+// the container only exists in the plan, so its status read must not reach
+// Graph either.
+func TestIGPublishFeedDryRunPlansEveryStep(t *testing.T) {
+	fixture := &testutil.Fixture{
+		Profile: "prod",
+		Args:    []string{"--media-url", "https://cdn.example.com/spring.jpg", "--caption", "Spring drop", "--quota-policy", "skip", "--dry-run"},
+	}
+	stdout := runSuccessFixture(t, "meta ig publish feed", []string{"ig", "publish", "feed"}, fixture)
+
+	var envelope struct {
+		Data struct {
+			DryRun    bool                    `json:"dry_run"`
+			Mutations []graph.PlannedMutation `json:"mutations"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(stdout, &envelope); err != nil {
+		t.Fatalf("decode envelope: %v\n%s", err, stdout)
+	}
+	mutations := envelope.Data.Mutations
+	if !envelope.Data.DryRun || len(mutations) != 2 {
+		t.Fatalf("expected the upload and publish to be planned, got %s", stdout)
+	}
+	if mutations[0].Path != "300/media" || mutations[1].Path != "300/media_publish" || mutations[1].Params["creation_id"] != mutations[0].SimulatedID {
+		t.Fatalf("unexpected plan %#v", mutations)
+	}
+}
+
+func leafCommands(root *cobra.Command) [][]string {
+	paths := [][]string{}
+	var walk func(cmd *cobra.Command, prefix []string)
+	walk = func(cmd *cobra.Command, prefix []string) {
+		for _, child := range cmd.Commands() {
+			if child.Hidden || child.Name() == "help" || child.Name() == "completion" {
+				continue
+			}
+			path := append(append([]string{}, prefix...), child.Name())
+			if child.HasSubCommands() {
+				walk(child, path)
+				continue
+			}
+			if child.Runnable() {
+				paths = append(paths, path)
+			}
+		}
+	}
+	walk(root, nil)
+	return paths
+}
+
+// envelopesIn returns the JSON objects in raw that carry this contract
+// version. ops and smoke version their envelopes on their own and publish
+// their reports as separate contracts.
+func envelopesIn(t *testing.T, raw []byte) [][]byte {
+	t.Helper()
+
+	envelopes := [][]byte{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	for {
+		var value json.RawMessage
+		// Stop at the end or at text that is not JSON, such as usage.
+		if err := decoder.Decode(&value); err != nil {
+			return envelopes
+		}
+		var header struct {
+			ContractVersion string `json:"contract_version"`
+		}
+		if json.Unmarshal(value, &header) == nil && header.ContractVersion == output.ContractVersion {
+			envelopes = append(envelopes, value)
+		}
+	}
+}
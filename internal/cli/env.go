@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	command "github.com/bilalbayram/metacli/internal/cli/cmd"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const defaultEnvPrefix = "META"
+
+// envUnboundFlags are never read from the environment. Neither are safety
+// overrides such as --break-glass, --approval-token, --force or --confirm-*:
+// an exported META_CONFIRM_DELETE would approve every delete the shell runs,
+// so they have to be given explicitly on each invocation that needs them.
+var envUnboundFlags = map[string]struct{}{
+	"env-prefix": {},
+	"help":       {},
+}
+
+// envYieldsTo skips binding a flag when a conflicting flag was set on the
+// command line: an explicit --quiet wins over META_OUTPUT.
+var envYieldsTo = map[string]string{
+	"output": "quiet",
+}
+
+// flagEnvName maps a flag to its environment variable: --account-id with the
+// META prefix reads META_ACCOUNT_ID. An empty prefix disables binding.
+func flagEnvName(prefix string, flagName string) string {
+	prefix = strings.Trim(strings.ToUpper(strings.TrimSpace(prefix)), "_")
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// bindFlagEnv fills every flag of cmd that was not set on the command line from
+// its environment variable, so precedence is flag > env > config. Bound flags
+// count as set, which keeps required-flag checks and replay plans accurate.
+func bindFlagEnv(cmd *cobra.Command, prefix string) error {
+	var bindErr error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if bindErr != nil || flag.Changed {
+			return
+		}
+		if _, skip := envUnboundFlags[flag.Name]; skip || config.IsSafetyOverrideFlag(flag.Name) {
+			return
+		}
+		if other, ok := envYieldsTo[flag.Name]; ok && cmd.Flags().Changed(other) {
+			return
+		}
+		name := flagEnvName(prefix, flag.Name)
+		if name == "" {
+			return
+		}
+		// Empty values are treated as unset so templated CI variables that
+		// expand to nothing do not clear defaults.
+		value := os.Getenv(name)
+		if strings.TrimSpace(value) == "" {
+			return
+		}
+		if err := cmd.Flags().Set(flag.Name, value); err != nil {
+			bindErr = fmt.Errorf("invalid %s value %q for --%s: %w", name, value, flag.Name, err)
+			return
+		}
+		markFlagSource(flag, "env "+name)
+	})
+	return bindErr
+}
+
+// markFlagSource records that flag was filled from source rather than the
+// command line.
+func markFlagSource(flag *pflag.Flag, source string) {
+	if flag.Annotations == nil {
+		flag.Annotations = map[string][]string{}
+	}
+	flag.Annotations[command.FlagSourceAnnotation] = []string{source}
+}
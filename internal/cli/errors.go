@@ -8,6 +8,10 @@ const (
 	ExitCodeAuth    = 3
 	ExitCodeInput   = 4
 	ExitCodeAPI     = 5
+
+	// Conventional shell codes for timeout(1) and SIGINT.
+	ExitCodeTimeout     = 124
+	ExitCodeInterrupted = 130
 )
 
 type ExitError struct {
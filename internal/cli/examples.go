@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// exampleFiles hold the examples shown under `--help` and in man pages, one
+// file per command named after its path: examples/campaign_create.txt for
+// `meta campaign create`. Examples are separated by blank lines; each is one
+// or more `#` description lines followed by a single command line. The golden
+// tests run every example through the command tree, so an example that stops
+// parsing after a flag change fails the build instead of going stale.
+//
+//go:embed examples/*.txt
+var exampleFiles embed.FS
+
+type commandExample struct {
+	Description []string
+	Line        string
+	// Args is Line split into arguments, without the leading binary name.
+	Args []string
+}
+
+type exampleSet struct {
+	// Command is the command path without the binary name.
+	Command  string
+	File     string
+	Examples []commandExample
+}
+
+func loadExamples() ([]exampleSet, error) {
+	entries, err := exampleFiles.ReadDir("examples")
+	if err != nil {
+		return nil, err
+	}
+	sets := make([]exampleSet, 0, len(entries))
+	for _, entry := range entries {
+		file := path.Join("examples", entry.Name())
+		data, err := exampleFiles.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		examples, err := parseExamples(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		sets = append(sets, exampleSet{
+			Command:  strings.ReplaceAll(strings.TrimSuffix(entry.Name(), ".txt"), "_", " "),
+			File:     file,
+			Examples: examples,
+		})
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].Command < sets[j].Command })
+	return sets, nil
+}
+
+func parseExamples(data string) ([]commandExample, error) {
+	examples := []commandExample{}
+	current := commandExample{}
+	for number, raw := range strings.Split(data, "\n") {
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "":
+			if len(current.Description) > 0 {
+				return nil, fmt.Errorf("line %d: description without a command", number+1)
+			}
+		case strings.HasPrefix(line, "#"):
+			current.Description = append(current.Description, strings.TrimSpace(strings.TrimPrefix(line, "#")))
+		default:
+			if len(current.Description) == 0 {
+				return nil, fmt.Errorf("line %d: command without a description", number+1)
+			}
+			args, err := splitCommandLine(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", number+1, err)
+			}
+			if len(args) == 0 || args[0] != appName {
+				return nil, fmt.Errorf("line %d: command must start with %q", number+1, appName)
+			}
+			current.Line = line
+			current.Args = args[1:]
+			examples = append(examples, current)
+			current = commandExample{}
+		}
+	}
+	if len(current.Description) > 0 {
+		return nil, fmt.Errorf("description at end of file without a command")
+	}
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("no examples")
+	}
+	return examples, nil
+}
+
+// splitCommandLine splits line the way a POSIX shell would for plain words,
+// single and double quotes and backslash escapes.
+func splitCommandLine(line string) ([]string, error) {
+	args := []string{}
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape")
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
+
+// renderExamples formats examples for cobra's Examples section.
+func renderExamples(examples []commandExample) string {
+	blocks := make([]string, 0, len(examples))
+	for _, example := range examples {
+		lines := make([]string, 0, len(example.Description)+1)
+		for _, description := range example.Description {
+			lines = append(lines, "  # "+description)
+		}
+		lines = append(lines, "  "+example.Line)
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// attachExamples sets the Example text of every command that has an examples
+// file.
+func attachExamples(root *cobra.Command) error {
+	sets, err := loadExamples()
+	if err != nil {
+		return err
+	}
+	for _, set := range sets {
+		found, _, err := root.Find(strings.Fields(set.Command))
+		if err != nil || found.CommandPath() != appName+" "+set.Command {
+			return fmt.Errorf("%s does not name a command", set.File)
+		}
+		found.Example = renderExamples(set.Examples)
+	}
+	return nil
+}
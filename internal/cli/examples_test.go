@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// TestExamplesRunAgainstTheCommandTree executes every example through a fresh
+// root command, with the command's own RunE swapped for a recorder, and
+// compares the resolved invocation with testdata/examples/<name>.golden. Global
+// validation, flag parsing, required flags and argument checks all run, so an
+// example broken by a renamed or removed flag fails here.
+func TestExamplesRunAgainstTheCommandTree(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	sets, err := loadExamples()
+	if err != nil {
+		t.Fatalf("load examples: %v", err)
+	}
+	if len(sets) == 0 {
+		t.Fatal("expected example files")
+	}
+	for _, set := range sets {
+		var golden strings.Builder
+		for index, example := range set.Examples {
+			resolved, err := runExample(example.Args)
+			if err != nil {
+				t.Fatalf("%s example %d (%s): %v", set.File, index+1, example.Line, err)
+			}
+			if !strings.HasPrefix(resolved, appName+" "+set.Command+"\n") {
+				t.Fatalf("%s example %d runs %q, not %q", set.File, index+1, strings.SplitN(resolved, "\n", 2)[0], appName+" "+set.Command)
+			}
+			if index > 0 {
+				golden.WriteString("\n")
+			}
+			golden.WriteString("$ " + example.Line + "\n" + resolved)
+		}
+		name := strings.ReplaceAll(set.Command, " ", "_") + ".golden"
+		assertExampleGolden(t, name, golden.String())
+	}
+}
+
+func TestExamplesAreShownInHelp(t *testing.T) {
+	t.Parallel()
+
+	root := NewRootCommand()
+	found, _, err := root.Find([]string{"campaign", "create"})
+	if err != nil {
+		t.Fatalf("find campaign create: %v", err)
+	}
+	stdout := &bytes.Buffer{}
+	found.SetOut(stdout)
+	if err := found.Help(); err != nil {
+		t.Fatalf("help: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Examples:\n  # Create a paused campaign") {
+		t.Fatalf("expected examples in help, got:\n%s", stdout.String())
+	}
+}
+
+func TestParseExamplesRejectsMalformedFiles(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "empty", data: "\n", want: "no examples"},
+		{name: "no description", data: "meta campaign list\n", want: "command without a description"},
+		{name: "dangling description", data: "# List campaigns.\n", want: "without a command"},
+		{name: "other binary", data: "# List campaigns.\nmetacli campaign list\n", want: `must start with "meta"`},
+		{name: "open quote", data: "# List campaigns.\nmeta campaign list --name \"spring\n", want: "unterminated"},
+	} {
+		if _, err := parseExamples(tc.data); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestSplitCommandLineHandlesQuotes(t *testing.T) {
+	t.Parallel()
+
+	got, err := splitCommandLine(`meta api get act_1 --params "a=1,b=two words" --json '{"k":"v"}' --var name="Spring Sale" x\ y`)
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+	want := []string{"meta", "api", "get", "act_1", "--params", "a=1,b=two words", "--json", `{"k":"v"}`, "--var", "name=Spring Sale", "x y"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("split = %q, want %q", got, want)
+	}
+}
+
+// runExample executes args and returns the command path, positional args and
+// every flag the invocation set, one per line.
+func runExample(args []string) (string, error) {
+	root := newRootCommand(&GlobalFlags{})
+	found, _, err := root.Find(args)
+	if err != nil {
+		return "", err
+	}
+	var resolved string
+	found.Run = nil
+	found.RunE = func(cmd *cobra.Command, positional []string) error {
+		lines := []string{cmd.CommandPath()}
+		for _, arg := range positional {
+			lines = append(lines, "  "+arg)
+		}
+		flags := []string{}
+		cmd.Flags().Visit(func(flag *pflag.Flag) {
+			if flag.Name == "env-prefix" {
+				return
+			}
+			flags = append(flags, fmt.Sprintf("  --%s=%s", flag.Name, flag.Value.String()))
+		})
+		sort.Strings(flags)
+		resolved = strings.Join(append(lines, flags...), "\n") + "\n"
+		return nil
+	}
+	// Keep META_* variables of the machine running the tests out of the result.
+	root.SetArgs(append([]string{"--env-prefix="}, args...))
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	if err := root.Execute(); err != nil {
+		return "", err
+	}
+	if resolved == "" {
+		return "", fmt.Errorf("command did not run")
+	}
+	return resolved, nil
+}
+
+func assertExampleGolden(t *testing.T, name string, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "examples", name)
+	want, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Fatalf("golden file %s is missing; expected contents:\n%s", path, got)
+		}
+		t.Fatalf("read golden file %s: %v", path, err)
+	}
+	if got != string(want) {
+		t.Fatalf("golden mismatch for %s\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
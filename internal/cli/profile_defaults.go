@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	command "github.com/bilalbayram/metacli/internal/cli/cmd"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/redact"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// workingDir is where project config discovery starts.
+var workingDir = os.Getwd
+
+// profileDefaultFlags are the flags a profile's defaults block may fill.
+var profileDefaultFlags = []string{"account-id", "lang", "output", "rules-dir", "schema-dir"}
+
+// changedFlags snapshots the flags set on the command line, before env and
+// profile binding mark more flags as changed.
+func changedFlags(cmd *cobra.Command) map[string]bool {
+	changed := map[string]bool{}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		changed[flag.Name] = true
+	})
+	return changed
+}
+
+func selectedProfileName(cmd *cobra.Command) string {
+	flag := cmd.Flags().Lookup("profile")
+	if flag == nil {
+		return ""
+	}
+	return strings.TrimSpace(flag.Value.String())
+}
+
+// bindProjectConfig applies the project-local .meta/config.yaml found above
+// the working directory: it selects the pinned profile when none was given and
+// fills still-unset flags from the project's defaults and flags.
+func bindProjectConfig(cmd *cobra.Command, sources map[string]string) error {
+	userPath, err := config.DefaultPath()
+	if err != nil {
+		return nil
+	}
+	dir, err := workingDir()
+	if err != nil {
+		return nil
+	}
+	path, err := config.FindProject(dir, userPath)
+	if err != nil || path == "" {
+		return err
+	}
+	project, err := config.LoadProject(path)
+	if err != nil {
+		return err
+	}
+
+	source := fmt.Sprintf("project %s", path)
+	if profile := strings.TrimSpace(project.Profile); profile != "" {
+		if err := bindFlagDefaults(cmd, map[string]string{"profile": profile}, source, sources); err != nil {
+			return err
+		}
+	}
+	return bindFlagDefaults(cmd, project.FlagValues(), source, sources)
+}
+
+// bindProfileDefaults fills flags still unset from the selected profile's
+// defaults, so precedence is flag > env > project > profile > built-in. A
+// missing or unreadable config is left for the command itself to report.
+func bindProfileDefaults(cmd *cobra.Command, profileName string, sources map[string]string) error {
+	if profileName == "" {
+		return nil
+	}
+	configPath, err := config.DefaultPath()
+	if err != nil {
+		return nil
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil
+	}
+	profile, ok := cfg.Profiles[profileName]
+	if !ok {
+		return nil
+	}
+	return bindFlagDefaults(cmd, profile.Defaults.Flags(), fmt.Sprintf("profile %s", profileName), sources)
+}
+
+// bindFlagDefaults sets each flag of cmd that is still unset to its value in
+// values and records source for it. Values for flags the command does not
+// have are ignored, and safety overrides are never filled from config.
+func bindFlagDefaults(cmd *cobra.Command, values map[string]string, source string, sources map[string]string) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		if config.IsSafetyOverrideFlag(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if other, ok := envYieldsTo[name]; ok && cmd.Flags().Changed(other) {
+			continue
+		}
+		if err := cmd.Flags().Set(name, values[name]); err != nil {
+			return fmt.Errorf("invalid %s value %q for --%s: %w", source, values[name], name, err)
+		}
+		sources[name] = source
+		markFlagSource(flag, source)
+	}
+	return nil
+}
+
+// writeFlagProvenance reports under --debug where each defaultable flag of the
+// command, and every flag filled from config, got its value.
+func writeFlagProvenance(w io.Writer, cmd *cobra.Command, explicit map[string]bool, envPrefix string, sources map[string]string) {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, name := range profileDefaultFlags {
+		seen[name] = true
+		names = append(names, name)
+	}
+	for name := range sources {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			continue
+		}
+		fmt.Fprintf(w, "debug: --%s=%q (from %s)\n", name, redactFlagValue(flag), flagSource(flag, explicit, envPrefix, sources))
+	}
+}
+
+// flagProvenance maps every flag of the command that did not keep its default
+// to its redacted value and source, for dry-run plans.
+func flagProvenance(cmd *cobra.Command, explicit map[string]bool, envPrefix string, sources map[string]string) map[string]command.FlagProvenance {
+	provenance := map[string]command.FlagProvenance{}
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if !flag.Changed && sources[flag.Name] == "" {
+			return
+		}
+		provenance[flag.Name] = command.FlagProvenance{
+			Value:  redactFlagValue(flag),
+			Source: flagSource(flag, explicit, envPrefix, sources),
+		}
+	})
+	return provenance
+}
+
+func flagSource(flag *pflag.Flag, explicit map[string]bool, envPrefix string, sources map[string]string) string {
+	switch {
+	case explicit[flag.Name]:
+		return "flag"
+	case sources[flag.Name] != "":
+		return sources[flag.Name]
+	case flag.Changed:
+		return fmt.Sprintf("env %s", flagEnvName(envPrefix, flag.Name))
+	default:
+		return "default"
+	}
+}
+
+func redactFlagValue(flag *pflag.Flag) string {
+	return redact.Field(strings.ReplaceAll(flag.Name, "-", "_"), flag.Value.String())
+}
@@ -1,10 +1,23 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
 	"runtime/debug"
+	"strings"
+	"syscall"
+	"time"
 
 	command "github.com/bilalbayram/metacli/internal/cli/cmd"
+	"github.com/bilalbayram/metacli/internal/filelock"
+	"github.com/bilalbayram/metacli/internal/i18n"
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/bilalbayram/metacli/internal/query"
+	"github.com/bilalbayram/metacli/internal/transport"
 	"github.com/spf13/cobra"
 )
 
@@ -26,36 +39,108 @@ func init() {
 type GlobalFlags struct {
 	Profile string
 	Output  string
-	Debug   bool
+	Columns string
+	Query   string
+	Quiet   bool
+	Timeout time.Duration
+	// WaitLock is how long to wait for a config or state file locked by
+	// another process; zero fails immediately.
+	WaitLock time.Duration
+	Debug    bool
+	// NoProgress turns off progress reporting on stderr, for CI logs.
+	NoProgress bool
+	// EnvPrefix names the environment variables bound to flags; empty disables them.
+	EnvPrefix string
+	// ApprovalTokens are second-operator approvals for mutations the approval
+	// policy covers, each a token or a file holding one.
+	ApprovalTokens []string
+	// BreakGlass overrides freeze windows; the reason goes to the audit log.
+	BreakGlass string
+	// OverrideAnomaly lets resumes and budget increases through a spend
+	// anomaly; the reason goes to the audit log.
+	OverrideAnomaly string
+	// DryRun plans every mutation instead of sending it.
+	DryRun bool
+	// Offline forbids network calls: reads come from the read cache and
+	// mutations are planned as with DryRun.
+	Offline bool
+	// Lang is the language of prompts and remediation text; empty is English.
+	Lang string
+
+	// releaseTimeout stops the --timeout timer once the command returns.
+	releaseTimeout context.CancelFunc
 }
 
+// Execute runs the CLI under a context canceled by Ctrl-C or SIGTERM. After the
+// first signal default handling is restored, so a second one exits immediately.
 func Execute() error {
-	root := NewRootCommand()
-	return root.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
+	// Workflow steps, fleet runs and retries replay under this context, so they
+	// all share one account context cache.
+	ctx = marketing.WithAccountContextCache(ctx, marketing.NewAccountContextCache())
+
+	flags := &GlobalFlags{}
+	root := newRootCommand(flags)
+	started := time.Now()
+	executed, err := root.ExecuteContextC(ctx)
+	command.RecordUsage(executed, time.Since(started), err)
+	if flags.Debug {
+		writeTransportStats(root.ErrOrStderr(), transport.Snapshot())
+	}
+	if flags.releaseTimeout != nil {
+		flags.releaseTimeout()
+	}
+	return wrapCanceled(executed, err)
+}
+
+func newRuntime(flags *GlobalFlags) command.Runtime {
+	return command.Runtime{
+		Profile:    &flags.Profile,
+		Output:     &flags.Output,
+		Columns:    &flags.Columns,
+		Query:      &flags.Query,
+		Quiet:      &flags.Quiet,
+		Debug:      &flags.Debug,
+		NoProgress: &flags.NoProgress,
+		Lang:       &flags.Lang,
+	}
 }
 
 func NewRootCommand() *cobra.Command {
-	flags := &GlobalFlags{}
+	return newRootCommand(&GlobalFlags{})
+}
 
+func newRootCommand(flags *GlobalFlags) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               appName,
 		Short:             "Meta Marketing CLI",
 		Long:              "Meta Marketing CLI provides authenticated access to Meta Graph and Marketing APIs.",
 		SilenceErrors:     true,
 		SilenceUsage:      true,
-		PersistentPreRunE: validateGlobalFlags(flags),
+		PersistentPreRunE: prepareRootRun(flags),
 	}
 
 	cmd.PersistentFlags().StringVar(&flags.Profile, "profile", "", "Auth profile name")
-	cmd.PersistentFlags().StringVar(&flags.Output, "output", "json", "Output format: json|jsonl|table|csv")
+	cmd.PersistentFlags().StringVar(&flags.Output, "output", "json", "Output format: json|jsonl|table|csv|ids")
+	cmd.PersistentFlags().StringVar(&flags.Columns, "columns", "", "Comma-separated columns for --output table (dot paths select nested fields) and the csv header of streamed exports")
+	cmd.PersistentFlags().StringVar(&flags.Query, "query", "", "JMESPath expression applied to the envelope data before rendering")
+	cmd.PersistentFlags().BoolVar(&flags.Quiet, "quiet", false, "Suppress the envelope and print only the primary id or result")
+	cmd.PersistentFlags().DurationVar(&flags.Timeout, "timeout", 0, "Abort the command after this duration, e.g. 30s or 5m (0 disables)")
+	cmd.PersistentFlags().DurationVar(&flags.WaitLock, "wait-lock", 0, "Wait up to this duration for a config or state file locked by another meta process, e.g. 30s (0 fails immediately)")
 	cmd.PersistentFlags().BoolVar(&flags.Debug, "debug", false, "Enable debug logging")
+	cmd.PersistentFlags().BoolVar(&flags.NoProgress, "no-progress", false, "Do not report progress of long operations on stderr (bars are only drawn when stderr is a terminal)")
+	cmd.PersistentFlags().BoolVar(&flags.DryRun, "dry-run", false, "Plan every mutation (method, path, final payload, flag provenance) without sending it; reads still run")
+	cmd.PersistentFlags().BoolVar(&flags.Offline, "offline", false, "Forbid network calls: serve reads from the local read cache, plan mutations as with --dry-run, and fail anything else with offline_unavailable")
+	cmd.PersistentFlags().StringArrayVar(&flags.ApprovalTokens, "approval-token", nil, "Approval token (or file holding one) from meta approve for a mutation the approval policy covers (repeatable)")
+	cmd.PersistentFlags().StringVar(&flags.BreakGlass, "break-glass", "", "Override freeze windows for this invocation; the reason is recorded in the audit log")
+	cmd.PersistentFlags().StringVar(&flags.OverrideAnomaly, "override-anomaly", "", "Resume or raise budgets despite a spend anomaly for this invocation; the reason is recorded in the audit log")
+	cmd.PersistentFlags().StringVar(&flags.Lang, "lang", "", "Language of prompts and remediation text: "+strings.Join(i18n.Supported(), "|")+" (codes and field names stay in English)")
+	cmd.PersistentFlags().StringVar(&flags.EnvPrefix, "env-prefix", defaultEnvPrefix, "Prefix of environment variables that set unset flags, e.g. META_ACCOUNT_ID for --account-id (empty disables)")
 	configureVersionFlag(cmd)
 
-	runtime := command.Runtime{
-		Profile: &flags.Profile,
-		Output:  &flags.Output,
-		Debug:   &flags.Debug,
-	}
+	runtime := newRuntime(flags)
 
 	cmd.AddCommand(command.NewAuthCommand(runtime))
 	cmd.AddCommand(command.NewAPICommand(runtime))
@@ -67,21 +152,70 @@ func NewRootCommand() *cobra.Command {
 	cmd.AddCommand(command.NewIGCommand(runtime))
 	cmd.AddCommand(command.NewWACommand(runtime))
 	cmd.AddCommand(command.NewMSGRCommand(runtime))
+	cmd.AddCommand(command.NewPageCommand(runtime))
+	cmd.AddCommand(command.NewPublishCommand(runtime))
 	cmd.AddCommand(command.NewThreadsCommand(runtime))
 	cmd.AddCommand(command.NewCAPICommand(runtime))
 	cmd.AddCommand(command.NewOpsCommand(runtime))
 	cmd.AddCommand(command.NewSmokeCommand(runtime))
 	cmd.AddCommand(command.NewEnterpriseCommand(runtime))
+	cmd.AddCommand(command.NewAccountCommand(runtime))
 	cmd.AddCommand(command.NewCampaignCommand(runtime))
 	cmd.AddCommand(command.NewAdsetCommand(runtime))
 	cmd.AddCommand(command.NewAdCommand(runtime))
+	cmd.AddCommand(command.NewPlanCommand(runtime))
+	cmd.AddCommand(command.NewApplyCommand(runtime))
+	cmd.AddCommand(command.NewExportCommand(runtime))
+	cmd.AddCommand(command.NewTemplateCommand(runtime))
+	cmd.AddCommand(command.NewBulkCommand(runtime))
+	cmd.AddCommand(command.NewWorkflowCommand(runtime))
 	cmd.AddCommand(command.NewCreativeCommand(runtime))
 	cmd.AddCommand(command.NewAudienceCommand(runtime))
 	cmd.AddCommand(command.NewCatalogCommand(runtime))
+	cmd.AddCommand(command.NewCommerceCommand(runtime))
+	cmd.AddCommand(command.NewLeadsCommand(runtime))
+	cmd.AddCommand(command.NewBusinessCommand(runtime))
+	cmd.AddCommand(command.NewWebhookCommand(runtime))
+	cmd.AddCommand(command.NewPluginCommand(runtime))
+	cmd.AddCommand(command.NewRetryCommand(runtime, replayArgs))
+	cmd.AddCommand(command.NewTUICommand(runtime))
+	cmd.AddCommand(command.NewAuditCommand(runtime))
+	cmd.AddCommand(command.NewApproveCommand(runtime))
+	cmd.AddCommand(command.NewConfigCommand(runtime))
+	cmd.AddCommand(command.NewInitCommand(runtime))
+	cmd.AddCommand(command.NewMetricsCommand(runtime))
+	cmd.AddCommand(command.NewDebugCommand(runtime))
+	cmd.AddCommand(command.NewDocsCommand(runtime))
+	cmd.AddCommand(command.NewVersionCommand(runtime, Version))
+	cmd.AddCommand(command.NewSelfUpdateCommand(runtime, Version))
+	cmd.AddCommand(command.NewServeCommand(runtime, replayArgs, Version))
+
+	command.SetFleetReplayer(replayArgs)
+
+	// External plugin discovery errors are surfaced by `meta plugin list`.
+	_ = command.AddExternalPluginCommands(cmd, runtime)
+	command.RegisterDynamicCompletions(cmd)
+	// Broken example files fail the golden tests in examples_test.go.
+	_ = attachExamples(cmd)
 
 	return cmd
 }
 
+// replayArgs runs a recorded invocation through a fresh root command so flag state
+// from the retry invocation does not leak into the replayed one.
+func replayArgs(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := &GlobalFlags{}
+	root := newRootCommand(flags)
+	root.SetArgs(args)
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	_, err := root.ExecuteContextC(ctx)
+	if flags.releaseTimeout != nil {
+		flags.releaseTimeout()
+	}
+	return err
+}
+
 func configureVersionFlag(cmd *cobra.Command) {
 	if cmd == nil {
 		return
@@ -99,13 +233,134 @@ func configureVersionFlag(cmd *cobra.Command) {
 	versionFlag.Usage = "Print the CLI version"
 }
 
+// writeTransportStats reports how the shared HTTP transport was used, so
+// --debug shows whether requests reused connections and negotiated HTTP/2.
+func writeTransportStats(w io.Writer, stats transport.Stats) {
+	if stats.Requests == 0 {
+		return
+	}
+	fmt.Fprintf(w, "debug: transport requests=%d new_connections=%d reused_connections=%d idle_reuses=%d tls_handshakes=%d http2_responses=%d http1_responses=%d failed_round_trips=%d\n",
+		stats.Requests, stats.NewConnections, stats.ReusedConnections, stats.IdleReuses, stats.TLSHandshakes, stats.HTTP2Responses, stats.HTTP1Responses, stats.FailedRoundTrips)
+}
+
+// wrapCanceled maps a command that failed because its context ended to the
+// timeout or interrupt exit code.
+func wrapCanceled(executed *cobra.Command, err error) error {
+	if err == nil || executed == nil {
+		return err
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return err
+	}
+	switch command.CancellationReason(executed.Context()) {
+	case command.CancelReasonTimeout:
+		return WrapExit(ExitCodeTimeout, err)
+	case command.CancelReasonInterrupted:
+		return WrapExit(ExitCodeInterrupted, err)
+	default:
+		return err
+	}
+}
+
 func validateGlobalFlags(flags *GlobalFlags) func(*cobra.Command, []string) error {
-	return func(_ *cobra.Command, _ []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
 		switch flags.Output {
-		case "json", "jsonl", "table", "csv":
-			return nil
+		case "json", "jsonl", "table", "csv", "ids":
 		default:
-			return WrapExit(ExitCodeInput, fmt.Errorf("invalid --output value %q; expected json|jsonl|table|csv", flags.Output))
+			return WrapExit(ExitCodeInput, fmt.Errorf("invalid --output value %q; expected json|jsonl|table|csv|ids", flags.Output))
+		}
+		if flags.Timeout < 0 {
+			return WrapExit(ExitCodeInput, fmt.Errorf("--timeout must be >= 0, got %s", flags.Timeout))
+		}
+		if flags.WaitLock < 0 {
+			return WrapExit(ExitCodeInput, fmt.Errorf("--wait-lock must be >= 0, got %s", flags.WaitLock))
+		}
+		if cmd.Flags().Changed("break-glass") && strings.TrimSpace(flags.BreakGlass) == "" {
+			return WrapExit(ExitCodeInput, fmt.Errorf("--break-glass requires a reason"))
+		}
+		if cmd.Flags().Changed("override-anomaly") && strings.TrimSpace(flags.OverrideAnomaly) == "" {
+			return WrapExit(ExitCodeInput, fmt.Errorf("--override-anomaly requires a reason"))
+		}
+		if _, err := i18n.Parse(flags.Lang); err != nil {
+			return WrapExit(ExitCodeInput, fmt.Errorf("invalid --lang: %w", err))
+		}
+		if flags.Quiet && cmd.Flags().Changed("output") {
+			return WrapExit(ExitCodeInput, fmt.Errorf("--quiet cannot be combined with --output"))
+		}
+		if strings.TrimSpace(flags.Columns) != "" && flags.Output != "table" {
+			return WrapExit(ExitCodeInput, fmt.Errorf("--columns requires --output table"))
+		}
+		if strings.TrimSpace(flags.Query) != "" {
+			if _, err := query.Compile(flags.Query); err != nil {
+				return WrapExit(ExitCodeInput, fmt.Errorf("invalid --query: %w", err))
+			}
+		}
+		return nil
+	}
+}
+
+func prepareRootRun(flags *GlobalFlags) func(*cobra.Command, []string) error {
+	validate := validateGlobalFlags(flags)
+	return func(cmd *cobra.Command, args []string) error {
+		explicit := changedFlags(cmd)
+		if err := bindFlagEnv(cmd, flags.EnvPrefix); err != nil {
+			return WrapExit(ExitCodeInput, err)
+		}
+		sources := map[string]string{}
+		if err := bindProjectConfig(cmd, sources); err != nil {
+			return WrapExit(ExitCodeConfig, err)
+		}
+		if err := bindProfileDefaults(cmd, selectedProfileName(cmd), sources); err != nil {
+			return WrapExit(ExitCodeConfig, err)
+		}
+		if flags.Debug {
+			writeFlagProvenance(cmd.ErrOrStderr(), cmd, explicit, flags.EnvPrefix, sources)
+		}
+		if err := validate(cmd, args); err != nil {
+			return err
+		}
+		if err := command.EnforceCommandPolicy(cmd, newRuntime(flags)); err != nil {
+			return WrapExit(ExitCodeConfig, err)
+		}
+		if err := command.EnforceScopePreflight(cmd, newRuntime(flags)); err != nil {
+			return WrapExit(ExitCodeAuth, err)
+		}
+		if err := command.ConfigureTraceSinks(cmd.ErrOrStderr()); err != nil {
+			return WrapExit(ExitCodeConfig, fmt.Errorf("configure plugin trace sinks: %w", err))
+		}
+		filelock.SetWait(flags.WaitLock)
+		command.ConfigureDebugLog(cmd.ErrOrStderr(), flags.Debug)
+		command.ConfigureAuditLog(cmd)
+		command.ConfigureReadCache()
+		// Commands with their own --dry-run shadow the global flag and plan
+		// themselves; --offline plans every mutation regardless.
+		if flags.DryRun || flags.Offline {
+			command.EnableDryRun(cmd, flagProvenance(cmd, explicit, flags.EnvPrefix, sources))
+		}
+		if flags.Offline {
+			command.EnableOffline(cmd)
+		}
+		if err := command.ConfigureFreezeWindows(); err != nil {
+			return WrapExit(ExitCodeConfig, fmt.Errorf("configure freeze windows: %w", err))
+		}
+		if err := command.ConfigureSpendGuardrails(); err != nil {
+			return WrapExit(ExitCodeConfig, fmt.Errorf("configure spend guardrails: %w", err))
+		}
+		if err := command.ConfigureAnomalyGuard(); err != nil {
+			return WrapExit(ExitCodeConfig, fmt.Errorf("configure spend anomaly guard: %w", err))
+		}
+		if err := command.ConfigureApprovalGate(flags.ApprovalTokens); err != nil {
+			return WrapExit(ExitCodeConfig, fmt.Errorf("configure approval gate: %w", err))
+		}
+		if err := command.ConfigureHooks(cmd.ErrOrStderr()); err != nil {
+			return WrapExit(ExitCodeConfig, fmt.Errorf("configure hooks: %w", err))
+		}
+		if flags.Timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), flags.Timeout)
+			flags.releaseTimeout = cancel
+			cmd.SetContext(ctx)
 		}
+		return nil
 	}
 }
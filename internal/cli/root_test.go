@@ -2,8 +2,21 @@ package cli
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/replay"
+	"github.com/bilalbayram/metacli/internal/scopes"
+	"github.com/bilalbayram/metacli/internal/transport"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func TestRootRegistersDoctorCommand(t *testing.T) {
@@ -26,6 +39,16 @@ func TestRootRegistersDoctorCommand(t *testing.T) {
 	}
 }
 
+func TestScopeCapabilitiesNameRegisteredCommands(t *testing.T) {
+	root := NewRootCommand()
+	for _, command := range scopes.Commands() {
+		found, _, err := root.Find(strings.Fields(command))
+		if err != nil || found.CommandPath() != "meta "+command {
+			t.Fatalf("scope capability %q does not name a command (found %v, err %v)", command, found, err)
+		}
+	}
+}
+
 func TestRootVersionFlags(t *testing.T) {
 	t.Parallel()
 
@@ -118,12 +141,60 @@ func TestSubcommandRequiredErrorsPrintHelp(t *testing.T) {
 			errorString: "creative requires a subcommand",
 			usagePrefix: "meta creative",
 		},
+		{
+			name:        "template",
+			args:        []string{"template"},
+			errorString: "template requires a subcommand",
+			usagePrefix: "meta template",
+		},
+		{
+			name:        "bulk",
+			args:        []string{"bulk"},
+			errorString: "bulk requires a subcommand",
+			usagePrefix: "meta bulk",
+		},
+		{
+			name:        "workflow",
+			args:        []string{"workflow"},
+			errorString: "workflow requires a subcommand",
+			usagePrefix: "meta workflow",
+		},
 		{
 			name:        "catalog",
 			args:        []string{"catalog"},
 			errorString: "catalog requires a subcommand",
 			usagePrefix: "meta catalog",
 		},
+		{
+			name:        "commerce",
+			args:        []string{"commerce"},
+			errorString: "commerce requires a subcommand",
+			usagePrefix: "meta commerce",
+		},
+		{
+			name:        "leads",
+			args:        []string{"leads"},
+			errorString: "leads requires a subcommand",
+			usagePrefix: "meta leads",
+		},
+		{
+			name:        "business",
+			args:        []string{"business"},
+			errorString: "business requires a subcommand",
+			usagePrefix: "meta business",
+		},
+		{
+			name:        "account",
+			args:        []string{"account"},
+			errorString: "account requires a subcommand",
+			usagePrefix: "meta account",
+		},
+		{
+			name:        "webhook",
+			args:        []string{"webhook"},
+			errorString: "webhook requires a subcommand",
+			usagePrefix: "meta webhook",
+		},
 	}
 
 	for _, tc := range cases {
@@ -162,3 +233,554 @@ func TestSubcommandRequiredErrorsPrintHelp(t *testing.T) {
 		})
 	}
 }
+
+func TestRootRejectsColumnsOutsideTableOutput(t *testing.T) {
+	t.Parallel()
+
+	root := NewRootCommand()
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"template", "list", "--columns", "name"})
+
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--columns requires --output table") {
+		t.Fatalf("expected columns validation error, got %v", err)
+	}
+}
+
+func TestRootRejectsInvalidQuery(t *testing.T) {
+	t.Parallel()
+
+	root := NewRootCommand()
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"template", "list", "--query", "items[?status='ACTIVE']"})
+
+	err := root.Execute()
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeInput || !strings.Contains(err.Error(), "invalid --query") {
+		t.Fatalf("expected invalid query input error, got %v", err)
+	}
+}
+
+func TestRootRejectsQuietWithExplicitOutput(t *testing.T) {
+	t.Parallel()
+
+	root := NewRootCommand()
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"template", "list", "--quiet", "--output", "ids"})
+
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--quiet cannot be combined with --output") {
+		t.Fatalf("expected quiet/output conflict, got %v", err)
+	}
+}
+
+func TestRootRejectsUnsupportedLanguage(t *testing.T) {
+	t.Parallel()
+
+	root := NewRootCommand()
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"template", "list", "--lang", "de"})
+
+	err := root.Execute()
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeInput || !strings.Contains(err.Error(), "invalid --lang") {
+		t.Fatalf("expected invalid lang input error, got %v", err)
+	}
+}
+
+func TestRootTimeoutBoundsTheCommandContext(t *testing.T) {
+	t.Parallel()
+
+	flags := &GlobalFlags{}
+	root := newRootCommand(flags)
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"template", "list", "--timeout", "-1s"})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "--timeout must be >= 0") {
+		t.Fatalf("expected negative timeout error, got %v", err)
+	}
+
+	flags = &GlobalFlags{}
+	root = newRootCommand(flags)
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"template", "list", "--timeout", "1ns"})
+	executed, _ := root.ExecuteC()
+	defer flags.releaseTimeout()
+	if _, ok := executed.Context().Deadline(); !ok {
+		t.Fatal("expected --timeout to set a deadline on the command context")
+	}
+}
+
+// redeclaredGlobalFlags are global flags some commands declare again with the
+// same meaning.
+var redeclaredGlobalFlags = map[string]bool{
+	"approval-token": true,
+	"dry-run":        true,
+	"profile":        true,
+}
+
+func TestNoCommandShadowsAGlobalFlag(t *testing.T) {
+	t.Parallel()
+
+	root := NewRootCommand()
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		cmd.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+			if root.PersistentFlags().Lookup(flag.Name) != nil && !redeclaredGlobalFlags[flag.Name] {
+				t.Errorf("%s declares its own --%s, which hides the global --%s; give it a distinct name", cmd.CommandPath(), flag.Name, flag.Name)
+			}
+		})
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+	}
+	walk(root)
+}
+
+func TestWrapCanceledMapsContextEndToExitCodes(t *testing.T) {
+	t.Parallel()
+
+	timedOut, cancelTimeout := context.WithTimeout(context.Background(), -time.Second)
+	defer cancelTimeout()
+	interrupted, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := []struct {
+		ctx  context.Context
+		want int
+	}{
+		{ctx: timedOut, want: ExitCodeTimeout},
+		{ctx: interrupted, want: ExitCodeInterrupted},
+	}
+	for _, tc := range cases {
+		executed := &cobra.Command{}
+		executed.SetContext(tc.ctx)
+		var exitErr *ExitError
+		if err := wrapCanceled(executed, errors.New("send request")); !errors.As(err, &exitErr) || exitErr.Code != tc.want {
+			t.Fatalf("expected exit code %d, got %v", tc.want, err)
+		}
+	}
+
+	executed := &cobra.Command{}
+	executed.SetContext(context.Background())
+	if err := wrapCanceled(executed, errors.New("boom")); errors.As(err, new(*ExitError)) {
+		t.Fatalf("expected live context errors to pass through, got %v", err)
+	}
+}
+
+func TestRootRetryReplaysRecordedPlan(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "last-failed.json")
+	plan := replay.NewPlan("meta changelog check", []string{"changelog", "check", "--version=v25.0"}, replay.Failure{
+		Class:   "transient_error",
+		Type:    "error",
+		Message: "connection reset",
+	}, time.Now())
+	if err := replay.Save(path, plan); err != nil {
+		t.Fatalf("save replay plan: %v", err)
+	}
+
+	output := &bytes.Buffer{}
+	root := NewRootCommand()
+	root.SetOut(output)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"retry", "--from", path})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if !strings.Contains(output.String(), `"command": "meta changelog check"`) {
+		t.Fatalf("expected replayed changelog envelope, got %s", output.String())
+	}
+}
+
+func newEnvProbeRoot(t *testing.T, captured map[string]string) *cobra.Command {
+	t.Helper()
+	flags := &GlobalFlags{}
+	root := newRootCommand(flags)
+	probe := &cobra.Command{
+		Use: "probe",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			for _, name := range []string{"account-id", "limit"} {
+				captured[name] = cmd.Flags().Lookup(name).Value.String()
+			}
+			captured["profile"] = flags.Profile
+			captured["output"] = flags.Output
+			return nil
+		},
+	}
+	probe.Flags().String("account-id", "", "")
+	probe.Flags().Int("limit", 10, "")
+	if err := probe.MarkFlagRequired("account-id"); err != nil {
+		t.Fatalf("mark required: %v", err)
+	}
+	root.AddCommand(probe)
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	return root
+}
+
+func TestRootBindsUnsetFlagsFromEnvironment(t *testing.T) {
+	t.Setenv("META_ACCOUNT_ID", "act_env")
+	t.Setenv("META_LIMIT", "25")
+	t.Setenv("META_PROFILE", "ci")
+	t.Setenv("META_OUTPUT", "")
+	t.Setenv("CI_ACCOUNT_ID", "act_ci")
+
+	cases := []struct {
+		name string
+		args []string
+		want map[string]string
+	}{
+		{
+			name: "env fills unset flags and satisfies required flags",
+			args: []string{"probe"},
+			want: map[string]string{"account-id": "act_env", "limit": "25", "profile": "ci", "output": "json"},
+		},
+		{
+			name: "explicit flags win over env",
+			args: []string{"--profile", "prod", "probe", "--account-id", "act_flag"},
+			want: map[string]string{"account-id": "act_flag", "limit": "25", "profile": "prod"},
+		},
+		{
+			name: "custom prefix",
+			args: []string{"--env-prefix", "CI", "probe"},
+			want: map[string]string{"account-id": "act_ci", "limit": "10", "profile": ""},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			captured := map[string]string{}
+			root := newEnvProbeRoot(t, captured)
+			root.SetArgs(tc.args)
+			if err := root.Execute(); err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			for key, want := range tc.want {
+				if captured[key] != want {
+					t.Fatalf("expected %s=%q, got %q", key, want, captured[key])
+				}
+			}
+		})
+	}
+
+	root := newEnvProbeRoot(t, map[string]string{})
+	root.SetArgs([]string{"--env-prefix", "", "probe"})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), `required flag(s) "account-id" not set`) {
+		t.Fatalf("expected an empty prefix to disable binding, got %v", err)
+	}
+
+	t.Setenv("META_LIMIT", "many")
+	root = newEnvProbeRoot(t, map[string]string{})
+	root.SetArgs([]string{"probe"})
+	err := root.Execute()
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeInput || !strings.Contains(err.Error(), `invalid META_LIMIT value "many" for --limit`) {
+		t.Fatalf("expected invalid env value input error, got %v", err)
+	}
+}
+
+func TestSafetyOverrideFlagsIgnoreTheEnvironment(t *testing.T) {
+	root := NewRootCommand()
+	overrides := map[string]struct{}{}
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+		cmd.InheritedFlags()
+		cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+			if flag.Name == "force" || flag.Name == "break-glass" || flag.Name == "override-anomaly" || flag.Name == "approval-token" || strings.HasPrefix(flag.Name, "confirm-") {
+				overrides[flag.Name] = struct{}{}
+				t.Setenv(flagEnvName(defaultEnvPrefix, flag.Name), "true")
+			}
+		})
+	}
+	walk(root)
+	for _, name := range []string{"approval-token", "break-glass", "confirm-budget-change", "confirm-delete", "confirm-grant", "force", "override-anomaly"} {
+		if _, ok := overrides[name]; !ok {
+			t.Fatalf("expected a command with --%s", name)
+		}
+	}
+
+	walk = func(cmd *cobra.Command) {
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+		if err := bindFlagEnv(cmd, defaultEnvPrefix); err != nil {
+			t.Fatalf("bind %s: %v", cmd.CommandPath(), err)
+		}
+		cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+			if _, ok := overrides[flag.Name]; ok && flag.Changed {
+				t.Errorf("%s --%s was read from %s", cmd.CommandPath(), flag.Name, flagEnvName(defaultEnvPrefix, flag.Name))
+			}
+		})
+	}
+	walk(root)
+}
+
+func TestRootFillsUnsetFlagsFromProfileDefaults(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("META_ACCOUNT_ID", "")
+	t.Setenv("META_OUTPUT", "")
+	t.Setenv("META_PROFILE", "")
+
+	cfg := config.New()
+	if err := cfg.UpsertProfile("prod", config.Profile{
+		TokenType:       "system_user",
+		AppID:           "app-1",
+		TokenRef:        "keychain://meta-marketing-cli/prod/token",
+		AppSecretRef:    "keychain://meta-marketing-cli/prod/app_secret",
+		AuthProvider:    "system_user",
+		AuthMode:        "both",
+		Scopes:          []string{"ads_read"},
+		IssuedAt:        "2026-01-01T00:00:00Z",
+		ExpiresAt:       "2027-01-01T00:00:00Z",
+		LastValidatedAt: "2026-01-01T00:00:00Z",
+		Defaults:        config.ProfileDefaults{AccountID: "act_profile", Output: "table"},
+	}); err != nil {
+		t.Fatalf("upsert profile: %v", err)
+	}
+	if err := config.Save(filepath.Join(home, ".meta", "config.yaml"), cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	captured := map[string]string{}
+	root := newEnvProbeRoot(t, captured)
+	stderr := &bytes.Buffer{}
+	root.SetErr(stderr)
+	root.SetArgs([]string{"--profile", "prod", "--debug", "probe"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if captured["account-id"] != "act_profile" || captured["output"] != "table" {
+		t.Fatalf("expected profile defaults, got %v", captured)
+	}
+	if !strings.Contains(stderr.String(), `debug: --account-id="act_profile" (from profile prod)`) {
+		t.Fatalf("expected provenance in debug output, got %q", stderr.String())
+	}
+
+	t.Setenv("META_OUTPUT", "csv")
+	captured = map[string]string{}
+	root = newEnvProbeRoot(t, captured)
+	stderr = &bytes.Buffer{}
+	root.SetErr(stderr)
+	root.SetArgs([]string{"--profile", "prod", "--debug", "probe", "--account-id", "act_flag"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if captured["account-id"] != "act_flag" || captured["output"] != "csv" {
+		t.Fatalf("expected flag and env to win over profile defaults, got %v", captured)
+	}
+	for _, line := range []string{`debug: --account-id="act_flag" (from flag)`, `debug: --output="csv" (from env META_OUTPUT)`} {
+		if !strings.Contains(stderr.String(), line) {
+			t.Fatalf("expected %q in debug output, got %q", line, stderr.String())
+		}
+	}
+
+	captured = map[string]string{}
+	root = newEnvProbeRoot(t, captured)
+	root.SetArgs([]string{"--profile", "other", "probe", "--account-id", "act_flag"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if captured["output"] != "csv" {
+		t.Fatalf("expected no profile defaults for another profile, got %v", captured)
+	}
+}
+
+func TestRootAppliesProjectConfigOverProfileDefaults(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("META_ACCOUNT_ID", "")
+	t.Setenv("META_OUTPUT", "")
+	t.Setenv("META_PROFILE", "")
+	t.Setenv("META_LIMIT", "")
+
+	cfg := config.New()
+	if err := cfg.UpsertProfile("acme", config.Profile{
+		TokenType:       "system_user",
+		AppID:           "app-1",
+		TokenRef:        "keychain://meta-marketing-cli/acme/token",
+		AppSecretRef:    "keychain://meta-marketing-cli/acme/app_secret",
+		AuthProvider:    "system_user",
+		AuthMode:        "both",
+		Scopes:          []string{"ads_read"},
+		IssuedAt:        "2026-01-01T00:00:00Z",
+		ExpiresAt:       "2027-01-01T00:00:00Z",
+		LastValidatedAt: "2026-01-01T00:00:00Z",
+		Defaults:        config.ProfileDefaults{AccountID: "act_profile", Output: "table"},
+	}); err != nil {
+		t.Fatalf("upsert profile: %v", err)
+	}
+	if err := config.Save(filepath.Join(home, ".meta", "config.yaml"), cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	repo := filepath.Join(t.TempDir(), "repo")
+	projectPath := filepath.Join(repo, ".meta", "config.yaml")
+	if err := os.MkdirAll(filepath.Join(repo, ".meta"), 0o700); err != nil {
+		t.Fatalf("create project dir: %v", err)
+	}
+	if err := os.WriteFile(projectPath, []byte("schema_version: 1\nprofile: acme\ndefaults:\n  account_id: act_project\nflags:\n  limit: \"50\"\n"), 0o600); err != nil {
+		t.Fatalf("write project config: %v", err)
+	}
+	originalWorkingDir := workingDir
+	workingDir = func() (string, error) { return filepath.Join(repo, "campaigns"), nil }
+	t.Cleanup(func() { workingDir = originalWorkingDir })
+
+	captured := map[string]string{}
+	root := newEnvProbeRoot(t, captured)
+	stderr := &bytes.Buffer{}
+	root.SetErr(stderr)
+	root.SetArgs([]string{"--debug", "probe"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	want := map[string]string{"profile": "acme", "account-id": "act_project", "limit": "50", "output": "table"}
+	for key, value := range want {
+		if captured[key] != value {
+			t.Fatalf("expected %s=%q, got %q", key, value, captured[key])
+		}
+	}
+	for _, line := range []string{
+		`debug: --account-id="act_project" (from project ` + projectPath + `)`,
+		`debug: --output="table" (from profile acme)`,
+		`debug: --profile="acme" (from project ` + projectPath + `)`,
+	} {
+		if !strings.Contains(stderr.String(), line) {
+			t.Fatalf("expected %q in debug output, got %q", line, stderr.String())
+		}
+	}
+
+	captured = map[string]string{}
+	root = newEnvProbeRoot(t, captured)
+	root.SetArgs([]string{"--profile", "other", "probe", "--limit", "5"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if captured["profile"] != "other" || captured["limit"] != "5" || captured["account-id"] != "act_project" {
+		t.Fatalf("expected explicit flags to win over the project config, got %v", captured)
+	}
+
+	if err := os.WriteFile(projectPath, []byte("schema_version: 1\nprofile: acme\nunknown: true\n"), 0o600); err != nil {
+		t.Fatalf("rewrite project config: %v", err)
+	}
+	root = newEnvProbeRoot(t, map[string]string{})
+	root.SetArgs([]string{"probe", "--account-id", "act_1"})
+	err := root.Execute()
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeConfig || !strings.Contains(err.Error(), "decode project config") {
+		t.Fatalf("expected invalid project config to fail with a config error, got %v", err)
+	}
+
+	if err := os.WriteFile(projectPath, []byte("schema_version: 1\nflags:\n  confirm-delete: \"true\"\n"), 0o600); err != nil {
+		t.Fatalf("rewrite project config: %v", err)
+	}
+	root = newEnvProbeRoot(t, map[string]string{})
+	root.SetArgs([]string{"probe", "--account-id", "act_1"})
+	err = root.Execute()
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeConfig || !strings.Contains(err.Error(), "cannot set --confirm-delete") {
+		t.Fatalf("expected a project config setting confirm-delete to be refused, got %v", err)
+	}
+}
+
+func TestBindFlagDefaultsNeverFillsSafetyOverrides(t *testing.T) {
+	cmd := &cobra.Command{Use: "probe"}
+	cmd.Flags().Bool("confirm-delete", false, "")
+	cmd.Flags().String("public-key", "trusted", "")
+	cmd.Flags().String("account-id", "", "")
+
+	sources := map[string]string{}
+	values := map[string]string{"confirm-delete": "true", "public-key": "attacker", "account-id": "act_1"}
+	if err := bindFlagDefaults(cmd, values, "project test", sources); err != nil {
+		t.Fatalf("bind flag defaults: %v", err)
+	}
+	if cmd.Flags().Changed("confirm-delete") || cmd.Flags().Lookup("public-key").Value.String() != "trusted" {
+		t.Fatalf("expected safety overrides to keep their defaults, got sources %v", sources)
+	}
+	if sources["account-id"] != "project test" {
+		t.Fatalf("expected account-id to be filled, got sources %v", sources)
+	}
+}
+
+func TestWriteTransportStatsReportsReuse(t *testing.T) {
+	var buf bytes.Buffer
+	writeTransportStats(&buf, transport.Stats{})
+	if buf.Len() != 0 {
+		t.Fatalf("expected no line without requests, got %q", buf.String())
+	}
+
+	writeTransportStats(&buf, transport.Stats{Requests: 12, NewConnections: 1, ReusedConnections: 11, IdleReuses: 11, TLSHandshakes: 1, HTTP2Responses: 12})
+	want := "debug: transport requests=12 new_connections=1 reused_connections=11 idle_reuses=11 tls_handshakes=1 http2_responses=12 http1_responses=0 failed_round_trips=0\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected stats line:\n%s", buf.String())
+	}
+}
+
+func TestRootDryRunFromEnvironmentWrapsEnvelopeInPlan(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("META_DRY_RUN", "1")
+
+	output := &bytes.Buffer{}
+	root := NewRootCommand()
+	root.SetOut(output)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"template", "list"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("template list: %v", err)
+	}
+
+	var envelope struct {
+		Data struct {
+			DryRun    bool                         `json:"dry_run"`
+			Command   string                       `json:"command"`
+			Mutations []any                        `json:"mutations"`
+			Flags     map[string]map[string]string `json:"flags"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(output.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode envelope: %v\n%s", err, output.String())
+	}
+	if !envelope.Data.DryRun || envelope.Data.Command != "meta template list" || envelope.Data.Mutations == nil {
+		t.Fatalf("unexpected dry-run plan %s", output.String())
+	}
+	if source := envelope.Data.Flags["dry-run"]["source"]; source != "env META_DRY_RUN" {
+		t.Fatalf("expected dry-run provenance from the environment, got %q", source)
+	}
+}
+
+func TestRootOfflinePlansMutationsAndMarksTheEnvelope(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	output := &bytes.Buffer{}
+	root := NewRootCommand()
+	root.SetOut(output)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"template", "list", "--offline"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("template list: %v", err)
+	}
+
+	var envelope struct {
+		Data struct {
+			DryRun bool `json:"dry_run"`
+		} `json:"data"`
+		Offline *struct {
+			Reads         []any `json:"reads"`
+			MaxAgeSeconds int64 `json:"max_age_seconds"`
+		} `json:"offline"`
+	}
+	if err := json.Unmarshal(output.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode envelope: %v\n%s", err, output.String())
+	}
+	if !envelope.Data.DryRun || envelope.Offline == nil || envelope.Offline.Reads == nil || len(envelope.Offline.Reads) != 0 {
+		t.Fatalf("unexpected offline envelope %s", output.String())
+	}
+}
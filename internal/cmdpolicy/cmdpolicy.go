@@ -0,0 +1,188 @@
+// Package cmdpolicy enforces administrator allow and deny lists of CLI
+// commands, globally or for selected profiles. Entries name a command family
+// ("business") or a command ("capi send"); an entry covers the command and
+// everything under it.
+package cmdpolicy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+const SchemaVersion = 1
+
+// SystemPath is the installation-wide policy, enforced in addition to the
+// user's.
+const SystemPath = "/etc/meta/command-policy.yaml"
+
+// exempt commands stay available under any policy so users can still read
+// help and inspect the CLI.
+var exempt = map[string]struct{}{
+	"":                 {},
+	"help":             {},
+	"completion":       {},
+	"__complete":       {},
+	"__completeNoDesc": {},
+}
+
+// Policy is a command policy file.
+type Policy struct {
+	SchemaVersion int    `yaml:"schema_version"`
+	Rules         []Rule `yaml:"rules"`
+
+	// Source is the file the policy was loaded from.
+	Source string `yaml:"-"`
+}
+
+// Rule applies to every profile unless it lists profiles or a label selector;
+// with both, a profile matching either is covered. When a rule has an allow
+// list, only commands on it may run; deny entries always block.
+type Rule struct {
+	Profiles []string `yaml:"profiles,omitempty"`
+	Labels   string   `yaml:"labels,omitempty"`
+	Allow    []string `yaml:"allow,omitempty"`
+	Deny     []string `yaml:"deny,omitempty"`
+
+	selector *config.Selector
+}
+
+// Subject is the command being dispatched and the profile it runs as.
+type Subject struct {
+	// Command is the command path without the binary name, e.g. "capi send".
+	Command string
+	Profile string
+	Labels  map[string]string
+}
+
+// Decision explains why a command was blocked.
+type Decision struct {
+	Command string `json:"command"`
+	Profile string `json:"profile,omitempty"`
+	Rule    int    `json:"rule"`
+	Reason  string `json:"reason"`
+	Entry   string `json:"entry,omitempty"`
+	Source  string `json:"source"`
+}
+
+func DefaultUserPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "command-policy.yaml"), nil
+}
+
+// Load returns nil without error when path does not exist.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read command policy %s: %w", path, err)
+	}
+	policy := &Policy{Source: path}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(policy); err != nil {
+		return nil, fmt.Errorf("decode command policy %s: %w", path, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid command policy %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+func (p *Policy) Validate() error {
+	if p.SchemaVersion != SchemaVersion {
+		return fmt.Errorf("unsupported schema_version=%d (expected %d)", p.SchemaVersion, SchemaVersion)
+	}
+	for index := range p.Rules {
+		rule := &p.Rules[index]
+		if len(rule.Allow) == 0 && len(rule.Deny) == 0 {
+			return fmt.Errorf("rules[%d]: set allow or deny", index)
+		}
+		for _, list := range [][]string{rule.Allow, rule.Deny} {
+			for entryIndex, entry := range list {
+				normalized := normalizeCommand(entry)
+				if normalized == "" {
+					return fmt.Errorf("rules[%d]: empty command entry", index)
+				}
+				list[entryIndex] = normalized
+			}
+		}
+		if labels := strings.TrimSpace(rule.Labels); labels != "" {
+			selector, err := config.ParseSelector(labels)
+			if err != nil {
+				return fmt.Errorf("rules[%d]: %w", index, err)
+			}
+			rule.selector = &selector
+		}
+	}
+	return nil
+}
+
+// Check returns the decision that blocks subject, or nil when it may run.
+func (p *Policy) Check(subject Subject) *Decision {
+	if p == nil {
+		return nil
+	}
+	command := normalizeCommand(subject.Command)
+	if _, ok := exempt[command]; ok {
+		return nil
+	}
+	if _, ok := exempt[strings.Fields(command)[0]]; ok {
+		return nil
+	}
+	for index := range p.Rules {
+		rule := &p.Rules[index]
+		if !rule.applies(subject) {
+			continue
+		}
+		decision := &Decision{Command: command, Profile: subject.Profile, Rule: index, Source: p.Source}
+		if entry, ok := matchEntry(rule.Deny, command); ok {
+			decision.Reason = "denied"
+			decision.Entry = entry
+			return decision
+		}
+		if len(rule.Allow) > 0 {
+			if _, ok := matchEntry(rule.Allow, command); !ok {
+				decision.Reason = "not_allowed"
+				return decision
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Rule) applies(subject Subject) bool {
+	if len(r.Profiles) == 0 && r.selector == nil {
+		return true
+	}
+	for _, profile := range r.Profiles {
+		if strings.TrimSpace(profile) == subject.Profile {
+			return true
+		}
+	}
+	return r.selector != nil && r.selector.Matches(subject.Labels)
+}
+
+func matchEntry(entries []string, command string) (string, bool) {
+	for _, entry := range entries {
+		if command == entry || strings.HasPrefix(command, entry+" ") {
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+func normalizeCommand(command string) string {
+	return strings.Join(strings.Fields(command), " ")
+}
@@ -0,0 +1,69 @@
+package cmdpolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testPolicy = `schema_version: 1
+rules:
+  - deny: [business, "capi send"]
+  - profiles: [client-acme]
+    deny: ["campaign create"]
+  - labels: env=readonly
+    allow: [insights, "campaign list", "api get"]
+`
+
+func TestCheckAppliesGlobalProfileAndLabelRules(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "command-policy.yaml")
+	if err := os.WriteFile(path, []byte(testPolicy), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	policy, err := Load(path)
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+
+	readonly := map[string]string{"env": "readonly"}
+	cases := []struct {
+		subject Subject
+		reason  string
+	}{
+		{Subject{Command: "business audit", Profile: "prod"}, "denied"},
+		{Subject{Command: "capi send", Profile: "prod"}, "denied"},
+		{Subject{Command: "capi test-events", Profile: "prod"}, ""},
+		{Subject{Command: "campaign create", Profile: "client-acme"}, "denied"},
+		{Subject{Command: "campaign create", Profile: "prod"}, ""},
+		{Subject{Command: "insights get", Profile: "viewer", Labels: readonly}, ""},
+		{Subject{Command: "campaign update", Profile: "viewer", Labels: readonly}, "not_allowed"},
+		{Subject{Command: "help", Profile: "viewer", Labels: readonly}, ""},
+		{Subject{Command: "completion zsh", Profile: "viewer", Labels: readonly}, ""},
+	}
+	for _, tc := range cases {
+		decision := policy.Check(tc.subject)
+		switch {
+		case tc.reason == "" && decision != nil:
+			t.Fatalf("expected %#v to run, got %#v", tc.subject, decision)
+		case tc.reason != "" && (decision == nil || decision.Reason != tc.reason):
+			t.Fatalf("expected %#v to be %s, got %#v", tc.subject, tc.reason, decision)
+		}
+	}
+}
+
+func TestLoadRejectsEmptyRules(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "command-policy.yaml")
+	if policy, err := Load(path); err != nil || policy != nil {
+		t.Fatalf("expected missing policy to be nil, got %#v %v", policy, err)
+	}
+	if err := os.WriteFile(path, []byte("schema_version: 1\nrules:\n  - profiles: [prod]\n"), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected a rule without allow or deny to be rejected")
+	}
+}
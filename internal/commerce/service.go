@@ -0,0 +1,466 @@
+package commerce
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	OperationAcknowledge = "acknowledge"
+	OperationShip        = "ship"
+	OperationCancel      = "cancel"
+	OperationRefund      = "refund"
+
+	maxIdempotencyKeyLength = 255
+)
+
+var (
+	DefaultOrderFields = []string{
+		"id", "order_status", "created", "last_updated", "merchant_order_id", "channel",
+		"buyer_details", "ship_by_date", "selected_shipping_option", "estimated_payment_details",
+	}
+
+	orderStates = map[string]struct{}{
+		"FB_PROCESSING": {}, "CREATED": {}, "IN_PROGRESS": {}, "COMPLETED": {},
+	}
+	cancelReasonCodes = map[string]struct{}{
+		"CUSTOMER_REQUESTED": {}, "OUT_OF_STOCK": {}, "INVALID_ADDRESS": {}, "SUSPICIOUS_ORDER": {}, "CANCEL_REASON_OTHER": {},
+	}
+	refundReasonCodes = map[string]struct{}{
+		"BUYERS_REMORSE": {}, "DAMAGED_GOODS": {}, "NOT_AS_DESCRIBED": {}, "QUALITY_ISSUE": {},
+		"WRONG_ITEM": {}, "REFUND_REASON_OTHER": {}, "REFUND_SFI_FAKE": {}, "REFUND_SFI_REAL": {},
+	}
+
+	amountPattern   = regexp.MustCompile(`^\d+(\.\d{1,2})?$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+type ListOrdersOptions struct {
+	CMSID        string
+	States       []string
+	UpdatedAfter int64
+	Fields       []string
+	Limit        int
+	FollowNext   bool
+}
+
+type ListOrdersResult struct {
+	CMSID       string                  `json:"cms_id"`
+	States      []string                `json:"states"`
+	RequestPath string                  `json:"request_path"`
+	Orders      []map[string]any        `json:"orders"`
+	Paging      *graph.PaginationResult `json:"paging,omitempty"`
+}
+
+// LineItem addresses order items by retailer id (the catalog SKU).
+type LineItem struct {
+	RetailerID string `json:"retailer_id"`
+	Quantity   int    `json:"quantity"`
+}
+
+type AcknowledgeOptions struct {
+	OrderID                string
+	MerchantOrderReference string
+	IdempotencyKey         string
+}
+
+type ShipOptions struct {
+	OrderID        string
+	Items          []LineItem
+	TrackingNumber string
+	Carrier        string
+	ShippingMethod string
+	IdempotencyKey string
+}
+
+type CancelOptions struct {
+	OrderID           string
+	ReasonCode        string
+	ReasonDescription string
+	RestockItems      bool
+	IdempotencyKey    string
+}
+
+type RefundOptions struct {
+	OrderID        string
+	ReasonCode     string
+	ReasonText     string
+	Items          []LineItem
+	ShippingRefund string
+	Currency       string
+	IdempotencyKey string
+}
+
+// Mutation is a validated order update ready to post. Summary describes its effect
+// for confirmation prompts.
+type Mutation struct {
+	Operation      string            `json:"operation"`
+	OrderID        string            `json:"order_id"`
+	RequestPath    string            `json:"request_path"`
+	IdempotencyKey string            `json:"idempotency_key"`
+	Summary        string            `json:"summary"`
+	Form           map[string]string `json:"-"`
+}
+
+type MutationResult struct {
+	Mutation
+	Response map[string]any `json:"response"`
+}
+
+type Service struct {
+	Client *graph.Client
+}
+
+func New(client *graph.Client) *Service {
+	if client == nil {
+		client = graph.NewClient(nil, "")
+	}
+	return &Service{Client: client}
+}
+
+func (s *Service) ListOrders(ctx context.Context, version string, token string, appSecret string, options ListOrdersOptions) (*ListOrdersResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("commerce service client is required")
+	}
+	cmsID, err := normalizeGraphID("commerce account id", options.CMSID)
+	if err != nil {
+		return nil, err
+	}
+	states, err := normalizeOrderStates(options.States)
+	if err != nil {
+		return nil, err
+	}
+	fields := options.Fields
+	if len(fields) == 0 {
+		fields = DefaultOrderFields
+	}
+	query := map[string]string{
+		"fields": strings.Join(fields, ","),
+		"state":  strings.Join(states, ","),
+	}
+	if options.UpdatedAfter < 0 {
+		return nil, fmt.Errorf("updated after must be a unix timestamp >= 0, got %d", options.UpdatedAfter)
+	}
+	if options.UpdatedAfter > 0 {
+		query["updated_after"] = strconv.FormatInt(options.UpdatedAfter, 10)
+	}
+
+	path := cmsID + "/commerce_orders"
+	orders := make([]map[string]any, 0)
+	pagination, err := s.Client.FetchWithPagination(ctx, graph.Request{
+		Method:      "GET",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Query:       query,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, graph.PaginationOptions{
+		FollowNext: options.FollowNext,
+		Limit:      options.Limit,
+	}, func(item map[string]any) error {
+		orders = append(orders, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ListOrdersResult{
+		CMSID:       cmsID,
+		States:      states,
+		RequestPath: path,
+		Orders:      orders,
+		Paging:      pagination,
+	}, nil
+}
+
+// PlanAcknowledge moves an order from CREATED to IN_PROGRESS on the merchant side.
+func PlanAcknowledge(options AcknowledgeOptions) (*Mutation, error) {
+	form := map[string]string{}
+	if reference := strings.TrimSpace(options.MerchantOrderReference); reference != "" {
+		form["merchant_order_reference"] = reference
+	}
+	return newMutation(OperationAcknowledge, options.OrderID, "acknowledge_order", options.IdempotencyKey, form, func(orderID string) string {
+		return fmt.Sprintf("acknowledge order %s", orderID)
+	})
+}
+
+func PlanShip(options ShipOptions) (*Mutation, error) {
+	items, err := normalizeLineItems(options.Items)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("at least one shipped item is required")
+	}
+	trackingNumber := strings.TrimSpace(options.TrackingNumber)
+	if trackingNumber == "" {
+		return nil, errors.New("tracking number is required")
+	}
+	carrier := strings.ToUpper(strings.TrimSpace(options.Carrier))
+	if carrier == "" {
+		return nil, errors.New("carrier is required")
+	}
+	trackingInfo := map[string]string{
+		"tracking_number": trackingNumber,
+		"carrier":         carrier,
+	}
+	if method := strings.TrimSpace(options.ShippingMethod); method != "" {
+		trackingInfo["shipping_method_name"] = method
+	}
+
+	form := map[string]string{}
+	if err := setJSONFormValue(form, "items", items); err != nil {
+		return nil, err
+	}
+	if err := setJSONFormValue(form, "tracking_info", trackingInfo); err != nil {
+		return nil, err
+	}
+	return newMutation(OperationShip, options.OrderID, "shipments", options.IdempotencyKey, form, func(orderID string) string {
+		return fmt.Sprintf("ship %s on order %s via %s %s", describeLineItems(items), orderID, carrier, trackingNumber)
+	})
+}
+
+func PlanCancel(options CancelOptions) (*Mutation, error) {
+	reasonCode := strings.ToUpper(strings.TrimSpace(options.ReasonCode))
+	if reasonCode == "" {
+		reasonCode = "CANCEL_REASON_OTHER"
+	}
+	if _, ok := cancelReasonCodes[reasonCode]; !ok {
+		return nil, fmt.Errorf("unsupported cancel reason %q: expected %s", options.ReasonCode, joinKeys(cancelReasonCodes))
+	}
+	cancelReason := map[string]string{"reason_code": reasonCode}
+	if description := strings.TrimSpace(options.ReasonDescription); description != "" {
+		cancelReason["reason_description"] = description
+	}
+
+	form := map[string]string{
+		"restock_items": strconv.FormatBool(options.RestockItems),
+	}
+	if err := setJSONFormValue(form, "cancel_reason", cancelReason); err != nil {
+		return nil, err
+	}
+	return newMutation(OperationCancel, options.OrderID, "cancellations", options.IdempotencyKey, form, func(orderID string) string {
+		return fmt.Sprintf("cancel order %s (%s)", orderID, reasonCode)
+	})
+}
+
+// PlanRefund refunds the whole order when no items are given, otherwise only the
+// listed quantities. A shipping refund is added on top when set.
+func PlanRefund(options RefundOptions) (*Mutation, error) {
+	reasonCode := strings.ToUpper(strings.TrimSpace(options.ReasonCode))
+	if _, ok := refundReasonCodes[reasonCode]; !ok {
+		return nil, fmt.Errorf("unsupported refund reason %q: expected %s", options.ReasonCode, joinKeys(refundReasonCodes))
+	}
+	items, err := normalizeLineItems(options.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	form := map[string]string{"reason_code": reasonCode}
+	if text := strings.TrimSpace(options.ReasonText); text != "" {
+		form["reason_text"] = text
+	}
+	if len(items) > 0 {
+		if err := setJSONFormValue(form, "items", items); err != nil {
+			return nil, err
+		}
+	}
+	shippingRefund := strings.TrimSpace(options.ShippingRefund)
+	currency := strings.ToUpper(strings.TrimSpace(options.Currency))
+	if shippingRefund != "" {
+		if !amountPattern.MatchString(shippingRefund) {
+			return nil, fmt.Errorf("invalid shipping refund %q: expected an amount such as 4.99", options.ShippingRefund)
+		}
+		if !currencyPattern.MatchString(currency) {
+			return nil, fmt.Errorf("invalid currency %q: expected an ISO 4217 code such as USD", options.Currency)
+		}
+		if err := setJSONFormValue(form, "shipping", map[string]any{
+			"shipping_refund": map[string]string{"amount": shippingRefund, "currency": currency},
+		}); err != nil {
+			return nil, err
+		}
+	} else if currency != "" {
+		return nil, errors.New("currency is only used with a shipping refund")
+	}
+
+	return newMutation(OperationRefund, options.OrderID, "refunds", options.IdempotencyKey, form, func(orderID string) string {
+		scope := "the full order"
+		if len(items) > 0 {
+			scope = describeLineItems(items)
+		}
+		summary := fmt.Sprintf("refund %s on order %s (%s)", scope, orderID, reasonCode)
+		if shippingRefund != "" {
+			summary += fmt.Sprintf(" plus %s %s shipping", shippingRefund, currency)
+		}
+		return summary
+	})
+}
+
+func (s *Service) Apply(ctx context.Context, version string, token string, appSecret string, mutation *Mutation) (*MutationResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("commerce service client is required")
+	}
+	if mutation == nil {
+		return nil, errors.New("commerce mutation is required")
+	}
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:      "POST",
+		Path:        mutation.RequestPath,
+		Version:     strings.TrimSpace(version),
+		Form:        mutation.Form,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if success, ok := response.Body["success"].(bool); ok && !success {
+		return nil, fmt.Errorf("commerce %s response was not successful for order %s", mutation.Operation, mutation.OrderID)
+	}
+	return &MutationResult{Mutation: *mutation, Response: response.Body}, nil
+}
+
+// newMutation attaches the idempotency key. Without an explicit key one is derived
+// from the operation, order, and payload so retrying the same command is safe.
+func newMutation(operation string, rawOrderID string, edge string, rawKey string, form map[string]string, summarize func(orderID string) string) (*Mutation, error) {
+	orderID, err := normalizeGraphID("order id", rawOrderID)
+	if err != nil {
+		return nil, err
+	}
+	key := strings.TrimSpace(rawKey)
+	if key == "" {
+		key = deriveIdempotencyKey(operation, orderID, form)
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, fmt.Errorf("idempotency key exceeds %d characters", maxIdempotencyKeyLength)
+	}
+	form["idempotency_key"] = key
+	return &Mutation{
+		Operation:      operation,
+		OrderID:        orderID,
+		RequestPath:    orderID + "/" + edge,
+		IdempotencyKey: key,
+		Summary:        summarize(orderID),
+		Form:           form,
+	}, nil
+}
+
+func deriveIdempotencyKey(operation string, orderID string, form map[string]string) string {
+	keys := make([]string, 0, len(form))
+	for key := range form {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%s=%s\n", key, form[key])
+	}
+	return fmt.Sprintf("metacli-%s-%s-%s", operation, orderID, hex.EncodeToString(hash.Sum(nil))[:16])
+}
+
+func normalizeOrderStates(values []string) ([]string, error) {
+	states := make([]string, 0, len(values))
+	seen := map[string]struct{}{}
+	for _, value := range values {
+		state := strings.ToUpper(strings.TrimSpace(value))
+		if state == "" {
+			continue
+		}
+		if _, ok := orderStates[state]; !ok {
+			return nil, fmt.Errorf("unsupported order state %q: expected %s", value, joinKeys(orderStates))
+		}
+		if _, ok := seen[state]; ok {
+			continue
+		}
+		seen[state] = struct{}{}
+		states = append(states, state)
+	}
+	if len(states) == 0 {
+		states = []string{"CREATED"}
+	}
+	return states, nil
+}
+
+func normalizeLineItems(items []LineItem) ([]LineItem, error) {
+	normalized := make([]LineItem, 0, len(items))
+	for _, item := range items {
+		retailerID := strings.TrimSpace(item.RetailerID)
+		if retailerID == "" {
+			return nil, errors.New("item retailer id is required")
+		}
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("item %s quantity must be > 0, got %d", retailerID, item.Quantity)
+		}
+		normalized = append(normalized, LineItem{RetailerID: retailerID, Quantity: item.Quantity})
+	}
+	return normalized, nil
+}
+
+// ParseLineItems parses "SKU:QTY" entries; a bare SKU means quantity 1.
+func ParseLineItems(values []string) ([]LineItem, error) {
+	items := make([]LineItem, 0, len(values))
+	for _, value := range values {
+		entry := strings.TrimSpace(value)
+		if entry == "" {
+			continue
+		}
+		retailerID, rawQuantity, hasQuantity := strings.Cut(entry, ":")
+		quantity := 1
+		if hasQuantity {
+			parsed, err := strconv.Atoi(strings.TrimSpace(rawQuantity))
+			if err != nil {
+				return nil, fmt.Errorf("invalid item %q: expected RETAILER_ID:QUANTITY", value)
+			}
+			quantity = parsed
+		}
+		items = append(items, LineItem{RetailerID: strings.TrimSpace(retailerID), Quantity: quantity})
+	}
+	return normalizeLineItems(items)
+}
+
+func describeLineItems(items []LineItem) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, fmt.Sprintf("%dx %s", item.Quantity, item.RetailerID))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func setJSONFormValue(form map[string]string, key string, value any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", key, err)
+	}
+	form[key] = string(encoded)
+	return nil
+}
+
+func joinKeys(values map[string]struct{}) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "|")
+}
+
+func normalizeGraphID(label string, value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "", fmt.Errorf("%s is required", label)
+	}
+	if strings.Contains(trimmed, "/") {
+		return "", fmt.Errorf("invalid %s %q: expected single graph id token", label, value)
+	}
+	return trimmed, nil
+}
@@ -0,0 +1,226 @@
+package commerce
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestListOrdersFiltersByState(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v25.0/cms_1/commerce_orders" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("state") != "CREATED,IN_PROGRESS" || query.Get("updated_after") != "1700000000" {
+			t.Fatalf("unexpected query %v", query)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{{"id": "order_1", "order_status": map[string]any{"state": "CREATED"}}},
+		})
+	}))
+	defer server.Close()
+
+	result, err := New(graph.NewClient(server.Client(), server.URL)).ListOrders(context.Background(), "v25.0", "token", "", ListOrdersOptions{
+		CMSID:        "cms_1",
+		States:       []string{"created", "in_progress", "CREATED"},
+		UpdatedAfter: 1700000000,
+		FollowNext:   true,
+	})
+	if err != nil {
+		t.Fatalf("list orders: %v", err)
+	}
+	if len(result.Orders) != 1 || len(result.States) != 2 {
+		t.Fatalf("unexpected result %#v", result)
+	}
+}
+
+func TestListOrdersRejectsUnknownState(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(graph.NewClient(nil, "")).ListOrders(context.Background(), "v25.0", "token", "", ListOrdersOptions{
+		CMSID:  "cms_1",
+		States: []string{"SHIPPED"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "unsupported order state") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestPlanShipEncodesItemsAndTracking(t *testing.T) {
+	t.Parallel()
+
+	mutation, err := PlanShip(ShipOptions{
+		OrderID:        "order_1",
+		Items:          []LineItem{{RetailerID: "sku-1", Quantity: 2}},
+		TrackingNumber: "1Z999",
+		Carrier:        "ups",
+	})
+	if err != nil {
+		t.Fatalf("plan ship: %v", err)
+	}
+	if mutation.RequestPath != "order_1/shipments" {
+		t.Fatalf("unexpected path %q", mutation.RequestPath)
+	}
+	if mutation.Form["items"] != `[{"retailer_id":"sku-1","quantity":2}]` {
+		t.Fatalf("unexpected items %q", mutation.Form["items"])
+	}
+	if mutation.Form["tracking_info"] != `{"carrier":"UPS","tracking_number":"1Z999"}` {
+		t.Fatalf("unexpected tracking info %q", mutation.Form["tracking_info"])
+	}
+	if !strings.HasPrefix(mutation.IdempotencyKey, "metacli-ship-order_1-") || mutation.Form["idempotency_key"] != mutation.IdempotencyKey {
+		t.Fatalf("unexpected idempotency key %q", mutation.IdempotencyKey)
+	}
+}
+
+func TestPlanIdempotencyKeyIsStable(t *testing.T) {
+	t.Parallel()
+
+	options := RefundOptions{OrderID: "order_1", ReasonCode: "damaged_goods", Items: []LineItem{{RetailerID: "sku-1", Quantity: 1}}}
+	first, err := PlanRefund(options)
+	if err != nil {
+		t.Fatalf("plan refund: %v", err)
+	}
+	second, err := PlanRefund(options)
+	if err != nil {
+		t.Fatalf("plan refund: %v", err)
+	}
+	if first.IdempotencyKey != second.IdempotencyKey {
+		t.Fatalf("expected stable key, got %q and %q", first.IdempotencyKey, second.IdempotencyKey)
+	}
+
+	options.Items[0].Quantity = 2
+	third, err := PlanRefund(options)
+	if err != nil {
+		t.Fatalf("plan refund: %v", err)
+	}
+	if third.IdempotencyKey == first.IdempotencyKey {
+		t.Fatal("expected a different key for a different payload")
+	}
+
+	options.IdempotencyKey = "custom-key"
+	explicit, err := PlanRefund(options)
+	if err != nil {
+		t.Fatalf("plan refund: %v", err)
+	}
+	if explicit.IdempotencyKey != "custom-key" {
+		t.Fatalf("unexpected explicit key %q", explicit.IdempotencyKey)
+	}
+}
+
+func TestPlanRefundValidatesInput(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		options RefundOptions
+		want    string
+	}{
+		{"reason", RefundOptions{OrderID: "order_1", ReasonCode: "because"}, "unsupported refund reason"},
+		{"order", RefundOptions{OrderID: "a/b", ReasonCode: "WRONG_ITEM"}, "invalid order id"},
+		{"amount", RefundOptions{OrderID: "order_1", ReasonCode: "WRONG_ITEM", ShippingRefund: "4.999", Currency: "USD"}, "invalid shipping refund"},
+		{"currency", RefundOptions{OrderID: "order_1", ReasonCode: "WRONG_ITEM", ShippingRefund: "4.99"}, "invalid currency"},
+		{"orphan currency", RefundOptions{OrderID: "order_1", ReasonCode: "WRONG_ITEM", Currency: "USD"}, "only used with a shipping refund"},
+	}
+	for _, tc := range cases {
+		if _, err := PlanRefund(tc.options); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+	}
+}
+
+func TestPlanRefundFullOrderWithShipping(t *testing.T) {
+	t.Parallel()
+
+	mutation, err := PlanRefund(RefundOptions{
+		OrderID:        "order_1",
+		ReasonCode:     "NOT_AS_DESCRIBED",
+		ShippingRefund: "4.99",
+		Currency:       "usd",
+	})
+	if err != nil {
+		t.Fatalf("plan refund: %v", err)
+	}
+	if _, ok := mutation.Form["items"]; ok {
+		t.Fatalf("full refund should not send items: %v", mutation.Form)
+	}
+	if mutation.Form["shipping"] != `{"shipping_refund":{"amount":"4.99","currency":"USD"}}` {
+		t.Fatalf("unexpected shipping %q", mutation.Form["shipping"])
+	}
+	if mutation.Summary != "refund the full order on order order_1 (NOT_AS_DESCRIBED) plus 4.99 USD shipping" {
+		t.Fatalf("unexpected summary %q", mutation.Summary)
+	}
+}
+
+func TestParseLineItems(t *testing.T) {
+	t.Parallel()
+
+	items, err := ParseLineItems([]string{"sku-1:3", " sku-2 ", ""})
+	if err != nil {
+		t.Fatalf("parse line items: %v", err)
+	}
+	if len(items) != 2 || items[0] != (LineItem{RetailerID: "sku-1", Quantity: 3}) || items[1] != (LineItem{RetailerID: "sku-2", Quantity: 1}) {
+		t.Fatalf("unexpected items %#v", items)
+	}
+	for _, raw := range []string{"sku-1:x", "sku-1:0", ":2"} {
+		if _, err := ParseLineItems([]string{raw}); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestApplyPostsCancellation(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v25.0/order_1/cancellations" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		if form.Get("cancel_reason") != `{"reason_code":"OUT_OF_STOCK"}` || form.Get("restock_items") != "true" || form.Get("idempotency_key") == "" {
+			t.Fatalf("unexpected form %v", form)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"success": true})
+	}))
+	defer server.Close()
+
+	mutation, err := PlanCancel(CancelOptions{OrderID: "order_1", ReasonCode: "out_of_stock", RestockItems: true})
+	if err != nil {
+		t.Fatalf("plan cancel: %v", err)
+	}
+	result, err := New(graph.NewClient(server.Client(), server.URL)).Apply(context.Background(), "v25.0", "token", "", mutation)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if result.Operation != OperationCancel || result.Response["success"] != true {
+		t.Fatalf("unexpected result %#v", result)
+	}
+}
+
+func TestApplyFailsOnUnsuccessfulResponse(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"success": false})
+	}))
+	defer server.Close()
+
+	mutation, err := PlanAcknowledge(AcknowledgeOptions{OrderID: "order_1"})
+	if err != nil {
+		t.Fatalf("plan acknowledge: %v", err)
+	}
+	_, err = New(graph.NewClient(server.Client(), server.URL)).Apply(context.Background(), "v25.0", "token", "", mutation)
+	if err == nil || !strings.Contains(err.Error(), "not successful") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/bilalbayram/metacli/internal/filelock"
+	"github.com/bilalbayram/metacli/internal/i18n"
 	"gopkg.in/yaml.v3"
 )
 
@@ -35,6 +37,40 @@ type Profile struct {
 	ExpiresAt       string   `yaml:"expires_at"`
 	LastValidatedAt string   `yaml:"last_validated_at"`
 	IGUserID        string   `yaml:"ig_user_id,omitempty"`
+	WABAID          string   `yaml:"waba_id,omitempty"`
+	// ScopesVerifiedAt is when Scopes were last read from the token with
+	// debug_token. Empty means they were assumed when the profile was added.
+	ScopesVerifiedAt string `yaml:"scopes_verified_at,omitempty"`
+	// Labels group profiles for --profiles-selector, e.g. env: prod.
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// Defaults fill flags left unset on the command line when this profile is
+	// selected.
+	Defaults ProfileDefaults `yaml:"defaults,omitempty"`
+}
+
+type ProfileDefaults struct {
+	AccountID string `yaml:"account_id,omitempty"`
+	SchemaDir string `yaml:"schema_dir,omitempty"`
+	RulesDir  string `yaml:"rules_dir,omitempty"`
+	Output    string `yaml:"output,omitempty"`
+	Lang      string `yaml:"lang,omitempty"`
+}
+
+// Flags maps the configured defaults onto the flag names they fill.
+func (d ProfileDefaults) Flags() map[string]string {
+	flags := map[string]string{}
+	for name, value := range map[string]string{
+		"account-id": d.AccountID,
+		"schema-dir": d.SchemaDir,
+		"rules-dir":  d.RulesDir,
+		"output":     d.Output,
+		"lang":       d.Lang,
+	} {
+		if value = strings.TrimSpace(value); value != "" {
+			flags[name] = value
+		}
+	}
+	return flags
 }
 
 type Config struct {
@@ -59,11 +95,14 @@ func New() *Config {
 }
 
 func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	data, encrypted, err := readConfigData(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return nil, fmt.Errorf("%w: config file does not exist at %s", os.ErrNotExist, path)
 		}
+		if encrypted {
+			return nil, err
+		}
 		return nil, fmt.Errorf("read config file %s: %w", path, err)
 	}
 
@@ -71,15 +110,23 @@ func Load(path string) (*Config, error) {
 	decoder := yaml.NewDecoder(bytes.NewReader(data))
 	decoder.KnownFields(true)
 	if err := decoder.Decode(cfg); err != nil {
-		return nil, fmt.Errorf("decode config file %s: %w", path, err)
+		return nil, fmt.Errorf("decode config file %s: %w (run `meta config doctor` for fixes)", path, err)
 	}
 	if err := cfg.Validate(); err != nil {
-		return nil, err
+		if cfg.SchemaVersion > 0 && cfg.SchemaVersion < SchemaVersion {
+			return nil, fmt.Errorf("%w; run `meta config doctor --fix` to migrate %s", err, path)
+		}
+		return nil, fmt.Errorf("%w (run `meta config doctor` for fixes)", err)
 	}
 	return cfg, nil
 }
 
 func LoadOrCreate(path string) (*Config, error) {
+	return LoadOrCreateHeld(nil, path)
+}
+
+// LoadOrCreateHeld is LoadOrCreate for a caller that holds the lock of path.
+func LoadOrCreateHeld(held *filelock.Lock, path string) (*Config, error) {
 	cfg, err := Load(path)
 	if err == nil {
 		return cfg, nil
@@ -89,28 +136,57 @@ func LoadOrCreate(path string) (*Config, error) {
 	}
 
 	cfg = New()
-	if err := Save(path, cfg); err != nil {
+	if err := SaveHeld(held, path, cfg); err != nil {
 		return nil, err
 	}
 	return cfg, nil
 }
 
+// Save writes cfg to path atomically. An encrypted config stays encrypted,
+// using the secret from the environment.
 func Save(path string, cfg *Config) error {
+	return SaveHeld(nil, path, cfg)
+}
+
+// SaveHeld is Save for a caller that holds the lock of path, taken with
+// filelock.Acquire before reading the config it modifies.
+func SaveHeld(held *filelock.Lock, path string, cfg *Config) error {
 	if cfg == nil {
 		return errors.New("config is nil")
 	}
 	if err := cfg.Validate(); err != nil {
 		return err
 	}
-
-	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
-		return fmt.Errorf("create config directory for %s: %w", path, err)
+	lock, err := held.Acquire(path)
+	if err != nil {
+		return err
 	}
+	defer lock.Release()
 
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
 		return fmt.Errorf("marshal config: %w", err)
 	}
+	encrypted, err := IsEncrypted(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read config file %s: %w", path, err)
+	}
+	if encrypted {
+		secret, err := EncryptionSecretFromEnv()
+		if err != nil {
+			return fmt.Errorf("save config file %s: %w", path, err)
+		}
+		if data, err = encryptConfig(data, secret); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(path, data)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create config directory for %s: %w", path, err)
+	}
 
 	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".config-*.yaml")
 	if err != nil {
@@ -183,6 +259,16 @@ func (c *Config) UpsertProfile(name string, profile Profile) error {
 		c.Profiles = map[string]Profile{}
 	}
 	profile = applyProfileDefaults(profile)
+	// Re-authenticating rebuilds the profile; keep the flag defaults and labels
+	// the user configured for it.
+	if existing, ok := c.Profiles[name]; ok {
+		if profile.Defaults == (ProfileDefaults{}) {
+			profile.Defaults = existing.Defaults
+		}
+		if profile.Labels == nil {
+			profile.Labels = existing.Labels
+		}
+	}
 	if err := validateProfile(name, profile); err != nil {
 		return err
 	}
@@ -269,5 +355,18 @@ func validateProfile(name string, profile Profile) error {
 	if _, err := time.Parse(time.RFC3339, profile.LastValidatedAt); err != nil {
 		return fmt.Errorf("profile %q last_validated_at must be RFC3339: %w", name, err)
 	}
-	return nil
+	if profile.ScopesVerifiedAt != "" {
+		if _, err := time.Parse(time.RFC3339, profile.ScopesVerifiedAt); err != nil {
+			return fmt.Errorf("profile %q scopes_verified_at must be RFC3339: %w", name, err)
+		}
+	}
+	switch profile.Defaults.Output {
+	case "", "json", "jsonl", "table", "csv", "ids":
+	default:
+		return fmt.Errorf("profile %q defaults.output must be one of [json jsonl table csv ids]", name)
+	}
+	if _, err := i18n.Parse(profile.Defaults.Lang); err != nil {
+		return fmt.Errorf("profile %q defaults.lang: %w", name, err)
+	}
+	return validateLabels(name, profile.Labels)
 }
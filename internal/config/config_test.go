@@ -1,10 +1,14 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
 )
 
 func validProfile() Profile {
@@ -302,3 +306,51 @@ func TestUpsertProfileAllowsSupportedTokenTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestUpsertProfileKeepsFlagDefaults(t *testing.T) {
+	t.Parallel()
+
+	cfg := New()
+	profile := validProfile()
+	profile.Defaults = ProfileDefaults{AccountID: "act_1", RulesDir: "rules"}
+	if err := cfg.UpsertProfile("prod", profile); err != nil {
+		t.Fatalf("upsert profile: %v", err)
+	}
+	if err := cfg.UpsertProfile("prod", validProfile()); err != nil {
+		t.Fatalf("re-upsert profile: %v", err)
+	}
+	if got := cfg.Profiles["prod"].Defaults; got != (ProfileDefaults{AccountID: "act_1", RulesDir: "rules"}) {
+		t.Fatalf("expected defaults to survive re-authentication, got %+v", got)
+	}
+	if got := cfg.Profiles["prod"].Defaults.Flags(); got["account-id"] != "act_1" || got["rules-dir"] != "rules" || len(got) != 2 {
+		t.Fatalf("unexpected flag defaults: %v", got)
+	}
+
+	profile.Defaults = ProfileDefaults{Output: "yaml"}
+	if err := cfg.UpsertProfile("prod", profile); err == nil || !strings.Contains(err.Error(), "defaults.output") {
+		t.Fatalf("expected invalid default output to fail, got %v", err)
+	}
+}
+
+func TestSaveFailsWhileAnotherProcessHoldsTheLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	lock := fmt.Sprintf(`{"pid":%d,"acquired_at":"2026-01-01T00:00:00Z"}`, os.Getppid())
+	if err := os.WriteFile(path+".lock", []byte(lock), 0o600); err != nil {
+		t.Fatalf("write lock file: %v", err)
+	}
+
+	err := Save(path, New())
+	if !errors.Is(err, filelock.ErrLocked) || !strings.Contains(err.Error(), fmt.Sprintf("state locked by PID %d", os.Getppid())) {
+		t.Fatalf("expected locked error, got %v", err)
+	}
+	if _, statErr := os.Stat(path); !errors.Is(statErr, os.ErrNotExist) {
+		t.Fatalf("expected config not to be written, got %v", statErr)
+	}
+
+	if err := os.Remove(path + ".lock"); err != nil {
+		t.Fatalf("remove lock file: %v", err)
+	}
+	if err := Save(path, New()); err != nil {
+		t.Fatalf("save after lock release: %v", err)
+	}
+}
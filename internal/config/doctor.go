@@ -0,0 +1,347 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	IssueError   = "error"
+	IssueWarning = "warning"
+)
+
+// Issue is one problem found in a config file with the action that fixes it.
+type Issue struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Profile  string `json:"profile,omitempty"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix"`
+}
+
+// Diagnosis describes a config file without requiring it to load. Config holds
+// the decoded file, migrated in memory when a migration applies, so callers can
+// run further checks against it.
+type Diagnosis struct {
+	Path                 string   `json:"path"`
+	SchemaVersion        int      `json:"schema_version"`
+	CurrentSchemaVersion int      `json:"current_schema_version"`
+	Migrations           []string `json:"migrations,omitempty"`
+	Issues               []Issue  `json:"issues"`
+	Config               *Config  `json:"-"`
+}
+
+type MigrationResult struct {
+	Path       string   `json:"path"`
+	From       int      `json:"from"`
+	To         int      `json:"to"`
+	BackupPath string   `json:"backup_path"`
+	Changes    []string `json:"changes"`
+}
+
+// migrations upgrade a config from the keyed schema version to the next one and
+// describe each change they made.
+var migrations = map[int]func(cfg *Config) []string{
+	1: migrateV1ToV2,
+}
+
+// migrateV1ToV2 fills the profile fields schema_version 2 made mandatory where
+// they can be derived. app_id and app_secret_ref cannot be, so profiles
+// without them still need to be re-created after the migration.
+func migrateV1ToV2(cfg *Config) []string {
+	var changes []string
+	for _, name := range sortedProfileNames(cfg) {
+		profile := cfg.Profiles[name]
+		before := profile
+		profile = applyProfileDefaults(profile)
+		if profile.AuthProvider == "" {
+			switch profile.TokenType {
+			case "system_user", "app":
+				profile.AuthProvider = profile.TokenType
+			case "user", "page":
+				profile.AuthProvider = "facebook_login"
+			}
+		}
+		if profile.AuthMode == "" {
+			profile.AuthMode = "both"
+		}
+		if profile.LastValidatedAt == "" {
+			profile.LastValidatedAt = profile.IssuedAt
+		}
+		for _, change := range []struct {
+			field string
+			from  string
+			to    string
+		}{
+			{"domain", before.Domain, profile.Domain},
+			{"graph_version", before.GraphVersion, profile.GraphVersion},
+			{"auth_provider", before.AuthProvider, profile.AuthProvider},
+			{"auth_mode", before.AuthMode, profile.AuthMode},
+			{"last_validated_at", before.LastValidatedAt, profile.LastValidatedAt},
+		} {
+			if change.from != change.to {
+				changes = append(changes, fmt.Sprintf("profiles.%s.%s set to %q", name, change.field, change.to))
+			}
+		}
+		cfg.Profiles[name] = profile
+	}
+	cfg.SchemaVersion = 2
+	return append(changes, "schema_version 1 -> 2")
+}
+
+// Diagnose inspects the config at path and reports every problem it can find
+// instead of stopping at the first one, as Load does.
+func Diagnose(path string) (Diagnosis, error) {
+	diagnosis := Diagnosis{Path: path, CurrentSchemaVersion: SchemaVersion, Issues: []Issue{}}
+	data, encrypted, err := readConfigData(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			diagnosis.addIssue(Issue{
+				Code:     "config_missing",
+				Severity: IssueError,
+				Message:  fmt.Sprintf("config file does not exist at %s", path),
+				Fix:      "Create a profile with `meta auth setup` or `meta auth add system-user`.",
+			})
+			return diagnosis, nil
+		}
+		if encrypted {
+			diagnosis.addIssue(Issue{
+				Code:     "config_encrypted",
+				Severity: IssueError,
+				Message:  err.Error(),
+				Fix:      fmt.Sprintf("Export the passphrase in %s, or the key file path in %s, that the config was encrypted with.", PassphraseEnv, KeyFileEnv),
+			})
+			return diagnosis, nil
+		}
+		return Diagnosis{}, fmt.Errorf("read config file %s: %w", path, err)
+	}
+
+	cfg, strictErr := decodeConfig(data, true)
+	if strictErr != nil {
+		lenient, lenientErr := decodeConfig(data, false)
+		if lenientErr != nil {
+			diagnosis.addIssue(Issue{
+				Code:     "config_invalid_yaml",
+				Severity: IssueError,
+				Message:  fmt.Sprintf("config is not valid YAML: %v", lenientErr),
+				Fix:      fmt.Sprintf("Fix the YAML syntax in %s, or restore it from a backup.", path),
+			})
+			return diagnosis, nil
+		}
+		diagnosis.addIssue(Issue{
+			Code:     "config_unknown_field",
+			Severity: IssueError,
+			Message:  strictErr.Error(),
+			Fix:      fmt.Sprintf("Remove or rename the unrecognized keys in %s.", path),
+		})
+		cfg = lenient
+	}
+	diagnosis.SchemaVersion = cfg.SchemaVersion
+	diagnosis.Config = cfg
+
+	switch {
+	case cfg.SchemaVersion > SchemaVersion:
+		diagnosis.addIssue(Issue{
+			Code:     "schema_version_newer",
+			Severity: IssueError,
+			Message:  fmt.Sprintf("config schema_version=%d was written by a newer meta (this build supports %d)", cfg.SchemaVersion, SchemaVersion),
+			Fix:      "Upgrade meta to the version that wrote this config.",
+		})
+		return diagnosis, nil
+	case cfg.SchemaVersion < SchemaVersion:
+		migrated, changes, err := migrate(cfg)
+		if err != nil {
+			diagnosis.addIssue(Issue{
+				Code:     "schema_version_unsupported",
+				Severity: IssueError,
+				Message:  err.Error(),
+				Fix:      "Recreate the config with `meta auth setup`; this schema_version cannot be migrated.",
+			})
+			return diagnosis, nil
+		}
+		diagnosis.addIssue(Issue{
+			Code:     "schema_version_outdated",
+			Severity: IssueError,
+			Message:  fmt.Sprintf("config schema_version=%d is older than the supported %d", cfg.SchemaVersion, SchemaVersion),
+			Fix:      "Run `meta config doctor --fix` to migrate it; the original is backed up first.",
+		})
+		diagnosis.Migrations = changes
+		diagnosis.Config = migrated
+		cfg = migrated
+	}
+
+	if cfg.Profiles == nil {
+		diagnosis.addIssue(Issue{
+			Code:     "profiles_missing",
+			Severity: IssueError,
+			Message:  "config profiles map is required",
+			Fix:      fmt.Sprintf("Add `profiles: {}` to %s, or create a profile with `meta auth setup`.", path),
+		})
+		return diagnosis, nil
+	}
+	for _, name := range sortedProfileNames(cfg) {
+		if err := validateProfile(name, cfg.Profiles[name]); err != nil {
+			diagnosis.addIssue(Issue{
+				Code:     "profile_invalid",
+				Severity: IssueError,
+				Profile:  name,
+				Message:  err.Error(),
+				Fix:      fmt.Sprintf("Re-create the profile with `meta auth setup --profile %s`, or edit profiles.%s in %s.", name, name, path),
+			})
+		}
+	}
+	switch {
+	case cfg.DefaultProfile != "" && !hasProfile(cfg, cfg.DefaultProfile):
+		diagnosis.addIssue(Issue{
+			Code:     "default_profile_missing",
+			Severity: IssueError,
+			Message:  fmt.Sprintf("default_profile %q does not exist", cfg.DefaultProfile),
+			Fix:      fmt.Sprintf("Set default_profile to one of [%s] in %s, or remove it.", strings.Join(sortedProfileNames(cfg), " "), path),
+		})
+	case cfg.DefaultProfile == "" && len(cfg.Profiles) > 0:
+		diagnosis.addIssue(Issue{
+			Code:     "default_profile_unset",
+			Severity: IssueWarning,
+			Message:  "no default_profile set; every command needs --profile",
+			Fix:      fmt.Sprintf("Set default_profile to one of [%s] in %s.", strings.Join(sortedProfileNames(cfg), " "), path),
+		})
+	}
+	return diagnosis, nil
+}
+
+// AddIssue records an issue found by checks outside this package, such as
+// secret store lookups.
+func (d *Diagnosis) AddIssue(issue Issue) {
+	d.addIssue(issue)
+}
+
+func (d *Diagnosis) addIssue(issue Issue) {
+	d.Issues = append(d.Issues, issue)
+}
+
+// HasErrors reports whether any issue has error severity.
+func (d Diagnosis) HasErrors() bool {
+	for _, issue := range d.Issues {
+		if issue.Severity == IssueError {
+			return true
+		}
+	}
+	return false
+}
+
+// MigrateFile upgrades the config at path to the current schema version. The
+// original is copied next to it first, encrypted if it was, and nothing is
+// written unless the migrated config validates.
+func MigrateFile(path string, now time.Time) (MigrationResult, error) {
+	lock, err := filelock.Acquire(path)
+	if err != nil {
+		return MigrationResult{}, err
+	}
+	defer lock.Release()
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return MigrationResult{}, fmt.Errorf("read config file %s: %w", path, err)
+	}
+	data, _, err := readConfigData(path)
+	if err != nil {
+		return MigrationResult{}, err
+	}
+	cfg, err := decodeConfig(data, true)
+	if err != nil {
+		return MigrationResult{}, fmt.Errorf("decode config file %s: %w", path, err)
+	}
+	from := cfg.SchemaVersion
+	if from == SchemaVersion {
+		return MigrationResult{}, fmt.Errorf("config %s is already at schema_version=%d", path, SchemaVersion)
+	}
+	migrated, changes, err := migrate(cfg)
+	if err != nil {
+		return MigrationResult{}, err
+	}
+	if err := migrated.Validate(); err != nil {
+		return MigrationResult{}, fmt.Errorf("migrated config is still invalid, nothing was written: %w", err)
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d-%s.bak", path, from, now.UTC().Format("20060102T150405Z"))
+	if err := os.WriteFile(backupPath, original, 0o600); err != nil {
+		return MigrationResult{}, fmt.Errorf("write config backup %s: %w", backupPath, err)
+	}
+	if err := SaveHeld(lock, path, migrated); err != nil {
+		return MigrationResult{}, err
+	}
+	return MigrationResult{
+		Path:       path,
+		From:       from,
+		To:         SchemaVersion,
+		BackupPath: backupPath,
+		Changes:    changes,
+	}, nil
+}
+
+func migrate(cfg *Config) (*Config, []string, error) {
+	if cfg.SchemaVersion > SchemaVersion {
+		return nil, nil, fmt.Errorf("config schema_version=%d is newer than the supported %d", cfg.SchemaVersion, SchemaVersion)
+	}
+	migrated := cloneConfig(cfg)
+	var changes []string
+	for migrated.SchemaVersion < SchemaVersion {
+		step, ok := migrations[migrated.SchemaVersion]
+		if !ok {
+			return nil, nil, fmt.Errorf("no migration from config schema_version=%d", migrated.SchemaVersion)
+		}
+		changes = append(changes, step(migrated)...)
+	}
+	return migrated, changes, nil
+}
+
+func decodeConfig(data []byte, strict bool) (*Config, error) {
+	cfg := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(strict)
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func cloneConfig(cfg *Config) *Config {
+	clone := *cfg
+	if cfg.Profiles != nil {
+		clone.Profiles = make(map[string]Profile, len(cfg.Profiles))
+		for name, profile := range cfg.Profiles {
+			profile.Scopes = append([]string(nil), profile.Scopes...)
+			if profile.Labels != nil {
+				labels := make(map[string]string, len(profile.Labels))
+				for key, value := range profile.Labels {
+					labels[key] = value
+				}
+				profile.Labels = labels
+			}
+			clone.Profiles[name] = profile
+		}
+	}
+	return &clone
+}
+
+func hasProfile(cfg *Config, name string) bool {
+	_, ok := cfg.Profiles[name]
+	return ok
+}
+
+func sortedProfileNames(cfg *Config) []string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const schemaV1Fixture = `
+schema_version: 1
+default_profile: prod
+profiles:
+  prod:
+    token_type: system_user
+    app_id: "1234567890"
+    token_ref: keychain://meta-marketing-cli/prod/token
+    app_secret_ref: keychain://meta-marketing-cli/prod/app_secret
+    scopes:
+      - ads_read
+    issued_at: 2026-01-01T00:00:00Z
+    expires_at: 2026-12-31T00:00:00Z
+`
+
+func writeConfigFixture(t *testing.T, raw string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatalf("write config fixture: %v", err)
+	}
+	return path
+}
+
+func issueCodes(diagnosis Diagnosis) []string {
+	codes := make([]string, 0, len(diagnosis.Issues))
+	for _, issue := range diagnosis.Issues {
+		codes = append(codes, issue.Code)
+	}
+	return codes
+}
+
+func TestDiagnoseReportsOutdatedSchemaWithMigrationPlan(t *testing.T) {
+	t.Parallel()
+
+	diagnosis, err := Diagnose(writeConfigFixture(t, schemaV1Fixture))
+	if err != nil {
+		t.Fatalf("diagnose: %v", err)
+	}
+	if got := strings.Join(issueCodes(diagnosis), ","); got != "schema_version_outdated" {
+		t.Fatalf("unexpected issues: %s", got)
+	}
+	if diagnosis.SchemaVersion != 1 || diagnosis.CurrentSchemaVersion != SchemaVersion {
+		t.Fatalf("unexpected versions: %+v", diagnosis)
+	}
+	if !strings.Contains(diagnosis.Issues[0].Fix, "meta config doctor --fix") {
+		t.Fatalf("expected migration fix, got %q", diagnosis.Issues[0].Fix)
+	}
+	migrated := diagnosis.Config.Profiles["prod"]
+	if migrated.AuthProvider != "system_user" || migrated.AuthMode != "both" || migrated.LastValidatedAt != "2026-01-01T00:00:00Z" {
+		t.Fatalf("unexpected migrated profile: %+v", migrated)
+	}
+	if len(diagnosis.Migrations) == 0 || diagnosis.Migrations[len(diagnosis.Migrations)-1] != "schema_version 1 -> 2" {
+		t.Fatalf("unexpected migrations: %v", diagnosis.Migrations)
+	}
+}
+
+func TestDiagnoseCollectsEveryProblem(t *testing.T) {
+	t.Parallel()
+
+	raw := `
+schema_version: 2
+default_profile: missing
+profiles:
+  broken:
+    domain: marketing
+    graph_version: v25.0
+    token_type: system_user
+    token_ref: keychain://meta-marketing-cli/broken/token
+    surprise: true
+`
+	diagnosis, err := Diagnose(writeConfigFixture(t, raw))
+	if err != nil {
+		t.Fatalf("diagnose: %v", err)
+	}
+	want := "config_unknown_field,profile_invalid,default_profile_missing"
+	if got := strings.Join(issueCodes(diagnosis), ","); got != want {
+		t.Fatalf("unexpected issues: got %s want %s", got, want)
+	}
+	if diagnosis.Issues[1].Profile != "broken" || !strings.Contains(diagnosis.Issues[1].Fix, "meta auth setup --profile broken") {
+		t.Fatalf("unexpected profile issue: %+v", diagnosis.Issues[1])
+	}
+	if !diagnosis.HasErrors() {
+		t.Fatal("expected errors")
+	}
+}
+
+func TestDiagnoseRejectsNewerSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	diagnosis, err := Diagnose(writeConfigFixture(t, "schema_version: 9\nprofiles: {}\n"))
+	if err != nil {
+		t.Fatalf("diagnose: %v", err)
+	}
+	if got := strings.Join(issueCodes(diagnosis), ","); got != "schema_version_newer" {
+		t.Fatalf("unexpected issues: %s", got)
+	}
+}
+
+func TestMigrateFileBacksUpAndRewritesConfig(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfigFixture(t, schemaV1Fixture)
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	result, err := MigrateFile(path, now)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if result.From != 1 || result.To != SchemaVersion {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.BackupPath != path+".v1-20261016T093000Z.bak" {
+		t.Fatalf("unexpected backup path: %s", result.BackupPath)
+	}
+	backup, err := os.ReadFile(result.BackupPath)
+	if err != nil {
+		t.Fatalf("read backup: %v", err)
+	}
+	if string(backup) != schemaV1Fixture {
+		t.Fatalf("backup does not match original:\n%s", backup)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load migrated config: %v", err)
+	}
+	if cfg.Profiles["prod"].AuthProvider != "system_user" {
+		t.Fatalf("unexpected migrated profile: %+v", cfg.Profiles["prod"])
+	}
+	if _, err := MigrateFile(path, now); err == nil || !strings.Contains(err.Error(), "already at schema_version") {
+		t.Fatalf("expected second migration to be refused, got %v", err)
+	}
+}
+
+func TestMigrateFileLeavesUnmigratableConfigUntouched(t *testing.T) {
+	t.Parallel()
+
+	raw := strings.Replace(schemaV1Fixture, "    app_id: \"1234567890\"\n", "", 1)
+	path := writeConfigFixture(t, raw)
+	if _, err := MigrateFile(path, time.Now()); err == nil || !strings.Contains(err.Error(), "nothing was written") {
+		t.Fatalf("expected migration to fail, got %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if string(data) != raw {
+		t.Fatal("config was modified")
+	}
+	matches, _ := filepath.Glob(path + ".*.bak")
+	if len(matches) != 0 {
+		t.Fatalf("unexpected backups: %v", matches)
+	}
+}
@@ -0,0 +1,306 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// PassphraseEnv and KeyFileEnv supply the secret for an encrypted config.
+	// The key file wins when both are set.
+	PassphraseEnv = "META_CONFIG_PASSPHRASE"
+	KeyFileEnv    = "META_CONFIG_KEY_FILE"
+
+	encryptedFormatV1   = "v1"
+	kdfPBKDF2SHA256     = "pbkdf2-sha256"
+	kdfKeyFileSHA256    = "key-file-sha256"
+	pbkdf2Iterations    = 600000
+	encryptionSaltBytes = 16
+	encryptionKeyBytes  = 32
+	minKeyFileBytes     = 32
+)
+
+// maxPBKDF2Iterations bounds the work a tampered iterations field can make
+// every command do before the authentication tag is even checked.
+const maxPBKDF2Iterations = 10 * pbkdf2Iterations
+
+// ErrEncryptionKeyMissing reports an encrypted config with no passphrase or key
+// file available to open it.
+var ErrEncryptionKeyMissing = errors.New("config is encrypted: set " + PassphraseEnv + " or " + KeyFileEnv)
+
+// encryptedConfigFile is the on-disk form of an encrypted config. Everything
+// but the ciphertext is authenticated as additional data.
+type encryptedConfigFile struct {
+	Encrypted  string `yaml:"encrypted"`
+	KDF        string `yaml:"kdf"`
+	Iterations int    `yaml:"iterations,omitempty"`
+	Salt       string `yaml:"salt"`
+	Nonce      string `yaml:"nonce"`
+	Ciphertext string `yaml:"ciphertext"`
+}
+
+// EncryptionSecret is a passphrase or the contents of a key file.
+type EncryptionSecret struct {
+	Passphrase string
+	KeyFile    []byte
+}
+
+// EncryptionSecretFromEnv reads the secret from META_CONFIG_KEY_FILE or
+// META_CONFIG_PASSPHRASE. It returns ErrEncryptionKeyMissing when neither is set.
+func EncryptionSecretFromEnv() (EncryptionSecret, error) {
+	if path := strings.TrimSpace(os.Getenv(KeyFileEnv)); path != "" {
+		return LoadKeyFile(path)
+	}
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return EncryptionSecret{Passphrase: passphrase}, nil
+	}
+	return EncryptionSecret{}, ErrEncryptionKeyMissing
+}
+
+func LoadKeyFile(path string) (EncryptionSecret, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return EncryptionSecret{}, fmt.Errorf("read config key file %s: %w", path, err)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) < minKeyFileBytes {
+		return EncryptionSecret{}, fmt.Errorf("config key file %s must hold at least %d bytes of key material", path, minKeyFileBytes)
+	}
+	return EncryptionSecret{KeyFile: data}, nil
+}
+
+// IsEncrypted reports whether the config file at path is encrypted.
+func IsEncrypted(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	return isEncryptedData(data), nil
+}
+
+func isEncryptedData(data []byte) bool {
+	var probe struct {
+		Encrypted string `yaml:"encrypted"`
+	}
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return false
+	}
+	return probe.Encrypted != ""
+}
+
+// readConfigData returns the plaintext YAML of the config at path, decrypting
+// it with the secret from the environment when the file is encrypted.
+func readConfigData(path string) ([]byte, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	if !isEncryptedData(data) {
+		return data, false, nil
+	}
+	secret, err := EncryptionSecretFromEnv()
+	if err != nil {
+		return nil, true, fmt.Errorf("open config file %s: %w", path, err)
+	}
+	plaintext, err := decryptConfig(data, secret)
+	if err != nil {
+		return nil, true, fmt.Errorf("open config file %s: %w", path, err)
+	}
+	return plaintext, true, nil
+}
+
+func encryptConfig(plaintext []byte, secret EncryptionSecret) ([]byte, error) {
+	file := encryptedConfigFile{Encrypted: encryptedFormatV1}
+	salt := make([]byte, encryptionSaltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate config salt: %w", err)
+	}
+	file.Salt = base64.StdEncoding.EncodeToString(salt)
+	if secret.KeyFile != nil {
+		file.KDF = kdfKeyFileSHA256
+	} else {
+		if secret.Passphrase == "" {
+			return nil, ErrEncryptionKeyMissing
+		}
+		file.KDF = kdfPBKDF2SHA256
+		file.Iterations = pbkdf2Iterations
+	}
+
+	aead, err := configAEAD(file, salt, secret)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate config nonce: %w", err)
+	}
+	file.Nonce = base64.StdEncoding.EncodeToString(nonce)
+	file.Ciphertext = base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, plaintext, encryptionAAD(file)))
+
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return nil, fmt.Errorf("marshal encrypted config: %w", err)
+	}
+	return data, nil
+}
+
+func decryptConfig(data []byte, secret EncryptionSecret) ([]byte, error) {
+	file := encryptedConfigFile{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("decode encrypted config: %w", err)
+	}
+	if file.Encrypted != encryptedFormatV1 {
+		return nil, fmt.Errorf("unsupported encrypted config format %q", file.Encrypted)
+	}
+	switch {
+	case file.KDF == kdfKeyFileSHA256 && secret.KeyFile == nil:
+		return nil, fmt.Errorf("config was encrypted with a key file: set %s", KeyFileEnv)
+	case file.KDF == kdfPBKDF2SHA256 && secret.KeyFile != nil:
+		return nil, fmt.Errorf("config was encrypted with a passphrase: set %s and unset %s", PassphraseEnv, KeyFileEnv)
+	}
+	salt, err := base64.StdEncoding.DecodeString(file.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("decode encrypted config salt: %w", err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(file.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("decode encrypted config nonce: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(file.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("decode encrypted config ciphertext: %w", err)
+	}
+	aead, err := configAEAD(file, salt, secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("encrypted config nonce has the wrong length")
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, encryptionAAD(file))
+	if err != nil {
+		return nil, errors.New("decrypt config: wrong passphrase or key file, or the file was modified")
+	}
+	return plaintext, nil
+}
+
+func configAEAD(file encryptedConfigFile, salt []byte, secret EncryptionSecret) (cipher.AEAD, error) {
+	var key []byte
+	switch file.KDF {
+	case kdfPBKDF2SHA256:
+		if file.Iterations < 1 {
+			return nil, fmt.Errorf("encrypted config iterations must be positive, got %d", file.Iterations)
+		}
+		if file.Iterations > maxPBKDF2Iterations {
+			return nil, fmt.Errorf("encrypted config iterations %d exceed the maximum of %d; the file was modified or written by an incompatible meta", file.Iterations, maxPBKDF2Iterations)
+		}
+		key = pbkdf2SHA256([]byte(secret.Passphrase), salt, file.Iterations, encryptionKeyBytes)
+	case kdfKeyFileSHA256:
+		sum := sha256.Sum256(append(append([]byte{}, salt...), secret.KeyFile...))
+		key = sum[:]
+	default:
+		return nil, fmt.Errorf("unsupported encrypted config kdf %q", file.KDF)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create config cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptionAAD(file encryptedConfigFile) []byte {
+	return []byte(fmt.Sprintf("%s|%s|%d|%s", file.Encrypted, file.KDF, file.Iterations, file.Salt))
+}
+
+// pbkdf2SHA256 implements PBKDF2 (RFC 8018) with HMAC-SHA256.
+func pbkdf2SHA256(password []byte, salt []byte, iterations int, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+	derived := make([]byte, 0, blocks*hashLen)
+	counter := make([]byte, 4)
+	for block := 1; block <= blocks; block++ {
+		binary.BigEndian.PutUint32(counter, uint32(block))
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(counter)
+		u := prf.Sum(nil)
+		t := append([]byte{}, u...)
+		for n := 1; n < iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range t {
+				t[i] ^= u[i]
+			}
+		}
+		derived = append(derived, t...)
+	}
+	return derived[:keyLen]
+}
+
+// Encrypt rewrites the plaintext config at path encrypted with secret.
+func Encrypt(path string, secret EncryptionSecret) error {
+	lock, err := filelock.Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file %s: %w", path, err)
+	}
+	if isEncryptedData(data) {
+		return fmt.Errorf("config file %s is already encrypted", path)
+	}
+	cfg, err := decodeConfig(data, true)
+	if err != nil {
+		return fmt.Errorf("decode config file %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	encrypted, err := encryptConfig(data, secret)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, encrypted)
+}
+
+// Decrypt rewrites the encrypted config at path as plaintext YAML.
+func Decrypt(path string, secret EncryptionSecret) error {
+	lock, err := filelock.Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file %s: %w", path, err)
+	}
+	if !isEncryptedData(data) {
+		return fmt.Errorf("config file %s is not encrypted", path)
+	}
+	plaintext, err := decryptConfig(data, secret)
+	if err != nil {
+		return fmt.Errorf("open config file %s: %w", path, err)
+	}
+	return writeFileAtomic(path, plaintext)
+}
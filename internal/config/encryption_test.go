@@ -0,0 +1,139 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPBKDF2SHA256MatchesRFC7914Vector(t *testing.T) {
+	t.Parallel()
+
+	got := hex.EncodeToString(pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64))
+	want := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if got != want {
+		t.Fatalf("unexpected derived key:\n got %s\nwant %s", got, want)
+	}
+}
+
+func writeEncryptionFixture(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := New()
+	profile := validProfile()
+	profile.BusinessID = "business-4242"
+	if err := cfg.UpsertProfile("acme-prod", profile); err != nil {
+		t.Fatalf("upsert profile: %v", err)
+	}
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	return path
+}
+
+func TestEncryptedConfigLoadsAndSavesTransparently(t *testing.T) {
+	t.Setenv(KeyFileEnv, "")
+	t.Setenv(PassphraseEnv, "correct horse battery staple")
+	path := writeEncryptionFixture(t)
+
+	if err := Encrypt(path, EncryptionSecret{Passphrase: "correct horse battery staple"}); err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read encrypted config: %v", err)
+	}
+	for _, plaintext := range []string{"acme-prod", "business-4242", "keychain://"} {
+		if strings.Contains(string(raw), plaintext) {
+			t.Fatalf("encrypted config leaks %q:\n%s", plaintext, raw)
+		}
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load encrypted config: %v", err)
+	}
+	if cfg.Profiles["acme-prod"].BusinessID != "business-4242" {
+		t.Fatalf("unexpected profile: %+v", cfg.Profiles["acme-prod"])
+	}
+
+	cfg.DefaultProfile = "acme-prod"
+	profile := cfg.Profiles["acme-prod"]
+	profile.BusinessID = "business-7"
+	cfg.Profiles["acme-prod"] = profile
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("save encrypted config: %v", err)
+	}
+	if encrypted, err := IsEncrypted(path); err != nil || !encrypted {
+		t.Fatalf("expected config to stay encrypted, got %v %v", encrypted, err)
+	}
+	if cfg, err = Load(path); err != nil || cfg.Profiles["acme-prod"].BusinessID != "business-7" {
+		t.Fatalf("unexpected reload: %+v %v", cfg, err)
+	}
+
+	tampered := strings.Replace(string(raw), "iterations: 600000", "iterations: 6000001", 1)
+	if tampered == string(raw) {
+		t.Fatalf("encrypted config has no iterations field:\n%s", raw)
+	}
+	tamperedPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(tamperedPath, []byte(tampered), 0o600); err != nil {
+		t.Fatalf("write tampered config: %v", err)
+	}
+	if _, err := Load(tamperedPath); err == nil || !strings.Contains(err.Error(), "exceed the maximum of 6000000") {
+		t.Fatalf("expected excessive iterations to be rejected, got %v", err)
+	}
+
+	t.Setenv(PassphraseEnv, "wrong")
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "wrong passphrase or key file") {
+		t.Fatalf("expected wrong passphrase to fail, got %v", err)
+	}
+	t.Setenv(PassphraseEnv, "")
+	if _, err := Load(path); !errors.Is(err, ErrEncryptionKeyMissing) {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+
+	if err := Decrypt(path, EncryptionSecret{Passphrase: "correct horse battery staple"}); err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if cfg, err = Load(path); err != nil || cfg.Profiles["acme-prod"].BusinessID != "business-7" {
+		t.Fatalf("unexpected plaintext reload: %+v %v", cfg, err)
+	}
+}
+
+func TestEncryptedConfigWithKeyFile(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "config.key")
+	if err := os.WriteFile(keyPath, []byte("0123456789abcdef0123456789abcdef\n"), 0o600); err != nil {
+		t.Fatalf("write key file: %v", err)
+	}
+	t.Setenv(PassphraseEnv, "")
+	t.Setenv(KeyFileEnv, keyPath)
+	path := writeEncryptionFixture(t)
+
+	secret, err := EncryptionSecretFromEnv()
+	if err != nil {
+		t.Fatalf("secret from env: %v", err)
+	}
+	if err := Encrypt(path, secret); err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if _, err := Load(path); err != nil {
+		t.Fatalf("load with key file: %v", err)
+	}
+
+	t.Setenv(KeyFileEnv, "")
+	t.Setenv(PassphraseEnv, "anything")
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "encrypted with a key file") {
+		t.Fatalf("expected key-file hint, got %v", err)
+	}
+
+	shortKey := filepath.Join(t.TempDir(), "short.key")
+	if err := os.WriteFile(shortKey, []byte("short"), 0o600); err != nil {
+		t.Fatalf("write short key: %v", err)
+	}
+	if _, err := LoadKeyFile(shortKey); err == nil || !strings.Contains(err.Error(), "at least 32 bytes") {
+		t.Fatalf("expected short key file to be rejected, got %v", err)
+	}
+}
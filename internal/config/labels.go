@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Selector matches profiles by label, e.g. "env=prod,client!=acme,team".
+// Every term must match: key=value, key!=value, or a bare key that must be set.
+type Selector struct {
+	terms []selectorTerm
+}
+
+type selectorTerm struct {
+	key    string
+	value  string
+	negate bool
+	exists bool
+}
+
+func ParseSelector(raw string) (Selector, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Selector{}, errors.New("profiles selector is empty")
+	}
+	selector := Selector{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		term := selectorTerm{}
+		switch {
+		case strings.Contains(part, "!="):
+			key, value, _ := strings.Cut(part, "!=")
+			term = selectorTerm{key: strings.TrimSpace(key), value: strings.TrimSpace(value), negate: true}
+		case strings.Contains(part, "="):
+			key, value, _ := strings.Cut(part, "=")
+			term = selectorTerm{key: strings.TrimSpace(key), value: strings.TrimSpace(value)}
+		default:
+			term = selectorTerm{key: part, exists: true}
+		}
+		if err := validateLabelKey(term.key); err != nil {
+			return Selector{}, fmt.Errorf("invalid profiles selector %q: %w", raw, err)
+		}
+		selector.terms = append(selector.terms, term)
+	}
+	return selector, nil
+}
+
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, term := range s.terms {
+		value, ok := labels[term.key]
+		switch {
+		case term.exists:
+			if !ok {
+				return false
+			}
+		case term.negate:
+			if ok && value == term.value {
+				return false
+			}
+		default:
+			if !ok || value != term.value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SelectProfiles returns the names of the profiles whose labels match selector,
+// sorted.
+func (c *Config) SelectProfiles(selector Selector) []string {
+	names := []string{}
+	for name, profile := range c.Profiles {
+		if selector.Matches(profile.Labels) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateLabelKey(key string) error {
+	if key == "" {
+		return errors.New("label key cannot be empty")
+	}
+	if strings.ContainsAny(key, "=!, \t") {
+		return fmt.Errorf("label key %q cannot contain '=', '!', ',' or whitespace", key)
+	}
+	return nil
+}
+
+func validateLabels(name string, labels map[string]string) error {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := validateLabelKey(key); err != nil {
+			return fmt.Errorf("profile %q labels: %w", name, err)
+		}
+		if strings.ContainsAny(labels[key], ",") {
+			return fmt.Errorf("profile %q labels: value of %q cannot contain ','", name, key)
+		}
+	}
+	return nil
+}
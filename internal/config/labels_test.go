@@ -0,0 +1,77 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectorMatchesLabels(t *testing.T) {
+	t.Parallel()
+
+	labels := map[string]string{"env": "staging", "client": "acme", "team": "growth"}
+	cases := map[string]bool{
+		"env=staging":                    true,
+		"env=prod":                       false,
+		"env=staging,client=acme":        true,
+		"env=staging,client!=acme":       false,
+		"client!=globex":                 true,
+		"team":                           true,
+		"region":                         false,
+		" env = staging , team ":         true,
+		"env=staging,client=acme,region": false,
+	}
+	for raw, want := range cases {
+		selector, err := ParseSelector(raw)
+		if err != nil {
+			t.Fatalf("parse %q: %v", raw, err)
+		}
+		if got := selector.Matches(labels); got != want {
+			t.Fatalf("selector %q: expected %v, got %v", raw, want, got)
+		}
+	}
+
+	for _, raw := range []string{"", "env=staging,", "=prod", "my env=prod"} {
+		if _, err := ParseSelector(raw); err == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestSelectProfilesAndLabelValidation(t *testing.T) {
+	t.Parallel()
+
+	cfg := New()
+	for name, labels := range map[string]map[string]string{
+		"acme-prod":    {"env": "prod", "client": "acme"},
+		"acme-staging": {"env": "staging", "client": "acme"},
+		"globex-prod":  {"env": "prod", "client": "globex"},
+		"unlabelled":   nil,
+	} {
+		profile := validProfile()
+		profile.Labels = labels
+		if err := cfg.UpsertProfile(name, profile); err != nil {
+			t.Fatalf("upsert %s: %v", name, err)
+		}
+	}
+
+	selector, err := ParseSelector("env=prod")
+	if err != nil {
+		t.Fatalf("parse selector: %v", err)
+	}
+	if got := strings.Join(cfg.SelectProfiles(selector), ","); got != "acme-prod,globex-prod" {
+		t.Fatalf("unexpected selection: %s", got)
+	}
+
+	if err := cfg.UpsertProfile("acme-prod", validProfile()); err != nil {
+		t.Fatalf("re-upsert profile: %v", err)
+	}
+	if cfg.Profiles["acme-prod"].Labels["client"] != "acme" {
+		t.Fatalf("expected labels to survive re-authentication, got %v", cfg.Profiles["acme-prod"].Labels)
+	}
+
+	profile := validProfile()
+	profile.Labels = map[string]string{"env=prod": "x"}
+	if err := cfg.UpsertProfile("bad", profile); err == nil || !strings.Contains(err.Error(), "labels") {
+		t.Fatalf("expected invalid label key to fail, got %v", err)
+	}
+}
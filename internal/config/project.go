@@ -0,0 +1,122 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/i18n"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ProjectSchemaVersion = 1
+	ProjectDirName       = ".meta"
+	ProjectFileName      = "config.yaml"
+)
+
+// ProjectConfig is a repository-local .meta/config.yaml. It holds no
+// credentials: it pins which profile to use and the flag values the project
+// expects, layered over the user config.
+type ProjectConfig struct {
+	SchemaVersion int               `yaml:"schema_version"`
+	Profile       string            `yaml:"profile,omitempty"`
+	Defaults      ProfileDefaults   `yaml:"defaults,omitempty"`
+	Flags         map[string]string `yaml:"flags,omitempty"`
+
+	// Path is the file the project config was loaded from.
+	Path string `yaml:"-"`
+}
+
+// FindProject walks up from start looking for .meta/config.yaml, the way git
+// finds .git. The user config at userPath is never treated as a project config,
+// so running from the home directory does not pick it up. It returns "" when no
+// project config exists.
+func FindProject(start string, userPath string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", fmt.Errorf("resolve project search directory %s: %w", start, err)
+	}
+	userPath = filepath.Clean(userPath)
+	for {
+		candidate := filepath.Join(dir, ProjectDirName, ProjectFileName)
+		if candidate != userPath {
+			info, err := os.Stat(candidate)
+			switch {
+			case err == nil && !info.IsDir():
+				return candidate, nil
+			case err != nil && !errors.Is(err, os.ErrNotExist):
+				return "", fmt.Errorf("stat project config %s: %w", candidate, err)
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+func LoadProject(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read project config %s: %w", path, err)
+	}
+	project := &ProjectConfig{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(project); err != nil {
+		return nil, fmt.Errorf("decode project config %s: %w", path, err)
+	}
+	if project.SchemaVersion != ProjectSchemaVersion {
+		return nil, fmt.Errorf("unsupported project config schema_version=%d in %s (expected %d)", project.SchemaVersion, path, ProjectSchemaVersion)
+	}
+	switch project.Defaults.Output {
+	case "", "json", "jsonl", "table", "csv", "ids":
+	default:
+		return nil, fmt.Errorf("project config %s defaults.output must be one of [json jsonl table csv ids]", path)
+	}
+	if _, err := i18n.Parse(project.Defaults.Lang); err != nil {
+		return nil, fmt.Errorf("project config %s defaults.lang: %w", path, err)
+	}
+	for name := range project.Flags {
+		if strings.TrimSpace(name) == "" || strings.HasPrefix(name, "-") {
+			return nil, fmt.Errorf("project config %s flags keys must be flag names without dashes, got %q", path, name)
+		}
+		if name == "profile" {
+			return nil, fmt.Errorf("project config %s sets the profile with the top-level profile key, not flags", path)
+		}
+		if IsSafetyOverrideFlag(name) {
+			return nil, fmt.Errorf("project config %s cannot set --%s: safety overrides and trust anchors must be given on the command line", path, name)
+		}
+	}
+	project.Path = path
+	return project, nil
+}
+
+// FlagValues merges the defaults block and the flags map into flag values.
+// Entries in flags win. Relative paths in directory and file flags resolve
+// against the project root, the directory holding .meta, so they work from any
+// subdirectory.
+func (p *ProjectConfig) FlagValues() map[string]string {
+	values := p.Defaults.Flags()
+	for name, value := range p.Flags {
+		if value = strings.TrimSpace(value); value != "" {
+			values[name] = value
+		}
+	}
+	root := filepath.Dir(filepath.Dir(p.Path))
+	for name, value := range values {
+		if isPathFlag(name) && !filepath.IsAbs(value) {
+			values[name] = filepath.Join(root, value)
+		}
+	}
+	return values
+}
+
+func isPathFlag(name string) bool {
+	return strings.HasSuffix(name, "-dir") || strings.HasSuffix(name, "-file") || strings.HasSuffix(name, "-path")
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeProjectFixture(t *testing.T, root string, raw string) string {
+	t.Helper()
+	path := filepath.Join(root, ProjectDirName, ProjectFileName)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("create project dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatalf("write project config: %v", err)
+	}
+	return path
+}
+
+func TestFindProjectWalksUpAndSkipsUserConfig(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	userPath := writeProjectFixture(t, home, "schema_version: 2\nprofiles: {}\n")
+	nested := filepath.Join(home, "work", "repo", "campaigns", "q4")
+	if err := os.MkdirAll(nested, 0o700); err != nil {
+		t.Fatalf("create nested dir: %v", err)
+	}
+
+	path, err := FindProject(nested, userPath)
+	if err != nil {
+		t.Fatalf("find project: %v", err)
+	}
+	if path != "" {
+		t.Fatalf("expected the user config to be skipped, got %s", path)
+	}
+
+	projectPath := writeProjectFixture(t, filepath.Join(home, "work", "repo"), "schema_version: 1\n")
+	path, err = FindProject(nested, userPath)
+	if err != nil {
+		t.Fatalf("find project: %v", err)
+	}
+	if path != projectPath {
+		t.Fatalf("expected %s, got %s", projectPath, path)
+	}
+}
+
+func TestLoadProjectResolvesPathFlagsAgainstProjectRoot(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	path := writeProjectFixture(t, root, `
+schema_version: 1
+profile: acme-prod
+defaults:
+  account_id: act_1
+  schema_dir: schema-packs
+  output: table
+flags:
+  rules-dir: /opt/rules
+  exit-policy-file: policy/exit.json
+  fail-on: blocking
+  output: jsonl
+`)
+	project, err := LoadProject(path)
+	if err != nil {
+		t.Fatalf("load project: %v", err)
+	}
+	if project.Profile != "acme-prod" || project.Path != path {
+		t.Fatalf("unexpected project: %+v", project)
+	}
+	want := map[string]string{
+		"account-id":       "act_1",
+		"schema-dir":       filepath.Join(root, "schema-packs"),
+		"rules-dir":        "/opt/rules",
+		"exit-policy-file": filepath.Join(root, "policy", "exit.json"),
+		"fail-on":          "blocking",
+		"output":           "jsonl",
+	}
+	got := project.FlagValues()
+	if len(got) != len(want) {
+		t.Fatalf("unexpected flag values: %v", got)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Fatalf("expected %s=%q, got %q", name, value, got[name])
+		}
+	}
+}
+
+func TestLoadProjectRejectsInvalidFiles(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"schema_version: 2\n":                                     "unsupported project config schema_version=2",
+		"schema_version: 1\ntoken_ref: keychain://x\n":            "field token_ref not found",
+		"schema_version: 1\nflags:\n  profile: prod\n":            "top-level profile key",
+		"schema_version: 1\ndefaults:\n  output: yaml\n":          "defaults.output",
+		"schema_version: 1\nflags:\n  --account-id: act\n":        "without dashes",
+		"schema_version: 1\nflags:\n  confirm-delete: \"true\"\n": "cannot set --confirm-delete",
+		"schema_version: 1\nflags:\n  public-key: AAAA\n":         "cannot set --public-key",
+	}
+	for raw, want := range cases {
+		path := writeProjectFixture(t, t.TempDir(), raw)
+		if _, err := LoadProject(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q for %q, got %v", want, raw, err)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import "strings"
+
+// safetyOverrideFlags acknowledge or bypass a safety gate: freeze windows, the
+// anomaly guard, the approval gate, overwrite protection and the confirm-*
+// acknowledgements of destructive commands. public-key and manifest-url
+// replace the trust anchors that signed schema packs and releases are
+// verified against.
+var safetyOverrideFlags = map[string]struct{}{
+	"break-glass":      {},
+	"override-anomaly": {},
+	"approval-token":   {},
+	"force":            {},
+	"public-key":       {},
+	"manifest-url":     {},
+}
+
+// IsSafetyOverrideFlag reports whether the flag called name bypasses a safety
+// gate. Such flags must come from whoever runs the command, never from the
+// environment, a project config or an agent calling through meta serve.
+func IsSafetyOverrideFlag(name string) bool {
+	if _, ok := safetyOverrideFlags[name]; ok {
+		return true
+	}
+	return strings.HasPrefix(name, "confirm-")
+}
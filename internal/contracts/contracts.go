@@ -0,0 +1,43 @@
+// Package contracts publishes JSON Schemas for the CLI's machine-readable
+// outputs. The schemas are generated from the Go types that produce the
+// output and committed under schemas/, so they ship inside the binary and can
+// be reviewed in diffs.
+package contracts
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+const schemaSuffix = ".schema.json"
+
+//go:embed schemas/*.schema.json
+var embedded embed.FS
+
+// Names lists the published schemas in name order.
+func Names() []string {
+	entries, _ := fs.ReadDir(embedded, "schemas")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), schemaSuffix))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Schema returns the published schema called name.
+func Schema(name string) ([]byte, error) {
+	data, err := embedded.ReadFile("schemas/" + name + schemaSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("unknown contract %q; expected one of %s", name, strings.Join(Names(), ", "))
+	}
+	return data, nil
+}
+
+// FileName is the file name a schema is published under.
+func FileName(name string) string {
+	return name + schemaSuffix
+}
@@ -0,0 +1,247 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// IDBase prefixes the $id of every published schema.
+const IDBase = "https://github.com/bilalbayram/metacli/contracts/"
+
+// Contract is a Go type published as a JSON Schema.
+type Contract struct {
+	Name        string
+	Title       string
+	Description string
+	// Value is a zero value of the type the schema describes.
+	Value any
+	// Const pins top-level properties to a value, such as contract_version
+	// or kind.
+	Const map[string]any
+}
+
+// node is a JSON Schema node. The zero node accepts any value.
+type node struct {
+	Dialect              string           `json:"$schema,omitempty"`
+	ID                   string           `json:"$id,omitempty"`
+	Title                string           `json:"title,omitempty"`
+	Description          string           `json:"description,omitempty"`
+	Ref                  string           `json:"$ref,omitempty"`
+	Type                 any              `json:"type,omitempty"`
+	Format               string           `json:"format,omitempty"`
+	Const                any              `json:"const,omitempty"`
+	Properties           *properties      `json:"properties,omitempty"`
+	Required             []string         `json:"required,omitempty"`
+	Items                *node            `json:"items,omitempty"`
+	AdditionalProperties *node            `json:"additionalProperties,omitempty"`
+	AnyOf                []*node          `json:"anyOf,omitempty"`
+	Defs                 map[string]*node `json:"$defs,omitempty"`
+}
+
+// properties keeps struct field order in the encoded schema.
+type properties struct {
+	names   []string
+	schemas map[string]*node
+}
+
+func (p *properties) set(name string, schema *node) {
+	if p.schemas == nil {
+		p.schemas = map[string]*node{}
+	}
+	if _, ok := p.schemas[name]; !ok {
+		p.names = append(p.names, name)
+	}
+	p.schemas[name] = schema
+}
+
+func (p *properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for index, name := range p.names {
+		if index > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.schemas[name])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Generate returns the indented JSON Schema of contract.Value as encoding/json
+// writes it: fields without omitempty are required, nil slices and maps and
+// pointers without omitempty may be null, and interface fields accept
+// anything. Named struct types go to $defs.
+func Generate(contract Contract) ([]byte, error) {
+	t := reflect.TypeOf(contract.Value)
+	if t == nil {
+		return nil, fmt.Errorf("contract %s has no value", contract.Name)
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("contract %s must be a struct, got %s", contract.Name, t.Kind())
+	}
+	g := &generator{defs: map[string]*node{}, names: map[reflect.Type]string{}, taken: map[string]reflect.Type{}, root: t}
+	root := g.structSchema(t)
+	for name, value := range contract.Const {
+		property, ok := root.Properties.schemas[name]
+		if !ok {
+			return nil, fmt.Errorf("contract %s has no property %q to pin", contract.Name, name)
+		}
+		property.Const = value
+	}
+	root.Dialect = schemaDialect
+	root.ID = IDBase + contract.Name + ".schema.json"
+	root.Title = contract.Title
+	root.Description = contract.Description
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	out, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+type generator struct {
+	defs  map[string]*node
+	names map[reflect.Type]string
+	taken map[string]reflect.Type
+	root  reflect.Type
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *generator) schemaOf(t reflect.Type) *node {
+	switch {
+	case t == timeType:
+		return &node{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return &node{Type: "string", Format: "byte"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaOf(t.Elem())
+	case reflect.Bool:
+		return &node{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &node{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &node{Type: "number"}
+	case reflect.String:
+		return &node{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &node{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &node{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t == g.root {
+			return &node{Ref: "#"}
+		}
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &node{Ref: "#/$defs/" + g.define(t)}
+	default:
+		return &node{}
+	}
+}
+
+// define adds the named struct t to $defs once, under its type name, or
+// package and type name when two packages share a name.
+func (g *generator) define(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if other, ok := g.taken[name]; ok && other != t {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + t.Name()
+	}
+	g.names[t] = name
+	g.taken[name] = t
+	g.defs[name] = &node{}
+	*g.defs[name] = *g.structSchema(t)
+	return name
+}
+
+func (g *generator) structSchema(t reflect.Type) *node {
+	schema := &node{Type: "object", Properties: &properties{}}
+	g.addFields(schema, t)
+	return schema
+}
+
+func (g *generator) addFields(schema *node, t reflect.Type) {
+	for index := 0; index < t.NumField(); index++ {
+		field := t.Field(index)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		property := g.schemaOf(field.Type)
+		omitEmpty := strings.Contains(","+options+",", ",omitempty,")
+		if !omitEmpty {
+			schema.Required = append(schema.Required, name)
+			if nullable(field.Type) {
+				property = orNull(property)
+			}
+		}
+		schema.Properties.set(name, property)
+	}
+}
+
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Map:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+// orNull widens schema to also accept null.
+func orNull(schema *node) *node {
+	if kind, ok := schema.Type.(string); ok && schema.Ref == "" {
+		widened := *schema
+		widened.Type = []string{kind, "null"}
+		return &widened
+	}
+	return &node{AnyOf: []*node{schema, {Type: "null"}}}
+}
@@ -0,0 +1,128 @@
+package contracts
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sampleBase struct {
+	ID string `json:"id"`
+}
+
+type sampleChild struct {
+	Name     string         `json:"name"`
+	Children []*sampleChild `json:"children,omitempty"`
+}
+
+type sample struct {
+	sampleBase
+	Count    int               `json:"count"`
+	Ratio    float64           `json:"ratio,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Child    *sampleChild      `json:"child"`
+	At       time.Time         `json:"at"`
+	Anything any               `json:"anything"`
+	Skipped  string            `json:"-"`
+	Kind     string            `json:"kind"`
+	internal string
+}
+
+func TestGenerateFollowsEncodingJSON(t *testing.T) {
+	t.Parallel()
+
+	raw, err := Generate(Contract{Name: "sample", Title: "Sample", Value: sample{}, Const: map[string]any{"kind": "sample"}})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	schema := map[string]any{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if schema["$id"] != IDBase+"sample.schema.json" || schema["title"] != "Sample" {
+		t.Fatalf("unexpected header: %v %v", schema["$id"], schema["title"])
+	}
+	if got := strings.Join(toStrings(schema["required"]), ","); got != "id,count,tags,child,at,anything,kind" {
+		t.Fatalf("required = %s", got)
+	}
+	properties := schema["properties"].(map[string]any)
+	for name, want := range map[string]string{
+		"id":       `{"type":"string"}`,
+		"ratio":    `{"type":"number"}`,
+		"tags":     `{"type":["array","null"],"items":{"type":"string"}}`,
+		"labels":   `{"type":"object","additionalProperties":{"type":"string"}}`,
+		"child":    `{"anyOf":[{"$ref":"#/$defs/sampleChild"},{"type":"null"}]}`,
+		"at":       `{"type":"string","format":"date-time"}`,
+		"anything": `{}`,
+		"kind":     `{"type":"string","const":"sample"}`,
+	} {
+		got, _ := json.Marshal(properties[name])
+		if string(got) != canonical(t, want) {
+			t.Fatalf("%s = %s, want %s", name, got, want)
+		}
+	}
+	for _, name := range []string{"Skipped", "internal", "sampleBase"} {
+		if _, ok := properties[name]; ok {
+			t.Fatalf("unexpected property %s", name)
+		}
+	}
+	child := schema["$defs"].(map[string]any)["sampleChild"].(map[string]any)
+	children, _ := json.Marshal(child["properties"].(map[string]any)["children"])
+	if string(children) != canonical(t, `{"type":"array","items":{"$ref":"#/$defs/sampleChild"}}`) {
+		t.Fatalf("recursive children = %s", children)
+	}
+	if !strings.Contains(string(raw), "\"id\": {\n      \"type\": \"string\"\n    },\n    \"count\"") {
+		t.Fatalf("properties are not in field order:\n%s", raw)
+	}
+}
+
+func TestGenerateRejectsUnknownConst(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Generate(Contract{Name: "sample", Value: sample{}, Const: map[string]any{"missing": 1}}); err == nil || !strings.Contains(err.Error(), `no property "missing"`) {
+		t.Fatalf("expected unknown const error, got %v", err)
+	}
+}
+
+func TestPublishedSchemasAreValidJSON(t *testing.T) {
+	t.Parallel()
+
+	names := Names()
+	if len(names) == 0 {
+		t.Fatal("expected published schemas")
+	}
+	for _, name := range names {
+		raw, err := Schema(name)
+		if err != nil {
+			t.Fatalf("schema %s: %v", name, err)
+		}
+		if !json.Valid(raw) {
+			t.Fatalf("schema %s is not valid JSON", name)
+		}
+	}
+	if _, err := Schema("nope"); err == nil || !strings.Contains(err.Error(), "envelope") {
+		t.Fatalf("expected unknown contract error listing names, got %v", err)
+	}
+}
+
+func toStrings(value any) []string {
+	out := []string{}
+	for _, item := range value.([]any) {
+		out = append(out, item.(string))
+	}
+	return out
+}
+
+// canonical re-encodes raw with sorted keys, as decoded schemas are.
+func canonical(t *testing.T, raw string) string {
+	t.Helper()
+
+	var value any
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		t.Fatalf("decode %s: %v", raw, err)
+	}
+	out, _ := json.Marshal(value)
+	return string(out)
+}
@@ -0,0 +1,258 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError lists every place a document breaks its contract.
+type ValidationError struct {
+	Contract   string
+	Violations []Violation
+}
+
+// Violation is one broken rule, at a JSON Pointer fragment such as
+// #/error/class into the document.
+type Violation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		lines = append(lines, violation.Path+": "+violation.Message)
+	}
+	return fmt.Sprintf("document does not match contract %s: %s", e.Contract, strings.Join(lines, "; "))
+}
+
+// Validate checks document against the published schema called name. It
+// understands the keywords Generate writes: type, format, const, properties,
+// required, items, additionalProperties, anyOf and local $ref.
+func Validate(name string, document []byte) error {
+	raw, err := Schema(name)
+	if err != nil {
+		return err
+	}
+	root := &node{}
+	if err := json.Unmarshal(raw, root); err != nil {
+		return fmt.Errorf("decode contract %s: %w", name, err)
+	}
+	var value any
+	if err := json.Unmarshal(document, &value); err != nil {
+		return fmt.Errorf("decode document for contract %s: %w", name, err)
+	}
+	v := &validator{root: root}
+	v.check(root, value, "")
+	if len(v.violations) > 0 {
+		return &ValidationError{Contract: name, Violations: v.violations}
+	}
+	return nil
+}
+
+// UnmarshalJSON reads properties back in document order.
+func (p *properties) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		schema := &node{}
+		if err := decoder.Decode(schema); err != nil {
+			return err
+		}
+		p.set(token.(string), schema)
+	}
+	_, err := decoder.Token()
+	return err
+}
+
+type validator struct {
+	root       *node
+	violations []Violation
+}
+
+func (v *validator) fail(path string, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Path: "#" + path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) check(schema *node, value any, path string) {
+	if schema.Ref != "" {
+		target, ok := v.resolve(schema.Ref)
+		if !ok {
+			v.fail(path, "unresolvable $ref %s", schema.Ref)
+			return
+		}
+		v.check(target, value, path)
+		return
+	}
+	if len(schema.AnyOf) > 0 && !v.matchesAny(schema.AnyOf, value, path) {
+		v.fail(path, "matches none of the allowed schemas")
+		return
+	}
+	if schema.Type != nil {
+		kinds := typeNames(schema.Type)
+		if !hasKind(kinds, value) {
+			v.fail(path, "expected %s, got %s", strings.Join(kinds, " or "), kindOf(value))
+			return
+		}
+	}
+	if schema.Const != nil && !reflect.DeepEqual(normalize(schema.Const), value) {
+		v.fail(path, "expected %v, got %v", schema.Const, value)
+	}
+	if text, ok := value.(string); ok && schema.Format != "" && !validFormat(schema.Format, text) {
+		v.fail(path, "%q is not a valid %s", text, schema.Format)
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		v.checkObject(schema, typed, path)
+	case []any:
+		if schema.Items != nil {
+			for index, item := range typed {
+				v.check(schema.Items, item, path+"/"+strconv.Itoa(index))
+			}
+		}
+	}
+}
+
+func (v *validator) checkObject(schema *node, object map[string]any, path string) {
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			v.fail(path, "missing required property %q", name)
+		}
+	}
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		child := path + "/" + escapePointer(name)
+		if schema.Properties != nil {
+			if property, ok := schema.Properties.schemas[name]; ok {
+				v.check(property, object[name], child)
+				continue
+			}
+		}
+		if schema.AdditionalProperties != nil {
+			v.check(schema.AdditionalProperties, object[name], child)
+		}
+	}
+}
+
+// matchesAny reports whether value matches one of options without recording
+// the violations of the options it does not match.
+func (v *validator) matchesAny(options []*node, value any, path string) bool {
+	for _, option := range options {
+		trial := &validator{root: v.root}
+		trial.check(option, value, path)
+		if len(trial.violations) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) resolve(ref string) (*node, bool) {
+	if ref == "#" {
+		return v.root, true
+	}
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, false
+	}
+	target, ok := v.root.Defs[name]
+	return target, ok
+}
+
+func typeNames(value any) []string {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}
+	case []any:
+		kinds := make([]string, 0, len(typed))
+		for _, kind := range typed {
+			if name, ok := kind.(string); ok {
+				kinds = append(kinds, name)
+			}
+		}
+		return kinds
+	case []string:
+		return typed
+	}
+	return nil
+}
+
+func hasKind(kinds []string, value any) bool {
+	actual := kindOf(value)
+	for _, kind := range kinds {
+		if kind == actual || (kind == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func kindOf(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if typed == math.Trunc(typed) && !math.IsInf(typed, 0) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// normalize round-trips a const through JSON so it compares equal to the
+// decoded document value.
+func normalize(value any) any {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var out any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return value
+	}
+	return out
+}
+
+func validFormat(format string, text string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, text)
+		return err == nil
+	case "byte":
+		_, err := base64.StdEncoding.DecodeString(text)
+		return err == nil
+	}
+	return true
+}
+
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
@@ -0,0 +1,59 @@
+package contracts
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateAcceptsConformingEnvelopes(t *testing.T) {
+	t.Parallel()
+
+	for _, document := range []string{
+		`{"contract_version":"1.0","command":"meta campaign pause","timestamp":"2026-10-16T09:00:00Z","request_id":"r1","success":true,"data":{"id":"1"}}`,
+		`{"contract_version":"1.0","command":"meta campaign pause","timestamp":"2026-10-16T09:00:00Z","request_id":"r1","success":false,"error":{"type":"OAuthException","class":"auth","message":"expired","code":190,"error_subcode":463,"retryable":false}}`,
+	} {
+		if err := Validate("envelope", []byte(document)); err != nil {
+			t.Fatalf("validate %s: %v", document, err)
+		}
+	}
+}
+
+func TestValidateReportsEveryViolation(t *testing.T) {
+	t.Parallel()
+
+	err := Validate("envelope", []byte(`{"contract_version":"2.0","command":7,"timestamp":"now","success":"yes","error":{"type":"x","message":"m","code":1.5}}`))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	got := map[string]bool{}
+	for _, violation := range validationErr.Violations {
+		got[violation.Path+": "+violation.Message] = true
+	}
+	for _, want := range []string{
+		`#: missing required property "request_id"`,
+		`#/contract_version: expected 1.0, got 2.0`,
+		`#/command: expected string, got integer`,
+		`#/success: expected boolean, got string`,
+		`#/error/code: expected integer, got number`,
+	} {
+		if !got[want] {
+			t.Fatalf("missing violation %q in %v", want, validationErr.Violations)
+		}
+	}
+	if !strings.HasPrefix(err.Error(), "document does not match contract envelope: ") {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+}
+
+func TestValidateRejectsUnknownContractsAndInvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	if err := Validate("nope", []byte(`{}`)); err == nil || !strings.Contains(err.Error(), `unknown contract "nope"`) {
+		t.Fatalf("expected unknown contract error, got %v", err)
+	}
+	if err := Validate("envelope", []byte(`{`)); err == nil || !strings.Contains(err.Error(), "decode document") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package crosspost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	PlatformIG   = "ig"
+	PlatformPage = "page"
+
+	StatusValid     = "valid"
+	StatusInvalid   = "invalid"
+	StatusPublished = "published"
+	StatusFailed    = "failed"
+
+	errorTypeValidation     = "crosspost_validation_error"
+	errorCodeValidation     = 422400
+	errorTypePartialFailure = "crosspost_partial_failure"
+	errorCodePartialFailure = 424400
+)
+
+var supportedDestinations = []string{"ig:feed", "ig:reel", "ig:story", "page:feed"}
+
+type Destination struct {
+	Platform string `json:"platform"`
+	Surface  string `json:"surface"`
+}
+
+func (d Destination) String() string {
+	return d.Platform + ":" + d.Surface
+}
+
+// Target binds one destination to its validation and publish steps. Validate must not
+// call the Graph API; it runs for every destination before anything is published.
+type Target struct {
+	Destination Destination
+	Validate    func() error
+	Publish     func(ctx context.Context) (any, error)
+}
+
+type DestinationResult struct {
+	Destination string `json:"destination"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+	Result      any    `json:"result,omitempty"`
+}
+
+type Result struct {
+	Destinations []string            `json:"destinations"`
+	Published    int                 `json:"published"`
+	Failed       int                 `json:"failed"`
+	Results      []DestinationResult `json:"results"`
+}
+
+func ParseDestinations(values []string) ([]Destination, error) {
+	destinations := make([]Destination, 0, len(values))
+	seen := map[string]bool{}
+	for _, value := range values {
+		normalized := strings.ToLower(strings.TrimSpace(value))
+		if normalized == "" {
+			continue
+		}
+		supported := false
+		for _, candidate := range supportedDestinations {
+			if candidate == normalized {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return nil, fmt.Errorf("unsupported destination %q: expected %s", value, strings.Join(supportedDestinations, "|"))
+		}
+		if seen[normalized] {
+			return nil, fmt.Errorf("duplicate destination %q", normalized)
+		}
+		seen[normalized] = true
+		platform, surface, _ := strings.Cut(normalized, ":")
+		destinations = append(destinations, Destination{Platform: platform, Surface: surface})
+	}
+	if len(destinations) == 0 {
+		return nil, errors.New("at least one destination is required (--destinations)")
+	}
+	return destinations, nil
+}
+
+// Run validates every target first and refuses to publish anywhere when any
+// destination is invalid. Publishing then continues past individual failures so the
+// result reports exactly which destinations received the content.
+func Run(ctx context.Context, targets []Target) (*Result, error) {
+	if len(targets) == 0 {
+		return nil, errors.New("at least one crosspost target is required")
+	}
+
+	result := &Result{
+		Destinations: make([]string, 0, len(targets)),
+		Results:      make([]DestinationResult, 0, len(targets)),
+	}
+	invalid := 0
+	for _, target := range targets {
+		entry := DestinationResult{
+			Destination: target.Destination.String(),
+			Status:      StatusValid,
+		}
+		if target.Validate != nil {
+			if err := target.Validate(); err != nil {
+				entry.Status = StatusInvalid
+				entry.Error = err.Error()
+				invalid++
+			}
+		}
+		result.Destinations = append(result.Destinations, entry.Destination)
+		result.Results = append(result.Results, entry)
+	}
+	if invalid > 0 {
+		return result, &graph.APIError{
+			Type:      errorTypeValidation,
+			Code:      errorCodeValidation,
+			Message:   fmt.Sprintf("crosspost has %d invalid destination(s) of %d; nothing was published", invalid, len(targets)),
+			Retryable: false,
+			Diagnostics: map[string]any{
+				"results": result.Results,
+			},
+			Remediation: &graph.Remediation{
+				Category: graph.RemediationCategoryValidation,
+				Summary:  "One or more destinations rejected the content before publishing.",
+				Actions: []string{
+					"Fix or drop the destinations listed in diagnostics.results and rerun.",
+				},
+			},
+		}
+	}
+
+	for index, target := range targets {
+		entry := &result.Results[index]
+		published, err := target.Publish(ctx)
+		if err != nil {
+			entry.Status = StatusFailed
+			entry.Error = err.Error()
+			result.Failed++
+			continue
+		}
+		entry.Status = StatusPublished
+		entry.Result = published
+		result.Published++
+	}
+
+	if result.Failed > 0 {
+		return result, &graph.APIError{
+			Type:      errorTypePartialFailure,
+			Code:      errorCodePartialFailure,
+			Message:   fmt.Sprintf("crosspost published to %d of %d destination(s); %d failed", result.Published, len(targets), result.Failed),
+			Retryable: false,
+			Diagnostics: map[string]any{
+				"published": result.Published,
+				"failed":    result.Failed,
+				"results":   result.Results,
+			},
+			Remediation: &graph.Remediation{
+				Category: graph.RemediationCategoryUnknown,
+				Summary:  "Content reached some destinations but not others.",
+				Actions: []string{
+					"Inspect diagnostics.results for the failed destinations.",
+					"Rerun with --destinations limited to the failed destinations to avoid duplicate posts.",
+				},
+			},
+		}
+	}
+	return result, nil
+}
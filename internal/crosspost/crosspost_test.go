@@ -0,0 +1,80 @@
+package crosspost
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestParseDestinations(t *testing.T) {
+	t.Parallel()
+
+	destinations, err := ParseDestinations([]string{" IG:Feed ", "page:feed", ""})
+	if err != nil {
+		t.Fatalf("parse destinations: %v", err)
+	}
+	if len(destinations) != 2 || destinations[0].String() != "ig:feed" || destinations[1].String() != "page:feed" {
+		t.Fatalf("unexpected destinations %#v", destinations)
+	}
+
+	for name, values := range map[string][]string{
+		"empty":       nil,
+		"unsupported": {"page:reel"},
+		"duplicate":   {"ig:feed", "IG:FEED"},
+	} {
+		if _, err := ParseDestinations(values); err == nil {
+			t.Fatalf("expected %s destinations to be rejected", name)
+		}
+	}
+}
+
+func TestRunBlocksPublishingWhenAnyDestinationIsInvalid(t *testing.T) {
+	t.Parallel()
+
+	published := 0
+	publish := func(context.Context) (any, error) {
+		published++
+		return nil, nil
+	}
+	result, err := Run(context.Background(), []Target{
+		{Destination: Destination{Platform: PlatformIG, Surface: "feed"}, Validate: func() error { return nil }, Publish: publish},
+		{Destination: Destination{Platform: PlatformPage, Surface: "feed"}, Validate: func() error { return errors.New("page token required") }, Publish: publish},
+	})
+
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != errorTypeValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if published != 0 {
+		t.Fatalf("expected no publishes, got %d", published)
+	}
+	if result.Results[0].Status != StatusValid || result.Results[1].Status != StatusInvalid || result.Results[1].Error != "page token required" {
+		t.Fatalf("unexpected results %#v", result.Results)
+	}
+}
+
+func TestRunContinuesPastPublishFailures(t *testing.T) {
+	t.Parallel()
+
+	result, err := Run(context.Background(), []Target{
+		{Destination: Destination{Platform: PlatformIG, Surface: "feed"}, Publish: func(context.Context) (any, error) {
+			return nil, errors.New("rate limited")
+		}},
+		{Destination: Destination{Platform: PlatformPage, Surface: "feed"}, Publish: func(context.Context) (any, error) {
+			return map[string]any{"id": "post_1"}, nil
+		}},
+	})
+
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != errorTypePartialFailure {
+		t.Fatalf("expected partial failure error, got %v", err)
+	}
+	if result.Published != 1 || result.Failed != 1 {
+		t.Fatalf("unexpected counts %#v", result)
+	}
+	if result.Results[0].Status != StatusFailed || result.Results[1].Status != StatusPublished {
+		t.Fatalf("unexpected results %#v", result.Results)
+	}
+}
@@ -0,0 +1,260 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/marketing"
+)
+
+const (
+	StatusApplied        = "applied"
+	StatusUnchanged      = "unchanged"
+	StatusFailed         = "failed"
+	StatusSkipped        = "skipped"
+	StatusRolledBack     = "rolled_back"
+	StatusRollbackFailed = "rollback_failed"
+
+	errorTypeApplyFailed = "declarative_apply_failed"
+	errorCodeApplyFailed = 424410
+)
+
+type StepResult struct {
+	Key           string `json:"key"`
+	Kind          string `json:"kind"`
+	Name          string `json:"name"`
+	Action        string `json:"action"`
+	ID            string `json:"id,omitempty"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
+	RollbackError string `json:"rollback_error,omitempty"`
+}
+
+type ApplyResult struct {
+	AccountID string       `json:"account_id"`
+	Summary   PlanSummary  `json:"summary"`
+	Results   []StepResult `json:"results"`
+}
+
+// Apply executes the plan in order. Graph has no multi-object transactions, so a
+// failure rolls back what this run changed: created objects are deleted and updated
+// fields are restored to their planned-from values, newest first.
+func (s *Service) Apply(ctx context.Context, version string, token string, appSecret string, plan *Plan) (*ApplyResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("declarative service client is required")
+	}
+	if plan == nil {
+		return nil, errors.New("declarative plan is required")
+	}
+	accountID, err := normalizeAccountID(plan.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	executor := stepExecutor{
+		client:    s.Client,
+		version:   strings.TrimSpace(version),
+		token:     token,
+		appSecret: appSecret,
+		accountID: accountID,
+		ids:       map[string]string{},
+	}
+	result := &ApplyResult{AccountID: plan.AccountID, Summary: plan.Summary, Results: make([]StepResult, 0, len(plan.Steps))}
+
+	failedAt := -1
+	var failure error
+	for index, step := range plan.Steps {
+		entry := StepResult{Key: step.Key, Kind: step.Kind, Name: step.Name, Action: step.Action, ID: step.ID}
+		if failedAt >= 0 {
+			entry.Status = StatusSkipped
+			result.Results = append(result.Results, entry)
+			continue
+		}
+		id, err := executor.run(ctx, step)
+		if err != nil {
+			entry.Status = StatusFailed
+			entry.Error = err.Error()
+			failedAt = index
+			failure = err
+			result.Results = append(result.Results, entry)
+			continue
+		}
+		entry.ID = id
+		entry.Status = StatusApplied
+		if step.Action == ActionNoop {
+			entry.Status = StatusUnchanged
+		}
+		result.Results = append(result.Results, entry)
+	}
+	if failedAt < 0 {
+		return result, nil
+	}
+
+	rolledBack, rollbackFailed := 0, 0
+	for index := failedAt - 1; index >= 0; index-- {
+		entry := &result.Results[index]
+		if entry.Status != StatusApplied {
+			continue
+		}
+		if err := executor.rollback(ctx, plan.Steps[index], entry.ID); err != nil {
+			entry.Status = StatusRollbackFailed
+			entry.RollbackError = err.Error()
+			rollbackFailed++
+			continue
+		}
+		entry.Status = StatusRolledBack
+		rolledBack++
+	}
+
+	failed := plan.Steps[failedAt]
+	actions := []string{"Inspect diagnostics.results, fix the spec or account state, and rerun plan."}
+	if rollbackFailed > 0 {
+		actions = append(actions, "Steps with status rollback_failed were left changed; reconcile them manually before rerunning apply.")
+	}
+	return result, &graph.APIError{
+		Type:      errorTypeApplyFailed,
+		Code:      errorCodeApplyFailed,
+		Message:   fmt.Sprintf("apply failed at %s (%s): %v; rolled back %d step(s), %d rollback failure(s)", failed.Key, failed.Action, failure, rolledBack, rollbackFailed),
+		Retryable: false,
+		Diagnostics: map[string]any{
+			"failed_step":     failed.Key,
+			"rolled_back":     rolledBack,
+			"rollback_failed": rollbackFailed,
+			"results":         result.Results,
+		},
+		Remediation: &graph.Remediation{
+			Category: graph.RemediationCategoryUnknown,
+			Summary:  "The plan could not be fully applied and completed steps were rolled back.",
+			Actions:  actions,
+		},
+	}
+}
+
+type stepExecutor struct {
+	client    *graph.Client
+	version   string
+	token     string
+	appSecret string
+	accountID string
+	ids       map[string]string
+}
+
+func (e *stepExecutor) run(ctx context.Context, step Step) (string, error) {
+	switch step.Action {
+	case ActionNoop:
+		e.ids[step.Key] = step.ID
+		return step.ID, nil
+	case ActionUpdate:
+		if err := e.update(ctx, step.Kind, step.ID, step.Params); err != nil {
+			return "", err
+		}
+		e.ids[step.Key] = step.ID
+		return step.ID, nil
+	case ActionCreate:
+		params := copyParams(step.Params)
+		if step.ParentKey != "" {
+			parentID := e.ids[step.ParentKey]
+			if parentID == "" {
+				return "", fmt.Errorf("parent %s has no id", step.ParentKey)
+			}
+			switch step.Kind {
+			case KindAdSet:
+				params["campaign_id"] = parentID
+			case KindAd:
+				params["adset_id"] = parentID
+			}
+		}
+		id, err := e.create(ctx, step.Kind, params)
+		if err != nil {
+			return "", err
+		}
+		e.ids[step.Key] = id
+		return id, nil
+	default:
+		return "", fmt.Errorf("unsupported plan action %q", step.Action)
+	}
+}
+
+func (e *stepExecutor) create(ctx context.Context, kind string, params map[string]string) (string, error) {
+	switch kind {
+	case KindCampaign:
+		result, err := marketing.NewCampaignService(e.client).Create(ctx, e.version, e.token, e.appSecret, marketing.CampaignCreateInput{AccountID: e.accountID, Params: params})
+		if err != nil {
+			return "", err
+		}
+		return result.CampaignID, nil
+	case KindAdSet:
+		result, err := marketing.NewAdSetService(e.client).Create(ctx, e.version, e.token, e.appSecret, marketing.AdSetCreateInput{AccountID: e.accountID, Params: params})
+		if err != nil {
+			return "", err
+		}
+		return result.AdSetID, nil
+	case KindAd:
+		result, err := marketing.NewAdService(e.client).Create(ctx, e.version, e.token, e.appSecret, marketing.AdCreateInput{AccountID: e.accountID, Params: params})
+		if err != nil {
+			return "", err
+		}
+		return result.AdID, nil
+	default:
+		return "", fmt.Errorf("unsupported object kind %q", kind)
+	}
+}
+
+func (e *stepExecutor) update(ctx context.Context, kind string, id string, params map[string]string) error {
+	var err error
+	switch kind {
+	case KindCampaign:
+		_, err = marketing.NewCampaignService(e.client).Update(ctx, e.version, e.token, e.appSecret, marketing.CampaignUpdateInput{CampaignID: id, Params: params})
+	case KindAdSet:
+		_, err = marketing.NewAdSetService(e.client).Update(ctx, e.version, e.token, e.appSecret, marketing.AdSetUpdateInput{AdSetID: id, Params: params})
+	case KindAd:
+		_, err = marketing.NewAdService(e.client).Update(ctx, e.version, e.token, e.appSecret, marketing.AdUpdateInput{AdID: id, Params: params})
+	default:
+		err = fmt.Errorf("unsupported object kind %q", kind)
+	}
+	return err
+}
+
+func (e *stepExecutor) rollback(ctx context.Context, step Step, id string) error {
+	switch step.Action {
+	case ActionCreate:
+		response, err := e.client.Do(ctx, graph.Request{
+			Method:      "DELETE",
+			Path:        id,
+			Version:     e.version,
+			AccessToken: e.token,
+			AppSecret:   e.appSecret,
+		})
+		if err != nil {
+			return fmt.Errorf("delete created %s %s: %w", step.Kind, id, err)
+		}
+		if success, ok := response.Body["success"].(bool); ok && !success {
+			return fmt.Errorf("delete created %s %s was not successful", step.Kind, id)
+		}
+		return nil
+	case ActionUpdate:
+		if len(step.previous) == 0 {
+			return fmt.Errorf("%s %s had no previous values to restore", step.Kind, id)
+		}
+		if err := e.update(ctx, step.Kind, id, step.previous); err != nil {
+			return fmt.Errorf("restore %s %s: %w", step.Kind, id, err)
+		}
+		if len(step.previous) < len(step.Params) {
+			return fmt.Errorf("%s %s restored partially: fields that were unset before apply cannot be cleared", step.Kind, id)
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+func copyParams(values map[string]string) map[string]string {
+	cloned := make(map[string]string, len(values)+1)
+	for key, value := range values {
+		cloned[key] = value
+	}
+	return cloned
+}
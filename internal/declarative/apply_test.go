@@ -0,0 +1,122 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestApplyCreatesChildrenUnderNewParents(t *testing.T) {
+	t.Parallel()
+
+	graphServer := newFakeGraph(t, map[string]any{
+		"POST /v25.0/act_123/campaigns": map[string]any{"id": "c_new"},
+		"POST /v25.0/act_123/adsets":    map[string]any{"id": "s_new"},
+		"POST /v25.0/s1":                map[string]any{"success": true},
+	})
+	plan := &Plan{
+		AccountID: "act_123",
+		Steps: []Step{
+			{Key: "campaign:Launch", Kind: KindCampaign, Name: "Launch", Action: ActionCreate, Params: map[string]string{"name": "Launch", "objective": "OUTCOME_SALES"}},
+			{Key: "campaign:Launch/adset:Prospecting", ParentKey: "campaign:Launch", Kind: KindAdSet, Name: "Prospecting", Action: ActionCreate, Params: map[string]string{"name": "Prospecting"}},
+			{Key: "campaign:Old", Kind: KindCampaign, Name: "Old", Action: ActionNoop, ID: "c0"},
+			{Key: "campaign:Old/adset:Live", ParentKey: "campaign:Old", Kind: KindAdSet, Name: "Live", Action: ActionUpdate, ID: "s1", Params: map[string]string{"daily_budget": "2000"}},
+		},
+		Summary: PlanSummary{Create: 2, Update: 1, Noop: 1},
+	}
+
+	result, err := New(graphServer.client()).Apply(context.Background(), "v25.0", "token", "", plan)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if got := graphServer.form("POST /v25.0/act_123/adsets").Get("campaign_id"); got != "c_new" {
+		t.Fatalf("expected new campaign id on ad set create, got %q", got)
+	}
+	if got := graphServer.form("POST /v25.0/s1").Get("daily_budget"); got != "2000" {
+		t.Fatalf("unexpected update form %v", graphServer.form("POST /v25.0/s1"))
+	}
+	statuses := make([]string, 0, len(result.Results))
+	for _, entry := range result.Results {
+		statuses = append(statuses, entry.ID+":"+entry.Status)
+	}
+	if strings.Join(statuses, ",") != "c_new:applied,s_new:applied,c0:unchanged,s1:applied" {
+		t.Fatalf("unexpected results %v", statuses)
+	}
+}
+
+func TestApplyRollsBackOnFailure(t *testing.T) {
+	t.Parallel()
+
+	graphServer := newFakeGraph(t, map[string]any{
+		"POST /v25.0/s1":             map[string]any{"success": true},
+		"POST /v25.0/act_123/adsets": map[string]any{"id": "s_new"},
+		"GET /v25.0/s_new":           map[string]any{"id": "s_new"},
+		"POST /v25.0/act_123/ads":    http.StatusBadRequest,
+		"DELETE /v25.0/s_new":        map[string]any{"success": true},
+	})
+	plan := &Plan{
+		AccountID: "act_123",
+		Steps: []Step{
+			{Key: "campaign:Old", Kind: KindCampaign, Name: "Old", Action: ActionNoop, ID: "c0"},
+			{Key: "campaign:Old/adset:Live", ParentKey: "campaign:Old", Kind: KindAdSet, Name: "Live", Action: ActionUpdate, ID: "s1", Params: map[string]string{"daily_budget": "2000"}, previous: map[string]string{"daily_budget": "1000"}},
+			{Key: "campaign:Old/adset:New", ParentKey: "campaign:Old", Kind: KindAdSet, Name: "New", Action: ActionCreate, Params: map[string]string{"name": "New"}},
+			{Key: "campaign:Old/adset:New/ad:Ad", ParentKey: "campaign:Old/adset:New", Kind: KindAd, Name: "Ad", Action: ActionCreate, Params: map[string]string{"name": "Ad"}},
+			{Key: "campaign:Later", Kind: KindCampaign, Name: "Later", Action: ActionCreate, Params: map[string]string{"name": "Later"}},
+		},
+		Summary: PlanSummary{Create: 3, Update: 1, Noop: 1},
+	}
+
+	result, err := New(graphServer.client()).Apply(context.Background(), "v25.0", "token", "", plan)
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != errorTypeApplyFailed {
+		t.Fatalf("expected apply failure, got %v", err)
+	}
+	if apiErr.Diagnostics["failed_step"] != "campaign:Old/adset:New/ad:Ad" || apiErr.Diagnostics["rolled_back"] != 2 {
+		t.Fatalf("unexpected diagnostics %#v", apiErr.Diagnostics)
+	}
+
+	statuses := make([]string, 0, len(result.Results))
+	for _, entry := range result.Results {
+		statuses = append(statuses, entry.Status)
+	}
+	if strings.Join(statuses, ",") != "unchanged,rolled_back,rolled_back,failed,skipped" {
+		t.Fatalf("unexpected statuses %v", statuses)
+	}
+	routes := graphServer.routes()
+	if routes[len(routes)-2] != "DELETE /v25.0/s_new" || routes[len(routes)-1] != "POST /v25.0/s1" {
+		t.Fatalf("expected rollback newest first, got %v", routes)
+	}
+	if got := graphServer.lastForm("POST /v25.0/s1").Get("daily_budget"); got != "1000" {
+		t.Fatalf("expected daily_budget restored to 1000, got %q", got)
+	}
+}
+
+func TestApplyReportsRollbackFailures(t *testing.T) {
+	t.Parallel()
+
+	graphServer := newFakeGraph(t, map[string]any{
+		"POST /v25.0/act_123/campaigns": map[string]any{"id": "c_new"},
+		"POST /v25.0/act_123/adsets":    http.StatusBadRequest,
+		"DELETE /v25.0/c_new":           http.StatusBadRequest,
+	})
+	plan := &Plan{
+		AccountID: "act_123",
+		Steps: []Step{
+			{Key: "campaign:Launch", Kind: KindCampaign, Name: "Launch", Action: ActionCreate, Params: map[string]string{"name": "Launch"}},
+			{Key: "campaign:Launch/adset:A", ParentKey: "campaign:Launch", Kind: KindAdSet, Name: "A", Action: ActionCreate, Params: map[string]string{"name": "A"}},
+		},
+	}
+
+	result, err := New(graphServer.client()).Apply(context.Background(), "v25.0", "token", "", plan)
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Diagnostics["rollback_failed"] != 1 {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.Results[0].Status != StatusRollbackFailed || result.Results[0].RollbackError == "" {
+		t.Fatalf("unexpected first result %#v", result.Results[0])
+	}
+}
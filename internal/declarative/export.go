@@ -0,0 +1,266 @@
+package declarative
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/checkpoint"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	DefaultExportCampaignFields = []string{
+		"objective", "status", "buying_type", "special_ad_categories", "bid_strategy",
+		"daily_budget", "lifetime_budget", "spend_cap",
+	}
+	DefaultExportAdSetFields = []string{
+		"status", "billing_event", "optimization_goal", "bid_strategy", "bid_amount",
+		"daily_budget", "lifetime_budget", "targeting", "promoted_object", "destination_type",
+		"start_time", "end_time",
+	}
+	DefaultExportAdFields = []string{
+		"status", "creative", "tracking_specs", "conversion_domain",
+	}
+
+	// systemFields are read-only or derived from the spec hierarchy and never exported.
+	systemFields = map[string]struct{}{
+		"id":                {},
+		"name":              {},
+		"account_id":        {},
+		"campaign_id":       {},
+		"adset_id":          {},
+		"effective_status":  {},
+		"configured_status": {},
+		"created_time":      {},
+		"updated_time":      {},
+		"issues_info":       {},
+		"recommendations":   {},
+	}
+)
+
+type ExportOptions struct {
+	AccountID      string
+	CampaignIDs    []string
+	CampaignFields []string
+	AdSetFields    []string
+	AdFields       []string
+	// Checkpoint records every exported campaign subtree; campaigns it already
+	// holds are not read again.
+	Checkpoint *checkpoint.Checkpoint
+}
+
+type ExportSummary struct {
+	Campaigns int `json:"campaigns"`
+	AdSets    int `json:"adsets"`
+	Ads       int `json:"ads"`
+}
+
+// Export snapshots live campaigns into a spec that plans as all noop against the
+// same account. Without campaign ids every campaign of the account is exported.
+func (s *Service) Export(ctx context.Context, version string, token string, appSecret string, options ExportOptions) (*Spec, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("declarative service client is required")
+	}
+	accountID, err := normalizeAccountID(options.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	campaignFields := exportFields(options.CampaignFields, DefaultExportCampaignFields)
+	adSetFields := exportFields(options.AdSetFields, DefaultExportAdSetFields)
+	adFields := exportFields(options.AdFields, DefaultExportAdFields)
+
+	reader := liveReader{client: s.Client, version: strings.TrimSpace(version), token: token, appSecret: appSecret}
+	campaigns, err := reader.exportCampaigns(ctx, accountID, options.CampaignIDs, campaignFields)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &Spec{SchemaVersion: SpecSchemaVersion, AccountID: "act_" + accountID, Campaigns: make([]CampaignSpec, 0, len(campaigns))}
+	for _, campaign := range campaigns {
+		campaignID, _ := campaign["id"].(string)
+		unit := "campaign/" + campaignID
+		var campaignSpec CampaignSpec
+		done, err := options.Checkpoint.Lookup(unit, &campaignSpec)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			spec.Campaigns = append(spec.Campaigns, campaignSpec)
+			continue
+		}
+		campaignSpec = CampaignSpec{Name: liveName(campaign), Params: exportParams(campaign)}
+
+		adSets, err := reader.list(ctx, campaignID+"/adsets", adSetFields)
+		if err != nil {
+			return nil, err
+		}
+		for _, adSet := range adSets {
+			adSetID, _ := adSet["id"].(string)
+			adSetSpec := AdSetSpec{Name: liveName(adSet), Params: exportParams(adSet)}
+
+			ads, err := reader.list(ctx, adSetID+"/ads", adFields)
+			if err != nil {
+				return nil, err
+			}
+			for _, ad := range ads {
+				adSetSpec.Ads = append(adSetSpec.Ads, AdSpec{Name: liveName(ad), Params: exportParams(ad)})
+			}
+			campaignSpec.AdSets = append(campaignSpec.AdSets, adSetSpec)
+		}
+		if err := options.Checkpoint.Complete(unit, campaignSpec); err != nil {
+			return nil, err
+		}
+		spec.Campaigns = append(spec.Campaigns, campaignSpec)
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("exported structure cannot be managed as a spec: %w", err)
+	}
+	return spec, nil
+}
+
+// Summary counts the objects of a spec.
+func (s *Spec) Summary() ExportSummary {
+	summary := ExportSummary{}
+	if s == nil {
+		return summary
+	}
+	for _, campaign := range s.Campaigns {
+		summary.Campaigns++
+		for _, adSet := range campaign.AdSets {
+			summary.AdSets++
+			summary.Ads += len(adSet.Ads)
+		}
+	}
+	return summary
+}
+
+// WriteSpec writes a spec as JSON for .json paths and YAML otherwise.
+func WriteSpec(path string, spec *Spec) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return errors.New("spec output path is required")
+	}
+	if spec == nil {
+		return errors.New("spec is required")
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err = json.MarshalIndent(spec, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(spec)
+	}
+	if err != nil {
+		return fmt.Errorf("encode spec: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write spec %s: %w", path, err)
+	}
+	return nil
+}
+
+func (r liveReader) exportCampaigns(ctx context.Context, accountID string, campaignIDs []string, fields []string) ([]map[string]any, error) {
+	if len(campaignIDs) == 0 {
+		return r.list(ctx, "act_"+accountID+"/campaigns", fields)
+	}
+	campaigns := make([]map[string]any, 0, len(campaignIDs))
+	for _, raw := range campaignIDs {
+		campaignID := strings.TrimSpace(raw)
+		if campaignID == "" || strings.Contains(campaignID, "/") {
+			return nil, fmt.Errorf("invalid campaign id %q: expected single graph id token", raw)
+		}
+		response, err := r.client.Do(ctx, graph.Request{
+			Method:      "GET",
+			Path:        campaignID,
+			Version:     r.version,
+			Query:       map[string]string{"fields": strings.Join(append([]string{"id", "name", "account_id"}, fields...), ",")},
+			AccessToken: r.token,
+			AppSecret:   r.appSecret,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("read campaign %s: %w", campaignID, err)
+		}
+		if owner, _ := response.Body["account_id"].(string); owner != "" && owner != accountID {
+			return nil, fmt.Errorf("campaign %s belongs to act_%s, not act_%s", campaignID, owner, accountID)
+		}
+		campaigns = append(campaigns, response.Body)
+	}
+	return campaigns, nil
+}
+
+func (r liveReader) list(ctx context.Context, path string, fields []string) ([]map[string]any, error) {
+	items := make([]map[string]any, 0)
+	_, err := r.client.FetchWithPagination(ctx, graph.Request{
+		Method:      "GET",
+		Path:        path,
+		Version:     r.version,
+		Query:       map[string]string{"fields": strings.Join(append([]string{"id", "name"}, fields...), ",")},
+		AccessToken: r.token,
+		AppSecret:   r.appSecret,
+	}, graph.PaginationOptions{FollowNext: true}, func(item map[string]any) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return items, nil
+}
+
+func exportParams(item map[string]any) map[string]any {
+	params := map[string]any{}
+	for key, value := range item {
+		if _, system := systemFields[key]; system {
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		if text, ok := value.(string); ok && strings.TrimSpace(text) == "" {
+			continue
+		}
+		if key == "creative" {
+			id := creativeID(value)
+			if id == "" {
+				continue
+			}
+			value = map[string]any{"creative_id": id}
+		}
+		params[key] = value
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	return params
+}
+
+func liveName(item map[string]any) string {
+	name, _ := item["name"].(string)
+	return strings.TrimSpace(name)
+}
+
+func exportFields(values []string, defaults []string) []string {
+	keys := newKeySet()
+	for _, value := range values {
+		field := strings.TrimSpace(value)
+		if field == "" {
+			continue
+		}
+		keys.add(map[string]any{field: nil})
+	}
+	if len(keys.values) == 0 {
+		return append([]string(nil), defaults...)
+	}
+	return keys.values
+}
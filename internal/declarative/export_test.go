@@ -0,0 +1,139 @@
+package declarative
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/checkpoint"
+)
+
+func exportTestResponses() map[string]any {
+	return map[string]any{
+		"GET /v25.0/act_123/campaigns": map[string]any{"data": []any{
+			map[string]any{"id": "c1", "name": "Launch", "objective": "OUTCOME_SALES", "status": "PAUSED", "special_ad_categories": []any{}, "daily_budget": ""},
+		}},
+		"GET /v25.0/c1/adsets": map[string]any{"data": []any{
+			map[string]any{"id": "s1", "name": "Prospecting", "campaign_id": "c1", "daily_budget": "1000", "targeting": map[string]any{"geo_locations": map[string]any{"countries": []any{"US"}}}},
+		}},
+		"GET /v25.0/s1/ads": map[string]any{"data": []any{
+			map[string]any{"id": "a1", "name": "Launch Ad", "status": "ACTIVE", "creative": map[string]any{"id": "cr1"}},
+		}},
+	}
+}
+
+func TestExportStripsSystemFields(t *testing.T) {
+	t.Parallel()
+
+	graphServer := newFakeGraph(t, exportTestResponses())
+	spec, err := New(graphServer.client()).Export(context.Background(), "v25.0", "token", "", ExportOptions{AccountID: "123"})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if spec.AccountID != "act_123" || spec.Summary() != (ExportSummary{Campaigns: 1, AdSets: 1, Ads: 1}) {
+		t.Fatalf("unexpected spec %#v", spec)
+	}
+	campaign := spec.Campaigns[0]
+	if _, ok := campaign.Params["daily_budget"]; ok {
+		t.Fatalf("expected empty values to be dropped: %#v", campaign.Params)
+	}
+	adSet := campaign.AdSets[0]
+	if _, ok := adSet.Params["campaign_id"]; ok {
+		t.Fatalf("expected campaign_id to be stripped: %#v", adSet.Params)
+	}
+	creative, _ := adSet.Ads[0].Params["creative"].(map[string]any)
+	if creative["creative_id"] != "cr1" {
+		t.Fatalf("expected creative in write shape, got %#v", adSet.Ads[0].Params["creative"])
+	}
+	if fields := graphServer.query("GET /v25.0/s1/ads").Get("fields"); fields != "id,name,"+strings.Join(DefaultExportAdFields, ",") {
+		t.Fatalf("unexpected ad fields %q", fields)
+	}
+}
+
+func TestExportResumesCheckpointedCampaigns(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "spec.checkpoint.json")
+	first, err := checkpoint.Open(path, "export", "act_123", false)
+	if err != nil {
+		t.Fatalf("open checkpoint: %v", err)
+	}
+	if _, err := New(newFakeGraph(t, exportTestResponses()).client()).Export(context.Background(), "v25.0", "token", "", ExportOptions{AccountID: "123", Checkpoint: first}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	resumed, err := checkpoint.Open(path, "export", "act_123", true)
+	if err != nil {
+		t.Fatalf("resume checkpoint: %v", err)
+	}
+	graphServer := newFakeGraph(t, exportTestResponses())
+	spec, err := New(graphServer.client()).Export(context.Background(), "v25.0", "token", "", ExportOptions{AccountID: "123", Checkpoint: resumed})
+	if err != nil {
+		t.Fatalf("resumed export: %v", err)
+	}
+	if graphServer.count("GET /v25.0/c1/adsets") != 0 || spec.Summary() != (ExportSummary{Campaigns: 1, AdSets: 1, Ads: 1}) {
+		t.Fatalf("expected the checkpointed campaign to be reused, got routes %v", graphServer.routes())
+	}
+}
+
+func TestExportedSpecPlansAsNoop(t *testing.T) {
+	t.Parallel()
+
+	graphServer := newFakeGraph(t, exportTestResponses())
+	service := New(graphServer.client())
+	spec, err := service.Export(context.Background(), "v25.0", "token", "", ExportOptions{AccountID: "act_123"})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "exported.yaml")
+	if err := WriteSpec(path, spec); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	loaded, err := LoadSpec(path)
+	if err != nil {
+		t.Fatalf("load exported spec: %v", err)
+	}
+	plan, err := service.Plan(context.Background(), "v25.0", "token", "", loaded)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if plan.HasChanges() {
+		t.Fatalf("expected noop plan, got %#v", plan.Steps)
+	}
+}
+
+func TestExportCampaignRejectsOtherAccount(t *testing.T) {
+	t.Parallel()
+
+	graphServer := newFakeGraph(t, map[string]any{
+		"GET /v25.0/c7": map[string]any{"id": "c7", "name": "Other", "account_id": "999"},
+	})
+	_, err := New(graphServer.client()).Export(context.Background(), "v25.0", "token", "", ExportOptions{AccountID: "123", CampaignIDs: []string{"c7"}})
+	if err == nil || !strings.Contains(err.Error(), "belongs to act_999") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestWriteSpecUsesExtension(t *testing.T) {
+	t.Parallel()
+
+	spec := &Spec{SchemaVersion: 1, AccountID: "act_1", Campaigns: []CampaignSpec{{Name: "Launch"}}}
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "spec.json")
+	if err := WriteSpec(jsonPath, spec); err != nil {
+		t.Fatalf("write json spec: %v", err)
+	}
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("read json spec: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "{\n  \"schema_version\": 1") {
+		t.Fatalf("unexpected json spec %s", data)
+	}
+	if _, err := LoadSpec(jsonPath); err != nil {
+		t.Fatalf("load json spec: %v", err)
+	}
+}
@@ -0,0 +1,382 @@
+package declarative
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionNoop   = "noop"
+)
+
+// Change is one differing param of an update step. From is the live value, or
+// nil when the field is not set on the live object.
+type Change struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    string `json:"to"`
+}
+
+// Step is the planned action for one spec object. Steps are ordered parents first,
+// so applying them in order always has a parent id available.
+type Step struct {
+	Key       string            `json:"key"`
+	ParentKey string            `json:"parent_key,omitempty"`
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Action    string            `json:"action"`
+	ID        string            `json:"id,omitempty"`
+	Params    map[string]string `json:"params,omitempty"`
+	Changes   []Change          `json:"changes,omitempty"`
+
+	// previous holds the encoded live values of changed fields for rollback.
+	previous map[string]string
+}
+
+type PlanSummary struct {
+	Create int `json:"create"`
+	Update int `json:"update"`
+	Noop   int `json:"noop"`
+}
+
+type Plan struct {
+	AccountID string      `json:"account_id"`
+	Steps     []Step      `json:"steps"`
+	Summary   PlanSummary `json:"summary"`
+}
+
+// HasChanges reports whether applying the plan would mutate anything.
+func (p *Plan) HasChanges() bool {
+	return p != nil && p.Summary.Create+p.Summary.Update > 0
+}
+
+type Service struct {
+	Client *graph.Client
+}
+
+func New(client *graph.Client) *Service {
+	if client == nil {
+		client = graph.NewClient(nil, "")
+	}
+	return &Service{Client: client}
+}
+
+// Plan diffs the spec against live state. Objects are matched by exact name within
+// their parent; only params present in the spec are compared, so fields the spec
+// does not mention are never touched.
+func (s *Service) Plan(ctx context.Context, version string, token string, appSecret string, spec *Spec) (*Plan, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("declarative service client is required")
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	accountID, err := normalizeAccountID(spec.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := liveReader{client: s.Client, version: strings.TrimSpace(version), token: token, appSecret: appSecret}
+	plan := &Plan{AccountID: "act_" + accountID, Steps: make([]Step, 0)}
+
+	liveCampaigns, err := reader.children(ctx, KindCampaign, "act_"+accountID+"/campaigns", campaignParamKeys(spec.Campaigns))
+	if err != nil {
+		return nil, err
+	}
+	for _, campaign := range spec.Campaigns {
+		campaignStep, err := planStep(KindCampaign, "", campaign.Name, campaign.Params, liveCampaigns)
+		if err != nil {
+			return nil, err
+		}
+		plan.add(campaignStep)
+
+		liveAdSets := map[string]map[string]any{}
+		if campaignStep.ID != "" && len(campaign.AdSets) > 0 {
+			liveAdSets, err = reader.children(ctx, KindAdSet, campaignStep.ID+"/adsets", adSetParamKeys(campaign.AdSets))
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, adSet := range campaign.AdSets {
+			adSetStep, err := planStep(KindAdSet, campaignStep.Key, adSet.Name, adSet.Params, liveAdSets)
+			if err != nil {
+				return nil, err
+			}
+			plan.add(adSetStep)
+
+			liveAds := map[string]map[string]any{}
+			if adSetStep.ID != "" && len(adSet.Ads) > 0 {
+				liveAds, err = reader.children(ctx, KindAd, adSetStep.ID+"/ads", adParamKeys(adSet.Ads))
+				if err != nil {
+					return nil, err
+				}
+			}
+			for _, ad := range adSet.Ads {
+				adStep, err := planStep(KindAd, adSetStep.Key, ad.Name, ad.Params, liveAds)
+				if err != nil {
+					return nil, err
+				}
+				plan.add(adStep)
+			}
+		}
+	}
+	return plan, nil
+}
+
+func (p *Plan) add(step Step) {
+	p.Steps = append(p.Steps, step)
+	switch step.Action {
+	case ActionCreate:
+		p.Summary.Create++
+	case ActionUpdate:
+		p.Summary.Update++
+	default:
+		p.Summary.Noop++
+	}
+}
+
+func planStep(kind string, parentKey string, name string, params map[string]any, live map[string]map[string]any) (Step, error) {
+	name = strings.TrimSpace(name)
+	key := kind + ":" + name
+	if parentKey != "" {
+		key = parentKey + "/" + key
+	}
+	step := Step{Key: key, ParentKey: parentKey, Kind: kind, Name: name}
+
+	current, exists := live[name]
+	if !exists {
+		form, err := encodeParams(name, params)
+		if err != nil {
+			return Step{}, fmt.Errorf("%s: %w", key, err)
+		}
+		step.Action = ActionCreate
+		step.Params = form
+		return step, nil
+	}
+
+	step.ID, _ = current["id"].(string)
+	for _, field := range sortedParamKeys(params) {
+		if valuesMatch(field, params[field], current[field]) {
+			continue
+		}
+		encoded, err := encodeParamValue(params[field])
+		if err != nil {
+			return Step{}, fmt.Errorf("%s: encode param %s: %w", key, field, err)
+		}
+		if step.Params == nil {
+			step.Params = map[string]string{}
+			step.previous = map[string]string{}
+		}
+		step.Params[field] = encoded
+		step.Changes = append(step.Changes, Change{Field: field, From: current[field], To: encoded})
+		if previous, ok := encodeLiveValue(field, current[field]); ok {
+			step.previous[field] = previous
+		}
+	}
+	step.Action = ActionNoop
+	if len(step.Changes) > 0 {
+		step.Action = ActionUpdate
+	}
+	return step, nil
+}
+
+type liveReader struct {
+	client    *graph.Client
+	version   string
+	token     string
+	appSecret string
+}
+
+// children lists the objects of an edge keyed by name. Duplicate live names make
+// matching ambiguous, so they fail the plan rather than guessing.
+func (r liveReader) children(ctx context.Context, kind string, path string, paramKeys []string) (map[string]map[string]any, error) {
+	fields := append([]string{"id", "name"}, paramKeys...)
+	byName := map[string]map[string]any{}
+	_, err := r.client.FetchWithPagination(ctx, graph.Request{
+		Method:      "GET",
+		Path:        path,
+		Version:     r.version,
+		Query:       map[string]string{"fields": strings.Join(fields, ",")},
+		AccessToken: r.token,
+		AppSecret:   r.appSecret,
+	}, graph.PaginationOptions{FollowNext: true}, func(item map[string]any) error {
+		name, _ := item["name"].(string)
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil
+		}
+		if existing, ok := byName[name]; ok {
+			return fmt.Errorf("ambiguous live %s name %q under %s: matches %v and %v; rename one before planning", kind, name, path, existing["id"], item["id"])
+		}
+		byName[name] = item
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read live %s state from %s: %w", kind, path, err)
+	}
+	return byName, nil
+}
+
+func campaignParamKeys(campaigns []CampaignSpec) []string {
+	keys := newKeySet()
+	for _, campaign := range campaigns {
+		keys.add(campaign.Params)
+	}
+	return keys.values
+}
+
+func adSetParamKeys(adSets []AdSetSpec) []string {
+	keys := newKeySet()
+	for _, adSet := range adSets {
+		keys.add(adSet.Params)
+	}
+	return keys.values
+}
+
+func adParamKeys(ads []AdSpec) []string {
+	keys := newKeySet()
+	for _, ad := range ads {
+		keys.add(ad.Params)
+	}
+	return keys.values
+}
+
+type keySet struct {
+	seen   map[string]struct{}
+	values []string
+}
+
+func newKeySet() *keySet {
+	return &keySet{seen: map[string]struct{}{}, values: make([]string, 0)}
+}
+
+func (k *keySet) add(params map[string]any) {
+	for _, key := range sortedParamKeys(params) {
+		if _, ok := k.seen[key]; ok {
+			continue
+		}
+		k.seen[key] = struct{}{}
+		k.values = append(k.values, key)
+	}
+}
+
+// valuesMatch compares a spec value to its live counterpart. JSON-encoded strings
+// are decoded first, and objects match when every desired key matches, because
+// Graph fills in defaults (for example inside targeting) on read.
+func valuesMatch(field string, desired any, live any) bool {
+	if live == nil {
+		return false
+	}
+	if field == "creative" {
+		return creativeID(desired) != "" && creativeID(desired) == creativeID(live)
+	}
+	return structurallyMatch(decodeJSONString(desired), decodeJSONString(live))
+}
+
+func structurallyMatch(desired any, live any) bool {
+	switch typed := desired.(type) {
+	case map[string]any:
+		liveMap, ok := live.(map[string]any)
+		if !ok {
+			return false
+		}
+		for key, value := range typed {
+			if !structurallyMatch(decodeJSONString(value), decodeJSONString(liveMap[key])) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		liveSlice, ok := live.([]any)
+		if !ok || len(liveSlice) != len(typed) {
+			return false
+		}
+		for i := range typed {
+			if !structurallyMatch(decodeJSONString(typed[i]), decodeJSONString(liveSlice[i])) {
+				return false
+			}
+		}
+		return true
+	default:
+		if live == nil {
+			return desired == nil
+		}
+		return scalarString(desired) == scalarString(live)
+	}
+}
+
+func decodeJSONString(value any) any {
+	text, ok := value.(string)
+	if !ok {
+		return value
+	}
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return value
+	}
+	var decoded any
+	if err := json.Unmarshal([]byte(trimmed), &decoded); err != nil {
+		return value
+	}
+	return decoded
+}
+
+func scalarString(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return strings.TrimSpace(typed)
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(typed)
+	case int64:
+		return strconv.FormatInt(typed, 10)
+	case bool:
+		return strconv.FormatBool(typed)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+// creativeID reads the creative id from either the write shape
+// ({"creative_id": ...}) or the read shape ({"id": ...}).
+func creativeID(value any) string {
+	decoded, ok := decodeJSONString(value).(map[string]any)
+	if !ok {
+		return ""
+	}
+	for _, key := range []string{"creative_id", "id"} {
+		if id, ok := decoded[key].(string); ok && strings.TrimSpace(id) != "" {
+			return strings.TrimSpace(id)
+		}
+	}
+	return ""
+}
+
+// encodeLiveValue renders a live value back into a writable form value so an
+// update can be reverted. Unset fields cannot be restored and report false.
+func encodeLiveValue(field string, value any) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+	if field == "creative" {
+		id := creativeID(value)
+		if id == "" {
+			return "", false
+		}
+		value = map[string]string{"creative_id": id}
+	}
+	encoded, err := encodeParamValue(value)
+	if err != nil {
+		return "", false
+	}
+	return encoded, true
+}
@@ -0,0 +1,241 @@
+package declarative
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestPlanDiffsSpecAgainstLiveState(t *testing.T) {
+	t.Parallel()
+
+	graphServer := newFakeGraph(t, map[string]any{
+		"GET /v25.0/act_123/campaigns": map[string]any{"data": []any{
+			map[string]any{"id": "c1", "name": "Launch", "objective": "OUTCOME_SALES", "special_ad_categories": []any{}},
+			map[string]any{"id": "c9", "name": "Unmanaged"},
+		}},
+		"GET /v25.0/c1/adsets": map[string]any{"data": []any{
+			map[string]any{"id": "s1", "name": "Prospecting", "daily_budget": "1000", "targeting": map[string]any{"geo_locations": map[string]any{"countries": []any{"US"}}, "age_min": float64(18)}},
+		}},
+		"GET /v25.0/s1/ads": map[string]any{"data": []any{
+			map[string]any{"id": "a1", "name": "Launch Ad", "creative": map[string]any{"id": "cr1"}, "status": "ACTIVE"},
+		}},
+	})
+
+	spec := &Spec{
+		SchemaVersion: 1,
+		AccountID:     "123",
+		Campaigns: []CampaignSpec{{
+			Name:   "Launch",
+			Params: map[string]any{"objective": "OUTCOME_SALES", "special_ad_categories": []any{}},
+			AdSets: []AdSetSpec{
+				{
+					Name:   "Prospecting",
+					Params: map[string]any{"daily_budget": 2000, "targeting": `{"geo_locations":{"countries":["US"]}}`},
+					Ads: []AdSpec{
+						{Name: "Launch Ad", Params: map[string]any{"creative": map[string]any{"creative_id": "cr1"}, "status": "ACTIVE"}},
+						{Name: "Second Ad", Params: map[string]any{"creative": map[string]any{"creative_id": "cr2"}}},
+					},
+				},
+				{Name: "Retargeting", Ads: []AdSpec{{Name: "Retargeting Ad"}}},
+			},
+		}},
+	}
+
+	plan, err := New(graphServer.client()).Plan(context.Background(), "v25.0", "token", "", spec)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+
+	got := make([]string, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		got = append(got, step.Key+"="+step.Action)
+	}
+	want := []string{
+		"campaign:Launch=noop",
+		"campaign:Launch/adset:Prospecting=update",
+		"campaign:Launch/adset:Prospecting/ad:Launch Ad=noop",
+		"campaign:Launch/adset:Prospecting/ad:Second Ad=create",
+		"campaign:Launch/adset:Retargeting=create",
+		"campaign:Launch/adset:Retargeting/ad:Retargeting Ad=create",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected plan steps:\n%s", strings.Join(got, "\n"))
+	}
+	if plan.Summary != (PlanSummary{Create: 3, Update: 1, Noop: 2}) || !plan.HasChanges() {
+		t.Fatalf("unexpected summary %#v", plan.Summary)
+	}
+
+	update := plan.Steps[1]
+	if len(update.Changes) != 1 || update.Changes[0].Field != "daily_budget" || update.Changes[0].From != "1000" || update.Changes[0].To != "2000" {
+		t.Fatalf("unexpected update changes %#v", update.Changes)
+	}
+	if update.previous["daily_budget"] != "1000" {
+		t.Fatalf("unexpected rollback values %#v", update.previous)
+	}
+	if plan.Steps[3].Params["name"] != "Second Ad" || plan.Steps[3].Params["creative"] != `{"creative_id":"cr2"}` {
+		t.Fatalf("unexpected create params %#v", plan.Steps[3].Params)
+	}
+	if graphServer.count("GET /v25.0/act_123/campaigns") != 1 {
+		t.Fatal("expected a single campaign read")
+	}
+	if fields := graphServer.query("GET /v25.0/c1/adsets").Get("fields"); fields != "id,name,daily_budget,targeting" {
+		t.Fatalf("unexpected adset read fields %q", fields)
+	}
+}
+
+func TestPlanFailsOnAmbiguousLiveNames(t *testing.T) {
+	t.Parallel()
+
+	graphServer := newFakeGraph(t, map[string]any{
+		"GET /v25.0/act_123/campaigns": map[string]any{"data": []any{
+			map[string]any{"id": "c1", "name": "Launch"},
+			map[string]any{"id": "c2", "name": "Launch"},
+		}},
+	})
+	spec := &Spec{SchemaVersion: 1, AccountID: "act_123", Campaigns: []CampaignSpec{{Name: "Launch"}}}
+
+	_, err := New(graphServer.client()).Plan(context.Background(), "v25.0", "token", "", spec)
+	if err == nil || !strings.Contains(err.Error(), `ambiguous live campaign name "Launch"`) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestValuesMatch(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		field   string
+		desired any
+		live    any
+		want    bool
+	}{
+		{"daily_budget", 1000, "1000", true},
+		{"daily_budget", "1000", float64(1000), true},
+		{"status", "PAUSED", "ACTIVE", false},
+		{"status", "PAUSED", nil, false},
+		{"bid_strategy", true, true, true},
+		{"targeting", map[string]any{"age_min": 18}, map[string]any{"age_min": float64(18), "age_max": float64(65)}, true},
+		{"targeting", `{"age_min":21}`, map[string]any{"age_min": float64(18)}, false},
+		{"special_ad_categories", []any{"HOUSING"}, []any{}, false},
+		{"creative", `{"creative_id":"1"}`, map[string]any{"id": "1"}, true},
+		{"creative", map[string]any{"creative_id": "1"}, map[string]any{"id": "2"}, false},
+	}
+	for _, tc := range cases {
+		if got := valuesMatch(tc.field, tc.desired, tc.live); got != tc.want {
+			t.Fatalf("valuesMatch(%s, %#v, %#v) = %v, want %v", tc.field, tc.desired, tc.live, got, tc.want)
+		}
+	}
+}
+
+// fakeGraph serves canned JSON by "METHOD /path" and records every request.
+type fakeGraph struct {
+	t         *testing.T
+	server    *httptest.Server
+	responses map[string]any
+
+	mu       sync.Mutex
+	requests []fakeGraphRequest
+}
+
+type fakeGraphRequest struct {
+	route string
+	query url.Values
+	form  url.Values
+}
+
+func newFakeGraph(t *testing.T, responses map[string]any) *fakeGraph {
+	t.Helper()
+	fake := &fakeGraph{t: t, responses: responses}
+	fake.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.Method + " " + r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, fakeGraphRequest{route: route, query: r.URL.Query(), form: form})
+		response, ok := fake.responses[route]
+		fake.mu.Unlock()
+
+		if !ok {
+			t.Errorf("unexpected request %s", route)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if status, ok := response.(int); ok {
+			w.WriteHeader(status)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"message": "boom", "type": "OAuthException", "code": 100}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(fake.server.Close)
+	return fake
+}
+
+func (f *fakeGraph) client() *graph.Client {
+	client := graph.NewClient(f.server.Client(), f.server.URL)
+	client.MaxRetries = 0
+	return client
+}
+
+func (f *fakeGraph) routes() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	routes := make([]string, 0, len(f.requests))
+	for _, request := range f.requests {
+		routes = append(routes, request.route)
+	}
+	return routes
+}
+
+func (f *fakeGraph) count(route string) int {
+	count := 0
+	for _, candidate := range f.routes() {
+		if candidate == route {
+			count++
+		}
+	}
+	return count
+}
+
+func (f *fakeGraph) query(route string) url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, request := range f.requests {
+		if request.route == route {
+			return request.query
+		}
+	}
+	return nil
+}
+
+func (f *fakeGraph) form(route string) url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, request := range f.requests {
+		if request.route == route {
+			return request.form
+		}
+	}
+	return nil
+}
+
+func (f *fakeGraph) lastForm(route string) url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for index := len(f.requests) - 1; index >= 0; index-- {
+		if f.requests[index].route == route {
+			return f.requests[index].form
+		}
+	}
+	return nil
+}
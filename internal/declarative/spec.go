@@ -0,0 +1,191 @@
+package declarative
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const SpecSchemaVersion = 1
+
+const (
+	KindCampaign = "campaign"
+	KindAdSet    = "adset"
+	KindAd       = "ad"
+)
+
+var ErrInvalidSpec = errors.New("invalid declarative spec")
+
+// parentReferenceParams are filled in from the spec hierarchy and may not be set by hand.
+var parentReferenceParams = map[string]struct{}{
+	"account_id":  {},
+	"campaign_id": {},
+	"adset_id":    {},
+}
+
+// Spec describes the desired campaign -> ad set -> ad tree of one ad account.
+// Objects are matched to live state by name within their parent.
+type Spec struct {
+	SchemaVersion int            `yaml:"schema_version" json:"schema_version"`
+	AccountID     string         `yaml:"account_id" json:"account_id"`
+	Campaigns     []CampaignSpec `yaml:"campaigns" json:"campaigns"`
+}
+
+type CampaignSpec struct {
+	Name   string         `yaml:"name" json:"name"`
+	Params map[string]any `yaml:"params,omitempty" json:"params,omitempty"`
+	AdSets []AdSetSpec    `yaml:"adsets,omitempty" json:"adsets,omitempty"`
+}
+
+type AdSetSpec struct {
+	Name   string         `yaml:"name" json:"name"`
+	Params map[string]any `yaml:"params,omitempty" json:"params,omitempty"`
+	Ads    []AdSpec       `yaml:"ads,omitempty" json:"ads,omitempty"`
+}
+
+type AdSpec struct {
+	Name   string         `yaml:"name" json:"name"`
+	Params map[string]any `yaml:"params,omitempty" json:"params,omitempty"`
+}
+
+// LoadSpec reads a YAML or JSON spec file. JSON is decoded through the YAML
+// decoder, so both formats share strict field checking.
+func LoadSpec(path string) (*Spec, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("%w: spec file path is required", ErrInvalidSpec)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read declarative spec %s: %w", path, err)
+	}
+
+	spec := &Spec{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(spec); err != nil {
+		return nil, fmt.Errorf("%w: decode %s: %v", ErrInvalidSpec, path, err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func (s *Spec) Validate() error {
+	if s == nil {
+		return fmt.Errorf("%w: spec is nil", ErrInvalidSpec)
+	}
+	if s.SchemaVersion != SpecSchemaVersion {
+		return fmt.Errorf("%w: unsupported schema_version=%d (expected %d)", ErrInvalidSpec, s.SchemaVersion, SpecSchemaVersion)
+	}
+	if _, err := normalizeAccountID(s.AccountID); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSpec, err)
+	}
+	if len(s.Campaigns) == 0 {
+		return fmt.Errorf("%w: at least one campaign is required", ErrInvalidSpec)
+	}
+
+	campaignNames := map[string]struct{}{}
+	for i, campaign := range s.Campaigns {
+		location := fmt.Sprintf("campaigns[%d]", i)
+		if err := validateObject(location, campaign.Name, campaign.Params, campaignNames); err != nil {
+			return err
+		}
+		adSetNames := map[string]struct{}{}
+		for j, adSet := range campaign.AdSets {
+			location := fmt.Sprintf("%s.adsets[%d]", location, j)
+			if err := validateObject(location, adSet.Name, adSet.Params, adSetNames); err != nil {
+				return err
+			}
+			adNames := map[string]struct{}{}
+			for k, ad := range adSet.Ads {
+				if err := validateObject(fmt.Sprintf("%s.ads[%d]", location, k), ad.Name, ad.Params, adNames); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateObject(location string, name string, params map[string]any, seen map[string]struct{}) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("%w: %s: name is required", ErrInvalidSpec, location)
+	}
+	if _, exists := seen[name]; exists {
+		return fmt.Errorf("%w: %s: duplicate name %q within parent", ErrInvalidSpec, location, name)
+	}
+	seen[name] = struct{}{}
+
+	for _, key := range sortedParamKeys(params) {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("%w: %s: param keys must not be empty", ErrInvalidSpec, location)
+		}
+		if key == "name" {
+			return fmt.Errorf("%w: %s: set name on the object instead of params.name", ErrInvalidSpec, location)
+		}
+		if _, reserved := parentReferenceParams[key]; reserved {
+			return fmt.Errorf("%w: %s: params.%s is derived from the spec hierarchy", ErrInvalidSpec, location, key)
+		}
+		if params[key] == nil {
+			return fmt.Errorf("%w: %s: params.%s must not be null", ErrInvalidSpec, location, key)
+		}
+	}
+	return nil
+}
+
+// encodeParams renders spec params as Graph form values: strings pass through,
+// everything else is JSON encoded.
+func encodeParams(name string, params map[string]any) (map[string]string, error) {
+	form := map[string]string{"name": strings.TrimSpace(name)}
+	for key, value := range params {
+		encoded, err := encodeParamValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("encode param %s: %w", key, err)
+		}
+		form[key] = encoded
+	}
+	return form, nil
+}
+
+func encodeParamValue(value any) (string, error) {
+	if typed, ok := value.(string); ok {
+		return typed, nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func sortedParamKeys(params map[string]any) []string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func normalizeAccountID(value string) (string, error) {
+	normalized := strings.TrimSpace(value)
+	if strings.HasPrefix(strings.ToLower(normalized), "act_") {
+		normalized = normalized[4:]
+	}
+	if normalized == "" {
+		return "", errors.New("account_id is required")
+	}
+	if strings.Contains(normalized, "/") {
+		return "", fmt.Errorf("invalid account_id %q: expected single graph id token", value)
+	}
+	return normalized, nil
+}
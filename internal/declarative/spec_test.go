@@ -0,0 +1,86 @@
+package declarative
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSpecReadsYAMLAndJSON(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "campaign.yaml")
+	if err := os.WriteFile(yamlPath, []byte(`schema_version: 1
+account_id: act_123
+campaigns:
+  - name: Launch
+    params:
+      objective: OUTCOME_SALES
+      special_ad_categories: []
+    adsets:
+      - name: Prospecting
+        params:
+          daily_budget: 1000
+        ads:
+          - name: Launch Ad
+            params:
+              creative: {creative_id: "c1"}
+`), 0o644); err != nil {
+		t.Fatalf("write yaml spec: %v", err)
+	}
+	spec, err := LoadSpec(yamlPath)
+	if err != nil {
+		t.Fatalf("load yaml spec: %v", err)
+	}
+	if len(spec.Campaigns) != 1 || len(spec.Campaigns[0].AdSets[0].Ads) != 1 {
+		t.Fatalf("unexpected spec %#v", spec)
+	}
+
+	jsonPath := filepath.Join(dir, "campaign.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"schema_version":1,"account_id":"123","campaigns":[{"name":"Launch","params":{"objective":"OUTCOME_SALES"}}]}`), 0o644); err != nil {
+		t.Fatalf("write json spec: %v", err)
+	}
+	if _, err := LoadSpec(jsonPath); err != nil {
+		t.Fatalf("load json spec: %v", err)
+	}
+}
+
+func TestLoadSpecRejectsUnknownFields(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "campaign.yaml")
+	if err := os.WriteFile(path, []byte("schema_version: 1\naccount_id: act_1\ncampaign: []\n"), 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	_, err := LoadSpec(path)
+	if !errors.Is(err, ErrInvalidSpec) {
+		t.Fatalf("expected ErrInvalidSpec, got %v", err)
+	}
+}
+
+func TestSpecValidate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		spec Spec
+		want string
+	}{
+		{"version", Spec{SchemaVersion: 2, AccountID: "1", Campaigns: []CampaignSpec{{Name: "a"}}}, "unsupported schema_version"},
+		{"account", Spec{SchemaVersion: 1, Campaigns: []CampaignSpec{{Name: "a"}}}, "account_id is required"},
+		{"campaigns", Spec{SchemaVersion: 1, AccountID: "1"}, "at least one campaign"},
+		{"duplicate", Spec{SchemaVersion: 1, AccountID: "1", Campaigns: []CampaignSpec{{Name: "a"}, {Name: "a"}}}, "duplicate name"},
+		{"name param", Spec{SchemaVersion: 1, AccountID: "1", Campaigns: []CampaignSpec{{Name: "a", Params: map[string]any{"name": "b"}}}}, "params.name"},
+		{"parent ref", Spec{SchemaVersion: 1, AccountID: "1", Campaigns: []CampaignSpec{{Name: "a", AdSets: []AdSetSpec{{Name: "s", Params: map[string]any{"campaign_id": "9"}}}}}}, "campaigns[0].adsets[0]: params.campaign_id is derived"},
+		{"ad name", Spec{SchemaVersion: 1, AccountID: "1", Campaigns: []CampaignSpec{{Name: "a", AdSets: []AdSetSpec{{Name: "s", Ads: []AdSpec{{}}}}}}}, "campaigns[0].adsets[0].ads[0]: name is required"},
+	}
+	for _, tc := range cases {
+		err := tc.spec.Validate()
+		if !errors.Is(err, ErrInvalidSpec) || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/bilalbayram/metacli/internal/filelock"
 	"gopkg.in/yaml.v3"
 )
 
@@ -87,6 +88,12 @@ func Save(path string, cfg *Config) error {
 	if err := cfg.Validate(); err != nil {
 		return err
 	}
+	lock, err := filelock.Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
 	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
 		return fmt.Errorf("create enterprise config directory for %s: %w", path, err)
 	}
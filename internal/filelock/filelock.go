@@ -0,0 +1,257 @@
+// Package filelock serializes writers of local state files across CLI
+// processes and the goroutines of one process. A lock is an advisory
+// "<path>.lock" file created exclusively and holding the owner's PID, so a
+// cron run and an interactive run cannot interleave a read-modify-write of
+// the same config or state file.
+package filelock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	retryInterval = 25 * time.Millisecond
+	// staleAge is how old a lock file must be before it is assumed to be left
+	// behind, even when its PID has been reused by a live process.
+	staleAge = 10 * time.Minute
+)
+
+// ErrLocked matches every *LockedError.
+var ErrLocked = errors.New("state locked")
+
+// LockedError reports a lock held by another process.
+type LockedError struct {
+	Path       string
+	PID        int
+	AcquiredAt string
+}
+
+func (e *LockedError) Error() string {
+	owner := "another process"
+	if e.PID > 0 {
+		owner = fmt.Sprintf("PID %d", e.PID)
+	}
+	return fmt.Sprintf("state locked by %s: %s is being written; retry or pass --wait-lock to wait for it", owner, e.Path)
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+type lockOwner struct {
+	PID        int    `json:"pid"`
+	AcquiredAt string `json:"acquired_at"`
+}
+
+// Lock is a held lock on one path. Release it exactly once; further calls do
+// nothing.
+type Lock struct {
+	state *lockState
+	once  sync.Once
+}
+
+// lockState is shared by a lock and the nested acquisitions made through it.
+type lockState struct {
+	path string
+	refs int
+	// done is closed when the last reference is released, waking goroutines
+	// of this process that wait for the path.
+	done chan struct{}
+}
+
+var (
+	mu   sync.Mutex
+	wait time.Duration
+	// held maps every path this process has locked to its lock. Another
+	// goroutine locking the same path waits for it instead of sharing it;
+	// sharing takes the handle, through Lock.Acquire.
+	held = map[string]*lockState{}
+)
+
+// SetWait sets how long Acquire waits for a lock held by another process
+// before failing. Zero fails immediately.
+func SetWait(d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	wait = d
+}
+
+// Acquire locks path. Other goroutines of this process holding path are
+// waited for until they release it; another process holding it is waited for
+// as long as SetWait allows. Acquire is not reentrant: a caller that already
+// holds path must lock it again through its handle with Lock.Acquire.
+func Acquire(path string) (*Lock, error) {
+	return AcquireWait(path, 0)
+}
+
+// AcquireWait is Acquire waiting at least minWait for another process, or
+// longer when SetWait asks for more.
+func AcquireWait(path string, minWait time.Duration) (*Lock, error) {
+	path, err := normalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &lockState{path: path, refs: 1, done: make(chan struct{})}
+	mu.Lock()
+	deadline := time.Now().Add(max(wait, minWait))
+	for {
+		holder, busy := held[path]
+		if !busy {
+			held[path] = state
+			break
+		}
+		mu.Unlock()
+		<-holder.done
+		mu.Lock()
+	}
+	mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		state.forget()
+		return nil, fmt.Errorf("create lock directory for %s: %w", path, err)
+	}
+	lockPath := path + ".lock"
+	for {
+		err := createLockFile(lockPath)
+		if err == nil {
+			return &Lock{state: state}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			state.forget()
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+
+		owner, ownerErr := readOwner(lockPath)
+		if ownerErr == nil && isStale(lockPath, owner) {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			state.forget()
+			return nil, &LockedError{Path: path, PID: owner.PID, AcquiredAt: owner.AcquiredAt}
+		}
+		time.Sleep(retryInterval)
+	}
+}
+
+// Acquire locks path for a caller that holds l, such as a saver called in the
+// middle of a read-modify-write. When l locks path the new handle shares it
+// and the lock file stays until both are released; any other path is locked
+// as with the package Acquire. A nil l is the package Acquire.
+func (l *Lock) Acquire(path string) (*Lock, error) {
+	if l == nil {
+		return Acquire(path)
+	}
+	normalized, err := normalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	if l.state.path == normalized && l.state.refs > 0 {
+		l.state.refs++
+		mu.Unlock()
+		return &Lock{state: l.state}, nil
+	}
+	mu.Unlock()
+	return Acquire(path)
+}
+
+// Release drops this handle. The lock file is removed when the last handle
+// sharing it is released.
+func (l *Lock) Release() {
+	if l == nil {
+		return
+	}
+	l.once.Do(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		l.state.refs--
+		if l.state.refs > 0 {
+			return
+		}
+		_ = os.Remove(l.state.path + ".lock")
+		delete(held, l.state.path)
+		close(l.state.done)
+	})
+}
+
+// forget gives up a path this process reserved but never locked on disk.
+func (s *lockState) forget() {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(held, s.path)
+	close(s.done)
+}
+
+func normalizePath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", errors.New("lock path is required")
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return path, nil
+}
+
+func createLockFile(lockPath string) error {
+	file, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(lockOwner{PID: os.Getpid(), AcquiredAt: time.Now().UTC().Format(time.RFC3339)})
+	if err == nil {
+		_, err = file.Write(payload)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(lockPath)
+		return fmt.Errorf("write lock file %s: %w", lockPath, err)
+	}
+	return nil
+}
+
+// readOwner returns the lock owner. A lock file that cannot be parsed yet is
+// reported with a zero PID; it may still be mid-write.
+func readOwner(lockPath string) (lockOwner, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return lockOwner{}, err
+	}
+	owner := lockOwner{}
+	_ = json.Unmarshal(data, &owner)
+	return owner, nil
+}
+
+func isStale(lockPath string, owner lockOwner) bool {
+	if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleAge {
+		return true
+	}
+	if owner.PID <= 0 || owner.PID == os.Getpid() {
+		return false
+	}
+	return !processAlive(owner.PID)
+}
+
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	return !errors.Is(err, os.ErrProcessDone) && !errors.Is(err, syscall.ESRCH)
+}
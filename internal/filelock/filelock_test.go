@@ -0,0 +1,123 @@
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeForeignLock(t *testing.T, path string, pid int) {
+	t.Helper()
+	payload := fmt.Sprintf(`{"pid":%d,"acquired_at":"2026-01-01T00:00:00Z"}`, pid)
+	if err := os.WriteFile(path+".lock", []byte(payload), 0o600); err != nil {
+		t.Fatalf("write lock file: %v", err)
+	}
+}
+
+func TestLockAcquireNestsThroughTheHandleAndRemovesLockOnRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	lock, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	nested, err := lock.Acquire(path)
+	if err != nil {
+		t.Fatalf("nested acquire: %v", err)
+	}
+	nested.Release()
+	nested.Release()
+	if _, err := os.Stat(path + ".lock"); err != nil {
+		t.Fatalf("expected lock to survive nested release: %v", err)
+	}
+	lock.Release()
+	if _, err := os.Stat(path + ".lock"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected lock file to be removed, got %v", err)
+	}
+
+	var none *Lock
+	unheld, err := none.Acquire(path)
+	if err != nil {
+		t.Fatalf("acquire through a nil handle: %v", err)
+	}
+	unheld.Release()
+}
+
+func TestAcquireMakesOtherGoroutinesWait(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.json")
+	SetWait(0)
+
+	lock, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	acquired := make(chan *Lock)
+	go func() {
+		second, err := Acquire(path)
+		if err != nil {
+			t.Errorf("second goroutine: %v", err)
+		}
+		acquired <- second
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second goroutine shared a lock it does not hold")
+	case <-time.After(100 * time.Millisecond):
+	}
+	lock.Release()
+	select {
+	case second := <-acquired:
+		if _, err := os.Stat(path + ".lock"); err != nil {
+			t.Fatalf("expected the second holder to own the lock file: %v", err)
+		}
+		second.Release()
+	case <-time.After(2 * time.Second):
+		t.Fatal("second goroutine was not woken by the release")
+	}
+}
+
+func TestAcquireReportsOwnerPIDAndWaits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	// The parent process is alive for the duration of the test.
+	writeForeignLock(t, path, os.Getppid())
+
+	SetWait(0)
+	_, err := Acquire(path)
+	var locked *LockedError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected locked error, got %v", err)
+	}
+	if locked.PID != os.Getppid() || !strings.Contains(err.Error(), fmt.Sprintf("state locked by PID %d", os.Getppid())) {
+		t.Fatalf("unexpected locked error: %v", err)
+	}
+
+	SetWait(2 * time.Second)
+	t.Cleanup(func() { SetWait(0) })
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = os.Remove(path + ".lock")
+	}()
+	lock, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("expected --wait-lock to outlast the holder, got %v", err)
+	}
+	lock.Release()
+}
+
+func TestAcquireBreaksLockOfDeadProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	// PIDs are capped well below this on every supported platform.
+	writeForeignLock(t, path, 1<<30)
+
+	SetWait(0)
+	lock, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("expected stale lock to be broken, got %v", err)
+	}
+	lock.Release()
+}
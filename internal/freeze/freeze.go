@@ -0,0 +1,268 @@
+// Package freeze blocks Graph mutations during configured freeze windows, such
+// as peak sales events. A window is a fixed date range or a recurring cron
+// schedule with a duration, optionally scoped to ad accounts or profile labels.
+package freeze
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ConfigSchemaVersion = 1
+
+	// MaxRecurringDuration bounds how far back a recurring window's start is
+	// searched for.
+	MaxRecurringDuration = 7 * 24 * time.Hour
+
+	errorTypeFrozen = "freeze_window_gate"
+	errorCodeFrozen = 423100
+)
+
+// Config is the freeze window file.
+type Config struct {
+	SchemaVersion int      `yaml:"schema_version"`
+	Windows       []Window `yaml:"windows"`
+}
+
+// Window is one freeze period. Set Start and End for a date range, or Cron and
+// Duration for a recurring window that opens at every cron match.
+type Window struct {
+	Name  string `yaml:"name"`
+	Start string `yaml:"start,omitempty"`
+	End   string `yaml:"end,omitempty"`
+	// Cron is a five-field schedule: minute hour day-of-month month
+	// day-of-week.
+	Cron     string `yaml:"cron,omitempty"`
+	Duration string `yaml:"duration,omitempty"`
+	// Timezone is an IANA zone for Cron; it defaults to UTC.
+	Timezone string `yaml:"timezone,omitempty"`
+	// Accounts lists ad account ids, with or without the act_ prefix.
+	Accounts []string `yaml:"accounts,omitempty"`
+	// Labels is a profile selector such as "env=prod".
+	Labels string `yaml:"labels,omitempty"`
+
+	start    time.Time
+	end      time.Time
+	schedule *schedule
+	duration time.Duration
+	location *time.Location
+	selector *config.Selector
+}
+
+// Active is a window that is open at the time of a check.
+type Active struct {
+	Name  string `json:"name"`
+	Until string `json:"until"`
+}
+
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "freeze.yaml"), nil
+}
+
+// Load returns nil without error when no freeze file exists.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read freeze windows %s: %w", path, err)
+	}
+	cfg := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("decode freeze windows %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid freeze windows %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	if c.SchemaVersion != ConfigSchemaVersion {
+		return fmt.Errorf("unsupported schema_version=%d (expected %d)", c.SchemaVersion, ConfigSchemaVersion)
+	}
+	for index := range c.Windows {
+		window := &c.Windows[index]
+		window.Name = strings.TrimSpace(window.Name)
+		if window.Name == "" {
+			return fmt.Errorf("windows[%d]: name is required", index)
+		}
+		if err := window.compile(); err != nil {
+			return fmt.Errorf("window %q: %w", window.Name, err)
+		}
+	}
+	return nil
+}
+
+func (w *Window) compile() error {
+	ranged := strings.TrimSpace(w.Start) != "" || strings.TrimSpace(w.End) != ""
+	recurring := strings.TrimSpace(w.Cron) != "" || strings.TrimSpace(w.Duration) != ""
+	switch {
+	case ranged && recurring:
+		return errors.New("set either start/end or cron/duration, not both")
+	case ranged:
+		start, err := parseTime(w.Start)
+		if err != nil {
+			return fmt.Errorf("invalid start: %w", err)
+		}
+		end, err := parseTime(w.End)
+		if err != nil {
+			return fmt.Errorf("invalid end: %w", err)
+		}
+		if !end.After(start) {
+			return errors.New("end must be after start")
+		}
+		w.start, w.end = start, end
+	case recurring:
+		compiled, err := parseSchedule(w.Cron)
+		if err != nil {
+			return fmt.Errorf("invalid cron: %w", err)
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(w.Duration))
+		if err != nil || duration <= 0 || duration > MaxRecurringDuration {
+			return fmt.Errorf("invalid duration %q: expected a positive duration up to %s", w.Duration, MaxRecurringDuration)
+		}
+		location := time.UTC
+		if zone := strings.TrimSpace(w.Timezone); zone != "" {
+			if location, err = time.LoadLocation(zone); err != nil {
+				return fmt.Errorf("invalid timezone: %w", err)
+			}
+		}
+		w.schedule, w.duration, w.location = compiled, duration, location
+	default:
+		return errors.New("set start/end or cron/duration")
+	}
+	for index, account := range w.Accounts {
+		w.Accounts[index] = strings.TrimPrefix(strings.TrimSpace(account), "act_")
+	}
+	if labels := strings.TrimSpace(w.Labels); labels != "" {
+		selector, err := config.ParseSelector(labels)
+		if err != nil {
+			return err
+		}
+		w.selector = &selector
+	}
+	return nil
+}
+
+// parseTime accepts RFC3339 timestamps and plain dates, which mean midnight UTC.
+func parseTime(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC3339 timestamp nor a YYYY-MM-DD date", raw)
+	}
+	return parsed, nil
+}
+
+// openUntil reports whether w is open at now and when it closes.
+func (w *Window) openUntil(now time.Time) (time.Time, bool) {
+	if w.schedule == nil {
+		return w.end, !now.Before(w.start) && now.Before(w.end)
+	}
+	local := now.In(w.location).Truncate(time.Minute)
+	for opened := local; now.Sub(opened) < w.duration; opened = opened.Add(-time.Minute) {
+		if w.schedule.matches(opened) {
+			return opened.Add(w.duration), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (w *Window) covers(mutation graph.Mutation, labels map[string]string) bool {
+	if w.selector != nil && !w.selector.Matches(labels) {
+		return false
+	}
+	if len(w.Accounts) == 0 {
+		return true
+	}
+	segment, _, _ := strings.Cut(strings.Trim(mutation.Path, "/"), "/")
+	if !strings.HasPrefix(segment, "act_") {
+		// A request addressed by object id does not name its account; the
+		// freeze errs on the side of blocking it.
+		return true
+	}
+	account := strings.TrimPrefix(segment, "act_")
+	for _, candidate := range w.Accounts {
+		if candidate == account {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveFor returns the windows open at now that cover mutation.
+func (c *Config) ActiveFor(mutation graph.Mutation, labels map[string]string, now time.Time) []Active {
+	if c == nil {
+		return nil
+	}
+	active := make([]Active, 0)
+	for index := range c.Windows {
+		window := &c.Windows[index]
+		until, open := window.openUntil(now)
+		if !open || !window.covers(mutation, labels) {
+			continue
+		}
+		active = append(active, Active{Name: window.Name, Until: until.UTC().Format(time.RFC3339)})
+	}
+	return active
+}
+
+// Guard returns a graph.MutationGuard enforcing c. A --break-glass reason on
+// the invocation lets mutations through; the audit log records the reason.
+// labels resolves the labels of the invoking profile and may be nil.
+func (c *Config) Guard(labels func(profile string) map[string]string, now func() time.Time) graph.MutationGuard {
+	return func(ctx context.Context, mutation graph.Mutation) error {
+		invocation := audit.InvocationFromContext(ctx)
+		if strings.TrimSpace(invocation.BreakGlass) != "" {
+			return nil
+		}
+		var profileLabels map[string]string
+		if labels != nil {
+			profileLabels = labels(invocation.Profile)
+		}
+		active := c.ActiveFor(mutation, profileLabels, now())
+		if len(active) == 0 {
+			return nil
+		}
+		return &graph.APIError{
+			Type:      errorTypeFrozen,
+			Code:      errorCodeFrozen,
+			Message:   fmt.Sprintf("%s %s blocked: freeze window %q is in effect until %s", mutation.Method, mutation.Path, active[0].Name, active[0].Until),
+			Retryable: false,
+			Diagnostics: map[string]any{
+				"windows": active,
+			},
+			Remediation: &graph.Remediation{
+				Category: graph.RemediationCategoryPermission,
+				Summary:  "Mutations are frozen for this account or profile.",
+				Actions: []string{
+					"Wait until the freeze window closes.",
+					"For an emergency change, rerun with --break-glass \"<reason>\"; the reason is recorded in the audit log.",
+				},
+			},
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package freeze
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const testWindows = `schema_version: 1
+windows:
+  - name: black-friday
+    start: 2026-11-27
+    end: 2026-12-01T00:00:00Z
+    accounts: [act_111]
+  - name: weekend
+    cron: "0 18 * * 5"
+    duration: 62h
+    labels: env=prod
+`
+
+func loadTestWindows(t *testing.T) *Config {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "freeze.yaml")
+	if err := os.WriteFile(path, []byte(testWindows), 0o600); err != nil {
+		t.Fatalf("write windows: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load windows: %v", err)
+	}
+	return cfg
+}
+
+func TestActiveForDateRangesAndRecurringWindows(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadTestWindows(t)
+	prod := map[string]string{"env": "prod"}
+	cases := []struct {
+		name   string
+		path   string
+		labels map[string]string
+		at     time.Time
+		want   string
+	}{
+		{"inside range", "act_111/campaigns", nil, time.Date(2026, 11, 28, 9, 0, 0, 0, time.UTC), "black-friday"},
+		{"other account", "act_222/campaigns", nil, time.Date(2026, 11, 28, 9, 0, 0, 0, time.UTC), ""},
+		{"object id inside range", "120", nil, time.Date(2026, 11, 28, 9, 0, 0, 0, time.UTC), "black-friday"},
+		{"after range", "act_111/campaigns", nil, time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC), ""},
+		{"saturday in weekend window", "act_222/campaigns", prod, time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC), "weekend"},
+		{"weekend window needs label", "act_222/campaigns", nil, time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC), ""},
+		{"friday before window", "act_222/campaigns", prod, time.Date(2026, 3, 6, 17, 59, 0, 0, time.UTC), ""},
+		{"monday after window", "act_222/campaigns", prod, time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC), ""},
+	}
+	for _, tc := range cases {
+		active := cfg.ActiveFor(graph.Mutation{Method: "POST", Path: tc.path}, tc.labels, tc.at)
+		names := make([]string, 0, len(active))
+		for _, window := range active {
+			names = append(names, window.Name)
+		}
+		if strings.Join(names, ",") != tc.want {
+			t.Fatalf("%s: expected %q, got %#v", tc.name, tc.want, active)
+		}
+	}
+}
+
+func TestGuardBlocksUnlessBreakGlass(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadTestWindows(t)
+	now := func() time.Time { return time.Date(2026, 11, 28, 9, 0, 0, 0, time.UTC) }
+	guard := cfg.Guard(nil, now)
+	mutation := graph.Mutation{Method: "POST", Path: "act_111/campaigns"}
+
+	err := guard(context.Background(), mutation)
+	apiErr := &graph.APIError{}
+	if !errors.As(err, &apiErr) || apiErr.Type != "freeze_window_gate" || !strings.Contains(apiErr.Message, "2026-12-01T00:00:00Z") {
+		t.Fatalf("expected freeze_window_gate, got %v", err)
+	}
+
+	ctx := audit.WithInvocation(context.Background(), audit.Invocation{BreakGlass: "checkout outage hotfix"})
+	if err := guard(ctx, mutation); err != nil {
+		t.Fatalf("expected break glass to pass, got %v", err)
+	}
+}
+
+func TestParseScheduleRejectsInvalidFields(t *testing.T) {
+	t.Parallel()
+
+	for _, raw := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := parseSchedule(raw); err == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+	compiled, err := parseSchedule("*/15 9-17 * * 1-5")
+	if err != nil {
+		t.Fatalf("parse schedule: %v", err)
+	}
+	if !compiled.matches(time.Date(2026, 3, 2, 9, 45, 0, 0, time.UTC)) || compiled.matches(time.Date(2026, 3, 1, 9, 45, 0, 0, time.UTC)) {
+		t.Fatal("unexpected schedule matching")
+	}
+}
@@ -0,0 +1,113 @@
+package freeze
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schedule is a parsed five-field cron expression. Each field supports *,
+// single values, a-b ranges, comma lists and /step.
+type schedule struct {
+	minute     map[int]bool
+	hour       map[int]bool
+	dayOfMonth map[int]bool
+	month      map[int]bool
+	dayOfWeek  map[int]bool
+	// domAny and dowAny record which day fields were *. When both are
+	// restricted a day matching either one matches, as in crontab(5).
+	domAny bool
+	dowAny bool
+}
+
+func parseSchedule(raw string) (*schedule, error) {
+	fields := strings.Fields(raw)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%q: expected 5 fields (minute hour day-of-month month day-of-week)", raw)
+	}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	sets := [5]map[int]bool{}
+	for index, field := range fields {
+		set, err := parseField(field, bounds[index][0], bounds[index][1])
+		if err != nil {
+			return nil, fmt.Errorf("%q: field %d: %w", raw, index+1, err)
+		}
+		sets[index] = set
+	}
+	// Sunday is both 0 and 7.
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+	return &schedule{
+		minute:     sets[0],
+		hour:       sets[1],
+		dayOfMonth: sets[2],
+		month:      sets[3],
+		dayOfWeek:  sets[4],
+		domAny:     fields[2] == "*",
+		dowAny:     fields[4] == "*",
+	}, nil
+}
+
+func parseField(field string, low int, high int) (map[int]bool, error) {
+	set := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if base, rawStep, ok := strings.Cut(part, "/"); ok {
+			parsed, err := strconv.Atoi(rawStep)
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid step %q", rawStep)
+			}
+			part, step = base, parsed
+		}
+		from, to := low, high
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			rawFrom, rawTo, _ := strings.Cut(part, "-")
+			var err error
+			if from, err = strconv.Atoi(rawFrom); err != nil {
+				return nil, fmt.Errorf("invalid value %q", rawFrom)
+			}
+			if to, err = strconv.Atoi(rawTo); err != nil {
+				return nil, fmt.Errorf("invalid value %q", rawTo)
+			}
+		default:
+			value, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", part)
+			}
+			from, to = value, value
+		}
+		if from < low || to > high || from > to {
+			return nil, fmt.Errorf("%q is outside %d-%d", part, low, high)
+		}
+		for value := from; value <= to; value += step {
+			set[value] = true
+		}
+	}
+	if len(set) == 0 {
+		return nil, errors.New("matches no values")
+	}
+	return set, nil
+}
+
+func (s *schedule) matches(at time.Time) bool {
+	if !s.minute[at.Minute()] || !s.hour[at.Hour()] || !s.month[int(at.Month())] {
+		return false
+	}
+	dom := s.dayOfMonth[at.Day()]
+	dow := s.dayOfWeek[int(at.Weekday())]
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
@@ -21,6 +21,12 @@ const (
 	httpMethodGet    = "GET"
 )
 
+var batchMethods = map[string]struct{}{
+	http.MethodGet:    {},
+	http.MethodPost:   {},
+	http.MethodDelete: {},
+}
+
 type BatchRequest struct {
 	Method string            `json:"method"`
 	Path   string            `json:"path"`
@@ -28,8 +34,9 @@ type BatchRequest struct {
 }
 
 type BatchResult struct {
-	Code int            `json:"code"`
-	Body map[string]any `json:"body"`
+	Code  int            `json:"code"`
+	Body  map[string]any `json:"body"`
+	Error *APIError      `json:"error,omitempty"`
 }
 
 func ValidateBatchRequests(requests []BatchRequest) error {
@@ -55,27 +62,132 @@ func (c *Client) ExecuteGETBatch(ctx context.Context, version string, accessToke
 	if err := ValidateBatchRequests(requests); err != nil {
 		return nil, err
 	}
+
+	results, err := c.executeBatch(ctx, version, accessToken, appSecret, requests)
+	if err != nil {
+		return nil, err
+	}
+	for idx, result := range results {
+		if result.Error != nil {
+			return nil, fmt.Errorf("batch item %d failed: %w", idx, result.Error)
+		}
+	}
+	return results, nil
+}
+
+// ExecuteBatch sends GET, POST, and DELETE requests in one Graph batch call. Unlike
+// ExecuteGETBatch, a failed item does not fail the call: it is reported through the
+// Error of its result so callers can map failures back to their inputs.
+func (c *Client) ExecuteBatch(ctx context.Context, version string, accessToken string, appSecret string, requests []BatchRequest) ([]BatchResult, error) {
+	if len(requests) == 0 {
+		return nil, errors.New("batch request list cannot be empty")
+	}
+	if len(requests) > maxBatchRequests {
+		return nil, fmt.Errorf("batch request count %d exceeds limit %d", len(requests), maxBatchRequests)
+	}
+	for idx, req := range requests {
+		if strings.TrimSpace(req.Path) == "" {
+			return nil, fmt.Errorf("batch request %d path is required", idx)
+		}
+		if _, ok := batchMethods[strings.ToUpper(strings.TrimSpace(req.Method))]; !ok {
+			return nil, fmt.Errorf("batch request %d uses unsupported method %q; expected GET, POST, or DELETE", idx, req.Method)
+		}
+	}
+	if err := c.guardBatch(ctx, version, accessToken, appSecret, requests); err != nil {
+		return nil, err
+	}
+
+	var (
+		results []BatchResult
+		err     error
+	)
+	if dryRun := DryRunFromContext(ctx); dryRun != nil {
+		results, err = c.dryRunBatch(ctx, dryRun, version, accessToken, appSecret, requests)
+	} else {
+		results, err = c.executeBatch(ctx, version, accessToken, appSecret, requests)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for idx := range results {
+		result := &results[idx]
+		if result.Error == nil && (result.Code < 200 || result.Code >= 300) {
+			result.Error = &APIError{
+				Type:       "batch_item_failed",
+				Code:       result.Code,
+				Message:    fmt.Sprintf("batch item %d returned status %d without an error payload", idx, result.Code),
+				StatusCode: result.Code,
+				Retryable:  result.Code == 0 || result.Code >= 500,
+			}
+		}
+	}
+	return results, nil
+}
+
+// dryRunBatch plans the mutations of a batch and sends only its reads of
+// objects that exist.
+func (c *Client) dryRunBatch(ctx context.Context, dryRun *DryRun, version string, accessToken string, appSecret string, requests []BatchRequest) ([]BatchResult, error) {
+	if version == "" {
+		version = config.DefaultGraphVersion
+	}
+	results := make([]BatchResult, len(requests))
+	reads := make([]BatchRequest, 0)
+	readIndexes := make([]int, 0)
+	for idx, req := range requests {
+		method := strings.ToUpper(strings.TrimSpace(req.Method))
+		if method == http.MethodGet && dryRun.readsSimulated(req.Path) {
+			results[idx] = BatchResult{Code: http.StatusOK, Body: simulatedRead(req.Path)}
+			continue
+		}
+		if method == http.MethodGet {
+			reads = append(reads, req)
+			readIndexes = append(readIndexes, idx)
+			continue
+		}
+		results[idx] = BatchResult{Code: http.StatusOK, Body: dryRun.plan(method, version, req.Path, req.Params, nil, true)}
+	}
+	if len(reads) == 0 {
+		return results, nil
+	}
+	readResults, err := c.executeBatch(ctx, version, accessToken, appSecret, reads)
+	if err != nil {
+		return nil, err
+	}
+	for position, idx := range readIndexes {
+		results[idx] = readResults[position]
+	}
+	return results, nil
+}
+
+func (c *Client) executeBatch(ctx context.Context, version string, accessToken string, appSecret string, requests []BatchRequest) ([]BatchResult, error) {
 	if strings.TrimSpace(accessToken) == "" {
 		return nil, errors.New("access token is required for batch execution")
 	}
 	if version == "" {
 		version = config.DefaultGraphVersion
 	}
+	if offline := OfflineFromContext(ctx); offline != nil {
+		return c.batchOffline(ctx, offline, version, requests)
+	}
 
 	entries := make([]map[string]string, 0, len(requests))
 	for _, req := range requests {
+		method := strings.ToUpper(strings.TrimSpace(req.Method))
 		relativeURL := strings.TrimPrefix(req.Path, "/")
+		entry := map[string]string{"method": method}
 		if len(req.Params) > 0 {
 			values := url.Values{}
 			for key, value := range req.Params {
 				values.Set(key, value)
 			}
-			relativeURL = relativeURL + "?" + values.Encode()
+			if method == http.MethodPost {
+				entry["body"] = values.Encode()
+			} else {
+				relativeURL = relativeURL + "?" + values.Encode()
+			}
 		}
-		entries = append(entries, map[string]string{
-			"method":       httpMethodGet,
-			"relative_url": relativeURL,
-		})
+		entry["relative_url"] = relativeURL
+		entries = append(entries, entry)
 	}
 	batchPayload, err := json.Marshal(entries)
 	if err != nil {
@@ -128,6 +240,9 @@ func (c *Client) ExecuteGETBatch(ctx context.Context, version string, accessToke
 	if err := json.Unmarshal(body, &rawItems); err != nil {
 		return nil, fmt.Errorf("decode batch response: %w", err)
 	}
+	if len(rawItems) != len(requests) {
+		return nil, fmt.Errorf("batch response has %d item(s) for %d request(s)", len(rawItems), len(requests))
+	}
 
 	results := make([]BatchResult, 0, len(rawItems))
 	for idx, item := range rawItems {
@@ -137,13 +252,32 @@ func (c *Client) ExecuteGETBatch(ctx context.Context, version string, accessToke
 				return nil, fmt.Errorf("decode batch item %d body: %w", idx, err)
 			}
 		}
-		if apiErr := parseAPIError(item.Code, parsedBody); apiErr != nil {
-			return nil, fmt.Errorf("batch item %d failed: %w", idx, apiErr)
-		}
 		results = append(results, BatchResult{
-			Code: item.Code,
-			Body: parsedBody,
+			Code:  item.Code,
+			Body:  parsedBody,
+			Error: parseAPIError(item.Code, parsedBody),
 		})
+		if method := strings.ToUpper(strings.TrimSpace(requests[idx].Method)); method == http.MethodGet && results[idx].Error == nil && item.Code >= 200 && item.Code < 300 {
+			c.storeRead(ctx, version, requests[idx].Path, requests[idx].Params, item.Code, parsedBody)
+		}
+	}
+	return results, nil
+}
+
+// batchOffline answers the reads of a batch from the read cache; the batch
+// fails as a whole if one of them is not cached or it holds a mutation.
+func (c *Client) batchOffline(ctx context.Context, offline *Offline, version string, requests []BatchRequest) ([]BatchResult, error) {
+	results := make([]BatchResult, 0, len(requests))
+	for _, req := range requests {
+		method := strings.ToUpper(strings.TrimSpace(req.Method))
+		if method != http.MethodGet {
+			return nil, offlineError(method, req.Path, fmt.Sprintf("batched %s %s needs the network, which --offline disables", method, req.Path))
+		}
+		read, err := c.loadCachedRead(ctx, offline, version, req.Path, req.Params)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, BatchResult{Code: read.StatusCode, Body: read.Body})
 	}
 	return results, nil
 }
@@ -17,6 +17,8 @@ import (
 
 	"github.com/bilalbayram/metacli/internal/auth"
 	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/progress"
+	"github.com/bilalbayram/metacli/internal/transport"
 )
 
 type HTTPClient interface {
@@ -31,6 +33,8 @@ type Client struct {
 	MaxBackoff     time.Duration
 	Sleep          func(time.Duration)
 	UserAgent      string
+	// Governor, when set, bounds concurrent requests; see WithGovernor.
+	Governor *Governor
 }
 
 type Request struct {
@@ -66,7 +70,7 @@ type RateLimit struct {
 
 func NewClient(httpClient HTTPClient, baseURL string) *Client {
 	if httpClient == nil {
-		httpClient = &http.Client{Timeout: 30 * time.Second}
+		httpClient = transport.NewClient(transport.DefaultTimeout)
 	}
 	if baseURL == "" {
 		baseURL = auth.DefaultGraphBaseURL
@@ -95,26 +99,67 @@ func (c *Client) Do(ctx context.Context, req Request) (*Response, error) {
 	if version == "" {
 		version = config.DefaultGraphVersion
 	}
+	if err := c.guardMutation(ctx, method, version, req); err != nil {
+		return nil, err
+	}
+	if dryRun := DryRunFromContext(ctx); dryRun != nil {
+		if method != http.MethodGet {
+			return dryRun.response(method, version, req), nil
+		}
+		if dryRun.readsSimulated(req.Path) {
+			return dryRun.readResponse(req.Path), nil
+		}
+	}
+	if offline := OfflineFromContext(ctx); offline != nil {
+		return c.readOffline(ctx, offline, method, version, req)
+	}
+	if c.Governor != nil {
+		release, err := c.Governor.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+	response, err := c.doWithRetry(ctx, method, version, req)
+	if c.Governor != nil && response != nil {
+		c.Governor.Observe(response.RateLimit)
+	}
+	recordMutation(ctx, method, version, req, response, err)
+	if err == nil && method == http.MethodGet {
+		c.storeRead(ctx, version, req.Path, req.Query, response.StatusCode, response.Body)
+	}
+	return response, err
+}
+
+func (c *Client) doWithRetry(ctx context.Context, method string, version string, req Request) (*Response, error) {
 	attempt := 0
 	backoff := c.InitialBackoff
 
 	for {
 		attempt++
+		started := time.Now()
 		response, err := c.doOnce(ctx, method, version, req)
+		c.logRequest(ctx, method, version, req, attempt, started, response, err)
 		if err == nil {
 			return response, nil
 		}
 
 		var apiErr *APIError
 		if errors.As(err, &apiErr) && apiErr.Retryable && attempt <= c.MaxRetries {
-			c.Sleep(backoff)
+			progress.FromContext(ctx).Wait(retryWaitReason(apiErr), backoff)
+			if err := SleepContext(ctx, c.Sleep, backoff); err != nil {
+				return nil, err
+			}
 			backoff = nextBackoff(backoff, c.MaxBackoff)
 			continue
 		}
 
 		var transient *TransientError
 		if errors.As(err, &transient) && attempt <= c.MaxRetries {
-			c.Sleep(backoff)
+			progress.FromContext(ctx).Wait("retrying after a transient network error", backoff)
+			if err := SleepContext(ctx, c.Sleep, backoff); err != nil {
+				return nil, err
+			}
 			backoff = nextBackoff(backoff, c.MaxBackoff)
 			continue
 		}
@@ -123,6 +168,36 @@ func (c *Client) Do(ctx context.Context, req Request) (*Response, error) {
 	}
 }
 
+// retryWaitReason explains a retry backoff on a progress bar.
+func retryWaitReason(apiErr *APIError) string {
+	if apiErr.Remediation != nil && apiErr.Remediation.Category == RemediationCategoryRateLimit {
+		return fmt.Sprintf("rate limited by Meta (code %d)", apiErr.Code)
+	}
+	return fmt.Sprintf("retrying after Graph error code %d", apiErr.Code)
+}
+
+// SleepContext waits for d using sleep (injectable for tests) but returns early
+// with the context error once ctx is done.
+func SleepContext(ctx context.Context, sleep func(time.Duration), d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	done := make(chan struct{})
+	go func() {
+		sleep(d)
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (c *Client) doOnce(ctx context.Context, method string, version string, req Request) (*Response, error) {
 	endpoint, err := url.Parse(c.BaseURL)
 	if err != nil {
@@ -170,6 +245,10 @@ func (c *Client) doOnce(ctx context.Context, method string, version string, req
 
 	httpRes, err := c.HTTP.Do(httpReq)
 	if err != nil {
+		// A canceled or timed out invocation is final, not a transient failure.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("send request: %w", ctxErr)
+		}
 		return nil, &TransientError{Message: fmt.Sprintf("send request: %v", err)}
 	}
 	defer httpRes.Body.Close()
@@ -1,14 +1,19 @@
 package graph
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/bilalbayram/metacli/internal/progress"
 )
 
 func TestShouldRetryClassifier(t *testing.T) {
@@ -61,7 +66,9 @@ func TestClientRetriesOnMetaRateLimitCode(t *testing.T) {
 	client.MaxBackoff = 1 * time.Millisecond
 	client.Sleep = func(time.Duration) {}
 
-	resp, err := client.Do(context.Background(), Request{
+	var bar bytes.Buffer
+	ctx := progress.WithBar(context.Background(), progress.New(&bar, "api get", 0))
+	resp, err := client.Do(ctx, Request{
 		Method:  http.MethodGet,
 		Path:    "/act_123/insights",
 		Version: "v25.0",
@@ -75,6 +82,9 @@ func TestClientRetriesOnMetaRateLimitCode(t *testing.T) {
 	if atomic.LoadInt32(&calls) != 2 {
 		t.Fatalf("expected exactly 2 attempts, got %d", calls)
 	}
+	if !strings.Contains(bar.String(), "rate limited by Meta (code 613)") {
+		t.Fatalf("expected the backoff to be reported on the progress bar, got %q", bar.String())
+	}
 }
 
 func TestClientNormalizesGraphError(t *testing.T) {
@@ -273,3 +283,365 @@ func TestClientSupportsMultipartRequestBodies(t *testing.T) {
 		t.Fatalf("unexpected response id %q", got)
 	}
 }
+
+func TestClientDoesNotRetryCanceledRequests(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL)
+	client.Sleep = func(time.Duration) {}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.Do(ctx, Request{Method: http.MethodGet, Path: "/me", Version: "v25.0"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled error, got %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 0 {
+		t.Fatalf("expected no attempts after cancellation, got %d", calls)
+	}
+}
+
+func TestSleepContextReturnsWhenContextEnds(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	block := make(chan struct{})
+	defer close(block)
+
+	err := SleepContext(ctx, func(time.Duration) { <-block }, time.Hour)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if err := SleepContext(context.Background(), func(time.Duration) {}, time.Hour); err != nil {
+		t.Fatalf("expected completed sleep, got %v", err)
+	}
+}
+
+func TestClientRecordsFinalMutationAttemptOnly(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id":"42"}`))
+	}))
+	defer server.Close()
+
+	var recorded []Mutation
+	SetMutationRecorder(func(_ context.Context, mutation Mutation) {
+		recorded = append(recorded, mutation)
+	})
+	t.Cleanup(func() { SetMutationRecorder(nil) })
+
+	client := NewClient(server.Client(), server.URL)
+	client.Sleep = func(time.Duration) {}
+	if _, err := client.Do(context.Background(), Request{Method: http.MethodGet, Path: "/42", Version: "v25.0"}); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if _, err := client.Do(context.Background(), Request{
+		Method:  http.MethodPost,
+		Path:    "/42",
+		Version: "v25.0",
+		Form:    map[string]string{"status": "PAUSED"},
+	}); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+
+	if len(recorded) != 1 {
+		t.Fatalf("expected one recorded mutation, got %d", len(recorded))
+	}
+	mutation := recorded[0]
+	if mutation.Method != http.MethodPost || mutation.Path != "/42" || mutation.Form["status"] != "PAUSED" {
+		t.Fatalf("unexpected mutation %+v", mutation)
+	}
+	if mutation.Err != nil || mutation.Response == nil || mutation.Response.Body["id"] != "42" {
+		t.Fatalf("expected successful final attempt, got response=%+v err=%v", mutation.Response, mutation.Err)
+	}
+}
+
+func TestMutationObserversRunAfterRecorderInOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	var calls []string
+	SetMutationRecorder(func(context.Context, Mutation) { calls = append(calls, "recorder") })
+	SetMutationObserver("first", func(context.Context, Mutation) { calls = append(calls, "first") })
+	SetMutationObserver("second", func(context.Context, Mutation) { calls = append(calls, "second") })
+	SetMutationObserver("first", func(context.Context, Mutation) { calls = append(calls, "first-replaced") })
+	t.Cleanup(func() {
+		SetMutationRecorder(nil)
+		SetMutationObserver("first", nil)
+		SetMutationObserver("second", nil)
+	})
+
+	client := NewClient(server.Client(), server.URL)
+	if _, err := client.Do(context.Background(), Request{Method: http.MethodPost, Path: "/42", Version: "v25.0"}); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	SetMutationObserver("second", nil)
+	if _, err := client.Do(context.Background(), Request{Method: http.MethodDelete, Path: "/42", Version: "v25.0"}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	want := []string{"recorder", "first-replaced", "second", "recorder", "first-replaced"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected calls %v", calls)
+	}
+}
+
+func TestMutationGuardBlocksBeforeSending(t *testing.T) {
+	var sent int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&sent, 1)
+		_, _ = w.Write([]byte(`{"id":"42"}`))
+	}))
+	defer server.Close()
+
+	blocked := errors.New("blocked by guard")
+	var guarded []string
+	SetMutationGuard("test", func(_ context.Context, mutation Mutation) error {
+		guarded = append(guarded, mutation.Method+" "+mutation.Path)
+		if mutation.Method == http.MethodDelete {
+			return blocked
+		}
+		return nil
+	})
+	t.Cleanup(func() { SetMutationGuard("test", nil) })
+
+	client := NewClient(server.Client(), server.URL)
+	if _, err := client.Do(context.Background(), Request{Method: http.MethodGet, Path: "/42", Version: "v25.0"}); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if _, err := client.Do(context.Background(), Request{Method: http.MethodPost, Path: "/42", Version: "v25.0"}); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if _, err := client.Do(context.Background(), Request{Method: http.MethodDelete, Path: "/42", Version: "v25.0"}); !errors.Is(err, blocked) {
+		t.Fatalf("expected guard error, got %v", err)
+	}
+	if _, err := client.ExecuteBatch(context.Background(), "v25.0", "token", "", []BatchRequest{{Method: "DELETE", Path: "43"}}); !errors.Is(err, blocked) {
+		t.Fatalf("expected guard to see batched mutations, got %v", err)
+	}
+	if atomic.LoadInt32(&sent) != 2 || len(guarded) != 3 {
+		t.Fatalf("expected blocked mutations not to be sent, sent=%d guarded=%v", sent, guarded)
+	}
+}
+
+func TestDryRunPlansMutationsAndSendsReads(t *testing.T) {
+	t.Parallel()
+
+	var sent []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sent = append(sent, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/v25.0" {
+			_, _ = w.Write([]byte(`[{"code":200,"body":"{\"id\":\"7\",\"name\":\"read\"}"}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"42","name":"read"}`))
+	}))
+	defer server.Close()
+
+	dryRun := &DryRun{}
+	ctx := WithDryRun(context.Background(), dryRun)
+	client := NewClient(server.Client(), server.URL)
+
+	if _, err := client.Do(ctx, Request{Method: http.MethodGet, Path: "42", Version: "v25.0"}); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	created, err := client.Do(ctx, Request{Method: http.MethodPost, Path: "act_1/campaigns", Version: "v25.0", Form: map[string]string{"name": "Launch", "access_token": "secret"}})
+	if err != nil || created.Body["id"] != "dry_run_1" {
+		t.Fatalf("expected simulated id, got %#v %v", created, err)
+	}
+	results, err := client.ExecuteBatch(ctx, "v25.0", "token", "", []BatchRequest{{Method: "GET", Path: "7"}, {Method: "DELETE", Path: "43"}})
+	if err != nil || len(results) != 2 || results[0].Body["name"] != "read" || results[1].Body["id"] != "dry_run_2" {
+		t.Fatalf("unexpected batch results %#v %v", results, err)
+	}
+
+	mutations := dryRun.Mutations()
+	if len(mutations) != 2 || mutations[0].Path != "act_1/campaigns" || mutations[0].Params["name"] != "Launch" || !mutations[1].Batched {
+		t.Fatalf("unexpected plan %#v", mutations)
+	}
+	if _, leaked := mutations[0].Params["access_token"]; leaked {
+		t.Fatalf("plan kept the access token: %#v", mutations[0].Params)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent) != 2 || sent[0] != "GET /v25.0/42" || sent[1] != "POST /v25.0" {
+		t.Fatalf("expected only reads to be sent, got %v", sent)
+	}
+}
+
+func TestDryRunAnswersReadsOfSimulatedObjectsLocally(t *testing.T) {
+	t.Parallel()
+
+	var sent atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent.Add(1)
+		_, _ = w.Write([]byte(`{"error":{"message":"Unsupported get request","type":"GraphMethodException","code":100}}`))
+	}))
+	defer server.Close()
+
+	ctx := WithDryRun(context.Background(), &DryRun{})
+	client := NewClient(server.Client(), server.URL)
+
+	created, err := client.Do(ctx, Request{Method: http.MethodPost, Path: "300/media", Version: "v25.0"})
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	read, err := client.Do(ctx, Request{Method: http.MethodGet, Path: "dry_run_1", Version: "v25.0", Query: map[string]string{"fields": "id,status_code"}})
+	if err != nil || read.Body["id"] != created.Body["id"] {
+		t.Fatalf("expected a local read of the simulated object, got %#v %v", read, err)
+	}
+	results, err := client.ExecuteBatch(ctx, "v25.0", "token", "", []BatchRequest{{Method: "GET", Path: "dry_run_1/insights"}})
+	if err != nil || len(results) != 1 || results[0].Body["id"] != "dry_run_1" {
+		t.Fatalf("unexpected batch results %#v %v", results, err)
+	}
+	if sent.Load() != 0 {
+		t.Fatalf("expected no request to Graph, got %d", sent.Load())
+	}
+}
+
+func TestReadCacheSkipsPersonalData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"id":"1","field_data":[{"name":"email","values":["a@example.com"]}]}]}`))
+	}))
+	defer server.Close()
+
+	cache := &memoryReadCache{reads: map[string]CachedRead{}}
+	SetReadCache(cache)
+	t.Cleanup(func() { SetReadCache(nil) })
+
+	client := NewClient(server.Client(), server.URL)
+	for _, req := range []Request{
+		{Method: http.MethodGet, Path: "55/leads", Version: "v25.0"},
+		{Method: http.MethodGet, Path: "200/conversations", Version: "v25.0"},
+		{Method: http.MethodGet, Path: "66", Version: "v25.0", Query: map[string]string{"fields": "id,created_time,field_data"}},
+	} {
+		if _, err := client.Do(context.Background(), req); err != nil {
+			t.Fatalf("get %s: %v", req.Path, err)
+		}
+	}
+	if len(cache.reads) != 0 {
+		t.Fatalf("personal data reached the read cache: %v", cache.reads)
+	}
+
+	_, err := client.Do(WithOffline(context.Background(), &Offline{}), Request{Method: http.MethodGet, Path: "55/leads", Version: "v25.0"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != ErrorTypeOffline || !strings.Contains(apiErr.Message, "personal data") {
+		t.Fatalf("expected offline personal-data error, got %v", err)
+	}
+}
+
+type memoryReadCache struct {
+	mu    sync.Mutex
+	reads map[string]CachedRead
+}
+
+func (c *memoryReadCache) cacheKey(key ReadKey) string {
+	return key.Version + " " + key.Path + " " + key.Query["fields"] + " " + key.Query["access_token"]
+}
+
+func (c *memoryReadCache) Load(_ context.Context, key ReadKey) (*CachedRead, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	read, ok := c.reads[c.cacheKey(key)]
+	if !ok {
+		return nil, nil
+	}
+	return &read, nil
+}
+
+func (c *memoryReadCache) Store(_ context.Context, key ReadKey, read CachedRead) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reads[c.cacheKey(key)] = read
+	return nil
+}
+
+func TestOfflineServesCachedReadsAndRefusesTheNetwork(t *testing.T) {
+	var sent atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent.Add(1)
+		if r.URL.Path == "/v25.0" {
+			_, _ = w.Write([]byte(`[{"code":200,"body":"{\"id\":\"7\",\"name\":\"batched\"}"}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"42","name":"Launch","access_token":"page-token"}`))
+	}))
+	defer server.Close()
+
+	cache := &memoryReadCache{reads: map[string]CachedRead{}}
+	SetReadCache(cache)
+	t.Cleanup(func() { SetReadCache(nil) })
+
+	client := NewClient(server.Client(), server.URL)
+	read := Request{Method: http.MethodGet, Path: "/42", Version: "v25.0", Query: map[string]string{"fields": "name"}, AccessToken: "secret"}
+	if _, err := client.Do(context.Background(), read); err != nil {
+		t.Fatalf("online get: %v", err)
+	}
+	if _, err := client.ExecuteGETBatch(context.Background(), "v25.0", "secret", "", []BatchRequest{{Method: "GET", Path: "7"}}); err != nil {
+		t.Fatalf("online batch: %v", err)
+	}
+	cached, _ := cache.Load(context.Background(), ReadKey{Version: "v25.0", Path: "42", Query: map[string]string{"fields": "name"}})
+	if cached == nil {
+		t.Fatalf("online read was not cached: %v", cache.reads)
+	}
+	if _, leaked := cached.Body["access_token"]; leaked {
+		t.Fatalf("cache kept an access token: %v", cached.Body)
+	}
+	for key := range cache.reads {
+		if strings.Contains(key, "secret") {
+			t.Fatalf("cache key kept the access token: %q", key)
+		}
+	}
+
+	sentOnline := sent.Load()
+	offline := &Offline{Now: func() time.Time { return cached.CachedAt.Add(90 * time.Second) }}
+	ctx := WithOffline(context.Background(), offline)
+	response, err := client.Do(ctx, read)
+	if err != nil || response.Body["name"] != "Launch" {
+		t.Fatalf("expected cached read, got %#v %v", response, err)
+	}
+	results, err := client.ExecuteGETBatch(ctx, "v25.0", "secret", "", []BatchRequest{{Method: "GET", Path: "7"}})
+	if err != nil || results[0].Body["name"] != "batched" {
+		t.Fatalf("expected cached batch read, got %#v %v", results, err)
+	}
+	reads := offline.Reads()
+	if len(reads) != 2 || reads[0].Path != "42" || reads[0].AgeSeconds != 90 || reads[1].Path != "7" {
+		t.Fatalf("unexpected offline reads %#v", reads)
+	}
+
+	for _, req := range []Request{
+		{Method: http.MethodGet, Path: "43", Version: "v25.0"},
+		{Method: http.MethodPost, Path: "42", Version: "v25.0", Form: map[string]string{"status": "PAUSED"}},
+	} {
+		_, err := client.Do(ctx, req)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Type != ErrorTypeOffline || apiErr.Retryable {
+			t.Fatalf("%s %s: expected offline error, got %v", req.Method, req.Path, err)
+		}
+	}
+	// Under a dry run the mutation is planned before it would need the network.
+	planned, err := client.Do(WithDryRun(ctx, &DryRun{}), Request{Method: http.MethodPost, Path: "42", Version: "v25.0"})
+	if err != nil || planned.Body["id"] != "dry_run_1" {
+		t.Fatalf("expected planned mutation, got %#v %v", planned, err)
+	}
+	if got := sent.Load(); got != sentOnline {
+		t.Fatalf("offline client sent %d request(s)", got-sentOnline)
+	}
+}
@@ -0,0 +1,155 @@
+package graph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DryRun collects the mutations an invocation would have sent. While a
+// context carries one, POST and DELETE requests pass the mutation guards and
+// are recorded instead of sent; reads still go to the API so commands can
+// resolve what they would change, except reads of an object the dry run only
+// simulated, which Graph has never seen and which are answered locally.
+type DryRun struct {
+	mu        sync.Mutex
+	mutations []PlannedMutation
+}
+
+// PlannedMutation is one mutation a dry run skipped. Params exclude
+// credentials.
+type PlannedMutation struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Version string            `json:"version"`
+	Params  map[string]string `json:"params,omitempty"`
+	Upload  *PlannedUpload    `json:"upload,omitempty"`
+	// Batched marks mutations that would have gone out in a batch call.
+	Batched bool `json:"batched,omitempty"`
+	// SimulatedID is the placeholder id returned to the command in place of
+	// the id Meta would have assigned.
+	SimulatedID string `json:"simulated_id"`
+}
+
+type PlannedUpload struct {
+	FieldName string `json:"field_name"`
+	FileName  string `json:"file_name"`
+	Bytes     int    `json:"bytes"`
+	SHA256    string `json:"sha256"`
+}
+
+type dryRunKey struct{}
+
+func WithDryRun(ctx context.Context, dryRun *DryRun) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, dryRun)
+}
+
+// DryRunFromContext returns the dry run ctx carries, or nil outside a dry run.
+func DryRunFromContext(ctx context.Context) *DryRun {
+	if ctx == nil {
+		return nil
+	}
+	dryRun, _ := ctx.Value(dryRunKey{}).(*DryRun)
+	return dryRun
+}
+
+// Mutations returns the planned mutations in the order they were issued.
+func (d *DryRun) Mutations() []PlannedMutation {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]PlannedMutation(nil), d.mutations...)
+}
+
+// plan records a mutation and returns the placeholder response the command
+// sees: {"id": <simulated id>, "success": true}.
+func (d *DryRun) plan(method string, version string, path string, form map[string]string, multipart *MultipartFile, batched bool) map[string]any {
+	params := make(map[string]string, len(form))
+	for key, value := range form {
+		if key == "access_token" || key == "appsecret_proof" {
+			continue
+		}
+		params[key] = value
+	}
+	if len(params) == 0 {
+		params = nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	planned := PlannedMutation{
+		Method:      method,
+		Path:        strings.TrimPrefix(path, "/"),
+		Version:     version,
+		Params:      params,
+		Batched:     batched,
+		SimulatedID: fmt.Sprintf("dry_run_%d", len(d.mutations)+1),
+	}
+	if multipart != nil {
+		sum := sha256.Sum256(multipart.FileBytes)
+		planned.Upload = &PlannedUpload{
+			FieldName: multipart.FieldName,
+			FileName:  multipart.FileName,
+			Bytes:     len(multipart.FileBytes),
+			SHA256:    hex.EncodeToString(sum[:]),
+		}
+	}
+	d.mutations = append(d.mutations, planned)
+	return map[string]any{"id": planned.SimulatedID, "success": true}
+}
+
+// Simulated reports whether id is a placeholder this dry run returned in place
+// of an id Meta would have assigned.
+func (d *DryRun) Simulated(id string) bool {
+	if d == nil {
+		return false
+	}
+	id = strings.TrimSpace(id)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, planned := range d.mutations {
+		if planned.SimulatedID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// readsSimulated reports whether path reads an object the dry run simulated.
+func (d *DryRun) readsSimulated(path string) bool {
+	node, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return d.Simulated(node)
+}
+
+// simulatedRead is the body a read of a simulated object gets: just its id,
+// since there is nothing behind it to report.
+func simulatedRead(path string) map[string]any {
+	node, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return map[string]any{"id": node}
+}
+
+func (d *DryRun) readResponse(path string) *Response {
+	body := simulatedRead(path)
+	return &Response{
+		StatusCode: http.StatusOK,
+		Body:       body,
+		Raw:        []byte(fmt.Sprintf(`{"id":%q}`, body["id"])),
+		Headers:    http.Header{},
+	}
+}
+
+func (d *DryRun) response(method string, version string, req Request) *Response {
+	body := d.plan(method, version, req.Path, req.Form, req.Multipart, false)
+	return &Response{
+		StatusCode: http.StatusOK,
+		Body:       body,
+		Raw:        []byte(fmt.Sprintf(`{"id":%q,"success":true}`, body["id"])),
+		Headers:    http.Header{},
+	}
+}
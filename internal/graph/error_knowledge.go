@@ -0,0 +1,277 @@
+package graph
+
+import "fmt"
+
+const (
+	docErrorHandling     = "https://developers.facebook.com/docs/graph-api/guides/error-handling"
+	docRateLimits        = "https://developers.facebook.com/docs/graph-api/overview/rate-limiting"
+	docAdsRateLimits     = "https://developers.facebook.com/docs/marketing-api/overview/rate-limiting"
+	docAccessTokenErrors = "https://developers.facebook.com/docs/facebook-login/guides/access-tokens/debugging-and-error-handling"
+	docPermissions       = "https://developers.facebook.com/docs/permissions"
+	docAdsErrors         = "https://developers.facebook.com/docs/marketing-api/error-reference"
+	docVersioning        = "https://developers.facebook.com/docs/graph-api/guides/versioning"
+)
+
+// knownError is a knowledge-base entry for a Graph error code. A zero subcode
+// matches every subcode without an entry of its own.
+type knownError struct {
+	code     int
+	subcode  int
+	category string
+	summary  string
+	actions  []string
+	docs     []string
+}
+
+var (
+	retryActions = []string{
+		"Retry with backoff.",
+		"If it persists, shrink the request: fewer fields, a shorter date range or a smaller batch.",
+	}
+	reauthActions = []string{
+		"Run `meta auth validate --profile <name>` to confirm token health.",
+		"Refresh credentials with `meta auth setup` or `meta auth login`.",
+	}
+)
+
+// businessUseCaseLimit builds the entry for a business use case rate limit,
+// which Meta tracks per ad account, page or business and reports in the
+// X-Business-Use-Case-Usage header.
+func businessUseCaseLimit(code int, useCase string, extra ...string) knownError {
+	return knownError{
+		code:     code,
+		category: RemediationCategoryRateLimit,
+		summary:  fmt.Sprintf("The %s rate limit for this business object was reached.", useCase),
+		actions: append([]string{
+			"Wait for estimated_time_to_regain_access from the X-Business-Use-Case-Usage header before retrying.",
+			"Lower the call volume against this object: fewer concurrent automations, larger batches.",
+		}, extra...),
+		docs: []string{docAdsRateLimits},
+	}
+}
+
+var knownErrors = []knownError{
+	{code: 1, category: RemediationCategoryTransient, summary: "Meta reported an unknown error, usually temporary.", actions: retryActions, docs: []string{docErrorHandling}},
+	{code: 2, category: RemediationCategoryTransient, summary: "A Meta service is temporarily unavailable.", actions: retryActions, docs: []string{docErrorHandling}},
+	{
+		code:     4,
+		category: RemediationCategoryRateLimit,
+		summary:  "The app reached its application-level rate limit.",
+		actions: []string{
+			"Wait before retrying; the limit is measured over a rolling hour.",
+			"Spread automation across the hour instead of running it in bursts.",
+		},
+		docs: []string{docRateLimits},
+	},
+	{
+		code:     17,
+		category: RemediationCategoryRateLimit,
+		summary:  "The user's request rate limit was reached.",
+		actions: []string{
+			"Wait before retrying.",
+			"Use a system user token for automation; its limits are separate from a person's.",
+		},
+		docs: []string{docRateLimits},
+	},
+	{
+		code:     32,
+		category: RemediationCategoryRateLimit,
+		summary:  "The Page's request rate limit was reached.",
+		actions:  []string{"Wait before retrying requests made with this Page token."},
+		docs:     []string{docRateLimits},
+	},
+	{
+		code:     341,
+		category: RemediationCategoryRateLimit,
+		summary:  "The app reached a Meta application limit.",
+		actions:  []string{"Wait before retrying and reduce the rate of write calls."},
+		docs:     []string{docRateLimits},
+	},
+	{
+		code:     613,
+		category: RemediationCategoryRateLimit,
+		summary:  "Too many calls to this ad account within the last hour.",
+		actions: []string{
+			"Wait before retrying.",
+			"Group changes with `meta bulk import` or batch requests instead of one call per object.",
+		},
+		docs: []string{docAdsRateLimits},
+	},
+	businessUseCaseLimit(80000, "Ads Insights", "Run large reports asynchronously with `meta insights jobs` instead of synchronous queries."),
+	businessUseCaseLimit(80001, "Pages"),
+	businessUseCaseLimit(80002, "Instagram"),
+	businessUseCaseLimit(80003, "Custom Audience", "Upload users in fewer, larger `meta audience upload-users` runs."),
+	businessUseCaseLimit(80004, "Ads Management", "Group changes with `meta bulk import` instead of one call per object."),
+	businessUseCaseLimit(80005, "Lead Ads"),
+	businessUseCaseLimit(80006, "Messenger"),
+	businessUseCaseLimit(80008, "WhatsApp Business Management"),
+	businessUseCaseLimit(80009, "Catalog Management"),
+	businessUseCaseLimit(80014, "Catalog Batch", "Send more items per `meta catalog upload-items` request instead of more requests."),
+	{code: 102, category: RemediationCategoryAuth, summary: "The session is invalid; the token has to be reissued.", actions: reauthActions, docs: []string{docAccessTokenErrors}},
+	{code: 190, category: RemediationCategoryAuth, summary: "Access token is invalid or expired.", actions: reauthActions, docs: []string{docAccessTokenErrors}},
+	{
+		code:     190,
+		subcode:  458,
+		category: RemediationCategoryAuth,
+		summary:  "The user has not authorized the app, or removed it.",
+		actions:  []string{"Log in again with `meta auth login` and grant the app access."},
+		docs:     []string{docAccessTokenErrors},
+	},
+	{
+		code:     190,
+		subcode:  459,
+		category: RemediationCategoryAuth,
+		summary:  "The user's Facebook account is checkpointed.",
+		actions:  []string{"Log in at facebook.com to clear the checkpoint, then refresh credentials with `meta auth login`."},
+		docs:     []string{docAccessTokenErrors},
+	},
+	{
+		code:     190,
+		subcode:  460,
+		category: RemediationCategoryAuth,
+		summary:  "The token was invalidated because the user changed their password.",
+		actions:  []string{"Refresh credentials with `meta auth login`."},
+		docs:     []string{docAccessTokenErrors},
+	},
+	{
+		code:     190,
+		subcode:  463,
+		category: RemediationCategoryAuth,
+		summary:  "The access token has expired.",
+		actions: []string{
+			"Refresh credentials with `meta auth login`, or `meta auth setup` for a system user.",
+			"Use a long-lived or system user token for automation.",
+		},
+		docs: []string{docAccessTokenErrors},
+	},
+	{
+		code:     190,
+		subcode:  464,
+		category: RemediationCategoryAuth,
+		summary:  "The user's Facebook account is unconfirmed.",
+		actions:  []string{"Confirm the account at facebook.com, then refresh credentials with `meta auth login`."},
+		docs:     []string{docAccessTokenErrors},
+	},
+	{
+		code:     190,
+		subcode:  467,
+		category: RemediationCategoryAuth,
+		summary:  "The access token is invalid: revoked, malformed or issued for another app.",
+		actions:  reauthActions,
+		docs:     []string{docAccessTokenErrors},
+	},
+	{
+		code:     190,
+		subcode:  492,
+		category: RemediationCategoryAuth,
+		summary:  "The session is invalid for this Page; the user may have lost their role on it.",
+		actions: []string{
+			"Confirm the user still has a role on the Page in Business Manager.",
+			"Refresh the Page token with `meta auth page-token`.",
+		},
+		docs: []string{docAccessTokenErrors},
+	},
+	{
+		code:     10,
+		category: RemediationCategoryPermission,
+		summary:  "The app does not have permission for this action, often because a permission or feature has not passed App Review.",
+		actions: []string{
+			"Check the permission's access level for the app in the App Dashboard.",
+			"Confirm the token has access to the target business asset.",
+		},
+		docs: []string{docPermissions},
+	},
+	{
+		code:     200,
+		category: RemediationCategoryPermission,
+		summary:  "Profile token is missing required permissions for this operation.",
+		actions: []string{
+			"Verify required scopes are granted for the active profile.",
+			"Confirm the token has access to the target business asset.",
+		},
+		docs: []string{docPermissions},
+	},
+	{
+		code:     294,
+		category: RemediationCategoryPermission,
+		summary:  "Managing ads requires the ads_management permission.",
+		actions:  []string{"Grant ads_management to the token and record it on the profile with `--scopes`."},
+		docs:     []string{docPermissions},
+	},
+	{
+		code:     100,
+		subcode:  33,
+		category: RemediationCategoryNotFound,
+		summary:  "Referenced object or edge does not exist for this request.",
+		actions: []string{
+			"Check object IDs and endpoint path for typos.",
+			"Ensure the object belongs to the authenticated account context.",
+			"Meta returns the same error for objects the token cannot see; confirm the profile has access to the object.",
+		},
+		docs: []string{docErrorHandling},
+	},
+	{
+		code:     368,
+		category: RemediationCategoryPermission,
+		summary:  "The action was blocked as a policy violation; the account is temporarily restricted.",
+		actions: []string{
+			"Review the account's quality and restrictions in Business Manager.",
+			"Do not retry the same action until the restriction is lifted.",
+		},
+		docs: []string{docErrorHandling},
+	},
+	{
+		code:     506,
+		category: RemediationCategoryConflict,
+		summary:  "Duplicate post: the same content was published moments ago.",
+		actions:  []string{"Change the content, or confirm the earlier post went out before publishing again."},
+		docs:     []string{docErrorHandling},
+	},
+	{
+		code:     2635,
+		category: RemediationCategoryValidation,
+		summary:  "The request used a deprecated Graph API version.",
+		actions:  []string{"Set a supported graph_version on the profile, or pass a newer --version."},
+		docs:     []string{docVersioning, docAdsErrors},
+	},
+}
+
+// lookupKnownError returns the entry for code and subcode, falling back to
+// the code-wide entry.
+func lookupKnownError(code int, subcode int) (knownError, bool) {
+	var fallback *knownError
+	for index := range knownErrors {
+		entry := &knownErrors[index]
+		if entry.code != code {
+			continue
+		}
+		if entry.subcode == subcode && subcode != 0 {
+			return *entry, true
+		}
+		if entry.subcode == 0 {
+			fallback = entry
+		}
+	}
+	if fallback == nil {
+		return knownError{}, false
+	}
+	return *fallback, true
+}
+
+func (e knownError) remediation(blameFields []string) Remediation {
+	reference := fmt.Sprintf("%d", e.code)
+	if e.subcode != 0 {
+		reference = fmt.Sprintf("%d/%d", e.code, e.subcode)
+	}
+	remediation := Remediation{
+		Category:  e.category,
+		Summary:   e.summary,
+		Actions:   append([]string(nil), e.actions...),
+		Reference: reference,
+		Docs:      append([]string(nil), e.docs...),
+	}
+	if e.category == RemediationCategoryValidation && len(blameFields) > 0 {
+		remediation.Fields = blameFields
+	}
+	return remediation
+}
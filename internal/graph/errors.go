@@ -14,6 +14,8 @@ const (
 	RemediationCategoryNotFound   = "not_found"
 	RemediationCategoryConflict   = "conflict"
 	RemediationCategoryTransient  = "transient"
+	RemediationCategoryCanceled   = "canceled"
+	RemediationCategoryOffline    = "offline"
 	RemediationCategoryUnknown    = "unknown"
 )
 
@@ -22,6 +24,10 @@ type Remediation struct {
 	Summary  string   `json:"summary"`
 	Actions  []string `json:"actions,omitempty"`
 	Fields   []string `json:"fields,omitempty"`
+	// Reference is the knowledge-base entry that matched the error, "code" or
+	// "code/subcode"; Docs link Meta's documentation for it.
+	Reference string   `json:"reference,omitempty"`
+	Docs      []string `json:"docs,omitempty"`
 }
 
 type APIError struct {
@@ -62,11 +68,19 @@ func (e *TransientError) Error() string {
 	return e.Message
 }
 
+// ClassifyRemediation prefers the knowledge-base entry for code and subcode,
+// except that an HTTP 429 always reads as a rate limit, and falls back to
+// broad categories for codes the knowledge base does not cover.
 func ClassifyRemediation(statusCode int, code int, subcode int, _ string, diagnostics map[string]any) Remediation {
 	blameFields := extractBlameFields(diagnostics)
+	if statusCode != 429 {
+		if known, ok := lookupKnownError(code, subcode); ok {
+			return known.remediation(blameFields)
+		}
+	}
 
 	switch {
-	case statusCode == 429 || code == 4 || code == 17 || code == 32 || code == 613:
+	case statusCode == 429:
 		return Remediation{
 			Category: RemediationCategoryRateLimit,
 			Summary:  "Meta API rate limits were reached.",
@@ -75,33 +89,6 @@ func ClassifyRemediation(statusCode int, code int, subcode int, _ string, diagno
 				"Reduce request concurrency for this account/app.",
 			},
 		}
-	case code == 190:
-		return Remediation{
-			Category: RemediationCategoryAuth,
-			Summary:  "Access token is invalid or expired.",
-			Actions: []string{
-				"Run `meta auth validate --profile <name>` to confirm token health.",
-				"Refresh credentials with `meta auth setup` or `meta auth login`.",
-			},
-		}
-	case code == 200 || code == 10:
-		return Remediation{
-			Category: RemediationCategoryPermission,
-			Summary:  "Profile token is missing required permissions for this operation.",
-			Actions: []string{
-				"Verify required scopes are granted for the active profile.",
-				"Confirm the token has access to the target business asset.",
-			},
-		}
-	case code == 100 && subcode == 33:
-		return Remediation{
-			Category: RemediationCategoryNotFound,
-			Summary:  "Referenced object or edge does not exist for this request.",
-			Actions: []string{
-				"Check object IDs and endpoint path for typos.",
-				"Ensure the object belongs to the authenticated account context.",
-			},
-		}
 	case code == 100:
 		remediation := Remediation{
 			Category: RemediationCategoryValidation,
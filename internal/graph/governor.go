@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"context"
+	"sync"
+)
+
+// usageKeys are the percentage fields Meta reports in X-App-Usage,
+// X-Page-Usage and X-Ad-Account-Usage. Other fields (reset_time_duration,
+// ads_api_access_tier) are not percentages and are ignored.
+var usageKeys = []string{"call_count", "total_cputime", "total_time", "acc_id_util_pct"}
+
+// Governor bounds how many Graph requests a client runs at once. The bound
+// starts at the configured maximum and shrinks as the usage headers of recent
+// responses approach Meta's throttling threshold, so parallel reads back off
+// before they are rate limited rather than after.
+type Governor struct {
+	max int
+
+	mu       sync.Mutex
+	inFlight int
+	usage    float64
+	released chan struct{}
+}
+
+func NewGovernor(max int) *Governor {
+	if max < 1 {
+		max = 1
+	}
+	return &Governor{max: max, released: make(chan struct{})}
+}
+
+// Limit is the number of requests currently allowed in flight.
+func (g *Governor) Limit() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.limitLocked()
+}
+
+func (g *Governor) limitLocked() int {
+	limit := g.max
+	switch {
+	case g.usage >= 90:
+		limit = 1
+	case g.usage >= 75:
+		limit = g.max / 4
+	case g.usage >= 50:
+		limit = g.max / 2
+	}
+	if limit < 1 {
+		limit = 1
+	}
+	return limit
+}
+
+// Acquire blocks until a request slot is free or ctx is done.
+func (g *Governor) Acquire(ctx context.Context) (func(), error) {
+	for {
+		g.mu.Lock()
+		if g.inFlight < g.limitLocked() {
+			g.inFlight++
+			g.mu.Unlock()
+			return g.release, nil
+		}
+		released := g.released
+		g.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+func (g *Governor) release() {
+	g.mu.Lock()
+	g.inFlight--
+	close(g.released)
+	g.released = make(chan struct{})
+	g.mu.Unlock()
+}
+
+// Observe records the usage reported by a response. The latest response wins:
+// usage drops as Meta's rolling window moves on.
+func (g *Governor) Observe(rateLimit RateLimit) {
+	usage := 0.0
+	for _, header := range []map[string]any{rateLimit.AppUsage, rateLimit.PageUsage, rateLimit.AdAccountUsage} {
+		for _, key := range usageKeys {
+			if value, ok := header[key].(float64); ok && value > usage {
+				usage = value
+			}
+		}
+	}
+	g.mu.Lock()
+	g.usage = usage
+	g.mu.Unlock()
+}
+
+// WithGovernor returns a copy of the client whose requests are bounded by
+// governor. The receiver is left unchanged.
+func (c *Client) WithGovernor(governor *Governor) *Client {
+	governed := *c
+	governed.Governor = governor
+	return &governed
+}
@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bilalbayram/metacli/internal/config"
+)
+
+// MutationGuard inspects a POST or DELETE before it is sent. A returned error
+// blocks the request; the mutation's Response and Err are always nil.
+type MutationGuard func(ctx context.Context, mutation Mutation) error
+
+type namedMutationGuard struct {
+	name  string
+	guard MutationGuard
+}
+
+var (
+	mutationGuardsMu sync.RWMutex
+	mutationGuards   []namedMutationGuard
+)
+
+// SetMutationGuard installs the process-wide guard registered under name,
+// replacing an earlier one of the same name. Guards run in the order they were
+// first registered; passing nil removes the guard.
+func SetMutationGuard(name string, guard MutationGuard) {
+	mutationGuardsMu.Lock()
+	defer mutationGuardsMu.Unlock()
+	for index, existing := range mutationGuards {
+		if existing.name != name {
+			continue
+		}
+		if guard == nil {
+			mutationGuards = append(mutationGuards[:index:index], mutationGuards[index+1:]...)
+			return
+		}
+		mutationGuards[index].guard = guard
+		return
+	}
+	if guard != nil {
+		mutationGuards = append(mutationGuards, namedMutationGuard{name: name, guard: guard})
+	}
+}
+
+func (c *Client) guardMutation(ctx context.Context, method string, version string, req Request) error {
+	if method == http.MethodGet {
+		return nil
+	}
+	mutationGuardsMu.RLock()
+	guards := append([]namedMutationGuard(nil), mutationGuards...)
+	mutationGuardsMu.RUnlock()
+	for _, current := range guards {
+		if err := current.guard(ctx, Mutation{
+			Method:    method,
+			Path:      req.Path,
+			Version:   version,
+			Form:      req.Form,
+			Multipart: req.Multipart,
+			Read:      c.guardReader(version, req.AccessToken, req.AppSecret),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// guardBatch runs the guards over every mutation of a batch, so batching does
+// not bypass them.
+func (c *Client) guardBatch(ctx context.Context, version string, accessToken string, appSecret string, requests []BatchRequest) error {
+	if version == "" {
+		version = config.DefaultGraphVersion
+	}
+	for _, req := range requests {
+		method := strings.ToUpper(strings.TrimSpace(req.Method))
+		if err := c.guardMutation(ctx, method, version, Request{Method: method, Path: req.Path, Form: req.Params, AccessToken: accessToken, AppSecret: appSecret}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) guardReader(version string, accessToken string, appSecret string) func(context.Context, string, map[string]string) (*Response, error) {
+	return func(ctx context.Context, path string, query map[string]string) (*Response, error) {
+		return c.Do(ctx, Request{
+			Method:      http.MethodGet,
+			Path:        path,
+			Version:     version,
+			Query:       query,
+			AccessToken: accessToken,
+			AppSecret:   appSecret,
+		})
+	}
+}
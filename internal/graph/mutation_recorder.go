@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Mutation describes one executed non-GET Graph request after its final
+// attempt. Response is nil when the request failed.
+type Mutation struct {
+	Method    string
+	Path      string
+	Version   string
+	Form      map[string]string
+	Multipart *MultipartFile
+	Response  *Response
+	Err       error
+	// Read issues a GET with the credentials of the mutation, so a guard can
+	// look up the state a mutation changes. It is only set for guards.
+	Read func(ctx context.Context, path string, query map[string]string) (*Response, error)
+}
+
+// MutationRecorder receives every executed mutation. It must not block for long:
+// it runs on the request path.
+type MutationRecorder func(ctx context.Context, mutation Mutation)
+
+type namedMutationObserver struct {
+	name     string
+	observer MutationRecorder
+}
+
+var (
+	mutationRecorderMu sync.RWMutex
+	mutationRecorder   MutationRecorder
+	mutationObservers  []namedMutationObserver
+)
+
+// SetMutationRecorder installs the process-wide recorder notified after every
+// POST or DELETE. Passing nil disables recording.
+func SetMutationRecorder(recorder MutationRecorder) {
+	mutationRecorderMu.Lock()
+	defer mutationRecorderMu.Unlock()
+	mutationRecorder = recorder
+}
+
+// SetMutationObserver installs a process-wide observer under name that is
+// notified after the recorder, replacing any observer of that name; nil
+// removes it. Observers run in installation order.
+func SetMutationObserver(name string, observer MutationRecorder) {
+	mutationRecorderMu.Lock()
+	defer mutationRecorderMu.Unlock()
+	for index, existing := range mutationObservers {
+		if existing.name != name {
+			continue
+		}
+		if observer == nil {
+			mutationObservers = append(mutationObservers[:index:index], mutationObservers[index+1:]...)
+			return
+		}
+		mutationObservers[index].observer = observer
+		return
+	}
+	if observer != nil {
+		mutationObservers = append(mutationObservers, namedMutationObserver{name: name, observer: observer})
+	}
+}
+
+func recordMutation(ctx context.Context, method string, version string, req Request, response *Response, err error) {
+	if method == http.MethodGet {
+		return
+	}
+	mutationRecorderMu.RLock()
+	current := mutationRecorder
+	observers := append([]namedMutationObserver(nil), mutationObservers...)
+	mutationRecorderMu.RUnlock()
+	if current == nil && len(observers) == 0 {
+		return
+	}
+	mutation := Mutation{
+		Method:    method,
+		Path:      req.Path,
+		Version:   version,
+		Form:      req.Form,
+		Multipart: req.Multipart,
+		Response:  response,
+		Err:       err,
+	}
+	if current != nil {
+		current(ctx, mutation)
+	}
+	for _, observer := range observers {
+		observer.observer(ctx, mutation)
+	}
+}
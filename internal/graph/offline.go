@@ -0,0 +1,261 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/transport"
+)
+
+// ErrorTypeOffline marks a request an offline invocation could not serve
+// without the network.
+const ErrorTypeOffline = "offline_unavailable"
+
+// ReadKey identifies a cached GET. Query never carries credentials.
+type ReadKey struct {
+	BaseURL string            `json:"base_url"`
+	Version string            `json:"version"`
+	Path    string            `json:"path"`
+	Query   map[string]string `json:"query,omitempty"`
+}
+
+// CachedRead is a GET response kept by a ReadCache. Body has every
+// access_token field removed.
+type CachedRead struct {
+	StatusCode int
+	Body       map[string]any
+	CachedAt   time.Time
+}
+
+// ReadCache keeps successful GET responses so an offline invocation can serve
+// reads without the network. Load returns nil, nil on a miss.
+type ReadCache interface {
+	Load(ctx context.Context, key ReadKey) (*CachedRead, error)
+	Store(ctx context.Context, key ReadKey, read CachedRead) error
+}
+
+var (
+	readCacheMu sync.RWMutex
+	readCache   ReadCache
+)
+
+// SetReadCache installs the process-wide cache every successful GET is written
+// through to and offline reads are served from. Passing nil disables caching.
+func SetReadCache(cache ReadCache) {
+	readCacheMu.Lock()
+	defer readCacheMu.Unlock()
+	readCache = cache
+}
+
+func currentReadCache() ReadCache {
+	readCacheMu.RLock()
+	defer readCacheMu.RUnlock()
+	return readCache
+}
+
+// Offline collects the cached reads served to an offline invocation. While a
+// context carries one, GETs are answered from the read cache, anything else
+// fails with ErrorTypeOffline, and the shared transport refuses every request.
+type Offline struct {
+	Now func() time.Time
+
+	mu    sync.Mutex
+	reads []OfflineRead
+}
+
+// OfflineRead is one GET served from the cache, with how stale it was.
+type OfflineRead struct {
+	Path       string `json:"path"`
+	Version    string `json:"version"`
+	CachedAt   string `json:"cached_at"`
+	AgeSeconds int64  `json:"age_seconds"`
+}
+
+type offlineKey struct{}
+
+func WithOffline(ctx context.Context, offline *Offline) context.Context {
+	return context.WithValue(transport.WithOffline(ctx), offlineKey{}, offline)
+}
+
+// OfflineFromContext returns the offline state ctx carries, or nil online.
+func OfflineFromContext(ctx context.Context) *Offline {
+	if ctx == nil {
+		return nil
+	}
+	offline, _ := ctx.Value(offlineKey{}).(*Offline)
+	return offline
+}
+
+// Reads returns the cached reads served so far, in the order they were served.
+func (o *Offline) Reads() []OfflineRead {
+	if o == nil {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]OfflineRead(nil), o.reads...)
+}
+
+func (o *Offline) record(key ReadKey, cachedAt time.Time) {
+	now := time.Now
+	if o.Now != nil {
+		now = o.Now
+	}
+	age := now().Sub(cachedAt)
+	if age < 0 {
+		age = 0
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.reads = append(o.reads, OfflineRead{
+		Path:       key.Path,
+		Version:    key.Version,
+		CachedAt:   cachedAt.UTC().Format(time.RFC3339),
+		AgeSeconds: int64(age / time.Second),
+	})
+}
+
+func (c *Client) readKey(version string, path string, query map[string]string) ReadKey {
+	key := ReadKey{
+		BaseURL: c.BaseURL,
+		Version: version,
+		Path:    strings.TrimPrefix(path, "/"),
+	}
+	for name, value := range query {
+		if name == "access_token" || name == "appsecret_proof" {
+			continue
+		}
+		if key.Query == nil {
+			key.Query = map[string]string{}
+		}
+		key.Query[name] = value
+	}
+	return key
+}
+
+// readOffline answers a request of an offline invocation from the read cache.
+func (c *Client) readOffline(ctx context.Context, offline *Offline, method string, version string, req Request) (*Response, error) {
+	if method != http.MethodGet {
+		return nil, offlineError(method, req.Path, fmt.Sprintf("%s %s needs the network, which --offline disables", method, req.Path))
+	}
+	read, err := c.loadCachedRead(ctx, offline, version, req.Path, req.Query)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(read.Body)
+	if err != nil {
+		return nil, fmt.Errorf("encode cached read: %w", err)
+	}
+	return &Response{
+		StatusCode: read.StatusCode,
+		Body:       read.Body,
+		Raw:        raw,
+		Headers:    http.Header{},
+	}, nil
+}
+
+func (c *Client) loadCachedRead(ctx context.Context, offline *Offline, version string, path string, query map[string]string) (*CachedRead, error) {
+	cache := currentReadCache()
+	if cache == nil {
+		return nil, offlineError(http.MethodGet, path, fmt.Sprintf("GET %s cannot be served offline: the read cache is disabled", path))
+	}
+	if readsPersonalData(path, query) {
+		return nil, offlineError(http.MethodGet, path, fmt.Sprintf("GET %s cannot be served offline: it returns personal data, which is never cached", path))
+	}
+	key := c.readKey(version, path, query)
+	read, err := cache.Load(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("load cached read of %s: %w", path, err)
+	}
+	if read == nil {
+		return nil, offlineError(http.MethodGet, path, fmt.Sprintf("GET %s has no cached response; run the command online once to cache it", path))
+	}
+	offline.record(key, read.CachedAt)
+	return read, nil
+}
+
+// storeRead writes a successful GET through to the read cache. Caching is best
+// effort: a failed write never fails the read.
+func (c *Client) storeRead(ctx context.Context, version string, path string, query map[string]string, statusCode int, body map[string]any) {
+	cache := currentReadCache()
+	if cache == nil || body == nil || readsPersonalData(path, query) {
+		return
+	}
+	_ = cache.Store(ctx, c.readKey(version, path, query), CachedRead{
+		StatusCode: statusCode,
+		Body:       withoutAccessTokens(body).(map[string]any),
+		CachedAt:   time.Now().UTC(),
+	})
+}
+
+// personalDataEdges list people's own data: lead form answers and message
+// threads. Reads of them are never written to the read cache.
+var personalDataEdges = map[string]bool{
+	"conversations": true,
+	"leads":         true,
+	"messages":      true,
+}
+
+// readsPersonalData reports whether a GET of path returns personal data,
+// either from a personal-data edge or by asking a lead for its answers.
+func readsPersonalData(path string, query map[string]string) bool {
+	trimmed := strings.Trim(path, "/")
+	if personalDataEdges[trimmed[strings.LastIndex(trimmed, "/")+1:]] {
+		return true
+	}
+	for _, field := range strings.Split(query["fields"], ",") {
+		if strings.TrimSpace(field) == "field_data" {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutAccessTokens copies value without access_token fields, such as the
+// page tokens /me/accounts returns, so they never reach the cache on disk.
+func withoutAccessTokens(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(typed))
+		for key, item := range typed {
+			if key == "access_token" {
+				continue
+			}
+			out[key] = withoutAccessTokens(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(typed))
+		for index, item := range typed {
+			out[index] = withoutAccessTokens(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+func offlineError(method string, path string, message string) *APIError {
+	return &APIError{
+		Type:      ErrorTypeOffline,
+		Message:   message,
+		Retryable: false,
+		Remediation: &Remediation{
+			Category: RemediationCategoryOffline,
+			Summary:  "This request needs the network, which --offline disables.",
+			Actions: []string{
+				"Rerun the command without --offline.",
+				"To read offline, run the same read online once so its response is cached.",
+			},
+		},
+		Diagnostics: map[string]any{
+			"method": method,
+			"path":   strings.TrimPrefix(path, "/"),
+		},
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/bilalbayram/metacli/internal/progress"
 )
 
 type PaginationOptions struct {
@@ -13,6 +16,10 @@ type PaginationOptions struct {
 	Limit      int
 	PageSize   int
 	Stream     bool
+	// Concurrency above 1 fetches offset-paginated results that many pages at
+	// a time, bounded by a Governor. Cursor-paginated results are always read
+	// one page after the other because each cursor comes from the page before.
+	Concurrency int
 }
 
 type PaginationResult struct {
@@ -42,22 +49,14 @@ func (c *Client) FetchWithPagination(ctx context.Context, req Request, options P
 		}
 		result.PagesFetched++
 
-		items := extractDataItems(resp.Body)
-		for _, item := range items {
-			if options.Limit > 0 && result.ItemsFetched >= options.Limit {
-				return result, nil
-			}
-			result.ItemsFetched++
-			if onItem != nil {
-				if err := onItem(item); err != nil {
-					return nil, err
-				}
-			}
+		done, err := emitPageItems(resp.Body, options.Limit, result, onItem)
+		if err != nil {
+			return nil, err
 		}
-
+		progress.FromContext(ctx).Set(int64(result.ItemsFetched), 0)
 		next := extractNextPage(resp.Body)
 		result.Next = next
-		if !options.FollowNext || next == "" {
+		if done || !options.FollowNext || next == "" {
 			return result, nil
 		}
 
@@ -65,10 +64,115 @@ func (c *Client) FetchWithPagination(ctx context.Context, req Request, options P
 		if err != nil {
 			return nil, err
 		}
+		if options.Concurrency > 1 {
+			if offset, stride, ok := offsetPaging(nextReq); ok {
+				return c.fetchOffsetPages(ctx, nextReq, offset, stride, options, result, onItem)
+			}
+		}
 		current = nextReq
 	}
 }
 
+// fetchOffsetPages reads an offset-paginated listing in waves of concurrent
+// requests, starting at offset and stepping by stride. Pages are emitted in
+// order; the first empty page or page without paging.next ends the listing and
+// any pages fetched past it are discarded.
+func (c *Client) fetchOffsetPages(ctx context.Context, req Request, offset int, stride int, options PaginationOptions, result *PaginationResult, onItem func(map[string]any) error) (*PaginationResult, error) {
+	governed := c
+	if governed.Governor == nil {
+		governed = c.WithGovernor(NewGovernor(options.Concurrency))
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for {
+		// A shared Governor may allow more requests than this listing asked for.
+		wave := min(governed.Governor.Limit(), options.Concurrency)
+		if options.Limit > 0 {
+			remaining := options.Limit - result.ItemsFetched
+			if pages := (remaining + stride - 1) / stride; pages < wave {
+				wave = pages
+			}
+		}
+		if wave < 1 {
+			wave = 1
+		}
+
+		responses := make([]*Response, wave)
+		errs := make([]error, wave)
+		var group sync.WaitGroup
+		for index := 0; index < wave; index++ {
+			pageReq := withOffset(req, offset+index*stride)
+			group.Add(1)
+			go func(index int) {
+				defer group.Done()
+				responses[index], errs[index] = governed.Do(ctx, pageReq)
+			}(index)
+		}
+		group.Wait()
+
+		for index := 0; index < wave; index++ {
+			if errs[index] != nil {
+				return nil, errs[index]
+			}
+			result.PagesFetched++
+			items := len(extractDataItems(responses[index].Body))
+			done, err := emitPageItems(responses[index].Body, options.Limit, result, onItem)
+			if err != nil {
+				return nil, err
+			}
+			progress.FromContext(ctx).Set(int64(result.ItemsFetched), 0)
+			next := extractNextPage(responses[index].Body)
+			result.Next = next
+			if done || next == "" || items == 0 {
+				return result, nil
+			}
+		}
+		offset += wave * stride
+	}
+}
+
+// emitPageItems hands the page's data items to onItem and reports whether
+// options.Limit has been reached.
+func emitPageItems(payload map[string]any, limit int, result *PaginationResult, onItem func(map[string]any) error) (bool, error) {
+	for _, item := range extractDataItems(payload) {
+		if limit > 0 && result.ItemsFetched >= limit {
+			return true, nil
+		}
+		result.ItemsFetched++
+		if onItem != nil {
+			if err := onItem(item); err != nil {
+				return false, err
+			}
+		}
+	}
+	return false, nil
+}
+
+// offsetPaging reports whether a follow-up request pages by offset, and if so
+// where it starts and how far each page advances.
+func offsetPaging(req Request) (int, int, bool) {
+	offset, err := strconv.Atoi(req.Query["offset"])
+	if err != nil || offset < 0 {
+		return 0, 0, false
+	}
+	stride, err := strconv.Atoi(req.Query["limit"])
+	if err != nil || stride <= 0 {
+		return 0, 0, false
+	}
+	return offset, stride, true
+}
+
+func withOffset(req Request, offset int) Request {
+	query := make(map[string]string, len(req.Query))
+	for key, value := range req.Query {
+		query[key] = value
+	}
+	query["offset"] = strconv.Itoa(offset)
+	req.Query = query
+	return req
+}
+
 func extractDataItems(payload map[string]any) []map[string]any {
 	raw, ok := payload["data"].([]any)
 	if !ok {
@@ -3,9 +3,13 @@ package graph
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestFetchWithPagination(t *testing.T) {
@@ -53,6 +57,154 @@ func TestFetchWithPagination(t *testing.T) {
 	}
 }
 
+func TestFetchWithPaginationFetchesOffsetPagesConcurrently(t *testing.T) {
+	t.Parallel()
+
+	const total = 95
+	var inFlight, peak, requests atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		data := []map[string]any{}
+		for id := offset; id < offset+10 && id < total; id++ {
+			data = append(data, map[string]any{"id": strconv.Itoa(id)})
+		}
+		payload := map[string]any{"data": data}
+		if offset+10 < total {
+			payload["paging"] = map[string]any{
+				"next": fmt.Sprintf("%s/v25.0/act_1/customaudiences?limit=10&offset=%d", server.URL, offset+10),
+			}
+		}
+		_ = json.NewEncoder(w).Encode(payload)
+	}))
+	defer server.Close()
+
+	var seen []string
+	client := NewClient(server.Client(), server.URL)
+	result, err := client.FetchWithPagination(context.Background(), Request{
+		Method:  "GET",
+		Path:    "act_1/customaudiences",
+		Version: "v25.0",
+	}, PaginationOptions{
+		FollowNext:  true,
+		PageSize:    10,
+		Concurrency: 4,
+	}, func(item map[string]any) error {
+		seen = append(seen, item["id"].(string))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("fetch with pagination: %v", err)
+	}
+	if len(seen) != total || result.ItemsFetched != total || result.PagesFetched != 10 {
+		t.Fatalf("unexpected result: items=%d pages=%d seen=%d", result.ItemsFetched, result.PagesFetched, len(seen))
+	}
+	for index, id := range seen {
+		if id != strconv.Itoa(index) {
+			t.Fatalf("expected items in order, got %s at %d", id, index)
+		}
+	}
+	if peak.Load() < 2 || peak.Load() > 4 {
+		t.Fatalf("expected between 2 and 4 concurrent requests, peak was %d", peak.Load())
+	}
+	// One sequential first page, then three waves of four; the last wave
+	// overshoots the final page by two requests.
+	if requests.Load() != 13 {
+		t.Fatalf("expected 13 requests, got %d", requests.Load())
+	}
+}
+
+func TestFetchWithPaginationKeepsConcurrencyUnderASharedGovernor(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, peak atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		payload := map[string]any{"data": []map[string]any{{"id": strconv.Itoa(offset)}}}
+		if offset < 9 {
+			payload["paging"] = map[string]any{
+				"next": fmt.Sprintf("%s/v25.0/act_1/customaudiences?limit=1&offset=%d", server.URL, offset+1),
+			}
+		}
+		_ = json.NewEncoder(w).Encode(payload)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL).WithGovernor(NewGovernor(8))
+	result, err := client.FetchWithPagination(context.Background(), Request{
+		Method:  "GET",
+		Path:    "act_1/customaudiences",
+		Version: "v25.0",
+	}, PaginationOptions{
+		FollowNext:  true,
+		PageSize:    1,
+		Concurrency: 2,
+	}, func(map[string]any) error { return nil })
+	if err != nil {
+		t.Fatalf("fetch with pagination: %v", err)
+	}
+	if result.ItemsFetched != 10 {
+		t.Fatalf("expected 10 items, got %d", result.ItemsFetched)
+	}
+	if peak.Load() != 2 {
+		t.Fatalf("expected the governor's 8 slots to be capped at --concurrency 2, peak was %d", peak.Load())
+	}
+}
+
+func TestGovernorShrinksAsUsageClimbs(t *testing.T) {
+	t.Parallel()
+
+	governor := NewGovernor(8)
+	if governor.Limit() != 8 {
+		t.Fatalf("expected full limit, got %d", governor.Limit())
+	}
+	governor.Observe(RateLimit{AppUsage: map[string]any{"call_count": float64(55), "total_time": float64(10)}})
+	if governor.Limit() != 4 {
+		t.Fatalf("expected half limit at 55%%, got %d", governor.Limit())
+	}
+	governor.Observe(RateLimit{AdAccountUsage: map[string]any{"acc_id_util_pct": float64(93), "reset_time_duration": float64(300)}})
+	if governor.Limit() != 1 {
+		t.Fatalf("expected a single slot at 93%%, got %d", governor.Limit())
+	}
+
+	release, err := governor.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := governor.Acquire(ctx); err == nil {
+		t.Fatal("expected second acquire to wait for the held slot")
+	}
+	release()
+	governor.Observe(RateLimit{})
+	if governor.Limit() != 8 {
+		t.Fatalf("expected limit to recover, got %d", governor.Limit())
+	}
+}
+
 func TestValidateBatchRequestsRejectsUnsupportedMethods(t *testing.T) {
 	t.Parallel()
 
@@ -63,3 +215,44 @@ func TestValidateBatchRequestsRejectsUnsupportedMethods(t *testing.T) {
 		t.Fatal("expected validation error")
 	}
 }
+
+func TestExecuteBatchReportsItemErrors(t *testing.T) {
+	t.Parallel()
+
+	var entries []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		if err := json.Unmarshal([]byte(r.PostForm.Get("batch")), &entries); err != nil {
+			t.Fatalf("decode batch payload: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"code": 200, "body": `{"id":"c1"}`},
+			{"code": 400, "body": `{"error":{"message":"Invalid parameter","type":"OAuthException","code":100}}`},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL)
+	results, err := client.ExecuteBatch(context.Background(), "v25.0", "token", "", []BatchRequest{
+		{Method: "POST", Path: "act_1/campaigns", Params: map[string]string{"name": "Launch"}},
+		{Method: "POST", Path: "act_1/campaigns", Params: map[string]string{"name": "Broken"}},
+	})
+	if err != nil {
+		t.Fatalf("execute batch: %v", err)
+	}
+	if entries[0]["body"] != "name=Launch" || entries[0]["relative_url"] != "act_1/campaigns" {
+		t.Fatalf("expected POST params in body, got %v", entries[0])
+	}
+	if results[0].Error != nil || results[0].Body["id"] != "c1" {
+		t.Fatalf("unexpected first result %#v", results[0])
+	}
+	if results[1].Error == nil || results[1].Error.Code != 100 {
+		t.Fatalf("expected item error, got %#v", results[1])
+	}
+
+	if _, err := client.ExecuteBatch(context.Background(), "v25.0", "token", "", []BatchRequest{{Method: "PATCH", Path: "me"}}); err == nil {
+		t.Fatal("expected unsupported method error")
+	}
+}
@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestLog describes one attempt of a Graph request for debug logging. Query
+// and Form are the caller's params before credentials are attached; loggers
+// must still redact them, since callers may pass tokens as params.
+type RequestLog struct {
+	Method     string
+	URL        string
+	Query      map[string]string
+	Form       map[string]string
+	Attempt    int
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// RequestLogger receives every request attempt. It runs on the request path.
+type RequestLogger func(ctx context.Context, entry RequestLog)
+
+var (
+	requestLoggerMu sync.RWMutex
+	requestLogger   RequestLogger
+)
+
+// SetRequestLogger installs the process-wide request logger used by --debug.
+// Passing nil disables it.
+func SetRequestLogger(logger RequestLogger) {
+	requestLoggerMu.Lock()
+	defer requestLoggerMu.Unlock()
+	requestLogger = logger
+}
+
+func (c *Client) logRequest(ctx context.Context, method string, version string, req Request, attempt int, started time.Time, response *Response, err error) {
+	requestLoggerMu.RLock()
+	current := requestLogger
+	requestLoggerMu.RUnlock()
+	if current == nil {
+		return
+	}
+	entry := RequestLog{
+		Method:   method,
+		URL:      strings.TrimSuffix(c.BaseURL, "/") + "/" + version + "/" + strings.TrimPrefix(req.Path, "/"),
+		Query:    req.Query,
+		Form:     req.Form,
+		Attempt:  attempt,
+		Duration: time.Since(started),
+		Err:      err,
+	}
+	if response != nil {
+		entry.StatusCode = response.StatusCode
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		entry.StatusCode = apiErr.StatusCode
+	}
+	current(ctx, entry)
+}
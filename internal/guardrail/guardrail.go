@@ -0,0 +1,241 @@
+// Package guardrail enforces spend ceilings on budget-bearing Graph mutations.
+// A policy file caps daily and lifetime budgets per ad account, campaign or
+// profile label; a mutation that exceeds a matching ceiling is blocked before it
+// is sent, whatever confirmation flags the command was given.
+package guardrail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	PolicySchemaVersion = 1
+
+	errorTypeViolation = "spend_policy_violation"
+	errorCodeViolation = 422600
+)
+
+// ceilingParams maps the budget params a mutation can carry to the ceiling
+// that bounds them.
+var ceilingParams = []string{"daily_budget", "lifetime_budget"}
+
+// Policy is the spend guardrail policy file.
+type Policy struct {
+	SchemaVersion int    `yaml:"schema_version"`
+	Rules         []Rule `yaml:"rules"`
+}
+
+// Rule caps budgets, in minor currency units, for the mutations it matches. A
+// rule without accounts, campaigns or labels matches every mutation.
+type Rule struct {
+	Name string `yaml:"name"`
+	// Accounts lists ad account ids, with or without the act_ prefix.
+	Accounts []string `yaml:"accounts,omitempty"`
+	// Campaigns lists campaign ids; they match updates of the campaign and ad
+	// sets created under it.
+	Campaigns []string `yaml:"campaigns,omitempty"`
+	// Labels is a profile selector such as "env=prod,client=acme".
+	Labels            string `yaml:"labels,omitempty"`
+	MaxDailyBudget    int64  `yaml:"max_daily_budget,omitempty"`
+	MaxLifetimeBudget int64  `yaml:"max_lifetime_budget,omitempty"`
+
+	selector *config.Selector
+}
+
+// Finding is one ceiling a mutation exceeds.
+type Finding struct {
+	Rule   string `json:"rule"`
+	Param  string `json:"param"`
+	Amount int64  `json:"amount"`
+	Limit  int64  `json:"limit"`
+}
+
+func DefaultPolicyPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "guardrails.yaml"), nil
+}
+
+// LoadPolicy returns nil without error when no policy file exists, which
+// leaves the guardrails off.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read spend guardrail policy %s: %w", path, err)
+	}
+	policy := &Policy{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(policy); err != nil {
+		return nil, fmt.Errorf("decode spend guardrail policy %s: %w", path, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid spend guardrail policy %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+func (p *Policy) Validate() error {
+	if p.SchemaVersion != PolicySchemaVersion {
+		return fmt.Errorf("unsupported schema_version=%d (expected %d)", p.SchemaVersion, PolicySchemaVersion)
+	}
+	names := map[string]struct{}{}
+	for index := range p.Rules {
+		rule := &p.Rules[index]
+		rule.Name = strings.TrimSpace(rule.Name)
+		if rule.Name == "" {
+			return fmt.Errorf("rules[%d]: name is required", index)
+		}
+		if _, exists := names[rule.Name]; exists {
+			return fmt.Errorf("rules[%d]: duplicate rule name %q", index, rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+		if rule.MaxDailyBudget < 0 || rule.MaxLifetimeBudget < 0 {
+			return fmt.Errorf("rule %q: budget ceilings must be >= 0", rule.Name)
+		}
+		if rule.MaxDailyBudget == 0 && rule.MaxLifetimeBudget == 0 {
+			return fmt.Errorf("rule %q: set max_daily_budget or max_lifetime_budget", rule.Name)
+		}
+		for accountIndex, account := range rule.Accounts {
+			rule.Accounts[accountIndex] = strings.TrimPrefix(strings.TrimSpace(account), "act_")
+		}
+		if labels := strings.TrimSpace(rule.Labels); labels != "" {
+			selector, err := config.ParseSelector(labels)
+			if err != nil {
+				return fmt.Errorf("rule %q: %w", rule.Name, err)
+			}
+			rule.selector = &selector
+		}
+	}
+	return nil
+}
+
+// Target is what a mutation is known to touch. Account is empty when the
+// request addresses an object by id, such as a campaign update.
+type Target struct {
+	Account  string
+	Campaign string
+	Labels   map[string]string
+}
+
+// TargetOf derives the account and campaign a mutation touches from its path
+// and params.
+func TargetOf(mutation graph.Mutation) Target {
+	target := Target{Campaign: strings.TrimSpace(mutation.Form["campaign_id"])}
+	segments := strings.Split(strings.Trim(mutation.Path, "/"), "/")
+	if strings.HasPrefix(segments[0], "act_") {
+		target.Account = strings.TrimPrefix(segments[0], "act_")
+	} else if len(segments) == 1 && target.Campaign == "" {
+		target.Campaign = segments[0]
+	}
+	return target
+}
+
+func (r *Rule) matches(target Target) bool {
+	if r.selector != nil && !r.selector.Matches(target.Labels) {
+		return false
+	}
+	if len(r.Accounts) == 0 && len(r.Campaigns) == 0 {
+		return true
+	}
+	// A request addressed by object id does not name its account, so account
+	// ceilings apply to it too; the guardrail errs on the side of blocking.
+	if len(r.Accounts) > 0 && (target.Account == "" || containsID(r.Accounts, target.Account)) {
+		return true
+	}
+	return target.Campaign != "" && containsID(r.Campaigns, target.Campaign)
+}
+
+func (r *Rule) limit(param string) int64 {
+	if param == "daily_budget" {
+		return r.MaxDailyBudget
+	}
+	return r.MaxLifetimeBudget
+}
+
+// Check returns the ceilings mutation exceeds for target.
+func (p *Policy) Check(mutation graph.Mutation, target Target) []Finding {
+	if p == nil || mutation.Method == "DELETE" {
+		return nil
+	}
+	findings := make([]Finding, 0)
+	for _, param := range ceilingParams {
+		raw := strings.TrimSpace(mutation.Form[param])
+		if raw == "" {
+			continue
+		}
+		amount, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			continue
+		}
+		for index := range p.Rules {
+			rule := &p.Rules[index]
+			limit := rule.limit(param)
+			if limit == 0 || amount <= limit || !rule.matches(target) {
+				continue
+			}
+			findings = append(findings, Finding{Rule: rule.Name, Param: param, Amount: amount, Limit: limit})
+		}
+	}
+	return findings
+}
+
+// Guard returns a graph.MutationGuard enforcing p. labels resolves the labels
+// of the invoking profile; it may be nil.
+func (p *Policy) Guard(labels func(profile string) map[string]string) graph.MutationGuard {
+	return func(ctx context.Context, mutation graph.Mutation) error {
+		target := TargetOf(mutation)
+		if labels != nil {
+			target.Labels = labels(audit.InvocationFromContext(ctx).Profile)
+		}
+		findings := p.Check(mutation, target)
+		if len(findings) == 0 {
+			return nil
+		}
+		first := findings[0]
+		return &graph.APIError{
+			Type:      errorTypeViolation,
+			Code:      errorCodeViolation,
+			Message:   fmt.Sprintf("%s %s blocked by spend guardrail %q: %s=%d exceeds the ceiling of %d", mutation.Method, mutation.Path, first.Rule, first.Param, first.Amount, first.Limit),
+			Retryable: false,
+			Diagnostics: map[string]any{
+				"findings": findings,
+			},
+			Remediation: &graph.Remediation{
+				Category: graph.RemediationCategoryValidation,
+				Summary:  "The budget exceeds a ceiling in the spend guardrail policy.",
+				Actions: []string{
+					"Lower the budget to the ceiling or below.",
+					"If the ceiling is out of date, raise it in the spend guardrail policy.",
+				},
+			},
+		}
+	}
+}
+
+func containsID(ids []string, id string) bool {
+	id = strings.TrimPrefix(id, "act_")
+	for _, candidate := range ids {
+		if strings.TrimPrefix(candidate, "act_") == id {
+			return true
+		}
+	}
+	return false
+}
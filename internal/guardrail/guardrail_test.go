@@ -0,0 +1,113 @@
+package guardrail
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const testPolicy = `schema_version: 1
+rules:
+  - name: acme-account
+    accounts: [act_111]
+    max_daily_budget: 50000
+  - name: launch-campaign
+    campaigns: ["900"]
+    max_lifetime_budget: 1000000
+  - name: prod-profiles
+    labels: env=prod
+    max_daily_budget: 200000
+`
+
+func loadTestPolicy(t *testing.T) *Policy {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "guardrails.yaml")
+	if err := os.WriteFile(path, []byte(testPolicy), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	return policy
+}
+
+func TestCheckMatchesAccountCampaignAndLabelRules(t *testing.T) {
+	t.Parallel()
+
+	policy := loadTestPolicy(t)
+	cases := []struct {
+		name     string
+		mutation graph.Mutation
+		labels   map[string]string
+		want     []string
+	}{
+		{"account create over ceiling", graph.Mutation{Method: "POST", Path: "act_111/campaigns", Form: map[string]string{"daily_budget": "60000"}}, nil, []string{"acme-account"}},
+		{"other account", graph.Mutation{Method: "POST", Path: "act_222/campaigns", Form: map[string]string{"daily_budget": "60000"}}, nil, nil},
+		{"update by id applies account ceilings", graph.Mutation{Method: "POST", Path: "123", Form: map[string]string{"daily_budget": "60000"}}, nil, []string{"acme-account"}},
+		{"adset under campaign", graph.Mutation{Method: "POST", Path: "act_222/adsets", Form: map[string]string{"campaign_id": "900", "lifetime_budget": "2000000"}}, nil, []string{"launch-campaign"}},
+		{"label rule", graph.Mutation{Method: "POST", Path: "act_222/campaigns", Form: map[string]string{"daily_budget": "300000"}}, map[string]string{"env": "prod"}, []string{"prod-profiles"}},
+		{"within ceilings", graph.Mutation{Method: "POST", Path: "act_111/campaigns", Form: map[string]string{"daily_budget": "40000"}}, map[string]string{"env": "prod"}, nil},
+	}
+	for _, tc := range cases {
+		target := TargetOf(tc.mutation)
+		target.Labels = tc.labels
+		findings := policy.Check(tc.mutation, target)
+		rules := make([]string, 0, len(findings))
+		for _, finding := range findings {
+			rules = append(rules, finding.Rule)
+		}
+		if strings.Join(rules, ",") != strings.Join(tc.want, ",") {
+			t.Fatalf("%s: expected %v, got %#v", tc.name, tc.want, findings)
+		}
+	}
+}
+
+func TestGuardBlocksWithPolicyViolation(t *testing.T) {
+	t.Parallel()
+
+	policy := loadTestPolicy(t)
+	guard := policy.Guard(func(profile string) map[string]string {
+		if profile == "prod" {
+			return map[string]string{"env": "prod"}
+		}
+		return nil
+	})
+	mutation := graph.Mutation{Method: "POST", Path: "act_222/campaigns", Form: map[string]string{"daily_budget": "300000"}}
+
+	if err := guard(context.Background(), mutation); err != nil {
+		t.Fatalf("expected unlabeled profile to pass, got %v", err)
+	}
+	ctx := audit.WithInvocation(context.Background(), audit.Invocation{Command: "meta campaign create", Profile: "prod"})
+	err := guard(ctx, mutation)
+	apiErr := &graph.APIError{}
+	if !errors.As(err, &apiErr) || apiErr.Type != "spend_policy_violation" {
+		t.Fatalf("expected spend_policy_violation, got %v", err)
+	}
+	findings, ok := apiErr.Diagnostics["findings"].([]Finding)
+	if !ok || len(findings) != 1 || findings[0].Limit != 200000 {
+		t.Fatalf("unexpected findings %#v", apiErr.Diagnostics)
+	}
+}
+
+func TestLoadPolicyRejectsInvalidRules(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "guardrails.yaml")
+	if policy, err := LoadPolicy(path); err != nil || policy != nil {
+		t.Fatalf("expected missing policy to disable guardrails, got %#v %v", policy, err)
+	}
+	if err := os.WriteFile(path, []byte("schema_version: 1\nrules:\n  - name: empty\n    accounts: [act_1]\n"), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	if _, err := LoadPolicy(path); err == nil || !strings.Contains(err.Error(), "max_daily_budget") {
+		t.Fatalf("expected missing ceiling error, got %v", err)
+	}
+}
@@ -0,0 +1,395 @@
+// Package hooks runs user-defined commands around Graph mutations. A hook gets
+// a JSON event describing the mutation on stdin: pre_mutation hooks run before
+// it is sent and can block it by failing, post_mutation hooks run after it
+// succeeded and on_error hooks after it failed.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/redact"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ConfigSchemaVersion = 1
+	EventSchemaVersion  = 1
+
+	EventPreMutation  = "pre_mutation"
+	EventPostMutation = "post_mutation"
+	EventOnError      = "on_error"
+
+	DefaultTimeout = 30 * time.Second
+
+	errorTypeRejected = "pre_mutation_hook_gate"
+	errorCodeRejected = 412100
+
+	// maxStderr bounds the hook output quoted in errors and warnings.
+	maxStderr = 2048
+)
+
+// credentialFields never reach a hook.
+var credentialFields = map[string]struct{}{
+	"access_token":    {},
+	"appsecret_proof": {},
+}
+
+// Config is the hooks file.
+type Config struct {
+	SchemaVersion int    `yaml:"schema_version"`
+	Hooks         []Hook `yaml:"hooks"`
+}
+
+// Hook is one command run for an event.
+type Hook struct {
+	Name  string `yaml:"name"`
+	Event string `yaml:"event"`
+	// Command is the program and its arguments; it is not run through a shell.
+	Command []string `yaml:"command"`
+	// Timeout bounds one run, e.g. 10s; it defaults to DefaultTimeout.
+	Timeout string `yaml:"timeout,omitempty"`
+	// Labels is a profile selector such as "env=prod".
+	Labels string `yaml:"labels,omitempty"`
+
+	timeout  time.Duration
+	selector *config.Selector
+}
+
+// Event is the JSON document a hook reads from stdin.
+type Event struct {
+	SchemaVersion int    `json:"schema_version"`
+	Event         string `json:"event"`
+	Hook          string `json:"hook"`
+	Timestamp     string `json:"timestamp"`
+	Command       string `json:"command,omitempty"`
+	Profile       string `json:"profile,omitempty"`
+	// BreakGlass and AnomalyOverride are the override reasons of the
+	// invocation, when given.
+	BreakGlass      string       `json:"break_glass,omitempty"`
+	AnomalyOverride string       `json:"anomaly_override,omitempty"`
+	Mutation        MutationPlan `json:"mutation"`
+	Result          *Result      `json:"result,omitempty"`
+	Error           *Error       `json:"error,omitempty"`
+}
+
+// MutationPlan is the request a mutation sends, without credentials.
+type MutationPlan struct {
+	Method       string            `json:"method"`
+	Path         string            `json:"path"`
+	GraphVersion string            `json:"graph_version"`
+	Params       map[string]string `json:"params,omitempty"`
+	Upload       *Upload           `json:"upload,omitempty"`
+	PayloadHash  string            `json:"payload_hash"`
+}
+
+type Upload struct {
+	FieldName string `json:"field_name"`
+	FileName  string `json:"file_name"`
+	Bytes     int    `json:"bytes"`
+}
+
+// Result is the Graph response of a successful mutation.
+type Result struct {
+	StatusCode int            `json:"status_code"`
+	TargetIDs  []string       `json:"target_ids,omitempty"`
+	Body       map[string]any `json:"body,omitempty"`
+}
+
+// Error describes a failed mutation.
+type Error struct {
+	Type    string `json:"type"`
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "hooks.yaml"), nil
+}
+
+// Load returns nil without error when no hooks file exists.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read hooks %s: %w", path, err)
+	}
+	cfg := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("decode hooks %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid hooks %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	if c.SchemaVersion != ConfigSchemaVersion {
+		return fmt.Errorf("unsupported schema_version=%d (expected %d)", c.SchemaVersion, ConfigSchemaVersion)
+	}
+	for index := range c.Hooks {
+		hook := &c.Hooks[index]
+		hook.Name = strings.TrimSpace(hook.Name)
+		if hook.Name == "" {
+			return fmt.Errorf("hooks[%d]: name is required", index)
+		}
+		if err := hook.compile(); err != nil {
+			return fmt.Errorf("hook %q: %w", hook.Name, err)
+		}
+	}
+	return nil
+}
+
+func (h *Hook) compile() error {
+	h.Event = strings.TrimSpace(h.Event)
+	switch h.Event {
+	case EventPreMutation, EventPostMutation, EventOnError:
+	default:
+		return fmt.Errorf("event must be one of %s, %s, %s", EventPreMutation, EventPostMutation, EventOnError)
+	}
+	if len(h.Command) == 0 || strings.TrimSpace(h.Command[0]) == "" {
+		return errors.New("command is required")
+	}
+	h.timeout = DefaultTimeout
+	if raw := strings.TrimSpace(h.Timeout); raw != "" {
+		timeout, err := time.ParseDuration(raw)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("timeout %q must be a positive duration such as 10s", h.Timeout)
+		}
+		h.timeout = timeout
+	}
+	if raw := strings.TrimSpace(h.Labels); raw != "" {
+		selector, err := config.ParseSelector(raw)
+		if err != nil {
+			return fmt.Errorf("labels: %w", err)
+		}
+		h.selector = &selector
+	}
+	return nil
+}
+
+// For returns the hooks of event that apply to a profile with labels, in file
+// order.
+func (c *Config) For(event string, labels map[string]string) []Hook {
+	if c == nil {
+		return nil
+	}
+	var matched []Hook
+	for _, hook := range c.Hooks {
+		if hook.Event != event {
+			continue
+		}
+		if hook.selector != nil && !hook.selector.Matches(labels) {
+			continue
+		}
+		matched = append(matched, hook)
+	}
+	return matched
+}
+
+// Runner runs hooks for the mutations of the current process.
+type Runner struct {
+	Config *Config
+	// Labels resolves the labels of a profile for Hook.Labels.
+	Labels func(profile string) map[string]string
+	// Warnings receives a line for every post_mutation or on_error hook that
+	// failed; nil discards them.
+	Warnings io.Writer
+	Now      func() time.Time
+}
+
+// Guard runs the pre_mutation hooks of a mutation about to be sent. The first
+// failing hook blocks it. Dry-run plans send nothing and run no hooks.
+func (r *Runner) Guard(ctx context.Context, mutation graph.Mutation) error {
+	if graph.DryRunFromContext(ctx) != nil {
+		return nil
+	}
+	invocation := audit.InvocationFromContext(ctx)
+	for _, hook := range r.Config.For(EventPreMutation, r.labels(invocation.Profile)) {
+		stderr, err := r.run(ctx, hook, r.event(hook, invocation, mutation))
+		if err == nil {
+			continue
+		}
+		message := fmt.Sprintf("%s %s blocked by pre_mutation hook %q: %v", mutation.Method, mutation.Path, hook.Name, err)
+		if stderr != "" {
+			message += ": " + stderr
+		}
+		return &graph.APIError{
+			Type:      errorTypeRejected,
+			Code:      errorCodeRejected,
+			Message:   message,
+			Retryable: false,
+			Diagnostics: map[string]any{
+				"hook":    hook.Name,
+				"command": hook.Command,
+			},
+			Remediation: &graph.Remediation{
+				Category: graph.RemediationCategoryValidation,
+				Summary:  "A pre_mutation hook rejected the change.",
+				Actions: []string{
+					"Read the hook output above and fix what it reports.",
+					"Run the hook by hand with the event on stdin to debug it, or disable it in the hooks file.",
+				},
+			},
+		}
+	}
+	return nil
+}
+
+// Observe runs the post_mutation hooks of a successful mutation or the
+// on_error hooks of a failed one. The mutation has already happened, so a
+// failing hook only produces a warning.
+func (r *Runner) Observe(ctx context.Context, mutation graph.Mutation) {
+	event := EventPostMutation
+	if mutation.Err != nil {
+		event = EventOnError
+	}
+	invocation := audit.InvocationFromContext(ctx)
+	for _, hook := range r.Config.For(event, r.labels(invocation.Profile)) {
+		stderr, err := r.run(ctx, hook, r.event(hook, invocation, mutation))
+		if err == nil {
+			continue
+		}
+		warnings := r.Warnings
+		if warnings == nil {
+			warnings = io.Discard
+		}
+		if stderr != "" {
+			err = fmt.Errorf("%w: %s", err, stderr)
+		}
+		fmt.Fprintf(warnings, "warning: %s hook %q failed: %v\n", event, hook.Name, err)
+	}
+}
+
+func (r *Runner) labels(profile string) map[string]string {
+	if r.Labels == nil {
+		return nil
+	}
+	return r.Labels(profile)
+}
+
+func (r *Runner) event(hook Hook, invocation audit.Invocation, mutation graph.Mutation) Event {
+	now := time.Now
+	if r.Now != nil {
+		now = r.Now
+	}
+	event := Event{
+		SchemaVersion:   EventSchemaVersion,
+		Event:           hook.Event,
+		Hook:            hook.Name,
+		Timestamp:       now().UTC().Format(time.RFC3339Nano),
+		Command:         invocation.Command,
+		Profile:         invocation.Profile,
+		BreakGlass:      redact.String(invocation.BreakGlass),
+		AnomalyOverride: redact.String(invocation.AnomalyOverride),
+		Mutation:        plan(mutation),
+	}
+	if mutation.Err != nil {
+		event.Error = describeError(mutation.Err)
+		return event
+	}
+	if mutation.Response != nil {
+		entry := audit.NewEntry(invocation, mutation, now())
+		event.Result = &Result{
+			StatusCode: mutation.Response.StatusCode,
+			TargetIDs:  entry.TargetIDs,
+		}
+		if body, ok := redact.Value(mutation.Response.Body).(map[string]any); ok {
+			event.Result.Body = body
+		}
+	}
+	return event
+}
+
+func plan(mutation graph.Mutation) MutationPlan {
+	plan := MutationPlan{
+		Method:       mutation.Method,
+		Path:         redact.String(strings.TrimPrefix(mutation.Path, "/")),
+		GraphVersion: mutation.Version,
+		PayloadHash:  audit.PayloadHash(mutation),
+	}
+	params := map[string]string{}
+	for key, value := range mutation.Form {
+		if _, ok := credentialFields[key]; ok {
+			continue
+		}
+		params[key] = value
+	}
+	if len(params) > 0 {
+		plan.Params = redact.Map(params)
+	}
+	if mutation.Multipart != nil {
+		plan.Upload = &Upload{
+			FieldName: mutation.Multipart.FieldName,
+			FileName:  mutation.Multipart.FileName,
+			Bytes:     len(mutation.Multipart.FileBytes),
+		}
+	}
+	return plan
+}
+
+func describeError(err error) *Error {
+	out := &Error{Type: "request_error", Message: redact.String(err.Error())}
+	var apiErr *graph.APIError
+	var transient *graph.TransientError
+	switch {
+	case errors.As(err, &apiErr):
+		out.Type = apiErr.Type
+		out.Code = apiErr.Code
+	case errors.As(err, &transient):
+		out.Type = "transient_error"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		out.Type = "canceled"
+	}
+	return out
+}
+
+// run pipes event to the hook and returns its trimmed stderr. The hook's
+// stdout is discarded so it cannot corrupt the command's envelope.
+func (r *Runner) run(ctx context.Context, hook Hook, event Event) (string, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("encode hook event: %w", err)
+	}
+	// A canceled command still runs its post_mutation and on_error hooks,
+	// bounded by the hook timeout.
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), hook.timeout)
+	defer cancel()
+
+	stderr := &bytes.Buffer{}
+	process := exec.CommandContext(runCtx, hook.Command[0], hook.Command[1:]...)
+	process.Stdin = bytes.NewReader(payload)
+	process.Stdout = io.Discard
+	process.Stderr = stderr
+	process.Env = append(os.Environ(), "META_HOOK_EVENT="+hook.Event, "META_HOOK_NAME="+hook.Name)
+	err = process.Run()
+	if runCtx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", hook.timeout)
+	}
+	output := strings.TrimSpace(stderr.String())
+	if len(output) > maxStderr {
+		output = output[:maxStderr] + "..."
+	}
+	return output, err
+}
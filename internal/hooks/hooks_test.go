@@ -0,0 +1,195 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func writeHooks(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hooks.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write hooks: %v", err)
+	}
+	return path
+}
+
+func loadHooks(t *testing.T, content string) *Config {
+	t.Helper()
+	cfg, err := Load(writeHooks(t, content))
+	if err != nil {
+		t.Fatalf("load hooks: %v", err)
+	}
+	return cfg
+}
+
+func TestLoadMissingFileReturnsNil(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "hooks.yaml"))
+	if err != nil || cfg != nil {
+		t.Fatalf("expected nil config, got %v, %v", cfg, err)
+	}
+}
+
+func TestLoadRejectsInvalidHooks(t *testing.T) {
+	for _, tc := range []struct {
+		content string
+		want    string
+	}{
+		{content: "schema_version: 2\n", want: "unsupported schema_version"},
+		{content: "schema_version: 1\nhooks:\n  - event: pre_mutation\n    command: [true]\n", want: "name is required"},
+		{content: "schema_version: 1\nhooks:\n  - name: a\n    event: before\n    command: [true]\n", want: "event must be one of"},
+		{content: "schema_version: 1\nhooks:\n  - name: a\n    event: on_error\n", want: "command is required"},
+		{content: "schema_version: 1\nhooks:\n  - name: a\n    event: on_error\n    command: [true]\n    timeout: soon\n", want: "positive duration"},
+		{content: "schema_version: 1\nhooks:\n  - name: a\n    event: on_error\n    command: [true]\n    shell: bash\n", want: "field shell not found"},
+	} {
+		_, err := Load(writeHooks(t, tc.content))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%q: expected error containing %q, got %v", tc.content, tc.want, err)
+		}
+	}
+}
+
+func TestGuardBlocksMutationWhenPreMutationHookFails(t *testing.T) {
+	dir := t.TempDir()
+	eventPath := filepath.Join(dir, "event.json")
+	cfg := loadHooks(t, `schema_version: 1
+hooks:
+  - name: record
+    event: pre_mutation
+    command: ["sh", "-c", "cat > `+eventPath+`"]
+  - name: change-freeze
+    event: pre_mutation
+    command: ["sh", "-c", "echo 'CAB ticket required' >&2; exit 3"]
+    labels: env=prod
+`)
+	labels := map[string]map[string]string{"prod": {"env": "prod"}, "dev": {"env": "dev"}}
+	runner := &Runner{
+		Config: cfg,
+		Labels: func(profile string) map[string]string { return labels[profile] },
+		Now:    func() time.Time { return time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC) },
+	}
+	mutation := graph.Mutation{
+		Method:  http.MethodPost,
+		Path:    "/120000000000001",
+		Version: "v25.0",
+		Form:    map[string]string{"status": "PAUSED", "access_token": "secret-token"},
+	}
+
+	ctx := audit.WithInvocation(context.Background(), audit.Invocation{Command: "meta campaign pause", Profile: "prod"})
+	err := runner.Guard(ctx, mutation)
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != errorTypeRejected {
+		t.Fatalf("expected hook rejection, got %v", err)
+	}
+	if !strings.Contains(apiErr.Message, `"change-freeze"`) || !strings.Contains(apiErr.Message, "CAB ticket required") {
+		t.Fatalf("rejection should name the hook and quote its stderr: %s", apiErr.Message)
+	}
+
+	raw, err := os.ReadFile(eventPath)
+	if err != nil {
+		t.Fatalf("read event: %v", err)
+	}
+	if bytes.Contains(raw, []byte("secret-token")) {
+		t.Fatalf("event leaked the access token: %s", raw)
+	}
+	var event Event
+	if err := json.Unmarshal(raw, &event); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if event.Event != EventPreMutation || event.Hook != "record" || event.Profile != "prod" || event.Command != "meta campaign pause" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if event.Mutation.Path != "120000000000001" || event.Mutation.Params["status"] != "PAUSED" || event.Mutation.PayloadHash == "" {
+		t.Fatalf("unexpected mutation plan %+v", event.Mutation)
+	}
+
+	devCtx := audit.WithInvocation(context.Background(), audit.Invocation{Profile: "dev"})
+	if err := runner.Guard(devCtx, mutation); err != nil {
+		t.Fatalf("label-scoped hook ran for another profile: %v", err)
+	}
+	if err := runner.Guard(graph.WithDryRun(ctx, &graph.DryRun{}), mutation); err != nil {
+		t.Fatalf("dry-run plan ran hooks: %v", err)
+	}
+}
+
+func TestObserveRunsPostMutationOrOnErrorHooksAndWarnsOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	postPath := filepath.Join(dir, "post.json")
+	errorPath := filepath.Join(dir, "error.json")
+	cfg := loadHooks(t, `schema_version: 1
+hooks:
+  - name: slack
+    event: post_mutation
+    command: ["sh", "-c", "cat > `+postPath+`"]
+  - name: pager
+    event: on_error
+    command: ["sh", "-c", "cat > `+errorPath+`; echo 'pager down' >&2; exit 1"]
+  - name: slow
+    event: post_mutation
+    command: ["sleep", "5"]
+    timeout: 50ms
+`)
+	warnings := &bytes.Buffer{}
+	runner := &Runner{Config: cfg, Warnings: warnings}
+	ctx := audit.WithInvocation(context.Background(), audit.Invocation{Command: "meta campaign pause", Profile: "prod"})
+
+	runner.Observe(ctx, graph.Mutation{
+		Method:   http.MethodPost,
+		Path:     "120000000000001",
+		Version:  "v25.0",
+		Response: &graph.Response{StatusCode: http.StatusOK, Body: map[string]any{"success": true}},
+	})
+	var post Event
+	raw, err := os.ReadFile(postPath)
+	if err != nil {
+		t.Fatalf("read post event: %v", err)
+	}
+	if err := json.Unmarshal(raw, &post); err != nil {
+		t.Fatalf("decode post event: %v", err)
+	}
+	if post.Event != EventPostMutation || post.Result == nil || post.Result.StatusCode != http.StatusOK || post.Error != nil {
+		t.Fatalf("unexpected post event %+v", post)
+	}
+	if len(post.Result.TargetIDs) != 1 || post.Result.TargetIDs[0] != "120000000000001" {
+		t.Fatalf("unexpected targets %v", post.Result.TargetIDs)
+	}
+	if !strings.Contains(warnings.String(), `post_mutation hook "slow" failed: timed out after 50ms`) {
+		t.Fatalf("expected timeout warning, got %q", warnings.String())
+	}
+	if _, err := os.Stat(errorPath); !os.IsNotExist(err) {
+		t.Fatalf("on_error hook ran for a successful mutation: %v", err)
+	}
+
+	warnings.Reset()
+	runner.Observe(ctx, graph.Mutation{
+		Method:  http.MethodPost,
+		Path:    "120000000000001",
+		Version: "v25.0",
+		Err:     &graph.APIError{Type: "OAuthException", Code: 100, Message: "Invalid parameter"},
+	})
+	var failed Event
+	raw, err = os.ReadFile(errorPath)
+	if err != nil {
+		t.Fatalf("read error event: %v", err)
+	}
+	if err := json.Unmarshal(raw, &failed); err != nil {
+		t.Fatalf("decode error event: %v", err)
+	}
+	if failed.Event != EventOnError || failed.Error == nil || failed.Error.Type != "OAuthException" || failed.Error.Code != 100 {
+		t.Fatalf("unexpected error event %+v", failed)
+	}
+	if !strings.Contains(warnings.String(), `on_error hook "pager" failed: exit status 1: pager down`) {
+		t.Fatalf("expected pager warning, got %q", warnings.String())
+	}
+}
@@ -0,0 +1,138 @@
+package i18n
+
+var turkish = map[string]string{
+	// Table output labels.
+	"Error":       "Hata",
+	"Remediation": "Çözüm",
+
+	// Command failures reported by the CLI itself.
+	"Unhandled command failure.": "Beklenmeyen komut hatası.",
+	"Review the error message and fix input/configuration before retrying.":                                              "Yeniden denemeden önce hata mesajını inceleyin ve girdiyi/yapılandırmayı düzeltin.",
+	"Another meta process is writing the same local state file.":                                                         "Başka bir meta işlemi aynı yerel durum dosyasına yazıyor.",
+	"Rerun with --wait-lock 30s (or META_WAIT_LOCK) to wait for the other process to finish.":                            "Diğer işlemin bitmesini beklemek için --wait-lock 30s (veya META_WAIT_LOCK) ile yeniden çalıştırın.",
+	"If no meta process is running, remove %s.lock.":                                                                     "Çalışan bir meta işlemi yoksa %s.lock dosyasını silin.",
+	"The command was interrupted before it finished.":                                                                    "Komut bitmeden kesildi.",
+	"The command did not finish within --timeout.":                                                                       "Komut --timeout süresi içinde bitmedi.",
+	"Inspect diagnostics for work completed before the interrupt, then rerun the command.":                               "Kesintiden önce tamamlanan işler için diagnostics alanını inceleyin, ardından komutu yeniden çalıştırın.",
+	"Inspect diagnostics for work completed before the deadline, then rerun with a larger --timeout.":                    "Süre dolmadan önce tamamlanan işler için diagnostics alanını inceleyin, ardından daha büyük bir --timeout ile yeniden çalıştırın.",
+	"Once the cause has cleared, rerun this command with `meta retry --last`.":                                           "Sorun giderildiğinde bu komutu `meta retry --last` ile yeniden çalıştırın.",
+	"The profile's token was not granted a scope this command needs.":                                                    "Profilin token'ına bu komutun ihtiyaç duyduğu bir izin kapsamı verilmemiş.",
+	"Grant the missing scope to the app or system user and issue a new token.":                                           "Eksik kapsamı uygulamaya veya sistem kullanıcısına verin ve yeni bir token oluşturun.",
+	"Record the new scopes on the profile by rerunning its `meta auth` setup with --scopes.":                             "Profilin `meta auth` kurulumunu --scopes ile yeniden çalıştırarak yeni kapsamları profile kaydedin.",
+	"An administrator's command policy blocks this command.":                                                             "Bir yöneticinin komut politikası bu komutu engelliyor.",
+	"Run the command with a profile the policy allows, or ask the administrator to change the policy.":                   "Komutu politikanın izin verdiği bir profille çalıştırın veya yöneticiden politikayı değiştirmesini isteyin.",
+	"Mutations are frozen for this account or profile.":                                                                  "Bu hesap veya profil için değişiklikler donduruldu.",
+	"Wait until the freeze window closes.":                                                                               "Dondurma penceresi kapanana kadar bekleyin.",
+	"For an emergency change, rerun with --break-glass \"<reason>\"; the reason is recorded in the audit log.":           "Acil bir değişiklik için --break-glass \"<gerekçe>\" ile yeniden çalıştırın; gerekçe denetim günlüğüne kaydedilir.",
+	"The budget exceeds a ceiling in the spend guardrail policy.":                                                        "Bütçe, harcama koruma politikasındaki bir üst sınırı aşıyor.",
+	"Lower the budget to the ceiling or below.":                                                                          "Bütçeyi üst sınıra veya altına düşürün.",
+	"If the ceiling is out of date, raise it in the spend guardrail policy.":                                             "Üst sınır güncel değilse harcama koruma politikasında yükseltin.",
+	"Account spend is anomalous; resumes and budget increases are held back.":                                            "Hesap harcaması olağan dışı; yeniden başlatmalar ve bütçe artışları bekletiliyor.",
+	"The spend anomaly check could not read the object or its account's spend.":                                          "Harcama anomalisi denetimi nesneyi veya hesabının harcamasını okuyamadı.",
+	"Review the account's spend with `meta insights run` before resuming delivery or raising budgets.":                   "Yayını yeniden başlatmadan veya bütçeleri artırmadan önce hesabın harcamasını `meta insights run` ile inceleyin.",
+	"If the change is intended, rerun with --override-anomaly \"<reason>\"; the reason is recorded in the audit log.":    "Değişiklik bilerek yapılıyorsa --override-anomaly \"<gerekçe>\" ile yeniden çalıştırın; gerekçe denetim günlüğüne kaydedilir.",
+	"The approval policy requires a second operator to approve this mutation.":                                           "Onay politikası bu değişikliğin ikinci bir operatör tarafından onaylanmasını gerektiriyor.",
+	"Create an operator key with `meta approve keygen --operator <name>` and add its public key to the approval policy.": "`meta approve keygen --operator <ad>` ile bir operatör anahtarı oluşturun ve açık anahtarını onay politikasına ekleyin.",
+	"Send %s to another operator and have them run `meta approve %s`.":                                                   "%[1]s dosyasını başka bir operatöre gönderin ve `meta approve %[2]s` komutunu çalıştırmasını isteyin.",
+	"Rerun this command unchanged with --approval-token <token> (the token or the file `meta approve` wrote).":           "Bu komutu değiştirmeden --approval-token <token> ile yeniden çalıştırın (token veya `meta approve` komutunun yazdığı dosya).",
+	"This request needs the network, which --offline disables.":                                                          "Bu istek ağ erişimi gerektiriyor; --offline ağ erişimini kapatıyor.",
+	"Rerun the command without --offline.":                                                                               "Komutu --offline olmadan yeniden çalıştırın.",
+	"To read offline, run the same read online once so its response is cached.":                                          "Çevrimdışı okumak için aynı okumayı bir kez çevrimiçi çalıştırın; yanıtı önbelleğe alınır.",
+
+	// Graph API fallbacks.
+	"Meta API rate limits were reached.":                                       "Meta API hız sınırlarına ulaşıldı.",
+	"Retry with exponential backoff.":                                          "Üstel geri çekilmeyle yeniden deneyin.",
+	"Reduce request concurrency for this account/app.":                         "Bu hesap/uygulama için eşzamanlı istek sayısını azaltın.",
+	"Request payload failed Meta validation.":                                  "İstek içeriği Meta doğrulamasından geçemedi.",
+	"Review required fields and payload shape for the endpoint.":               "Uç nokta için zorunlu alanları ve içerik yapısını gözden geçirin.",
+	"Fix invalid field paths: %s.":                                             "Geçersiz alan yollarını düzeltin: %s.",
+	"Meta API returned a transient server-side failure.":                       "Meta API geçici bir sunucu tarafı hatası döndürdü.",
+	"Capture fbtrace_id if failures continue across retries.":                  "Hatalar yeniden denemelerde sürerse fbtrace_id değerini kaydedin.",
+	"Unknown Meta API failure.":                                                "Bilinmeyen Meta API hatası.",
+	"Inspect `error.diagnostics` and `fbtrace_id` for the full Meta response.": "Meta yanıtının tamamı için `error.diagnostics` ve `fbtrace_id` alanlarını inceleyin.",
+	"Adjust request inputs and retry after validation.":                        "İstek girdilerini düzeltin ve doğrulamadan sonra yeniden deneyin.",
+
+	// Graph error knowledge base.
+	"Retry with backoff.": "Geri çekilmeyle yeniden deneyin.",
+	"If it persists, shrink the request: fewer fields, a shorter date range or a smaller batch.":          "Sürerse isteği küçültün: daha az alan, daha kısa bir tarih aralığı veya daha küçük bir toplu istek.",
+	"Run `meta auth validate --profile <name>` to confirm token health.":                                  "Token'ın sağlıklı olduğunu doğrulamak için `meta auth validate --profile <ad>` çalıştırın.",
+	"Refresh credentials with `meta auth setup` or `meta auth login`.":                                    "Kimlik bilgilerini `meta auth setup` veya `meta auth login` ile yenileyin.",
+	"The %s rate limit for this business object was reached.":                                             "Bu işletme nesnesi için %s hız sınırına ulaşıldı.",
+	"Wait for estimated_time_to_regain_access from the X-Business-Use-Case-Usage header before retrying.": "Yeniden denemeden önce X-Business-Use-Case-Usage başlığındaki estimated_time_to_regain_access süresi kadar bekleyin.",
+	"Lower the call volume against this object: fewer concurrent automations, larger batches.":            "Bu nesneye yapılan çağrı hacmini düşürün: daha az eşzamanlı otomasyon, daha büyük toplu istekler.",
+	"Run large reports asynchronously with `meta insights jobs` instead of synchronous queries.":          "Büyük raporları eşzamanlı sorgular yerine `meta insights jobs` ile eşzamansız çalıştırın.",
+	"Upload users in fewer, larger `meta audience upload-users` runs.":                                    "Kullanıcıları daha az sayıda ve daha büyük `meta audience upload-users` çalıştırmalarıyla yükleyin.",
+	"Group changes with `meta bulk import` instead of one call per object.":                               "Her nesne için ayrı çağrı yerine değişiklikleri `meta bulk import` ile gruplayın.",
+	"Send more items per `meta catalog upload-items` request instead of more requests.":                   "Daha fazla istek yerine her `meta catalog upload-items` isteğinde daha fazla ürün gönderin.",
+	"Meta reported an unknown error, usually temporary.":                                                  "Meta genellikle geçici olan bilinmeyen bir hata bildirdi.",
+	"A Meta service is temporarily unavailable.":                                                          "Bir Meta hizmeti geçici olarak kullanılamıyor.",
+	"The app reached its application-level rate limit.":                                                   "Uygulama, uygulama düzeyindeki hız sınırına ulaştı.",
+	"Wait before retrying; the limit is measured over a rolling hour.":                                    "Yeniden denemeden önce bekleyin; sınır kayan bir saatlik pencerede ölçülür.",
+	"Spread automation across the hour instead of running it in bursts.":                                  "Otomasyonu ani yüklenmeler yerine saate yayarak çalıştırın.",
+	"The user's request rate limit was reached.":                                                          "Kullanıcının istek hız sınırına ulaşıldı.",
+	"Wait before retrying.": "Yeniden denemeden önce bekleyin.",
+	"Use a system user token for automation; its limits are separate from a person's.":                                   "Otomasyon için sistem kullanıcısı token'ı kullanın; sınırları kişisel hesaplardan ayrıdır.",
+	"The Page's request rate limit was reached.":                                                                         "Sayfanın istek hız sınırına ulaşıldı.",
+	"Wait before retrying requests made with this Page token.":                                                           "Bu Sayfa token'ıyla yapılan istekleri yeniden denemeden önce bekleyin.",
+	"The app reached a Meta application limit.":                                                                          "Uygulama bir Meta uygulama sınırına ulaştı.",
+	"Wait before retrying and reduce the rate of write calls.":                                                           "Yeniden denemeden önce bekleyin ve yazma çağrılarının sıklığını azaltın.",
+	"Too many calls to this ad account within the last hour.":                                                            "Son bir saat içinde bu reklam hesabına çok fazla çağrı yapıldı.",
+	"Group changes with `meta bulk import` or batch requests instead of one call per object.":                            "Her nesne için ayrı çağrı yerine değişiklikleri `meta bulk import` veya toplu isteklerle gruplayın.",
+	"The session is invalid; the token has to be reissued.":                                                              "Oturum geçersiz; token'ın yeniden oluşturulması gerekiyor.",
+	"Access token is invalid or expired.":                                                                                "Erişim token'ı geçersiz veya süresi dolmuş.",
+	"The user has not authorized the app, or removed it.":                                                                "Kullanıcı uygulamayı yetkilendirmemiş veya kaldırmış.",
+	"Log in again with `meta auth login` and grant the app access.":                                                      "`meta auth login` ile yeniden giriş yapın ve uygulamaya erişim izni verin.",
+	"The user's Facebook account is checkpointed.":                                                                       "Kullanıcının Facebook hesabı güvenlik denetiminde.",
+	"Log in at facebook.com to clear the checkpoint, then refresh credentials with `meta auth login`.":                   "Denetimi kaldırmak için facebook.com'da oturum açın, ardından kimlik bilgilerini `meta auth login` ile yenileyin.",
+	"The token was invalidated because the user changed their password.":                                                 "Kullanıcı parolasını değiştirdiği için token geçersiz kılındı.",
+	"Refresh credentials with `meta auth login`.":                                                                        "Kimlik bilgilerini `meta auth login` ile yenileyin.",
+	"The access token has expired.":                                                                                      "Erişim token'ının süresi dolmuş.",
+	"Refresh credentials with `meta auth login`, or `meta auth setup` for a system user.":                                "Kimlik bilgilerini `meta auth login` ile, sistem kullanıcısı içinse `meta auth setup` ile yenileyin.",
+	"Use a long-lived or system user token for automation.":                                                              "Otomasyon için uzun ömürlü veya sistem kullanıcısı token'ı kullanın.",
+	"The user's Facebook account is unconfirmed.":                                                                        "Kullanıcının Facebook hesabı onaylanmamış.",
+	"Confirm the account at facebook.com, then refresh credentials with `meta auth login`.":                              "Hesabı facebook.com'da onaylayın, ardından kimlik bilgilerini `meta auth login` ile yenileyin.",
+	"The access token is invalid: revoked, malformed or issued for another app.":                                         "Erişim token'ı geçersiz: iptal edilmiş, bozuk veya başka bir uygulama için oluşturulmuş.",
+	"The session is invalid for this Page; the user may have lost their role on it.":                                     "Oturum bu Sayfa için geçersiz; kullanıcı Sayfadaki rolünü kaybetmiş olabilir.",
+	"Confirm the user still has a role on the Page in Business Manager.":                                                 "Kullanıcının Business Manager'da Sayfada hâlâ bir rolü olduğunu doğrulayın.",
+	"Refresh the Page token with `meta auth page-token`.":                                                                "Sayfa token'ını `meta auth page-token` ile yenileyin.",
+	"The app does not have permission for this action, often because a permission or feature has not passed App Review.": "Uygulamanın bu işlem için izni yok; çoğunlukla bir izin veya özellik Uygulama İncelemesi'nden geçmemiştir.",
+	"Check the permission's access level for the app in the App Dashboard.":                                              "Uygulama Panosu'nda iznin uygulama için erişim düzeyini kontrol edin.",
+	"Confirm the token has access to the target business asset.":                                                         "Token'ın hedef işletme varlığına erişimi olduğunu doğrulayın.",
+	"Profile token is missing required permissions for this operation.":                                                  "Profil token'ında bu işlem için gerekli izinler eksik.",
+	"Verify required scopes are granted for the active profile.":                                                         "Etkin profil için gerekli kapsamların verildiğini doğrulayın.",
+	"Managing ads requires the ads_management permission.":                                                               "Reklamları yönetmek ads_management iznini gerektirir.",
+	"Grant ads_management to the token and record it on the profile with `--scopes`.":                                    "Token'a ads_management iznini verin ve `--scopes` ile profile kaydedin.",
+	"Referenced object or edge does not exist for this request.":                                                         "Bu istekte başvurulan nesne veya bağlantı mevcut değil.",
+	"Check object IDs and endpoint path for typos.":                                                                      "Nesne kimliklerini ve uç nokta yolunu yazım hatalarına karşı kontrol edin.",
+	"Ensure the object belongs to the authenticated account context.":                                                    "Nesnenin kimliği doğrulanmış hesap bağlamına ait olduğundan emin olun.",
+	"Meta returns the same error for objects the token cannot see; confirm the profile has access to the object.":        "Meta, token'ın göremediği nesneler için de aynı hatayı döndürür; profilin nesneye erişimi olduğunu doğrulayın.",
+	"The action was blocked as a policy violation; the account is temporarily restricted.":                               "İşlem politika ihlali olarak engellendi; hesap geçici olarak kısıtlandı.",
+	"Review the account's quality and restrictions in Business Manager.":                                                 "Hesabın kalitesini ve kısıtlamalarını Business Manager'da inceleyin.",
+	"Do not retry the same action until the restriction is lifted.":                                                      "Kısıtlama kalkana kadar aynı işlemi yeniden denemeyin.",
+	"Duplicate post: the same content was published moments ago.":                                                        "Yinelenen gönderi: aynı içerik az önce yayınlandı.",
+	"Change the content, or confirm the earlier post went out before publishing again.":                                  "İçeriği değiştirin veya yeniden yayınlamadan önce önceki gönderinin yayınlandığını doğrulayın.",
+	"The request used a deprecated Graph API version.":                                                                   "İstek, kullanımdan kaldırılmış bir Graph API sürümü kullandı.",
+	"Set a supported graph_version on the profile, or pass a newer --version.":                                           "Profilde desteklenen bir graph_version ayarlayın veya daha yeni bir --version verin.",
+
+	// meta init prompts.
+	"App credentials":    "Uygulama kimlik bilgileri",
+	"Login":              "Giriş",
+	"Asset discovery":    "Varlık keşfi",
+	"Default profile":    "Varsayılan profil",
+	"Schema packs":       "Şema paketleri",
+	"Smoke read":         "Duman testi okuması",
+	"Profile name":       "Profil adı",
+	"Meta App ID":        "Meta Uygulama Kimliği",
+	"Meta App Secret":    "Meta Uygulama Gizli Anahtarı",
+	"Page to bind":       "Bağlanacak Sayfa",
+	"Default ad account": "Varsayılan reklam hesabı",
+	"Scope pack (solo_smb|ads_only|ig_publish)": "Kapsam paketi (solo_smb|ads_only|ig_publish)",
+	"Replace default profile %q with %q?":       "Varsayılan profil %q, %q ile değiştirilsin mi?",
+	"0 to skip":                                 "atlamak için 0",
+	"Y/n":                                       "E/h",
+	"y/N":                                       "e/H",
+
+	// meta tui prompts.
+	"resume %s %s (%s)? [y/N] ": "%s %s (%s) yeniden başlatılsın mı? [e/H] ",
+	"resume canceled":           "yeniden başlatma iptal edildi",
+}
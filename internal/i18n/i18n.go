@@ -0,0 +1,123 @@
+// Package i18n renders human-facing text (prompts, remediation summaries and
+// actions) in the user's language. Messages are keyed by their English source
+// text, so code keeps writing English and a missing translation falls back to
+// it. Machine-readable fields such as error types, codes, classes and field
+// names are never passed through here and stay stable across languages.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	English = "en"
+	Turkish = "tr"
+)
+
+// catalogs maps a language to translations keyed by English source text.
+// Keys may hold %s verbs: they translate text produced by fmt.Sprintf with
+// that format, and Sprintf translates the format itself.
+var catalogs = map[string]map[string]string{
+	Turkish: turkish,
+}
+
+// Supported lists the languages messages can render in, sorted.
+func Supported() []string {
+	languages := []string{English}
+	for lang := range catalogs {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Parse normalizes a language setting such as "tr", "TR" or "tr_TR.UTF-8".
+// An empty value selects English.
+func Parse(value string) (string, error) {
+	lang := strings.ToLower(strings.TrimSpace(value))
+	if index := strings.IndexAny(lang, "_-."); index >= 0 {
+		lang = lang[:index]
+	}
+	if lang == "" || lang == English {
+		return English, nil
+	}
+	if _, ok := catalogs[lang]; ok {
+		return lang, nil
+	}
+	return "", fmt.Errorf("unsupported language %q; expected %s", value, strings.Join(Supported(), "|"))
+}
+
+// Translate returns text in lang, or text itself when lang is English,
+// unknown or has no translation for it.
+func Translate(lang string, text string) string {
+	catalog := catalogs[lang]
+	if catalog == nil || text == "" {
+		return text
+	}
+	if translated, ok := catalog[text]; ok {
+		return translated
+	}
+	for source, translated := range catalog {
+		if !strings.Contains(source, "%s") {
+			continue
+		}
+		if args, ok := match(source, text); ok {
+			return fmt.Sprintf(translated, args...)
+		}
+	}
+	return text
+}
+
+// Sprintf formats args with the translation of format.
+func Sprintf(lang string, format string, args ...any) string {
+	if translated, ok := catalogs[lang][format]; ok {
+		format = translated
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// TranslateAll translates each of texts.
+func TranslateAll(lang string, texts []string) []string {
+	if texts == nil {
+		return nil
+	}
+	translated := make([]string, len(texts))
+	for index, text := range texts {
+		translated[index] = Translate(lang, text)
+	}
+	return translated
+}
+
+// match reports whether text was formatted from format, whose only verbs are
+// %s, and returns the substituted values.
+func match(format string, text string) ([]any, bool) {
+	parts := strings.Split(format, "%s")
+	if !strings.HasPrefix(text, parts[0]) {
+		return nil, false
+	}
+	rest := text[len(parts[0]):]
+	args := make([]any, 0, len(parts)-1)
+	for index, part := range parts[1:] {
+		last := index == len(parts)-2
+		var end int
+		switch {
+		case last && part == "":
+			end = len(rest)
+		case last:
+			if !strings.HasSuffix(rest, part) {
+				return nil, false
+			}
+			end = len(rest) - len(part)
+		default:
+			end = strings.Index(rest, part)
+		}
+		if end <= 0 {
+			return nil, false
+		}
+		args = append(args, rest[:end])
+		rest = rest[end+len(part):]
+	}
+	return args, rest == ""
+}
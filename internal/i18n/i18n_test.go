@@ -0,0 +1,94 @@
+package i18n_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/i18n"
+)
+
+func TestParseNormalizesLanguageSettings(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: i18n.English},
+		{value: "en", want: i18n.English},
+		{value: "en_US.UTF-8", want: i18n.English},
+		{value: "TR", want: i18n.Turkish},
+		{value: "tr_TR.UTF-8", want: i18n.Turkish},
+		{value: " tr-TR ", want: i18n.Turkish},
+	} {
+		got, err := i18n.Parse(tc.value)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.value, err)
+		}
+		if got != tc.want {
+			t.Fatalf("parse %q = %q, want %q", tc.value, got, tc.want)
+		}
+	}
+
+	if _, err := i18n.Parse("de"); err == nil || !strings.Contains(err.Error(), "expected en|tr") {
+		t.Fatalf("expected unsupported language error, got %v", err)
+	}
+}
+
+func TestTranslateFallsBackToSourceText(t *testing.T) {
+	t.Parallel()
+
+	if got := i18n.Translate(i18n.English, "Retry with backoff."); got != "Retry with backoff." {
+		t.Fatalf("english must pass through, got %q", got)
+	}
+	if got := i18n.Translate(i18n.Turkish, "A message without a translation."); got != "A message without a translation." {
+		t.Fatalf("missing translation must fall back to english, got %q", got)
+	}
+	if got := i18n.Translate(i18n.Turkish, "Retry with backoff."); got != "Geri çekilmeyle yeniden deneyin." {
+		t.Fatalf("unexpected translation %q", got)
+	}
+}
+
+func TestTranslateMatchesFormattedText(t *testing.T) {
+	t.Parallel()
+
+	got := i18n.Translate(i18n.Turkish, "The Ads Insights rate limit for this business object was reached.")
+	if got != "Bu işletme nesnesi için Ads Insights hız sınırına ulaşıldı." {
+		t.Fatalf("unexpected translation %q", got)
+	}
+
+	got = i18n.Translate(i18n.Turkish, "Send /tmp/a.json to another operator and have them run `meta approve /tmp/a.json`.")
+	if got != "/tmp/a.json dosyasını başka bir operatöre gönderin ve `meta approve /tmp/a.json` komutunu çalıştırmasını isteyin." {
+		t.Fatalf("unexpected translation %q", got)
+	}
+
+	if got := i18n.Sprintf(i18n.Turkish, "Replace default profile %q with %q?", "old", "new"); got != `Varsayılan profil "old", "new" ile değiştirilsin mi?` {
+		t.Fatalf("unexpected sprintf %q", got)
+	}
+}
+
+func TestTurkishCatalogCoversErrorKnowledgeBase(t *testing.T) {
+	t.Parallel()
+
+	codes := [][2]int{
+		{1, 0}, {2, 0}, {4, 0}, {17, 0}, {32, 0}, {341, 0}, {613, 0},
+		{80000, 0}, {80001, 0}, {80002, 0}, {80003, 0}, {80004, 0}, {80005, 0}, {80006, 0}, {80008, 0}, {80009, 0}, {80014, 0},
+		{102, 0}, {190, 0}, {190, 458}, {190, 459}, {190, 460}, {190, 463}, {190, 464}, {190, 467}, {190, 492},
+		{10, 0}, {200, 0}, {294, 0}, {100, 33}, {368, 0}, {506, 0}, {2635, 0},
+		{100, 0}, {999, 0},
+	}
+	for _, code := range codes {
+		remediation := graph.ClassifyRemediation(400, code[0], code[1], "", nil)
+		for _, text := range append([]string{remediation.Summary}, remediation.Actions...) {
+			if i18n.Translate(i18n.Turkish, text) == text {
+				t.Errorf("code %d/%d: no turkish translation for %q", code[0], code[1], text)
+			}
+		}
+	}
+	for _, text := range append([]string{graph.ClassifyRemediation(429, 4, 0, "", nil).Summary}, graph.ClassifyRemediation(500, 0, 0, "", nil).Actions...) {
+		if i18n.Translate(i18n.Turkish, text) == text {
+			t.Errorf("no turkish translation for %q", text)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package ig
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	PublishBatchModeImmediate = "immediate"
+	PublishBatchModeScheduled = "scheduled"
+
+	PublishBatchStatusValid     = "valid"
+	PublishBatchStatusInvalid   = "invalid"
+	PublishBatchStatusPublished = "published"
+	PublishBatchStatusScheduled = "scheduled"
+	PublishBatchStatusDuplicate = "duplicate_suppressed"
+	PublishBatchStatusFailed    = "failed"
+
+	publishBatchIdempotencyKeyPrefix = "batch:"
+
+	igErrorTypeBatchValidation     = "ig_batch_validation_error"
+	igErrorCodeBatchValidation     = 422300
+	igErrorTypeBatchPartialFailure = "ig_batch_partial_failure"
+	igErrorCodeBatchPartialFailure = 424200
+)
+
+var publishBatchColumns = []string{"id", "surface", "ig_user_id", "media_url", "caption", "media_type", "publish_at"}
+
+var publishBatchDefaultMediaTypes = map[string]string{
+	PublishSurfaceFeed:  MediaTypeImage,
+	PublishSurfaceReel:  MediaTypeReels,
+	PublishSurfaceStory: MediaTypeStories,
+}
+
+type PublishBatchRow struct {
+	ID        string `json:"id"`
+	Surface   string `json:"surface"`
+	IGUserID  string `json:"ig_user_id"`
+	MediaURL  string `json:"media_url"`
+	Caption   string `json:"caption"`
+	MediaType string `json:"media_type"`
+	PublishAt string `json:"publish_at"`
+}
+
+type PublishBatchEntry struct {
+	Row            int      `json:"row"`
+	ID             string   `json:"id"`
+	Surface        string   `json:"surface"`
+	IGUserID       string   `json:"ig_user_id,omitempty"`
+	MediaURL       string   `json:"media_url"`
+	Caption        string   `json:"-"`
+	MediaType      string   `json:"media_type,omitempty"`
+	PublishAt      string   `json:"publish_at,omitempty"`
+	Mode           string   `json:"mode"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty"`
+	Status         string   `json:"status"`
+	Errors         []string `json:"errors,omitempty"`
+	ScheduleID     string   `json:"schedule_id,omitempty"`
+	CreationID     string   `json:"creation_id,omitempty"`
+	MediaID        string   `json:"media_id,omitempty"`
+}
+
+type PublishBatchReport struct {
+	File      string              `json:"file"`
+	DryRun    bool                `json:"dry_run"`
+	Total     int                 `json:"total"`
+	Invalid   int                 `json:"invalid"`
+	Published int                 `json:"published"`
+	Scheduled int                 `json:"scheduled"`
+	Failed    int                 `json:"failed"`
+	Rows      []PublishBatchEntry `json:"rows"`
+}
+
+type PublishBatchPlanOptions struct {
+	File            string
+	StrictMode      bool
+	CaptionLint     *CaptionLintConfig
+	Now             time.Time
+	ResolveIGUserID func(requested string) (string, error)
+}
+
+type PublishBatchExecutor struct {
+	Publish  func(ctx context.Context, entry PublishBatchEntry) (*FeedPublishResult, error)
+	Schedule func(entry PublishBatchEntry) (*SchedulePublishResult, error)
+}
+
+func LoadPublishBatchFile(path string) ([]PublishBatchRow, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("batch file path is required")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read batch file %s: %w", path, err)
+	}
+
+	var rows []PublishBatchRow
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		rows, err = decodePublishBatchCSV(data)
+	case ".json":
+		rows, err = decodePublishBatchJSON(data)
+	default:
+		return nil, fmt.Errorf("unsupported batch file %s: expected .csv or .json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode batch file %s: %w", path, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("batch file %s has no rows", path)
+	}
+	return rows, nil
+}
+
+// PlanPublishBatch validates every row up front so a calendar with any invalid entry
+// is rejected before anything is published or scheduled.
+func PlanPublishBatch(rows []PublishBatchRow, options PublishBatchPlanOptions) *PublishBatchReport {
+	report := &PublishBatchReport{
+		File:  options.File,
+		Total: len(rows),
+		Rows:  make([]PublishBatchEntry, 0, len(rows)),
+	}
+	now := options.Now.UTC()
+	seenIDs := map[string]int{}
+	seenSlots := map[string]string{}
+
+	for index, row := range rows {
+		entry := PublishBatchEntry{
+			Row:      index + 1,
+			ID:       strings.TrimSpace(row.ID),
+			MediaURL: strings.TrimSpace(row.MediaURL),
+			Caption:  row.Caption,
+			Mode:     PublishBatchModeImmediate,
+		}
+		addError := func(message string) {
+			entry.Errors = append(entry.Errors, message)
+		}
+
+		if entry.ID == "" {
+			addError("id is required")
+		} else if previous, exists := seenIDs[entry.ID]; exists {
+			addError(fmt.Sprintf("duplicate id %q (first used on row %d)", entry.ID, previous))
+		} else {
+			seenIDs[entry.ID] = entry.Row
+			idempotencyKey, err := normalizeIdempotencyKey(publishBatchIdempotencyKeyPrefix + entry.ID)
+			if err != nil {
+				addError(err.Error())
+			}
+			entry.IdempotencyKey = idempotencyKey
+		}
+
+		surface := strings.TrimSpace(row.Surface)
+		if surface == "" {
+			surface = PublishSurfaceFeed
+		}
+		normalizedSurface, err := normalizePublishSurface(surface)
+		if err != nil {
+			addError(err.Error())
+			entry.Surface = surface
+		} else {
+			entry.Surface = normalizedSurface
+			mediaType := strings.TrimSpace(row.MediaType)
+			if mediaType == "" {
+				mediaType = publishBatchDefaultMediaTypes[normalizedSurface]
+			}
+			mediaType, err = ValidatePublishMediaTypeForSurface(normalizedSurface, mediaType)
+			if err != nil {
+				addError(err.Error())
+			}
+			entry.MediaType = mediaType
+		}
+
+		captionValidation := ValidateCaptionWithLint(row.Caption, options.StrictMode, options.CaptionLint)
+		entry.Errors = append(entry.Errors, captionValidation.Errors...)
+
+		if options.ResolveIGUserID != nil {
+			igUserID, err := options.ResolveIGUserID(strings.TrimSpace(row.IGUserID))
+			if err != nil {
+				addError(err.Error())
+			}
+			entry.IGUserID = igUserID
+		} else {
+			entry.IGUserID = strings.TrimSpace(row.IGUserID)
+		}
+
+		if entry.MediaType != "" {
+			if _, _, err := BuildUploadRequest("", "", "", MediaUploadOptions{
+				IGUserID:  entry.IGUserID,
+				MediaURL:  entry.MediaURL,
+				Caption:   entry.Caption,
+				MediaType: entry.MediaType,
+			}); err != nil {
+				addError(err.Error())
+			}
+		}
+
+		if strings.TrimSpace(row.PublishAt) != "" {
+			entry.Mode = PublishBatchModeScheduled
+			publishAt, err := parsePublishAt(row.PublishAt)
+			if err != nil {
+				addError(err.Error())
+			} else {
+				entry.PublishAt = publishAt.Format(time.RFC3339)
+				if !publishAt.After(now) {
+					addError(fmt.Sprintf("publish_at %s must be in the future", entry.PublishAt))
+				}
+				slot := entry.IGUserID + "|" + entry.PublishAt
+				if previous, exists := seenSlots[slot]; exists {
+					addError(fmt.Sprintf("publish_at %s collides with row %s for the same ig user", entry.PublishAt, previous))
+				} else {
+					seenSlots[slot] = entry.ID
+				}
+			}
+		}
+
+		entry.Status = PublishBatchStatusValid
+		if len(entry.Errors) > 0 {
+			entry.Status = PublishBatchStatusInvalid
+			report.Invalid++
+		}
+		report.Rows = append(report.Rows, entry)
+	}
+	return report
+}
+
+func ExecutePublishBatch(ctx context.Context, report *PublishBatchReport, executor PublishBatchExecutor) error {
+	if report == nil {
+		return errors.New("publish batch report is required")
+	}
+	if report.Invalid > 0 {
+		return NewPublishBatchValidationError(report)
+	}
+	if executor.Publish == nil || executor.Schedule == nil {
+		return errors.New("publish batch executor is incomplete")
+	}
+
+	for index := range report.Rows {
+		entry := &report.Rows[index]
+		switch entry.Mode {
+		case PublishBatchModeScheduled:
+			result, err := executor.Schedule(*entry)
+			if err != nil {
+				entry.Status = PublishBatchStatusFailed
+				entry.Errors = append(entry.Errors, err.Error())
+				report.Failed++
+				continue
+			}
+			entry.ScheduleID = result.Schedule.ScheduleID
+			entry.Status = PublishBatchStatusScheduled
+			if result.DuplicateSuppressed {
+				entry.Status = PublishBatchStatusDuplicate
+			}
+			report.Scheduled++
+		default:
+			result, err := executor.Publish(ctx, *entry)
+			if err != nil {
+				entry.Status = PublishBatchStatusFailed
+				entry.Errors = append(entry.Errors, err.Error())
+				report.Failed++
+				continue
+			}
+			entry.CreationID = result.CreationID
+			entry.MediaID = result.MediaID
+			entry.Status = PublishBatchStatusPublished
+			report.Published++
+		}
+	}
+
+	if report.Failed > 0 {
+		return &graph.APIError{
+			Type:      igErrorTypeBatchPartialFailure,
+			Code:      igErrorCodeBatchPartialFailure,
+			Message:   fmt.Sprintf("publish batch finished with %d failed row(s) of %d", report.Failed, report.Total),
+			Retryable: false,
+			Diagnostics: map[string]any{
+				"report": report,
+			},
+			Remediation: newIGRemediation(
+				graph.RemediationCategoryValidation,
+				"Some calendar rows failed after other rows were published or scheduled.",
+				"Inspect diagnostics.report.rows for failed rows; rerunning the batch reuses the row-derived idempotency keys.",
+			),
+		}
+	}
+	return nil
+}
+
+func NewPublishBatchValidationError(report *PublishBatchReport) error {
+	return &graph.APIError{
+		Type:      igErrorTypeBatchValidation,
+		Code:      igErrorCodeBatchValidation,
+		Message:   fmt.Sprintf("publish batch has %d invalid row(s) of %d; nothing was published", report.Invalid, report.Total),
+		Retryable: false,
+		Diagnostics: map[string]any{
+			"report": report,
+		},
+		Remediation: newIGRemediation(
+			graph.RemediationCategoryValidation,
+			"Content calendar rows failed validation.",
+			"Fix the rows listed in diagnostics.report.rows and rerun; use --dry-run to validate without publishing.",
+		),
+	}
+}
+
+func decodePublishBatchCSV(data []byte) ([]PublishBatchRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("missing header row")
+		}
+		return nil, err
+	}
+
+	columnIndex := map[string]int{}
+	for index, column := range header {
+		name := strings.ToLower(strings.TrimSpace(column))
+		if !containsString(publishBatchColumns, name) {
+			return nil, fmt.Errorf("unknown column %q: expected %s", column, strings.Join(publishBatchColumns, ","))
+		}
+		if _, exists := columnIndex[name]; exists {
+			return nil, fmt.Errorf("duplicate column %q", column)
+		}
+		columnIndex[name] = index
+	}
+
+	rows := make([]PublishBatchRow, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		value := func(column string) string {
+			index, ok := columnIndex[column]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return record[index]
+		}
+		rows = append(rows, PublishBatchRow{
+			ID:        value("id"),
+			Surface:   value("surface"),
+			IGUserID:  value("ig_user_id"),
+			MediaURL:  value("media_url"),
+			Caption:   value("caption"),
+			MediaType: value("media_type"),
+			PublishAt: value("publish_at"),
+		})
+	}
+	return rows, nil
+}
+
+func decodePublishBatchJSON(data []byte) ([]PublishBatchRow, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var rows []PublishBatchRow
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, err
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return nil, errors.New("multiple JSON values")
+		}
+		return nil, err
+	}
+	return rows, nil
+}
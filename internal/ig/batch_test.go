@@ -0,0 +1,147 @@
+package ig
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestLoadPublishBatchFileParsesCSVAndJSON(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "calendar.csv")
+	csvBody := "id,surface,media_url,caption,media_type,publish_at\n" +
+		"post-1,feed,https://cdn.example.com/a.jpg,\"hello, #meta\",IMAGE,\n" +
+		"post-2,reel,https://cdn.example.com/b.mp4,reel time,REELS,2030-01-02T15:04:05Z\n"
+	if err := os.WriteFile(csvPath, []byte(csvBody), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	rows, err := LoadPublishBatchFile(csvPath)
+	if err != nil {
+		t.Fatalf("load csv: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Caption != "hello, #meta" || rows[1].Surface != "reel" || rows[1].PublishAt != "2030-01-02T15:04:05Z" {
+		t.Fatalf("unexpected csv rows %#v", rows)
+	}
+
+	jsonPath := filepath.Join(dir, "calendar.json")
+	if err := os.WriteFile(jsonPath, []byte(`[{"id":"post-1","media_url":"https://cdn.example.com/a.jpg","caption":"hi"}]`), 0o600); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	rows, err = LoadPublishBatchFile(jsonPath)
+	if err != nil {
+		t.Fatalf("load json: %v", err)
+	}
+	if len(rows) != 1 || rows[0].ID != "post-1" {
+		t.Fatalf("unexpected json rows %#v", rows)
+	}
+
+	unknownPath := filepath.Join(dir, "unknown.csv")
+	if err := os.WriteFile(unknownPath, []byte("id,location\npost-1,here\n"), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	if _, err := LoadPublishBatchFile(unknownPath); err == nil || !strings.Contains(err.Error(), `unknown column "location"`) {
+		t.Fatalf("expected unknown column error, got %v", err)
+	}
+
+	if _, err := LoadPublishBatchFile(filepath.Join(dir, "calendar.txt")); err == nil {
+		t.Fatal("expected unsupported extension error")
+	}
+}
+
+func TestPlanPublishBatchValidatesRows(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rows := []PublishBatchRow{
+		{ID: "post-1", IGUserID: "1784", MediaURL: "https://cdn.example.com/a.jpg", Caption: "hello #meta", MediaType: "IMAGE"},
+		{ID: "post-2", Surface: "story", IGUserID: "1784", MediaURL: "https://cdn.example.com/b.jpg", Caption: "story", MediaType: "IMAGE", PublishAt: "2026-03-02T09:00:00Z"},
+		{ID: "post-3", Surface: "reel", IGUserID: "1784", MediaURL: "https://cdn.example.com/c.mp4", Caption: "reel", MediaType: "REELS", PublishAt: "2026-03-02T10:00:00Z"},
+		{ID: "post-1", IGUserID: "1784", MediaURL: "https://cdn.example.com/d.jpg", Caption: "dup", MediaType: "IMAGE"},
+		{ID: "post-5", IGUserID: "1784", MediaURL: "https://cdn.example.com/e.jpg", Caption: "", MediaType: "IMAGE", PublishAt: "2026-02-01T09:00:00Z"},
+	}
+
+	report := PlanPublishBatch(rows, PublishBatchPlanOptions{File: "calendar.csv", StrictMode: true, Now: now})
+	if report.Total != 5 || report.Invalid != 3 {
+		t.Fatalf("unexpected totals total=%d invalid=%d", report.Total, report.Invalid)
+	}
+
+	first := report.Rows[0]
+	if first.Status != PublishBatchStatusValid || first.Mode != PublishBatchModeImmediate || first.Surface != PublishSurfaceFeed || first.IdempotencyKey != "batch:post-1" {
+		t.Fatalf("unexpected first row %#v", first)
+	}
+	if got := report.Rows[1]; got.Status != PublishBatchStatusInvalid || !strings.Contains(strings.Join(got.Errors, ";"), "STORIES") {
+		t.Fatalf("expected story media type violation, got %#v", got)
+	}
+	if got := report.Rows[2]; got.Status != PublishBatchStatusValid || got.Mode != PublishBatchModeScheduled {
+		t.Fatalf("expected scheduled reel to be valid, got %#v", got)
+	}
+	if got := report.Rows[3]; !strings.Contains(strings.Join(got.Errors, ";"), `duplicate id "post-1"`) {
+		t.Fatalf("expected duplicate id error, got %#v", got)
+	}
+	last := strings.Join(report.Rows[4].Errors, ";")
+	for _, want := range []string{"caption is required", "must be in the future"} {
+		if !strings.Contains(last, want) {
+			t.Fatalf("expected %q in %q", want, last)
+		}
+	}
+}
+
+func TestPlanPublishBatchRejectsCollidingPublishTimes(t *testing.T) {
+	t.Parallel()
+
+	rows := []PublishBatchRow{
+		{ID: "a", IGUserID: "1784", MediaURL: "https://cdn.example.com/a.jpg", Caption: "a", MediaType: "IMAGE", PublishAt: "2030-01-01T10:00:00Z"},
+		{ID: "b", IGUserID: "1784", MediaURL: "https://cdn.example.com/b.jpg", Caption: "b", MediaType: "IMAGE", PublishAt: "2030-01-01T12:00:00+02:00"},
+		{ID: "c", IGUserID: "1785", MediaURL: "https://cdn.example.com/c.jpg", Caption: "c", MediaType: "IMAGE", PublishAt: "2030-01-01T10:00:00Z"},
+	}
+	report := PlanPublishBatch(rows, PublishBatchPlanOptions{Now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)})
+	if report.Invalid != 1 {
+		t.Fatalf("expected one colliding row, got %#v", report.Rows)
+	}
+	if got := strings.Join(report.Rows[1].Errors, ";"); !strings.Contains(got, "collides with row a") {
+		t.Fatalf("unexpected collision error %q", got)
+	}
+}
+
+func TestExecutePublishBatchContinuesAfterRowFailure(t *testing.T) {
+	t.Parallel()
+
+	report := &PublishBatchReport{
+		Total: 3,
+		Rows: []PublishBatchEntry{
+			{ID: "a", Mode: PublishBatchModeImmediate, Status: PublishBatchStatusValid},
+			{ID: "b", Mode: PublishBatchModeScheduled, Status: PublishBatchStatusValid},
+			{ID: "c", Mode: PublishBatchModeImmediate, Status: PublishBatchStatusValid},
+		},
+	}
+	err := ExecutePublishBatch(context.Background(), report, PublishBatchExecutor{
+		Publish: func(_ context.Context, entry PublishBatchEntry) (*FeedPublishResult, error) {
+			if entry.ID == "c" {
+				return nil, errors.New("container not ready")
+			}
+			return &FeedPublishResult{CreationID: "creation_" + entry.ID, MediaID: "media_" + entry.ID}, nil
+		},
+		Schedule: func(entry PublishBatchEntry) (*SchedulePublishResult, error) {
+			return &SchedulePublishResult{Schedule: ScheduledPublishRecord{ScheduleID: "sched_" + entry.ID}}, nil
+		},
+	})
+
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != igErrorTypeBatchPartialFailure {
+		t.Fatalf("expected partial failure error, got %v", err)
+	}
+	if report.Published != 1 || report.Scheduled != 1 || report.Failed != 1 {
+		t.Fatalf("unexpected counters %#v", report)
+	}
+	if report.Rows[0].MediaID != "media_a" || report.Rows[1].ScheduleID != "sched_b" || report.Rows[2].Status != PublishBatchStatusFailed {
+		t.Fatalf("unexpected rows %#v", report.Rows)
+	}
+}
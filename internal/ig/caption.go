@@ -17,6 +17,8 @@ type CaptionValidationResult struct {
 	Caption        string   `json:"caption"`
 	CharacterCount int      `json:"character_count"`
 	HashtagCount   int      `json:"hashtag_count"`
+	MentionCount   int      `json:"mention_count"`
+	LintConfig     string   `json:"lint_config,omitempty"`
 	Strict         bool     `json:"strict"`
 	Valid          bool     `json:"valid"`
 	Errors         []string `json:"errors"`
@@ -24,11 +26,18 @@ type CaptionValidationResult struct {
 }
 
 func ValidateCaption(caption string, strict bool) CaptionValidationResult {
+	return ValidateCaptionWithLint(caption, strict, nil)
+}
+
+// ValidateCaptionWithLint applies the built-in limits plus mention format checks and,
+// when lint is set, the org banned word/phrase, mention allowlist, and hashtag policies.
+func ValidateCaptionWithLint(caption string, strict bool, lint *CaptionLintConfig) CaptionValidationResult {
 	trimmed := strings.TrimSpace(caption)
 	result := CaptionValidationResult{
 		Caption:        caption,
 		CharacterCount: utf8.RuneCountInString(caption),
 		HashtagCount:   countPrefixedTokens(caption, "#"),
+		MentionCount:   len(extractMentions(caption)),
 		Strict:         strict,
 		Errors:         make([]string, 0, 4),
 		Warnings:       make([]string, 0, 4),
@@ -50,6 +59,10 @@ func ValidateCaption(caption string, strict bool) CaptionValidationResult {
 	if result.HashtagCount > CaptionWarningHashtags && result.HashtagCount <= MaxCaptionHashtags {
 		result.Warnings = append(result.Warnings, fmt.Sprintf("caption uses many hashtags (%d/%d)", result.HashtagCount, MaxCaptionHashtags))
 	}
+	result.Warnings = append(result.Warnings, lintCaption(caption, result.HashtagCount, lint)...)
+	if lint != nil {
+		result.LintConfig = lint.Source
+	}
 
 	if strict && len(result.Warnings) > 0 {
 		for _, warning := range result.Warnings {
@@ -0,0 +1,178 @@
+package ig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	CaptionLintSchemaVersion = 1
+	MaxMentionLength         = 30
+)
+
+var mentionHandlePattern = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)*$`)
+
+type CaptionLintConfig struct {
+	SchemaVersion   int      `json:"schema_version"`
+	BannedWords     []string `json:"banned_words,omitempty"`
+	BannedPhrases   []string `json:"banned_phrases,omitempty"`
+	MaxHashtags     int      `json:"max_hashtags,omitempty"`
+	AllowedMentions []string `json:"allowed_mentions,omitempty"`
+	Source          string   `json:"-"`
+}
+
+func DefaultCaptionLintConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "ig", "caption_lint.json"), nil
+}
+
+func LoadCaptionLintConfig(path string) (*CaptionLintConfig, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("caption lint config path is required")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read caption lint config %s: %w", path, err)
+	}
+
+	var config CaptionLintConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("decode caption lint config %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return nil, fmt.Errorf("decode caption lint config %s: multiple JSON values", path)
+		}
+		return nil, fmt.Errorf("decode caption lint config %s: %w", path, err)
+	}
+	if config.SchemaVersion != CaptionLintSchemaVersion {
+		return nil, fmt.Errorf("unsupported caption lint schema_version=%d in %s (expected %d)", config.SchemaVersion, path, CaptionLintSchemaVersion)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid caption lint config %s: %w", path, err)
+	}
+	config.Source = path
+	return &config, nil
+}
+
+func (c *CaptionLintConfig) Validate() error {
+	if c.MaxHashtags < 0 || c.MaxHashtags > MaxCaptionHashtags {
+		return fmt.Errorf("max_hashtags must be between 0 and %d", MaxCaptionHashtags)
+	}
+	for _, word := range c.BannedWords {
+		if strings.TrimSpace(word) == "" {
+			return errors.New("banned_words entries must not be empty")
+		}
+	}
+	for _, phrase := range c.BannedPhrases {
+		if strings.TrimSpace(phrase) == "" {
+			return errors.New("banned_phrases entries must not be empty")
+		}
+	}
+	for _, mention := range c.AllowedMentions {
+		if _, ok := normalizeMentionHandle(mention); !ok {
+			return fmt.Errorf("allowed_mentions entry %q is not a valid instagram username", mention)
+		}
+	}
+	return nil
+}
+
+// lintCaption returns brand-compliance findings as warnings so strict mode can
+// promote them to errors alongside the built-in length and hashtag checks.
+func lintCaption(caption string, hashtagCount int, config *CaptionLintConfig) []string {
+	warnings := make([]string, 0)
+	mentions := extractMentions(caption)
+	for _, mention := range mentions {
+		if _, ok := normalizeMentionHandle(mention); !ok {
+			warnings = append(warnings, fmt.Sprintf("mention %q is not a valid instagram username", mention))
+		}
+	}
+	if config == nil {
+		return warnings
+	}
+
+	if len(config.AllowedMentions) > 0 {
+		allowed := make([]string, 0, len(config.AllowedMentions))
+		for _, mention := range config.AllowedMentions {
+			handle, _ := normalizeMentionHandle(mention)
+			allowed = append(allowed, handle)
+		}
+		for _, mention := range mentions {
+			handle, ok := normalizeMentionHandle(mention)
+			if ok && !containsString(allowed, handle) {
+				warnings = append(warnings, fmt.Sprintf("mention @%s is not in the approved mention list", handle))
+			}
+		}
+	}
+
+	words := captionWords(caption)
+	for _, banned := range config.BannedWords {
+		needle := strings.ToLower(strings.TrimSpace(banned))
+		if containsString(words, needle) {
+			warnings = append(warnings, fmt.Sprintf("caption contains banned word %q", banned))
+		}
+	}
+	normalizedCaption := " " + strings.Join(words, " ") + " "
+	for _, banned := range config.BannedPhrases {
+		phrase := strings.Join(captionWords(banned), " ")
+		if phrase != "" && strings.Contains(normalizedCaption, " "+phrase+" ") {
+			warnings = append(warnings, fmt.Sprintf("caption contains banned phrase %q", banned))
+		}
+	}
+
+	if config.MaxHashtags > 0 && hashtagCount > config.MaxHashtags {
+		warnings = append(warnings, fmt.Sprintf("caption exceeds hashtag policy of %d (%d)", config.MaxHashtags, hashtagCount))
+	}
+	return warnings
+}
+
+func extractMentions(caption string) []string {
+	mentions := make([]string, 0)
+	for _, token := range strings.Fields(caption) {
+		if !strings.HasPrefix(token, "@") {
+			continue
+		}
+		end := strings.IndexFunc(token[1:], func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.'
+		})
+		handle := token[1:]
+		if end >= 0 {
+			handle = handle[:end]
+		}
+		handle = strings.TrimRight(handle, ".")
+		if handle != "" {
+			mentions = append(mentions, "@"+handle)
+		}
+	}
+	return mentions
+}
+
+func normalizeMentionHandle(mention string) (string, bool) {
+	handle := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(mention), "@"))
+	if handle == "" || len(handle) > MaxMentionLength {
+		return handle, false
+	}
+	return handle, mentionHandlePattern.MatchString(handle)
+}
+
+func captionWords(value string) []string {
+	return strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '-'
+	})
+}
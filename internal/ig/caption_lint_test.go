@@ -0,0 +1,101 @@
+package ig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateCaptionWithLintAppliesOrgPolicy(t *testing.T) {
+	t.Parallel()
+
+	lint := &CaptionLintConfig{
+		SchemaVersion:   CaptionLintSchemaVersion,
+		BannedWords:     []string{"Guaranteed"},
+		BannedPhrases:   []string{"link in  bio"},
+		MaxHashtags:     2,
+		AllowedMentions: []string{"@meta"},
+		Source:          "caption_lint.json",
+	}
+	caption := "Results guaranteed! Link in bio, thanks @Meta and @partner. #one #two #three"
+
+	result := ValidateCaptionWithLint(caption, false, lint)
+	if !result.Valid {
+		t.Fatalf("expected non-strict lint findings to stay warnings, got %#v", result.Errors)
+	}
+	if result.MentionCount != 2 || result.LintConfig != "caption_lint.json" {
+		t.Fatalf("unexpected result metadata %#v", result)
+	}
+	warnings := strings.Join(result.Warnings, ";")
+	for _, want := range []string{
+		`banned word "Guaranteed"`,
+		`banned phrase "link in  bio"`,
+		"hashtag policy of 2 (3)",
+		"@partner is not in the approved mention list",
+	} {
+		if !strings.Contains(warnings, want) {
+			t.Fatalf("expected %q in %q", want, warnings)
+		}
+	}
+	if strings.Contains(warnings, "@meta is not") {
+		t.Fatalf("allowlisted mention should pass case-insensitively: %q", warnings)
+	}
+
+	strictResult := ValidateCaptionWithLint(caption, true, lint)
+	if strictResult.Valid || len(strictResult.Errors) != 4 {
+		t.Fatalf("expected strict mode to promote lint warnings, got %#v", strictResult.Errors)
+	}
+}
+
+func TestValidateCaptionFlagsMalformedMentions(t *testing.T) {
+	t.Parallel()
+
+	result := ValidateCaption("shoutout @good.name, @bad..name and @.dot plus mail@example.com", false)
+	if result.MentionCount != 3 {
+		t.Fatalf("expected 3 mentions, got %d", result.MentionCount)
+	}
+	if len(result.Warnings) != 2 {
+		t.Fatalf("expected two malformed mention warnings, got %#v", result.Warnings)
+	}
+	if !strings.Contains(result.Warnings[0], `"@bad..name"`) || !strings.Contains(result.Warnings[1], `"@.dot"`) {
+		t.Fatalf("unexpected warnings %#v", result.Warnings)
+	}
+
+	if got := ValidateCaption("thanks @"+strings.Repeat("a", MaxMentionLength+1), false); len(got.Warnings) != 1 {
+		t.Fatalf("expected overlong mention warning, got %#v", got.Warnings)
+	}
+}
+
+func TestLoadCaptionLintConfigValidatesFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "caption_lint.json")
+	if err := os.WriteFile(path, []byte(`{"schema_version":1,"banned_words":["scam"],"max_hashtags":5,"allowed_mentions":["meta"]}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	config, err := LoadCaptionLintConfig(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if config.Source != path || config.MaxHashtags != 5 || len(config.BannedWords) != 1 {
+		t.Fatalf("unexpected config %#v", config)
+	}
+
+	for name, body := range map[string]string{
+		"schema":  `{"schema_version":2}`,
+		"unknown": `{"schema_version":1,"blocked":["x"]}`,
+		"max":     `{"schema_version":1,"max_hashtags":31}`,
+		"empty":   `{"schema_version":1,"banned_phrases":[" "]}`,
+		"mention": `{"schema_version":1,"allowed_mentions":["not valid"]}`,
+	} {
+		invalidPath := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(invalidPath, []byte(body), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		if _, err := LoadCaptionLintConfig(invalidPath); err == nil {
+			t.Fatalf("expected %s config to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package ig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	MediaTypeCarousel      = "CAROUSEL"
+	PublishSurfaceCarousel = "carousel"
+	MediaStatusCodeError   = "ERROR"
+	MediaStatusCodeExpired = "EXPIRED"
+	MinCarouselChildren    = 2
+	MaxCarouselChildren    = 10
+	CarouselStageChild     = "child_container"
+	CarouselStageContainer = "carousel_container"
+	CarouselStagePublish   = "publish"
+
+	carouselPlanNodeID          = "carousel"
+	defaultCarouselPollInterval = 5 * time.Second
+	defaultCarouselPollTimeout  = 5 * time.Minute
+
+	igErrorTypeCarouselPartialFailure = "ig_carousel_partial_failure"
+	igErrorCodeCarouselPartialFailure = 424100
+)
+
+type CarouselPublishOptions struct {
+	IGUserID       string
+	MediaURLs      []string
+	Caption        string
+	StrictMode     bool
+	IdempotencyKey string
+	DryRun         bool
+	PollInterval   time.Duration
+	Timeout        time.Duration
+}
+
+type CarouselChildResult struct {
+	Index      int    `json:"index"`
+	MediaURL   string `json:"media_url"`
+	MediaType  string `json:"media_type"`
+	CreationID string `json:"creation_id,omitempty"`
+	StatusCode string `json:"status_code,omitempty"`
+	Polls      int    `json:"polls,omitempty"`
+}
+
+type CarouselPlanNode struct {
+	ID        string            `json:"id"`
+	Stage     string            `json:"stage"`
+	Method    string            `json:"method"`
+	Path      string            `json:"path"`
+	Form      map[string]string `json:"form"`
+	DependsOn []string          `json:"depends_on,omitempty"`
+}
+
+type CarouselPublishResult struct {
+	Mode               string                  `json:"mode"`
+	Surface            string                  `json:"surface"`
+	IGUserID           string                  `json:"ig_user_id"`
+	IdempotencyKey     string                  `json:"idempotency_key,omitempty"`
+	CaptionValidation  CaptionValidationResult `json:"caption_validation"`
+	Children           []CarouselChildResult   `json:"children"`
+	Plan               []CarouselPlanNode      `json:"plan,omitempty"`
+	CreationID         string                  `json:"creation_id,omitempty"`
+	StatusCode         string                  `json:"status_code,omitempty"`
+	MediaID            string                  `json:"media_id,omitempty"`
+	PublishRequestPath string                  `json:"publish_request_path,omitempty"`
+	PublishResponse    map[string]any          `json:"publish_response,omitempty"`
+	QuotaPreflight     *PublishQuotaPreflight  `json:"quota_preflight,omitempty"`
+}
+
+func (s *Service) PublishCarousel(ctx context.Context, version string, token string, appSecret string, options CarouselPublishOptions) (*CarouselPublishResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("instagram service client is required")
+	}
+
+	plan, children, captionValidation, err := PlanCarousel(options)
+	if err != nil {
+		return nil, err
+	}
+	result := &CarouselPublishResult{
+		Mode:              "immediate",
+		Surface:           PublishSurfaceCarousel,
+		IGUserID:          strings.TrimSpace(options.IGUserID),
+		IdempotencyKey:    strings.TrimSpace(options.IdempotencyKey),
+		CaptionValidation: captionValidation,
+		Children:          children,
+	}
+	if options.DryRun {
+		result.Mode = "dry_run"
+		result.Plan = plan
+		return result, nil
+	}
+
+	pollInterval, timeout, err := resolveCarouselPolling(options)
+	if err != nil {
+		return nil, err
+	}
+
+	childIDs := make([]string, 0, len(result.Children))
+	for index := range result.Children {
+		child := &result.Children[index]
+		upload, err := s.Upload(ctx, version, token, appSecret, MediaUploadOptions{
+			IGUserID:       options.IGUserID,
+			MediaURL:       child.MediaURL,
+			MediaType:      child.MediaType,
+			IsCarouselItem: true,
+			IdempotencyKey: carouselChildIdempotencyKey(options.IdempotencyKey, child.Index),
+		})
+		if err != nil {
+			return result, newCarouselPartialFailureError(CarouselStageChild, child.Index, result, err)
+		}
+		child.CreationID = upload.CreationID
+
+		statusCode, polls, err := s.waitForContainer(ctx, version, token, appSecret, upload.CreationID, pollInterval, timeout)
+		child.StatusCode = statusCode
+		child.Polls = polls
+		if err != nil {
+			return result, newCarouselPartialFailureError(CarouselStageChild, child.Index, result, err)
+		}
+		childIDs = append(childIDs, upload.CreationID)
+	}
+
+	request, err := BuildCarouselContainerRequest(version, token, appSecret, options.IGUserID, childIDs, options.Caption, options.IdempotencyKey)
+	if err != nil {
+		return result, newCarouselPartialFailureError(CarouselStageContainer, 0, result, err)
+	}
+	response, err := s.Client.Do(ctx, request)
+	if err != nil {
+		return result, newCarouselPartialFailureError(CarouselStageContainer, 0, result, err)
+	}
+	creationID, _ := response.Body["id"].(string)
+	if strings.TrimSpace(creationID) == "" {
+		return result, newCarouselPartialFailureError(CarouselStageContainer, 0, result, errors.New("instagram carousel container response did not include id"))
+	}
+	result.CreationID = strings.TrimSpace(creationID)
+
+	statusCode, _, err := s.waitForContainer(ctx, version, token, appSecret, result.CreationID, pollInterval, timeout)
+	result.StatusCode = statusCode
+	if err != nil {
+		return result, newCarouselPartialFailureError(CarouselStageContainer, 0, result, err)
+	}
+
+	publishResult, err := s.Publish(ctx, version, token, appSecret, MediaPublishOptions{
+		IGUserID:       options.IGUserID,
+		CreationID:     result.CreationID,
+		IdempotencyKey: options.IdempotencyKey,
+	})
+	if err != nil {
+		return result, newCarouselPartialFailureError(CarouselStagePublish, 0, result, err)
+	}
+	result.MediaID = publishResult.MediaID
+	result.PublishRequestPath = publishResult.RequestPath
+	result.PublishResponse = publishResult.Response
+	return result, nil
+}
+
+func PlanCarousel(options CarouselPublishOptions) ([]CarouselPlanNode, []CarouselChildResult, CaptionValidationResult, error) {
+	if len(options.MediaURLs) < MinCarouselChildren || len(options.MediaURLs) > MaxCarouselChildren {
+		return nil, nil, CaptionValidationResult{}, fmt.Errorf("carousel requires %d-%d media urls, got %d", MinCarouselChildren, MaxCarouselChildren, len(options.MediaURLs))
+	}
+
+	captionValidation := ValidateCaption(options.Caption, options.StrictMode)
+	if !captionValidation.Valid {
+		return nil, nil, captionValidation, errors.New(strings.Join(captionValidation.Errors, "; "))
+	}
+
+	plan := make([]CarouselPlanNode, 0, len(options.MediaURLs)+2)
+	children := make([]CarouselChildResult, 0, len(options.MediaURLs))
+	childNodes := make([]string, 0, len(options.MediaURLs))
+	for index, mediaURL := range options.MediaURLs {
+		child := CarouselChildResult{
+			Index:     index + 1,
+			MediaURL:  strings.TrimSpace(mediaURL),
+			MediaType: InferCarouselChildMediaType(mediaURL),
+		}
+		request, _, err := BuildUploadRequest("", "", "", MediaUploadOptions{
+			IGUserID:       options.IGUserID,
+			MediaURL:       child.MediaURL,
+			MediaType:      child.MediaType,
+			IsCarouselItem: true,
+			IdempotencyKey: carouselChildIdempotencyKey(options.IdempotencyKey, child.Index),
+		})
+		if err != nil {
+			return nil, nil, captionValidation, fmt.Errorf("carousel child %d: %w", child.Index, err)
+		}
+		nodeID := fmt.Sprintf("child_%d", child.Index)
+		plan = append(plan, CarouselPlanNode{
+			ID:     nodeID,
+			Stage:  CarouselStageChild,
+			Method: request.Method,
+			Path:   request.Path,
+			Form:   request.Form,
+		})
+		children = append(children, child)
+		childNodes = append(childNodes, nodeID)
+	}
+
+	placeholders := make([]string, 0, len(childNodes))
+	for _, nodeID := range childNodes {
+		placeholders = append(placeholders, "{{"+nodeID+".id}}")
+	}
+	containerRequest, err := BuildCarouselContainerRequest("", "", "", options.IGUserID, placeholders, options.Caption, options.IdempotencyKey)
+	if err != nil {
+		return nil, nil, captionValidation, err
+	}
+	plan = append(plan, CarouselPlanNode{
+		ID:        carouselPlanNodeID,
+		Stage:     CarouselStageContainer,
+		Method:    containerRequest.Method,
+		Path:      containerRequest.Path,
+		Form:      containerRequest.Form,
+		DependsOn: childNodes,
+	})
+
+	publishRequest, _, _, err := BuildPublishRequest("", "", "", MediaPublishOptions{
+		IGUserID:       options.IGUserID,
+		CreationID:     "{{" + carouselPlanNodeID + ".id}}",
+		IdempotencyKey: options.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, nil, captionValidation, err
+	}
+	plan = append(plan, CarouselPlanNode{
+		ID:        CarouselStagePublish,
+		Stage:     CarouselStagePublish,
+		Method:    publishRequest.Method,
+		Path:      publishRequest.Path,
+		Form:      publishRequest.Form,
+		DependsOn: []string{carouselPlanNodeID},
+	})
+	return plan, children, captionValidation, nil
+}
+
+func BuildCarouselContainerRequest(version string, token string, appSecret string, igUserID string, childIDs []string, caption string, idempotencyKey string) (graph.Request, error) {
+	normalizedIGUserID, err := normalizeGraphID("ig user id", igUserID)
+	if err != nil {
+		return graph.Request{}, err
+	}
+	if len(childIDs) < MinCarouselChildren || len(childIDs) > MaxCarouselChildren {
+		return graph.Request{}, fmt.Errorf("carousel requires %d-%d children, got %d", MinCarouselChildren, MaxCarouselChildren, len(childIDs))
+	}
+
+	form := map[string]string{
+		"media_type": MediaTypeCarousel,
+		"children":   strings.Join(childIDs, ","),
+	}
+	if trimmed := strings.TrimSpace(caption); trimmed != "" {
+		form["caption"] = trimmed
+	}
+	normalizedKey, err := normalizeIdempotencyKey(idempotencyKey)
+	if err != nil {
+		return graph.Request{}, err
+	}
+	if normalizedKey != "" {
+		form["idempotency_key"] = normalizedKey
+	}
+
+	return graph.Request{
+		Method:      "POST",
+		Path:        fmt.Sprintf("%s/media", normalizedIGUserID),
+		Version:     strings.TrimSpace(version),
+		Form:        form,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, nil
+}
+
+func InferCarouselChildMediaType(mediaURL string) string {
+	trimmed := strings.TrimSpace(mediaURL)
+	if index := strings.IndexAny(trimmed, "?#"); index >= 0 {
+		trimmed = trimmed[:index]
+	}
+	switch strings.ToLower(path.Ext(trimmed)) {
+	case ".mp4", ".mov", ".m4v":
+		return MediaTypeVideo
+	default:
+		return MediaTypeImage
+	}
+}
+
+func (s *Service) waitForContainer(ctx context.Context, version string, token string, appSecret string, creationID string, pollInterval time.Duration, timeout time.Duration) (string, int, error) {
+	startedAt := time.Now()
+	polls := 0
+	for {
+		status, err := s.Status(ctx, version, token, appSecret, MediaStatusOptions{CreationID: creationID})
+		if err != nil {
+			return "", polls, err
+		}
+		polls++
+
+		statusCode := strings.ToUpper(strings.TrimSpace(status.StatusCode))
+		switch statusCode {
+		case MediaStatusCodeFinished:
+			return statusCode, polls, nil
+		case MediaStatusCodeError, MediaStatusCodeExpired:
+			return statusCode, polls, fmt.Errorf("instagram media container %s processing failed: status_code=%s", creationID, statusCode)
+		}
+		if time.Since(startedAt) >= timeout {
+			return statusCode, polls, newMediaNotReadyError(statusCode)
+		}
+
+		timer := time.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return statusCode, polls, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func resolveCarouselPolling(options CarouselPublishOptions) (time.Duration, time.Duration, error) {
+	pollInterval := options.PollInterval
+	if pollInterval == 0 {
+		pollInterval = defaultCarouselPollInterval
+	}
+	if pollInterval < 0 {
+		return 0, 0, errors.New("poll interval must be greater than zero")
+	}
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = defaultCarouselPollTimeout
+	}
+	if timeout < 0 {
+		return 0, 0, errors.New("timeout must be greater than zero")
+	}
+	return pollInterval, timeout, nil
+}
+
+func carouselChildIdempotencyKey(idempotencyKey string, index int) string {
+	trimmed := strings.TrimSpace(idempotencyKey)
+	if trimmed == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:child-%d", trimmed, index)
+}
+
+func newCarouselPartialFailureError(stage string, childIndex int, result *CarouselPublishResult, cause error) *graph.APIError {
+	created := make([]any, 0, len(result.Children)+1)
+	for _, child := range result.Children {
+		if child.CreationID == "" {
+			continue
+		}
+		created = append(created, map[string]any{
+			"index":       child.Index,
+			"creation_id": child.CreationID,
+			"status_code": child.StatusCode,
+		})
+	}
+	if result.CreationID != "" {
+		created = append(created, map[string]any{
+			"stage":       CarouselStageContainer,
+			"creation_id": result.CreationID,
+			"status_code": result.StatusCode,
+		})
+	}
+
+	location := stage
+	if childIndex > 0 {
+		location = fmt.Sprintf("%s %d", stage, childIndex)
+	}
+	diagnostics := map[string]any{
+		"stage":              stage,
+		"created_containers": created,
+	}
+	if childIndex > 0 {
+		diagnostics["failed_child_index"] = childIndex
+	}
+	retryable := false
+	var apiErr *graph.APIError
+	if errors.As(cause, &apiErr) {
+		diagnostics["cause_type"] = apiErr.Type
+		diagnostics["cause_code"] = apiErr.Code
+		retryable = apiErr.Retryable
+	}
+	var transientErr *graph.TransientError
+	if errors.As(cause, &transientErr) {
+		retryable = true
+	}
+
+	category := graph.RemediationCategoryValidation
+	if retryable {
+		category = graph.RemediationCategoryTransient
+	}
+	return &graph.APIError{
+		Type:        igErrorTypeCarouselPartialFailure,
+		Code:        igErrorCodeCarouselPartialFailure,
+		Message:     fmt.Sprintf("instagram carousel publish failed at %s: %v (%d container(s) created and left unpublished)", location, cause, len(created)),
+		Retryable:   retryable,
+		Diagnostics: diagnostics,
+		Remediation: newIGRemediation(
+			category,
+			"Instagram carousel publish stopped before the carousel was published.",
+			"Inspect diagnostics.created_containers; unpublished containers expire after 24 hours and need no cleanup.",
+			"Fix the failing media URL or wait for processing, then rerun the carousel publish.",
+		),
+	}
+}
@@ -0,0 +1,195 @@
+package ig
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestServicePublishCarouselPollsChildrenAndPublishes(t *testing.T) {
+	t.Parallel()
+
+	stub := &sequenceHTTPClient{
+		t: t,
+		responses: []sequenceStubResponse{
+			{statusCode: http.StatusOK, response: `{"id":"child_1"}`},
+			{statusCode: http.StatusOK, response: `{"id":"child_1","status_code":"FINISHED"}`},
+			{statusCode: http.StatusOK, response: `{"id":"child_2"}`},
+			{statusCode: http.StatusOK, response: `{"id":"child_2","status_code":"IN_PROGRESS"}`},
+			{statusCode: http.StatusOK, response: `{"id":"child_2","status_code":"FINISHED"}`},
+			{statusCode: http.StatusOK, response: `{"id":"carousel_9"}`},
+			{statusCode: http.StatusOK, response: `{"id":"carousel_9","status_code":"FINISHED"}`},
+			{statusCode: http.StatusOK, response: `{"id":"media_10"}`},
+		},
+	}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+
+	result, err := New(client).PublishCarousel(context.Background(), "v25.0", "token-1", "secret-1", CarouselPublishOptions{
+		IGUserID:       "17841400008460056",
+		MediaURLs:      []string{"https://cdn.example.com/one.jpg", "https://cdn.example.com/two.mp4?sig=1"},
+		Caption:        "hello #meta",
+		StrictMode:     true,
+		IdempotencyKey: "carousel_01",
+		PollInterval:   time.Millisecond,
+		Timeout:        time.Second,
+	})
+	if err != nil {
+		t.Fatalf("publish carousel: %v", err)
+	}
+	if len(stub.calls) != 8 {
+		t.Fatalf("expected eight graph calls, got %d", len(stub.calls))
+	}
+
+	firstChildForm, err := url.ParseQuery(stub.calls[0].body)
+	if err != nil {
+		t.Fatalf("parse first child form: %v", err)
+	}
+	if firstChildForm.Get("image_url") != "https://cdn.example.com/one.jpg" || firstChildForm.Get("is_carousel_item") != "true" {
+		t.Fatalf("unexpected first child form: %v", firstChildForm)
+	}
+	if got := firstChildForm.Get("idempotency_key"); got != "carousel_01:child-1" {
+		t.Fatalf("unexpected child idempotency_key %q", got)
+	}
+	if firstChildForm.Get("caption") != "" {
+		t.Fatalf("expected child container without caption, got %q", firstChildForm.Get("caption"))
+	}
+	secondChildForm, err := url.ParseQuery(stub.calls[2].body)
+	if err != nil {
+		t.Fatalf("parse second child form: %v", err)
+	}
+	if got := secondChildForm.Get("video_url"); got != "https://cdn.example.com/two.mp4?sig=1" {
+		t.Fatalf("unexpected video_url %q", got)
+	}
+
+	carouselForm, err := url.ParseQuery(stub.calls[5].body)
+	if err != nil {
+		t.Fatalf("parse carousel form: %v", err)
+	}
+	if carouselForm.Get("media_type") != MediaTypeCarousel || carouselForm.Get("children") != "child_1,child_2" {
+		t.Fatalf("unexpected carousel form: %v", carouselForm)
+	}
+	if carouselForm.Get("caption") != "hello #meta" || carouselForm.Get("idempotency_key") != "carousel_01" {
+		t.Fatalf("unexpected carousel caption or key: %v", carouselForm)
+	}
+	publishForm, err := url.ParseQuery(stub.calls[7].body)
+	if err != nil {
+		t.Fatalf("parse publish form: %v", err)
+	}
+	if got := publishForm.Get("creation_id"); got != "carousel_9" {
+		t.Fatalf("unexpected creation_id %q", got)
+	}
+
+	if result.Mode != "immediate" || result.CreationID != "carousel_9" || result.MediaID != "media_10" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.Children[1].MediaType != MediaTypeVideo || result.Children[1].Polls != 2 || result.Children[1].StatusCode != MediaStatusCodeFinished {
+		t.Fatalf("unexpected second child: %+v", result.Children[1])
+	}
+}
+
+func TestServicePublishCarouselReportsPartialFailure(t *testing.T) {
+	t.Parallel()
+
+	stub := &sequenceHTTPClient{
+		t: t,
+		responses: []sequenceStubResponse{
+			{statusCode: http.StatusOK, response: `{"id":"child_1"}`},
+			{statusCode: http.StatusOK, response: `{"id":"child_1","status_code":"FINISHED"}`},
+			{statusCode: http.StatusOK, response: `{"id":"child_2"}`},
+			{statusCode: http.StatusOK, response: `{"id":"child_2","status_code":"ERROR"}`},
+		},
+	}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+
+	result, err := New(client).PublishCarousel(context.Background(), "v25.0", "token-1", "secret-1", CarouselPublishOptions{
+		IGUserID:     "17841400008460056",
+		MediaURLs:    []string{"https://cdn.example.com/one.jpg", "https://cdn.example.com/two.jpg", "https://cdn.example.com/three.jpg"},
+		Caption:      "hello #meta",
+		PollInterval: time.Millisecond,
+		Timeout:      time.Second,
+	})
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected structured api error, got %T (%v)", err, err)
+	}
+	if apiErr.Type != igErrorTypeCarouselPartialFailure || apiErr.Retryable {
+		t.Fatalf("unexpected partial failure error: %+v", apiErr)
+	}
+	if !strings.Contains(apiErr.Message, "child_container 2") || !strings.Contains(apiErr.Message, "status_code=ERROR") {
+		t.Fatalf("unexpected partial failure message: %q", apiErr.Message)
+	}
+	if apiErr.Diagnostics["failed_child_index"] != 2 || apiErr.Diagnostics["stage"] != CarouselStageChild {
+		t.Fatalf("unexpected diagnostics: %+v", apiErr.Diagnostics)
+	}
+	if created, ok := apiErr.Diagnostics["created_containers"].([]any); !ok || len(created) != 2 {
+		t.Fatalf("unexpected created containers: %+v", apiErr.Diagnostics["created_containers"])
+	}
+	if len(stub.calls) != 4 {
+		t.Fatalf("expected no calls after failed child, got %d", len(stub.calls))
+	}
+	if result == nil || result.Children[2].CreationID != "" || result.MediaID != "" {
+		t.Fatalf("unexpected partial result: %+v", result)
+	}
+}
+
+func TestServicePublishCarouselDryRunReturnsContainerGraph(t *testing.T) {
+	t.Parallel()
+
+	stub := &sequenceHTTPClient{t: t}
+	client := graph.NewClient(stub, "https://graph.example.com")
+
+	result, err := New(client).PublishCarousel(context.Background(), "v25.0", "token-1", "secret-1", CarouselPublishOptions{
+		IGUserID:  "17841400008460056",
+		MediaURLs: []string{"https://cdn.example.com/one.jpg", "https://cdn.example.com/two.mov"},
+		Caption:   "hello #meta",
+		DryRun:    true,
+	})
+	if err != nil {
+		t.Fatalf("dry-run carousel: %v", err)
+	}
+	if len(stub.calls) != 0 {
+		t.Fatalf("expected no graph calls in dry-run, got %d", len(stub.calls))
+	}
+	if result.Mode != "dry_run" || len(result.Plan) != 4 {
+		t.Fatalf("unexpected dry-run result: %+v", result)
+	}
+	container := result.Plan[2]
+	if container.Stage != CarouselStageContainer || container.Form["children"] != "{{child_1.id}},{{child_2.id}}" {
+		t.Fatalf("unexpected container node: %+v", container)
+	}
+	if len(container.DependsOn) != 2 || container.DependsOn[1] != "child_2" {
+		t.Fatalf("unexpected container dependencies: %+v", container.DependsOn)
+	}
+	publish := result.Plan[3]
+	if publish.Path != "17841400008460056/media_publish" || publish.Form["creation_id"] != "{{carousel.id}}" {
+		t.Fatalf("unexpected publish node: %+v", publish)
+	}
+	if result.Plan[1].Form["video_url"] != "https://cdn.example.com/two.mov" {
+		t.Fatalf("unexpected second child node: %+v", result.Plan[1])
+	}
+}
+
+func TestPlanCarouselRejectsInvalidChildCounts(t *testing.T) {
+	t.Parallel()
+
+	urls := make([]string, 0, MaxCarouselChildren+1)
+	for len(urls) < MaxCarouselChildren+1 {
+		urls = append(urls, "https://cdn.example.com/image.jpg")
+	}
+	for _, mediaURLs := range [][]string{urls[:1], urls} {
+		if _, _, _, err := PlanCarousel(CarouselPublishOptions{IGUserID: "1784", MediaURLs: mediaURLs, Caption: "hello"}); err == nil || !strings.Contains(err.Error(), "2-10 media urls") {
+			t.Fatalf("expected child count error for %d urls, got %v", len(mediaURLs), err)
+		}
+	}
+	if _, _, _, err := PlanCarousel(CarouselPublishOptions{IGUserID: "1784", MediaURLs: []string{"https://cdn.example.com/a.jpg", " "}, Caption: "hello"}); err == nil || !strings.Contains(err.Error(), "carousel child 2") {
+		t.Fatalf("expected child validation error, got %v", err)
+	}
+}
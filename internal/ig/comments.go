@@ -0,0 +1,271 @@
+package ig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+var DefaultCommentFields = []string{"id", "text", "username", "timestamp", "like_count", "hidden", "parent_id"}
+
+type CommentListOptions struct {
+	MediaID    string
+	Fields     []string
+	Limit      int
+	PageSize   int
+	FollowNext bool
+}
+
+type CommentListResult struct {
+	MediaID     string                  `json:"media_id"`
+	RequestPath string                  `json:"request_path"`
+	Comments    []map[string]any        `json:"comments"`
+	Paging      *graph.PaginationResult `json:"paging,omitempty"`
+}
+
+type CommentReplyOptions struct {
+	CommentID string
+	Message   string
+}
+
+type CommentHideOptions struct {
+	CommentID string
+	Hide      bool
+}
+
+type CommentDeleteOptions struct {
+	CommentID string
+}
+
+type CommentMutationResult struct {
+	Operation   string         `json:"operation"`
+	CommentID   string         `json:"comment_id"`
+	ReplyID     string         `json:"reply_id,omitempty"`
+	Hidden      *bool          `json:"hidden,omitempty"`
+	RequestPath string         `json:"request_path"`
+	Response    map[string]any `json:"response"`
+}
+
+func (s *Service) ListComments(ctx context.Context, version string, token string, appSecret string, options CommentListOptions) (*CommentListResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("instagram service client is required")
+	}
+
+	request, mediaID, err := BuildCommentListRequest(version, token, appSecret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]map[string]any, 0)
+	pagination, err := s.Client.FetchWithPagination(ctx, request, graph.PaginationOptions{
+		FollowNext: options.FollowNext,
+		Limit:      options.Limit,
+		PageSize:   options.PageSize,
+	}, func(item map[string]any) error {
+		comments = append(comments, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CommentListResult{
+		MediaID:     mediaID,
+		RequestPath: request.Path,
+		Comments:    comments,
+		Paging:      pagination,
+	}, nil
+}
+
+func (s *Service) ReplyToComment(ctx context.Context, version string, token string, appSecret string, options CommentReplyOptions) (*CommentMutationResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("instagram service client is required")
+	}
+
+	request, commentID, err := BuildCommentReplyRequest(version, token, appSecret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.Client.Do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	replyID, _ := response.Body["id"].(string)
+	if strings.TrimSpace(replyID) == "" {
+		return nil, errors.New("instagram comment reply response did not include id")
+	}
+
+	return &CommentMutationResult{
+		Operation:   "reply",
+		CommentID:   commentID,
+		ReplyID:     strings.TrimSpace(replyID),
+		RequestPath: request.Path,
+		Response:    response.Body,
+	}, nil
+}
+
+func (s *Service) HideComment(ctx context.Context, version string, token string, appSecret string, options CommentHideOptions) (*CommentMutationResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("instagram service client is required")
+	}
+
+	request, commentID, err := BuildCommentHideRequest(version, token, appSecret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.Client.Do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureCommentMutationSucceeded(response.Body, "hide"); err != nil {
+		return nil, err
+	}
+
+	operation := "hide"
+	if !options.Hide {
+		operation = "unhide"
+	}
+	hidden := options.Hide
+	return &CommentMutationResult{
+		Operation:   operation,
+		CommentID:   commentID,
+		Hidden:      &hidden,
+		RequestPath: request.Path,
+		Response:    response.Body,
+	}, nil
+}
+
+func (s *Service) DeleteComment(ctx context.Context, version string, token string, appSecret string, options CommentDeleteOptions) (*CommentMutationResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("instagram service client is required")
+	}
+
+	request, commentID, err := BuildCommentDeleteRequest(version, token, appSecret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.Client.Do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureCommentMutationSucceeded(response.Body, "delete"); err != nil {
+		return nil, err
+	}
+
+	return &CommentMutationResult{
+		Operation:   "delete",
+		CommentID:   commentID,
+		RequestPath: request.Path,
+		Response:    response.Body,
+	}, nil
+}
+
+func BuildCommentListRequest(version string, token string, appSecret string, options CommentListOptions) (graph.Request, string, error) {
+	mediaID, err := normalizeGraphID("media id", options.MediaID)
+	if err != nil {
+		return graph.Request{}, "", err
+	}
+	if options.Limit < 0 {
+		return graph.Request{}, "", errors.New("comment list limit must be >= 0")
+	}
+	if options.PageSize < 0 {
+		return graph.Request{}, "", errors.New("comment list page size must be >= 0")
+	}
+
+	fields := make([]string, 0, len(options.Fields))
+	for _, field := range options.Fields {
+		if trimmed := strings.TrimSpace(field); trimmed != "" {
+			fields = append(fields, trimmed)
+		}
+	}
+	if len(fields) == 0 {
+		fields = append(fields, DefaultCommentFields...)
+	}
+
+	query := map[string]string{
+		"fields": strings.Join(fields, ","),
+	}
+	if options.PageSize > 0 {
+		query["limit"] = strconv.Itoa(options.PageSize)
+	}
+
+	return graph.Request{
+		Method:      "GET",
+		Path:        fmt.Sprintf("%s/comments", mediaID),
+		Version:     strings.TrimSpace(version),
+		Query:       query,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, mediaID, nil
+}
+
+func BuildCommentReplyRequest(version string, token string, appSecret string, options CommentReplyOptions) (graph.Request, string, error) {
+	commentID, err := normalizeGraphID("comment id", options.CommentID)
+	if err != nil {
+		return graph.Request{}, "", err
+	}
+	message := strings.TrimSpace(options.Message)
+	if message == "" {
+		return graph.Request{}, "", errors.New("message is required")
+	}
+
+	return graph.Request{
+		Method:  "POST",
+		Path:    fmt.Sprintf("%s/replies", commentID),
+		Version: strings.TrimSpace(version),
+		Form: map[string]string{
+			"message": message,
+		},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, commentID, nil
+}
+
+func BuildCommentHideRequest(version string, token string, appSecret string, options CommentHideOptions) (graph.Request, string, error) {
+	commentID, err := normalizeGraphID("comment id", options.CommentID)
+	if err != nil {
+		return graph.Request{}, "", err
+	}
+
+	return graph.Request{
+		Method:  "POST",
+		Path:    commentID,
+		Version: strings.TrimSpace(version),
+		Form: map[string]string{
+			"hide": strconv.FormatBool(options.Hide),
+		},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, commentID, nil
+}
+
+func BuildCommentDeleteRequest(version string, token string, appSecret string, options CommentDeleteOptions) (graph.Request, string, error) {
+	commentID, err := normalizeGraphID("comment id", options.CommentID)
+	if err != nil {
+		return graph.Request{}, "", err
+	}
+
+	return graph.Request{
+		Method:      "DELETE",
+		Path:        commentID,
+		Version:     strings.TrimSpace(version),
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, commentID, nil
+}
+
+func ensureCommentMutationSucceeded(body map[string]any, operation string) error {
+	success, ok := body["success"].(bool)
+	if ok && !success {
+		return fmt.Errorf("instagram comment %s response reported success=false", operation)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package ig
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestServiceListCommentsFollowsNextPages(t *testing.T) {
+	t.Parallel()
+
+	stub := &sequenceHTTPClient{
+		t: t,
+		responses: []sequenceStubResponse{
+			{
+				statusCode: http.StatusOK,
+				response:   `{"data":[{"id":"c_1","text":"love it"}],"paging":{"next":"https://graph.example.com/v25.0/media_1/comments?after=cursor_1"}}`,
+			},
+			{
+				statusCode: http.StatusOK,
+				response:   `{"data":[{"id":"c_2","text":"spam"},{"id":"c_3","text":"where to buy?"}]}`,
+			},
+		},
+	}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+
+	result, err := New(client).ListComments(context.Background(), "v25.0", "token-1", "secret-1", CommentListOptions{
+		MediaID:    "media_1",
+		PageSize:   1,
+		FollowNext: true,
+		Limit:      2,
+	})
+	if err != nil {
+		t.Fatalf("list comments: %v", err)
+	}
+	if len(stub.calls) != 2 {
+		t.Fatalf("expected two graph calls, got %d", len(stub.calls))
+	}
+	firstURL, err := url.Parse(stub.calls[0].url)
+	if err != nil {
+		t.Fatalf("parse first url: %v", err)
+	}
+	if firstURL.Path != "/v25.0/media_1/comments" {
+		t.Fatalf("unexpected path %q", firstURL.Path)
+	}
+	if got := firstURL.Query().Get("fields"); got != "id,text,username,timestamp,like_count,hidden,parent_id" {
+		t.Fatalf("unexpected fields %q", got)
+	}
+	if got := firstURL.Query().Get("limit"); got != "1" {
+		t.Fatalf("unexpected page size %q", got)
+	}
+	if len(result.Comments) != 2 || result.Comments[1]["id"] != "c_2" {
+		t.Fatalf("unexpected comments: %+v", result.Comments)
+	}
+	if result.Paging == nil || result.Paging.PagesFetched != 2 {
+		t.Fatalf("unexpected paging: %+v", result.Paging)
+	}
+}
+
+func TestBuildCommentModerationRequestsShapePayloads(t *testing.T) {
+	t.Parallel()
+
+	reply, commentID, err := BuildCommentReplyRequest("v25.0", "token", "secret", CommentReplyOptions{CommentID: " c_1 ", Message: " thanks! "})
+	if err != nil {
+		t.Fatalf("build reply request: %v", err)
+	}
+	if commentID != "c_1" || reply.Method != "POST" || reply.Path != "c_1/replies" || reply.Form["message"] != "thanks!" {
+		t.Fatalf("unexpected reply request: %+v", reply)
+	}
+
+	hide, _, err := BuildCommentHideRequest("v25.0", "token", "secret", CommentHideOptions{CommentID: "c_1", Hide: true})
+	if err != nil {
+		t.Fatalf("build hide request: %v", err)
+	}
+	if hide.Method != "POST" || hide.Path != "c_1" || hide.Form["hide"] != "true" {
+		t.Fatalf("unexpected hide request: %+v", hide)
+	}
+	unhide, _, err := BuildCommentHideRequest("v25.0", "token", "secret", CommentHideOptions{CommentID: "c_1"})
+	if err != nil {
+		t.Fatalf("build unhide request: %v", err)
+	}
+	if unhide.Form["hide"] != "false" {
+		t.Fatalf("unexpected unhide request: %+v", unhide)
+	}
+
+	deleteRequest, _, err := BuildCommentDeleteRequest("v25.0", "token", "secret", CommentDeleteOptions{CommentID: "c_1"})
+	if err != nil {
+		t.Fatalf("build delete request: %v", err)
+	}
+	if deleteRequest.Method != "DELETE" || deleteRequest.Path != "c_1" {
+		t.Fatalf("unexpected delete request: %+v", deleteRequest)
+	}
+
+	if _, _, err := BuildCommentReplyRequest("v25.0", "token", "secret", CommentReplyOptions{CommentID: "c_1"}); err == nil {
+		t.Fatal("expected missing message error")
+	}
+	if _, _, err := BuildCommentDeleteRequest("v25.0", "token", "secret", CommentDeleteOptions{CommentID: "c_1/replies"}); err == nil {
+		t.Fatal("expected invalid comment id error")
+	}
+	if _, _, err := BuildCommentListRequest("v25.0", "token", "secret", CommentListOptions{}); err == nil {
+		t.Fatal("expected missing media id error")
+	}
+}
+
+func TestServiceHideCommentFailsWhenGraphReportsFailure(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{t: t, statusCode: http.StatusOK, response: `{"success":false}`}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+
+	if _, err := New(client).HideComment(context.Background(), "v25.0", "token", "secret", CommentHideOptions{CommentID: "c_1", Hide: true}); err == nil {
+		t.Fatal("expected hide failure")
+	}
+}
@@ -0,0 +1,519 @@
+package ig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	HashtagMediaTypeTop    = "top"
+	HashtagMediaTypeRecent = "recent"
+
+	HashtagQuotaStateSchemaVersion = 1
+	HashtagQueryLimit              = 30
+	HashtagQueryWindow             = 7 * 24 * time.Hour
+	HashtagQuotaWarnRemaining      = 5
+
+	igErrorTypeHashtagQuotaExhausted = "ig_hashtag_quota_exhausted"
+	igErrorCodeHashtagQuotaExhausted = 429100
+)
+
+var DefaultHashtagMediaFields = []string{"id", "caption", "media_type", "permalink", "timestamp", "like_count", "comments_count"}
+
+type HashtagSearchOptions struct {
+	IGUserID string
+	Query    string
+}
+
+type HashtagSearchResult struct {
+	IGUserID    string              `json:"ig_user_id"`
+	Query       string              `json:"query"`
+	HashtagID   string              `json:"hashtag_id"`
+	RequestPath string              `json:"request_path"`
+	Quota       *HashtagQuotaStatus `json:"quota,omitempty"`
+}
+
+type HashtagMediaOptions struct {
+	IGUserID   string
+	HashtagID  string
+	Type       string
+	Fields     []string
+	Limit      int
+	PageSize   int
+	FollowNext bool
+}
+
+type HashtagMediaResult struct {
+	IGUserID    string                  `json:"ig_user_id"`
+	HashtagID   string                  `json:"hashtag_id"`
+	Type        string                  `json:"type"`
+	RequestPath string                  `json:"request_path"`
+	Media       []map[string]any        `json:"media"`
+	Paging      *graph.PaginationResult `json:"paging,omitempty"`
+}
+
+type HashtagQuotaStatus struct {
+	IGUserID    string   `json:"ig_user_id"`
+	Limit       int      `json:"limit"`
+	Used        int      `json:"used"`
+	Remaining   int      `json:"remaining"`
+	WindowStart string   `json:"window_start"`
+	Hashtags    []string `json:"hashtags"`
+	Warnings    []string `json:"warnings,omitempty"`
+}
+
+type HashtagQueryRecord struct {
+	IGUserID  string `json:"ig_user_id"`
+	Hashtag   string `json:"hashtag"`
+	HashtagID string `json:"hashtag_id,omitempty"`
+	QueriedAt string `json:"queried_at"`
+}
+
+type HashtagQuotaTracker struct {
+	Path string
+	Now  func() time.Time
+}
+
+type hashtagQuotaState struct {
+	SchemaVersion int                  `json:"schema_version"`
+	Queries       []HashtagQueryRecord `json:"queries"`
+}
+
+func NewHashtagQuotaTracker(path string) *HashtagQuotaTracker {
+	return &HashtagQuotaTracker{
+		Path: strings.TrimSpace(path),
+		Now:  time.Now,
+	}
+}
+
+func DefaultHashtagQuotaStatePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "ig", "hashtag_quota.json"), nil
+}
+
+func (s *Service) SearchHashtag(ctx context.Context, version string, token string, appSecret string, options HashtagSearchOptions) (*HashtagSearchResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("instagram service client is required")
+	}
+
+	request, igUserID, query, err := BuildHashtagSearchRequest(version, token, appSecret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.Client.Do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	items := extractBodyItems(response.Body)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("instagram hashtag search returned no id for %q", query)
+	}
+	hashtagID, _ := items[0]["id"].(string)
+	if strings.TrimSpace(hashtagID) == "" {
+		return nil, fmt.Errorf("instagram hashtag search returned no id for %q", query)
+	}
+
+	return &HashtagSearchResult{
+		IGUserID:    igUserID,
+		Query:       query,
+		HashtagID:   strings.TrimSpace(hashtagID),
+		RequestPath: request.Path,
+	}, nil
+}
+
+func (s *Service) HashtagMedia(ctx context.Context, version string, token string, appSecret string, options HashtagMediaOptions) (*HashtagMediaResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("instagram service client is required")
+	}
+
+	request, normalized, err := BuildHashtagMediaRequest(version, token, appSecret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	media := make([]map[string]any, 0)
+	pagination, err := s.Client.FetchWithPagination(ctx, request, graph.PaginationOptions{
+		FollowNext: normalized.FollowNext,
+		Limit:      normalized.Limit,
+		PageSize:   normalized.PageSize,
+	}, func(item map[string]any) error {
+		media = append(media, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &HashtagMediaResult{
+		IGUserID:    normalized.IGUserID,
+		HashtagID:   normalized.HashtagID,
+		Type:        normalized.Type,
+		RequestPath: request.Path,
+		Media:       media,
+		Paging:      pagination,
+	}, nil
+}
+
+func BuildHashtagSearchRequest(version string, token string, appSecret string, options HashtagSearchOptions) (graph.Request, string, string, error) {
+	igUserID, err := normalizeGraphID("ig user id", options.IGUserID)
+	if err != nil {
+		return graph.Request{}, "", "", err
+	}
+	query, err := NormalizeHashtag(options.Query)
+	if err != nil {
+		return graph.Request{}, "", "", err
+	}
+
+	return graph.Request{
+		Method:  "GET",
+		Path:    "ig_hashtag_search",
+		Version: strings.TrimSpace(version),
+		Query: map[string]string{
+			"user_id": igUserID,
+			"q":       query,
+		},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, igUserID, query, nil
+}
+
+func BuildHashtagMediaRequest(version string, token string, appSecret string, options HashtagMediaOptions) (graph.Request, HashtagMediaOptions, error) {
+	igUserID, err := normalizeGraphID("ig user id", options.IGUserID)
+	if err != nil {
+		return graph.Request{}, HashtagMediaOptions{}, err
+	}
+	hashtagID, err := normalizeGraphID("hashtag id", options.HashtagID)
+	if err != nil {
+		return graph.Request{}, HashtagMediaOptions{}, err
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(options.Type))
+	if mediaType == "" {
+		mediaType = HashtagMediaTypeTop
+	}
+	if mediaType != HashtagMediaTypeTop && mediaType != HashtagMediaTypeRecent {
+		return graph.Request{}, HashtagMediaOptions{}, fmt.Errorf("invalid hashtag media type %q: expected %s|%s", options.Type, HashtagMediaTypeTop, HashtagMediaTypeRecent)
+	}
+	if options.Limit < 0 {
+		return graph.Request{}, HashtagMediaOptions{}, errors.New("hashtag media limit must be >= 0")
+	}
+	if options.PageSize < 0 {
+		return graph.Request{}, HashtagMediaOptions{}, errors.New("hashtag media page size must be >= 0")
+	}
+
+	fields := make([]string, 0, len(options.Fields))
+	for _, field := range options.Fields {
+		if trimmed := strings.TrimSpace(field); trimmed != "" {
+			fields = append(fields, trimmed)
+		}
+	}
+	if len(fields) == 0 {
+		fields = append(fields, DefaultHashtagMediaFields...)
+	}
+
+	query := map[string]string{
+		"user_id": igUserID,
+		"fields":  strings.Join(fields, ","),
+	}
+	if options.PageSize > 0 {
+		query["limit"] = strconv.Itoa(options.PageSize)
+	}
+
+	normalized := options
+	normalized.IGUserID = igUserID
+	normalized.HashtagID = hashtagID
+	normalized.Type = mediaType
+	normalized.Fields = fields
+
+	return graph.Request{
+		Method:      "GET",
+		Path:        fmt.Sprintf("%s/%s_media", hashtagID, mediaType),
+		Version:     strings.TrimSpace(version),
+		Query:       query,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, normalized, nil
+}
+
+func NormalizeHashtag(value string) (string, error) {
+	hashtag := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), "#"))
+	if hashtag == "" {
+		return "", errors.New("hashtag query is required")
+	}
+	if strings.ContainsAny(hashtag, " \t\n#/") {
+		return "", fmt.Errorf("invalid hashtag query %q: expected a single hashtag without spaces", value)
+	}
+	return hashtag, nil
+}
+
+// Check reports quota usage as it would look after querying hashtag. Re-querying a
+// hashtag already counted in the rolling window does not consume another slot.
+func (t *HashtagQuotaTracker) Check(igUserID string, hashtag string) (HashtagQuotaStatus, error) {
+	if t == nil {
+		return HashtagQuotaStatus{}, errors.New("hashtag quota tracker is required")
+	}
+	igUserID, hashtag, err := normalizeHashtagQuotaKey(igUserID, hashtag)
+	if err != nil {
+		return HashtagQuotaStatus{}, err
+	}
+	state, err := loadHashtagQuotaState(t.Path)
+	if err != nil {
+		return HashtagQuotaStatus{}, err
+	}
+
+	now := t.nowUTC()
+	status := hashtagQuotaStatus(state, igUserID, now)
+	if containsString(status.Hashtags, hashtag) {
+		return status, nil
+	}
+	if status.Remaining <= 0 {
+		return status, newHashtagQuotaExhaustedError(status, hashtag)
+	}
+	status.Used++
+	status.Remaining--
+	status.Hashtags = append(status.Hashtags, hashtag)
+	sort.Strings(status.Hashtags)
+	status.Warnings = hashtagQuotaWarnings(status)
+	return status, nil
+}
+
+func (t *HashtagQuotaTracker) Record(igUserID string, hashtag string, hashtagID string) (HashtagQuotaStatus, error) {
+	if t == nil {
+		return HashtagQuotaStatus{}, errors.New("hashtag quota tracker is required")
+	}
+	igUserID, hashtag, err := normalizeHashtagQuotaKey(igUserID, hashtag)
+	if err != nil {
+		return HashtagQuotaStatus{}, err
+	}
+	lock, err := filelock.Acquire(t.Path)
+	if err != nil {
+		return HashtagQuotaStatus{}, err
+	}
+	defer lock.Release()
+
+	state, err := loadHashtagQuotaState(t.Path)
+	if err != nil {
+		return HashtagQuotaStatus{}, err
+	}
+
+	now := t.nowUTC()
+	pruneHashtagQueries(&state, now)
+	if !containsString(hashtagQuotaStatus(state, igUserID, now).Hashtags, hashtag) {
+		state.Queries = append(state.Queries, HashtagQueryRecord{
+			IGUserID:  igUserID,
+			Hashtag:   hashtag,
+			HashtagID: strings.TrimSpace(hashtagID),
+			QueriedAt: now.Format(time.RFC3339),
+		})
+	}
+	if err := saveHashtagQuotaState(lock, t.Path, state); err != nil {
+		return HashtagQuotaStatus{}, err
+	}
+
+	status := hashtagQuotaStatus(state, igUserID, now)
+	status.Warnings = hashtagQuotaWarnings(status)
+	return status, nil
+}
+
+func (t *HashtagQuotaTracker) nowUTC() time.Time {
+	if t.Now == nil {
+		return time.Now().UTC()
+	}
+	return t.Now().UTC()
+}
+
+func normalizeHashtagQuotaKey(igUserID string, hashtag string) (string, string, error) {
+	normalizedUserID, err := normalizeGraphID("ig user id", igUserID)
+	if err != nil {
+		return "", "", err
+	}
+	normalizedHashtag, err := NormalizeHashtag(hashtag)
+	if err != nil {
+		return "", "", err
+	}
+	return normalizedUserID, normalizedHashtag, nil
+}
+
+func hashtagQuotaStatus(state hashtagQuotaState, igUserID string, now time.Time) HashtagQuotaStatus {
+	windowStart := now.Add(-HashtagQueryWindow)
+	hashtags := make([]string, 0)
+	for _, record := range state.Queries {
+		if record.IGUserID != igUserID {
+			continue
+		}
+		queriedAt, err := time.Parse(time.RFC3339, record.QueriedAt)
+		if err != nil || !queriedAt.After(windowStart) {
+			continue
+		}
+		if !containsString(hashtags, record.Hashtag) {
+			hashtags = append(hashtags, record.Hashtag)
+		}
+	}
+	sort.Strings(hashtags)
+
+	remaining := HashtagQueryLimit - len(hashtags)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return HashtagQuotaStatus{
+		IGUserID:    igUserID,
+		Limit:       HashtagQueryLimit,
+		Used:        len(hashtags),
+		Remaining:   remaining,
+		WindowStart: windowStart.Format(time.RFC3339),
+		Hashtags:    hashtags,
+	}
+}
+
+func hashtagQuotaWarnings(status HashtagQuotaStatus) []string {
+	if status.Remaining > HashtagQuotaWarnRemaining {
+		return nil
+	}
+	return []string{fmt.Sprintf("hashtag query quota nearly exhausted (%d/%d unique hashtags used in the last 7 days)", status.Used, status.Limit)}
+}
+
+func newHashtagQuotaExhaustedError(status HashtagQuotaStatus, hashtag string) error {
+	return &graph.APIError{
+		Type:       igErrorTypeHashtagQuotaExhausted,
+		Code:       igErrorCodeHashtagQuotaExhausted,
+		StatusCode: 429,
+		Message:    fmt.Sprintf("hashtag query quota exhausted for ig user %s (%d/%d unique hashtags in the last 7 days); cannot query %q", status.IGUserID, status.Used, status.Limit, hashtag),
+		Retryable:  false,
+		Diagnostics: map[string]any{
+			"ig_user_id":   status.IGUserID,
+			"limit":        status.Limit,
+			"used":         status.Used,
+			"window_start": status.WindowStart,
+			"hashtags":     status.Hashtags,
+		},
+		Remediation: &graph.Remediation{
+			Category: graph.RemediationCategoryRateLimit,
+			Summary:  "Instagram allows 30 unique hashtag searches per IG user in a rolling 7-day window.",
+			Actions: []string{
+				"Reuse a hashtag id already searched this week with `meta ig hashtag media --hashtag-id`.",
+				"Wait for older queries to leave the 7-day window before searching new hashtags.",
+			},
+		},
+	}
+}
+
+func pruneHashtagQueries(state *hashtagQuotaState, now time.Time) {
+	windowStart := now.Add(-HashtagQueryWindow)
+	kept := make([]HashtagQueryRecord, 0, len(state.Queries))
+	for _, record := range state.Queries {
+		queriedAt, err := time.Parse(time.RFC3339, record.QueriedAt)
+		if err != nil || !queriedAt.After(windowStart) {
+			continue
+		}
+		kept = append(kept, record)
+	}
+	state.Queries = kept
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+func loadHashtagQuotaState(path string) (hashtagQuotaState, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return hashtagQuotaState{}, errors.New("hashtag quota state path is required")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return hashtagQuotaState{SchemaVersion: HashtagQuotaStateSchemaVersion, Queries: []HashtagQueryRecord{}}, nil
+		}
+		return hashtagQuotaState{}, fmt.Errorf("read hashtag quota state %s: %w", path, err)
+	}
+
+	var state hashtagQuotaState
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&state); err != nil {
+		return hashtagQuotaState{}, fmt.Errorf("decode hashtag quota state %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return hashtagQuotaState{}, fmt.Errorf("decode hashtag quota state %s: multiple JSON values", path)
+		}
+		return hashtagQuotaState{}, fmt.Errorf("decode hashtag quota state %s: %w", path, err)
+	}
+	if state.SchemaVersion != HashtagQuotaStateSchemaVersion {
+		return hashtagQuotaState{}, fmt.Errorf("unsupported hashtag quota schema_version=%d (expected %d)", state.SchemaVersion, HashtagQuotaStateSchemaVersion)
+	}
+	if state.Queries == nil {
+		state.Queries = []HashtagQueryRecord{}
+	}
+	return state, nil
+}
+
+func saveHashtagQuotaState(held *filelock.Lock, path string, state hashtagQuotaState) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return errors.New("hashtag quota state path is required")
+	}
+
+	lock, err := held.Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create hashtag quota directory for %s: %w", path, err)
+	}
+
+	payload, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal hashtag quota state: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".ig-hashtag-quota-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp hashtag quota file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp hashtag quota file: %w", err)
+	}
+	if err := tmpFile.Chmod(0o600); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("chmod temp hashtag quota file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp hashtag quota file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("replace hashtag quota state %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package ig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestServiceSearchHashtagShapesRequest(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"data":[{"id":"17843857450040591"}]}`,
+	}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+
+	result, err := New(client).SearchHashtag(context.Background(), "v25.0", "token-1", "secret-1", HashtagSearchOptions{
+		IGUserID: "178",
+		Query:    "#Fitness",
+	})
+	if err != nil {
+		t.Fatalf("search hashtag: %v", err)
+	}
+	parsedURL, err := url.Parse(stub.lastURL)
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+	if parsedURL.Path != "/v25.0/ig_hashtag_search" {
+		t.Fatalf("unexpected path %q", parsedURL.Path)
+	}
+	if parsedURL.Query().Get("user_id") != "178" || parsedURL.Query().Get("q") != "fitness" {
+		t.Fatalf("unexpected query %q", parsedURL.RawQuery)
+	}
+	if result.HashtagID != "17843857450040591" || result.Query != "fitness" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestBuildHashtagMediaRequestValidatesType(t *testing.T) {
+	t.Parallel()
+
+	request, normalized, err := BuildHashtagMediaRequest("v25.0", "token", "", HashtagMediaOptions{
+		IGUserID:  "178",
+		HashtagID: "1784",
+		Type:      "Recent",
+		PageSize:  25,
+	})
+	if err != nil {
+		t.Fatalf("build hashtag media request: %v", err)
+	}
+	if request.Path != "1784/recent_media" || normalized.Type != HashtagMediaTypeRecent {
+		t.Fatalf("unexpected request %+v", request)
+	}
+	if request.Query["user_id"] != "178" || request.Query["limit"] != "25" {
+		t.Fatalf("unexpected query %+v", request.Query)
+	}
+
+	if _, _, err := BuildHashtagMediaRequest("v25.0", "token", "", HashtagMediaOptions{IGUserID: "178", HashtagID: "1784", Type: "popular"}); err == nil {
+		t.Fatal("expected invalid type error")
+	}
+}
+
+func TestHashtagQuotaTrackerWarnsAndFailsClosed(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	tracker := NewHashtagQuotaTracker(filepath.Join(t.TempDir(), "hashtag_quota.json"))
+	tracker.Now = func() time.Time { return now }
+
+	for index := 0; index < HashtagQueryLimit; index++ {
+		hashtag := fmt.Sprintf("tag%02d", index)
+		if _, err := tracker.Check("178", hashtag); err != nil {
+			t.Fatalf("check %s: %v", hashtag, err)
+		}
+		status, err := tracker.Record("178", hashtag, "")
+		if err != nil {
+			t.Fatalf("record %s: %v", hashtag, err)
+		}
+		wantWarning := status.Remaining <= HashtagQuotaWarnRemaining
+		if (len(status.Warnings) > 0) != wantWarning {
+			t.Fatalf("unexpected warnings at used=%d: %v", status.Used, status.Warnings)
+		}
+	}
+
+	status, err := tracker.Check("178", "#TAG05")
+	if err != nil {
+		t.Fatalf("re-querying a counted hashtag should not consume quota: %v", err)
+	}
+	if status.Used != HashtagQueryLimit || status.Remaining != 0 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	_, err = tracker.Check("178", "newtag")
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != igErrorTypeHashtagQuotaExhausted {
+		t.Fatalf("expected quota exhausted error, got %v", err)
+	}
+	if _, err := tracker.Check("999", "newtag"); err != nil {
+		t.Fatalf("quota should be tracked per ig user: %v", err)
+	}
+
+	now = now.Add(HashtagQueryWindow + time.Minute)
+	status, err = tracker.Check("178", "newtag")
+	if err != nil {
+		t.Fatalf("expected quota to reset after window: %v", err)
+	}
+	if status.Used != 1 {
+		t.Fatalf("unexpected status after window %+v", status)
+	}
+}
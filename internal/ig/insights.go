@@ -169,6 +169,47 @@ func (s *Service) MediaInsights(ctx context.Context, version string, token strin
 	return rows, nil
 }
 
+func FlattenInsightsMetrics(objectKey string, objectID string, rawMetrics []map[string]any) []map[string]any {
+	rows := make([]map[string]any, 0, len(rawMetrics))
+	for _, metric := range rawMetrics {
+		base := map[string]any{
+			objectKey: objectID,
+			"metric":  metric["name"],
+			"period":  metric["period"],
+		}
+		values, _ := metric["values"].([]any)
+		if len(values) == 0 {
+			row := cloneInsightsRow(base)
+			if totalValue, ok := metric["total_value"].(map[string]any); ok {
+				row["value"] = totalValue["value"]
+			}
+			rows = append(rows, row)
+			continue
+		}
+		for _, rawValue := range values {
+			value, ok := rawValue.(map[string]any)
+			if !ok {
+				continue
+			}
+			row := cloneInsightsRow(base)
+			row["value"] = value["value"]
+			if endTime, ok := value["end_time"]; ok {
+				row["end_time"] = endTime
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func cloneInsightsRow(base map[string]any) map[string]any {
+	row := make(map[string]any, len(base)+2)
+	for key, value := range base {
+		row[key] = value
+	}
+	return row
+}
+
 func buildInsightsQuery(idLabel string, rawID string, rawMetrics []string, period string, metricType string, breakdown string, since string, until string, timeframe string) (string, map[string]string, error) {
 	graphID, err := normalizeGraphID(idLabel, rawID)
 	if err != nil {
@@ -183,3 +183,36 @@ func TestBuildAccountLocalIntentReportNormalizesSupportedBreakdowns(t *testing.T
 		t.Fatalf("unexpected profile_views summary %#v", got)
 	}
 }
+
+func TestFlattenInsightsMetricsEmitsOneRowPerValue(t *testing.T) {
+	t.Parallel()
+
+	rows := FlattenInsightsMetrics("ig_user_id", "178", []map[string]any{
+		{
+			"name":   "reach",
+			"period": "day",
+			"values": []any{
+				map[string]any{"value": float64(10), "end_time": "2026-03-14T07:00:00+0000"},
+				map[string]any{"value": float64(12), "end_time": "2026-03-15T07:00:00+0000"},
+			},
+		},
+		{
+			"name":        "profile_views",
+			"period":      "day",
+			"total_value": map[string]any{"value": float64(5918)},
+		},
+	})
+
+	if len(rows) != 3 {
+		t.Fatalf("expected three flattened rows, got %#v", rows)
+	}
+	if rows[0]["ig_user_id"] != "178" || rows[0]["metric"] != "reach" || rows[0]["value"] != float64(10) || rows[0]["end_time"] != "2026-03-14T07:00:00+0000" {
+		t.Fatalf("unexpected first row %#v", rows[0])
+	}
+	if rows[2]["metric"] != "profile_views" || rows[2]["value"] != float64(5918) {
+		t.Fatalf("unexpected total_value row %#v", rows[2])
+	}
+	if _, ok := rows[2]["end_time"]; ok {
+		t.Fatalf("expected total_value row without end_time, got %#v", rows[2])
+	}
+}
@@ -0,0 +1,213 @@
+package ig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	PublishQuotaPolicyFail = "fail"
+	PublishQuotaPolicyWarn = "warn"
+	PublishQuotaPolicySkip = "skip"
+
+	PublishQuotaWarnRemaining = 5
+
+	igErrorTypePublishQuotaGate = "ig_publish_quota_gate"
+	igErrorCodePublishQuotaGate = 429101
+)
+
+type PublishQuotaOptions struct {
+	IGUserID string
+}
+
+type PublishQuotaResult struct {
+	IGUserID      string         `json:"ig_user_id"`
+	QuotaUsage    int            `json:"quota_usage"`
+	QuotaTotal    int            `json:"quota_total"`
+	QuotaDuration int            `json:"quota_duration"`
+	Remaining     int            `json:"remaining"`
+	RequestPath   string         `json:"request_path"`
+	Response      map[string]any `json:"response"`
+}
+
+type PublishQuotaPreflightOptions struct {
+	IGUserID string
+	Policy   string
+	Reserve  int
+	Required int
+}
+
+type PublishQuotaPreflight struct {
+	Policy   string              `json:"policy"`
+	Required int                 `json:"required"`
+	Reserve  int                 `json:"reserve"`
+	Quota    *PublishQuotaResult `json:"quota,omitempty"`
+	Warnings []string            `json:"warnings,omitempty"`
+}
+
+func (s *Service) PublishQuota(ctx context.Context, version string, token string, appSecret string, options PublishQuotaOptions) (*PublishQuotaResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("instagram service client is required")
+	}
+
+	request, igUserID, err := BuildPublishQuotaRequest(version, token, appSecret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.Client.Do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	items := extractBodyItems(response.Body)
+	if len(items) == 0 {
+		return nil, errors.New("instagram content publishing limit response did not include data")
+	}
+	quotaConfig, _ := items[0]["config"].(map[string]any)
+	if quotaConfig == nil {
+		return nil, errors.New("instagram content publishing limit response did not include config")
+	}
+
+	result := &PublishQuotaResult{
+		IGUserID:      igUserID,
+		QuotaUsage:    intFromQuotaValue(items[0]["quota_usage"]),
+		QuotaTotal:    intFromQuotaValue(quotaConfig["quota_total"]),
+		QuotaDuration: intFromQuotaValue(quotaConfig["quota_duration"]),
+		RequestPath:   request.Path,
+		Response:      response.Body,
+	}
+	result.Remaining = result.QuotaTotal - result.QuotaUsage
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	return result, nil
+}
+
+// PublishQuotaPreflight checks the rolling 24h content publishing limit before a
+// publish. Under the fail policy, lookup errors and quota shortfalls block the publish;
+// under the warn policy they are reported as warnings instead.
+func (s *Service) PublishQuotaPreflight(ctx context.Context, version string, token string, appSecret string, options PublishQuotaPreflightOptions) (*PublishQuotaPreflight, error) {
+	policy, err := NormalizePublishQuotaPolicy(options.Policy)
+	if err != nil {
+		return nil, err
+	}
+	if options.Reserve < 0 {
+		return nil, errors.New("publish quota reserve must be >= 0")
+	}
+	required := options.Required
+	if required <= 0 {
+		required = 1
+	}
+
+	preflight := &PublishQuotaPreflight{
+		Policy:   policy,
+		Required: required,
+		Reserve:  options.Reserve,
+	}
+	if policy == PublishQuotaPolicySkip {
+		return preflight, nil
+	}
+
+	quota, err := s.PublishQuota(ctx, version, token, appSecret, PublishQuotaOptions{IGUserID: options.IGUserID})
+	if err != nil {
+		if policy == PublishQuotaPolicyWarn {
+			preflight.Warnings = append(preflight.Warnings, fmt.Sprintf("publish quota lookup failed: %v", err))
+			return preflight, nil
+		}
+		return nil, err
+	}
+	preflight.Quota = quota
+
+	remainingAfter := quota.Remaining - required
+	if remainingAfter < options.Reserve {
+		message := fmt.Sprintf("instagram publish quota for ig user %s has %d of %d posts remaining in the rolling 24h window; publishing %d would leave less than the reserve of %d", quota.IGUserID, quota.Remaining, quota.QuotaTotal, required, options.Reserve)
+		if remainingAfter < 0 {
+			message = fmt.Sprintf("instagram publish quota exhausted for ig user %s (%d/%d posts used in the rolling 24h window)", quota.IGUserID, quota.QuotaUsage, quota.QuotaTotal)
+		}
+		if policy == PublishQuotaPolicyWarn {
+			preflight.Warnings = append(preflight.Warnings, message)
+			return preflight, nil
+		}
+		return nil, newPublishQuotaGateError(message, quota, required, options.Reserve)
+	}
+	if remainingAfter <= PublishQuotaWarnRemaining {
+		preflight.Warnings = append(preflight.Warnings, fmt.Sprintf("instagram publish quota nearly exhausted (%d of %d posts remaining after this publish)", remainingAfter, quota.QuotaTotal))
+	}
+	return preflight, nil
+}
+
+func BuildPublishQuotaRequest(version string, token string, appSecret string, options PublishQuotaOptions) (graph.Request, string, error) {
+	igUserID, err := normalizeGraphID("ig user id", options.IGUserID)
+	if err != nil {
+		return graph.Request{}, "", err
+	}
+
+	return graph.Request{
+		Method:  "GET",
+		Path:    fmt.Sprintf("%s/content_publishing_limit", igUserID),
+		Version: strings.TrimSpace(version),
+		Query: map[string]string{
+			"fields": "config,quota_usage",
+		},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, igUserID, nil
+}
+
+func NormalizePublishQuotaPolicy(policy string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(policy))
+	if normalized == "" {
+		return PublishQuotaPolicyFail, nil
+	}
+	switch normalized {
+	case PublishQuotaPolicyFail, PublishQuotaPolicyWarn, PublishQuotaPolicySkip:
+		return normalized, nil
+	default:
+		return "", fmt.Errorf("invalid publish quota policy %q: expected %s|%s|%s", policy, PublishQuotaPolicyFail, PublishQuotaPolicyWarn, PublishQuotaPolicySkip)
+	}
+}
+
+func newPublishQuotaGateError(message string, quota *PublishQuotaResult, required int, reserve int) error {
+	return &graph.APIError{
+		Type:       igErrorTypePublishQuotaGate,
+		Code:       igErrorCodePublishQuotaGate,
+		StatusCode: http.StatusTooManyRequests,
+		Message:    message,
+		Retryable:  false,
+		Diagnostics: map[string]any{
+			"ig_user_id":     quota.IGUserID,
+			"quota_usage":    quota.QuotaUsage,
+			"quota_total":    quota.QuotaTotal,
+			"quota_duration": quota.QuotaDuration,
+			"remaining":      quota.Remaining,
+			"required":       required,
+			"reserve":        reserve,
+		},
+		Remediation: newIGRemediation(
+			graph.RemediationCategoryRateLimit,
+			"Instagram limits API-published posts per IG user in a rolling 24h window.",
+			"Check remaining quota with `meta ig publish quota`.",
+			"Schedule the post with --publish-at for after older posts leave the 24h window.",
+			"Lower --quota-reserve or use --quota-policy warn to publish anyway.",
+		),
+	}
+}
+
+func intFromQuotaValue(value any) int {
+	switch typed := value.(type) {
+	case float64:
+		return int(typed)
+	case int:
+		return typed
+	case int64:
+		return int(typed)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,96 @@
+package ig
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func newPublishQuotaTestService(t *testing.T, statusCode int, response string) (*Service, *stubHTTPClient) {
+	t.Helper()
+	stub := &stubHTTPClient{t: t, statusCode: statusCode, response: response}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+	return New(client), stub
+}
+
+func TestServicePublishQuotaParsesContentPublishingLimit(t *testing.T) {
+	t.Parallel()
+
+	service, stub := newPublishQuotaTestService(t, http.StatusOK, `{"data":[{"config":{"quota_total":100,"quota_duration":86400},"quota_usage":7}]}`)
+	result, err := service.PublishQuota(context.Background(), "v25.0", "token", "", PublishQuotaOptions{IGUserID: "178"})
+	if err != nil {
+		t.Fatalf("publish quota: %v", err)
+	}
+	if !strings.Contains(stub.lastURL, "/v25.0/178/content_publishing_limit") || !strings.Contains(stub.lastURL, "fields=config%2Cquota_usage") {
+		t.Fatalf("unexpected url %q", stub.lastURL)
+	}
+	if result.QuotaTotal != 100 || result.QuotaUsage != 7 || result.Remaining != 93 || result.QuotaDuration != 86400 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestServicePublishQuotaPreflightAppliesPolicy(t *testing.T) {
+	t.Parallel()
+
+	nearlyExhausted := `{"data":[{"config":{"quota_total":100,"quota_duration":86400},"quota_usage":97}]}`
+	exhausted := `{"data":[{"config":{"quota_total":100,"quota_duration":86400},"quota_usage":100}]}`
+
+	service, _ := newPublishQuotaTestService(t, http.StatusOK, nearlyExhausted)
+	preflight, err := service.PublishQuotaPreflight(context.Background(), "v25.0", "token", "", PublishQuotaPreflightOptions{IGUserID: "178"})
+	if err != nil {
+		t.Fatalf("preflight near limit: %v", err)
+	}
+	if preflight.Policy != PublishQuotaPolicyFail || len(preflight.Warnings) != 1 || preflight.Quota.Remaining != 3 {
+		t.Fatalf("unexpected near-limit preflight %+v", preflight)
+	}
+
+	if _, err := service.PublishQuotaPreflight(context.Background(), "v25.0", "token", "", PublishQuotaPreflightOptions{IGUserID: "178", Reserve: 3}); err == nil {
+		t.Fatal("expected reserve to block publish")
+	}
+
+	service, _ = newPublishQuotaTestService(t, http.StatusOK, exhausted)
+	_, err = service.PublishQuotaPreflight(context.Background(), "v25.0", "token", "", PublishQuotaPreflightOptions{IGUserID: "178", Policy: "fail"})
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != igErrorTypePublishQuotaGate {
+		t.Fatalf("expected quota gate error, got %v", err)
+	}
+
+	preflight, err = service.PublishQuotaPreflight(context.Background(), "v25.0", "token", "", PublishQuotaPreflightOptions{IGUserID: "178", Policy: "warn"})
+	if err != nil {
+		t.Fatalf("warn policy should not fail: %v", err)
+	}
+	if len(preflight.Warnings) != 1 || !strings.Contains(preflight.Warnings[0], "exhausted") {
+		t.Fatalf("unexpected warn preflight %+v", preflight)
+	}
+
+	service, stub := newPublishQuotaTestService(t, http.StatusOK, exhausted)
+	preflight, err = service.PublishQuotaPreflight(context.Background(), "v25.0", "token", "", PublishQuotaPreflightOptions{IGUserID: "178", Policy: "skip"})
+	if err != nil || preflight.Quota != nil || stub.calls != 0 {
+		t.Fatalf("expected skip policy to avoid graph call, got preflight=%+v err=%v calls=%d", preflight, err, stub.calls)
+	}
+
+	if _, err := service.PublishQuotaPreflight(context.Background(), "v25.0", "token", "", PublishQuotaPreflightOptions{IGUserID: "178", Policy: "maybe"}); err == nil {
+		t.Fatal("expected invalid policy error")
+	}
+}
+
+func TestServicePublishQuotaPreflightLookupFailureRespectsPolicy(t *testing.T) {
+	t.Parallel()
+
+	service, _ := newPublishQuotaTestService(t, http.StatusBadRequest, `{"error":{"message":"Unsupported get request","type":"GraphMethodException","code":100}}`)
+	if _, err := service.PublishQuotaPreflight(context.Background(), "v25.0", "token", "", PublishQuotaPreflightOptions{IGUserID: "178"}); err == nil {
+		t.Fatal("expected fail policy to surface lookup error")
+	}
+	preflight, err := service.PublishQuotaPreflight(context.Background(), "v25.0", "token", "", PublishQuotaPreflightOptions{IGUserID: "178", Policy: PublishQuotaPolicyWarn})
+	if err != nil {
+		t.Fatalf("warn policy should not fail on lookup error: %v", err)
+	}
+	if len(preflight.Warnings) != 1 || !strings.Contains(preflight.Warnings[0], "lookup failed") {
+		t.Fatalf("unexpected preflight %+v", preflight)
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
 )
 
 const (
@@ -130,6 +132,12 @@ func (s *ScheduleService) Schedule(options SchedulePublishOptions) (*SchedulePub
 		return nil, err
 	}
 
+	lock, err := filelock.Acquire(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
 	state, err := loadScheduleState(s.Path)
 	if err != nil {
 		return nil, err
@@ -202,6 +210,12 @@ func (s *ScheduleService) List(options ScheduleListOptions) (*ScheduleListResult
 		filterStatus = normalizedStatus
 	}
 
+	lock, err := filelock.Acquire(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
 	state, err := loadScheduleState(s.Path)
 	if err != nil {
 		return nil, err
@@ -250,6 +264,12 @@ func (s *ScheduleService) Cancel(options ScheduleCancelOptions) (*ScheduleTransi
 		return nil, errors.New("schedule id is required")
 	}
 
+	lock, err := filelock.Acquire(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
 	state, err := loadScheduleState(s.Path)
 	if err != nil {
 		return nil, err
@@ -294,6 +314,12 @@ func (s *ScheduleService) Retry(options ScheduleRetryOptions) (*ScheduleTransiti
 		return nil, errors.New("schedule id is required")
 	}
 
+	lock, err := filelock.Acquire(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
 	state, err := loadScheduleState(s.Path)
 	if err != nil {
 		return nil, err
@@ -386,6 +412,12 @@ func (s *ScheduleService) ExecuteDue(ctx context.Context, options ScheduleExecut
 		return nil, errors.New("publish function is required")
 	}
 
+	lock, err := filelock.Acquire(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
 	state, err := loadScheduleState(s.Path)
 	if err != nil {
 		return nil, err
@@ -87,6 +87,7 @@ type FeedPublishResult struct {
 	UploadResponse     map[string]any          `json:"upload_response"`
 	StatusResponse     map[string]any          `json:"status_response"`
 	PublishResponse    map[string]any          `json:"publish_response"`
+	QuotaPreflight     *PublishQuotaPreflight  `json:"quota_preflight,omitempty"`
 }
 
 type Service struct {
@@ -153,6 +154,11 @@ func (s *Service) Status(ctx context.Context, version string, token string, appS
 
 	status, _ := response.Body["status"].(string)
 	statusCode, _ := response.Body["status_code"].(string)
+	if graph.DryRunFromContext(ctx).Simulated(creationID) {
+		// A container planned by a dry run was never uploaded, so nothing
+		// processes it; report it ready so the publish is planned too.
+		statusCode = MediaStatusCodeFinished
+	}
 	return &MediaStatusResult{
 		CreationID: creationID,
 		Status:     strings.TrimSpace(status),
@@ -0,0 +1,115 @@
+package ig
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	StoryElementLinkSticker     = "link_sticker"
+	StoryElementPollSticker     = "poll_sticker"
+	StoryElementQuestionSticker = "question_sticker"
+
+	MinStoryPollOptions = 2
+	MaxStoryPollOptions = 4
+
+	igErrorTypeStoryElement = "ig_story_element_violation"
+	igErrorCodeStoryElement = 422200
+)
+
+type StoryElementOptions struct {
+	LinkURL        string
+	Poll           string
+	QuestionPrompt string
+}
+
+func (o StoryElementOptions) Requested() []string {
+	elements := make([]string, 0, 3)
+	if strings.TrimSpace(o.LinkURL) != "" {
+		elements = append(elements, StoryElementLinkSticker)
+	}
+	if strings.TrimSpace(o.Poll) != "" {
+		elements = append(elements, StoryElementPollSticker)
+	}
+	if strings.TrimSpace(o.QuestionPrompt) != "" {
+		elements = append(elements, StoryElementQuestionSticker)
+	}
+	return elements
+}
+
+// ValidateStoryElements checks requested interactive elements against the publish
+// surface and media type. The Content Publishing API exposes no sticker fields on IG
+// user media, so requested stickers always produce violations instead of being
+// silently dropped from the published story.
+func ValidateStoryElements(surface string, mediaType string, options StoryElementOptions) error {
+	requested := options.Requested()
+	if len(requested) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(requested))
+	violations := make([]any, 0, len(requested))
+	addViolation := func(element string, reason string) {
+		messages = append(messages, reason)
+		violations = append(violations, map[string]any{
+			"element":    element,
+			"surface":    surface,
+			"media_type": mediaType,
+			"reason":     reason,
+		})
+	}
+
+	for _, element := range requested {
+		if reason := storyElementFormatViolation(element, options); reason != "" {
+			addViolation(element, reason)
+		}
+		if surface != PublishSurfaceStory {
+			addViolation(element, fmt.Sprintf("%s is only available on stories, not the %s surface", element, surface))
+			continue
+		}
+		addViolation(element, fmt.Sprintf("the Instagram content publishing API does not support %s on %s media; add it in the Instagram app after publishing", element, mediaType))
+	}
+
+	return &graph.APIError{
+		Type:      igErrorTypeStoryElement,
+		Code:      igErrorCodeStoryElement,
+		Message:   "unsupported interactive elements: " + strings.Join(messages, "; "),
+		Retryable: false,
+		Diagnostics: map[string]any{
+			"violations": violations,
+		},
+		Remediation: newIGRemediation(
+			graph.RemediationCategoryValidation,
+			"Requested interactive elements cannot be attached through the publishing API.",
+			"Remove --link-sticker, --poll, and --question-sticker and add stickers in the Instagram app after publishing.",
+		),
+	}
+}
+
+func storyElementFormatViolation(element string, options StoryElementOptions) string {
+	switch element {
+	case StoryElementLinkSticker:
+		parsed, err := url.Parse(strings.TrimSpace(options.LinkURL))
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return fmt.Sprintf("link sticker url %q must be an absolute https URL", options.LinkURL)
+		}
+	case StoryElementPollSticker:
+		parts := strings.Split(options.Poll, "|")
+		if strings.TrimSpace(parts[0]) == "" {
+			return "poll sticker requires a question before the first '|'"
+		}
+		optionCount := 0
+		for _, option := range parts[1:] {
+			if strings.TrimSpace(option) != "" {
+				optionCount++
+			}
+		}
+		if optionCount < MinStoryPollOptions || optionCount > MaxStoryPollOptions {
+			return fmt.Sprintf("poll sticker requires %d-%d options, got %d", MinStoryPollOptions, MaxStoryPollOptions, optionCount)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+package ig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestValidateStoryElementsReportsViolationsPerSurface(t *testing.T) {
+	t.Parallel()
+
+	if err := ValidateStoryElements(PublishSurfaceStory, MediaTypeStories, StoryElementOptions{}); err != nil {
+		t.Fatalf("expected no violations without elements, got %v", err)
+	}
+
+	err := ValidateStoryElements(PublishSurfaceStory, MediaTypeStories, StoryElementOptions{
+		LinkURL: "https://shop.example.com/drop",
+		Poll:    "Which color?|Red|Blue",
+	})
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != igErrorTypeStoryElement {
+		t.Fatalf("expected story element violation, got %v", err)
+	}
+	violations, _ := apiErr.Diagnostics["violations"].([]any)
+	if len(violations) != 2 {
+		t.Fatalf("expected one support violation per element, got %#v", violations)
+	}
+	first, _ := violations[0].(map[string]any)
+	if first["element"] != StoryElementLinkSticker || first["media_type"] != MediaTypeStories {
+		t.Fatalf("unexpected first violation %#v", first)
+	}
+
+	err = ValidateStoryElements(PublishSurfaceFeed, MediaTypeImage, StoryElementOptions{
+		LinkURL: "http://shop.example.com",
+		Poll:    "Pick one|Only",
+	})
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected story element violation, got %v", err)
+	}
+	for _, want := range []string{"must be an absolute https URL", "requires 2-4 options, got 1", "only available on stories, not the feed surface"} {
+		if !strings.Contains(apiErr.Message, want) {
+			t.Fatalf("expected %q in %q", want, apiErr.Message)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package ig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/progress"
+	"github.com/bilalbayram/metacli/internal/transport"
+)
+
+const (
+	ResumableUploadBaseURL       = "https://rupload.facebook.com/ig-api-upload"
+	DefaultResumableChunkSize    = 8 << 20
+	DefaultResumableChunkRetries = 3
+	defaultResumableRetryBackoff = 500 * time.Millisecond
+
+	igErrorTypeResumableUpload = "ig_resumable_upload_error"
+	igErrorCodeResumableUpload = 502100
+)
+
+type ResumableUploadOptions struct {
+	IGUserID        string
+	FilePath        string
+	Caption         string
+	MediaType       string
+	IsCarouselItem  bool
+	IdempotencyKey  string
+	ChunkSize       int64
+	MaxChunkRetries int
+	Progress        func(ResumableUploadProgress)
+}
+
+type ResumableUploadProgress struct {
+	Chunk         int   `json:"chunk"`
+	Chunks        int   `json:"chunks"`
+	BytesUploaded int64 `json:"bytes_uploaded"`
+	TotalBytes    int64 `json:"total_bytes"`
+}
+
+type ResumableUploadResult struct {
+	CreationID     string         `json:"creation_id"`
+	RequestPath    string         `json:"request_path"`
+	MediaType      string         `json:"media_type"`
+	FilePath       string         `json:"file_path"`
+	FileSize       int64          `json:"file_size"`
+	UploadURI      string         `json:"upload_uri"`
+	Chunks         int            `json:"chunks"`
+	ChunkRetries   int            `json:"chunk_retries"`
+	Response       map[string]any `json:"response"`
+	UploadResponse map[string]any `json:"upload_response"`
+}
+
+func (s *Service) UploadFile(ctx context.Context, version string, token string, appSecret string, options ResumableUploadOptions) (*ResumableUploadResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("instagram service client is required")
+	}
+	if s.Client.HTTP == nil {
+		return nil, errors.New("instagram service http client is required")
+	}
+
+	request, mediaType, err := BuildResumableUploadRequest(version, token, appSecret, options)
+	if err != nil {
+		return nil, err
+	}
+	filePath, fileSize, err := statResumableUploadFile(options.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	chunkSize, maxRetries, err := normalizeResumableChunking(options.ChunkSize, options.MaxChunkRetries)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.Client.Do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	creationID, _ := response.Body["id"].(string)
+	creationID = strings.TrimSpace(creationID)
+	if creationID == "" {
+		return nil, errors.New("instagram resumable upload response did not include id")
+	}
+	uploadURI, _ := response.Body["uri"].(string)
+	uploadURI = strings.TrimSpace(uploadURI)
+	if uploadURI == "" {
+		uploadURI = fmt.Sprintf("%s/%s/%s", ResumableUploadBaseURL, request.Version, creationID)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("open upload file %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	chunks := int((fileSize + chunkSize - 1) / chunkSize)
+	result := &ResumableUploadResult{
+		CreationID:  creationID,
+		RequestPath: request.Path,
+		MediaType:   mediaType,
+		FilePath:    filePath,
+		FileSize:    fileSize,
+		UploadURI:   uploadURI,
+		Chunks:      chunks,
+		Response:    response.Body,
+	}
+
+	buffer := make([]byte, chunkSize)
+	var offset int64
+	for chunk := 1; offset < fileSize; chunk++ {
+		read, err := io.ReadFull(file, buffer)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("read upload file %s: %w", filePath, err)
+		}
+		if read == 0 {
+			return nil, fmt.Errorf("read upload file %s: unexpected end of file at offset %d", filePath, offset)
+		}
+
+		for attempt := 1; ; attempt++ {
+			body, retryable, err := s.uploadChunk(ctx, uploadURI, token, buffer[:read], offset, fileSize)
+			if err == nil {
+				result.UploadResponse = body
+				break
+			}
+			if !retryable || attempt > maxRetries {
+				return nil, newResumableUploadError(result, chunk, offset, attempt, err)
+			}
+			result.ChunkRetries++
+			backoff := defaultResumableRetryBackoff * time.Duration(attempt)
+			progress.FromContext(ctx).Wait(fmt.Sprintf("retrying chunk %d after attempt %d failed", chunk, attempt), backoff)
+			if sleepErr := s.sleep(ctx, backoff); sleepErr != nil {
+				return nil, newResumableUploadError(result, chunk, offset, attempt, sleepErr)
+			}
+		}
+
+		offset += int64(read)
+		if options.Progress != nil {
+			options.Progress(ResumableUploadProgress{
+				Chunk:         chunk,
+				Chunks:        chunks,
+				BytesUploaded: offset,
+				TotalBytes:    fileSize,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func BuildResumableUploadRequest(version string, token string, appSecret string, options ResumableUploadOptions) (graph.Request, string, error) {
+	igUserID, err := normalizeGraphID("ig user id", options.IGUserID)
+	if err != nil {
+		return graph.Request{}, "", err
+	}
+	if strings.TrimSpace(options.FilePath) == "" {
+		return graph.Request{}, "", errors.New("upload file path is required")
+	}
+	mediaType, err := normalizeMediaType(options.MediaType)
+	if err != nil {
+		return graph.Request{}, "", err
+	}
+	if mediaType == MediaTypeImage {
+		return graph.Request{}, "", errors.New("local file upload supports VIDEO|REELS|STORIES; use --media-url for images")
+	}
+
+	form := map[string]string{
+		"media_type":  mediaType,
+		"upload_type": "resumable",
+	}
+	if caption := strings.TrimSpace(options.Caption); caption != "" {
+		form["caption"] = caption
+	}
+	if options.IsCarouselItem {
+		form["is_carousel_item"] = "true"
+	}
+	idempotencyKey, err := normalizeIdempotencyKey(options.IdempotencyKey)
+	if err != nil {
+		return graph.Request{}, "", err
+	}
+	if idempotencyKey != "" {
+		form["idempotency_key"] = idempotencyKey
+	}
+
+	return graph.Request{
+		Method:      "POST",
+		Path:        fmt.Sprintf("%s/media", igUserID),
+		Version:     strings.TrimSpace(version),
+		Form:        form,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, mediaType, nil
+}
+
+func (s *Service) uploadChunk(ctx context.Context, uploadURI string, token string, chunk []byte, offset int64, fileSize int64) (map[string]any, bool, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURI, bytes.NewReader(chunk))
+	if err != nil {
+		return nil, false, fmt.Errorf("build resumable upload request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "OAuth "+token)
+	httpReq.Header.Set("offset", strconv.FormatInt(offset, 10))
+	httpReq.Header.Set("file_size", strconv.FormatInt(fileSize, 10))
+	httpReq.Header.Set("Content-Type", "application/octet-stream")
+	if s.Client.UserAgent != "" {
+		httpReq.Header.Set("User-Agent", s.Client.UserAgent)
+	}
+
+	httpRes, err := s.Client.HTTP.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, false, ctx.Err()
+		}
+		if errors.Is(err, transport.ErrOffline) {
+			return nil, false, fmt.Errorf("send chunk: %w", err)
+		}
+		return nil, true, fmt.Errorf("send chunk: %w", err)
+	}
+	defer httpRes.Body.Close()
+
+	payload, err := io.ReadAll(httpRes.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("read chunk response: %w", err)
+	}
+	body := map[string]any{}
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &body); err != nil {
+			return nil, httpRes.StatusCode >= 500, fmt.Errorf("decode chunk response (status %d): %w", httpRes.StatusCode, err)
+		}
+	}
+
+	if httpRes.StatusCode >= 200 && httpRes.StatusCode < 300 {
+		if success, ok := body["success"].(bool); ok && !success {
+			return nil, false, errors.New("chunk response reported success=false")
+		}
+		return body, false, nil
+	}
+
+	retryable := httpRes.StatusCode >= 500 || httpRes.StatusCode == http.StatusTooManyRequests
+	message := fmt.Sprintf("chunk upload failed with status %d", httpRes.StatusCode)
+	if debugInfo, ok := body["debug_info"].(map[string]any); ok {
+		if value, ok := debugInfo["retriable"].(bool); ok {
+			retryable = value
+		}
+		if debugMessage, _ := debugInfo["message"].(string); strings.TrimSpace(debugMessage) != "" {
+			message = fmt.Sprintf("%s: %s", message, strings.TrimSpace(debugMessage))
+		}
+	}
+	return nil, retryable, errors.New(message)
+}
+
+func (s *Service) sleep(ctx context.Context, duration time.Duration) error {
+	return graph.SleepContext(ctx, s.Client.Sleep, duration)
+}
+
+func statResumableUploadFile(path string) (string, int64, error) {
+	path = filepath.Clean(strings.TrimSpace(path))
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("stat upload file %s: %w", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", 0, fmt.Errorf("upload file %s is not a regular file", path)
+	}
+	if info.Size() == 0 {
+		return "", 0, fmt.Errorf("upload file %s is empty", path)
+	}
+	return path, info.Size(), nil
+}
+
+func normalizeResumableChunking(chunkSize int64, maxRetries int) (int64, int, error) {
+	if chunkSize < 0 {
+		return 0, 0, errors.New("chunk size must be >= 0")
+	}
+	if maxRetries < 0 {
+		return 0, 0, errors.New("chunk retries must be >= 0")
+	}
+	if chunkSize == 0 {
+		chunkSize = DefaultResumableChunkSize
+	}
+	return chunkSize, maxRetries, nil
+}
+
+func newResumableUploadError(result *ResumableUploadResult, chunk int, offset int64, attempts int, cause error) error {
+	return &graph.APIError{
+		Type:       igErrorTypeResumableUpload,
+		Code:       igErrorCodeResumableUpload,
+		StatusCode: http.StatusBadGateway,
+		Message:    fmt.Sprintf("resumable upload failed at chunk %d/%d (offset %d) after %d attempt(s): %v", chunk, result.Chunks, offset, attempts, cause),
+		Retryable:  false,
+		Diagnostics: map[string]any{
+			"creation_id": result.CreationID,
+			"upload_uri":  result.UploadURI,
+			"file_path":   result.FilePath,
+			"file_size":   result.FileSize,
+			"chunk":       chunk,
+			"chunks":      result.Chunks,
+			"offset":      offset,
+			"attempts":    attempts,
+		},
+		Remediation: &graph.Remediation{
+			Category: graph.RemediationCategoryTransient,
+			Summary:  "The media container was created but the file bytes did not finish uploading.",
+			Actions: []string{
+				"Re-run the upload to create a fresh container; partially uploaded containers expire automatically.",
+				"Use --chunk-retries to allow more attempts per chunk on unstable connections.",
+			},
+		},
+	}
+}
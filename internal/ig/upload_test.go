@@ -0,0 +1,159 @@
+package ig
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+type ruploadCall struct {
+	url     string
+	headers http.Header
+	body    string
+}
+
+type ruploadHTTPClient struct {
+	t *testing.T
+
+	createResponse string
+	chunkFailures  map[string]int
+	calls          []ruploadCall
+}
+
+func (c *ruploadHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		payload, err := io.ReadAll(req.Body)
+		if err != nil {
+			c.t.Fatalf("read request body: %v", err)
+		}
+		body = string(payload)
+	}
+	c.calls = append(c.calls, ruploadCall{url: req.URL.String(), headers: req.Header.Clone(), body: body})
+
+	if req.URL.Host != "rupload.example.com" {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(c.createResponse))}, nil
+	}
+	offset := req.Header.Get("offset")
+	if c.chunkFailures[offset] > 0 {
+		c.chunkFailures[offset]--
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`{"debug_info":{"retriable":true,"type":"TransientError","message":"try again"}}`)),
+		}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(`{"success":true,"message":"Upload successful."}`))}, nil
+}
+
+func writeUploadFixture(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "clip.mp4")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	return path
+}
+
+func TestServiceUploadFileChunksAndRetriesTransientChunk(t *testing.T) {
+	t.Parallel()
+
+	stub := &ruploadHTTPClient{
+		t:              t,
+		createResponse: `{"id":"container_1","uri":"https://rupload.example.com/ig-api-upload/v25.0/container_1"}`,
+		chunkFailures:  map[string]int{"4": 1},
+	}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+	client.Sleep = func(time.Duration) {}
+
+	progress := make([]ResumableUploadProgress, 0)
+	result, err := New(client).UploadFile(context.Background(), "v25.0", "token-1", "secret-1", ResumableUploadOptions{
+		IGUserID:        "178",
+		FilePath:        writeUploadFixture(t, "abcdefghij"),
+		MediaType:       "reels",
+		Caption:         "hello",
+		ChunkSize:       4,
+		MaxChunkRetries: 2,
+		Progress: func(update ResumableUploadProgress) {
+			progress = append(progress, update)
+		},
+	})
+	if err != nil {
+		t.Fatalf("upload file: %v", err)
+	}
+
+	if len(stub.calls) != 5 {
+		t.Fatalf("expected create + 3 chunks + 1 retry, got %d calls", len(stub.calls))
+	}
+	create := stub.calls[0]
+	if !strings.Contains(create.url, "/v25.0/178/media") || !strings.Contains(create.body, "upload_type=resumable") || !strings.Contains(create.body, "media_type=REELS") {
+		t.Fatalf("unexpected container create call %+v", create)
+	}
+	wantChunks := []struct{ offset, body string }{{"0", "abcd"}, {"4", "efgh"}, {"4", "efgh"}, {"8", "ij"}}
+	for index, want := range wantChunks {
+		call := stub.calls[index+1]
+		if call.headers.Get("offset") != want.offset || call.body != want.body {
+			t.Fatalf("unexpected chunk call %d: offset=%q body=%q", index, call.headers.Get("offset"), call.body)
+		}
+		if call.headers.Get("file_size") != "10" || call.headers.Get("Authorization") != "OAuth token-1" {
+			t.Fatalf("unexpected chunk headers %v", call.headers)
+		}
+	}
+
+	if result.CreationID != "container_1" || result.Chunks != 3 || result.ChunkRetries != 1 || result.FileSize != 10 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(progress) != 3 || progress[2].BytesUploaded != 10 || progress[2].Chunk != 3 {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+}
+
+func TestServiceUploadFileFailsAfterChunkRetriesExhausted(t *testing.T) {
+	t.Parallel()
+
+	stub := &ruploadHTTPClient{
+		t:              t,
+		createResponse: `{"id":"container_1","uri":"https://rupload.example.com/ig-api-upload/v25.0/container_1"}`,
+		chunkFailures:  map[string]int{"0": 5},
+	}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+	client.Sleep = func(time.Duration) {}
+
+	_, err := New(client).UploadFile(context.Background(), "v25.0", "token-1", "", ResumableUploadOptions{
+		IGUserID:        "178",
+		FilePath:        writeUploadFixture(t, "abcdef"),
+		MediaType:       MediaTypeVideo,
+		MaxChunkRetries: 1,
+	})
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != igErrorTypeResumableUpload {
+		t.Fatalf("expected resumable upload error, got %v", err)
+	}
+	if apiErr.Diagnostics["creation_id"] != "container_1" || apiErr.Diagnostics["attempts"] != 2 {
+		t.Fatalf("unexpected diagnostics %+v", apiErr.Diagnostics)
+	}
+	if len(stub.calls) != 3 {
+		t.Fatalf("expected create + 2 chunk attempts, got %d", len(stub.calls))
+	}
+}
+
+func TestBuildResumableUploadRequestRejectsImages(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := BuildResumableUploadRequest("v25.0", "token", "", ResumableUploadOptions{IGUserID: "178", FilePath: "photo.jpg", MediaType: MediaTypeImage}); err == nil {
+		t.Fatal("expected image rejection")
+	}
+	if _, _, err := BuildResumableUploadRequest("v25.0", "token", "", ResumableUploadOptions{IGUserID: "178", MediaType: MediaTypeReels}); err == nil {
+		t.Fatal("expected missing file error")
+	}
+}
@@ -0,0 +1,222 @@
+package insights
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ExportFormatJSON  = "json"
+	ExportFormatJSONL = "jsonl"
+	ExportFormatCSV   = "csv"
+)
+
+// ExportColumns orders columns with the requested fields first, in request order,
+// followed by any other keys present in rows sorted alphabetically. Requested fields
+// are always emitted so repeated exports share a stable header.
+func ExportColumns(rows []map[string]any, preferred []string) []string {
+	columns := make([]string, 0, len(preferred))
+	seen := map[string]struct{}{}
+	for _, field := range preferred {
+		trimmed := strings.TrimSpace(field)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		columns = append(columns, trimmed)
+	}
+
+	extra := make([]string, 0)
+	for _, row := range rows {
+		for key := range row {
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+	return append(columns, extra...)
+}
+
+// WriteRows writes raw insight rows without the CLI envelope. Nested values such as
+// actions are JSON-encoded in csv cells. When header is false csv output omits the
+// header row so files can be appended to.
+func WriteRows(w io.Writer, format string, rows []map[string]any, columns []string, header bool) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case ExportFormatJSON:
+		if rows == nil {
+			rows = []map[string]any{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	case ExportFormatJSONL:
+		for _, row := range rows {
+			encoded, err := json.Marshal(row)
+			if err != nil {
+				return fmt.Errorf("encode insights row: %w", err)
+			}
+			if _, err := fmt.Fprintln(w, string(encoded)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ExportFormatCSV:
+		if len(columns) == 0 {
+			return errors.New("csv export requires at least one column")
+		}
+		writer := csv.NewWriter(w)
+		if header {
+			if err := writer.Write(columns); err != nil {
+				return err
+			}
+		}
+		for _, row := range rows {
+			record := make([]string, 0, len(columns))
+			for _, column := range columns {
+				value, err := exportCellValue(row[column])
+				if err != nil {
+					return fmt.Errorf("encode insights column %q: %w", column, err)
+				}
+				record = append(record, value)
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unsupported export format %q: expected json|jsonl|csv", format)
+	}
+}
+
+type ExportFileResult struct {
+	Path     string   `json:"path"`
+	Format   string   `json:"format"`
+	Columns  []string `json:"columns,omitempty"`
+	Rows     int      `json:"rows"`
+	Appended bool     `json:"appended"`
+}
+
+// WriteExportFile writes rows to a csv or jsonl file. In append mode rows are added
+// to an existing file; an existing csv header is reused so appended rows line up, and
+// rows carrying a column that header lacks are rejected rather than silently dropped.
+func WriteExportFile(path string, format string, rows []map[string]any, columns []string, appendRows bool) (*ExportFileResult, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != ExportFormatCSV && format != ExportFormatJSONL {
+		return nil, fmt.Errorf("unsupported export file format %q: expected csv|jsonl", format)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create export directory for %s: %w", path, err)
+		}
+	}
+
+	appended := false
+	if appendRows {
+		info, err := os.Stat(path)
+		switch {
+		case err == nil:
+			appended = info.Size() > 0
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, fmt.Errorf("stat export file %s: %w", path, err)
+		}
+	}
+	if appended && format == ExportFormatCSV {
+		header, err := readCSVHeader(path)
+		if err != nil {
+			return nil, err
+		}
+		known := make(map[string]struct{}, len(header))
+		for _, column := range header {
+			known[column] = struct{}{}
+		}
+		for _, row := range rows {
+			for key := range row {
+				if _, ok := known[key]; !ok {
+					return nil, fmt.Errorf("export file %s header does not include column %q; write to a new file or overwrite it", path, key)
+				}
+			}
+		}
+		columns = header
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appended {
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open export file %s: %w", path, err)
+	}
+	if err := WriteRows(file, format, rows, columns, !appended); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("write export file %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("close export file %s: %w", path, err)
+	}
+
+	result := &ExportFileResult{
+		Path:     path,
+		Format:   format,
+		Rows:     len(rows),
+		Appended: appended,
+	}
+	if format == ExportFormatCSV {
+		result.Columns = columns
+	}
+	return result, nil
+}
+
+func readCSVHeader(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open export file %s: %w", path, err)
+	}
+	defer file.Close()
+	header, err := csv.NewReader(file).Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header from %s: %w", path, err)
+	}
+	return header, nil
+}
+
+func exportCellValue(value any) (string, error) {
+	switch typed := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return typed, nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), nil
+	case int:
+		return strconv.Itoa(typed), nil
+	case int64:
+		return strconv.FormatInt(typed, 10), nil
+	case json.Number:
+		return typed.String(), nil
+	default:
+		encoded, err := json.Marshal(typed)
+		if err != nil {
+			return "", err
+		}
+		return string(encoded), nil
+	}
+}
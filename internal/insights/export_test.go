@@ -0,0 +1,61 @@
+package insights
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExportColumnsKeepsRequestedFieldsFirst(t *testing.T) {
+	t.Parallel()
+
+	rows := []map[string]any{
+		{"spend": "1", "date_stop": "2026-03-07", "campaign_id": "c1"},
+		{"impressions": "10", "date_start": "2026-03-01"},
+	}
+	got := ExportColumns(rows, []string{"impressions", "spend", "reach", "spend"})
+	want := "impressions,spend,reach,campaign_id,date_start,date_stop"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("unexpected columns %v, want %s", got, want)
+	}
+}
+
+func TestWriteRowsCSVEncodesNestedValues(t *testing.T) {
+	t.Parallel()
+
+	rows := []map[string]any{{
+		"spend":   "5.00",
+		"clicks":  float64(3),
+		"actions": []any{map[string]any{"action_type": "link_click", "value": "3"}},
+	}}
+	columns := []string{"spend", "clicks", "actions", "reach"}
+
+	withHeader := &bytes.Buffer{}
+	if err := WriteRows(withHeader, ExportFormatCSV, rows, columns, true); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	want := "spend,clicks,actions,reach\n5.00,3,\"[{\"\"action_type\"\":\"\"link_click\"\",\"\"value\"\":\"\"3\"\"}]\",\n"
+	if withHeader.String() != want {
+		t.Fatalf("unexpected csv %q", withHeader.String())
+	}
+
+	appended := &bytes.Buffer{}
+	if err := WriteRows(appended, ExportFormatCSV, rows, columns, false); err != nil {
+		t.Fatalf("write csv without header: %v", err)
+	}
+	if strings.HasPrefix(appended.String(), "spend,") {
+		t.Fatalf("expected header to be omitted, got %q", appended.String())
+	}
+
+	jsonl := &bytes.Buffer{}
+	if err := WriteRows(jsonl, ExportFormatJSONL, rows, nil, true); err != nil {
+		t.Fatalf("write jsonl: %v", err)
+	}
+	if strings.Count(jsonl.String(), "\n") != 1 || !strings.HasPrefix(jsonl.String(), `{"actions":`) {
+		t.Fatalf("unexpected jsonl %q", jsonl.String())
+	}
+
+	if err := WriteRows(&bytes.Buffer{}, "parquet", rows, columns, true); err == nil {
+		t.Fatal("expected unsupported format error")
+	}
+}
@@ -0,0 +1,473 @@
+package insights
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	JobStateSchemaVersion = 1
+
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+
+	defaultDownloadPageSize = 500
+)
+
+type Job struct {
+	ReportRunID       string            `json:"report_run_id"`
+	Profile           string            `json:"profile"`
+	Version           string            `json:"version"`
+	AccountID         string            `json:"account_id"`
+	Params            map[string]string `json:"params"`
+	PublisherPlatform string            `json:"publisher_platform,omitempty"`
+	Status            string            `json:"status"`
+	AsyncStatus       string            `json:"async_status,omitempty"`
+	PercentCompletion float64           `json:"percent_completion"`
+	SubmittedAt       string            `json:"submitted_at"`
+	UpdatedAt         string            `json:"updated_at"`
+	Download          *JobDownload      `json:"download,omitempty"`
+}
+
+// JobDownload tracks an in-progress or finished download. Bytes is the file size
+// after the last fully written page; a resumed download truncates back to it before
+// requesting the page after Cursor.
+type JobDownload struct {
+	Path      string   `json:"path"`
+	Format    string   `json:"format"`
+	Columns   []string `json:"columns,omitempty"`
+	Cursor    string   `json:"cursor,omitempty"`
+	Bytes     int64    `json:"bytes"`
+	Rows      int      `json:"rows"`
+	Pages     int      `json:"pages"`
+	Completed bool     `json:"completed"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+type ReportRunStatus struct {
+	ReportRunID       string  `json:"report_run_id"`
+	AsyncStatus       string  `json:"async_status"`
+	PercentCompletion float64 `json:"percent_completion"`
+}
+
+func (s ReportRunStatus) JobStatus() string {
+	status := strings.ToLower(strings.TrimSpace(s.AsyncStatus))
+	switch {
+	case isCompleted(status):
+		return JobStatusCompleted
+	case strings.Contains(status, "fail"), strings.Contains(status, "skip"):
+		return JobStatusFailed
+	default:
+		return JobStatusRunning
+	}
+}
+
+type JobStore struct {
+	Path string
+	Now  func() time.Time
+}
+
+type jobState struct {
+	SchemaVersion int   `json:"schema_version"`
+	Jobs          []Job `json:"jobs"`
+}
+
+func NewJobStore(path string) *JobStore {
+	return &JobStore{
+		Path: strings.TrimSpace(path),
+		Now:  time.Now,
+	}
+}
+
+func DefaultJobStatePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "insights", "jobs.json"), nil
+}
+
+func (s *JobStore) List(status string) ([]Job, error) {
+	state, err := loadJobState(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	status = strings.ToLower(strings.TrimSpace(status))
+	jobs := make([]Job, 0, len(state.Jobs))
+	for _, job := range state.Jobs {
+		if status != "" && job.Status != status {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].SubmittedAt > jobs[j].SubmittedAt
+	})
+	return jobs, nil
+}
+
+func (s *JobStore) Get(reportRunID string) (*Job, error) {
+	reportRunID = strings.TrimSpace(reportRunID)
+	if reportRunID == "" {
+		return nil, errors.New("report run id is required")
+	}
+	state, err := loadJobState(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range state.Jobs {
+		if job.ReportRunID == reportRunID {
+			found := job
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("insights job %q was not found in %s", reportRunID, s.Path)
+}
+
+// Put inserts or replaces a job by report run id and stamps UpdatedAt.
+func (s *JobStore) Put(job Job) (*Job, error) {
+	lock, err := filelock.Acquire(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	state, err := loadJobState(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	now := s.nowUTC().Format(time.RFC3339)
+	if job.SubmittedAt == "" {
+		job.SubmittedAt = now
+	}
+	job.UpdatedAt = now
+
+	replaced := false
+	for index := range state.Jobs {
+		if state.Jobs[index].ReportRunID == job.ReportRunID {
+			state.Jobs[index] = job
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		state.Jobs = append(state.Jobs, job)
+	}
+	if err := saveJobState(lock, s.Path, state); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *JobStore) nowUTC() time.Time {
+	if s.Now == nil {
+		return time.Now().UTC()
+	}
+	return s.Now().UTC()
+}
+
+func (s *Service) ReportRunStatus(ctx context.Context, version string, token string, appSecret string, reportRunID string) (*ReportRunStatus, error) {
+	reportRunID = strings.TrimSpace(reportRunID)
+	if reportRunID == "" {
+		return nil, errors.New("report run id is required")
+	}
+	resp, err := s.Client.Do(ctx, graph.Request{
+		Method:  "GET",
+		Path:    reportRunID,
+		Version: version,
+		Query: map[string]string{
+			"fields": "async_status,async_percent_completion",
+		},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+	status := &ReportRunStatus{ReportRunID: reportRunID}
+	status.AsyncStatus, _ = resp.Body["async_status"].(string)
+	switch percent := resp.Body["async_percent_completion"].(type) {
+	case float64:
+		status.PercentCompletion = percent
+	case string:
+		status.PercentCompletion, _ = strconv.ParseFloat(percent, 64)
+	}
+	return status, nil
+}
+
+func (s *Service) CancelReportRun(ctx context.Context, version string, token string, appSecret string, reportRunID string) error {
+	reportRunID = strings.TrimSpace(reportRunID)
+	if reportRunID == "" {
+		return errors.New("report run id is required")
+	}
+	_, err := s.Client.Do(ctx, graph.Request{
+		Method:      "DELETE",
+		Path:        reportRunID,
+		Version:     version,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	return err
+}
+
+type DownloadPage struct {
+	Rows       []map[string]any
+	NextCursor string
+	Done       bool
+}
+
+// DownloadReportRun pages through a completed report run starting after cursor and
+// hands each page to onPage before requesting the next one, so callers can persist
+// progress between pages.
+func (s *Service) DownloadReportRun(ctx context.Context, version string, token string, appSecret string, job Job, cursor string, pageSize int, onPage func(DownloadPage) error) error {
+	if onPage == nil {
+		return errors.New("download page handler is required")
+	}
+	if pageSize <= 0 {
+		pageSize = defaultDownloadPageSize
+	}
+	publisherPlatform := strings.ToLower(strings.TrimSpace(job.PublisherPlatform))
+
+	for {
+		query := map[string]string{}
+		for key, value := range job.Params {
+			query[key] = value
+		}
+		query["limit"] = strconv.Itoa(pageSize)
+		if cursor != "" {
+			query["after"] = cursor
+		}
+
+		resp, err := s.Client.Do(ctx, graph.Request{
+			Method:      "GET",
+			Path:        job.ReportRunID + "/insights",
+			Version:     version,
+			Query:       query,
+			AccessToken: token,
+			AppSecret:   appSecret,
+		})
+		if err != nil {
+			return err
+		}
+
+		page := DownloadPage{Rows: make([]map[string]any, 0)}
+		rawRows, _ := resp.Body["data"].([]any)
+		for _, raw := range rawRows {
+			row, ok := raw.(map[string]any)
+			if !ok {
+				continue
+			}
+			if publisherPlatform != "" {
+				value, _ := row["publisher_platform"].(string)
+				if strings.ToLower(strings.TrimSpace(value)) != publisherPlatform {
+					continue
+				}
+			}
+			page.Rows = append(page.Rows, row)
+		}
+		paging, _ := resp.Body["paging"].(map[string]any)
+		next, _ := paging["next"].(string)
+		cursors, _ := paging["cursors"].(map[string]any)
+		page.NextCursor, _ = cursors["after"].(string)
+		page.Done = next == "" || page.NextCursor == ""
+
+		if err := onPage(page); err != nil {
+			return err
+		}
+		if page.Done {
+			return nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+func newJobState() jobState {
+	return jobState{
+		SchemaVersion: JobStateSchemaVersion,
+		Jobs:          []Job{},
+	}
+}
+
+func loadJobState(path string) (jobState, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return jobState{}, errors.New("insights job state path is required")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return newJobState(), nil
+		}
+		return jobState{}, fmt.Errorf("read insights job state %s: %w", path, err)
+	}
+
+	var state jobState
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&state); err != nil {
+		return jobState{}, fmt.Errorf("decode insights job state %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return jobState{}, fmt.Errorf("decode insights job state %s: multiple JSON values", path)
+		}
+		return jobState{}, fmt.Errorf("decode insights job state %s: %w", path, err)
+	}
+	if state.SchemaVersion != JobStateSchemaVersion {
+		return jobState{}, fmt.Errorf("unsupported insights job schema_version=%d (expected %d)", state.SchemaVersion, JobStateSchemaVersion)
+	}
+	return state, nil
+}
+
+func saveJobState(held *filelock.Lock, path string, state jobState) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return errors.New("insights job state path is required")
+	}
+
+	lock, err := held.Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create insights job directory for %s: %w", path, err)
+	}
+
+	payload, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal insights job state: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".insights-jobs-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp insights job file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp insights job file: %w", err)
+	}
+	if err := tmpFile.Chmod(0o600); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("chmod temp insights job file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp insights job file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("replace insights job state %s: %w", path, err)
+	}
+	return nil
+}
+
+type JobDownloadOptions struct {
+	Path     string
+	Format   string
+	PageSize int
+	Restart  bool
+}
+
+// DownloadJob writes a completed report run to a local csv/jsonl file, persisting the
+// cursor after every page. Rerunning with the same path resumes an interrupted
+// download; a finished download to the same path is returned unchanged unless
+// Restart is set.
+func (s *Service) DownloadJob(ctx context.Context, version string, token string, appSecret string, store *JobStore, job Job, options JobDownloadOptions) (*Job, error) {
+	if store == nil {
+		return nil, errors.New("insights job store is required")
+	}
+	path := strings.TrimSpace(options.Path)
+	if path == "" {
+		return nil, errors.New("download path is required")
+	}
+	format := strings.ToLower(strings.TrimSpace(options.Format))
+	if format != ExportFormatCSV && format != ExportFormatJSONL {
+		return nil, fmt.Errorf("unsupported download format %q: expected csv|jsonl", options.Format)
+	}
+
+	download := job.Download
+	resume := download != nil && download.Path == path && !options.Restart
+	if resume && download.Format != format {
+		return nil, fmt.Errorf("download to %s was started as %s; rerun with --format %s or --restart", path, download.Format, download.Format)
+	}
+	if resume && download.Completed {
+		return &job, nil
+	}
+	if !resume {
+		download = &JobDownload{Path: path, Format: format}
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create download directory for %s: %w", path, err)
+		}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open download file %s: %w", path, err)
+	}
+	defer file.Close()
+	if err := file.Truncate(download.Bytes); err != nil {
+		return nil, fmt.Errorf("truncate download file %s: %w", path, err)
+	}
+	if _, err := file.Seek(download.Bytes, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("seek download file %s: %w", path, err)
+	}
+
+	var fields []string
+	if rawFields := strings.TrimSpace(job.Params["fields"]); rawFields != "" {
+		fields = strings.Split(rawFields, ",")
+	}
+	job.Download = download
+	err = s.DownloadReportRun(ctx, version, token, appSecret, job, download.Cursor, options.PageSize, func(page DownloadPage) error {
+		if format == ExportFormatCSV && len(download.Columns) == 0 {
+			download.Columns = ExportColumns(page.Rows, fields)
+		}
+		if len(page.Rows) > 0 {
+			header := format == ExportFormatCSV && download.Bytes == 0
+			if err := WriteRows(file, format, page.Rows, download.Columns, header); err != nil {
+				return fmt.Errorf("write download file %s: %w", path, err)
+			}
+			if err := file.Sync(); err != nil {
+				return fmt.Errorf("sync download file %s: %w", path, err)
+			}
+			offset, err := file.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return fmt.Errorf("seek download file %s: %w", path, err)
+			}
+			download.Bytes = offset
+		}
+		download.Rows += len(page.Rows)
+		download.Pages++
+		download.Cursor = page.NextCursor
+		download.Completed = page.Done
+		download.UpdatedAt = store.nowUTC().Format(time.RFC3339)
+		_, err := store.Put(job)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
@@ -0,0 +1,153 @@
+package insights
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestJobStorePutGetAndList(t *testing.T) {
+	t.Parallel()
+
+	store := NewJobStore(filepath.Join(t.TempDir(), "jobs.json"))
+	clock := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	store.Now = func() time.Time { return clock }
+
+	if _, err := store.Put(Job{ReportRunID: "run_1", Status: JobStatusRunning}); err != nil {
+		t.Fatalf("put run_1: %v", err)
+	}
+	clock = clock.Add(time.Hour)
+	if _, err := store.Put(Job{ReportRunID: "run_2", Status: JobStatusCompleted}); err != nil {
+		t.Fatalf("put run_2: %v", err)
+	}
+
+	job, err := store.Get("run_1")
+	if err != nil {
+		t.Fatalf("get run_1: %v", err)
+	}
+	job.Status = JobStatusCancelled
+	if _, err := store.Put(*job); err != nil {
+		t.Fatalf("update run_1: %v", err)
+	}
+
+	jobs, err := store.List("")
+	if err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].ReportRunID != "run_2" || jobs[1].Status != JobStatusCancelled {
+		t.Fatalf("unexpected jobs %#v", jobs)
+	}
+	if jobs[1].SubmittedAt != "2026-04-01T12:00:00Z" || jobs[1].UpdatedAt != "2026-04-01T13:00:00Z" {
+		t.Fatalf("unexpected timestamps %#v", jobs[1])
+	}
+
+	cancelled, err := store.List(JobStatusCancelled)
+	if err != nil || len(cancelled) != 1 {
+		t.Fatalf("expected one cancelled job, got %#v (%v)", cancelled, err)
+	}
+	if _, err := store.Get("run_missing"); err == nil {
+		t.Fatal("expected missing job error")
+	}
+}
+
+func TestReportRunStatusMapsJobStatus(t *testing.T) {
+	t.Parallel()
+
+	for asyncStatus, want := range map[string]string{
+		"Job Completed":   JobStatusCompleted,
+		"Job Running":     JobStatusRunning,
+		"Job Not Started": JobStatusRunning,
+		"Job Failed":      JobStatusFailed,
+		"Job Skipped":     JobStatusFailed,
+	} {
+		if got := (ReportRunStatus{AsyncStatus: asyncStatus}).JobStatus(); got != want {
+			t.Fatalf("%q: expected %s, got %s", asyncStatus, want, got)
+		}
+	}
+}
+
+func TestDownloadJobResumesAfterInterruptedPage(t *testing.T) {
+	t.Parallel()
+
+	var failSecondPage atomic.Bool
+	failSecondPage.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v25.0/run_1/insights" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("limit") != "2" || r.URL.Query().Get("fields") != "spend,impressions" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch r.URL.Query().Get("after") {
+		case "":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data":   []map[string]any{{"spend": "1", "impressions": "10"}, {"spend": "2", "impressions": "20"}},
+				"paging": map[string]any{"cursors": map[string]any{"after": "c1"}, "next": "https://graph.example.com/next"},
+			})
+		case "c1":
+			if failSecondPage.CompareAndSwap(true, false) {
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"message": "temporary", "code": 2}})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data":   []map[string]any{{"spend": "3", "impressions": "30"}},
+				"paging": map[string]any{"cursors": map[string]any{"after": "c2"}},
+			})
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("after"))
+		}
+	}))
+	defer server.Close()
+
+	client := graph.NewClient(server.Client(), server.URL)
+	client.MaxRetries = 0
+	service := New(client)
+	dir := t.TempDir()
+	store := NewJobStore(filepath.Join(dir, "jobs.json"))
+	job := Job{ReportRunID: "run_1", Status: JobStatusCompleted, Params: map[string]string{"fields": "spend,impressions", "level": "campaign"}}
+	if _, err := store.Put(job); err != nil {
+		t.Fatalf("put job: %v", err)
+	}
+	options := JobDownloadOptions{Path: filepath.Join(dir, "out", "run_1.csv"), Format: ExportFormatCSV, PageSize: 2}
+
+	if _, err := service.DownloadJob(context.Background(), "v25.0", "token", "", store, job, options); err == nil {
+		t.Fatal("expected interrupted download error")
+	}
+	stored, err := store.Get("run_1")
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	if stored.Download == nil || stored.Download.Cursor != "c1" || stored.Download.Rows != 2 || stored.Download.Completed {
+		t.Fatalf("unexpected partial download %#v", stored.Download)
+	}
+
+	finished, err := service.DownloadJob(context.Background(), "v25.0", "token", "", store, *stored, options)
+	if err != nil {
+		t.Fatalf("resume download: %v", err)
+	}
+	if !finished.Download.Completed || finished.Download.Rows != 3 || finished.Download.Pages != 2 {
+		t.Fatalf("unexpected finished download %#v", finished.Download)
+	}
+	content, err := os.ReadFile(options.Path)
+	if err != nil {
+		t.Fatalf("read download: %v", err)
+	}
+	if string(content) != "spend,impressions\n1,10\n2,20\n3,30\n" {
+		t.Fatalf("unexpected download content %q", string(content))
+	}
+
+	again, err := service.DownloadJob(context.Background(), "v25.0", "token", "", store, *finished, options)
+	if err != nil || again.Download.Rows != 3 {
+		t.Fatalf("expected completed download to be a no-op, got %#v (%v)", again, err)
+	}
+}
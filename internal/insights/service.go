@@ -2,6 +2,7 @@ package insights
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -9,8 +10,19 @@ import (
 	"time"
 
 	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/progress"
 )
 
+const asyncWindowThreshold = 31 * 24 * time.Hour
+
+var largeDatePresets = map[string]struct{}{
+	"last_90d":     {},
+	"last_quarter": {},
+	"last_year":    {},
+	"this_year":    {},
+	"maximum":      {},
+}
+
 type RunOptions struct {
 	AccountID         string
 	Level             string
@@ -23,6 +35,19 @@ type RunOptions struct {
 	Limit             int
 	Async             bool
 	PublisherPlatform string
+	TimeIncrement     string
+	Filtering         []Filter
+	// Concurrency above 1 splits a since/until window with a day-based or
+	// monthly time_increment into that many date slices fetched in parallel.
+	// Other queries run as a single request chain.
+	Concurrency int
+}
+
+// Filter is one entry of the Graph insights filtering parameter.
+type Filter struct {
+	Field    string `json:"field" yaml:"field"`
+	Operator string `json:"operator" yaml:"operator"`
+	Value    any    `json:"value" yaml:"value"`
 }
 
 type Result struct {
@@ -51,11 +76,54 @@ func New(client *graph.Client) *Service {
 }
 
 func (s *Service) Run(ctx context.Context, version string, token string, appSecret string, options RunOptions) (*Result, error) {
+	if slices := TimeSlices(options); len(slices) > 1 {
+		return s.runSlices(ctx, version, token, appSecret, options, slices)
+	}
+	path, params, err := buildRunParams(options)
+	if err != nil {
+		return nil, err
+	}
+	trimmedPublisherPlatform := strings.ToLower(strings.TrimSpace(options.PublisherPlatform))
+	if !options.Async {
+		return s.fetchInsights(ctx, version, path, token, appSecret, params, options.Limit, trimmedPublisherPlatform)
+	}
+
+	runID, err := s.startAsyncRun(ctx, version, path, token, appSecret, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.waitForRun(ctx, version, runID, token, appSecret); err != nil {
+		return nil, err
+	}
+	progress.FromContext(ctx).Restart("rows", int64(options.Limit))
+	result, err := s.fetchInsights(ctx, version, fmt.Sprintf("%s/insights", runID), token, appSecret, params, options.Limit, trimmedPublisherPlatform)
+	if err != nil {
+		return nil, err
+	}
+	result.ReportRunID = runID
+	return result, nil
+}
+
+// Submit starts an async report run without waiting for it. The returned params are
+// the query used for the run and should be replayed when downloading its rows.
+func (s *Service) Submit(ctx context.Context, version string, token string, appSecret string, options RunOptions) (string, map[string]string, error) {
+	path, params, err := buildRunParams(options)
+	if err != nil {
+		return "", nil, err
+	}
+	runID, err := s.startAsyncRun(ctx, version, path, token, appSecret, params)
+	if err != nil {
+		return "", nil, err
+	}
+	return runID, params, nil
+}
+
+func buildRunParams(options RunOptions) (string, map[string]string, error) {
 	if strings.TrimSpace(options.AccountID) == "" {
-		return nil, errors.New("account id is required")
+		return "", nil, errors.New("account id is required")
 	}
 	if strings.TrimSpace(options.Level) == "" {
-		return nil, errors.New("insights level is required")
+		return "", nil, errors.New("insights level is required")
 	}
 	params := map[string]string{
 		"level": options.Level,
@@ -65,13 +133,13 @@ func (s *Service) Run(ctx context.Context, version string, token string, appSecr
 		since := strings.TrimSpace(options.Since)
 		until := strings.TrimSpace(options.Until)
 		if since == "" || until == "" {
-			return nil, errors.New("both since and until are required when time_range is used")
+			return "", nil, errors.New("both since and until are required when time_range is used")
 		}
 		params["time_range"] = fmt.Sprintf(`{"since":"%s","until":"%s"}`, since, until)
 	case strings.TrimSpace(options.DatePreset) != "":
 		params["date_preset"] = options.DatePreset
 	default:
-		return nil, errors.New("date preset is required when time_range is not set")
+		return "", nil, errors.New("date preset is required when time_range is not set")
 	}
 
 	breakdowns := normalizeBreakdowns(options.Breakdowns)
@@ -87,33 +155,51 @@ func (s *Service) Run(ctx context.Context, version string, token string, appSecr
 	if len(options.Fields) > 0 {
 		normalizedFields, err := normalizeFields(options.Fields)
 		if err != nil {
-			return nil, err
+			return "", nil, err
 		}
 		params["fields"] = strings.Join(normalizedFields, ",")
 	}
 	if options.Limit > 0 {
 		params["limit"] = strconv.Itoa(options.Limit)
 	}
-
-	path := fmt.Sprintf("act_%s/insights", options.AccountID)
-	trimmedPublisherPlatform := strings.ToLower(strings.TrimSpace(options.PublisherPlatform))
-	if !options.Async {
-		return s.fetchInsights(ctx, version, path, token, appSecret, params, options.Limit, trimmedPublisherPlatform)
+	if timeIncrement := strings.TrimSpace(options.TimeIncrement); timeIncrement != "" {
+		params["time_increment"] = timeIncrement
 	}
+	if len(options.Filtering) > 0 {
+		for index, filter := range options.Filtering {
+			if strings.TrimSpace(filter.Field) == "" || strings.TrimSpace(filter.Operator) == "" {
+				return "", nil, fmt.Errorf("insights filtering[%d] requires field and operator", index)
+			}
+		}
+		encoded, err := json.Marshal(options.Filtering)
+		if err != nil {
+			return "", nil, fmt.Errorf("encode insights filtering: %w", err)
+		}
+		params["filtering"] = string(encoded)
+	}
+	return fmt.Sprintf("act_%s/insights", options.AccountID), params, nil
+}
 
-	runID, err := s.startAsyncRun(ctx, version, path, token, appSecret, params)
-	if err != nil {
-		return nil, err
+// ShouldRunAsync reports whether a query is large enough to be submitted as an async
+// report run: ad-level queries, breakdowns, daily increments, or windows longer than
+// a month.
+func ShouldRunAsync(options RunOptions) bool {
+	if strings.EqualFold(strings.TrimSpace(options.Level), "ad") {
+		return true
 	}
-	if err := s.waitForRun(ctx, version, runID, token, appSecret); err != nil {
-		return nil, err
+	if len(normalizeBreakdowns(options.Breakdowns)) > 0 || strings.TrimSpace(options.PublisherPlatform) != "" {
+		return true
 	}
-	result, err := s.fetchInsights(ctx, version, fmt.Sprintf("%s/insights", runID), token, appSecret, params, options.Limit, trimmedPublisherPlatform)
-	if err != nil {
-		return nil, err
+	if strings.TrimSpace(options.TimeIncrement) != "" {
+		return true
 	}
-	result.ReportRunID = runID
-	return result, nil
+	since, sinceErr := time.Parse(time.DateOnly, strings.TrimSpace(options.Since))
+	until, untilErr := time.Parse(time.DateOnly, strings.TrimSpace(options.Until))
+	if sinceErr == nil && untilErr == nil {
+		return until.Sub(since) > asyncWindowThreshold
+	}
+	_, large := largeDatePresets[strings.ToLower(strings.TrimSpace(options.DatePreset))]
+	return large
 }
 
 func (s *Service) startAsyncRun(ctx context.Context, version string, path string, token string, appSecret string, params map[string]string) (string, error) {
@@ -142,28 +228,25 @@ func (s *Service) startAsyncRun(ctx context.Context, version string, path string
 }
 
 func (s *Service) waitForRun(ctx context.Context, version string, runID string, token string, appSecret string) error {
+	// The bar tracks Meta's percent completion while the report runs.
+	bar := progress.FromContext(ctx)
+	bar.Restart("", 100)
 	for attempt := 1; attempt <= s.MaxPollAttempts; attempt++ {
-		resp, err := s.Client.Do(ctx, graph.Request{
-			Method:  "GET",
-			Path:    runID,
-			Version: version,
-			Query: map[string]string{
-				"fields": "async_status,async_percent_completion",
-			},
-			AccessToken: token,
-			AppSecret:   appSecret,
-		})
+		status, err := s.ReportRunStatus(ctx, version, token, appSecret, runID)
 		if err != nil {
 			return err
 		}
-		status, _ := resp.Body["async_status"].(string)
-		if isCompleted(status) {
+		if isCompleted(status.AsyncStatus) {
 			return nil
 		}
-		if strings.Contains(strings.ToLower(status), "fail") {
-			return fmt.Errorf("async insights run %s failed with status %q", runID, status)
+		if strings.Contains(strings.ToLower(status.AsyncStatus), "fail") {
+			return fmt.Errorf("async insights run %s failed with status %q", runID, status.AsyncStatus)
+		}
+		bar.Set(int64(status.PercentCompletion), 100)
+		bar.Wait(fmt.Sprintf("report run %s is %q", runID, status.AsyncStatus), s.PollInterval)
+		if err := graph.SleepContext(ctx, s.Sleep, s.PollInterval); err != nil {
+			return fmt.Errorf("wait for async insights run %s: %w", runID, err)
 		}
-		s.Sleep(s.PollInterval)
 	}
 	return fmt.Errorf("async insights run %s did not complete after %d attempts", runID, s.MaxPollAttempts)
 }
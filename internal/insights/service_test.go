@@ -221,3 +221,110 @@ func TestRunSupportsExplicitTimeRange(t *testing.T) {
 		t.Fatalf("expected 1 row, got %d", len(result.Rows))
 	}
 }
+
+func TestShouldRunAsync(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		options RunOptions
+		want    bool
+	}{
+		{name: "small campaign query", options: RunOptions{Level: "campaign", DatePreset: "last_7d"}, want: false},
+		{name: "ad level", options: RunOptions{Level: "ad", DatePreset: "last_7d"}, want: true},
+		{name: "breakdowns", options: RunOptions{Level: "campaign", DatePreset: "last_7d", Breakdowns: []string{"age"}}, want: true},
+		{name: "daily increment", options: RunOptions{Level: "campaign", DatePreset: "last_7d", TimeIncrement: "1"}, want: true},
+		{name: "large preset", options: RunOptions{Level: "account", DatePreset: "last_90d"}, want: true},
+		{name: "short range", options: RunOptions{Level: "account", Since: "2026-03-01", Until: "2026-03-31"}, want: false},
+		{name: "long range", options: RunOptions{Level: "account", Since: "2026-01-01", Until: "2026-03-31"}, want: true},
+	}
+	for _, tc := range cases {
+		if got := ShouldRunAsync(tc.options); got != tc.want {
+			t.Fatalf("%s: expected async=%t, got %t", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestTimeSlicesAlignToIncrementBuckets(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		options RunOptions
+		want    []TimeSlice
+	}{
+		{
+			name:    "daily",
+			options: RunOptions{Since: "2026-01-01", Until: "2026-01-10", TimeIncrement: "1", Concurrency: 3},
+			want:    []TimeSlice{{"2026-01-01", "2026-01-03"}, {"2026-01-04", "2026-01-06"}, {"2026-01-07", "2026-01-10"}},
+		},
+		{
+			name:    "weekly buckets stay whole",
+			options: RunOptions{Since: "2026-01-01", Until: "2026-01-20", TimeIncrement: "7", Concurrency: 2},
+			want:    []TimeSlice{{"2026-01-01", "2026-01-07"}, {"2026-01-08", "2026-01-20"}},
+		},
+		{
+			name:    "monthly from mid-month",
+			options: RunOptions{Since: "2026-01-15", Until: "2026-03-10", TimeIncrement: "monthly", Concurrency: 8},
+			want:    []TimeSlice{{"2026-01-15", "2026-01-31"}, {"2026-02-01", "2026-02-28"}, {"2026-03-01", "2026-03-10"}},
+		},
+		{name: "all_days aggregates", options: RunOptions{Since: "2026-01-01", Until: "2026-01-10", TimeIncrement: "all_days", Concurrency: 4}},
+		{name: "date preset", options: RunOptions{DatePreset: "last_30d", TimeIncrement: "1", Concurrency: 4}},
+		{name: "sequential", options: RunOptions{Since: "2026-01-01", Until: "2026-01-10", TimeIncrement: "1", Concurrency: 1}},
+	}
+	for _, tc := range cases {
+		got := TimeSlices(tc.options)
+		if len(got) != len(tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+		for index := range got {
+			if got[index] != tc.want[index] {
+				t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+			}
+		}
+	}
+}
+
+func TestRunSplitsTimeRangeAcrossConcurrentSlices(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		window := map[string]string{}
+		_ = json.Unmarshal([]byte(r.URL.Query().Get("time_range")), &window)
+		if r.URL.Query().Get("time_increment") != "1" {
+			t.Errorf("expected time_increment to be kept, got %q", r.URL.Query().Get("time_increment"))
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{
+				{"date_start": window["since"], "date_stop": window["since"]},
+				{"date_start": window["until"], "date_stop": window["until"]},
+			},
+		})
+	}))
+	defer server.Close()
+
+	svc := New(graph.NewClient(server.Client(), server.URL))
+	result, err := svc.Run(context.Background(), "v25.0", "token", "", RunOptions{
+		AccountID:     "1",
+		Level:         "campaign",
+		Since:         "2026-01-01",
+		Until:         "2026-01-04",
+		TimeIncrement: "1",
+		Concurrency:   2,
+	})
+	if err != nil {
+		t.Fatalf("run sliced insights: %v", err)
+	}
+	var starts []string
+	for _, row := range result.Rows {
+		starts = append(starts, row["date_start"].(string))
+	}
+	if strings.Join(starts, ",") != "2026-01-01,2026-01-02,2026-01-03,2026-01-04" {
+		t.Fatalf("expected rows in date order, got %v", starts)
+	}
+	if requests.Load() != 2 || result.Pagination.ItemsFetched != 4 || result.Pagination.PagesFetched != 2 {
+		t.Fatalf("unexpected requests=%d pagination=%+v", requests.Load(), result.Pagination)
+	}
+}
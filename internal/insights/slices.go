@@ -0,0 +1,142 @@
+package insights
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/progress"
+)
+
+// TimeSlice is one contiguous part of a since/until window, inclusive.
+type TimeSlice struct {
+	Since string `json:"since"`
+	Until string `json:"until"`
+}
+
+// TimeSlices splits the query window into at most options.Concurrency slices
+// whose boundaries fall on time_increment buckets, so every row Meta returns
+// for a slice is a row it would have returned for the whole window. Queries
+// without an explicit window, or aggregated over it (no increment or
+// all_days), are not split and yield nil.
+func TimeSlices(options RunOptions) []TimeSlice {
+	if options.Concurrency < 2 {
+		return nil
+	}
+	since, err := time.Parse(time.DateOnly, strings.TrimSpace(options.Since))
+	if err != nil {
+		return nil
+	}
+	until, err := time.Parse(time.DateOnly, strings.TrimSpace(options.Until))
+	if err != nil || until.Before(since) {
+		return nil
+	}
+
+	var buckets []time.Time
+	increment := strings.ToLower(strings.TrimSpace(options.TimeIncrement))
+	switch {
+	case increment == "monthly":
+		for start := since; !start.After(until); start = time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, time.UTC) {
+			buckets = append(buckets, start)
+		}
+	default:
+		days, err := strconv.Atoi(increment)
+		if err != nil || days < 1 {
+			return nil
+		}
+		for start := since; !start.After(until); start = start.AddDate(0, 0, days) {
+			buckets = append(buckets, start)
+		}
+	}
+
+	count := options.Concurrency
+	if count > len(buckets) {
+		count = len(buckets)
+	}
+	if count < 2 {
+		return nil
+	}
+	slices := make([]TimeSlice, 0, count)
+	for index := 0; index < count; index++ {
+		first := buckets[index*len(buckets)/count]
+		end := until
+		if next := (index + 1) * len(buckets) / count; next < len(buckets) {
+			end = buckets[next].AddDate(0, 0, -1)
+		}
+		slices = append(slices, TimeSlice{Since: first.Format(time.DateOnly), Until: end.Format(time.DateOnly)})
+	}
+	return slices
+}
+
+// runSlices runs one query per slice concurrently, bounded by a shared
+// governor, and concatenates the rows in date order.
+func (s *Service) runSlices(ctx context.Context, version string, token string, appSecret string, options RunOptions, slices []TimeSlice) (*Result, error) {
+	sliced := *s
+	if sliced.Client.Governor == nil {
+		sliced.Client = s.Client.WithGovernor(graph.NewGovernor(options.Concurrency))
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	bar := progress.FromContext(ctx)
+	bar.SetUnit("slices")
+	bar.Set(0, int64(len(slices)))
+	sliceCtx := progress.Without(ctx)
+
+	results := make([]*Result, len(slices))
+	var (
+		group    sync.WaitGroup
+		failOnce sync.Once
+		firstErr error
+		finished atomic.Int64
+	)
+	for index, slice := range slices {
+		sliceOptions := options
+		sliceOptions.Since = slice.Since
+		sliceOptions.Until = slice.Until
+		sliceOptions.DatePreset = ""
+		sliceOptions.Concurrency = 0
+		group.Add(1)
+		go func(index int) {
+			defer group.Done()
+			result, err := sliced.Run(sliceCtx, version, token, appSecret, sliceOptions)
+			if err != nil {
+				// The first failure cancels the other slices; report it, not
+				// the cancellations it caused.
+				failOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[index] = result
+			bar.Set(finished.Add(1), 0)
+		}(index)
+	}
+	group.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	merged := &Result{Rows: make([]map[string]any, 0), Pagination: &graph.PaginationResult{}}
+	runIDs := make([]string, 0, len(slices))
+	for index := range slices {
+		merged.Rows = append(merged.Rows, results[index].Rows...)
+		if pagination := results[index].Pagination; pagination != nil {
+			merged.Pagination.PagesFetched += pagination.PagesFetched
+		}
+		if results[index].ReportRunID != "" {
+			runIDs = append(runIDs, results[index].ReportRunID)
+		}
+	}
+	if options.Limit > 0 && len(merged.Rows) > options.Limit {
+		merged.Rows = merged.Rows[:options.Limit]
+	}
+	merged.Pagination.ItemsFetched = len(merged.Rows)
+	merged.ReportRunID = strings.Join(runIDs, ",")
+	return merged, nil
+}
@@ -0,0 +1,227 @@
+package insights
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const ExportTemplateSchemaVersion = 1
+
+var ErrInvalidExportTemplate = errors.New("invalid insights export template")
+
+var exportTemplateLevels = map[string]struct{}{
+	"account":  {},
+	"campaign": {},
+	"adset":    {},
+	"ad":       {},
+}
+
+// ExportTemplate describes a repeatable insights export, typically run from cron.
+type ExportTemplate struct {
+	SchemaVersion     int                `yaml:"schema_version"`
+	Name              string             `yaml:"name"`
+	AccountID         string             `yaml:"account_id,omitempty"`
+	Level             string             `yaml:"level"`
+	Fields            []string           `yaml:"fields"`
+	Breakdowns        []string           `yaml:"breakdowns,omitempty"`
+	Attribution       []string           `yaml:"attribution,omitempty"`
+	PublisherPlatform string             `yaml:"publisher_platform,omitempty"`
+	Filters           []Filter           `yaml:"filters,omitempty"`
+	Date              ExportTemplateDate `yaml:"date"`
+	Format            string             `yaml:"format,omitempty"`
+	SortBy            []string           `yaml:"sort_by,omitempty"`
+}
+
+// ExportTemplateDate selects the reporting window. Exactly one of preset, since/until,
+// or last_days is set. last_days windows are resolved against the run date and end
+// end_offset_days before it (default 1, i.e. through yesterday) so reruns on the same
+// day query the same window.
+type ExportTemplateDate struct {
+	Preset        string `yaml:"preset,omitempty"`
+	Since         string `yaml:"since,omitempty"`
+	Until         string `yaml:"until,omitempty"`
+	LastDays      int    `yaml:"last_days,omitempty"`
+	EndOffsetDays *int   `yaml:"end_offset_days,omitempty"`
+	TimeIncrement string `yaml:"time_increment,omitempty"`
+}
+
+func LoadExportTemplate(path string) (*ExportTemplate, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("%w: template file path is required", ErrInvalidExportTemplate)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read insights export template %s: %w", path, err)
+	}
+
+	template := &ExportTemplate{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(template); err != nil {
+		return nil, fmt.Errorf("%w: decode %s: %v", ErrInvalidExportTemplate, path, err)
+	}
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (t *ExportTemplate) Validate() error {
+	if t == nil {
+		return fmt.Errorf("%w: template is nil", ErrInvalidExportTemplate)
+	}
+	if t.SchemaVersion != ExportTemplateSchemaVersion {
+		return fmt.Errorf("%w: unsupported schema_version=%d (expected %d)", ErrInvalidExportTemplate, t.SchemaVersion, ExportTemplateSchemaVersion)
+	}
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidExportTemplate)
+	}
+	if _, ok := exportTemplateLevels[strings.ToLower(strings.TrimSpace(t.Level))]; !ok {
+		return fmt.Errorf("%w: level must be one of account|campaign|adset|ad", ErrInvalidExportTemplate)
+	}
+	if len(t.Fields) == 0 {
+		return fmt.Errorf("%w: at least one field is required", ErrInvalidExportTemplate)
+	}
+	if _, err := normalizeFields(t.Fields); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidExportTemplate, err)
+	}
+	for index, filter := range t.Filters {
+		if strings.TrimSpace(filter.Field) == "" || strings.TrimSpace(filter.Operator) == "" {
+			return fmt.Errorf("%w: filters[%d] requires field and operator", ErrInvalidExportTemplate, index)
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(t.Format)) {
+	case "", ExportFormatCSV, ExportFormatJSONL:
+	default:
+		return fmt.Errorf("%w: format must be csv or jsonl", ErrInvalidExportTemplate)
+	}
+	if err := t.Date.validate(); err != nil {
+		return fmt.Errorf("%w: date: %v", ErrInvalidExportTemplate, err)
+	}
+	return nil
+}
+
+func (d ExportTemplateDate) validate() error {
+	windows := 0
+	if strings.TrimSpace(d.Preset) != "" {
+		windows++
+	}
+	if strings.TrimSpace(d.Since) != "" || strings.TrimSpace(d.Until) != "" {
+		windows++
+		if _, err := time.Parse(time.DateOnly, strings.TrimSpace(d.Since)); err != nil {
+			return fmt.Errorf("since must be YYYY-MM-DD")
+		}
+		if _, err := time.Parse(time.DateOnly, strings.TrimSpace(d.Until)); err != nil {
+			return fmt.Errorf("until must be YYYY-MM-DD")
+		}
+	}
+	if d.LastDays != 0 {
+		windows++
+		if d.LastDays < 0 {
+			return errors.New("last_days must be > 0")
+		}
+	}
+	if d.EndOffsetDays != nil {
+		if d.LastDays == 0 {
+			return errors.New("end_offset_days requires last_days")
+		}
+		if *d.EndOffsetDays < 0 {
+			return errors.New("end_offset_days must be >= 0")
+		}
+	}
+	if windows != 1 {
+		return errors.New("exactly one of preset, since/until, or last_days is required")
+	}
+	return nil
+}
+
+// RunOptions resolves the template into an insights query for accountID, using asOf
+// as the run date for relative windows.
+func (t *ExportTemplate) RunOptions(accountID string, asOf time.Time) RunOptions {
+	options := RunOptions{
+		AccountID:         strings.TrimPrefix(strings.TrimSpace(accountID), "act_"),
+		Level:             strings.ToLower(strings.TrimSpace(t.Level)),
+		Fields:            t.Fields,
+		Breakdowns:        t.Breakdowns,
+		Attribution:       t.Attribution,
+		PublisherPlatform: strings.ToLower(strings.TrimSpace(t.PublisherPlatform)),
+		TimeIncrement:     strings.TrimSpace(t.Date.TimeIncrement),
+		Filtering:         t.Filters,
+	}
+	switch {
+	case t.Date.LastDays > 0:
+		offset := 1
+		if t.Date.EndOffsetDays != nil {
+			offset = *t.Date.EndOffsetDays
+		}
+		day := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+		until := day.AddDate(0, 0, -offset)
+		since := until.AddDate(0, 0, -(t.Date.LastDays - 1))
+		options.Since = since.Format(time.DateOnly)
+		options.Until = until.Format(time.DateOnly)
+	case strings.TrimSpace(t.Date.Since) != "":
+		options.Since = strings.TrimSpace(t.Date.Since)
+		options.Until = strings.TrimSpace(t.Date.Until)
+	default:
+		options.DatePreset = strings.TrimSpace(t.Date.Preset)
+	}
+	options.Async = ShouldRunAsync(options)
+	return options
+}
+
+// Columns returns the stable export header for the template: requested fields, then
+// breakdowns, then the reporting window columns Graph adds to every row.
+func (t *ExportTemplate) Columns() []string {
+	columns := make([]string, 0, len(t.Fields)+len(t.Breakdowns)+3)
+	columns = append(columns, t.Fields...)
+	columns = append(columns, normalizeBreakdowns(t.Breakdowns)...)
+	if platform := strings.TrimSpace(t.PublisherPlatform); platform != "" {
+		columns = append(columns, "publisher_platform")
+	}
+	return append(columns, "date_start", "date_stop")
+}
+
+// SortRows orders rows deterministically by the given columns, falling back to the
+// remaining columns so identical queries always produce identical output.
+func SortRows(rows []map[string]any, sortBy []string, columns []string) error {
+	keys := append(append([]string{}, sortBy...), columns...)
+	cells := make([][]string, len(rows))
+	for index, row := range rows {
+		values := make([]string, len(keys))
+		for keyIndex, key := range keys {
+			value, err := exportCellValue(row[strings.TrimSpace(key)])
+			if err != nil {
+				return fmt.Errorf("encode insights sort column %q: %w", key, err)
+			}
+			values[keyIndex] = value
+		}
+		cells[index] = values
+	}
+	order := make([]int, len(rows))
+	for index := range order {
+		order[index] = index
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		left, right := cells[order[i]], cells[order[j]]
+		for index := range left {
+			if left[index] != right[index] {
+				return left[index] < right[index]
+			}
+		}
+		return false
+	})
+	sorted := make([]map[string]any, len(rows))
+	for index, rowIndex := range order {
+		sorted[index] = rows[rowIndex]
+	}
+	copy(rows, sorted)
+	return nil
+}
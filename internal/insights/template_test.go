@@ -0,0 +1,112 @@
+package insights
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeExportTemplate(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "template.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	return path
+}
+
+func TestLoadExportTemplateResolvesRelativeWindowAndFilters(t *testing.T) {
+	t.Parallel()
+
+	path := writeExportTemplate(t, `schema_version: 1
+name: weekly-performance
+level: campaign
+fields: [campaign_id, spend]
+breakdowns: [age]
+filters:
+  - field: campaign.effective_status
+    operator: IN
+    value: [ACTIVE]
+date:
+  last_days: 7
+`)
+	template, err := LoadExportTemplate(path)
+	if err != nil {
+		t.Fatalf("load template: %v", err)
+	}
+
+	options := template.RunOptions("act_123", time.Date(2026, 4, 15, 18, 0, 0, 0, time.UTC))
+	if options.AccountID != "123" || options.Since != "2026-04-08" || options.Until != "2026-04-14" || !options.Async {
+		t.Fatalf("unexpected run options %#v", options)
+	}
+	_, params, err := buildRunParams(options)
+	if err != nil {
+		t.Fatalf("build params: %v", err)
+	}
+	if params["filtering"] != `[{"field":"campaign.effective_status","operator":"IN","value":["ACTIVE"]}]` {
+		t.Fatalf("unexpected filtering param %q", params["filtering"])
+	}
+	if got := strings.Join(template.Columns(), ","); got != "campaign_id,spend,age,date_start,date_stop" {
+		t.Fatalf("unexpected columns %s", got)
+	}
+}
+
+func TestLoadExportTemplateRejectsInvalidTemplates(t *testing.T) {
+	t.Parallel()
+
+	for name, content := range map[string]string{
+		"unknown field":  "schema_version: 1\nname: x\nlevel: ad\nfields: [spend]\ndate: {preset: last_7d}\nextra: true\n",
+		"two windows":    "schema_version: 1\nname: x\nlevel: ad\nfields: [spend]\ndate: {preset: last_7d, last_days: 7}\n",
+		"bad level":      "schema_version: 1\nname: x\nlevel: page\nfields: [spend]\ndate: {preset: last_7d}\n",
+		"json format":    "schema_version: 1\nname: x\nlevel: ad\nfields: [spend]\ndate: {preset: last_7d}\nformat: json\n",
+		"missing fields": "schema_version: 1\nname: x\nlevel: ad\ndate: {preset: last_7d}\n",
+	} {
+		if _, err := LoadExportTemplate(writeExportTemplate(t, content)); !errors.Is(err, ErrInvalidExportTemplate) {
+			t.Fatalf("%s: expected invalid template error, got %v", name, err)
+		}
+	}
+}
+
+func TestSortRowsIsDeterministic(t *testing.T) {
+	t.Parallel()
+
+	rows := []map[string]any{
+		{"campaign_id": "2", "date_start": "2026-04-02"},
+		{"campaign_id": "1", "date_start": "2026-04-02"},
+		{"campaign_id": "3", "date_start": "2026-04-01"},
+	}
+	if err := SortRows(rows, []string{"date_start"}, []string{"campaign_id", "date_start"}); err != nil {
+		t.Fatalf("sort rows: %v", err)
+	}
+	if rows[0]["campaign_id"] != "3" || rows[1]["campaign_id"] != "1" || rows[2]["campaign_id"] != "2" {
+		t.Fatalf("unexpected order %#v", rows)
+	}
+}
+
+func TestWriteExportFileAppendsUsingExistingHeader(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "exports", "weekly.csv")
+	first, err := WriteExportFile(path, ExportFormatCSV, []map[string]any{{"spend": "1", "campaign_id": "c1"}}, []string{"campaign_id", "spend"}, true)
+	if err != nil || first.Appended {
+		t.Fatalf("expected new file, got %#v (%v)", first, err)
+	}
+	second, err := WriteExportFile(path, ExportFormatCSV, []map[string]any{{"campaign_id": "c2"}}, []string{"spend", "campaign_id"}, true)
+	if err != nil || !second.Appended {
+		t.Fatalf("expected append, got %#v (%v)", second, err)
+	}
+	if _, err := WriteExportFile(path, ExportFormatCSV, []map[string]any{{"clicks": "4"}}, []string{"clicks"}, true); err == nil || !strings.Contains(err.Error(), `"clicks"`) {
+		t.Fatalf("expected header mismatch error, got %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if string(content) != "campaign_id,spend\nc1,1\nc2,\n" {
+		t.Fatalf("unexpected export content %q", string(content))
+	}
+}
@@ -0,0 +1,363 @@
+package leads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	SubscriptionFieldLeadgen = "leadgen"
+
+	// fieldColumnPrefix marks flattened form answers that collide with lead metadata
+	// columns such as id or created_time.
+	fieldColumnPrefix = "field."
+)
+
+var (
+	DefaultFormFields = []string{"id", "name", "status", "locale", "leads_count", "created_time"}
+	DefaultLeadFields = []string{"id", "created_time", "form_id", "ad_id", "ad_name", "adset_id", "adset_name", "campaign_id", "campaign_name", "is_organic", "platform", "field_data"}
+)
+
+type ListFormsOptions struct {
+	PageID     string
+	Fields     []string
+	Limit      int
+	FollowNext bool
+}
+
+type ListFormsResult struct {
+	PageID      string                  `json:"page_id"`
+	RequestPath string                  `json:"request_path"`
+	Forms       []map[string]any        `json:"forms"`
+	Paging      *graph.PaginationResult `json:"paging,omitempty"`
+}
+
+type ListLeadsOptions struct {
+	FormID string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+type ListLeadsResult struct {
+	FormID      string           `json:"form_id"`
+	RequestPath string           `json:"request_path"`
+	Leads       []map[string]any `json:"leads"`
+}
+
+type SubscriptionOptions struct {
+	PageID string
+	Fields []string
+}
+
+type SubscriptionResult struct {
+	Operation   string           `json:"operation"`
+	PageID      string           `json:"page_id"`
+	RequestPath string           `json:"request_path"`
+	Fields      []string         `json:"subscribed_fields,omitempty"`
+	Success     bool             `json:"success"`
+	Apps        []map[string]any `json:"apps,omitempty"`
+}
+
+type Service struct {
+	Client *graph.Client
+}
+
+func New(client *graph.Client) *Service {
+	if client == nil {
+		client = graph.NewClient(nil, "")
+	}
+	return &Service{Client: client}
+}
+
+func (s *Service) ListForms(ctx context.Context, version string, token string, appSecret string, options ListFormsOptions) (*ListFormsResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("leads service client is required")
+	}
+	pageID, err := normalizeGraphID("page id", options.PageID)
+	if err != nil {
+		return nil, err
+	}
+	fields := normalizeFields(options.Fields, DefaultFormFields)
+
+	path := pageID + "/leadgen_forms"
+	forms := make([]map[string]any, 0)
+	pagination, err := s.Client.FetchWithPagination(ctx, graph.Request{
+		Method:      "GET",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Query:       map[string]string{"fields": strings.Join(fields, ",")},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, graph.PaginationOptions{
+		FollowNext: options.FollowNext,
+		Limit:      options.Limit,
+	}, func(item map[string]any) error {
+		forms = append(forms, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ListFormsResult{
+		PageID:      pageID,
+		RequestPath: path,
+		Forms:       forms,
+		Paging:      pagination,
+	}, nil
+}
+
+// ListLeads reads every lead submitted to a form, optionally bounded by submission
+// time. Paging is always followed so exports are complete.
+func (s *Service) ListLeads(ctx context.Context, version string, token string, appSecret string, options ListLeadsOptions) (*ListLeadsResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("leads service client is required")
+	}
+	formID, err := normalizeGraphID("form id", options.FormID)
+	if err != nil {
+		return nil, err
+	}
+	if !options.Since.IsZero() && !options.Until.IsZero() && !options.Until.After(options.Since) {
+		return nil, errors.New("lead export until must be after since")
+	}
+
+	query := map[string]string{"fields": strings.Join(DefaultLeadFields, ",")}
+	filters := make([]map[string]any, 0, 2)
+	if !options.Since.IsZero() {
+		filters = append(filters, map[string]any{"field": "time_created", "operator": "GREATER_THAN_OR_EQUAL", "value": options.Since.Unix()})
+	}
+	if !options.Until.IsZero() {
+		filters = append(filters, map[string]any{"field": "time_created", "operator": "LESS_THAN", "value": options.Until.Unix()})
+	}
+	if len(filters) > 0 {
+		encoded, err := json.Marshal(filters)
+		if err != nil {
+			return nil, fmt.Errorf("encode lead filtering: %w", err)
+		}
+		query["filtering"] = string(encoded)
+	}
+
+	path := formID + "/leads"
+	leads := make([]map[string]any, 0)
+	if _, err := s.Client.FetchWithPagination(ctx, graph.Request{
+		Method:      "GET",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Query:       query,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, graph.PaginationOptions{
+		FollowNext: true,
+		Limit:      options.Limit,
+	}, func(item map[string]any) error {
+		leads = append(leads, item)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &ListLeadsResult{
+		FormID:      formID,
+		RequestPath: path,
+		Leads:       leads,
+	}, nil
+}
+
+// Subscribe subscribes the app behind the page token to the page's webhook fields
+// (leadgen by default) so new leads are delivered in real time.
+func (s *Service) Subscribe(ctx context.Context, version string, token string, appSecret string, options SubscriptionOptions) (*SubscriptionResult, error) {
+	fields := normalizeFields(options.Fields, []string{SubscriptionFieldLeadgen})
+	result, err := s.mutateSubscription(ctx, version, token, appSecret, "POST", options.PageID, map[string]string{
+		"subscribed_fields": strings.Join(fields, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Operation = "subscribe"
+	result.Fields = fields
+	return result, nil
+}
+
+func (s *Service) Unsubscribe(ctx context.Context, version string, token string, appSecret string, options SubscriptionOptions) (*SubscriptionResult, error) {
+	result, err := s.mutateSubscription(ctx, version, token, appSecret, "DELETE", options.PageID, nil)
+	if err != nil {
+		return nil, err
+	}
+	result.Operation = "unsubscribe"
+	return result, nil
+}
+
+func (s *Service) Subscriptions(ctx context.Context, version string, token string, appSecret string, options SubscriptionOptions) (*SubscriptionResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("leads service client is required")
+	}
+	pageID, err := normalizeGraphID("page id", options.PageID)
+	if err != nil {
+		return nil, err
+	}
+	path := pageID + "/subscribed_apps"
+	apps := make([]map[string]any, 0)
+	if _, err := s.Client.FetchWithPagination(ctx, graph.Request{
+		Method:      "GET",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, graph.PaginationOptions{FollowNext: true}, func(item map[string]any) error {
+		apps = append(apps, item)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &SubscriptionResult{
+		Operation:   "status",
+		PageID:      pageID,
+		RequestPath: path,
+		Success:     true,
+		Apps:        apps,
+	}, nil
+}
+
+func (s *Service) mutateSubscription(ctx context.Context, version string, token string, appSecret string, method string, rawPageID string, form map[string]string) (*SubscriptionResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("leads service client is required")
+	}
+	pageID, err := normalizeGraphID("page id", rawPageID)
+	if err != nil {
+		return nil, err
+	}
+	path := pageID + "/subscribed_apps"
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:      method,
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Form:        form,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, _ := response.Body["success"].(bool)
+	if !success {
+		return nil, fmt.Errorf("page %s subscribed_apps %s response was not successful", pageID, strings.ToLower(method))
+	}
+	return &SubscriptionResult{
+		PageID:      pageID,
+		RequestPath: path,
+		Success:     true,
+	}, nil
+}
+
+// FlattenLeads turns each lead's field_data answers into top-level columns. Answers
+// with several values are joined with "|". The returned columns list metadata first
+// and form answers in first-seen order so exports keep the form's question order.
+func FlattenLeads(leads []map[string]any) ([]map[string]any, []string) {
+	metadata := make(map[string]struct{}, len(DefaultLeadFields))
+	columns := make([]string, 0, len(DefaultLeadFields))
+	for _, field := range DefaultLeadFields {
+		if field == "field_data" {
+			continue
+		}
+		metadata[field] = struct{}{}
+		columns = append(columns, field)
+	}
+
+	seen := map[string]struct{}{}
+	rows := make([]map[string]any, 0, len(leads))
+	for _, lead := range leads {
+		row := make(map[string]any, len(lead))
+		for key, value := range lead {
+			if key != "field_data" {
+				row[key] = value
+			}
+		}
+		entries, _ := lead["field_data"].([]any)
+		for _, entry := range entries {
+			answer, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			name := strings.TrimSpace(stringValue(answer["name"]))
+			if name == "" {
+				continue
+			}
+			if _, collides := metadata[name]; collides {
+				name = fieldColumnPrefix + name
+			}
+			values, _ := answer["values"].([]any)
+			parts := make([]string, 0, len(values))
+			for _, value := range values {
+				parts = append(parts, fmt.Sprint(value))
+			}
+			row[name] = strings.Join(parts, "|")
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				columns = append(columns, name)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, columns
+}
+
+// ParseTime accepts RFC3339 timestamps, YYYY-MM-DD dates (UTC midnight), and unix
+// seconds.
+func ParseTime(value string) (time.Time, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, trimmed); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse("2006-01-02", trimmed); err == nil {
+		return parsed, nil
+	}
+	if seconds, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected RFC3339, YYYY-MM-DD, or unix seconds", value)
+}
+
+func normalizeFields(fields []string, defaults []string) []string {
+	normalized := make([]string, 0, len(fields))
+	seen := map[string]struct{}{}
+	for _, field := range fields {
+		trimmed := strings.TrimSpace(field)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		normalized = append(normalized, trimmed)
+	}
+	if len(normalized) == 0 {
+		return append([]string(nil), defaults...)
+	}
+	return normalized
+}
+
+func normalizeGraphID(label string, value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "", fmt.Errorf("%s is required", label)
+	}
+	if strings.Contains(trimmed, "/") {
+		return "", fmt.Errorf("invalid %s %q: expected single graph id token", label, value)
+	}
+	return trimmed, nil
+}
+
+func stringValue(value any) string {
+	typed, _ := value.(string)
+	return typed
+}
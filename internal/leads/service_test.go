@@ -0,0 +1,149 @@
+package leads
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestListLeadsAppliesTimeFilteringAndFollowsPaging(t *testing.T) {
+	t.Parallel()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v25.0/form_1/leads" {
+			t.Fatalf("unexpected request path %q", r.URL.Path)
+		}
+		if r.URL.Query().Get("after") == "" {
+			var filters []map[string]any
+			if err := json.Unmarshal([]byte(r.URL.Query().Get("filtering")), &filters); err != nil {
+				t.Fatalf("decode filtering: %v", err)
+			}
+			if len(filters) != 2 || filters[0]["operator"] != "GREATER_THAN_OR_EQUAL" || filters[0]["value"] != float64(1759968000) {
+				t.Fatalf("unexpected filtering %#v", filters)
+			}
+			if filters[1]["operator"] != "LESS_THAN" || filters[1]["value"] != float64(1760054400) {
+				t.Fatalf("unexpected until filter %#v", filters[1])
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data":   []map[string]any{{"id": "lead_1"}},
+				"paging": map[string]any{"next": server.URL + "/v25.0/form_1/leads?after=cursor-1"},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{{"id": "lead_2"}}})
+	}))
+	defer server.Close()
+
+	service := New(graph.NewClient(server.Client(), server.URL))
+	result, err := service.ListLeads(context.Background(), "v25.0", "page-token", "", ListLeadsOptions{
+		FormID: "form_1",
+		Since:  time.Date(2025, 10, 9, 0, 0, 0, 0, time.UTC),
+		Until:  time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("list leads: %v", err)
+	}
+	if len(result.Leads) != 2 {
+		t.Fatalf("expected 2 leads across pages, got %d", len(result.Leads))
+	}
+}
+
+func TestFlattenLeadsMapsAnswersToColumns(t *testing.T) {
+	t.Parallel()
+
+	rows, columns := FlattenLeads([]map[string]any{
+		{
+			"id":           "lead_1",
+			"created_time": "2025-10-09T10:00:00+0000",
+			"field_data": []any{
+				map[string]any{"name": "email", "values": []any{"jane@example.com"}},
+				map[string]any{"name": "interests", "values": []any{"shoes", "hats"}},
+				map[string]any{"name": "id", "values": []any{"customer-9"}},
+			},
+		},
+		{
+			"id": "lead_2",
+			"field_data": []any{
+				map[string]any{"name": "phone_number", "values": []any{"+15550100"}},
+			},
+		},
+	})
+
+	if len(rows) != 2 {
+		t.Fatalf("unexpected row count %d", len(rows))
+	}
+	if rows[0]["email"] != "jane@example.com" || rows[0]["interests"] != "shoes|hats" {
+		t.Fatalf("unexpected flattened row %#v", rows[0])
+	}
+	if rows[0]["id"] != "lead_1" || rows[0]["field.id"] != "customer-9" {
+		t.Fatalf("expected colliding answer under field.id, got %#v", rows[0])
+	}
+	if _, ok := rows[0]["field_data"]; ok {
+		t.Fatal("field_data must not be copied into flattened rows")
+	}
+	tail := strings.Join(columns[len(columns)-4:], ",")
+	if tail != "email,interests,field.id,phone_number" {
+		t.Fatalf("unexpected answer column order %q", tail)
+	}
+	if columns[0] != "id" {
+		t.Fatalf("expected metadata columns first, got %v", columns)
+	}
+}
+
+func TestSubscribeDefaultsToLeadgenField(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v25.0/page_1/subscribed_apps" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("parse form body: %v", err)
+		}
+		if got := form.Get("subscribed_fields"); got != "leadgen" {
+			t.Fatalf("unexpected subscribed_fields %q", got)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"success": true})
+	}))
+	defer server.Close()
+
+	service := New(graph.NewClient(server.Client(), server.URL))
+	result, err := service.Subscribe(context.Background(), "v25.0", "page-token", "", SubscriptionOptions{PageID: "page_1"})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if !result.Success || result.Operation != "subscribe" || len(result.Fields) != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestParseTimeAcceptsSupportedFormats(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{"2025-10-09", "2025-10-09T00:00:00Z", "1759968000"} {
+		parsed, err := ParseTime(value)
+		if err != nil {
+			t.Fatalf("parse %q: %v", value, err)
+		}
+		if parsed.Unix() != 1759968000 {
+			t.Fatalf("unexpected unix time for %q: %d", value, parsed.Unix())
+		}
+	}
+	if _, err := ParseTime("yesterday"); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
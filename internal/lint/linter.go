@@ -24,14 +24,16 @@ type Result struct {
 }
 
 type Linter struct {
-	pack *schema.Pack
+	index *schema.Index
 }
 
+// New returns a linter over pack. Linters for the same pack share one index,
+// so building many of them in one invocation is cheap.
 func New(pack *schema.Pack) (*Linter, error) {
 	if pack == nil {
 		return nil, errors.New("schema pack is required")
 	}
-	return &Linter{pack: pack}, nil
+	return &Linter{index: schema.IndexFor(pack)}, nil
 }
 
 func LoadRequestSpec(path string) (*RequestSpec, error) {
@@ -73,18 +75,18 @@ func (l *Linter) Lint(spec *RequestSpec, strict bool) Result {
 	endpoint := detectEndpoint(spec.Path, method)
 	entity := detectEntity(spec.Path, endpoint)
 
-	allowedParams := toSet(l.pack.EndpointParams[endpoint])
-	deprecatedParams := toSet(l.pack.DeprecatedParams[endpoint])
+	allowedParams := l.index.EndpointParams[endpoint]
+	deprecatedParams := l.index.DeprecatedParams[endpoint]
 	if strict && isMutationMethod(method) && endpoint != "generic" && len(allowedParams) == 0 && len(deprecatedParams) == 0 {
 		result.Errors = append(result.Errors, fmt.Sprintf("schema pack has no mutation param definitions for endpoint %q", endpoint))
 	}
 	for key := range spec.Params {
-		if _, deprecated := deprecatedParams[key]; deprecated {
+		if deprecatedParams.Has(key) {
 			result.Errors = append(result.Errors, fmt.Sprintf("deprecated param %q is not allowed for endpoint %q", key, endpoint))
 			continue
 		}
 		if len(allowedParams) > 0 {
-			if _, ok := allowedParams[key]; !ok {
+			if !allowedParams.Has(key) {
 				message := fmt.Sprintf("unknown param %q for endpoint %q", key, endpoint)
 				if strict {
 					result.Errors = append(result.Errors, message)
@@ -95,14 +97,14 @@ func (l *Linter) Lint(spec *RequestSpec, strict bool) Result {
 		}
 	}
 
-	allowedFields := toSet(l.pack.Entities[entity])
+	allowedFields := l.index.Entities[entity]
 	for _, field := range spec.Fields {
 		field = strings.TrimSpace(field)
 		if field == "" {
 			continue
 		}
 		if len(allowedFields) > 0 {
-			if _, ok := allowedFields[field]; !ok {
+			if !allowedFields.Has(field) {
 				message := fmt.Sprintf("unknown field %q for entity %q", field, entity)
 				if strict {
 					result.Errors = append(result.Errors, message)
@@ -193,15 +195,3 @@ func isMutationMethod(method string) bool {
 		return false
 	}
 }
-
-func toSet(values []string) map[string]struct{} {
-	set := map[string]struct{}{}
-	for _, value := range values {
-		value = strings.TrimSpace(value)
-		if value == "" {
-			continue
-		}
-		set[value] = struct{}{}
-	}
-	return set
-}
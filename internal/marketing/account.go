@@ -0,0 +1,370 @@
+package marketing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	AccountStatusActive = 1
+
+	spendCapActionReset = "reset"
+)
+
+var DefaultAccountReadFields = []string{
+	"id",
+	"account_id",
+	"name",
+	"account_status",
+	"currency",
+	"timezone_name",
+	"timezone_offset_hours_utc",
+	"spend_cap",
+	"amount_spent",
+}
+
+var DefaultAccountGetFields = []string{
+	"id",
+	"account_id",
+	"name",
+	"account_status",
+	"disable_reason",
+	"currency",
+	"timezone_name",
+	"timezone_offset_hours_utc",
+	"spend_cap",
+	"amount_spent",
+	"balance",
+	"business",
+	"funding_source",
+	"funding_source_details",
+}
+
+var accountStatusNames = map[int]string{
+	1:   "ACTIVE",
+	2:   "DISABLED",
+	3:   "UNSETTLED",
+	7:   "PENDING_RISK_REVIEW",
+	8:   "PENDING_SETTLEMENT",
+	9:   "IN_GRACE_PERIOD",
+	100: "PENDING_CLOSURE",
+	101: "CLOSED",
+	201: "ANY_ACTIVE",
+	202: "ANY_CLOSED",
+}
+
+var fundingSourceTypeNames = map[int]string{
+	0:  "UNSET",
+	1:  "CREDIT_CARD",
+	2:  "FACEBOOK_WALLET",
+	3:  "FACEBOOK_PAID_CREDIT",
+	4:  "FACEBOOK_EXTENDED_CREDIT",
+	5:  "ORDER",
+	6:  "INVOICE",
+	7:  "FACEBOOK_TOKEN",
+	8:  "EXTERNAL_FUNDING",
+	9:  "FEE",
+	10: "FX",
+	11: "DISCOUNT",
+	12: "PAYPAL_TOKEN",
+	13: "PAYPAL_BILLING_AGREEMENT",
+	14: "FS_NULL",
+	15: "EXTERNAL_DEPOSIT",
+	16: "TAX",
+	17: "DIRECT_DEBIT",
+	18: "DUMMY",
+	19: "ALTPAY",
+	20: "STORED_BALANCE",
+}
+
+var spendCapAmountPattern = regexp.MustCompile(`^\d+(\.\d{1,2})?$`)
+
+type AccountListInput struct {
+	Fields     []string
+	Name       string
+	ActiveOnly bool
+	Limit      int
+	FollowNext bool
+}
+
+type AccountListResult struct {
+	Operation   string                  `json:"operation"`
+	RequestPath string                  `json:"request_path"`
+	Accounts    []map[string]any        `json:"accounts"`
+	Paging      *graph.PaginationResult `json:"paging,omitempty"`
+}
+
+type AccountGetInput struct {
+	AccountID string
+	Fields    []string
+}
+
+type AccountFundingResult struct {
+	AccountID       string         `json:"account_id"`
+	RequestPath     string         `json:"request_path"`
+	FundingSourceID string         `json:"funding_source_id,omitempty"`
+	Type            string         `json:"type,omitempty"`
+	DisplayString   string         `json:"display_string,omitempty"`
+	Details         map[string]any `json:"details,omitempty"`
+}
+
+type AccountSpendCapInput struct {
+	AccountID string
+	Amount    string
+	Remove    bool
+	Reset     bool
+}
+
+type AccountMutationResult struct {
+	Operation   string            `json:"operation"`
+	AccountID   string            `json:"account_id"`
+	RequestPath string            `json:"request_path"`
+	Payload     map[string]string `json:"payload"`
+	Response    map[string]any    `json:"response"`
+}
+
+type AccountService struct {
+	Client *graph.Client
+}
+
+func NewAccountService(client *graph.Client) *AccountService {
+	if client == nil {
+		client = graph.NewClient(nil, "")
+	}
+	return &AccountService{Client: client}
+}
+
+// List reads ad accounts accessible to the token. Rows gain account_status_name
+// and a display label combining name, id, currency, and timezone.
+func (s *AccountService) List(ctx context.Context, version string, token string, appSecret string, input AccountListInput) (*AccountListResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("account service client is required")
+	}
+	if input.Limit < 0 {
+		return nil, errors.New("account list limit must be >= 0")
+	}
+	fields, err := normalizeEntityReadFields("account", input.Fields, DefaultAccountReadFields)
+	if err != nil {
+		return nil, err
+	}
+	nameFilter := strings.ToLower(strings.TrimSpace(input.Name))
+
+	fetchFields := mergeEntityReadFields(fields, "account_id", "name", "account_status", "currency", "timezone_name")
+	path := "me/adaccounts"
+	rows := make([]map[string]any, 0)
+	pagination, err := s.Client.FetchWithPagination(ctx, graph.Request{
+		Method:      "GET",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Query:       map[string]string{"fields": strings.Join(fetchFields, ",")},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, graph.PaginationOptions{
+		FollowNext: input.FollowNext,
+	}, func(item map[string]any) error {
+		if nameFilter != "" && !strings.Contains(strings.ToLower(entityItemStringValue(item, "name")), nameFilter) {
+			return nil
+		}
+		status := graphResponseInt(item["account_status"])
+		if input.ActiveOnly && status != AccountStatusActive {
+			return nil
+		}
+		if input.Limit > 0 && len(rows) >= input.Limit {
+			return nil
+		}
+		row := projectEntityReadFields(item, fields)
+		decorateAccount(row, item)
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountListResult{
+		Operation:   "list",
+		RequestPath: path,
+		Accounts:    rows,
+		Paging:      pagination,
+	}, nil
+}
+
+func (s *AccountService) Get(ctx context.Context, version string, token string, appSecret string, input AccountGetInput) (map[string]any, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("account service client is required")
+	}
+	accountID, err := normalizeAdAccountID(input.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := normalizeEntityReadFields("account", input.Fields, DefaultAccountGetFields)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:      "GET",
+		Path:        "act_" + accountID,
+		Version:     strings.TrimSpace(version),
+		Query:       map[string]string{"fields": strings.Join(fields, ",")},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+	account := make(map[string]any, len(response.Body)+2)
+	for key, value := range response.Body {
+		account[key] = value
+	}
+	decorateAccount(account, response.Body)
+	return account, nil
+}
+
+func (s *AccountService) FundingSource(ctx context.Context, version string, token string, appSecret string, rawAccountID string) (*AccountFundingResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("account service client is required")
+	}
+	accountID, err := normalizeAdAccountID(rawAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	path := "act_" + accountID
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:      "GET",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Query:       map[string]string{"fields": "funding_source,funding_source_details"},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AccountFundingResult{
+		AccountID:       "act_" + accountID,
+		RequestPath:     path,
+		FundingSourceID: entityItemStringValue(response.Body, "funding_source"),
+	}
+	if details, ok := response.Body["funding_source_details"].(map[string]any); ok {
+		result.Details = details
+		result.DisplayString = entityItemStringValue(details, "display_string")
+		if result.FundingSourceID == "" {
+			result.FundingSourceID = entityItemStringValue(details, "id")
+		}
+		if rawType, exists := details["type"]; exists {
+			typeCode := graphResponseInt(rawType)
+			if name, known := fundingSourceTypeNames[typeCode]; known {
+				result.Type = name
+			} else {
+				result.Type = strconv.Itoa(typeCode)
+			}
+		}
+	}
+	return result, nil
+}
+
+// SetSpendCap updates the account spending limit. Amounts are in the account
+// currency; Remove clears the cap (spend_cap=0) and Reset restarts the amount spent
+// against the current cap.
+func (s *AccountService) SetSpendCap(ctx context.Context, version string, token string, appSecret string, input AccountSpendCapInput) (*AccountMutationResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("account service client is required")
+	}
+	accountID, err := normalizeAdAccountID(input.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	form, err := BuildSpendCapPayload(input)
+	if err != nil {
+		return nil, err
+	}
+
+	path := "act_" + accountID
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:      "POST",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Form:        form,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if success, ok := response.Body["success"].(bool); ok && !success {
+		return nil, fmt.Errorf("spend cap update for %s was not successful", path)
+	}
+	return &AccountMutationResult{
+		Operation:   "set_spend_cap",
+		AccountID:   path,
+		RequestPath: path,
+		Payload:     form,
+		Response:    response.Body,
+	}, nil
+}
+
+func BuildSpendCapPayload(input AccountSpendCapInput) (map[string]string, error) {
+	amount := strings.TrimSpace(input.Amount)
+	modes := 0
+	for _, set := range []bool{amount != "", input.Remove, input.Reset} {
+		if set {
+			modes++
+		}
+	}
+	if modes != 1 {
+		return nil, errors.New("exactly one of spend cap amount, remove, or reset is required")
+	}
+
+	switch {
+	case input.Remove:
+		return map[string]string{"spend_cap": "0"}, nil
+	case input.Reset:
+		return map[string]string{"spend_cap_action": spendCapActionReset}, nil
+	}
+	if !spendCapAmountPattern.MatchString(amount) {
+		return nil, fmt.Errorf("invalid spend cap amount %q: expected a positive decimal with at most two fraction digits", input.Amount)
+	}
+	if parsed, _ := strconv.ParseFloat(amount, 64); parsed <= 0 {
+		return nil, errors.New("spend cap amount must be > 0; use remove to clear the cap")
+	}
+	return map[string]string{"spend_cap": amount}, nil
+}
+
+func decorateAccount(row map[string]any, source map[string]any) {
+	if _, exists := source["account_status"]; exists {
+		status := graphResponseInt(source["account_status"])
+		if name, known := accountStatusNames[status]; known {
+			row["account_status_name"] = name
+		}
+	}
+
+	id := entityItemStringValue(source, "id")
+	if id == "" {
+		if accountID := entityItemStringValue(source, "account_id"); accountID != "" {
+			id = "act_" + accountID
+		}
+	}
+	parts := make([]string, 0, 3)
+	if name := entityItemStringValue(source, "name"); name != "" {
+		parts = append(parts, fmt.Sprintf("%s (%s)", name, id))
+	} else if id != "" {
+		parts = append(parts, id)
+	}
+	for _, key := range []string{"currency", "timezone_name"} {
+		if value := entityItemStringValue(source, key); value != "" {
+			parts = append(parts, value)
+		}
+	}
+	if len(parts) > 0 {
+		row["display"] = strings.Join(parts, " · ")
+	}
+}
@@ -0,0 +1,188 @@
+package marketing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+// AccountContextFields are fetched for every account context lookup, so one
+// request answers currency, timezone and naming questions alike.
+var AccountContextFields = []string{"id", "account_id", "name", "currency", "timezone_name", "account_status"}
+
+// AccountContextCache shares ad account metadata across the steps of one
+// invocation. Workflow steps, fleet runs and retries execute in-process under
+// the invocation's context, so a cache carried there lets each account be
+// fetched once however many steps format money or check budget floors.
+// Concurrent lookups of the same account wait for a single request; failed
+// lookups are not kept, so a later step retries them.
+//
+// A nil cache is valid and fetches on every lookup.
+type AccountContextCache struct {
+	mu      sync.Mutex
+	entries map[accountContextKey]*accountContextEntry
+}
+
+type accountContextKey struct {
+	version   string
+	tokenHash string
+	accountID string
+}
+
+type accountContextEntry struct {
+	done    chan struct{}
+	account map[string]any
+	err     error
+}
+
+type accountContextCacheKey struct{}
+
+func NewAccountContextCache() *AccountContextCache {
+	return &AccountContextCache{entries: map[accountContextKey]*accountContextEntry{}}
+}
+
+// WithAccountContextCache returns ctx carrying cache.
+func WithAccountContextCache(ctx context.Context, cache *AccountContextCache) context.Context {
+	return context.WithValue(ctx, accountContextCacheKey{}, cache)
+}
+
+// AccountContextCacheFrom returns the cache carried by ctx, or nil.
+func AccountContextCacheFrom(ctx context.Context) *AccountContextCache {
+	if ctx == nil {
+		return nil
+	}
+	cache, _ := ctx.Value(accountContextCacheKey{}).(*AccountContextCache)
+	return cache
+}
+
+// Prefetch starts fetching the account in the background so a later Get finds
+// it ready. It is a no-op on a nil cache or when the account is already known.
+func (c *AccountContextCache) Prefetch(ctx context.Context, client *graph.Client, version string, token string, appSecret string, accountID string) {
+	if c == nil || client == nil {
+		return
+	}
+	normalizedAccountID, err := normalizeAdAccountID(accountID)
+	if err != nil {
+		return
+	}
+	entry, owner := c.entry(version, token, normalizedAccountID)
+	if !owner {
+		return
+	}
+	go c.fill(ctx, entry, client, version, token, appSecret, normalizedAccountID)
+}
+
+// Get returns the account's context fields. The returned map is shared;
+// callers must not modify it.
+func (c *AccountContextCache) Get(ctx context.Context, client *graph.Client, version string, token string, appSecret string, accountID string) (map[string]any, error) {
+	if client == nil {
+		return nil, errors.New("account context client is required")
+	}
+	normalizedAccountID, err := normalizeAdAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return fetchAccountContext(ctx, client, version, token, appSecret, normalizedAccountID)
+	}
+
+	entry, owner := c.entry(version, token, normalizedAccountID)
+	if owner {
+		c.fill(ctx, entry, client, version, token, appSecret, normalizedAccountID)
+	}
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return entry.account, entry.err
+}
+
+// Currency returns the account currency, upper-cased. Without a cache only the
+// currency field is requested, since nothing else would be kept.
+func (c *AccountContextCache) Currency(ctx context.Context, client *graph.Client, version string, token string, appSecret string, accountID string) (string, error) {
+	if c == nil {
+		if client == nil {
+			return "", errors.New("account context client is required")
+		}
+		account, err := NewAccountService(client).Get(ctx, version, token, appSecret, AccountGetInput{
+			AccountID: accountID,
+			Fields:    []string{"currency"},
+		})
+		if err != nil {
+			return "", err
+		}
+		return accountCurrencyFromBody(account)
+	}
+	account, err := c.Get(ctx, client, version, token, appSecret, accountID)
+	if err != nil {
+		return "", err
+	}
+	return accountCurrencyFromBody(account)
+}
+
+// entry returns the entry for the key, creating it when missing. owner reports
+// whether the caller created it and must fill it.
+func (c *AccountContextCache) entry(version string, token string, accountID string) (*accountContextEntry, bool) {
+	sum := sha256.Sum256([]byte(token))
+	key := accountContextKey{
+		version:   strings.TrimSpace(version),
+		tokenHash: hex.EncodeToString(sum[:]),
+		accountID: accountID,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[accountContextKey]*accountContextEntry{}
+	}
+	if entry, ok := c.entries[key]; ok {
+		return entry, false
+	}
+	entry := &accountContextEntry{done: make(chan struct{})}
+	c.entries[key] = entry
+	return entry, true
+}
+
+func (c *AccountContextCache) fill(ctx context.Context, entry *accountContextEntry, client *graph.Client, version string, token string, appSecret string, accountID string) {
+	entry.account, entry.err = fetchAccountContext(ctx, client, version, token, appSecret, accountID)
+	if entry.err != nil {
+		c.mu.Lock()
+		for key, existing := range c.entries {
+			if existing == entry {
+				delete(c.entries, key)
+			}
+		}
+		c.mu.Unlock()
+	}
+	close(entry.done)
+}
+
+func fetchAccountContext(ctx context.Context, client *graph.Client, version string, token string, appSecret string, accountID string) (map[string]any, error) {
+	return NewAccountService(client).Get(ctx, version, token, appSecret, AccountGetInput{
+		AccountID: accountID,
+		Fields:    AccountContextFields,
+	})
+}
+
+func accountCurrencyFromBody(body map[string]any) (string, error) {
+	currencyRaw, exists := body["currency"]
+	if !exists {
+		return "", errors.New("ad account currency lookup response did not include currency")
+	}
+	currency, ok := currencyRaw.(string)
+	if !ok {
+		return "", fmt.Errorf("ad account currency lookup response field currency has unsupported type %T", currencyRaw)
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return "", errors.New("ad account currency lookup response included empty currency")
+	}
+	return currency, nil
+}
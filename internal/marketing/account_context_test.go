@@ -0,0 +1,110 @@
+package marketing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestAccountContextCacheFetchesEachAccountOnce(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/v25.0/act_1234" {
+			t.Errorf("unexpected request path %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("fields"); got != "id,account_id,name,currency,timezone_name,account_status" {
+			t.Errorf("unexpected fields %q", got)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "act_1234", "account_id": "1234", "currency": "usd", "timezone_name": "UTC"})
+	}))
+	defer server.Close()
+
+	client := graph.NewClient(server.Client(), server.URL)
+	cache := NewAccountContextCache()
+	ctx := WithAccountContextCache(context.Background(), cache)
+	cache.Prefetch(ctx, client, "v25.0", "token-1", "", "1234")
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			currency, err := AccountContextCacheFrom(ctx).Currency(ctx, client, "v25.0", "token-1", "", "act_1234")
+			if err != nil {
+				t.Errorf("currency: %v", err)
+				return
+			}
+			if currency != "USD" {
+				t.Errorf("expected USD, got %q", currency)
+			}
+		}()
+	}
+	wg.Wait()
+
+	currency, err := (&AdSetService{Client: client}).ResolveAccountCurrency(ctx, "v25.0", "token-1", "", "1234")
+	if err != nil || currency != "USD" {
+		t.Fatalf("resolve account currency: %q %v", currency, err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected one account request, got %d", got)
+	}
+
+	if _, err := cache.Get(ctx, client, "v25.0", "token-2", "", "1234"); err != nil {
+		t.Fatalf("get with another token: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected another token to fetch separately, got %d requests", got)
+	}
+}
+
+func TestAccountContextCacheDoesNotKeepFailures(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"message": "temporary", "type": "OAuthException", "code": 2}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "act_1234", "currency": "EUR"})
+	}))
+	defer server.Close()
+
+	client := graph.NewClient(server.Client(), server.URL)
+	cache := NewAccountContextCache()
+	if _, err := cache.Currency(context.Background(), client, "v25.0", "token-1", "", "1234"); err == nil {
+		t.Fatal("expected first lookup to fail")
+	}
+	currency, err := cache.Currency(context.Background(), client, "v25.0", "token-1", "", "1234")
+	if err != nil || currency != "EUR" {
+		t.Fatalf("expected retry to succeed with EUR, got %q %v", currency, err)
+	}
+}
+
+func TestNilAccountContextCacheRequestsOnlyCurrency(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("fields"); got != "currency" {
+			t.Errorf("unexpected fields %q", got)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"currency": "gbp"})
+	}))
+	defer server.Close()
+
+	var cache *AccountContextCache
+	currency, err := cache.Currency(context.Background(), graph.NewClient(server.Client(), server.URL), "v25.0", "token-1", "", "1234")
+	if err != nil || currency != "GBP" {
+		t.Fatalf("expected GBP, got %q %v", currency, err)
+	}
+}
@@ -0,0 +1,319 @@
+package marketing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+)
+
+const (
+	AccountCacheSchemaVersion = 1
+	DefaultAccountCacheTTL    = time.Hour
+)
+
+var (
+	ErrAccountCachePathRequired = errors.New("account cache path is required")
+
+	accountIDReferencePattern = regexp.MustCompile(`^(?i:act_)?[0-9]+$`)
+)
+
+type AccountCache struct {
+	SchemaVersion int                          `json:"schema_version"`
+	Profiles      map[string]AccountCacheEntry `json:"profiles"`
+}
+
+type AccountCacheEntry struct {
+	FetchedAt time.Time       `json:"fetched_at"`
+	Accounts  []CachedAccount `json:"accounts"`
+}
+
+type CachedAccount struct {
+	AccountID string `json:"account_id"`
+	Name      string `json:"name"`
+}
+
+type AccountResolution struct {
+	Reference string `json:"reference"`
+	AccountID string `json:"account_id"`
+	Name      string `json:"name,omitempty"`
+	FromCache bool   `json:"from_cache"`
+}
+
+// AccountAmbiguityError reports a name that did not resolve to exactly one
+// account. Candidates lists the accounts the caller can pick from with --account-id.
+type AccountAmbiguityError struct {
+	Reference  string
+	Candidates []CachedAccount
+}
+
+func (e *AccountAmbiguityError) Error() string {
+	if len(e.Candidates) == 0 {
+		return fmt.Sprintf("no accessible ad account matches %q; pass --account-id explicitly", e.Reference)
+	}
+	labels := make([]string, 0, len(e.Candidates))
+	for _, candidate := range e.Candidates {
+		labels = append(labels, fmt.Sprintf("act_%s (%s)", candidate.AccountID, candidate.Name))
+	}
+	return fmt.Sprintf("ad account %q is ambiguous: %s; pass --account-id explicitly", e.Reference, strings.Join(labels, ", "))
+}
+
+type AccountResolver struct {
+	Service   *AccountService
+	CachePath string
+	TTL       time.Duration
+	Now       func() time.Time
+}
+
+func DefaultAccountCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "cache", "ad-accounts.json"), nil
+}
+
+// IsAccountIDReference reports whether ref is already an ad account id (with or
+// without act_) and needs no lookup.
+func IsAccountIDReference(ref string) bool {
+	return accountIDReferencePattern.MatchString(strings.TrimSpace(ref))
+}
+
+// Resolve maps an account name to its id using the accounts accessible to the
+// profile token. Names match case-insensitively and must match exactly one account;
+// a cached list younger than TTL is used first and refreshed once on a miss.
+func (r *AccountResolver) Resolve(ctx context.Context, version string, token string, appSecret string, profile string, ref string) (*AccountResolution, error) {
+	reference := strings.TrimSpace(ref)
+	if reference == "" {
+		return nil, errors.New("account reference is required")
+	}
+	if IsAccountIDReference(reference) {
+		accountID, err := normalizeAdAccountID(reference)
+		if err != nil {
+			return nil, err
+		}
+		return &AccountResolution{Reference: reference, AccountID: accountID}, nil
+	}
+	if r == nil || r.Service == nil {
+		return nil, errors.New("account resolver service is required")
+	}
+	if r.TTL < 0 {
+		return nil, fmt.Errorf("account cache ttl must be >= 0, got %s", r.TTL)
+	}
+	now := time.Now().UTC()
+	if r.Now != nil {
+		now = r.Now().UTC()
+	}
+	profile = strings.TrimSpace(profile)
+
+	if r.TTL > 0 && strings.TrimSpace(r.CachePath) != "" {
+		cache, err := LoadAccountCache(r.CachePath)
+		switch {
+		case err == nil:
+			if entry, ok := cache.Profiles[profile]; ok && now.Sub(entry.FetchedAt) < r.TTL {
+				if match, err := matchAccountReference(reference, entry.Accounts); err == nil {
+					return &AccountResolution{Reference: reference, AccountID: match.AccountID, Name: match.Name, FromCache: true}, nil
+				}
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, err
+		}
+	}
+
+	accounts, err := r.refresh(ctx, version, token, appSecret, profile, now)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := matchAccountReference(reference, accounts)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountResolution{Reference: reference, AccountID: match.AccountID, Name: match.Name}, nil
+}
+
+// Accounts returns the accounts accessible to the profile token, from the cache
+// when it is younger than TTL and from the Graph API otherwise.
+func (r *AccountResolver) Accounts(ctx context.Context, version string, token string, appSecret string, profile string) ([]CachedAccount, error) {
+	if r == nil || r.Service == nil {
+		return nil, errors.New("account resolver service is required")
+	}
+	now := time.Now().UTC()
+	if r.Now != nil {
+		now = r.Now().UTC()
+	}
+	profile = strings.TrimSpace(profile)
+	if r.TTL > 0 && strings.TrimSpace(r.CachePath) != "" {
+		cache, err := LoadAccountCache(r.CachePath)
+		switch {
+		case err == nil:
+			if entry, ok := cache.Profiles[profile]; ok && now.Sub(entry.FetchedAt) < r.TTL {
+				return entry.Accounts, nil
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, err
+		}
+	}
+	return r.refresh(ctx, version, token, appSecret, profile, now)
+}
+
+// refresh lists every accessible account and stores the list for profile.
+func (r *AccountResolver) refresh(ctx context.Context, version string, token string, appSecret string, profile string, now time.Time) ([]CachedAccount, error) {
+	listed, err := r.Service.List(ctx, version, token, appSecret, AccountListInput{
+		Fields:     []string{"account_id", "name"},
+		FollowNext: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]CachedAccount, 0, len(listed.Accounts))
+	for _, item := range listed.Accounts {
+		accountID := entityItemStringValue(item, "account_id")
+		if accountID == "" {
+			continue
+		}
+		accounts = append(accounts, CachedAccount{AccountID: accountID, Name: entityItemStringValue(item, "name")})
+	}
+	if strings.TrimSpace(r.CachePath) != "" {
+		if err := storeAccountCacheEntry(r.CachePath, profile, AccountCacheEntry{FetchedAt: now, Accounts: accounts}); err != nil {
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+// matchAccountReference prefers exact case-insensitive name matches and falls back
+// to substring matches only to list candidates; it never picks a partial match.
+func matchAccountReference(reference string, accounts []CachedAccount) (CachedAccount, error) {
+	needle := strings.ToLower(reference)
+	exact := make([]CachedAccount, 0, 1)
+	partial := make([]CachedAccount, 0)
+	for _, account := range accounts {
+		name := strings.ToLower(strings.TrimSpace(account.Name))
+		switch {
+		case name == needle:
+			exact = append(exact, account)
+		case strings.Contains(name, needle):
+			partial = append(partial, account)
+		}
+	}
+	if len(exact) == 1 {
+		return exact[0], nil
+	}
+	candidates := exact
+	if len(candidates) == 0 {
+		candidates = partial
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].AccountID < candidates[j].AccountID
+	})
+	return CachedAccount{}, &AccountAmbiguityError{Reference: reference, Candidates: candidates}
+}
+
+func LoadAccountCache(path string) (AccountCache, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return AccountCache{}, ErrAccountCachePathRequired
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return AccountCache{}, fmt.Errorf("read account cache %s: %w", path, err)
+	}
+
+	var cache AccountCache
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cache); err != nil {
+		return AccountCache{}, fmt.Errorf("decode account cache %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return AccountCache{}, fmt.Errorf("decode account cache %s: multiple JSON values", path)
+		}
+		return AccountCache{}, fmt.Errorf("decode account cache %s: %w", path, err)
+	}
+	if cache.SchemaVersion != AccountCacheSchemaVersion {
+		return AccountCache{}, fmt.Errorf(
+			"unsupported account cache schema_version=%d in %s (expected %d)",
+			cache.SchemaVersion,
+			path,
+			AccountCacheSchemaVersion,
+		)
+	}
+	if cache.Profiles == nil {
+		cache.Profiles = map[string]AccountCacheEntry{}
+	}
+	return cache, nil
+}
+
+func SaveAccountCache(path string, cache AccountCache) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return ErrAccountCachePathRequired
+	}
+	cache.SchemaVersion = AccountCacheSchemaVersion
+	if cache.Profiles == nil {
+		cache.Profiles = map[string]AccountCacheEntry{}
+	}
+
+	lock, err := filelock.Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create account cache directory for %s: %w", path, err)
+	}
+	payload, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode account cache: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".ad-accounts-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp account cache file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp account cache file: %w", err)
+	}
+	if err := tmpFile.Chmod(0o600); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("chmod temp account cache file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp account cache file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("replace account cache %s: %w", path, err)
+	}
+	return nil
+}
+
+func storeAccountCacheEntry(path string, profile string, entry AccountCacheEntry) error {
+	cache, err := LoadAccountCache(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		cache = AccountCache{Profiles: map[string]AccountCacheEntry{}}
+	}
+	cache.Profiles[profile] = entry
+	return SaveAccountCache(path, cache)
+}
@@ -0,0 +1,117 @@
+package marketing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func newAccountResolverTestServer(t *testing.T, calls *int32, accounts []map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		if r.URL.Path != "/v25.0/me/adaccounts" {
+			t.Fatalf("unexpected request path %q", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": accounts})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAccountResolverMatchesExactNameAndCaches(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := newAccountResolverTestServer(t, &calls, []map[string]any{
+		{"account_id": "1", "name": "Acme Inc"},
+		{"account_id": "2", "name": "Acme Inc EU"},
+	})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	resolver := &AccountResolver{
+		Service:   NewAccountService(graph.NewClient(server.Client(), server.URL)),
+		CachePath: filepath.Join(t.TempDir(), "ad-accounts.json"),
+		TTL:       time.Hour,
+		Now:       func() time.Time { return now },
+	}
+
+	first, err := resolver.Resolve(context.Background(), "v25.0", "token", "", "prod", "acme inc")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if first.AccountID != "1" || first.FromCache {
+		t.Fatalf("unexpected resolution %#v", first)
+	}
+
+	second, err := resolver.Resolve(context.Background(), "v25.0", "token", "", "prod", "Acme Inc EU")
+	if err != nil {
+		t.Fatalf("resolve cached: %v", err)
+	}
+	if second.AccountID != "2" || !second.FromCache {
+		t.Fatalf("unexpected cached resolution %#v", second)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected one account lookup, got %d", got)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := resolver.Resolve(context.Background(), "v25.0", "token", "", "prod", "Acme Inc"); err != nil {
+		t.Fatalf("resolve after ttl: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected refresh after ttl, got %d lookups", got)
+	}
+}
+
+func TestAccountResolverFailsOnAmbiguousOrPartialNames(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := newAccountResolverTestServer(t, &calls, []map[string]any{
+		{"account_id": "1", "name": "Acme Inc"},
+		{"account_id": "3", "name": "acme inc"},
+		{"account_id": "2", "name": "Acme Inc EU"},
+	})
+	resolver := &AccountResolver{
+		Service: NewAccountService(graph.NewClient(server.Client(), server.URL)),
+	}
+
+	_, err := resolver.Resolve(context.Background(), "v25.0", "token", "", "prod", "Acme Inc")
+	var ambiguity *AccountAmbiguityError
+	if !errors.As(err, &ambiguity) {
+		t.Fatalf("expected ambiguity error, got %v", err)
+	}
+	if len(ambiguity.Candidates) != 2 || ambiguity.Candidates[0].AccountID != "1" || ambiguity.Candidates[1].AccountID != "3" {
+		t.Fatalf("unexpected candidates %#v", ambiguity.Candidates)
+	}
+	if !strings.Contains(err.Error(), "pass --account-id explicitly") {
+		t.Fatalf("unexpected error text %q", err.Error())
+	}
+
+	_, err = resolver.Resolve(context.Background(), "v25.0", "token", "", "prod", "EU")
+	if !errors.As(err, &ambiguity) || len(ambiguity.Candidates) != 1 || ambiguity.Candidates[0].AccountID != "2" {
+		t.Fatalf("expected partial match to be listed, not picked: %v", err)
+	}
+}
+
+func TestAccountResolverPassesThroughIDs(t *testing.T) {
+	t.Parallel()
+
+	resolver := &AccountResolver{}
+	resolution, err := resolver.Resolve(context.Background(), "v25.0", "token", "", "prod", "act_123")
+	if err != nil {
+		t.Fatalf("resolve id: %v", err)
+	}
+	if resolution.AccountID != "123" {
+		t.Fatalf("unexpected resolution %#v", resolution)
+	}
+}
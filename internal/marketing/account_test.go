@@ -0,0 +1,149 @@
+package marketing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestAccountListFiltersAndDecoratesAccounts(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v25.0/me/adaccounts" {
+			t.Fatalf("unexpected request path %q", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{
+				{"id": "act_1", "account_id": "1", "name": "Acme Inc", "account_status": 1, "currency": "USD", "timezone_name": "America/New_York"},
+				{"id": "act_2", "account_id": "2", "name": "Acme Legacy", "account_status": 101, "currency": "EUR", "timezone_name": "Europe/Berlin"},
+				{"id": "act_3", "account_id": "3", "name": "Other", "account_status": 1, "currency": "USD", "timezone_name": "UTC"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	service := NewAccountService(graph.NewClient(server.Client(), server.URL))
+	result, err := service.List(context.Background(), "v25.0", "token-1", "", AccountListInput{
+		Fields: []string{"id", "name"},
+		Name:   "acme",
+	})
+	if err != nil {
+		t.Fatalf("list accounts: %v", err)
+	}
+	if len(result.Accounts) != 2 {
+		t.Fatalf("expected 2 acme accounts, got %#v", result.Accounts)
+	}
+	first := result.Accounts[0]
+	if first["display"] != "Acme Inc (act_1) · USD · America/New_York" || first["account_status_name"] != "ACTIVE" {
+		t.Fatalf("unexpected decoration %#v", first)
+	}
+	if _, exists := first["currency"]; exists {
+		t.Fatalf("expected projection to drop currency, got %#v", first)
+	}
+	if result.Accounts[1]["account_status_name"] != "CLOSED" {
+		t.Fatalf("unexpected status name %#v", result.Accounts[1])
+	}
+
+	activeOnly, err := service.List(context.Background(), "v25.0", "token-1", "", AccountListInput{Name: "acme", ActiveOnly: true})
+	if err != nil {
+		t.Fatalf("list active accounts: %v", err)
+	}
+	if len(activeOnly.Accounts) != 1 || activeOnly.Accounts[0]["id"] != "act_1" {
+		t.Fatalf("unexpected active accounts %#v", activeOnly.Accounts)
+	}
+}
+
+func TestAccountFundingSourceMapsType(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v25.0/act_42" {
+			t.Fatalf("unexpected request path %q", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":             "act_42",
+			"funding_source": "fs_1",
+			"funding_source_details": map[string]any{
+				"id":             "fs_1",
+				"display_string": "Visa *1234",
+				"type":           1,
+			},
+		})
+	}))
+	defer server.Close()
+
+	service := NewAccountService(graph.NewClient(server.Client(), server.URL))
+	result, err := service.FundingSource(context.Background(), "v25.0", "token-1", "", "42")
+	if err != nil {
+		t.Fatalf("funding source: %v", err)
+	}
+	if result.FundingSourceID != "fs_1" || result.Type != "CREDIT_CARD" || result.DisplayString != "Visa *1234" {
+		t.Fatalf("unexpected funding result %#v", result)
+	}
+}
+
+func TestAccountSetSpendCapPostsAmount(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v25.0/act_42" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("parse body: %v", err)
+		}
+		if form.Get("spend_cap") != "2500.50" {
+			t.Fatalf("unexpected form %v", form)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"success": true})
+	}))
+	defer server.Close()
+
+	service := NewAccountService(graph.NewClient(server.Client(), server.URL))
+	result, err := service.SetSpendCap(context.Background(), "v25.0", "token-1", "", AccountSpendCapInput{AccountID: "act_42", Amount: "2500.50"})
+	if err != nil {
+		t.Fatalf("set spend cap: %v", err)
+	}
+	if result.AccountID != "act_42" || result.Payload["spend_cap"] != "2500.50" {
+		t.Fatalf("unexpected result %#v", result)
+	}
+}
+
+func TestBuildSpendCapPayloadValidatesModes(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		input AccountSpendCapInput
+		want  string
+	}{
+		{name: "none", input: AccountSpendCapInput{}, want: "exactly one of"},
+		{name: "amount and remove", input: AccountSpendCapInput{Amount: "10", Remove: true}, want: "exactly one of"},
+		{name: "bad amount", input: AccountSpendCapInput{Amount: "10.123"}, want: "invalid spend cap amount"},
+		{name: "zero amount", input: AccountSpendCapInput{Amount: "0"}, want: "must be > 0"},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := BuildSpendCapPayload(tc.input); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+
+	payload, err := BuildSpendCapPayload(AccountSpendCapInput{Reset: true})
+	if err != nil || payload["spend_cap_action"] != "reset" {
+		t.Fatalf("unexpected reset payload %#v (%v)", payload, err)
+	}
+}
@@ -64,6 +64,10 @@ type AdMutationResult struct {
 	AdID        string         `json:"ad_id"`
 	RequestPath string         `json:"request_path"`
 	Response    map[string]any `json:"response"`
+	// Diff is set by updates run with PreviewDiff.
+	Diff *UpdateDiff `json:"diff,omitempty"`
+	// TaggedLinks is set when create verified the creative's UTM tags.
+	TaggedLinks []TaggedLink `json:"tagged_links,omitempty"`
 }
 
 type AdCreateInput struct {
@@ -74,6 +78,7 @@ type AdCreateInput struct {
 type AdUpdateInput struct {
 	AdID   string
 	Params map[string]string
+	DiffPreview
 }
 
 type AdStatusInput struct {
@@ -288,6 +293,11 @@ func (s *AdService) Update(ctx context.Context, version string, token string, ap
 		return nil, err
 	}
 
+	var diff *UpdateDiff
+	if input.PreviewDiff {
+		diff = fetchUpdateDiff(ctx, s.Client, version, token, appSecret, adID, form)
+	}
+
 	response, err := s.Client.Do(ctx, graph.Request{
 		Method:      "POST",
 		Path:        adID,
@@ -309,6 +319,7 @@ func (s *AdService) Update(ctx context.Context, version string, token string, ap
 		AdID:        adID,
 		RequestPath: adID,
 		Response:    response.Body,
+		Diff:        diff,
 	}, nil
 }
 
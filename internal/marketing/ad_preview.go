@@ -0,0 +1,223 @@
+package marketing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	AdPreviewSourceAd           = "ad"
+	AdPreviewSourceCreativeSpec = "creative_spec"
+)
+
+// adPreviewFormats mirrors the ad_format enum accepted by the previews edges.
+var adPreviewFormats = map[string]struct{}{
+	"AUDIENCE_NETWORK_INSTREAM_VIDEO":         {},
+	"AUDIENCE_NETWORK_OUTSTREAM_VIDEO":        {},
+	"AUDIENCE_NETWORK_REWARDED_VIDEO":         {},
+	"BIZ_DISCO_FEED_MOBILE":                   {},
+	"DESKTOP_FEED_STANDARD":                   {},
+	"FACEBOOK_REELS_MOBILE":                   {},
+	"FACEBOOK_STORY_MOBILE":                   {},
+	"INSTAGRAM_EXPLORE_CONTEXTUAL":            {},
+	"INSTAGRAM_EXPLORE_GRID_HOME":             {},
+	"INSTAGRAM_EXPLORE_IMMERSIVE":             {},
+	"INSTAGRAM_FEED_WEB":                      {},
+	"INSTAGRAM_FEED_WEB_M_SITE":               {},
+	"INSTAGRAM_PROFILE_FEED":                  {},
+	"INSTAGRAM_REELS":                         {},
+	"INSTAGRAM_SEARCH_CHAIN":                  {},
+	"INSTAGRAM_STANDARD":                      {},
+	"INSTAGRAM_STORY":                         {},
+	"INSTANT_ARTICLE_STANDARD":                {},
+	"INSTREAM_VIDEO_DESKTOP":                  {},
+	"INSTREAM_VIDEO_MOBILE":                   {},
+	"MARKETPLACE_MOBILE":                      {},
+	"MESSENGER_MOBILE_INBOX_MEDIA":            {},
+	"MESSENGER_MOBILE_STORY_MEDIA":            {},
+	"MOBILE_BANNER":                           {},
+	"MOBILE_FEED_BASIC":                       {},
+	"MOBILE_FEED_STANDARD":                    {},
+	"MOBILE_FULLWIDTH":                        {},
+	"MOBILE_INTERSTITIAL":                     {},
+	"MOBILE_MEDIUM_RECTANGLE":                 {},
+	"MOBILE_NATIVE":                           {},
+	"RIGHT_COLUMN_STANDARD":                   {},
+	"SUGGESTED_VIDEO_DESKTOP":                 {},
+	"SUGGESTED_VIDEO_MOBILE":                  {},
+	"WATCH_FEED_HOME":                         {},
+	"WATCH_FEED_MOBILE":                       {},
+	"FACEBOOK_PROFILE_FEED_DESKTOP":           {},
+	"FACEBOOK_PROFILE_FEED_MOBILE":            {},
+	"FACEBOOK_IFU_REELS_MOBILE":               {},
+	"FACEBOOK_REELS_BANNER":                   {},
+	"FACEBOOK_REELS_STICKER":                  {},
+	"FACEBOOK_REELS_POSTLOOP":                 {},
+	"FACEBOOK_REELS_BANNER_DESKTOP":           {},
+	"FACEBOOK_REELS_BANNER_FULLSCREEN_MOBILE": {},
+}
+
+var adPreviewIframeSrcPattern = regexp.MustCompile(`(?i)<iframe[^>]*\ssrc\s*=\s*["']([^"']+)["']`)
+
+type AdPreviewInput struct {
+	AdID         string
+	AccountID    string
+	CreativeSpec map[string]any
+	Formats      []string
+}
+
+// AdPreview is one rendered placement. URL is the iframe source, which can be
+// opened directly in a browser for review.
+type AdPreview struct {
+	Format string `json:"format"`
+	URL    string `json:"url,omitempty"`
+	Iframe string `json:"iframe"`
+}
+
+type AdPreviewResult struct {
+	Source        string      `json:"source"`
+	AdID          string      `json:"ad_id,omitempty"`
+	AccountID     string      `json:"account_id,omitempty"`
+	RequestPath   string      `json:"request_path"`
+	ShareableLink string      `json:"shareable_link,omitempty"`
+	Previews      []AdPreview `json:"previews"`
+}
+
+// Preview renders an existing ad (previews edge) or an unsaved creative spec
+// (generatepreviews edge) once per requested format. Existing ads also return the
+// ad-level shareable preview link.
+func (s *AdService) Preview(ctx context.Context, version string, token string, appSecret string, input AdPreviewInput) (*AdPreviewResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("ad service client is required")
+	}
+	formats, err := normalizeAdPreviewFormats(input.Formats)
+	if err != nil {
+		return nil, err
+	}
+
+	hasAd := strings.TrimSpace(input.AdID) != ""
+	hasSpec := len(input.CreativeSpec) > 0
+	if hasAd == hasSpec {
+		return nil, errors.New("exactly one of ad id or creative spec is required")
+	}
+
+	result := &AdPreviewResult{Previews: make([]AdPreview, 0, len(formats))}
+	query := map[string]string{}
+	if hasAd {
+		adID, err := normalizeGraphID("ad id", input.AdID)
+		if err != nil {
+			return nil, err
+		}
+		result.Source = AdPreviewSourceAd
+		result.AdID = adID
+		result.RequestPath = adID + "/previews"
+	} else {
+		accountID, err := normalizeAdAccountID(input.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := json.Marshal(input.CreativeSpec)
+		if err != nil {
+			return nil, fmt.Errorf("encode creative spec: %w", err)
+		}
+		query["creative"] = string(encoded)
+		result.Source = AdPreviewSourceCreativeSpec
+		result.AccountID = "act_" + accountID
+		result.RequestPath = "act_" + accountID + "/generatepreviews"
+	}
+
+	for _, format := range formats {
+		query["ad_format"] = format
+		response, err := s.Client.Do(ctx, graph.Request{
+			Method:      "GET",
+			Path:        result.RequestPath,
+			Version:     strings.TrimSpace(version),
+			Query:       query,
+			AccessToken: token,
+			AppSecret:   appSecret,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("generate %s preview: %w", format, err)
+		}
+		iframe, err := extractAdPreviewBody(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("generate %s preview: %w", format, err)
+		}
+		result.Previews = append(result.Previews, AdPreview{
+			Format: format,
+			URL:    extractAdPreviewURL(iframe),
+			Iframe: iframe,
+		})
+	}
+
+	if hasAd {
+		response, err := s.Client.Do(ctx, graph.Request{
+			Method:      "GET",
+			Path:        result.AdID,
+			Version:     strings.TrimSpace(version),
+			Query:       map[string]string{"fields": "preview_shareable_link"},
+			AccessToken: token,
+			AppSecret:   appSecret,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("read ad preview shareable link: %w", err)
+		}
+		result.ShareableLink, _ = response.Body["preview_shareable_link"].(string)
+	}
+	return result, nil
+}
+
+func extractAdPreviewBody(body map[string]any) (string, error) {
+	data, _ := body["data"].([]any)
+	for _, raw := range data {
+		item, _ := raw.(map[string]any)
+		if iframe, _ := item["body"].(string); strings.TrimSpace(iframe) != "" {
+			return iframe, nil
+		}
+	}
+	return "", errors.New("preview response did not include a body")
+}
+
+func extractAdPreviewURL(iframe string) string {
+	match := adPreviewIframeSrcPattern.FindStringSubmatch(iframe)
+	if len(match) < 2 {
+		return ""
+	}
+	return html.UnescapeString(match[1])
+}
+
+func normalizeAdPreviewFormats(values []string) ([]string, error) {
+	formats := make([]string, 0, len(values))
+	seen := map[string]struct{}{}
+	for _, value := range values {
+		format := strings.ToUpper(strings.TrimSpace(value))
+		if format == "" {
+			continue
+		}
+		if _, ok := adPreviewFormats[format]; !ok {
+			supported := make([]string, 0, len(adPreviewFormats))
+			for name := range adPreviewFormats {
+				supported = append(supported, name)
+			}
+			sort.Strings(supported)
+			return nil, fmt.Errorf("unsupported ad preview format %q: expected one of %s", value, strings.Join(supported, ", "))
+		}
+		if _, ok := seen[format]; ok {
+			continue
+		}
+		seen[format] = struct{}{}
+		formats = append(formats, format)
+	}
+	if len(formats) == 0 {
+		return nil, errors.New("at least one ad preview format is required")
+	}
+	return formats, nil
+}
@@ -0,0 +1,121 @@
+package marketing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestAdPreviewRendersEachFormatAndShareableLink(t *testing.T) {
+	t.Parallel()
+
+	formats := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v25.0/ad_1/previews":
+			format := r.URL.Query().Get("ad_format")
+			formats = append(formats, format)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": []map[string]any{{"body": `<iframe src="https://www.facebook.com/ads/api/preview_iframe.php?d=abc&amp;t=` + format + `" width="540"></iframe>`}},
+			})
+		case "/v25.0/ad_1":
+			if r.URL.Query().Get("fields") != "preview_shareable_link" {
+				t.Fatalf("unexpected fields %q", r.URL.Query().Get("fields"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "ad_1", "preview_shareable_link": "https://fb.me/preview"})
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	result, err := NewAdService(graph.NewClient(server.Client(), server.URL)).Preview(context.Background(), "v25.0", "token", "", AdPreviewInput{
+		AdID:    "ad_1",
+		Formats: []string{"mobile_feed_standard", "INSTAGRAM_STORY", "MOBILE_FEED_STANDARD"},
+	})
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	if strings.Join(formats, ",") != "MOBILE_FEED_STANDARD,INSTAGRAM_STORY" {
+		t.Fatalf("unexpected requested formats %v", formats)
+	}
+	if result.Source != AdPreviewSourceAd || result.ShareableLink != "https://fb.me/preview" || len(result.Previews) != 2 {
+		t.Fatalf("unexpected result %#v", result)
+	}
+	if got := result.Previews[1].URL; got != "https://www.facebook.com/ads/api/preview_iframe.php?d=abc&t=INSTAGRAM_STORY" {
+		t.Fatalf("unexpected preview url %q", got)
+	}
+}
+
+func TestAdPreviewCreativeSpecUsesGeneratePreviews(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v25.0/act_123/generatepreviews" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("creative"); got != `{"object_story_spec":{"page_id":"p1"}}` {
+			t.Fatalf("unexpected creative %q", got)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{{"body": `<iframe src="https://preview.example.com/1"></iframe>`}}})
+	}))
+	defer server.Close()
+
+	result, err := NewAdService(graph.NewClient(server.Client(), server.URL)).Preview(context.Background(), "v25.0", "token", "", AdPreviewInput{
+		AccountID:    "act_123",
+		CreativeSpec: map[string]any{"object_story_spec": map[string]any{"page_id": "p1"}},
+		Formats:      []string{"DESKTOP_FEED_STANDARD"},
+	})
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	if result.Source != AdPreviewSourceCreativeSpec || result.AccountID != "act_123" || result.ShareableLink != "" {
+		t.Fatalf("unexpected result %#v", result)
+	}
+	if result.Previews[0].URL != "https://preview.example.com/1" {
+		t.Fatalf("unexpected preview %#v", result.Previews[0])
+	}
+}
+
+func TestAdPreviewValidatesInput(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		input AdPreviewInput
+		want  string
+	}{
+		{"format", AdPreviewInput{AdID: "ad_1", Formats: []string{"BILLBOARD"}}, "unsupported ad preview format"},
+		{"no format", AdPreviewInput{AdID: "ad_1"}, "at least one ad preview format"},
+		{"no source", AdPreviewInput{Formats: []string{"MOBILE_FEED_STANDARD"}}, "exactly one of ad id or creative spec"},
+		{"account", AdPreviewInput{CreativeSpec: map[string]any{"name": "x"}, Formats: []string{"MOBILE_FEED_STANDARD"}}, "account id is required"},
+	}
+	for _, tc := range cases {
+		_, err := NewAdService(graph.NewClient(nil, "")).Preview(context.Background(), "v25.0", "token", "", tc.input)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+	}
+}
+
+func TestAdPreviewFailsWhenBodyMissing(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{}})
+	}))
+	defer server.Close()
+
+	_, err := NewAdService(graph.NewClient(server.Client(), server.URL)).Preview(context.Background(), "v25.0", "token", "", AdPreviewInput{
+		AdID:    "ad_1",
+		Formats: []string{"INSTAGRAM_STORY"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "generate INSTAGRAM_STORY preview") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -33,6 +33,8 @@ type AdSetMutationResult struct {
 	AdSetID     string         `json:"adset_id"`
 	RequestPath string         `json:"request_path"`
 	Response    map[string]any `json:"response"`
+	// Diff is set by updates run with PreviewDiff.
+	Diff *UpdateDiff `json:"diff,omitempty"`
 }
 
 type AdSetCreateInput struct {
@@ -43,6 +45,7 @@ type AdSetCreateInput struct {
 type AdSetUpdateInput struct {
 	AdSetID string
 	Params  map[string]string
+	DiffPreview
 }
 
 type AdSetStatusInput struct {
@@ -223,6 +226,11 @@ func (s *AdSetService) Update(ctx context.Context, version string, token string,
 		return nil, err
 	}
 
+	var diff *UpdateDiff
+	if input.PreviewDiff {
+		diff = fetchUpdateDiff(ctx, s.Client, version, token, appSecret, adSetID, form)
+	}
+
 	response, err := s.Client.Do(ctx, graph.Request{
 		Method:      "POST",
 		Path:        adSetID,
@@ -244,6 +252,7 @@ func (s *AdSetService) Update(ctx context.Context, version string, token string,
 		AdSetID:     adSetID,
 		RequestPath: adSetID,
 		Response:    response.Body,
+		Diff:        diff,
 	}, nil
 }
 
@@ -308,6 +317,10 @@ func (s *AdSetService) ResolveAccountCurrency(ctx context.Context, version strin
 		return "", err
 	}
 
+	if cache := AccountContextCacheFrom(ctx); cache != nil {
+		return cache.Currency(ctx, s.Client, version, token, appSecret, normalizedAccountID)
+	}
+
 	path := fmt.Sprintf("act_%s", normalizedAccountID)
 	response, err := s.Client.Do(ctx, graph.Request{
 		Method:  "GET",
@@ -323,19 +336,7 @@ func (s *AdSetService) ResolveAccountCurrency(ctx context.Context, version strin
 		return "", err
 	}
 
-	currencyRaw, exists := response.Body["currency"]
-	if !exists {
-		return "", errors.New("ad account currency lookup response did not include currency")
-	}
-	currency, ok := currencyRaw.(string)
-	if !ok {
-		return "", fmt.Errorf("ad account currency lookup response field currency has unsupported type %T", currencyRaw)
-	}
-	currency = strings.ToUpper(strings.TrimSpace(currency))
-	if currency == "" {
-		return "", errors.New("ad account currency lookup response included empty currency")
-	}
-	return currency, nil
+	return accountCurrencyFromBody(response.Body)
 }
 
 func normalizeAdSetStatus(value string) (string, error) {
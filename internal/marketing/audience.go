@@ -2,6 +2,7 @@ package marketing
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
@@ -26,6 +27,12 @@ const (
 
 	audienceListEdgeCustom = "customaudiences"
 	audienceListEdgeSaved  = "saved_audiences"
+
+	audienceSubtypeLookalike = "LOOKALIKE"
+	audienceShareEdge        = "adaccounts"
+
+	AudienceLookalikeMinRatio = 0.01
+	AudienceLookalikeMaxRatio = 0.20
 )
 
 type AudienceService struct {
@@ -41,12 +48,26 @@ type AudienceCreateInput struct {
 type AudienceUpdateInput struct {
 	AudienceID string
 	Params     map[string]string
+	DiffPreview
 }
 
 type AudienceDeleteInput struct {
 	AudienceID string
 }
 
+type AudienceShareInput struct {
+	AudienceID string
+	AccountIDs []string
+}
+
+// AudienceLookalikeSpec describes a lookalike seeded from an existing custom audience.
+// Ratio is the share of the country's population to target (0.01-0.20).
+type AudienceLookalikeSpec struct {
+	OriginAudienceID string
+	Ratio            float64
+	Country          string
+}
+
 type AudienceListInput struct {
 	AccountID  string
 	Fields     []string
@@ -65,6 +86,8 @@ type AudienceMutationResult struct {
 	AudienceID  string         `json:"audience_id"`
 	RequestPath string         `json:"request_path"`
 	Response    map[string]any `json:"response"`
+	// Diff is set by updates run with PreviewDiff.
+	Diff *UpdateDiff `json:"diff,omitempty"`
 }
 
 type AudienceListResult struct {
@@ -261,6 +284,11 @@ func (s *AudienceService) Update(ctx context.Context, version string, token stri
 		return nil, err
 	}
 
+	var diff *UpdateDiff
+	if input.PreviewDiff {
+		diff = fetchUpdateDiff(ctx, s.Client, version, token, appSecret, audienceID, form)
+	}
+
 	response, err := s.Client.Do(ctx, graph.Request{
 		Method:      "POST",
 		Path:        audienceID,
@@ -282,6 +310,7 @@ func (s *AudienceService) Update(ctx context.Context, version string, token stri
 		AudienceID:  audienceID,
 		RequestPath: audienceID,
 		Response:    response.Body,
+		Diff:        diff,
 	}, nil
 }
 
@@ -322,6 +351,95 @@ func (s *AudienceService) Delete(ctx context.Context, version string, token stri
 	}, nil
 }
 
+func (s *AudienceService) Share(ctx context.Context, version string, token string, appSecret string, input AudienceShareInput) (*AudienceMutationResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("audience service client is required")
+	}
+
+	audienceID, err := normalizeGraphID("audience id", input.AudienceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.AccountIDs) == 0 {
+		return nil, errors.New("at least one account id is required to share an audience")
+	}
+	accountIDs := make([]string, 0, len(input.AccountIDs))
+	seen := make(map[string]struct{}, len(input.AccountIDs))
+	for _, value := range input.AccountIDs {
+		accountID, err := normalizeAdAccountID(value)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := seen[accountID]; exists {
+			continue
+		}
+		seen[accountID] = struct{}{}
+		accountIDs = append(accountIDs, accountID)
+	}
+	encoded, err := json.Marshal(accountIDs)
+	if err != nil {
+		return nil, fmt.Errorf("encode audience share account ids: %w", err)
+	}
+
+	path := fmt.Sprintf("%s/%s", audienceID, audienceShareEdge)
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:  "POST",
+		Path:    path,
+		Version: strings.TrimSpace(version),
+		Form: map[string]string{
+			"adaccounts": string(encoded),
+		},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	successValue, hasSuccess := response.Body["success"]
+	if hasSuccess {
+		success, ok := successValue.(bool)
+		if !ok || !success {
+			return nil, errors.New("audience share response was not successful")
+		}
+	}
+
+	return &AudienceMutationResult{
+		Operation:   "share",
+		AudienceID:  audienceID,
+		RequestPath: path,
+		Response:    response.Body,
+	}, nil
+}
+
+// Params returns the customaudiences create params for the lookalike.
+func (spec AudienceLookalikeSpec) Params() (map[string]string, error) {
+	originAudienceID, err := normalizeGraphID("lookalike origin audience id", spec.OriginAudienceID)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Ratio < AudienceLookalikeMinRatio || spec.Ratio > AudienceLookalikeMaxRatio {
+		return nil, fmt.Errorf("lookalike ratio must be between %.2f and %.2f, got %v", AudienceLookalikeMinRatio, AudienceLookalikeMaxRatio, spec.Ratio)
+	}
+	country := strings.ToUpper(strings.TrimSpace(spec.Country))
+	if len(country) != 2 {
+		return nil, fmt.Errorf("lookalike country must be a two-letter country code, got %q", spec.Country)
+	}
+
+	lookalikeSpec, err := json.Marshal(map[string]any{
+		"ratio":   spec.Ratio,
+		"country": country,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode lookalike spec: %w", err)
+	}
+	return map[string]string{
+		"subtype":            audienceSubtypeLookalike,
+		"origin_audience_id": originAudienceID,
+		"lookalike_spec":     string(lookalikeSpec),
+	}, nil
+}
+
 func normalizeAudienceMutationParams(params map[string]string) (map[string]string, error) {
 	normalized := map[string]string{}
 	for key, value := range params {
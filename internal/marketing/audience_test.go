@@ -620,3 +620,53 @@ func TestAudienceGetRejectsMissingAudienceID(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestAudienceLookalikeSpecParams(t *testing.T) {
+	t.Parallel()
+
+	params, err := AudienceLookalikeSpec{OriginAudienceID: "aud_1", Ratio: 0.05, Country: "gb"}.Params()
+	if err != nil {
+		t.Fatalf("lookalike params: %v", err)
+	}
+	if params["subtype"] != "LOOKALIKE" || params["origin_audience_id"] != "aud_1" || params["lookalike_spec"] != `{"country":"GB","ratio":0.05}` {
+		t.Fatalf("unexpected lookalike params %#v", params)
+	}
+
+	for _, spec := range []AudienceLookalikeSpec{
+		{OriginAudienceID: "", Ratio: 0.01, Country: "US"},
+		{OriginAudienceID: "aud_1", Ratio: 0.25, Country: "US"},
+		{OriginAudienceID: "aud_1", Ratio: 0.01, Country: "USA"},
+	} {
+		if _, err := spec.Params(); err == nil {
+			t.Fatalf("expected error for %#v", spec)
+		}
+	}
+}
+
+func TestAudienceShareRejectsMissingAccounts(t *testing.T) {
+	t.Parallel()
+
+	service := NewAudienceService(graph.NewClient(nil, ""))
+	_, err := service.Share(context.Background(), "v25.0", "token", "", AudienceShareInput{AudienceID: "aud_1"})
+	if err == nil || !strings.Contains(err.Error(), "at least one account id") {
+		t.Fatalf("expected missing accounts error, got %v", err)
+	}
+}
+
+func TestAudienceShareFailsWhenSuccessIsFalse(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{
+		t:          t,
+		statusCode: http.StatusOK,
+		response:   `{"success":false}`,
+	}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+	service := NewAudienceService(client)
+
+	_, err := service.Share(context.Background(), "v25.0", "token", "", AudienceShareInput{AudienceID: "aud_1", AccountIDs: []string{"act_2"}})
+	if err == nil || !strings.Contains(err.Error(), "not successful") {
+		t.Fatalf("expected unsuccessful share error, got %v", err)
+	}
+}
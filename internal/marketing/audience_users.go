@@ -0,0 +1,434 @@
+package marketing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/bilalbayram/metacli/internal/checkpoint"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/workqueue"
+)
+
+const (
+	AudienceUserKeyEmail     = "EMAIL"
+	AudienceUserKeyPhone     = "PHONE"
+	AudienceUserKeyFirstName = "FN"
+	AudienceUserKeyLastName  = "LN"
+	AudienceUserKeyCity      = "CT"
+	AudienceUserKeyState     = "ST"
+	AudienceUserKeyZip       = "ZIP"
+	AudienceUserKeyCountry   = "COUNTRY"
+	AudienceUserKeyGender    = "GEN"
+	AudienceUserKeyBirthYear = "DOBY"
+	AudienceUserKeyBirthMon  = "DOBM"
+	AudienceUserKeyBirthDay  = "DOBD"
+	AudienceUserKeyMobileAd  = "MADID"
+	AudienceUserKeyExternID  = "EXTERN_ID"
+
+	AudienceUserMaxBatchSize = 10000
+
+	audienceUsersEdge = "users"
+)
+
+var (
+	audienceUserSchemaAliases = map[string]string{
+		"email":      AudienceUserKeyEmail,
+		"phone":      AudienceUserKeyPhone,
+		"fn":         AudienceUserKeyFirstName,
+		"first_name": AudienceUserKeyFirstName,
+		"ln":         AudienceUserKeyLastName,
+		"last_name":  AudienceUserKeyLastName,
+		"ct":         AudienceUserKeyCity,
+		"city":       AudienceUserKeyCity,
+		"st":         AudienceUserKeyState,
+		"state":      AudienceUserKeyState,
+		"zip":        AudienceUserKeyZip,
+		"country":    AudienceUserKeyCountry,
+		"gen":        AudienceUserKeyGender,
+		"gender":     AudienceUserKeyGender,
+		"doby":       AudienceUserKeyBirthYear,
+		"dobm":       AudienceUserKeyBirthMon,
+		"dobd":       AudienceUserKeyBirthDay,
+		"madid":      AudienceUserKeyMobileAd,
+		"extern_id":  AudienceUserKeyExternID,
+	}
+	audienceUserDigitsOnly = regexp.MustCompile(`^[0-9]+$`)
+	audienceUserSHA256Hex  = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	audienceUserUSZipPlus4 = regexp.MustCompile(`^([0-9]{5})-?[0-9]{4}$`)
+)
+
+// AudienceUserPayload is a customer file that has been normalized and hashed locally.
+// Rows only contain SHA-256 hex digests, except MADID and EXTERN_ID which Meta expects
+// unhashed.
+type AudienceUserPayload struct {
+	Schema []string
+	Rows   [][]string
+	Stats  AudienceUserPrepareStats
+}
+
+type AudienceUserPrepareStats struct {
+	RowsRead      int            `json:"rows_read"`
+	RowsPrepared  int            `json:"rows_prepared"`
+	RowsSkipped   int            `json:"rows_skipped"`
+	InvalidValues map[string]int `json:"invalid_values,omitempty"`
+	FieldCoverage map[string]int `json:"field_coverage"`
+}
+
+type AudienceUserUploadInput struct {
+	AudienceID string
+	Payload    *AudienceUserPayload
+	SessionID  int64
+	BatchSize  int
+	// Concurrency is how many batches are sent at once; the closing batch is
+	// always sent last, on its own.
+	Concurrency int
+	// OnProgress is called after every batch.
+	OnProgress func(workqueue.Progress)
+	// Checkpoint records every accepted batch by sequence number. Batches it
+	// already holds are not sent again, so a resumed upload must keep the
+	// session id and batch size of the interrupted one.
+	Checkpoint *checkpoint.Checkpoint
+}
+
+type AudienceUserUploadBatch struct {
+	BatchSeq          int            `json:"batch_seq"`
+	Rows              int            `json:"rows"`
+	NumReceived       int            `json:"num_received"`
+	NumInvalidEntries int            `json:"num_invalid_entries"`
+	InvalidSamples    map[string]any `json:"invalid_entry_samples,omitempty"`
+}
+
+type AudienceUserUploadResult struct {
+	Operation         string                    `json:"operation"`
+	AudienceID        string                    `json:"audience_id"`
+	RequestPath       string                    `json:"request_path"`
+	SessionID         int64                     `json:"session_id"`
+	Schema            []string                  `json:"schema"`
+	Prepared          AudienceUserPrepareStats  `json:"prepared"`
+	NumReceived       int                       `json:"num_received"`
+	NumInvalidEntries int                       `json:"num_invalid_entries"`
+	Batches           []AudienceUserUploadBatch `json:"batches"`
+}
+
+// NormalizeAudienceUserSchema maps schema names (email, phone, fn, ...) to Meta
+// customer file keys, rejecting unknown and duplicate keys.
+func NormalizeAudienceUserSchema(schema []string) ([]string, error) {
+	if len(schema) == 0 {
+		return nil, errors.New("audience user schema is required")
+	}
+	normalized := make([]string, 0, len(schema))
+	seen := make(map[string]struct{}, len(schema))
+	for _, value := range schema {
+		key, ok := audienceUserSchemaAliases[strings.ToLower(strings.TrimSpace(value))]
+		if !ok {
+			return nil, fmt.Errorf("unsupported audience user schema key %q", value)
+		}
+		if _, exists := seen[key]; exists {
+			return nil, fmt.Errorf("duplicate audience user schema key %q", value)
+		}
+		seen[key] = struct{}{}
+		normalized = append(normalized, key)
+	}
+	return normalized, nil
+}
+
+// PrepareAudienceUsers normalizes and hashes customer records per Meta's customer
+// file spec. Values that are already SHA-256 hex digests pass through unchanged. Values
+// that cannot be normalized are blanked and counted; rows with no usable value are
+// skipped. Raw values never leave this function.
+func PrepareAudienceUsers(schema []string, records [][]string) (*AudienceUserPayload, error) {
+	keys, err := NormalizeAudienceUserSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &AudienceUserPayload{
+		Schema: keys,
+		Rows:   make([][]string, 0, len(records)),
+		Stats: AudienceUserPrepareStats{
+			InvalidValues: map[string]int{},
+			FieldCoverage: map[string]int{},
+		},
+	}
+	for index, record := range records {
+		payload.Stats.RowsRead++
+		if len(record) != len(keys) {
+			return nil, fmt.Errorf("customer record %d has %d column(s); schema has %d", index+1, len(record), len(keys))
+		}
+		row := make([]string, len(keys))
+		populated := false
+		for column, key := range keys {
+			raw := strings.TrimSpace(record[column])
+			if raw == "" {
+				continue
+			}
+			var value string
+			if hashed := strings.ToLower(raw); audienceUserKeyIsHashed(key) && audienceUserSHA256Hex.MatchString(hashed) {
+				value = hashed
+			} else {
+				normalized, ok := normalizeAudienceUserValue(key, raw)
+				if !ok {
+					payload.Stats.InvalidValues[key]++
+					continue
+				}
+				value = normalized
+				if audienceUserKeyIsHashed(key) {
+					value = hashAudienceUserValue(value)
+				}
+			}
+			row[column] = value
+			payload.Stats.FieldCoverage[key]++
+			populated = true
+		}
+		if !populated {
+			payload.Stats.RowsSkipped++
+			continue
+		}
+		payload.Rows = append(payload.Rows, row)
+		payload.Stats.RowsPrepared++
+	}
+	if len(payload.Stats.InvalidValues) == 0 {
+		payload.Stats.InvalidValues = nil
+	}
+	return payload, nil
+}
+
+func (s *AudienceService) UploadUsers(ctx context.Context, version string, token string, appSecret string, input AudienceUserUploadInput) (*AudienceUserUploadResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("audience service client is required")
+	}
+
+	audienceID, err := normalizeGraphID("audience id", input.AudienceID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Payload == nil || len(input.Payload.Rows) == 0 {
+		return nil, errors.New("customer file has no usable rows to upload")
+	}
+	if input.SessionID <= 0 {
+		return nil, errors.New("audience upload session id must be > 0")
+	}
+	batchSize := input.BatchSize
+	if batchSize <= 0 {
+		batchSize = AudienceUserMaxBatchSize
+	}
+	if batchSize > AudienceUserMaxBatchSize {
+		return nil, fmt.Errorf("audience upload batch size must be <= %d, got %d", AudienceUserMaxBatchSize, batchSize)
+	}
+
+	path := fmt.Sprintf("%s/%s", audienceID, audienceUsersEdge)
+	result := &AudienceUserUploadResult{
+		Operation:   "upload_users",
+		AudienceID:  audienceID,
+		RequestPath: path,
+		SessionID:   input.SessionID,
+		Schema:      input.Payload.Schema,
+		Prepared:    input.Payload.Stats,
+		Batches:     make([]AudienceUserUploadBatch, 0),
+	}
+	total := len(input.Payload.Rows)
+	type batchRange struct{ start, end int }
+	ranges := make([]batchRange, 0, (total+batchSize-1)/batchSize)
+	for start := 0; start < total; start += batchSize {
+		ranges = append(ranges, batchRange{start: start, end: min(start+batchSize, total)})
+	}
+	batches := make([]AudienceUserUploadBatch, len(ranges))
+	upload := func(ctx context.Context, index int) error {
+		unit := "batch/" + strconv.Itoa(index+1)
+		if done, err := input.Checkpoint.Lookup(unit, &batches[index]); err != nil || done {
+			return err
+		}
+		batch, err := s.uploadUserBatch(ctx, version, token, appSecret, path, input, index+1, ranges[index].start, ranges[index].end, total)
+		batches[index] = batch
+		if err != nil {
+			return err
+		}
+		return input.Checkpoint.Complete(unit, batch)
+	}
+	progress := func(done int, failed int) {
+		if input.OnProgress != nil {
+			input.OnProgress(workqueue.Progress{Total: len(ranges), Done: done, Failed: failed})
+		}
+	}
+
+	// Every batch but the last may be sent in parallel; the last carries
+	// last_batch_flag, which closes the session, so it goes alone once the rest
+	// are accepted. The first failure stops the upload.
+	leading := len(ranges) - 1
+	uploadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		firstErr  error
+		firstOnce sync.Once
+	)
+	finished, runErr := workqueue.Run(uploadCtx, leading, workqueue.Options{
+		Concurrency: input.Concurrency,
+		OnProgress: func(p workqueue.Progress) {
+			progress(p.Done, p.Failed)
+		},
+	}, func(ctx context.Context, index int) error {
+		err := upload(ctx, index)
+		if err != nil {
+			firstOnce.Do(func() {
+				firstErr = err
+				cancel()
+			})
+		}
+		return err
+	})
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if runErr != nil {
+		return nil, runErr
+	}
+	if err := upload(ctx, leading); err != nil {
+		progress(finished.Done+1, 1)
+		return nil, err
+	}
+	progress(finished.Done+1, 0)
+
+	for _, batch := range batches {
+		result.NumReceived += batch.NumReceived
+		result.NumInvalidEntries += batch.NumInvalidEntries
+		result.Batches = append(result.Batches, batch)
+	}
+	return result, nil
+}
+
+func (s *AudienceService) uploadUserBatch(ctx context.Context, version string, token string, appSecret string, path string, input AudienceUserUploadInput, batchSeq int, start int, end int, total int) (AudienceUserUploadBatch, error) {
+	encodedPayload, err := json.Marshal(map[string]any{
+		"schema": input.Payload.Schema,
+		"data":   input.Payload.Rows[start:end],
+	})
+	if err != nil {
+		return AudienceUserUploadBatch{}, fmt.Errorf("encode audience upload batch %d: %w", batchSeq, err)
+	}
+	encodedSession, err := json.Marshal(map[string]any{
+		"session_id":          input.SessionID,
+		"batch_seq":           batchSeq,
+		"last_batch_flag":     end == total,
+		"estimated_num_total": total,
+	})
+	if err != nil {
+		return AudienceUserUploadBatch{}, fmt.Errorf("encode audience upload session: %w", err)
+	}
+
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:  "POST",
+		Path:    path,
+		Version: strings.TrimSpace(version),
+		Form: map[string]string{
+			"payload": string(encodedPayload),
+			"session": string(encodedSession),
+		},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return AudienceUserUploadBatch{}, fmt.Errorf("audience upload batch %d (session %d): %w", batchSeq, input.SessionID, err)
+	}
+
+	batch := AudienceUserUploadBatch{
+		BatchSeq:          batchSeq,
+		Rows:              end - start,
+		NumReceived:       graphResponseInt(response.Body["num_received"]),
+		NumInvalidEntries: graphResponseInt(response.Body["num_invalid_entries"]),
+	}
+	if samples, ok := response.Body["invalid_entry_samples"].(map[string]any); ok && len(samples) > 0 {
+		batch.InvalidSamples = samples
+	}
+	return batch, nil
+}
+
+func normalizeAudienceUserValue(key string, raw string) (string, bool) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	switch key {
+	case AudienceUserKeyEmail:
+		if strings.Count(value, "@") != 1 || strings.HasPrefix(value, "@") || strings.HasSuffix(value, "@") || strings.ContainsAny(value, " \t") {
+			return "", false
+		}
+		return value, true
+	case AudienceUserKeyPhone:
+		digits := strings.TrimLeft(keepAudienceUserRunes(value, unicode.IsDigit), "0")
+		if len(digits) < 7 || len(digits) > 15 {
+			return "", false
+		}
+		return digits, true
+	case AudienceUserKeyFirstName, AudienceUserKeyLastName, AudienceUserKeyCity:
+		letters := keepAudienceUserRunes(value, unicode.IsLetter)
+		return letters, letters != ""
+	case AudienceUserKeyState, AudienceUserKeyCountry:
+		letters := keepAudienceUserRunes(value, unicode.IsLetter)
+		return letters, len(letters) == 2
+	case AudienceUserKeyZip:
+		compact := strings.ReplaceAll(value, " ", "")
+		if match := audienceUserUSZipPlus4.FindStringSubmatch(compact); match != nil {
+			return match[1], true
+		}
+		return compact, compact != ""
+	case AudienceUserKeyGender:
+		switch value {
+		case "m", "male":
+			return "m", true
+		case "f", "female":
+			return "f", true
+		}
+		return "", false
+	case AudienceUserKeyBirthYear:
+		return value, len(value) == 4 && audienceUserDigitsOnly.MatchString(value)
+	case AudienceUserKeyBirthMon, AudienceUserKeyBirthDay:
+		if len(value) == 1 {
+			value = "0" + value
+		}
+		return value, len(value) == 2 && audienceUserDigitsOnly.MatchString(value)
+	case AudienceUserKeyMobileAd:
+		return value, true
+	case AudienceUserKeyExternID:
+		return strings.TrimSpace(raw), true
+	default:
+		return "", false
+	}
+}
+
+func audienceUserKeyIsHashed(key string) bool {
+	return key != AudienceUserKeyMobileAd && key != AudienceUserKeyExternID
+}
+
+func hashAudienceUserValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func keepAudienceUserRunes(value string, keep func(rune) bool) string {
+	var builder strings.Builder
+	for _, r := range value {
+		if keep(r) {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+func graphResponseInt(value any) int {
+	switch typed := value.(type) {
+	case float64:
+		return int(typed)
+	case int:
+		return typed
+	case json.Number:
+		parsed, _ := typed.Int64()
+		return int(parsed)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,247 @@
+package marketing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/checkpoint"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/workqueue"
+)
+
+func TestPrepareAudienceUsersNormalizesAndHashes(t *testing.T) {
+	t.Parallel()
+
+	payload, err := PrepareAudienceUsers([]string{"email", "phone", "madid", "gender"}, [][]string{
+		{"  Jane.Doe@Example.com ", "+1 (555) 123-4567", "AB-CD", "Female"},
+		{"not-an-email", "", "", ""},
+		{"", "", "", ""},
+		{"86E0B9E56C17CC4D12387E1949B85053FBE73BC3CE5A1188713A9D300CC6133D", "", "", "x"},
+	})
+	if err != nil {
+		t.Fatalf("prepare audience users: %v", err)
+	}
+
+	if strings.Join(payload.Schema, ",") != "EMAIL,PHONE,MADID,GEN" {
+		t.Fatalf("unexpected schema %v", payload.Schema)
+	}
+	if len(payload.Rows) != 2 {
+		t.Fatalf("expected 2 prepared rows, got %#v", payload.Rows)
+	}
+	first := payload.Rows[0]
+	if first[0] != "86e0b9e56c17cc4d12387e1949b85053fbe73bc3ce5a1188713a9d300cc6133d" {
+		t.Fatalf("unexpected email hash %q", first[0])
+	}
+	if first[1] != "d6736136ea896c1bfdc553e0e86e702c70d060d805696ca3e4e9e0961353860a" {
+		t.Fatalf("unexpected phone hash %q", first[1])
+	}
+	if first[2] != "ab-cd" {
+		t.Fatalf("expected madid to stay unhashed, got %q", first[2])
+	}
+	if payload.Rows[1][0] != first[0] {
+		t.Fatalf("expected pre-hashed email to pass through, got %q", payload.Rows[1][0])
+	}
+
+	stats := payload.Stats
+	if stats.RowsRead != 4 || stats.RowsPrepared != 2 || stats.RowsSkipped != 2 {
+		t.Fatalf("unexpected row stats %#v", stats)
+	}
+	if stats.InvalidValues["EMAIL"] != 1 || stats.InvalidValues["GEN"] != 1 || stats.FieldCoverage["EMAIL"] != 2 {
+		t.Fatalf("unexpected value stats %#v", stats)
+	}
+}
+
+func TestPrepareAudienceUsersRejectsBadSchemaAndRecords(t *testing.T) {
+	t.Parallel()
+
+	if _, err := PrepareAudienceUsers([]string{"email", "ssn"}, nil); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Fatalf("expected unsupported schema error, got %v", err)
+	}
+	if _, err := PrepareAudienceUsers([]string{"email", "EMAIL"}, nil); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Fatalf("expected duplicate schema error, got %v", err)
+	}
+	_, err := PrepareAudienceUsers([]string{"email", "phone"}, [][]string{{"secret@example.com"}})
+	if err == nil || !strings.Contains(err.Error(), "record 1") {
+		t.Fatalf("expected column count error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "secret@example.com") {
+		t.Fatalf("error leaked raw value: %v", err)
+	}
+}
+
+func TestAudienceUploadUsersSendsSessionBatches(t *testing.T) {
+	t.Parallel()
+
+	bodies := make([]string, 0)
+	stub := &recordingAudienceUploadClient{t: t, bodies: &bodies}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+	service := NewAudienceService(client)
+
+	payload := &AudienceUserPayload{
+		Schema: []string{"EMAIL"},
+		Rows:   [][]string{{"a"}, {"b"}, {"c"}},
+		Stats:  AudienceUserPrepareStats{RowsRead: 3, RowsPrepared: 3},
+	}
+	result, err := service.UploadUsers(context.Background(), "v25.0", "token", "", AudienceUserUploadInput{
+		AudienceID: "aud_1",
+		Payload:    payload,
+		SessionID:  42,
+		BatchSize:  2,
+	})
+	if err != nil {
+		t.Fatalf("upload users: %v", err)
+	}
+	if len(bodies) != 2 || len(result.Batches) != 2 || result.NumReceived != 3 || result.NumInvalidEntries != 0 {
+		t.Fatalf("unexpected upload result %#v", result)
+	}
+
+	for index, body := range bodies {
+		form, err := url.ParseQuery(body)
+		if err != nil {
+			t.Fatalf("parse body: %v", err)
+		}
+		session := map[string]any{}
+		if err := json.Unmarshal([]byte(form.Get("session")), &session); err != nil {
+			t.Fatalf("decode session: %v", err)
+		}
+		if session["session_id"] != float64(42) || session["batch_seq"] != float64(index+1) || session["last_batch_flag"] != (index == 1) || session["estimated_num_total"] != float64(3) {
+			t.Fatalf("unexpected session %v", session)
+		}
+	}
+	if !strings.Contains(bodies[1], url.QueryEscape(`"data":[["c"]]`)) {
+		t.Fatalf("unexpected last batch body %s", bodies[1])
+	}
+}
+
+func TestAudienceUploadUsersSendsClosingBatchLastWhenConcurrent(t *testing.T) {
+	t.Parallel()
+
+	bodies := make([]string, 0)
+	stub := &recordingAudienceUploadClient{t: t, bodies: &bodies}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+	service := NewAudienceService(client)
+
+	payload := &AudienceUserPayload{
+		Schema: []string{"EMAIL"},
+		Rows:   [][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}},
+	}
+	var reports []string
+	result, err := service.UploadUsers(context.Background(), "v25.0", "token", "", AudienceUserUploadInput{
+		AudienceID:  "aud_1",
+		Payload:     payload,
+		SessionID:   42,
+		BatchSize:   1,
+		Concurrency: 3,
+		OnProgress: func(progress workqueue.Progress) {
+			reports = append(reports, progress.String())
+		},
+	})
+	if err != nil {
+		t.Fatalf("upload users: %v", err)
+	}
+	if len(bodies) != 5 || result.NumReceived != 5 {
+		t.Fatalf("unexpected upload result %#v", result)
+	}
+	for index, batch := range result.Batches {
+		if batch.BatchSeq != index+1 {
+			t.Fatalf("expected batches in sequence order, got %#v", result.Batches)
+		}
+	}
+	form, err := url.ParseQuery(bodies[4])
+	if err != nil {
+		t.Fatalf("parse body: %v", err)
+	}
+	session := map[string]any{}
+	if err := json.Unmarshal([]byte(form.Get("session")), &session); err != nil {
+		t.Fatalf("decode session: %v", err)
+	}
+	if session["batch_seq"] != float64(5) || session["last_batch_flag"] != true {
+		t.Fatalf("expected the closing batch to be sent last, got %v", session)
+	}
+	if len(reports) != 5 || reports[4] != "5/5 done, 0 failed" {
+		t.Fatalf("unexpected progress %#v", reports)
+	}
+}
+
+func TestAudienceUploadUsersSkipsCheckpointedBatches(t *testing.T) {
+	t.Parallel()
+
+	bodies := make([]string, 0)
+	stub := &recordingAudienceUploadClient{t: t, bodies: &bodies}
+	client := graph.NewClient(stub, "https://graph.example.com")
+	client.MaxRetries = 0
+	service := NewAudienceService(client)
+
+	path := filepath.Join(t.TempDir(), "customers.checkpoint.json")
+	interrupted, err := checkpoint.Open(path, "audience_upload_users", "customers", false)
+	if err != nil {
+		t.Fatalf("open checkpoint: %v", err)
+	}
+	if err := interrupted.Complete("batch/1", AudienceUserUploadBatch{BatchSeq: 1, Rows: 2, NumReceived: 2}); err != nil {
+		t.Fatalf("record batch: %v", err)
+	}
+	resumed, err := checkpoint.Open(path, "audience_upload_users", "customers", true)
+	if err != nil {
+		t.Fatalf("resume checkpoint: %v", err)
+	}
+
+	result, err := service.UploadUsers(context.Background(), "v25.0", "token", "", AudienceUserUploadInput{
+		AudienceID: "aud_1",
+		Payload:    &AudienceUserPayload{Schema: []string{"EMAIL"}, Rows: [][]string{{"a"}, {"b"}, {"c"}}},
+		SessionID:  42,
+		BatchSize:  2,
+		Checkpoint: resumed,
+	})
+	if err != nil {
+		t.Fatalf("upload users: %v", err)
+	}
+	if len(bodies) != 1 || !strings.Contains(bodies[0], url.QueryEscape(`"data":[["c"]]`)) {
+		t.Fatalf("expected only the closing batch to be sent, got %v", bodies)
+	}
+	if len(result.Batches) != 2 || result.NumReceived != 3 || resumed.Len() != 2 {
+		t.Fatalf("unexpected resumed result %#v", result)
+	}
+}
+
+type recordingAudienceUploadClient struct {
+	t      *testing.T
+	mu     sync.Mutex
+	bodies *[]string
+}
+
+func (c *recordingAudienceUploadClient) Do(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		c.t.Fatalf("read request body: %v", err)
+	}
+	c.mu.Lock()
+	*c.bodies = append(*c.bodies, string(body))
+	c.mu.Unlock()
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		c.t.Fatalf("parse request body: %v", err)
+	}
+	payload := struct {
+		Data [][]string `json:"data"`
+	}{}
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		c.t.Fatalf("decode payload: %v", err)
+	}
+	response := fmt.Sprintf(`{"audience_id":"aud_1","session_id":42,"num_received":%d,"num_invalid_entries":0}`, len(payload.Data))
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(response)),
+	}, nil
+}
@@ -50,6 +50,8 @@ type CampaignMutationResult struct {
 	CampaignID  string         `json:"campaign_id"`
 	RequestPath string         `json:"request_path"`
 	Response    map[string]any `json:"response"`
+	// Diff is set by updates run with PreviewDiff.
+	Diff *UpdateDiff `json:"diff,omitempty"`
 }
 
 type CampaignCreateInput struct {
@@ -60,6 +62,7 @@ type CampaignCreateInput struct {
 type CampaignUpdateInput struct {
 	CampaignID string
 	Params     map[string]string
+	DiffPreview
 }
 
 type CampaignStatusInput struct {
@@ -250,6 +253,11 @@ func (s *Service) Update(ctx context.Context, version string, token string, appS
 		return nil, err
 	}
 
+	var diff *UpdateDiff
+	if input.PreviewDiff {
+		diff = fetchUpdateDiff(ctx, s.Client, version, token, appSecret, campaignID, form)
+	}
+
 	response, err := s.Client.Do(ctx, graph.Request{
 		Method:      "POST",
 		Path:        campaignID,
@@ -271,6 +279,7 @@ func (s *Service) Update(ctx context.Context, version string, token string, appS
 		CampaignID:  campaignID,
 		RequestPath: campaignID,
 		Response:    response.Body,
+		Diff:        diff,
 	}, nil
 }
 
@@ -0,0 +1,243 @@
+package marketing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultCatalogDiagnoseUploadLimit = 1
+	defaultCatalogDiagnoseSampleLimit = 3
+)
+
+var (
+	catalogFeedUploadFields = []string{
+		"id",
+		"start_time",
+		"end_time",
+		"url",
+		"error_count",
+		"warning_count",
+		"num_detected_items",
+		"num_persisted_items",
+	}
+
+	// catalogErrorHints maps substrings of feed upload error types to remediation hints.
+	// Entries are checked in order so more specific keys must come first.
+	catalogErrorHints = []struct {
+		match string
+		hint  string
+	}{
+		{"sale_price", "Keep sale_price below price and in the same currency, formatted as \"<amount> <ISO currency>\"."},
+		{"price", "Format price as \"<amount> <ISO currency>\" (for example \"9.99 USD\") with a positive amount."},
+		{"image", "Make image_link a public https URL that returns an image of at least 500x500 pixels."},
+		{"url", "Make link an absolute https URL that resolves without redirects to a login page."},
+		{"link", "Make link an absolute https URL that resolves without redirects to a login page."},
+		{"availability", "Use one of: in stock, out of stock, preorder, available for order, discontinued."},
+		{"condition", "Use one of: new, refurbished, used."},
+		{"duplicate", "Give every row a unique id; duplicated retailer ids overwrite each other."},
+		{"missing", "Populate every required column (id, title, description, availability, condition, price, link, image_link, brand)."},
+		{"required", "Populate every required column (id, title, description, availability, condition, price, link, image_link, brand)."},
+		{"policy", "Review the item against Commerce Policies; policy rejections need content changes, not format fixes."},
+		{"category", "Use a valid google_product_category or fb_product_category value."},
+	}
+)
+
+type CatalogDiagnoseInput struct {
+	CatalogID   string
+	FeedID      string
+	UploadLimit int
+	SampleLimit int
+}
+
+type CatalogErrorGroup struct {
+	Code     string           `json:"code"`
+	Severity string           `json:"severity,omitempty"`
+	Count    int              `json:"count"`
+	Summary  string           `json:"summary,omitempty"`
+	Hint     string           `json:"hint"`
+	Samples  []map[string]any `json:"samples,omitempty"`
+}
+
+type CatalogFeedDiagnosis struct {
+	FeedID         string              `json:"feed_id"`
+	Name           string              `json:"name,omitempty"`
+	Uploads        []map[string]any    `json:"uploads"`
+	LatestUploadID string              `json:"latest_upload_id,omitempty"`
+	DetectedItems  int                 `json:"detected_items"`
+	PersistedItems int                 `json:"persisted_items"`
+	RejectedItems  int                 `json:"rejected_items"`
+	ErrorGroups    []CatalogErrorGroup `json:"error_groups"`
+}
+
+type CatalogDiagnoseResult struct {
+	Operation     string                 `json:"operation"`
+	CatalogID     string                 `json:"catalog_id,omitempty"`
+	FeedCount     int                    `json:"feed_count"`
+	RejectedItems int                    `json:"rejected_items"`
+	ErrorCount    int                    `json:"error_count"`
+	Feeds         []CatalogFeedDiagnosis `json:"feeds"`
+}
+
+// Diagnose pulls recent upload sessions for one feed (FeedID) or every feed in a
+// catalog (CatalogID), and groups the latest session's errors by error type.
+func (s *CatalogService) Diagnose(ctx context.Context, version string, token string, appSecret string, input CatalogDiagnoseInput) (*CatalogDiagnoseResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("catalog service client is required")
+	}
+	catalogID := strings.TrimSpace(input.CatalogID)
+	feedID := strings.TrimSpace(input.FeedID)
+	if (catalogID == "") == (feedID == "") {
+		return nil, errors.New("exactly one of catalog id or feed id is required")
+	}
+	uploadLimit := input.UploadLimit
+	if uploadLimit == 0 {
+		uploadLimit = defaultCatalogDiagnoseUploadLimit
+	}
+	if uploadLimit < 0 {
+		return nil, fmt.Errorf("upload limit must be >= 0, got %d", input.UploadLimit)
+	}
+	sampleLimit := input.SampleLimit
+	if sampleLimit == 0 {
+		sampleLimit = defaultCatalogDiagnoseSampleLimit
+	}
+	if sampleLimit < 0 {
+		return nil, fmt.Errorf("sample limit must be >= 0, got %d", input.SampleLimit)
+	}
+
+	feeds := []map[string]any{{"id": feedID}}
+	if catalogID != "" {
+		listed, err := s.ListFeeds(ctx, version, token, appSecret, CatalogEdgeListInput{
+			CatalogID:  catalogID,
+			Fields:     []string{"id", "name"},
+			FollowNext: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		feeds = listed.Items
+	}
+
+	result := &CatalogDiagnoseResult{
+		Operation: "diagnose",
+		CatalogID: catalogID,
+		Feeds:     make([]CatalogFeedDiagnosis, 0, len(feeds)),
+	}
+	for _, feed := range feeds {
+		diagnosis, err := s.diagnoseFeed(ctx, version, token, appSecret, feed, uploadLimit, sampleLimit)
+		if err != nil {
+			return nil, err
+		}
+		result.RejectedItems += diagnosis.RejectedItems
+		for _, group := range diagnosis.ErrorGroups {
+			result.ErrorCount += group.Count
+		}
+		result.Feeds = append(result.Feeds, diagnosis)
+	}
+	result.FeedCount = len(result.Feeds)
+	return result, nil
+}
+
+func (s *CatalogService) diagnoseFeed(ctx context.Context, version string, token string, appSecret string, feed map[string]any, uploadLimit int, sampleLimit int) (CatalogFeedDiagnosis, error) {
+	feedID, err := normalizeGraphID("feed id", catalogString(feed["id"]))
+	if err != nil {
+		return CatalogFeedDiagnosis{}, err
+	}
+	diagnosis := CatalogFeedDiagnosis{
+		FeedID:      feedID,
+		Name:        catalogString(feed["name"]),
+		ErrorGroups: []CatalogErrorGroup{},
+	}
+
+	uploads, err := s.listEdge(ctx, version, token, appSecret, "list_feed_uploads", feedID+"/uploads", catalogFeedUploadFields, nil, uploadLimit, false)
+	if err != nil {
+		return CatalogFeedDiagnosis{}, err
+	}
+	diagnosis.Uploads = uploads.Items
+	if len(uploads.Items) == 0 {
+		return diagnosis, nil
+	}
+
+	latest := uploads.Items[0]
+	diagnosis.LatestUploadID, err = normalizeGraphID("feed upload id", catalogString(latest["id"]))
+	if err != nil {
+		return CatalogFeedDiagnosis{}, err
+	}
+	diagnosis.DetectedItems = graphResponseInt(latest["num_detected_items"])
+	diagnosis.PersistedItems = graphResponseInt(latest["num_persisted_items"])
+	if rejected := diagnosis.DetectedItems - diagnosis.PersistedItems; rejected > 0 {
+		diagnosis.RejectedItems = rejected
+	}
+
+	errorFields := []string{"id", "summary", "description", "severity", "error_type", "total_count"}
+	if sampleLimit > 0 {
+		errorFields = append(errorFields, fmt.Sprintf("samples.limit(%d){retailer_id,row_number,id}", sampleLimit))
+	}
+	uploadErrors, err := s.listEdge(ctx, version, token, appSecret, "list_feed_upload_errors", diagnosis.LatestUploadID+"/errors", errorFields, nil, 0, true)
+	if err != nil {
+		return CatalogFeedDiagnosis{}, err
+	}
+	diagnosis.ErrorGroups = groupCatalogUploadErrors(uploadErrors.Items, sampleLimit)
+	return diagnosis, nil
+}
+
+func groupCatalogUploadErrors(items []map[string]any, sampleLimit int) []CatalogErrorGroup {
+	byCode := map[string]*CatalogErrorGroup{}
+	for _, item := range items {
+		code := strings.TrimSpace(catalogString(item["error_type"]))
+		if code == "" {
+			code = "unknown"
+		}
+		group, ok := byCode[code]
+		if !ok {
+			group = &CatalogErrorGroup{
+				Code:     code,
+				Severity: strings.ToLower(strings.TrimSpace(catalogString(item["severity"]))),
+				Summary:  strings.TrimSpace(catalogString(item["summary"])),
+				Hint:     catalogErrorHint(code),
+			}
+			byCode[code] = group
+		}
+		count := graphResponseInt(item["total_count"])
+		if count <= 0 {
+			count = 1
+		}
+		group.Count += count
+
+		samples, _ := item["samples"].(map[string]any)
+		entries, _ := samples["data"].([]any)
+		for _, entry := range entries {
+			if len(group.Samples) >= sampleLimit {
+				break
+			}
+			if sample, ok := entry.(map[string]any); ok {
+				group.Samples = append(group.Samples, sample)
+			}
+		}
+	}
+
+	groups := make([]CatalogErrorGroup, 0, len(byCode))
+	for _, group := range byCode {
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Code < groups[j].Code
+	})
+	return groups
+}
+
+func catalogErrorHint(code string) string {
+	normalized := strings.ToLower(code)
+	for _, entry := range catalogErrorHints {
+		if strings.Contains(normalized, entry.match) {
+			return entry.hint
+		}
+	}
+	return "Inspect the sample rows in the feed file and compare them with the catalog field specifications."
+}
@@ -0,0 +1,110 @@
+package marketing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestCatalogDiagnoseGroupsLatestUploadErrorsByCode(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/v25.0/cat_1/product_feeds":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": []map[string]any{{"id": "feed_1", "name": "Main feed"}},
+			})
+		case "/v25.0/feed_1/uploads":
+			if got := r.URL.Query().Get("limit"); got != "1" {
+				t.Fatalf("unexpected uploads limit %q", got)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": []map[string]any{{
+					"id":                  "upload_9",
+					"num_detected_items":  120,
+					"num_persisted_items": 100,
+					"error_count":         3,
+				}},
+			})
+		case "/v25.0/upload_9/errors":
+			if got := r.URL.Query().Get("fields"); !strings.Contains(got, "samples.limit(2){retailer_id,row_number,id}") {
+				t.Fatalf("expected sample field expansion, got %q", got)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": []map[string]any{
+					{
+						"id": "err_1", "error_type": "INVALID_PRICE", "severity": "FATAL", "summary": "Invalid price", "total_count": 12,
+						"samples": map[string]any{"data": []map[string]any{
+							{"retailer_id": "sku_1", "row_number": 4},
+							{"retailer_id": "sku_2", "row_number": 9},
+						}},
+					},
+					{"id": "err_2", "error_type": "IMAGE_DOWNLOAD_FAILED", "severity": "FATAL", "summary": "Image unavailable", "total_count": 8},
+					{"id": "err_3", "error_type": "INVALID_PRICE", "severity": "FATAL", "summary": "Invalid price", "total_count": 3},
+				},
+			})
+		default:
+			t.Fatalf("unexpected request path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	service := NewCatalogService(graph.NewClient(server.Client(), server.URL))
+	result, err := service.Diagnose(context.Background(), "v25.0", "token-1", "", CatalogDiagnoseInput{
+		CatalogID:   "cat_1",
+		SampleLimit: 2,
+	})
+	if err != nil {
+		t.Fatalf("diagnose catalog: %v", err)
+	}
+	if result.FeedCount != 1 || result.RejectedItems != 20 || result.ErrorCount != 23 {
+		t.Fatalf("unexpected totals %+v", result)
+	}
+	feed := result.Feeds[0]
+	if feed.FeedID != "feed_1" || feed.Name != "Main feed" || feed.LatestUploadID != "upload_9" {
+		t.Fatalf("unexpected feed diagnosis %+v", feed)
+	}
+	if len(feed.ErrorGroups) != 2 {
+		t.Fatalf("expected 2 error groups, got %d", len(feed.ErrorGroups))
+	}
+	first := feed.ErrorGroups[0]
+	if first.Code != "INVALID_PRICE" || first.Count != 15 || first.Severity != "fatal" {
+		t.Fatalf("unexpected first group %+v", first)
+	}
+	if len(first.Samples) != 2 || first.Samples[0]["retailer_id"] != "sku_1" {
+		t.Fatalf("unexpected samples %#v", first.Samples)
+	}
+	if !strings.Contains(first.Hint, "Format price") {
+		t.Fatalf("unexpected price hint %q", first.Hint)
+	}
+	if second := feed.ErrorGroups[1]; second.Code != "IMAGE_DOWNLOAD_FAILED" || !strings.Contains(second.Hint, "image_link") {
+		t.Fatalf("unexpected second group %+v", second)
+	}
+}
+
+func TestCatalogDiagnoseRequiresExactlyOneTarget(t *testing.T) {
+	t.Parallel()
+
+	service := NewCatalogService(graph.NewClient(nil, ""))
+	for _, input := range []CatalogDiagnoseInput{
+		{},
+		{CatalogID: "cat_1", FeedID: "feed_1"},
+	} {
+		_, err := service.Diagnose(context.Background(), "v25.0", "token-1", "", input)
+		if err == nil {
+			t.Fatalf("expected target error for %+v", input)
+		}
+		if !strings.Contains(err.Error(), "exactly one of catalog id or feed id") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package marketing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	CatalogFeedIntervalHourly = "HOURLY"
+	CatalogFeedIntervalDaily  = "DAILY"
+	CatalogFeedIntervalWeekly = "WEEKLY"
+)
+
+var (
+	DefaultCatalogReadFields    = []string{"id", "name", "vertical", "product_count"}
+	DefaultProductSetReadFields = []string{"id", "name", "filter", "product_count"}
+	DefaultCatalogFeedFields    = []string{"id", "name", "schedule", "latest_upload", "product_count"}
+)
+
+type CatalogListInput struct {
+	BusinessID string
+	Fields     []string
+	Limit      int
+	FollowNext bool
+}
+
+type CatalogEdgeListInput struct {
+	CatalogID  string
+	Fields     []string
+	Limit      int
+	FollowNext bool
+}
+
+type CatalogCreateInput struct {
+	BusinessID string
+	Name       string
+	Vertical   string
+}
+
+type ProductSetCreateInput struct {
+	CatalogID string
+	Name      string
+	Filter    map[string]any
+}
+
+type ProductSetDeleteInput struct {
+	ProductSetID string
+}
+
+type CatalogFeedSchedule struct {
+	Interval string
+	URL      string
+	Hour     int
+	Weekday  string
+}
+
+type CatalogFeedCreateInput struct {
+	CatalogID string
+	Name      string
+	Schedule  CatalogFeedSchedule
+}
+
+type CatalogFeedUploadInput struct {
+	FeedID string
+	URL    string
+}
+
+type CatalogListResult struct {
+	Operation   string                  `json:"operation"`
+	RequestPath string                  `json:"request_path"`
+	Items       []map[string]any        `json:"items"`
+	Paging      *graph.PaginationResult `json:"paging,omitempty"`
+}
+
+type CatalogMutationResult struct {
+	Operation   string         `json:"operation"`
+	ID          string         `json:"id"`
+	RequestPath string         `json:"request_path"`
+	Response    map[string]any `json:"response"`
+}
+
+func (s *CatalogService) ListCatalogs(ctx context.Context, version string, token string, appSecret string, input CatalogListInput) (*CatalogListResult, error) {
+	businessID, err := normalizeGraphID("business id", input.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	return s.listEdge(ctx, version, token, appSecret, "list_catalogs", businessID+"/owned_product_catalogs", input.Fields, DefaultCatalogReadFields, input.Limit, input.FollowNext)
+}
+
+func (s *CatalogService) ListProductSets(ctx context.Context, version string, token string, appSecret string, input CatalogEdgeListInput) (*CatalogListResult, error) {
+	catalogID, err := normalizeGraphID("catalog id", input.CatalogID)
+	if err != nil {
+		return nil, err
+	}
+	return s.listEdge(ctx, version, token, appSecret, "list_product_sets", catalogID+"/product_sets", input.Fields, DefaultProductSetReadFields, input.Limit, input.FollowNext)
+}
+
+func (s *CatalogService) ListFeeds(ctx context.Context, version string, token string, appSecret string, input CatalogEdgeListInput) (*CatalogListResult, error) {
+	catalogID, err := normalizeGraphID("catalog id", input.CatalogID)
+	if err != nil {
+		return nil, err
+	}
+	return s.listEdge(ctx, version, token, appSecret, "list_feeds", catalogID+"/product_feeds", input.Fields, DefaultCatalogFeedFields, input.Limit, input.FollowNext)
+}
+
+func (s *CatalogService) CreateCatalog(ctx context.Context, version string, token string, appSecret string, input CatalogCreateInput) (*CatalogMutationResult, error) {
+	businessID, err := normalizeGraphID("business id", input.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("catalog name is required")
+	}
+	form := map[string]string{"name": name}
+	if vertical := strings.ToLower(strings.TrimSpace(input.Vertical)); vertical != "" {
+		form["vertical"] = vertical
+	}
+	return s.mutate(ctx, version, token, appSecret, "create_catalog", "POST", businessID+"/owned_product_catalogs", form)
+}
+
+func (s *CatalogService) CreateProductSet(ctx context.Context, version string, token string, appSecret string, input ProductSetCreateInput) (*CatalogMutationResult, error) {
+	catalogID, err := normalizeGraphID("catalog id", input.CatalogID)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("product set name is required")
+	}
+	form := map[string]string{"name": name}
+	if len(input.Filter) > 0 {
+		encoded, err := json.Marshal(input.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("encode product set filter: %w", err)
+		}
+		form["filter"] = string(encoded)
+	}
+	return s.mutate(ctx, version, token, appSecret, "create_product_set", "POST", catalogID+"/product_sets", form)
+}
+
+func (s *CatalogService) DeleteProductSet(ctx context.Context, version string, token string, appSecret string, input ProductSetDeleteInput) (*CatalogMutationResult, error) {
+	productSetID, err := normalizeGraphID("product set id", input.ProductSetID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.mutate(ctx, version, token, appSecret, "delete_product_set", "DELETE", productSetID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if success, ok := result.Response["success"]; ok {
+		if value, isBool := success.(bool); !isBool || !value {
+			return nil, errors.New("product set delete response was not successful")
+		}
+	}
+	result.ID = productSetID
+	return result, nil
+}
+
+// CreateFeed creates a scheduled feed that Meta fetches from Schedule.URL on the
+// given interval.
+func (s *CatalogService) CreateFeed(ctx context.Context, version string, token string, appSecret string, input CatalogFeedCreateInput) (*CatalogMutationResult, error) {
+	catalogID, err := normalizeGraphID("catalog id", input.CatalogID)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("catalog feed name is required")
+	}
+	schedule, err := normalizeCatalogFeedSchedule(input.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("encode catalog feed schedule: %w", err)
+	}
+	return s.mutate(ctx, version, token, appSecret, "create_feed", "POST", catalogID+"/product_feeds", map[string]string{
+		"name":     name,
+		"schedule": string(encoded),
+	})
+}
+
+// UploadFeed triggers a one-off fetch of a feed file from URL.
+func (s *CatalogService) UploadFeed(ctx context.Context, version string, token string, appSecret string, input CatalogFeedUploadInput) (*CatalogMutationResult, error) {
+	feedID, err := normalizeGraphID("feed id", input.FeedID)
+	if err != nil {
+		return nil, err
+	}
+	feedURL, err := normalizeCatalogFeedURL(input.URL)
+	if err != nil {
+		return nil, err
+	}
+	return s.mutate(ctx, version, token, appSecret, "upload_feed", "POST", feedID+"/uploads", map[string]string{
+		"url": feedURL,
+	})
+}
+
+func (s *CatalogService) listEdge(ctx context.Context, version string, token string, appSecret string, operation string, path string, fields []string, defaults []string, limit int, followNext bool) (*CatalogListResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("catalog service client is required")
+	}
+	readFields, err := normalizeCatalogReadFields(fields, defaults)
+	if err != nil {
+		return nil, err
+	}
+	query := map[string]string{"fields": strings.Join(readFields, ",")}
+	if limit > 0 {
+		query["limit"] = strconv.Itoa(limit)
+	}
+
+	items := make([]map[string]any, 0)
+	pagination, err := s.Client.FetchWithPagination(ctx, graph.Request{
+		Method:      "GET",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Query:       query,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, graph.PaginationOptions{
+		FollowNext: followNext,
+		Limit:      limit,
+	}, func(item map[string]any) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &CatalogListResult{
+		Operation:   operation,
+		RequestPath: path,
+		Items:       items,
+		Paging:      pagination,
+	}, nil
+}
+
+func (s *CatalogService) mutate(ctx context.Context, version string, token string, appSecret string, operation string, method string, path string, form map[string]string) (*CatalogMutationResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("catalog service client is required")
+	}
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:      method,
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Form:        form,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+	id, _ := response.Body["id"].(string)
+	if method == "POST" && strings.TrimSpace(id) == "" {
+		return nil, fmt.Errorf("catalog %s response did not include id", strings.ReplaceAll(operation, "_", " "))
+	}
+	return &CatalogMutationResult{
+		Operation:   operation,
+		ID:          id,
+		RequestPath: path,
+		Response:    response.Body,
+	}, nil
+}
+
+func normalizeCatalogFeedSchedule(schedule CatalogFeedSchedule) (map[string]any, error) {
+	interval := strings.ToUpper(strings.TrimSpace(schedule.Interval))
+	switch interval {
+	case CatalogFeedIntervalHourly, CatalogFeedIntervalDaily, CatalogFeedIntervalWeekly:
+	default:
+		return nil, fmt.Errorf("unsupported feed schedule interval %q: expected HOURLY|DAILY|WEEKLY", schedule.Interval)
+	}
+	feedURL, err := normalizeCatalogFeedURL(schedule.URL)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Hour < 0 || schedule.Hour > 23 {
+		return nil, fmt.Errorf("feed schedule hour must be between 0 and 23, got %d", schedule.Hour)
+	}
+
+	normalized := map[string]any{
+		"interval": interval,
+		"url":      feedURL,
+	}
+	if interval != CatalogFeedIntervalHourly {
+		normalized["hour"] = schedule.Hour
+	}
+	weekday := strings.ToUpper(strings.TrimSpace(schedule.Weekday))
+	switch {
+	case interval == CatalogFeedIntervalWeekly && weekday == "":
+		return nil, errors.New("weekly feed schedules require a weekday")
+	case interval != CatalogFeedIntervalWeekly && weekday != "":
+		return nil, errors.New("feed schedule weekday is only valid for WEEKLY interval")
+	case weekday != "":
+		switch weekday {
+		case "MONDAY", "TUESDAY", "WEDNESDAY", "THURSDAY", "FRIDAY", "SATURDAY", "SUNDAY":
+			normalized["day_of_week"] = weekday
+		default:
+			return nil, fmt.Errorf("invalid feed schedule weekday %q", schedule.Weekday)
+		}
+	}
+	return normalized, nil
+}
+
+func normalizeCatalogFeedURL(value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "", errors.New("feed url is required")
+	}
+	parsed, err := url.Parse(trimmed)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http" && parsed.Scheme != "ftp" && parsed.Scheme != "sftp") || parsed.Host == "" {
+		return "", fmt.Errorf("invalid feed url %q: expected an http(s) or (s)ftp url", value)
+	}
+	return trimmed, nil
+}
+
+func normalizeCatalogReadFields(fields []string, defaults []string) ([]string, error) {
+	if len(fields) == 0 {
+		return append([]string(nil), defaults...), nil
+	}
+	normalized := make([]string, 0, len(fields))
+	seen := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		trimmed := strings.TrimSpace(field)
+		if trimmed == "" {
+			return nil, errors.New("catalog fields contain blank entries")
+		}
+		if _, exists := seen[trimmed]; exists {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		normalized = append(normalized, trimmed)
+	}
+	return normalized, nil
+}
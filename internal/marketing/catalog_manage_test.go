@@ -0,0 +1,229 @@
+package marketing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestCatalogListCatalogsReadsBusinessEdge(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+		if r.URL.Path != "/v25.0/biz_1/owned_product_catalogs" {
+			t.Fatalf("unexpected request path %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("fields"); got != "id,name,vertical,product_count" {
+			t.Fatalf("unexpected fields %q", got)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{
+				{"id": "cat_1", "name": "Main", "vertical": "commerce"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	service := NewCatalogService(graph.NewClient(server.Client(), server.URL))
+	result, err := service.ListCatalogs(context.Background(), "v25.0", "token-1", "", CatalogListInput{BusinessID: "biz_1"})
+	if err != nil {
+		t.Fatalf("list catalogs: %v", err)
+	}
+	if result.Operation != "list_catalogs" {
+		t.Fatalf("unexpected operation %q", result.Operation)
+	}
+	if len(result.Items) != 1 || result.Items[0]["id"] != "cat_1" {
+		t.Fatalf("unexpected items %#v", result.Items)
+	}
+}
+
+func TestCatalogCreateFeedEncodesSchedule(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+		if r.URL.Path != "/v25.0/cat_1/product_feeds" {
+			t.Fatalf("unexpected request path %q", r.URL.Path)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("parse form body: %v", err)
+		}
+		if got := form.Get("name"); got != "Nightly" {
+			t.Fatalf("unexpected name %q", got)
+		}
+		var schedule map[string]any
+		if err := json.Unmarshal([]byte(form.Get("schedule")), &schedule); err != nil {
+			t.Fatalf("decode schedule: %v", err)
+		}
+		if schedule["interval"] != "WEEKLY" || schedule["day_of_week"] != "MONDAY" || schedule["hour"] != float64(3) {
+			t.Fatalf("unexpected schedule %#v", schedule)
+		}
+		if schedule["url"] != "https://example.com/feed.csv" {
+			t.Fatalf("unexpected schedule url %#v", schedule["url"])
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "feed_1"})
+	}))
+	defer server.Close()
+
+	service := NewCatalogService(graph.NewClient(server.Client(), server.URL))
+	result, err := service.CreateFeed(context.Background(), "v25.0", "token-1", "", CatalogFeedCreateInput{
+		CatalogID: "cat_1",
+		Name:      "Nightly",
+		Schedule: CatalogFeedSchedule{
+			Interval: "weekly",
+			URL:      "https://example.com/feed.csv",
+			Hour:     3,
+			Weekday:  "monday",
+		},
+	})
+	if err != nil {
+		t.Fatalf("create feed: %v", err)
+	}
+	if result.ID != "feed_1" {
+		t.Fatalf("unexpected feed id %q", result.ID)
+	}
+}
+
+func TestNormalizeCatalogFeedScheduleRejectsInvalidCombinations(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		schedule CatalogFeedSchedule
+		want     string
+	}{
+		{
+			name:     "unknown interval",
+			schedule: CatalogFeedSchedule{Interval: "monthly", URL: "https://example.com/feed.csv"},
+			want:     "unsupported feed schedule interval",
+		},
+		{
+			name:     "weekly without weekday",
+			schedule: CatalogFeedSchedule{Interval: "weekly", URL: "https://example.com/feed.csv"},
+			want:     "require a weekday",
+		},
+		{
+			name:     "weekday on daily",
+			schedule: CatalogFeedSchedule{Interval: "daily", URL: "https://example.com/feed.csv", Weekday: "monday"},
+			want:     "only valid for WEEKLY",
+		},
+		{
+			name:     "hour out of range",
+			schedule: CatalogFeedSchedule{Interval: "daily", URL: "https://example.com/feed.csv", Hour: 24},
+			want:     "between 0 and 23",
+		},
+		{
+			name:     "local file url",
+			schedule: CatalogFeedSchedule{Interval: "daily", URL: "file:///tmp/feed.csv"},
+			want:     "invalid feed url",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := normalizeCatalogFeedSchedule(testCase.schedule)
+			if err == nil {
+				t.Fatal("expected schedule validation error")
+			}
+			if !strings.Contains(err.Error(), testCase.want) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateCatalogBatchRequestsReportsAllViolations(t *testing.T) {
+	t.Parallel()
+
+	violations, err := ValidateCatalogBatchRequests("", []CatalogBatchRequest{
+		{
+			Method:     CatalogBatchMethodCreate,
+			RetailerID: "sku_1",
+			Data: map[string]any{
+				"title":        "Shirt",
+				"description":  "Cotton shirt",
+				"availability": "in stock",
+				"condition":    "new",
+				"price":        "10.00 USD",
+				"link":         "https://example.com/sku_1",
+				"image_link":   "https://example.com/sku_1.png",
+				"brand":        "Acme",
+			},
+		},
+		{
+			Method:     CatalogBatchMethodCreate,
+			RetailerID: "sku_2",
+			Data: map[string]any{
+				"title":     "Hat",
+				"price":     "5",
+				"condition": "mint",
+				"link":      "example.com/hat",
+				"colour":    "red",
+			},
+		},
+		{
+			Method:     CatalogBatchMethodUpdate,
+			RetailerID: "sku_3",
+			Data: map[string]any{
+				"availability": "out of stock",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("validate requests: %v", err)
+	}
+
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		if violation.RetailerID != "sku_2" || violation.Index != 1 {
+			t.Fatalf("unexpected violation target %#v", violation)
+		}
+		messages = append(messages, violation.Message)
+	}
+	joined := strings.Join(messages, "\n")
+	for _, want := range []string{
+		`missing required field "description"`,
+		`missing required field "image_link"`,
+		`unknown field "colour"`,
+		`field "condition" must be one of`,
+		`field "link" must be an absolute http(s) url`,
+		`field "price" must be formatted`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected violation %q in:\n%s", want, joined)
+		}
+	}
+}
+
+func TestValidateCatalogBatchRequestsRejectsUnknownItemType(t *testing.T) {
+	t.Parallel()
+
+	_, err := ValidateCatalogBatchRequests("VEHICLE", []CatalogBatchRequest{
+		{Method: CatalogBatchMethodUpdate, RetailerID: "sku_1", Data: map[string]any{"title": "Car"}},
+	})
+	if err == nil {
+		t.Fatal("expected item type error")
+	}
+	if !strings.Contains(err.Error(), "no catalog item schema") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
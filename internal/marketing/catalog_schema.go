@@ -0,0 +1,158 @@
+package marketing
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CatalogItemSchema is the local field contract used to validate items_batch request
+// files before they are sent.
+type CatalogItemSchema struct {
+	ItemType string
+	Required []string
+	Fields   []string
+	Enums    map[string][]string
+	URLs     []string
+	Prices   []string
+}
+
+var (
+	catalogPricePattern = regexp.MustCompile(`^[0-9]+(\.[0-9]{1,2})? [A-Z]{3}$`)
+
+	catalogItemSchemas = map[string]CatalogItemSchema{
+		defaultCatalogItemType: {
+			ItemType: defaultCatalogItemType,
+			Required: []string{"title", "description", "availability", "condition", "price", "link", "image_link", "brand"},
+			Fields: []string{
+				"title", "description", "availability", "condition", "price", "link", "image_link", "brand",
+				"additional_image_link", "age_group", "color", "custom_label_0", "custom_label_1", "custom_label_2",
+				"custom_label_3", "custom_label_4", "fb_product_category", "gender", "google_product_category",
+				"gtin", "inventory", "item_group_id", "material", "mpn", "pattern", "product_type",
+				"rich_text_description", "sale_price", "sale_price_effective_date", "shipping", "shipping_weight",
+				"size", "status", "video",
+			},
+			Enums: map[string][]string{
+				"availability": {"in stock", "out of stock", "preorder", "available for order", "discontinued"},
+				"condition":    {"new", "refurbished", "used"},
+				"gender":       {"female", "male", "unisex"},
+				"age_group":    {"adult", "all ages", "infant", "kids", "newborn", "teen", "toddler"},
+				"status":       {"active", "archived"},
+			},
+			URLs:   []string{"link", "image_link"},
+			Prices: []string{"price", "sale_price"},
+		},
+	}
+)
+
+// LookupCatalogItemSchema returns the local schema for an item type.
+func LookupCatalogItemSchema(itemType string) (CatalogItemSchema, error) {
+	normalized, err := normalizeCatalogItemType(itemType)
+	if err != nil {
+		return CatalogItemSchema{}, err
+	}
+	schema, ok := catalogItemSchemas[normalized]
+	if !ok {
+		supported := make([]string, 0, len(catalogItemSchemas))
+		for key := range catalogItemSchemas {
+			supported = append(supported, key)
+		}
+		sort.Strings(supported)
+		return CatalogItemSchema{}, fmt.Errorf("no catalog item schema for item type %q (supported: %s)", normalized, strings.Join(supported, ", "))
+	}
+	return schema, nil
+}
+
+// ValidateCatalogBatchRequests checks every request against the item type schema and
+// returns all violations rather than stopping at the first one. CREATE requests must
+// carry every required field; UPDATE/UPSERT only validate the fields they send.
+func ValidateCatalogBatchRequests(itemType string, requests []CatalogBatchRequest) ([]CatalogItemError, error) {
+	schema, err := LookupCatalogItemSchema(itemType)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeCatalogBatchRequests(requests)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]struct{}, len(schema.Fields))
+	for _, field := range schema.Fields {
+		known[field] = struct{}{}
+	}
+	urlFields := make(map[string]struct{}, len(schema.URLs))
+	for _, field := range schema.URLs {
+		urlFields[field] = struct{}{}
+	}
+	priceFields := make(map[string]struct{}, len(schema.Prices))
+	for _, field := range schema.Prices {
+		priceFields[field] = struct{}{}
+	}
+
+	violations := make([]CatalogItemError, 0)
+	report := func(index int, request CatalogBatchRequest, format string, args ...any) {
+		violations = append(violations, CatalogItemError{
+			Index:      index,
+			Method:     request.Method,
+			RetailerID: request.RetailerID,
+			Message:    fmt.Sprintf(format, args...),
+		})
+	}
+	for index, request := range normalized {
+		if request.Method == CatalogBatchMethodCreate {
+			for _, field := range schema.Required {
+				if strings.TrimSpace(catalogString(request.Data[field])) == "" {
+					report(index, request, "missing required field %q", field)
+				}
+			}
+		}
+
+		fields := make([]string, 0, len(request.Data))
+		for field := range request.Data {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			if _, ok := known[field]; !ok {
+				report(index, request, "unknown field %q for item type %s", field, schema.ItemType)
+				continue
+			}
+			value := strings.TrimSpace(catalogString(request.Data[field]))
+			if allowed, ok := schema.Enums[field]; ok && !catalogContains(allowed, strings.ToLower(value)) {
+				report(index, request, "field %q must be one of [%s], got %q", field, strings.Join(allowed, ", "), value)
+			}
+			if _, ok := urlFields[field]; ok {
+				parsed, err := url.Parse(value)
+				if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+					report(index, request, "field %q must be an absolute http(s) url", field)
+				}
+			}
+			if _, ok := priceFields[field]; ok && !catalogPricePattern.MatchString(value) {
+				report(index, request, "field %q must be formatted as \"<amount> <ISO currency>\" (for example \"9.99 USD\"), got %q", field, value)
+			}
+		}
+	}
+	return violations, nil
+}
+
+func catalogString(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+func catalogContains(values []string, candidate string) bool {
+	for _, value := range values {
+		if value == candidate {
+			return true
+		}
+	}
+	return false
+}
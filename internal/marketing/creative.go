@@ -62,6 +62,8 @@ type CreativeMutationResult struct {
 	CreativeID  string         `json:"creative_id"`
 	RequestPath string         `json:"request_path"`
 	Response    map[string]any `json:"response"`
+	// TaggedLinks is set when the spec links were tagged with UTM params.
+	TaggedLinks []TaggedLink `json:"tagged_links,omitempty"`
 }
 
 type CreativeVideoStatusResult struct {
@@ -0,0 +1,349 @@
+package marketing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	CreativeCopyPackSchemaVersion = 1
+
+	// Hard limits: copy over them is an error on every placement.
+	MaxCreativeTitleCharacters       = 255
+	MaxCreativeBodyCharacters        = 2200
+	MaxCreativeDescriptionCharacters = 255
+
+	// CreativeCopyBuiltinPack is the pack name reported when no pack file is
+	// configured.
+	CreativeCopyBuiltinPack = "builtin"
+
+	// A field is shouting when more than this share of its letters, and at
+	// least creativeCopyCapsMinLetters of them, are capitals.
+	creativeCopyCapsRatio      = 0.6
+	creativeCopyCapsMinLetters = 8
+)
+
+var creativeCopyFields = []string{"title", "body", "description"}
+
+var creativeCopyMaxCharacters = map[string]int{
+	"title":       MaxCreativeTitleCharacters,
+	"body":        MaxCreativeBodyCharacters,
+	"description": MaxCreativeDescriptionCharacters,
+}
+
+// creativePlacements holds the length past which each placement truncates a
+// field. Fields a placement does not show are left out.
+var creativePlacements = map[string]map[string]int{
+	"facebook_feed":         {"title": 40, "body": 125, "description": 30},
+	"facebook_right_column": {"title": 40, "body": 125},
+	"facebook_marketplace":  {"title": 40, "body": 125},
+	"instagram_feed":        {"title": 40, "body": 125},
+	"instagram_stories":     {"title": 40, "body": 125},
+	"instagram_reels":       {"title": 40, "body": 72},
+	"messenger_inbox":       {"title": 40, "body": 125},
+	"audience_network":      {"title": 25, "body": 90, "description": 30},
+}
+
+// CreativeCopyInput is the text of an ad creative: title is the headline,
+// body the primary text and description the link description.
+type CreativeCopyInput struct {
+	Title       string
+	Body        string
+	Description string
+}
+
+type CreativeCopyField struct {
+	Field          string `json:"field"`
+	Text           string `json:"text"`
+	CharacterCount int    `json:"character_count"`
+}
+
+type CreativeCopyLintResult struct {
+	Fields     []CreativeCopyField `json:"fields"`
+	Placements []string            `json:"placements"`
+	Pack       string              `json:"pack"`
+	Strict     bool                `json:"strict"`
+	Valid      bool                `json:"valid"`
+	Errors     []string            `json:"errors"`
+	Warnings   []string            `json:"warnings"`
+}
+
+// CreativeCopyPack lists prohibited words and phrases by policy category.
+type CreativeCopyPack struct {
+	SchemaVersion int                    `json:"schema_version"`
+	Categories    []CreativeCopyCategory `json:"categories"`
+	Source        string                 `json:"-"`
+}
+
+type CreativeCopyCategory struct {
+	Name    string   `json:"name"`
+	Words   []string `json:"words,omitempty"`
+	Phrases []string `json:"phrases,omitempty"`
+}
+
+// DefaultCreativeCopyPack flags claims Meta's advertising policies commonly
+// reject. A pack file replaces it rather than extending it.
+func DefaultCreativeCopyPack() *CreativeCopyPack {
+	return &CreativeCopyPack{
+		SchemaVersion: CreativeCopyPackSchemaVersion,
+		Source:        CreativeCopyBuiltinPack,
+		Categories: []CreativeCopyCategory{
+			{Name: "personal_attributes", Phrases: []string{"are you overweight", "are you depressed", "are you in debt", "other singles like you"}},
+			{Name: "health_claims", Words: []string{"miracle"}, Phrases: []string{"lose weight fast", "cure your", "doctors hate"}},
+			{Name: "financial_claims", Phrases: []string{"get rich quick", "guaranteed returns", "guaranteed income", "risk-free investment"}},
+			{Name: "misleading", Phrases: []string{"click here", "you won't believe", "you have won"}},
+		},
+	}
+}
+
+func DefaultCreativeCopyPackPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "creative", "copy_lint.json"), nil
+}
+
+func LoadCreativeCopyPack(path string) (*CreativeCopyPack, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("creative copy pack path is required")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read creative copy pack %s: %w", path, err)
+	}
+
+	var pack CreativeCopyPack
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&pack); err != nil {
+		return nil, fmt.Errorf("decode creative copy pack %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return nil, fmt.Errorf("decode creative copy pack %s: multiple JSON values", path)
+		}
+		return nil, fmt.Errorf("decode creative copy pack %s: %w", path, err)
+	}
+	if pack.SchemaVersion != CreativeCopyPackSchemaVersion {
+		return nil, fmt.Errorf("unsupported creative copy pack schema_version=%d in %s (expected %d)", pack.SchemaVersion, path, CreativeCopyPackSchemaVersion)
+	}
+	if err := pack.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid creative copy pack %s: %w", path, err)
+	}
+	pack.Source = path
+	return &pack, nil
+}
+
+func (p *CreativeCopyPack) Validate() error {
+	seen := map[string]struct{}{}
+	for index, category := range p.Categories {
+		name := strings.TrimSpace(category.Name)
+		if name == "" {
+			return fmt.Errorf("categories[%d].name is required", index)
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("duplicate category %q", name)
+		}
+		seen[name] = struct{}{}
+		if len(category.Words) == 0 && len(category.Phrases) == 0 {
+			return fmt.Errorf("category %q needs words or phrases", name)
+		}
+		for _, word := range category.Words {
+			if len(copyWords(word)) != 1 {
+				return fmt.Errorf("category %q word %q must be a single word", name, word)
+			}
+		}
+		for _, phrase := range category.Phrases {
+			if len(copyWords(phrase)) == 0 {
+				return fmt.Errorf("category %q phrases must not be empty", name)
+			}
+		}
+	}
+	return nil
+}
+
+// CreativePlacements lists the placements LintCreativeCopy knows the limits
+// of.
+func CreativePlacements() []string {
+	names := make([]string, 0, len(creativePlacements))
+	for name := range creativePlacements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NormalizeCreativePlacements lower-cases and deduplicates values; no values
+// selects every placement.
+func NormalizeCreativePlacements(values []string) ([]string, error) {
+	placements := make([]string, 0, len(values))
+	seen := map[string]struct{}{}
+	for _, value := range values {
+		placement := strings.ToLower(strings.TrimSpace(value))
+		if placement == "" {
+			continue
+		}
+		if _, ok := creativePlacements[placement]; !ok {
+			return nil, fmt.Errorf("unsupported placement %q: expected one of %s", value, strings.Join(CreativePlacements(), ", "))
+		}
+		if _, ok := seen[placement]; ok {
+			continue
+		}
+		seen[placement] = struct{}{}
+		placements = append(placements, placement)
+	}
+	if len(placements) == 0 {
+		return CreativePlacements(), nil
+	}
+	return placements, nil
+}
+
+// LintCreativeCopy checks copy against the hard limits, then reports as
+// warnings the placements that truncate it, shouting capitals, repeated
+// punctuation and pack matches; strict turns warnings into errors, as
+// ig caption validate does. A nil pack selects DefaultCreativeCopyPack.
+func LintCreativeCopy(input CreativeCopyInput, placements []string, strict bool, pack *CreativeCopyPack) CreativeCopyLintResult {
+	if pack == nil {
+		pack = DefaultCreativeCopyPack()
+	}
+	result := CreativeCopyLintResult{
+		Fields:     make([]CreativeCopyField, 0, len(creativeCopyFields)),
+		Placements: placements,
+		Pack:       pack.Source,
+		Strict:     strict,
+		Errors:     make([]string, 0, 4),
+		Warnings:   make([]string, 0, 4),
+	}
+
+	texts := map[string]string{"title": input.Title, "body": input.Body, "description": input.Description}
+	for _, field := range creativeCopyFields {
+		text := texts[field]
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		count := utf8.RuneCountInString(text)
+		result.Fields = append(result.Fields, CreativeCopyField{Field: field, Text: text, CharacterCount: count})
+
+		if limit := creativeCopyMaxCharacters[field]; count > limit {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s exceeds %d characters (%d)", field, limit, count))
+		}
+		if truncating := truncatingPlacements(field, count, placements); len(truncating) > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s is %d characters and is truncated on %s", field, count, strings.Join(truncating, ", ")))
+		}
+		if ratio, shouting := shoutingRatio(text); shouting {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s is mostly capital letters (%d%%)", field, int(ratio*100)))
+		}
+		for _, run := range repeatedPunctuation(text) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s repeats punctuation %q", field, run))
+		}
+		result.Warnings = append(result.Warnings, lintCopyPack(field, text, pack)...)
+	}
+	if len(result.Fields) == 0 {
+		result.Errors = append(result.Errors, "at least one of title, body or description is required")
+	}
+
+	if strict && len(result.Warnings) > 0 {
+		for _, warning := range result.Warnings {
+			result.Errors = append(result.Errors, fmt.Sprintf("strict mode: %s", warning))
+		}
+		result.Warnings = []string{}
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+func truncatingPlacements(field string, count int, placements []string) []string {
+	truncating := make([]string, 0)
+	for _, placement := range placements {
+		if limit, ok := creativePlacements[placement][field]; ok && count > limit {
+			truncating = append(truncating, fmt.Sprintf("%s (%d)", placement, limit))
+		}
+	}
+	return truncating
+}
+
+func shoutingRatio(text string) (float64, bool) {
+	letters, upper := 0, 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+	if letters < creativeCopyCapsMinLetters {
+		return 0, false
+	}
+	ratio := float64(upper) / float64(letters)
+	return ratio, ratio > creativeCopyCapsRatio
+}
+
+// repeatedPunctuation returns runs of two or more ! or ? characters, such as
+// "!!" or "?!", once each.
+func repeatedPunctuation(text string) []string {
+	runs := make([]string, 0)
+	seen := map[string]struct{}{}
+	var current strings.Builder
+	flush := func() {
+		if utf8.RuneCountInString(current.String()) >= 2 {
+			run := current.String()
+			if _, ok := seen[run]; !ok {
+				seen[run] = struct{}{}
+				runs = append(runs, run)
+			}
+		}
+		current.Reset()
+	}
+	for _, r := range text {
+		if r == '!' || r == '?' {
+			current.WriteRune(r)
+			continue
+		}
+		flush()
+	}
+	flush()
+	return runs
+}
+
+func lintCopyPack(field string, text string, pack *CreativeCopyPack) []string {
+	warnings := make([]string, 0)
+	words := copyWords(text)
+	normalized := " " + strings.Join(words, " ") + " "
+	for _, category := range pack.Categories {
+		for _, word := range category.Words {
+			if slices.Contains(words, strings.ToLower(strings.TrimSpace(word))) {
+				warnings = append(warnings, fmt.Sprintf("%s contains prohibited word %q (%s)", field, word, category.Name))
+			}
+		}
+		for _, phrase := range category.Phrases {
+			needle := strings.Join(copyWords(phrase), " ")
+			if needle != "" && strings.Contains(normalized, " "+needle+" ") {
+				warnings = append(warnings, fmt.Sprintf("%s contains prohibited phrase %q (%s)", field, phrase, category.Name))
+			}
+		}
+	}
+	return warnings
+}
+
+func copyWords(value string) []string {
+	return strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '-'
+	})
+}
@@ -0,0 +1,106 @@
+package marketing
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintCreativeCopyReportsLimitsHeuristicsAndPackMatches(t *testing.T) {
+	t.Parallel()
+
+	placements, err := NormalizeCreativePlacements([]string{"Facebook_Feed", "instagram_feed", "facebook_feed"})
+	if err != nil {
+		t.Fatalf("normalize placements: %v", err)
+	}
+	input := CreativeCopyInput{
+		Title:       "HUGE SUMMER SALE TODAY!!!",
+		Body:        "Get rich quick with our miracle plan?! " + strings.Repeat("x", 100),
+		Description: "Free shipping",
+	}
+
+	result := LintCreativeCopy(input, placements, false, nil)
+	if !result.Valid || result.Pack != CreativeCopyBuiltinPack {
+		t.Fatalf("expected non-strict findings to stay warnings, got %#v", result)
+	}
+	if len(result.Fields) != 3 || result.Fields[1].Field != "body" || result.Fields[1].CharacterCount != 139 {
+		t.Fatalf("unexpected fields %#v", result.Fields)
+	}
+	warnings := strings.Join(result.Warnings, ";")
+	for _, want := range []string{
+		"title is mostly capital letters (100%)",
+		`title repeats punctuation "!!!"`,
+		"body is 139 characters and is truncated on facebook_feed (125), instagram_feed (125)",
+		`body repeats punctuation "?!"`,
+		`body contains prohibited word "miracle" (health_claims)`,
+		`body contains prohibited phrase "get rich quick" (financial_claims)`,
+	} {
+		if !strings.Contains(warnings, want) {
+			t.Fatalf("expected %q in %q", want, warnings)
+		}
+	}
+	if strings.Contains(warnings, "description") {
+		t.Fatalf("description should pass: %q", warnings)
+	}
+
+	strict := LintCreativeCopy(input, placements, true, nil)
+	if strict.Valid || len(strict.Errors) != len(result.Warnings) || len(strict.Warnings) != 0 {
+		t.Fatalf("expected strict mode to promote warnings, got %#v", strict)
+	}
+}
+
+func TestLintCreativeCopyEnforcesHardLimitsAndRequiresText(t *testing.T) {
+	t.Parallel()
+
+	result := LintCreativeCopy(CreativeCopyInput{Title: strings.Repeat("a", MaxCreativeTitleCharacters+1)}, []string{"audience_network"}, false, nil)
+	if result.Valid || !strings.Contains(strings.Join(result.Errors, ";"), "title exceeds 255 characters (256)") {
+		t.Fatalf("expected hard limit error, got %#v", result.Errors)
+	}
+
+	empty := LintCreativeCopy(CreativeCopyInput{Body: "  "}, CreativePlacements(), false, nil)
+	if empty.Valid || empty.Errors[0] != "at least one of title, body or description is required" {
+		t.Fatalf("expected missing copy error, got %#v", empty.Errors)
+	}
+
+	if _, err := NormalizeCreativePlacements([]string{"tiktok"}); err == nil || !strings.Contains(err.Error(), "expected one of audience_network") {
+		t.Fatalf("expected unsupported placement error, got %v", err)
+	}
+	if all, _ := NormalizeCreativePlacements(nil); len(all) != len(CreativePlacements()) {
+		t.Fatalf("expected every placement by default, got %v", all)
+	}
+}
+
+func TestLoadCreativeCopyPackValidatesFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "copy_lint.json")
+	if err := os.WriteFile(path, []byte(`{"schema_version":1,"categories":[{"name":"brand","words":["cheap"],"phrases":["best in  town"]}]}`), 0o600); err != nil {
+		t.Fatalf("write pack: %v", err)
+	}
+	pack, err := LoadCreativeCopyPack(path)
+	if err != nil {
+		t.Fatalf("load pack: %v", err)
+	}
+	result := LintCreativeCopy(CreativeCopyInput{Body: "Cheap eats, the best in town. Miracle prices."}, []string{"facebook_feed"}, false, pack)
+	if result.Pack != path || len(result.Warnings) != 2 {
+		t.Fatalf("expected the pack to replace the built-in one, got %#v", result)
+	}
+
+	for content, want := range map[string]string{
+		`{"schema_version":2,"categories":[]}`:                                   "unsupported creative copy pack schema_version=2",
+		`{"schema_version":1,"categories":[{"name":"","words":["x"]}]}`:          "categories[0].name is required",
+		`{"schema_version":1,"categories":[{"name":"a"}]}`:                       `category "a" needs words or phrases`,
+		`{"schema_version":1,"categories":[{"name":"a","words":["two words"]}]}`: `word "two words" must be a single word`,
+		`{"schema_version":1,"categories":[],"extra":true}`:                      `unknown field "extra"`,
+	} {
+		invalid := filepath.Join(dir, "invalid.json")
+		if err := os.WriteFile(invalid, []byte(content), 0o600); err != nil {
+			t.Fatalf("write pack: %v", err)
+		}
+		if _, err := LoadCreativeCopyPack(invalid); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: expected error containing %q, got %v", content, want, err)
+		}
+	}
+}
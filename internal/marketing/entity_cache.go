@@ -0,0 +1,224 @@
+package marketing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+)
+
+const (
+	EntityCacheSchemaVersion = 1
+	// MaxCachedEntities bounds each profile/kind list; the most recently seen
+	// entities are kept.
+	MaxCachedEntities = 500
+
+	EntityKindCampaign = "campaign"
+	EntityKindAdSet    = "adset"
+)
+
+var ErrEntityCachePathRequired = errors.New("entity cache path is required")
+
+// EntityCache remembers campaigns and ad sets returned by list commands so shell
+// completion can offer them without calling the Graph API.
+type EntityCache struct {
+	SchemaVersion int                                  `json:"schema_version"`
+	Profiles      map[string]map[string][]CachedEntity `json:"profiles"`
+}
+
+type CachedEntity struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	AccountID  string    `json:"account_id,omitempty"`
+	CampaignID string    `json:"campaign_id,omitempty"`
+	SeenAt     time.Time `json:"seen_at"`
+}
+
+func DefaultEntityCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "cache", "entities.json"), nil
+}
+
+// CachedEntitiesFromItems converts list command items into cache entries. The
+// effective status wins over the configured status because it is what the
+// user sees in Ads Manager.
+func CachedEntitiesFromItems(accountID string, items []map[string]any, now time.Time) []CachedEntity {
+	accountID = strings.TrimPrefix(strings.TrimSpace(accountID), "act_")
+	entities := make([]CachedEntity, 0, len(items))
+	for _, item := range items {
+		id := entityItemStringValue(item, "id")
+		if id == "" {
+			continue
+		}
+		status := entityItemStringValue(item, "effective_status")
+		if status == "" {
+			status = entityItemStringValue(item, "status")
+		}
+		entityAccountID := strings.TrimPrefix(entityItemStringValue(item, "account_id"), "act_")
+		if entityAccountID == "" {
+			entityAccountID = accountID
+		}
+		entities = append(entities, CachedEntity{
+			ID:         id,
+			Name:       entityItemStringValue(item, "name"),
+			Status:     status,
+			AccountID:  entityAccountID,
+			CampaignID: entityItemStringValue(item, "campaign_id"),
+			SeenAt:     now.UTC(),
+		})
+	}
+	return entities
+}
+
+// Upsert merges entities into the profile/kind list by id, newest first.
+func (c *EntityCache) Upsert(profile string, kind string, entities []CachedEntity) {
+	if c.Profiles == nil {
+		c.Profiles = map[string]map[string][]CachedEntity{}
+	}
+	kinds := c.Profiles[profile]
+	if kinds == nil {
+		kinds = map[string][]CachedEntity{}
+		c.Profiles[profile] = kinds
+	}
+
+	byID := make(map[string]CachedEntity, len(kinds[kind])+len(entities))
+	for _, entity := range kinds[kind] {
+		byID[entity.ID] = entity
+	}
+	for _, entity := range entities {
+		byID[entity.ID] = entity
+	}
+	merged := make([]CachedEntity, 0, len(byID))
+	for _, entity := range byID {
+		merged = append(merged, entity)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		if !merged[i].SeenAt.Equal(merged[j].SeenAt) {
+			return merged[i].SeenAt.After(merged[j].SeenAt)
+		}
+		return merged[i].ID < merged[j].ID
+	})
+	if len(merged) > MaxCachedEntities {
+		merged = merged[:MaxCachedEntities]
+	}
+	kinds[kind] = merged
+}
+
+// Entities returns the cached entities of kind for profile.
+func (c EntityCache) Entities(profile string, kind string) []CachedEntity {
+	return c.Profiles[profile][kind]
+}
+
+// RecordEntities loads the cache at path, upserts entities and saves it.
+func RecordEntities(path string, profile string, kind string, entities []CachedEntity) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	cache, err := LoadEntityCache(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		cache = EntityCache{}
+	}
+	cache.Upsert(profile, kind, entities)
+	return SaveEntityCache(path, cache)
+}
+
+func LoadEntityCache(path string) (EntityCache, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return EntityCache{}, ErrEntityCachePathRequired
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return EntityCache{}, fmt.Errorf("read entity cache %s: %w", path, err)
+	}
+
+	var cache EntityCache
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cache); err != nil {
+		return EntityCache{}, fmt.Errorf("decode entity cache %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return EntityCache{}, fmt.Errorf("decode entity cache %s: multiple JSON values", path)
+		}
+		return EntityCache{}, fmt.Errorf("decode entity cache %s: %w", path, err)
+	}
+	if cache.SchemaVersion != EntityCacheSchemaVersion {
+		return EntityCache{}, fmt.Errorf(
+			"unsupported entity cache schema_version=%d in %s (expected %d)",
+			cache.SchemaVersion,
+			path,
+			EntityCacheSchemaVersion,
+		)
+	}
+	if cache.Profiles == nil {
+		cache.Profiles = map[string]map[string][]CachedEntity{}
+	}
+	return cache, nil
+}
+
+func SaveEntityCache(path string, cache EntityCache) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return ErrEntityCachePathRequired
+	}
+	cache.SchemaVersion = EntityCacheSchemaVersion
+	if cache.Profiles == nil {
+		cache.Profiles = map[string]map[string][]CachedEntity{}
+	}
+
+	lock, err := filelock.Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create entity cache directory for %s: %w", path, err)
+	}
+	payload, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode entity cache: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".entities-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp entity cache file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp entity cache file: %w", err)
+	}
+	if err := tmpFile.Chmod(0o600); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("chmod temp entity cache file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp entity cache file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("replace entity cache %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package marketing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+// FieldChange is one field an update sets: the value Meta held before the
+// update and the value sent.
+type FieldChange struct {
+	Field   string `json:"field"`
+	Before  any    `json:"before"`
+	After   any    `json:"after"`
+	Changed bool   `json:"changed"`
+}
+
+// UpdateDiff is the before/after view of an update. Error is set instead of
+// Changes when the current values could not be read; the update still runs.
+type UpdateDiff struct {
+	Changes []FieldChange `json:"changes,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// DiffPreview is embedded in the update inputs of campaigns, ad sets, ads and
+// audiences.
+type DiffPreview struct {
+	// PreviewDiff reads the current value of each param before the update
+	// and reports it in the result's Diff.
+	PreviewDiff bool
+}
+
+// fetchUpdateDiff reads the current values of the fields in form from
+// objectID and compares them with the proposed values. Reads run under
+// --dry-run too, so the diff appears in the plan.
+func fetchUpdateDiff(ctx context.Context, client *graph.Client, version string, token string, appSecret string, objectID string, form map[string]string) *UpdateDiff {
+	fields := make([]string, 0, len(form))
+	for field := range form {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	response, err := client.Do(ctx, graph.Request{
+		Method:      "GET",
+		Path:        objectID,
+		Version:     strings.TrimSpace(version),
+		Query:       map[string]string{"fields": strings.Join(fields, ",")},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return &UpdateDiff{Error: fmt.Sprintf("read current values: %v", err)}
+	}
+	return diffFields(fields, response.Body, form)
+}
+
+func diffFields(fields []string, current map[string]any, form map[string]string) *UpdateDiff {
+	diff := &UpdateDiff{Changes: make([]FieldChange, 0, len(fields))}
+	for _, field := range fields {
+		before := current[field]
+		after := proposedValue(form[field])
+		diff.Changes = append(diff.Changes, FieldChange{
+			Field:   field,
+			Before:  before,
+			After:   after,
+			Changed: !sameValue(before, after),
+		})
+	}
+	return diff
+}
+
+// proposedValue decodes JSON-valued params such as targeting so they compare
+// against, and display like, the object Meta returns. Scalars stay strings.
+func proposedValue(raw string) any {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var decoded any
+		if err := json.Unmarshal([]byte(trimmed), &decoded); err == nil {
+			return decoded
+		}
+	}
+	return raw
+}
+
+// sameValue compares a Graph value with a proposed one. Graph returns budgets
+// and other numbers as strings or numbers depending on the field, so scalars
+// are compared by their string form.
+func sameValue(before any, after any) bool {
+	if before == nil {
+		return false
+	}
+	switch after.(type) {
+	case map[string]any, []any:
+		return reflect.DeepEqual(before, after)
+	}
+	switch typed := before.(type) {
+	case string:
+		return typed == after
+	case float64, bool:
+		encoded, err := json.Marshal(typed)
+		return err == nil && string(encoded) == after
+	default:
+		return false
+	}
+}
@@ -0,0 +1,99 @@
+package marketing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestAdSetUpdatePreviewDiffComparesCurrentValues(t *testing.T) {
+	t.Parallel()
+
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.Method == http.MethodGet {
+			if got := r.URL.Query().Get("fields"); got != "daily_budget,status,targeting" {
+				t.Fatalf("unexpected fields query %q", got)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":           "8100",
+				"daily_budget": "1500",
+				"status":       "ACTIVE",
+				"targeting":    map[string]any{"geo_locations": map[string]any{"countries": []any{"US"}}},
+			})
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	client := graph.NewClient(server.Client(), server.URL)
+	client.MaxRetries = 0
+	result, err := NewAdSetService(client).Update(context.Background(), "v25.0", "token", "", AdSetUpdateInput{
+		AdSetID: "8100",
+		Params: map[string]string{
+			"daily_budget": "2000",
+			"status":       "ACTIVE",
+			"targeting":    `{"geo_locations":{"countries":["US"]}}`,
+		},
+		DiffPreview: DiffPreview{PreviewDiff: true},
+	})
+	if err != nil {
+		t.Fatalf("update ad set: %v", err)
+	}
+	if strings.Join(methods, ",") != "GET,POST" {
+		t.Fatalf("expected a read before the update, got %v", methods)
+	}
+	if result.Diff == nil || result.Diff.Error != "" || len(result.Diff.Changes) != 3 {
+		t.Fatalf("unexpected diff %#v", result.Diff)
+	}
+	changed := map[string]bool{}
+	for _, change := range result.Diff.Changes {
+		changed[change.Field] = change.Changed
+	}
+	if !changed["daily_budget"] || changed["status"] || changed["targeting"] {
+		t.Fatalf("unexpected changed flags %#v", changed)
+	}
+	if before := result.Diff.Changes[0].Before; before != "1500" {
+		t.Fatalf("unexpected daily_budget before value %#v", before)
+	}
+}
+
+func TestUpdatePreviewDiffRecordsReadFailureAndStillUpdates(t *testing.T) {
+	t.Parallel()
+
+	var posted bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"message":"(#100) Tried accessing nonexisting field","type":"OAuthException","code":100}}`))
+			return
+		}
+		posted = true
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	client := graph.NewClient(server.Client(), server.URL)
+	client.MaxRetries = 0
+	result, err := NewCampaignService(client).Update(context.Background(), "v25.0", "token", "", CampaignUpdateInput{
+		CampaignID:  "777",
+		Params:      map[string]string{"special_ad_categories": `["NONE"]`},
+		DiffPreview: DiffPreview{PreviewDiff: true},
+	})
+	if err != nil {
+		t.Fatalf("update campaign: %v", err)
+	}
+	if !posted {
+		t.Fatal("expected the update to be sent")
+	}
+	if result.Diff == nil || !strings.Contains(result.Diff.Error, "nonexisting field") || len(result.Diff.Changes) != 0 {
+		t.Fatalf("expected the read failure in the diff, got %#v", result.Diff)
+	}
+}
@@ -0,0 +1,403 @@
+package marketing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+// UTMPlaceholders are the {{object.field}} names a UTM template may use.
+var UTMPlaceholders = []string{"account.id", "ad.name", "adset.id", "adset.name", "campaign.id", "campaign.name", "creative.name"}
+
+var utmPlaceholderPattern = regexp.MustCompile(`\{\{\s*([a-z_]+\.[a-z_]+)\s*\}\}`)
+
+// creativeLinkFields are the keys of creative specs that hold click-through
+// URLs, such as object_story_spec.link_data.link and
+// asset_feed_spec.link_urls[].website_url.
+var creativeLinkFields = map[string]bool{"link": true, "website_url": true}
+
+// UTMTemplate is a query string such as
+// "utm_source=meta&utm_campaign={{campaign.name}}" whose values may use
+// UTMPlaceholders.
+type UTMTemplate struct {
+	Raw    string
+	params []UTMTag
+}
+
+// UTMTag is one rendered query parameter.
+type UTMTag struct {
+	Key   string
+	Value string
+}
+
+// TaggedLink is a click-through URL of a creative. Original is set when the
+// URL was tagged and Status when it was checked.
+type TaggedLink struct {
+	Path     string `json:"path"`
+	Original string `json:"original,omitempty"`
+	URL      string `json:"url"`
+	Status   int    `json:"status,omitempty"`
+}
+
+func ParseUTMTemplate(raw string) (*UTMTemplate, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(raw), "?")
+	if trimmed == "" {
+		return nil, errors.New("utm template is empty")
+	}
+	template := &UTMTemplate{Raw: raw}
+	seen := map[string]struct{}{}
+	for _, pair := range strings.Split(trimmed, "&") {
+		if pair == "" {
+			continue
+		}
+		rawKey, rawValue, ok := strings.Cut(pair, "=")
+		key, keyErr := url.QueryUnescape(rawKey)
+		value, valueErr := url.QueryUnescape(rawValue)
+		if !ok || strings.TrimSpace(key) == "" || keyErr != nil || valueErr != nil {
+			return nil, fmt.Errorf("utm template parameter %q must be key=value", pair)
+		}
+		if _, duplicate := seen[key]; duplicate {
+			return nil, fmt.Errorf("utm template sets %q twice", key)
+		}
+		seen[key] = struct{}{}
+		for _, match := range utmPlaceholderPattern.FindAllStringSubmatch(value, -1) {
+			if !containsUTMPlaceholder(match[1]) {
+				return nil, fmt.Errorf("utm template placeholder %s is not supported: expected one of {{%s}}", match[0], strings.Join(UTMPlaceholders, "}}, {{"))
+			}
+		}
+		if rest := utmPlaceholderPattern.ReplaceAllString(value, ""); strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+			return nil, fmt.Errorf("utm template parameter %q has a malformed placeholder", key)
+		}
+		template.params = append(template.params, UTMTag{Key: key, Value: value})
+	}
+	if len(template.params) == 0 {
+		return nil, errors.New("utm template is empty")
+	}
+	return template, nil
+}
+
+// Placeholders lists the placeholders the template uses, sorted.
+func (t *UTMTemplate) Placeholders() []string {
+	seen := map[string]struct{}{}
+	for _, param := range t.params {
+		for _, match := range utmPlaceholderPattern.FindAllStringSubmatch(param.Value, -1) {
+			seen[match[1]] = struct{}{}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render fills the placeholders from vars. A placeholder without a non-empty
+// value is an error, so no link is tagged with a blank campaign.
+func (t *UTMTemplate) Render(vars map[string]string) ([]UTMTag, error) {
+	tags := make([]UTMTag, 0, len(t.params))
+	for _, param := range t.params {
+		var missing []string
+		value := utmPlaceholderPattern.ReplaceAllStringFunc(param.Value, func(placeholder string) string {
+			name := utmPlaceholderPattern.FindStringSubmatch(placeholder)[1]
+			resolved := strings.TrimSpace(vars[name])
+			if resolved == "" {
+				missing = append(missing, name)
+			}
+			return resolved
+		})
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("utm template placeholder {{%s}} has no value; pass --utm-var %s=<value>", missing[0], missing[0])
+		}
+		tags = append(tags, UTMTag{Key: param.Key, Value: value})
+	}
+	return tags, nil
+}
+
+// TagURL sets tags on the query of link, replacing parameters of the same
+// name, and re-encodes the whole query the same way: keys and values
+// percent-encoded, spaces as %20. The fragment is kept.
+func TagURL(link string, tags []UTMTag) (string, error) {
+	parsed, err := parseLinkURL(link)
+	if err != nil {
+		return "", err
+	}
+	existing, err := parseLinkQuery(parsed.RawQuery)
+	if err != nil {
+		return "", fmt.Errorf("link %q: %w", link, err)
+	}
+	replaced := map[string]struct{}{}
+	for _, tag := range tags {
+		replaced[tag.Key] = struct{}{}
+	}
+	pairs := make([]string, 0, len(existing)+len(tags))
+	for _, param := range existing {
+		if _, ok := replaced[param.Key]; !ok {
+			pairs = append(pairs, encodeQueryComponent(param.Key)+"="+encodeQueryComponent(param.Value))
+		}
+	}
+	for _, tag := range tags {
+		pairs = append(pairs, encodeQueryComponent(tag.Key)+"="+encodeQueryComponent(tag.Value))
+	}
+	parsed.RawQuery = strings.Join(pairs, "&")
+	parsed.ForceQuery = false
+	return parsed.String(), nil
+}
+
+// MissingUTMTags returns the tags, as key=value, that link does not carry
+// with the same value.
+func MissingUTMTags(link string, tags []UTMTag) ([]string, error) {
+	parsed, err := parseLinkURL(link)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := parseLinkQuery(parsed.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("link %q: %w", link, err)
+	}
+	values := map[string]string{}
+	for _, param := range existing {
+		values[param.Key] = param.Value
+	}
+	missing := make([]string, 0)
+	for _, tag := range tags {
+		if value, ok := values[tag.Key]; !ok || value != tag.Value {
+			missing = append(missing, tag.Key+"="+tag.Value)
+		}
+	}
+	return missing, nil
+}
+
+// TagCreativeSpec tags every link in spec, a JSON object such as an
+// object_story_spec, and returns the re-encoded spec with the links under
+// their JSON paths below prefix.
+func TagCreativeSpec(prefix string, spec string, tags []UTMTag) (string, []TaggedLink, error) {
+	decoder := json.NewDecoder(strings.NewReader(spec))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "", nil, fmt.Errorf("decode %s: %w", prefix, err)
+	}
+	links := make([]TaggedLink, 0)
+	err := walkLinks(value, prefix, func(path string, link string) (string, error) {
+		taggedURL, err := TagURL(link, tags)
+		if err != nil {
+			return "", err
+		}
+		links = append(links, TaggedLink{Path: path, Original: link, URL: taggedURL})
+		return taggedURL, nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", nil, fmt.Errorf("encode %s: %w", prefix, err)
+	}
+	return strings.TrimSuffix(out.String(), "\n"), links, nil
+}
+
+// walkLinks calls visit for every link field in value, in key order, and
+// stores what it returns in place of the link.
+func walkLinks(value any, path string, visit func(path string, link string) (string, error)) error {
+	switch typed := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			childPath := path + "." + key
+			if link, ok := typed[key].(string); ok && creativeLinkFields[key] {
+				replaced, err := visit(childPath, link)
+				if err != nil {
+					return fmt.Errorf("%s: %w", childPath, err)
+				}
+				typed[key] = replaced
+				continue
+			}
+			if err := walkLinks(typed[key], childPath, visit); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for index, item := range typed {
+			if err := walkLinks(item, fmt.Sprintf("%s[%d]", path, index), visit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CheckLinks requests every link with HEAD, falling back to GET for servers
+// that refuse HEAD, and fails on the first link that does not resolve to a
+// status below 400 after redirects.
+func CheckLinks(ctx context.Context, client *http.Client, links []TaggedLink) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	statuses := map[string]int{}
+	for index := range links {
+		link := &links[index]
+		status, ok := statuses[link.URL]
+		if !ok {
+			var err error
+			status, err = checkLink(ctx, client, link.URL)
+			if err != nil {
+				return fmt.Errorf("link %s (%s) does not resolve: %w", link.Path, link.URL, err)
+			}
+			statuses[link.URL] = status
+		}
+		link.Status = status
+		if status >= http.StatusBadRequest {
+			return fmt.Errorf("link %s (%s) does not resolve: HTTP %d", link.Path, link.URL, status)
+		}
+	}
+	return nil
+}
+
+func checkLink(ctx context.Context, client *http.Client, link string) (int, error) {
+	status := 0
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		request, err := http.NewRequestWithContext(ctx, method, link, nil)
+		if err != nil {
+			return 0, err
+		}
+		response, err := client.Do(request)
+		if err != nil {
+			return 0, err
+		}
+		response.Body.Close()
+		status = response.StatusCode
+		if status != http.StatusMethodNotAllowed && status != http.StatusNotImplemented {
+			break
+		}
+	}
+	return status, nil
+}
+
+// UTMVars reads the names a UTM template can use from an ad set and its
+// campaign.
+func (s *AdSetService) UTMVars(ctx context.Context, version string, token string, appSecret string, adSetID string) (map[string]string, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("ad set service client is required")
+	}
+	normalizedAdSetID, err := normalizeGraphID("ad set id", adSetID)
+	if err != nil {
+		return nil, err
+	}
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:  "GET",
+		Path:    normalizedAdSetID,
+		Version: strings.TrimSpace(version),
+		Query: map[string]string{
+			"fields": "id,name,campaign{id,name}",
+		},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read utm context of ad set %q: %w", normalizedAdSetID, err)
+	}
+	vars := map[string]string{"adset.id": normalizedAdSetID}
+	if name, ok := response.Body["name"].(string); ok {
+		vars["adset.name"] = name
+	}
+	if campaign, ok := response.Body["campaign"].(map[string]any); ok {
+		if id, ok := campaign["id"].(string); ok {
+			vars["campaign.id"] = id
+		}
+		if name, ok := campaign["name"].(string); ok {
+			vars["campaign.name"] = name
+		}
+	}
+	return vars, nil
+}
+
+// CreativeLinks reads the click-through URLs of the creative an ad's
+// creative param references.
+func (s *AdService) CreativeLinks(ctx context.Context, version string, token string, appSecret string, creativeRef string) (string, []TaggedLink, error) {
+	if s == nil || s.Client == nil {
+		return "", nil, errors.New("ad service client is required")
+	}
+	creativeID, err := extractCreativeReferenceID(creativeRef)
+	if err != nil {
+		return "", nil, err
+	}
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:  "GET",
+		Path:    creativeID,
+		Version: strings.TrimSpace(version),
+		Query: map[string]string{
+			"fields": "object_story_spec,asset_feed_spec",
+		},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("read links of creative %q: %w", creativeID, err)
+	}
+	links := make([]TaggedLink, 0)
+	for _, field := range []string{"object_story_spec", "asset_feed_spec"} {
+		_ = walkLinks(response.Body[field], field, func(path string, link string) (string, error) {
+			links = append(links, TaggedLink{Path: path, URL: link})
+			return link, nil
+		})
+	}
+	return creativeID, links, nil
+}
+
+func parseLinkURL(link string) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return nil, fmt.Errorf("link %q is not a valid URL: %w", link, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("link %q must be an absolute http(s) URL", link)
+	}
+	return parsed, nil
+}
+
+// parseLinkQuery splits a raw query into decoded pairs in their original
+// order; url.ParseQuery would lose the order.
+func parseLinkQuery(raw string) ([]UTMTag, error) {
+	params := make([]UTMTag, 0)
+	for _, pair := range strings.Split(raw, "&") {
+		if pair == "" {
+			continue
+		}
+		rawKey, rawValue, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query parameter %q: %w", pair, err)
+		}
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query parameter %q: %w", pair, err)
+		}
+		params = append(params, UTMTag{Key: key, Value: value})
+	}
+	return params, nil
+}
+
+func encodeQueryComponent(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+func containsUTMPlaceholder(name string) bool {
+	return slices.Contains(UTMPlaceholders, name)
+}
@@ -0,0 +1,172 @@
+package marketing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestUTMTemplateRendersAndTagsURLsWithConsistentEncoding(t *testing.T) {
+	t.Parallel()
+
+	template, err := ParseUTMTemplate("?utm_source=meta&utm_medium=paid%20social&utm_campaign={{ campaign.name }}&utm_content={{ad.name}}")
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	if got := template.Placeholders(); !reflect.DeepEqual(got, []string{"ad.name", "campaign.name"}) {
+		t.Fatalf("unexpected placeholders %v", got)
+	}
+	if _, err := template.Render(map[string]string{"campaign.name": "Summer"}); err == nil || !strings.Contains(err.Error(), "{{ad.name}} has no value; pass --utm-var ad.name=<value>") {
+		t.Fatalf("expected missing placeholder error, got %v", err)
+	}
+	tags, err := template.Render(map[string]string{"campaign.name": "Summer Sale & More", "ad.name": "Ad/1"})
+	if err != nil {
+		t.Fatalf("render template: %v", err)
+	}
+
+	tagged, err := TagURL("https://shop.example.com/p?a=1+2&utm_source=old&b=%C3%A9#top", tags)
+	if err != nil {
+		t.Fatalf("tag url: %v", err)
+	}
+	want := "https://shop.example.com/p?a=1%202&b=%C3%A9&utm_source=meta&utm_medium=paid%20social&utm_campaign=Summer%20Sale%20%26%20More&utm_content=Ad%2F1#top"
+	if tagged != want {
+		t.Fatalf("tagged url\n got %s\nwant %s", tagged, want)
+	}
+	if again, _ := TagURL(tagged, tags); again != tagged {
+		t.Fatalf("tagging is not idempotent: %s", again)
+	}
+	if missing, _ := MissingUTMTags(tagged, tags); len(missing) != 0 {
+		t.Fatalf("unexpected missing tags %v", missing)
+	}
+	if missing, _ := MissingUTMTags("https://shop.example.com/p?utm_source=meta", tags); len(missing) != 3 || missing[0] != "utm_medium=paid social" {
+		t.Fatalf("unexpected missing tags %v", missing)
+	}
+	if _, err := TagURL("/relative", tags); err == nil || !strings.Contains(err.Error(), "absolute http(s) URL") {
+		t.Fatalf("expected relative link error, got %v", err)
+	}
+}
+
+func TestParseUTMTemplateRejectsInvalidTemplates(t *testing.T) {
+	t.Parallel()
+
+	for raw, want := range map[string]string{
+		"":                                 "utm template is empty",
+		"utm_source":                       `"utm_source" must be key=value`,
+		"utm_source=a&utm_source=b":        `sets "utm_source" twice`,
+		"utm_campaign={{campaign.budget}}": "placeholder {{campaign.budget}} is not supported",
+		"utm_campaign={{campaign.name}":    "malformed placeholder",
+		"utm_campaign=%zz":                 "must be key=value",
+	} {
+		if _, err := ParseUTMTemplate(raw); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%q: expected error containing %q, got %v", raw, want, err)
+		}
+	}
+}
+
+func TestTagCreativeSpecTagsEveryLink(t *testing.T) {
+	t.Parallel()
+
+	tags := []UTMTag{{Key: "utm_source", Value: "meta"}}
+	spec := `{"page_id":"1","link_data":{"link":"https://a.example.com","message":"Hi & welcome","call_to_action":{"type":"SHOP_NOW","value":{"link":"https://b.example.com/?x=1"}},"child_attachments":[{"link":"https://c.example.com","picture_height":1080}]}}`
+	tagged, links, err := TagCreativeSpec("object_story_spec", spec, tags)
+	if err != nil {
+		t.Fatalf("tag spec: %v", err)
+	}
+	paths := []string{}
+	for _, link := range links {
+		paths = append(paths, link.Path+" "+link.URL)
+	}
+	wantPaths := []string{
+		"object_story_spec.link_data.call_to_action.value.link https://b.example.com/?x=1&utm_source=meta",
+		"object_story_spec.link_data.child_attachments[0].link https://c.example.com?utm_source=meta",
+		"object_story_spec.link_data.link https://a.example.com?utm_source=meta",
+	}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Fatalf("unexpected links %v", paths)
+	}
+	if !strings.Contains(tagged, `"picture_height":1080`) || !strings.Contains(tagged, `"message":"Hi & welcome"`) {
+		t.Fatalf("spec was not re-encoded faithfully: %s", tagged)
+	}
+
+	if _, _, err := TagCreativeSpec("object_story_spec", `{"link_data":{"link":"ftp://a"}}`, tags); err == nil || !strings.Contains(err.Error(), "object_story_spec.link_data.link") {
+		t.Fatalf("expected bad link error naming its path, got %v", err)
+	}
+}
+
+func TestCheckLinksFallsBackToGETAndFailsOnBrokenLinks(t *testing.T) {
+	t.Parallel()
+
+	methods := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/no-head" && r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.URL.Path == "/gone":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	links := []TaggedLink{{Path: "a", URL: server.URL + "/ok"}, {Path: "b", URL: server.URL + "/no-head"}, {Path: "c", URL: server.URL + "/ok"}}
+	if err := CheckLinks(context.Background(), server.Client(), links); err != nil {
+		t.Fatalf("check links: %v", err)
+	}
+	if links[0].Status != http.StatusOK || links[1].Status != http.StatusOK || links[2].Status != http.StatusOK {
+		t.Fatalf("unexpected statuses %+v", links)
+	}
+	if want := []string{"HEAD /ok", "HEAD /no-head", "GET /no-head"}; !reflect.DeepEqual(methods, want) {
+		t.Fatalf("unexpected requests %v", methods)
+	}
+
+	err := CheckLinks(context.Background(), server.Client(), []TaggedLink{{Path: "object_story_spec.link_data.link", URL: server.URL + "/gone"}})
+	if err == nil || !strings.Contains(err.Error(), "object_story_spec.link_data.link") || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Fatalf("expected broken link error, got %v", err)
+	}
+}
+
+func TestUTMVarsAndCreativeLinksReadGraph(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v25.0/adset_1":
+			if r.URL.Query().Get("fields") != "id,name,campaign{id,name}" {
+				t.Fatalf("unexpected fields %q", r.URL.Query().Get("fields"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "adset_1", "name": "Prospecting", "campaign": map[string]any{"id": "cmp_1", "name": "Summer Sale"}})
+		case "/v25.0/creative_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"object_story_spec": map[string]any{"link_data": map[string]any{"link": "https://a.example.com?utm_source=meta"}},
+				"asset_feed_spec":   map[string]any{"link_urls": []any{map[string]any{"website_url": "https://b.example.com"}}},
+			})
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := graph.NewClient(server.Client(), server.URL)
+
+	vars, err := NewAdSetService(client).UTMVars(context.Background(), "v25.0", "token", "", "adset_1")
+	if err != nil {
+		t.Fatalf("utm vars: %v", err)
+	}
+	want := map[string]string{"adset.id": "adset_1", "adset.name": "Prospecting", "campaign.id": "cmp_1", "campaign.name": "Summer Sale"}
+	if !reflect.DeepEqual(vars, want) {
+		t.Fatalf("unexpected vars %v", vars)
+	}
+
+	creativeID, links, err := NewAdService(client).CreativeLinks(context.Background(), "v25.0", "token", "", `{"creative_id":"creative_1"}`)
+	if err != nil {
+		t.Fatalf("creative links: %v", err)
+	}
+	if creativeID != "creative_1" || len(links) != 2 || links[0].Path != "object_story_spec.link_data.link" || links[1].Path != "asset_feed_spec.link_urls[0].website_url" {
+		t.Fatalf("unexpected links %s %+v", creativeID, links)
+	}
+}
@@ -0,0 +1,285 @@
+// Package metrics keeps opt-in, local usage aggregates: per command, how often
+// it ran, how long it took and which error classes it failed with. Nothing is
+// recorded until metrics are enabled, nothing identifying (arguments, profile
+// names, ids) is stored, and aggregates leave the machine only through Push to
+// an endpoint the user configured.
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+	"github.com/bilalbayram/metacli/internal/transport"
+)
+
+const SchemaVersion = 1
+
+var ErrPathRequired = errors.New("metrics path is required")
+
+// CommandStats aggregates every recorded run of one command path.
+type CommandStats struct {
+	Invocations     int64            `json:"invocations"`
+	Errors          int64            `json:"errors"`
+	TotalDurationMS int64            `json:"total_duration_ms"`
+	MaxDurationMS   int64            `json:"max_duration_ms"`
+	ErrorClasses    map[string]int64 `json:"error_classes,omitempty"`
+	LastRunAt       string           `json:"last_run_at"`
+}
+
+// State is the metrics file: the opt-in settings and the aggregates collected
+// since Since.
+type State struct {
+	SchemaVersion int                     `json:"schema_version"`
+	Enabled       bool                    `json:"enabled"`
+	Endpoint      string                  `json:"endpoint,omitempty"`
+	Since         string                  `json:"since,omitempty"`
+	LastPushedAt  string                  `json:"last_pushed_at,omitempty"`
+	Commands      map[string]CommandStats `json:"commands"`
+}
+
+// Invocation is one finished command. ErrorClass is empty on success.
+type Invocation struct {
+	Command    string
+	Duration   time.Duration
+	ErrorClass string
+	At         time.Time
+}
+
+// Report is what Push sends: the aggregates and the window they cover.
+type Report struct {
+	SchemaVersion int                     `json:"schema_version"`
+	Since         string                  `json:"since"`
+	Until         string                  `json:"until"`
+	Commands      map[string]CommandStats `json:"commands"`
+}
+
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "metrics.json"), nil
+}
+
+func newState() State {
+	return State{SchemaVersion: SchemaVersion, Commands: map[string]CommandStats{}}
+}
+
+// Load reads the metrics file. A missing file is a disabled, empty state.
+func Load(path string) (State, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return State{}, ErrPathRequired
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return newState(), nil
+		}
+		return State{}, fmt.Errorf("read metrics %s: %w", path, err)
+	}
+
+	state := State{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&state); err != nil {
+		return State{}, fmt.Errorf("decode metrics %s: %w", path, err)
+	}
+	if state.SchemaVersion != SchemaVersion {
+		return State{}, fmt.Errorf("unsupported metrics schema_version=%d (expected %d)", state.SchemaVersion, SchemaVersion)
+	}
+	if state.Commands == nil {
+		state.Commands = map[string]CommandStats{}
+	}
+	return state, nil
+}
+
+// Update applies fn to the metrics file under its lock and saves the result.
+func Update(path string, fn func(*State) error) (State, error) {
+	lock, err := filelock.Acquire(path)
+	if err != nil {
+		return State{}, err
+	}
+	defer lock.Release()
+
+	state, err := Load(path)
+	if err != nil {
+		return State{}, err
+	}
+	if err := fn(&state); err != nil {
+		return State{}, err
+	}
+	if err := save(path, state); err != nil {
+		return State{}, err
+	}
+	return state, nil
+}
+
+// Record adds one invocation to the aggregates. It does nothing, and writes
+// nothing, while metrics are disabled.
+func Record(path string, invocation Invocation) error {
+	state, err := Load(path)
+	if err != nil || !state.Enabled {
+		return err
+	}
+	command := strings.TrimSpace(invocation.Command)
+	if command == "" {
+		return nil
+	}
+	_, err = Update(path, func(state *State) error {
+		if !state.Enabled {
+			return nil
+		}
+		at := invocation.At.UTC().Format(time.RFC3339)
+		if state.Since == "" {
+			state.Since = at
+		}
+		stats := state.Commands[command]
+		stats.Invocations++
+		durationMS := invocation.Duration.Milliseconds()
+		stats.TotalDurationMS += durationMS
+		if durationMS > stats.MaxDurationMS {
+			stats.MaxDurationMS = durationMS
+		}
+		if invocation.ErrorClass != "" {
+			stats.Errors++
+			if stats.ErrorClasses == nil {
+				stats.ErrorClasses = map[string]int64{}
+			}
+			stats.ErrorClasses[invocation.ErrorClass]++
+		}
+		stats.LastRunAt = at
+		state.Commands[command] = stats
+		return nil
+	})
+	return err
+}
+
+// Reset clears the aggregates and keeps the settings.
+func (s *State) Reset() {
+	s.Commands = map[string]CommandStats{}
+	s.Since = ""
+}
+
+// CommandNames returns the recorded command paths in order.
+func (s State) CommandNames() []string {
+	names := make([]string, 0, len(s.Commands))
+	for name := range s.Commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateEndpoint accepts an absolute http or https URL.
+func ValidateEndpoint(raw string) error {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return fmt.Errorf("invalid metrics endpoint %q: %w", raw, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid metrics endpoint %q: expected an absolute http or https URL", raw)
+	}
+	return nil
+}
+
+// Push posts the aggregates to the configured endpoint as JSON and, once the
+// endpoint accepts them, resets the counters so the next push sends only new
+// runs.
+func Push(ctx context.Context, client HTTPClient, path string, now time.Time) (Report, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second, Transport: transport.RefuseOffline(nil)}
+	}
+	report := Report{}
+	_, err := Update(path, func(state *State) error {
+		if strings.TrimSpace(state.Endpoint) == "" {
+			return errors.New("no metrics endpoint configured; run `meta metrics enable --endpoint <url>`")
+		}
+		report = Report{
+			SchemaVersion: SchemaVersion,
+			Since:         state.Since,
+			Until:         now.UTC().Format(time.RFC3339),
+			Commands:      state.Commands,
+		}
+		if err := post(ctx, client, state.Endpoint, report); err != nil {
+			return err
+		}
+		state.Reset()
+		state.LastPushedAt = report.Until
+		return nil
+	})
+	return report, err
+}
+
+func post(ctx context.Context, client HTTPClient, endpoint string, report Report) error {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("marshal metrics report: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build metrics request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("push metrics to %s: %w", endpoint, err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64*1024))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("push metrics to %s: endpoint returned status %d", endpoint, res.StatusCode)
+	}
+	return nil
+}
+
+func save(path string, state State) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create metrics directory for %s: %w", path, err)
+	}
+
+	payload, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal metrics: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".metrics-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp metrics file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp metrics file: %w", err)
+	}
+	if err := tmpFile.Chmod(0o600); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("chmod temp metrics file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp metrics file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("replace metrics %s: %w", path, err)
+	}
+	return nil
+}
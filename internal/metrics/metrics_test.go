@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type stubHTTPClient struct {
+	status int
+	body   []byte
+}
+
+func (c *stubHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.body = body
+	return &http.Response{StatusCode: c.status, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestRecordWritesNothingWhileDisabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	if err := Record(path, Invocation{Command: "meta api get", Duration: time.Second, At: time.Now()}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no metrics file while disabled, got %v", err)
+	}
+}
+
+func TestRecordAggregatesPerCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	if _, err := Update(path, func(state *State) error {
+		state.Enabled = true
+		return nil
+	}); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, invocation := range []Invocation{
+		{Command: "meta api get", Duration: 100 * time.Millisecond, At: at},
+		{Command: "meta api get", Duration: 300 * time.Millisecond, ErrorClass: "rate_limit", At: at.Add(time.Minute)},
+		{Command: "meta auth list", Duration: 5 * time.Millisecond, At: at},
+	} {
+		if err := Record(path, invocation); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	state, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	stats := state.Commands["meta api get"]
+	if stats.Invocations != 2 || stats.Errors != 1 || stats.TotalDurationMS != 400 || stats.MaxDurationMS != 300 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if stats.ErrorClasses["rate_limit"] != 1 || stats.LastRunAt != "2026-03-01T12:01:00Z" {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if state.Since != "2026-03-01T12:00:00Z" || strings.Join(state.CommandNames(), ",") != "meta api get,meta auth list" {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+}
+
+func TestPushSendsAggregatesAndResetsCounters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	if _, err := Update(path, func(state *State) error {
+		state.Enabled = true
+		return nil
+	}); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	if err := Record(path, Invocation{Command: "meta api get", Duration: time.Second, At: time.Now()}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	client := &stubHTTPClient{status: http.StatusOK}
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := Push(context.Background(), client, path, now); err == nil || !strings.Contains(err.Error(), "no metrics endpoint configured") {
+		t.Fatalf("expected missing endpoint error, got %v", err)
+	}
+
+	if _, err := Update(path, func(state *State) error {
+		state.Endpoint = "https://metrics.example.com/meta"
+		return nil
+	}); err != nil {
+		t.Fatalf("set endpoint: %v", err)
+	}
+	client.status = http.StatusInternalServerError
+	if _, err := Push(context.Background(), client, path, now); err == nil {
+		t.Fatal("expected push to fail on 500")
+	}
+	if state, _ := Load(path); state.Commands["meta api get"].Invocations != 1 {
+		t.Fatalf("expected counters to survive a failed push, got %+v", state.Commands)
+	}
+
+	client.status = http.StatusAccepted
+	if _, err := Push(context.Background(), client, path, now); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+	report := Report{}
+	if err := json.Unmarshal(client.body, &report); err != nil {
+		t.Fatalf("decode pushed report: %v", err)
+	}
+	if report.Until != "2026-03-02T00:00:00Z" || report.Commands["meta api get"].Invocations != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	state, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(state.Commands) != 0 || state.LastPushedAt != report.Until || !state.Enabled {
+		t.Fatalf("expected counters reset after push, got %+v", state)
+	}
+}
+
+func TestValidateEndpoint(t *testing.T) {
+	if err := ValidateEndpoint("https://metrics.example.com/meta"); err != nil {
+		t.Fatalf("expected https endpoint to pass: %v", err)
+	}
+	for _, raw := range []string{"metrics.example.com", "ftp://example.com", "/relative"} {
+		if err := ValidateEndpoint(raw); err == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
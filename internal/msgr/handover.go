@@ -0,0 +1,166 @@
+package msgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	HandoverActionPass    = "pass"
+	HandoverActionTake    = "take"
+	HandoverActionRequest = "request"
+)
+
+// handoverEdges maps handover actions to their page-scoped endpoints.
+var handoverEdges = map[string]string{
+	HandoverActionPass:    "me/pass_thread_control",
+	HandoverActionTake:    "me/take_thread_control",
+	HandoverActionRequest: "me/request_thread_control",
+}
+
+type HandoverOptions struct {
+	Action      string
+	RecipientID string
+	TargetAppID string
+	Metadata    string
+}
+
+type HandoverResult struct {
+	Action      string         `json:"action"`
+	RecipientID string         `json:"recipient_id"`
+	TargetAppID string         `json:"target_app_id,omitempty"`
+	Response    map[string]any `json:"response"`
+}
+
+type ThreadOwnerResult struct {
+	RecipientID string         `json:"recipient_id"`
+	OwnerAppID  string         `json:"owner_app_id,omitempty"`
+	Response    map[string]any `json:"response"`
+}
+
+func (s *Service) Handover(ctx context.Context, version string, token string, appSecret string, options HandoverOptions) (*HandoverResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("messenger service client is required")
+	}
+
+	req, err := BuildHandoverRequest(version, token, appSecret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.Client.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if success, ok := response.Body["success"].(bool); ok && !success {
+		return nil, fmt.Errorf("messenger handover %s response was not successful", strings.ToLower(strings.TrimSpace(options.Action)))
+	}
+
+	return &HandoverResult{
+		Action:      strings.ToLower(strings.TrimSpace(options.Action)),
+		RecipientID: strings.TrimSpace(options.RecipientID),
+		TargetAppID: strings.TrimSpace(options.TargetAppID),
+		Response:    response.Body,
+	}, nil
+}
+
+func (s *Service) ThreadOwner(ctx context.Context, version string, token string, appSecret string, recipientID string) (*ThreadOwnerResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("messenger service client is required")
+	}
+
+	req, err := BuildThreadOwnerRequest(version, token, appSecret, recipientID)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.Client.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ThreadOwnerResult{
+		RecipientID: strings.TrimSpace(recipientID),
+		Response:    response.Body,
+	}
+	if data, ok := response.Body["data"].([]any); ok && len(data) > 0 {
+		if entry, ok := data[0].(map[string]any); ok {
+			if owner, ok := entry["thread_owner"].(map[string]any); ok {
+				result.OwnerAppID = fmt.Sprint(owner["app_id"])
+			}
+		}
+	}
+	return result, nil
+}
+
+// BuildHandoverRequest shapes a handover protocol call. Passing control requires
+// the target app id; take and request act on behalf of the calling app.
+func BuildHandoverRequest(version string, token string, appSecret string, options HandoverOptions) (graph.Request, error) {
+	action := strings.ToLower(strings.TrimSpace(options.Action))
+	path, ok := handoverEdges[action]
+	if !ok {
+		return graph.Request{}, fmt.Errorf("unsupported handover action %q: expected pass|take|request", options.Action)
+	}
+
+	recipientID := strings.TrimSpace(options.RecipientID)
+	if recipientID == "" {
+		return graph.Request{}, errors.New("recipient id is required")
+	}
+
+	targetAppID := strings.TrimSpace(options.TargetAppID)
+	switch {
+	case action == HandoverActionPass && targetAppID == "":
+		return graph.Request{}, errors.New("target app id is required to pass thread control")
+	case action != HandoverActionPass && targetAppID != "":
+		return graph.Request{}, fmt.Errorf("target app id is only supported when passing thread control")
+	}
+
+	recipientPayload, err := marshalJSONFormValue(map[string]string{"id": recipientID})
+	if err != nil {
+		return graph.Request{}, fmt.Errorf("encode recipient payload: %w", err)
+	}
+
+	form := map[string]string{
+		"recipient": recipientPayload,
+	}
+	if targetAppID != "" {
+		form["target_app_id"] = targetAppID
+	}
+	if metadata := strings.TrimSpace(options.Metadata); metadata != "" {
+		if len(metadata) > 1000 {
+			return graph.Request{}, errors.New("handover metadata must be at most 1000 characters")
+		}
+		form["metadata"] = metadata
+	}
+
+	return graph.Request{
+		Method:      "POST",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Form:        form,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, nil
+}
+
+func BuildThreadOwnerRequest(version string, token string, appSecret string, recipientID string) (graph.Request, error) {
+	recipientID = strings.TrimSpace(recipientID)
+	if recipientID == "" {
+		return graph.Request{}, errors.New("recipient id is required")
+	}
+
+	return graph.Request{
+		Method:  "GET",
+		Path:    "me/thread_owner",
+		Version: strings.TrimSpace(version),
+		Query: map[string]string{
+			"recipient": recipientID,
+		},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package msgr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestBuildHandoverRequestShapesPassThreadControl(t *testing.T) {
+	t.Parallel()
+
+	req, err := BuildHandoverRequest("v25.0", "token", "", HandoverOptions{
+		Action:      "PASS",
+		RecipientID: "psid_123",
+		TargetAppID: "263902037430900",
+		Metadata:    "escalated by bot",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Method != "POST" || req.Path != "me/pass_thread_control" {
+		t.Fatalf("unexpected request %s %s", req.Method, req.Path)
+	}
+	if req.Form["recipient"] != `{"id":"psid_123"}` || req.Form["target_app_id"] != "263902037430900" || req.Form["metadata"] != "escalated by bot" {
+		t.Fatalf("unexpected form %#v", req.Form)
+	}
+}
+
+func TestBuildHandoverRequestValidatesTargetApp(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		options HandoverOptions
+		want    string
+	}{
+		{"pass requires target", HandoverOptions{Action: HandoverActionPass, RecipientID: "psid_123"}, "target app id is required"},
+		{"take rejects target", HandoverOptions{Action: HandoverActionTake, RecipientID: "psid_123", TargetAppID: "1"}, "only supported when passing"},
+		{"unknown action", HandoverOptions{Action: "release", RecipientID: "psid_123"}, "unsupported handover action"},
+		{"recipient required", HandoverOptions{Action: HandoverActionTake}, "recipient id is required"},
+	}
+	for _, tc := range cases {
+		if _, err := BuildHandoverRequest("v25.0", "token", "", tc.options); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+	}
+}
+
+func TestThreadOwnerReadsOwnerAppID(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v25.0/me/thread_owner" || r.URL.Query().Get("recipient") != "psid_123" {
+			t.Fatalf("unexpected request %s", r.URL.String())
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []any{map[string]any{"thread_owner": map[string]any{"app_id": "263902037430900"}}},
+		})
+	}))
+	defer server.Close()
+
+	result, err := New(graph.NewClient(server.Client(), server.URL)).ThreadOwner(context.Background(), "v25.0", "token", "", "psid_123")
+	if err != nil {
+		t.Fatalf("thread owner: %v", err)
+	}
+	if result.OwnerAppID != "263902037430900" {
+		t.Fatalf("unexpected owner %#v", result)
+	}
+}
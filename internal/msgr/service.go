@@ -10,8 +10,23 @@ import (
 	"github.com/bilalbayram/metacli/internal/graph"
 )
 
+const (
+	MessagingTypeResponse   = "RESPONSE"
+	MessagingTypeUpdate     = "UPDATE"
+	MessagingTypeMessageTag = "MESSAGE_TAG"
+)
+
+// messageTags are the tags that allow sending outside the 24h standard messaging window.
+var messageTags = map[string]struct{}{
+	"CONFIRMED_EVENT_UPDATE": {},
+	"POST_PURCHASE_UPDATE":   {},
+	"ACCOUNT_UPDATE":         {},
+	"HUMAN_AGENT":            {},
+}
+
 type ListConversationsOptions struct {
 	PageID string
+	UserID string
 	Limit  int
 }
 
@@ -31,6 +46,21 @@ type ReplyResult struct {
 	Response    map[string]any `json:"response"`
 }
 
+type SendOptions struct {
+	RecipientID   string
+	Text          string
+	MessagingType string
+	Tag           string
+}
+
+type SendResult struct {
+	RecipientID   string         `json:"recipient_id"`
+	MessagingType string         `json:"messaging_type"`
+	Tag           string         `json:"tag,omitempty"`
+	MessageID     string         `json:"message_id,omitempty"`
+	Response      map[string]any `json:"response"`
+}
+
 type SetGreetingOptions struct {
 	PageID  string
 	Message string
@@ -103,6 +133,31 @@ func (s *Service) Reply(ctx context.Context, version string, token string, appSe
 	}, nil
 }
 
+func (s *Service) Send(ctx context.Context, version string, token string, appSecret string, options SendOptions) (*SendResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("messenger service client is required")
+	}
+
+	req, err := BuildSendRequest(version, token, appSecret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.Client.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	messageID, _ := response.Body["message_id"].(string)
+	return &SendResult{
+		RecipientID:   strings.TrimSpace(options.RecipientID),
+		MessagingType: req.Form["messaging_type"],
+		Tag:           req.Form["tag"],
+		MessageID:     messageID,
+		Response:      response.Body,
+	}, nil
+}
+
 func (s *Service) SetGreeting(ctx context.Context, version string, token string, appSecret string, options SetGreetingOptions) (*SetGreetingResult, error) {
 	if s == nil || s.Client == nil {
 		return nil, errors.New("messenger service client is required")
@@ -130,13 +185,18 @@ func BuildListConversationsRequest(version string, token string, appSecret strin
 		return graph.Request{}, "", errors.New("page id is required")
 	}
 
+	query := map[string]string{
+		"fields": "id,updated_time,participants,messages{message,from,created_time}",
+	}
+	if userID := strings.TrimSpace(options.UserID); userID != "" {
+		query["user_id"] = userID
+	}
+
 	return graph.Request{
-		Method:  "GET",
-		Path:    fmt.Sprintf("%s/conversations", pageID),
-		Version: strings.TrimSpace(version),
-		Query: map[string]string{
-			"fields": "id,updated_time,participants,messages{message,from,created_time}",
-		},
+		Method:      "GET",
+		Path:        fmt.Sprintf("%s/conversations", pageID),
+		Version:     strings.TrimSpace(version),
+		Query:       query,
 		AccessToken: token,
 		AppSecret:   appSecret,
 	}, pageID, nil
@@ -176,6 +236,67 @@ func BuildReplyRequest(version string, token string, appSecret string, options R
 	}, nil
 }
 
+// BuildSendRequest shapes a text send. RESPONSE and UPDATE are limited to the 24h
+// standard messaging window; MESSAGE_TAG requires one of the supported tags.
+func BuildSendRequest(version string, token string, appSecret string, options SendOptions) (graph.Request, error) {
+	recipientID := strings.TrimSpace(options.RecipientID)
+	if recipientID == "" {
+		return graph.Request{}, errors.New("recipient id is required")
+	}
+
+	text := strings.TrimSpace(options.Text)
+	if text == "" {
+		return graph.Request{}, errors.New("message is required")
+	}
+
+	messagingType := strings.ToUpper(strings.TrimSpace(options.MessagingType))
+	if messagingType == "" {
+		messagingType = MessagingTypeResponse
+	}
+	tag := strings.ToUpper(strings.TrimSpace(options.Tag))
+	switch messagingType {
+	case MessagingTypeResponse, MessagingTypeUpdate:
+		if tag != "" {
+			return graph.Request{}, fmt.Errorf("message tag requires messaging type %s", MessagingTypeMessageTag)
+		}
+	case MessagingTypeMessageTag:
+		if tag == "" {
+			return graph.Request{}, errors.New("message tag is required for messaging type MESSAGE_TAG")
+		}
+		if _, ok := messageTags[tag]; !ok {
+			return graph.Request{}, fmt.Errorf("unsupported message tag %q: expected CONFIRMED_EVENT_UPDATE|POST_PURCHASE_UPDATE|ACCOUNT_UPDATE|HUMAN_AGENT", options.Tag)
+		}
+	default:
+		return graph.Request{}, fmt.Errorf("unsupported messaging type %q: expected RESPONSE|UPDATE|MESSAGE_TAG", options.MessagingType)
+	}
+
+	recipientPayload, err := marshalJSONFormValue(map[string]string{"id": recipientID})
+	if err != nil {
+		return graph.Request{}, fmt.Errorf("encode recipient payload: %w", err)
+	}
+	messagePayload, err := marshalJSONFormValue(map[string]string{"text": text})
+	if err != nil {
+		return graph.Request{}, fmt.Errorf("encode message payload: %w", err)
+	}
+
+	form := map[string]string{
+		"recipient":      recipientPayload,
+		"message":        messagePayload,
+		"messaging_type": messagingType,
+	}
+	if tag != "" {
+		form["tag"] = tag
+	}
+	return graph.Request{
+		Method:      "POST",
+		Path:        "me/messages",
+		Version:     strings.TrimSpace(version),
+		Form:        form,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, nil
+}
+
 func BuildSetGreetingRequest(version string, token string, appSecret string, options SetGreetingOptions) (graph.Request, string, error) {
 	pageID := strings.TrimSpace(options.PageID)
 	if pageID == "" {
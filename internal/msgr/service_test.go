@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/bilalbayram/metacli/internal/graph"
@@ -304,3 +305,69 @@ func searchSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestBuildSendRequestDefaultsToResponse(t *testing.T) {
+	t.Parallel()
+
+	req, err := BuildSendRequest("v25.0", "test-token", "", SendOptions{
+		RecipientID: "psid_123",
+		Text:        "hello",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Path != "me/messages" || req.Form["messaging_type"] != MessagingTypeResponse {
+		t.Fatalf("unexpected request %#v", req)
+	}
+	if _, ok := req.Form["tag"]; ok {
+		t.Fatalf("unexpected tag in form %#v", req.Form)
+	}
+}
+
+func TestBuildSendRequestValidatesMessageTags(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		options SendOptions
+		want    string
+	}{
+		{"tag required", SendOptions{RecipientID: "psid_123", Text: "hi", MessagingType: "message_tag"}, "message tag is required"},
+		{"unsupported tag", SendOptions{RecipientID: "psid_123", Text: "hi", MessagingType: "MESSAGE_TAG", Tag: "PROMO"}, "unsupported message tag"},
+		{"tag without type", SendOptions{RecipientID: "psid_123", Text: "hi", Tag: "HUMAN_AGENT"}, "requires messaging type MESSAGE_TAG"},
+		{"unsupported type", SendOptions{RecipientID: "psid_123", Text: "hi", MessagingType: "BROADCAST"}, "unsupported messaging type"},
+	}
+	for _, tc := range cases {
+		if _, err := BuildSendRequest("v25.0", "token", "", tc.options); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+	}
+
+	req, err := BuildSendRequest("v25.0", "token", "", SendOptions{
+		RecipientID:   "psid_123",
+		Text:          "An agent will follow up",
+		MessagingType: "message_tag",
+		Tag:           "human_agent",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Form["messaging_type"] != MessagingTypeMessageTag || req.Form["tag"] != "HUMAN_AGENT" {
+		t.Fatalf("unexpected form %#v", req.Form)
+	}
+}
+
+func TestBuildListConversationsRequestFiltersByUserID(t *testing.T) {
+	t.Parallel()
+
+	req, _, err := BuildListConversationsRequest("v25.0", "token", "", ListConversationsOptions{
+		PageID: "123456",
+		UserID: "psid_123",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Query["user_id"] != "psid_123" {
+		t.Fatalf("unexpected query %#v", req.Query)
+	}
+}
@@ -0,0 +1,342 @@
+package ops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/changelog"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/transport"
+)
+
+const CustomChecksSchemaVersion = 1
+
+const (
+	CustomCheckTypeCommand = "command"
+	CustomCheckTypeHTTP    = "http"
+	CustomCheckTypeBuiltin = "builtin"
+)
+
+const (
+	CustomCheckPolicyBlocking = "blocking"
+	CustomCheckPolicyWarning  = "warning"
+)
+
+const (
+	BuiltinCheckGraphVersionDeprecation = "graph_version_deprecation"
+	BuiltinCheckSchemaPackSource        = "schema_pack_source"
+)
+
+const (
+	defaultCustomCheckTimeout  = 30 * time.Second
+	customCheckOutputMaxLength = 512
+)
+
+var ErrCustomChecksPathRequired = errors.New("custom checks config path is required")
+
+var reservedCheckNames = map[string]struct{}{
+	checkNameChangelogOCCDelta:         {},
+	checkNameSchemaPackDrift:           {},
+	checkNameRateLimitThreshold:        {},
+	checkNamePermissionPolicyPreflight: {},
+	checkNameRuntimeResponseShapeDrift: {},
+}
+
+type builtinCustomCheck func(now time.Time, state BaselineState) (bool, string)
+
+var builtinCustomChecks = map[string]builtinCustomCheck{
+	BuiltinCheckGraphVersionDeprecation: evaluateBuiltinGraphVersionDeprecation,
+	BuiltinCheckSchemaPackSource:        evaluateBuiltinSchemaPackSource,
+}
+
+type CustomChecksConfig struct {
+	SchemaVersion int                     `json:"schema_version"`
+	Checks        []CustomCheckDefinition `json:"checks"`
+}
+
+type CustomCheckDefinition struct {
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Policy         string   `json:"policy,omitempty"`
+	Command        []string `json:"command,omitempty"`
+	URL            string   `json:"url,omitempty"`
+	Method         string   `json:"method,omitempty"`
+	ExpectedStatus int      `json:"expected_status,omitempty"`
+	Builtin        string   `json:"builtin,omitempty"`
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"`
+}
+
+type CustomCheckExecutor interface {
+	RunCommand(ctx context.Context, argv []string) (string, error)
+	Probe(ctx context.Context, method string, url string) (int, error)
+}
+
+type processCustomCheckExecutor struct {
+	client *http.Client
+}
+
+func NewCustomCheckExecutor(client *http.Client) CustomCheckExecutor {
+	if client == nil {
+		client = &http.Client{Transport: transport.RefuseOffline(nil)}
+	}
+	return &processCustomCheckExecutor{client: client}
+}
+
+func (e *processCustomCheckExecutor) RunCommand(ctx context.Context, argv []string) (string, error) {
+	if len(argv) == 0 {
+		return "", errors.New("command is required")
+	}
+	output, err := exec.CommandContext(ctx, argv[0], argv[1:]...).CombinedOutput()
+	return string(output), err
+}
+
+func (e *processCustomCheckExecutor) Probe(ctx context.Context, method string, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+func LoadCustomChecksConfig(path string) (CustomChecksConfig, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return CustomChecksConfig{}, ErrCustomChecksPathRequired
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return CustomChecksConfig{}, fmt.Errorf("read custom checks config %s: %w", path, err)
+	}
+
+	var cfg CustomChecksConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return CustomChecksConfig{}, fmt.Errorf("decode custom checks config %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return CustomChecksConfig{}, fmt.Errorf("decode custom checks config %s: multiple JSON values", path)
+		}
+		return CustomChecksConfig{}, fmt.Errorf("decode custom checks config %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return CustomChecksConfig{}, fmt.Errorf("validate custom checks config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (c CustomChecksConfig) Validate() error {
+	if c.SchemaVersion != CustomChecksSchemaVersion {
+		return fmt.Errorf("unsupported custom checks schema_version=%d (expected %d)", c.SchemaVersion, CustomChecksSchemaVersion)
+	}
+	return validateCustomCheckDefinitions(c.Checks)
+}
+
+func validateCustomCheckDefinitions(definitions []CustomCheckDefinition) error {
+	seen := make(map[string]struct{}, len(definitions))
+	for index, definition := range definitions {
+		if err := definition.Validate(); err != nil {
+			return fmt.Errorf("checks[%d]: %w", index, err)
+		}
+		name := strings.TrimSpace(definition.Name)
+		if _, exists := seen[name]; exists {
+			return fmt.Errorf("checks[%d]: duplicate check name %q", index, name)
+		}
+		seen[name] = struct{}{}
+	}
+	return nil
+}
+
+func (d CustomCheckDefinition) Validate() error {
+	name := strings.TrimSpace(d.Name)
+	if name == "" {
+		return errors.New("check name is required")
+	}
+	if _, reserved := reservedCheckNames[name]; reserved {
+		return fmt.Errorf("check name %q is reserved for a built-in ops check", name)
+	}
+	if normalizeCustomCheckPolicy(d.Policy) == "" {
+		return fmt.Errorf("check %q policy must be one of [blocking warning], got %q", name, d.Policy)
+	}
+	if d.TimeoutSeconds < 0 {
+		return fmt.Errorf("check %q timeout_seconds must be >= 0", name)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(d.Type)) {
+	case CustomCheckTypeCommand:
+		if len(d.Command) == 0 || strings.TrimSpace(d.Command[0]) == "" {
+			return fmt.Errorf("check %q requires command for type=%s", name, CustomCheckTypeCommand)
+		}
+	case CustomCheckTypeHTTP:
+		url := strings.TrimSpace(d.URL)
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return fmt.Errorf("check %q requires an http(s) url for type=%s", name, CustomCheckTypeHTTP)
+		}
+		if d.ExpectedStatus != 0 && (d.ExpectedStatus < 100 || d.ExpectedStatus > 599) {
+			return fmt.Errorf("check %q expected_status must be a valid HTTP status code", name)
+		}
+	case CustomCheckTypeBuiltin:
+		builtin := strings.TrimSpace(d.Builtin)
+		if _, ok := builtinCustomChecks[builtin]; !ok {
+			return fmt.Errorf("check %q references unknown builtin %q; expected one of [%s]", name, d.Builtin, strings.Join(BuiltinCustomCheckIDs(), " "))
+		}
+	default:
+		return fmt.Errorf("check %q type must be one of [builtin command http], got %q", name, d.Type)
+	}
+	return nil
+}
+
+func BuiltinCustomCheckIDs() []string {
+	ids := make([]string, 0, len(builtinCustomChecks))
+	for id := range builtinCustomChecks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func normalizeCustomCheckPolicy(policy string) string {
+	switch strings.ToLower(strings.TrimSpace(policy)) {
+	case "", CustomCheckPolicyBlocking:
+		return CustomCheckPolicyBlocking
+	case CustomCheckPolicyWarning:
+		return CustomCheckPolicyWarning
+	default:
+		return ""
+	}
+}
+
+func evaluateCustomChecks(ctx context.Context, definitions []CustomCheckDefinition, executor CustomCheckExecutor, state BaselineState, now time.Time) []Check {
+	if executor == nil {
+		executor = NewCustomCheckExecutor(nil)
+	}
+	checks := make([]Check, 0, len(definitions))
+	for _, definition := range definitions {
+		checks = append(checks, evaluateCustomCheck(ctx, definition, executor, state, now))
+	}
+	return checks
+}
+
+func evaluateCustomCheck(ctx context.Context, definition CustomCheckDefinition, executor CustomCheckExecutor, state BaselineState, now time.Time) Check {
+	check := Check{
+		Name:   strings.TrimSpace(definition.Name),
+		Status: CheckStatusPass,
+	}
+
+	timeout := defaultCustomCheckTimeout
+	if definition.TimeoutSeconds > 0 {
+		timeout = time.Duration(definition.TimeoutSeconds) * time.Second
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	passed := false
+	message := ""
+	switch strings.ToLower(strings.TrimSpace(definition.Type)) {
+	case CustomCheckTypeCommand:
+		passed, message = runCustomCommandCheck(checkCtx, executor, definition.Command)
+	case CustomCheckTypeHTTP:
+		passed, message = runCustomHTTPCheck(checkCtx, executor, definition)
+	case CustomCheckTypeBuiltin:
+		builtin := strings.TrimSpace(definition.Builtin)
+		evaluate, ok := builtinCustomChecks[builtin]
+		if !ok {
+			message = fmt.Sprintf("unknown builtin %q", definition.Builtin)
+			break
+		}
+		passed, message = evaluate(now, state)
+		message = fmt.Sprintf("builtin=%s %s", builtin, message)
+	default:
+		message = fmt.Sprintf("unsupported custom check type %q", definition.Type)
+	}
+
+	if passed {
+		check.Message = "custom check passed: " + message
+		return check
+	}
+	check.Status = CheckStatusFail
+	check.Blocking = normalizeCustomCheckPolicy(definition.Policy) == CustomCheckPolicyBlocking
+	check.Message = "custom check failed: " + message
+	return check
+}
+
+func runCustomCommandCheck(ctx context.Context, executor CustomCheckExecutor, argv []string) (bool, string) {
+	commandLine := strings.Join(argv, " ")
+	output, err := executor.RunCommand(ctx, argv)
+	output = truncateCustomCheckOutput(output)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return false, fmt.Sprintf("command=%q timed out", commandLine)
+		}
+		if output != "" {
+			return false, fmt.Sprintf("command=%q error=%v output=%q", commandLine, err, output)
+		}
+		return false, fmt.Sprintf("command=%q error=%v", commandLine, err)
+	}
+	return true, fmt.Sprintf("command=%q exited 0", commandLine)
+}
+
+func runCustomHTTPCheck(ctx context.Context, executor CustomCheckExecutor, definition CustomCheckDefinition) (bool, string) {
+	method := normalizeHTTPMethod(definition.Method)
+	url := strings.TrimSpace(definition.URL)
+	status, err := executor.Probe(ctx, method, url)
+	if err != nil {
+		return false, fmt.Sprintf("http %s %s error=%v", method, url, err)
+	}
+	if definition.ExpectedStatus != 0 {
+		if status != definition.ExpectedStatus {
+			return false, fmt.Sprintf("http %s %s status=%d expected_status=%d", method, url, status, definition.ExpectedStatus)
+		}
+		return true, fmt.Sprintf("http %s %s status=%d", method, url, status)
+	}
+	if status < 200 || status > 299 {
+		return false, fmt.Sprintf("http %s %s status=%d expected 2xx", method, url, status)
+	}
+	return true, fmt.Sprintf("http %s %s status=%d", method, url, status)
+}
+
+func evaluateBuiltinGraphVersionDeprecation(now time.Time, _ BaselineState) (bool, string) {
+	result, err := changelog.NewChecker().Check(config.DefaultGraphVersion, now.UTC())
+	if err != nil {
+		return false, err.Error()
+	}
+	if result.IsDeprecated || result.DaysToDeprecation <= 90 {
+		return false, fmt.Sprintf("version=%s days_to_deprecation=%d: %s", result.RequestedVersion, result.DaysToDeprecation, result.Warning)
+	}
+	return true, fmt.Sprintf("version=%s days_to_deprecation=%d", result.RequestedVersion, result.DaysToDeprecation)
+}
+
+func evaluateBuiltinSchemaPackSource(_ time.Time, state BaselineState) (bool, string) {
+	path, err := resolveSchemaPackSnapshotSource(state.Snapshots.SchemaPack.Domain, state.Snapshots.SchemaPack.Version)
+	if err != nil {
+		return false, err.Error()
+	}
+	return true, fmt.Sprintf("source=%s", path)
+}
+
+func truncateCustomCheckOutput(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) <= customCheckOutputMaxLength {
+		return output
+	}
+	return output[:customCheckOutputMaxLength] + "..."
+}
@@ -0,0 +1,181 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type customCheckExecutorStub struct {
+	commandOutput string
+	commandErr    error
+	probeStatus   int
+	probeErr      error
+	commands      [][]string
+	probes        []string
+}
+
+func (s *customCheckExecutorStub) RunCommand(_ context.Context, argv []string) (string, error) {
+	s.commands = append(s.commands, append([]string(nil), argv...))
+	return s.commandOutput, s.commandErr
+}
+
+func (s *customCheckExecutorStub) Probe(_ context.Context, method string, url string) (int, error) {
+	s.probes = append(s.probes, method+" "+url)
+	return s.probeStatus, s.probeErr
+}
+
+func TestLoadCustomChecksConfigRejectsReservedName(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "checks.json")
+	body := `{"schema_version":1,"checks":[{"name":"rate_limit_threshold","type":"builtin","builtin":"schema_pack_source"}]}`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write checks config: %v", err)
+	}
+
+	_, err := LoadCustomChecksConfig(path)
+	if err == nil {
+		t.Fatal("expected reserved check name to be rejected")
+	}
+	if !strings.Contains(err.Error(), "reserved") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadCustomChecksConfigRejectsUnknownBuiltin(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "checks.json")
+	body := `{"schema_version":1,"checks":[{"name":"org_gate","type":"builtin","builtin":"does_not_exist"}]}`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write checks config: %v", err)
+	}
+
+	_, err := LoadCustomChecksConfig(path)
+	if err == nil {
+		t.Fatal("expected unknown builtin to be rejected")
+	}
+	if !strings.Contains(err.Error(), "unknown builtin") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEvaluateCustomChecksAppliesPolicyToFailures(t *testing.T) {
+	t.Parallel()
+
+	executor := &customCheckExecutorStub{
+		commandOutput: "vpn down",
+		commandErr:    errors.New("exit status 1"),
+		probeStatus:   503,
+	}
+	definitions := []CustomCheckDefinition{
+		{Name: "vpn_gate", Type: CustomCheckTypeCommand, Command: []string{"check-vpn", "--strict"}},
+		{Name: "status_page", Type: CustomCheckTypeHTTP, URL: "https://status.example.com/health", Policy: CustomCheckPolicyWarning},
+	}
+
+	checks := evaluateCustomChecks(context.Background(), definitions, executor, BaselineState{}, time.Now().UTC())
+	if len(checks) != 2 {
+		t.Fatalf("expected two checks, got %d", len(checks))
+	}
+	if checks[0].Name != "vpn_gate" || checks[0].Status != CheckStatusFail || !checks[0].Blocking {
+		t.Fatalf("unexpected command check: %+v", checks[0])
+	}
+	if !strings.Contains(checks[0].Message, "vpn down") {
+		t.Fatalf("expected command output in message, got %q", checks[0].Message)
+	}
+	if checks[1].Name != "status_page" || checks[1].Status != CheckStatusFail || checks[1].Blocking {
+		t.Fatalf("unexpected http check: %+v", checks[1])
+	}
+	if len(executor.commands) != 1 || executor.commands[0][0] != "check-vpn" {
+		t.Fatalf("unexpected command invocations: %+v", executor.commands)
+	}
+	if len(executor.probes) != 1 || executor.probes[0] != "GET https://status.example.com/health" {
+		t.Fatalf("unexpected probe invocations: %+v", executor.probes)
+	}
+}
+
+func TestEvaluateCustomChecksHonorsExpectedStatus(t *testing.T) {
+	t.Parallel()
+
+	executor := &customCheckExecutorStub{probeStatus: 204}
+	definitions := []CustomCheckDefinition{
+		{Name: "webhook_ping", Type: CustomCheckTypeHTTP, Method: "head", URL: "https://hooks.example.com/ping", ExpectedStatus: 204},
+	}
+
+	checks := evaluateCustomChecks(context.Background(), definitions, executor, BaselineState{}, time.Now().UTC())
+	if checks[0].Status != CheckStatusPass {
+		t.Fatalf("expected pass, got %+v", checks[0])
+	}
+	if executor.probes[0] != "HEAD https://hooks.example.com/ping" {
+		t.Fatalf("unexpected probe: %s", executor.probes[0])
+	}
+}
+
+func TestRunWithOptionsMergesCustomChecksIntoOtherSection(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "baseline-state.json")
+	if _, err := InitBaseline(path); err != nil {
+		t.Fatalf("init baseline: %v", err)
+	}
+
+	result, err := RunWithOptions(path, RunOptions{
+		OptionalModulePolicy: OptionalModulePolicySkip,
+		CustomChecks: []CustomCheckDefinition{
+			{Name: "schema_source", Type: CustomCheckTypeBuiltin, Builtin: BuiltinCheckSchemaPackSource},
+			{Name: "org_gate", Type: CustomCheckTypeCommand, Command: []string{"org-gate"}, Policy: CustomCheckPolicyWarning},
+		},
+		CustomCheckExecutor: &customCheckExecutorStub{commandErr: errors.New("exit status 2")},
+	})
+	if err != nil {
+		t.Fatalf("run with options: %v", err)
+	}
+
+	if len(result.Report.Checks) != 7 {
+		t.Fatalf("expected seven checks, got %d", len(result.Report.Checks))
+	}
+	if result.Report.Outcome != RunOutcomeWarning {
+		t.Fatalf("unexpected outcome: %s", result.Report.Outcome)
+	}
+	other := result.Report.Sections[len(result.Report.Sections)-1]
+	if other.Name != reportSectionOther {
+		t.Fatalf("expected trailing other section, got %s", other.Name)
+	}
+	if len(other.Checks) != 2 || other.Checks[0].Name != "schema_source" || other.Checks[1].Name != "org_gate" {
+		t.Fatalf("unexpected other section checks: %+v", other.Checks)
+	}
+	if other.Checks[0].Status != CheckStatusPass {
+		t.Fatalf("expected builtin schema source check to pass: %+v", other.Checks[0])
+	}
+	if other.Summary.Warnings != 1 {
+		t.Fatalf("unexpected other section summary: %+v", other.Summary)
+	}
+}
+
+func TestRunWithOptionsRejectsInvalidCustomChecks(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "baseline-state.json")
+	if _, err := InitBaseline(path); err != nil {
+		t.Fatalf("init baseline: %v", err)
+	}
+
+	_, err := RunWithOptions(path, RunOptions{
+		OptionalModulePolicy: OptionalModulePolicySkip,
+		CustomChecks: []CustomCheckDefinition{
+			{Name: "dup", Type: CustomCheckTypeBuiltin, Builtin: BuiltinCheckSchemaPackSource},
+			{Name: "dup", Type: CustomCheckTypeBuiltin, Builtin: BuiltinCheckSchemaPackSource},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected duplicate custom check names to fail")
+	}
+	if code := ExitCode(err); code != ExitCodeInput {
+		t.Fatalf("unexpected exit code: got=%d want=%d", code, ExitCodeInput)
+	}
+}
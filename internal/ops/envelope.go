@@ -8,13 +8,18 @@ import (
 	"io"
 	"strconv"
 	"strings"
+
+	"github.com/bilalbayram/metacli/internal/changelog"
+	"github.com/bilalbayram/metacli/internal/output"
 )
 
 const (
-	ContractVersion = "ops.v1"
-	CommandInit     = "meta ops init"
-	CommandRun      = "meta ops run"
-	CommandCleanup  = "meta ops cleanup"
+	ContractVersion     = "ops.v1"
+	CommandInit         = "meta ops init"
+	CommandRun          = "meta ops run"
+	CommandCleanup      = "meta ops cleanup"
+	CommandReportDiff   = "meta ops report diff"
+	CommandMetricsServe = "meta ops metrics serve"
 )
 
 const ReportSchemaVersion = 1
@@ -29,28 +34,33 @@ type Envelope struct {
 }
 
 type ErrorInfo struct {
+	Class   string `json:"class"`
 	Type    string `json:"type"`
 	Message string `json:"message"`
 }
 
 type InitResult struct {
-	StatePath string        `json:"state_path"`
-	State     BaselineState `json:"state"`
+	StatePath     string                    `json:"state_path"`
+	State         BaselineState             `json:"state"`
+	ChangelogFeed *changelog.FeedResolution `json:"changelog_feed,omitempty"`
 }
 
 type RunResult struct {
-	StatePath string `json:"state_path"`
-	Report    Report `json:"report"`
+	StatePath     string                    `json:"state_path"`
+	HistoryPath   string                    `json:"history_path,omitempty"`
+	ChangelogFeed *changelog.FeedResolution `json:"changelog_feed,omitempty"`
+	Report        Report                    `json:"report"`
 }
 
 type Report struct {
-	SchemaVersion int             `json:"schema_version"`
-	Kind          string          `json:"kind"`
-	Baseline      BaselineState   `json:"baseline"`
-	Summary       Summary         `json:"summary"`
-	Outcome       string          `json:"outcome"`
-	Sections      []ReportSection `json:"sections"`
-	Checks        []Check         `json:"checks"`
+	SchemaVersion int                   `json:"schema_version"`
+	Kind          string                `json:"kind"`
+	Baseline      BaselineState         `json:"baseline"`
+	Summary       Summary               `json:"summary"`
+	Outcome       string                `json:"outcome"`
+	Sections      []ReportSection       `json:"sections"`
+	Checks        []Check               `json:"checks"`
+	ExitPolicy    *ExitPolicyEvaluation `json:"exit_policy,omitempty"`
 }
 
 type Summary struct {
@@ -99,6 +109,7 @@ func NewErrorEnvelope(command string, code int, err error) Envelope {
 		Success:         false,
 		ExitCode:        code,
 		Error: &ErrorInfo{
+			Class:   errorClass(code),
 			Type:    errorType(code),
 			Message: message,
 		},
@@ -341,3 +352,15 @@ func errorType(code int) string {
 		return "error"
 	}
 }
+
+// errorClass maps exit codes onto the shared error.class taxonomy.
+func errorClass(code int) string {
+	switch code {
+	case ExitCodeInput, ExitCodeState:
+		return output.ErrorClassInput
+	case ExitCodePolicy, ExitCodeWarning:
+		return output.ErrorClassPolicy
+	default:
+		return output.ErrorClassInternal
+	}
+}
@@ -0,0 +1,250 @@
+package ops
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const ExitPolicySchemaVersion = 1
+
+const (
+	FailOnWarning  = "warning"
+	FailOnBlocking = "blocking"
+)
+
+const (
+	FindingSeverityBlocking = "blocking"
+	FindingSeverityWarning  = "warning"
+	FindingSeverityIgnore   = "ignore"
+)
+
+const (
+	ExitPolicySourceDefault = "default"
+	ExitPolicySourceFile    = "file"
+	ExitPolicySourceFlags   = "flags"
+)
+
+var ErrInvalidExitPolicy = errors.New("invalid exit policy")
+
+type ExitPolicy struct {
+	SchemaVersion int               `json:"schema_version,omitempty"`
+	FailOn        string            `json:"fail_on"`
+	Overrides     map[string]string `json:"overrides,omitempty"`
+	Source        string            `json:"-"`
+}
+
+type Finding struct {
+	Name     string
+	Blocking bool
+}
+
+type ExitPolicyEvaluation struct {
+	FailOn    string            `json:"fail_on"`
+	Overrides map[string]string `json:"overrides,omitempty"`
+	Source    string            `json:"source"`
+	Warnings  int               `json:"warnings"`
+	Blocking  int               `json:"blocking"`
+	Ignored   int               `json:"ignored"`
+	ExitCode  int               `json:"exit_code"`
+}
+
+func DefaultExitPolicy() ExitPolicy {
+	return ExitPolicy{
+		FailOn: FailOnWarning,
+		Source: ExitPolicySourceDefault,
+	}
+}
+
+func LoadExitPolicy(path string) (ExitPolicy, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return ExitPolicy{}, fmt.Errorf("%w: policy file path is required", ErrInvalidExitPolicy)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ExitPolicy{}, fmt.Errorf("read exit policy file %s: %w", path, err)
+	}
+
+	var policy ExitPolicy
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		return ExitPolicy{}, fmt.Errorf("decode exit policy file %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return ExitPolicy{}, fmt.Errorf("decode exit policy file %s: multiple JSON values", path)
+		}
+		return ExitPolicy{}, fmt.Errorf("decode exit policy file %s: %w", path, err)
+	}
+	if policy.SchemaVersion != ExitPolicySchemaVersion {
+		return ExitPolicy{}, fmt.Errorf(
+			"%w: unsupported exit policy schema_version=%d in %s (expected %d)",
+			ErrInvalidExitPolicy,
+			policy.SchemaVersion,
+			path,
+			ExitPolicySchemaVersion,
+		)
+	}
+	if strings.TrimSpace(policy.FailOn) == "" {
+		policy.FailOn = FailOnWarning
+	}
+	policy.Source = ExitPolicySourceFile
+	policy = policy.normalized()
+	if err := policy.Validate(); err != nil {
+		return ExitPolicy{}, err
+	}
+	return policy, nil
+}
+
+func ParseExitPolicyOverrides(values []string) (map[string]string, error) {
+	overrides := make(map[string]string, len(values))
+	for _, value := range values {
+		name, severity, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: override %q must be in name=severity form", ErrInvalidExitPolicy, value)
+		}
+		severity = normalizeFindingSeverity(severity)
+		if severity == "" {
+			return nil, fmt.Errorf("%w: override %q severity must be one of [blocking warning ignore]", ErrInvalidExitPolicy, value)
+		}
+		overrides[name] = severity
+	}
+	return overrides, nil
+}
+
+func (p ExitPolicy) WithFlags(failOn string, overrides map[string]string) ExitPolicy {
+	merged := p.normalized()
+	if strings.TrimSpace(failOn) != "" {
+		merged.FailOn = strings.ToLower(strings.TrimSpace(failOn))
+		merged.Source = ExitPolicySourceFlags
+	}
+	if len(overrides) > 0 {
+		combined := make(map[string]string, len(merged.Overrides)+len(overrides))
+		for name, severity := range merged.Overrides {
+			combined[name] = severity
+		}
+		for name, severity := range overrides {
+			combined[strings.TrimSpace(name)] = normalizeFindingSeverity(severity)
+		}
+		merged.Overrides = combined
+		merged.Source = ExitPolicySourceFlags
+	}
+	return merged
+}
+
+func (p ExitPolicy) Validate() error {
+	switch p.FailOn {
+	case FailOnWarning, FailOnBlocking:
+	default:
+		return fmt.Errorf("%w: fail_on must be one of [warning blocking], got %q", ErrInvalidExitPolicy, p.FailOn)
+	}
+	names := make([]string, 0, len(p.Overrides))
+	for name := range p.Overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("%w: override names cannot be empty", ErrInvalidExitPolicy)
+		}
+		if normalizeFindingSeverity(p.Overrides[name]) == "" {
+			return fmt.Errorf("%w: override %q severity must be one of [blocking warning ignore], got %q", ErrInvalidExitPolicy, name, p.Overrides[name])
+		}
+	}
+	return nil
+}
+
+func (p ExitPolicy) Evaluate(findings []Finding) ExitPolicyEvaluation {
+	policy := p.normalized()
+	evaluation := ExitPolicyEvaluation{
+		FailOn:    policy.FailOn,
+		Overrides: policy.Overrides,
+		Source:    policy.Source,
+	}
+	for _, finding := range findings {
+		severity := FindingSeverityWarning
+		if finding.Blocking {
+			severity = FindingSeverityBlocking
+		}
+		if override, ok := policy.Overrides[finding.Name]; ok {
+			severity = override
+		}
+		switch severity {
+		case FindingSeverityBlocking:
+			evaluation.Blocking++
+		case FindingSeverityWarning:
+			evaluation.Warnings++
+		default:
+			evaluation.Ignored++
+		}
+	}
+
+	switch {
+	case evaluation.Blocking > 0:
+		evaluation.ExitCode = ExitCodePolicy
+	case evaluation.Warnings > 0 && policy.FailOn == FailOnWarning:
+		evaluation.ExitCode = ExitCodeWarning
+	default:
+		evaluation.ExitCode = ExitCodeSuccess
+	}
+	return evaluation
+}
+
+func (p ExitPolicy) normalized() ExitPolicy {
+	normalized := ExitPolicy{
+		SchemaVersion: p.SchemaVersion,
+		FailOn:        strings.ToLower(strings.TrimSpace(p.FailOn)),
+		Source:        strings.TrimSpace(p.Source),
+	}
+	if normalized.FailOn == "" {
+		normalized.FailOn = FailOnWarning
+	}
+	if normalized.Source == "" {
+		normalized.Source = ExitPolicySourceDefault
+	}
+	if len(p.Overrides) > 0 {
+		normalized.Overrides = make(map[string]string, len(p.Overrides))
+		for name, severity := range p.Overrides {
+			normalized.Overrides[strings.TrimSpace(name)] = strings.ToLower(strings.TrimSpace(severity))
+		}
+	}
+	return normalized
+}
+
+func normalizeFindingSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case FindingSeverityBlocking:
+		return FindingSeverityBlocking
+	case FindingSeverityWarning:
+		return FindingSeverityWarning
+	case FindingSeverityIgnore:
+		return FindingSeverityIgnore
+	default:
+		return ""
+	}
+}
+
+func applyExitPolicy(report *Report, policy *ExitPolicy) {
+	if report == nil || policy == nil {
+		return
+	}
+	findings := make([]Finding, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		if check.Status == CheckStatusPass {
+			continue
+		}
+		findings = append(findings, Finding{Name: check.Name, Blocking: check.Blocking})
+	}
+	evaluation := policy.Evaluate(findings)
+	report.ExitPolicy = &evaluation
+}
@@ -0,0 +1,98 @@
+package ops
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExitPolicyEvaluateMapsFindingsToExitCodes(t *testing.T) {
+	t.Parallel()
+
+	findings := []Finding{
+		{Name: checkNameRateLimitThreshold},
+		{Name: checkNameSchemaPackDrift, Blocking: true},
+	}
+
+	evaluation := DefaultExitPolicy().Evaluate(findings)
+	if evaluation.Warnings != 1 || evaluation.Blocking != 1 || evaluation.ExitCode != ExitCodePolicy {
+		t.Fatalf("unexpected default evaluation: %+v", evaluation)
+	}
+	if evaluation.Source != ExitPolicySourceDefault || evaluation.FailOn != FailOnWarning {
+		t.Fatalf("unexpected default policy echo: %+v", evaluation)
+	}
+
+	policy := DefaultExitPolicy().WithFlags(FailOnBlocking, map[string]string{checkNameSchemaPackDrift: FindingSeverityWarning})
+	evaluation = policy.Evaluate(findings)
+	if evaluation.Warnings != 2 || evaluation.Blocking != 0 {
+		t.Fatalf("unexpected overridden evaluation: %+v", evaluation)
+	}
+	if evaluation.ExitCode != ExitCodeSuccess {
+		t.Fatalf("unexpected exit code: got=%d want=%d", evaluation.ExitCode, ExitCodeSuccess)
+	}
+	if evaluation.Source != ExitPolicySourceFlags {
+		t.Fatalf("unexpected policy source: %s", evaluation.Source)
+	}
+
+	policy = DefaultExitPolicy().WithFlags("", map[string]string{
+		checkNameRateLimitThreshold: FindingSeverityIgnore,
+		checkNameSchemaPackDrift:    FindingSeverityIgnore,
+	})
+	evaluation = policy.Evaluate(findings)
+	if evaluation.Ignored != 2 || evaluation.ExitCode != ExitCodeSuccess {
+		t.Fatalf("unexpected ignored evaluation: %+v", evaluation)
+	}
+}
+
+func TestLoadExitPolicyMergesFlagOverrides(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "exit-policy.json")
+	content := "{\n  \"schema_version\": 1,\n  \"fail_on\": \"blocking\",\n  \"overrides\": {\n    \"rate_limit_threshold\": \"blocking\"\n  }\n}\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write exit policy fixture: %v", err)
+	}
+
+	policy, err := LoadExitPolicy(path)
+	if err != nil {
+		t.Fatalf("load exit policy: %v", err)
+	}
+	if policy.FailOn != FailOnBlocking || policy.Source != ExitPolicySourceFile {
+		t.Fatalf("unexpected loaded policy: %+v", policy)
+	}
+
+	overrides, err := ParseExitPolicyOverrides([]string{"rate_limit_threshold=ignore", "custom_probe=WARNING"})
+	if err != nil {
+		t.Fatalf("parse overrides: %v", err)
+	}
+	merged := policy.WithFlags("", overrides)
+	if merged.FailOn != FailOnBlocking {
+		t.Fatalf("expected file fail_on to be kept, got %s", merged.FailOn)
+	}
+	if merged.Overrides["rate_limit_threshold"] != FindingSeverityIgnore || merged.Overrides["custom_probe"] != FindingSeverityWarning {
+		t.Fatalf("unexpected merged overrides: %+v", merged.Overrides)
+	}
+}
+
+func TestExitPolicyRejectsInvalidValues(t *testing.T) {
+	t.Parallel()
+
+	if err := DefaultExitPolicy().WithFlags("never", nil).Validate(); !errors.Is(err, ErrInvalidExitPolicy) {
+		t.Fatalf("expected invalid fail_on error, got %v", err)
+	}
+	if _, err := ParseExitPolicyOverrides([]string{"rate_limit_threshold"}); !errors.Is(err, ErrInvalidExitPolicy) {
+		t.Fatalf("expected malformed override error, got %v", err)
+	}
+	if _, err := ParseExitPolicyOverrides([]string{"rate_limit_threshold=fatal"}); !errors.Is(err, ErrInvalidExitPolicy) {
+		t.Fatalf("expected invalid severity error, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "exit-policy.json")
+	if err := os.WriteFile(path, []byte("{\"schema_version\": 2, \"fail_on\": \"warning\"}\n"), 0o600); err != nil {
+		t.Fatalf("write exit policy fixture: %v", err)
+	}
+	if _, err := LoadExitPolicy(path); !errors.Is(err, ErrInvalidExitPolicy) {
+		t.Fatalf("expected unsupported schema version error, got %v", err)
+	}
+}
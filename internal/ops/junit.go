@@ -0,0 +1,183 @@
+package ops
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ReportFormatNone  = ""
+	ReportFormatJUnit = "junit"
+)
+
+var ErrInvalidReportFormat = errors.New("invalid report format")
+
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+type JUnitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []JUnitTestCase `xml:"testcase"`
+}
+
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type JUnitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type JUnitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+func ValidateReportFormat(format string, path string) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	path = strings.TrimSpace(path)
+	switch format {
+	case ReportFormatNone:
+		if path != "" {
+			return fmt.Errorf("%w: --report-file requires --report-format", ErrInvalidReportFormat)
+		}
+	case ReportFormatJUnit:
+		if path == "" {
+			return fmt.Errorf("%w: --report-format %s requires --report-file", ErrInvalidReportFormat, format)
+		}
+	default:
+		return fmt.Errorf("%w: report format must be %s, got %q", ErrInvalidReportFormat, ReportFormatJUnit, format)
+	}
+	return nil
+}
+
+func (e *ExitPolicyEvaluation) FindingSeverity(finding Finding) string {
+	severity := FindingSeverityWarning
+	if finding.Blocking {
+		severity = FindingSeverityBlocking
+	}
+	if e == nil {
+		return severity
+	}
+	if override, ok := e.Overrides[finding.Name]; ok {
+		severity = override
+	}
+	if severity == FindingSeverityWarning && e.FailOn == FailOnBlocking {
+		return FindingSeverityIgnore
+	}
+	return severity
+}
+
+func NewJUnitFindingCase(className string, finding Finding, message string, evaluation *ExitPolicyEvaluation) JUnitTestCase {
+	testCase := JUnitTestCase{
+		Name:      finding.Name,
+		ClassName: className,
+	}
+	severity := evaluation.FindingSeverity(finding)
+	if severity == FindingSeverityIgnore {
+		testCase.SystemOut = message
+		return testCase
+	}
+	testCase.Failure = &JUnitFailure{
+		Type:    severity,
+		Message: message,
+		Text:    message,
+	}
+	return testCase
+}
+
+func (s *JUnitTestSuite) Add(testCase JUnitTestCase) {
+	s.Cases = append(s.Cases, testCase)
+	s.Tests++
+	if testCase.Failure != nil {
+		s.Failures++
+	}
+	if testCase.Skipped != nil {
+		s.Skipped++
+	}
+}
+
+func (s *JUnitTestSuites) Add(suite JUnitTestSuite) {
+	s.Suites = append(s.Suites, suite)
+	s.Tests += suite.Tests
+	s.Failures += suite.Failures
+	s.Skipped += suite.Skipped
+}
+
+func JUnitFromReport(report Report) JUnitTestSuites {
+	suites := JUnitTestSuites{Name: CommandRun}
+	sections := report.Sections
+	if len(sections) == 0 {
+		sections = []ReportSection{{Name: reportSectionOther, Checks: report.Checks}}
+	}
+	for _, section := range sections {
+		suite := JUnitTestSuite{Name: section.Name}
+		className := "ops." + section.Name
+		for _, check := range section.Checks {
+			if check.Status == CheckStatusPass {
+				suite.Add(JUnitTestCase{Name: check.Name, ClassName: className, SystemOut: check.Message})
+				continue
+			}
+			suite.Add(NewJUnitFindingCase(className, Finding{Name: check.Name, Blocking: check.Blocking}, check.Message, report.ExitPolicy))
+		}
+		suites.Add(suite)
+	}
+	return suites
+}
+
+func WriteJUnitFile(path string, suites JUnitTestSuites) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return fmt.Errorf("%w: report file path is required", ErrInvalidReportFormat)
+	}
+	payload, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal junit report: %w", err)
+	}
+	payload = append([]byte(xml.Header), payload...)
+	payload = append(payload, '\n')
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create junit report directory %s: %w", dir, err)
+	}
+	tmpFile, err := os.CreateTemp(dir, ".junit-*.xml")
+	if err != nil {
+		return fmt.Errorf("create temp junit report: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp junit report: %w", err)
+	}
+	if err := tmpFile.Chmod(0o644); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("chmod temp junit report: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp junit report: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("write junit report %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package ops
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJUnitFromReportMapsChecksToTestCases(t *testing.T) {
+	t.Parallel()
+
+	report := Report{
+		Sections: []ReportSection{
+			{
+				Name: "monitor",
+				Checks: []Check{
+					{Name: checkNameSchemaPackDrift, Status: CheckStatusPass, Message: "no drift"},
+					{Name: checkNameRateLimitThreshold, Status: CheckStatusFail, Message: "app_call_count at 65%"},
+				},
+			},
+			{
+				Name: "preflight",
+				Checks: []Check{
+					{Name: "permission_preflight", Status: CheckStatusFail, Blocking: true, Message: "missing ads_management"},
+				},
+			},
+		},
+	}
+	evaluation := DefaultExitPolicy().Evaluate([]Finding{
+		{Name: checkNameRateLimitThreshold},
+		{Name: "permission_preflight", Blocking: true},
+	})
+	report.ExitPolicy = &evaluation
+
+	suites := JUnitFromReport(report)
+	if suites.Tests != 3 || suites.Failures != 2 || len(suites.Suites) != 2 {
+		t.Fatalf("unexpected junit totals: tests=%d failures=%d suites=%d", suites.Tests, suites.Failures, len(suites.Suites))
+	}
+	warning := suites.Suites[0].Cases[1]
+	if warning.Failure == nil || warning.Failure.Type != FindingSeverityWarning || warning.Failure.Message != "app_call_count at 65%" {
+		t.Fatalf("unexpected warning case: %+v", warning)
+	}
+	blocking := suites.Suites[1].Cases[0]
+	if blocking.ClassName != "ops.preflight" || blocking.Failure == nil || blocking.Failure.Type != FindingSeverityBlocking {
+		t.Fatalf("unexpected blocking case: %+v", blocking)
+	}
+
+	relaxed := DefaultExitPolicy().WithFlags(FailOnBlocking, nil).Evaluate([]Finding{{Name: checkNameRateLimitThreshold}})
+	report.ExitPolicy = &relaxed
+	suites = JUnitFromReport(report)
+	if suites.Failures != 1 || suites.Suites[0].Cases[1].Failure != nil {
+		t.Fatalf("expected warning to pass under fail-on blocking, got %+v", suites.Suites[0].Cases[1])
+	}
+}
+
+func TestWriteJUnitFileWritesParseableXML(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "reports", "ops.xml")
+	suites := JUnitTestSuites{Name: CommandRun}
+	suite := JUnitTestSuite{Name: "monitor"}
+	suite.Add(JUnitTestCase{Name: "check", ClassName: "ops.monitor", Failure: &JUnitFailure{Type: FindingSeverityBlocking, Message: "a < b", Text: "a < b"}})
+	suites.Add(suite)
+
+	if err := WriteJUnitFile(path, suites); err != nil {
+		t.Fatalf("write junit file: %v", err)
+	}
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read junit file: %v", err)
+	}
+	if !strings.HasPrefix(string(payload), xml.Header) {
+		t.Fatalf("expected xml header, got %q", string(payload))
+	}
+	var decoded JUnitTestSuites
+	if err := xml.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("decode junit file: %v", err)
+	}
+	if decoded.Failures != 1 || decoded.Suites[0].Cases[0].Failure.Message != "a < b" {
+		t.Fatalf("unexpected decoded junit: %+v", decoded)
+	}
+}
+
+func TestValidateReportFormatRequiresFileAndKnownFormat(t *testing.T) {
+	t.Parallel()
+
+	if err := ValidateReportFormat("", ""); err != nil {
+		t.Fatalf("expected empty format to be valid, got %v", err)
+	}
+	if err := ValidateReportFormat("JUnit", "out.xml"); err != nil {
+		t.Fatalf("expected junit format to be valid, got %v", err)
+	}
+	for _, tc := range [][2]string{{"junit", ""}, {"", "out.xml"}, {"tap", "out.tap"}} {
+		if err := ValidateReportFormat(tc[0], tc[1]); !errors.Is(err, ErrInvalidReportFormat) {
+			t.Fatalf("expected invalid report format for %v, got %v", tc, err)
+		}
+	}
+}
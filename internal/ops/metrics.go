@@ -0,0 +1,329 @@
+package ops
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+)
+
+const (
+	DefaultMetricsListenAddr = ":9109"
+	MetricsPath              = "/metrics"
+	metricsContentType       = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+const (
+	MetricTypeGauge = "gauge"
+)
+
+type MetricsSources struct {
+	StatePath          string
+	HistoryDir         string
+	RateLimitTelemetry *RateLimitTelemetrySnapshot
+	Profiles           map[string]config.Profile
+	Now                func() time.Time
+}
+
+type MetricFamily struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []MetricSample
+}
+
+type MetricSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+func CollectMetrics(sources MetricsSources) ([]MetricFamily, error) {
+	now := time.Now().UTC()
+	if sources.Now != nil {
+		now = sources.Now().UTC()
+	}
+
+	families := make([]MetricFamily, 0, 12)
+
+	var latest *StoredReport
+	historyRuns := 0
+	if strings.TrimSpace(sources.HistoryDir) != "" {
+		reports, err := LoadReportHistory(sources.HistoryDir)
+		if err != nil {
+			return nil, err
+		}
+		historyRuns = len(reports)
+		if historyRuns > 0 {
+			latest = &reports[historyRuns-1]
+		}
+	}
+
+	families = append(families, MetricFamily{
+		Name:    "meta_ops_report_history_runs",
+		Help:    "Number of ops run reports recorded in the history directory.",
+		Type:    MetricTypeGauge,
+		Samples: []MetricSample{{Value: float64(historyRuns)}},
+	})
+	families = append(families, lastRunMetricFamilies(latest)...)
+
+	rateTelemetry, hasRateTelemetry, err := resolveMetricsRateTelemetry(sources, latest)
+	if err != nil {
+		return nil, err
+	}
+	if hasRateTelemetry {
+		families = append(families, rateLimitMetricFamily(rateTelemetry))
+	}
+
+	if len(sources.Profiles) > 0 {
+		families = append(families, tokenTTLMetricFamily(sources.Profiles, now))
+	}
+	return families, nil
+}
+
+func WriteMetrics(w io.Writer, families []MetricFamily) error {
+	if w == nil {
+		return errors.New("writer is nil")
+	}
+	buffered := bufio.NewWriter(w)
+	for _, family := range families {
+		if _, err := fmt.Fprintf(buffered, "# HELP %s %s\n", family.Name, escapeMetricHelp(family.Help)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(buffered, "# TYPE %s %s\n", family.Name, family.Type); err != nil {
+			return err
+		}
+		for _, sample := range family.Samples {
+			if _, err := fmt.Fprintf(buffered, "%s%s %s\n", family.Name, formatMetricLabels(sample.Labels), formatMetricValue(sample.Value)); err != nil {
+				return err
+			}
+		}
+	}
+	return buffered.Flush()
+}
+
+func NewMetricsHandler(collect func() ([]MetricFamily, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		families, err := collect()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("collect metrics: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", metricsContentType)
+		if r.Method == http.MethodHead {
+			return
+		}
+		_ = WriteMetrics(w, families)
+	})
+}
+
+func lastRunMetricFamilies(latest *StoredReport) []MetricFamily {
+	available := 0.0
+	if latest != nil {
+		available = 1
+	}
+	families := []MetricFamily{{
+		Name:    "meta_ops_last_run_available",
+		Help:    "Whether a recorded ops run report is available (1) or not (0).",
+		Type:    MetricTypeGauge,
+		Samples: []MetricSample{{Value: available}},
+	}}
+	if latest == nil {
+		return families
+	}
+
+	report := latest.Entry.Report
+	outcome := RunOutcomeForReport(report)
+	outcomeSamples := make([]MetricSample, 0, 4)
+	for _, candidate := range []string{RunOutcomeClean, RunOutcomeWarning, RunOutcomeBlocking, RunOutcomeError} {
+		value := 0.0
+		if candidate == outcome {
+			value = 1
+		}
+		outcomeSamples = append(outcomeSamples, MetricSample{Labels: map[string]string{"outcome": candidate}, Value: value})
+	}
+
+	sectionByCheck := make(map[string]string, len(report.Checks))
+	for _, section := range report.Sections {
+		for _, check := range section.Checks {
+			sectionByCheck[check.Name] = section.Name
+		}
+	}
+	statusSamples := make([]MetricSample, 0, len(report.Checks))
+	blockingSamples := make([]MetricSample, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		section := sectionByCheck[check.Name]
+		if section == "" {
+			section = reportSectionOther
+		}
+		labels := map[string]string{"check": check.Name, "section": section}
+		status := 0.0
+		if check.Status == CheckStatusPass {
+			status = 1
+		}
+		blocking := 0.0
+		if check.Status != CheckStatusPass && check.Blocking {
+			blocking = 1
+		}
+		statusSamples = append(statusSamples, MetricSample{Labels: labels, Value: status})
+		blockingSamples = append(blockingSamples, MetricSample{Labels: labels, Value: blocking})
+	}
+
+	return append(families,
+		MetricFamily{
+			Name:    "meta_ops_last_run_timestamp_seconds",
+			Help:    "Unix timestamp of the latest recorded ops run.",
+			Type:    MetricTypeGauge,
+			Samples: []MetricSample{{Value: float64(latest.Entry.RecordedAt.Unix())}},
+		},
+		MetricFamily{
+			Name:    "meta_ops_last_run_outcome",
+			Help:    "Outcome of the latest recorded ops run (1 for the active outcome).",
+			Type:    MetricTypeGauge,
+			Samples: outcomeSamples,
+		},
+		MetricFamily{
+			Name: "meta_ops_last_run_checks",
+			Help: "Check counts of the latest recorded ops run by result.",
+			Type: MetricTypeGauge,
+			Samples: []MetricSample{
+				{Labels: map[string]string{"result": "total"}, Value: float64(report.Summary.Total)},
+				{Labels: map[string]string{"result": "passed"}, Value: float64(report.Summary.Passed)},
+				{Labels: map[string]string{"result": "failed"}, Value: float64(report.Summary.Failed)},
+				{Labels: map[string]string{"result": "warnings"}, Value: float64(report.Summary.Warnings)},
+				{Labels: map[string]string{"result": "blocking"}, Value: float64(report.Summary.Blocking)},
+			},
+		},
+		MetricFamily{
+			Name:    "meta_ops_check_status",
+			Help:    "Status of each check in the latest recorded ops run (1 pass, 0 fail).",
+			Type:    MetricTypeGauge,
+			Samples: statusSamples,
+		},
+		MetricFamily{
+			Name:    "meta_ops_check_blocking",
+			Help:    "Whether each check in the latest recorded ops run is a blocking failure.",
+			Type:    MetricTypeGauge,
+			Samples: blockingSamples,
+		},
+	)
+}
+
+func resolveMetricsRateTelemetry(sources MetricsSources, latest *StoredReport) (RateLimitTelemetrySnapshot, bool, error) {
+	if sources.RateLimitTelemetry != nil {
+		if err := sources.RateLimitTelemetry.Validate(); err != nil {
+			return RateLimitTelemetrySnapshot{}, false, err
+		}
+		return *sources.RateLimitTelemetry, true, nil
+	}
+	if latest != nil {
+		return latest.Entry.Report.Baseline.Snapshots.RateLimit, true, nil
+	}
+	if strings.TrimSpace(sources.StatePath) == "" {
+		return RateLimitTelemetrySnapshot{}, false, nil
+	}
+	state, err := LoadBaseline(sources.StatePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return RateLimitTelemetrySnapshot{}, false, nil
+		}
+		return RateLimitTelemetrySnapshot{}, false, err
+	}
+	return state.Snapshots.RateLimit, true, nil
+}
+
+func rateLimitMetricFamily(snapshot RateLimitTelemetrySnapshot) MetricFamily {
+	metrics := []struct {
+		name  string
+		value int
+	}{
+		{name: "app_call_count", value: snapshot.AppCallCount},
+		{name: "app_total_cputime", value: snapshot.AppTotalCPUTime},
+		{name: "app_total_time", value: snapshot.AppTotalTime},
+		{name: "page_call_count", value: snapshot.PageCallCount},
+		{name: "page_total_cputime", value: snapshot.PageTotalCPUTime},
+		{name: "page_total_time", value: snapshot.PageTotalTime},
+		{name: "ad_account_util_pct", value: snapshot.AdAccountUtilPct},
+	}
+	samples := make([]MetricSample, 0, len(metrics))
+	for _, metric := range metrics {
+		samples = append(samples, MetricSample{Labels: map[string]string{"metric": metric.name}, Value: float64(metric.value)})
+	}
+	return MetricFamily{
+		Name:    "meta_ops_rate_limit_utilization_percent",
+		Help:    "Graph API rate-limit utilization percentage by usage metric.",
+		Type:    MetricTypeGauge,
+		Samples: samples,
+	}
+}
+
+func tokenTTLMetricFamily(profiles map[string]config.Profile, now time.Time) MetricFamily {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	samples := make([]MetricSample, 0, len(names))
+	for _, name := range names {
+		profile := profiles[name]
+		expiresAt, err := time.Parse(time.RFC3339, strings.TrimSpace(profile.ExpiresAt))
+		if err != nil {
+			continue
+		}
+		samples = append(samples, MetricSample{
+			Labels: map[string]string{"profile": name, "token_type": profile.TokenType},
+			Value:  float64(int64(expiresAt.Sub(now).Seconds())),
+		})
+	}
+	return MetricFamily{
+		Name:    "meta_auth_token_ttl_seconds",
+		Help:    "Seconds until the profile access token expires (negative when expired).",
+		Type:    MetricTypeGauge,
+		Samples: samples,
+	}
+}
+
+func formatMetricLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=\"%s\"", key, escapeMetricLabelValue(labels[key])))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatMetricValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func escapeMetricLabelValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
+
+func escapeMetricHelp(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
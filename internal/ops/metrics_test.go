@@ -0,0 +1,126 @@
+package ops
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+)
+
+func TestCollectMetricsExposesLastRunChecksAndTokenTTL(t *testing.T) {
+	t.Parallel()
+
+	historyDir := filepath.Join(t.TempDir(), "reports")
+	recordedAt := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	report := Report{
+		Outcome: RunOutcomeWarning,
+		Summary: Summary{Total: 2, Passed: 1, Failed: 1, Warnings: 1},
+		Checks: []Check{
+			{Name: checkNameSchemaPackDrift, Status: CheckStatusPass},
+			{Name: checkNameRateLimitThreshold, Status: CheckStatusFail},
+		},
+	}
+	report.Sections = composeReportSections(report.Checks)
+	report.Baseline.Snapshots.RateLimit = RateLimitTelemetrySnapshot{AppCallCount: 61}
+	if _, err := SaveReportHistoryEntry(historyDir, ReportHistoryEntry{RecordedAt: recordedAt, Report: report}); err != nil {
+		t.Fatalf("save report history: %v", err)
+	}
+
+	families, err := CollectMetrics(MetricsSources{
+		HistoryDir: historyDir,
+		Profiles: map[string]config.Profile{
+			"prod": {TokenType: "user", ExpiresAt: recordedAt.Add(2 * time.Hour).Format(time.RFC3339)},
+			"ci":   {TokenType: "system_user"},
+		},
+		Now: func() time.Time { return recordedAt },
+	})
+	if err != nil {
+		t.Fatalf("collect metrics: %v", err)
+	}
+
+	var output bytes.Buffer
+	if err := WriteMetrics(&output, families); err != nil {
+		t.Fatalf("write metrics: %v", err)
+	}
+	text := output.String()
+	for _, expected := range []string{
+		"# TYPE meta_ops_check_status gauge\n",
+		"meta_ops_report_history_runs 1\n",
+		"meta_ops_last_run_available 1\n",
+		"meta_ops_last_run_timestamp_seconds 1772366400\n",
+		`meta_ops_last_run_outcome{outcome="warning"} 1` + "\n",
+		`meta_ops_last_run_outcome{outcome="clean"} 0` + "\n",
+		`meta_ops_check_status{check="schema_pack_drift",section="drift"} 1` + "\n",
+		`meta_ops_check_status{check="rate_limit_threshold",section="rate_limit"} 0` + "\n",
+		`meta_ops_check_blocking{check="rate_limit_threshold",section="rate_limit"} 0` + "\n",
+		`meta_ops_rate_limit_utilization_percent{metric="app_call_count"} 61` + "\n",
+		`meta_auth_token_ttl_seconds{profile="prod",token_type="user"} 7200` + "\n",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected metrics output to contain %q\n%s", expected, text)
+		}
+	}
+	if strings.Contains(text, `profile="ci"`) {
+		t.Fatalf("expected profile without expires_at to be omitted\n%s", text)
+	}
+}
+
+func TestCollectMetricsWithoutHistoryReportsUnavailableLastRun(t *testing.T) {
+	t.Parallel()
+
+	families, err := CollectMetrics(MetricsSources{
+		HistoryDir: filepath.Join(t.TempDir(), "reports"),
+		StatePath:  filepath.Join(t.TempDir(), "missing-state.json"),
+	})
+	if err != nil {
+		t.Fatalf("collect metrics: %v", err)
+	}
+	var output bytes.Buffer
+	if err := WriteMetrics(&output, families); err != nil {
+		t.Fatalf("write metrics: %v", err)
+	}
+	text := output.String()
+	if !strings.Contains(text, "meta_ops_last_run_available 0\n") {
+		t.Fatalf("expected unavailable last run\n%s", text)
+	}
+	if strings.Contains(text, "meta_ops_check_status") || strings.Contains(text, "meta_ops_rate_limit_utilization_percent") {
+		t.Fatalf("expected no check or rate-limit samples without sources\n%s", text)
+	}
+}
+
+func TestMetricsHandlerServesTextExposition(t *testing.T) {
+	t.Parallel()
+
+	handler := NewMetricsHandler(func() ([]MetricFamily, error) {
+		return []MetricFamily{{
+			Name:    "meta_test_metric",
+			Help:    "Test metric.",
+			Type:    MetricTypeGauge,
+			Samples: []MetricSample{{Labels: map[string]string{"name": "a\"b"}, Value: 2}},
+		}}, nil
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type: %s", contentType)
+	}
+	expected := "# HELP meta_test_metric Test metric.\n# TYPE meta_test_metric gauge\nmeta_test_metric{name=\"a\\\"b\"} 2\n"
+	if recorder.Body.String() != expected {
+		t.Fatalf("unexpected body:\n%s", recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, MetricsPath, nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected status for POST: %d", recorder.Code)
+	}
+}
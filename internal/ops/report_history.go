@@ -0,0 +1,289 @@
+package ops
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const ReportHistorySchemaVersion = 1
+
+const (
+	reportHistoryDirName       = "reports"
+	reportHistoryFilePrefix    = "report-"
+	reportHistoryFileSuffix    = ".json"
+	reportHistoryTimestampForm = "20060102T150405.000000000Z"
+)
+
+var (
+	ErrReportHistoryDirRequired  = errors.New("report history directory is required")
+	ErrReportHistoryInsufficient = errors.New("report history requires at least two recorded runs")
+)
+
+type ReportHistoryEntry struct {
+	SchemaVersion int       `json:"schema_version"`
+	RecordedAt    time.Time `json:"recorded_at"`
+	StatePath     string    `json:"state_path"`
+	Report        Report    `json:"report"`
+}
+
+type StoredReport struct {
+	Path  string
+	Entry ReportHistoryEntry
+}
+
+type ReportDiffResult struct {
+	HistoryDir         string               `json:"history_dir"`
+	Previous           ReportDiffRun        `json:"previous"`
+	Latest             ReportDiffRun        `json:"latest"`
+	OutcomeChanged     bool                 `json:"outcome_changed"`
+	SummaryDelta       Summary              `json:"summary_delta"`
+	NewFailures        []Check              `json:"new_failures"`
+	ResolvedFindings   []Check              `json:"resolved_findings"`
+	PersistingFailures []Check              `json:"persisting_failures"`
+	Regression         ReportDiffRegression `json:"regression"`
+}
+
+type ReportDiffRun struct {
+	Path       string    `json:"path"`
+	RecordedAt time.Time `json:"recorded_at"`
+	Outcome    string    `json:"outcome"`
+	Summary    Summary   `json:"summary"`
+}
+
+type ReportDiffRegression struct {
+	NewWarnings int `json:"new_warnings"`
+	NewBlocking int `json:"new_blocking"`
+	Resolved    int `json:"resolved"`
+}
+
+func DefaultReportHistoryDir(statePath string) (string, error) {
+	statePath = strings.TrimSpace(statePath)
+	if statePath == "" {
+		return "", ErrStatePathRequired
+	}
+	return filepath.Join(filepath.Dir(statePath), reportHistoryDirName), nil
+}
+
+func SaveReportHistoryEntry(dir string, entry ReportHistoryEntry) (string, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return "", ErrReportHistoryDirRequired
+	}
+	if entry.RecordedAt.IsZero() {
+		return "", errors.New("report history entry recorded_at is required")
+	}
+	entry.SchemaVersion = ReportHistorySchemaVersion
+	entry.RecordedAt = entry.RecordedAt.UTC()
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("create report history directory %s: %w", dir, err)
+	}
+
+	path := filepath.Join(dir, reportHistoryFilePrefix+entry.RecordedAt.Format(reportHistoryTimestampForm)+reportHistoryFileSuffix)
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("report history entry already exists at %s", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("stat report history entry %s: %w", path, err)
+	}
+
+	payload, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal report history entry: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".report-*.json")
+	if err != nil {
+		return "", fmt.Errorf("create temp report history file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("write temp report history file: %w", err)
+	}
+	if err := tmpFile.Chmod(0o600); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("chmod temp report history file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return "", fmt.Errorf("close temp report history file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return "", fmt.Errorf("write report history entry %s: %w", path, err)
+	}
+	return path, nil
+}
+
+func LoadReportHistory(dir string) ([]StoredReport, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return nil, ErrReportHistoryDirRequired
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []StoredReport{}, nil
+		}
+		return nil, fmt.Errorf("read report history directory %s: %w", dir, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, reportHistoryFilePrefix) || !strings.HasSuffix(name, reportHistoryFileSuffix) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	reports := make([]StoredReport, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		entry, err := loadReportHistoryEntry(path)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, StoredReport{Path: path, Entry: entry})
+	}
+	return reports, nil
+}
+
+func DiffLatestReports(dir string) (ReportDiffResult, error) {
+	reports, err := LoadReportHistory(dir)
+	if err != nil {
+		return ReportDiffResult{}, err
+	}
+	if len(reports) < 2 {
+		return ReportDiffResult{}, fmt.Errorf("%w: found %d in %s", ErrReportHistoryInsufficient, len(reports), strings.TrimSpace(dir))
+	}
+
+	result := DiffReports(reports[len(reports)-2], reports[len(reports)-1])
+	result.HistoryDir = strings.TrimSpace(dir)
+	return result, nil
+}
+
+func DiffReports(previous StoredReport, latest StoredReport) ReportDiffResult {
+	previousReport := previous.Entry.Report
+	latestReport := latest.Entry.Report
+
+	result := ReportDiffResult{
+		Previous:           newReportDiffRun(previous),
+		Latest:             newReportDiffRun(latest),
+		NewFailures:        []Check{},
+		ResolvedFindings:   []Check{},
+		PersistingFailures: []Check{},
+	}
+	result.OutcomeChanged = result.Previous.Outcome != result.Latest.Outcome
+	result.SummaryDelta = Summary{
+		Total:    latestReport.Summary.Total - previousReport.Summary.Total,
+		Passed:   latestReport.Summary.Passed - previousReport.Summary.Passed,
+		Failed:   latestReport.Summary.Failed - previousReport.Summary.Failed,
+		Warnings: latestReport.Summary.Warnings - previousReport.Summary.Warnings,
+		Blocking: latestReport.Summary.Blocking - previousReport.Summary.Blocking,
+	}
+
+	previousFailures := failingChecksByName(previousReport.Checks)
+	latestFailures := failingChecksByName(latestReport.Checks)
+
+	for _, check := range latestReport.Checks {
+		if check.Status == CheckStatusPass {
+			continue
+		}
+		previousCheck, failedBefore := previousFailures[check.Name]
+		if failedBefore && previousCheck.Blocking == check.Blocking {
+			result.PersistingFailures = append(result.PersistingFailures, check)
+			continue
+		}
+		result.NewFailures = append(result.NewFailures, check)
+		if check.Blocking {
+			result.Regression.NewBlocking++
+		} else {
+			result.Regression.NewWarnings++
+		}
+	}
+	for _, check := range previousReport.Checks {
+		if check.Status == CheckStatusPass {
+			continue
+		}
+		if _, stillFailing := latestFailures[check.Name]; stillFailing {
+			continue
+		}
+		result.ResolvedFindings = append(result.ResolvedFindings, check)
+		result.Regression.Resolved++
+	}
+	return result
+}
+
+func ReportDiffExitCode(result ReportDiffResult) int {
+	if result.Regression.NewBlocking > 0 {
+		return ExitCodePolicy
+	}
+	if result.Regression.NewWarnings > 0 {
+		return ExitCodeWarning
+	}
+	return ExitCodeSuccess
+}
+
+func newReportDiffRun(stored StoredReport) ReportDiffRun {
+	return ReportDiffRun{
+		Path:       stored.Path,
+		RecordedAt: stored.Entry.RecordedAt,
+		Outcome:    RunOutcomeForReport(stored.Entry.Report),
+		Summary:    stored.Entry.Report.Summary,
+	}
+}
+
+func failingChecksByName(checks []Check) map[string]Check {
+	failures := make(map[string]Check, len(checks))
+	for _, check := range checks {
+		if check.Status == CheckStatusPass {
+			continue
+		}
+		if _, exists := failures[check.Name]; exists {
+			continue
+		}
+		failures[check.Name] = check
+	}
+	return failures
+}
+
+func loadReportHistoryEntry(path string) (ReportHistoryEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ReportHistoryEntry{}, fmt.Errorf("read report history entry %s: %w", path, err)
+	}
+
+	var entry ReportHistoryEntry
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&entry); err != nil {
+		return ReportHistoryEntry{}, fmt.Errorf("decode report history entry %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return ReportHistoryEntry{}, fmt.Errorf("decode report history entry %s: multiple JSON values", path)
+		}
+		return ReportHistoryEntry{}, fmt.Errorf("decode report history entry %s: %w", path, err)
+	}
+	if entry.SchemaVersion != ReportHistorySchemaVersion {
+		return ReportHistoryEntry{}, fmt.Errorf(
+			"unsupported report history schema_version=%d in %s (expected %d)",
+			entry.SchemaVersion,
+			path,
+			ReportHistorySchemaVersion,
+		)
+	}
+	return entry, nil
+}
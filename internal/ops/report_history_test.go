@@ -0,0 +1,138 @@
+package ops
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveReportHistoryEntryOrdersRunsChronologically(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "reports")
+	later := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	earlier := later.Add(-24 * time.Hour)
+
+	if _, err := SaveReportHistoryEntry(dir, ReportHistoryEntry{RecordedAt: later, Report: Report{Outcome: RunOutcomeWarning}}); err != nil {
+		t.Fatalf("save later entry: %v", err)
+	}
+	if _, err := SaveReportHistoryEntry(dir, ReportHistoryEntry{RecordedAt: earlier, Report: Report{Outcome: RunOutcomeClean}}); err != nil {
+		t.Fatalf("save earlier entry: %v", err)
+	}
+	if _, err := SaveReportHistoryEntry(dir, ReportHistoryEntry{RecordedAt: later}); err == nil {
+		t.Fatal("expected duplicate timestamp to be rejected")
+	}
+
+	reports, err := LoadReportHistory(dir)
+	if err != nil {
+		t.Fatalf("load report history: %v", err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("expected two reports, got %d", len(reports))
+	}
+	if !reports[0].Entry.RecordedAt.Equal(earlier) || !reports[1].Entry.RecordedAt.Equal(later) {
+		t.Fatalf("unexpected report order: %s then %s", reports[0].Entry.RecordedAt, reports[1].Entry.RecordedAt)
+	}
+	if reports[0].Entry.SchemaVersion != ReportHistorySchemaVersion {
+		t.Fatalf("unexpected schema version: %d", reports[0].Entry.SchemaVersion)
+	}
+}
+
+func TestDiffReportsClassifiesNewResolvedAndPersistingFindings(t *testing.T) {
+	t.Parallel()
+
+	previous := StoredReport{Entry: ReportHistoryEntry{Report: Report{
+		Outcome: RunOutcomeBlocking,
+		Summary: Summary{Total: 3, Passed: 1, Failed: 2, Warnings: 1, Blocking: 1},
+		Checks: []Check{
+			{Name: checkNameChangelogOCCDelta, Status: CheckStatusFail, Blocking: true},
+			{Name: checkNameRateLimitThreshold, Status: CheckStatusFail},
+			{Name: checkNameSchemaPackDrift, Status: CheckStatusPass},
+		},
+	}}}
+	latest := StoredReport{Entry: ReportHistoryEntry{Report: Report{
+		Outcome: RunOutcomeBlocking,
+		Summary: Summary{Total: 3, Passed: 1, Failed: 2, Warnings: 0, Blocking: 2},
+		Checks: []Check{
+			{Name: checkNameChangelogOCCDelta, Status: CheckStatusPass},
+			{Name: checkNameRateLimitThreshold, Status: CheckStatusFail, Blocking: true},
+			{Name: checkNameSchemaPackDrift, Status: CheckStatusFail, Blocking: true},
+		},
+	}}}
+
+	diff := DiffReports(previous, latest)
+	if diff.OutcomeChanged {
+		t.Fatal("expected unchanged outcome")
+	}
+	if len(diff.NewFailures) != 2 {
+		t.Fatalf("expected escalation and new drift as new failures, got %+v", diff.NewFailures)
+	}
+	if diff.NewFailures[0].Name != checkNameRateLimitThreshold || diff.NewFailures[1].Name != checkNameSchemaPackDrift {
+		t.Fatalf("unexpected new failures: %+v", diff.NewFailures)
+	}
+	if len(diff.ResolvedFindings) != 1 || diff.ResolvedFindings[0].Name != checkNameChangelogOCCDelta {
+		t.Fatalf("unexpected resolved findings: %+v", diff.ResolvedFindings)
+	}
+	if len(diff.PersistingFailures) != 0 {
+		t.Fatalf("unexpected persisting failures: %+v", diff.PersistingFailures)
+	}
+	if diff.SummaryDelta.Warnings != -1 || diff.SummaryDelta.Blocking != 1 {
+		t.Fatalf("unexpected summary delta: %+v", diff.SummaryDelta)
+	}
+	if diff.Regression.NewBlocking != 2 || diff.Regression.Resolved != 1 {
+		t.Fatalf("unexpected regression totals: %+v", diff.Regression)
+	}
+	if code := ReportDiffExitCode(diff); code != ExitCodePolicy {
+		t.Fatalf("unexpected exit code: got=%d want=%d", code, ExitCodePolicy)
+	}
+}
+
+func TestDiffLatestReportsRequiresTwoRuns(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if _, err := SaveReportHistoryEntry(dir, ReportHistoryEntry{RecordedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("save entry: %v", err)
+	}
+	_, err := DiffLatestReports(dir)
+	if !errors.Is(err, ErrReportHistoryInsufficient) {
+		t.Fatalf("expected insufficient history error, got %v", err)
+	}
+}
+
+func TestRunWithOptionsPersistsReportHistory(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	statePath := filepath.Join(tempDir, "baseline-state.json")
+	if _, err := InitBaseline(statePath); err != nil {
+		t.Fatalf("init baseline: %v", err)
+	}
+	historyDir, err := DefaultReportHistoryDir(statePath)
+	if err != nil {
+		t.Fatalf("default history dir: %v", err)
+	}
+	if historyDir != filepath.Join(tempDir, "reports") {
+		t.Fatalf("unexpected history dir: %s", historyDir)
+	}
+
+	result, err := RunWithOptions(statePath, RunOptions{OptionalModulePolicy: OptionalModulePolicySkip, HistoryDir: historyDir})
+	if err != nil {
+		t.Fatalf("run with options: %v", err)
+	}
+	if filepath.Dir(result.HistoryPath) != historyDir {
+		t.Fatalf("unexpected history path: %s", result.HistoryPath)
+	}
+
+	reports, err := LoadReportHistory(historyDir)
+	if err != nil {
+		t.Fatalf("load report history: %v", err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("expected one persisted report, got %d", len(reports))
+	}
+	if reports[0].Entry.StatePath != statePath || reports[0].Entry.Report.Outcome != RunOutcomeClean {
+		t.Fatalf("unexpected persisted entry: %+v", reports[0].Entry)
+	}
+}
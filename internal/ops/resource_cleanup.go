@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/bilalbayram/metacli/internal/filelock"
 	"github.com/bilalbayram/metacli/internal/graph"
 )
 
@@ -137,6 +138,12 @@ func CleanupResourceLedger(ctx context.Context, ledgerPath string, options Clean
 		return CleanupResult{}, ErrResourceLedgerPathRequired
 	}
 
+	lock, err := filelock.Acquire(ledgerPath)
+	if err != nil {
+		return CleanupResult{}, err
+	}
+	defer lock.Release()
+
 	ledger, err := LoadResourceLedger(ledgerPath)
 	if err != nil {
 		return CleanupResult{}, err
@@ -220,7 +227,7 @@ func CleanupResourceLedger(ctx context.Context, ledgerPath string, options Clean
 	result.Summary = summarizeCleanupResults(result.Resources, len(remaining))
 	if options.Apply {
 		ledger.Resources = remaining
-		if err := SaveResourceLedger(ledgerPath, ledger); err != nil {
+		if err := saveResourceLedger(lock, ledgerPath, ledger); err != nil {
 			return CleanupResult{}, err
 		}
 	}
@@ -9,6 +9,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
 )
 
 const ResourceLedgerSchemaVersion = 1
@@ -119,6 +121,12 @@ func LoadResourceLedger(path string) (ResourceLedger, error) {
 }
 
 func SaveResourceLedger(path string, ledger ResourceLedger) error {
+	return saveResourceLedger(nil, path, ledger)
+}
+
+// saveResourceLedger writes ledger for a caller that may already hold its
+// lock.
+func saveResourceLedger(held *filelock.Lock, path string, ledger ResourceLedger) error {
 	path = strings.TrimSpace(path)
 	if path == "" {
 		return ErrResourceLedgerPathRequired
@@ -127,6 +135,12 @@ func SaveResourceLedger(path string, ledger ResourceLedger) error {
 		return err
 	}
 
+	lock, err := held.Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o700); err != nil {
 		return fmt.Errorf("create resource ledger directory for %s: %w", path, err)
@@ -171,6 +185,12 @@ func AppendResourceLedgerEntry(path string, entry TrackedResource) (ResourceLedg
 		return ResourceLedger{}, err
 	}
 
+	lock, err := filelock.Acquire(path)
+	if err != nil {
+		return ResourceLedger{}, err
+	}
+	defer lock.Release()
+
 	ledger, err := loadResourceLedgerForAppend(path)
 	if err != nil {
 		return ResourceLedger{}, err
@@ -192,7 +212,7 @@ func AppendResourceLedgerEntry(path string, entry TrackedResource) (ResourceLedg
 
 	entry.Sequence = nextTrackedResourceSequence(ledger.Resources)
 	ledger.Resources = append(ledger.Resources, entry)
-	if err := SaveResourceLedger(path, ledger); err != nil {
+	if err := saveResourceLedger(lock, path, ledger); err != nil {
 		return ResourceLedger{}, err
 	}
 	return ledger, nil
@@ -241,13 +261,9 @@ func (r TrackedResource) validate(requireSequence bool) error {
 		return errors.New("sequence must be greater than zero")
 	}
 
-	resourceKind := strings.TrimSpace(r.ResourceKind)
-	if resourceKind == "" {
-		return ErrResourceKindRequired
-	}
-	cleanupActions, ok := allowedCleanupActionsByResourceKind[resourceKind]
-	if !ok {
-		return fmt.Errorf("unsupported resource kind %q", r.ResourceKind)
+	cleanupActions, err := cleanupActionsForResourceKind(r.ResourceKind)
+	if err != nil {
+		return err
 	}
 
 	resourceID := strings.TrimSpace(r.ResourceID)
@@ -258,20 +274,47 @@ func (r TrackedResource) validate(requireSequence bool) error {
 		return ErrResourceCommandRequired
 	}
 
-	cleanupAction := strings.TrimSpace(r.CleanupAction)
-	if cleanupAction == "" {
+	if err := validateCleanupAction(cleanupActions, r.ResourceKind, r.CleanupAction); err != nil {
+		return err
+	}
+	if err := validateTrackedResourceMetadata(r.Metadata); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ValidateResourceCleanupAction(resourceKind string, cleanupAction string) error {
+	cleanupActions, err := cleanupActionsForResourceKind(resourceKind)
+	if err != nil {
+		return err
+	}
+	return validateCleanupAction(cleanupActions, resourceKind, cleanupAction)
+}
+
+func cleanupActionsForResourceKind(resourceKind string) (map[string]struct{}, error) {
+	trimmed := strings.TrimSpace(resourceKind)
+	if trimmed == "" {
+		return nil, ErrResourceKindRequired
+	}
+	cleanupActions, ok := allowedCleanupActionsByResourceKind[trimmed]
+	if !ok {
+		return nil, fmt.Errorf("unsupported resource kind %q", resourceKind)
+	}
+	return cleanupActions, nil
+}
+
+func validateCleanupAction(cleanupActions map[string]struct{}, resourceKind string, cleanupAction string) error {
+	trimmed := strings.TrimSpace(cleanupAction)
+	if trimmed == "" {
 		return ErrCleanupActionRequired
 	}
-	if _, exists := cleanupActions[cleanupAction]; !exists {
+	if _, exists := cleanupActions[trimmed]; !exists {
 		return fmt.Errorf(
 			"cleanup action %q is not supported for resource kind %q",
-			r.CleanupAction,
-			r.ResourceKind,
+			cleanupAction,
+			resourceKind,
 		)
 	}
-	if err := validateTrackedResourceMetadata(r.Metadata); err != nil {
-		return err
-	}
 	return nil
 }
 
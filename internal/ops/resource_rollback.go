@@ -0,0 +1,137 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+)
+
+const (
+	CleanupModeRollback = "rollback"
+
+	// TrackedRunMetadataKey tags ledger entries with the composed run (workflow run,
+	// bulk import) that created them so the run can be rolled back as a unit.
+	TrackedRunMetadataKey = "run_id"
+)
+
+var ErrRollbackRunIDRequired = errors.New("rollback requires a run id")
+
+type RollbackOptions struct {
+	RunID string
+	// Version is used for entries recorded without a graph version.
+	Version string
+	// Credentials resolves the token for the profile an entry was created with.
+	Credentials func(profile string) (token string, appSecret string, err error)
+	Executor    CleanupExecutor
+}
+
+// RollbackTrackedRun applies the cleanup action of every ledger entry created by one
+// run, newest first so children are handled before their parents. Entries that were
+// cleaned up are dropped from the ledger; failures stay for `ops cleanup`.
+func RollbackTrackedRun(ctx context.Context, ledgerPath string, options RollbackOptions) (CleanupResult, error) {
+	ledgerPath = strings.TrimSpace(ledgerPath)
+	if ledgerPath == "" {
+		return CleanupResult{}, ErrResourceLedgerPathRequired
+	}
+	runID := strings.TrimSpace(options.RunID)
+	if runID == "" {
+		return CleanupResult{}, ErrRollbackRunIDRequired
+	}
+	if options.Credentials == nil {
+		return CleanupResult{}, ErrCleanupApplyTokenRequired
+	}
+
+	result := CleanupResult{
+		LedgerPath: ledgerPath,
+		Mode:       CleanupModeRollback,
+		Resources:  []CleanupResourceResult{},
+	}
+	lock, err := filelock.Acquire(ledgerPath)
+	if err != nil {
+		return CleanupResult{}, err
+	}
+	defer lock.Release()
+
+	ledger, err := LoadResourceLedger(ledgerPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return result, nil
+		}
+		return CleanupResult{}, err
+	}
+
+	executor := options.Executor
+	if executor == nil {
+		executor = NewGraphCleanupExecutor(nil)
+	}
+
+	cleaned := map[int]struct{}{}
+	for index := len(ledger.Resources) - 1; index >= 0; index-- {
+		resource := ledger.Resources[index]
+		if resource.Metadata[TrackedRunMetadataKey] != runID {
+			continue
+		}
+		resourceResult := CleanupResourceResult{
+			Sequence:      resource.Sequence,
+			Command:       resource.Command,
+			ResourceKind:  resource.ResourceKind,
+			ResourceID:    resource.ResourceID,
+			CleanupAction: resource.CleanupAction,
+		}
+
+		err := rollbackTrackedResource(ctx, executor, options, resource)
+		if err != nil {
+			resourceResult.Classification = CleanupClassificationFailed
+			resourceResult.Message = err.Error()
+			result.Resources = append(result.Resources, resourceResult)
+			continue
+		}
+		resourceResult.Classification = CleanupClassificationApplied
+		resourceResult.Success = true
+		resourceResult.Message = fmt.Sprintf(
+			"%s %s %s",
+			resource.CleanupAction,
+			resource.ResourceKind,
+			resource.ResourceID,
+		)
+		result.Resources = append(result.Resources, resourceResult)
+		cleaned[resource.Sequence] = struct{}{}
+	}
+
+	remaining := make([]TrackedResource, 0, len(ledger.Resources)-len(cleaned))
+	for _, resource := range ledger.Resources {
+		if _, ok := cleaned[resource.Sequence]; !ok {
+			remaining = append(remaining, resource)
+		}
+	}
+	result.Summary = summarizeCleanupResults(result.Resources, len(remaining))
+	if len(cleaned) > 0 {
+		ledger.Resources = remaining
+		if err := saveResourceLedger(lock, ledgerPath, ledger); err != nil {
+			return CleanupResult{}, err
+		}
+	}
+	return result, nil
+}
+
+func rollbackTrackedResource(ctx context.Context, executor CleanupExecutor, options RollbackOptions, resource TrackedResource) error {
+	version := resource.GraphVersion
+	if version == "" {
+		version = strings.TrimSpace(options.Version)
+	}
+	if version == "" {
+		return ErrCleanupApplyVersionRequired
+	}
+	token, appSecret, err := options.Credentials(resource.Profile)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(token) == "" {
+		return ErrCleanupApplyTokenRequired
+	}
+	return applyCleanupAction(ctx, executor, version, token, appSecret, resource)
+}
@@ -0,0 +1,111 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestRollbackTrackedRunCleansOnlyRunResourcesNewestFirst(t *testing.T) {
+	t.Parallel()
+
+	ledgerPath := filepath.Join(t.TempDir(), "resource-ledger.json")
+	ledger := NewResourceLedger()
+	ledger.Resources = append(ledger.Resources,
+		TrackedResource{
+			Sequence:      1,
+			Command:       "meta campaign create",
+			ResourceKind:  ResourceKindCampaign,
+			ResourceID:    "cmp_other",
+			CleanupAction: CleanupActionPause,
+			Profile:       "prod",
+			GraphVersion:  "v25.0",
+		},
+		TrackedResource{
+			Sequence:      2,
+			Command:       "meta campaign create",
+			ResourceKind:  ResourceKindCampaign,
+			ResourceID:    "cmp_1001",
+			CleanupAction: CleanupActionPause,
+			Profile:       "prod",
+			GraphVersion:  "v25.0",
+			Metadata:      map[string]string{TrackedRunMetadataKey: "workflow-1"},
+		},
+		TrackedResource{
+			Sequence:      3,
+			Command:       "meta creative create",
+			ResourceKind:  ResourceKindCreative,
+			ResourceID:    "cr_2001",
+			CleanupAction: CleanupActionDelete,
+			Profile:       "prod",
+			Metadata:      map[string]string{TrackedRunMetadataKey: "workflow-1"},
+		},
+		TrackedResource{
+			Sequence:      4,
+			Command:       "meta adset create",
+			ResourceKind:  ResourceKindAdSet,
+			ResourceID:    "as_3001",
+			CleanupAction: CleanupActionPause,
+			Profile:       "prod",
+			GraphVersion:  "v25.0",
+			Metadata:      map[string]string{TrackedRunMetadataKey: "workflow-1"},
+		},
+	)
+	if err := SaveResourceLedger(ledgerPath, ledger); err != nil {
+		t.Fatalf("save ledger: %v", err)
+	}
+
+	executor := &cleanupExecutorStub{
+		deleteErrors: map[string]error{"cr_2001": errors.New("delete failed")},
+	}
+	profiles := make([]string, 0)
+	result, err := RollbackTrackedRun(context.Background(), ledgerPath, RollbackOptions{
+		RunID:   "workflow-1",
+		Version: "v25.0",
+		Credentials: func(profile string) (string, string, error) {
+			profiles = append(profiles, profile)
+			return "token", "", nil
+		},
+		Executor: executor,
+	})
+	if err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+
+	if result.Mode != CleanupModeRollback || result.Summary.Applied != 2 || result.Summary.Failed != 1 || result.Summary.Remaining != 2 {
+		t.Fatalf("unexpected rollback result: %+v", result)
+	}
+	if len(executor.paused) != 2 || executor.paused[0] != "as_3001" || executor.paused[1] != "cmp_1001" {
+		t.Fatalf("expected newest-first pauses of run resources, got %+v", executor.paused)
+	}
+	if len(profiles) != 3 || profiles[0] != "prod" {
+		t.Fatalf("unexpected credential lookups: %+v", profiles)
+	}
+
+	loaded, err := LoadResourceLedger(ledgerPath)
+	if err != nil {
+		t.Fatalf("reload ledger: %v", err)
+	}
+	if len(loaded.Resources) != 2 || loaded.Resources[0].ResourceID != "cmp_other" || loaded.Resources[1].ResourceID != "cr_2001" {
+		t.Fatalf("unexpected remaining ledger resources: %+v", loaded.Resources)
+	}
+}
+
+func TestRollbackTrackedRunWithoutLedgerIsEmpty(t *testing.T) {
+	t.Parallel()
+
+	result, err := RollbackTrackedRun(context.Background(), filepath.Join(t.TempDir(), "missing.json"), RollbackOptions{
+		RunID:       "bulk-1",
+		Credentials: func(string) (string, string, error) { return "token", "", nil },
+	})
+	if err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if result.Summary.Total != 0 {
+		t.Fatalf("expected nothing to roll back, got %+v", result.Summary)
+	}
+	if _, err := RollbackTrackedRun(context.Background(), "ledger.json", RollbackOptions{}); !errors.Is(err, ErrRollbackRunIDRequired) {
+		t.Fatalf("expected run id error, got %v", err)
+	}
+}
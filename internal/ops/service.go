@@ -1,10 +1,13 @@
 package ops
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/bilalbayram/metacli/internal/changelog"
 	"github.com/bilalbayram/metacli/internal/lint"
 )
 
@@ -60,10 +63,31 @@ type RunOptions struct {
 	LintRequestSpec      *lint.RequestSpec
 	LintRequestSpecFile  string
 	OptionalModulePolicy string
+	CustomChecks         []CustomCheckDefinition
+	CustomCheckExecutor  CustomCheckExecutor
+	HistoryDir           string
+	ExitPolicy           *ExitPolicy
+	ChangelogFeed        *changelog.FeedResolution
+}
+
+type InitOptions struct {
+	ChangelogFeed *changelog.FeedResolution
 }
 
 func Initialize(statePath string) (InitResult, error) {
-	state, err := InitBaseline(statePath)
+	return InitializeWithOptions(statePath, InitOptions{})
+}
+
+func InitializeWithOptions(statePath string, options InitOptions) (InitResult, error) {
+	var changelogOverride *ChangelogOCCSnapshot
+	if options.ChangelogFeed != nil {
+		snapshot := ChangelogOCCSnapshotFromFeed(options.ChangelogFeed.Feed)
+		if err := snapshot.Validate(); err != nil {
+			return InitResult{}, WrapExit(ExitCodeInput, err)
+		}
+		changelogOverride = &snapshot
+	}
+	state, err := initBaseline(statePath, changelogOverride)
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrStatePathRequired):
@@ -75,8 +99,9 @@ func Initialize(statePath string) (InitResult, error) {
 		}
 	}
 	return InitResult{
-		StatePath: statePath,
-		State:     state,
+		StatePath:     statePath,
+		State:         state,
+		ChangelogFeed: options.ChangelogFeed,
 	}, nil
 }
 
@@ -99,7 +124,13 @@ func RunWithOptions(statePath string, options RunOptions) (RunResult, error) {
 		return RunResult{}, WrapExit(ExitCodeInput, err)
 	}
 	optionalPolicy := NormalizeOptionalModulePolicy(options.OptionalModulePolicy)
+	if options.ExitPolicy != nil {
+		if err := options.ExitPolicy.Validate(); err != nil {
+			return RunResult{}, WrapExit(ExitCodeInput, err)
+		}
+	}
 
+	now := time.Now().UTC()
 	report := NewReportSkeleton(state)
 	preflightSnapshot := PermissionPreflightSnapshot{}
 	if options.PermissionPreflight != nil {
@@ -110,10 +141,8 @@ func RunWithOptions(statePath string, options RunOptions) (RunResult, error) {
 	if preflightCheck.Status == CheckStatusFail && preflightCheck.Blocking {
 		report.Checks = append(report.Checks, preflightCheck)
 		finalizeRunReport(&report)
-		return RunResult{
-			StatePath: statePath,
-			Report:    report,
-		}, nil
+		applyExitPolicy(&report, options.ExitPolicy)
+		return recordRunResult(statePath, report, options, now)
 	}
 
 	if options.RuntimeResponse != nil {
@@ -129,10 +158,21 @@ func RunWithOptions(statePath string, options RunOptions) (RunResult, error) {
 	if options.LintRequestSpec != nil && options.RuntimeResponse == nil {
 		return RunResult{}, WrapExit(ExitCodeInput, errors.New("runtime response snapshot is required when lint request spec is provided"))
 	}
+	if err := validateCustomCheckDefinitions(options.CustomChecks); err != nil {
+		return RunResult{}, WrapExit(ExitCodeInput, err)
+	}
 
-	currentSnapshot, err := captureChangelogOCCSnapshot(time.Now().UTC())
-	if err != nil {
-		return RunResult{}, WrapExit(ExitCodeRuntime, err)
+	var currentSnapshot ChangelogOCCSnapshot
+	if options.ChangelogFeed != nil {
+		currentSnapshot = ChangelogOCCSnapshotFromFeed(options.ChangelogFeed.Feed)
+		if err := currentSnapshot.Validate(); err != nil {
+			return RunResult{}, WrapExit(ExitCodeInput, err)
+		}
+	} else {
+		currentSnapshot, err = captureChangelogOCCSnapshot(now)
+		if err != nil {
+			return RunResult{}, WrapExit(ExitCodeRuntime, err)
+		}
 	}
 	changelogCheck := evaluateChangelogOCCDelta(state.Snapshots.ChangelogOCC, currentSnapshot)
 
@@ -168,12 +208,33 @@ func RunWithOptions(statePath string, options RunOptions) (RunResult, error) {
 		preflightCheck,
 		runtimeDriftCheck,
 	)
+	report.Checks = append(report.Checks, evaluateCustomChecks(context.Background(), options.CustomChecks, options.CustomCheckExecutor, state, now)...)
 	finalizeRunReport(&report)
+	applyExitPolicy(&report, options.ExitPolicy)
 
-	return RunResult{
-		StatePath: statePath,
-		Report:    report,
-	}, nil
+	return recordRunResult(statePath, report, options, now)
+}
+
+func recordRunResult(statePath string, report Report, options RunOptions, now time.Time) (RunResult, error) {
+	result := RunResult{
+		StatePath:     statePath,
+		ChangelogFeed: options.ChangelogFeed,
+		Report:        report,
+	}
+	historyDir := options.HistoryDir
+	if strings.TrimSpace(historyDir) == "" {
+		return result, nil
+	}
+	path, err := SaveReportHistoryEntry(historyDir, ReportHistoryEntry{
+		RecordedAt: now,
+		StatePath:  statePath,
+		Report:     report,
+	})
+	if err != nil {
+		return RunResult{}, WrapExit(ExitCodeState, err)
+	}
+	result.HistoryPath = path
+	return result, nil
 }
 
 func finalizeRunReport(report *Report) {
@@ -186,6 +247,9 @@ func finalizeRunReport(report *Report) {
 }
 
 func RunExitCode(report Report) int {
+	if report.ExitPolicy != nil {
+		return report.ExitPolicy.ExitCode
+	}
 	switch RunOutcomeForReport(report) {
 	case RunOutcomeBlocking:
 		return ExitCodePolicy
@@ -15,6 +15,7 @@ import (
 
 	"github.com/bilalbayram/metacli/internal/changelog"
 	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/filelock"
 	"github.com/bilalbayram/metacli/internal/schema"
 )
 
@@ -66,9 +67,19 @@ type RateLimitTelemetrySnapshot struct {
 }
 
 func NewBaselineState() (BaselineState, error) {
-	changelogSnapshot, err := captureChangelogOCCSnapshot(time.Now().UTC())
-	if err != nil {
-		return BaselineState{}, err
+	return newBaselineState(nil)
+}
+
+func newBaselineState(changelogOverride *ChangelogOCCSnapshot) (BaselineState, error) {
+	var changelogSnapshot ChangelogOCCSnapshot
+	if changelogOverride != nil {
+		changelogSnapshot = *changelogOverride
+	} else {
+		captured, err := captureChangelogOCCSnapshot(time.Now().UTC())
+		if err != nil {
+			return BaselineState{}, err
+		}
+		changelogSnapshot = captured
 	}
 	schemaPackSnapshot, err := captureSchemaPackSnapshot()
 	if err != nil {
@@ -99,28 +110,43 @@ func DefaultStatePath() (string, error) {
 }
 
 func InitBaseline(path string) (BaselineState, error) {
+	return initBaseline(path, nil)
+}
+
+func initBaseline(path string, changelogOverride *ChangelogOCCSnapshot) (BaselineState, error) {
 	path = strings.TrimSpace(path)
 	if path == "" {
 		return BaselineState{}, ErrStatePathRequired
 	}
 
+	lock, err := filelock.Acquire(path)
+	if err != nil {
+		return BaselineState{}, err
+	}
+	defer lock.Release()
+
 	if _, err := os.Stat(path); err == nil {
 		return BaselineState{}, fmt.Errorf("%w at %s", ErrBaselineAlreadyExist, path)
 	} else if !errors.Is(err, os.ErrNotExist) {
 		return BaselineState{}, fmt.Errorf("stat baseline state %s: %w", path, err)
 	}
 
-	state, err := NewBaselineState()
+	state, err := newBaselineState(changelogOverride)
 	if err != nil {
 		return BaselineState{}, err
 	}
-	if err := SaveBaseline(path, state); err != nil {
+	if err := saveBaseline(lock, path, state); err != nil {
 		return BaselineState{}, err
 	}
 	return state, nil
 }
 
 func SaveBaseline(path string, state BaselineState) error {
+	return saveBaseline(nil, path, state)
+}
+
+// saveBaseline writes state for a caller that may already hold its lock.
+func saveBaseline(held *filelock.Lock, path string, state BaselineState) error {
 	path = strings.TrimSpace(path)
 	if path == "" {
 		return ErrStatePathRequired
@@ -129,6 +155,12 @@ func SaveBaseline(path string, state BaselineState) error {
 		return err
 	}
 
+	lock, err := held.Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o700); err != nil {
 		return fmt.Errorf("create baseline directory for %s: %w", path, err)
@@ -284,6 +316,13 @@ func captureChangelogOCCSnapshot(now time.Time) (ChangelogOCCSnapshot, error) {
 	}, nil
 }
 
+func ChangelogOCCSnapshotFromFeed(feed changelog.Feed) ChangelogOCCSnapshot {
+	return ChangelogOCCSnapshot{
+		LatestVersion: feed.LatestVersion,
+		OCCDigest:     feed.Digest,
+	}
+}
+
 func captureSchemaPackSnapshot() (SchemaPackSnapshot, error) {
 	path, err := resolveSchemaPackSnapshotSource(config.DefaultDomain, config.DefaultGraphVersion)
 	if err != nil {
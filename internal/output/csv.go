@@ -0,0 +1,129 @@
+package output
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// csvBufferSize bounds how much encoded CSV is held before it reaches the
+// underlying writer, however many rows are written.
+const csvBufferSize = 64 * 1024
+
+// CSVWriter streams rows as CSV. Each row is encoded into a bounded buffer as
+// soon as it is written and the buffer drains to the underlying writer when
+// full or on Flush, so exports never hold more than one row plus
+// csvBufferSize bytes in memory. Columns are fixed by NewCSVWriter or,
+// when none are given, by the sorted keys of the first row. Values under keys
+// outside those columns are left out and the keys reported by DroppedColumns,
+// since a streamed header cannot grow after it has been written.
+type CSVWriter struct {
+	csv     *csv.Writer
+	columns []string
+	known   map[string]struct{}
+	dropped map[string]struct{}
+	// headerDone is set once the header is written or when it is suppressed.
+	headerDone bool
+	rows       int
+}
+
+// NewCSVWriter writes to w. With header false no header row is emitted, for
+// appending to a file that already has one.
+func NewCSVWriter(w io.Writer, columns []string, header bool) *CSVWriter {
+	// csv.NewWriter adopts a large enough *bufio.Writer instead of wrapping it.
+	writer := &CSVWriter{csv: csv.NewWriter(bufio.NewWriterSize(w, csvBufferSize)), dropped: map[string]struct{}{}, headerDone: !header}
+	writer.setColumns(columns)
+	return writer
+}
+
+func (c *CSVWriter) setColumns(columns []string) {
+	c.columns = nil
+	c.known = map[string]struct{}{}
+	for _, column := range columns {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			continue
+		}
+		if _, ok := c.known[column]; ok {
+			continue
+		}
+		c.known[column] = struct{}{}
+		c.columns = append(c.columns, column)
+	}
+}
+
+// Columns returns the header in effect, empty until the first row when it is
+// derived from that row.
+func (c *CSVWriter) Columns() []string {
+	return append([]string(nil), c.columns...)
+}
+
+// DroppedColumns lists, sorted, the row keys that were not in the header.
+func (c *CSVWriter) DroppedColumns() []string {
+	dropped := make([]string, 0, len(c.dropped))
+	for key := range c.dropped {
+		dropped = append(dropped, key)
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+// Rows is the number of data rows written so far.
+func (c *CSVWriter) Rows() int {
+	return c.rows
+}
+
+func (c *CSVWriter) WriteRow(row map[string]any) error {
+	if len(c.columns) == 0 {
+		keys := make([]string, 0, len(row))
+		for key := range row {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		c.columns = keys
+		for _, key := range keys {
+			c.known[key] = struct{}{}
+		}
+	}
+	if !c.headerDone {
+		c.headerDone = true
+		if err := c.csv.Write(c.columns); err != nil {
+			return err
+		}
+	}
+	for key := range row {
+		if _, ok := c.known[key]; !ok {
+			c.dropped[key] = struct{}{}
+		}
+	}
+	record := make([]string, 0, len(c.columns))
+	for _, column := range c.columns {
+		record = append(record, fmt.Sprint(row[column]))
+	}
+	if err := c.csv.Write(record); err != nil {
+		return err
+	}
+	c.rows++
+	return nil
+}
+
+// Flush pushes everything buffered to the underlying writer. Call it after
+// each page so a reader of the file or pipe sees rows as pages arrive.
+func (c *CSVWriter) Flush() error {
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+// Close writes the header if no row was written and flushes.
+func (c *CSVWriter) Close() error {
+	if !c.headerDone && len(c.columns) > 0 {
+		c.headerDone = true
+		if err := c.csv.Write(c.columns); err != nil {
+			return err
+		}
+	}
+	return c.Flush()
+}
@@ -0,0 +1,95 @@
+package output
+
+import "strings"
+
+// Error classes are the stable, documented taxonomy carried in error.class.
+// Automation should branch on the class; error.type and error.code stay
+// source-specific (a Meta exception type and code, or a meta domain type).
+const (
+	ErrorClassInput       = "input_error"
+	ErrorClassAuth        = "auth_error"
+	ErrorClassPermission  = "permission_denied"
+	ErrorClassRateLimited = "rate_limited"
+	ErrorClassNotFound    = "not_found"
+	ErrorClassConflict    = "conflict"
+	ErrorClassGraphAPI    = "graph_api_error"
+	ErrorClassTransient   = "transient_error"
+	ErrorClassPolicy      = "policy_blocked"
+	ErrorClassPartial     = "partial_failure"
+	ErrorClassCanceled    = "canceled"
+	ErrorClassOffline     = "offline"
+	ErrorClassInternal    = "internal_error"
+)
+
+// domainErrorClasses covers meta error types whose name does not follow one of
+// the suffix conventions in errorTypeSuffixClasses, or that a suffix would
+// classify wrongly.
+var domainErrorClasses = map[string]string{
+	"canceled":                    ErrorClassCanceled,
+	"offline_unavailable":         ErrorClassOffline,
+	"workflow_step_failed":        ErrorClassPartial,
+	"declarative_apply_failed":    ErrorClassPartial,
+	"batch_item_failed":           ErrorClassPartial,
+	"catalog_item_errors":         ErrorClassPartial,
+	"cleanup_failures":            ErrorClassPartial,
+	"domain_gate_blocked":         ErrorClassPolicy,
+	"blocking_findings":           ErrorClassPolicy,
+	"spend_policy_violation":      ErrorClassPolicy,
+	"serve_read_only":             ErrorClassPolicy,
+	"page_token_required":         ErrorClassAuth,
+	"ig_media_not_ready":          ErrorClassTransient,
+	"ig_binding_resolution_error": ErrorClassInput,
+}
+
+var errorTypeSuffixClasses = []struct {
+	suffix string
+	class  string
+}{
+	{suffix: "_partial_failure", class: ErrorClassPartial},
+	{suffix: "_validation_error", class: ErrorClassInput},
+	{suffix: "_validation_failed", class: ErrorClassInput},
+	{suffix: "_violation", class: ErrorClassInput},
+	{suffix: "_gate", class: ErrorClassPolicy},
+	{suffix: "_conflict", class: ErrorClassConflict},
+	{suffix: "_transient_error", class: ErrorClassTransient},
+}
+
+// remediationClasses maps the Graph remediation categories onto error classes.
+var remediationClasses = map[string]string{
+	"auth":       ErrorClassAuth,
+	"permission": ErrorClassPermission,
+	"rate_limit": ErrorClassRateLimited,
+	"validation": ErrorClassInput,
+	"not_found":  ErrorClassNotFound,
+	"conflict":   ErrorClassConflict,
+	"transient":  ErrorClassTransient,
+	"canceled":   ErrorClassCanceled,
+	"offline":    ErrorClassOffline,
+}
+
+// ClassifyError derives error.class from the rest of the error payload: the
+// meta domain type first, then the remediation category, then whether the
+// error came back from the Graph API at all.
+func ClassifyError(info *ErrorInfo) string {
+	if info == nil {
+		return ""
+	}
+	errorType := strings.TrimSpace(info.Type)
+	if class, ok := domainErrorClasses[errorType]; ok {
+		return class
+	}
+	for _, candidate := range errorTypeSuffixClasses {
+		if strings.HasSuffix(errorType, candidate.suffix) {
+			return candidate.class
+		}
+	}
+	if info.Remediation != nil {
+		if class, ok := remediationClasses[info.Remediation.Category]; ok {
+			return class
+		}
+	}
+	if info.StatusCode > 0 || info.FBTraceID != "" {
+		return ErrorClassGraphAPI
+	}
+	return ErrorClassInternal
+}
@@ -0,0 +1,152 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrNoPrimaryID is returned by the ids format when the data carries no id.
+var ErrNoPrimaryID = errors.New("ids output found no id in command data; use --query to select one")
+
+// primaryIDKeys are tried in order for objects without an "id" field, covering
+// the result payloads of create and clone commands.
+var primaryIDKeys = []string{
+	"campaign_id",
+	"adset_id",
+	"ad_id",
+	"creative_id",
+	"audience_id",
+	"catalog_id",
+	"product_set_id",
+	"form_id",
+	"media_id",
+	"post_id",
+	"container_id",
+}
+
+// writeIDs prints one primary id per line. Data without ids is an error so
+// scripts never capture an empty or partial value.
+func writeIDs(w io.Writer, envelope Envelope) error {
+	if envelope.Error != nil {
+		return writeErrorLine(w, envelope.Error)
+	}
+	data, err := normalizeData(envelope.Data)
+	if err != nil {
+		return err
+	}
+	ids, ok := extractIDs(data)
+	if !ok {
+		return ErrNoPrimaryID
+	}
+	return writeLines(w, ids)
+}
+
+// writeQuiet prints the primary id(s) when there are any and otherwise the
+// bare result: scalars as text, anything else as compact JSON.
+func writeQuiet(w io.Writer, envelope Envelope) error {
+	if envelope.Error != nil {
+		return writeErrorLine(w, envelope.Error)
+	}
+	data, err := normalizeData(envelope.Data)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+	if ids, ok := extractIDs(data); ok {
+		return writeLines(w, ids)
+	}
+	if text, ok := scalarText(data); ok {
+		return writeLines(w, []string{text})
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return writeLines(w, []string{string(encoded)})
+}
+
+func writeErrorLine(w io.Writer, info *ErrorInfo) error {
+	_, err := fmt.Fprintln(w, "error: "+flattenTableText(info.Message))
+	return err
+}
+
+func writeLines(w io.Writer, lines []string) error {
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractIDs(data any) ([]string, bool) {
+	switch typed := data.(type) {
+	case nil:
+		return nil, true
+	case []any:
+		ids := make([]string, 0, len(typed))
+		for _, item := range typed {
+			id, ok := primaryID(item)
+			if !ok {
+				return nil, false
+			}
+			ids = append(ids, id)
+		}
+		return ids, true
+	default:
+		id, ok := primaryID(typed)
+		if !ok {
+			return nil, false
+		}
+		return []string{id}, true
+	}
+}
+
+func primaryID(value any) (string, bool) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return scalarText(value)
+	}
+	for _, key := range append([]string{"id"}, primaryIDKeys...) {
+		if id, ok := scalarText(object[key]); ok && id != "" {
+			return id, true
+		}
+	}
+
+	// Fall back to a lone *_id field, ignoring references to the source object
+	// and the owning account.
+	found := ""
+	for key, candidate := range object {
+		if !strings.HasSuffix(key, "_id") || strings.HasPrefix(key, "source_") || key == "account_id" {
+			continue
+		}
+		id, ok := scalarText(candidate)
+		if !ok || id == "" {
+			continue
+		}
+		if found != "" {
+			return "", false
+		}
+		found = id
+	}
+	return found, found != ""
+}
+
+func scalarText(value any) (string, bool) {
+	switch typed := value.(type) {
+	case string:
+		return typed, true
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(typed), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,168 @@
+package output
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MoneyFields are Graph fields that carry amounts in the account currency's
+// minor units. Table output formats them; machine formats keep the raw value.
+var MoneyFields = map[string]struct{}{
+	"amount_spent":     {},
+	"balance":          {},
+	"bid_amount":       {},
+	"budget_remaining": {},
+	"daily_budget":     {},
+	"lifetime_budget":  {},
+	"spend_cap":        {},
+}
+
+type currencyFormat struct {
+	symbol string
+	// offset is Meta's minor-unit divisor exponent: 2 for cents, 0 for
+	// currencies Meta bills in whole units.
+	offset   int
+	grouping string
+	decimal  string
+}
+
+var defaultCurrencyFormat = currencyFormat{offset: 2, grouping: ",", decimal: "."}
+
+var currencyFormats = map[string]currencyFormat{
+	"AUD": {symbol: "A$", offset: 2, grouping: ",", decimal: "."},
+	"BRL": {symbol: "R$", offset: 2, grouping: ".", decimal: ","},
+	"CAD": {symbol: "CA$", offset: 2, grouping: ",", decimal: "."},
+	"CHF": {symbol: "CHF ", offset: 2, grouping: "'", decimal: "."},
+	"CLP": {symbol: "CLP$", offset: 0, grouping: ".", decimal: ","},
+	"COP": {symbol: "COL$", offset: 0, grouping: ".", decimal: ","},
+	"CRC": {symbol: "₡", offset: 0, grouping: ".", decimal: ","},
+	"DKK": {symbol: "kr. ", offset: 2, grouping: ".", decimal: ","},
+	"EUR": {symbol: "€", offset: 2, grouping: ".", decimal: ","},
+	"GBP": {symbol: "£", offset: 2, grouping: ",", decimal: "."},
+	"HUF": {symbol: "Ft ", offset: 0, grouping: ".", decimal: ","},
+	"IDR": {symbol: "Rp", offset: 0, grouping: ".", decimal: ","},
+	"ILS": {symbol: "₪", offset: 2, grouping: ",", decimal: "."},
+	"INR": {symbol: "₹", offset: 2, grouping: ",", decimal: "."},
+	"ISK": {symbol: "kr ", offset: 0, grouping: ".", decimal: ","},
+	"JPY": {symbol: "¥", offset: 0, grouping: ",", decimal: "."},
+	"KRW": {symbol: "₩", offset: 0, grouping: ",", decimal: "."},
+	"MXN": {symbol: "MX$", offset: 2, grouping: ",", decimal: "."},
+	"NOK": {symbol: "kr ", offset: 2, grouping: " ", decimal: ","},
+	"NZD": {symbol: "NZ$", offset: 2, grouping: ",", decimal: "."},
+	"PLN": {symbol: "zł ", offset: 2, grouping: " ", decimal: ","},
+	"PYG": {symbol: "₲", offset: 0, grouping: ".", decimal: ","},
+	"SEK": {symbol: "kr ", offset: 2, grouping: " ", decimal: ","},
+	"TRY": {symbol: "₺", offset: 2, grouping: ".", decimal: ","},
+	"TWD": {symbol: "NT$", offset: 0, grouping: ",", decimal: "."},
+	"USD": {symbol: "$", offset: 2, grouping: ",", decimal: "."},
+	"VND": {symbol: "₫", offset: 0, grouping: ".", decimal: ","},
+	"ZAR": {symbol: "R", offset: 2, grouping: " ", decimal: ","},
+}
+
+// FormatMoney renders a minor-unit amount for people, keeping the raw value
+// visible: "₺1.500,00 (150000 minor units, TRY)". Unknown currencies use the
+// code instead of a symbol and two decimals.
+func FormatMoney(minorUnits int64, currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	format, known := currencyFormats[currency]
+	if !known {
+		format = defaultCurrencyFormat
+		if currency != "" {
+			format.symbol = currency + " "
+		}
+	}
+
+	sign := ""
+	magnitude := minorUnits
+	if magnitude < 0 {
+		sign = "-"
+		magnitude = -magnitude
+	}
+	digits := strconv.FormatInt(magnitude, 10)
+	fraction := ""
+	if format.offset > 0 {
+		for len(digits) <= format.offset {
+			digits = "0" + digits
+		}
+		fraction = format.decimal + digits[len(digits)-format.offset:]
+		digits = digits[:len(digits)-format.offset]
+	}
+
+	amount := sign + format.symbol + groupDigits(digits, format.grouping) + fraction
+	if currency == "" {
+		return amount
+	}
+	return amount + " (" + strconv.FormatInt(minorUnits, 10) + " minor units, " + currency + ")"
+}
+
+// FormatMoneyValue formats a Graph money value, which arrives as a string or a
+// number of minor units. It reports false for anything that is not a whole
+// number so callers fall back to the raw value.
+func FormatMoneyValue(value any, currency string) (string, bool) {
+	if strings.TrimSpace(currency) == "" {
+		return "", false
+	}
+	var minorUnits int64
+	switch typed := value.(type) {
+	case string:
+		parsed, err := strconv.ParseInt(strings.TrimSpace(typed), 10, 64)
+		if err != nil {
+			return "", false
+		}
+		minorUnits = parsed
+	case float64:
+		if typed != float64(int64(typed)) {
+			return "", false
+		}
+		minorUnits = int64(typed)
+	default:
+		return "", false
+	}
+	return FormatMoney(minorUnits, currency), true
+}
+
+func groupDigits(digits string, separator string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var builder strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		builder.WriteString(digits[:head])
+	}
+	for index := head; index < len(digits); index += 3 {
+		if builder.Len() > 0 {
+			builder.WriteString(separator)
+		}
+		builder.WriteString(digits[index : index+3])
+	}
+	return builder.String()
+}
+
+func isMoneyColumn(column string) bool {
+	if index := strings.LastIndex(column, "."); index >= 0 {
+		column = column[index+1:]
+	}
+	_, ok := MoneyFields[column]
+	return ok
+}
+
+// rowCurrency prefers a currency carried by the row itself, as account rows do,
+// over the command-wide currency.
+func rowCurrency(item any, fallback string) string {
+	if row, ok := item.(map[string]any); ok {
+		if currency, ok := row["currency"].(string); ok && strings.TrimSpace(currency) != "" {
+			return currency
+		}
+	}
+	return fallback
+}
+
+func formatTableValue(column string, value any, currency string) string {
+	if isMoneyColumn(column) {
+		if formatted, ok := FormatMoneyValue(value, currency); ok {
+			return formatted
+		}
+	}
+	return formatTableCell(value)
+}
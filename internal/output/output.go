@@ -2,7 +2,6 @@ package output
 
 import (
 	"crypto/rand"
-	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -10,7 +9,6 @@ import (
 	"io"
 	"sort"
 	"strings"
-	"text/tabwriter"
 	"time"
 )
 
@@ -26,6 +24,23 @@ type Envelope struct {
 	Paging          any        `json:"paging,omitempty"`
 	RateLimit       any        `json:"rate_limit,omitempty"`
 	Error           *ErrorInfo `json:"error,omitempty"`
+	// Offline is set on envelopes of --offline invocations.
+	Offline *OfflineInfo `json:"offline,omitempty"`
+}
+
+// OfflineInfo lists the cached reads an offline envelope was built from, so
+// consumers can judge how stale its data is.
+type OfflineInfo struct {
+	Reads []OfflineRead `json:"reads"`
+	// MaxAgeSeconds is the age of the stalest read, 0 when none were served.
+	MaxAgeSeconds int64 `json:"max_age_seconds"`
+}
+
+type OfflineRead struct {
+	Path       string `json:"path"`
+	Version    string `json:"version"`
+	CachedAt   string `json:"cached_at"`
+	AgeSeconds int64  `json:"age_seconds"`
 }
 
 type Remediation struct {
@@ -33,9 +48,15 @@ type Remediation struct {
 	Summary  string   `json:"summary"`
 	Actions  []string `json:"actions,omitempty"`
 	Fields   []string `json:"fields,omitempty"`
+	// Reference is the Graph error code, or code/subcode, of the knowledge-base
+	// entry the remediation came from.
+	Reference string   `json:"reference,omitempty"`
+	Docs      []string `json:"docs,omitempty"`
 }
 
 type ErrorInfo struct {
+	// Class is one of the ErrorClass* values; NewEnvelope derives it when unset.
+	Class        string         `json:"class"`
 	Type         string         `json:"type"`
 	Code         int            `json:"code"`
 	ErrorSubcode int            `json:"error_subcode"`
@@ -52,6 +73,9 @@ func NewEnvelope(command string, success bool, data any, paging any, rateLimit a
 	if err != nil {
 		return Envelope{}, err
 	}
+	if errorInfo != nil && errorInfo.Class == "" {
+		errorInfo.Class = ClassifyError(errorInfo)
+	}
 	return Envelope{
 		ContractVersion: ContractVersion,
 		Command:         command,
@@ -66,15 +90,25 @@ func NewEnvelope(command string, success bool, data any, paging any, rateLimit a
 }
 
 func Write(w io.Writer, format string, envelope Envelope) error {
+	return WriteWithOptions(w, format, envelope, TableOptions{})
+}
+
+// WriteWithOptions is Write with rendering options for the table format.
+func WriteWithOptions(w io.Writer, format string, envelope Envelope, table TableOptions) error {
 	switch strings.ToLower(strings.TrimSpace(format)) {
 	case "json":
 		return writeJSON(w, envelope)
 	case "jsonl":
 		return writeJSONL(w, envelope)
 	case "table":
-		return writeTable(w, envelope.Data)
+		return writeTable(w, envelope, table)
 	case "csv":
 		return writeCSV(w, envelope.Data)
+	case "ids":
+		return writeIDs(w, envelope)
+	case "quiet":
+		// Selected by --quiet rather than --output.
+		return writeQuiet(w, envelope)
 	default:
 		return fmt.Errorf("unsupported output format %q", format)
 	}
@@ -124,47 +158,18 @@ func writeJSONL(w io.Writer, envelope Envelope) error {
 	}
 }
 
-func writeTable(w io.Writer, data any) error {
-	rows, headers, err := normalizeRows(data)
-	if err != nil {
-		return err
-	}
-	tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
-	if _, err := fmt.Fprintln(tw, strings.Join(headers, "\t")); err != nil {
-		return err
-	}
-	for _, row := range rows {
-		values := make([]string, 0, len(headers))
-		for _, header := range headers {
-			values = append(values, fmt.Sprint(row[header]))
-		}
-		if _, err := fmt.Fprintln(tw, strings.Join(values, "\t")); err != nil {
-			return err
-		}
-	}
-	return tw.Flush()
-}
-
 func writeCSV(w io.Writer, data any) error {
 	rows, headers, err := normalizeRows(data)
 	if err != nil {
 		return err
 	}
-	cw := csv.NewWriter(w)
-	if err := cw.Write(headers); err != nil {
-		return err
-	}
+	writer := NewCSVWriter(w, headers, true)
 	for _, row := range rows {
-		record := make([]string, 0, len(headers))
-		for _, header := range headers {
-			record = append(record, fmt.Sprint(row[header]))
-		}
-		if err := cw.Write(record); err != nil {
+		if err := writer.WriteRow(row); err != nil {
 			return err
 		}
 	}
-	cw.Flush()
-	return cw.Error()
+	return writer.Close()
 }
 
 func normalizeRows(data any) ([]map[string]any, []string, error) {
@@ -175,8 +180,19 @@ func normalizeRows(data any) ([]map[string]any, []string, error) {
 	case map[string]any:
 		headers := orderedHeaders([]map[string]any{typed})
 		return []map[string]any{typed}, headers, nil
+	case []any:
+		// Query results are generic JSON; scalar items become a single value column.
+		rows := make([]map[string]any, 0, len(typed))
+		for _, item := range typed {
+			row, ok := item.(map[string]any)
+			if !ok {
+				row = map[string]any{"value": item}
+			}
+			rows = append(rows, row)
+		}
+		return rows, orderedHeaders(rows), nil
 	default:
-		return nil, nil, errors.New("table/csv output requires map or []map data")
+		return nil, nil, errors.New("csv output requires map or []map data")
 	}
 }
 
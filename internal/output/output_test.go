@@ -124,3 +124,331 @@ func TestJSONEnvelopeIncludesStructuredRemediation(t *testing.T) {
 		t.Fatalf("unexpected diagnostics payload %v", diagnostics)
 	}
 }
+
+func TestTableOrdersIdentityColumnsAndSelectsDotPaths(t *testing.T) {
+	t.Parallel()
+
+	type campaign struct {
+		Status    string         `json:"status"`
+		Objective string         `json:"objective"`
+		ID        string         `json:"id"`
+		Name      string         `json:"name"`
+		Targeting map[string]any `json:"targeting,omitempty"`
+	}
+	data := []campaign{
+		{ID: "1", Name: "Launch", Status: "ACTIVE", Objective: "OUTCOME_SALES", Targeting: map[string]any{"age_min": 18}},
+		{ID: "22", Name: "Retargeting", Status: "PAUSED", Objective: "OUTCOME_TRAFFIC"},
+	}
+	envelope, err := NewEnvelope("meta campaign list", true, data, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("new envelope: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "table", envelope); err != nil {
+		t.Fatalf("write table: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "id  name         status  objective") {
+		t.Fatalf("unexpected default table:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteWithOptions(&buf, "table", envelope, TableOptions{Columns: []string{"name", "targeting.age_min"}}); err != nil {
+		t.Fatalf("write table: %v", err)
+	}
+	want := "name         targeting.age_min\nLaunch       18\nRetargeting\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected selected columns:\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func TestTableTruncatesToWidthAndColorsStatuses(t *testing.T) {
+	t.Parallel()
+
+	data := []map[string]any{
+		{"id": "1", "name": strings.Repeat("x", 40), "status": "ACTIVE"},
+		{"id": "2", "name": "short", "status": "DELETED"},
+	}
+	envelope, err := NewEnvelope("meta campaign list", true, data, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("new envelope: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteWithOptions(&buf, "table", envelope, TableOptions{Width: 30, Color: true}); err != nil {
+		t.Fatalf("write table: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.Contains(lines[1], strings.Repeat("x", 16)+"…") || strings.Contains(lines[1], strings.Repeat("x", 17)) {
+		t.Fatalf("expected truncated name, got %q", lines[1])
+	}
+	if !strings.HasSuffix(lines[1], ansiGreen+"ACTIVE"+ansiReset) || !strings.HasSuffix(lines[2], ansiRed+"DELETED"+ansiReset) {
+		t.Fatalf("expected colored statuses, got %q", buf.String())
+	}
+	if strings.Contains(lines[0], "\x1b[") {
+		t.Fatalf("header must not be colored: %q", lines[0])
+	}
+}
+
+func TestTableRendersSingleObjectsAndErrors(t *testing.T) {
+	t.Parallel()
+
+	envelope, err := NewEnvelope("meta campaign get", true, map[string]any{"status": "PAUSED", "id": "1", "budget": 500}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("new envelope: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, "table", envelope); err != nil {
+		t.Fatalf("write table: %v", err)
+	}
+	if want := "field   value\nid      1\nstatus  PAUSED\nbudget  500\n"; buf.String() != want {
+		t.Fatalf("unexpected field table:\n%q\nwant\n%q", buf.String(), want)
+	}
+
+	envelope, err = NewEnvelope("meta campaign get", false, nil, nil, nil, &ErrorInfo{
+		Type:        "OAuthException",
+		Code:        190,
+		Message:     "Invalid OAuth access token",
+		Remediation: &Remediation{Summary: "Refresh the token.", Actions: []string{"Run meta auth login."}},
+	})
+	if err != nil {
+		t.Fatalf("new envelope: %v", err)
+	}
+	buf.Reset()
+	if err := Write(&buf, "table", envelope); err != nil {
+		t.Fatalf("write table error: %v", err)
+	}
+	want := "Error: Invalid OAuth access token (OAuthException, code 190)\nRemediation: Refresh the token.\n  - Run meta auth login.\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected table error:\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func TestCSVAcceptsGenericRows(t *testing.T) {
+	t.Parallel()
+
+	envelope, err := NewEnvelope("meta campaign list", true, []any{"cmp_1", "cmp_2"}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("new envelope: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, "csv", envelope); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	if want := "value\ncmp_1\ncmp_2\n"; buf.String() != want {
+		t.Fatalf("unexpected csv:\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func TestIDsOutputPrintsPrimaryIDs(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		data any
+		want string
+	}{
+		{data: map[string]any{"campaign_id": "992", "operation": "create"}, want: "992\n"},
+		{data: map[string]any{"source_campaign_id": "1", "campaign_id": "2"}, want: "2\n"},
+		{data: map[string]any{"account_id": "act_1", "pixel_id": "77"}, want: "77\n"},
+		{data: []map[string]any{{"id": "1"}, {"id": "2"}}, want: "1\n2\n"},
+		{data: []any{"cmp_1", float64(42)}, want: "cmp_1\n42\n"},
+		{data: nil, want: ""},
+	}
+	for _, tc := range cases {
+		envelope, err := NewEnvelope("meta campaign create", true, tc.data, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("new envelope: %v", err)
+		}
+		var buf bytes.Buffer
+		if err := Write(&buf, "ids", envelope); err != nil {
+			t.Fatalf("write ids for %#v: %v", tc.data, err)
+		}
+		if buf.String() != tc.want {
+			t.Fatalf("ids for %#v: got %q want %q", tc.data, buf.String(), tc.want)
+		}
+	}
+
+	envelope, err := NewEnvelope("meta campaign create", true, map[string]any{"status": "ok"}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("new envelope: %v", err)
+	}
+	if err := Write(&bytes.Buffer{}, "ids", envelope); err != ErrNoPrimaryID {
+		t.Fatalf("expected missing id error, got %v", err)
+	}
+}
+
+func TestQuietOutputFallsBackToBareResult(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		data any
+		want string
+	}{
+		{data: map[string]any{"adset_id": "s1"}, want: "s1\n"},
+		{data: "deleted", want: "deleted\n"},
+		{data: map[string]any{"status": "ok"}, want: "{\"status\":\"ok\"}\n"},
+	}
+	for _, tc := range cases {
+		envelope, err := NewEnvelope("meta adset create", true, tc.data, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("new envelope: %v", err)
+		}
+		var buf bytes.Buffer
+		if err := Write(&buf, "quiet", envelope); err != nil {
+			t.Fatalf("write quiet: %v", err)
+		}
+		if buf.String() != tc.want {
+			t.Fatalf("quiet for %#v: got %q want %q", tc.data, buf.String(), tc.want)
+		}
+	}
+
+	envelope, err := NewEnvelope("meta adset create", false, nil, nil, nil, &ErrorInfo{Type: "error", Message: "invalid\nparams"})
+	if err != nil {
+		t.Fatalf("new envelope: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, "quiet", envelope); err != nil {
+		t.Fatalf("write quiet error: %v", err)
+	}
+	if buf.String() != "error: invalid params\n" {
+		t.Fatalf("unexpected quiet error %q", buf.String())
+	}
+}
+
+func TestNewEnvelopeClassifiesErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		info ErrorInfo
+		want string
+	}{
+		{name: "domain validation", info: ErrorInfo{Type: "capi_validation_failed"}, want: ErrorClassInput},
+		{name: "domain gate", info: ErrorInfo{Type: "ig_preflight_gate"}, want: ErrorClassPolicy},
+		{name: "domain partial", info: ErrorInfo{Type: "workflow_step_failed"}, want: ErrorClassPartial},
+		{name: "serve read-only", info: ErrorInfo{Type: "serve_read_only"}, want: ErrorClassPolicy},
+		{name: "offline", info: ErrorInfo{Type: "offline_unavailable", Remediation: &Remediation{Category: "offline"}}, want: ErrorClassOffline},
+		{name: "remediation auth", info: ErrorInfo{Type: "OAuthException", Code: 190, StatusCode: 401, Remediation: &Remediation{Category: "auth"}}, want: ErrorClassAuth},
+		{name: "remediation rate limit", info: ErrorInfo{Type: "OAuthException", Code: 17, Remediation: &Remediation{Category: "rate_limit"}}, want: ErrorClassRateLimited},
+		{name: "unmapped graph error", info: ErrorInfo{Type: "FacebookApiException", StatusCode: 500, Remediation: &Remediation{Category: "unknown"}}, want: ErrorClassGraphAPI},
+		{name: "explicit class", info: ErrorInfo{Class: ErrorClassAuth, Type: "error"}, want: ErrorClassAuth},
+		{name: "unknown", info: ErrorInfo{Type: "error"}, want: ErrorClassInternal},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			info := tc.info
+			envelope, err := NewEnvelope("meta test", false, nil, nil, nil, &info)
+			if err != nil {
+				t.Fatalf("new envelope: %v", err)
+			}
+			if envelope.Error.Class != tc.want {
+				t.Fatalf("expected class %q, got %q", tc.want, envelope.Error.Class)
+			}
+		})
+	}
+}
+
+func TestFormatMoneyUsesCurrencyConventions(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		minorUnits int64
+		currency   string
+		want       string
+	}{
+		{minorUnits: 150000, currency: "TRY", want: "₺1.500,00 (150000 minor units, TRY)"},
+		{minorUnits: 123456789, currency: "usd", want: "$1,234,567.89 (123456789 minor units, USD)"},
+		{minorUnits: 5, currency: "EUR", want: "€0,05 (5 minor units, EUR)"},
+		{minorUnits: 150000, currency: "JPY", want: "¥150,000 (150000 minor units, JPY)"},
+		{minorUnits: -2500, currency: "GBP", want: "-£25.00 (-2500 minor units, GBP)"},
+		{minorUnits: 1000, currency: "XYZ", want: "XYZ 10.00 (1000 minor units, XYZ)"},
+	}
+	for _, tc := range cases {
+		if got := FormatMoney(tc.minorUnits, tc.currency); got != tc.want {
+			t.Fatalf("FormatMoney(%d, %q)=%q want %q", tc.minorUnits, tc.currency, got, tc.want)
+		}
+	}
+	if _, ok := FormatMoneyValue("12.5", "USD"); ok {
+		t.Fatal("expected fractional values to be left unformatted")
+	}
+	if _, ok := FormatMoneyValue("1250", ""); ok {
+		t.Fatal("expected values without a currency to be left unformatted")
+	}
+}
+
+func TestTableFormatsMoneyColumnsOnlyForHumans(t *testing.T) {
+	t.Parallel()
+
+	data := []map[string]any{
+		{"id": "1", "daily_budget": "150000", "objective": "OUTCOME_SALES"},
+		{"id": "2", "lifetime_budget": "5000", "currency": "USD"},
+	}
+	envelope, err := NewEnvelope("meta campaign list", true, data, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("new envelope: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteWithOptions(&buf, "table", envelope, TableOptions{Columns: []string{"id", "daily_budget", "lifetime_budget"}, Currency: "TRY"}); err != nil {
+		t.Fatalf("write table: %v", err)
+	}
+	for _, want := range []string{"₺1.500,00 (150000 minor units, TRY)", "$50.00 (5000 minor units, USD)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expected %q in table:\n%s", want, buf.String())
+		}
+	}
+
+	for _, format := range []string{"json", "csv"} {
+		buf.Reset()
+		if err := WriteWithOptions(&buf, format, envelope, TableOptions{Currency: "TRY"}); err != nil {
+			t.Fatalf("write %s: %v", format, err)
+		}
+		if strings.Contains(buf.String(), "minor units") || !strings.Contains(buf.String(), "150000") {
+			t.Fatalf("expected raw minor units in %s output:\n%s", format, buf.String())
+		}
+	}
+}
+
+func TestCSVWriterStreamsWithFixedHeader(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewCSVWriter(&buf, nil, true)
+	if err := writer.WriteRow(map[string]any{"name": "One", "id": "1"}); err != nil {
+		t.Fatalf("write row: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected rows to stay buffered until flush, got %q", buf.String())
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if buf.String() != "id,name\n1,One\n" {
+		t.Fatalf("unexpected csv after flush: %q", buf.String())
+	}
+	if err := writer.WriteRow(map[string]any{"id": "2", "status": "ACTIVE"}); err != nil {
+		t.Fatalf("write row: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if buf.String() != "id,name\n1,One\n2,<nil>\n" {
+		t.Fatalf("unexpected csv: %q", buf.String())
+	}
+	if writer.Rows() != 2 || strings.Join(writer.DroppedColumns(), ",") != "status" {
+		t.Fatalf("unexpected rows=%d dropped=%v", writer.Rows(), writer.DroppedColumns())
+	}
+
+	buf.Reset()
+	appending := NewCSVWriter(&buf, []string{"id", " name ", "id"}, false)
+	if err := appending.WriteRow(map[string]any{"id": "3", "name": "Three"}); err != nil {
+		t.Fatalf("write row: %v", err)
+	}
+	if err := appending.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if buf.String() != "3,Three\n" {
+		t.Fatalf("expected headerless append, got %q", buf.String())
+	}
+}
@@ -0,0 +1,406 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bilalbayram/metacli/internal/i18n"
+)
+
+const (
+	defaultTerminalWidth = 120
+	minTableColumnWidth  = 6
+	tableColumnGap       = "  "
+	truncationMarker     = "…"
+
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// leadingTableColumns are rendered first, in this order, when present; the
+// remaining columns follow alphabetically.
+var leadingTableColumns = []string{"id", "name", "status", "effective_status"}
+
+var statusColors = map[string]string{
+	"ACTIVE":      ansiGreen,
+	"APPLIED":     ansiGreen,
+	"COMPLETED":   ansiGreen,
+	"CREATED":     ansiGreen,
+	"ENABLED":     ansiGreen,
+	"OK":          ansiGreen,
+	"PASS":        ansiGreen,
+	"READY":       ansiGreen,
+	"SUCCEEDED":   ansiGreen,
+	"SUCCESS":     ansiGreen,
+	"PAUSED":      ansiYellow,
+	"PLANNED":     ansiYellow,
+	"SKIPPED":     ansiYellow,
+	"WARN":        ansiYellow,
+	"WARNING":     ansiYellow,
+	"IN_PROCESS":  ansiCyan,
+	"IN_PROGRESS": ansiCyan,
+	"PENDING":     ansiCyan,
+	"RUNNING":     ansiCyan,
+	"SCHEDULED":   ansiCyan,
+	"ARCHIVED":    ansiRed,
+	"DELETED":     ansiRed,
+	"DISAPPROVED": ansiRed,
+	"ERROR":       ansiRed,
+	"FAILED":      ansiRed,
+	"INVALID":     ansiRed,
+	"REJECTED":    ansiRed,
+	"ROLLED_BACK": ansiRed,
+	"WITH_ISSUES": ansiRed,
+}
+
+// TableOptions controls the human-friendly table renderer. Machine formats
+// (json, jsonl, csv) ignore it.
+type TableOptions struct {
+	// Columns selects and orders columns; entries are dot paths into each row.
+	Columns []string
+	// Width caps the rendered line width; 0 disables truncation.
+	Width int
+	// Color enables ANSI colors for status-like columns.
+	Color bool
+	// Currency formats minor-unit money columns such as daily_budget; a
+	// currency field on the row itself takes precedence.
+	Currency string
+	// Lang renders error labels in this language; empty is English.
+	Lang string
+}
+
+// TerminalTableOptions sizes and colors tables only when w is an interactive
+// terminal, so redirected output stays plain. COLUMNS overrides the width and
+// NO_COLOR disables colors.
+func TerminalTableOptions(w io.Writer, columns []string) TableOptions {
+	options := TableOptions{Columns: columns}
+	if !IsTerminal(w) {
+		return options
+	}
+	options.Width = defaultTerminalWidth
+	if raw := strings.TrimSpace(os.Getenv("COLUMNS")); raw != "" {
+		if width, err := strconv.Atoi(raw); err == nil && width > 0 {
+			options.Width = width
+		}
+	}
+	options.Color = os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+	return options
+}
+
+// IsTerminal reports whether w is an interactive terminal.
+func IsTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func writeTable(w io.Writer, envelope Envelope, options TableOptions) error {
+	if envelope.Error != nil {
+		return writeTableError(w, envelope.Error, options)
+	}
+
+	data, err := normalizeData(envelope.Data)
+	if err != nil {
+		return err
+	}
+	switch typed := data.(type) {
+	case nil:
+		return nil
+	case []any:
+		return writeTableRows(w, typed, options)
+	case map[string]any:
+		if len(options.Columns) > 0 {
+			return writeTableRows(w, []any{typed}, options)
+		}
+		return writeTableFields(w, typed, options)
+	default:
+		_, err := fmt.Fprintln(w, formatTableCell(typed))
+		return err
+	}
+}
+
+// normalizeData round-trips data through JSON so structs, typed slices and
+// maps all render the same way their json output does.
+func normalizeData(data any) (any, error) {
+	if data == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("encode table data: %w", err)
+	}
+	var normalized any
+	if err := json.Unmarshal(encoded, &normalized); err != nil {
+		return nil, fmt.Errorf("decode table data: %w", err)
+	}
+	return normalized, nil
+}
+
+func writeTableRows(w io.Writer, items []any, options TableOptions) error {
+	columns := options.Columns
+	if len(columns) == 0 {
+		columns = defaultTableColumns(items)
+	}
+	if len(columns) == 0 {
+		columns = []string{"value"}
+	}
+
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		currency := rowCurrency(item, options.Currency)
+		row := make([]string, 0, len(columns))
+		for _, column := range columns {
+			value, _ := lookupTablePath(item, column, len(options.Columns) == 0)
+			row = append(row, formatTableValue(column, value, currency))
+		}
+		rows = append(rows, row)
+	}
+	return renderTable(w, columns, rows, options, func(_ []string, index int) bool {
+		return isStatusColumn(columns[index])
+	})
+}
+
+func writeTableFields(w io.Writer, item map[string]any, options TableOptions) error {
+	keys := orderTableColumns(item)
+	currency := rowCurrency(item, options.Currency)
+	rows := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, []string{key, formatTableValue(key, item[key], currency)})
+	}
+	return renderTable(w, []string{"field", "value"}, rows, options, func(row []string, index int) bool {
+		return index == 1 && isStatusColumn(row[0])
+	})
+}
+
+func writeTableError(w io.Writer, info *ErrorInfo, options TableOptions) error {
+	label := i18n.Translate(options.Lang, "Error")
+	if options.Color {
+		label = ansiRed + label + ansiReset
+	}
+	details := info.Type
+	if info.Code != 0 {
+		details = fmt.Sprintf("%s, code %d", details, info.Code)
+	}
+	if info.ErrorSubcode != 0 {
+		details = fmt.Sprintf("%s, subcode %d", details, info.ErrorSubcode)
+	}
+	lines := []string{fmt.Sprintf("%s: %s (%s)", label, info.Message, details)}
+	if info.Remediation != nil {
+		if info.Remediation.Summary != "" {
+			lines = append(lines, i18n.Translate(options.Lang, "Remediation")+": "+info.Remediation.Summary)
+		}
+		for _, action := range info.Remediation.Actions {
+			lines = append(lines, "  - "+action)
+		}
+	}
+	if info.FBTraceID != "" {
+		lines = append(lines, "fbtrace_id: "+info.FBTraceID)
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func defaultTableColumns(items []any) []string {
+	merged := map[string]any{}
+	for _, item := range items {
+		row, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		for key := range row {
+			merged[key] = nil
+		}
+	}
+	return orderTableColumns(merged)
+}
+
+func orderTableColumns(row map[string]any) []string {
+	columns := make([]string, 0, len(row))
+	for _, key := range leadingTableColumns {
+		if _, ok := row[key]; ok {
+			columns = append(columns, key)
+		}
+	}
+	rest := make([]string, 0, len(row))
+	for key := range row {
+		if !isLeadingTableColumn(key) {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(columns, rest...)
+}
+
+func isLeadingTableColumn(key string) bool {
+	for _, leading := range leadingTableColumns {
+		if key == leading {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupTablePath resolves a dot path such as targeting.geo_locations. Keys
+// discovered from the rows themselves are looked up literally.
+func lookupTablePath(item any, path string, literal bool) (any, bool) {
+	row, ok := item.(map[string]any)
+	if !ok {
+		if path == "value" {
+			return item, true
+		}
+		return nil, false
+	}
+	if value, ok := row[path]; ok || literal {
+		return value, ok
+	}
+
+	var current any = row
+	for _, segment := range strings.Split(path, ".") {
+		switch typed := current.(type) {
+		case map[string]any:
+			value, ok := typed[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(typed) {
+				return nil, false
+			}
+			current = typed[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func formatTableCell(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return flattenTableText(typed)
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(typed)
+	default:
+		encoded, err := json.Marshal(typed)
+		if err != nil {
+			return flattenTableText(fmt.Sprint(typed))
+		}
+		return string(encoded)
+	}
+}
+
+func flattenTableText(value string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ").Replace(value)
+}
+
+// renderTable pads cells to aligned columns; statusCell reports which cells are
+// colored when options.Color is set.
+func renderTable(w io.Writer, headers []string, rows [][]string, options TableOptions, statusCell func(row []string, index int) bool) error {
+	widths := make([]int, len(headers))
+	for index, header := range headers {
+		widths[index] = utf8.RuneCountInString(header)
+	}
+	for _, row := range rows {
+		for index, cell := range row {
+			if width := utf8.RuneCountInString(cell); width > widths[index] {
+				widths[index] = width
+			}
+		}
+	}
+	if options.Width > 0 {
+		fitTableWidths(widths, options.Width)
+	}
+
+	if !options.Color {
+		statusCell = nil
+	}
+
+	if err := writeTableLine(w, headers, widths, nil); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writeTableLine(w, row, widths, statusCell); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fitTableWidths shrinks the widest columns until the line fits, never below
+// minTableColumnWidth.
+func fitTableWidths(widths []int, limit int) {
+	total := func() int {
+		sum := len(tableColumnGap) * (len(widths) - 1)
+		for _, width := range widths {
+			sum += width
+		}
+		return sum
+	}
+	for total() > limit {
+		widest := 0
+		for index, width := range widths {
+			if width > widths[widest] {
+				widest = index
+			}
+		}
+		if widths[widest] <= minTableColumnWidth {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+func writeTableLine(w io.Writer, cells []string, widths []int, statusCell func(row []string, index int) bool) error {
+	var line strings.Builder
+	for index, cell := range cells {
+		cell = truncateTableCell(cell, widths[index])
+		padding := widths[index] - utf8.RuneCountInString(cell)
+		if statusCell != nil && statusCell(cells, index) {
+			if color, ok := statusColors[strings.ToUpper(cell)]; ok {
+				cell = color + cell + ansiReset
+			}
+		}
+		line.WriteString(cell)
+		if index < len(cells)-1 {
+			line.WriteString(strings.Repeat(" ", padding))
+			line.WriteString(tableColumnGap)
+		}
+	}
+	_, err := fmt.Fprintln(w, strings.TrimRight(line.String(), " "))
+	return err
+}
+
+func truncateTableCell(cell string, width int) string {
+	if utf8.RuneCountInString(cell) <= width {
+		return cell
+	}
+	runes := []rune(cell)
+	return string(runes[:width-1]) + truncationMarker
+}
+
+func isStatusColumn(header string) bool {
+	header = strings.ToLower(header)
+	return header == "status" || header == "classification" ||
+		strings.HasSuffix(header, "_status") || strings.HasSuffix(header, ".status")
+}
@@ -0,0 +1,404 @@
+package page
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	PostTypeText  = "text"
+	PostTypeLink  = "link"
+	PostTypePhoto = "photo"
+	PostTypeVideo = "video"
+
+	PostModePublished = "published"
+	PostModeScheduled = "scheduled"
+
+	ListKindPublished = "published"
+	ListKindScheduled = "scheduled"
+
+	MinScheduleLead = 10 * time.Minute
+	MaxScheduleLead = 30 * 24 * time.Hour
+
+	tokenTypePage = "page"
+
+	pageErrorTypeTokenRequired = "page_token_required"
+	pageErrorCodeTokenRequired = 403300
+)
+
+var DefaultListFields = []string{"id", "message", "created_time", "permalink_url", "scheduled_publish_time", "is_published"}
+
+type PostOptions struct {
+	PageID      string
+	Message     string
+	Link        string
+	PhotoURL    string
+	VideoURL    string
+	VideoTitle  string
+	PublishAt   string
+	RequireTime bool
+	Now         time.Time
+}
+
+type PostResult struct {
+	PageID               string         `json:"page_id"`
+	PostType             string         `json:"post_type"`
+	Mode                 string         `json:"mode"`
+	ID                   string         `json:"id"`
+	PostID               string         `json:"post_id,omitempty"`
+	ScheduledPublishTime string         `json:"scheduled_publish_time,omitempty"`
+	RequestPath          string         `json:"request_path"`
+	Response             map[string]any `json:"response"`
+}
+
+type ListPostsOptions struct {
+	PageID     string
+	Kind       string
+	Fields     []string
+	Limit      int
+	PageSize   int
+	FollowNext bool
+}
+
+type ListPostsResult struct {
+	PageID      string                  `json:"page_id"`
+	Kind        string                  `json:"kind"`
+	RequestPath string                  `json:"request_path"`
+	Posts       []map[string]any        `json:"posts"`
+	Paging      *graph.PaginationResult `json:"paging,omitempty"`
+}
+
+type DeletePostOptions struct {
+	PostID string
+}
+
+type DeletePostResult struct {
+	PostID   string         `json:"post_id"`
+	Deleted  bool           `json:"deleted"`
+	Response map[string]any `json:"response"`
+}
+
+type Service struct {
+	Client *graph.Client
+}
+
+func New(client *graph.Client) *Service {
+	if client == nil {
+		client = graph.NewClient(nil, "")
+	}
+	return &Service{Client: client}
+}
+
+func (s *Service) Post(ctx context.Context, version string, token string, appSecret string, options PostOptions) (*PostResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("page service client is required")
+	}
+
+	request, result, err := BuildPostRequest(version, token, appSecret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.Client.Do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := response.Body["id"].(string)
+	result.ID = strings.TrimSpace(id)
+	if result.ID == "" {
+		return nil, errors.New("page post response did not include id")
+	}
+	postID, _ := response.Body["post_id"].(string)
+	result.PostID = strings.TrimSpace(postID)
+	result.Response = response.Body
+	return result, nil
+}
+
+func (s *Service) ListPosts(ctx context.Context, version string, token string, appSecret string, options ListPostsOptions) (*ListPostsResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("page service client is required")
+	}
+
+	request, normalized, err := BuildListPostsRequest(version, token, appSecret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([]map[string]any, 0)
+	pagination, err := s.Client.FetchWithPagination(ctx, request, graph.PaginationOptions{
+		FollowNext: normalized.FollowNext,
+		Limit:      normalized.Limit,
+		PageSize:   normalized.PageSize,
+	}, func(item map[string]any) error {
+		posts = append(posts, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListPostsResult{
+		PageID:      normalized.PageID,
+		Kind:        normalized.Kind,
+		RequestPath: request.Path,
+		Posts:       posts,
+		Paging:      pagination,
+	}, nil
+}
+
+func (s *Service) DeletePost(ctx context.Context, version string, token string, appSecret string, options DeletePostOptions) (*DeletePostResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("page service client is required")
+	}
+
+	request, postID, err := BuildDeletePostRequest(version, token, appSecret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.Client.Do(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	success, _ := response.Body["success"].(bool)
+	return &DeletePostResult{
+		PostID:   postID,
+		Deleted:  success,
+		Response: response.Body,
+	}, nil
+}
+
+// BuildPostRequest maps the post content to the matching page edge: photos and videos
+// go to /photos and /videos, text and link posts go to /feed. Scheduled posts are
+// created unpublished with scheduled_publish_time.
+func BuildPostRequest(version string, token string, appSecret string, options PostOptions) (graph.Request, *PostResult, error) {
+	pageID, err := normalizeGraphID("page id", options.PageID)
+	if err != nil {
+		return graph.Request{}, nil, err
+	}
+
+	message := strings.TrimSpace(options.Message)
+	link := strings.TrimSpace(options.Link)
+	photoURL := strings.TrimSpace(options.PhotoURL)
+	videoURL := strings.TrimSpace(options.VideoURL)
+
+	postType := PostTypeText
+	switch {
+	case photoURL != "" && videoURL != "":
+		return graph.Request{}, nil, errors.New("use either --photo-url or --video-url, not both")
+	case (photoURL != "" || videoURL != "") && link != "":
+		return graph.Request{}, nil, errors.New("--link cannot be combined with --photo-url or --video-url")
+	case photoURL != "":
+		postType = PostTypePhoto
+	case videoURL != "":
+		postType = PostTypeVideo
+	case link != "":
+		postType = PostTypeLink
+	}
+	if postType == PostTypeText && message == "" {
+		return graph.Request{}, nil, errors.New("message is required for text posts")
+	}
+	for label, value := range map[string]string{"link": link, "photo url": photoURL, "video url": videoURL} {
+		if value == "" {
+			continue
+		}
+		if err := validateAbsoluteURL(label, value); err != nil {
+			return graph.Request{}, nil, err
+		}
+	}
+
+	form := map[string]string{}
+	var path string
+	switch postType {
+	case PostTypePhoto:
+		path = fmt.Sprintf("%s/photos", pageID)
+		form["url"] = photoURL
+		if message != "" {
+			form["caption"] = message
+		}
+	case PostTypeVideo:
+		path = fmt.Sprintf("%s/videos", pageID)
+		form["file_url"] = videoURL
+		if message != "" {
+			form["description"] = message
+		}
+		if title := strings.TrimSpace(options.VideoTitle); title != "" {
+			form["title"] = title
+		}
+	default:
+		path = fmt.Sprintf("%s/feed", pageID)
+		if message != "" {
+			form["message"] = message
+		}
+		if link != "" {
+			form["link"] = link
+		}
+	}
+
+	result := &PostResult{
+		PageID:      pageID,
+		PostType:    postType,
+		Mode:        PostModePublished,
+		RequestPath: path,
+	}
+
+	publishAt := strings.TrimSpace(options.PublishAt)
+	if publishAt == "" && options.RequireTime {
+		return graph.Request{}, nil, errors.New("publish-at is required to schedule a page post")
+	}
+	if publishAt != "" {
+		scheduledAt, err := parseSchedulePublishTime(publishAt, options.Now)
+		if err != nil {
+			return graph.Request{}, nil, err
+		}
+		form["published"] = "false"
+		form["scheduled_publish_time"] = strconv.FormatInt(scheduledAt.Unix(), 10)
+		result.Mode = PostModeScheduled
+		result.ScheduledPublishTime = scheduledAt.Format(time.RFC3339)
+	}
+
+	return graph.Request{
+		Method:      "POST",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Form:        form,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, result, nil
+}
+
+func BuildListPostsRequest(version string, token string, appSecret string, options ListPostsOptions) (graph.Request, ListPostsOptions, error) {
+	pageID, err := normalizeGraphID("page id", options.PageID)
+	if err != nil {
+		return graph.Request{}, ListPostsOptions{}, err
+	}
+	kind := strings.ToLower(strings.TrimSpace(options.Kind))
+	if kind == "" {
+		kind = ListKindPublished
+	}
+	if kind != ListKindPublished && kind != ListKindScheduled {
+		return graph.Request{}, ListPostsOptions{}, fmt.Errorf("invalid page post list kind %q: expected %s|%s", options.Kind, ListKindPublished, ListKindScheduled)
+	}
+	if options.Limit < 0 {
+		return graph.Request{}, ListPostsOptions{}, errors.New("page post limit must be >= 0")
+	}
+	if options.PageSize < 0 {
+		return graph.Request{}, ListPostsOptions{}, errors.New("page post page size must be >= 0")
+	}
+
+	fields := make([]string, 0, len(options.Fields))
+	for _, field := range options.Fields {
+		if trimmed := strings.TrimSpace(field); trimmed != "" {
+			fields = append(fields, trimmed)
+		}
+	}
+	if len(fields) == 0 {
+		fields = append(fields, DefaultListFields...)
+	}
+
+	query := map[string]string{
+		"fields": strings.Join(fields, ","),
+	}
+	if options.PageSize > 0 {
+		query["limit"] = strconv.Itoa(options.PageSize)
+	}
+
+	normalized := options
+	normalized.PageID = pageID
+	normalized.Kind = kind
+	normalized.Fields = fields
+
+	return graph.Request{
+		Method:      "GET",
+		Path:        fmt.Sprintf("%s/%s_posts", pageID, kind),
+		Version:     strings.TrimSpace(version),
+		Query:       query,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, normalized, nil
+}
+
+func BuildDeletePostRequest(version string, token string, appSecret string, options DeletePostOptions) (graph.Request, string, error) {
+	postID, err := normalizeGraphID("post id", options.PostID)
+	if err != nil {
+		return graph.Request{}, "", err
+	}
+	return graph.Request{
+		Method:      "DELETE",
+		Path:        postID,
+		Version:     strings.TrimSpace(version),
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, postID, nil
+}
+
+// ValidatePageToken requires a page-scoped profile token; page feed writes made with
+// user or system-user tokens fail at the Graph API with opaque permission errors.
+func ValidatePageToken(profileName string, profile config.Profile) error {
+	tokenType := strings.ToLower(strings.TrimSpace(profile.TokenType))
+	if tokenType == tokenTypePage {
+		return nil
+	}
+	return &graph.APIError{
+		Type:      pageErrorTypeTokenRequired,
+		Code:      pageErrorCodeTokenRequired,
+		Message:   fmt.Sprintf("profile %q has token_type %q; page commands require a page token", profileName, tokenType),
+		Retryable: false,
+		Diagnostics: map[string]any{
+			"profile":    profileName,
+			"token_type": tokenType,
+			"page_id":    strings.TrimSpace(profile.PageID),
+		},
+		Remediation: &graph.Remediation{
+			Category: graph.RemediationCategoryAuth,
+			Summary:  "Facebook Page publishing requires a page access token.",
+			Actions: []string{
+				"Derive one with `meta auth page-token --profile <page-profile> --page-id <PAGE_ID> --source-profile <user-profile>`.",
+				"Run page commands with --profile <page-profile>.",
+			},
+		},
+	}
+}
+
+func parseSchedulePublishTime(raw string, now time.Time) (time.Time, error) {
+	publishAt, err := time.Parse(time.RFC3339, strings.TrimSpace(raw))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid publish-at %q: expected RFC3339 timestamp", raw)
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+	lead := publishAt.Sub(now)
+	if lead < MinScheduleLead || lead > MaxScheduleLead {
+		return time.Time{}, fmt.Errorf("publish-at %s must be between 10 minutes and 30 days from now", publishAt.UTC().Format(time.RFC3339))
+	}
+	return publishAt.UTC(), nil
+}
+
+func validateAbsoluteURL(label string, value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid %s %q: expected absolute http(s) URL", label, value)
+	}
+	return nil
+}
+
+func normalizeGraphID(label string, value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "", fmt.Errorf("%s is required", label)
+	}
+	if strings.Contains(trimmed, "/") {
+		return "", fmt.Errorf("invalid %s %q: expected single graph id token", label, value)
+	}
+	return trimmed, nil
+}
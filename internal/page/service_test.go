@@ -0,0 +1,193 @@
+package page
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestBuildPostRequestRoutesContentToEdges(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		options  PostOptions
+		path     string
+		postType string
+		form     map[string]string
+	}{
+		{
+			name:     "text",
+			options:  PostOptions{PageID: "page_1", Message: "hello"},
+			path:     "page_1/feed",
+			postType: PostTypeText,
+			form:     map[string]string{"message": "hello"},
+		},
+		{
+			name:     "link",
+			options:  PostOptions{PageID: "page_1", Link: "https://example.com/launch"},
+			path:     "page_1/feed",
+			postType: PostTypeLink,
+			form:     map[string]string{"link": "https://example.com/launch"},
+		},
+		{
+			name:     "photo",
+			options:  PostOptions{PageID: "page_1", Message: "look", PhotoURL: "https://cdn.example.com/a.jpg"},
+			path:     "page_1/photos",
+			postType: PostTypePhoto,
+			form:     map[string]string{"url": "https://cdn.example.com/a.jpg", "caption": "look"},
+		},
+		{
+			name:     "video",
+			options:  PostOptions{PageID: "page_1", Message: "watch", VideoURL: "https://cdn.example.com/a.mp4", VideoTitle: "Launch"},
+			path:     "page_1/videos",
+			postType: PostTypeVideo,
+			form:     map[string]string{"file_url": "https://cdn.example.com/a.mp4", "description": "watch", "title": "Launch"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, result, err := BuildPostRequest("v25.0", "page-token", "", tc.options)
+			if err != nil {
+				t.Fatalf("build post request: %v", err)
+			}
+			if req.Method != "POST" || req.Path != tc.path {
+				t.Fatalf("unexpected request %s %s", req.Method, req.Path)
+			}
+			if result.PostType != tc.postType || result.Mode != PostModePublished {
+				t.Fatalf("unexpected result %#v", result)
+			}
+			if len(req.Form) != len(tc.form) {
+				t.Fatalf("unexpected form %#v", req.Form)
+			}
+			for key, want := range tc.form {
+				if req.Form[key] != want {
+					t.Fatalf("unexpected form[%s]=%q want %q", key, req.Form[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildPostRequestValidatesInput(t *testing.T) {
+	t.Parallel()
+
+	for name, options := range map[string]PostOptions{
+		"missing page":     {Message: "hi"},
+		"empty text":       {PageID: "page_1"},
+		"photo and video":  {PageID: "page_1", PhotoURL: "https://a/x.jpg", VideoURL: "https://a/x.mp4"},
+		"link with photo":  {PageID: "page_1", Link: "https://a", PhotoURL: "https://a/x.jpg"},
+		"relative link":    {PageID: "page_1", Link: "example.com"},
+		"schedule no time": {PageID: "page_1", Message: "hi", RequireTime: true},
+	} {
+		if _, _, err := BuildPostRequest("v25.0", "token", "", options); err == nil {
+			t.Fatalf("expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestBuildPostRequestSchedulesWithinWindow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	req, result, err := BuildPostRequest("v25.0", "token", "", PostOptions{
+		PageID:    "page_1",
+		Message:   "later",
+		PublishAt: "2026-04-02T09:30:00+02:00",
+		Now:       now,
+	})
+	if err != nil {
+		t.Fatalf("build scheduled post: %v", err)
+	}
+	if req.Form["published"] != "false" || req.Form["scheduled_publish_time"] != "1775115000" {
+		t.Fatalf("unexpected schedule form %#v", req.Form)
+	}
+	if result.Mode != PostModeScheduled || result.ScheduledPublishTime != "2026-04-02T07:30:00Z" {
+		t.Fatalf("unexpected result %#v", result)
+	}
+
+	for _, publishAt := range []string{"2026-04-01T12:05:00Z", "2026-05-15T12:00:00Z", "tomorrow"} {
+		if _, _, err := BuildPostRequest("v25.0", "token", "", PostOptions{PageID: "page_1", Message: "x", PublishAt: publishAt, Now: now}); err == nil {
+			t.Fatalf("expected publish-at %q to be rejected", publishAt)
+		}
+	}
+}
+
+func TestBuildListPostsRequestSelectsEdge(t *testing.T) {
+	t.Parallel()
+
+	req, normalized, err := BuildListPostsRequest("v25.0", "token", "", ListPostsOptions{PageID: "page_1", Kind: "Scheduled", PageSize: 25})
+	if err != nil {
+		t.Fatalf("build list request: %v", err)
+	}
+	if req.Path != "page_1/scheduled_posts" || req.Query["limit"] != "25" || normalized.Kind != ListKindScheduled {
+		t.Fatalf("unexpected request %#v", req)
+	}
+	if req.Query["fields"] != strings.Join(DefaultListFields, ",") {
+		t.Fatalf("unexpected fields %q", req.Query["fields"])
+	}
+	if _, _, err := BuildListPostsRequest("v25.0", "token", "", ListPostsOptions{PageID: "page_1", Kind: "drafts"}); err == nil {
+		t.Fatal("expected invalid kind error")
+	}
+}
+
+func TestServicePostAndDelete(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v25.0/page_1/photos":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "photo_1", "post_id": "page_1_post_1"})
+		case r.Method == http.MethodDelete && r.URL.Path == "/v25.0/page_1_post_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"success": true})
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	service := New(graph.NewClient(server.Client(), server.URL))
+	posted, err := service.Post(context.Background(), "v25.0", "token", "", PostOptions{PageID: "page_1", PhotoURL: "https://cdn.example.com/a.jpg"})
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if posted.ID != "photo_1" || posted.PostID != "page_1_post_1" {
+		t.Fatalf("unexpected post result %#v", posted)
+	}
+
+	deleted, err := service.DeletePost(context.Background(), "v25.0", "token", "", DeletePostOptions{PostID: posted.PostID})
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if !deleted.Deleted {
+		t.Fatalf("expected deleted=true, got %#v", deleted)
+	}
+}
+
+func TestValidatePageTokenRequiresPageTokenType(t *testing.T) {
+	t.Parallel()
+
+	if err := ValidatePageToken("page", config.Profile{TokenType: "page"}); err != nil {
+		t.Fatalf("expected page token to pass, got %v", err)
+	}
+	err := ValidatePageToken("prod", config.Profile{TokenType: "system_user", PageID: "page_1"})
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != pageErrorTypeTokenRequired {
+		t.Fatalf("expected page token error, got %v", err)
+	}
+	if apiErr.Remediation == nil || apiErr.Remediation.Category != graph.RemediationCategoryAuth {
+		t.Fatalf("expected auth remediation, got %#v", apiErr.Remediation)
+	}
+}
@@ -0,0 +1,257 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ExternalBinaryPrefix           = "meta-"
+	ExternalConfigSchemaVersion    = 1
+	ExternalHandshakeSchemaVersion = 1
+
+	ExternalSourcePath   = "path"
+	ExternalSourceConfig = "config"
+)
+
+type ExternalPlugin struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Short  string `json:"short"`
+	Source string `json:"source"`
+}
+
+type ExternalConfig struct {
+	SchemaVersion int                   `yaml:"schema_version"`
+	Plugins       []ExternalConfigEntry `yaml:"plugins"`
+	Trace         *TraceConfig          `yaml:"trace,omitempty"`
+}
+
+type ExternalConfigEntry struct {
+	Name  string `yaml:"name"`
+	Path  string `yaml:"path"`
+	Short string `yaml:"short,omitempty"`
+}
+
+type ExternalDiscoveryOptions struct {
+	PathEnv    string
+	ConfigPath string
+	Reserved   []string
+}
+
+// ExternalHandshake is written as a single JSON document to the plugin's stdin.
+// Profile is omitted when no profile was selected for the invocation.
+type ExternalHandshake struct {
+	SchemaVersion int                       `json:"schema_version"`
+	Plugin        string                    `json:"plugin"`
+	Args          []string                  `json:"args"`
+	Runtime       ExternalHandshakeRuntime  `json:"runtime"`
+	Profile       *ExternalHandshakeProfile `json:"profile,omitempty"`
+}
+
+type ExternalHandshakeRuntime struct {
+	Output string `json:"output"`
+	Debug  bool   `json:"debug"`
+}
+
+type ExternalHandshakeProfile struct {
+	Name         string   `json:"name"`
+	Domain       string   `json:"domain"`
+	GraphVersion string   `json:"graph_version"`
+	TokenType    string   `json:"token_type"`
+	Token        string   `json:"token"`
+	AppSecret    string   `json:"app_secret,omitempty"`
+	AppID        string   `json:"app_id,omitempty"`
+	BusinessID   string   `json:"business_id,omitempty"`
+	PageID       string   `json:"page_id,omitempty"`
+	IGUserID     string   `json:"ig_user_id,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+}
+
+func DefaultExternalConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "plugins.yaml"), nil
+}
+
+func LoadExternalConfig(path string) (*ExternalConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: plugin config file does not exist at %s", os.ErrNotExist, path)
+		}
+		return nil, fmt.Errorf("read plugin config %s: %w", path, err)
+	}
+
+	cfg := &ExternalConfig{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("decode plugin config %s: %w", path, err)
+	}
+	if cfg.SchemaVersion != ExternalConfigSchemaVersion {
+		return nil, fmt.Errorf("plugin config %s has unsupported schema_version %d; expected %d", path, cfg.SchemaVersion, ExternalConfigSchemaVersion)
+	}
+	for index, entry := range cfg.Plugins {
+		if err := validateNameToken(fmt.Sprintf("plugins[%d].name", index), entry.Name); err != nil {
+			return nil, fmt.Errorf("plugin config %s: %w", path, err)
+		}
+		if strings.TrimSpace(entry.Path) == "" {
+			return nil, fmt.Errorf("plugin config %s: plugins[%d].path is required", path, index)
+		}
+	}
+	if cfg.Trace != nil {
+		if err := cfg.Trace.Validate(); err != nil {
+			return nil, fmt.Errorf("plugin config %s: %w", path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// DiscoverExternal resolves external plugins from plugins.yaml and from meta-<name>
+// executables on PATH. Config entries win over PATH, earlier PATH directories win
+// over later ones, and PATH binaries that collide with reserved (built-in) command
+// names are ignored.
+func DiscoverExternal(options ExternalDiscoveryOptions) ([]ExternalPlugin, error) {
+	reserved := make(map[string]bool, len(options.Reserved))
+	for _, name := range options.Reserved {
+		reserved[name] = true
+	}
+	discovered := map[string]ExternalPlugin{}
+
+	if configPath := strings.TrimSpace(options.ConfigPath); configPath != "" {
+		cfg, err := LoadExternalConfig(configPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if cfg != nil {
+			for _, entry := range cfg.Plugins {
+				if reserved[entry.Name] {
+					return nil, fmt.Errorf("plugin config %s: plugin %q collides with a built-in command", configPath, entry.Name)
+				}
+				if _, exists := discovered[entry.Name]; exists {
+					return nil, fmt.Errorf("plugin config %s: plugin %q is declared more than once", configPath, entry.Name)
+				}
+				path := entry.Path
+				if !filepath.IsAbs(path) {
+					path = filepath.Join(filepath.Dir(configPath), path)
+				}
+				if !isExecutableFile(path) {
+					return nil, fmt.Errorf("plugin config %s: plugin %q path %s is not an executable file", configPath, entry.Name, path)
+				}
+				short := strings.TrimSpace(entry.Short)
+				if short == "" {
+					short = externalDefaultShort(path)
+				}
+				discovered[entry.Name] = ExternalPlugin{Name: entry.Name, Path: path, Short: short, Source: ExternalSourceConfig}
+			}
+		}
+	}
+
+	for _, dir := range filepath.SplitList(options.PathEnv) {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := externalBinaryName(entry.Name())
+			if !ok || reserved[name] {
+				continue
+			}
+			if _, exists := discovered[name]; exists {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutableFile(path) {
+				continue
+			}
+			discovered[name] = ExternalPlugin{Name: name, Path: path, Short: externalDefaultShort(path), Source: ExternalSourcePath}
+		}
+	}
+
+	plugins := make([]ExternalPlugin, 0, len(discovered))
+	for _, external := range discovered {
+		plugins = append(plugins, external)
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+	return plugins, nil
+}
+
+// RunExternal executes the plugin with the handshake on stdin. The plugin owns
+// stdout/stderr; a non-zero exit status is returned as an error.
+func RunExternal(ctx context.Context, external ExternalPlugin, handshake ExternalHandshake, stdout io.Writer, stderr io.Writer) error {
+	handshake.SchemaVersion = ExternalHandshakeSchemaVersion
+	handshake.Plugin = external.Name
+	if handshake.Args == nil {
+		handshake.Args = []string{}
+	}
+	payload, err := json.Marshal(handshake)
+	if err != nil {
+		return fmt.Errorf("encode plugin handshake: %w", err)
+	}
+
+	command := exec.CommandContext(ctx, external.Path, handshake.Args...)
+	command.Stdin = bytes.NewReader(append(payload, '\n'))
+	command.Stdout = stdout
+	command.Stderr = stderr
+	command.Env = append(os.Environ(),
+		"META_PLUGIN_NAME="+external.Name,
+		fmt.Sprintf("META_PLUGIN_HANDSHAKE_VERSION=%d", ExternalHandshakeSchemaVersion),
+	)
+	if err := command.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("external plugin %q exited with status %d", external.Name, exitErr.ExitCode())
+		}
+		return fmt.Errorf("run external plugin %q: %w", external.Name, err)
+	}
+	return nil
+}
+
+func externalBinaryName(fileName string) (string, bool) {
+	if !strings.HasPrefix(fileName, ExternalBinaryPrefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(fileName, ExternalBinaryPrefix)
+	if goruntime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	if validateNameToken("plugin name", name) != nil {
+		return "", false
+	}
+	return name, true
+}
+
+func isExecutableFile(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	if goruntime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode().Perm()&0o111 != 0
+}
+
+func externalDefaultShort(path string) string {
+	return fmt.Sprintf("External plugin (%s)", path)
+}
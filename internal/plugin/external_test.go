@@ -0,0 +1,127 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"testing"
+)
+
+func writeExternalTestBinary(t *testing.T, dir string, name string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\ncat\n"), mode); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	return path
+}
+
+func TestDiscoverExternalScansPathAndConfig(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("executable bit discovery is unix-only")
+	}
+	t.Parallel()
+
+	first := t.TempDir()
+	second := t.TempDir()
+	configDir := t.TempDir()
+
+	firstFoo := writeExternalTestBinary(t, first, "meta-foo", 0o755)
+	writeExternalTestBinary(t, second, "meta-foo", 0o755)
+	writeExternalTestBinary(t, second, "meta-ig", 0o755)
+	writeExternalTestBinary(t, second, "meta-noexec", 0o644)
+	writeExternalTestBinary(t, second, "meta-Bad", 0o755)
+	writeExternalTestBinary(t, second, "meta-bar", 0o755)
+	writeExternalTestBinary(t, configDir, "bar-plugin", 0o755)
+
+	configPath := filepath.Join(configDir, "plugins.yaml")
+	config := "schema_version: 1\nplugins:\n  - name: bar\n    path: bar-plugin\n    short: Bar tools\n"
+	if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	plugins, err := DiscoverExternal(ExternalDiscoveryOptions{
+		PathEnv:    strings.Join([]string{first, second}, string(os.PathListSeparator)),
+		ConfigPath: configPath,
+		Reserved:   []string{"ig"},
+	})
+	if err != nil {
+		t.Fatalf("discover external plugins: %v", err)
+	}
+	if len(plugins) != 2 {
+		t.Fatalf("expected bar and foo, got %#v", plugins)
+	}
+	if plugins[0].Name != "bar" || plugins[0].Source != ExternalSourceConfig || plugins[0].Short != "Bar tools" || plugins[0].Path != filepath.Join(configDir, "bar-plugin") {
+		t.Fatalf("unexpected config plugin %#v", plugins[0])
+	}
+	if plugins[1].Name != "foo" || plugins[1].Source != ExternalSourcePath || plugins[1].Path != firstFoo {
+		t.Fatalf("unexpected path plugin %#v", plugins[1])
+	}
+}
+
+func TestDiscoverExternalRejectsInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"schema":   "schema_version: 2\nplugins: []\n",
+		"unknown":  "schema_version: 1\nplugins: []\nextra: true\n",
+		"name":     "schema_version: 1\nplugins:\n  - name: Foo\n    path: /bin/true\n",
+		"reserved": "schema_version: 1\nplugins:\n  - name: ig\n    path: /bin/sh\n",
+		"missing":  "schema_version: 1\nplugins:\n  - name: foo\n    path: does-not-exist\n",
+	} {
+		path := filepath.Join(dir, name+".yaml")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		if _, err := DiscoverExternal(ExternalDiscoveryOptions{ConfigPath: path, Reserved: []string{"ig"}}); err == nil {
+			t.Fatalf("expected %s config to be rejected", name)
+		}
+	}
+
+	if _, err := DiscoverExternal(ExternalDiscoveryOptions{ConfigPath: filepath.Join(dir, "absent.yaml")}); err != nil {
+		t.Fatalf("expected missing config to be ignored, got %v", err)
+	}
+}
+
+func TestRunExternalWritesHandshakeToStdin(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("shell script plugin is unix-only")
+	}
+	t.Parallel()
+
+	path := writeExternalTestBinary(t, t.TempDir(), "meta-echo", 0o755)
+	stdout := &bytes.Buffer{}
+	err := RunExternal(context.Background(), ExternalPlugin{Name: "echo", Path: path}, ExternalHandshake{
+		Args:    []string{"run", "--fast"},
+		Runtime: ExternalHandshakeRuntime{Output: "json"},
+		Profile: &ExternalHandshakeProfile{Name: "prod", GraphVersion: "v25.0", Token: "token"},
+	}, stdout, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("run external plugin: %v", err)
+	}
+
+	handshake := ExternalHandshake{}
+	if err := json.Unmarshal(stdout.Bytes(), &handshake); err != nil {
+		t.Fatalf("decode handshake %q: %v", stdout.String(), err)
+	}
+	if handshake.SchemaVersion != ExternalHandshakeSchemaVersion || handshake.Plugin != "echo" {
+		t.Fatalf("unexpected handshake header %#v", handshake)
+	}
+	if strings.Join(handshake.Args, " ") != "run --fast" || handshake.Profile == nil || handshake.Profile.Token != "token" {
+		t.Fatalf("unexpected handshake payload %#v", handshake)
+	}
+
+	failing := filepath.Join(t.TempDir(), "meta-fail")
+	if err := os.WriteFile(failing, []byte("#!/bin/sh\nexit 3\n"), 0o755); err != nil {
+		t.Fatalf("write failing plugin: %v", err)
+	}
+	err = RunExternal(context.Background(), ExternalPlugin{Name: "fail", Path: failing}, ExternalHandshake{}, &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "exited with status 3") {
+		t.Fatalf("expected exit status error, got %v", err)
+	}
+}
@@ -8,10 +8,10 @@ import (
 )
 
 type TraceEvent struct {
-	PluginID  string
-	Namespace string
-	Command   string
-	Timestamp time.Time
+	PluginID  string    `json:"plugin_id"`
+	Namespace string    `json:"namespace"`
+	Command   string    `json:"command"`
+	Timestamp time.Time `json:"timestamp"`
 }
 
 type Tracer interface {
@@ -69,8 +69,10 @@ func (t *NamespaceTracer) Trace(event TraceEvent) error {
 		return fmt.Errorf("namespace tracer mismatch: expected %q got %q", t.namespace, event.Namespace)
 	}
 	t.mu.Lock()
-	defer t.mu.Unlock()
 	t.events = append(t.events, event)
+	t.mu.Unlock()
+
+	EmitTrace(event)
 	return nil
 }
 
@@ -0,0 +1,341 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/transport"
+)
+
+const (
+	TraceSinkFile   = "file"
+	TraceSinkStderr = "stderr"
+	TraceSinkOTLP   = "otlp"
+
+	otlpTracesPath       = "/v1/traces"
+	otlpServiceName      = "meta-cli"
+	defaultOTLPTimeout   = 2 * time.Second
+	defaultTraceTailSize = 20
+)
+
+type TraceConfig struct {
+	Sinks []TraceSinkConfig `yaml:"sinks"`
+}
+
+type TraceSinkConfig struct {
+	Type     string            `yaml:"type"`
+	Path     string            `yaml:"path,omitempty"`
+	Endpoint string            `yaml:"endpoint,omitempty"`
+	Headers  map[string]string `yaml:"headers,omitempty"`
+}
+
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+var (
+	sinkMu sync.RWMutex
+	sink   Tracer
+)
+
+// SetTraceSink installs the process-wide sink that receives every namespace trace
+// event. Passing nil disables export.
+func SetTraceSink(tracer Tracer) {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+	sink = tracer
+}
+
+// EmitTrace forwards an event to the configured sink. Export is best-effort so a
+// broken sink never fails the command being traced.
+func EmitTrace(event TraceEvent) {
+	sinkMu.RLock()
+	current := sink
+	sinkMu.RUnlock()
+	if current == nil {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	_ = current.Trace(event)
+}
+
+func DefaultTraceLogPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "plugin_trace.jsonl"), nil
+}
+
+func (c TraceConfig) Validate() error {
+	for index, sinkConfig := range c.Sinks {
+		switch strings.TrimSpace(sinkConfig.Type) {
+		case TraceSinkFile, TraceSinkStderr:
+		case TraceSinkOTLP:
+			if _, err := otlpTracesURL(sinkConfig.Endpoint); err != nil {
+				return fmt.Errorf("trace.sinks[%d]: %w", index, err)
+			}
+		default:
+			return fmt.Errorf("trace.sinks[%d]: unsupported sink type %q; expected %s|%s|%s", index, sinkConfig.Type, TraceSinkFile, TraceSinkStderr, TraceSinkOTLP)
+		}
+	}
+	return nil
+}
+
+// NewTraceSink builds a tracer fanning out to every configured sink. File sinks
+// without a path write to DefaultTraceLogPath.
+func NewTraceSink(cfg TraceConfig, stderr io.Writer, client HTTPClient) (Tracer, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	sinks := make(MultiTracer, 0, len(cfg.Sinks))
+	for _, sinkConfig := range cfg.Sinks {
+		switch strings.TrimSpace(sinkConfig.Type) {
+		case TraceSinkFile:
+			path := strings.TrimSpace(sinkConfig.Path)
+			if path == "" {
+				defaultPath, err := DefaultTraceLogPath()
+				if err != nil {
+					return nil, err
+				}
+				path = defaultPath
+			}
+			sinks = append(sinks, &FileTraceSink{Path: path})
+		case TraceSinkStderr:
+			if stderr == nil {
+				return nil, errors.New("stderr trace sink requires a writer")
+			}
+			sinks = append(sinks, &WriterTraceSink{Writer: stderr})
+		case TraceSinkOTLP:
+			endpoint, _ := otlpTracesURL(sinkConfig.Endpoint)
+			if client == nil {
+				client = &http.Client{Timeout: defaultOTLPTimeout, Transport: transport.RefuseOffline(nil)}
+			}
+			sinks = append(sinks, &OTLPTraceSink{Endpoint: endpoint, Headers: sinkConfig.Headers, Client: client})
+		}
+	}
+	return sinks, nil
+}
+
+type MultiTracer []Tracer
+
+func (m MultiTracer) Trace(event TraceEvent) error {
+	var errs []error
+	for _, tracer := range m {
+		if err := tracer.Trace(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type WriterTraceSink struct {
+	Writer io.Writer
+	mu     sync.Mutex
+}
+
+func (s *WriterTraceSink) Trace(event TraceEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode trace event: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.Writer.Write(append(line, '\n'))
+	return err
+}
+
+type FileTraceSink struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (s *FileTraceSink) Trace(event TraceEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode trace event: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		return fmt.Errorf("create trace log directory for %s: %w", s.Path, err)
+	}
+	file, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open trace log %s: %w", s.Path, err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("write trace log %s: %w", s.Path, err)
+	}
+	return file.Close()
+}
+
+// OTLPTraceSink exports each event as a zero-duration span using OTLP/HTTP JSON.
+type OTLPTraceSink struct {
+	Endpoint string
+	Headers  map[string]string
+	Client   HTTPClient
+}
+
+func (s *OTLPTraceSink) Trace(event TraceEvent) error {
+	payload, err := json.Marshal(otlpPayload(event))
+	if err != nil {
+		return fmt.Errorf("encode otlp trace payload: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOTLPTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build otlp trace request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("export otlp trace: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("export otlp trace: collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func otlpTracesURL(endpoint string) (string, error) {
+	trimmed := strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	if trimmed == "" {
+		return "", errors.New("otlp sink endpoint is required")
+	}
+	parsed, err := url.Parse(trimmed)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("invalid otlp sink endpoint %q: expected http(s) URL", endpoint)
+	}
+	if !strings.HasSuffix(parsed.Path, otlpTracesPath) {
+		trimmed += otlpTracesPath
+	}
+	return trimmed, nil
+}
+
+func otlpPayload(event TraceEvent) map[string]any {
+	timestamp := strconv.FormatInt(event.Timestamp.UnixNano(), 10)
+	return map[string]any{
+		"resourceSpans": []any{
+			map[string]any{
+				"resource": map[string]any{
+					"attributes": []any{otlpStringAttribute("service.name", otlpServiceName)},
+				},
+				"scopeSpans": []any{
+					map[string]any{
+						"scope": map[string]any{"name": "github.com/bilalbayram/metacli/internal/plugin"},
+						"spans": []any{
+							map[string]any{
+								"traceId":           randomHex(16),
+								"spanId":            randomHex(8),
+								"name":              event.Namespace + "." + event.Command,
+								"kind":              1,
+								"startTimeUnixNano": timestamp,
+								"endTimeUnixNano":   timestamp,
+								"attributes": []any{
+									otlpStringAttribute("meta.plugin.id", event.PluginID),
+									otlpStringAttribute("meta.plugin.namespace", event.Namespace),
+									otlpStringAttribute("meta.plugin.command", event.Command),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func otlpStringAttribute(key string, value string) map[string]any {
+	return map[string]any{"key": key, "value": map[string]any{"stringValue": value}}
+}
+
+func randomHex(size int) string {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return strings.Repeat("0", size*2)
+	}
+	return hex.EncodeToString(buf)
+}
+
+type TraceTailOptions struct {
+	Path      string
+	Limit     int
+	Namespace string
+	PluginID  string
+}
+
+// TailTraceLog returns the most recent matching events from a jsonl trace log,
+// oldest first.
+func TailTraceLog(options TraceTailOptions) ([]TraceEvent, error) {
+	limit := options.Limit
+	if limit == 0 {
+		limit = defaultTraceTailSize
+	}
+	if limit < 0 {
+		return nil, errors.New("trace tail limit must be >= 0")
+	}
+
+	file, err := os.Open(options.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: trace log does not exist at %s; configure a file sink in plugins.yaml", os.ErrNotExist, options.Path)
+		}
+		return nil, fmt.Errorf("open trace log %s: %w", options.Path, err)
+	}
+	defer file.Close()
+
+	events := make([]TraceEvent, 0, limit)
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		event := TraceEvent{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return nil, fmt.Errorf("decode trace log %s line %d: %w", options.Path, lineNumber, err)
+		}
+		if options.Namespace != "" && event.Namespace != options.Namespace {
+			continue
+		}
+		if options.PluginID != "" && event.PluginID != options.PluginID {
+			continue
+		}
+		if len(events) == limit {
+			events = append(events[1:], event)
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read trace log %s: %w", options.Path, err)
+	}
+	return events, nil
+}
@@ -0,0 +1,138 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewTraceSinkRejectsInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	for name, cfg := range map[string]TraceConfig{
+		"unknown type":  {Sinks: []TraceSinkConfig{{Type: "syslog"}}},
+		"otlp missing":  {Sinks: []TraceSinkConfig{{Type: TraceSinkOTLP}}},
+		"otlp relative": {Sinks: []TraceSinkConfig{{Type: TraceSinkOTLP, Endpoint: "collector:4318"}}},
+	} {
+		if _, err := NewTraceSink(cfg, &bytes.Buffer{}, nil); err == nil {
+			t.Fatalf("expected %s config to be rejected", name)
+		}
+	}
+}
+
+func TestFileTraceSinkAndTailTraceLog(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "traces", "plugin_trace.jsonl")
+	stderr := &bytes.Buffer{}
+	sink, err := NewTraceSink(TraceConfig{Sinks: []TraceSinkConfig{
+		{Type: TraceSinkFile, Path: path},
+		{Type: TraceSinkStderr},
+	}}, stderr, nil)
+	if err != nil {
+		t.Fatalf("new trace sink: %v", err)
+	}
+
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	for index, event := range []TraceEvent{
+		{PluginID: "instagram", Namespace: "ig", Command: "health"},
+		{PluginID: "instagram", Namespace: "ig", Command: "publish-feed"},
+		{PluginID: "facebook-page", Namespace: "page", Command: "post"},
+		{PluginID: "instagram", Namespace: "ig", Command: "publish-reel"},
+	} {
+		event.Timestamp = base.Add(time.Duration(index) * time.Minute)
+		if err := sink.Trace(event); err != nil {
+			t.Fatalf("trace event: %v", err)
+		}
+	}
+	if lines := strings.Count(stderr.String(), "\n"); lines != 4 {
+		t.Fatalf("expected four stderr trace lines, got %d", lines)
+	}
+
+	events, err := TailTraceLog(TraceTailOptions{Path: path, Limit: 2, Namespace: "ig"})
+	if err != nil {
+		t.Fatalf("tail trace log: %v", err)
+	}
+	if len(events) != 2 || events[0].Command != "publish-feed" || events[1].Command != "publish-reel" {
+		t.Fatalf("unexpected tail events %#v", events)
+	}
+	if !events[1].Timestamp.Equal(base.Add(3 * time.Minute)) {
+		t.Fatalf("unexpected timestamp %s", events[1].Timestamp)
+	}
+
+	if _, err := TailTraceLog(TraceTailOptions{Path: filepath.Join(t.TempDir(), "absent.jsonl")}); err == nil {
+		t.Fatal("expected missing trace log error")
+	}
+}
+
+func TestOTLPTraceSinkExportsSpan(t *testing.T) {
+	t.Parallel()
+
+	var (
+		gotPath   string
+		gotHeader string
+		payload   map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink, err := NewTraceSink(TraceConfig{Sinks: []TraceSinkConfig{{
+		Type:     TraceSinkOTLP,
+		Endpoint: server.URL,
+		Headers:  map[string]string{"Authorization": "Bearer collector-token"},
+	}}}, nil, server.Client())
+	if err != nil {
+		t.Fatalf("new trace sink: %v", err)
+	}
+	if err := sink.Trace(TraceEvent{PluginID: "instagram", Namespace: "ig", Command: "health", Timestamp: time.Unix(1775044800, 0)}); err != nil {
+		t.Fatalf("export trace: %v", err)
+	}
+
+	if gotPath != "/v1/traces" || gotHeader != "Bearer collector-token" {
+		t.Fatalf("unexpected otlp request path=%q auth=%q", gotPath, gotHeader)
+	}
+	encoded, _ := json.Marshal(payload)
+	for _, want := range []string{`"name":"ig.health"`, `"stringValue":"instagram"`, `"startTimeUnixNano":"1775044800000000000"`} {
+		if !strings.Contains(string(encoded), want) {
+			t.Fatalf("expected %s in otlp payload %s", want, encoded)
+		}
+	}
+}
+
+func TestNamespaceTracerForwardsToTraceSink(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	SetTraceSink(&WriterTraceSink{Writer: buffer})
+	t.Cleanup(func() { SetTraceSink(nil) })
+
+	tracer, err := NewNamespaceTracer("ig")
+	if err != nil {
+		t.Fatalf("new tracer: %v", err)
+	}
+	runtime, err := NewRuntime(tracer)
+	if err != nil {
+		t.Fatalf("new runtime: %v", err)
+	}
+	if err := runtime.Trace(TraceEvent{PluginID: "instagram", Namespace: "ig", Command: "health"}); err != nil {
+		t.Fatalf("trace: %v", err)
+	}
+
+	event := TraceEvent{}
+	if err := json.Unmarshal(buffer.Bytes(), &event); err != nil {
+		t.Fatalf("decode forwarded event %q: %v", buffer.String(), err)
+	}
+	if event.PluginID != "instagram" || event.Command != "health" || event.Timestamp.IsZero() {
+		t.Fatalf("unexpected forwarded event %#v", event)
+	}
+}
@@ -0,0 +1,238 @@
+// Package progress draws single-line progress bars on a terminal for long
+// operations: chunked uploads, async report polling, bulk batches and
+// pagination. A bar travels in the context so lower layers, such as the Graph
+// client backing off after a rate limit, can say why the operation is waiting.
+//
+// Every method accepts a nil *Bar and does nothing, so callers never check
+// whether progress is enabled.
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	barWidth = 24
+	// redrawInterval keeps fast loops from flooding the terminal.
+	redrawInterval = 100 * time.Millisecond
+)
+
+type Bar struct {
+	w     io.Writer
+	label string
+	now   func() time.Time
+
+	mu        sync.Mutex
+	started   time.Time
+	total     int64
+	done      int64
+	unit      string
+	detail    string
+	waitFor   string
+	waitUntil time.Time
+	lastDraw  time.Time
+	width     int
+	finished  bool
+}
+
+// New returns a bar drawing on w. total may be zero when it is not known yet;
+// the bar then shows a count and the elapsed time instead of a percentage.
+func New(w io.Writer, label string, total int64) *Bar {
+	return newBar(w, label, total, time.Now)
+}
+
+func newBar(w io.Writer, label string, total int64, now func() time.Time) *Bar {
+	return &Bar{w: w, label: label, total: total, now: now, started: now()}
+}
+
+type barKey struct{}
+
+// WithBar returns ctx carrying bar.
+func WithBar(ctx context.Context, bar *Bar) context.Context {
+	if bar == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, barKey{}, bar)
+}
+
+// Without returns ctx with no bar, for work whose own progress would fight the
+// caller's, such as parallel slices each polling a report.
+func Without(ctx context.Context) context.Context {
+	return context.WithValue(ctx, barKey{}, (*Bar)(nil))
+}
+
+// FromContext returns the bar carried by ctx, or nil.
+func FromContext(ctx context.Context) *Bar {
+	if ctx == nil {
+		return nil
+	}
+	bar, _ := ctx.Value(barKey{}).(*Bar)
+	return bar
+}
+
+// SetUnit names what is counted, such as "pages" or "batches".
+func (b *Bar) SetUnit(unit string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.unit = unit
+	b.mu.Unlock()
+}
+
+// Restart begins a new phase counting unit, such as rows fetched after a
+// report finished; the ETA restarts with it.
+func (b *Bar) Restart(unit string, total int64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.unit = unit
+	b.done = 0
+	b.total = total
+	b.detail = ""
+	b.waitFor = ""
+	b.started = b.now()
+	b.drawLocked(true)
+}
+
+// Set records done of total units; a total of zero keeps the current total.
+func (b *Bar) Set(done int64, total int64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done = done
+	if total > 0 {
+		b.total = total
+	}
+	b.waitFor = ""
+	b.drawLocked(false)
+}
+
+// Add advances the bar by n units.
+func (b *Bar) Add(n int64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done += n
+	b.waitFor = ""
+	b.drawLocked(false)
+}
+
+// Detail sets a short note shown after the counts, such as "2 failed".
+func (b *Bar) Detail(detail string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.detail = detail
+	b.drawLocked(false)
+}
+
+// Wait shows that the operation is paused for d and why. The note clears on the
+// next Set or Add.
+func (b *Bar) Wait(reason string, d time.Duration) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.waitFor = reason
+	b.waitUntil = b.now().Add(d)
+	b.drawLocked(true)
+}
+
+// Finish draws the final state and ends the line. Later calls do nothing.
+func (b *Bar) Finish() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.finished {
+		return
+	}
+	b.waitFor = ""
+	b.drawLocked(true)
+	b.finished = true
+	fmt.Fprintln(b.w)
+}
+
+func (b *Bar) drawLocked(force bool) {
+	if b.finished {
+		return
+	}
+	now := b.now()
+	if !force && !b.lastDraw.IsZero() && now.Sub(b.lastDraw) < redrawInterval {
+		return
+	}
+	b.lastDraw = now
+	line := b.renderLocked(now)
+	// Pad over the previous line instead of relying on terminal escapes, so a
+	// shorter line never leaves stale characters behind.
+	padding := ""
+	if len(line) < b.width {
+		padding = strings.Repeat(" ", b.width-len(line))
+	}
+	b.width = len(line)
+	fmt.Fprintf(b.w, "\r%s%s", line, padding)
+}
+
+func (b *Bar) renderLocked(now time.Time) string {
+	elapsed := now.Sub(b.started)
+	parts := []string{b.label}
+	if b.total > 0 {
+		done := min(b.done, b.total)
+		filled := int(done * barWidth / b.total)
+		parts = append(parts,
+			"["+strings.Repeat("=", filled)+strings.Repeat(" ", barWidth-filled)+"]",
+			fmt.Sprintf("%d/%d%s", done, b.total, b.unitSuffix()),
+			fmt.Sprintf("%d%%", done*100/b.total),
+		)
+		if done > 0 && done < b.total {
+			remaining := time.Duration(float64(elapsed) / float64(done) * float64(b.total-done))
+			parts = append(parts, "ETA "+formatDuration(remaining))
+		} else {
+			parts = append(parts, formatDuration(elapsed))
+		}
+	} else {
+		parts = append(parts, fmt.Sprintf("%d%s", b.done, b.unitSuffix()), formatDuration(elapsed))
+	}
+	if b.detail != "" {
+		parts = append(parts, b.detail)
+	}
+	if b.waitFor != "" {
+		wait := "waiting"
+		if remaining := b.waitUntil.Sub(now); remaining > 0 {
+			wait += " " + formatDuration(remaining)
+		}
+		parts = append(parts, wait+": "+b.waitFor)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (b *Bar) unitSuffix() string {
+	if b.unit == "" {
+		return ""
+	}
+	return " " + b.unit
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < time.Second {
+		return "0s"
+	}
+	return d.String()
+}
@@ -0,0 +1,85 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func lastLine(out *bytes.Buffer) string {
+	lines := strings.Split(out.String(), "\r")
+	return strings.TrimRight(lines[len(lines)-1], " \n")
+}
+
+func TestBarRendersCountsAndETA(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	out := &bytes.Buffer{}
+	bar := newBar(out, "bulk import", 4, clock.Now)
+	bar.SetUnit("objects")
+
+	clock.now = clock.now.Add(10 * time.Second)
+	bar.Set(1, 0)
+	if got := lastLine(out); got != "bulk import [======                  ] 1/4 objects 25% ETA 30s" {
+		t.Fatalf("unexpected line %q", got)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	bar.Wait("rate limited by Meta (code 4)", 8*time.Second)
+	if got := lastLine(out); !strings.HasSuffix(got, "waiting 8s: rate limited by Meta (code 4)") {
+		t.Fatalf("expected wait note, got %q", got)
+	}
+
+	clock.now = clock.now.Add(9 * time.Second)
+	bar.Set(4, 0)
+	bar.Finish()
+	if got := lastLine(out); got != "bulk import [========================] 4/4 objects 100% 20s" {
+		t.Fatalf("unexpected final line %q", got)
+	}
+	if !strings.HasSuffix(out.String(), "\n") {
+		t.Fatal("expected Finish to end the line")
+	}
+}
+
+func TestBarThrottlesRedraws(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	out := &bytes.Buffer{}
+	bar := newBar(out, "api get", 0, clock.Now)
+	for range 50 {
+		bar.Add(1)
+	}
+	if draws := strings.Count(out.String(), "\r"); draws != 1 {
+		t.Fatalf("expected one draw within the redraw interval, got %d", draws)
+	}
+	clock.now = clock.now.Add(redrawInterval)
+	bar.Add(1)
+	if got := lastLine(out); got != "api get 51 0s" {
+		t.Fatalf("unexpected line %q", got)
+	}
+}
+
+func TestNilBarAndContext(t *testing.T) {
+	t.Parallel()
+
+	var bar *Bar
+	bar.Set(1, 2)
+	bar.Wait("x", time.Second)
+	bar.Finish()
+
+	ctx := WithBar(context.Background(), New(&bytes.Buffer{}, "x", 0))
+	if FromContext(ctx) == nil {
+		t.Fatal("expected bar in context")
+	}
+	if FromContext(Without(ctx)) != nil {
+		t.Fatal("expected Without to hide the bar")
+	}
+}
@@ -0,0 +1,391 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type function struct {
+	arity int
+	// variadic functions accept arity or more arguments.
+	variadic bool
+	call     func(arguments []any, expressions []node) (any, error)
+}
+
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		"length":      {arity: 1, call: lengthFunction},
+		"keys":        {arity: 1, call: keysFunction},
+		"values":      {arity: 1, call: valuesFunction},
+		"contains":    {arity: 2, call: containsFunction},
+		"starts_with": {arity: 2, call: affixFunction("starts_with", strings.HasPrefix)},
+		"ends_with":   {arity: 2, call: affixFunction("ends_with", strings.HasSuffix)},
+		"join":        {arity: 2, call: joinFunction},
+		"sort":        {arity: 1, call: sortFunction},
+		"reverse":     {arity: 1, call: reverseFunction},
+		"sum":         {arity: 1, call: sumFunction},
+		"avg":         {arity: 1, call: avgFunction},
+		"max":         {arity: 1, call: extremeFunction("max", 1)},
+		"min":         {arity: 1, call: extremeFunction("min", -1)},
+		"sort_by":     {arity: 2, call: sortByFunction},
+		"max_by":      {arity: 2, call: extremeByFunction("max_by", 1)},
+		"min_by":      {arity: 2, call: extremeByFunction("min_by", -1)},
+		"to_string":   {arity: 1, call: toStringFunction},
+		"to_number":   {arity: 1, call: toNumberFunction},
+		"type":        {arity: 1, call: typeFunction},
+		"not_null":    {arity: 1, variadic: true, call: notNullFunction},
+	}
+}
+
+func callFunction(current node, value any) (any, error) {
+	name := current.value.(string)
+	definition, ok := functions[name]
+	if !ok {
+		return nil, fmt.Errorf("query: unknown function %s()", name)
+	}
+	count := len(current.children)
+	if count < definition.arity || (!definition.variadic && count != definition.arity) {
+		return nil, fmt.Errorf("query: %s() expects %d argument(s), got %d", name, definition.arity, count)
+	}
+
+	arguments := make([]any, count)
+	for index, child := range current.children {
+		if child.kind == nodeExpref {
+			continue
+		}
+		argument, err := evaluate(child, value)
+		if err != nil {
+			return nil, err
+		}
+		arguments[index] = argument
+	}
+	return definition.call(arguments, current.children)
+}
+
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func argumentError(name string, expected string, value any) error {
+	return fmt.Errorf("query: %s() expects %s, got %s", name, expected, typeOf(value))
+}
+
+func lengthFunction(arguments []any, _ []node) (any, error) {
+	switch typed := arguments[0].(type) {
+	case string:
+		return float64(len([]rune(typed))), nil
+	case []any:
+		return float64(len(typed)), nil
+	case map[string]any:
+		return float64(len(typed)), nil
+	default:
+		return nil, argumentError("length", "string, array or object", typed)
+	}
+}
+
+func keysFunction(arguments []any, _ []node) (any, error) {
+	object, ok := arguments[0].(map[string]any)
+	if !ok {
+		return nil, argumentError("keys", "object", arguments[0])
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]any, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, key)
+	}
+	return result, nil
+}
+
+func valuesFunction(arguments []any, _ []node) (any, error) {
+	object, ok := arguments[0].(map[string]any)
+	if !ok {
+		return nil, argumentError("values", "object", arguments[0])
+	}
+	return objectValues(object), nil
+}
+
+func containsFunction(arguments []any, _ []node) (any, error) {
+	switch subject := arguments[0].(type) {
+	case string:
+		search, ok := arguments[1].(string)
+		if !ok {
+			return false, nil
+		}
+		return strings.Contains(subject, search), nil
+	case []any:
+		for _, item := range subject {
+			if compare(tEQ, item, arguments[1]) == true {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return nil, argumentError("contains", "string or array", subject)
+	}
+}
+
+func affixFunction(name string, match func(string, string) bool) func([]any, []node) (any, error) {
+	return func(arguments []any, _ []node) (any, error) {
+		subject, ok := arguments[0].(string)
+		if !ok {
+			return nil, argumentError(name, "string", arguments[0])
+		}
+		affix, ok := arguments[1].(string)
+		if !ok {
+			return nil, argumentError(name, "string", arguments[1])
+		}
+		return match(subject, affix), nil
+	}
+}
+
+func joinFunction(arguments []any, _ []node) (any, error) {
+	separator, ok := arguments[0].(string)
+	if !ok {
+		return nil, argumentError("join", "string separator", arguments[0])
+	}
+	list, ok := arguments[1].([]any)
+	if !ok {
+		return nil, argumentError("join", "array of strings", arguments[1])
+	}
+	parts := make([]string, 0, len(list))
+	for _, item := range list {
+		part, ok := item.(string)
+		if !ok {
+			return nil, argumentError("join", "array of strings", item)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, separator), nil
+}
+
+func sortFunction(arguments []any, _ []node) (any, error) {
+	list, ok := arguments[0].([]any)
+	if !ok {
+		return nil, argumentError("sort", "array", arguments[0])
+	}
+	keys := make([]any, len(list))
+	copy(keys, list)
+	return sortByKeys("sort", list, keys)
+}
+
+func sortByFunction(arguments []any, expressions []node) (any, error) {
+	list, keys, err := expressionKeys("sort_by", arguments, expressions)
+	if err != nil {
+		return nil, err
+	}
+	return sortByKeys("sort_by", list, keys)
+}
+
+func sortByKeys(name string, list []any, keys []any) (any, error) {
+	if err := sameOrderedType(name, keys); err != nil {
+		return nil, err
+	}
+	indexes := make([]int, len(list))
+	for index := range indexes {
+		indexes[index] = index
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return compare(tLT, keys[indexes[a]], keys[indexes[b]]) == true
+	})
+	sorted := make([]any, 0, len(list))
+	for _, index := range indexes {
+		sorted = append(sorted, list[index])
+	}
+	return sorted, nil
+}
+
+func reverseFunction(arguments []any, _ []node) (any, error) {
+	switch typed := arguments[0].(type) {
+	case string:
+		runes := []rune(typed)
+		for left, right := 0, len(runes)-1; left < right; left, right = left+1, right-1 {
+			runes[left], runes[right] = runes[right], runes[left]
+		}
+		return string(runes), nil
+	case []any:
+		reversed := make([]any, 0, len(typed))
+		for index := len(typed) - 1; index >= 0; index-- {
+			reversed = append(reversed, typed[index])
+		}
+		return reversed, nil
+	default:
+		return nil, argumentError("reverse", "string or array", typed)
+	}
+}
+
+func numbers(name string, value any) ([]float64, error) {
+	list, ok := value.([]any)
+	if !ok {
+		return nil, argumentError(name, "array of numbers", value)
+	}
+	result := make([]float64, 0, len(list))
+	for _, item := range list {
+		number, ok := item.(float64)
+		if !ok {
+			return nil, argumentError(name, "array of numbers", item)
+		}
+		result = append(result, number)
+	}
+	return result, nil
+}
+
+func sumFunction(arguments []any, _ []node) (any, error) {
+	values, err := numbers("sum", arguments[0])
+	if err != nil {
+		return nil, err
+	}
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total, nil
+}
+
+func avgFunction(arguments []any, _ []node) (any, error) {
+	values, err := numbers("avg", arguments[0])
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values)), nil
+}
+
+func extremeFunction(name string, direction int) func([]any, []node) (any, error) {
+	return func(arguments []any, _ []node) (any, error) {
+		list, ok := arguments[0].([]any)
+		if !ok {
+			return nil, argumentError(name, "array", arguments[0])
+		}
+		return pickExtreme(name, list, list, direction)
+	}
+}
+
+func extremeByFunction(name string, direction int) func([]any, []node) (any, error) {
+	return func(arguments []any, expressions []node) (any, error) {
+		list, keys, err := expressionKeys(name, arguments, expressions)
+		if err != nil {
+			return nil, err
+		}
+		return pickExtreme(name, list, keys, direction)
+	}
+}
+
+func pickExtreme(name string, list []any, keys []any, direction int) (any, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	if err := sameOrderedType(name, keys); err != nil {
+		return nil, err
+	}
+	best := 0
+	for index := 1; index < len(keys); index++ {
+		operator := tGT
+		if direction < 0 {
+			operator = tLT
+		}
+		if compare(operator, keys[index], keys[best]) == true {
+			best = index
+		}
+	}
+	return list[best], nil
+}
+
+// expressionKeys evaluates the &expression argument of the *_by functions
+// against every element of the array argument.
+func expressionKeys(name string, arguments []any, expressions []node) ([]any, []any, error) {
+	list, ok := arguments[0].([]any)
+	if !ok {
+		return nil, nil, argumentError(name, "array", arguments[0])
+	}
+	if expressions[1].kind != nodeExpref {
+		return nil, nil, fmt.Errorf("query: %s() expects an &expression as its second argument", name)
+	}
+	keys := make([]any, 0, len(list))
+	for _, item := range list {
+		key, err := evaluate(expressions[1].children[0], item)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+	}
+	return list, keys, nil
+}
+
+func sameOrderedType(name string, keys []any) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	kind := typeOf(keys[0])
+	for _, key := range keys {
+		if current := typeOf(key); (current != "number" && current != "string") || current != kind {
+			return argumentError(name, "values that are all numbers or all strings", key)
+		}
+	}
+	return nil
+}
+
+func toStringFunction(arguments []any, _ []node) (any, error) {
+	if value, ok := arguments[0].(string); ok {
+		return value, nil
+	}
+	encoded, err := json.Marshal(arguments[0])
+	if err != nil {
+		return nil, fmt.Errorf("query: to_string(): %w", err)
+	}
+	return string(encoded), nil
+}
+
+func toNumberFunction(arguments []any, _ []node) (any, error) {
+	switch typed := arguments[0].(type) {
+	case float64:
+		return typed, nil
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(typed), 64)
+		if err != nil {
+			return nil, nil
+		}
+		return number, nil
+	default:
+		return nil, nil
+	}
+}
+
+func typeFunction(arguments []any, _ []node) (any, error) {
+	return typeOf(arguments[0]), nil
+}
+
+func notNullFunction(arguments []any, _ []node) (any, error) {
+	for _, argument := range arguments {
+		if argument != nil {
+			return argument, nil
+		}
+	}
+	return nil, nil
+}
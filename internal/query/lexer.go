@@ -0,0 +1,219 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	tEOF tokenType = iota
+	tIdentifier
+	tQuotedIdentifier
+	tRawString
+	tLiteral
+	tNumber
+	tDot
+	tStar
+	tFlatten
+	tFilter
+	tLbracket
+	tRbracket
+	tLbrace
+	tRbrace
+	tLparen
+	tRparen
+	tComma
+	tColon
+	tCurrent
+	tExpref
+	tPipe
+	tOr
+	tAnd
+	tNot
+	tEQ
+	tNE
+	tLT
+	tLTE
+	tGT
+	tGTE
+)
+
+// bindingPowers drive the Pratt parser; tokens missing here bind with 0.
+var bindingPowers = map[tokenType]int{
+	tPipe:     1,
+	tOr:       2,
+	tAnd:      3,
+	tEQ:       5,
+	tNE:       5,
+	tLT:       5,
+	tLTE:      5,
+	tGT:       5,
+	tGTE:      5,
+	tFlatten:  9,
+	tStar:     20,
+	tFilter:   21,
+	tDot:      40,
+	tNot:      45,
+	tLbrace:   50,
+	tLbracket: 55,
+	tLparen:   60,
+}
+
+type token struct {
+	kind     tokenType
+	value    string
+	literal  any
+	position int
+}
+
+var simpleTokens = map[byte]tokenType{
+	'.': tDot,
+	'*': tStar,
+	']': tRbracket,
+	'{': tLbrace,
+	'}': tRbrace,
+	'(': tLparen,
+	')': tRparen,
+	',': tComma,
+	':': tColon,
+	'@': tCurrent,
+}
+
+func tokenize(expression string) ([]token, error) {
+	tokens := make([]token, 0, len(expression)/2)
+	for position := 0; position < len(expression); {
+		char := expression[position]
+		start := position
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			position++
+			continue
+		case isIdentifierStart(char):
+			for position < len(expression) && isIdentifierPart(expression[position]) {
+				position++
+			}
+			tokens = append(tokens, token{kind: tIdentifier, value: expression[start:position], position: start})
+			continue
+		case char == '-' || (char >= '0' && char <= '9'):
+			position++
+			for position < len(expression) && expression[position] >= '0' && expression[position] <= '9' {
+				position++
+			}
+			value := expression[start:position]
+			if value == "-" {
+				return nil, syntaxError(start, "expected digits after '-'")
+			}
+			tokens = append(tokens, token{kind: tNumber, value: value, position: start})
+			continue
+		}
+
+		if kind, ok := simpleTokens[char]; ok {
+			tokens = append(tokens, token{kind: kind, value: string(char), position: start})
+			position++
+			continue
+		}
+
+		next := byte(0)
+		if position+1 < len(expression) {
+			next = expression[position+1]
+		}
+		switch char {
+		case '[':
+			switch next {
+			case ']':
+				tokens = append(tokens, token{kind: tFlatten, value: "[]", position: start})
+				position += 2
+			case '?':
+				tokens = append(tokens, token{kind: tFilter, value: "[?", position: start})
+				position += 2
+			default:
+				tokens = append(tokens, token{kind: tLbracket, value: "[", position: start})
+				position++
+			}
+		case '|':
+			tokens, position = appendPair(tokens, next == '|', tOr, tPipe, start)
+		case '&':
+			tokens, position = appendPair(tokens, next == '&', tAnd, tExpref, start)
+		case '!':
+			tokens, position = appendPair(tokens, next == '=', tNE, tNot, start)
+		case '<':
+			tokens, position = appendPair(tokens, next == '=', tLTE, tLT, start)
+		case '>':
+			tokens, position = appendPair(tokens, next == '=', tGTE, tGT, start)
+		case '=':
+			if next != '=' {
+				return nil, syntaxError(start, "expected '==', found '='")
+			}
+			tokens = append(tokens, token{kind: tEQ, value: "==", position: start})
+			position += 2
+		case '"', '\'', '`':
+			end, err := findClosingQuote(expression, position)
+			if err != nil {
+				return nil, err
+			}
+			raw := expression[position+1 : end]
+			parsed, err := quotedToken(char, raw, start)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, parsed)
+			position = end + 1
+		default:
+			return nil, syntaxError(start, fmt.Sprintf("unexpected character %q", char))
+		}
+	}
+	return append(tokens, token{kind: tEOF, position: len(expression)}), nil
+}
+
+func appendPair(tokens []token, double bool, doubleKind tokenType, singleKind tokenType, position int) ([]token, int) {
+	if double {
+		return append(tokens, token{kind: doubleKind, position: position}), position + 2
+	}
+	return append(tokens, token{kind: singleKind, position: position}), position + 1
+}
+
+func findClosingQuote(expression string, start int) (int, error) {
+	quote := expression[start]
+	for position := start + 1; position < len(expression); position++ {
+		switch expression[position] {
+		case '\\':
+			position++
+		case quote:
+			return position, nil
+		}
+	}
+	return 0, syntaxError(start, fmt.Sprintf("unterminated %c quote", quote))
+}
+
+func quotedToken(quote byte, raw string, position int) (token, error) {
+	switch quote {
+	case '"':
+		value, err := strconv.Unquote(`"` + raw + `"`)
+		if err != nil {
+			return token{}, syntaxError(position, "invalid quoted identifier")
+		}
+		return token{kind: tQuotedIdentifier, value: value, position: position}, nil
+	case '\'':
+		value := strings.ReplaceAll(raw, `\'`, `'`)
+		return token{kind: tRawString, value: value, literal: value, position: position}, nil
+	default:
+		raw = strings.TrimSpace(strings.ReplaceAll(raw, "\\`", "`"))
+		var value any
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return token{}, syntaxError(position, fmt.Sprintf("invalid JSON literal `%s`", raw))
+		}
+		return token{kind: tLiteral, value: raw, literal: value, position: position}, nil
+	}
+}
+
+func isIdentifierStart(char byte) bool {
+	return char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+func isIdentifierPart(char byte) bool {
+	return isIdentifierStart(char) || (char >= '0' && char <= '9')
+}
@@ -0,0 +1,433 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type nodeType int
+
+const (
+	nodeIdentity nodeType = iota
+	nodeCurrent
+	nodeField
+	nodeLiteral
+	nodeSubexpression
+	nodeIndexExpression
+	nodeIndex
+	nodeSlice
+	nodeProjection
+	nodeValueProjection
+	nodeFilterProjection
+	nodeFlatten
+	nodePipe
+	nodeOr
+	nodeAnd
+	nodeNot
+	nodeComparator
+	nodeMultiSelectList
+	nodeMultiSelectHash
+	nodeFunction
+	nodeExpref
+)
+
+type node struct {
+	kind     nodeType
+	value    any
+	children []node
+}
+
+type parser struct {
+	tokens []token
+	index  int
+}
+
+func parse(expression string) (node, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return node{}, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseExpression(0)
+	if err != nil {
+		return node{}, err
+	}
+	if current := p.current(); current.kind != tEOF {
+		return node{}, syntaxError(current.position, fmt.Sprintf("unexpected token %s", describe(current)))
+	}
+	return root, nil
+}
+
+func (p *parser) current() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) lookahead(offset int) token {
+	if p.index+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.index+offset]
+}
+
+func (p *parser) advance() token {
+	current := p.tokens[p.index]
+	if current.kind != tEOF {
+		p.index++
+	}
+	return current
+}
+
+func (p *parser) match(kind tokenType, expected string) error {
+	current := p.current()
+	if current.kind != kind {
+		return syntaxError(current.position, fmt.Sprintf("expected %s, found %s", expected, describe(current)))
+	}
+	p.advance()
+	return nil
+}
+
+func (p *parser) parseExpression(bindingPower int) (node, error) {
+	left, err := p.nud(p.advance())
+	if err != nil {
+		return node{}, err
+	}
+	for bindingPower < bindingPowers[p.current().kind] {
+		left, err = p.led(p.advance(), left)
+		if err != nil {
+			return node{}, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) nud(current token) (node, error) {
+	switch current.kind {
+	case tIdentifier, tQuotedIdentifier:
+		if current.kind == tQuotedIdentifier && p.current().kind == tLparen {
+			return node{}, syntaxError(current.position, "quoted identifiers cannot be function names")
+		}
+		return node{kind: nodeField, value: current.value}, nil
+	case tRawString, tLiteral:
+		return node{kind: nodeLiteral, value: current.literal}, nil
+	case tNumber:
+		// Bare numbers are accepted as literals so filters like [?age > `30`]
+		// can also be written [?age > 30].
+		number, err := strconv.ParseFloat(current.value, 64)
+		if err != nil {
+			return node{}, syntaxError(current.position, "invalid number")
+		}
+		return node{kind: nodeLiteral, value: number}, nil
+	case tCurrent:
+		return node{kind: nodeCurrent}, nil
+	case tStar:
+		right := node{kind: nodeIdentity}
+		if p.current().kind != tRbracket {
+			var err error
+			right, err = p.parseProjectionRHS(bindingPowers[tStar])
+			if err != nil {
+				return node{}, err
+			}
+		}
+		return node{kind: nodeValueProjection, children: []node{{kind: nodeIdentity}, right}}, nil
+	case tFilter:
+		return p.parseFilter(node{kind: nodeIdentity})
+	case tLbrace:
+		return p.parseMultiSelectHash()
+	case tFlatten:
+		left := node{kind: nodeFlatten, children: []node{{kind: nodeIdentity}}}
+		right, err := p.parseProjectionRHS(bindingPowers[tFlatten])
+		if err != nil {
+			return node{}, err
+		}
+		return node{kind: nodeProjection, children: []node{left, right}}, nil
+	case tLbracket:
+		switch p.current().kind {
+		case tNumber, tColon:
+			right, err := p.parseIndexExpression()
+			if err != nil {
+				return node{}, err
+			}
+			return p.projectIfSlice(node{kind: nodeIdentity}, right)
+		case tStar:
+			if p.lookahead(1).kind == tRbracket {
+				p.advance()
+				p.advance()
+				right, err := p.parseProjectionRHS(bindingPowers[tStar])
+				if err != nil {
+					return node{}, err
+				}
+				return node{kind: nodeProjection, children: []node{{kind: nodeIdentity}, right}}, nil
+			}
+		}
+		return p.parseMultiSelectList()
+	case tNot:
+		expression, err := p.parseExpression(bindingPowers[tNot])
+		if err != nil {
+			return node{}, err
+		}
+		return node{kind: nodeNot, children: []node{expression}}, nil
+	case tLparen:
+		expression, err := p.parseExpression(0)
+		if err != nil {
+			return node{}, err
+		}
+		if err := p.match(tRparen, "')'"); err != nil {
+			return node{}, err
+		}
+		return expression, nil
+	case tExpref:
+		expression, err := p.parseExpression(0)
+		if err != nil {
+			return node{}, err
+		}
+		return node{kind: nodeExpref, children: []node{expression}}, nil
+	default:
+		return node{}, syntaxError(current.position, fmt.Sprintf("unexpected token %s", describe(current)))
+	}
+}
+
+func (p *parser) led(current token, left node) (node, error) {
+	switch current.kind {
+	case tDot:
+		if p.current().kind != tStar {
+			right, err := p.parseDotRHS(bindingPowers[tDot])
+			if err != nil {
+				return node{}, err
+			}
+			return node{kind: nodeSubexpression, children: []node{left, right}}, nil
+		}
+		p.advance()
+		right, err := p.parseProjectionRHS(bindingPowers[tDot])
+		if err != nil {
+			return node{}, err
+		}
+		return node{kind: nodeValueProjection, children: []node{left, right}}, nil
+	case tPipe, tOr, tAnd:
+		right, err := p.parseExpression(bindingPowers[current.kind])
+		if err != nil {
+			return node{}, err
+		}
+		kind := map[tokenType]nodeType{tPipe: nodePipe, tOr: nodeOr, tAnd: nodeAnd}[current.kind]
+		return node{kind: kind, children: []node{left, right}}, nil
+	case tEQ, tNE, tLT, tLTE, tGT, tGTE:
+		right, err := p.parseExpression(bindingPowers[current.kind])
+		if err != nil {
+			return node{}, err
+		}
+		return node{kind: nodeComparator, value: current.kind, children: []node{left, right}}, nil
+	case tLparen:
+		if left.kind != nodeField {
+			return node{}, syntaxError(current.position, "only identifiers can be called as functions")
+		}
+		arguments := make([]node, 0, 2)
+		for p.current().kind != tRparen {
+			argument, err := p.parseExpression(0)
+			if err != nil {
+				return node{}, err
+			}
+			arguments = append(arguments, argument)
+			if p.current().kind == tRparen {
+				break
+			}
+			if err := p.match(tComma, "',' or ')'"); err != nil {
+				return node{}, err
+			}
+		}
+		p.advance()
+		return node{kind: nodeFunction, value: left.value, children: arguments}, nil
+	case tFilter:
+		return p.parseFilter(left)
+	case tFlatten:
+		flattened := node{kind: nodeFlatten, children: []node{left}}
+		right, err := p.parseProjectionRHS(bindingPowers[tFlatten])
+		if err != nil {
+			return node{}, err
+		}
+		return node{kind: nodeProjection, children: []node{flattened, right}}, nil
+	case tLbracket:
+		switch p.current().kind {
+		case tNumber, tColon:
+			right, err := p.parseIndexExpression()
+			if err != nil {
+				return node{}, err
+			}
+			return p.projectIfSlice(left, right)
+		}
+		if err := p.match(tStar, "index, slice or '*'"); err != nil {
+			return node{}, err
+		}
+		if err := p.match(tRbracket, "']'"); err != nil {
+			return node{}, err
+		}
+		right, err := p.parseProjectionRHS(bindingPowers[tStar])
+		if err != nil {
+			return node{}, err
+		}
+		return node{kind: nodeProjection, children: []node{left, right}}, nil
+	default:
+		return node{}, syntaxError(current.position, fmt.Sprintf("unexpected token %s", describe(current)))
+	}
+}
+
+func (p *parser) parseIndexExpression() (node, error) {
+	if p.lookahead(1).kind == tColon || p.current().kind == tColon {
+		return p.parseSlice()
+	}
+	current := p.advance()
+	index, err := strconv.Atoi(current.value)
+	if err != nil {
+		return node{}, syntaxError(current.position, "invalid index")
+	}
+	if err := p.match(tRbracket, "']'"); err != nil {
+		return node{}, err
+	}
+	return node{kind: nodeIndex, value: index}, nil
+}
+
+func (p *parser) parseSlice() (node, error) {
+	parts := [3]*int{}
+	part := 0
+	for p.current().kind != tRbracket && part < 3 {
+		current := p.current()
+		switch current.kind {
+		case tColon:
+			part++
+			p.advance()
+		case tNumber:
+			value, err := strconv.Atoi(current.value)
+			if err != nil {
+				return node{}, syntaxError(current.position, "invalid slice bound")
+			}
+			parts[part] = &value
+			p.advance()
+		default:
+			return node{}, syntaxError(current.position, fmt.Sprintf("expected slice bound, found %s", describe(current)))
+		}
+	}
+	if part > 2 {
+		return node{}, syntaxError(p.current().position, "too many colons in slice")
+	}
+	if err := p.match(tRbracket, "']'"); err != nil {
+		return node{}, err
+	}
+	if parts[2] != nil && *parts[2] == 0 {
+		return node{}, syntaxError(p.current().position, "slice step cannot be 0")
+	}
+	return node{kind: nodeSlice, value: parts}, nil
+}
+
+func (p *parser) projectIfSlice(left node, right node) (node, error) {
+	indexed := node{kind: nodeIndexExpression, children: []node{left, right}}
+	if right.kind != nodeSlice {
+		return indexed, nil
+	}
+	projected, err := p.parseProjectionRHS(bindingPowers[tStar])
+	if err != nil {
+		return node{}, err
+	}
+	return node{kind: nodeProjection, children: []node{indexed, projected}}, nil
+}
+
+func (p *parser) parseFilter(left node) (node, error) {
+	condition, err := p.parseExpression(0)
+	if err != nil {
+		return node{}, err
+	}
+	if err := p.match(tRbracket, "']'"); err != nil {
+		return node{}, err
+	}
+	right := node{kind: nodeIdentity}
+	if p.current().kind != tFlatten {
+		right, err = p.parseProjectionRHS(bindingPowers[tFilter])
+		if err != nil {
+			return node{}, err
+		}
+	}
+	return node{kind: nodeFilterProjection, children: []node{left, right, condition}}, nil
+}
+
+func (p *parser) parseDotRHS(bindingPower int) (node, error) {
+	switch p.current().kind {
+	case tIdentifier, tQuotedIdentifier, tStar:
+		return p.parseExpression(bindingPower)
+	case tLbracket:
+		p.advance()
+		return p.parseMultiSelectList()
+	case tLbrace:
+		p.advance()
+		return p.parseMultiSelectHash()
+	default:
+		current := p.current()
+		return node{}, syntaxError(current.position, fmt.Sprintf("expected identifier, '[' or '{' after '.', found %s", describe(current)))
+	}
+}
+
+func (p *parser) parseProjectionRHS(bindingPower int) (node, error) {
+	current := p.current()
+	switch {
+	case bindingPowers[current.kind] < 10:
+		return node{kind: nodeIdentity}, nil
+	case current.kind == tLbracket, current.kind == tFilter:
+		return p.parseExpression(bindingPower)
+	case current.kind == tDot:
+		p.advance()
+		return p.parseDotRHS(bindingPower)
+	default:
+		return node{}, syntaxError(current.position, fmt.Sprintf("unexpected token %s after projection", describe(current)))
+	}
+}
+
+func (p *parser) parseMultiSelectList() (node, error) {
+	items := make([]node, 0, 2)
+	for {
+		item, err := p.parseExpression(0)
+		if err != nil {
+			return node{}, err
+		}
+		items = append(items, item)
+		if p.current().kind == tRbracket {
+			p.advance()
+			return node{kind: nodeMultiSelectList, children: items}, nil
+		}
+		if err := p.match(tComma, "',' or ']'"); err != nil {
+			return node{}, err
+		}
+	}
+}
+
+func (p *parser) parseMultiSelectHash() (node, error) {
+	keys := make([]string, 0, 2)
+	values := make([]node, 0, 2)
+	for {
+		key := p.advance()
+		if key.kind != tIdentifier && key.kind != tQuotedIdentifier {
+			return node{}, syntaxError(key.position, fmt.Sprintf("expected key name, found %s", describe(key)))
+		}
+		if err := p.match(tColon, "':'"); err != nil {
+			return node{}, err
+		}
+		value, err := p.parseExpression(0)
+		if err != nil {
+			return node{}, err
+		}
+		keys = append(keys, key.value)
+		values = append(values, value)
+		if p.current().kind == tRbrace {
+			p.advance()
+			return node{kind: nodeMultiSelectHash, value: keys, children: values}, nil
+		}
+		if err := p.match(tComma, "',' or '}'"); err != nil {
+			return node{}, err
+		}
+	}
+}
+
+func describe(current token) string {
+	if current.kind == tEOF {
+		return "end of expression"
+	}
+	if current.value == "" {
+		return "operator"
+	}
+	return strconv.Quote(current.value)
+}
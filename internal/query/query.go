@@ -0,0 +1,366 @@
+// Package query implements the JMESPath expression language used by --query to
+// slice command output before it is rendered.
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidExpression = errors.New("invalid query expression")
+
+// Expression is a compiled query that can be applied to many documents.
+type Expression struct {
+	source string
+	root   node
+}
+
+func Compile(expression string) (*Expression, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, fmt.Errorf("%w: expression is empty", ErrInvalidExpression)
+	}
+	root, err := parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	return &Expression{source: expression, root: root}, nil
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Search evaluates the expression against data. Data is normalized through its
+// JSON encoding first, so structs are queried by their json field names.
+func (e *Expression) Search(data any) (any, error) {
+	normalized, err := normalize(data)
+	if err != nil {
+		return nil, err
+	}
+	return evaluate(e.root, normalized)
+}
+
+func normalize(data any) (any, error) {
+	if data == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("encode query input: %w", err)
+	}
+	var normalized any
+	if err := json.Unmarshal(encoded, &normalized); err != nil {
+		return nil, fmt.Errorf("decode query input: %w", err)
+	}
+	return normalized, nil
+}
+
+func syntaxError(position int, message string) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidExpression, message, position)
+}
+
+func evaluate(current node, value any) (any, error) {
+	switch current.kind {
+	case nodeIdentity, nodeCurrent:
+		return value, nil
+	case nodeLiteral:
+		return current.value, nil
+	case nodeField:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		return object[current.value.(string)], nil
+	case nodeSubexpression, nodePipe:
+		left, err := evaluate(current.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		return evaluate(current.children[1], left)
+	case nodeIndexExpression:
+		left, err := evaluate(current.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		return evaluate(current.children[1], left)
+	case nodeIndex:
+		list, ok := value.([]any)
+		if !ok {
+			return nil, nil
+		}
+		index := current.value.(int)
+		if index < 0 {
+			index += len(list)
+		}
+		if index < 0 || index >= len(list) {
+			return nil, nil
+		}
+		return list[index], nil
+	case nodeSlice:
+		list, ok := value.([]any)
+		if !ok {
+			return nil, nil
+		}
+		return slice(list, current.value.([3]*int)), nil
+	case nodeProjection:
+		left, err := evaluate(current.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		list, ok := left.([]any)
+		if !ok {
+			return nil, nil
+		}
+		return project(list, current.children[1])
+	case nodeValueProjection:
+		left, err := evaluate(current.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		object, ok := left.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		return project(objectValues(object), current.children[1])
+	case nodeFilterProjection:
+		left, err := evaluate(current.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		list, ok := left.([]any)
+		if !ok {
+			return nil, nil
+		}
+		matched := make([]any, 0, len(list))
+		for _, item := range list {
+			condition, err := evaluate(current.children[2], item)
+			if err != nil {
+				return nil, err
+			}
+			if truthy(condition) {
+				matched = append(matched, item)
+			}
+		}
+		return project(matched, current.children[1])
+	case nodeFlatten:
+		left, err := evaluate(current.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		list, ok := left.([]any)
+		if !ok {
+			return nil, nil
+		}
+		flattened := make([]any, 0, len(list))
+		for _, item := range list {
+			if nested, ok := item.([]any); ok {
+				flattened = append(flattened, nested...)
+				continue
+			}
+			flattened = append(flattened, item)
+		}
+		return flattened, nil
+	case nodeOr, nodeAnd:
+		left, err := evaluate(current.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(left) == (current.kind == nodeOr) {
+			return left, nil
+		}
+		return evaluate(current.children[1], value)
+	case nodeNot:
+		operand, err := evaluate(current.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		return !truthy(operand), nil
+	case nodeComparator:
+		left, err := evaluate(current.children[0], value)
+		if err != nil {
+			return nil, err
+		}
+		right, err := evaluate(current.children[1], value)
+		if err != nil {
+			return nil, err
+		}
+		return compare(current.value.(tokenType), left, right), nil
+	case nodeMultiSelectList:
+		if value == nil {
+			return nil, nil
+		}
+		items := make([]any, 0, len(current.children))
+		for _, child := range current.children {
+			item, err := evaluate(child, value)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case nodeMultiSelectHash:
+		if value == nil {
+			return nil, nil
+		}
+		keys := current.value.([]string)
+		object := make(map[string]any, len(keys))
+		for index, key := range keys {
+			item, err := evaluate(current.children[index], value)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = item
+		}
+		return object, nil
+	case nodeFunction:
+		return callFunction(current, value)
+	case nodeExpref:
+		return nil, fmt.Errorf("query: expression references (&) are only valid as function arguments")
+	default:
+		return nil, fmt.Errorf("query: unsupported expression node %d", current.kind)
+	}
+}
+
+func project(list []any, right node) (any, error) {
+	projected := make([]any, 0, len(list))
+	for _, item := range list {
+		result, err := evaluate(right, item)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			projected = append(projected, result)
+		}
+	}
+	return projected, nil
+}
+
+func objectValues(object map[string]any) []any {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([]any, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, object[key])
+	}
+	return values
+}
+
+func slice(list []any, bounds [3]*int) []any {
+	step := 1
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+	length := len(list)
+	clamp := func(bound *int, fallback int) int {
+		if bound == nil {
+			return fallback
+		}
+		index := *bound
+		if index < 0 {
+			index += length
+			if index < 0 {
+				if step < 0 {
+					return -1
+				}
+				return 0
+			}
+		}
+		if index >= length {
+			if step < 0 {
+				return length - 1
+			}
+			return length
+		}
+		return index
+	}
+
+	sliced := make([]any, 0, length)
+	if step > 0 {
+		for index := clamp(bounds[0], 0); index < clamp(bounds[1], length); index += step {
+			sliced = append(sliced, list[index])
+		}
+		return sliced
+	}
+	for index := clamp(bounds[0], length-1); index > clamp(bounds[1], -1); index += step {
+		sliced = append(sliced, list[index])
+	}
+	return sliced
+}
+
+// truthy follows JMESPath: null, false and empty strings, lists and objects are
+// false; everything else, including 0, is true.
+func truthy(value any) bool {
+	switch typed := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return typed
+	case string:
+		return typed != ""
+	case []any:
+		return len(typed) > 0
+	case map[string]any:
+		return len(typed) > 0
+	default:
+		return true
+	}
+}
+
+// compare supports ordering numbers and, as an extension useful for ISO
+// timestamps, strings. Ordering mismatched types yields null.
+func compare(operator tokenType, left any, right any) any {
+	switch operator {
+	case tEQ:
+		return reflect.DeepEqual(left, right)
+	case tNE:
+		return !reflect.DeepEqual(left, right)
+	}
+
+	var order int
+	switch leftValue := left.(type) {
+	case float64:
+		rightValue, ok := right.(float64)
+		if !ok {
+			return nil
+		}
+		order = compareOrdered(leftValue, rightValue)
+	case string:
+		rightValue, ok := right.(string)
+		if !ok {
+			return nil
+		}
+		order = strings.Compare(leftValue, rightValue)
+	default:
+		return nil
+	}
+
+	switch operator {
+	case tLT:
+		return order < 0
+	case tLTE:
+		return order <= 0
+	case tGT:
+		return order > 0
+	default:
+		return order >= 0
+	}
+}
+
+func compareOrdered(left float64, right float64) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,104 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearchEvaluatesExpressions(t *testing.T) {
+	t.Parallel()
+
+	type adset struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Status      string `json:"status"`
+		DailyBudget int    `json:"daily_budget"`
+	}
+	data := map[string]any{
+		"adsets": []adset{
+			{ID: "1", Name: "Prospecting", Status: "ACTIVE", DailyBudget: 500},
+			{ID: "2", Name: "Retargeting", Status: "PAUSED", DailyBudget: 1500},
+			{ID: "3", Name: "Lookalike", Status: "ACTIVE", DailyBudget: 2500},
+		},
+		"paging": map[string]any{"cursors": map[string]any{"after": "abc"}},
+		"tags":   []any{[]any{"a", "b"}, []any{"c"}},
+	}
+
+	cases := map[string]any{
+		`adsets[?status=='ACTIVE'].id`:                          []any{"1", "3"},
+		"adsets[?status=='ACTIVE' && daily_budget > `1000`].id": []any{"3"},
+		"adsets[?daily_budget >= 1500].name":                    []any{"Retargeting", "Lookalike"},
+		"adsets[?!(status=='ACTIVE')] | [0].name":               "Retargeting",
+		"adsets[0].name":  "Prospecting",
+		"adsets[-1].id":   "3",
+		"adsets[1:].id":   []any{"2", "3"},
+		"adsets[::-1].id": []any{"3", "2", "1"},
+		"adsets[*].{id: id, budget: daily_budget} | [1]": map[string]any{"id": "2", "budget": float64(1500)},
+		"adsets[].[id, status] | [2]":                    []any{"3", "ACTIVE"},
+		"paging.cursors.after":                           "abc",
+		"paging.*.after":                                 []any{"abc"},
+		"tags[]":                                         []any{"a", "b", "c"},
+		"length(adsets)":                                 float64(3),
+		"sum(adsets[].daily_budget)":                     float64(4500),
+		"max_by(adsets, &daily_budget).id":               "3",
+		"sort_by(adsets, &name)[].id":                    []any{"3", "1", "2"},
+		`join(',', adsets[?starts_with(name, 'P')].id)`:  "1",
+		"missing.field":                                  nil,
+		`"adsets"[0].id`:                                 "1",
+	}
+	for expression, want := range cases {
+		compiled, err := Compile(expression)
+		if err != nil {
+			t.Fatalf("compile %q: %v", expression, err)
+		}
+		got, err := compiled.Search(data)
+		if err != nil {
+			t.Fatalf("search %q: %v", expression, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("search %q: got %#v want %#v", expression, got, want)
+		}
+	}
+}
+
+func TestCompileRejectsInvalidExpressions(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"":                     "expression is empty",
+		"adsets[?status='x']":  "expected '==', found '='",
+		"adsets[":              "end of expression",
+		"adsets.":              "expected identifier",
+		"a b":                  `unexpected token "b"`,
+		"`{not json}`":         "invalid JSON literal",
+		"adsets[?name=='open]": "unterminated ' quote",
+	}
+	for expression, want := range cases {
+		_, err := Compile(expression)
+		if err == nil || !errors.Is(err, ErrInvalidExpression) || !strings.Contains(err.Error(), want) {
+			t.Fatalf("compile %q: expected %q, got %v", expression, want, err)
+		}
+	}
+}
+
+func TestSearchReportsFunctionErrors(t *testing.T) {
+	t.Parallel()
+
+	compiled, err := Compile("length(`1`)")
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if _, err := compiled.Search(nil); err == nil || !strings.Contains(err.Error(), "length() expects string, array or object, got number") {
+		t.Fatalf("expected length type error, got %v", err)
+	}
+
+	compiled, err = Compile("nope(@)")
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if _, err := compiled.Search(nil); err == nil || !strings.Contains(err.Error(), "unknown function nope()") {
+		t.Fatalf("expected unknown function error, got %v", err)
+	}
+}
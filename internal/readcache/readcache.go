@@ -0,0 +1,217 @@
+// Package readcache keeps Graph GET responses on disk so an offline invocation
+// can serve reads without the network. Every successful read is written
+// through, except reads of personal data, which graph never hands to the
+// cache. Entries older than MaxAge are dropped, and the oldest go first once
+// there are more than MaxEntries.
+package readcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	SchemaVersion = 1
+	// DirEnv moves the cache away from ~/.meta/cache/graph, or turns it off
+	// when set to Off.
+	DirEnv = "META_READ_CACHE_DIR"
+	Off    = "off"
+
+	DefaultMaxAge     = 30 * 24 * time.Hour
+	DefaultMaxEntries = 2000
+)
+
+// Cache is a graph.ReadCache with one JSON file per read, keyed by the
+// invocation's profile so profiles never see each other's responses. A zero
+// MaxAge or MaxEntries leaves that bound off.
+type Cache struct {
+	Dir        string
+	MaxAge     time.Duration
+	MaxEntries int
+	Now        func() time.Time
+}
+
+type entry struct {
+	SchemaVersion int            `json:"schema_version"`
+	Profile       string         `json:"profile,omitempty"`
+	Key           graph.ReadKey  `json:"key"`
+	StatusCode    int            `json:"status_code"`
+	CachedAt      string         `json:"cached_at"`
+	Body          map[string]any `json:"body"`
+}
+
+func New(dir string) *Cache {
+	return &Cache{Dir: dir, MaxAge: DefaultMaxAge, MaxEntries: DefaultMaxEntries}
+}
+
+// DefaultDir returns $META_READ_CACHE_DIR, or ~/.meta/cache/graph. It returns
+// "" when the variable is set to Off.
+func DefaultDir() (string, error) {
+	if dir := strings.TrimSpace(os.Getenv(DirEnv)); dir != "" {
+		if strings.EqualFold(dir, Off) {
+			return "", nil
+		}
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "cache", "graph"), nil
+}
+
+// Load returns the cached response for key, or nil when there is none. An
+// entry written by another schema version counts as a miss.
+func (c *Cache) Load(ctx context.Context, key graph.ReadKey) (*graph.CachedRead, error) {
+	profile := audit.InvocationFromContext(ctx).Profile
+	path, err := c.path(profile, key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cached response %s: %w", path, err)
+	}
+	var cached entry
+	if err := json.Unmarshal(raw, &cached); err != nil {
+		return nil, fmt.Errorf("decode cached response %s: %w", path, err)
+	}
+	if cached.SchemaVersion != SchemaVersion || cached.Profile != profile {
+		return nil, nil
+	}
+	read := &graph.CachedRead{StatusCode: cached.StatusCode, Body: cached.Body}
+	if err := read.CachedAt.UnmarshalText([]byte(cached.CachedAt)); err != nil {
+		return nil, fmt.Errorf("decode cached response %s: invalid cached_at: %w", path, err)
+	}
+	if c.expired(read.CachedAt) {
+		_ = os.Remove(path)
+		return nil, nil
+	}
+	return read, nil
+}
+
+// Store replaces the cached response for key.
+func (c *Cache) Store(ctx context.Context, key graph.ReadKey, read graph.CachedRead) error {
+	profile := audit.InvocationFromContext(ctx).Profile
+	path, err := c.path(profile, key)
+	if err != nil {
+		return err
+	}
+	cachedAt, err := read.CachedAt.UTC().MarshalText()
+	if err != nil {
+		return fmt.Errorf("encode cached_at: %w", err)
+	}
+	payload, err := json.Marshal(entry{
+		SchemaVersion: SchemaVersion,
+		Profile:       profile,
+		Key:           key,
+		StatusCode:    read.StatusCode,
+		CachedAt:      string(cachedAt),
+		Body:          read.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("encode cached response: %w", err)
+	}
+	if err := os.MkdirAll(c.Dir, 0o700); err != nil {
+		return fmt.Errorf("create read cache directory %s: %w", c.Dir, err)
+	}
+	tmpFile, err := os.CreateTemp(c.Dir, ".read-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp cached response: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp cached response: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp cached response: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("replace cached response: %w", err)
+	}
+	return c.evict()
+}
+
+// evict removes expired entries, then the least recently written ones beyond
+// MaxEntries. Entries are dated by file modification time, which Store sets.
+func (c *Cache) evict() error {
+	if c.MaxAge <= 0 && c.MaxEntries <= 0 {
+		return nil
+	}
+	dirEntries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		return fmt.Errorf("list read cache %s: %w", c.Dir, err)
+	}
+	type cachedFile struct {
+		path    string
+		written time.Time
+	}
+	files := make([]cachedFile, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || strings.HasPrefix(dirEntry.Name(), ".") || filepath.Ext(dirEntry.Name()) != ".json" {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(c.Dir, dirEntry.Name())
+		if c.expired(info.ModTime()) {
+			_ = os.Remove(path)
+			continue
+		}
+		files = append(files, cachedFile{path: path, written: info.ModTime()})
+	}
+	if c.MaxEntries <= 0 || len(files) <= c.MaxEntries {
+		return nil
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].written.Before(files[j].written) })
+	for _, file := range files[:len(files)-c.MaxEntries] {
+		_ = os.Remove(file.path)
+	}
+	return nil
+}
+
+func (c *Cache) expired(cachedAt time.Time) bool {
+	if c.MaxAge <= 0 {
+		return false
+	}
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+	return now().Sub(cachedAt) > c.MaxAge
+}
+
+// path names the entry after a digest of the profile and key; JSON encoding
+// sorts the query, so the same read always maps to the same file.
+func (c *Cache) path(profile string, key graph.ReadKey) (string, error) {
+	if strings.TrimSpace(c.Dir) == "" {
+		return "", errors.New("read cache directory is required")
+	}
+	encoded, err := json.Marshal(struct {
+		Profile string        `json:"profile"`
+		Key     graph.ReadKey `json:"key"`
+	}{Profile: profile, Key: key})
+	if err != nil {
+		return "", fmt.Errorf("encode read cache key: %w", err)
+	}
+	sum := sha256.Sum256(encoded)
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json"), nil
+}
@@ -0,0 +1,107 @@
+package readcache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestCacheRoundTripsReadsPerProfile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "graph")
+	cache := New(dir)
+	cachedAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	cache.Now = func() time.Time { return cachedAt.Add(time.Hour) }
+	prod := audit.WithInvocation(context.Background(), audit.Invocation{Profile: "prod"})
+	dev := audit.WithInvocation(context.Background(), audit.Invocation{Profile: "dev"})
+	key := graph.ReadKey{BaseURL: "https://graph.facebook.com", Version: "v25.0", Path: "act_1/campaigns", Query: map[string]string{"fields": "id,name", "limit": "25"}}
+
+	if read, err := cache.Load(prod, key); err != nil || read != nil {
+		t.Fatalf("expected a miss on an empty cache, got %v, %v", read, err)
+	}
+	if err := cache.Store(prod, key, graph.CachedRead{StatusCode: 200, Body: map[string]any{"data": []any{map[string]any{"id": "1"}}}, CachedAt: cachedAt}); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+
+	read, err := cache.Load(prod, graph.ReadKey{BaseURL: key.BaseURL, Version: key.Version, Path: key.Path, Query: map[string]string{"limit": "25", "fields": "id,name"}})
+	if err != nil || read == nil {
+		t.Fatalf("expected a hit, got %v, %v", read, err)
+	}
+	if read.StatusCode != 200 || !read.CachedAt.Equal(cachedAt) || len(read.Body["data"].([]any)) != 1 {
+		t.Fatalf("unexpected cached read %+v", read)
+	}
+	if read, err := cache.Load(dev, key); err != nil || read != nil {
+		t.Fatalf("another profile saw the read: %v, %v", read, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one cache file, got %v, %v", entries, err)
+	}
+	info, err := entries[0].Info()
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected a private cache file, got %v, %v", info.Mode(), err)
+	}
+}
+
+func TestDefaultDirHonorsEnv(t *testing.T) {
+	t.Setenv(DirEnv, "/tmp/meta-read-cache")
+	if dir, err := DefaultDir(); err != nil || dir != "/tmp/meta-read-cache" {
+		t.Fatalf("unexpected dir %q, %v", dir, err)
+	}
+}
+
+func TestDefaultDirOffDisablesTheCache(t *testing.T) {
+	t.Setenv(DirEnv, "OFF")
+	if dir, err := DefaultDir(); err != nil || dir != "" {
+		t.Fatalf("expected no cache directory, got %q, %v", dir, err)
+	}
+}
+
+func TestCacheDropsReadsOlderThanMaxAge(t *testing.T) {
+	cache := New(t.TempDir())
+	cachedAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	cache.Now = func() time.Time { return cachedAt.Add(DefaultMaxAge + time.Minute) }
+	key := graph.ReadKey{Version: "v25.0", Path: "act_1"}
+	if err := cache.Store(context.Background(), key, graph.CachedRead{StatusCode: 200, Body: map[string]any{"id": "act_1"}, CachedAt: cachedAt}); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+
+	if read, err := cache.Load(context.Background(), key); err != nil || read != nil {
+		t.Fatalf("expected an expired read to miss, got %v, %v", read, err)
+	}
+	if entries, _ := os.ReadDir(cache.Dir); len(entries) != 0 {
+		t.Fatalf("expected the expired entry to be removed, got %v", entries)
+	}
+}
+
+func TestCacheEvictsOldestReadsBeyondMaxEntries(t *testing.T) {
+	cache := New(t.TempDir())
+	cache.MaxEntries = 2
+	now := time.Now()
+	keys := []graph.ReadKey{{Path: "1"}, {Path: "2"}, {Path: "3"}}
+	for index, key := range keys {
+		if err := cache.Store(context.Background(), key, graph.CachedRead{StatusCode: 200, Body: map[string]any{"id": key.Path}, CachedAt: now}); err != nil {
+			t.Fatalf("store %s: %v", key.Path, err)
+		}
+		path, _ := cache.path("", key)
+		written := now.Add(time.Duration(index-len(keys)) * time.Minute)
+		if err := os.Chtimes(path, written, written); err != nil {
+			t.Fatalf("date entry %s: %v", key.Path, err)
+		}
+	}
+
+	for index, key := range keys {
+		read, err := cache.Load(context.Background(), key)
+		if err != nil {
+			t.Fatalf("load %s: %v", key.Path, err)
+		}
+		if evicted := read == nil; evicted != (index == 0) {
+			t.Fatalf("read %s evicted=%v, want only the oldest evicted", key.Path, evicted)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package redact masks credentials and customer data before they reach debug
+// logs, traces or the audit log. Values are masked by key (access_token,
+// client_secret, hashed user_data fields, ...) and by shape (Meta access tokens,
+// bearer tokens and sha256 digests of customer identifiers), so a secret is
+// caught whether it travels as a form field, a URL query or free text.
+package redact
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Mask replaces every redacted value.
+const Mask = "***"
+
+// secretKeys hold credentials.
+var secretKeys = map[string]struct{}{
+	"access_token":      {},
+	"appsecret_proof":   {},
+	"app_secret":        {},
+	"client_secret":     {},
+	"fb_exchange_token": {},
+	"input_token":       {},
+	"refresh_token":     {},
+	"password":          {},
+	"authorization":     {},
+	"verify_token":      {},
+}
+
+// customerKeys hold customer data, usually sha256-hashed: Conversions API
+// user_data fields and Custom Audience upload payloads.
+var customerKeys = map[string]struct{}{
+	"em":          {},
+	"ph":          {},
+	"fn":          {},
+	"ln":          {},
+	"db":          {},
+	"ge":          {},
+	"ct":          {},
+	"st":          {},
+	"zp":          {},
+	"country":     {},
+	"external_id": {},
+	"madid":       {},
+	"email":       {},
+	"phone":       {},
+	"user_data":   {},
+	"payload":     {},
+}
+
+var (
+	// secretParamPattern matches key=value and "key":"value" pairs whose key
+	// names a credential.
+	secretParamPattern = regexp.MustCompile(`(?i)((?:access_token|appsecret_proof|app_secret|client_secret|fb_exchange_token|input_token|refresh_token|password|verify_token)(?:=|"\s*:\s*"))[^&\s"]+`)
+	// metaTokenPattern matches user, page and system user access tokens.
+	metaTokenPattern = regexp.MustCompile(`\bEA[A-Za-z0-9]{2}[A-Za-z0-9]{20,}`)
+	bearerPattern    = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+	// digestPattern matches sha256 hex digests, the form Meta expects hashed
+	// customer identifiers in.
+	digestPattern = regexp.MustCompile(`\b[0-9a-fA-F]{64}\b`)
+)
+
+// IsSensitiveKey reports whether values under key are masked outright.
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if _, ok := secretKeys[key]; ok {
+		return true
+	}
+	if _, ok := customerKeys[key]; ok {
+		return true
+	}
+	return strings.HasSuffix(key, "_token") || strings.HasSuffix(key, "_secret")
+}
+
+// String masks secrets and hashed customer data inside free text, URLs and
+// JSON documents.
+func String(value string) string {
+	if value == "" {
+		return value
+	}
+	value = secretParamPattern.ReplaceAllString(value, "${1}"+Mask)
+	value = bearerPattern.ReplaceAllString(value, "${1}"+Mask)
+	value = metaTokenPattern.ReplaceAllString(value, Mask)
+	return digestPattern.ReplaceAllString(value, Mask)
+}
+
+// Field masks value when key is sensitive and scrubs it as free text
+// otherwise.
+func Field(key string, value string) string {
+	if IsSensitiveKey(key) {
+		if value == "" {
+			return value
+		}
+		return Mask
+	}
+	return String(value)
+}
+
+// Map returns a redacted copy of form-style params.
+func Map(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(values))
+	for key, value := range values {
+		redacted[key] = Field(key, value)
+	}
+	return redacted
+}
+
+// Value returns a redacted copy of a decoded JSON value.
+func Value(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(typed))
+		for key, item := range typed {
+			if IsSensitiveKey(key) && item != nil {
+				redacted[key] = Mask
+				continue
+			}
+			redacted[key] = Value(item)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(typed))
+		for index, item := range typed {
+			redacted[index] = Value(item)
+		}
+		return redacted
+	case map[string]string:
+		return Map(typed)
+	case string:
+		return String(typed)
+	default:
+		return value
+	}
+}
+
+// URL masks sensitive query parameters of raw and scrubs the rest.
+func URL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return String(raw)
+	}
+	query := parsed.Query()
+	for key, values := range query {
+		for index, value := range values {
+			values[index] = Field(key, value)
+		}
+		query[key] = values
+	}
+	parsed.RawQuery = query.Encode()
+	return String(strings.ReplaceAll(parsed.String(), url.QueryEscape(Mask), Mask))
+}
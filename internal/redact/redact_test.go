@@ -0,0 +1,74 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	testToken  = "EAABsbCS1iHgBAKZCZCtestTokenValue1234567890"
+	testDigest = "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"
+)
+
+func TestStringMasksKnownSecretPatterns(t *testing.T) {
+	t.Parallel()
+
+	cases := []string{
+		"GET /v25.0/me?access_token=" + testToken + "&fields=id",
+		"appsecret_proof=9b1c4d2e7f&client_secret=s3cr3t",
+		`{"access_token":"` + testToken + `","fb_exchange_token":"abc"}`,
+		"Authorization: Bearer " + testToken,
+		"token was " + testToken + " when it failed",
+		`{"data":[["` + testDigest + `"]]}`,
+	}
+	for _, input := range cases {
+		redacted := String(input)
+		for _, secret := range []string{testToken, "9b1c4d2e7f", "s3cr3t", testDigest, `"abc"`} {
+			if strings.Contains(redacted, secret) {
+				t.Fatalf("String(%q) = %q leaked %q", input, redacted, secret)
+			}
+		}
+		if !strings.Contains(redacted, Mask) {
+			t.Fatalf("String(%q) = %q has no mask", input, redacted)
+		}
+	}
+
+	if got := String("act_1234567890/campaigns?fields=id,name"); got != "act_1234567890/campaigns?fields=id,name" {
+		t.Fatalf("expected ids and fields to survive, got %q", got)
+	}
+}
+
+func TestValueMasksSensitiveKeys(t *testing.T) {
+	t.Parallel()
+
+	redacted := Value(map[string]any{
+		"event_name": "Purchase",
+		"user_data":  map[string]any{"em": []any{"hash"}},
+		"custom":     map[string]any{"my_api_token": "t0k", "note": "refresh_token=xyz"},
+		"params":     map[string]string{"appsecret_proof": "p", "name": "Launch"},
+	}).(map[string]any)
+
+	if redacted["event_name"] != "Purchase" || redacted["user_data"] != Mask {
+		t.Fatalf("unexpected redaction %#v", redacted)
+	}
+	custom := redacted["custom"].(map[string]any)
+	if custom["my_api_token"] != Mask || custom["note"] != "refresh_token="+Mask {
+		t.Fatalf("unexpected nested redaction %#v", custom)
+	}
+	params := redacted["params"].(map[string]string)
+	if params["appsecret_proof"] != Mask || params["name"] != "Launch" {
+		t.Fatalf("unexpected params redaction %#v", params)
+	}
+}
+
+func TestURLMasksQueryParams(t *testing.T) {
+	t.Parallel()
+
+	redacted := URL("https://graph.facebook.com/v25.0/me?access_token=" + testToken + "&appsecret_proof=abc123&fields=id")
+	if strings.Contains(redacted, testToken) || strings.Contains(redacted, "abc123") {
+		t.Fatalf("URL leaked a secret: %s", redacted)
+	}
+	if !strings.Contains(redacted, "access_token=***") || !strings.Contains(redacted, "fields=id") {
+		t.Fatalf("unexpected redacted URL %s", redacted)
+	}
+}
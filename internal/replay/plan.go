@@ -0,0 +1,159 @@
+// Package replay persists the invocation of a command that failed with a
+// retryable error so `meta retry` can run it again without re-typing it.
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const SchemaVersion = 1
+
+var (
+	ErrPathRequired    = errors.New("replay file path is required")
+	ErrArgsRequired    = errors.New("replay plan args are required")
+	ErrCommandRequired = errors.New("replay plan command is required")
+)
+
+// Plan is the resolved invocation of a failed command. Secret flag values are
+// never stored; their names are listed in OmittedFlags instead.
+type Plan struct {
+	SchemaVersion  int      `json:"schema_version"`
+	Command        string   `json:"command"`
+	Args           []string `json:"args"`
+	Profile        string   `json:"profile,omitempty"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty"`
+	OmittedFlags   []string `json:"omitted_flags,omitempty"`
+	Failure        Failure  `json:"failure"`
+	FailedAt       string   `json:"failed_at"`
+}
+
+type Failure struct {
+	Class   string `json:"class"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+func NewPlan(command string, args []string, failure Failure, now time.Time) Plan {
+	return Plan{
+		SchemaVersion: SchemaVersion,
+		Command:       strings.TrimSpace(command),
+		Args:          args,
+		Failure:       failure,
+		FailedAt:      now.UTC().Format(time.RFC3339),
+	}
+}
+
+func (p Plan) Validate() error {
+	if p.SchemaVersion != SchemaVersion {
+		return fmt.Errorf("unsupported replay plan schema_version %d (expected %d)", p.SchemaVersion, SchemaVersion)
+	}
+	if strings.TrimSpace(p.Command) == "" {
+		return ErrCommandRequired
+	}
+	if len(p.Args) == 0 {
+		return ErrArgsRequired
+	}
+	return nil
+}
+
+// IsSecretFlag reports whether a flag carries a credential that must not be
+// written to the replay file.
+func IsSecretFlag(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return name == "token" ||
+		strings.HasSuffix(name, "-token") ||
+		strings.Contains(name, "secret") ||
+		strings.Contains(name, "password")
+}
+
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "replay", "last-failed.json"), nil
+}
+
+func Load(path string) (Plan, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return Plan{}, ErrPathRequired
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Plan{}, fmt.Errorf("%w: no failed command recorded at %s", os.ErrNotExist, path)
+		}
+		return Plan{}, fmt.Errorf("read replay plan %s: %w", path, err)
+	}
+
+	var plan Plan
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&plan); err != nil {
+		return Plan{}, fmt.Errorf("decode replay plan %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return Plan{}, fmt.Errorf("decode replay plan %s: multiple JSON values", path)
+		}
+		return Plan{}, fmt.Errorf("decode replay plan %s: %w", path, err)
+	}
+	if err := plan.Validate(); err != nil {
+		return Plan{}, fmt.Errorf("invalid replay plan %s: %w", path, err)
+	}
+	return plan, nil
+}
+
+func Save(path string, plan Plan) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return ErrPathRequired
+	}
+	if err := plan.Validate(); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create replay directory for %s: %w", path, err)
+	}
+
+	payload, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode replay plan: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".last-failed-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp replay file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp replay file: %w", err)
+	}
+	if err := tmpFile.Chmod(0o600); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("chmod temp replay file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp replay file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("replace replay file %s: %w", path, err)
+	}
+	return nil
+}
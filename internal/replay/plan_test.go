@@ -0,0 +1,69 @@
+package replay
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSaveAndLoadRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "replay", "last-failed.json")
+	plan := NewPlan("meta campaign list", []string{"campaign", "list", "--account-id=1234"}, Failure{
+		Class:   "rate_limited",
+		Type:    "OAuthException",
+		Message: "User request limit reached",
+	}, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	plan.Profile = "prod"
+	plan.OmittedFlags = []string{"app-secret"}
+
+	if err := Save(path, plan); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 replay file, got %v", info.Mode().Perm())
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, plan) {
+		t.Fatalf("round trip mismatch:\n got %#v\nwant %#v", loaded, plan)
+	}
+}
+
+func TestLoadReportsMissingPlan(t *testing.T) {
+	t.Parallel()
+
+	_, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not-exist error, got %v", err)
+	}
+}
+
+func TestIsSecretFlag(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]bool{
+		"token":           true,
+		"app-secret":      true,
+		"approval-token":  true,
+		"verify-token":    true,
+		"idempotency-key": false,
+		"public-key":      false,
+		"account-id":      false,
+	} {
+		if got := IsSecretFlag(name); got != want {
+			t.Fatalf("IsSecretFlag(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+// Package s3 reads and writes single objects in Amazon S3, or any store that
+// speaks its API, with AWS Signature Version 4. It covers what exports need,
+// a GET and a conditional PUT, without pulling in the AWS SDK.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/transport"
+)
+
+const (
+	DefaultRegion = "us-east-1"
+
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	timeFormat       = "20060102T150405Z"
+)
+
+var (
+	// ErrNotFound is returned by Get for a missing object.
+	ErrNotFound = errors.New("s3 object not found")
+	// ErrConflict is returned by Put when the object changed since it was
+	// read, or appeared when it was expected to be missing.
+	ErrConflict = errors.New("s3 object changed concurrently")
+)
+
+// Location is one object, parsed from an s3://bucket/key URL.
+type Location struct {
+	Bucket string
+	Key    string
+}
+
+func (l Location) String() string {
+	return "s3://" + l.Bucket + "/" + l.Key
+}
+
+// ParseURL parses an s3://bucket/key URL.
+func ParseURL(raw string) (Location, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(raw), "s3://")
+	if !ok {
+		return Location{}, fmt.Errorf("invalid s3 url %q: expected s3://bucket/key", raw)
+	}
+	bucket, key, _ := strings.Cut(rest, "/")
+	if bucket == "" || key == "" || strings.HasSuffix(key, "/") {
+		return Location{}, fmt.Errorf("invalid s3 url %q: expected s3://bucket/key", raw)
+	}
+	return Location{Bucket: bucket, Key: key}, nil
+}
+
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// Client signs requests with Credentials. Endpoint overrides the AWS endpoint
+// for S3-compatible stores and uses path-style URLs; otherwise requests go to
+// https://<bucket>.s3.<region>.amazonaws.com.
+type Client struct {
+	HTTPClient  *http.Client
+	Region      string
+	Endpoint    string
+	Credentials Credentials
+
+	now func() time.Time
+}
+
+// NewClientFromEnv configures a client from the standard AWS variables:
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN, the region
+// from AWS_REGION or AWS_DEFAULT_REGION, and an endpoint from
+// AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL.
+func NewClientFromEnv() (*Client, error) {
+	credentials := Credentials{
+		AccessKeyID:     strings.TrimSpace(os.Getenv("AWS_ACCESS_KEY_ID")),
+		SecretAccessKey: strings.TrimSpace(os.Getenv("AWS_SECRET_ACCESS_KEY")),
+		SessionToken:    strings.TrimSpace(os.Getenv("AWS_SESSION_TOKEN")),
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, errors.New("s3 credentials are required: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	return &Client{
+		HTTPClient:  &http.Client{Timeout: 5 * time.Minute, Transport: transport.RefuseOffline(nil)},
+		Region:      firstEnv("AWS_REGION", "AWS_DEFAULT_REGION"),
+		Endpoint:    firstEnv("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"),
+		Credentials: credentials,
+	}, nil
+}
+
+// Object is the body and entity tag of an object read with Get.
+type Object struct {
+	Body []byte
+	ETag string
+}
+
+// Get reads the object at location. A missing object is ErrNotFound.
+func (c *Client) Get(ctx context.Context, location Location) (*Object, error) {
+	response, err := c.do(ctx, http.MethodGet, location, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, location)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, responseError("read", location, response)
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", location, err)
+	}
+	return &Object{Body: body, ETag: response.Header.Get("ETag")}, nil
+}
+
+// PutOptions makes a Put conditional. IfMatch writes only over the object
+// with that entity tag; IfAbsent writes only when no object exists.
+type PutOptions struct {
+	ContentType string
+	IfMatch     string
+	IfAbsent    bool
+}
+
+// Put writes body to location. A failed condition is ErrConflict.
+func (c *Client) Put(ctx context.Context, location Location, body []byte, options PutOptions) error {
+	header := http.Header{}
+	if options.ContentType != "" {
+		header.Set("Content-Type", options.ContentType)
+	}
+	if options.IfMatch != "" {
+		header.Set("If-Match", options.IfMatch)
+	}
+	if options.IfAbsent {
+		header.Set("If-None-Match", "*")
+	}
+	response, err := c.do(ctx, http.MethodPut, location, header, body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusPreconditionFailed, http.StatusConflict:
+		return fmt.Errorf("%w: %s was written by someone else; rerun to retry", ErrConflict, location)
+	default:
+		return responseError("write", location, response)
+	}
+}
+
+func (c *Client) do(ctx context.Context, method string, location Location, header http.Header, body []byte) (*http.Response, error) {
+	target, err := c.objectURL(location)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	c.sign(request, body)
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", strings.ToLower(method), location, err)
+	}
+	return response, nil
+}
+
+func (c *Client) objectURL(location Location) (*url.URL, error) {
+	key := escapePath(location.Key)
+	if strings.TrimSpace(c.Endpoint) == "" {
+		return url.Parse("https://" + location.Bucket + ".s3." + c.region() + ".amazonaws.com/" + key)
+	}
+	endpoint, err := url.Parse(strings.TrimRight(strings.TrimSpace(c.Endpoint), "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", c.Endpoint)
+	}
+	return url.Parse(endpoint.String() + "/" + escapePath(location.Bucket) + "/" + key)
+}
+
+func (c *Client) region() string {
+	if region := strings.TrimSpace(c.Region); region != "" {
+		return region
+	}
+	return DefaultRegion
+}
+
+// sign adds the Signature Version 4 headers, signing the host and every
+// header already set on request.
+func (c *Client) sign(request *http.Request, body []byte) {
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	stamp := now().UTC().Format(timeFormat)
+	payloadSum := sha256.Sum256(body)
+	request.Header.Set("X-Amz-Date", stamp)
+	request.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadSum[:]))
+	if c.Credentials.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", c.Credentials.SessionToken)
+	}
+
+	canonical, signedHeaders := canonicalRequest(request, hex.EncodeToString(payloadSum[:]))
+	scope := stamp[:8] + "/" + c.region() + "/s3/aws4_request"
+	canonicalSum := sha256.Sum256([]byte(canonical))
+	stringToSign := signingAlgorithm + "\n" + stamp + "\n" + scope + "\n" + hex.EncodeToString(canonicalSum[:])
+
+	key := hmacSHA256([]byte("AWS4"+c.Credentials.SecretAccessKey), stamp[:8])
+	for _, part := range []string{c.region(), "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	request.Header.Set("Authorization", signingAlgorithm+" Credential="+c.Credentials.AccessKeyID+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func canonicalRequest(request *http.Request, payloadHash string) (string, string) {
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := request.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	return strings.Join([]string{
+		request.Method,
+		path,
+		request.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n"), signedHeaders
+}
+
+// escapePath percent-encodes a key the way Signature Version 4 expects:
+// everything but unreserved characters and the / separators.
+func escapePath(key string) string {
+	var builder strings.Builder
+	for _, b := range []byte(key) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9', b == '-', b == '_', b == '.', b == '~', b == '/':
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func responseError(action string, location Location, response *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+	return fmt.Errorf("%s %s: HTTP %d: %s", action, location, response.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
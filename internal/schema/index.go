@@ -0,0 +1,135 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CompiledCacheDirEnv, when set, enables an on-disk cache of decoded packs
+// keyed by the pack file's sha256. A pack that changes on disk has a new sum,
+// so stale entries are never read; they are simply left behind.
+const CompiledCacheDirEnv = "META_SCHEMA_CACHE_DIR"
+
+// Set is a string set used for schema lookups.
+type Set map[string]struct{}
+
+func (s Set) Has(value string) bool {
+	_, ok := s[value]
+	return ok
+}
+
+// Index holds a pack's lists as sets so lookups do not scan them. It is built
+// once per pack and shared; treat it as read-only.
+type Index struct {
+	Entities               map[string]Set
+	EndpointParams         map[string]Set
+	EndpointRequiredParams map[string]Set
+	DeprecatedParams       map[string]Set
+}
+
+var (
+	// packsBySHA caches decoded packs for the life of the process, so every
+	// lint and resolver of one invocation shares a single decode.
+	cacheMu    sync.Mutex
+	packsBySHA = map[string]*Pack{}
+	indexes    = map[*Pack]*Index{}
+)
+
+// IndexFor returns the index of pack, building it on first use.
+func IndexFor(pack *Pack) *Index {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if index, ok := indexes[pack]; ok {
+		return index
+	}
+	index := &Index{
+		Entities:               indexLists(pack.Entities),
+		EndpointParams:         indexLists(pack.EndpointParams),
+		EndpointRequiredParams: indexLists(pack.EndpointRequiredParams),
+		DeprecatedParams:       indexLists(pack.DeprecatedParams),
+	}
+	indexes[pack] = index
+	return index
+}
+
+func indexLists(lists map[string][]string) map[string]Set {
+	sets := make(map[string]Set, len(lists))
+	for name, values := range lists {
+		set := make(Set, len(values))
+		for _, value := range values {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			set[value] = struct{}{}
+		}
+		sets[name] = set
+	}
+	return sets
+}
+
+func cachedPack(sha string) *Pack {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	return packsBySHA[sha]
+}
+
+func storePack(sha string, pack *Pack) *Pack {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if existing, ok := packsBySHA[sha]; ok {
+		return existing
+	}
+	packsBySHA[sha] = pack
+	return pack
+}
+
+func compiledPackPath(dir string, sha string) string {
+	return filepath.Join(dir, sha+".gob")
+}
+
+// readCompiledPack loads a pack from the compiled cache. Any failure is a
+// cache miss.
+func readCompiledPack(dir string, sha string) (*Pack, bool) {
+	data, err := os.ReadFile(compiledPackPath(dir, sha))
+	if err != nil {
+		return nil, false
+	}
+	var pack Pack
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&pack); err != nil {
+		return nil, false
+	}
+	return &pack, true
+}
+
+// writeCompiledPack stores a decoded pack in the compiled cache.
+func writeCompiledPack(dir string, sha string, pack *Pack) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create schema cache directory %s: %w", dir, err)
+	}
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(pack); err != nil {
+		return fmt.Errorf("encode compiled schema pack: %w", err)
+	}
+	tmpFile, err := os.CreateTemp(dir, ".pack-*.gob")
+	if err != nil {
+		return fmt.Errorf("create temp compiled schema pack: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(buffer.Bytes()); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp compiled schema pack: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp compiled schema pack: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), compiledPackPath(dir, sha)); err != nil {
+		return fmt.Errorf("replace compiled schema pack: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestPack(t *testing.T, baseDir string, body string) string {
+	t.Helper()
+	dir := filepath.Join(baseDir, "marketing")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("create pack dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "v25.0.json"), []byte(body), 0o644); err != nil {
+		t.Fatalf("write pack: %v", err)
+	}
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// resetPackCache empties the process-wide pack and index caches for the test
+// and again when it ends, so tests never see packs decoded by earlier runs.
+func resetPackCache(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		cacheMu.Lock()
+		defer cacheMu.Unlock()
+		packsBySHA = map[string]*Pack{}
+		indexes = map[*Pack]*Index{}
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestGetPackDecodesOncePerContentAndIndexesIt(t *testing.T) {
+	resetPackCache(t)
+	baseDir := t.TempDir()
+	writeTestPack(t, baseDir, `{"domain":"marketing","version":"v25.0","endpoint_params":{"campaigns.post":["name"," objective ",""]}}`)
+	provider := &Provider{BaseDir: baseDir}
+
+	first, err := provider.GetPack("marketing", "v25.0")
+	if err != nil {
+		t.Fatalf("get pack: %v", err)
+	}
+	second, err := provider.GetPack("marketing", "v25.0")
+	if err != nil {
+		t.Fatalf("get pack again: %v", err)
+	}
+	if first != second {
+		t.Fatal("expected the decoded pack to be reused within the process")
+	}
+	index := IndexFor(first)
+	if index != IndexFor(second) {
+		t.Fatal("expected one index per pack")
+	}
+	params := index.EndpointParams["campaigns.post"]
+	if !params.Has("name") || !params.Has("objective") || params.Has("") || len(params) != 2 {
+		t.Fatalf("unexpected params set: %v", params)
+	}
+
+	writeTestPack(t, baseDir, `{"domain":"marketing","version":"v25.0","endpoint_params":{"campaigns.post":["name","status"]}}`)
+	changed, err := provider.GetPack("marketing", "v25.0")
+	if err != nil {
+		t.Fatalf("get changed pack: %v", err)
+	}
+	if changed == first || !IndexFor(changed).EndpointParams["campaigns.post"].Has("status") {
+		t.Fatal("expected a rewritten pack to be decoded again")
+	}
+}
+
+func TestGetPackUsesCompiledCacheKeyedBySHA(t *testing.T) {
+	resetPackCache(t)
+	baseDir, cacheDir := t.TempDir(), t.TempDir()
+	sha := writeTestPack(t, baseDir, `{"domain":"marketing","version":"v25.0","entities":{"campaign":["id","name","compiled_marker"]}}`)
+	provider := &Provider{BaseDir: baseDir, CompiledCacheDir: cacheDir}
+
+	if _, err := provider.GetPack("marketing", "v25.0"); err != nil {
+		t.Fatalf("get pack: %v", err)
+	}
+	if _, err := os.Stat(compiledPackPath(cacheDir, sha)); err != nil {
+		t.Fatalf("expected compiled pack for %s: %v", sha, err)
+	}
+
+	// A new process starts with an empty memory cache and reads the compiled
+	// pack instead of decoding JSON.
+	resetPackCache(t)
+	compiled, ok := readCompiledPack(cacheDir, sha)
+	if !ok || len(compiled.Entities["campaign"]) != 3 {
+		t.Fatalf("unexpected compiled pack: %+v", compiled)
+	}
+	pack, err := provider.GetPack("marketing", "v25.0")
+	if err != nil {
+		t.Fatalf("get pack from compiled cache: %v", err)
+	}
+	if !IndexFor(pack).Entities["campaign"].Has("compiled_marker") {
+		t.Fatalf("unexpected pack from compiled cache: %+v", pack)
+	}
+
+	writeTestPack(t, baseDir, `{"domain":"other","version":"v25.0"}`)
+	if _, err := provider.GetPack("marketing", "v25.0"); err == nil {
+		t.Fatal("expected identity mismatch to be reported for a cached decode path")
+	}
+}
@@ -17,6 +17,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/bilalbayram/metacli/internal/transport"
 )
 
 const (
@@ -85,6 +87,9 @@ type Provider struct {
 	ManifestURL string
 	PublicKey   string
 	HTTPClient  *http.Client
+	// CompiledCacheDir, when set, keeps decoded packs on disk; see
+	// CompiledCacheDirEnv.
+	CompiledCacheDir string
 }
 
 type SignedManifest struct {
@@ -137,8 +142,10 @@ func NewProvider(baseDir string, manifestURL string, publicKey string) *Provider
 		ManifestURL: manifestURL,
 		PublicKey:   publicKey,
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: transport.RefuseOffline(nil),
 		},
+		CompiledCacheDir: strings.TrimSpace(os.Getenv(CompiledCacheDirEnv)),
 	}
 }
 
@@ -191,6 +198,10 @@ func ValidateRemoteFailurePolicy(policy string) error {
 	return nil
 }
 
+// GetPack loads a pack once per process: repeated calls for the same file
+// contents return the same *Pack, which callers must treat as read-only. With
+// CompiledCacheDir set, the decoded pack is also kept on disk under its
+// sha256 so later invocations skip JSON decoding.
 func (p *Provider) GetPack(domain string, version string) (*Pack, error) {
 	if strings.TrimSpace(domain) == "" {
 		return nil, errors.New("schema domain is required")
@@ -208,15 +219,29 @@ func (p *Provider) GetPack(domain string, version string) (*Pack, error) {
 		return nil, fmt.Errorf("read schema pack %s: %w", path, err)
 	}
 
-	if _, err := verifyPackBytes(data, domain, version, "", path); err != nil {
-		return nil, err
+	sum := sha256.Sum256(data)
+	sha := hex.EncodeToString(sum[:])
+	pack := cachedPack(sha)
+	if pack == nil && p.CompiledCacheDir != "" {
+		if compiled, ok := readCompiledPack(p.CompiledCacheDir, sha); ok {
+			pack = storePack(sha, compiled)
+		}
 	}
-
-	var pack Pack
-	if err := json.Unmarshal(data, &pack); err != nil {
-		return nil, fmt.Errorf("decode schema pack %s: %w", path, err)
+	if pack == nil {
+		decoded, err := decodePack(data, path)
+		if err != nil {
+			return nil, err
+		}
+		pack = storePack(sha, decoded)
+		if p.CompiledCacheDir != "" {
+			// The compiled cache only saves time; failing to fill it is not an error.
+			_ = writeCompiledPack(p.CompiledCacheDir, sha, pack)
+		}
 	}
-	return &pack, nil
+	if err := checkPackIdentity(pack, domain, version, path); err != nil {
+		return nil, err
+	}
+	return pack, nil
 }
 
 func (p *Provider) ListPacks() ([]PackRef, error) {
@@ -742,12 +767,27 @@ func verifyPackBytes(body []byte, expectedDomain string, expectedVersion string,
 		)
 	}
 
+	pack, err := decodePack(body, path)
+	if err != nil {
+		return actualSHA, err
+	}
+	if err := checkPackIdentity(pack, expectedDomain, expectedVersion, path); err != nil {
+		return actualSHA, err
+	}
+	return actualSHA, nil
+}
+
+func decodePack(body []byte, path string) (*Pack, error) {
 	var pack Pack
 	if err := json.Unmarshal(body, &pack); err != nil {
-		return actualSHA, fmt.Errorf("decode schema pack %s: %w", path, err)
+		return nil, fmt.Errorf("decode schema pack %s: %w", path, err)
 	}
+	return &pack, nil
+}
+
+func checkPackIdentity(pack *Pack, expectedDomain string, expectedVersion string, path string) error {
 	if pack.Domain != expectedDomain || pack.Version != expectedVersion {
-		return actualSHA, fmt.Errorf(
+		return fmt.Errorf(
 			"schema pack identity mismatch in %s: expected %s/%s, got %s/%s",
 			path,
 			expectedDomain,
@@ -756,7 +796,7 @@ func verifyPackBytes(body []byte, expectedDomain string, expectedVersion string,
 			pack.Version,
 		)
 	}
-	return actualSHA, nil
+	return nil
 }
 
 func hasBlockingDriftDiagnostics(drift []DriftDiagnostic) bool {
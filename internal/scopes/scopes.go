@@ -0,0 +1,121 @@
+// Package scopes declares the OAuth scopes each command needs. Checking them
+// against the scopes recorded on a profile lets a command fail before it runs
+// with the exact scope that is missing, instead of a generic Graph permission
+// error (code 200 or 10) after the call.
+package scopes
+
+import (
+	"sort"
+	"strings"
+)
+
+// Requirement is met by any one of its scopes: ads_management also grants
+// what ads_read does.
+type Requirement []string
+
+func (r Requirement) String() string {
+	return strings.Join(r, " or ")
+}
+
+var (
+	adsRead       = Requirement{"ads_read", "ads_management"}
+	adsManagement = Requirement{"ads_management"}
+	igPublish     = []Requirement{{"instagram_basic"}, {"instagram_content_publish"}}
+	pagesMessages = []Requirement{{"pages_messaging"}}
+)
+
+// capabilities maps commands, without the binary name, to the scopes they
+// need. An entry covers the command and everything under it; the longest
+// matching entry wins. Commands that are not listed, such as auth, config,
+// local validators and app-token commands, are not checked.
+var capabilities = map[string][]Requirement{
+	"account":                       {adsRead},
+	"account spend-cap":             {adsManagement},
+	"campaign":                      {adsManagement},
+	"campaign list":                 {adsRead},
+	"campaign resolve-requirements": {adsRead},
+	"adset":                         {adsManagement},
+	"adset list":                    {adsRead},
+	"ad":                            {adsManagement},
+	"ad list":                       {adsRead},
+	"ad preview":                    {adsRead},
+	"creative":                      {adsManagement},
+	"audience":                      {adsManagement},
+	"audience list":                 {adsRead},
+	"audience get":                  {adsRead},
+	"insights":                      {adsRead},
+	"bulk":                          {adsManagement},
+	"plan":                          {adsRead},
+	"apply":                         {adsManagement},
+	"business":                      {{"business_management"}},
+	"catalog":                       {{"catalog_management"}},
+	"leads export":                  {{"leads_retrieval"}},
+	"leads list":                    {{"leads_retrieval"}},
+	"leads subscribe":               {{"pages_manage_metadata"}},
+	"ig publish":                    igPublish,
+	"ig media":                      igPublish,
+	"ig comments":                   {{"instagram_basic"}, {"instagram_manage_comments"}},
+	"ig insights":                   {{"instagram_basic"}, {"instagram_manage_insights"}},
+	"ig hashtag":                    {{"instagram_basic"}},
+	"ig conversations":              {{"instagram_basic"}, {"instagram_manage_messages"}},
+	"page list":                     {{"pages_read_engagement"}},
+	"page post":                     {{"pages_manage_posts"}},
+	"page schedule":                 {{"pages_manage_posts"}},
+	"page delete":                   {{"pages_manage_posts"}},
+	"msgr send":                     pagesMessages,
+	"msgr conversations":            pagesMessages,
+	"msgr handover":                 pagesMessages,
+	"msgr auto-reply":               {{"pages_manage_metadata"}},
+	"wa send":                       {{"whatsapp_business_messaging"}},
+	"wa templates":                  {{"whatsapp_business_management"}},
+	"wa phone-numbers":              {{"whatsapp_business_management"}},
+}
+
+// Required returns the requirements of command, or nil when it is not
+// checked.
+func Required(command string) []Requirement {
+	command = strings.Join(strings.Fields(command), " ")
+	for candidate := command; candidate != ""; {
+		if requirements, ok := capabilities[candidate]; ok {
+			return requirements
+		}
+		index := strings.LastIndex(candidate, " ")
+		if index < 0 {
+			break
+		}
+		candidate = candidate[:index]
+	}
+	return nil
+}
+
+// Missing returns the requirements of command that granted does not meet.
+func Missing(command string, granted []string) []Requirement {
+	have := make(map[string]struct{}, len(granted))
+	for _, scope := range granted {
+		have[strings.TrimSpace(scope)] = struct{}{}
+	}
+	missing := make([]Requirement, 0)
+	for _, requirement := range Required(command) {
+		met := false
+		for _, scope := range requirement {
+			if _, ok := have[scope]; ok {
+				met = true
+				break
+			}
+		}
+		if !met {
+			missing = append(missing, requirement)
+		}
+	}
+	return missing
+}
+
+// Commands lists the checked commands, sorted.
+func Commands() []string {
+	commands := make([]string, 0, len(capabilities))
+	for command := range capabilities {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+	return commands
+}
@@ -0,0 +1,42 @@
+package scopes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRequiredUsesLongestMatchingEntry(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string][]Requirement{
+		"campaign update":       {{"ads_management"}},
+		"campaign list":         {{"ads_read", "ads_management"}},
+		"insights jobs list":    {{"ads_read", "ads_management"}},
+		"ig publish  feed":      {{"instagram_basic"}, {"instagram_content_publish"}},
+		"ig caption validate":   nil,
+		"auth login":            nil,
+		"account spend-cap set": {{"ads_management"}},
+		"account get":           {{"ads_read", "ads_management"}},
+	}
+	for command, want := range cases {
+		if got := Required(command); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: expected %v, got %v", command, want, got)
+		}
+	}
+}
+
+func TestMissingAcceptsAnyScopeOfARequirement(t *testing.T) {
+	t.Parallel()
+
+	if missing := Missing("campaign list", []string{"ads_management"}); len(missing) != 0 {
+		t.Fatalf("expected ads_management to cover reads, got %v", missing)
+	}
+	missing := Missing("campaign update", []string{"ads_read"})
+	if len(missing) != 1 || missing[0].String() != "ads_management" {
+		t.Fatalf("expected ads_management to be missing, got %v", missing)
+	}
+	missing = Missing("ig publish feed", []string{"instagram_basic"})
+	if len(missing) != 1 || missing[0].String() != "instagram_content_publish" {
+		t.Fatalf("expected instagram_content_publish to be missing, got %v", missing)
+	}
+}
@@ -0,0 +1,432 @@
+// Package selfupdate checks a signed release manifest for newer versions of
+// the CLI and replaces the running binary with a verified download. The
+// trust model is the one schema sync uses: the manifest payload carries an
+// Ed25519 signature checked against a pinned public key, and every artifact is
+// pinned by SHA-256 inside the signed payload.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/schema"
+	"github.com/bilalbayram/metacli/internal/transport"
+)
+
+const (
+	DefaultManifestURL = "https://github.com/bilalbayram/metacli/releases/latest/download/release-manifest.json"
+	// DefaultPublicKey is the key schema manifests are signed with; releases
+	// are signed with the same key.
+	DefaultPublicKey = schema.DefaultManifestPubKey
+
+	binaryName = "meta"
+	maxBinary  = 256 << 20
+)
+
+type SignedManifest struct {
+	Payload   ManifestPayload `json:"payload"`
+	Signature string          `json:"signature"`
+}
+
+type ManifestPayload struct {
+	Channel     string     `json:"channel"`
+	Version     string     `json:"version"`
+	PublishedAt string     `json:"published_at"`
+	NotesURL    string     `json:"notes_url,omitempty"`
+	Artifacts   []Artifact `json:"artifacts"`
+}
+
+// Artifact is a release build for one platform: a .tar.gz or .zip archive
+// holding the meta binary, or the bare binary.
+type Artifact struct {
+	OS     string `json:"os"`
+	Arch   string `json:"arch"`
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+type Updater struct {
+	ManifestURL string
+	PublicKey   string
+	HTTPClient  *http.Client
+}
+
+func New(manifestURL string, publicKey string) *Updater {
+	if strings.TrimSpace(manifestURL) == "" {
+		manifestURL = DefaultManifestURL
+	}
+	if strings.TrimSpace(publicKey) == "" {
+		publicKey = DefaultPublicKey
+	}
+	return &Updater{
+		ManifestURL: manifestURL,
+		PublicKey:   publicKey,
+		HTTPClient: &http.Client{
+			Timeout:   5 * time.Minute,
+			Transport: transport.RefuseOffline(nil),
+		},
+	}
+}
+
+// Check is the running version compared with the manifest's release.
+type Check struct {
+	Current         string `json:"current"`
+	Latest          string `json:"latest"`
+	Channel         string `json:"channel"`
+	PublishedAt     string `json:"published_at,omitempty"`
+	NotesURL        string `json:"notes_url,omitempty"`
+	UpdateAvailable bool   `json:"update_available"`
+	// Comparable is false for builds without a release version, such as
+	// "dev"; they are never reported as outdated.
+	Comparable bool `json:"comparable"`
+}
+
+// FetchManifest downloads the manifest and verifies its signature and channel.
+func (u *Updater) FetchManifest(ctx context.Context, channel string) (ManifestPayload, error) {
+	body, err := u.get(ctx, u.ManifestURL, "release manifest")
+	if err != nil {
+		return ManifestPayload{}, err
+	}
+	signed := SignedManifest{}
+	if err := json.Unmarshal(body, &signed); err != nil {
+		return ManifestPayload{}, fmt.Errorf("decode release manifest: %w", err)
+	}
+	if signed.Signature == "" {
+		return ManifestPayload{}, errors.New("release manifest signature is missing")
+	}
+	if err := verifySignature(signed.Payload, signed.Signature, u.PublicKey); err != nil {
+		return ManifestPayload{}, err
+	}
+	if signed.Payload.Channel != channel {
+		return ManifestPayload{}, fmt.Errorf("release manifest channel mismatch: expected %s got %s", channel, signed.Payload.Channel)
+	}
+	if _, err := parseVersion(signed.Payload.Version); err != nil {
+		return ManifestPayload{}, fmt.Errorf("release manifest version: %w", err)
+	}
+	return signed.Payload, nil
+}
+
+func (u *Updater) Check(ctx context.Context, channel string, current string) (Check, ManifestPayload, error) {
+	manifest, err := u.FetchManifest(ctx, channel)
+	if err != nil {
+		return Check{}, ManifestPayload{}, err
+	}
+	check := Check{
+		Current:     current,
+		Latest:      manifest.Version,
+		Channel:     manifest.Channel,
+		PublishedAt: manifest.PublishedAt,
+		NotesURL:    manifest.NotesURL,
+	}
+	if order, err := Compare(current, manifest.Version); err == nil {
+		check.Comparable = true
+		check.UpdateAvailable = order < 0
+	}
+	return check, manifest, nil
+}
+
+// Install downloads the artifact for goos/goarch, verifies it against the
+// signed checksum and atomically replaces the binary at executable.
+func (u *Updater) Install(ctx context.Context, manifest ManifestPayload, goos string, goarch string, executable string) (Artifact, error) {
+	artifact, ok := manifest.Artifact(goos, goarch)
+	if !ok {
+		return Artifact{}, fmt.Errorf("release %s has no build for %s/%s", manifest.Version, goos, goarch)
+	}
+	body, err := u.get(ctx, artifact.URL, "release artifact")
+	if err != nil {
+		return Artifact{}, err
+	}
+	sum := sha256.Sum256(body)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, artifact.SHA256) {
+		return Artifact{}, fmt.Errorf("release artifact checksum mismatch for %s/%s: expected %s got %s", goos, goarch, artifact.SHA256, actual)
+	}
+	binary, err := extractBinary(artifact.URL, body, goos)
+	if err != nil {
+		return Artifact{}, err
+	}
+	if err := replaceExecutable(executable, binary); err != nil {
+		return Artifact{}, err
+	}
+	return artifact, nil
+}
+
+// Artifact returns the build for goos/goarch.
+func (m ManifestPayload) Artifact(goos string, goarch string) (Artifact, bool) {
+	for _, artifact := range m.Artifacts {
+		if artifact.OS == goos && artifact.Arch == goarch && artifact.URL != "" && artifact.SHA256 != "" {
+			return artifact, true
+		}
+	}
+	return Artifact{}, false
+}
+
+func (u *Updater) get(ctx context.Context, url string, what string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build %s request: %w", what, err)
+	}
+	res, err := u.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", what, err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxBinary+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", what, err)
+	}
+	if len(body) > maxBinary {
+		return nil, fmt.Errorf("%s exceeds %d bytes", what, maxBinary)
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s request failed with status %d", what, res.StatusCode)
+	}
+	return body, nil
+}
+
+func verifySignature(payload ManifestPayload, signatureB64 string, pubKeyB64 string) error {
+	pubKey, err := base64.StdEncoding.DecodeString(pubKeyB64)
+	if err != nil {
+		return fmt.Errorf("decode release manifest public key: %w", err)
+	}
+	if len(pubKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid release manifest public key length %d", len(pubKey))
+	}
+	signature, err := base64.StdEncoding.DecodeString(signatureB64)
+	if err != nil {
+		return fmt.Errorf("decode release manifest signature: %w", err)
+	}
+	if len(signature) != ed25519.SignatureSize {
+		return fmt.Errorf("invalid release manifest signature length %d", len(signature))
+	}
+	message, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal release manifest payload for verification: %w", err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pubKey), message, signature) {
+		return errors.New("release manifest signature verification failed")
+	}
+	return nil
+}
+
+// extractBinary returns the meta binary from a .tar.gz or .zip archive, or
+// body itself for a bare binary.
+func extractBinary(url string, body []byte, goos string) ([]byte, error) {
+	name := binaryName
+	if goos == "windows" {
+		name += ".exe"
+	}
+	switch lower := strings.ToLower(url); {
+	case strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz"):
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("open release archive: %w", err)
+		}
+		archive := tar.NewReader(gz)
+		for {
+			header, err := archive.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("read release archive: %w", err)
+			}
+			if header.Typeflag == tar.TypeReg && path.Base(header.Name) == name {
+				return io.ReadAll(io.LimitReader(archive, maxBinary))
+			}
+		}
+	case strings.HasSuffix(lower, ".zip"):
+		archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			return nil, fmt.Errorf("open release archive: %w", err)
+		}
+		for _, file := range archive.File {
+			if path.Base(file.Name) != name || file.FileInfo().IsDir() {
+				continue
+			}
+			reader, err := file.Open()
+			if err != nil {
+				return nil, fmt.Errorf("read release archive: %w", err)
+			}
+			defer reader.Close()
+			return io.ReadAll(io.LimitReader(reader, maxBinary))
+		}
+	default:
+		return body, nil
+	}
+	return nil, fmt.Errorf("release archive does not contain %s", name)
+}
+
+// replaceExecutable writes binary next to executable and renames it into
+// place. The old binary is moved aside first, since Windows cannot overwrite
+// a running executable, and restored if the swap fails.
+func replaceExecutable(executable string, binary []byte) error {
+	info, err := os.Stat(executable)
+	if err != nil {
+		return fmt.Errorf("stat current executable: %w", err)
+	}
+	dir := filepath.Dir(executable)
+	tmp, err := os.CreateTemp(dir, ".meta-update-*")
+	if err != nil {
+		return fmt.Errorf("stage update in %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(binary); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write staged update: %w", err)
+	}
+	if err := tmp.Chmod(info.Mode().Perm() | 0o111); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("chmod staged update: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("close staged update: %w", err)
+	}
+
+	backup := executable + ".old"
+	_ = os.Remove(backup)
+	if err := os.Rename(executable, backup); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("move current executable aside: %w", err)
+	}
+	if err := os.Rename(tmpPath, executable); err != nil {
+		_ = os.Rename(backup, executable)
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("install update: %w", err)
+	}
+	// A running Windows binary cannot be removed; the next update clears it.
+	_ = os.Remove(backup)
+	return nil
+}
+
+// Compare orders two release versions such as "v1.4.0", "1.4.0" or
+// "1.5.0-rc.1". A pre-release sorts before its release.
+func Compare(a string, b string) (int, error) {
+	left, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	right, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for index := range left.core {
+		if left.core[index] != right.core[index] {
+			if left.core[index] < right.core[index] {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	switch {
+	case left.pre == right.pre:
+		return 0, nil
+	case left.pre == "":
+		return 1, nil
+	case right.pre == "":
+		return -1, nil
+	default:
+		return comparePrerelease(left.pre, right.pre), nil
+	}
+}
+
+// comparePrerelease orders pre-release versions as semver 2.0.0 §11 does:
+// identifier by identifier, numeric ones by value and below alphanumeric ones,
+// so rc.10 follows rc.2, and a longer list wins when the shorter is its prefix.
+func comparePrerelease(a string, b string) int {
+	left, right := strings.Split(a, "."), strings.Split(b, ".")
+	for index := 0; index < len(left) && index < len(right); index++ {
+		l, r := left[index], right[index]
+		lNumeric, rNumeric := isNumericIdentifier(l), isNumericIdentifier(r)
+		switch {
+		case lNumeric && rNumeric:
+			// Without leading zeros the longer number is the larger one, and
+			// comparing the digits cannot overflow.
+			l, r = strings.TrimLeft(l, "0"), strings.TrimLeft(r, "0")
+			if len(l) != len(r) {
+				return compareInts(len(l), len(r))
+			}
+		case lNumeric:
+			return -1
+		case rNumeric:
+			return 1
+		}
+		if c := strings.Compare(l, r); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(left), len(right))
+}
+
+func isNumericIdentifier(identifier string) bool {
+	if identifier == "" {
+		return false
+	}
+	for _, r := range identifier {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func compareInts(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type version struct {
+	core [3]int
+	pre  string
+}
+
+func parseVersion(raw string) (version, error) {
+	value := strings.TrimPrefix(strings.TrimSpace(raw), "v")
+	if index := strings.Index(value, "+"); index >= 0 {
+		value = value[:index]
+	}
+	parsed := version{}
+	if index := strings.Index(value, "-"); index >= 0 {
+		parsed.pre = value[index+1:]
+		value = value[:index]
+	}
+	parts := strings.Split(value, ".")
+	if len(parts) != 3 {
+		return version{}, fmt.Errorf("%q is not a release version", raw)
+	}
+	for index, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return version{}, fmt.Errorf("%q is not a release version", raw)
+		}
+		parsed.core[index] = number
+	}
+	return parsed, nil
+}
@@ -0,0 +1,281 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompareOrdersReleaseVersions(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{a: "v1.4.0", b: "1.4.0", want: 0},
+		{a: "v1.4.0", b: "v1.10.0", want: -1},
+		{a: "v2.0.0", b: "v1.99.99", want: 1},
+		{a: "v1.5.0-rc.1", b: "v1.5.0", want: -1},
+		{a: "v1.5.0-rc.2", b: "v1.5.0-rc.1", want: 1},
+		{a: "v1.5.0-rc.10", b: "v1.5.0-rc.2", want: 1},
+		{a: "v1.5.0-rc.2", b: "v1.5.0-rc.10", want: -1},
+		{a: "v1.5.0-alpha", b: "v1.5.0-alpha.1", want: -1},
+		{a: "v1.5.0-alpha.1", b: "v1.5.0-alpha.beta", want: -1},
+		{a: "v1.5.0-alpha.beta", b: "v1.5.0-beta", want: -1},
+		{a: "v1.5.0-beta.11", b: "v1.5.0-beta.2", want: 1},
+		{a: "v1.5.0-1", b: "v1.5.0-alpha", want: -1},
+		{a: "v1.5.0-rc.1", b: "v1.5.0-rc.1+build.7", want: 0},
+		{a: "v1.5.0-rc.99999999999999999999", b: "v1.5.0-rc.100000000000000000000", want: -1},
+		{a: "v1.5.0+build.7", b: "v1.5.0", want: 0},
+	} {
+		got, err := Compare(tc.a, tc.b)
+		if err != nil {
+			t.Fatalf("compare %s %s: %v", tc.a, tc.b, err)
+		}
+		if got != tc.want {
+			t.Fatalf("compare %s %s = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+	for _, raw := range []string{"dev", "v1.4", "v1.x.0", ""} {
+		if _, err := Compare(raw, "v1.0.0"); err == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestCheckReportsNewerRelease(t *testing.T) {
+	t.Parallel()
+
+	pub, priv := generateKey(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeSignedManifest(t, w, priv, ManifestPayload{Channel: "stable", Version: "v1.5.0", PublishedAt: "2026-10-01T00:00:00Z"})
+	}))
+	defer server.Close()
+
+	updater := New(server.URL+"/release-manifest.json", pub)
+	check, _, err := updater.Check(context.Background(), "stable", "v1.4.2")
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if !check.Comparable || !check.UpdateAvailable || check.Latest != "v1.5.0" {
+		t.Fatalf("unexpected check: %#v", check)
+	}
+
+	check, _, err = updater.Check(context.Background(), "stable", "dev")
+	if err != nil {
+		t.Fatalf("check dev: %v", err)
+	}
+	if check.Comparable || check.UpdateAvailable {
+		t.Fatalf("dev build must not be reported as outdated: %#v", check)
+	}
+}
+
+func TestFetchManifestRejectsUntrustedManifests(t *testing.T) {
+	t.Parallel()
+
+	pub, priv := generateKey(t)
+	otherPub, _ := generateKey(t)
+	payload := ManifestPayload{Channel: "stable", Version: "v1.5.0"}
+	for _, tc := range []struct {
+		name    string
+		handler http.HandlerFunc
+		key     string
+		channel string
+		want    string
+	}{
+		{
+			name: "wrong key",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				writeSignedManifest(t, w, priv, payload)
+			},
+			key:     otherPub,
+			channel: "stable",
+			want:    "signature verification failed",
+		},
+		{
+			name: "tampered payload",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				signed := signManifest(t, priv, payload)
+				signed.Payload.Version = "v9.9.9"
+				_ = json.NewEncoder(w).Encode(signed)
+			},
+			key:     pub,
+			channel: "stable",
+			want:    "signature verification failed",
+		},
+		{
+			name: "unsigned",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(SignedManifest{Payload: payload})
+			},
+			key:     pub,
+			channel: "stable",
+			want:    "signature is missing",
+		},
+		{
+			name: "other channel",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				writeSignedManifest(t, w, priv, payload)
+			},
+			key:     pub,
+			channel: "beta",
+			want:    "channel mismatch",
+		},
+	} {
+		server := httptest.NewServer(tc.handler)
+		_, err := New(server.URL, tc.key).FetchManifest(context.Background(), tc.channel)
+		server.Close()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestInstallReplacesExecutableFromVerifiedArchive(t *testing.T) {
+	t.Parallel()
+
+	archive := tarGz(t, map[string]string{"meta_1.5.0/README.md": "readme", "meta_1.5.0/meta": "new binary"})
+	sum := sha256.Sum256(archive)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+
+	executable := filepath.Join(t.TempDir(), "meta")
+	if err := os.WriteFile(executable, []byte("old binary"), 0o755); err != nil {
+		t.Fatalf("write executable: %v", err)
+	}
+	manifest := ManifestPayload{
+		Channel: "stable",
+		Version: "v1.5.0",
+		Artifacts: []Artifact{
+			{OS: "darwin", Arch: "arm64", URL: server.URL + "/meta_darwin_arm64.tar.gz", SHA256: strings.Repeat("0", 64)},
+			{OS: "linux", Arch: "amd64", URL: server.URL + "/meta_linux_amd64.tar.gz", SHA256: hex.EncodeToString(sum[:])},
+		},
+	}
+
+	artifact, err := New(server.URL, "").Install(context.Background(), manifest, "linux", "amd64", executable)
+	if err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if !strings.HasSuffix(artifact.URL, "meta_linux_amd64.tar.gz") {
+		t.Fatalf("unexpected artifact: %#v", artifact)
+	}
+	raw, err := os.ReadFile(executable)
+	if err != nil {
+		t.Fatalf("read executable: %v", err)
+	}
+	if string(raw) != "new binary" {
+		t.Fatalf("executable not replaced: %q", raw)
+	}
+	info, err := os.Stat(executable)
+	if err != nil {
+		t.Fatalf("stat executable: %v", err)
+	}
+	if info.Mode().Perm()&0o111 == 0 {
+		t.Fatalf("installed binary is not executable: %v", info.Mode())
+	}
+	if _, err := os.Stat(executable + ".old"); !os.IsNotExist(err) {
+		t.Fatalf("expected backup to be removed, got %v", err)
+	}
+}
+
+func TestInstallLeavesExecutableOnChecksumMismatch(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("tampered binary"))
+	}))
+	defer server.Close()
+
+	executable := filepath.Join(t.TempDir(), "meta")
+	if err := os.WriteFile(executable, []byte("old binary"), 0o755); err != nil {
+		t.Fatalf("write executable: %v", err)
+	}
+	sum := sha256.Sum256([]byte("new binary"))
+	manifest := ManifestPayload{
+		Channel:   "stable",
+		Version:   "v1.5.0",
+		Artifacts: []Artifact{{OS: "linux", Arch: "amd64", URL: server.URL + "/meta", SHA256: hex.EncodeToString(sum[:])}},
+	}
+
+	_, err := New(server.URL, "").Install(context.Background(), manifest, "linux", "amd64", executable)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	raw, _ := os.ReadFile(executable)
+	if string(raw) != "old binary" {
+		t.Fatalf("executable changed after failed install: %q", raw)
+	}
+
+	_, err = New(server.URL, "").Install(context.Background(), manifest, "windows", "amd64", executable)
+	if err == nil || !strings.Contains(err.Error(), "no build for windows/amd64") {
+		t.Fatalf("expected missing platform error, got %v", err)
+	}
+}
+
+func generateKey(t *testing.T) (string, ed25519.PrivateKey) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate keypair: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(pub), priv
+}
+
+func signManifest(t *testing.T, key ed25519.PrivateKey, payload ManifestPayload) SignedManifest {
+	t.Helper()
+
+	message, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	return SignedManifest{Payload: payload, Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, message))}
+}
+
+func writeSignedManifest(t *testing.T, w http.ResponseWriter, key ed25519.PrivateKey, payload ManifestPayload) {
+	t.Helper()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(signManifest(t, key, payload)); err != nil {
+		t.Fatalf("encode manifest: %v", err)
+	}
+}
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	archive := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := archive.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("write tar header: %v", err)
+		}
+		if _, err := archive.Write([]byte(content)); err != nil {
+			t.Fatalf("write tar entry: %v", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("close tar: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("close gzip: %v", err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,164 @@
+package smoke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/ops"
+)
+
+const (
+	CleanupPolicyAlways    = "always"
+	CleanupPolicyOnSuccess = "on-success"
+	CleanupPolicyNever     = "never"
+)
+
+const (
+	CleanupStatusApplied = "applied"
+	CleanupStatusFailed  = "failed"
+	CleanupStatusSkipped = "skipped"
+)
+
+const stepNameCleanup = "cleanup"
+
+var ErrInvalidCleanupPolicy = errors.New("invalid cleanup policy")
+
+type CleanupReport struct {
+	Policy    string                  `json:"policy"`
+	Executed  bool                    `json:"executed"`
+	Reason    string                  `json:"reason,omitempty"`
+	Summary   CleanupSummary          `json:"summary"`
+	Resources []CleanupResourceResult `json:"resources"`
+}
+
+type CleanupSummary struct {
+	Total   int `json:"total"`
+	Applied int `json:"applied"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+type CleanupResourceResult struct {
+	Sequence      int    `json:"sequence"`
+	ResourceKind  string `json:"resource_kind"`
+	ResourceID    string `json:"resource_id"`
+	CleanupAction string `json:"cleanup_action"`
+	Status        string `json:"status"`
+	Message       string `json:"message"`
+}
+
+func NormalizeCleanupPolicy(policy string) string {
+	switch strings.ToLower(strings.TrimSpace(policy)) {
+	case "":
+		return CleanupPolicyNever
+	case CleanupPolicyAlways:
+		return CleanupPolicyAlways
+	case CleanupPolicyOnSuccess:
+		return CleanupPolicyOnSuccess
+	case CleanupPolicyNever:
+		return CleanupPolicyNever
+	default:
+		return ""
+	}
+}
+
+func ValidateCleanupPolicy(policy string) error {
+	if NormalizeCleanupPolicy(policy) == "" {
+		return fmt.Errorf(
+			"%w: cleanup policy must be one of [%s %s %s], got %q",
+			ErrInvalidCleanupPolicy,
+			CleanupPolicyAlways,
+			CleanupPolicyOnSuccess,
+			CleanupPolicyNever,
+			policy,
+		)
+	}
+	return nil
+}
+
+func (r Report) RemainingResources() []CreatedResource {
+	applied := make(map[int]struct{}, len(r.Cleanup.Resources))
+	for _, result := range r.Cleanup.Resources {
+		if result.Status == CleanupStatusApplied {
+			applied[result.Sequence] = struct{}{}
+		}
+	}
+	remaining := make([]CreatedResource, 0, len(r.CreatedResources))
+	for _, resource := range r.CreatedResources {
+		if _, ok := applied[resource.Sequence]; ok {
+			continue
+		}
+		remaining = append(remaining, resource)
+	}
+	return remaining
+}
+
+func runCleanupPhase(ctx context.Context, executor ops.CleanupExecutor, report *Report, policy string, version string, token string, appSecret string) {
+	cleanup := CleanupReport{
+		Policy:    policy,
+		Resources: make([]CleanupResourceResult, 0, len(report.CreatedResources)),
+	}
+
+	switch {
+	case len(report.CreatedResources) == 0:
+		cleanup.Reason = "no resources were created"
+	case policy == CleanupPolicyNever:
+		cleanup.Reason = "cleanup policy is never"
+	case policy == CleanupPolicyOnSuccess && report.Summary.Blocking > 0:
+		cleanup.Reason = "run reported blocking findings; on-success cleanup skipped"
+	default:
+		cleanup.Executed = true
+	}
+
+	for index := len(report.CreatedResources) - 1; index >= 0; index-- {
+		resource := report.CreatedResources[index]
+		result := CleanupResourceResult{
+			Sequence:      resource.Sequence,
+			ResourceKind:  resource.ResourceKind,
+			ResourceID:    resource.ResourceID,
+			CleanupAction: resource.CleanupAction,
+		}
+		if !cleanup.Executed {
+			result.Status = CleanupStatusSkipped
+			result.Message = cleanup.Reason
+			cleanup.Resources = append(cleanup.Resources, result)
+			continue
+		}
+
+		var err error
+		switch resource.CleanupAction {
+		case ops.CleanupActionPause:
+			err = executor.Pause(ctx, version, token, appSecret, resource.ResourceID)
+		case ops.CleanupActionDelete:
+			err = executor.Delete(ctx, version, token, appSecret, resource.ResourceID)
+		default:
+			err = fmt.Errorf("unsupported cleanup action %q for resource %s", resource.CleanupAction, resource.ResourceID)
+		}
+		if err != nil {
+			result.Status = CleanupStatusFailed
+			result.Message = strings.TrimSpace(err.Error())
+			failure := failureFromError(stepNameCleanup, false, false, err)
+			failure.Message = fmt.Sprintf("%s %s %s: %s", resource.CleanupAction, resource.ResourceKind, resource.ResourceID, failure.Message)
+			report.Failures = append(report.Failures, failure)
+		} else {
+			result.Status = CleanupStatusApplied
+			result.Message = fmt.Sprintf("%s %s %s", resource.CleanupAction, resource.ResourceKind, resource.ResourceID)
+		}
+		cleanup.Resources = append(cleanup.Resources, result)
+	}
+
+	cleanup.Summary = CleanupSummary{Total: len(cleanup.Resources)}
+	for _, result := range cleanup.Resources {
+		switch result.Status {
+		case CleanupStatusApplied:
+			cleanup.Summary.Applied++
+		case CleanupStatusFailed:
+			cleanup.Summary.Failed++
+		case CleanupStatusSkipped:
+			cleanup.Summary.Skipped++
+		}
+	}
+	report.Cleanup = cleanup
+}
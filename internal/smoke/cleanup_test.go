@@ -0,0 +1,168 @@
+package smoke
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func cleanupSmokeCalls() []fakeCall {
+	return []fakeCall{
+		{
+			Method: http.MethodGet,
+			Path:   "act_1234",
+			Response: &graph.Response{
+				Body: map[string]any{
+					"id":             "act_1234",
+					"name":           "Primary",
+					"currency":       "USD",
+					"account_status": float64(1),
+				},
+			},
+		},
+		{
+			Method: http.MethodPost,
+			Path:   "act_1234/campaigns",
+			Response: &graph.Response{
+				Body: map[string]any{"id": "cmp_1001"},
+			},
+		},
+		{
+			Method: http.MethodPost,
+			Path:   "act_1234/customaudiences",
+			Response: &graph.Response{
+				Body: map[string]any{"id": "aud_2001"},
+			},
+		},
+		{
+			Method: http.MethodPost,
+			Path:   "act_1234/adsets",
+			Response: &graph.Response{
+				Body: map[string]any{"id": "ads_3001"},
+			},
+		},
+		{
+			Method: http.MethodGet,
+			Path:   "cmp_1001/insights",
+			Response: &graph.Response{
+				Body: map[string]any{"data": []any{}},
+			},
+		},
+	}
+}
+
+func TestRunnerCleanupAlwaysTearsDownCreatedResourcesInReverseOrder(t *testing.T) {
+	calls := append(cleanupSmokeCalls(),
+		fakeCall{Method: http.MethodPost, Path: "ads_3001", Response: &graph.Response{Body: map[string]any{"success": true}}},
+		fakeCall{Method: http.MethodDelete, Path: "aud_2001", Response: &graph.Response{Body: map[string]any{"success": true}}},
+		fakeCall{Method: http.MethodPost, Path: "cmp_1001", Response: &graph.Response{Body: map[string]any{"success": true}}},
+	)
+	client := &fakeGraphClient{t: t, calls: calls}
+
+	result, err := NewRunner(client).Run(context.Background(), RunInput{
+		Version:        "v25.0",
+		AccountID:      "1234",
+		Token:          "token",
+		OptionalPolicy: OptionalPolicySkip,
+		CleanupPolicy:  CleanupPolicyAlways,
+	})
+	if err != nil {
+		t.Fatalf("run smoke runner: %v", err)
+	}
+	client.assertAllCallsConsumed()
+
+	cleanup := result.Report.Cleanup
+	if !cleanup.Executed || cleanup.Policy != CleanupPolicyAlways {
+		t.Fatalf("unexpected cleanup report: %+v", cleanup)
+	}
+	if cleanup.Summary.Total != 3 || cleanup.Summary.Applied != 3 || cleanup.Summary.Failed != 0 {
+		t.Fatalf("unexpected cleanup summary: %+v", cleanup.Summary)
+	}
+	if cleanup.Resources[0].ResourceID != "ads_3001" || cleanup.Resources[1].ResourceID != "aud_2001" || cleanup.Resources[2].ResourceID != "cmp_1001" {
+		t.Fatalf("unexpected cleanup order: %+v", cleanup.Resources)
+	}
+	if remaining := result.Report.RemainingResources(); len(remaining) != 0 {
+		t.Fatalf("expected no remaining resources, got %+v", remaining)
+	}
+}
+
+func TestRunnerCleanupFailureIsReportedAsWarning(t *testing.T) {
+	calls := append(cleanupSmokeCalls(),
+		fakeCall{Method: http.MethodPost, Path: "ads_3001", Response: &graph.Response{Body: map[string]any{"success": true}}},
+		fakeCall{Method: http.MethodDelete, Path: "aud_2001", Err: &graph.APIError{Type: "OAuthException", Code: 100, StatusCode: http.StatusBadRequest, Message: "Unsupported delete request"}},
+		fakeCall{Method: http.MethodPost, Path: "cmp_1001", Response: &graph.Response{Body: map[string]any{"success": true}}},
+	)
+	client := &fakeGraphClient{t: t, calls: calls}
+
+	result, err := NewRunner(client).Run(context.Background(), RunInput{
+		Version:        "v25.0",
+		AccountID:      "1234",
+		Token:          "token",
+		OptionalPolicy: OptionalPolicySkip,
+		CleanupPolicy:  CleanupPolicyOnSuccess,
+	})
+	if err != nil {
+		t.Fatalf("run smoke runner: %v", err)
+	}
+	client.assertAllCallsConsumed()
+
+	report := result.Report
+	if report.Cleanup.Summary.Applied != 2 || report.Cleanup.Summary.Failed != 1 {
+		t.Fatalf("unexpected cleanup summary: %+v", report.Cleanup.Summary)
+	}
+	if report.Summary.Warnings != 4 || report.Outcome != RunOutcomeWarning {
+		t.Fatalf("expected cleanup failure to count as warning, got summary=%+v outcome=%s", report.Summary, report.Outcome)
+	}
+	if len(report.Failures) != 1 || report.Failures[0].Step != stepNameCleanup || report.Failures[0].Blocking {
+		t.Fatalf("unexpected failures: %+v", report.Failures)
+	}
+	remaining := report.RemainingResources()
+	if len(remaining) != 1 || remaining[0].ResourceID != "aud_2001" {
+		t.Fatalf("unexpected remaining resources: %+v", remaining)
+	}
+	if code := RunExitCode(report); code != ExitCodeWarning {
+		t.Fatalf("unexpected exit code: got=%d want=%d", code, ExitCodeWarning)
+	}
+}
+
+func TestRunnerCleanupOnSuccessSkipsBlockedRuns(t *testing.T) {
+	client := &fakeGraphClient{t: t, calls: cleanupSmokeCalls()[:3]}
+
+	result, err := NewRunner(client).Run(context.Background(), RunInput{
+		Version:        "v25.0",
+		AccountID:      "1234",
+		Token:          "token",
+		OptionalPolicy: OptionalPolicyStrict,
+		CleanupPolicy:  CleanupPolicyOnSuccess,
+	})
+	if err != nil {
+		t.Fatalf("run smoke runner: %v", err)
+	}
+	client.assertAllCallsConsumed()
+
+	cleanup := result.Report.Cleanup
+	if result.Report.Outcome != RunOutcomeBlocking {
+		t.Fatalf("expected strict run without catalog to block, got %s", result.Report.Outcome)
+	}
+	if cleanup.Executed || cleanup.Summary.Skipped != 2 {
+		t.Fatalf("expected skipped cleanup, got %+v", cleanup)
+	}
+	if len(result.Report.RemainingResources()) != 2 {
+		t.Fatalf("expected all resources to remain, got %+v", result.Report.RemainingResources())
+	}
+}
+
+func TestValidateCleanupPolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []string{"", "always", "ON-SUCCESS", "never"} {
+		if err := ValidateCleanupPolicy(policy); err != nil {
+			t.Fatalf("expected %q to be valid: %v", policy, err)
+		}
+	}
+	if err := ValidateCleanupPolicy("sometimes"); err == nil {
+		t.Fatal("expected invalid cleanup policy error")
+	}
+}
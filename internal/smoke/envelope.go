@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"io"
 	"strings"
+
+	"github.com/bilalbayram/metacli/internal/output"
 )
 
 const (
 	ContractVersion = "smoke.v2"
 	CommandRun      = "meta smoke run"
+	CommandDiff     = "meta smoke diff"
 )
 
 type Envelope struct {
@@ -23,6 +26,7 @@ type Envelope struct {
 }
 
 type ErrorInfo struct {
+	Class   string `json:"class"`
 	Type    string `json:"type"`
 	Message string `json:"message"`
 }
@@ -51,6 +55,7 @@ func NewErrorEnvelope(command string, code int, err error) Envelope {
 		Success:         false,
 		ExitCode:        code,
 		Error: &ErrorInfo{
+			Class:   errorClass(code),
 			Type:    errorType(code),
 			Message: message,
 		},
@@ -87,3 +92,15 @@ func errorType(code int) string {
 		return "error"
 	}
 }
+
+// errorClass maps exit codes onto the shared error.class taxonomy.
+func errorClass(code int) string {
+	switch code {
+	case ExitCodeInput:
+		return output.ErrorClassInput
+	case ExitCodePolicy, ExitCodeWarning:
+		return output.ErrorClassPolicy
+	default:
+		return output.ErrorClassInternal
+	}
+}
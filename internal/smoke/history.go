@@ -0,0 +1,334 @@
+package smoke
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const HistorySchemaVersion = 1
+
+const (
+	historyFilePrefix    = "report-"
+	historyFileSuffix    = ".json"
+	historyTimestampForm = "20060102T150405.000000000Z"
+	historyDefaultKey    = "default"
+)
+
+var (
+	ErrHistoryDirRequired  = errors.New("smoke history directory is required")
+	ErrHistoryInsufficient = errors.New("smoke history requires at least two recorded runs")
+	historyKeyUnsafeRegex  = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+type HistoryEntry struct {
+	SchemaVersion int       `json:"schema_version"`
+	RecordedAt    time.Time `json:"recorded_at"`
+	ProfileName   string    `json:"profile_name"`
+	AccountID     string    `json:"account_id"`
+	Report        Report    `json:"report"`
+}
+
+type StoredReport struct {
+	Path  string
+	Entry HistoryEntry
+}
+
+type DiffResult struct {
+	HistoryDir            string             `json:"history_dir"`
+	Previous              DiffRun            `json:"previous"`
+	Latest                DiffRun            `json:"latest"`
+	OutcomeChanged        bool               `json:"outcome_changed"`
+	CapabilityRegressions []CapabilityChange `json:"capability_regressions"`
+	StepRegressions       []StepChange       `json:"step_regressions"`
+	Recoveries            []StepChange       `json:"recoveries"`
+	Regression            DiffSummary        `json:"regression"`
+}
+
+type DiffRun struct {
+	Path       string    `json:"path"`
+	RecordedAt time.Time `json:"recorded_at"`
+	Outcome    string    `json:"outcome"`
+	Summary    Summary   `json:"summary"`
+}
+
+type CapabilityChange struct {
+	Name     string `json:"name"`
+	Previous string `json:"previous"`
+	Latest   string `json:"latest"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type StepChange struct {
+	Name     string `json:"name"`
+	Previous string `json:"previous"`
+	Latest   string `json:"latest"`
+	Message  string `json:"message,omitempty"`
+}
+
+type DiffSummary struct {
+	Regressed  bool   `json:"regressed"`
+	Capability int    `json:"capability"`
+	Steps      int    `json:"steps"`
+	Recovered  int    `json:"recovered"`
+	Summary    string `json:"summary"`
+}
+
+func DefaultHistoryRoot() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "smoke", "reports"), nil
+}
+
+func HistoryDir(root string, profileName string, accountID string) (string, error) {
+	root = strings.TrimSpace(root)
+	if root == "" {
+		return "", ErrHistoryDirRequired
+	}
+	normalizedAccountID, err := normalizeAdAccountID(accountID)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, historyKey(profileName), "act_"+normalizedAccountID), nil
+}
+
+func SaveHistoryEntry(dir string, entry HistoryEntry) (string, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return "", ErrHistoryDirRequired
+	}
+	if entry.RecordedAt.IsZero() {
+		return "", errors.New("smoke history entry recorded_at is required")
+	}
+	entry.SchemaVersion = HistorySchemaVersion
+	entry.RecordedAt = entry.RecordedAt.UTC()
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("create smoke history directory %s: %w", dir, err)
+	}
+
+	path := filepath.Join(dir, historyFilePrefix+entry.RecordedAt.Format(historyTimestampForm)+historyFileSuffix)
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("smoke history entry already exists at %s", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("stat smoke history entry %s: %w", path, err)
+	}
+
+	payload, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal smoke history entry: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".report-*.json")
+	if err != nil {
+		return "", fmt.Errorf("create temp smoke history file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("write temp smoke history file: %w", err)
+	}
+	if err := tmpFile.Chmod(0o600); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("chmod temp smoke history file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return "", fmt.Errorf("close temp smoke history file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return "", fmt.Errorf("write smoke history entry %s: %w", path, err)
+	}
+	return path, nil
+}
+
+func LoadHistory(dir string) ([]StoredReport, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return nil, ErrHistoryDirRequired
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []StoredReport{}, nil
+		}
+		return nil, fmt.Errorf("read smoke history directory %s: %w", dir, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, historyFilePrefix) || !strings.HasSuffix(name, historyFileSuffix) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	reports := make([]StoredReport, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		entry, err := loadHistoryEntry(path)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, StoredReport{Path: path, Entry: entry})
+	}
+	return reports, nil
+}
+
+func DiffLatestReports(dir string) (DiffResult, error) {
+	reports, err := LoadHistory(dir)
+	if err != nil {
+		return DiffResult{}, err
+	}
+	if len(reports) < 2 {
+		return DiffResult{}, fmt.Errorf("%w: found %d in %s", ErrHistoryInsufficient, len(reports), strings.TrimSpace(dir))
+	}
+
+	result := DiffReports(reports[len(reports)-2], reports[len(reports)-1])
+	result.HistoryDir = strings.TrimSpace(dir)
+	return result, nil
+}
+
+func DiffReports(previous StoredReport, latest StoredReport) DiffResult {
+	previousReport := previous.Entry.Report
+	latestReport := latest.Entry.Report
+
+	result := DiffResult{
+		Previous:              newDiffRun(previous),
+		Latest:                newDiffRun(latest),
+		CapabilityRegressions: []CapabilityChange{},
+		StepRegressions:       []StepChange{},
+		Recoveries:            []StepChange{},
+	}
+	result.OutcomeChanged = result.Previous.Outcome != result.Latest.Outcome
+
+	previousCapabilities := make(map[string]string, len(previousReport.Capabilities))
+	for _, capability := range previousReport.Capabilities {
+		previousCapabilities[capability.Name] = capability.Status
+	}
+	for _, capability := range latestReport.Capabilities {
+		if previousCapabilities[capability.Name] != CapabilityStatusAvailable || capability.Status != CapabilityStatusUnavailable {
+			continue
+		}
+		result.CapabilityRegressions = append(result.CapabilityRegressions, CapabilityChange{
+			Name:     capability.Name,
+			Previous: CapabilityStatusAvailable,
+			Latest:   capability.Status,
+			Reason:   capability.Reason,
+		})
+	}
+
+	previousSteps := make(map[string]string, len(previousReport.Steps))
+	for _, step := range previousReport.Steps {
+		previousSteps[step.Name] = step.Status
+	}
+	for _, step := range latestReport.Steps {
+		previousStatus := previousSteps[step.Name]
+		switch {
+		case previousStatus == StepStatusExecuted && step.Status == StepStatusFailed:
+			result.StepRegressions = append(result.StepRegressions, StepChange{
+				Name:     step.Name,
+				Previous: previousStatus,
+				Latest:   step.Status,
+				Message:  step.Message,
+			})
+		case previousStatus == StepStatusFailed && step.Status == StepStatusExecuted:
+			result.Recoveries = append(result.Recoveries, StepChange{
+				Name:     step.Name,
+				Previous: previousStatus,
+				Latest:   step.Status,
+			})
+		}
+	}
+
+	result.Regression = DiffSummary{
+		Capability: len(result.CapabilityRegressions),
+		Steps:      len(result.StepRegressions),
+		Recovered:  len(result.Recoveries),
+	}
+	result.Regression.Regressed = result.Regression.Capability > 0 || result.Regression.Steps > 0
+	result.Regression.Summary = summarizeDiff(result)
+	return result
+}
+
+func DiffExitCode(result DiffResult) int {
+	if result.Regression.Regressed {
+		return ExitCodePolicy
+	}
+	return ExitCodeSuccess
+}
+
+func summarizeDiff(result DiffResult) string {
+	if !result.Regression.Regressed {
+		return fmt.Sprintf("no regressions (%d step(s) recovered)", result.Regression.Recovered)
+	}
+	parts := make([]string, 0, len(result.CapabilityRegressions)+len(result.StepRegressions))
+	for _, change := range result.CapabilityRegressions {
+		parts = append(parts, fmt.Sprintf("capability %s available->unavailable", change.Name))
+	}
+	for _, change := range result.StepRegressions {
+		parts = append(parts, fmt.Sprintf("step %s executed->failed", change.Name))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func newDiffRun(stored StoredReport) DiffRun {
+	return DiffRun{
+		Path:       stored.Path,
+		RecordedAt: stored.Entry.RecordedAt,
+		Outcome:    RunOutcomeForReport(stored.Entry.Report),
+		Summary:    stored.Entry.Report.Summary,
+	}
+}
+
+func historyKey(value string) string {
+	key := strings.Trim(historyKeyUnsafeRegex.ReplaceAllString(strings.TrimSpace(value), "_"), "._")
+	if key == "" {
+		return historyDefaultKey
+	}
+	return key
+}
+
+func loadHistoryEntry(path string) (HistoryEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return HistoryEntry{}, fmt.Errorf("read smoke history entry %s: %w", path, err)
+	}
+
+	var entry HistoryEntry
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&entry); err != nil {
+		return HistoryEntry{}, fmt.Errorf("decode smoke history entry %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return HistoryEntry{}, fmt.Errorf("decode smoke history entry %s: multiple JSON values", path)
+		}
+		return HistoryEntry{}, fmt.Errorf("decode smoke history entry %s: %w", path, err)
+	}
+	if entry.SchemaVersion != HistorySchemaVersion {
+		return HistoryEntry{}, fmt.Errorf(
+			"unsupported smoke history schema_version=%d in %s (expected %d)",
+			entry.SchemaVersion,
+			path,
+			HistorySchemaVersion,
+		)
+	}
+	return entry, nil
+}
@@ -0,0 +1,95 @@
+package smoke
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistoryDirKeysByProfileAndAccount(t *testing.T) {
+	t.Parallel()
+
+	dir, err := HistoryDir("/tmp/smoke", "prod/eu", "act_1234")
+	if err != nil {
+		t.Fatalf("resolve history dir: %v", err)
+	}
+	if want := filepath.Join("/tmp/smoke", "prod_eu", "act_1234"); dir != want {
+		t.Fatalf("unexpected history dir: got=%s want=%s", dir, want)
+	}
+	dir, err = HistoryDir("/tmp/smoke", "", "1234")
+	if err != nil {
+		t.Fatalf("resolve default history dir: %v", err)
+	}
+	if want := filepath.Join("/tmp/smoke", "default", "act_1234"); dir != want {
+		t.Fatalf("unexpected default history dir: got=%s want=%s", dir, want)
+	}
+	if _, err := HistoryDir("/tmp/smoke", "prod", ""); !errors.Is(err, ErrAccountIDMissing) {
+		t.Fatalf("expected missing account error, got %v", err)
+	}
+}
+
+func TestDiffLatestReportsDetectsRegressions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	recordedAt := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+	if _, err := DiffLatestReports(dir); !errors.Is(err, ErrHistoryInsufficient) {
+		t.Fatalf("expected insufficient history error, got %v", err)
+	}
+
+	previous := Report{
+		Outcome: RunOutcomeClean,
+		Capabilities: []CapabilityStatus{
+			{Name: capabilityAudience, Status: CapabilityStatusAvailable},
+			{Name: capabilityCatalog, Status: CapabilityStatusUnavailable},
+		},
+		Steps: []Step{
+			{Name: stepNameAccountContext, Status: StepStatusExecuted},
+			{Name: stepNameCampaignCreate, Status: StepStatusExecuted},
+			{Name: stepNameInsightsRead, Status: StepStatusFailed},
+		},
+	}
+	latest := Report{
+		Outcome: RunOutcomeBlocking,
+		Capabilities: []CapabilityStatus{
+			{Name: capabilityAudience, Status: CapabilityStatusUnavailable, Reason: "Permissions error"},
+			{Name: capabilityCatalog, Status: CapabilityStatusUnavailable},
+		},
+		Steps: []Step{
+			{Name: stepNameAccountContext, Status: StepStatusExecuted},
+			{Name: stepNameCampaignCreate, Status: StepStatusFailed, Message: "Invalid parameter"},
+			{Name: stepNameInsightsRead, Status: StepStatusExecuted},
+		},
+	}
+	if _, err := SaveHistoryEntry(dir, HistoryEntry{RecordedAt: recordedAt, Report: previous}); err != nil {
+		t.Fatalf("save previous entry: %v", err)
+	}
+	if _, err := SaveHistoryEntry(dir, HistoryEntry{RecordedAt: recordedAt.Add(time.Hour), Report: latest}); err != nil {
+		t.Fatalf("save latest entry: %v", err)
+	}
+
+	result, err := DiffLatestReports(dir)
+	if err != nil {
+		t.Fatalf("diff latest reports: %v", err)
+	}
+	if !result.OutcomeChanged || !result.Regression.Regressed {
+		t.Fatalf("expected regression, got %+v", result.Regression)
+	}
+	if len(result.CapabilityRegressions) != 1 || result.CapabilityRegressions[0].Name != capabilityAudience {
+		t.Fatalf("unexpected capability regressions: %+v", result.CapabilityRegressions)
+	}
+	if len(result.StepRegressions) != 1 || result.StepRegressions[0].Name != stepNameCampaignCreate {
+		t.Fatalf("unexpected step regressions: %+v", result.StepRegressions)
+	}
+	if len(result.Recoveries) != 1 || result.Recoveries[0].Name != stepNameInsightsRead {
+		t.Fatalf("unexpected recoveries: %+v", result.Recoveries)
+	}
+	if result.Regression.Summary != "capability audience available->unavailable; step campaign_create executed->failed" {
+		t.Fatalf("unexpected regression summary: %q", result.Regression.Summary)
+	}
+	if code := DiffExitCode(result); code != ExitCodePolicy {
+		t.Fatalf("unexpected exit code: got=%d want=%d", code, ExitCodePolicy)
+	}
+}
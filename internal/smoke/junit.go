@@ -0,0 +1,69 @@
+package smoke
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/ops"
+)
+
+func JUnitFromReport(report Report) ops.JUnitTestSuites {
+	suites := ops.JUnitTestSuites{Name: CommandRun}
+	suites.Add(junitSuiteForReport(report))
+	return suites
+}
+
+func JUnitFromMultiRun(result MultiRunResult) ops.JUnitTestSuites {
+	suites := ops.JUnitTestSuites{Name: CommandRun}
+	for _, account := range result.Accounts {
+		if account.Report != nil {
+			suites.Add(junitSuiteForReport(*account.Report))
+			continue
+		}
+		suite := ops.JUnitTestSuite{Name: junitSuiteName("act_"+account.AccountID, "")}
+		suite.Add(ops.JUnitTestCase{
+			Name:      "run",
+			ClassName: "smoke.act_" + account.AccountID,
+			Failure: &ops.JUnitFailure{
+				Type:    "runtime_error",
+				Message: account.Error,
+				Text:    account.Error,
+			},
+		})
+		suites.Add(suite)
+	}
+	return suites
+}
+
+func junitSuiteForReport(report Report) ops.JUnitTestSuite {
+	accountLabel := "act_" + report.Account.AccountID
+	suite := ops.JUnitTestSuite{Name: junitSuiteName(accountLabel, report.Scenario)}
+	className := "smoke." + accountLabel
+
+	for _, step := range report.Steps {
+		switch {
+		case step.Status == StepStatusExecuted:
+			suite.Add(ops.JUnitTestCase{Name: step.Name, ClassName: className, SystemOut: step.Message})
+		case step.Blocking || step.Warning:
+			suite.Add(ops.NewJUnitFindingCase(className, ops.Finding{Name: step.Name, Blocking: step.Blocking}, step.Message, report.ExitPolicy))
+		default:
+			suite.Add(ops.JUnitTestCase{Name: step.Name, ClassName: className, Skipped: &ops.JUnitSkipped{Message: step.Message}})
+		}
+	}
+	for _, result := range report.Cleanup.Resources {
+		if result.Status != CleanupStatusFailed {
+			continue
+		}
+		testCase := ops.NewJUnitFindingCase(className, ops.Finding{Name: stepNameCleanup}, result.Message, report.ExitPolicy)
+		testCase.Name = fmt.Sprintf("%s %s %s", stepNameCleanup, result.ResourceKind, result.ResourceID)
+		suite.Add(testCase)
+	}
+	return suite
+}
+
+func junitSuiteName(accountLabel string, scenario string) string {
+	if strings.TrimSpace(scenario) == "" {
+		return accountLabel
+	}
+	return fmt.Sprintf("%s (%s)", accountLabel, strings.TrimSpace(scenario))
+}
@@ -0,0 +1,67 @@
+package smoke
+
+import (
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/ops"
+)
+
+func TestJUnitFromReportMapsStepsToTestCases(t *testing.T) {
+	t.Parallel()
+
+	report := Report{
+		Account:  AccountContext{AccountID: "1234"},
+		Scenario: "nightly",
+		Steps: []Step{
+			{Name: stepNameAccountContext, Status: StepStatusExecuted, Message: "account context resolved"},
+			{Name: stepNameCampaignCreate, Status: StepStatusFailed, Blocking: true, Message: "Invalid parameter"},
+			{Name: stepNameCatalogUpload, Status: StepStatusSkipped, Warning: true, Message: "catalog id not provided"},
+			{Name: stepNameWhatsAppProbe, Status: StepStatusSkipped, Message: "phone number id not provided"},
+		},
+		Cleanup: CleanupReport{
+			Resources: []CleanupResourceResult{
+				{ResourceKind: "campaign", ResourceID: "c_1", Status: CleanupStatusApplied},
+				{ResourceKind: "audience", ResourceID: "aud_1", Status: CleanupStatusFailed, Message: "delete failed"},
+			},
+		},
+	}
+
+	suites := JUnitFromReport(report)
+	if len(suites.Suites) != 1 || suites.Suites[0].Name != "act_1234 (nightly)" {
+		t.Fatalf("unexpected suites: %+v", suites.Suites)
+	}
+	if suites.Tests != 5 || suites.Failures != 3 || suites.Skipped != 1 {
+		t.Fatalf("unexpected totals: tests=%d failures=%d skipped=%d", suites.Tests, suites.Failures, suites.Skipped)
+	}
+	cases := suites.Suites[0].Cases
+	if cases[1].Failure == nil || cases[1].Failure.Type != ops.FindingSeverityBlocking || cases[1].Failure.Message != "Invalid parameter" {
+		t.Fatalf("unexpected blocking case: %+v", cases[1])
+	}
+	if cases[2].Failure == nil || cases[2].Failure.Type != ops.FindingSeverityWarning {
+		t.Fatalf("unexpected warning case: %+v", cases[2])
+	}
+	if cases[3].Skipped == nil {
+		t.Fatalf("expected skipped case, got %+v", cases[3])
+	}
+	if cases[4].Name != "cleanup audience aud_1" || cases[4].Failure == nil {
+		t.Fatalf("unexpected cleanup case: %+v", cases[4])
+	}
+}
+
+func TestJUnitFromMultiRunReportsErroredAccounts(t *testing.T) {
+	t.Parallel()
+
+	suites := JUnitFromMultiRun(MultiRunResult{
+		Accounts: []AccountRunResult{
+			{AccountID: "1", Report: &Report{Account: AccountContext{AccountID: "1"}, Steps: []Step{{Name: stepNameAccountContext, Status: StepStatusExecuted}}}},
+			{AccountID: "2", ExitCode: ExitCodeRuntime, Error: "context deadline exceeded"},
+		},
+	})
+	if len(suites.Suites) != 2 || suites.Tests != 2 || suites.Failures != 1 {
+		t.Fatalf("unexpected multi-run junit: %+v", suites)
+	}
+	errored := suites.Suites[1].Cases[0]
+	if errored.Name != "run" || errored.Failure == nil || errored.Failure.Message != "context deadline exceeded" {
+		t.Fatalf("unexpected errored account case: %+v", errored)
+	}
+}
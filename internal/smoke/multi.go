@@ -0,0 +1,150 @@
+package smoke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/workqueue"
+)
+
+var ErrInvalidConcurrency = errors.New("invalid smoke concurrency")
+
+type MultiRunResult struct {
+	Concurrency int                `json:"concurrency"`
+	Summary     MultiRunSummary    `json:"summary"`
+	Accounts    []AccountRunResult `json:"accounts"`
+}
+
+type MultiRunSummary struct {
+	Accounts int    `json:"accounts"`
+	Clean    int    `json:"clean"`
+	Warning  int    `json:"warning"`
+	Blocking int    `json:"blocking"`
+	Errored  int    `json:"errored"`
+	Outcome  string `json:"outcome"`
+}
+
+type AccountRunResult struct {
+	AccountID   string  `json:"account_id"`
+	ExitCode    int     `json:"exit_code"`
+	Error       string  `json:"error,omitempty"`
+	HistoryPath string  `json:"history_path,omitempty"`
+	Report      *Report `json:"report,omitempty"`
+}
+
+func ParseAccountIDs(values []string) ([]string, error) {
+	accountIDs := make([]string, 0, len(values))
+	seen := map[string]struct{}{}
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			accountID, err := normalizeAdAccountID(part)
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := seen[accountID]; exists {
+				continue
+			}
+			seen[accountID] = struct{}{}
+			accountIDs = append(accountIDs, accountID)
+		}
+	}
+	if len(accountIDs) == 0 {
+		return nil, ErrAccountIDMissing
+	}
+	return accountIDs, nil
+}
+
+func (r *Runner) RunAccounts(ctx context.Context, input RunInput, accountIDs []string, concurrency int) (MultiRunResult, error) {
+	if r == nil || r.Client == nil {
+		return MultiRunResult{}, ErrClientRequired
+	}
+	if len(accountIDs) == 0 {
+		return MultiRunResult{}, ErrAccountIDMissing
+	}
+	if concurrency < 1 {
+		return MultiRunResult{}, fmt.Errorf("%w: concurrency must be >= 1, got %d", ErrInvalidConcurrency, concurrency)
+	}
+	if concurrency > len(accountIDs) {
+		concurrency = len(accountIDs)
+	}
+
+	results := make([]AccountRunResult, len(accountIDs))
+	for index, accountID := range accountIDs {
+		results[index] = AccountRunResult{AccountID: accountID}
+	}
+	_, err := workqueue.Run(ctx, len(accountIDs), workqueue.Options{
+		Concurrency: concurrency,
+		OnProgress:  r.OnProgress,
+	}, func(ctx context.Context, index int) error {
+		accountInput := input
+		accountInput.AccountID = accountIDs[index]
+		run, err := r.Run(ctx, accountInput)
+		if err != nil {
+			results[index].ExitCode = ExitCodeRuntime
+			results[index].Error = err.Error()
+			return err
+		}
+		report := run.Report
+		results[index].Report = &report
+		results[index].ExitCode = RunExitCode(report)
+		return nil
+	})
+	if err != nil {
+		// Accounts that never started are reported as errored rather than
+		// dropped, so the summary still covers every requested account.
+		for index := range results {
+			if results[index].Report == nil && results[index].Error == "" {
+				results[index].ExitCode = ExitCodeRuntime
+				results[index].Error = fmt.Sprintf("not run: %v", err)
+			}
+		}
+	}
+
+	result := MultiRunResult{
+		Concurrency: concurrency,
+		Accounts:    results,
+	}
+	result.Summary = summarizeMultiRun(results)
+	return result, nil
+}
+
+func MultiRunExitCode(result MultiRunResult) int {
+	switch result.Summary.Outcome {
+	case RunOutcomeBlocking:
+		return ExitCodePolicy
+	case RunOutcomeWarning:
+		return ExitCodeWarning
+	default:
+		return ExitCodeSuccess
+	}
+}
+
+func summarizeMultiRun(results []AccountRunResult) MultiRunSummary {
+	summary := MultiRunSummary{Accounts: len(results)}
+	for _, result := range results {
+		switch {
+		case result.Report == nil:
+			summary.Errored++
+		case result.ExitCode == ExitCodePolicy:
+			summary.Blocking++
+		case result.ExitCode == ExitCodeWarning:
+			summary.Warning++
+		default:
+			summary.Clean++
+		}
+	}
+	switch {
+	case summary.Blocking > 0 || summary.Errored > 0:
+		summary.Outcome = RunOutcomeBlocking
+	case summary.Warning > 0:
+		summary.Outcome = RunOutcomeWarning
+	default:
+		summary.Outcome = RunOutcomeClean
+	}
+	return summary
+}
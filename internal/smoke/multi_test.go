@@ -0,0 +1,110 @@
+package smoke
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+type routedGraphClient struct {
+	mu        sync.Mutex
+	responses map[string]*graph.Response
+	errors    map[string]error
+	requests  []string
+}
+
+func (c *routedGraphClient) Do(_ context.Context, req graph.Request) (*graph.Response, error) {
+	key := req.Method + " " + req.Path
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, key)
+	if err, ok := c.errors[key]; ok {
+		return nil, err
+	}
+	if response, ok := c.responses[key]; ok {
+		return response, nil
+	}
+	return nil, &graph.APIError{Type: "OAuthException", Code: 100, StatusCode: http.StatusBadRequest, Message: "unexpected request " + key}
+}
+
+func TestParseAccountIDsNormalizesAndDeduplicates(t *testing.T) {
+	t.Parallel()
+
+	accountIDs, err := ParseAccountIDs([]string{"act_1, 2", "act_2", "3"})
+	if err != nil {
+		t.Fatalf("parse account ids: %v", err)
+	}
+	if len(accountIDs) != 3 || accountIDs[0] != "1" || accountIDs[1] != "2" || accountIDs[2] != "3" {
+		t.Fatalf("unexpected account ids: %v", accountIDs)
+	}
+	if _, err := ParseAccountIDs([]string{" , "}); !errors.Is(err, ErrAccountIDMissing) {
+		t.Fatalf("expected missing account error, got %v", err)
+	}
+	if _, err := ParseAccountIDs([]string{"act_12x"}); err == nil {
+		t.Fatal("expected invalid account id error")
+	}
+}
+
+func TestRunnerRunAccountsIsolatesReportsAndCombinesSummary(t *testing.T) {
+	scenario := &Scenario{
+		SchemaVersion: ScenarioSchemaVersion,
+		Name:          "list",
+		Steps: []ScenarioStep{
+			{Name: "campaign_list", Method: http.MethodGet, Path: "act_{{.account_id}}/campaigns"},
+		},
+	}
+	client := &routedGraphClient{
+		responses: map[string]*graph.Response{
+			"GET act_1":           {Body: map[string]any{"id": "act_1", "account_status": float64(1)}},
+			"GET act_1/campaigns": {Body: map[string]any{"data": []any{}}},
+			"GET act_3":           {Body: map[string]any{"id": "act_3", "account_status": float64(1)}},
+			"GET act_3/campaigns": {Body: map[string]any{"data": []any{}}},
+		},
+		errors: map[string]error{
+			"GET act_2": &graph.APIError{Type: "OAuthException", Code: 190, StatusCode: http.StatusUnauthorized, Message: "Invalid OAuth access token"},
+		},
+	}
+
+	result, err := NewRunner(client).RunAccounts(context.Background(), RunInput{
+		Version:        "v25.0",
+		Token:          "token",
+		OptionalPolicy: OptionalPolicySkip,
+		Scenario:       scenario,
+	}, []string{"1", "2", "3"}, 8)
+	if err != nil {
+		t.Fatalf("run accounts: %v", err)
+	}
+
+	if result.Concurrency != 3 {
+		t.Fatalf("expected concurrency to be capped at account count, got %d", result.Concurrency)
+	}
+	if len(result.Accounts) != 3 {
+		t.Fatalf("unexpected account results: %+v", result.Accounts)
+	}
+	for index, accountID := range []string{"1", "2", "3"} {
+		account := result.Accounts[index]
+		if account.AccountID != accountID || account.Report == nil || account.Report.Account.AccountID != accountID {
+			t.Fatalf("unexpected account result at %d: %+v", index, account)
+		}
+	}
+	if result.Accounts[1].ExitCode != ExitCodePolicy || result.Accounts[0].ExitCode != ExitCodeSuccess {
+		t.Fatalf("unexpected per-account exit codes: %+v", result.Accounts)
+	}
+	if result.Summary.Clean != 2 || result.Summary.Blocking != 1 || result.Summary.Outcome != RunOutcomeBlocking {
+		t.Fatalf("unexpected combined summary: %+v", result.Summary)
+	}
+	if code := MultiRunExitCode(result); code != ExitCodePolicy {
+		t.Fatalf("unexpected exit code: got=%d want=%d", code, ExitCodePolicy)
+	}
+	if len(client.requests) != 5 {
+		t.Fatalf("unexpected request count: %v", client.requests)
+	}
+
+	if _, err := NewRunner(client).RunAccounts(context.Background(), RunInput{}, []string{"1"}, 0); !errors.Is(err, ErrInvalidConcurrency) {
+		t.Fatalf("expected invalid concurrency error, got %v", err)
+	}
+}
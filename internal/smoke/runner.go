@@ -11,6 +11,7 @@ import (
 
 	"github.com/bilalbayram/metacli/internal/graph"
 	"github.com/bilalbayram/metacli/internal/ops"
+	"github.com/bilalbayram/metacli/internal/workqueue"
 )
 
 const (
@@ -41,9 +42,25 @@ const (
 	stepNameCampaignCreate = "campaign_create"
 	stepNameAudienceCreate = "audience_create"
 	stepNameCatalogUpload  = "catalog_upload"
-
-	capabilityAudience = "audience"
-	capabilityCatalog  = "catalog"
+	stepNameAdSetCreate    = "adset_create"
+	stepNameCreativeCreate = "creative_create"
+	stepNameAdCreate       = "ad_create"
+	stepNameInsightsRead   = "insights_read"
+	stepNamePageProbe      = "page_publishing_probe"
+	stepNameMessengerProbe = "messenger_probe"
+	stepNameLeadsProbe     = "lead_retrieval_probe"
+	stepNameWhatsAppProbe  = "whatsapp_messaging_probe"
+
+	capabilityAudience  = "audience"
+	capabilityCatalog   = "catalog"
+	capabilityAdSet     = "adset"
+	capabilityCreative  = "creative"
+	capabilityAd        = "ad"
+	capabilityInsights  = "insights"
+	capabilityPage      = "page_publishing"
+	capabilityMessenger = "messenger"
+	capabilityLeads     = "lead_retrieval"
+	capabilityWhatsApp  = "whatsapp_messaging"
 )
 
 var (
@@ -59,36 +76,49 @@ type GraphClient interface {
 
 type Runner struct {
 	Client GraphClient
+	// OnProgress, when set, is called as each account of RunAccounts finishes.
+	OnProgress func(workqueue.Progress)
 }
 
 type RunInput struct {
-	ProfileName    string
-	Version        string
-	AccountID      string
-	Token          string
-	AppSecret      string
-	OptionalPolicy string
-	CatalogID      string
+	ProfileName           string
+	Version               string
+	AccountID             string
+	Token                 string
+	AppSecret             string
+	OptionalPolicy        string
+	CatalogID             string
+	PageID                string
+	WhatsAppPhoneNumberID string
+	RequireSandbox        bool
+	CleanupPolicy         string
+	Scenario              *Scenario
+	ExitPolicy            *ops.ExitPolicy
 }
 
 type RunResult struct {
-	Report Report `json:"report"`
+	HistoryPath string `json:"history_path,omitempty"`
+	Report      Report `json:"report"`
 }
 
 type Report struct {
-	SchemaVersion    int                `json:"schema_version"`
-	Kind             string             `json:"kind"`
-	ProfileName      string             `json:"profile_name"`
-	GraphVersion     string             `json:"graph_version"`
-	OptionalPolicy   string             `json:"optional_policy"`
-	Account          AccountContext     `json:"account"`
-	Summary          Summary            `json:"summary"`
-	Outcome          string             `json:"outcome"`
-	Capabilities     []CapabilityStatus `json:"capabilities"`
-	Steps            []Step             `json:"steps"`
-	CreatedResources []CreatedResource  `json:"created_resources"`
-	Failures         []Failure          `json:"failures"`
-	RateLimit        RateLimitReport    `json:"rate_limit"`
+	SchemaVersion    int                       `json:"schema_version"`
+	Kind             string                    `json:"kind"`
+	ProfileName      string                    `json:"profile_name"`
+	GraphVersion     string                    `json:"graph_version"`
+	OptionalPolicy   string                    `json:"optional_policy"`
+	Scenario         string                    `json:"scenario,omitempty"`
+	Account          AccountContext            `json:"account"`
+	Sandbox          SandboxCheck              `json:"sandbox"`
+	Summary          Summary                   `json:"summary"`
+	Outcome          string                    `json:"outcome"`
+	Capabilities     []CapabilityStatus        `json:"capabilities"`
+	Steps            []Step                    `json:"steps"`
+	CreatedResources []CreatedResource         `json:"created_resources"`
+	Failures         []Failure                 `json:"failures"`
+	RateLimit        RateLimitReport           `json:"rate_limit"`
+	Cleanup          CleanupReport             `json:"cleanup"`
+	ExitPolicy       *ops.ExitPolicyEvaluation `json:"exit_policy,omitempty"`
 }
 
 type AccountContext struct {
@@ -190,6 +220,11 @@ func (r *Runner) Run(ctx context.Context, input RunInput) (RunResult, error) {
 		return RunResult{}, fmt.Errorf("%w: %q", ErrInvalidOptionalPolicy, input.OptionalPolicy)
 	}
 
+	cleanupPolicy := NormalizeCleanupPolicy(input.CleanupPolicy)
+	if cleanupPolicy == "" {
+		return RunResult{}, fmt.Errorf("%w: %q", ErrInvalidCleanupPolicy, input.CleanupPolicy)
+	}
+
 	version := strings.TrimSpace(input.Version)
 	if version == "" {
 		return RunResult{}, ErrVersionRequired
@@ -202,6 +237,16 @@ func (r *Runner) Run(ctx context.Context, input RunInput) (RunResult, error) {
 	if err != nil {
 		return RunResult{}, err
 	}
+	if input.ExitPolicy != nil {
+		if err := input.ExitPolicy.Validate(); err != nil {
+			return RunResult{}, err
+		}
+	}
+	if input.Scenario != nil {
+		if err := input.Scenario.Validate(); err != nil {
+			return RunResult{}, err
+		}
+	}
 
 	report := Report{
 		SchemaVersion:  ReportSchemaVersion,
@@ -213,6 +258,10 @@ func (r *Runner) Run(ctx context.Context, input RunInput) (RunResult, error) {
 			InputAccountID: strings.TrimSpace(input.AccountID),
 			AccountID:      accountID,
 		},
+		Sandbox: SandboxCheck{
+			Required:   input.RequireSandbox,
+			Indicators: []string{},
+		},
 		Capabilities: []CapabilityStatus{
 			{
 				Name:     capabilityAudience,
@@ -226,15 +275,75 @@ func (r *Runner) Run(ctx context.Context, input RunInput) (RunResult, error) {
 				Status:   CapabilityStatusNotEvaluated,
 				Policy:   normalizedPolicy,
 			},
+			{
+				Name:     capabilityAdSet,
+				Optional: true,
+				Status:   CapabilityStatusNotEvaluated,
+				Policy:   normalizedPolicy,
+			},
+			{
+				Name:     capabilityCreative,
+				Optional: true,
+				Status:   CapabilityStatusNotEvaluated,
+				Policy:   normalizedPolicy,
+			},
+			{
+				Name:     capabilityAd,
+				Optional: true,
+				Status:   CapabilityStatusNotEvaluated,
+				Policy:   normalizedPolicy,
+			},
+			{
+				Name:     capabilityInsights,
+				Optional: true,
+				Status:   CapabilityStatusNotEvaluated,
+				Policy:   normalizedPolicy,
+			},
+			{
+				Name:     capabilityPage,
+				Optional: true,
+				Status:   CapabilityStatusNotEvaluated,
+				Policy:   normalizedPolicy,
+			},
+			{
+				Name:     capabilityMessenger,
+				Optional: true,
+				Status:   CapabilityStatusNotEvaluated,
+				Policy:   normalizedPolicy,
+			},
+			{
+				Name:     capabilityLeads,
+				Optional: true,
+				Status:   CapabilityStatusNotEvaluated,
+				Policy:   normalizedPolicy,
+			},
+			{
+				Name:     capabilityWhatsApp,
+				Optional: true,
+				Status:   CapabilityStatusNotEvaluated,
+				Policy:   normalizedPolicy,
+			},
 		},
-		Steps:            make([]Step, 0, 4),
+		Steps:            make([]Step, 0, 12),
 		CreatedResources: []CreatedResource{},
 		Failures:         []Failure{},
 	}
 
 	capabilityIndex := map[string]int{
-		capabilityAudience: 0,
-		capabilityCatalog:  1,
+		capabilityAudience:  0,
+		capabilityCatalog:   1,
+		capabilityAdSet:     2,
+		capabilityCreative:  3,
+		capabilityAd:        4,
+		capabilityInsights:  5,
+		capabilityPage:      6,
+		capabilityMessenger: 7,
+		capabilityLeads:     8,
+		capabilityWhatsApp:  9,
+	}
+	if input.Scenario != nil {
+		report.Scenario = strings.TrimSpace(input.Scenario.Name)
+		report.Capabilities, capabilityIndex = scenarioCapabilities(input.Scenario, normalizedPolicy)
 	}
 
 	setCapability := func(name string, status string, reason string) {
@@ -332,6 +441,110 @@ func (r *Runner) Run(ctx context.Context, input RunInput) (RunResult, error) {
 		})
 	}
 
+	createOptionalResource := func(stepName string, capability string, request graph.Request, resourceKind string, cleanupAction string) string {
+		step := Step{
+			Name:       stepName,
+			Optional:   true,
+			Capability: capability,
+		}
+		request.Version = version
+		request.AccessToken = token
+		request.AppSecret = input.AppSecret
+		response, err := r.Client.Do(ctx, request)
+		if err != nil {
+			if reason, unavailable := classifyOptionalCapabilityUnavailable(err); unavailable {
+				handleOptionalUnavailable(stepName, capability, reason)
+				return ""
+			}
+			setCapability(capability, CapabilityStatusAvailable, "")
+			step.Status = StepStatusFailed
+			step.Blocking = true
+			step.Message = err.Error()
+			appendStep(step)
+			appendFailureFromError(stepName, true, true, err)
+			blocked = true
+			blockReason = step.Message
+			return ""
+		}
+
+		resourceID, _ := response.Body["id"].(string)
+		resourceID = strings.TrimSpace(resourceID)
+		if resourceID == "" {
+			step.Status = StepStatusFailed
+			step.Blocking = true
+			step.Message = fmt.Sprintf("%s create response did not include id", resourceKind)
+			appendStep(step)
+			appendFailure(stepName, true, true, "runtime_error", step.Message)
+			blocked = true
+			blockReason = step.Message
+			return ""
+		}
+		setCapability(capability, CapabilityStatusAvailable, "")
+		step.Status = StepStatusExecuted
+		if metadata := rateLimitMetadataFromGraph(response.RateLimit); metadata != nil {
+			step.RateLimit = metadata
+		}
+		step.Message = fmt.Sprintf("%s created: %s_id=%s", resourceKind, resourceKind, resourceID)
+		appendStep(step)
+		appendCreatedResource(resourceKind, resourceID, cleanupAction, stepName)
+		return resourceID
+	}
+
+	runCapabilityProbe := func(stepName string, capability string, targetFlag string, target string, request graph.Request, evaluate func(map[string]any) (string, string)) {
+		if blocked {
+			appendBlockedStep(stepName, true, capability)
+			return
+		}
+		if strings.TrimSpace(target) == "" {
+			appendStep(Step{
+				Name:       stepName,
+				Optional:   true,
+				Capability: capability,
+				Status:     StepStatusSkipped,
+				Message:    fmt.Sprintf("probe not requested: %s is not set", targetFlag),
+			})
+			return
+		}
+
+		step := Step{
+			Name:       stepName,
+			Optional:   true,
+			Capability: capability,
+		}
+		request.Version = version
+		request.AccessToken = token
+		request.AppSecret = input.AppSecret
+		response, err := r.Client.Do(ctx, request)
+		if err != nil {
+			if reason, unavailable := classifyOptionalCapabilityUnavailable(err); unavailable {
+				handleOptionalUnavailable(stepName, capability, reason)
+				return
+			}
+			setCapability(capability, CapabilityStatusAvailable, "")
+			step.Status = StepStatusFailed
+			step.Blocking = true
+			step.Message = err.Error()
+			appendStep(step)
+			appendFailureFromError(stepName, true, true, err)
+			blocked = true
+			blockReason = step.Message
+			return
+		}
+
+		message, unavailableReason := evaluate(response.Body)
+		if unavailableReason != "" {
+			handleOptionalUnavailable(stepName, capability, unavailableReason)
+			return
+		}
+		setCapability(capability, CapabilityStatusAvailable, "")
+		step.Status = StepStatusExecuted
+		if metadata := rateLimitMetadataFromGraph(response.RateLimit); metadata != nil {
+			step.RateLimit = metadata
+		}
+		step.Message = message
+		appendStep(step)
+	}
+
 	{
 		step := Step{
 			Name:     stepNameAccountContext,
@@ -342,7 +555,7 @@ func (r *Runner) Run(ctx context.Context, input RunInput) (RunResult, error) {
 			Path:    fmt.Sprintf("act_%s", accountID),
 			Version: version,
 			Query: map[string]string{
-				"fields": "id,name,account_status,currency",
+				"fields": "id,name,account_status,currency,business{name}",
 			},
 			AccessToken: token,
 			AppSecret:   input.AppSecret,
@@ -380,10 +593,113 @@ func (r *Runner) Run(ctx context.Context, input RunInput) (RunResult, error) {
 				report.Account.Currency,
 				report.Account.AccountStatus,
 			)
+			report.Sandbox.Indicators = assessSandbox(response.Body)
+			report.Sandbox.Detected = len(report.Sandbox.Indicators) > 0
+			if input.RequireSandbox && !report.Sandbox.Detected {
+				report.Sandbox.Enforced = true
+				report.Sandbox.Reason = sandboxRefusalMessage(report.Account.AccountID)
+				step.Status = StepStatusFailed
+				step.Blocking = true
+				step.Message = report.Sandbox.Reason
+				appendStep(step)
+				appendFailure(stepNameAccountContext, false, true, "sandbox_required", step.Message)
+				blocked = true
+				blockReason = step.Message
+			} else {
+				appendStep(step)
+			}
+		}
+	}
+
+	if input.Scenario != nil {
+		variables := map[string]string{
+			"account_id":    accountID,
+			"catalog_id":    strings.TrimSpace(input.CatalogID),
+			"graph_version": version,
+		}
+		for _, definition := range input.Scenario.Steps {
+			if blocked {
+				appendBlockedStep(definition.Name, definition.Optional, definition.Capability)
+				continue
+			}
+			step := Step{
+				Name:       definition.Name,
+				Optional:   definition.Optional,
+				Capability: definition.Capability,
+			}
+
+			request, err := definition.buildRequest(variables)
+			if err != nil {
+				if definition.Optional {
+					handleOptionalUnavailable(definition.Name, definition.Capability, fmt.Sprintf("step prerequisites unavailable: %v", err))
+					continue
+				}
+				step.Status = StepStatusFailed
+				step.Blocking = true
+				step.Message = err.Error()
+				appendStep(step)
+				appendFailure(definition.Name, false, true, "scenario_error", step.Message)
+				blocked = true
+				blockReason = step.Message
+				continue
+			}
+			request.Version = version
+			request.AccessToken = token
+			request.AppSecret = input.AppSecret
+
+			response, err := r.Client.Do(ctx, request)
+			if err != nil {
+				if definition.Optional {
+					if reason, unavailable := classifyOptionalCapabilityUnavailable(err); unavailable {
+						handleOptionalUnavailable(definition.Name, definition.Capability, reason)
+						continue
+					}
+					setCapability(definition.Capability, CapabilityStatusAvailable, "")
+				}
+				step.Status = StepStatusFailed
+				step.Blocking = true
+				step.Message = err.Error()
+				appendStep(step)
+				appendFailureFromError(definition.Name, definition.Optional, true, err)
+				blocked = true
+				blockReason = step.Message
+				continue
+			}
+
+			captured, resourceID, err := definition.extract(response.Body)
+			if err != nil {
+				step.Status = StepStatusFailed
+				step.Blocking = true
+				step.Message = err.Error()
+				appendStep(step)
+				appendFailure(definition.Name, definition.Optional, true, "runtime_error", step.Message)
+				blocked = true
+				blockReason = step.Message
+				continue
+			}
+			if definition.Optional {
+				setCapability(definition.Capability, CapabilityStatusAvailable, "")
+			}
+			step.Status = StepStatusExecuted
+			if metadata := rateLimitMetadataFromGraph(response.RateLimit); metadata != nil {
+				step.RateLimit = metadata
+			}
+			step.Message = fmt.Sprintf("scenario step executed: %s %s", request.Method, request.Path)
+			if resourceID != "" {
+				step.Message = fmt.Sprintf("%s resource_id=%s", step.Message, resourceID)
+			}
 			appendStep(step)
+			for variable, value := range captured {
+				variables[variable] = value
+			}
+			if definition.Track != nil {
+				appendCreatedResource(strings.TrimSpace(definition.Track.ResourceKind), resourceID, strings.TrimSpace(definition.Track.CleanupAction), definition.Name)
+			}
 		}
+		return r.completeRun(ctx, &report, input, cleanupPolicy, version, token), nil
 	}
 
+	campaignID := ""
 	if blocked {
 		appendBlockedStep(stepNameCampaignCreate, false, "")
 	} else {
@@ -414,7 +730,7 @@ func (r *Runner) Run(ctx context.Context, input RunInput) (RunResult, error) {
 			blocked = true
 			blockReason = step.Message
 		} else {
-			campaignID, _ := response.Body["id"].(string)
+			campaignID, _ = response.Body["id"].(string)
 			campaignID = strings.TrimSpace(campaignID)
 			if campaignID == "" {
 				step.Status = StepStatusFailed
@@ -582,8 +898,182 @@ func (r *Runner) Run(ctx context.Context, input RunInput) (RunResult, error) {
 		}
 	}
 
-	finalizeReport(&report)
-	return RunResult{Report: report}, nil
+	adSetID := ""
+	if blocked {
+		appendBlockedStep(stepNameAdSetCreate, true, capabilityAdSet)
+	} else {
+		adSetID = createOptionalResource(stepNameAdSetCreate, capabilityAdSet, graph.Request{
+			Method: http.MethodPost,
+			Path:   fmt.Sprintf("act_%s/adsets", accountID),
+			Form: map[string]string{
+				"name":              "CLI_SmokeV2_AdSet",
+				"campaign_id":       campaignID,
+				"status":            "PAUSED",
+				"daily_budget":      "100",
+				"billing_event":     "IMPRESSIONS",
+				"optimization_goal": "LINK_CLICKS",
+				"bid_strategy":      "LOWEST_COST_WITHOUT_CAP",
+				"targeting":         `{"geo_locations":{"countries":["US"]}}`,
+			},
+		}, ops.ResourceKindAdSet, ops.CleanupActionPause)
+	}
+
+	creativeID := ""
+	if blocked {
+		appendBlockedStep(stepNameCreativeCreate, true, capabilityCreative)
+	} else {
+		trimmedPageID := strings.TrimSpace(input.PageID)
+		if trimmedPageID == "" {
+			handleOptionalUnavailable(stepNameCreativeCreate, capabilityCreative, "page_id is required for creative optional module")
+		} else {
+			storySpec, err := json.Marshal(map[string]any{
+				"page_id": trimmedPageID,
+				"link_data": map[string]any{
+					"link":    "https://example.com/cli-smoke-v2",
+					"message": "Smoke runner v2 creative check",
+				},
+			})
+			if err != nil {
+				message := fmt.Sprintf("encode creative object_story_spec: %v", err)
+				appendStep(Step{
+					Name:       stepNameCreativeCreate,
+					Optional:   true,
+					Capability: capabilityCreative,
+					Status:     StepStatusFailed,
+					Blocking:   true,
+					Message:    message,
+				})
+				appendFailure(stepNameCreativeCreate, true, true, "runtime_error", message)
+				blocked = true
+				blockReason = message
+			} else {
+				creativeID = createOptionalResource(stepNameCreativeCreate, capabilityCreative, graph.Request{
+					Method: http.MethodPost,
+					Path:   fmt.Sprintf("act_%s/adcreatives", accountID),
+					Form: map[string]string{
+						"name":              "CLI_SmokeV2_Creative",
+						"object_story_spec": string(storySpec),
+					},
+				}, ops.ResourceKindCreative, ops.CleanupActionDelete)
+			}
+		}
+	}
+
+	if blocked {
+		appendBlockedStep(stepNameAdCreate, true, capabilityAd)
+	} else if adSetID == "" || creativeID == "" {
+		handleOptionalUnavailable(stepNameAdCreate, capabilityAd, "ad requires adset and creative from earlier smoke steps")
+	} else {
+		createOptionalResource(stepNameAdCreate, capabilityAd, graph.Request{
+			Method: http.MethodPost,
+			Path:   fmt.Sprintf("act_%s/ads", accountID),
+			Form: map[string]string{
+				"name":     "CLI_SmokeV2_Ad",
+				"adset_id": adSetID,
+				"creative": fmt.Sprintf(`{"creative_id":"%s"}`, creativeID),
+				"status":   "PAUSED",
+			},
+		}, ops.ResourceKindAd, ops.CleanupActionPause)
+	}
+
+	if blocked {
+		appendBlockedStep(stepNameInsightsRead, true, capabilityInsights)
+	} else {
+		step := Step{
+			Name:       stepNameInsightsRead,
+			Optional:   true,
+			Capability: capabilityInsights,
+		}
+		response, err := r.Client.Do(ctx, graph.Request{
+			Method:  http.MethodGet,
+			Path:    fmt.Sprintf("%s/insights", campaignID),
+			Version: version,
+			Query: map[string]string{
+				"fields":      "impressions,clicks,spend",
+				"date_preset": "last_7d",
+			},
+			AccessToken: token,
+			AppSecret:   input.AppSecret,
+		})
+		if err != nil {
+			if reason, unavailable := classifyOptionalCapabilityUnavailable(err); unavailable {
+				handleOptionalUnavailable(stepNameInsightsRead, capabilityInsights, reason)
+			} else {
+				setCapability(capabilityInsights, CapabilityStatusAvailable, "")
+				step.Status = StepStatusFailed
+				step.Blocking = true
+				step.Message = err.Error()
+				appendStep(step)
+				appendFailureFromError(stepNameInsightsRead, true, true, err)
+				blocked = true
+				blockReason = step.Message
+			}
+		} else {
+			rows, _ := response.Body["data"].([]any)
+			setCapability(capabilityInsights, CapabilityStatusAvailable, "")
+			step.Status = StepStatusExecuted
+			if metadata := rateLimitMetadataFromGraph(response.RateLimit); metadata != nil {
+				step.RateLimit = metadata
+			}
+			step.Message = fmt.Sprintf("insights read returned %d row(s): campaign_id=%s", len(rows), campaignID)
+			appendStep(step)
+		}
+	}
+
+	pageID := strings.TrimSpace(input.PageID)
+	runCapabilityProbe(stepNamePageProbe, capabilityPage, "page_id", pageID, graph.Request{
+		Method: http.MethodGet,
+		Path:   pageID,
+		Query: map[string]string{
+			"fields": "id,name,tasks",
+		},
+	}, func(body map[string]any) (string, string) {
+		if tasks, ok := body["tasks"].([]any); ok && !containsString(tasks, "CREATE_CONTENT") {
+			return "", "token lacks CREATE_CONTENT task on page " + pageID
+		}
+		return fmt.Sprintf("page publishing available: page_id=%s", pageID), ""
+	})
+	runCapabilityProbe(stepNameMessengerProbe, capabilityMessenger, "page_id", pageID, graph.Request{
+		Method: http.MethodGet,
+		Path:   fmt.Sprintf("%s/conversations", pageID),
+		Query: map[string]string{
+			"platform": "messenger",
+			"limit":    "1",
+		},
+	}, func(map[string]any) (string, string) {
+		return fmt.Sprintf("messenger conversations readable: page_id=%s", pageID), ""
+	})
+	runCapabilityProbe(stepNameLeadsProbe, capabilityLeads, "page_id", pageID, graph.Request{
+		Method: http.MethodGet,
+		Path:   fmt.Sprintf("%s/leadgen_forms", pageID),
+		Query: map[string]string{
+			"fields": "id,status",
+			"limit":  "1",
+		},
+	}, func(map[string]any) (string, string) {
+		return fmt.Sprintf("lead forms readable: page_id=%s", pageID), ""
+	})
+	phoneNumberID := strings.TrimSpace(input.WhatsAppPhoneNumberID)
+	runCapabilityProbe(stepNameWhatsAppProbe, capabilityWhatsApp, "whatsapp_phone_number_id", phoneNumberID, graph.Request{
+		Method: http.MethodGet,
+		Path:   phoneNumberID,
+		Query: map[string]string{
+			"fields": "id,display_phone_number,quality_rating",
+		},
+	}, func(body map[string]any) (string, string) {
+		quality, _ := body["quality_rating"].(string)
+		return fmt.Sprintf("whatsapp phone number readable: phone_number_id=%s quality_rating=%s", phoneNumberID, strings.TrimSpace(quality)), ""
+	})
+
+	return r.completeRun(ctx, &report, input, cleanupPolicy, version, token), nil
+}
+
+func (r *Runner) completeRun(ctx context.Context, report *Report, input RunInput, cleanupPolicy string, version string, token string) RunResult {
+	finalizeReport(report)
+	runCleanupPhase(ctx, ops.NewGraphCleanupExecutor(r.Client), report, cleanupPolicy, version, token, input.AppSecret)
+	finalizeReport(report)
+	applyExitPolicy(report, input.ExitPolicy)
+	return RunResult{Report: *report}
 }
 
 func RunOutcomeForReport(report Report) string {
@@ -594,6 +1084,9 @@ func RunOutcomeForReport(report Report) string {
 }
 
 func RunExitCode(report Report) int {
+	if report.ExitPolicy != nil {
+		return report.ExitPolicy.ExitCode
+	}
 	switch RunOutcomeForReport(report) {
 	case RunOutcomeBlocking:
 		return ExitCodePolicy
@@ -604,6 +1097,26 @@ func RunExitCode(report Report) int {
 	}
 }
 
+func applyExitPolicy(report *Report, policy *ops.ExitPolicy) {
+	if report == nil || policy == nil {
+		return
+	}
+	findings := make([]ops.Finding, 0, len(report.Steps))
+	for _, step := range report.Steps {
+		if !step.Blocking && !step.Warning {
+			continue
+		}
+		findings = append(findings, ops.Finding{Name: step.Name, Blocking: step.Blocking})
+	}
+	for _, result := range report.Cleanup.Resources {
+		if result.Status == CleanupStatusFailed {
+			findings = append(findings, ops.Finding{Name: stepNameCleanup})
+		}
+	}
+	evaluation := policy.Evaluate(findings)
+	report.ExitPolicy = &evaluation
+}
+
 func finalizeReport(report *Report) {
 	if report == nil {
 		return
@@ -630,6 +1143,7 @@ func finalizeReport(report *Report) {
 			summary.Blocking++
 		}
 	}
+	summary.Warnings += report.Cleanup.Summary.Failed
 	report.Summary = summary
 	report.Outcome = summarizeOutcome(summary)
 }
@@ -864,6 +1378,15 @@ func cloneAny(value any) any {
 	}
 }
 
+func containsString(values []any, want string) bool {
+	for _, value := range values {
+		if typed, ok := value.(string); ok && strings.EqualFold(strings.TrimSpace(typed), want) {
+			return true
+		}
+	}
+	return false
+}
+
 func intFromAny(value any) int {
 	switch typed := value.(type) {
 	case float64:
@@ -142,15 +142,20 @@ func TestRunnerStrictPolicyBlocksWhenCatalogIsUnavailable(t *testing.T) {
 	if report.RateLimit.MaxAppCallCount != 19 {
 		t.Fatalf("unexpected max app call count: %d", report.RateLimit.MaxAppCallCount)
 	}
-	if len(report.Steps) != 4 {
+	if len(report.Steps) != 12 {
 		t.Fatalf("unexpected step count: %d", len(report.Steps))
 	}
-	lastStep := report.Steps[3]
-	if lastStep.Name != stepNameCatalogUpload {
-		t.Fatalf("unexpected last step name: %s", lastStep.Name)
+	catalogStep := report.Steps[3]
+	if catalogStep.Name != stepNameCatalogUpload {
+		t.Fatalf("unexpected catalog step name: %s", catalogStep.Name)
 	}
-	if lastStep.Status != StepStatusFailed || !lastStep.Blocking {
-		t.Fatalf("expected blocking catalog failure step, got %+v", lastStep)
+	if catalogStep.Status != StepStatusFailed || !catalogStep.Blocking {
+		t.Fatalf("expected blocking catalog failure step, got %+v", catalogStep)
+	}
+	for index := 4; index < len(report.Steps); index++ {
+		if report.Steps[index].Status != StepStatusSkipped || report.Steps[index].Warning {
+			t.Fatalf("expected blocked step at index %d to be skipped, got %+v", index, report.Steps[index])
+		}
 	}
 	if report.Capabilities[1].Name != capabilityCatalog || report.Capabilities[1].Status != CapabilityStatusUnavailable {
 		t.Fatalf("unexpected catalog capability state: %+v", report.Capabilities[1])
@@ -192,6 +197,24 @@ func TestRunnerSkipPolicySkipsUnavailableOptionalStepsAsWarnings(t *testing.T) {
 					Message:    "Permissions error",
 				},
 			},
+			{
+				Method: http.MethodPost,
+				Path:   "act_1234/adsets",
+				Response: &graph.Response{
+					Body: map[string]any{
+						"id": "ads_3001",
+					},
+				},
+			},
+			{
+				Method: http.MethodGet,
+				Path:   "cmp_1001/insights",
+				Response: &graph.Response{
+					Body: map[string]any{
+						"data": []any{},
+					},
+				},
+			},
 		},
 	}
 
@@ -212,7 +235,7 @@ func TestRunnerSkipPolicySkipsUnavailableOptionalStepsAsWarnings(t *testing.T) {
 	if report.Outcome != RunOutcomeWarning {
 		t.Fatalf("unexpected outcome: %s", report.Outcome)
 	}
-	if report.Summary.Warnings != 2 {
+	if report.Summary.Warnings != 4 {
 		t.Fatalf("unexpected warning summary: %+v", report.Summary)
 	}
 	if report.Summary.Blocking != 0 {
@@ -224,8 +247,8 @@ func TestRunnerSkipPolicySkipsUnavailableOptionalStepsAsWarnings(t *testing.T) {
 	if len(report.Failures) != 0 {
 		t.Fatalf("expected no failures, got %+v", report.Failures)
 	}
-	if len(report.CreatedResources) != 1 {
-		t.Fatalf("expected one created resource, got %d", len(report.CreatedResources))
+	if len(report.CreatedResources) != 2 {
+		t.Fatalf("expected two created resources, got %d", len(report.CreatedResources))
 	}
 	if report.Steps[2].Status != StepStatusSkipped || !report.Steps[2].Warning {
 		t.Fatalf("expected audience step to be warning skip, got %+v", report.Steps[2])
@@ -233,11 +256,85 @@ func TestRunnerSkipPolicySkipsUnavailableOptionalStepsAsWarnings(t *testing.T) {
 	if report.Steps[3].Status != StepStatusSkipped || !report.Steps[3].Warning {
 		t.Fatalf("expected catalog step to be warning skip, got %+v", report.Steps[3])
 	}
+	if report.Steps[5].Name != stepNameCreativeCreate || report.Steps[5].Status != StepStatusSkipped || !report.Steps[5].Warning {
+		t.Fatalf("expected creative step without page id to be warning skip, got %+v", report.Steps[5])
+	}
+	if report.Steps[6].Name != stepNameAdCreate || report.Steps[6].Status != StepStatusSkipped || !report.Steps[6].Warning {
+		t.Fatalf("expected ad step without creative to be warning skip, got %+v", report.Steps[6])
+	}
+	if report.Steps[7].Name != stepNameInsightsRead || report.Steps[7].Status != StepStatusExecuted {
+		t.Fatalf("expected insights read to execute, got %+v", report.Steps[7])
+	}
 	if code := RunExitCode(report); code != ExitCodeWarning {
 		t.Fatalf("unexpected exit code: got=%d want=%d", code, ExitCodeWarning)
 	}
 }
 
+func TestRunnerCreatesAdDeliveryChainWhenPageIsProvided(t *testing.T) {
+	calls := []fakeCall{
+		{
+			Method: http.MethodGet,
+			Path:   "act_1234",
+			Response: &graph.Response{
+				Body: map[string]any{"id": "act_1234", "account_status": float64(1)},
+			},
+		},
+		{Method: http.MethodPost, Path: "act_1234/campaigns", Response: &graph.Response{Body: map[string]any{"id": "cmp_1001"}}},
+		{Method: http.MethodPost, Path: "act_1234/customaudiences", Response: &graph.Response{Body: map[string]any{"id": "aud_2001"}}},
+		{Method: http.MethodPost, Path: "cat_1/items_batch", Response: &graph.Response{Body: map[string]any{}}},
+		{Method: http.MethodPost, Path: "act_1234/adsets", Response: &graph.Response{Body: map[string]any{"id": "ads_3001"}}},
+		{Method: http.MethodPost, Path: "act_1234/adcreatives", Response: &graph.Response{Body: map[string]any{"id": "cr_4001"}}},
+		{Method: http.MethodPost, Path: "act_1234/ads", Response: &graph.Response{Body: map[string]any{"id": "ad_5001"}}},
+		{
+			Method: http.MethodGet,
+			Path:   "cmp_1001/insights",
+			Response: &graph.Response{
+				Body: map[string]any{"data": []any{map[string]any{"impressions": "0"}}},
+			},
+		},
+		{Method: http.MethodGet, Path: "page_1", Response: &graph.Response{Body: map[string]any{"id": "page_1", "tasks": []any{"ADVERTISE", "CREATE_CONTENT"}}}},
+		{Method: http.MethodGet, Path: "page_1/conversations", Response: &graph.Response{Body: map[string]any{"data": []any{}}}},
+		{Method: http.MethodGet, Path: "page_1/leadgen_forms", Response: &graph.Response{Body: map[string]any{"data": []any{}}}},
+	}
+	client := &fakeGraphClient{t: t, calls: calls}
+
+	result, err := NewRunner(client).Run(context.Background(), RunInput{
+		Version:        "v25.0",
+		AccountID:      "1234",
+		Token:          "token",
+		OptionalPolicy: OptionalPolicyStrict,
+		CatalogID:      "cat_1",
+		PageID:         "page_1",
+	})
+	if err != nil {
+		t.Fatalf("run smoke runner: %v", err)
+	}
+	client.assertAllCallsConsumed()
+
+	report := result.Report
+	if report.Outcome != RunOutcomeClean || report.Summary.ExecutedSteps != 11 {
+		t.Fatalf("unexpected report: outcome=%s summary=%+v", report.Outcome, report.Summary)
+	}
+	wantKinds := []string{"campaign", "audience", "adset", "creative", "ad"}
+	if len(report.CreatedResources) != len(wantKinds) {
+		t.Fatalf("unexpected created resources: %+v", report.CreatedResources)
+	}
+	for index, kind := range wantKinds {
+		if report.CreatedResources[index].ResourceKind != kind {
+			t.Fatalf("unexpected resource kind at %d: got=%s want=%s", index, report.CreatedResources[index].ResourceKind, kind)
+		}
+	}
+	for _, capability := range report.Capabilities {
+		want := CapabilityStatusAvailable
+		if capability.Name == capabilityWhatsApp {
+			want = CapabilityStatusNotEvaluated
+		}
+		if capability.Status != want {
+			t.Fatalf("unexpected capability state: %+v", capability)
+		}
+	}
+}
+
 func TestRunnerBlocksRemainingStepsAfterRequiredFailure(t *testing.T) {
 	client := &fakeGraphClient{
 		t: t,
@@ -273,7 +370,7 @@ func TestRunnerBlocksRemainingStepsAfterRequiredFailure(t *testing.T) {
 	if report.Outcome != RunOutcomeBlocking {
 		t.Fatalf("unexpected outcome: %s", report.Outcome)
 	}
-	if report.Summary.FailedSteps != 1 || report.Summary.SkippedSteps != 3 {
+	if report.Summary.FailedSteps != 1 || report.Summary.SkippedSteps != 11 {
 		t.Fatalf("unexpected summary: %+v", report.Summary)
 	}
 	if len(report.Failures) != 1 {
@@ -288,3 +385,59 @@ func TestRunnerBlocksRemainingStepsAfterRequiredFailure(t *testing.T) {
 		}
 	}
 }
+
+func TestRunnerCapabilityProbesBuildSurfaceMatrix(t *testing.T) {
+	client := &fakeGraphClient{
+		t: t,
+		calls: []fakeCall{
+			{Method: http.MethodGet, Path: "act_1234", Response: &graph.Response{Body: map[string]any{"id": "act_1234", "account_status": float64(1)}}},
+			{Method: http.MethodPost, Path: "act_1234/campaigns", Response: &graph.Response{Body: map[string]any{"id": "cmp_1001"}}},
+			{Method: http.MethodPost, Path: "act_1234/customaudiences", Response: &graph.Response{Body: map[string]any{"id": "aud_2001"}}},
+			{Method: http.MethodPost, Path: "act_1234/adsets", Response: &graph.Response{Body: map[string]any{"id": "ads_3001"}}},
+			{Method: http.MethodPost, Path: "act_1234/adcreatives", Response: &graph.Response{Body: map[string]any{"id": "cr_4001"}}},
+			{Method: http.MethodPost, Path: "act_1234/ads", Response: &graph.Response{Body: map[string]any{"id": "ad_5001"}}},
+			{Method: http.MethodGet, Path: "cmp_1001/insights", Response: &graph.Response{Body: map[string]any{"data": []any{}}}},
+			{Method: http.MethodGet, Path: "page_1", Response: &graph.Response{Body: map[string]any{"id": "page_1", "tasks": []any{"ADVERTISE"}}}},
+			{
+				Method: http.MethodGet,
+				Path:   "page_1/conversations",
+				Err:    &graph.APIError{Type: "OAuthException", Code: 200, StatusCode: http.StatusForbidden, Message: "Requires pages_messaging permission"},
+			},
+			{Method: http.MethodGet, Path: "page_1/leadgen_forms", Response: &graph.Response{Body: map[string]any{"data": []any{}}}},
+			{Method: http.MethodGet, Path: "wa_1", Response: &graph.Response{Body: map[string]any{"id": "wa_1", "quality_rating": "GREEN"}}},
+		},
+	}
+
+	result, err := NewRunner(client).Run(context.Background(), RunInput{
+		Version:               "v25.0",
+		AccountID:             "1234",
+		Token:                 "token",
+		OptionalPolicy:        OptionalPolicySkip,
+		PageID:                "page_1",
+		WhatsAppPhoneNumberID: "wa_1",
+	})
+	if err != nil {
+		t.Fatalf("run smoke runner: %v", err)
+	}
+	client.assertAllCallsConsumed()
+
+	statuses := map[string]string{}
+	for _, capability := range result.Report.Capabilities {
+		statuses[capability.Name] = capability.Status
+	}
+	want := map[string]string{
+		capabilityCatalog:   CapabilityStatusUnavailable,
+		capabilityPage:      CapabilityStatusUnavailable,
+		capabilityMessenger: CapabilityStatusUnavailable,
+		capabilityLeads:     CapabilityStatusAvailable,
+		capabilityWhatsApp:  CapabilityStatusAvailable,
+	}
+	for name, status := range want {
+		if statuses[name] != status {
+			t.Fatalf("unexpected %s capability: got=%s want=%s", name, statuses[name], status)
+		}
+	}
+	if result.Report.Summary.Warnings != 3 || result.Report.Outcome != RunOutcomeWarning {
+		t.Fatalf("unexpected summary: %+v", result.Report.Summary)
+	}
+}
@@ -0,0 +1,48 @@
+package smoke
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	SandboxIndicatorName     = "name_sandbox_marker"
+	SandboxIndicatorBusiness = "business_sandbox_marker"
+)
+
+type SandboxCheck struct {
+	Required   bool     `json:"required"`
+	Detected   bool     `json:"detected"`
+	Enforced   bool     `json:"enforced"`
+	Indicators []string `json:"indicators"`
+	Reason     string   `json:"reason,omitempty"`
+}
+
+// assessSandbox returns the positive signs that the account is a sandbox.
+// Billing state is no such sign: an uncapped live account reports spend_cap 0
+// and a new prepay account has spent nothing.
+func assessSandbox(body map[string]any) []string {
+	indicators := []string{}
+	if name, ok := body["name"].(string); ok && hasSandboxMarker(name) {
+		indicators = append(indicators, SandboxIndicatorName)
+	}
+	if business, ok := body["business"].(map[string]any); ok {
+		if name, ok := business["name"].(string); ok && hasSandboxMarker(name) {
+			indicators = append(indicators, SandboxIndicatorBusiness)
+		}
+	}
+	return indicators
+}
+
+func hasSandboxMarker(name string) bool {
+	return strings.Contains(strings.ToLower(name), "sandbox")
+}
+
+func sandboxRefusalMessage(accountID string) string {
+	return fmt.Sprintf(
+		"account act_%s shows no sandbox indicators (%s, %s); refusing to run mutation steps under --require-sandbox",
+		accountID,
+		SandboxIndicatorName,
+		SandboxIndicatorBusiness,
+	)
+}
@@ -0,0 +1,165 @@
+package smoke
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestAssessSandboxIndicators(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		body map[string]any
+		want []string
+	}{
+		"production": {
+			body: map[string]any{"name": "Primary", "spend_cap": "500000", "is_prepay_account": false, "amount_spent": "1234"},
+			want: []string{},
+		},
+		"name marker": {
+			body: map[string]any{"name": "Sandbox Ad Account", "spend_cap": "100"},
+			want: []string{SandboxIndicatorName},
+		},
+		"business marker": {
+			body: map[string]any{"name": "Test", "business": map[string]any{"id": "9", "name": "Acme Sandbox"}},
+			want: []string{SandboxIndicatorBusiness},
+		},
+		"uncapped live account": {
+			body: map[string]any{"name": "Primary", "spend_cap": "0", "amount_spent": "98765"},
+			want: []string{},
+		},
+		"unused prepay": {
+			body: map[string]any{"name": "Test", "is_prepay_account": true, "amount_spent": "0"},
+			want: []string{},
+		},
+	}
+	for name, testCase := range testCases {
+		name, testCase := name, testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := assessSandbox(testCase.body)
+			if len(got) != len(testCase.want) {
+				t.Fatalf("unexpected indicators: got=%v want=%v", got, testCase.want)
+			}
+			for index := range got {
+				if got[index] != testCase.want[index] {
+					t.Fatalf("unexpected indicators: got=%v want=%v", got, testCase.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunnerRequireSandboxRefusesProductionAccount(t *testing.T) {
+	for name, account := range map[string]map[string]any{
+		"capped": {
+			"id":                "act_1234",
+			"name":              "Primary",
+			"account_status":    float64(1),
+			"spend_cap":         "500000",
+			"amount_spent":      "98765",
+			"is_prepay_account": false,
+		},
+		"uncapped": {
+			"id":                "act_1234",
+			"name":              "Primary",
+			"account_status":    float64(1),
+			"spend_cap":         "0",
+			"amount_spent":      "98765",
+			"is_prepay_account": false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assertSandboxRefused(t, account)
+		})
+	}
+}
+
+func assertSandboxRefused(t *testing.T, account map[string]any) {
+	t.Helper()
+	client := &fakeGraphClient{
+		t: t,
+		calls: []fakeCall{
+			{
+				Method:   http.MethodGet,
+				Path:     "act_1234",
+				Response: &graph.Response{Body: account},
+			},
+		},
+	}
+
+	result, err := NewRunner(client).Run(context.Background(), RunInput{
+		Version:        "v25.0",
+		AccountID:      "1234",
+		Token:          "token",
+		OptionalPolicy: OptionalPolicySkip,
+		RequireSandbox: true,
+	})
+	if err != nil {
+		t.Fatalf("run smoke runner: %v", err)
+	}
+	client.assertAllCallsConsumed()
+
+	report := result.Report
+	if !report.Sandbox.Required || report.Sandbox.Detected || !report.Sandbox.Enforced {
+		t.Fatalf("unexpected sandbox check: %+v", report.Sandbox)
+	}
+	if report.Outcome != RunOutcomeBlocking || RunExitCode(report) != ExitCodePolicy {
+		t.Fatalf("unexpected outcome: %s", report.Outcome)
+	}
+	if len(report.Failures) != 1 || report.Failures[0].Type != "sandbox_required" {
+		t.Fatalf("unexpected failures: %+v", report.Failures)
+	}
+	if len(report.CreatedResources) != 0 || report.Summary.SkippedSteps != len(report.Steps)-1 {
+		t.Fatalf("expected mutation steps to be skipped, got summary=%+v", report.Summary)
+	}
+}
+
+func TestRunnerRequireSandboxAllowsSandboxAccount(t *testing.T) {
+	client := &fakeGraphClient{
+		t: t,
+		calls: []fakeCall{
+			{
+				Method: http.MethodGet,
+				Path:   "act_1234",
+				Response: &graph.Response{
+					Body: map[string]any{
+						"id":             "act_1234",
+						"name":           "Sandbox Ad Account",
+						"account_status": float64(1),
+						"spend_cap":      "0",
+					},
+				},
+			},
+			{Method: http.MethodPost, Path: "act_1234/campaigns", Response: &graph.Response{Body: map[string]any{"id": "cmp_1001"}}},
+			{
+				Method: http.MethodPost,
+				Path:   "act_1234/customaudiences",
+				Err:    &graph.APIError{Type: "OAuthException", Code: 200, StatusCode: http.StatusBadRequest, Message: "Permissions error"},
+			},
+		},
+	}
+
+	result, err := NewRunner(client).Run(context.Background(), RunInput{
+		Version:        "v25.0",
+		AccountID:      "1234",
+		Token:          "token",
+		OptionalPolicy: OptionalPolicyStrict,
+		RequireSandbox: true,
+	})
+	if err != nil {
+		t.Fatalf("run smoke runner: %v", err)
+	}
+	client.assertAllCallsConsumed()
+
+	sandbox := result.Report.Sandbox
+	if !sandbox.Detected || sandbox.Enforced || len(sandbox.Indicators) != 1 {
+		t.Fatalf("unexpected sandbox check: %+v", sandbox)
+	}
+	if result.Report.Steps[0].Status != StepStatusExecuted || result.Report.Steps[1].Status != StepStatusExecuted {
+		t.Fatalf("expected account context and campaign steps to execute, got %+v", result.Report.Steps[:2])
+	}
+}
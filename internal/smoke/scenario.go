@@ -0,0 +1,294 @@
+package smoke
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/ops"
+	"gopkg.in/yaml.v3"
+)
+
+const ScenarioSchemaVersion = 1
+
+const defaultScenarioIDField = "id"
+
+var (
+	ErrInvalidScenario      = errors.New("invalid smoke scenario")
+	scenarioIdentifierRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	reservedScenarioNames   = map[string]struct{}{
+		stepNameAccountContext: {},
+		stepNameCleanup:        {},
+	}
+	builtinScenarioVariables = map[string]struct{}{
+		"account_id":    {},
+		"catalog_id":    {},
+		"graph_version": {},
+	}
+)
+
+type Scenario struct {
+	SchemaVersion int            `yaml:"schema_version"`
+	Name          string         `yaml:"name"`
+	Steps         []ScenarioStep `yaml:"steps"`
+}
+
+type ScenarioStep struct {
+	Name       string            `yaml:"name"`
+	Method     string            `yaml:"method"`
+	Path       string            `yaml:"path"`
+	Params     map[string]string `yaml:"params,omitempty"`
+	Optional   bool              `yaml:"optional,omitempty"`
+	Capability string            `yaml:"capability,omitempty"`
+	Capture    map[string]string `yaml:"capture,omitempty"`
+	Track      *ScenarioTracking `yaml:"track,omitempty"`
+}
+
+type ScenarioTracking struct {
+	ResourceKind  string `yaml:"resource_kind"`
+	CleanupAction string `yaml:"cleanup_action"`
+	IDField       string `yaml:"id_field,omitempty"`
+}
+
+func LoadScenario(path string) (*Scenario, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("%w: scenario file path is required", ErrInvalidScenario)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read smoke scenario %s: %w", path, err)
+	}
+
+	scenario := &Scenario{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(scenario); err != nil {
+		return nil, fmt.Errorf("%w: decode %s: %v", ErrInvalidScenario, path, err)
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return scenario, nil
+}
+
+func (s *Scenario) Validate() error {
+	if s == nil {
+		return fmt.Errorf("%w: scenario is nil", ErrInvalidScenario)
+	}
+	if s.SchemaVersion != ScenarioSchemaVersion {
+		return fmt.Errorf("%w: unsupported schema_version=%d (expected %d)", ErrInvalidScenario, s.SchemaVersion, ScenarioSchemaVersion)
+	}
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidScenario)
+	}
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("%w: at least one step is required", ErrInvalidScenario)
+	}
+
+	seenSteps := map[string]struct{}{}
+	seenCaptures := map[string]struct{}{}
+	for index, step := range s.Steps {
+		if err := step.validate(); err != nil {
+			return fmt.Errorf("%w: steps[%d]: %v", ErrInvalidScenario, index, err)
+		}
+		if _, exists := seenSteps[step.Name]; exists {
+			return fmt.Errorf("%w: steps[%d]: duplicate step name %q", ErrInvalidScenario, index, step.Name)
+		}
+		seenSteps[step.Name] = struct{}{}
+		for variable := range step.Capture {
+			if _, exists := seenCaptures[variable]; exists {
+				return fmt.Errorf("%w: steps[%d]: capture variable %q is already defined", ErrInvalidScenario, index, variable)
+			}
+			seenCaptures[variable] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func (s ScenarioStep) validate() error {
+	if !scenarioIdentifierRegex.MatchString(s.Name) {
+		return fmt.Errorf("name %q must match %s", s.Name, scenarioIdentifierRegex.String())
+	}
+	if _, reserved := reservedScenarioNames[s.Name]; reserved {
+		return fmt.Errorf("name %q is reserved", s.Name)
+	}
+	switch strings.ToUpper(strings.TrimSpace(s.Method)) {
+	case http.MethodGet, http.MethodPost, http.MethodDelete:
+	default:
+		return fmt.Errorf("method must be one of [GET POST DELETE], got %q", s.Method)
+	}
+	if strings.TrimSpace(s.Path) == "" {
+		return errors.New("path is required")
+	}
+	if _, err := parseScenarioTemplate(s.Name+".path", s.Path); err != nil {
+		return err
+	}
+	for key, value := range s.Params {
+		if strings.TrimSpace(key) == "" {
+			return errors.New("param names cannot be empty")
+		}
+		if _, err := parseScenarioTemplate(s.Name+".params."+key, value); err != nil {
+			return err
+		}
+	}
+	if s.Optional && !scenarioIdentifierRegex.MatchString(s.Capability) {
+		return fmt.Errorf("optional steps require a capability matching %s", scenarioIdentifierRegex.String())
+	}
+	if !s.Optional && strings.TrimSpace(s.Capability) != "" {
+		return errors.New("capability is only supported on optional steps")
+	}
+	for variable, field := range s.Capture {
+		if !scenarioIdentifierRegex.MatchString(variable) {
+			return fmt.Errorf("capture variable %q must match %s", variable, scenarioIdentifierRegex.String())
+		}
+		if _, builtin := builtinScenarioVariables[variable]; builtin {
+			return fmt.Errorf("capture variable %q shadows a built-in variable", variable)
+		}
+		if strings.TrimSpace(field) == "" {
+			return fmt.Errorf("capture variable %q requires a response field", variable)
+		}
+	}
+	if s.Track != nil {
+		if err := ops.ValidateResourceCleanupAction(s.Track.ResourceKind, s.Track.CleanupAction); err != nil {
+			return fmt.Errorf("track: %v", err)
+		}
+	}
+	return nil
+}
+
+func (s ScenarioStep) buildRequest(variables map[string]string) (graph.Request, error) {
+	path, err := renderScenarioTemplate(s.Name+".path", s.Path, variables)
+	if err != nil {
+		return graph.Request{}, err
+	}
+	params := make(map[string]string, len(s.Params))
+	keys := make([]string, 0, len(s.Params))
+	for key := range s.Params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, err := renderScenarioTemplate(s.Name+".params."+key, s.Params[key], variables)
+		if err != nil {
+			return graph.Request{}, err
+		}
+		params[key] = value
+	}
+
+	request := graph.Request{
+		Method: strings.ToUpper(strings.TrimSpace(s.Method)),
+		Path:   strings.TrimPrefix(strings.TrimSpace(path), "/"),
+	}
+	if request.Method == http.MethodPost {
+		request.Form = params
+	} else if len(params) > 0 {
+		request.Query = params
+	}
+	return request, nil
+}
+
+func (s ScenarioStep) extract(body map[string]any) (map[string]string, string, error) {
+	captured := make(map[string]string, len(s.Capture))
+	for variable, field := range s.Capture {
+		value, ok := lookupScenarioField(body, field)
+		if !ok {
+			return nil, "", fmt.Errorf("%s response did not include %s", s.Name, field)
+		}
+		captured[variable] = value
+	}
+	if s.Track == nil {
+		return captured, "", nil
+	}
+	idField := strings.TrimSpace(s.Track.IDField)
+	if idField == "" {
+		idField = defaultScenarioIDField
+	}
+	resourceID, ok := lookupScenarioField(body, idField)
+	if !ok {
+		return nil, "", fmt.Errorf("%s response did not include %s", s.Name, idField)
+	}
+	return captured, resourceID, nil
+}
+
+func scenarioCapabilities(scenario *Scenario, policy string) ([]CapabilityStatus, map[string]int) {
+	capabilities := []CapabilityStatus{}
+	index := map[string]int{}
+	for _, step := range scenario.Steps {
+		if !step.Optional {
+			continue
+		}
+		if _, exists := index[step.Capability]; exists {
+			continue
+		}
+		index[step.Capability] = len(capabilities)
+		capabilities = append(capabilities, CapabilityStatus{
+			Name:     step.Capability,
+			Optional: true,
+			Status:   CapabilityStatusNotEvaluated,
+			Policy:   policy,
+		})
+	}
+	return capabilities, index
+}
+
+func parseScenarioTemplate(name string, text string) (*template.Template, error) {
+	parsed, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse template %s: %v", name, err)
+	}
+	return parsed, nil
+}
+
+func renderScenarioTemplate(name string, text string, variables map[string]string) (string, error) {
+	parsed, err := parseScenarioTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+	var rendered bytes.Buffer
+	if err := parsed.Execute(&rendered, variables); err != nil {
+		return "", fmt.Errorf("render template %s: %v", name, err)
+	}
+	return rendered.String(), nil
+}
+
+func lookupScenarioField(body map[string]any, field string) (string, bool) {
+	var current any = body
+	for _, part := range strings.Split(strings.TrimSpace(field), ".") {
+		switch typed := current.(type) {
+		case map[string]any:
+			next, ok := typed[part]
+			if !ok {
+				return "", false
+			}
+			current = next
+		case []any:
+			position, err := strconv.Atoi(part)
+			if err != nil || position < 0 || position >= len(typed) {
+				return "", false
+			}
+			current = typed[position]
+		default:
+			return "", false
+		}
+	}
+	switch typed := current.(type) {
+	case string:
+		value := strings.TrimSpace(typed)
+		return value, value != ""
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(typed), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,204 @@
+package smoke
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const testScenarioYAML = `schema_version: 1
+name: adset-pipeline
+steps:
+  - name: campaign_create
+    method: POST
+    path: act_{{.account_id}}/campaigns
+    params:
+      name: scenario-campaign
+      status: PAUSED
+    capture:
+      campaign_id: id
+    track:
+      resource_kind: campaign
+      cleanup_action: pause
+  - name: adset_create
+    method: POST
+    path: act_{{.account_id}}/adsets
+    params:
+      campaign_id: "{{.campaign_id}}"
+    track:
+      resource_kind: adset
+      cleanup_action: pause
+  - name: insights_read
+    method: GET
+    path: "{{.campaign_id}}/insights"
+    params:
+      fields: impressions
+    optional: true
+    capability: insights
+`
+
+func writeScenarioFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write scenario: %v", err)
+	}
+	return path
+}
+
+func TestLoadScenarioParsesSteps(t *testing.T) {
+	t.Parallel()
+
+	scenario, err := LoadScenario(writeScenarioFile(t, testScenarioYAML))
+	if err != nil {
+		t.Fatalf("load scenario: %v", err)
+	}
+	if scenario.Name != "adset-pipeline" || len(scenario.Steps) != 3 {
+		t.Fatalf("unexpected scenario: %+v", scenario)
+	}
+	if scenario.Steps[0].Capture["campaign_id"] != "id" || scenario.Steps[1].Track.ResourceKind != "adset" {
+		t.Fatalf("unexpected scenario steps: %+v", scenario.Steps)
+	}
+}
+
+func TestLoadScenarioRejectsInvalidDefinitions(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"unknown field":     "schema_version: 1\nname: x\nsteps:\n  - name: a\n    method: GET\n    path: me\n    retries: 2\n",
+		"schema version":    "schema_version: 2\nname: x\nsteps:\n  - name: a\n    method: GET\n    path: me\n",
+		"reserved name":     "schema_version: 1\nname: x\nsteps:\n  - name: cleanup\n    method: GET\n    path: me\n",
+		"duplicate step":    "schema_version: 1\nname: x\nsteps:\n  - name: a\n    method: GET\n    path: me\n  - name: a\n    method: GET\n    path: me\n",
+		"unsupported verb":  "schema_version: 1\nname: x\nsteps:\n  - name: a\n    method: PATCH\n    path: me\n",
+		"optional no cap":   "schema_version: 1\nname: x\nsteps:\n  - name: a\n    method: GET\n    path: me\n    optional: true\n",
+		"shadowed variable": "schema_version: 1\nname: x\nsteps:\n  - name: a\n    method: GET\n    path: me\n    capture:\n      account_id: id\n",
+		"bad template":      "schema_version: 1\nname: x\nsteps:\n  - name: a\n    method: GET\n    path: \"{{.account_id\"\n",
+		"bad track action":  "schema_version: 1\nname: x\nsteps:\n  - name: a\n    method: POST\n    path: me\n    track:\n      resource_kind: campaign\n      cleanup_action: delete\n",
+	}
+	for name, content := range testCases {
+		name, content := name, content
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := LoadScenario(writeScenarioFile(t, content)); !errors.Is(err, ErrInvalidScenario) {
+				t.Fatalf("expected invalid scenario error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRunnerExecutesScenarioSteps(t *testing.T) {
+	scenario, err := LoadScenario(writeScenarioFile(t, testScenarioYAML))
+	if err != nil {
+		t.Fatalf("load scenario: %v", err)
+	}
+	client := &fakeGraphClient{
+		t: t,
+		calls: []fakeCall{
+			{
+				Method: http.MethodGet,
+				Path:   "act_1234",
+				Response: &graph.Response{
+					Body: map[string]any{
+						"id":             "act_1234",
+						"name":           "Primary",
+						"currency":       "USD",
+						"account_status": float64(1),
+					},
+				},
+			},
+			{
+				Method:   http.MethodPost,
+				Path:     "act_1234/campaigns",
+				Response: &graph.Response{Body: map[string]any{"id": "cmp_1001"}},
+			},
+			{
+				Method:   http.MethodPost,
+				Path:     "act_1234/adsets",
+				Response: &graph.Response{Body: map[string]any{"id": "ads_3001"}},
+			},
+			{
+				Method: http.MethodGet,
+				Path:   "cmp_1001/insights",
+				Err:    &graph.APIError{Type: "OAuthException", Code: 10, StatusCode: http.StatusForbidden, Message: "Application does not have permission for this action"},
+			},
+		},
+	}
+
+	result, err := NewRunner(client).Run(context.Background(), RunInput{
+		Version:        "v25.0",
+		AccountID:      "1234",
+		Token:          "token",
+		OptionalPolicy: OptionalPolicySkip,
+		Scenario:       scenario,
+	})
+	if err != nil {
+		t.Fatalf("run scenario: %v", err)
+	}
+	client.assertAllCallsConsumed()
+
+	report := result.Report
+	if report.Scenario != "adset-pipeline" {
+		t.Fatalf("unexpected scenario name: %q", report.Scenario)
+	}
+	if len(report.Steps) != 4 || report.Steps[3].Status != StepStatusSkipped {
+		t.Fatalf("unexpected steps: %+v", report.Steps)
+	}
+	if len(report.Capabilities) != 1 || report.Capabilities[0].Name != "insights" || report.Capabilities[0].Status != CapabilityStatusUnavailable {
+		t.Fatalf("unexpected capabilities: %+v", report.Capabilities)
+	}
+	if len(report.CreatedResources) != 2 || report.CreatedResources[1].ResourceKind != "adset" || report.CreatedResources[1].ResourceID != "ads_3001" {
+		t.Fatalf("unexpected created resources: %+v", report.CreatedResources)
+	}
+	if report.Outcome != RunOutcomeWarning || RunExitCode(report) != ExitCodeWarning {
+		t.Fatalf("unexpected outcome: %s", report.Outcome)
+	}
+}
+
+func TestRunnerScenarioMissingCaptureBlocksRemainingSteps(t *testing.T) {
+	scenario, err := LoadScenario(writeScenarioFile(t, testScenarioYAML))
+	if err != nil {
+		t.Fatalf("load scenario: %v", err)
+	}
+	client := &fakeGraphClient{
+		t: t,
+		calls: []fakeCall{
+			{
+				Method: http.MethodGet,
+				Path:   "act_1234",
+				Response: &graph.Response{
+					Body: map[string]any{"id": "act_1234", "account_status": float64(1)},
+				},
+			},
+			{
+				Method:   http.MethodPost,
+				Path:     "act_1234/campaigns",
+				Response: &graph.Response{Body: map[string]any{"success": true}},
+			},
+		},
+	}
+
+	result, err := NewRunner(client).Run(context.Background(), RunInput{
+		Version:        "v25.0",
+		AccountID:      "1234",
+		Token:          "token",
+		OptionalPolicy: OptionalPolicySkip,
+		Scenario:       scenario,
+	})
+	if err != nil {
+		t.Fatalf("run scenario: %v", err)
+	}
+	client.assertAllCallsConsumed()
+
+	report := result.Report
+	if report.Outcome != RunOutcomeBlocking {
+		t.Fatalf("expected blocking outcome, got %s", report.Outcome)
+	}
+	if report.Steps[1].Status != StepStatusFailed || report.Steps[2].Status != StepStatusSkipped || report.Steps[3].Status != StepStatusSkipped {
+		t.Fatalf("unexpected steps: %+v", report.Steps)
+	}
+}
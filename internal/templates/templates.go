@@ -0,0 +1,336 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+)
+
+const StoreSchemaVersion = 1
+
+const (
+	KindCampaign = "campaign"
+	KindAdSet    = "adset"
+	KindAd       = "ad"
+)
+
+var (
+	ErrStorePathRequired = errors.New("template store path is required")
+	ErrTemplateNotFound  = errors.New("template not found")
+
+	templateNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	placeholderPattern  = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+	supportedKinds = map[string]struct{}{
+		KindCampaign: {},
+		KindAdSet:    {},
+		KindAd:       {},
+	}
+)
+
+// Template is a named create payload whose values may contain {{variable}}
+// placeholders. Keys are fixed so they can be linted once when the template is saved.
+type Template struct {
+	Name         string            `json:"name"`
+	Kind         string            `json:"kind"`
+	Params       map[string]string `json:"params"`
+	Variables    []string          `json:"variables"`
+	Defaults     map[string]string `json:"defaults,omitempty"`
+	GraphVersion string            `json:"graph_version,omitempty"`
+	SavedAt      time.Time         `json:"saved_at"`
+}
+
+type Store struct {
+	SchemaVersion int                  `json:"schema_version"`
+	Templates     map[string]*Template `json:"templates"`
+}
+
+func DefaultStorePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user home directory: %w", err)
+	}
+	return filepath.Join(home, ".meta", "templates.json"), nil
+}
+
+// New validates a template and derives its variable list from the placeholders.
+// Every default must name a variable that the params actually use.
+func New(name string, kind string, params map[string]string, defaults map[string]string) (*Template, error) {
+	name = strings.TrimSpace(name)
+	if !templateNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid template name %q: expected lowercase letters, digits, '.', '_' or '-'", name)
+	}
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	if _, ok := supportedKinds[kind]; !ok {
+		return nil, fmt.Errorf("unsupported template kind %q: expected campaign|adset|ad", kind)
+	}
+	if len(params) == 0 {
+		return nil, errors.New("template params cannot be empty")
+	}
+
+	normalized := make(map[string]string, len(params))
+	variables := map[string]struct{}{}
+	for key, value := range params {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, errors.New("template param key cannot be empty")
+		}
+		if strings.Contains(key, "{{") {
+			return nil, fmt.Errorf("template param key %q cannot contain placeholders", key)
+		}
+		names, err := Placeholders(value)
+		if err != nil {
+			return nil, fmt.Errorf("template param %s: %w", key, err)
+		}
+		for _, variable := range names {
+			variables[variable] = struct{}{}
+		}
+		normalized[key] = strings.TrimSpace(value)
+	}
+
+	normalizedDefaults := map[string]string{}
+	for variable, value := range defaults {
+		variable = strings.TrimSpace(variable)
+		if _, ok := variables[variable]; !ok {
+			return nil, fmt.Errorf("default for %q does not match any template variable", variable)
+		}
+		normalizedDefaults[variable] = value
+	}
+	if len(normalizedDefaults) == 0 {
+		normalizedDefaults = nil
+	}
+
+	return &Template{
+		Name:      name,
+		Kind:      kind,
+		Params:    normalized,
+		Variables: sortedKeys(variables),
+		Defaults:  normalizedDefaults,
+	}, nil
+}
+
+// Placeholders returns the distinct variable names referenced in value.
+func Placeholders(value string) ([]string, error) {
+	seen := map[string]struct{}{}
+	for _, match := range placeholderPattern.FindAllStringSubmatch(value, -1) {
+		name := match[1]
+		if !variableNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid placeholder %q: variable names must match %s", match[0], variableNamePattern.String())
+		}
+		seen[name] = struct{}{}
+	}
+	return sortedKeys(seen), nil
+}
+
+// Render substitutes variables into the template params. Values passed in vars win
+// over saved defaults; any variable left unresolved, or any var the template does
+// not use, fails the render.
+func (t *Template) Render(vars map[string]string) (map[string]string, error) {
+	if t == nil {
+		return nil, errors.New("template is required")
+	}
+	known := make(map[string]struct{}, len(t.Variables))
+	for _, variable := range t.Variables {
+		known[variable] = struct{}{}
+	}
+	unknown := make([]string, 0)
+	for variable := range vars {
+		if _, ok := known[variable]; !ok {
+			unknown = append(unknown, variable)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("template %s does not use variable(s): %s", t.Name, strings.Join(unknown, ", "))
+	}
+
+	values := make(map[string]string, len(t.Variables))
+	missing := make([]string, 0)
+	for _, variable := range t.Variables {
+		if value, ok := vars[variable]; ok {
+			values[variable] = value
+			continue
+		}
+		if value, ok := t.Defaults[variable]; ok {
+			values[variable] = value
+			continue
+		}
+		missing = append(missing, variable)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template %s is missing variable(s): %s; pass --var name=value", t.Name, strings.Join(missing, ", "))
+	}
+
+	rendered := make(map[string]string, len(t.Params))
+	for key, value := range t.Params {
+		rendered[key] = placeholderPattern.ReplaceAllStringFunc(value, func(match string) string {
+			name := placeholderPattern.FindStringSubmatch(match)[1]
+			return values[name]
+		})
+	}
+	return rendered, nil
+}
+
+// ParseVars parses repeated name=value entries. Values may contain commas and '='.
+func ParseVars(entries []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --var entry %q; expected name=value", entry)
+		}
+		if !variableNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid --var name %q: must match %s", name, variableNamePattern.String())
+		}
+		if _, exists := vars[name]; exists {
+			return nil, fmt.Errorf("duplicate --var %q", name)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+func LoadStore(path string) (*Store, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, ErrStorePathRequired
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Store{SchemaVersion: StoreSchemaVersion, Templates: map[string]*Template{}}, nil
+		}
+		return nil, fmt.Errorf("read template store %s: %w", path, err)
+	}
+
+	store := &Store{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(store); err != nil {
+		return nil, fmt.Errorf("decode template store %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return nil, fmt.Errorf("decode template store %s: multiple JSON values", path)
+		}
+		return nil, fmt.Errorf("decode template store %s: %w", path, err)
+	}
+	if store.SchemaVersion != StoreSchemaVersion {
+		return nil, fmt.Errorf("unsupported template store schema_version=%d in %s (expected %d)", store.SchemaVersion, path, StoreSchemaVersion)
+	}
+	if store.Templates == nil {
+		store.Templates = map[string]*Template{}
+	}
+	return store, nil
+}
+
+func SaveStore(path string, store *Store) error {
+	return SaveStoreHeld(nil, path, store)
+}
+
+// SaveStoreHeld is SaveStore for a caller that holds the lock of path, taken
+// with filelock.Acquire before loading the store it modifies.
+func SaveStoreHeld(held *filelock.Lock, path string, store *Store) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return ErrStorePathRequired
+	}
+	if store == nil {
+		return errors.New("template store is required")
+	}
+	store.SchemaVersion = StoreSchemaVersion
+	if store.Templates == nil {
+		store.Templates = map[string]*Template{}
+	}
+
+	lock, err := held.Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create template store directory for %s: %w", path, err)
+	}
+	payload, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode template store: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".templates-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp template store file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp template store file: %w", err)
+	}
+	if err := tmpFile.Chmod(0o600); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("chmod temp template store file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp template store file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("replace template store %s: %w", path, err)
+	}
+	return nil
+}
+
+// Get returns the named template or ErrTemplateNotFound.
+func (s *Store) Get(name string) (*Template, error) {
+	name = strings.TrimSpace(name)
+	if s != nil {
+		if template, ok := s.Templates[name]; ok && template != nil {
+			return template, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrTemplateNotFound, name)
+}
+
+// List returns templates sorted by name.
+func (s *Store) List() []*Template {
+	if s == nil {
+		return []*Template{}
+	}
+	names := make([]string, 0, len(s.Templates))
+	for name := range s.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make([]*Template, 0, len(names))
+	for _, name := range names {
+		out = append(out, s.Templates[name])
+	}
+	return out
+}
+
+func sortedKeys(values map[string]struct{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,179 @@
+package templates
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewDerivesVariables(t *testing.T) {
+	t.Parallel()
+
+	template, err := New("launch-sale", "Campaign", map[string]string{
+		"name":         "Sale {{ season }} {{audience_id}}",
+		"daily_budget": "{{budget}}",
+		"status":       "PAUSED",
+	}, map[string]string{"season": "Spring"})
+	if err != nil {
+		t.Fatalf("new template: %v", err)
+	}
+	if template.Kind != KindCampaign {
+		t.Fatalf("expected normalized kind, got %q", template.Kind)
+	}
+	if want := []string{"audience_id", "budget", "season"}; !reflect.DeepEqual(template.Variables, want) {
+		t.Fatalf("unexpected variables %v", template.Variables)
+	}
+}
+
+func TestNewRejectsInvalidTemplates(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		tmplName string
+		kind     string
+		params   map[string]string
+		defaults map[string]string
+		want     string
+	}{
+		{name: "name", tmplName: "Launch Sale", kind: KindAd, params: map[string]string{"status": "PAUSED"}, want: "invalid template name"},
+		{name: "kind", tmplName: "launch", kind: "creative", params: map[string]string{"status": "PAUSED"}, want: "unsupported template kind"},
+		{name: "empty params", tmplName: "launch", kind: KindAd, want: "params cannot be empty"},
+		{name: "placeholder", tmplName: "launch", kind: KindAd, params: map[string]string{"name": "{{audience-id}}"}, want: "invalid placeholder"},
+		{name: "default", tmplName: "launch", kind: KindAd, params: map[string]string{"name": "{{title}}"}, defaults: map[string]string{"budget": "1"}, want: `default for "budget"`},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := New(tc.tmplName, tc.kind, tc.params, tc.defaults)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestRenderSubstitutesVariablesAndDefaults(t *testing.T) {
+	t.Parallel()
+
+	template, err := New("launch-sale", KindAdSet, map[string]string{
+		"daily_budget": "{{budget}}",
+		"targeting":    `{"custom_audiences":[{"id":"{{audience_id}}"}]}`,
+	}, map[string]string{"budget": "1000"})
+	if err != nil {
+		t.Fatalf("new template: %v", err)
+	}
+
+	rendered, err := template.Render(map[string]string{"audience_id": "aud_1", "budget": "5000"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := map[string]string{
+		"daily_budget": "5000",
+		"targeting":    `{"custom_audiences":[{"id":"aud_1"}]}`,
+	}
+	if !reflect.DeepEqual(rendered, want) {
+		t.Fatalf("unexpected render %v", rendered)
+	}
+
+	rendered, err = template.Render(map[string]string{"audience_id": "aud_1"})
+	if err != nil {
+		t.Fatalf("render with default: %v", err)
+	}
+	if rendered["daily_budget"] != "1000" {
+		t.Fatalf("expected default budget, got %q", rendered["daily_budget"])
+	}
+}
+
+func TestRenderRejectsMissingAndUnknownVariables(t *testing.T) {
+	t.Parallel()
+
+	template, err := New("launch-sale", KindAdSet, map[string]string{"daily_budget": "{{budget}}"}, nil)
+	if err != nil {
+		t.Fatalf("new template: %v", err)
+	}
+	if _, err := template.Render(nil); err == nil || !strings.Contains(err.Error(), "missing variable(s): budget") {
+		t.Fatalf("expected missing variable error, got %v", err)
+	}
+	if _, err := template.Render(map[string]string{"budget": "1", "bugdet": "2"}); err == nil || !strings.Contains(err.Error(), "does not use variable(s): bugdet") {
+		t.Fatalf("expected unknown variable error, got %v", err)
+	}
+}
+
+func TestParseVars(t *testing.T) {
+	t.Parallel()
+
+	vars, err := ParseVars([]string{"budget=5000", "name=Sale, 50%=off"})
+	if err != nil {
+		t.Fatalf("parse vars: %v", err)
+	}
+	if vars["budget"] != "5000" || vars["name"] != "Sale, 50%=off" {
+		t.Fatalf("unexpected vars %v", vars)
+	}
+	if _, err := ParseVars([]string{"budget"}); err == nil {
+		t.Fatal("expected missing '=' to fail")
+	}
+	if _, err := ParseVars([]string{"budget=1", "budget=2"}); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Fatalf("expected duplicate var error, got %v", err)
+	}
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "templates.json")
+	store, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("load missing store: %v", err)
+	}
+	if len(store.List()) != 0 {
+		t.Fatalf("expected empty store, got %v", store.List())
+	}
+
+	template, err := New("launch-sale", KindCampaign, map[string]string{"daily_budget": "{{budget}}"}, nil)
+	if err != nil {
+		t.Fatalf("new template: %v", err)
+	}
+	store.Templates[template.Name] = template
+	if err := SaveStore(path, store); err != nil {
+		t.Fatalf("save store: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat store: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 store, got %v", info.Mode().Perm())
+	}
+
+	loaded, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("load store: %v", err)
+	}
+	got, err := loaded.Get("launch-sale")
+	if err != nil {
+		t.Fatalf("get template: %v", err)
+	}
+	if !reflect.DeepEqual(got.Variables, []string{"budget"}) {
+		t.Fatalf("unexpected loaded template %#v", got)
+	}
+	if _, err := loaded.Get("missing"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("expected ErrTemplateNotFound, got %v", err)
+	}
+}
+
+func TestLoadStoreRejectsUnknownSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "templates.json")
+	if err := os.WriteFile(path, []byte(`{"schema_version":2,"templates":{}}`), 0o600); err != nil {
+		t.Fatalf("write store: %v", err)
+	}
+	if _, err := LoadStore(path); err == nil || !strings.Contains(err.Error(), "schema_version=2") {
+		t.Fatalf("expected schema version error, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/contracts"
+)
+
+// UpdateGoldenEnv rewrites golden files instead of comparing with them when
+// set to 1.
+const UpdateGoldenEnv = "META_UPDATE_GOLDEN"
+
+// DecodeEnvelope decodes one JSON envelope and checks it against the
+// published envelope contract.
+func DecodeEnvelope(t testing.TB, raw []byte) map[string]any {
+	t.Helper()
+	envelope := map[string]any{}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		t.Fatalf("decode envelope: %v\n%s", err, raw)
+	}
+	AssertContract(t, "envelope", raw)
+	return envelope
+}
+
+// AssertContract fails the test when raw does not match the published schema
+// called name, so a change to the machine contract shows up as a broken test
+// rather than a broken automation.
+func AssertContract(t testing.TB, name string, raw []byte) {
+	t.Helper()
+	if err := contracts.Validate(name, raw); err != nil {
+		t.Fatalf("%v\n%s", err, raw)
+	}
+}
+
+// AssertSuccess checks the command and success of an envelope.
+func AssertSuccess(t testing.TB, envelope map[string]any, command string) {
+	t.Helper()
+	if got, _ := envelope["command"].(string); got != command {
+		t.Fatalf("unexpected envelope command %q, want %q", got, command)
+	}
+	if success, ok := envelope["success"].(bool); !ok || !success {
+		t.Fatalf("expected successful envelope, got %+v", envelope)
+	}
+}
+
+// AssertFailure checks the command and error.class of a failed envelope.
+func AssertFailure(t testing.TB, envelope map[string]any, command string, class string) {
+	t.Helper()
+	if got, _ := envelope["command"].(string); got != command {
+		t.Fatalf("unexpected envelope command %q, want %q", got, command)
+	}
+	if success, ok := envelope["success"].(bool); !ok || success {
+		t.Fatalf("expected failed envelope, got %+v", envelope)
+	}
+	errorInfo, _ := envelope["error"].(map[string]any)
+	if got, _ := errorInfo["class"].(string); got != class {
+		t.Fatalf("unexpected error class %q, want %q: %+v", got, class, errorInfo)
+	}
+}
+
+// NormalizeEnvelope replaces the per-run timestamp and request_id of an
+// envelope with placeholders and indents it, so it can be kept as a golden
+// file.
+func NormalizeEnvelope(t testing.TB, raw []byte) []byte {
+	t.Helper()
+	envelope := DecodeEnvelope(t, raw)
+	for key, placeholder := range map[string]string{"timestamp": "<timestamp>", "request_id": "<request_id>"} {
+		if _, ok := envelope[key]; ok {
+			envelope[key] = placeholder
+		}
+	}
+	var normalized bytes.Buffer
+	encoder := json.NewEncoder(&normalized)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(envelope); err != nil {
+		t.Fatalf("encode envelope: %v", err)
+	}
+	return normalized.Bytes()
+}
+
+// AssertGolden compares got with the golden file at path, or rewrites it when
+// META_UPDATE_GOLDEN=1.
+func AssertGolden(t testing.TB, path string, got []byte) {
+	t.Helper()
+	if os.Getenv(UpdateGoldenEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("write golden %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden %s: %v; rerun with %s=1 to create it. Got:\n%s", path, err, UpdateGoldenEnv, got)
+	}
+	if !bytes.Equal(want, got) {
+		t.Fatalf("%s is stale; rerun with %s=1 and review the diff.\nwant:\n%s\ngot:\n%s", path, UpdateGoldenEnv, want, got)
+	}
+}
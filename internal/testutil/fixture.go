@@ -0,0 +1,171 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const (
+	fixtureExt = ".json"
+	goldenExt  = ".golden.json"
+)
+
+// Fixture is one recorded command run: the arguments, the Graph requests the
+// command is expected to make with their canned responses, and, next to it in
+// <name>.golden.json, the envelope it must print.
+type Fixture struct {
+	Name string `json:"-"`
+	Path string `json:"-"`
+
+	Description string `json:"description,omitempty"`
+	// Profile is the profile the command runs as; empty selects "prod".
+	Profile string `json:"profile,omitempty"`
+	// Args follow the command under test; ${NAME} is replaced by the vars
+	// given to ExpandArgs.
+	Args []string `json:"args"`
+	// Stdin is what the command reads from standard input.
+	Stdin string `json:"stdin,omitempty"`
+	// Files are written by WriteFiles before the run, keyed by path relative
+	// to the run's directory or, after expansion, an absolute path such as
+	// ${HOME}/.meta/metrics.json; their contents are expanded like Args.
+	Files     map[string]string `json:"files,omitempty"`
+	Exchanges []Exchange        `json:"exchanges"`
+}
+
+// Exchange is one Graph request of a fixture and its response.
+type Exchange struct {
+	Method string `json:"method"`
+	// Path is the URL path the request must hit, such as /v25.0/777.
+	Path string `json:"path"`
+	// Params must be present in the query or form body with these values.
+	Params   map[string]string `json:"params,omitempty"`
+	Status   int               `json:"status,omitempty"`
+	Response json.RawMessage   `json:"response"`
+}
+
+// LoadFixtures reads every fixture in dir, sorted by name. A directory
+// without fixtures fails the test so a renamed directory is not silently
+// skipped.
+func LoadFixtures(t testing.TB, dir string) []*Fixture {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read fixtures: %v", err)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, fixtureExt) || strings.HasSuffix(name, goldenExt) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		t.Fatalf("no fixtures in %s", dir)
+	}
+
+	fixtures := make([]*Fixture, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read fixture %s: %v", path, err)
+		}
+		fixture := &Fixture{}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(fixture); err != nil {
+			t.Fatalf("decode fixture %s: %v", path, err)
+		}
+		fixture.Name = strings.TrimSuffix(name, fixtureExt)
+		fixture.Path = path
+		if fixture.Profile == "" {
+			fixture.Profile = "prod"
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures
+}
+
+// ExpandArgs returns Args with every ${NAME} replaced by vars[NAME].
+func (f *Fixture) ExpandArgs(vars map[string]string) []string {
+	args := make([]string, len(f.Args))
+	for index, arg := range f.Args {
+		args[index] = expand(arg, vars)
+	}
+	return args
+}
+
+// WriteFiles writes the fixture's Files under dir.
+func (f *Fixture) WriteFiles(t testing.TB, dir string, vars map[string]string) {
+	t.Helper()
+	for name, content := range f.Files {
+		path := filepath.FromSlash(expand(name, vars))
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("%s: create directory for %s: %v", f.Name, name, err)
+		}
+		if err := os.WriteFile(path, []byte(expand(content, vars)), 0o644); err != nil {
+			t.Fatalf("%s: write %s: %v", f.Name, name, err)
+		}
+	}
+}
+
+func expand(value string, vars map[string]string) string {
+	return os.Expand(value, func(name string) string {
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return "${" + name + "}"
+	})
+}
+
+// HTTPClient returns a queued client that answers the exchanges in order and
+// checks each request's method, path and params.
+func (f *Fixture) HTTPClient(t testing.TB) *QueuedHTTPClient {
+	responses := make([]Response, 0, len(f.Exchanges))
+	for index, exchange := range f.Exchanges {
+		index, exchange := index, exchange
+		responses = append(responses, Response{
+			StatusCode: exchange.Status,
+			Body:       string(exchange.Response),
+			Assert: func(t testing.TB, req *http.Request, body string) {
+				t.Helper()
+				call := Call{Method: req.Method, URL: req.URL.String(), Body: body}
+				if !strings.EqualFold(call.Method, exchange.Method) || call.Path() != exchange.Path {
+					t.Fatalf("%s exchange %d: expected %s %s, got %s %s", f.Name, index+1, exchange.Method, exchange.Path, call.Method, call.Path())
+				}
+				params := call.Params()
+				for key, want := range exchange.Params {
+					if got := params.Get(key); got != want {
+						t.Fatalf("%s exchange %d: expected param %s=%q, got %q", f.Name, index+1, key, want, got)
+					}
+				}
+			},
+		})
+	}
+	return NewQueuedHTTPClient(t, responses...)
+}
+
+// AssertEnvelope compares the envelope the command printed, stdout on
+// success and stderr on failure, with the fixture's golden file.
+func (f *Fixture) AssertEnvelope(t testing.TB, stdout []byte, stderr []byte, err error) {
+	t.Helper()
+	printed := stdout
+	if err != nil {
+		printed = stderr
+	}
+	if len(bytes.TrimSpace(printed)) == 0 {
+		t.Fatalf("%s: command printed no envelope (error: %v)", f.Name, err)
+	}
+	AssertGolden(t, strings.TrimSuffix(f.Path, fixtureExt)+goldenExt, NormalizeEnvelope(t, printed))
+}
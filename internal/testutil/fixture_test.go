@@ -0,0 +1,104 @@
+package testutil
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFixtureClientAnswersExchangesInOrder(t *testing.T) {
+	dir := t.TempDir()
+	fixture := `{
+  "args": ["--campaign-id", "777", "--schema-dir", "${SCHEMA_DIR}", "${UNSET}"],
+  "exchanges": [
+    {"method": "GET", "path": "/v25.0/777", "params": {"fields": "status"}, "response": {"status": "ACTIVE"}},
+    {"method": "POST", "path": "/v25.0/777", "params": {"status": "PAUSED"}, "status": 400, "response": {"error": {"code": 100}}}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(dir, "pause.json"), []byte(fixture), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pause.golden.json"), []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("write golden: %v", err)
+	}
+
+	fixtures := LoadFixtures(t, dir)
+	if len(fixtures) != 1 || fixtures[0].Name != "pause" || fixtures[0].Profile != "prod" {
+		t.Fatalf("unexpected fixtures %+v", fixtures)
+	}
+	args := fixtures[0].ExpandArgs(map[string]string{"SCHEMA_DIR": "/tmp/schema"})
+	if strings.Join(args, " ") != "--campaign-id 777 --schema-dir /tmp/schema ${UNSET}" {
+		t.Fatalf("unexpected args %v", args)
+	}
+
+	client := fixtures[0].HTTPClient(t)
+	read, _ := http.NewRequest(http.MethodGet, "https://graph.example.com/v25.0/777?fields=status&access_token=x", nil)
+	if response, err := client.Do(read); err != nil || response.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected read response %v, %v", response, err)
+	}
+	write, _ := http.NewRequest(http.MethodPost, "https://graph.example.com/v25.0/777", strings.NewReader("status=PAUSED&access_token=x"))
+	if response, err := client.Do(write); err != nil || response.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected write response %v, %v", response, err)
+	}
+	client.AssertDone(t)
+	if got := client.LastCall().Params().Get("status"); got != "PAUSED" {
+		t.Fatalf("unexpected last call %+v", client.LastCall())
+	}
+}
+
+func TestFixtureWriteFilesExpandsPathsAndContents(t *testing.T) {
+	dir, home := t.TempDir(), t.TempDir()
+	fixture := &Fixture{Name: "push", Files: map[string]string{
+		"events/in.json":             `{"event_time": ${NOW}}`,
+		"${HOME}/.meta/metrics.json": "{}",
+	}}
+	fixture.WriteFiles(t, dir, map[string]string{"HOME": home, "NOW": "1700000000"})
+
+	if data, err := os.ReadFile(filepath.Join(dir, "events", "in.json")); err != nil || string(data) != `{"event_time": 1700000000}` {
+		t.Fatalf("unexpected relative file %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".meta", "metrics.json")); err != nil {
+		t.Fatalf("expected the absolute file under home: %v", err)
+	}
+}
+
+func TestNormalizeEnvelopeReplacesPerRunFields(t *testing.T) {
+	got := NormalizeEnvelope(t, []byte(`{"contract_version":"1.0","command":"meta campaign pause","timestamp":"2026-10-16T09:00:00Z","request_id":"abc","success":true}`))
+	want := "{\n  \"command\": \"meta campaign pause\",\n  \"contract_version\": \"1.0\",\n  \"request_id\": \"<request_id>\",\n  \"success\": true,\n  \"timestamp\": \"<timestamp>\"\n}\n"
+	if string(got) != want {
+		t.Fatalf("unexpected normalized envelope:\n%s", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "nested", "envelope.golden.json")
+	t.Setenv(UpdateGoldenEnv, "1")
+	AssertGolden(t, path, got)
+	t.Setenv(UpdateGoldenEnv, "")
+	AssertGolden(t, path, got)
+}
+
+func TestDecodeEnvelopeChecksTheEnvelopeContract(t *testing.T) {
+	recorder := &fatalRecorder{TB: t}
+	func() {
+		defer func() { _ = recover() }()
+		DecodeEnvelope(recorder, []byte(`{"contract_version":"1.0","command":"meta campaign pause","success":"yes"}`))
+	}()
+	if !strings.Contains(recorder.message, `missing required property "timestamp"`) || !strings.Contains(recorder.message, "#/success: expected boolean, got string") {
+		t.Fatalf("expected contract violations, got %q", recorder.message)
+	}
+}
+
+// fatalRecorder captures Fatalf instead of failing the test.
+type fatalRecorder struct {
+	testing.TB
+	message string
+}
+
+func (r *fatalRecorder) Helper() {}
+
+func (r *fatalRecorder) Fatalf(format string, args ...any) {
+	r.message = fmt.Sprintf(format, args...)
+	panic(r.message)
+}
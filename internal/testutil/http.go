@@ -1,11 +1,159 @@
 package testutil
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
 )
 
 // NewJSONServer creates an httptest server from a callback to keep integration tests concise.
 func NewJSONServer(handler func(http.ResponseWriter, *http.Request)) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(handler))
 }
+
+// Response is one canned answer of a stub or queued client. A zero
+// StatusCode means 200; Err fails the request like a network error.
+type Response struct {
+	StatusCode int
+	Body       string
+	Err        error
+	// Assert checks the request it answers; body is the request body.
+	Assert func(t testing.TB, req *http.Request, body string)
+}
+
+// Call is one request a client received.
+type Call struct {
+	Method string
+	URL    string
+	Body   string
+}
+
+// Path is the URL path of the call, such as /v25.0/act_1/campaigns.
+func (c Call) Path() string {
+	parsed, err := url.Parse(c.URL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Path
+}
+
+// Params merges the query and the form body of the call.
+func (c Call) Params() url.Values {
+	params := url.Values{}
+	if parsed, err := url.Parse(c.URL); err == nil {
+		for key, values := range parsed.Query() {
+			params[key] = append(params[key], values...)
+		}
+	}
+	if form, err := url.ParseQuery(c.Body); err == nil {
+		for key, values := range form {
+			params[key] = append(params[key], values...)
+		}
+	}
+	return params
+}
+
+// recorder keeps the calls of a client; clients may be shared by goroutines.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(t testing.TB, req *http.Request) (Call, int) {
+	call := Call{Method: req.Method, URL: req.URL.String()}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		call.Body = string(body)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+	return call, len(r.calls)
+}
+
+// Calls returns the requests received so far, in order.
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// LastCall returns the latest request, or a zero Call before the first one.
+func (r *recorder) LastCall() Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.calls) == 0 {
+		return Call{}
+	}
+	return r.calls[len(r.calls)-1]
+}
+
+// StubHTTPClient answers every request with the same response, for commands
+// whose requests all get the same answer.
+type StubHTTPClient struct {
+	recorder
+	t        testing.TB
+	response Response
+}
+
+func NewStubHTTPClient(t testing.TB, response Response) *StubHTTPClient {
+	return &StubHTTPClient{t: t, response: response}
+}
+
+func (c *StubHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	call, _ := c.record(c.t, req)
+	return answer(c.t, req, call, c.response)
+}
+
+// QueuedHTTPClient answers requests with its responses in order and fails the
+// test on a request past the last one.
+type QueuedHTTPClient struct {
+	recorder
+	t         testing.TB
+	responses []Response
+}
+
+func NewQueuedHTTPClient(t testing.TB, responses ...Response) *QueuedHTTPClient {
+	return &QueuedHTTPClient{t: t, responses: responses}
+}
+
+func (c *QueuedHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	call, count := c.record(c.t, req)
+	if count > len(c.responses) {
+		c.t.Fatalf("unexpected graph request %d: %s %s", count, req.Method, req.URL.String())
+	}
+	return answer(c.t, req, call, c.responses[count-1])
+}
+
+// AssertDone fails the test when queued responses were left unused.
+func (c *QueuedHTTPClient) AssertDone(t testing.TB) {
+	t.Helper()
+	if calls := len(c.Calls()); calls != len(c.responses) {
+		t.Fatalf("expected %d graph request(s), got %d", len(c.responses), calls)
+	}
+}
+
+func answer(t testing.TB, req *http.Request, call Call, response Response) (*http.Response, error) {
+	if response.Assert != nil {
+		response.Assert(t, req, call.Body)
+	}
+	if response.Err != nil {
+		return nil, response.Err
+	}
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(response.Body)),
+	}, nil
+}
@@ -0,0 +1,181 @@
+package testutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// ScaffoldOptions describe the fixture test to scaffold for one command.
+type ScaffoldOptions struct {
+	// Command is the command path without "meta", such as "campaign pause".
+	Command string
+	// Dir is the package directory of the command, internal/cli/cmd.
+	Dir string
+	// Constructor builds the command family; empty selects New<Family>Command.
+	Constructor string
+	// Dependencies swaps the family's credential loader and Graph client,
+	// func(t, loadFn, clientFn); empty selects use<Family>Dependencies.
+	Dependencies string
+	// SchemaPack writes a schema pack for ${SCHEMA_DIR}; empty selects
+	// write<Family>SchemaPack when the package has one.
+	SchemaPack string
+}
+
+// ScaffoldFile is one file Scaffold would write.
+type ScaffoldFile struct {
+	Path    string
+	Content []byte
+}
+
+type scaffoldData struct {
+	Command      string
+	TestName     string
+	FixtureDir   string
+	Subcommands  []string
+	Constructor  string
+	Dependencies string
+	SchemaPack   string
+}
+
+var scaffoldTestTemplate = template.Must(template.New("test").Parse(`package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/testutil"
+)
+
+// {{.TestName}} runs every fixture in
+// testdata/fixtures/{{.FixtureDir}} through ` + "`meta {{.Command}}`" + ` and
+// compares the printed envelope with the fixture's golden file. Rerun with
+// META_UPDATE_GOLDEN=1 to record goldens.
+func {{.TestName}}(t *testing.T) {
+	for _, fixture := range testutil.LoadFixtures(t, filepath.Join("testdata", "fixtures", "{{.FixtureDir}}")) {
+		t.Run(fixture.Name, func(t *testing.T) {
+			httpClient := fixture.HTTPClient(t)
+			{{.Dependencies}}(t, fixtureCredentials(fixture), fixtureGraphClient(httpClient))
+{{- if .SchemaPack}}
+			vars := map[string]string{"SCHEMA_DIR": {{.SchemaPack}}(t)}
+{{- else}}
+			vars := map[string]string{}
+{{- end}}
+
+			args := append([]string{ {{- range $index, $sub := .Subcommands}}{{if $index}}, {{end}}{{printf "%q" $sub}}{{end -}} }, fixture.ExpandArgs(vars)...)
+			stdout, stderr, err := runFixtureCommand({{.Constructor}}(testRuntime(fixture.Profile)), args)
+			fixture.AssertEnvelope(t, stdout, stderr, err)
+			httpClient.AssertDone(t)
+		})
+	}
+}
+`))
+
+// Scaffold returns the test file and starter fixture of a fixture-driven
+// command test. The constructor and helpers it calls must exist in Dir.
+func Scaffold(options ScaffoldOptions) ([]ScaffoldFile, error) {
+	words := strings.Fields(strings.TrimPrefix(strings.TrimSpace(options.Command), "meta "))
+	if len(words) == 0 {
+		return nil, errors.New("command is required")
+	}
+	if strings.TrimSpace(options.Dir) == "" {
+		return nil, errors.New("package directory is required")
+	}
+	source, err := packageSource(options.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	family := goName(words[0])
+	data := scaffoldData{
+		Command:      strings.Join(words, " "),
+		TestName:     "Test" + goName(strings.Join(words, " ")) + "Fixtures",
+		FixtureDir:   strings.ReplaceAll(strings.Join(words, "_"), "-", "_"),
+		Subcommands:  words[1:],
+		Constructor:  firstNonEmpty(options.Constructor, "New"+family+"Command"),
+		Dependencies: firstNonEmpty(options.Dependencies, "use"+family+"Dependencies"),
+		SchemaPack:   options.SchemaPack,
+	}
+	if !definesFunc(source, data.Constructor) {
+		return nil, fmt.Errorf("%s is not defined in %s; pass the family constructor", data.Constructor, options.Dir)
+	}
+	if !definesFunc(source, data.Dependencies) {
+		return nil, fmt.Errorf("%s is not defined in %s; pass a func(t, loadFn, clientFn) dependency helper", data.Dependencies, options.Dir)
+	}
+	if data.SchemaPack == "" && definesFunc(source, "write"+family+"SchemaPack") {
+		data.SchemaPack = "write" + family + "SchemaPack"
+	}
+	if data.SchemaPack != "" && !definesFunc(source, data.SchemaPack) {
+		return nil, fmt.Errorf("%s is not defined in %s", data.SchemaPack, options.Dir)
+	}
+
+	var test bytes.Buffer
+	if err := scaffoldTestTemplate.Execute(&test, data); err != nil {
+		return nil, fmt.Errorf("render test: %w", err)
+	}
+	formatted, err := format.Source(test.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format test: %w", err)
+	}
+	fixture := "{\n  \"description\": \"TODO: describe the scenario\",\n  \"args\": []"
+	if data.SchemaPack != "" {
+		fixture = "{\n  \"description\": \"TODO: describe the scenario\",\n  \"args\": [\"--schema-dir\", \"${SCHEMA_DIR}\"]"
+	}
+	fixture += ",\n  \"exchanges\": []\n}\n"
+
+	return []ScaffoldFile{
+		{Path: filepath.Join(options.Dir, data.FixtureDir+"_fixture_test.go"), Content: formatted},
+		{Path: filepath.Join(options.Dir, "testdata", "fixtures", data.FixtureDir, "success"+fixtureExt), Content: []byte(fixture)},
+	}, nil
+}
+
+// packageSource concatenates the Go files of dir, tests included, where the
+// helpers a scaffolded test calls live.
+func packageSource(dir string) (string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	if len(paths) == 0 {
+		return "", fmt.Errorf("no Go files in %s", dir)
+	}
+	var source strings.Builder
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		source.Write(raw)
+		source.WriteByte('\n')
+	}
+	return source.String(), nil
+}
+
+func definesFunc(source string, name string) bool {
+	return regexp.MustCompile(`(?m)^func ` + regexp.QuoteMeta(name) + `\(`).MatchString(source)
+}
+
+// goName turns "campaign pause" or "ad-preview" into CampaignPause or
+// AdPreview.
+func goName(value string) string {
+	var name strings.Builder
+	for _, word := range strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == '-' || r == '_' }) {
+		name.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return name.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package testutil
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePackage(t *testing.T, source string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "helpers_test.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write package: %v", err)
+	}
+	return dir
+}
+
+func TestScaffoldRendersATestForTheCommandFamily(t *testing.T) {
+	dir := writePackage(t, "package cmd\n\nfunc NewAdSetCommand() {}\nfunc useAdsetDependencies() {}\nfunc writeAdsetSchemaPack() {}\n")
+
+	files, err := Scaffold(ScaffoldOptions{Command: "meta adset budget-shift", Dir: dir, Constructor: "NewAdSetCommand"})
+	if err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+	if len(files) != 2 || files[0].Path != filepath.Join(dir, "adset_budget_shift_fixture_test.go") || files[1].Path != filepath.Join(dir, "testdata", "fixtures", "adset_budget_shift", "success.json") {
+		t.Fatalf("unexpected files %+v", files)
+	}
+	test := string(files[0].Content)
+	for _, want := range []string{
+		"func TestAdsetBudgetShiftFixtures(t *testing.T) {",
+		`filepath.Join("testdata", "fixtures", "adset_budget_shift")`,
+		"useAdsetDependencies(t, fixtureCredentials(fixture), fixtureGraphClient(httpClient))",
+		`vars := map[string]string{"SCHEMA_DIR": writeAdsetSchemaPack(t)}`,
+		`args := append([]string{"budget-shift"}, fixture.ExpandArgs(vars)...)`,
+		"runFixtureCommand(NewAdSetCommand(testRuntime(fixture.Profile)), args)",
+	} {
+		if !strings.Contains(test, want) {
+			t.Fatalf("scaffolded test is missing %q:\n%s", want, test)
+		}
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "test.go", files[0].Content, 0); err != nil {
+		t.Fatalf("scaffolded test does not parse: %v", err)
+	}
+	if !strings.Contains(string(files[1].Content), `"${SCHEMA_DIR}"`) {
+		t.Fatalf("starter fixture should pass the schema dir: %s", files[1].Content)
+	}
+}
+
+func TestScaffoldRequiresTheFamilyHelpers(t *testing.T) {
+	dir := writePackage(t, "package cmd\n\nfunc NewPageCommand() {}\n")
+	if _, err := Scaffold(ScaffoldOptions{Command: "page list", Dir: dir}); err == nil || !strings.Contains(err.Error(), "usePageDependencies is not defined") {
+		t.Fatalf("expected missing helper error, got %v", err)
+	}
+	if _, err := Scaffold(ScaffoldOptions{Command: "ig media list", Dir: dir}); err == nil || !strings.Contains(err.Error(), "NewIgCommand is not defined") {
+		t.Fatalf("expected missing constructor error, got %v", err)
+	}
+	if _, err := Scaffold(ScaffoldOptions{Dir: dir}); err == nil {
+		t.Fatal("expected an error without a command")
+	}
+}
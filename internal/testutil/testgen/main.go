@@ -0,0 +1,60 @@
+// Command testgen scaffolds a fixture-driven test for a meta command: a test
+// that runs every fixture in testdata/fixtures/<command> through the command
+// and compares its envelope with a golden file, plus a starter fixture.
+//
+//	go run ./internal/testutil/testgen -command "campaign pause"
+//	META_UPDATE_GOLDEN=1 go test ./internal/cli/cmd -run TestCampaignPauseFixtures
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bilalbayram/metacli/internal/testutil"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "testgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	options := testutil.ScaffoldOptions{}
+	flags := flag.NewFlagSet("testgen", flag.ContinueOnError)
+	flags.StringVar(&options.Command, "command", "", "Command path without meta, e.g. \"campaign pause\"")
+	flags.StringVar(&options.Dir, "dir", filepath.Join("internal", "cli", "cmd"), "Package directory of the command")
+	flags.StringVar(&options.Constructor, "constructor", "", "Family constructor (default New<Family>Command)")
+	flags.StringVar(&options.Dependencies, "deps", "", "Dependency helper func(t, loadFn, clientFn) (default use<Family>Dependencies)")
+	flags.StringVar(&options.SchemaPack, "schema-pack", "", "Schema pack helper for ${SCHEMA_DIR} (default write<Family>SchemaPack when defined)")
+	force := flags.Bool("force", false, "Overwrite existing files")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	files, err := testutil.Scaffold(options)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if _, err := os.Stat(file.Path); err == nil && !*force {
+			return fmt.Errorf("%s already exists; pass -force to overwrite it", file.Path)
+		} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file.Path, file.Content, 0o644); err != nil {
+			return err
+		}
+		fmt.Println("wrote", file.Path)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+// Package transport owns the process-wide HTTP transport used for Graph API
+// traffic. One invocation may issue hundreds of requests (clone, merge, batch,
+// paginated exports); sharing a tuned transport keeps their connections alive
+// and multiplexed over HTTP/2 instead of dialing and handshaking per client.
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds a single request, matching the per-client timeout
+	// used before the transport was shared.
+	DefaultTimeout = 30 * time.Second
+
+	maxIdleConns        = 100
+	maxIdleConnsPerHost = 32
+	idleConnTimeout     = 90 * time.Second
+	tlsHandshakeTimeout = 10 * time.Second
+)
+
+// Stats counts what the shared transport did during this process.
+type Stats struct {
+	Requests          int64 `json:"requests"`
+	NewConnections    int64 `json:"new_connections"`
+	ReusedConnections int64 `json:"reused_connections"`
+	IdleReuses        int64 `json:"idle_reuses"`
+	TLSHandshakes     int64 `json:"tls_handshakes"`
+	HTTP2Responses    int64 `json:"http2_responses"`
+	HTTP1Responses    int64 `json:"http1_responses"`
+	FailedRoundTrips  int64 `json:"failed_round_trips"`
+}
+
+// ErrOffline is returned for a request whose context was marked offline; it
+// never reaches the network.
+var ErrOffline = errors.New("network access is disabled by --offline")
+
+type offlineKey struct{}
+
+// WithOffline marks ctx offline: the shared transport refuses every request
+// made under it.
+func WithOffline(ctx context.Context) context.Context {
+	return context.WithValue(ctx, offlineKey{}, true)
+}
+
+// IsOffline reports whether ctx was marked offline.
+func IsOffline(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	offline, _ := ctx.Value(offlineKey{}).(bool)
+	return offline
+}
+
+// RefuseOffline wraps base, http.DefaultTransport when nil, so requests made
+// under an offline context fail with ErrOffline. Clients that do not use the
+// shared transport, such as manifest and update downloads, wrap theirs.
+func RefuseOffline(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return offlineTransport{base: base}
+}
+
+type offlineTransport struct {
+	base http.RoundTripper
+}
+
+func (t offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if IsOffline(req.Context()) {
+		return nil, ErrOffline
+	}
+	return t.base.RoundTrip(req)
+}
+
+var (
+	sharedOnce sync.Once
+	shared     *http.Transport
+	counted    *countingTransport
+)
+
+// Shared returns the process-wide tuned transport.
+func Shared() *http.Transport {
+	sharedOnce.Do(initShared)
+	return shared
+}
+
+// NewClient returns an HTTP client over the shared transport. Clients are cheap;
+// the connection pool behind them is not, and is what they share.
+func NewClient(timeout time.Duration) *http.Client {
+	sharedOnce.Do(initShared)
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &http.Client{Timeout: timeout, Transport: counted}
+}
+
+// Snapshot returns the transport counters collected so far.
+func Snapshot() Stats {
+	sharedOnce.Do(initShared)
+	return Stats{
+		Requests:          counted.requests.Load(),
+		NewConnections:    counted.newConns.Load(),
+		ReusedConnections: counted.reusedConns.Load(),
+		IdleReuses:        counted.idleReuses.Load(),
+		TLSHandshakes:     counted.tlsHandshakes.Load(),
+		HTTP2Responses:    counted.http2.Load(),
+		HTTP1Responses:    counted.http1.Load(),
+		FailedRoundTrips:  counted.failed.Load(),
+	}
+}
+
+func initShared() {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if ok {
+		shared = base.Clone()
+	} else {
+		shared = &http.Transport{Proxy: http.ProxyFromEnvironment}
+	}
+	shared.ForceAttemptHTTP2 = true
+	shared.MaxIdleConns = maxIdleConns
+	shared.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	shared.IdleConnTimeout = idleConnTimeout
+	shared.TLSHandshakeTimeout = tlsHandshakeTimeout
+	counted = &countingTransport{base: shared}
+}
+
+// countingTransport records connection reuse through httptrace without
+// changing how requests are sent.
+type countingTransport struct {
+	base http.RoundTripper
+
+	requests      atomic.Int64
+	newConns      atomic.Int64
+	reusedConns   atomic.Int64
+	idleReuses    atomic.Int64
+	tlsHandshakes atomic.Int64
+	http2         atomic.Int64
+	http1         atomic.Int64
+	failed        atomic.Int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if IsOffline(req.Context()) {
+		return nil, ErrOffline
+	}
+	t.requests.Add(1)
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				t.reusedConns.Add(1)
+			} else {
+				t.newConns.Add(1)
+			}
+			if info.WasIdle {
+				t.idleReuses.Add(1)
+			}
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				t.tlsHandshakes.Add(1)
+			}
+		},
+	}
+	res, err := t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+	if err != nil {
+		t.failed.Add(1)
+		return nil, err
+	}
+	if res.ProtoMajor == 2 {
+		t.http2.Add(1)
+	} else {
+		t.http1.Add(1)
+	}
+	return res, nil
+}
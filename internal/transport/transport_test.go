@@ -0,0 +1,72 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSharedClientsReuseConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"id":"1"}`)
+	}))
+	defer server.Close()
+
+	before := Snapshot()
+	// Separate clients, as separate graph.Client values would create, still
+	// share one connection pool.
+	for index := 0; index < 3; index++ {
+		res, err := NewClient(0).Get(server.URL)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		_, _ = io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+	}
+	after := Snapshot()
+
+	if got := after.Requests - before.Requests; got != 3 {
+		t.Fatalf("expected 3 requests, got %d", got)
+	}
+	if got := after.NewConnections - before.NewConnections; got != 1 {
+		t.Fatalf("expected a single new connection, got %d", got)
+	}
+	if got := after.ReusedConnections - before.ReusedConnections; got != 2 {
+		t.Fatalf("expected 2 reused connections, got %d", got)
+	}
+	if got := after.HTTP1Responses - before.HTTP1Responses; got != 3 {
+		t.Fatalf("expected 3 HTTP/1.1 responses from the plain-text test server, got %d", got)
+	}
+}
+
+func TestSharedTransportIsTuned(t *testing.T) {
+	shared := Shared()
+	if !shared.ForceAttemptHTTP2 || shared.MaxIdleConnsPerHost != maxIdleConnsPerHost || shared.IdleConnTimeout != idleConnTimeout {
+		t.Fatalf("unexpected transport settings: http2=%v idle_per_host=%d idle_timeout=%s", shared.ForceAttemptHTTP2, shared.MaxIdleConnsPerHost, shared.IdleConnTimeout)
+	}
+	if NewClient(0).Timeout != DefaultTimeout {
+		t.Fatalf("expected default timeout %s", DefaultTimeout)
+	}
+}
+
+func TestOfflineContextRefusesRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("offline request reached the server")
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(WithOffline(context.Background()), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	before := Snapshot()
+	if _, err := NewClient(0).Do(req); !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline, got %v", err)
+	}
+	if got := Snapshot().Requests - before.Requests; got != 0 {
+		t.Fatalf("expected no counted requests, got %d", got)
+	}
+}
@@ -0,0 +1,465 @@
+package wa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	MessagingProduct = "whatsapp"
+
+	MessageTypeText     = "text"
+	MessageTypeTemplate = "template"
+
+	TemplateCategoryMarketing      = "MARKETING"
+	TemplateCategoryUtility        = "UTILITY"
+	TemplateCategoryAuthentication = "AUTHENTICATION"
+
+	DefaultTemplateLanguage = "en_US"
+)
+
+var (
+	DefaultTemplateFields    = []string{"id", "name", "language", "category", "status", "rejected_reason", "quality_score"}
+	DefaultPhoneNumberFields = []string{"id", "display_phone_number", "verified_name", "quality_rating", "messaging_limit_tier", "code_verification_status", "status"}
+
+	templateCategories = map[string]struct{}{
+		TemplateCategoryMarketing:      {},
+		TemplateCategoryUtility:        {},
+		TemplateCategoryAuthentication: {},
+	}
+	templateStatuses = map[string]struct{}{
+		"APPROVED": {}, "PENDING": {}, "REJECTED": {}, "PAUSED": {}, "DISABLED": {}, "IN_APPEAL": {},
+	}
+
+	templateNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,512}$`)
+	recipientPattern    = regexp.MustCompile(`^[0-9]{8,15}$`)
+)
+
+type SendMessageOptions struct {
+	PhoneNumberID      string
+	To                 string
+	Text               string
+	PreviewURL         bool
+	TemplateName       string
+	TemplateLanguage   string
+	TemplateComponents []map[string]any
+}
+
+type SendMessageResult struct {
+	Operation     string         `json:"operation"`
+	PhoneNumberID string         `json:"phone_number_id"`
+	RequestPath   string         `json:"request_path"`
+	To            string         `json:"to"`
+	Type          string         `json:"type"`
+	MessageIDs    []string       `json:"message_ids"`
+	Response      map[string]any `json:"response"`
+}
+
+type ListTemplatesOptions struct {
+	WABAID     string
+	Name       string
+	Status     string
+	Fields     []string
+	Limit      int
+	FollowNext bool
+}
+
+type ListTemplatesResult struct {
+	WABAID      string                  `json:"waba_id"`
+	RequestPath string                  `json:"request_path"`
+	Templates   []map[string]any        `json:"templates"`
+	Paging      *graph.PaginationResult `json:"paging,omitempty"`
+}
+
+type CreateTemplateOptions struct {
+	WABAID     string
+	Name       string
+	Language   string
+	Category   string
+	Components []map[string]any
+}
+
+type CreateTemplateResult struct {
+	Operation   string         `json:"operation"`
+	WABAID      string         `json:"waba_id"`
+	RequestPath string         `json:"request_path"`
+	Name        string         `json:"name"`
+	Language    string         `json:"language"`
+	Category    string         `json:"category"`
+	TemplateID  string         `json:"template_id,omitempty"`
+	Status      string         `json:"status,omitempty"`
+	Response    map[string]any `json:"response"`
+}
+
+type TemplateStatusOptions struct {
+	TemplateID string
+	WABAID     string
+	Name       string
+}
+
+type ListPhoneNumbersOptions struct {
+	WABAID     string
+	Fields     []string
+	Limit      int
+	FollowNext bool
+}
+
+type ListPhoneNumbersResult struct {
+	WABAID       string                  `json:"waba_id"`
+	RequestPath  string                  `json:"request_path"`
+	PhoneNumbers []map[string]any        `json:"phone_numbers"`
+	Paging       *graph.PaginationResult `json:"paging,omitempty"`
+}
+
+type Service struct {
+	Client *graph.Client
+}
+
+func New(client *graph.Client) *Service {
+	if client == nil {
+		client = graph.NewClient(nil, "")
+	}
+	return &Service{Client: client}
+}
+
+// BuildMessagePayload validates a send request and returns the Cloud API form body.
+// Exactly one of Text or TemplateName selects the message type.
+func BuildMessagePayload(options SendMessageOptions) (map[string]string, error) {
+	to := strings.TrimPrefix(strings.TrimSpace(options.To), "+")
+	if !recipientPattern.MatchString(to) {
+		return nil, fmt.Errorf("invalid recipient %q: expected an international phone number with country code (digits only)", options.To)
+	}
+	text := strings.TrimSpace(options.Text)
+	templateName := strings.TrimSpace(options.TemplateName)
+
+	payload := map[string]string{
+		"messaging_product": MessagingProduct,
+		"recipient_type":    "individual",
+		"to":                to,
+	}
+	switch {
+	case text != "" && templateName != "":
+		return nil, errors.New("use either a text body or a template, not both")
+	case text != "":
+		if len(options.TemplateComponents) > 0 {
+			return nil, errors.New("template components require a template message")
+		}
+		encoded, err := json.Marshal(map[string]any{"body": text, "preview_url": options.PreviewURL})
+		if err != nil {
+			return nil, fmt.Errorf("encode text message: %w", err)
+		}
+		payload["type"] = MessageTypeText
+		payload["text"] = string(encoded)
+	case templateName != "":
+		if !templateNamePattern.MatchString(templateName) {
+			return nil, fmt.Errorf("invalid template name %q: expected lowercase letters, digits, and underscores", options.TemplateName)
+		}
+		language := strings.TrimSpace(options.TemplateLanguage)
+		if language == "" {
+			language = DefaultTemplateLanguage
+		}
+		template := map[string]any{
+			"name":     templateName,
+			"language": map[string]string{"code": language},
+		}
+		if len(options.TemplateComponents) > 0 {
+			template["components"] = options.TemplateComponents
+		}
+		encoded, err := json.Marshal(template)
+		if err != nil {
+			return nil, fmt.Errorf("encode template message: %w", err)
+		}
+		payload["type"] = MessageTypeTemplate
+		payload["template"] = string(encoded)
+	default:
+		return nil, errors.New("a text body or template name is required")
+	}
+	return payload, nil
+}
+
+func (s *Service) SendMessage(ctx context.Context, version string, token string, appSecret string, options SendMessageOptions) (*SendMessageResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("wa service client is required")
+	}
+	phoneNumberID, err := normalizeGraphID("phone number id", options.PhoneNumberID)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := BuildMessagePayload(options)
+	if err != nil {
+		return nil, err
+	}
+
+	path := phoneNumberID + "/messages"
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:      "POST",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Form:        payload,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messageIDs := make([]string, 0, 1)
+	messages, _ := response.Body["messages"].([]any)
+	for _, raw := range messages {
+		message, _ := raw.(map[string]any)
+		if id := strings.TrimSpace(stringValue(message["id"])); id != "" {
+			messageIDs = append(messageIDs, id)
+		}
+	}
+	if len(messageIDs) == 0 {
+		return nil, errors.New("wa send response did not include a message id")
+	}
+	return &SendMessageResult{
+		Operation:     "send_message",
+		PhoneNumberID: phoneNumberID,
+		RequestPath:   path,
+		To:            payload["to"],
+		Type:          payload["type"],
+		MessageIDs:    messageIDs,
+		Response:      response.Body,
+	}, nil
+}
+
+func (s *Service) ListTemplates(ctx context.Context, version string, token string, appSecret string, options ListTemplatesOptions) (*ListTemplatesResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("wa service client is required")
+	}
+	wabaID, err := normalizeGraphID("waba id", options.WABAID)
+	if err != nil {
+		return nil, err
+	}
+	fields := options.Fields
+	if len(fields) == 0 {
+		fields = DefaultTemplateFields
+	}
+	query := map[string]string{"fields": strings.Join(fields, ",")}
+	if name := strings.TrimSpace(options.Name); name != "" {
+		query["name"] = name
+	}
+	if status := strings.ToUpper(strings.TrimSpace(options.Status)); status != "" {
+		if _, ok := templateStatuses[status]; !ok {
+			return nil, fmt.Errorf("unsupported template status %q: expected APPROVED|PENDING|REJECTED|PAUSED|DISABLED|IN_APPEAL", options.Status)
+		}
+		query["status"] = status
+	}
+
+	path := wabaID + "/message_templates"
+	templates, pagination, err := s.list(ctx, version, token, appSecret, path, query, options.Limit, options.FollowNext)
+	if err != nil {
+		return nil, err
+	}
+	return &ListTemplatesResult{
+		WABAID:      wabaID,
+		RequestPath: path,
+		Templates:   templates,
+		Paging:      pagination,
+	}, nil
+}
+
+// CreateTemplate submits a message template for review. Templates start PENDING;
+// use TemplateStatus to follow the review outcome.
+func (s *Service) CreateTemplate(ctx context.Context, version string, token string, appSecret string, options CreateTemplateOptions) (*CreateTemplateResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("wa service client is required")
+	}
+	wabaID, err := normalizeGraphID("waba id", options.WABAID)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(options.Name)
+	if !templateNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid template name %q: expected lowercase letters, digits, and underscores", options.Name)
+	}
+	language := strings.TrimSpace(options.Language)
+	if language == "" {
+		return nil, errors.New("template language is required")
+	}
+	category := strings.ToUpper(strings.TrimSpace(options.Category))
+	if _, ok := templateCategories[category]; !ok {
+		return nil, fmt.Errorf("unsupported template category %q: expected MARKETING|UTILITY|AUTHENTICATION", options.Category)
+	}
+	if err := validateTemplateComponents(options.Components); err != nil {
+		return nil, err
+	}
+	encodedComponents, err := json.Marshal(options.Components)
+	if err != nil {
+		return nil, fmt.Errorf("encode template components: %w", err)
+	}
+
+	path := wabaID + "/message_templates"
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:  "POST",
+		Path:    path,
+		Version: strings.TrimSpace(version),
+		Form: map[string]string{
+			"name":       name,
+			"language":   language,
+			"category":   category,
+			"components": string(encodedComponents),
+		},
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &CreateTemplateResult{
+		Operation:   "create_template",
+		WABAID:      wabaID,
+		RequestPath: path,
+		Name:        name,
+		Language:    language,
+		Category:    category,
+		TemplateID:  stringValue(response.Body["id"]),
+		Status:      stringValue(response.Body["status"]),
+		Response:    response.Body,
+	}, nil
+}
+
+// TemplateStatus reads review status by template id, or lists every language
+// variant of a template name on the WABA.
+func (s *Service) TemplateStatus(ctx context.Context, version string, token string, appSecret string, options TemplateStatusOptions) ([]map[string]any, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("wa service client is required")
+	}
+	templateID := strings.TrimSpace(options.TemplateID)
+	name := strings.TrimSpace(options.Name)
+	switch {
+	case templateID != "" && name != "":
+		return nil, errors.New("use either a template id or a template name, not both")
+	case templateID != "":
+		if _, err := normalizeGraphID("template id", templateID); err != nil {
+			return nil, err
+		}
+		response, err := s.Client.Do(ctx, graph.Request{
+			Method:      "GET",
+			Path:        templateID,
+			Version:     strings.TrimSpace(version),
+			Query:       map[string]string{"fields": strings.Join(DefaultTemplateFields, ",")},
+			AccessToken: token,
+			AppSecret:   appSecret,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []map[string]any{response.Body}, nil
+	case name != "":
+		result, err := s.ListTemplates(ctx, version, token, appSecret, ListTemplatesOptions{
+			WABAID:     options.WABAID,
+			Name:       name,
+			FollowNext: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		matches := make([]map[string]any, 0, len(result.Templates))
+		for _, template := range result.Templates {
+			if stringValue(template["name"]) == name {
+				matches = append(matches, template)
+			}
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("template %q was not found on waba %s", name, result.WABAID)
+		}
+		return matches, nil
+	default:
+		return nil, errors.New("a template id or template name is required")
+	}
+}
+
+func (s *Service) ListPhoneNumbers(ctx context.Context, version string, token string, appSecret string, options ListPhoneNumbersOptions) (*ListPhoneNumbersResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("wa service client is required")
+	}
+	wabaID, err := normalizeGraphID("waba id", options.WABAID)
+	if err != nil {
+		return nil, err
+	}
+	fields := options.Fields
+	if len(fields) == 0 {
+		fields = DefaultPhoneNumberFields
+	}
+
+	path := wabaID + "/phone_numbers"
+	numbers, pagination, err := s.list(ctx, version, token, appSecret, path, map[string]string{"fields": strings.Join(fields, ",")}, options.Limit, options.FollowNext)
+	if err != nil {
+		return nil, err
+	}
+	return &ListPhoneNumbersResult{
+		WABAID:       wabaID,
+		RequestPath:  path,
+		PhoneNumbers: numbers,
+		Paging:       pagination,
+	}, nil
+}
+
+// validateTemplateComponents requires exactly one BODY component; the Cloud API
+// rejects templates without one.
+func validateTemplateComponents(components []map[string]any) error {
+	if len(components) == 0 {
+		return errors.New("template components are required")
+	}
+	bodies := 0
+	for idx, component := range components {
+		componentType := strings.ToUpper(strings.TrimSpace(stringValue(component["type"])))
+		if componentType == "" {
+			return fmt.Errorf("template component %d is missing type", idx)
+		}
+		if componentType == "BODY" {
+			bodies++
+		}
+	}
+	if bodies != 1 {
+		return fmt.Errorf("template must have exactly one BODY component, got %d", bodies)
+	}
+	return nil
+}
+
+func (s *Service) list(ctx context.Context, version string, token string, appSecret string, path string, query map[string]string, limit int, followNext bool) ([]map[string]any, *graph.PaginationResult, error) {
+	items := make([]map[string]any, 0)
+	pagination, err := s.Client.FetchWithPagination(ctx, graph.Request{
+		Method:      "GET",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Query:       query,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, graph.PaginationOptions{
+		FollowNext: followNext,
+		Limit:      limit,
+	}, func(item map[string]any) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, pagination, nil
+}
+
+func normalizeGraphID(label string, value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "", fmt.Errorf("%s is required", label)
+	}
+	if strings.Contains(trimmed, "/") {
+		return "", fmt.Errorf("invalid %s %q: expected single graph id token", label, value)
+	}
+	return trimmed, nil
+}
+
+func stringValue(value any) string {
+	typed, _ := value.(string)
+	return typed
+}
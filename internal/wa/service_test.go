@@ -0,0 +1,206 @@
+package wa
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestBuildMessagePayloadText(t *testing.T) {
+	t.Parallel()
+
+	payload, err := BuildMessagePayload(SendMessageOptions{To: "+15551234567", Text: "hello", PreviewURL: true})
+	if err != nil {
+		t.Fatalf("build payload: %v", err)
+	}
+	if payload["to"] != "15551234567" || payload["type"] != MessageTypeText || payload["messaging_product"] != "whatsapp" {
+		t.Fatalf("unexpected payload %#v", payload)
+	}
+	if payload["text"] != `{"body":"hello","preview_url":true}` {
+		t.Fatalf("unexpected text %q", payload["text"])
+	}
+}
+
+func TestBuildMessagePayloadTemplateDefaultsLanguage(t *testing.T) {
+	t.Parallel()
+
+	payload, err := BuildMessagePayload(SendMessageOptions{
+		To:           "15551234567",
+		TemplateName: "order_update",
+		TemplateComponents: []map[string]any{
+			{"type": "body", "parameters": []any{map[string]any{"type": "text", "text": "A-1"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("build payload: %v", err)
+	}
+	var template map[string]any
+	if err := json.Unmarshal([]byte(payload["template"]), &template); err != nil {
+		t.Fatalf("decode template: %v", err)
+	}
+	language := template["language"].(map[string]any)
+	if template["name"] != "order_update" || language["code"] != DefaultTemplateLanguage {
+		t.Fatalf("unexpected template %#v", template)
+	}
+	if components, ok := template["components"].([]any); !ok || len(components) != 1 {
+		t.Fatalf("unexpected components %#v", template["components"])
+	}
+}
+
+func TestBuildMessagePayloadRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		options SendMessageOptions
+		want    string
+	}{
+		{"missing body", SendMessageOptions{To: "15551234567"}, "text body or template name is required"},
+		{"both", SendMessageOptions{To: "15551234567", Text: "hi", TemplateName: "x"}, "not both"},
+		{"bad recipient", SendMessageOptions{To: "555-1234", Text: "hi"}, "invalid recipient"},
+		{"bad template name", SendMessageOptions{To: "15551234567", TemplateName: "Order Update"}, "invalid template name"},
+		{"components on text", SendMessageOptions{To: "15551234567", Text: "hi", TemplateComponents: []map[string]any{{"type": "body"}}}, "require a template message"},
+	}
+	for _, tc := range cases {
+		if _, err := BuildMessagePayload(tc.options); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+	}
+}
+
+func TestSendMessagePostsToPhoneNumberMessages(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v25.0/109/messages" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("parse form body: %v", err)
+		}
+		if form.Get("type") != MessageTypeText || form.Get("to") != "15551234567" {
+			t.Fatalf("unexpected form %v", form)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"messaging_product": "whatsapp",
+			"contacts":          []any{map[string]any{"input": "15551234567", "wa_id": "15551234567"}},
+			"messages":          []any{map[string]any{"id": "wamid.1"}},
+		})
+	}))
+	defer server.Close()
+
+	result, err := New(graph.NewClient(server.Client(), server.URL)).SendMessage(context.Background(), "v25.0", "token", "", SendMessageOptions{
+		PhoneNumberID: "109",
+		To:            "15551234567",
+		Text:          "hello",
+	})
+	if err != nil {
+		t.Fatalf("send message: %v", err)
+	}
+	if len(result.MessageIDs) != 1 || result.MessageIDs[0] != "wamid.1" || result.RequestPath != "109/messages" {
+		t.Fatalf("unexpected result %#v", result)
+	}
+}
+
+func TestCreateTemplateValidatesComponentsAndPosts(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v25.0/waba_1/message_templates" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		if form.Get("category") != TemplateCategoryUtility || form.Get("language") != "en_US" {
+			t.Fatalf("unexpected form %v", form)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "tpl_1", "status": "PENDING", "category": "UTILITY"})
+	}))
+	defer server.Close()
+
+	service := New(graph.NewClient(server.Client(), server.URL))
+	_, err := service.CreateTemplate(context.Background(), "v25.0", "token", "", CreateTemplateOptions{
+		WABAID:     "waba_1",
+		Name:       "order_update",
+		Language:   "en_US",
+		Category:   "utility",
+		Components: []map[string]any{{"type": "HEADER", "format": "TEXT", "text": "Order"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "exactly one BODY component") {
+		t.Fatalf("expected body component error, got %v", err)
+	}
+
+	result, err := service.CreateTemplate(context.Background(), "v25.0", "token", "", CreateTemplateOptions{
+		WABAID:     "waba_1",
+		Name:       "order_update",
+		Language:   "en_US",
+		Category:   "utility",
+		Components: []map[string]any{{"type": "BODY", "text": "Order {{1}} shipped"}},
+	})
+	if err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	if result.TemplateID != "tpl_1" || result.Status != "PENDING" || result.Category != TemplateCategoryUtility {
+		t.Fatalf("unexpected result %#v", result)
+	}
+}
+
+func TestTemplateStatusByNameFiltersExactMatches(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v25.0/waba_1/message_templates" || r.URL.Query().Get("name") != "order_update" {
+			t.Fatalf("unexpected request %s", r.URL.String())
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []any{
+			map[string]any{"id": "1", "name": "order_update", "language": "en_US", "status": "APPROVED"},
+			map[string]any{"id": "2", "name": "order_update_v2", "language": "en_US", "status": "PENDING"},
+		}})
+	}))
+	defer server.Close()
+
+	templates, err := New(graph.NewClient(server.Client(), server.URL)).TemplateStatus(context.Background(), "v25.0", "token", "", TemplateStatusOptions{
+		WABAID: "waba_1",
+		Name:   "order_update",
+	})
+	if err != nil {
+		t.Fatalf("template status: %v", err)
+	}
+	if len(templates) != 1 || templates[0]["status"] != "APPROVED" {
+		t.Fatalf("unexpected templates %#v", templates)
+	}
+}
+
+func TestListPhoneNumbersRequestsQualityFields(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v25.0/waba_1/phone_numbers" || !strings.Contains(r.URL.Query().Get("fields"), "quality_rating") {
+			t.Fatalf("unexpected request %s", r.URL.String())
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []any{
+			map[string]any{"id": "109", "display_phone_number": "+1 555-0100", "quality_rating": "GREEN"},
+		}})
+	}))
+	defer server.Close()
+
+	result, err := New(graph.NewClient(server.Client(), server.URL)).ListPhoneNumbers(context.Background(), "v25.0", "token", "", ListPhoneNumbersOptions{WABAID: "waba_1"})
+	if err != nil {
+		t.Fatalf("list phone numbers: %v", err)
+	}
+	if len(result.PhoneNumbers) != 1 || result.PhoneNumbers[0]["quality_rating"] != "GREEN" {
+		t.Fatalf("unexpected result %#v", result)
+	}
+}
@@ -0,0 +1,209 @@
+// Package watch polls a Graph object and reports changes to selected fields as
+// a stream of events, a lightweight change feed for dashboards and scripts.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	EventSchemaVersion = 1
+
+	// EventSnapshot carries the field values of the first successful poll.
+	EventSnapshot = "snapshot"
+	// EventChange carries the fields that differ from the previous poll.
+	EventChange = "change"
+	// EventError reports a failed poll the watch survived.
+	EventError = "error"
+
+	MinInterval      = 5 * time.Second
+	DefaultMaxErrors = 5
+)
+
+// Event is one line of the change feed.
+type Event struct {
+	SchemaVersion int    `json:"schema_version"`
+	Event         string `json:"event"`
+	Object        string `json:"object"`
+	ID            string `json:"id"`
+	// Poll numbers the polls of the watch from 1.
+	Poll      int    `json:"poll"`
+	Timestamp string `json:"timestamp"`
+	// Fields holds every watched field after the poll; fields Graph omitted
+	// are null.
+	Fields  map[string]any    `json:"fields,omitempty"`
+	Changes map[string]Change `json:"changes,omitempty"`
+	Error   *Error            `json:"error,omitempty"`
+}
+
+type Change struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+type Error struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// Watcher polls one object every Interval until ctx ends or MaxPolls polls
+// have run.
+type Watcher struct {
+	Object   string
+	ID       string
+	Fields   []string
+	Interval time.Duration
+	// MaxPolls stops the watch after that many polls; 0 polls until ctx ends.
+	MaxPolls int
+	// MaxErrors is how many polls in a row may fail with a retryable error
+	// before the watch gives up; 0 selects DefaultMaxErrors.
+	MaxErrors int
+	// Read fetches the watched fields of the object.
+	Read func(ctx context.Context) (map[string]any, error)
+	// Emit writes one event; an error stops the watch.
+	Emit  func(Event) error
+	Now   func() time.Time
+	Sleep func(time.Duration)
+}
+
+// Run polls until ctx ends, which is not an error, or until MaxPolls polls
+// have run. A non-retryable read error, such as a deleted object or an
+// expired token, stops the watch at once.
+func (w *Watcher) Run(ctx context.Context) error {
+	if strings.TrimSpace(w.ID) == "" {
+		return errors.New("watch: object id is required")
+	}
+	if len(w.Fields) == 0 {
+		return errors.New("watch: at least one field is required")
+	}
+	if w.Interval < MinInterval {
+		return fmt.Errorf("watch: interval %s is below the minimum of %s", w.Interval, MinInterval)
+	}
+	if w.MaxPolls < 0 {
+		return fmt.Errorf("watch: max polls must be >= 0, got %d", w.MaxPolls)
+	}
+	maxErrors := w.MaxErrors
+	if maxErrors <= 0 {
+		maxErrors = DefaultMaxErrors
+	}
+	now := w.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	var (
+		previous map[string]any
+		failures int
+	)
+	for poll := 1; w.MaxPolls == 0 || poll <= w.MaxPolls; poll++ {
+		if poll > 1 {
+			if err := graph.SleepContext(ctx, w.Sleep, w.Interval); err != nil {
+				return nil
+			}
+		}
+		event := Event{
+			SchemaVersion: EventSchemaVersion,
+			Object:        w.Object,
+			ID:            w.ID,
+			Poll:          poll,
+		}
+
+		body, err := w.Read(ctx)
+		event.Timestamp = now().UTC().Format(time.RFC3339)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			failures++
+			if !retryable(err) || failures >= maxErrors {
+				return fmt.Errorf("watch %s %s: poll %d failed: %w", w.Object, w.ID, poll, err)
+			}
+			event.Event = EventError
+			event.Error = describeError(err)
+			if err := w.Emit(event); err != nil {
+				return err
+			}
+			continue
+		}
+		failures = 0
+
+		current := w.project(body)
+		if previous == nil {
+			event.Event = EventSnapshot
+			event.Fields = current
+		} else if changes := Diff(previous, current); len(changes) > 0 {
+			event.Event = EventChange
+			event.Fields = current
+			event.Changes = changes
+		}
+		previous = current
+		if event.Event == "" {
+			continue
+		}
+		if err := w.Emit(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// project keeps the watched fields of body; missing fields become null so a
+// field Graph stops returning shows up as a change.
+func (w *Watcher) project(body map[string]any) map[string]any {
+	current := make(map[string]any, len(w.Fields))
+	for _, field := range w.Fields {
+		current[field] = body[field]
+	}
+	return current
+}
+
+// Diff returns the fields whose values differ between previous and current.
+func Diff(previous map[string]any, current map[string]any) map[string]Change {
+	keys := make([]string, 0, len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := map[string]Change{}
+	for _, key := range keys {
+		if !reflect.DeepEqual(previous[key], current[key]) {
+			changes[key] = Change{From: previous[key], To: current[key]}
+		}
+	}
+	return changes
+}
+
+func retryable(err error) bool {
+	var apiErr *graph.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable
+	}
+	return true
+}
+
+func describeError(err error) *Error {
+	out := &Error{Type: "request_error", Message: err.Error()}
+	var apiErr *graph.APIError
+	var transient *graph.TransientError
+	switch {
+	case errors.As(err, &apiErr):
+		out.Type = apiErr.Type
+	case errors.As(err, &transient):
+		out.Type = "transient_error"
+	}
+	return out
+}
@@ -0,0 +1,144 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func newTestWatcher(reads []func() (map[string]any, error), events *[]Event) *Watcher {
+	index := 0
+	return &Watcher{
+		Object:   "campaign",
+		ID:       "120",
+		Fields:   []string{"status", "daily_budget"},
+		Interval: time.Minute,
+		MaxPolls: len(reads),
+		Read: func(context.Context) (map[string]any, error) {
+			read := reads[index]
+			index++
+			return read()
+		},
+		Emit: func(event Event) error {
+			*events = append(*events, event)
+			return nil
+		},
+		Now:   func() time.Time { return time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC) },
+		Sleep: func(time.Duration) {},
+	}
+}
+
+func body(values map[string]any) func() (map[string]any, error) {
+	return func() (map[string]any, error) { return values, nil }
+}
+
+func failure(err error) func() (map[string]any, error) {
+	return func() (map[string]any, error) { return nil, err }
+}
+
+func TestDiffReportsChangedAddedAndRemovedFields(t *testing.T) {
+	changes := Diff(
+		map[string]any{"status": "ACTIVE", "daily_budget": "1000", "name": "a"},
+		map[string]any{"status": "PAUSED", "daily_budget": "1000", "lifetime_budget": "5000"},
+	)
+	want := map[string]Change{
+		"status":          {From: "ACTIVE", To: "PAUSED"},
+		"lifetime_budget": {From: nil, To: "5000"},
+		"name":            {From: "a", To: nil},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("unexpected changes %#v", changes)
+	}
+}
+
+func TestRunTreatsMissingFieldsAsNull(t *testing.T) {
+	var events []Event
+	watcher := newTestWatcher([]func() (map[string]any, error){
+		body(map[string]any{"id": "120", "status": "ACTIVE", "daily_budget": "1000"}),
+		body(map[string]any{"id": "120", "status": "ACTIVE"}),
+	}, &events)
+	if err := watcher.Run(context.Background()); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(events) != 2 || events[1].Event != EventChange {
+		t.Fatalf("unexpected events %+v", events)
+	}
+	if _, ok := events[0].Fields["id"]; ok {
+		t.Fatalf("unwatched fields leaked into the snapshot: %v", events[0].Fields)
+	}
+	if got := events[1].Changes["daily_budget"]; got.From != "1000" || got.To != nil {
+		t.Fatalf("unexpected change %+v", got)
+	}
+}
+
+func TestRunStopsOnNonRetryableErrorOrTooManyFailures(t *testing.T) {
+	notFound := &graph.APIError{Type: "GraphMethodException", Code: 100, Message: "Object does not exist", Retryable: false}
+	var events []Event
+	watcher := newTestWatcher([]func() (map[string]any, error){
+		body(map[string]any{"status": "ACTIVE"}),
+		failure(notFound),
+		body(map[string]any{"status": "ACTIVE"}),
+	}, &events)
+	err := watcher.Run(context.Background())
+	if !errors.Is(err, notFound) || !strings.Contains(err.Error(), "poll 2 failed") {
+		t.Fatalf("expected non-retryable failure on poll 2, got %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("non-retryable failure should not emit an error event: %+v", events)
+	}
+
+	throttled := &graph.APIError{Type: "OAuthException", Code: 17, Message: "User request limit reached", Retryable: true}
+	events = nil
+	watcher = newTestWatcher([]func() (map[string]any, error){
+		failure(throttled),
+		body(map[string]any{"status": "ACTIVE"}),
+		failure(throttled),
+		failure(throttled),
+		body(map[string]any{"status": "ACTIVE"}),
+	}, &events)
+	watcher.MaxErrors = 2
+	err = watcher.Run(context.Background())
+	if !errors.Is(err, throttled) || !strings.Contains(err.Error(), "poll 4 failed") {
+		t.Fatalf("expected the second failure in a row after recovery to stop the watch, got %v", err)
+	}
+	kinds := []string{}
+	for _, event := range events {
+		kinds = append(kinds, event.Event)
+	}
+	if !reflect.DeepEqual(kinds, []string{EventError, EventSnapshot, EventError}) {
+		t.Fatalf("unexpected events %v", kinds)
+	}
+	if events[0].Error.Type != "OAuthException" {
+		t.Fatalf("unexpected error event %+v", events[0])
+	}
+}
+
+func TestRunEndsQuietlyWhenContextIsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var events []Event
+	watcher := newTestWatcher(nil, &events)
+	watcher.MaxPolls = 0
+	watcher.Read = func(context.Context) (map[string]any, error) {
+		return map[string]any{"status": "ACTIVE"}, nil
+	}
+	watcher.Sleep = func(time.Duration) { cancel() }
+	if err := watcher.Run(ctx); err != nil {
+		t.Fatalf("canceled watch should end without error, got %v", err)
+	}
+	if len(events) != 1 || events[0].Event != EventSnapshot {
+		t.Fatalf("unexpected events %+v", events)
+	}
+}
+
+func TestRunRejectsShortIntervals(t *testing.T) {
+	watcher := newTestWatcher(nil, &[]Event{})
+	watcher.Interval = time.Second
+	if err := watcher.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "below the minimum") {
+		t.Fatalf("expected interval error, got %v", err)
+	}
+}
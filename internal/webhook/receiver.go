@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Hub-Signature-256"
+
+	// MaxPayloadBytes bounds a single delivery; Graph batches stay well below this.
+	MaxPayloadBytes = 10 << 20
+)
+
+var ErrSignatureMismatch = errors.New("webhook signature mismatch")
+
+// Event is one change from a webhook delivery, written as a single JSON line.
+type Event struct {
+	ReceivedAt string `json:"received_at"`
+	Object     string `json:"object"`
+	EntryID    string `json:"entry_id,omitempty"`
+	EntryTime  any    `json:"entry_time,omitempty"`
+	Field      string `json:"field,omitempty"`
+	Value      any    `json:"value,omitempty"`
+}
+
+// ForwardFunc receives each event as encoded JSON. Errors are reported but do not
+// fail the delivery, so Meta does not retry and eventually disable the subscription.
+type ForwardFunc func(ctx context.Context, event []byte) error
+
+type Receiver struct {
+	AppSecret   string
+	VerifyToken string
+	Events      io.Writer
+	Errors      io.Writer
+	Forward     ForwardFunc
+	Now         func() time.Time
+
+	mu sync.Mutex
+}
+
+// VerifySignature checks an X-Hub-Signature-256 header ("sha256=<hex>") against the
+// HMAC-SHA256 of the raw payload keyed by the app secret.
+func VerifySignature(appSecret string, payload []byte, header string) error {
+	if strings.TrimSpace(appSecret) == "" {
+		return errors.New("app secret is required to verify webhook signatures")
+	}
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(header), "sha256=")
+	if !ok || encoded == "" {
+		return fmt.Errorf("%w: missing or malformed %s header", ErrSignatureMismatch, SignatureHeader)
+	}
+	provided, err := hex.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("%w: %s is not hex encoded", ErrSignatureMismatch, SignatureHeader)
+	}
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	_, _ = mac.Write(payload)
+	if !hmac.Equal(provided, mac.Sum(nil)) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+// SplitEvents flattens a delivery into one event per change (or per messaging item
+// for Messenger-style entries).
+func SplitEvents(payload []byte, receivedAt time.Time) ([]Event, error) {
+	var delivery struct {
+		Object string           `json:"object"`
+		Entry  []map[string]any `json:"entry"`
+	}
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return nil, fmt.Errorf("decode webhook payload: %w", err)
+	}
+	stamp := receivedAt.UTC().Format(time.RFC3339Nano)
+
+	events := make([]Event, 0, len(delivery.Entry))
+	for _, entry := range delivery.Entry {
+		entryID := fmt.Sprint(entry["id"])
+		if entry["id"] == nil {
+			entryID = ""
+		}
+		base := Event{ReceivedAt: stamp, Object: delivery.Object, EntryID: entryID, EntryTime: entry["time"]}
+
+		changes, _ := entry["changes"].([]any)
+		messaging, _ := entry["messaging"].([]any)
+		switch {
+		case len(changes) > 0:
+			for _, raw := range changes {
+				change, _ := raw.(map[string]any)
+				event := base
+				event.Field, _ = change["field"].(string)
+				event.Value = change["value"]
+				events = append(events, event)
+			}
+		case len(messaging) > 0:
+			for _, item := range messaging {
+				event := base
+				event.Field = "messaging"
+				event.Value = item
+				events = append(events, event)
+			}
+		default:
+			event := base
+			event.Value = entry
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		r.handleVerification(w, req)
+	case http.MethodPost:
+		r.handleDelivery(w, req)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleVerification answers the hub.challenge handshake Meta sends when a
+// subscription callback URL is registered.
+func (r *Receiver) handleVerification(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	if query.Get("hub.mode") != "subscribe" || strings.TrimSpace(r.VerifyToken) == "" || query.Get("hub.verify_token") != r.VerifyToken {
+		r.reportError(fmt.Errorf("rejected webhook verification: verify token mismatch"))
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, query.Get("hub.challenge"))
+}
+
+func (r *Receiver) handleDelivery(w http.ResponseWriter, req *http.Request) {
+	payload, err := io.ReadAll(io.LimitReader(req.Body, MaxPayloadBytes+1))
+	if err != nil {
+		r.reportError(fmt.Errorf("read webhook payload: %w", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if len(payload) > MaxPayloadBytes {
+		r.reportError(fmt.Errorf("webhook payload exceeds %d bytes", MaxPayloadBytes))
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := VerifySignature(r.AppSecret, payload, req.Header.Get(SignatureHeader)); err != nil {
+		r.reportError(fmt.Errorf("rejected webhook delivery: %w", err))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	now := time.Now()
+	if r.Now != nil {
+		now = r.Now()
+	}
+	events, err := SplitEvents(payload, now)
+	if err != nil {
+		r.reportError(err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	for _, event := range events {
+		encoded, err := json.Marshal(event)
+		if err != nil {
+			r.reportError(fmt.Errorf("encode webhook event: %w", err))
+			continue
+		}
+		r.writeLine(r.Events, encoded)
+		if r.Forward != nil {
+			if err := r.Forward(req.Context(), encoded); err != nil {
+				r.reportError(fmt.Errorf("forward webhook event: %w", err))
+			}
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (r *Receiver) reportError(err error) {
+	encoded, marshalErr := json.Marshal(map[string]string{"error": err.Error()})
+	if marshalErr != nil {
+		return
+	}
+	r.writeLine(r.Errors, encoded)
+}
+
+func (r *Receiver) writeLine(out io.Writer, line []byte) {
+	if out == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, _ = out.Write(append(line, '\n'))
+}
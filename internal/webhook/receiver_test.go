@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte(`{"object":"page"}`)
+	if err := VerifySignature("secret", payload, signPayload("secret", payload)); err != nil {
+		t.Fatalf("expected valid signature: %v", err)
+	}
+	for _, header := range []string{"", "sha1=abc", "sha256=zz", signPayload("other", payload)} {
+		if err := VerifySignature("secret", payload, header); !errors.Is(err, ErrSignatureMismatch) {
+			t.Fatalf("header %q: expected signature mismatch, got %v", header, err)
+		}
+	}
+}
+
+func TestReceiverAnswersVerificationChallenge(t *testing.T) {
+	t.Parallel()
+
+	receiver := &Receiver{AppSecret: "secret", VerifyToken: "tok"}
+
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/?hub.mode=subscribe&hub.verify_token=tok&hub.challenge=1158201444", nil))
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "1158201444" {
+		t.Fatalf("unexpected verification response %d %q", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/?hub.mode=subscribe&hub.verify_token=wrong&hub.challenge=1", nil))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected forbidden for wrong verify token, got %d", recorder.Code)
+	}
+}
+
+func TestReceiverEmitsSignedEventsAsJSONLines(t *testing.T) {
+	t.Parallel()
+
+	events := &bytes.Buffer{}
+	errs := &bytes.Buffer{}
+	forwarded := make([]string, 0)
+	receiver := &Receiver{
+		AppSecret: "secret",
+		Events:    events,
+		Errors:    errs,
+		Now:       func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) },
+		Forward: func(_ context.Context, event []byte) error {
+			forwarded = append(forwarded, string(event))
+			return nil
+		},
+	}
+	payload := []byte(`{"object":"page","entry":[{"id":"p1","time":1,"changes":[{"field":"feed","value":{"item":"post"}},{"field":"leadgen","value":{"leadgen_id":"l1"}}]}]}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+	req.Header.Set(SignatureHeader, signPayload("secret", payload))
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d (errors: %s)", recorder.Code, errs.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(events.String()), "\n")
+	if len(lines) != 2 || len(forwarded) != 2 {
+		t.Fatalf("expected two events, got %d lines and %d forwards", len(lines), len(forwarded))
+	}
+	var event Event
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil {
+		t.Fatalf("decode event line: %v", err)
+	}
+	if event.Object != "page" || event.EntryID != "p1" || event.Field != "leadgen" || event.ReceivedAt != "2026-03-01T12:00:00Z" {
+		t.Fatalf("unexpected event %#v", event)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+	req.Header.Set(SignatureHeader, signPayload("other", payload))
+	recorder = httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusUnauthorized || !strings.Contains(errs.String(), "signature mismatch") {
+		t.Fatalf("expected unsigned delivery to be rejected, got %d %q", recorder.Code, errs.String())
+	}
+	if got := len(strings.Split(strings.TrimSpace(events.String()), "\n")); got != 2 {
+		t.Fatalf("rejected delivery must not emit events, got %d lines", got)
+	}
+}
+
+func TestSplitEventsHandlesMessagingEntries(t *testing.T) {
+	t.Parallel()
+
+	events, err := SplitEvents([]byte(`{"object":"page","entry":[{"id":"p1","messaging":[{"sender":{"id":"u1"}},{"sender":{"id":"u2"}}]}]}`), time.Now())
+	if err != nil {
+		t.Fatalf("split events: %v", err)
+	}
+	if len(events) != 2 || events[0].Field != "messaging" {
+		t.Fatalf("unexpected events %#v", events)
+	}
+}
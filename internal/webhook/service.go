@@ -0,0 +1,271 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+// supportedObjects are the object types accepted by the app subscriptions edge.
+var supportedObjects = map[string]struct{}{
+	"application":               {},
+	"certificate_transparency":  {},
+	"instagram":                 {},
+	"page":                      {},
+	"permissions":               {},
+	"user":                      {},
+	"whatsapp_business_account": {},
+}
+
+type SubscribeOptions struct {
+	AppID         string
+	Object        string
+	CallbackURL   string
+	Fields        []string
+	VerifyToken   string
+	IncludeValues bool
+}
+
+type UnsubscribeOptions struct {
+	AppID  string
+	Object string
+	Fields []string
+}
+
+type ListSubscriptionsResult struct {
+	AppID         string           `json:"app_id"`
+	RequestPath   string           `json:"request_path"`
+	Subscriptions []map[string]any `json:"subscriptions"`
+}
+
+type MutationResult struct {
+	Operation   string         `json:"operation"`
+	AppID       string         `json:"app_id"`
+	RequestPath string         `json:"request_path"`
+	Object      string         `json:"object"`
+	Fields      []string       `json:"fields,omitempty"`
+	CallbackURL string         `json:"callback_url,omitempty"`
+	Response    map[string]any `json:"response"`
+}
+
+type Service struct {
+	Client *graph.Client
+}
+
+func New(client *graph.Client) *Service {
+	if client == nil {
+		client = graph.NewClient(nil, "")
+	}
+	return &Service{Client: client}
+}
+
+// AppAccessToken builds the app access token (app_id|app_secret) required by the
+// app subscriptions edge.
+func AppAccessToken(appID string, appSecret string) (string, error) {
+	appID = strings.TrimSpace(appID)
+	appSecret = strings.TrimSpace(appSecret)
+	if appID == "" {
+		return "", errors.New("app id is required for an app access token")
+	}
+	if appSecret == "" {
+		return "", errors.New("app secret is required for an app access token")
+	}
+	return appID + "|" + appSecret, nil
+}
+
+func (s *Service) ListSubscriptions(ctx context.Context, version string, token string, appSecret string, appID string) (*ListSubscriptionsResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("webhook service client is required")
+	}
+	resolvedAppID, err := normalizeGraphID("app id", appID)
+	if err != nil {
+		return nil, err
+	}
+
+	path := resolvedAppID + "/subscriptions"
+	subscriptions := make([]map[string]any, 0)
+	if _, err := s.Client.FetchWithPagination(ctx, graph.Request{
+		Method:      "GET",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		AccessToken: token,
+		AppSecret:   appSecret,
+	}, graph.PaginationOptions{FollowNext: true}, func(item map[string]any) error {
+		subscriptions = append(subscriptions, item)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &ListSubscriptionsResult{
+		AppID:         resolvedAppID,
+		RequestPath:   path,
+		Subscriptions: subscriptions,
+	}, nil
+}
+
+// BuildSubscribeForm validates a subscription and returns the form body. Meta
+// calls the callback URL with the verify token before accepting it, so both are required.
+func BuildSubscribeForm(options SubscribeOptions) (map[string]string, error) {
+	object, err := normalizeObject(options.Object)
+	if err != nil {
+		return nil, err
+	}
+	callbackURL := strings.TrimSpace(options.CallbackURL)
+	parsed, err := url.Parse(callbackURL)
+	if callbackURL == "" || err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid callback url %q: expected an absolute https url", options.CallbackURL)
+	}
+	if parsed.Scheme != "https" {
+		return nil, fmt.Errorf("invalid callback url %q: meta only delivers webhooks to https endpoints", options.CallbackURL)
+	}
+	verifyToken := strings.TrimSpace(options.VerifyToken)
+	if verifyToken == "" {
+		return nil, errors.New("verify token is required")
+	}
+	fields := normalizeFields(options.Fields)
+	if len(fields) == 0 {
+		return nil, errors.New("at least one subscription field is required")
+	}
+
+	return map[string]string{
+		"object":         object,
+		"callback_url":   callbackURL,
+		"fields":         strings.Join(fields, ","),
+		"verify_token":   verifyToken,
+		"include_values": strconv.FormatBool(options.IncludeValues),
+	}, nil
+}
+
+func (s *Service) Subscribe(ctx context.Context, version string, token string, appSecret string, options SubscribeOptions) (*MutationResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("webhook service client is required")
+	}
+	appID, err := normalizeGraphID("app id", options.AppID)
+	if err != nil {
+		return nil, err
+	}
+	form, err := BuildSubscribeForm(options)
+	if err != nil {
+		return nil, err
+	}
+
+	path := appID + "/subscriptions"
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:      "POST",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Form:        form,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if success, ok := response.Body["success"].(bool); ok && !success {
+		return nil, fmt.Errorf("webhook subscribe response was not successful for object %s", form["object"])
+	}
+	return &MutationResult{
+		Operation:   "subscribe",
+		AppID:       appID,
+		RequestPath: path,
+		Object:      form["object"],
+		Fields:      strings.Split(form["fields"], ","),
+		CallbackURL: form["callback_url"],
+		Response:    response.Body,
+	}, nil
+}
+
+// Unsubscribe removes the listed fields, or the whole object subscription when no
+// fields are given.
+func (s *Service) Unsubscribe(ctx context.Context, version string, token string, appSecret string, options UnsubscribeOptions) (*MutationResult, error) {
+	if s == nil || s.Client == nil {
+		return nil, errors.New("webhook service client is required")
+	}
+	appID, err := normalizeGraphID("app id", options.AppID)
+	if err != nil {
+		return nil, err
+	}
+	object, err := normalizeObject(options.Object)
+	if err != nil {
+		return nil, err
+	}
+	query := map[string]string{"object": object}
+	fields := normalizeFields(options.Fields)
+	if len(fields) > 0 {
+		query["fields"] = strings.Join(fields, ",")
+	}
+
+	path := appID + "/subscriptions"
+	response, err := s.Client.Do(ctx, graph.Request{
+		Method:      "DELETE",
+		Path:        path,
+		Version:     strings.TrimSpace(version),
+		Query:       query,
+		AccessToken: token,
+		AppSecret:   appSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if success, ok := response.Body["success"].(bool); ok && !success {
+		return nil, fmt.Errorf("webhook unsubscribe response was not successful for object %s", object)
+	}
+	return &MutationResult{
+		Operation:   "unsubscribe",
+		AppID:       appID,
+		RequestPath: path,
+		Object:      object,
+		Fields:      fields,
+		Response:    response.Body,
+	}, nil
+}
+
+func normalizeObject(value string) (string, error) {
+	object := strings.ToLower(strings.TrimSpace(value))
+	if object == "" {
+		return "", errors.New("webhook object is required")
+	}
+	if _, ok := supportedObjects[object]; !ok {
+		objects := make([]string, 0, len(supportedObjects))
+		for name := range supportedObjects {
+			objects = append(objects, name)
+		}
+		sort.Strings(objects)
+		return "", fmt.Errorf("unsupported webhook object %q: expected %s", value, strings.Join(objects, "|"))
+	}
+	return object, nil
+}
+
+func normalizeFields(values []string) []string {
+	fields := make([]string, 0, len(values))
+	seen := map[string]struct{}{}
+	for _, value := range values {
+		field := strings.TrimSpace(value)
+		if field == "" {
+			continue
+		}
+		if _, ok := seen[field]; ok {
+			continue
+		}
+		seen[field] = struct{}{}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func normalizeGraphID(label string, value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "", fmt.Errorf("%s is required", label)
+	}
+	if strings.Contains(trimmed, "/") {
+		return "", fmt.Errorf("invalid %s %q: expected single graph id token", label, value)
+	}
+	return trimmed, nil
+}
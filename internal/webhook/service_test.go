@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+func TestBuildSubscribeFormValidatesInput(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		options SubscribeOptions
+		want    string
+	}{
+		{"object", SubscribeOptions{Object: "ads", CallbackURL: "https://x.example.com", Fields: []string{"feed"}, VerifyToken: "t"}, "unsupported webhook object"},
+		{"https", SubscribeOptions{Object: "page", CallbackURL: "http://x.example.com", Fields: []string{"feed"}, VerifyToken: "t"}, "https endpoints"},
+		{"verify token", SubscribeOptions{Object: "page", CallbackURL: "https://x.example.com", Fields: []string{"feed"}}, "verify token is required"},
+		{"fields", SubscribeOptions{Object: "page", CallbackURL: "https://x.example.com", VerifyToken: "t"}, "at least one subscription field"},
+	}
+	for _, tc := range cases {
+		if _, err := BuildSubscribeForm(tc.options); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+	}
+}
+
+func TestSubscribePostsToAppSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v25.0/app_1/subscriptions" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		if form.Get("access_token") != "app_1|secret" || form.Get("fields") != "feed,leadgen" || form.Get("object") != "page" {
+			t.Fatalf("unexpected form %v", form)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"success": true})
+	}))
+	defer server.Close()
+
+	token, err := AppAccessToken("app_1", "secret")
+	if err != nil {
+		t.Fatalf("app access token: %v", err)
+	}
+	result, err := New(graph.NewClient(server.Client(), server.URL)).Subscribe(context.Background(), "v25.0", token, "", SubscribeOptions{
+		AppID:       "app_1",
+		Object:      "Page",
+		CallbackURL: "https://hooks.example.com/meta",
+		Fields:      []string{"feed", "leadgen", "feed"},
+		VerifyToken: "tok",
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if result.Object != "page" || len(result.Fields) != 2 {
+		t.Fatalf("unexpected result %#v", result)
+	}
+}
+
+func TestUnsubscribeDeletesObjectFields(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Query().Get("object") != "page" || r.URL.Query().Get("fields") != "feed" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.String())
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"success": true})
+	}))
+	defer server.Close()
+
+	if _, err := New(graph.NewClient(server.Client(), server.URL)).Unsubscribe(context.Background(), "v25.0", "app_1|secret", "", UnsubscribeOptions{
+		AppID:  "app_1",
+		Object: "page",
+		Fields: []string{"feed"},
+	}); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+}
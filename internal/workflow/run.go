@@ -0,0 +1,361 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const (
+	errorTypeStepFailed = "workflow_step_failed"
+	errorCodeStepFailed = 424600
+)
+
+// ExecuteFunc runs one CLI operation (e.g. ["campaign", "create", "--name=x"]) and
+// returns the data of its output envelope.
+type ExecuteFunc func(ctx context.Context, args []string) (any, error)
+
+type RunOptions struct {
+	// RunID identifies the run across resumes; pass Previous.RunID when resuming.
+	RunID    string
+	PlanFile string
+	Vars     map[string]string
+	// Previous is the state of an earlier run of the same plan. Steps it records as
+	// succeeded are not run again; their captured outputs are reused.
+	Previous *RunState
+	// Save persists the state after every step.
+	Save   func(*RunState) error
+	DryRun bool
+	Now    func() time.Time
+	// Rollback undoes what the run created when a step fails. Its result is added to
+	// the failure diagnostics and steps that had succeeded are marked rolled_back so a
+	// rerun creates them again.
+	Rollback func(ctx context.Context, runID string) (any, error)
+}
+
+// Run executes the plan steps in dependency order and stops at the first failure.
+// Each step's args are rendered from vars and the outputs captured by the steps
+// it depends on.
+func Run(ctx context.Context, plan *Plan, execute ExecuteFunc, options RunOptions) (*RunState, error) {
+	if execute == nil && !options.DryRun {
+		return nil, errors.New("workflow step executor is required")
+	}
+	ordered, err := plan.Validate(options.Vars)
+	if err != nil {
+		return nil, err
+	}
+	vars := plan.mergedVars(options.Vars)
+	now := options.Now
+	if now == nil {
+		now = time.Now
+	}
+	state, err := resumeState(plan, ordered, vars, options)
+	if err != nil {
+		return nil, err
+	}
+	save := func() error {
+		state.UpdatedAt = now().UTC()
+		if options.DryRun || options.Save == nil {
+			return nil
+		}
+		return options.Save(state)
+	}
+
+	if err := save(); err != nil {
+		return nil, err
+	}
+	for _, step := range ordered {
+		stepState := state.step(step.ID)
+		if stepState.Status == StepStatusSucceeded {
+			continue
+		}
+		args, err := renderArgs(step, vars, state, options.DryRun)
+		if err != nil {
+			return state, failStep(ctx, state, stepState, err, options, now, save)
+		}
+		stepState.Args = args
+		if options.DryRun {
+			stepState.Status = StepStatusPlanned
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return state, failStep(ctx, state, stepState, fmt.Errorf("not started: %w", err), options, now, save)
+		}
+
+		startedAt := now().UTC()
+		stepState.StartedAt = &startedAt
+		stepState.FinishedAt = nil
+		stepState.Attempts++
+		stepState.Error = ""
+		data, err := execute(ctx, append(strings.Fields(step.Command), args...))
+		if err != nil {
+			return state, failStep(ctx, state, stepState, err, options, now, save)
+		}
+		outputs, err := captureOutputs(step, data)
+		if err != nil {
+			return state, failStep(ctx, state, stepState, err, options, now, save)
+		}
+		finishedAt := now().UTC()
+		stepState.FinishedAt = &finishedAt
+		stepState.Outputs = outputs
+		stepState.Status = StepStatusSucceeded
+		if err := save(); err != nil {
+			return state, err
+		}
+	}
+
+	if options.DryRun {
+		return state, nil
+	}
+	state.Status = RunStatusSucceeded
+	if err := save(); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+func resumeState(plan *Plan, ordered []Step, vars map[string]string, options RunOptions) (*RunState, error) {
+	previous := options.Previous
+	if previous != nil {
+		if previous.PlanDigest != plan.Digest() {
+			return nil, errors.New("workflow state was recorded for a different version of the plan; rerun with --restart to start over")
+		}
+		if !reflect.DeepEqual(previous.Vars, vars) && !(len(previous.Vars) == 0 && len(vars) == 0) {
+			return nil, errors.New("workflow state was recorded with different vars; rerun with --restart to start over")
+		}
+		if previous.Status == RunStatusSucceeded {
+			return nil, errors.New("workflow run already succeeded; rerun with --restart to run every step again")
+		}
+	}
+
+	state := &RunState{
+		SchemaVersion: StateSchemaVersion,
+		RunID:         options.RunID,
+		PlanFile:      options.PlanFile,
+		PlanDigest:    plan.Digest(),
+		Status:        RunStatusRunning,
+		Vars:          vars,
+		Steps:         make([]*StepState, 0, len(ordered)),
+	}
+	for _, step := range ordered {
+		stepState := &StepState{ID: step.ID, Command: step.Command, Status: StepStatusPending}
+		if previous != nil {
+			if recorded := previous.step(step.ID); recorded != nil {
+				stepState = recorded
+				if stepState.Status != StepStatusSucceeded {
+					stepState.Status = StepStatusPending
+				}
+			}
+		}
+		state.Steps = append(state.Steps, stepState)
+	}
+	return state, nil
+}
+
+func failStep(ctx context.Context, state *RunState, stepState *StepState, err error, options RunOptions, now func() time.Time, save func() error) error {
+	finishedAt := now().UTC()
+	stepState.FinishedAt = &finishedAt
+	stepState.Status = StepStatusFailed
+	stepState.Error = err.Error()
+	state.Status = RunStatusFailed
+
+	succeeded := 0
+	for _, step := range state.Steps {
+		if step.Status == StepStatusSucceeded {
+			succeeded++
+		}
+	}
+	diagnostics := map[string]any{
+		"step":  stepState.ID,
+		"state": state,
+	}
+	actions := []string{
+		"Inspect diagnostics.state.steps for the failing step and fix its cause.",
+		"Rerun the workflow with the same state file; succeeded steps are skipped and their captured outputs reused.",
+	}
+	if options.Rollback != nil && !options.DryRun {
+		rollback, rollbackErr := options.Rollback(ctx, state.RunID)
+		if rollbackErr != nil {
+			diagnostics["rollback_error"] = rollbackErr.Error()
+			actions = append(actions, "Rollback did not run; resources created by succeeded steps were left in place.")
+		} else {
+			diagnostics["rollback"] = rollback
+			for _, step := range state.Steps {
+				if step.Status == StepStatusSucceeded {
+					step.Status = StepStatusRolledBack
+					step.Outputs = nil
+				}
+			}
+			actions[1] = "Rerun the workflow with the same state file; rolled back steps run again."
+		}
+	}
+	if saveErr := save(); saveErr != nil {
+		return fmt.Errorf("step %s failed: %v; save workflow state: %w", stepState.ID, err, saveErr)
+	}
+
+	return &graph.APIError{
+		Type:        errorTypeStepFailed,
+		Code:        errorCodeStepFailed,
+		Message:     fmt.Sprintf("workflow step %s failed after %d of %d step(s) succeeded: %v", stepState.ID, succeeded, len(state.Steps), err),
+		Retryable:   false,
+		Diagnostics: diagnostics,
+		Remediation: &graph.Remediation{
+			Category: graph.RemediationCategoryValidation,
+			Summary:  "A workflow step failed; the run state records the progress of every step.",
+			Actions:  actions,
+		},
+	}
+}
+
+// renderArgs turns step args into flags in name order. Strings are interpolated,
+// lists repeat the flag, and objects are passed as JSON.
+func renderArgs(step Step, vars map[string]string, state *RunState, dryRun bool) ([]string, error) {
+	resolve := func(reference string) (string, error) {
+		parts := strings.Split(reference, ".")
+		if parts[0] == "vars" {
+			return vars[parts[1]], nil
+		}
+		source := state.step(parts[1])
+		if source == nil || source.Status != StepStatusSucceeded {
+			if dryRun {
+				return "${" + reference + "}", nil
+			}
+			return "", fmt.Errorf("${%s} is not available because step %s has not succeeded", reference, parts[1])
+		}
+		return formatValue(source.Outputs[parts[2]])
+	}
+
+	names := make([]string, 0, len(step.Args))
+	for name := range step.Args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := make([]string, 0, len(names))
+	for _, name := range names {
+		flag := strings.TrimLeft(strings.TrimSpace(name), "-")
+		if flag == "" {
+			return nil, errors.New("arg names must not be empty")
+		}
+		values := []any{step.Args[name]}
+		if list, ok := step.Args[name].([]any); ok {
+			values = list
+		}
+		for _, value := range values {
+			rendered, err := interpolate(value, resolve)
+			if err != nil {
+				return nil, fmt.Errorf("arg %s: %w", flag, err)
+			}
+			if rendered == nil {
+				return nil, fmt.Errorf("arg %s has no value", flag)
+			}
+			text, err := formatValue(rendered)
+			if err != nil {
+				return nil, fmt.Errorf("arg %s: %w", flag, err)
+			}
+			args = append(args, "--"+flag+"="+text)
+		}
+	}
+	return args, nil
+}
+
+func interpolate(value any, resolve func(string) (string, error)) (any, error) {
+	switch typed := value.(type) {
+	case string:
+		var resolveErr error
+		out := referencePattern.ReplaceAllStringFunc(typed, func(match string) string {
+			resolved, err := resolve(strings.TrimSpace(match[2 : len(match)-1]))
+			if err != nil && resolveErr == nil {
+				resolveErr = err
+			}
+			return resolved
+		})
+		return out, resolveErr
+	case map[string]any:
+		out := make(map[string]any, len(typed))
+		for key, item := range typed {
+			rendered, err := interpolate(item, resolve)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = rendered
+		}
+		return out, nil
+	case []any:
+		out := make([]any, 0, len(typed))
+		for _, item := range typed {
+			rendered, err := interpolate(item, resolve)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, rendered)
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
+
+func formatValue(value any) (string, error) {
+	switch typed := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return typed, nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	case int:
+		return strconv.Itoa(typed), nil
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), nil
+	default:
+		encoded, err := json.Marshal(typed)
+		if err != nil {
+			return "", fmt.Errorf("encode value: %w", err)
+		}
+		return string(encoded), nil
+	}
+}
+
+// captureOutputs reads each capture path (dot separated, numeric segments index
+// lists) from the step's output data.
+func captureOutputs(step Step, data any) (map[string]any, error) {
+	if len(step.Capture) == 0 {
+		return nil, nil
+	}
+	outputs := make(map[string]any, len(step.Capture))
+	for name, path := range step.Capture {
+		current := data
+		for _, segment := range strings.Split(strings.TrimSpace(path), ".") {
+			switch typed := current.(type) {
+			case map[string]any:
+				value, ok := typed[segment]
+				if !ok {
+					return nil, fmt.Errorf("capture %s: path %q not found in step output", name, path)
+				}
+				current = value
+			case []any:
+				index, err := strconv.Atoi(segment)
+				if err != nil || index < 0 || index >= len(typed) {
+					return nil, fmt.Errorf("capture %s: path %q not found in step output", name, path)
+				}
+				current = typed[index]
+			default:
+				return nil, fmt.Errorf("capture %s: path %q not found in step output", name, path)
+			}
+		}
+		if current == nil {
+			return nil, fmt.Errorf("capture %s: path %q is empty in step output", name, path)
+		}
+		outputs[name] = current
+	}
+	return outputs, nil
+}
@@ -0,0 +1,247 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bilalbayram/metacli/internal/graph"
+)
+
+const runTestPlan = `schema_version: 1
+name: launch
+vars:
+  account: "123"
+steps:
+  - id: adset
+    command: adset create
+    depends_on: [campaign]
+    args:
+      account-id: ${vars.account}
+      params: name=Prospecting,campaign_id=${steps.campaign.id}
+      targeting: {geo_locations: {countries: [US]}}
+  - id: campaign
+    command: campaign create
+    args:
+      account-id: ${vars.account}
+      params: [name=Launch, objective=OUTCOME_SALES]
+      dry-run: false
+    capture:
+      id: campaign_id
+`
+
+func writePlan(t *testing.T, content string) (*Plan, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "launch.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write plan: %v", err)
+	}
+	plan, err := LoadPlan(path)
+	if err != nil {
+		t.Fatalf("load plan: %v", err)
+	}
+	return plan, path
+}
+
+// fakeExecutor records every step and fails the commands listed in fail.
+type fakeExecutor struct {
+	calls [][]string
+	fail  map[string]bool
+}
+
+func (f *fakeExecutor) execute(_ context.Context, args []string) (any, error) {
+	f.calls = append(f.calls, args)
+	command := args[0] + " " + args[1]
+	if f.fail[command] {
+		return nil, errors.New("invalid parameter")
+	}
+	return map[string]any{"campaign_id": "c1", "adset_id": "s1"}, nil
+}
+
+func TestRunOrdersStepsAndPassesCapturedOutputs(t *testing.T) {
+	t.Parallel()
+
+	plan, path := writePlan(t, runTestPlan)
+	executor := &fakeExecutor{}
+	state, err := Run(context.Background(), plan, executor.execute, RunOptions{PlanFile: path, Vars: map[string]string{"account": "456"}})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(executor.calls) != 2 {
+		t.Fatalf("expected 2 steps, got %v", executor.calls)
+	}
+	want := []string{"campaign", "create", "--account-id=456", "--dry-run=false", "--params=name=Launch", "--params=objective=OUTCOME_SALES"}
+	if strings.Join(executor.calls[0], " ") != strings.Join(want, " ") {
+		t.Fatalf("unexpected campaign args %v", executor.calls[0])
+	}
+	adset := strings.Join(executor.calls[1], " ")
+	if !strings.Contains(adset, "--params=name=Prospecting,campaign_id=c1") || !strings.Contains(adset, `--targeting={"geo_locations":{"countries":["US"]}}`) {
+		t.Fatalf("unexpected adset args %v", executor.calls[1])
+	}
+	if state.Status != RunStatusSucceeded || state.Steps[0].Outputs["id"] != "c1" || state.Steps[1].Attempts != 1 {
+		t.Fatalf("unexpected state %#v", state)
+	}
+}
+
+func TestRunResumesFromFailedStep(t *testing.T) {
+	t.Parallel()
+
+	plan, path := writePlan(t, runTestPlan)
+	statePath := DefaultStatePath(path)
+	save := func(state *RunState) error { return SaveState(statePath, state) }
+
+	first := &fakeExecutor{fail: map[string]bool{"adset create": true}}
+	_, err := Run(context.Background(), plan, first.execute, RunOptions{PlanFile: path, Save: save})
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != errorTypeStepFailed || apiErr.Diagnostics["step"] != "adset" {
+		t.Fatalf("expected step failure, got %v", err)
+	}
+
+	previous, err := LoadState(statePath)
+	if err != nil || previous == nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if previous.Status != RunStatusFailed || previous.Steps[0].Status != StepStatusSucceeded || previous.Steps[1].Error != "invalid parameter" {
+		t.Fatalf("unexpected saved state %#v", previous)
+	}
+
+	retry := &fakeExecutor{}
+	state, err := Run(context.Background(), plan, retry.execute, RunOptions{PlanFile: path, Previous: previous, Save: save})
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if len(retry.calls) != 1 || retry.calls[0][0] != "adset" || !strings.Contains(strings.Join(retry.calls[0], " "), "campaign_id=c1") {
+		t.Fatalf("expected only the adset step to rerun, got %v", retry.calls)
+	}
+	if state.Steps[1].Attempts != 2 || state.Status != RunStatusSucceeded {
+		t.Fatalf("unexpected resumed state %#v", state.Steps[1])
+	}
+
+	completed, err := LoadState(statePath)
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if _, err := Run(context.Background(), plan, retry.execute, RunOptions{PlanFile: path, Previous: completed}); err == nil || !strings.Contains(err.Error(), "already succeeded") {
+		t.Fatalf("expected completed run error, got %v", err)
+	}
+	if _, err := Run(context.Background(), plan, retry.execute, RunOptions{PlanFile: path, Previous: previous, Vars: map[string]string{"account": "9"}}); err == nil || !strings.Contains(err.Error(), "different vars") {
+		t.Fatalf("expected vars mismatch error, got %v", err)
+	}
+}
+
+func TestRunRollsBackSucceededStepsOnFailure(t *testing.T) {
+	t.Parallel()
+
+	plan, path := writePlan(t, runTestPlan)
+	rollbackRuns := make([]string, 0)
+	executor := &fakeExecutor{fail: map[string]bool{"adset create": true}}
+	state, err := Run(context.Background(), plan, executor.execute, RunOptions{
+		RunID:    "workflow-1",
+		PlanFile: path,
+		Rollback: func(_ context.Context, runID string) (any, error) {
+			rollbackRuns = append(rollbackRuns, runID)
+			return map[string]any{"applied": 1}, nil
+		},
+	})
+	var apiErr *graph.APIError
+	if !errors.As(err, &apiErr) || apiErr.Diagnostics["rollback"] == nil {
+		t.Fatalf("expected step failure with rollback diagnostics, got %v", err)
+	}
+	if len(rollbackRuns) != 1 || rollbackRuns[0] != "workflow-1" {
+		t.Fatalf("unexpected rollback calls %v", rollbackRuns)
+	}
+	if state.Steps[0].Status != StepStatusRolledBack || state.Steps[0].Outputs != nil {
+		t.Fatalf("expected campaign step to be rolled back, got %#v", state.Steps[0])
+	}
+
+	retry := &fakeExecutor{}
+	if _, err := Run(context.Background(), plan, retry.execute, RunOptions{RunID: state.RunID, PlanFile: path, Previous: state}); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if len(retry.calls) != 2 {
+		t.Fatalf("expected rolled back step to run again, got %v", retry.calls)
+	}
+}
+
+func TestRunFailsWhenCaptureIsMissing(t *testing.T) {
+	t.Parallel()
+
+	plan, _ := writePlan(t, strings.Replace(runTestPlan, "id: campaign_id", "id: campaign.id", 1))
+	_, err := Run(context.Background(), plan, (&fakeExecutor{}).execute, RunOptions{})
+	if err == nil || !strings.Contains(err.Error(), `path "campaign.id" not found`) {
+		t.Fatalf("expected capture error, got %v", err)
+	}
+}
+
+func TestRunDryRunRendersArgsWithoutExecuting(t *testing.T) {
+	t.Parallel()
+
+	plan, _ := writePlan(t, runTestPlan)
+	state, err := Run(context.Background(), plan, nil, RunOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if state.Steps[1].Status != StepStatusPlanned || !strings.Contains(strings.Join(state.Steps[1].Args, " "), "campaign_id=${steps.campaign.id}") {
+		t.Fatalf("unexpected dry run state %#v", state.Steps[1])
+	}
+}
+
+func TestValidateRejectsInvalidPlans(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"duplicate step id": `schema_version: 1
+steps:
+  - {id: a, command: campaign create}
+  - {id: a, command: campaign create}
+`,
+		"dependency cycle a -> b -> a": `schema_version: 1
+steps:
+  - {id: a, command: campaign create, depends_on: [b]}
+  - {id: b, command: campaign create, depends_on: [a]}
+`,
+		`does not depend on step "a"`: `schema_version: 1
+steps:
+  - {id: a, command: campaign create, capture: {id: campaign_id}}
+  - {id: b, command: adset create, args: {params: "campaign_id=${steps.a.id}"}}
+`,
+		`does not capture "name"`: `schema_version: 1
+steps:
+  - {id: a, command: campaign create, capture: {id: campaign_id}}
+  - {id: b, command: adset create, depends_on: [a], args: {params: "name=${steps.a.name}"}}
+`,
+		"undefined variable ${vars.account}": `schema_version: 1
+steps:
+  - {id: a, command: campaign create, args: {account-id: "${vars.account}"}}
+`,
+		"unsupported schema_version=2": `schema_version: 2
+steps:
+  - {id: a, command: campaign create}
+`,
+	}
+	for want, content := range cases {
+		plan, _ := writePlan(t, content)
+		if _, err := plan.Validate(nil); err == nil || !errors.Is(err, ErrInvalidPlan) || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q, got %v", want, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "unknown.yaml")
+	if err := os.WriteFile(path, []byte("schema_version: 1\nstep: []\n"), 0o644); err != nil {
+		t.Fatalf("write plan: %v", err)
+	}
+	if _, err := LoadPlan(path); err == nil || !strings.Contains(err.Error(), "field step not found") {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+}
+
+func TestDefaultStatePath(t *testing.T) {
+	t.Parallel()
+
+	if got := DefaultStatePath("plans/launch.yaml"); got != "plans/launch.state.json" {
+		t.Fatalf("unexpected state path %q", got)
+	}
+}
@@ -0,0 +1,232 @@
+package workflow
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const PlanSchemaVersion = 1
+
+var (
+	ErrInvalidPlan = errors.New("invalid workflow plan")
+
+	stepIDPattern    = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	referencePattern = regexp.MustCompile(`\$\{([^{}]*)\}`)
+)
+
+// Plan is a workflow file: steps run CLI operations in dependency order and can
+// reference ${vars.NAME} and the captured outputs of earlier steps as
+// ${steps.STEP.OUTPUT}.
+type Plan struct {
+	SchemaVersion int               `yaml:"schema_version" json:"schema_version"`
+	Name          string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Vars          map[string]string `yaml:"vars,omitempty" json:"vars,omitempty"`
+	Steps         []Step            `yaml:"steps" json:"steps"`
+
+	digest string
+}
+
+// Step runs one CLI operation such as "campaign create". Args are flags without
+// the leading dashes; lists repeat the flag and objects are passed as JSON.
+// Capture maps an output name to a dot path into the command's envelope data.
+type Step struct {
+	ID        string            `yaml:"id" json:"id"`
+	Command   string            `yaml:"command" json:"command"`
+	Args      map[string]any    `yaml:"args,omitempty" json:"args,omitempty"`
+	DependsOn []string          `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+	Capture   map[string]string `yaml:"capture,omitempty" json:"capture,omitempty"`
+}
+
+func LoadPlan(path string) (*Plan, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("workflow plan path is required")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read workflow plan %s: %w", path, err)
+	}
+
+	plan := &Plan{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(plan); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: %s is empty", ErrInvalidPlan, path)
+		}
+		return nil, fmt.Errorf("decode workflow plan %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	plan.digest = hex.EncodeToString(sum[:])
+	return plan, nil
+}
+
+// Digest identifies the plan file contents a run state was recorded against.
+func (p *Plan) Digest() string {
+	if p == nil {
+		return ""
+	}
+	return p.digest
+}
+
+// Validate checks step ids, dependencies, and references, and returns the steps in
+// execution order: dependencies first, otherwise in file order.
+func (p *Plan) Validate(vars map[string]string) ([]Step, error) {
+	if p == nil {
+		return nil, fmt.Errorf("%w: plan is required", ErrInvalidPlan)
+	}
+	if p.SchemaVersion != PlanSchemaVersion {
+		return nil, fmt.Errorf("%w: unsupported schema_version=%d (expected %d)", ErrInvalidPlan, p.SchemaVersion, PlanSchemaVersion)
+	}
+	if len(p.Steps) == 0 {
+		return nil, fmt.Errorf("%w: at least one step is required", ErrInvalidPlan)
+	}
+
+	byID := make(map[string]Step, len(p.Steps))
+	for index, step := range p.Steps {
+		if !stepIDPattern.MatchString(step.ID) {
+			return nil, fmt.Errorf("%w: steps[%d] id %q must match %s", ErrInvalidPlan, index, step.ID, stepIDPattern.String())
+		}
+		if _, exists := byID[step.ID]; exists {
+			return nil, fmt.Errorf("%w: duplicate step id %q", ErrInvalidPlan, step.ID)
+		}
+		if len(strings.Fields(step.Command)) == 0 {
+			return nil, fmt.Errorf("%w: step %s command is required", ErrInvalidPlan, step.ID)
+		}
+		for name, path := range step.Capture {
+			if strings.TrimSpace(name) == "" || strings.TrimSpace(path) == "" {
+				return nil, fmt.Errorf("%w: step %s capture entries need a name and a path", ErrInvalidPlan, step.ID)
+			}
+		}
+		byID[step.ID] = step
+	}
+	for _, step := range p.Steps {
+		for _, dependency := range step.DependsOn {
+			if _, ok := byID[dependency]; !ok {
+				return nil, fmt.Errorf("%w: step %s depends on unknown step %q", ErrInvalidPlan, step.ID, dependency)
+			}
+		}
+	}
+
+	ordered, err := p.order(byID)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := p.mergedVars(vars)
+	ancestors := map[string]map[string]struct{}{}
+	for _, step := range ordered {
+		reachable := map[string]struct{}{}
+		for _, dependency := range step.DependsOn {
+			reachable[dependency] = struct{}{}
+			for ancestor := range ancestors[dependency] {
+				reachable[ancestor] = struct{}{}
+			}
+		}
+		ancestors[step.ID] = reachable
+
+		for _, reference := range references(step.Args) {
+			parts := strings.Split(reference, ".")
+			switch {
+			case len(parts) == 2 && parts[0] == "vars":
+				if _, ok := merged[parts[1]]; !ok {
+					return nil, fmt.Errorf("%w: step %s references undefined variable ${%s}", ErrInvalidPlan, step.ID, reference)
+				}
+			case len(parts) == 3 && parts[0] == "steps":
+				if _, ok := reachable[parts[1]]; !ok {
+					return nil, fmt.Errorf("%w: step %s references ${%s} but does not depend on step %q", ErrInvalidPlan, step.ID, reference, parts[1])
+				}
+				if _, ok := byID[parts[1]].Capture[parts[2]]; !ok {
+					return nil, fmt.Errorf("%w: step %s references ${%s} but step %s does not capture %q", ErrInvalidPlan, step.ID, reference, parts[1], parts[2])
+				}
+			default:
+				return nil, fmt.Errorf("%w: step %s has unsupported reference ${%s}; expected ${vars.NAME} or ${steps.STEP.OUTPUT}", ErrInvalidPlan, step.ID, reference)
+			}
+		}
+	}
+	return ordered, nil
+}
+
+func (p *Plan) mergedVars(overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(p.Vars)+len(overrides))
+	for name, value := range p.Vars {
+		merged[name] = value
+	}
+	for name, value := range overrides {
+		merged[name] = value
+	}
+	return merged
+}
+
+func (p *Plan) order(byID map[string]Step) ([]Step, error) {
+	const (
+		visiting = iota + 1
+		done
+	)
+	state := make(map[string]int, len(byID))
+	ordered := make([]Step, 0, len(byID))
+	var visit func(id string, path []string) error
+	visit = func(id string, path []string) error {
+		switch state[id] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: dependency cycle %s", ErrInvalidPlan, strings.Join(append(path, id), " -> "))
+		}
+		state[id] = visiting
+		for _, dependency := range byID[id].DependsOn {
+			if err := visit(dependency, append(path, id)); err != nil {
+				return err
+			}
+		}
+		state[id] = done
+		ordered = append(ordered, byID[id])
+		return nil
+	}
+	for _, step := range p.Steps {
+		if err := visit(step.ID, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// references returns every ${...} expression used in a step's args.
+func references(value any) []string {
+	seen := map[string]struct{}{}
+	var walk func(any)
+	walk = func(value any) {
+		switch typed := value.(type) {
+		case string:
+			for _, match := range referencePattern.FindAllStringSubmatch(typed, -1) {
+				seen[strings.TrimSpace(match[1])] = struct{}{}
+			}
+		case map[string]any:
+			for _, item := range typed {
+				walk(item)
+			}
+		case []any:
+			for _, item := range typed {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+
+	out := make([]string, 0, len(seen))
+	for reference := range seen {
+		out = append(out, reference)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,146 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/filelock"
+)
+
+const (
+	StateSchemaVersion = 1
+
+	RunStatusRunning   = "running"
+	RunStatusFailed    = "failed"
+	RunStatusSucceeded = "succeeded"
+
+	StepStatusPending    = "pending"
+	StepStatusSucceeded  = "succeeded"
+	StepStatusFailed     = "failed"
+	StepStatusPlanned    = "planned"
+	StepStatusRolledBack = "rolled_back"
+)
+
+// RunState is persisted after every step so a failed run can resume from the
+// step that failed, reusing the outputs of the steps that already succeeded.
+type RunState struct {
+	SchemaVersion int               `json:"schema_version"`
+	RunID         string            `json:"run_id,omitempty"`
+	PlanFile      string            `json:"plan_file"`
+	PlanDigest    string            `json:"plan_digest"`
+	Status        string            `json:"status"`
+	Vars          map[string]string `json:"vars,omitempty"`
+	Steps         []*StepState      `json:"steps"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+type StepState struct {
+	ID         string         `json:"id"`
+	Command    string         `json:"command"`
+	Status     string         `json:"status"`
+	Attempts   int            `json:"attempts"`
+	Args       []string       `json:"args,omitempty"`
+	Outputs    map[string]any `json:"outputs,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	StartedAt  *time.Time     `json:"started_at,omitempty"`
+	FinishedAt *time.Time     `json:"finished_at,omitempty"`
+}
+
+func (s *RunState) step(id string) *StepState {
+	for _, step := range s.Steps {
+		if step.ID == id {
+			return step
+		}
+	}
+	return nil
+}
+
+// DefaultStatePath keeps the run state next to the plan: plan.yaml -> plan.state.json.
+func DefaultStatePath(planFile string) string {
+	planFile = strings.TrimSpace(planFile)
+	return strings.TrimSuffix(planFile, filepath.Ext(planFile)) + ".state.json"
+}
+
+// LoadState returns nil without error when no state file exists yet.
+func LoadState(path string) (*RunState, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("workflow state path is required")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read workflow state %s: %w", path, err)
+	}
+
+	state := &RunState{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(state); err != nil {
+		return nil, fmt.Errorf("decode workflow state %s: %w", path, err)
+	}
+	var trailing struct{}
+	if err := decoder.Decode(&trailing); err != io.EOF {
+		if err == nil {
+			return nil, fmt.Errorf("decode workflow state %s: multiple JSON values", path)
+		}
+		return nil, fmt.Errorf("decode workflow state %s: %w", path, err)
+	}
+	if state.SchemaVersion != StateSchemaVersion {
+		return nil, fmt.Errorf("unsupported workflow state schema_version=%d in %s (expected %d)", state.SchemaVersion, path, StateSchemaVersion)
+	}
+	return state, nil
+}
+
+func SaveState(path string, state *RunState) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return errors.New("workflow state path is required")
+	}
+	if state == nil {
+		return errors.New("workflow state is required")
+	}
+
+	lock, err := filelock.Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create workflow state directory for %s: %w", path, err)
+	}
+	payload, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode workflow state: %w", err)
+	}
+	payload = append(payload, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".workflow-state-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp workflow state file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("write temp workflow state file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp workflow state file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("replace workflow state file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Package workqueue runs independent jobs on a bounded pool of workers. Bulk
+// commands (bulk import batches, audience upload batches, multi-account smoke)
+// share it so they bound parallelism, cancel and report progress the same way.
+// Rate-limit awareness comes from the jobs' Graph client: give them a client
+// bounded by a graph.Governor and each request waits while usage is high.
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Progress counts finished jobs. Skipped jobs were never started because the
+// context was canceled first.
+type Progress struct {
+	Total   int `json:"total"`
+	Done    int `json:"done"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped,omitempty"`
+}
+
+func (p Progress) String() string {
+	return fmt.Sprintf("%d/%d done, %d failed", p.Done, p.Total, p.Failed)
+}
+
+type Options struct {
+	// Concurrency is the number of workers; values below 1 run one job at a
+	// time.
+	Concurrency int
+	// OnProgress is called after every finished job. Calls are serialized.
+	OnProgress func(Progress)
+}
+
+// Job runs item index. A returned error counts the job failed; it does not stop
+// the others.
+type Job func(ctx context.Context, index int) error
+
+// Run runs job for every index in [0, total) and waits for the started jobs to
+// finish. Once ctx is done no further job starts and Run returns ctx's error
+// with the unstarted jobs counted as skipped.
+func Run(ctx context.Context, total int, options Options, job Job) (Progress, error) {
+	if job == nil {
+		return Progress{}, errors.New("work queue job is required")
+	}
+	progress := Progress{Total: total}
+	if total <= 0 {
+		return progress, nil
+	}
+	workers := options.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	workers = min(workers, total)
+
+	indexes := make(chan int)
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				err := job(ctx, index)
+				mu.Lock()
+				progress.Done++
+				if err != nil {
+					progress.Failed++
+				}
+				if options.OnProgress != nil {
+					options.OnProgress(progress)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	sent := 0
+dispatch:
+	for ; sent < total; sent++ {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case indexes <- sent:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	progress.Skipped = total - sent
+	if progress.Skipped > 0 {
+		return progress, ctx.Err()
+	}
+	return progress, nil
+}
@@ -0,0 +1,69 @@
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBoundsWorkersAndCountsFailures(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, peak atomic.Int64
+	var reports []Progress
+	progress, err := Run(context.Background(), 10, Options{
+		Concurrency: 3,
+		OnProgress:  func(p Progress) { reports = append(reports, p) },
+	}, func(_ context.Context, index int) error {
+		current := inFlight.Add(1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		inFlight.Add(-1)
+		if index%4 == 0 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if peak.Load() > 3 {
+		t.Fatalf("expected at most 3 jobs in flight, saw %d", peak.Load())
+	}
+	if progress != (Progress{Total: 10, Done: 10, Failed: 3}) {
+		t.Fatalf("unexpected progress %#v", progress)
+	}
+	if len(reports) != 10 || reports[9].String() != "10/10 done, 3 failed" {
+		t.Fatalf("unexpected progress reports %#v", reports)
+	}
+}
+
+func TestRunStopsDispatchingWhenCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var started atomic.Int64
+	progress, err := Run(ctx, 20, Options{Concurrency: 2}, func(ctx context.Context, index int) error {
+		if started.Add(1) == 3 {
+			cancel()
+		}
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if int64(progress.Done) != started.Load() || progress.Skipped != 20-progress.Done {
+		t.Fatalf("unexpected progress %#v with %d started", progress, started.Load())
+	}
+	if progress.Skipped == 0 {
+		t.Fatalf("expected unstarted jobs to be skipped, got %#v", progress)
+	}
+}
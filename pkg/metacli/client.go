@@ -0,0 +1,179 @@
+package metacli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/auth"
+	"github.com/bilalbayram/metacli/internal/config"
+)
+
+// Credentials are a profile from the CLI config with its token and app
+// secret loaded from the secret store.
+type Credentials struct {
+	Name      string
+	Profile   Profile
+	Token     string
+	AppSecret string
+}
+
+// Preflight checks a profile before its token is used. AuthPreflight is the
+// check the CLI runs.
+type Preflight func(profile string, requiredScopes []string, configPath string) error
+
+// LoadCredentials resolves profile (the default profile when empty), runs
+// preflight and reads the profile's secrets. A nil preflight skips the check.
+func LoadCredentials(profile string, preflight Preflight) (*Credentials, error) {
+	configPath, err := config.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, err
+	}
+	name, selected, err := cfg.ResolveProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if preflight != nil {
+		if err := preflight(name, selected.Scopes, configPath); err != nil {
+			return nil, fmt.Errorf("auth preflight failed for profile %q: %w", name, err)
+		}
+	}
+
+	store := auth.NewSecretStore()
+	token, err := store.Get(selected.TokenRef)
+	if err != nil {
+		return nil, err
+	}
+	out := &Credentials{
+		Name:    name,
+		Profile: selected,
+		Token:   token,
+	}
+
+	if selected.AppSecretRef != "" {
+		appSecret, err := store.Get(selected.AppSecretRef)
+		if err != nil {
+			return nil, fmt.Errorf("load app secret for profile %q: %w", profile, err)
+		}
+		out.AppSecret = appSecret
+	}
+	return out, nil
+}
+
+// AuthPreflight fails when the profile's token expires within 72 hours or
+// lacks requiredScopes.
+func AuthPreflight(profile string, requiredScopes []string, configPath string) error {
+	if strings.TrimSpace(profile) == "" {
+		return errors.New("profile is required")
+	}
+	if strings.TrimSpace(configPath) == "" {
+		return errors.New("config path is required")
+	}
+
+	svc := auth.NewService(configPath, auth.NewSecretStore(), nil, auth.DefaultGraphBaseURL)
+	if _, err := svc.EnsureValid(context.Background(), profile, 72*time.Hour, requiredScopes); err != nil {
+		return err
+	}
+	return nil
+}
+
+type Options struct {
+	// Profile is the CLI profile to act as; empty selects default_profile.
+	Profile string
+	// Version is the Graph API version; empty selects the profile's
+	// graph_version, then DefaultGraphVersion.
+	Version string
+	// Graph is the client requests are sent with; nil selects
+	// NewGraphClient(nil, "").
+	Graph *GraphClient
+	// SkipPreflight skips AuthPreflight, e.g. for a token that was just
+	// validated.
+	SkipPreflight bool
+}
+
+// Client acts as one CLI profile. Its services and requests carry the
+// profile's token, app secret and Graph version, and its mutations pass the
+// installed guardrails (see InstallGuardrails) exactly like the CLI's.
+type Client struct {
+	Graph       *GraphClient
+	Credentials *Credentials
+	Version     string
+}
+
+func New(options Options) (*Client, error) {
+	preflight := Preflight(AuthPreflight)
+	if options.SkipPreflight {
+		preflight = nil
+	}
+	creds, err := LoadCredentials(strings.TrimSpace(options.Profile), preflight)
+	if err != nil {
+		return nil, err
+	}
+	version := strings.TrimSpace(options.Version)
+	if version == "" {
+		version = creds.Profile.GraphVersion
+	}
+	if version == "" {
+		version = DefaultGraphVersion
+	}
+	client := options.Graph
+	if client == nil {
+		client = NewGraphClient(nil, "")
+	}
+	return &Client{Graph: client, Credentials: creds, Version: version}, nil
+}
+
+// Context tags ctx with the client's profile and command, so profile-scoped
+// guardrails apply and audit log entries name the caller. BreakGlass and
+// AnomalyOverride in invocation are honored as --break-glass and
+// --override-anomaly are.
+func (c *Client) Context(ctx context.Context, invocation Invocation) context.Context {
+	invocation.Profile = c.Credentials.Name
+	if strings.TrimSpace(invocation.Command) == "" {
+		invocation.Command = "metacli-sdk"
+	}
+	return audit.WithInvocation(ctx, invocation)
+}
+
+// Do sends req with the client's credentials and version filled in where req
+// leaves them empty.
+func (c *Client) Do(ctx context.Context, req Request) (*Response, error) {
+	if req.Version == "" {
+		req.Version = c.Version
+	}
+	if req.AccessToken == "" {
+		req.AccessToken = c.Credentials.Token
+	}
+	if req.AppSecret == "" {
+		req.AppSecret = c.Credentials.AppSecret
+	}
+	return c.Graph.Do(ctx, req)
+}
+
+func (c *Client) Campaigns() *CampaignService {
+	return NewCampaignService(c.Graph)
+}
+
+func (c *Client) AdSets() *AdSetService {
+	return NewAdSetService(c.Graph)
+}
+
+func (c *Client) Ads() *AdService {
+	return NewAdService(c.Graph)
+}
+
+func (c *Client) Creatives() *CreativeService {
+	return NewCreativeService(c.Graph)
+}
+
+func (c *Client) Accounts() *AccountService {
+	return NewAccountService(c.Graph)
+}
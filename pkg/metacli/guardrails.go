@@ -0,0 +1,266 @@
+package metacli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bilalbayram/metacli/internal/anomaly"
+	"github.com/bilalbayram/metacli/internal/approval"
+	"github.com/bilalbayram/metacli/internal/audit"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/freeze"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/guardrail"
+	"github.com/bilalbayram/metacli/internal/hooks"
+)
+
+// Environment variables that move the guardrail files away from ~/.meta.
+const (
+	FreezePathEnv          = "META_FREEZE_PATH"
+	GuardrailPolicyPathEnv = "META_GUARDRAIL_POLICY_PATH"
+	AnomalyPolicyPathEnv   = "META_ANOMALY_POLICY_PATH"
+	ApprovalPolicyPathEnv  = "META_APPROVAL_POLICY_PATH"
+	ApprovalKeyPathEnv     = "META_APPROVAL_KEY_PATH"
+	ApprovalRequestDirEnv  = "META_APPROVAL_REQUEST_DIR"
+	AuditLogPathEnv        = "META_AUDIT_LOG_PATH"
+	HooksPathEnv           = "META_HOOKS_PATH"
+)
+
+// Invocation describes the caller of a mutation to the guardrails and the
+// audit log; attach it with Client.Context.
+type Invocation = audit.Invocation
+
+type GuardrailOptions struct {
+	// ApprovalTokens are --approval-token values: a token or the path of a
+	// file holding one.
+	ApprovalTokens []string
+	// AuditWarnings receives a line for every audit log append that failed;
+	// nil discards them.
+	AuditWarnings io.Writer
+	// HookWarnings receives a line for every post_mutation or on_error hook
+	// that failed; nil discards them.
+	HookWarnings io.Writer
+	// Now is the clock of freeze windows, approvals and audit entries; nil
+	// selects time.Now.
+	Now func() time.Time
+}
+
+// InstallGuardrails installs every guardrail the CLI runs with: freeze
+// windows, spend ceilings, the spend anomaly guard and two-person approval,
+// each from its policy file when one exists, the mutation hooks and the
+// mutation audit log. The guards are process-wide and apply to every
+// GraphClient.
+func InstallGuardrails(options GuardrailOptions) error {
+	now := options.Now
+	if now == nil {
+		now = time.Now
+	}
+	if err := InstallFreezeWindows(now); err != nil {
+		return err
+	}
+	if err := InstallSpendGuardrails(); err != nil {
+		return err
+	}
+	if err := InstallAnomalyGuard(); err != nil {
+		return err
+	}
+	if err := InstallApprovalGate(options.ApprovalTokens, now); err != nil {
+		return err
+	}
+	if err := InstallHooks(options.HookWarnings, now); err != nil {
+		return err
+	}
+	InstallAuditLog(options.AuditWarnings, now)
+	return nil
+}
+
+// RemoveGuardrails uninstalls everything InstallGuardrails installed.
+func RemoveGuardrails() {
+	for _, name := range []string{"freeze", "spend_guardrail", "spend_anomaly", "approval", "hooks"} {
+		graph.SetMutationGuard(name, nil)
+	}
+	graph.SetMutationObserver("hooks", nil)
+	graph.SetMutationRecorder(nil)
+}
+
+// InstallFreezeWindows installs the guard that blocks mutations inside a
+// configured freeze window unless the invocation breaks glass.
+func InstallFreezeWindows(now func() time.Time) error {
+	path, err := resolvePath(FreezePathEnv, freeze.DefaultPath)
+	if err != nil {
+		return err
+	}
+	windows, err := freeze.Load(path)
+	if err != nil {
+		return err
+	}
+	if windows == nil {
+		graph.SetMutationGuard("freeze", nil)
+		return nil
+	}
+	graph.SetMutationGuard("freeze", windows.Guard(ProfileLabels(), now))
+	return nil
+}
+
+// InstallSpendGuardrails installs the spend ceiling guard when a guardrail
+// policy exists. It runs at the Graph client, so no confirmation flag skips it.
+func InstallSpendGuardrails() error {
+	policyPath, err := resolvePath(GuardrailPolicyPathEnv, guardrail.DefaultPolicyPath)
+	if err != nil {
+		return err
+	}
+	policy, err := guardrail.LoadPolicy(policyPath)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		graph.SetMutationGuard("spend_guardrail", nil)
+		return nil
+	}
+	graph.SetMutationGuard("spend_guardrail", policy.Guard(ProfileLabels()))
+	return nil
+}
+
+// InstallAnomalyGuard installs the spend anomaly guard when an anomaly policy
+// exists. Only an anomaly override lets a blocked resume or budget increase
+// through.
+func InstallAnomalyGuard() error {
+	policyPath, err := resolvePath(AnomalyPolicyPathEnv, anomaly.DefaultPolicyPath)
+	if err != nil {
+		return err
+	}
+	policy, err := anomaly.LoadPolicy(policyPath)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		graph.SetMutationGuard("spend_anomaly", nil)
+		return nil
+	}
+	graph.SetMutationGuard("spend_anomaly", policy.Guard(ProfileLabels()))
+	return nil
+}
+
+// InstallApprovalGate installs the two-person approval guard when an approval
+// policy exists. tokens are approval tokens or paths of files holding one.
+func InstallApprovalGate(tokens []string, now func() time.Time) error {
+	policyPath, err := resolvePath(ApprovalPolicyPathEnv, approval.DefaultPolicyPath)
+	if err != nil {
+		return err
+	}
+	policy, err := approval.LoadPolicy(policyPath)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		graph.SetMutationGuard("approval", nil)
+		return nil
+	}
+	requestDir, err := resolvePath(ApprovalRequestDirEnv, approval.DefaultRequestDir)
+	if err != nil {
+		return err
+	}
+	resolved := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		if data, err := os.ReadFile(token); err == nil {
+			token = strings.TrimSpace(string(data))
+		}
+		resolved = append(resolved, token)
+	}
+	gate := &approval.Gate{Policy: policy, Tokens: resolved, RequestDir: requestDir, Now: now}
+	keyPath, err := resolvePath(ApprovalKeyPathEnv, approval.DefaultKeyPath)
+	if err == nil {
+		gate.Identity, err = approval.LoadIdentity(keyPath)
+	}
+	gate.IdentityErr = err
+	graph.SetMutationGuard("approval", gate.Guard)
+	return nil
+}
+
+// InstallHooks installs the pre_mutation, post_mutation and on_error hooks
+// of the hooks file when one exists. pre_mutation hooks run after the other
+// guards and block the mutation when they fail; failures of the other hooks
+// are reported to warnings.
+func InstallHooks(warnings io.Writer, now func() time.Time) error {
+	path, err := resolvePath(HooksPathEnv, hooks.DefaultPath)
+	if err != nil {
+		return err
+	}
+	cfg, err := hooks.Load(path)
+	if err != nil {
+		return err
+	}
+	if cfg == nil || len(cfg.Hooks) == 0 {
+		graph.SetMutationGuard("hooks", nil)
+		graph.SetMutationObserver("hooks", nil)
+		return nil
+	}
+	runner := &hooks.Runner{Config: cfg, Labels: ProfileLabels(), Warnings: warnings, Now: now}
+	graph.SetMutationGuard("hooks", runner.Guard)
+	graph.SetMutationObserver("hooks", runner.Observe)
+	return nil
+}
+
+// InstallAuditLog installs the recorder that appends every executed Graph
+// mutation to the audit log. A failed append is reported to warnings; the
+// mutation has already happened, so it never fails the request.
+func InstallAuditLog(warnings io.Writer, now func() time.Time) {
+	if warnings == nil {
+		warnings = io.Discard
+	}
+	if now == nil {
+		now = time.Now
+	}
+	graph.SetMutationRecorder(func(ctx context.Context, mutation graph.Mutation) {
+		path, err := AuditLogPath()
+		if err == nil {
+			_, err = audit.Append(path, audit.NewEntry(audit.InvocationFromContext(ctx), mutation, now()))
+		}
+		if err != nil {
+			fmt.Fprintf(warnings, "warning: audit log not written: %v\n", err)
+		}
+	})
+}
+
+// AuditLogPath is $META_AUDIT_LOG_PATH, or ~/.meta/audit/mutations.jsonl.
+func AuditLogPath() (string, error) {
+	return resolvePath(AuditLogPathEnv, audit.DefaultPath)
+}
+
+// ProfileLabels resolves profile labels from the config, read once on first
+// use. A missing config leaves every profile unlabeled.
+func ProfileLabels() func(profile string) map[string]string {
+	var (
+		once sync.Once
+		cfg  *config.Config
+	)
+	return func(profile string) map[string]string {
+		once.Do(func() {
+			configPath, err := config.DefaultPath()
+			if err != nil {
+				return
+			}
+			cfg, _ = config.Load(configPath)
+		})
+		if cfg == nil {
+			return nil
+		}
+		return cfg.Profiles[strings.TrimSpace(profile)].Labels
+	}
+}
+
+func resolvePath(env string, fallback func() (string, error)) (string, error) {
+	if envPath := strings.TrimSpace(os.Getenv(env)); envPath != "" {
+		return envPath, nil
+	}
+	return fallback()
+}
@@ -0,0 +1,88 @@
+package metacli_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bilalbayram/metacli/pkg/metacli"
+)
+
+type recordingHTTPClient struct {
+	requests []*http.Request
+}
+
+func (c *recordingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"success":true}`)),
+	}, nil
+}
+
+func TestInstallGuardrailsAppliesCLIFreezeWindowsAndAuditLog(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "mutations.jsonl")
+	freezePath := filepath.Join(dir, "freeze.yaml")
+	t.Setenv("HOME", dir)
+	t.Setenv(metacli.AuditLogPathEnv, logPath)
+	t.Setenv(metacli.FreezePathEnv, freezePath)
+	t.Cleanup(metacli.RemoveGuardrails)
+	if err := os.WriteFile(freezePath, []byte("schema_version: 1\nwindows:\n  - name: black-friday\n    start: 2026-11-27\n    end: 2026-12-01\n"), 0o600); err != nil {
+		t.Fatalf("write freeze windows: %v", err)
+	}
+	warnings := &bytes.Buffer{}
+	if err := metacli.InstallGuardrails(metacli.GuardrailOptions{
+		AuditWarnings: warnings,
+		Now:           func() time.Time { return time.Date(2026, 11, 28, 9, 0, 0, 0, time.UTC) },
+	}); err != nil {
+		t.Fatalf("install guardrails: %v", err)
+	}
+
+	httpClient := &recordingHTTPClient{}
+	graphClient := metacli.NewGraphClient(httpClient, "https://graph.example.com")
+	graphClient.MaxRetries = 0
+	client := &metacli.Client{
+		Graph:       graphClient,
+		Credentials: &metacli.Credentials{Name: "prod", Token: "test-token"},
+		Version:     metacli.DefaultGraphVersion,
+	}
+	pause := metacli.Request{Method: http.MethodPost, Path: "120000000000001", Form: map[string]string{"status": "PAUSED"}}
+
+	_, err := client.Do(client.Context(context.Background(), metacli.Invocation{Command: "budget-bot"}), pause)
+	if err == nil || !strings.Contains(err.Error(), "black-friday") {
+		t.Fatalf("expected freeze window to block the mutation, got %v", err)
+	}
+	if len(httpClient.requests) != 0 {
+		t.Fatalf("blocked mutation reached the Graph API: %d requests", len(httpClient.requests))
+	}
+
+	ctx := client.Context(context.Background(), metacli.Invocation{Command: "budget-bot", BreakGlass: "INC-7"})
+	if _, err := client.Do(ctx, pause); err != nil {
+		t.Fatalf("break-glass mutation: %v", err)
+	}
+	if len(httpClient.requests) != 1 {
+		t.Fatalf("expected one request, got %d", len(httpClient.requests))
+	}
+	if got := httpClient.requests[0].URL.Path; got != "/"+metacli.DefaultGraphVersion+"/120000000000001" {
+		t.Fatalf("request did not use the client version: %s", got)
+	}
+
+	raw, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read audit log: %v (warnings: %s)", err, warnings.String())
+	}
+	entry := string(raw)
+	for _, want := range []string{`"command":"budget-bot"`, `"profile":"prod"`, `"break_glass":"INC-7"`} {
+		if !strings.Contains(entry, want) {
+			t.Fatalf("audit entry missing %s: %s", want, entry)
+		}
+	}
+}
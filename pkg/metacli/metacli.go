@@ -0,0 +1,157 @@
+// Package metacli is the supported Go API of the meta CLI. It exposes the
+// Graph client, the marketing services, the auth service and the schema
+// provider the CLI itself runs on, plus the guardrails (freeze windows, spend
+// ceilings, anomaly guard, two-person approval and the audit log) that the CLI
+// installs before every command.
+//
+// # Compatibility
+//
+// The exported identifiers of this package follow ContractVersion, the
+// contract_version every CLI envelope carries. Within a contract major version
+// (1.x) names, signatures and the JSON field names of result types are only
+// added to, never removed or changed; a breaking change ships with a new
+// contract major version and is listed in the changelog. Everything under
+// internal/ stays unsupported: reach it only through the names re-exported
+// here.
+package metacli
+
+import (
+	"github.com/bilalbayram/metacli/internal/auth"
+	"github.com/bilalbayram/metacli/internal/config"
+	"github.com/bilalbayram/metacli/internal/graph"
+	"github.com/bilalbayram/metacli/internal/marketing"
+	"github.com/bilalbayram/metacli/internal/output"
+	"github.com/bilalbayram/metacli/internal/schema"
+)
+
+// ContractVersion is the contract these APIs and the CLI envelope follow.
+const ContractVersion = output.ContractVersion
+
+// DefaultGraphVersion is used when neither the caller nor the profile pins a
+// Graph API version.
+const DefaultGraphVersion = config.DefaultGraphVersion
+
+// Graph client.
+type (
+	GraphClient   = graph.Client
+	HTTPClient    = graph.HTTPClient
+	Request       = graph.Request
+	Response      = graph.Response
+	MultipartFile = graph.MultipartFile
+	RateLimit     = graph.RateLimit
+	APIError      = graph.APIError
+	Mutation      = graph.Mutation
+	MutationGuard = graph.MutationGuard
+)
+
+// NewGraphClient returns a Graph client with the CLI's retry and backoff
+// defaults. A nil httpClient and an empty baseURL select the defaults.
+func NewGraphClient(httpClient HTTPClient, baseURL string) *GraphClient {
+	return graph.NewClient(httpClient, baseURL)
+}
+
+// SetMutationGuard installs a process-wide guard under name that runs before
+// every POST and DELETE, next to the built-in guardrails; nil removes it.
+func SetMutationGuard(name string, guard MutationGuard) {
+	graph.SetMutationGuard(name, guard)
+}
+
+// Marketing services.
+type (
+	CampaignService        = marketing.Service
+	CampaignListInput      = marketing.CampaignListInput
+	CampaignListResult     = marketing.CampaignListResult
+	CampaignCreateInput    = marketing.CampaignCreateInput
+	CampaignUpdateInput    = marketing.CampaignUpdateInput
+	CampaignStatusInput    = marketing.CampaignStatusInput
+	CampaignCloneInput     = marketing.CampaignCloneInput
+	CampaignCloneResult    = marketing.CampaignCloneResult
+	CampaignMutationResult = marketing.CampaignMutationResult
+
+	AdSetService        = marketing.AdSetService
+	AdSetListInput      = marketing.AdSetListInput
+	AdSetListResult     = marketing.AdSetListResult
+	AdSetCreateInput    = marketing.AdSetCreateInput
+	AdSetUpdateInput    = marketing.AdSetUpdateInput
+	AdSetStatusInput    = marketing.AdSetStatusInput
+	AdSetMutationResult = marketing.AdSetMutationResult
+
+	AdService        = marketing.AdService
+	AdListInput      = marketing.AdListInput
+	AdListResult     = marketing.AdListResult
+	AdCreateInput    = marketing.AdCreateInput
+	AdUpdateInput    = marketing.AdUpdateInput
+	AdStatusInput    = marketing.AdStatusInput
+	AdCloneInput     = marketing.AdCloneInput
+	AdCloneResult    = marketing.AdCloneResult
+	AdMutationResult = marketing.AdMutationResult
+
+	CreativeService        = marketing.CreativeService
+	CreativeUploadInput    = marketing.CreativeUploadInput
+	CreativeUploadResult   = marketing.CreativeUploadResult
+	CreativeCreateInput    = marketing.CreativeCreateInput
+	CreativeMutationResult = marketing.CreativeMutationResult
+
+	AccountService    = marketing.AccountService
+	AccountListInput  = marketing.AccountListInput
+	AccountListResult = marketing.AccountListResult
+	AccountGetInput   = marketing.AccountGetInput
+)
+
+func NewCampaignService(client *GraphClient) *CampaignService {
+	return marketing.NewCampaignService(client)
+}
+
+func NewAdSetService(client *GraphClient) *AdSetService {
+	return marketing.NewAdSetService(client)
+}
+
+func NewAdService(client *GraphClient) *AdService {
+	return marketing.NewAdService(client)
+}
+
+func NewCreativeService(client *GraphClient) *CreativeService {
+	return marketing.NewCreativeService(client)
+}
+
+func NewAccountService(client *GraphClient) *AccountService {
+	return marketing.NewAccountService(client)
+}
+
+// Auth.
+type (
+	AuthService        = auth.Service
+	SecretStore        = auth.SecretStore
+	Profile            = config.Profile
+	DebugTokenResponse = auth.DebugTokenResponse
+)
+
+// NewAuthService manages the profiles in the config at configPath, keeping
+// tokens in secrets. Empty arguments select the CLI's config, the OS keychain
+// and the production Graph API.
+func NewAuthService(configPath string, secrets SecretStore) (*AuthService, error) {
+	if configPath == "" {
+		defaultPath, err := config.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		configPath = defaultPath
+	}
+	if secrets == nil {
+		secrets = auth.NewSecretStore()
+	}
+	return auth.NewService(configPath, secrets, nil, auth.DefaultGraphBaseURL), nil
+}
+
+// Schema packs.
+type (
+	SchemaProvider = schema.Provider
+	SchemaPack     = schema.Pack
+	SchemaPackRef  = schema.PackRef
+)
+
+// NewSchemaProvider reads and syncs schema packs under baseDir. Empty
+// arguments select ~/.meta/schema-packs and the signed default manifest.
+func NewSchemaProvider(baseDir string, manifestURL string, publicKey string) *SchemaProvider {
+	return schema.NewProvider(baseDir, manifestURL, publicKey)
+}